@@ -233,6 +233,23 @@ func genTestPathAndSet(src, dst *relayer.Chain) (*relayer.Path, error) {
 	return p, nil
 }
 
+// testChainsMap returns the chains keyed by chain ID, as expected by relayer.StartRelayer.
+func testChainsMap(chains ...*relayer.Chain) map[string]*relayer.Chain {
+	out := make(map[string]*relayer.Chain, len(chains))
+	for _, c := range chains {
+		out[c.ChainID()] = c
+	}
+	return out
+}
+
+// testNamedPaths returns the single path between src and dst, as expected by relayer.StartRelayer.
+func testNamedPaths(src, dst *relayer.Chain, filter relayer.ChannelFilter) []relayer.NamedPath {
+	return []relayer.NamedPath{{
+		Name: "test-path",
+		Path: &relayer.Path{Src: src.PathEnd, Dst: dst.PathEnd, Filter: filter},
+	}}
+}
+
 func genPrivValKeyJSON(seedNumber int) {
 	privKey := getPrivKey(seedNumber)
 	filePV := getFilePV(privKey, seedNumber)
@@ -144,19 +144,36 @@ $ %s ch d ibc-0`, appName, appName)),
 
 func chainsSetSettlementCmd(a *appState) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "set-settlement chain_name",
+		Use:     "set-settlement chain_name [rollapp_chain_name]",
 		Aliases: []string{"ss"},
-		Short:   "Set an existing chain as the settlement chain based on chain-id in the configuration file",
-		Args:    withUsage(cobra.ExactArgs(1)),
+		Short:   "Set an existing chain as the settlement chain of all rollapps, or of the given rollapp only",
+		Args:    withUsage(cobra.RangeArgs(1, 2)),
 		Example: strings.TrimSpace(fmt.Sprintf(`
 $ %s chains set-settlement hub
-$ %s ch ss hub`, appName, appName)),
+$ %s chains set-settlement hub2 rollapp2
+$ %s ch ss hub`, appName, appName, appName)),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			_, ok := a.Config.Chains[args[0]]
 			if !ok {
 				return errChainNotFound(args[0])
 			}
-			a.Config.SetSettlement(args[0])
+			if len(args) == 1 {
+				a.Config.SetSettlement(args[0])
+				return a.OverwriteConfig(a.Config)
+			}
+
+			rollapp, ok := a.Config.Chains[args[1]]
+			if !ok {
+				return errChainNotFound(args[1])
+			}
+			if args[0] == args[1] {
+				return fmt.Errorf("chain %s cannot settle on itself", args[1])
+			}
+			cp, ok := rollapp.ChainProvider.(*cosmos.CosmosProvider)
+			if !ok {
+				return fmt.Errorf("%s is not a CosmosProvider", args[1])
+			}
+			cp.PCfg.Settlement = args[0]
 			return a.OverwriteConfig(a.Config)
 		},
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/cosmos/relayer/v2/internal/relayertest"
 	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 )
 
 func TestChainsList_Empty(t *testing.T) {
@@ -121,3 +122,48 @@ func TestChainsAdd_Delete(t *testing.T) {
 	require.Empty(t, res.Stdout.String())
 	require.Contains(t, res.Stderr.String(), "no chains found")
 }
+
+func TestChainsSetSettlement_Rollapp(t *testing.T) {
+	t.Parallel()
+
+	sys := relayertest.NewSystem(t)
+
+	_ = sys.MustRun(t, "config", "init")
+
+	for _, c := range []struct{ name, chainID, clientType string }{
+		{"hub1", "hub-1", "07-tendermint"},
+		{"hub2", "hub-2", "07-tendermint"},
+		{"rollapp1", "rollapp-1", "01-furyint"},
+		{"rollapp2", "rollapp-2", "01-furyint"},
+	} {
+		sys.MustAddChain(t, c.name, cmd.ProviderConfigWrapper{
+			Type: "cosmos",
+			Value: cosmos.CosmosProviderConfig{
+				ChainID:        c.chainID,
+				KeyringBackend: "test",
+				Timeout:        "10s",
+				ClientType:     c.clientType,
+			},
+		})
+	}
+
+	// rollapp1 settles on the default settlement chain, rollapp2 on its own.
+	_ = sys.MustRun(t, "chains", "set-settlement", "hub1")
+	_ = sys.MustRun(t, "chains", "set-settlement", "hub2", "rollapp2")
+
+	res := sys.MustRun(t, "config", "show", "--json")
+	var cfg struct {
+		Chains map[string]struct {
+			Value cosmos.CosmosProviderConfig `json:"value"`
+		} `json:"chains"`
+		Settlement string `json:"settlement"`
+	}
+	require.NoError(t, json.Unmarshal(res.Stdout.Bytes(), &cfg))
+	require.Equal(t, "hub1", cfg.Settlement)
+	require.Empty(t, cfg.Chains["rollapp1"].Value.Settlement)
+	require.Equal(t, "hub2", cfg.Chains["rollapp2"].Value.Settlement)
+
+	// A chain cannot settle on itself.
+	res = sys.Run(zaptest.NewLogger(t), "chains", "set-settlement", "rollapp2", "rollapp2")
+	require.Error(t, res.Err)
+}
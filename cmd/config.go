@@ -29,9 +29,13 @@ import (
 	"strings"
 	"time"
 
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
 	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/cosmos/relayer/v2/relayer/oracle"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/provider/evm"
+	"github.com/cosmos/relayer/v2/relayer/tracing"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -47,6 +51,7 @@ func configCmd(a *appState) *cobra.Command {
 	cmd.AddCommand(
 		configShowCmd(a),
 		configInitCmd(a),
+		configRenderCmd(a),
 	)
 	return cmd
 }
@@ -344,6 +349,7 @@ type ProviderConfigYAMLWrapper struct {
 func (pcw *ProviderConfigWrapper) UnmarshalJSON(data []byte) error {
 	customTypes := map[string]reflect.Type{
 		"cosmos": reflect.TypeOf(cosmos.CosmosProviderConfig{}),
+		"evm":    reflect.TypeOf(evm.EVMProviderConfig{}),
 	}
 	val, err := UnmarshalJSONProviderConfig(data, customTypes)
 	if err != nil {
@@ -396,6 +402,8 @@ func (iw *ProviderConfigYAMLWrapper) UnmarshalYAML(n *yaml.Node) error {
 	switch iw.Type {
 	case "cosmos":
 		iw.Value = new(cosmos.CosmosProviderConfig)
+	case "evm":
+		iw.Value = new(evm.EVMProviderConfig)
 	default:
 		return fmt.Errorf("%s is an invalid chain type, check your config file", iw.Type)
 	}
@@ -450,6 +458,36 @@ type GlobalConfig struct {
 	Timeout        string `yaml:"timeout" json:"timeout"`
 	Memo           string `yaml:"memo" json:"memo"`
 	LightCacheSize int    `yaml:"light-cache-size" json:"light-cache-size"`
+
+	// PriceOracle prices the tokens of the chains in USD, e.g. for the packet filters of the paths.
+	PriceOracle *oracle.Config `yaml:"price-oracle,omitempty" json:"price-oracle,omitempty"`
+
+	// Watchtower configures the clients monitored by `rly watchtower`.
+	Watchtower *relayer.WatchtowerConfig `yaml:"watchtower,omitempty" json:"watchtower,omitempty"`
+
+	// Tracing exports the spans of `rly start` to an OpenTelemetry collector.
+	Tracing *tracing.Config `yaml:"tracing,omitempty" json:"tracing,omitempty"`
+
+	// HealthProbes configures the checks of the infrastructure the paths relayed by `rly start` depend on.
+	HealthProbes *relayer.HealthProbesConfig `yaml:"health-probes,omitempty" json:"health-probes,omitempty"`
+
+	// WalletMonitor configures the low-balance alerts and top-ups of the wallets of the chains relayed by `rly start`.
+	WalletMonitor *relayer.WalletMonitorConfig `yaml:"wallet-monitor,omitempty" json:"wallet-monitor,omitempty"`
+
+	// Maintenance configures the scheduler of the periodic maintenance tasks of `rly start`.
+	Maintenance *relayer.MaintenanceConfig `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+
+	// Alerts configures the alerts posted to webhooks while `rly start` fails to relay.
+	Alerts *relayer.AlertsConfig `yaml:"alerts,omitempty" json:"alerts,omitempty"`
+
+	// ReleaseCheck configures the checks of the version of `rly start` against a release manifest.
+	ReleaseCheck *relayer.ReleaseCheckConfig `yaml:"release-check,omitempty" json:"release-check,omitempty"`
+
+	// StuckPackets configures the detection of the packets pending for long on the paths relayed by `rly start`.
+	StuckPackets *relayer.StuckPacketsConfig `yaml:"stuck-packets,omitempty" json:"stuck-packets,omitempty"`
+
+	// FeatureFlags enables or disables the experimental features of the paths relayed by `rly start`.
+	FeatureFlags relayer.FeatureFlagsConfig `yaml:"feature-flags,omitempty" json:"feature-flags,omitempty"`
 }
 
 // newDefaultGlobalConfig returns a global config with defaults set
@@ -541,6 +579,41 @@ func validateConfig(c *Config) error {
 	if err != nil {
 		return fmt.Errorf("did you remember to run 'rly config init' error:%w", err)
 	}
+	if _, _, err := c.Global.PriceOracle.Oracle(); err != nil {
+		return err
+	}
+	if c.Global.Watchtower != nil {
+		if err := c.Global.Watchtower.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.Global.Tracing.Validate(); err != nil {
+		return err
+	}
+	if err := c.Global.HealthProbes.Validate(c.Paths); err != nil {
+		return err
+	}
+	if err := c.Global.WalletMonitor.Validate(c.Chains); err != nil {
+		return err
+	}
+	if err := c.Global.Maintenance.Validate(); err != nil {
+		return err
+	}
+	if err := c.Global.Alerts.Validate(); err != nil {
+		return err
+	}
+	if err := c.Global.ReleaseCheck.Validate(); err != nil {
+		return err
+	}
+	if err := c.Global.StuckPackets.Validate(); err != nil {
+		return err
+	}
+	if err := c.Global.FeatureFlags.Validate(c.Paths); err != nil {
+		return err
+	}
+	if err := c.Paths.ValidateDependencies(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -593,20 +666,8 @@ func initConfig(cmd *cobra.Command, a *appState) error {
 				chains[chainName] = chain
 			}
 
-			// Create furya settelemnt if configured
-			if cfgWrapper.Settlement != "" {
-				settlementChain := chains[cfgWrapper.Settlement]
-				if settlementChain == nil {
-					return fmt.Errorf("%s doesn't exists in the chain configuration", cfgWrapper.Settlement)
-				}
-				cp, ok := chains[cfgWrapper.Settlement].ChainProvider.(*cosmos.CosmosProvider)
-				if !ok {
-					return fmt.Errorf("%s is not a CosmosProvider", cfgWrapper.Settlement)
-				}
-				_, err = cosmos.NewSettlementProvider(cp)
-				if err != nil {
-					return err
-				}
+			if err := setSettlementProviders(chains, cfgWrapper.Settlement); err != nil {
+				return err
 			}
 
 			a.Config = &Config{
@@ -627,6 +688,70 @@ func initConfig(cmd *cobra.Command, a *appState) error {
 	return nil
 }
 
+// setSettlementProviders gives every rollapp the settlement provider of its hub, which is the chain named by
+// the settlement of its provider config, or defaultSettlement if unset.
+// Rollapps settling on the same hub share a settlement provider.
+func setSettlementProviders(chains relayer.Chains, defaultSettlement string) error {
+	// settlement providers by chain ID of the settlement hub
+	settlements := make(map[string]cosmos.SettlementProvider)
+
+	settlementProvider := func(name string) (cosmos.SettlementProvider, error) {
+		settlementChain, ok := chains[name]
+		if !ok {
+			return nil, fmt.Errorf("%s doesn't exists in the chain configuration", name)
+		}
+		if sp, ok := settlements[settlementChain.ChainID()]; ok {
+			return sp, nil
+		}
+		cp, ok := settlementChain.ChainProvider.(*cosmos.CosmosProvider)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a CosmosProvider", name)
+		}
+		sp, err := cosmos.NewSettlementProvider(cp)
+		if err != nil {
+			return nil, fmt.Errorf("settlement chain %s: %w", name, err)
+		}
+		settlements[settlementChain.ChainID()] = sp
+		return sp, nil
+	}
+
+	// the default settlement chain must exist even if no rollapp is configured yet
+	if defaultSettlement != "" {
+		if _, err := settlementProvider(defaultSettlement); err != nil {
+			return err
+		}
+	}
+
+	for chainName, chain := range chains {
+		cp, ok := chain.ChainProvider.(*cosmos.CosmosProvider)
+		if !ok {
+			continue
+		}
+		name := cp.PCfg.Settlement
+		if name == "" {
+			if cp.ClientType() != ibcexported.Furyint {
+				continue
+			}
+			name = defaultSettlement
+		}
+		if name == "" {
+			continue
+		}
+		if name == chainName {
+			return fmt.Errorf("chain %s cannot settle on itself", chainName)
+		}
+		sp, err := settlementProvider(name)
+		if err != nil {
+			return fmt.Errorf("settlement of chain %s: %w", chainName, err)
+		}
+		if m, ok := sp.(*cosmos.MockSettlementProvider); ok {
+			m.AddRollapp(cp)
+		}
+		cp.SetSettlementProvider(sp)
+	}
+	return nil
+}
+
 // ValidatePath checks that a path is valid
 func (c *Config) ValidatePath(ctx context.Context, stderr io.Writer, p *relayer.Path) (err error) {
 	if err = c.ValidatePathEnd(ctx, stderr, p.Src); err != nil {
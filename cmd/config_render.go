@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigValues holds the variables a config template is rendered with, and the overrides of each environment.
+type ConfigValues struct {
+	Vars         map[string]any               `yaml:"vars"`
+	Environments map[string]ConfigEnvironment `yaml:"environments"`
+}
+
+// ConfigEnvironment overrides the variables of a config template for an environment, such as testnet or mainnet,
+// and overlays the rendered config: maps are merged key by key, any other value, lists included, is replaced.
+type ConfigEnvironment struct {
+	Vars    map[string]any `yaml:"vars"`
+	Overlay map[string]any `yaml:"overlay"`
+}
+
+// RenderConfig renders the config template tmpl, a text/template producing a config file, with the variables of
+// values, those of environment env and then sets, a list of "key=value" where key may be a dotted path into
+// nested variables. The overlay of env is merged onto the result, which is validated and returned in the
+// canonical layout of the config file, so the same inputs always render the same config.
+func RenderConfig(tmpl []byte, values ConfigValues, env string, sets []string) ([]byte, error) {
+	vars := mergeValues(nil, values.Vars)
+	var overlay map[string]any
+	if env != "" {
+		e, ok := values.Environments[env]
+		if !ok {
+			return nil, fmt.Errorf("environment %s is not defined in the values", env)
+		}
+		vars = mergeValues(vars, e.Vars)
+		overlay = e.Overlay
+	}
+	for _, set := range sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected key=value", set)
+		}
+		vars = mergeValues(vars, nestedValue(strings.Split(key, "."), value))
+	}
+
+	t, err := template.New("config").Option("missingkey=error").Funcs(configTemplateFuncs).Parse(string(tmpl))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config template: %w", err)
+	}
+	var rendered bytes.Buffer
+	if err := t.Execute(&rendered, vars); err != nil {
+		return nil, fmt.Errorf("failed to render config template: %w", err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(rendered.Bytes(), &doc); err != nil {
+		return nil, fmt.Errorf("rendered config is not valid YAML: %w", err)
+	}
+	doc = mergeValues(doc, overlay)
+	merged, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg ConfigInputWrapper
+	if err := yaml.Unmarshal(merged, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered config: %w", err)
+	}
+	out, err := validateRenderedConfig(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("rendered config is invalid: %w", err)
+	}
+	return yaml.Marshal(out)
+}
+
+// validateRenderedConfig checks the rendered config as the relayer would on startup, short of connecting to the chains,
+// and returns it in the layout the relayer writes config files in.
+func validateRenderedConfig(cfg *ConfigInputWrapper) (*ConfigOutputWrapper, error) {
+	if err := validateConfig(&Config{Global: cfg.Global}); err != nil {
+		return nil, fmt.Errorf("invalid global timeout %q", cfg.Global.Timeout)
+	}
+
+	chainIDs := make(map[string]bool)
+	providers := make(ProviderConfigs, len(cfg.ProviderConfigs))
+	for name, pcw := range cfg.ProviderConfigs {
+		if pcw == nil || pcw.Value == nil {
+			return nil, fmt.Errorf("chain %s has no value", name)
+		}
+		pc := pcw.Value.(provider.ProviderConfig)
+		if err := pc.Validate(); err != nil {
+			return nil, fmt.Errorf("chain %s: %w", name, err)
+		}
+		if cpc, ok := pc.(*cosmos.CosmosProviderConfig); ok {
+			if cpc.ChainID == "" {
+				return nil, fmt.Errorf("chain %s has no chain-id", name)
+			}
+			chainIDs[cpc.ChainID] = true
+		}
+		providers[name] = &ProviderConfigWrapper{Type: pcw.Type, Value: pc}
+	}
+	if cfg.Settlement != "" && cfg.ProviderConfigs[cfg.Settlement] == nil {
+		return nil, fmt.Errorf("settlement chain %s is not configured", cfg.Settlement)
+	}
+
+	for name, p := range cfg.Paths {
+		if p.Src == nil || p.Dst == nil {
+			return nil, fmt.Errorf("path %s must have a src and a dst", name)
+		}
+		for _, chainID := range []string{p.Src.ChainID, p.Dst.ChainID} {
+			if !chainIDs[chainID] {
+				return nil, fmt.Errorf("path %s: chain %q is not configured", name, chainID)
+			}
+		}
+		if err := p.ValidateChannelFilterRule(); err != nil {
+			return nil, fmt.Errorf("path %s: %w", name, err)
+		}
+	}
+
+	return &ConfigOutputWrapper{Global: cfg.Global, ProviderConfigs: providers, Paths: cfg.Paths, Settlement: cfg.Settlement}, nil
+}
+
+// mergeValues merges src onto dst, recursing into maps present in both and replacing any other value.
+func mergeValues(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for k, v := range src {
+		if srcMap, ok := v.(map[string]any); ok {
+			if dstMap, ok := dst[k].(map[string]any); ok {
+				dst[k] = mergeValues(dstMap, srcMap)
+				continue
+			}
+			dst[k] = mergeValues(nil, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// nestedValue returns value nested under the keys, e.g. {"a": {"b": value}} for a.b.
+func nestedValue(keys []string, value any) map[string]any {
+	if len(keys) == 1 {
+		return map[string]any{keys[0]: value}
+	}
+	return map[string]any{keys[0]: nestedValue(keys[1:], value)}
+}
+
+var configTemplateFuncs = template.FuncMap{
+	// default returns value unless it is empty, e.g. {{ index . "gas_adjustment" | default 1.5 }}.
+	// Optional variables are looked up with index, referring to an unset variable otherwise fails the rendering.
+	"default": func(def, value any) any {
+		if value == nil || value == "" {
+			return def
+		}
+		return value
+	},
+	// required fails the rendering with msg unless value is set.
+	"required": func(msg string, value any) (any, error) {
+		if value == nil || value == "" {
+			return nil, errors.New(msg)
+		}
+		return value, nil
+	},
+	// quote returns value as a double-quoted YAML string.
+	"quote": func(value any) string {
+		return fmt.Sprintf("%q", fmt.Sprint(value))
+	},
+}
+
+func configRenderCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render template_file",
+		Short: "Render a config file from a template, variables and per-environment overlays",
+		Long: strings.TrimSpace(`Render a config file from a Go text/template producing the config file,
+so that many near-identical chain and path configs, such as those of a fleet of rollapps, can be generated
+from a single template.
+
+The values file holds the variables of the template under "vars", and per-environment variables and
+overlays under "environments.<name>". An overlay is merged onto the rendered config: maps are merged
+key by key, lists and other values are replaced. Variables can also be set with --set key=value.
+
+The rendered config is validated, and printed in the canonical layout of the config file,
+or written to the config file of the home directory with --write.`,
+		),
+		Args: withUsage(cobra.ExactArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s config render fleet.yaml.tmpl --values fleet-values.yaml --env testnet
+$ %s config render fleet.yaml.tmpl --values fleet-values.yaml --env mainnet --set hub.gas_prices=0.025ufury --write`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tmpl, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			var values ConfigValues
+			valuesFile, err := cmd.Flags().GetString(flagValues)
+			if err != nil {
+				return err
+			}
+			if valuesFile != "" {
+				bz, err := os.ReadFile(valuesFile)
+				if err != nil {
+					return err
+				}
+				if err := yaml.Unmarshal(bz, &values); err != nil {
+					return fmt.Errorf("failed to parse values file %s: %w", valuesFile, err)
+				}
+			}
+
+			env, err := cmd.Flags().GetString(flagEnv)
+			if err != nil {
+				return err
+			}
+			sets, err := cmd.Flags().GetStringArray(flagSet)
+			if err != nil {
+				return err
+			}
+
+			out, err := RenderConfig(tmpl, values, env, sets)
+			if err != nil {
+				return err
+			}
+
+			write, err := cmd.Flags().GetBool(flagWrite)
+			if err != nil {
+				return err
+			}
+			if !write {
+				_, err := cmd.OutOrStdout().Write(out)
+				return err
+			}
+
+			cfgDir := path.Join(a.HomePath, "config")
+			if err := os.MkdirAll(cfgDir, os.ModePerm); err != nil {
+				return err
+			}
+			cfgPath := path.Join(cfgDir, "config.yaml")
+			if err := os.WriteFile(cfgPath, out, 0600); err != nil {
+				return fmt.Errorf("failed to write config file at %s: %w", cfgPath, err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "wrote config to %s\n", cfgPath)
+			return nil
+		},
+	}
+	return configRenderFlags(a.Viper, cmd)
+}
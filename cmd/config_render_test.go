@@ -0,0 +1,133 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cosmos/relayer/v2/internal/relayertest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+const fleetTemplate = `global:
+  api-listen-addr: :5183
+  timeout: {{ index . "timeout" | default "10s" }}
+  memo: ""
+  light-cache-size: 20
+chains:
+  hub:
+    type: cosmos
+    value:
+      key: relayer
+      chain-id: {{ .hub.chain_id }}
+      rpc-addr: {{ .hub.rpc }}
+      account-prefix: fury
+      keyring-backend: test
+      gas-adjustment: 1.5
+      gas-prices: {{ .hub.gas_prices }}
+      timeout: 20s
+{{- range .rollapps }}
+  {{ .name }}:
+    type: cosmos
+    value:
+      key: relayer
+      chain-id: {{ .chain_id }}
+      rpc-addr: {{ .rpc }}
+      account-prefix: ethm
+      keyring-backend: test
+      gas-adjustment: 1.5
+      gas-prices: 0uroll
+      timeout: 20s
+      client-type: 01-furyint
+{{- end }}
+paths:
+{{- range .rollapps }}
+  hub-{{ .name }}:
+    src:
+      chain-id: {{ $.hub.chain_id }}
+    dst:
+      chain-id: {{ .chain_id }}
+    src-channel-filter:
+      rule: ""
+      channel-list: []
+{{- end }}
+settlement: hub
+`
+
+const fleetValues = `vars:
+  hub:
+    gas_prices: 0.01ufury
+  rollapps:
+    - name: rollapp-a
+      chain_id: rollapp-a_1-1
+      rpc: http://rollapp-a:26657
+    - name: rollapp-b
+      chain_id: rollapp-b_2-1
+      rpc: http://rollapp-b:26657
+environments:
+  testnet:
+    vars:
+      hub:
+        chain_id: furya-testnet-1
+        rpc: http://hub-testnet:26657
+    overlay:
+      paths:
+        hub-rollapp-b:
+          src-channel-filter:
+            rule: allowlist
+            channel-list: [channel-3]
+`
+
+func TestConfigRender(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tmplFile := filepath.Join(dir, "fleet.yaml.tmpl")
+	valuesFile := filepath.Join(dir, "values.yaml")
+	require.NoError(t, os.WriteFile(tmplFile, []byte(fleetTemplate), 0o600))
+	require.NoError(t, os.WriteFile(valuesFile, []byte(fleetValues), 0o600))
+
+	sys := relayertest.NewSystem(t)
+
+	res := sys.MustRun(t, "config", "render", tmplFile, "--values", valuesFile, "--env", "testnet", "--set", "hub.gas_prices=0.025ufury")
+	out := res.Stdout.String()
+	require.Contains(t, out, "chain-id: furya-testnet-1")
+	require.Contains(t, out, "gas-prices: 0.025ufury")
+	require.Contains(t, out, "rpc-addr: http://rollapp-b:26657")
+	require.Contains(t, out, "rule: allowlist")
+	require.Contains(t, out, "- channel-3")
+
+	// Rendering is deterministic.
+	again := sys.MustRun(t, "config", "render", tmplFile, "--values", valuesFile, "--env", "testnet", "--set", "hub.gas_prices=0.025ufury")
+	require.Equal(t, out, again.Stdout.String())
+
+	// The rendered config is the one the relayer loads.
+	_ = sys.MustRun(t, "config", "render", tmplFile, "--values", valuesFile, "--env", "testnet", "--write")
+	res = sys.MustRun(t, "paths", "list")
+	require.Contains(t, res.Stdout.String(), "hub-rollapp-a")
+	require.Contains(t, res.Stdout.String(), "hub-rollapp-b")
+}
+
+func TestConfigRender_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tmplFile := filepath.Join(dir, "fleet.yaml.tmpl")
+	valuesFile := filepath.Join(dir, "values.yaml")
+	require.NoError(t, os.WriteFile(tmplFile, []byte(fleetTemplate), 0o600))
+	require.NoError(t, os.WriteFile(valuesFile, []byte(fleetValues), 0o600))
+
+	sys := relayertest.NewSystem(t)
+	log := zaptest.NewLogger(t)
+
+	// Without an environment, the chain ID of the hub is missing.
+	res := sys.Run(log, "config", "render", tmplFile, "--values", valuesFile)
+	require.ErrorContains(t, res.Err, "chain_id")
+
+	res = sys.Run(log, "config", "render", tmplFile, "--values", valuesFile, "--env", "mainnet")
+	require.ErrorContains(t, res.Err, "environment mainnet is not defined")
+
+	res = sys.Run(log, "config", "render", tmplFile, "--values", valuesFile, "--env", "testnet", "--set", "timeout=soon")
+	require.ErrorContains(t, res.Err, "invalid global timeout")
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spf13/cobra"
+)
+
+const flagMinAllowance = "min-allowance"
+
+// queryFeeGrantCmd verifies that the fee granter of a chain grants the relayer an allowance covering its fees.
+func queryFeeGrantCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fee-grant chain_name",
+		Short: "query the allowance the fee-granter of a chain grants the relayer, verifying it covers the fees of relaying",
+		Long: strings.TrimSpace(`Queries the feegrant allowance granted by the fee-granter configured for a chain to the account of the relayer,
+and verifies that it has not expired, allows the messages relaying packets and has at least --min-allowance left to spend,
+in the current period for periodic allowances. The command fails if the grant is missing or insufficient.`),
+		Args: withUsage(cobra.ExactArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s query fee-grant ibc-0
+$ %s q fee-grant ibc-0 --min-allowance 5000000urax`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cp, err := cosmosProvider(a, args[0])
+			if err != nil {
+				return err
+			}
+			minAllowanceStr, err := cmd.Flags().GetString(flagMinAllowance)
+			if err != nil {
+				return err
+			}
+			var minAllowance sdk.Coins
+			if minAllowanceStr != "" {
+				if minAllowance, err = sdk.ParseCoinsNormalized(minAllowanceStr); err != nil {
+					return fmt.Errorf("invalid --%s: %w", flagMinAllowance, err)
+				}
+			}
+
+			g, err := cp.QueryFeeGrant(cmd.Context())
+			if err != nil {
+				return err
+			}
+			out, err := json.MarshalIndent(g, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			return g.Check(time.Now(), minAllowance)
+		},
+	}
+	cmd.Flags().String(flagMinAllowance, "", "minimum amount the grant must have left to spend, e.g. 5000000urax")
+	return cmd
+}
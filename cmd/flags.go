@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/cosmos/relayer/v2/relayer/processor"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -34,6 +35,57 @@ const (
 	flagOrder                   = "order"
 	flagVersion                 = "version"
 	flagDebugAddr               = "debug-addr"
+	flagAdminAddr               = "admin-addr"
+	flagAdminSecretFile         = "admin-secret-file"
+	flagMetricsAddr             = "metrics-addr"
+	flagAckStore                = "ack-store"
+	flagStoreCompaction         = "store-compaction-interval"
+	flagRetentionMaxAge         = "retention-max-age"
+	flagRetentionMaxEntries     = "retention-max-entries"
+	flagChannelDiscovery        = "channel-discovery-interval"
+	flagTimeoutScan             = "timeout-scan-interval"
+	flagMaxConcurrentChannels   = "max-concurrent-channels"
+	flagBatchWindow             = "batch-window"
+	flagDormantChannelAfter     = "dormant-channel-after"
+	flagClientUpdateThreshold   = "client-update-threshold"
+	flagRegisterPayee           = "register-counterparty-payee"
+	flagConsensusStateCheck     = "consensus-state-check-interval"
+	flagMaxConsensusStates      = "max-consensus-states"
+	flagEscrowCheck             = "escrow-check-interval"
+	flagClientChainIDCheck      = "client-chain-id-check-interval"
+	flagTimeoutReport           = "timeout-report-interval"
+	flagTimeoutReportBlocks     = "timeout-report-blocks"
+	flagTimeoutReportWindow     = "timeout-report-window"
+	flagOrderedChannelCheck     = "ordered-channel-check-interval"
+	flagOrderedChannelStall     = "ordered-channel-stall-after"
+	flagUpgradeCheck            = "upgrade-check-interval"
+	flagUpgradeHaltMargin       = "upgrade-halt-margin"
+	flagHeights                 = "heights"
+	flagSigner                  = "signer"
+	flagOutput                  = "output"
+	flagReadOnly                = "read-only"
+	flagFeedWebhook             = "feed-webhook"
+	flagFeedNATS                = "feed-nats"
+	flagFeedKafka               = "feed-kafka"
+	flagFeedStdout              = "feed-stdout"
+	flagFeedSocket              = "feed-unix-socket"
+	flagChangefeedFile          = "changefeed-file"
+	flagChangefeedWebhook       = "changefeed-webhook"
+	flagSettlementFinality      = "settlement-finality"
+	flagRelayDirection          = "relay-direction"
+	flagRepairWebhook           = "notify-webhook"
+	flagSubstitute              = "substitute"
+	flagTitle                   = "title"
+	flagDescription             = "description"
+	flagForce                   = "force"
+	flagScenarios               = "scenarios"
+	flagChannel                 = "channel"
+	flagTimeoutBlocks           = "timeout-blocks"
+	flagWait                    = "wait"
+	flagValues                  = "values"
+	flagEnv                     = "env"
+	flagSet                     = "set"
+	flagWrite                   = "write"
 	flagOverwriteConfig         = "overwrite"
 	flagOffset                  = "offset"
 	flagLimit                   = "limit"
@@ -44,6 +96,9 @@ const (
 	flagReverse                 = "reverse"
 	flagProcessor               = "processor"
 	flagInitialBlockHistory     = "block-history"
+	flagBackfillChunkSize       = "backfill-chunk-size"
+	flagBackfillMaxInFlight     = "backfill-max-in-flight"
+	flagBackfillCheckpoint      = "backfill-checkpoint"
 	flagMemo                    = "memo"
 )
 
@@ -223,6 +278,9 @@ func updateTimeFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	if err := v.BindPFlag(flagThresholdTime, cmd.Flags().Lookup(flagThresholdTime)); err != nil {
 		panic(err)
 	}
+	if err := cmd.Flags().MarkDeprecated(flagThresholdTime, "use --"+flagClientUpdateThreshold+" instead"); err != nil {
+		panic(err)
+	}
 	return cmd
 }
 
@@ -292,6 +350,314 @@ func debugServerFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func adminServerFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagAdminAddr, "", "address to use for admin server. Leave empty to disable admin server.")
+	if err := v.BindPFlag(flagAdminAddr, cmd.Flags().Lookup(flagAdminAddr)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(flagAdminSecretFile, "", "file holding the secret that state-changing admin requests must be signed with")
+	if err := v.BindPFlag(flagAdminSecretFile, cmd.Flags().Lookup(flagAdminSecretFile)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func metricsServerFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagMetricsAddr, "", "address to serve Prometheus metrics on at /metrics. Leave empty to disable metrics server.")
+	if err := v.BindPFlag(flagMetricsAddr, cmd.Flags().Lookup(flagMetricsAddr)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func ackStoreFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Bool(flagAckStore, true, "persist the acknowledgements relayed by the legacy processor under the home directory, to resume from them on restart")
+	if err := v.BindPFlag(flagAckStore, cmd.Flags().Lookup(flagAckStore)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func storeRetentionFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagStoreCompaction, time.Hour, "how often the state persisted under the home directory is pruned and measured, 0 to never do so")
+	cmd.Flags().Duration(flagRetentionMaxAge, 0, "delete persisted acknowledgements and repair records older than this, 0 to keep them regardless of age")
+	cmd.Flags().Int(flagRetentionMaxEntries, 0, "keep at most this many entries in each persisted store, deleting the oldest first, 0 for no limit")
+	if err := v.BindPFlag(flagStoreCompaction, cmd.Flags().Lookup(flagStoreCompaction)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagRetentionMaxAge, cmd.Flags().Lookup(flagRetentionMaxAge)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagRetentionMaxEntries, cmd.Flags().Lookup(flagRetentionMaxEntries)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func clientRefreshFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Float64(flagClientUpdateThreshold, 1.0/3, "update the clients of the paths once the time left until they expire falls below this fraction of their trusting period, 0 to disable")
+	if err := v.BindPFlag(flagClientUpdateThreshold, cmd.Flags().Lookup(flagClientUpdateThreshold)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func registerPayeeFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Bool(flagRegisterPayee, false, "register the relayer address on the other end of each path as counterparty payee on fee-enabled channels before relaying")
+	if err := v.BindPFlag(flagRegisterPayee, cmd.Flags().Lookup(flagRegisterPayee)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func consensusStateJanitorFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagConsensusStateCheck, 0, "how often the consensus states stored for the clients of the paths are counted, 0 to never count them")
+	cmd.Flags().Uint64(flagMaxConsensusStates, 10000, "warn about clients holding more consensus states than this, 0 to never warn")
+	if err := v.BindPFlag(flagConsensusStateCheck, cmd.Flags().Lookup(flagConsensusStateCheck)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagMaxConsensusStates, cmd.Flags().Lookup(flagMaxConsensusStates)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func clientChainIDCheckFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagClientChainIDCheck, 10*time.Minute, "how often the clients of the paths are verified to track the chain id and revision of their live counterparty, 0 to never verify them")
+	if err := v.BindPFlag(flagClientChainIDCheck, cmd.Flags().Lookup(flagClientChainIDCheck)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func pendingTimeoutReportFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagTimeoutReport, 0, "how often the packets of the paths pending relay are checked for approaching their timeout, 0 to never check them")
+	cmd.Flags().Uint64(flagTimeoutReportBlocks, 100, "report the pending packets within this many blocks of their timeout height")
+	cmd.Flags().Duration(flagTimeoutReportWindow, 30*time.Minute, "report the pending packets within this long of their timeout timestamp")
+	if err := v.BindPFlag(flagTimeoutReport, cmd.Flags().Lookup(flagTimeoutReport)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagTimeoutReportBlocks, cmd.Flags().Lookup(flagTimeoutReportBlocks)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagTimeoutReportWindow, cmd.Flags().Lookup(flagTimeoutReportWindow)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func orderedChannelStallFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagOrderedChannelCheck, 0, "how often the next sequence to receive of the ordered channels of the paths is checked for stalls, 0 to never check it")
+	cmd.Flags().Duration(flagOrderedChannelStall, 10*time.Minute, "report an ordered channel stalled once its next sequence to receive did not advance for this long while packets were waiting")
+	if err := v.BindPFlag(flagOrderedChannelCheck, cmd.Flags().Lookup(flagOrderedChannelCheck)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagOrderedChannelStall, cmd.Flags().Lookup(flagOrderedChannelStall)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func upgradeWatchFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagUpgradeCheck, 0, "how often the software upgrades scheduled on the chains of the paths are checked, to pause the paths around their halt height, 0 to never check them")
+	cmd.Flags().Int64(flagUpgradeHaltMargin, 5, "how many blocks before the height of a scheduled upgrade the paths of its chain are paused")
+	if err := v.BindPFlag(flagUpgradeCheck, cmd.Flags().Lookup(flagUpgradeCheck)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagUpgradeHaltMargin, cmd.Flags().Lookup(flagUpgradeHaltMargin)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func exportStateFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringToInt64(flagHeights, nil, "heights to query chains at, by chain ID, e.g. ibc-0=1200, their latest heights otherwise")
+	cmd.Flags().String(flagSigner, "", "chain ID of the chain whose key of the relayer signs the snapshot, the src chain of the first path if unset")
+	cmd.Flags().String(flagOutput, "", "file to write the snapshot to, stdout if unset")
+	if err := v.BindPFlag(flagHeights, cmd.Flags().Lookup(flagHeights)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagSigner, cmd.Flags().Lookup(flagSigner)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagOutput, cmd.Flags().Lookup(flagOutput)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func escrowMonitorFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagEscrowCheck, 0, "how often the escrow account balances of the transfer channels of the paths are queried, 0 to never query them")
+	if err := v.BindPFlag(flagEscrowCheck, cmd.Flags().Lookup(flagEscrowCheck)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func channelDiscoveryFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagChannelDiscovery, time.Minute, "how often the legacy processor looks for channels opened since it started, 0 to never look")
+	if err := v.BindPFlag(flagChannelDiscovery, cmd.Flags().Lookup(flagChannelDiscovery)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func timeoutScanFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagTimeoutScan, time.Minute, "how often the legacy processor scans each channel for timed out packets to relay timeouts for, 0 to never scan")
+	if err := v.BindPFlag(flagTimeoutScan, cmd.Flags().Lookup(flagTimeoutScan)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func maxConcurrentChannelsFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Int(flagMaxConcurrentChannels, 0, "maximum number of channels of a path relayed at once by the legacy processor, 0 for no limit")
+	if err := v.BindPFlag(flagMaxConcurrentChannels, cmd.Flags().Lookup(flagMaxConcurrentChannels)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func batchWindowFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagBatchWindow, 0, "how long the legacy processor gathers the messages of all channels of a path to send them in shared transactions, 0 to send the messages of each channel on their own")
+	if err := v.BindPFlag(flagBatchWindow, cmd.Flags().Lookup(flagBatchWindow)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func dormantChannelFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagDormantChannelAfter, 0, "stop relaying the channels the legacy processor had nothing to relay on for this long, until events show traffic on them again, 0 to always relay all channels")
+	if err := v.BindPFlag(flagDormantChannelAfter, cmd.Flags().Lookup(flagDormantChannelAfter)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func settlementFinalityFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Bool(flagSettlementFinality, false, "only relay packets sent on rollapps once finalized on the settlement layer")
+	if err := v.BindPFlag(flagSettlementFinality, cmd.Flags().Lookup(flagSettlementFinality)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func relayDirectionFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagRelayDirection, "both", "only relay the packets sent on rollapps (settlement) or on the other chains (instant), along with their acknowledgements and timeouts, leaving the other direction to another process")
+	if err := v.BindPFlag(flagRelayDirection, cmd.Flags().Lookup(flagRelayDirection)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func feedFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Bool(flagReadOnly, false, "only observe the paths with the events processor, without sending transactions; no keys are required")
+	if err := v.BindPFlag(flagReadOnly, cmd.Flags().Lookup(flagReadOnly)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().StringSlice(flagFeedWebhook, nil, "URL to post relayer events to, as JSON; may be repeated")
+	if err := v.BindPFlag(flagFeedWebhook, cmd.Flags().Lookup(flagFeedWebhook)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().StringSlice(flagFeedNATS, nil, "nats://host:port/subject to publish relayer events to; may be repeated")
+	if err := v.BindPFlag(flagFeedNATS, cmd.Flags().Lookup(flagFeedNATS)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().StringSlice(flagFeedKafka, nil, "Kafka REST proxy topic URL to produce relayer events to, e.g. http://localhost:8082/topics/relayer-events; may be repeated")
+	if err := v.BindPFlag(flagFeedKafka, cmd.Flags().Lookup(flagFeedKafka)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Bool(flagFeedStdout, false, "write relayer events to stdout, one JSON object per line; logs are written to stderr")
+	if err := v.BindPFlag(flagFeedStdout, cmd.Flags().Lookup(flagFeedStdout)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().StringSlice(flagFeedSocket, nil, "path of a unix socket to listen on, writing relayer events to every client connected to it as JSON lines; may be repeated")
+	if err := v.BindPFlag(flagFeedSocket, cmd.Flags().Lookup(flagFeedSocket)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func changefeedFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagChangefeedFile, "", "file to append the changes of the configuration and runtime state of the paths to, as lines of JSON")
+	if err := v.BindPFlag(flagChangefeedFile, cmd.Flags().Lookup(flagChangefeedFile)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().StringSlice(flagChangefeedWebhook, nil, "URL to post the changes of the configuration and runtime state of the paths to, as JSON; may be repeated")
+	if err := v.BindPFlag(flagChangefeedWebhook, cmd.Flags().Lookup(flagChangefeedWebhook)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func repairFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringSlice(flagRepairWebhook, nil, "URL to post the mapping of old to new identifiers to once the path is repaired, as JSON; may be repeated")
+	if err := v.BindPFlag(flagRepairWebhook, cmd.Flags().Lookup(flagRepairWebhook)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Bool(flagForce, false, "repair the path even if its clients are neither expired nor frozen")
+	if err := v.BindPFlag(flagForce, cmd.Flags().Lookup(flagForce)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func recoverClientFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagSubstitute, "", "active client of the counterparty to recover the client with, a new one being created if unset")
+	if err := v.BindPFlag(flagSubstitute, cmd.Flags().Lookup(flagSubstitute)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(flagTitle, "", "title of the proposal, derived from the client if unset")
+	if err := v.BindPFlag(flagTitle, cmd.Flags().Lookup(flagTitle)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(flagDescription, "", "description of the proposal, derived from the clients if unset")
+	if err := v.BindPFlag(flagDescription, cmd.Flags().Lookup(flagDescription)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func conformanceFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringSlice(flagScenarios, nil, fmt.Sprintf("conformance scenarios to run, all of them if unset: %v", relayer.ConformanceScenarios))
+	if err := v.BindPFlag(flagScenarios, cmd.Flags().Lookup(flagScenarios)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(flagChannel, "", "transfer channel of the source chain to send the packets of the scenarios over, chosen among the open channels of the path if unset")
+	if err := v.BindPFlag(flagChannel, cmd.Flags().Lookup(flagChannel)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Uint64(flagTimeoutBlocks, 10, "timeout height offset of the packet of the timeout scenario, which is left unrelayed until it times out")
+	if err := v.BindPFlag(flagTimeoutBlocks, cmd.Flags().Lookup(flagTimeoutBlocks)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Duration(flagWait, 2*time.Minute, "how long each scenario waits for its packets to be relayed before failing")
+	if err := v.BindPFlag(flagWait, cmd.Flags().Lookup(flagWait)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func configRenderFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagValues, "", "YAML file holding the variables of the template and its environments")
+	if err := v.BindPFlag(flagValues, cmd.Flags().Lookup(flagValues)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(flagEnv, "", "environment of the values file whose variables and overlay to apply")
+	if err := v.BindPFlag(flagEnv, cmd.Flags().Lookup(flagEnv)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().StringArray(flagSet, nil, "set a variable, overriding the values file, as key=value where key may be a dotted path; may be repeated")
+	if err := v.BindPFlag(flagSet, cmd.Flags().Lookup(flagSet)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Bool(flagWrite, false, "write the rendered config to the config file of the home directory instead of printing it")
+	if err := v.BindPFlag(flagWrite, cmd.Flags().Lookup(flagWrite)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
 func processorFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().StringP(flagProcessor, "p", relayer.ProcessorLegacy, "which relayer processor to use")
 	if err := v.BindPFlag(flagProcessor, cmd.Flags().Lookup(flagProcessor)); err != nil {
@@ -301,11 +667,23 @@ func processorFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	if err := v.BindPFlag(flagInitialBlockHistory, cmd.Flags().Lookup(flagInitialBlockHistory)); err != nil {
 		panic(err)
 	}
+	cmd.Flags().Uint64(flagBackfillChunkSize, processor.DefaultBackfillChunkSize, "number of blocks of the initial block history handed over to the 'events' processor at a time")
+	if err := v.BindPFlag(flagBackfillChunkSize, cmd.Flags().Lookup(flagBackfillChunkSize)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Int(flagBackfillMaxInFlight, processor.DefaultBackfillMaxInFlight, "maximum number of blocks of the initial block history queried at once from each chain")
+	if err := v.BindPFlag(flagBackfillMaxInFlight, cmd.Flags().Lookup(flagBackfillMaxInFlight)); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Bool(flagBackfillCheckpoint, false, "checkpoint the backfill of the initial block history under the home directory, to resume it on restart; packets of the blocks backfilled before the restart are not relayed by the 'events' processor, flush the paths to relay them")
+	if err := v.BindPFlag(flagBackfillCheckpoint, cmd.Flags().Lookup(flagBackfillCheckpoint)); err != nil {
+		panic(err)
+	}
 	return cmd
 }
 
 func memoFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
-	cmd.Flags().String(flagMemo, "", "a memo to include in relayed packets")
+	cmd.Flags().String(flagMemo, "", "a memo to include in relayed packets, a text/template evaluated per transaction with {{.Version}}, {{.PathName}}, {{.ChainID}}, {{.Sequence}} and {{.Sequences}}")
 	if err := v.BindPFlag(flagMemo, cmd.Flags().Lookup(flagMemo)); err != nil {
 		panic(err)
 	}
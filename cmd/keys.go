@@ -47,6 +47,10 @@ func keysCmd(a *appState) *cobra.Command {
 		keysListCmd(a),
 		keysShowCmd(a),
 		keysExportCmd(a),
+		keysPubKeyCmd(a),
+		keysAddMultisigCmd(a),
+		keysCoSignCmd(a),
+		keysServeSignerCmd(a),
 	)
 
 	return cmd
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	flagThreshold = "threshold"
+	flagSocket    = "socket"
+	flagMaxFee    = "max-fee"
+)
+
+// cosmosProvider returns the CosmosProvider of the chain, for the commands specific to Cosmos chains.
+func cosmosProvider(a *appState, chainName string) (*cosmos.CosmosProvider, error) {
+	chain, ok := a.Config.Chains[chainName]
+	if !ok {
+		return nil, errChainNotFound(chainName)
+	}
+	cp, ok := chain.ChainProvider.(*cosmos.CosmosProvider)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a CosmosProvider", chainName)
+	}
+	return cp, nil
+}
+
+// keysPubKeyCmd prints the public key of a key, for the other members of a multisig to add it with keys add-multisig.
+func keysPubKeyCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pubkey chain_name key_name",
+		Short: "Shows the public key of a key, as given to keys add-multisig by the other members of a multisig",
+		Args:  withUsage(cobra.ExactArgs(2)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keys pubkey ibc-0 alice`, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cp, err := cosmosProvider(a, args[0])
+			if err != nil {
+				return err
+			}
+			pk, err := cp.MultisigMember(args[1])
+			if err != nil {
+				return err
+			}
+			bz, err := cp.Codec.Marshaler.MarshalInterfaceJSON(pk)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// keysAddMultisigCmd adds a multisig of keys of the keyring and public keys of other members to the keyring.
+func keysAddMultisigCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-multisig chain_name key_name member...",
+		Short: "Adds a multisig of the given members to the keychain, to relay from its account",
+		Long: strings.TrimSpace(`Adds a multisig requiring --threshold signatures of the given members to the keychain of a chain.
+Members are either names of keys of the keychain or public keys, as shown by keys pubkey.
+
+To relay from the multisig account, set the multisig as the key of the chain, and configure the multisig section
+of the chain with the member key the relayer signs with, and the co-signers to gather the other signatures from.`),
+		Args: withUsage(cobra.MinimumNArgs(3)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keys add-multisig ibc-0 treasury alice '{"@type":"/cosmos.crypto.secp256k1.PubKey","key":"..."}' --threshold 2`, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cp, err := cosmosProvider(a, args[0])
+			if err != nil {
+				return err
+			}
+			if cp.KeyExists(args[1]) {
+				return errKeyExists(args[1])
+			}
+			threshold, err := cmd.Flags().GetInt(flagThreshold)
+			if err != nil {
+				return err
+			}
+			members := make([]cryptotypes.PubKey, len(args)-2)
+			for i, m := range args[2:] {
+				if members[i], err = cp.MultisigMember(m); err != nil {
+					return err
+				}
+			}
+			address, err := cp.AddMultisigKey(args[1], threshold, members)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), address)
+			return nil
+		},
+	}
+	cmd.Flags().Int(flagThreshold, 0, "number of signatures of members required by the multisig")
+	return cmd
+}
+
+// keysCoSignCmd co-signs the transactions of a multisig relayer as one of the members of the multisig.
+func keysCoSignCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cosign chain_name key_name [payload_file...]",
+		Short: "Co-signs the transactions of a relayer relaying from a multisig, as one of its members",
+		Long: strings.TrimSpace(`Co-signs the transactions of a relayer relaying from a multisig with a member key of the keychain.
+
+Either co-sign the payloads the relayer exported to its payload-dir, writing the co-signatures next to them,
+or serve the relayer on the unix socket given with --socket, listed under co-signers in its multisig config,
+co-signing its payloads as they come in until interrupted.
+
+Only transactions of IBC client, connection and channel messages of the multisig are co-signed,
+and with --max-fee only those paying at most the given fee.`),
+		Args: withUsage(cobra.MinimumNArgs(2)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keys cosign ibc-0 bob /shared/multisig/ibc-0-42-9f86d081884c7d65.payload.json
+$ %s keys cosign ibc-0 bob --socket /run/rly/bob.sock --max-fee 50000uatom`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cp, err := cosmosProvider(a, args[0])
+			if err != nil {
+				return err
+			}
+			keyName := args[1]
+			if !cp.KeyExists(keyName) {
+				return errKeyDoesntExist(keyName)
+			}
+			maxFeeStr, err := cmd.Flags().GetString(flagMaxFee)
+			if err != nil {
+				return err
+			}
+			maxFee, err := sdk.ParseCoinsNormalized(maxFeeStr)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", flagMaxFee, err)
+			}
+			socket, err := cmd.Flags().GetString(flagSocket)
+			if err != nil {
+				return err
+			}
+
+			if socket == "" {
+				if len(args) == 2 {
+					return fmt.Errorf("either payload files or --%s are required", flagSocket)
+				}
+				for _, file := range args[2:] {
+					sigFile, err := cp.CoSignPayloadFile(file, keyName, maxFee)
+					if err != nil {
+						return fmt.Errorf("failed to co-sign %s: %w", file, err)
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), sigFile)
+				}
+				return nil
+			}
+
+			// A socket left behind by an interrupted co-signer would fail the listen.
+			_ = os.Remove(socket)
+			ln, err := net.Listen("unix", socket)
+			if err != nil {
+				return fmt.Errorf("failed to listen on socket %q: %w", socket, err)
+			}
+			defer os.Remove(socket)
+			a.Log.Info("Co-signer listening", zap.String("socket", socket), zap.String("key", keyName))
+			if err := cp.ServeCoSigner(cmd.Context(), ln, keyName, maxFee); err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(flagSocket, "", "unix socket to serve co-signatures on, instead of co-signing payload files")
+	cmd.Flags().String(flagMaxFee, "", "highest fee of the transactions to co-sign, e.g. 50000uatom")
+	return cmd
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/cespare/permute/v2"
@@ -38,6 +40,10 @@ This includes the client, connection, and channel ids from both the source and d
 		pathsNewCmd(a),
 		pathsFetchCmd(a),
 		pathsDeleteCmd(a),
+		pathsPauseCmd(a, true),
+		pathsPauseCmd(a, false),
+		pathsExportStateCmd(a),
+		pathsRepointClientCmd(a),
 	)
 
 	return cmd
@@ -63,6 +69,156 @@ $ %s pth d path-name`, appName, appName)),
 	return cmd
 }
 
+// pathsPauseCmd returns the command pausing the path in the config if paused, and resuming it otherwise.
+func pathsPauseCmd(a *appState, paused bool) *cobra.Command {
+	use, short := "pause path_name", "Pause relaying the packets of a path, keeping its clients updated"
+	if !paused {
+		use, short = "resume path_name", "Resume relaying the packets of a paused path"
+	}
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long: `Sets whether a path is paused in the config, e.g. during an upgrade of either of its chains.
+The relayer relays no packets nor acknowledgements of paused paths, while their clients are still kept updated.
+A running relayer is paused or resumed through the POST /paused endpoint of its admin API.`,
+		Args: withUsage(cobra.ExactArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s paths %s demo-path`, appName, strings.Fields(use)[0])),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := a.Config.Paths.Get(args[0])
+			if err != nil {
+				return err
+			}
+			p.Paused = paused
+			return a.OverwriteConfig(a.Config)
+		},
+	}
+	return cmd
+}
+
+// pathsRepointClientCmd points an end of a path at another client, such as the substitute of a recovery proposal.
+func pathsRepointClientCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repoint-client path_name chain_id client_id",
+		Short: "Point an end of a path at another client, such as the substitute of a rejected client recovery",
+		Long: `Sets the client of the end of a path on the chain with chain_id, e.g. to the substitute client created by
+rly tx recover-client once the recovery proposal is rejected. The connection IDs of both ends are cleared,
+the connection being built on the previous client, for rly tx link to create a connection on the new client.`,
+		Args: withUsage(cobra.ExactArgs(3)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s paths repoint-client demo-path ibc-0 07-tendermint-7
+$ %s tx link demo-path`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := a.Config.Paths.Get(args[0])
+			if err != nil {
+				return err
+			}
+			if err := p.RepointClient(args[1], args[2]); err != nil {
+				return err
+			}
+			return a.OverwriteConfig(a.Config)
+		},
+	}
+	return cmd
+}
+
+// pathsExportStateCmd writes a signed snapshot of the IBC state of paths, for third-party audits of bridge health.
+func pathsExportStateCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-state [path_name...]",
+		Short: "Export a signed snapshot of the IBC state of paths for audits",
+		Long: `Exports the clients, connections and channels of the given paths, or of all the paths of the config, on both of
+their chains, along with the commitments of the packets pending on their channels, as JSON. Each chain is queried at
+a single height, its latest height unless pinned with --heights. The snapshot is signed with the key of the relayer on
+the --signer chain, so that auditors can verify it was exported by the relayer.`,
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s paths export-state
+$ %s paths export-state demo-path --heights ibc-0=1200,ibc-1=3400 --signer ibc-0 --output snapshot.json`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := args
+			if len(names) == 0 {
+				for name := range a.Config.Paths {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+			}
+			if len(names) == 0 {
+				return fmt.Errorf("no paths configured")
+			}
+
+			var chainIDs []string
+			paths := make([]relayer.NamedPath, len(names))
+			for i, name := range names {
+				pth, err := a.Config.Paths.Get(name)
+				if err != nil {
+					return err
+				}
+				paths[i] = relayer.NamedPath{Name: name, Path: pth}
+				chainIDs = append(chainIDs, pth.Src.ChainID, pth.Dst.ChainID)
+			}
+			chains, err := a.Config.Chains.Gets(chainIDs...)
+			if err != nil {
+				return err
+			}
+
+			signerID, err := cmd.Flags().GetString(flagSigner)
+			if err != nil {
+				return err
+			}
+			if signerID == "" {
+				signerID = paths[0].Path.Src.ChainID
+			}
+			signer, ok := chains[signerID]
+			if !ok {
+				return fmt.Errorf("signer chain %s is not a chain of the exported paths", signerID)
+			}
+			if err := ensureKeysExist(map[string]*relayer.Chain{signerID: signer}); err != nil {
+				return err
+			}
+
+			heights, err := cmd.Flags().GetStringToInt64(flagHeights)
+			if err != nil {
+				return err
+			}
+			if heights == nil {
+				heights = make(map[string]int64)
+			}
+			for chainID, h := range heights {
+				if _, ok := chains[chainID]; !ok {
+					return fmt.Errorf("chain %s of --%s is not a chain of the exported paths", chainID, flagHeights)
+				}
+				if h <= 0 {
+					return fmt.Errorf("height of chain %s must be positive, got %d", chainID, h)
+				}
+			}
+
+			snapshot, err := relayer.SnapshotIBCState(cmd.Context(), paths, chains, heights)
+			if err != nil {
+				return err
+			}
+			signed, err := relayer.SignIBCStateSnapshot(snapshot, signer)
+			if err != nil {
+				return err
+			}
+			out, err := json.MarshalIndent(signed, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			output, err := cmd.Flags().GetString(flagOutput)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+			return os.WriteFile(output, append(out, '\n'), 0o644)
+		},
+	}
+	return exportStateFlags(a.Viper, cmd)
+}
+
 func pathsListCmd(a *appState) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "list",
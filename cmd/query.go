@@ -27,6 +27,7 @@ func queryCmd(a *appState) *cobra.Command {
 		lineBreakCommand(),
 		//queryAccountCmd(),
 		queryBalanceCmd(a),
+		queryFeeGrantCmd(a),
 		queryHeaderCmd(a),
 		queryNodeStateCmd(a),
 		//queryValSetAtHeightCmd(),
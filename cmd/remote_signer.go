@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const flagListen = "listen"
+
+// keysServeSignerCmd serves keys of the keychain as the remote signer of relayers running on other hosts.
+func keysServeSignerCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve-signer chain_name key_name...",
+		Short: "Serves keys of the keychain as the remote signer of relayers, so their private keys are kept off the relayer hosts",
+		Long: strings.TrimSpace(`Serves keys of the keychain of a chain to relayers configuring it as the remote-signer of the chain,
+listening on the unix socket or TCP address given with --listen until interrupted.
+
+The keys are served to any relayer able to connect, for transactions of the chain only: serve them on a unix socket
+or a private network. External signers such as TMKMS or HashiCorp Vault are plugged in through adapters speaking
+the same protocol instead.`),
+		Args: withUsage(cobra.MinimumNArgs(2)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keys serve-signer ibc-0 relayer --listen unix:///run/rly/signer.sock
+$ %s keys serve-signer ibc-0 relayer --listen tcp://10.0.0.5:26660`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cp, err := cosmosProvider(a, args[0])
+			if err != nil {
+				return err
+			}
+			for _, keyName := range args[1:] {
+				if !cp.KeyExists(keyName) {
+					return errKeyDoesntExist(keyName)
+				}
+			}
+			addr, err := cmd.Flags().GetString(flagListen)
+			if err != nil {
+				return err
+			}
+			if addr == "" {
+				return fmt.Errorf("--%s is required", flagListen)
+			}
+			ln, err := cosmos.ListenRemoteSigner(addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %q: %w", addr, err)
+			}
+			defer ln.Close()
+			a.Log.Info("Remote signer listening", zap.String("addr", addr), zap.Strings("keys", args[1:]))
+			if err := cp.ServeRemoteSigner(cmd.Context(), ln, args[1:]); err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(flagListen, "", "address to serve the keys on, unix:///path/to/socket or tcp://host:port")
+	return cmd
+}
@@ -29,12 +29,11 @@ import (
 	"strings"
 	"time"
 
-	zaplogfmt "github.com/jsternberg/zap-logfmt"
+	"github.com/cosmos/relayer/v2/relayer"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"golang.org/x/term"
 )
 
 const (
@@ -128,6 +127,7 @@ func NewRootCmd(log *zap.Logger) *cobra.Command {
 		transactionCmd(a),
 		queryCmd(a),
 		startCmd(a),
+		watchtowerCmd(a),
 		lineBreakCommand(),
 		getVersionCmd(a),
 	)
@@ -181,30 +181,9 @@ func Execute() {
 }
 
 func newRootLogger(format string, debug bool) (*zap.Logger, error) {
-	config := zap.NewProductionEncoderConfig()
-	config.EncodeTime = func(ts time.Time, encoder zapcore.PrimitiveArrayEncoder) {
-		encoder.AppendString(ts.UTC().Format("2006-01-02T15:04:05.000000Z07:00"))
-	}
-	config.LevelKey = "lvl"
-
-	var enc zapcore.Encoder
-	switch format {
-	case "json":
-		enc = zapcore.NewJSONEncoder(config)
-	case "console":
-		enc = zapcore.NewConsoleEncoder(config)
-	case "logfmt":
-		enc = zaplogfmt.NewEncoder(config)
-	case "auto":
-		if term.IsTerminal(int(os.Stderr.Fd())) {
-			// When a user runs relayer in the foreground, use easier to read output.
-			enc = zapcore.NewConsoleEncoder(config)
-		} else {
-			// Otherwise, use consistent logfmt format for simplistic machine processing.
-			enc = zaplogfmt.NewEncoder(config)
-		}
-	default:
-		return nil, fmt.Errorf("unrecognized log format %q", format)
+	enc, err := relayer.NewLogEncoder(format)
+	if err != nil {
+		return nil, err
 	}
 
 	level := zap.InfoLevel
@@ -17,18 +17,24 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math"
 	"net"
+	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/avast/retry-go/v4"
 	"github.com/cosmos/relayer/v2/internal/relaydebug"
 	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/cosmos/relayer/v2/relayer/ackstore"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -38,33 +44,50 @@ import (
 // NOTE: This is basically pseudocode
 func startCmd(a *appState) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "start path_name",
+		Use:     "start path_name...",
 		Aliases: []string{"st"},
-		Short:   "Start the listening relayer on a given path",
-		Args:    withUsage(cobra.ExactArgs(1)),
+		Short:   "Start the listening relayer on the given paths",
+		Args:    withUsage(cobra.MinimumNArgs(1)),
 		Example: strings.TrimSpace(fmt.Sprintf(`
 $ %s start demo-path -p events # to use event processor
+$ %s start demo-path demo-path2 -p events # to relay multiple paths sharing chain processors
 $ %s start demo-path --max-msgs 3
-$ %s start demo-path2 --max-tx-size 10`, appName, appName, appName)),
+$ %s start demo-path2 --max-tx-size 10
+$ %s start demo-path --read-only --feed-webhook http://localhost:8080/events # to only observe and publish packets
+$ %s start demo-path --settlement-finality --relay-direction settlement # to only relay the packets sent on the rollapp`, appName, appName, appName, appName, appName, appName)),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, src, dst, err := a.Config.ChainsFromPath(args[0])
+			var chainIDs []string
+			paths := make([]relayer.NamedPath, len(args))
+			for i, pathName := range args {
+				path, err := a.Config.Paths.Get(pathName)
+				if err != nil {
+					return err
+				}
+				paths[i] = relayer.NamedPath{Name: pathName, Path: path}
+				chainIDs = append(chainIDs, path.Src.ChainID, path.Dst.ChainID)
+			}
+
+			chains, err := a.Config.Chains.Gets(chainIDs...)
 			if err != nil {
 				return err
 			}
 
-			if err = ensureKeysExist(c); err != nil {
+			readOnly, err := cmd.Flags().GetBool(flagReadOnly)
+			if err != nil {
 				return err
 			}
 
-			path := a.Config.Paths.MustGet(args[0])
+			if !readOnly {
+				if err = ensureKeysExist(chains); err != nil {
+					return err
+				}
+			}
 
 			maxTxSize, maxMsgLength, err := GetStartOptions(cmd)
 			if err != nil {
 				return err
 			}
 
-			filter := path.Filter
-
 			debugAddr, err := cmd.Flags().GetString(flagDebugAddr)
 			if err != nil {
 				return err
@@ -82,6 +105,22 @@ $ %s start demo-path2 --max-tx-size 10`, appName, appName, appName)),
 				relaydebug.StartDebugServer(cmd.Context(), log, ln)
 			}
 
+			shutdownTracing, err := a.Config.Global.Tracing.Export(cmd.Context(), a.Log.With(zap.String("sys", "tracing")), Version)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				// The context of the command is done by then, the spans left get a few seconds to be exported.
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := shutdownTracing(ctx); err != nil {
+					a.Log.Warn("Failed to export the last spans", zap.Error(err))
+				}
+			}()
+			if t := a.Config.Global.Tracing; t != nil {
+				a.Log.Info("Exporting traces", zap.String("endpoint", t.Endpoint))
+			}
+
 			processorType, err := cmd.Flags().GetString(flagProcessor)
 			if err != nil {
 				return err
@@ -92,49 +131,341 @@ $ %s start demo-path2 --max-tx-size 10`, appName, appName, appName)),
 				return err
 			}
 
-			rlyErrCh := relayer.StartRelayer(cmd.Context(), a.Log, c[src], c[dst], filter, maxTxSize, maxMsgLength, a.Config.memo(cmd), processorType, initialBlockHistory)
-
-			// NOTE: This block of code is useful for ensuring that the clients tracking each chain do not expire
-			// when there are no packets flowing across the channels. It is currently a source of errors that have been
-			// hard to rectify, so we are just avoiding this code path for now
-			if false {
-				thresholdTime := a.Viper.GetDuration(flagThresholdTime)
-				eg, egCtx := errgroup.WithContext(cmd.Context())
-
-				eg.Go(func() error {
-					for {
-						var timeToExpiry time.Duration
-						if err = retry.Do(func() error {
-							timeToExpiry, err = UpdateClientsFromChains(egCtx, c[src], c[dst], thresholdTime)
-							return err
-						}, retry.Context(egCtx), retry.Attempts(5), retry.Delay(time.Millisecond*500), retry.LastErrorOnly(true), retry.OnRetry(func(n uint, err error) {
-							a.Log.Info(
-								"Failed to update clients from chains",
-								zap.String("src_chain_id", c[src].ChainID()),
-								zap.String("dst_chain_id", c[dst].ChainID()),
-								zap.Uint("attempt", n+1),
-								zap.Uint("max_attempts", relayer.RtyAttNum),
-								zap.Error(err),
-							)
-						})); err != nil {
-							return err
-						}
-						select {
-						case <-time.After(timeToExpiry - thresholdTime):
-							// Nothing to do.
-						case <-egCtx.Done():
-							return egCtx.Err()
-						}
-					}
-				})
-				if err = eg.Wait(); err != nil {
-					a.Log.Warn(
-						"Error updating clients",
-						zap.Error(err),
-					)
+			startOpts := []relayer.StartOption{relayer.WithLogFormat(a.Viper.GetString("log-format"))}
+
+			backfill, err := backfillFromFlags(cmd, a.HomePath)
+			if err != nil {
+				return err
+			}
+			startOpts = append(startOpts, relayer.WithBackfill(backfill))
+
+			adminAddr, err := cmd.Flags().GetString(flagAdminAddr)
+			if err != nil {
+				return err
+			}
+			if adminAddr != "" {
+				ln, err := net.Listen("tcp", adminAddr)
+				if err != nil {
+					return fmt.Errorf("failed to listen on admin address %q: %w", adminAddr, err)
+				}
+				a.Log.Info("Admin server listening", zap.String("addr", adminAddr))
+				startOpts = append(startOpts, relayer.WithAdminListener(ln))
+			}
+
+			adminSecretFile, err := cmd.Flags().GetString(flagAdminSecretFile)
+			if err != nil {
+				return err
+			}
+			if adminSecretFile != "" {
+				bz, err := os.ReadFile(adminSecretFile)
+				if err != nil {
+					return fmt.Errorf("failed to read admin secret file: %w", err)
+				}
+				secret := bytes.TrimSpace(bz)
+				if len(secret) == 0 {
+					return fmt.Errorf("admin secret file %s is empty", adminSecretFile)
+				}
+				startOpts = append(startOpts, relayer.WithAdminSecret(secret))
+			}
+
+			metricsAddr, err := cmd.Flags().GetString(flagMetricsAddr)
+			if err != nil {
+				return err
+			}
+			if metricsAddr != "" {
+				ln, err := net.Listen("tcp", metricsAddr)
+				if err != nil {
+					return fmt.Errorf("failed to listen on metrics address %q: %w", metricsAddr, err)
+				}
+				a.Log.Info("Metrics server listening", zap.String("addr", metricsAddr))
+				startOpts = append(startOpts, relayer.WithMetricsListener(ln))
+			}
+
+			if readOnly {
+				if processorType != relayer.ProcessorEvents {
+					a.Log.Info("Read-only mode uses the events processor", zap.String("processor", processorType))
+				}
+				startOpts = append(startOpts, relayer.WithReadOnly())
+			}
+
+			settlementFinality, err := cmd.Flags().GetBool(flagSettlementFinality)
+			if err != nil {
+				return err
+			}
+			if settlementFinality {
+				startOpts = append(startOpts, relayer.WithSettlementFinality())
+			}
+
+			direction, err := cmd.Flags().GetString(flagRelayDirection)
+			if err != nil {
+				return err
+			}
+			relayDirection, err := relayer.ParseRelayDirection(direction)
+			if err != nil {
+				return err
+			}
+			startOpts = append(startOpts, relayer.WithRelayDirection(relayDirection))
+
+			clientRefresh, err := cmd.Flags().GetFloat64(flagClientUpdateThreshold)
+			if err != nil {
+				return err
+			}
+			startOpts = append(startOpts, relayer.WithClientRefresh(clientRefresh))
+
+			registerPayee, err := cmd.Flags().GetBool(flagRegisterPayee)
+			if err != nil {
+				return err
+			}
+			if registerPayee {
+				startOpts = append(startOpts, relayer.WithCounterpartyPayeeRegistration())
+			}
+
+			consensusStateCheck, err := cmd.Flags().GetDuration(flagConsensusStateCheck)
+			if err != nil {
+				return err
+			}
+			maxConsensusStates, err := cmd.Flags().GetUint64(flagMaxConsensusStates)
+			if err != nil {
+				return err
+			}
+			if consensusStateCheck > 0 {
+				startOpts = append(startOpts, relayer.WithConsensusStateJanitor(consensusStateCheck, maxConsensusStates))
+			}
+
+			escrowCheck, err := cmd.Flags().GetDuration(flagEscrowCheck)
+			if err != nil {
+				return err
+			}
+			if escrowCheck > 0 {
+				startOpts = append(startOpts, relayer.WithEscrowMonitor(escrowCheck))
+			}
+
+			clientChainIDCheck, err := cmd.Flags().GetDuration(flagClientChainIDCheck)
+			if err != nil {
+				return err
+			}
+			if clientChainIDCheck > 0 {
+				startOpts = append(startOpts, relayer.WithClientChainIDCheck(clientChainIDCheck))
+			}
+
+			priceOracle, priceOracleInterval, err := a.Config.Global.PriceOracle.Oracle()
+			if err != nil {
+				return err
+			}
+			if priceOracle != nil {
+				startOpts = append(startOpts, relayer.WithPriceOracle(priceOracle, priceOracleInterval))
+			}
+			if probes := a.Config.Global.HealthProbes; probes != nil {
+				startOpts = append(startOpts, relayer.WithHealthProbes(probes))
+			}
+			if wallets := a.Config.Global.WalletMonitor; wallets != nil {
+				startOpts = append(startOpts, relayer.WithWalletMonitor(wallets))
+			}
+			if maintenance := a.Config.Global.Maintenance; maintenance != nil {
+				startOpts = append(startOpts, relayer.WithMaintenance(maintenance))
+			}
+			if alerts := a.Config.Global.Alerts; alerts != nil {
+				startOpts = append(startOpts, relayer.WithAlerts(alerts))
+			}
+			if releaseCheck := a.Config.Global.ReleaseCheck; releaseCheck != nil {
+				startOpts = append(startOpts, relayer.WithReleaseCheck(releaseCheck))
+			}
+			if stuckPackets := a.Config.Global.StuckPackets; stuckPackets != nil {
+				startOpts = append(startOpts, relayer.WithStuckPackets(stuckPackets))
+			}
+			if flags := a.Config.Global.FeatureFlags; len(flags) > 0 {
+				startOpts = append(startOpts, relayer.WithFeatureFlags(flags))
+			}
+
+			timeoutReport, err := cmd.Flags().GetDuration(flagTimeoutReport)
+			if err != nil {
+				return err
+			}
+			timeoutReportBlocks, err := cmd.Flags().GetUint64(flagTimeoutReportBlocks)
+			if err != nil {
+				return err
+			}
+			timeoutReportWindow, err := cmd.Flags().GetDuration(flagTimeoutReportWindow)
+			if err != nil {
+				return err
+			}
+			if timeoutReport > 0 {
+				startOpts = append(startOpts, relayer.WithPendingTimeoutReport(timeoutReport, timeoutReportBlocks, timeoutReportWindow))
+			}
+
+			orderedChannelCheck, err := cmd.Flags().GetDuration(flagOrderedChannelCheck)
+			if err != nil {
+				return err
+			}
+			orderedChannelStall, err := cmd.Flags().GetDuration(flagOrderedChannelStall)
+			if err != nil {
+				return err
+			}
+			if orderedChannelCheck > 0 {
+				startOpts = append(startOpts, relayer.WithOrderedChannelStallDetection(orderedChannelCheck, orderedChannelStall))
+			}
+
+			upgradeCheck, err := cmd.Flags().GetDuration(flagUpgradeCheck)
+			if err != nil {
+				return err
+			}
+			upgradeHaltMargin, err := cmd.Flags().GetInt64(flagUpgradeHaltMargin)
+			if err != nil {
+				return err
+			}
+			if upgradeHaltMargin < 0 {
+				return fmt.Errorf("--%s must not be negative, got %d", flagUpgradeHaltMargin, upgradeHaltMargin)
+			}
+			if upgradeCheck > 0 {
+				startOpts = append(startOpts, relayer.WithUpgradeWatch(upgradeCheck, upgradeHaltMargin))
+			}
+
+			discoveryInterval, err := cmd.Flags().GetDuration(flagChannelDiscovery)
+			if err != nil {
+				return err
+			}
+			startOpts = append(startOpts, relayer.WithChannelDiscoveryInterval(discoveryInterval))
+
+			timeoutScanInterval, err := cmd.Flags().GetDuration(flagTimeoutScan)
+			if err != nil {
+				return err
+			}
+			startOpts = append(startOpts, relayer.WithTimeoutScanInterval(timeoutScanInterval))
+
+			maxConcurrentChannels, err := cmd.Flags().GetInt(flagMaxConcurrentChannels)
+			if err != nil {
+				return err
+			}
+			if maxConcurrentChannels < 0 {
+				return fmt.Errorf("--%s must not be negative", flagMaxConcurrentChannels)
+			}
+			startOpts = append(startOpts, relayer.WithMaxConcurrentChannels(maxConcurrentChannels))
+
+			batchWindow, err := cmd.Flags().GetDuration(flagBatchWindow)
+			if err != nil {
+				return err
+			}
+			startOpts = append(startOpts, relayer.WithCrossChannelBatching(batchWindow))
+
+			dormantAfter, err := cmd.Flags().GetDuration(flagDormantChannelAfter)
+			if err != nil {
+				return err
+			}
+			if dormantAfter < 0 {
+				return fmt.Errorf("--%s must not be negative", flagDormantChannelAfter)
+			}
+			startOpts = append(startOpts, relayer.WithDormantChannels(dormantAfter))
+
+			useAckStore, err := cmd.Flags().GetBool(flagAckStore)
+			if err != nil {
+				return err
+			}
+			if useAckStore {
+				st, err := ackstore.Open(path.Join(a.HomePath, "data", "acks"))
+				if err != nil {
+					return fmt.Errorf("%w (pass --%s=false if another relayer is running with the same home directory)", err, flagAckStore)
+				}
+				defer st.Close()
+				startOpts = append(startOpts, relayer.WithAckStore(st))
+			}
+
+			compactionInterval, err := cmd.Flags().GetDuration(flagStoreCompaction)
+			if err != nil {
+				return err
+			}
+			retentionMaxAge, err := cmd.Flags().GetDuration(flagRetentionMaxAge)
+			if err != nil {
+				return err
+			}
+			retentionMaxEntries, err := cmd.Flags().GetInt(flagRetentionMaxEntries)
+			if err != nil {
+				return err
+			}
+			if retentionMaxAge < 0 || retentionMaxEntries < 0 {
+				return fmt.Errorf("--%s and --%s must not be negative", flagRetentionMaxAge, flagRetentionMaxEntries)
+			}
+			startOpts = append(startOpts,
+				relayer.WithRepairLog(path.Join(a.HomePath, "data", "repairs.jsonl")),
+				relayer.WithStoreRetention(compactionInterval, relayer.RetentionPolicy{MaxAge: retentionMaxAge, MaxEntries: retentionMaxEntries}),
+			)
+
+			webhooks, err := cmd.Flags().GetStringSlice(flagFeedWebhook)
+			if err != nil {
+				return err
+			}
+			for _, url := range webhooks {
+				startOpts = append(startOpts, relayer.WithFeedSinks(feed.NewWebhookSink(url)))
+			}
+
+			natsURLs, err := cmd.Flags().GetStringSlice(flagFeedNATS)
+			if err != nil {
+				return err
+			}
+			for _, url := range natsURLs {
+				sink, err := feed.NewNATSSink(url)
+				if err != nil {
+					return fmt.Errorf("invalid --%s: %w", flagFeedNATS, err)
+				}
+				startOpts = append(startOpts, relayer.WithFeedSinks(sink))
+			}
+
+			kafkaURLs, err := cmd.Flags().GetStringSlice(flagFeedKafka)
+			if err != nil {
+				return err
+			}
+			for _, url := range kafkaURLs {
+				startOpts = append(startOpts, relayer.WithFeedSinks(feed.NewKafkaRESTSink(url)))
+			}
+
+			feedStdout, err := cmd.Flags().GetBool(flagFeedStdout)
+			if err != nil {
+				return err
+			}
+			if feedStdout {
+				startOpts = append(startOpts, relayer.WithFeedSinks(feed.NewStreamSink("stdout", cmd.OutOrStdout())))
+			}
+
+			sockets, err := cmd.Flags().GetStringSlice(flagFeedSocket)
+			if err != nil {
+				return err
+			}
+			for _, socket := range sockets {
+				sink, err := feed.ListenSocketSink(socket)
+				if err != nil {
+					return fmt.Errorf("invalid --%s: %w", flagFeedSocket, err)
+				}
+				defer sink.Close()
+				startOpts = append(startOpts, relayer.WithFeedSinks(sink))
+			}
+
+			changefeedFile, err := cmd.Flags().GetString(flagChangefeedFile)
+			if err != nil {
+				return err
+			}
+			if changefeedFile != "" {
+				sink, err := feed.OpenFileSink(changefeedFile)
+				if err != nil {
+					return fmt.Errorf("invalid --%s: %w", flagChangefeedFile, err)
 				}
+				defer sink.Close()
+				startOpts = append(startOpts, relayer.WithChangefeedSinks(sink))
 			}
 
+			changefeedWebhooks, err := cmd.Flags().GetStringSlice(flagChangefeedWebhook)
+			if err != nil {
+				return err
+			}
+			for _, url := range changefeedWebhooks {
+				startOpts = append(startOpts, relayer.WithChangefeedSinks(feed.NewWebhookSink(url)))
+			}
+
+			memo := a.Config.memo(cmd)
+			if err := provider.ValidateMemo(memo); err != nil {
+				return err
+			}
+
+			rlyErrCh := relayer.StartRelayer(cmd.Context(), a.Log, chains, paths, maxTxSize, maxMsgLength, memo, processorType, initialBlockHistory, startOpts...)
+
 			// Block until the error channel sends a message.
 			// The context being canceled will cause the relayer to stop,
 			// so we don't want to separately monitor the ctx.Done channel,
@@ -150,14 +481,38 @@ $ %s start demo-path2 --max-tx-size 10`, appName, appName, appName)),
 		},
 	}
 	cmd = updateTimeFlags(a.Viper, cmd)
+	cmd = clientRefreshFlag(a.Viper, cmd)
+	cmd = registerPayeeFlag(a.Viper, cmd)
+	cmd = consensusStateJanitorFlags(a.Viper, cmd)
+	cmd = escrowMonitorFlag(a.Viper, cmd)
+	cmd = clientChainIDCheckFlag(a.Viper, cmd)
+	cmd = pendingTimeoutReportFlags(a.Viper, cmd)
+	cmd = orderedChannelStallFlags(a.Viper, cmd)
+	cmd = upgradeWatchFlags(a.Viper, cmd)
 	cmd = strategyFlag(a.Viper, cmd)
 	cmd = debugServerFlags(a.Viper, cmd)
+	cmd = adminServerFlags(a.Viper, cmd)
+	cmd = metricsServerFlags(a.Viper, cmd)
+	cmd = ackStoreFlag(a.Viper, cmd)
+	cmd = storeRetentionFlags(a.Viper, cmd)
+	cmd = channelDiscoveryFlag(a.Viper, cmd)
+	cmd = timeoutScanFlag(a.Viper, cmd)
+	cmd = maxConcurrentChannelsFlag(a.Viper, cmd)
+	cmd = batchWindowFlag(a.Viper, cmd)
+	cmd = dormantChannelFlag(a.Viper, cmd)
+	cmd = feedFlags(a.Viper, cmd)
+	cmd = changefeedFlags(a.Viper, cmd)
+	cmd = settlementFinalityFlag(a.Viper, cmd)
+	cmd = relayDirectionFlag(a.Viper, cmd)
 	cmd = processorFlags(a.Viper, cmd)
 	cmd = memoFlag(a.Viper, cmd)
 	return cmd
 }
 
 // UpdateClientsFromChains takes src, dst chains, threshold time and update clients based on expiry time
+//
+// Deprecated: `rly start` keeps the clients of its paths from expiring in the background, see
+// relayer.WithClientRefresh. UpdateClientsFromChains is kept for the callers updating clients outside of it.
 func UpdateClientsFromChains(ctx context.Context, src, dst *relayer.Chain, thresholdTime time.Duration) (time.Duration, error) {
 	var (
 		srcTimeExpiry, dstTimeExpiry time.Duration
@@ -218,3 +573,32 @@ func GetStartOptions(cmd *cobra.Command) (uint64, uint64, error) {
 
 	return txSize * MB, msgLen, nil
 }
+
+// backfillFromFlags returns the bounds of the backfill of the initial block history,
+// checkpointed under the data directory of home if requested.
+func backfillFromFlags(cmd *cobra.Command, home string) (processor.Backfill, error) {
+	chunkSize, err := cmd.Flags().GetUint64(flagBackfillChunkSize)
+	if err != nil {
+		return processor.Backfill{}, err
+	}
+	maxInFlight, err := cmd.Flags().GetInt(flagBackfillMaxInFlight)
+	if err != nil {
+		return processor.Backfill{}, err
+	}
+	if chunkSize == 0 || maxInFlight <= 0 {
+		return processor.Backfill{}, fmt.Errorf("--%s and --%s must be positive", flagBackfillChunkSize, flagBackfillMaxInFlight)
+	}
+	backfill := processor.Backfill{ChunkSize: chunkSize, MaxInFlight: maxInFlight}
+
+	checkpoint, err := cmd.Flags().GetBool(flagBackfillCheckpoint)
+	if err != nil {
+		return processor.Backfill{}, err
+	}
+	if checkpoint {
+		backfill.Checkpoints, err = processor.OpenBackfillCheckpoints(path.Join(home, "data", "backfill.json"))
+		if err != nil {
+			return processor.Backfill{}, err
+		}
+	}
+	return backfill, nil
+}
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
@@ -31,8 +34,10 @@ Most of these commands take a [path] argument. Make sure:
 	cmd.AddCommand(
 		linkCmd(a),
 		linkThenStartCmd(a),
+		bootstrapCmd(a),
 		relayMsgsCmd(a),
 		relayAcksCmd(a),
+		flushCmd(a),
 		xfersend(a),
 		lineBreakCommand(),
 		createClientsCmd(a),
@@ -43,7 +48,10 @@ Most of these commands take a [path] argument. Make sure:
 		createConnectionCmd(a),
 		createChannelCmd(a),
 		closeChannelCmd(a),
+		repairCmd(a),
+		recoverClientCmd(a),
 		lineBreakCommand(),
+		conformanceCmd(a),
 
 		//sendCmd(),
 	)
@@ -676,6 +684,390 @@ $ %s tx connect demo-path --src-port transfer --dst-port transfer --order unorde
 	return cmd
 }
 
+func bootstrapCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap path_name",
+		Short: "create clients, connection, and channel of a configured path, resuming handshakes left midway",
+		Long: strings.TrimSpace(`Create the clients, connection, and channel between the two chains of a configured path,
+reusing those already created and resuming handshakes from whichever state they were left in,
+e.g. by a previous link which timed out. Each handshake step is retried up to max-retries times.
+Running it again on a bootstrapped path only prints the identifiers of its clients, connection, and channel.`,
+		),
+		Args: withUsage(cobra.ExactArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s tx bootstrap demo-path
+$ %s tx bootstrap demo-path --src-port transfer --dst-port transfer --order unordered --version ics20-1`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pth, err := a.Config.Paths.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			src, dst := pth.Src.ChainID, pth.Dst.ChainID
+			c, err := a.Config.Chains.Gets(src, dst)
+			if err != nil {
+				return err
+			}
+
+			c[src].PathEnd = pth.Src
+			c[dst].PathEnd = pth.Dst
+
+			opts := relayer.BootstrapOptions{Memo: a.Config.memo(cmd)}
+			if opts.AllowUpdateAfterExpiry, err = cmd.Flags().GetBool(flagUpdateAfterExpiry); err != nil {
+				return err
+			}
+			if opts.AllowUpdateAfterMisbehaviour, err = cmd.Flags().GetBool(flagUpdateAfterMisbehaviour); err != nil {
+				return err
+			}
+			if opts.SrcPortID, err = cmd.Flags().GetString(flagSrcPort); err != nil {
+				return err
+			}
+			if opts.DstPortID, err = cmd.Flags().GetString(flagDstPort); err != nil {
+				return err
+			}
+			if opts.Order, err = cmd.Flags().GetString(flagOrder); err != nil {
+				return err
+			}
+			if opts.Version, err = cmd.Flags().GetString(flagVersion); err != nil {
+				return err
+			}
+			if opts.Timeout, err = getTimeout(cmd); err != nil {
+				return err
+			}
+			if opts.MaxRetries, err = cmd.Flags().GetUint64(flagMaxRetries); err != nil {
+				return err
+			}
+
+			// ensure that keys exist
+			if exists := c[src].ChainProvider.KeyExists(c[src].ChainProvider.Key()); !exists {
+				return fmt.Errorf("key %s not found on src chain %s", c[src].ChainProvider.Key(), c[src].ChainID())
+			}
+			if exists := c[dst].ChainProvider.KeyExists(c[dst].ChainProvider.Key()); !exists {
+				return fmt.Errorf("key %s not found on dst chain %s", c[dst].ChainProvider.Key(), c[dst].ChainID())
+			}
+
+			res, err := c[src].BootstrapPath(cmd.Context(), c[dst], opts)
+			// save the clients and connection created even if the bootstrap failed part way, for it to be resumed.
+			if res != nil && res.Modified {
+				if err := a.OverwriteConfig(a.Config); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("error bootstrapping path %s: %w", args[0], err)
+			}
+
+			out, err := json.MarshalIndent(res, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			return nil
+		},
+	}
+	cmd = timeoutFlag(a.Viper, cmd)
+	cmd = retryFlag(a.Viper, cmd)
+	cmd = clientParameterFlags(a.Viper, cmd)
+	cmd = channelParameterFlags(a.Viper, cmd)
+	cmd = memoFlag(a.Viper, cmd)
+	return cmd
+}
+
+func repairCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repair path_name",
+		Short: "replace expired or frozen clients of a path, reopening its channels behind a new connection",
+		Long: strings.TrimSpace(`Once a client of the path is expired or frozen beyond recovery, create a fresh pair
+of clients and a connection between the two networks, and reopen every open channel of the old connection
+on the new one, with the same ports, ordering and version.
+
+The path is updated with the new identifiers, including literal channel IDs of its channel filter and monitor.
+The mapping of old to new identifiers is appended to data/repairs.jsonl in the home directory,
+and posted to each --notify-webhook so that applications can migrate to the new channels.
+
+A path declaring depends-on is only repaired once the paths it depends on are ready,
+so paths are repaired in dependency order.`,
+		),
+		Args: withUsage(cobra.ExactArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s tx repair demo-path
+$ %s tx repair demo-path --notify-webhook https://wallet.example.com/ibc/repairs`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pth, err := a.Config.Paths.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			src, dst := pth.Src.ChainID, pth.Dst.ChainID
+			c, err := a.Config.Chains.Gets(src, dst)
+			if err != nil {
+				return err
+			}
+
+			c[src].PathEnd = pth.Src
+			c[dst].PathEnd = pth.Dst
+
+			to, err := getTimeout(cmd)
+			if err != nil {
+				return err
+			}
+
+			retries, err := cmd.Flags().GetUint64(flagMaxRetries)
+			if err != nil {
+				return err
+			}
+
+			force, err := cmd.Flags().GetBool(flagForce)
+			if err != nil {
+				return err
+			}
+
+			webhooks, err := cmd.Flags().GetStringSlice(flagRepairWebhook)
+			if err != nil {
+				return err
+			}
+
+			if err := ensureKeysExist(c); err != nil {
+				return err
+			}
+
+			// Paths are repaired in dependency order, the path relying on the clients of the paths it depends on.
+			if err := relayer.PathDependenciesReady(cmd.Context(), a.Config.Paths, a.Config.Chains, args[0]); err != nil {
+				return fmt.Errorf("%w, repair it first", err)
+			}
+
+			// RepairPath clears the identifiers of the path ends, which are restored if the repair fails part way,
+			// so that the config is never left pointing at a half-built path.
+			oldSrc, oldDst := *pth.Src, *pth.Dst
+			rec, err := c[src].RepairPath(cmd.Context(), c[dst], args[0], retries, to, force, a.Config.memo(cmd))
+			if err != nil {
+				*pth.Src, *pth.Dst = oldSrc, oldDst
+				if rec != nil {
+					a.Log.Warn("Path repair failed part way", zap.Any("created_so_far", rec))
+				}
+				return fmt.Errorf("error repairing path %s: %w", args[0], err)
+			}
+
+			pth.ApplyRepair(rec)
+			if err := a.OverwriteConfig(a.Config); err != nil {
+				return err
+			}
+			if err := relayer.SaveRepairRecord(path.Join(a.HomePath, "data", "repairs.jsonl"), rec); err != nil {
+				return fmt.Errorf("failed to save repair record: %w", err)
+			}
+
+			out, err := json.MarshalIndent(rec, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(out))
+
+			if len(webhooks) > 0 {
+				if err := relayer.NotifyRepair(cmd.Context(), webhooks, rec); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd = timeoutFlag(a.Viper, cmd)
+	cmd = retryFlag(a.Viper, cmd)
+	cmd = repairFlags(a.Viper, cmd)
+	cmd = memoFlag(a.Viper, cmd)
+	return cmd
+}
+
+func recoverClientCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recover-client path_name chain_id deposit",
+		Short: "submit a governance proposal recovering the expired or frozen client of a path end with a substitute client",
+		Long: strings.TrimSpace(`Submit on the chain with chain_id a governance proposal to recover the expired or frozen client
+of its end of the path with the state of a substitute client, an active client of the same counterparty,
+depositing deposit. Once the proposal passes, the client is updated again and the path relayed unchanged,
+its connection and channels being kept.
+
+Unless --substitute is set, a new client of the counterparty is created to substitute for the client.
+The substitute must have the same parameters as the client other than its chain ID and trusting period,
+which is checked before submitting the proposal, and must be kept updated until the proposal passes.
+
+Should the proposal be rejected, the path can be pointed at the substitute with rly paths repoint-client,
+or be repaired with rly tx repair.`,
+		),
+		Args: withUsage(cobra.ExactArgs(3)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s tx recover-client demo-path ibc-0 10000000stake
+$ %s tx recover-client demo-path ibc-0 10000000stake --substitute 07-tendermint-7 --title "Recover the osmosis client"`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pth, err := a.Config.Paths.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			src, dst := pth.Src.ChainID, pth.Dst.ChainID
+			if args[1] == dst {
+				src, dst = dst, src
+			} else if args[1] != src {
+				return fmt.Errorf("chain %s is not an end of path %s", args[1], args[0])
+			}
+			c, err := a.Config.Chains.Gets(src, dst)
+			if err != nil {
+				return err
+			}
+			c[pth.Src.ChainID].PathEnd = pth.Src
+			c[pth.Dst.ChainID].PathEnd = pth.Dst
+
+			deposit, err := sdk.ParseCoinsNormalized(args[2])
+			if err != nil {
+				return err
+			}
+
+			substitute, err := cmd.Flags().GetString(flagSubstitute)
+			if err != nil {
+				return err
+			}
+			title, err := cmd.Flags().GetString(flagTitle)
+			if err != nil {
+				return err
+			}
+			description, err := cmd.Flags().GetString(flagDescription)
+			if err != nil {
+				return err
+			}
+
+			if err := ensureKeysExist(c); err != nil {
+				return err
+			}
+
+			memo := a.Config.memo(cmd)
+			if substitute == "" {
+				if substitute, err = c[src].CreateSubstituteClient(cmd.Context(), c[dst], memo); err != nil {
+					return fmt.Errorf("error creating substitute client: %w", err)
+				}
+			}
+
+			rec, err := c[src].ProposeClientRecovery(cmd.Context(), args[0], substitute, deposit, title, description, memo)
+			if err != nil {
+				return fmt.Errorf("error proposing recovery of client %s: %w", c[src].ClientID(), err)
+			}
+
+			out, err := json.MarshalIndent(rec, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			return nil
+		},
+	}
+	cmd = recoverClientFlags(a.Viper, cmd)
+	cmd = memoFlag(a.Viper, cmd)
+	return cmd
+}
+
+func conformanceCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conformance path_name amount",
+		Short: "run the conformance suite against the chains of a path and report which scenarios pass",
+		Long: strings.TrimSpace(`Run a battery of scenarios against the two chains of a path, to validate a new deployment
+before mainnet: client updates, unordered packets, acknowledgements, timeouts, ordered packets and ICS-29 fee packets.
+Each packet scenario transfers the amount from the source chain of the path to the relayer address on the
+destination chain, and relays it with the legacy processor logic.
+
+A scenario the path cannot run, such as ordered packets on a path without an ORDERED channel, is skipped.
+The command fails if any scenario fails. The suite sends transactions on both chains: run it against test chains.`,
+		),
+		Args: withUsage(cobra.ExactArgs(2)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s tx conformance demo-path 1000utest
+$ %s tx conformance demo-path 1000utest --scenarios timeout,acknowledgement --timeout-blocks 20
+$ %s tx conformance demo-path 1000utest --channel channel-0 --wait 5m -j`,
+			appName, appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pth, err := a.Config.Paths.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			src, dst := pth.Src.ChainID, pth.Dst.ChainID
+			c, err := a.Config.Chains.Gets(src, dst)
+			if err != nil {
+				return err
+			}
+
+			c[src].PathEnd = pth.Src
+			c[dst].PathEnd = pth.Dst
+
+			amount, err := sdk.ParseCoinNormalized(args[1])
+			if err != nil {
+				return err
+			}
+
+			opts := relayer.ConformanceOptions{Amount: amount, Memo: a.Config.memo(cmd)}
+			if opts.Scenarios, err = cmd.Flags().GetStringSlice(flagScenarios); err != nil {
+				return err
+			}
+			if err := relayer.ValidateConformanceScenarios(opts.Scenarios); err != nil {
+				return err
+			}
+			if opts.Channel, err = cmd.Flags().GetString(flagChannel); err != nil {
+				return err
+			}
+			if opts.TimeoutBlocks, err = cmd.Flags().GetUint64(flagTimeoutBlocks); err != nil {
+				return err
+			}
+			if opts.WaitTimeout, err = cmd.Flags().GetDuration(flagWait); err != nil {
+				return err
+			}
+			if opts.MaxTxSize, opts.MaxMsgLength, err = GetStartOptions(cmd); err != nil {
+				return err
+			}
+
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+
+			if err := ensureKeysExist(c); err != nil {
+				return err
+			}
+
+			report, err := relayer.RunConformance(cmd.Context(), a.Log, args[0], c[src], c[dst], opts)
+			if err != nil {
+				return err
+			}
+
+			if jsn {
+				out, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "conformance of path %s (%s -> %s)\n", report.Path, report.SrcChainID, report.DstChainID)
+				for _, res := range report.Results {
+					fmt.Fprintf(cmd.OutOrStdout(), "%-4s  %-16s  %6.1fs  %s\n", res.Status, res.Scenario, res.DurationSeconds, res.Detail)
+				}
+			}
+
+			if n := report.Failed(); n > 0 {
+				return fmt.Errorf("%d of %d conformance scenarios failed", n, len(report.Results))
+			}
+			return nil
+		},
+	}
+	cmd = conformanceFlags(a.Viper, cmd)
+	cmd = strategyFlag(a.Viper, cmd)
+	cmd = jsonFlag(a.Viper, cmd)
+	cmd = memoFlag(a.Viper, cmd)
+	return cmd
+}
+
 func linkThenStartCmd(a *appState) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "link-then-start path_name",
@@ -754,7 +1146,7 @@ $ %s tx relay-pkts demo-path channel-0`,
 			}
 			sp := relayer.UnrelayedSequences(cmd.Context(), c[src], c[dst], srch, dsth, channel)
 
-			if err = relayer.RelayPackets(cmd.Context(), a.Log, c[src], c[dst], srch, dsth, sp, maxTxSize, maxMsgLength, a.Config.memo(cmd), channel); err != nil {
+			if _, err = relayer.RelayPackets(cmd.Context(), a.Log, c[src], c[dst], srch, dsth, sp, maxTxSize, maxMsgLength, a.Config.memo(cmd), channel); err != nil {
 				return err
 			}
 
@@ -807,7 +1199,7 @@ $ %s tx relay-acks demo-path channel-0 -l 3 -s 6`,
 			}
 			sp := relayer.UnrelayedAcknowledgements(cmd.Context(), c[src], c[dst], srch, dsth, channel)
 
-			if err = relayer.RelayAcknowledgements(cmd.Context(), a.Log, c[src], c[dst], srch, dsth, sp, maxTxSize, maxMsgLength, a.Config.memo(cmd), channel); err != nil {
+			if _, err = relayer.RelayAcknowledgements(cmd.Context(), a.Log, c[src], c[dst], srch, dsth, sp, maxTxSize, maxMsgLength, a.Config.memo(cmd), channel); err != nil {
 				return err
 			}
 
@@ -820,6 +1212,102 @@ $ %s tx relay-acks demo-path channel-0 -l 3 -s 6`,
 	return cmd
 }
 
+func flushCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flush path_name...",
+		Short: "relay all pending packets and acknowledgements on the filtered channels of the given paths once, then exit",
+		Long: strings.TrimSpace(`Relay all packets and acknowledgements pending on the filtered channels of the given paths once,
+in both directions unless --relay-direction selects one, applying their packet filters and rate limits, then exit.
+
+The command fails if any packets or acknowledgements remain unrelayed afterwards, listing them,
+so it can be run from cron jobs and CI pipelines. Packets skipped by the packet filter of their path
+are not reported, and paths paused in the config are not flushed.`,
+		),
+		Args: withUsage(cobra.MinimumNArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s tx flush demo-path
+$ %s tx flush demo-path demo-path2 --settlement-finality
+$ %s tx flush demo-path --relay-direction settlement
+$ %s tx flush demo-path -j`,
+			appName, appName, appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var chainIDs []string
+			paths := make([]relayer.NamedPath, len(args))
+			for i, pathName := range args {
+				pth, err := a.Config.Paths.Get(pathName)
+				if err != nil {
+					return err
+				}
+				paths[i] = relayer.NamedPath{Name: pathName, Path: pth}
+				chainIDs = append(chainIDs, pth.Src.ChainID, pth.Dst.ChainID)
+			}
+
+			chains, err := a.Config.Chains.Gets(chainIDs...)
+			if err != nil {
+				return err
+			}
+			if err := ensureKeysExist(chains); err != nil {
+				return err
+			}
+
+			maxTxSize, maxMsgLength, err := GetStartOptions(cmd)
+			if err != nil {
+				return err
+			}
+
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+
+			opts := []relayer.StartOption{relayer.WithOneShot()}
+			settlementFinality, err := cmd.Flags().GetBool(flagSettlementFinality)
+			if err != nil {
+				return err
+			}
+			if settlementFinality {
+				opts = append(opts, relayer.WithSettlementFinality())
+			}
+
+			direction, err := cmd.Flags().GetString(flagRelayDirection)
+			if err != nil {
+				return err
+			}
+			relayDirection, err := relayer.ParseRelayDirection(direction)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, relayer.WithRelayDirection(relayDirection))
+
+			err = <-relayer.StartRelayer(cmd.Context(), a.Log, chains, paths, maxTxSize, maxMsgLength, a.Config.memo(cmd), relayer.ProcessorLegacy, 0, opts...)
+
+			var unrelayed *relayer.UnrelayedError
+			if errors.As(err, &unrelayed) {
+				if jsn {
+					out, err := json.MarshalIndent(unrelayed.Channels, "", "  ")
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				} else {
+					for _, c := range unrelayed.Channels {
+						fmt.Fprintf(cmd.OutOrStdout(), "%s %s/%s packets: %v acknowledgements: %v\n", c.Path, c.PortID, c.ChannelID, c.Packets, c.Acks)
+					}
+				}
+			}
+			return err
+		},
+	}
+
+	cmd = strategyFlag(a.Viper, cmd)
+	cmd = memoFlag(a.Viper, cmd)
+	cmd = settlementFinalityFlag(a.Viper, cmd)
+	cmd = relayDirectionFlag(a.Viper, cmd)
+	cmd = jsonFlag(a.Viper, cmd)
+	return cmd
+}
+
 // TODO still needs a revisit
 //func upgradeChainCmd() *cobra.Command {
 //	cmd := &cobra.Command{
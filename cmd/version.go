@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/cosmos/relayer/v2/internal/relaydebug"
+	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -17,6 +18,10 @@ var (
 	Version = ""
 )
 
+func init() {
+	provider.RelayerVersion = Version
+}
+
 type versionInfo struct {
 	Version   string `json:"version" yaml:"version"`
 	Commit    string `json:"commit" yaml:"commit"`
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// watchtowerCmd monitors the clients of rollapps on a hub, as configured under watchtower in the global config.
+func watchtowerCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watchtower",
+		Short: "Monitor the clients of rollapps on a hub and alert on freezes, expiries and stalls, without relaying or keys",
+		Long: strings.TrimSpace(`Monitor the clients of rollapps hosted on a hub, as configured under watchtower in the
+global config, alerting when a client is frozen, expired or about to expire, no longer updated, or tracks
+another chain than expected. Alerts are logged, posted to the configured webhooks and reported in the metrics.
+
+No keys are needed: the watchtower only queries the hub, so it can be run independently of the relaying operator.`),
+		Args: withUsage(cobra.NoArgs),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s watchtower
+$ %s watchtower --metrics-addr localhost:5184`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := a.Config.Global.Watchtower
+			if cfg == nil {
+				return fmt.Errorf("no watchtower is configured, add a watchtower section to the global config")
+			}
+			hub, ok := a.Config.Chains[cfg.Hub]
+			if !ok {
+				return errChainNotFound(cfg.Hub)
+			}
+
+			var metricsListener net.Listener
+			metricsAddr, err := cmd.Flags().GetString(flagMetricsAddr)
+			if err != nil {
+				return err
+			}
+			if metricsAddr != "" {
+				metricsListener, err = net.Listen("tcp", metricsAddr)
+				if err != nil {
+					return fmt.Errorf("failed to listen on metrics address %q: %w", metricsAddr, err)
+				}
+				a.Log.Info("Metrics server listening", zap.String("addr", metricsAddr))
+			}
+
+			err = relayer.RunWatchtower(cmd.Context(), a.Log, hub, *cfg, metricsListener)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+			return nil
+		},
+	}
+	return metricsServerFlags(a.Viper, cmd)
+}
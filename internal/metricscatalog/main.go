@@ -0,0 +1,19 @@
+// Command metricscatalog prints the catalog of the metrics published by the relayer as JSON,
+// see docs/metrics_catalog.json, which is generated with `make metrics-catalog`.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+)
+
+func main() {
+	bz, err := processor.MarshalMetricsCatalog()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(bz)
+}
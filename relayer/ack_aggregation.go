@@ -0,0 +1,56 @@
+package relayer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+)
+
+// AckAggregation holds back the acknowledgements relayed over the channels of a path for a short window, e.g. 5s,
+// so that the acknowledgements of high-throughput channels are relayed together in fewer transactions,
+// trading a little latency for less gas. Only the events processor aggregates acknowledgements,
+// the legacy processor batches the messages of all channels of a path with rly start --batch-window.
+type AckAggregation struct {
+	// Window is how long the oldest acknowledgement pending relay over a channel waits for others,
+	// 0 to relay acknowledgements right away.
+	Window time.Duration `yaml:"window" json:"window"`
+
+	// Channels override the window of the channels they match, the first matching one applying.
+	Channels []ChannelAckAggregation `yaml:"channels,omitempty" json:"channels,omitempty"`
+}
+
+// ChannelAckAggregation overrides the aggregation window of the acknowledgements relayed over a channel.
+type ChannelAckAggregation struct {
+	// Channel follows the syntax of ChannelFilter entries, matching the channels of the src chain of the path.
+	Channel string        `yaml:"channel" json:"channel"`
+	Window  time.Duration `yaml:"window" json:"window"`
+}
+
+// aggregator returns the processor aggregator of the acknowledgements of the path whose src chain is srcChainID,
+// nil if acknowledgements are never held back, or an error if a window is invalid.
+func (a *AckAggregation) aggregator(srcChainID string) (*processor.AckAggregator, error) {
+	if a == nil {
+		return nil, nil
+	}
+	if a.Window < 0 {
+		return nil, fmt.Errorf("invalid ack aggregation window %s", a.Window)
+	}
+	aggregates := a.Window > 0
+	channels := make([]processor.AckWindow, 0, len(a.Channels))
+	for _, c := range a.Channels {
+		if c.Channel == "" {
+			return nil, fmt.Errorf("ack aggregation window %s has no channel", c.Window)
+		}
+		if c.Window < 0 {
+			return nil, fmt.Errorf("invalid ack aggregation window %s of channel %s", c.Window, c.Channel)
+		}
+		aggregates = aggregates || c.Window > 0
+		portID, channelID := parseChannelFilterEntry(c.Channel)
+		channels = append(channels, processor.AckWindow{PortID: portID, ChannelID: channelID, Window: c.Window})
+	}
+	if !aggregates {
+		return nil, nil
+	}
+	return processor.NewAckAggregator(srcChainID, a.Window, channels), nil
+}
@@ -0,0 +1,82 @@
+package relayer
+
+import (
+	"context"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/ackstore"
+	"go.uber.org/zap"
+)
+
+type ackStoreKey struct{}
+
+// withAckStore persists the acknowledgements relayed by the legacy processor with ctx to st,
+// along with a checkpoint of the acknowledgements being relayed, see inFlightAckSequences.
+func withAckStore(ctx context.Context, st *ackstore.Store) context.Context {
+	if st == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, ackStoreKey{}, st)
+}
+
+func ackStoreFromContext(ctx context.Context) *ackstore.Store {
+	st, _ := ctx.Value(ackStoreKey{}).(*ackstore.Store)
+	return st
+}
+
+// ackCheckpointGrace is how long the acknowledgements of a checkpoint left by an interrupted relay are not relayed again,
+// giving the transaction which may carry them time to be included.
+const ackCheckpointGrace = time.Minute
+
+// inFlightAckSequences returns the acknowledgements relayed from the channel end of c by a relay interrupted less than
+// ackCheckpointGrace before now, e.g. by a crash, according to the checkpoint in the ack store attached to ctx.
+// Relays completed by this process clear their checkpoint, so only an interrupted relay leaves one behind.
+func inFlightAckSequences(ctx context.Context, log *zap.Logger, c *Chain, channelID, portID string, now time.Time) map[uint64]bool {
+	cp, err := ackStoreFromContext(ctx).Checkpoint(c.ChainID(), portID, channelID)
+	if err != nil {
+		log.Warn(
+			"Failed to load acknowledgements checkpoint",
+			zap.String("chain_id", c.ChainID()),
+			zap.String("channel_id", channelID),
+			zap.String("port_id", portID),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if len(cp.Sequences) == 0 || now.Sub(cp.Time) >= ackCheckpointGrace {
+		return nil
+	}
+	inFlight := make(map[uint64]bool, len(cp.Sequences))
+	for _, seq := range cp.Sequences {
+		inFlight[seq] = true
+	}
+	return inFlight
+}
+
+// checkpointAckSequences persists the acknowledgement sequences about to be relayed from the channel end of c
+// to the ack store attached to ctx, if any, the zero checkpoint clearing it.
+func checkpointAckSequences(ctx context.Context, log *zap.Logger, c *Chain, channelID, portID string, cp ackstore.Checkpoint) {
+	if err := ackStoreFromContext(ctx).SetCheckpoint(c.ChainID(), portID, channelID, cp); err != nil {
+		log.Warn(
+			"Failed to persist acknowledgements checkpoint",
+			zap.String("chain_id", c.ChainID()),
+			zap.String("channel_id", channelID),
+			zap.String("port_id", portID),
+			zap.Error(err),
+		)
+	}
+}
+
+// storeRelayedAckSequences persists the acknowledgement sequences relayed from the channel end of c
+// to the ack store attached to ctx, if any.
+func storeRelayedAckSequences(ctx context.Context, log *zap.Logger, c *Chain, channelID, portID string, seqs []uint64) {
+	if err := ackStoreFromContext(ctx).Add(c.ChainID(), portID, channelID, seqs); err != nil {
+		log.Warn(
+			"Failed to persist relayed acknowledgements",
+			zap.String("chain_id", c.ChainID()),
+			zap.String("channel_id", channelID),
+			zap.String("port_id", portID),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,116 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/ackstore"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestInFlightAckSequences(t *testing.T) {
+	st, err := ackstore.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+
+	log := zaptest.NewLogger(t)
+	ctx := withAckStore(context.Background(), st)
+	c := &Chain{ChainProvider: &cosmos.CosmosProvider{PCfg: cosmos.CosmosProviderConfig{ChainID: "chain-a"}}}
+	now := time.Now()
+
+	// Nothing is in flight before the first acknowledgements are relayed.
+	require.Empty(t, inFlightAckSequences(ctx, log, c, "channel-0", "transfer", now))
+
+	checkpointAckSequences(ctx, log, c, "channel-0", "transfer", ackstore.Checkpoint{Sequences: []uint64{2, 5}, Time: now})
+	require.Equal(t, map[uint64]bool{2: true, 5: true}, inFlightAckSequences(ctx, log, c, "channel-0", "transfer", now.Add(time.Second)))
+
+	// Once the grace period is over, the acknowledgements are relayed again if still unreceived.
+	require.Empty(t, inFlightAckSequences(ctx, log, c, "channel-0", "transfer", now.Add(ackCheckpointGrace)))
+
+	checkpointAckSequences(ctx, log, c, "channel-0", "transfer", ackstore.Checkpoint{})
+	require.Empty(t, inFlightAckSequences(ctx, log, c, "channel-0", "transfer", now))
+
+	// Without a store, nothing is ever in flight.
+	checkpointAckSequences(context.Background(), log, c, "channel-0", "transfer", ackstore.Checkpoint{Sequences: []uint64{7}, Time: now})
+	require.Empty(t, inFlightAckSequences(context.Background(), log, c, "channel-0", "transfer", now))
+}
+
+// ackStateProvider serves the commitments, acknowledgements and acknowledgements received of a channel end.
+type ackStateProvider struct {
+	provider.ChainProvider
+	chainID     string
+	commitments []uint64
+	acks        []uint64
+	received    map[uint64]bool
+}
+
+func (p *ackStateProvider) ChainId() string { return p.chainID }
+
+func (p *ackStateProvider) QueryPacketCommitments(_ context.Context, _ uint64, channelID, portID string) ([]*chantypes.PacketState, error) {
+	commitments := []*chantypes.PacketState{}
+	for _, seq := range p.commitments {
+		commitments = append(commitments, &chantypes.PacketState{PortId: portID, ChannelId: channelID, Sequence: seq})
+	}
+	return commitments, nil
+}
+
+func (p *ackStateProvider) QueryPacketAcknowledgements(_ context.Context, _ uint64, channelID, portID string, seqs []uint64) ([]*chantypes.PacketState, error) {
+	acks := []*chantypes.PacketState{}
+	for _, seq := range seqs {
+		for _, ack := range p.acks {
+			if ack == seq {
+				acks = append(acks, &chantypes.PacketState{PortId: portID, ChannelId: channelID, Sequence: seq})
+			}
+		}
+	}
+	return acks, nil
+}
+
+func (p *ackStateProvider) QueryUnreceivedAcknowledgements(_ context.Context, _ uint64, _, _ string, seqs []uint64) ([]uint64, error) {
+	unreceived := []uint64{}
+	for _, seq := range seqs {
+		if !p.received[seq] {
+			unreceived = append(unreceived, seq)
+		}
+	}
+	return unreceived, nil
+}
+
+func TestUnrelayedAcknowledgements(t *testing.T) {
+	ctx := context.Background()
+
+	// dst sent packets 1 to 9. Those still committed are 3 and 6 to 9, of which src received 3, 6 and 8,
+	// and dst received the acknowledgement of 6 since it was queried.
+	// Acknowledgements far below the latest ones, e.g. 3, are found all the same.
+	src := &Chain{log: zap.NewNop(), ChainProvider: &ackStateProvider{
+		chainID: "chain-a",
+		acks:    []uint64{1, 2, 3, 4, 5, 6, 8},
+	}}
+	dst := &Chain{log: zap.NewNop(), ChainProvider: &ackStateProvider{
+		chainID:     "chain-b",
+		commitments: []uint64{9, 3, 6, 7, 8},
+		received:    map[uint64]bool{6: true},
+	}}
+
+	sequences, err := unrelayedAcknowledgements(ctx,
+		src, "channel-0", "transfer", 10,
+		dst, "channel-1", "transfer", 10,
+	)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 8}, sequences)
+
+	// Nothing is pending once dst cleared its commitments, whatever was relayed before.
+	dst.ChainProvider.(*ackStateProvider).commitments = nil
+	sequences, err = unrelayedAcknowledgements(ctx,
+		src, "channel-0", "transfer", 10,
+		dst, "channel-1", "transfer", 10,
+	)
+	require.NoError(t, err)
+	require.Empty(t, sequences)
+}
@@ -0,0 +1,65 @@
+package ackstore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Checkpoint records the acknowledgements of a channel end being relayed, persisted before they are broadcast
+// and cleared once the relay completed, so that a relayer restarted in the meantime knows which acknowledgements
+// may already be on their way to the counterparty. The zero Checkpoint records no relay in flight.
+type Checkpoint struct {
+	Sequences []uint64
+	Time      time.Time
+}
+
+func checkpointKey(chainID, portID, channelID string) []byte {
+	return channelPrefix("cp", chainID, portID, channelID)
+}
+
+// Checkpoint returns the checkpoint of the channel end, or the zero Checkpoint if none is persisted.
+func (s *Store) Checkpoint(chainID, portID, channelID string) (Checkpoint, error) {
+	if s == nil {
+		return Checkpoint{}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bz, err := s.db.Get(checkpointKey(chainID, portID, channelID), nil)
+	switch {
+	case err == leveldb.ErrNotFound:
+		return Checkpoint{}, nil
+	case err != nil:
+		return Checkpoint{}, err
+	case len(bz) < 8 || len(bz)%8 != 0:
+		return Checkpoint{}, fmt.Errorf("invalid checkpoint for %s/%s on %s", portID, channelID, chainID)
+	}
+	cp := Checkpoint{Time: time.Unix(0, int64(binary.BigEndian.Uint64(bz)))}
+	for i := 8; i < len(bz); i += 8 {
+		cp.Sequences = append(cp.Sequences, binary.BigEndian.Uint64(bz[i:]))
+	}
+	return cp, nil
+}
+
+// SetCheckpoint persists the checkpoint of the channel end, the zero Checkpoint clearing it.
+func (s *Store) SetCheckpoint(chainID, portID, channelID string, cp Checkpoint) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := checkpointKey(chainID, portID, channelID)
+	if len(cp.Sequences) == 0 {
+		return s.db.Delete(key, nil)
+	}
+	bz := make([]byte, 0, 8*(len(cp.Sequences)+1))
+	bz = append(bz, uint64Bytes(uint64(cp.Time.UnixNano()))...)
+	for _, seq := range cp.Sequences {
+		bz = append(bz, uint64Bytes(seq)...)
+	}
+	return s.db.Put(key, bz, nil)
+}
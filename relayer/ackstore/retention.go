@@ -0,0 +1,121 @@
+package ackstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Stats is the size of the store.
+type Stats struct {
+	// Entries is the number of acknowledgement sequences stored, and Channels the number of channel ends they belong to.
+	Entries  int
+	Channels int
+
+	// SizeBytes is the size of the files of the store on disk.
+	SizeBytes int64
+}
+
+type storedSequence struct {
+	key   []byte
+	added time.Time
+}
+
+// Prune deletes the acknowledgement sequences added more than maxAge before now, then the oldest ones beyond
+// the newest maxEntries, a zero limit not applying, and compacts the store if any were deleted.
+// Sequences stored before they were stamped count as the oldest. High-water marks and checkpoints are kept,
+// pending acknowledgements being verified on chain rather than looked up in the store.
+// It returns the number of sequences deleted.
+func (s *Store) Prune(maxAge time.Duration, maxEntries int, now time.Time) (int, error) {
+	if s == nil || (maxAge <= 0 && maxEntries <= 0) {
+		return 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iter := s.db.NewIterator(util.BytesPrefix([]byte("seq/")), nil)
+	var stored []storedSequence
+	for iter.Next() {
+		seq := storedSequence{key: append([]byte(nil), iter.Key()...)}
+		if v := iter.Value(); len(v) == 8 {
+			seq.added = time.Unix(0, int64(binary.BigEndian.Uint64(v)))
+		}
+		stored = append(stored, seq)
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+
+	var batch leveldb.Batch
+	kept := stored[:0]
+	for _, seq := range stored {
+		if maxAge > 0 && now.Sub(seq.added) > maxAge {
+			batch.Delete(seq.key)
+			continue
+		}
+		kept = append(kept, seq)
+	}
+	if maxEntries > 0 && len(kept) > maxEntries {
+		sort.SliceStable(kept, func(i, j int) bool {
+			if !kept[i].added.Equal(kept[j].added) {
+				return kept[i].added.Before(kept[j].added)
+			}
+			return bytes.Compare(kept[i].key, kept[j].key) < 0
+		})
+		for _, seq := range kept[:len(kept)-maxEntries] {
+			batch.Delete(seq.key)
+		}
+	}
+
+	if batch.Len() == 0 {
+		return 0, nil
+	}
+	if err := s.db.Write(&batch, nil); err != nil {
+		return 0, err
+	}
+	return batch.Len(), s.db.CompactRange(util.Range{})
+}
+
+// Stats returns the size of the store.
+func (s *Store) Stats() (Stats, error) {
+	var st Stats
+	if s == nil {
+		return st, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iter := s.db.NewIterator(nil, nil)
+	for iter.Next() {
+		switch {
+		case bytes.HasPrefix(iter.Key(), []byte("seq/")):
+			st.Entries++
+		case bytes.HasPrefix(iter.Key(), []byte("hw/")):
+			st.Channels++
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return st, err
+	}
+
+	err := filepath.WalkDir(s.dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		st.SizeBytes += info.Size()
+		return nil
+	})
+	return st, err
+}
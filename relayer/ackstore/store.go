@@ -0,0 +1,136 @@
+// Package ackstore persists the acknowledgement sequences relayed by the legacy processor,
+// along with a checkpoint of the acknowledgements being relayed, so that a restarted relayer
+// does not retransmit acknowledgements that were on their way to the counterparty.
+package ackstore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Store keeps, for each channel end, the set of acknowledgement sequences relayed from it
+// and the highest of them, its high-water mark.
+// A nil Store stores nothing.
+type Store struct {
+	mu  sync.Mutex
+	db  *leveldb.DB
+	dir string
+}
+
+// Open opens the store in dir, creating it if needed.
+// Only one process may have a store open at a time.
+func Open(dir string) (*Store, error) {
+	db, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ack sequence store at %s: %w", dir, err)
+	}
+	return &Store{db: db, dir: dir}, nil
+}
+
+// Dir returns the directory the store is kept in.
+func (s *Store) Dir() string {
+	if s == nil {
+		return ""
+	}
+	return s.dir
+}
+
+// Close closes the store.
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// channelPrefix returns the prefix of the keys of the channel end identified by chainID, portID and channelID.
+func channelPrefix(kind, chainID, portID, channelID string) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s/%s/", kind, chainID, portID, channelID))
+}
+
+func uint64Bytes(v uint64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, v)
+	return bz
+}
+
+func sequenceKey(chainID, portID, channelID string, seq uint64) []byte {
+	return append(channelPrefix("seq", chainID, portID, channelID), uint64Bytes(seq)...)
+}
+
+func highWaterKey(chainID, portID, channelID string) []byte {
+	return channelPrefix("hw", chainID, portID, channelID)
+}
+
+// Add records the acknowledgement sequences relayed from the channel end, raising its high-water mark if needed.
+// Each sequence is stamped with the time it was added, which retention is based on.
+func (s *Store) Add(chainID, portID, channelID string, seqs []uint64) error {
+	if s == nil || len(seqs) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hw, err := s.highWater(chainID, portID, channelID)
+	if err != nil {
+		return err
+	}
+
+	added := uint64Bytes(uint64(time.Now().UnixNano()))
+	var batch leveldb.Batch
+	for _, seq := range seqs {
+		batch.Put(sequenceKey(chainID, portID, channelID, seq), added)
+		if seq > hw {
+			hw = seq
+		}
+	}
+	batch.Put(highWaterKey(chainID, portID, channelID), uint64Bytes(hw))
+	return s.db.Write(&batch, nil)
+}
+
+// Load returns the acknowledgement sequences relayed from the channel end, in ascending order.
+func (s *Store) Load(chainID, portID, channelID string) ([]uint64, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefix := channelPrefix("seq", chainID, portID, channelID)
+	iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	var seqs []uint64
+	for iter.Next() {
+		seqs = append(seqs, binary.BigEndian.Uint64(iter.Key()[len(prefix):]))
+	}
+	return seqs, iter.Error()
+}
+
+// HighWater returns the highest acknowledgement sequence relayed from the channel end, or 0 if there is none.
+func (s *Store) HighWater(chainID, portID, channelID string) (uint64, error) {
+	if s == nil {
+		return 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.highWater(chainID, portID, channelID)
+}
+
+func (s *Store) highWater(chainID, portID, channelID string) (uint64, error) {
+	bz, err := s.db.Get(highWaterKey(chainID, portID, channelID), nil)
+	switch {
+	case err == leveldb.ErrNotFound:
+		return 0, nil
+	case err != nil:
+		return 0, err
+	case len(bz) != 8:
+		return 0, fmt.Errorf("invalid high-water mark for %s/%s on %s", portID, channelID, chainID)
+	}
+	return binary.BigEndian.Uint64(bz), nil
+}
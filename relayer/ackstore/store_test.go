@@ -0,0 +1,123 @@
+package ackstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorePersistsAcrossReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Open(dir)
+	require.NoError(t, err)
+	require.NoError(t, s.Add("chain-a", "transfer", "channel-1", []uint64{3, 1, 2}))
+	require.NoError(t, s.Add("chain-a", "transfer", "channel-1", []uint64{7}))
+	require.NoError(t, s.Add("chain-a", "transfer", "channel-10", []uint64{100}))
+	require.NoError(t, s.Close())
+
+	s, err = Open(dir)
+	require.NoError(t, err)
+	defer s.Close()
+
+	seqs, err := s.Load("chain-a", "transfer", "channel-1")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3, 7}, seqs)
+
+	hw, err := s.HighWater("chain-a", "transfer", "channel-1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), hw)
+
+	// Channel ends don't leak into one another.
+	seqs, err = s.Load("chain-b", "transfer", "channel-1")
+	require.NoError(t, err)
+	require.Empty(t, seqs)
+	hw, err = s.HighWater("chain-a", "transfer", "channel-10")
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), hw)
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+	require.NoError(t, s.Add("chain-a", "transfer", "channel-1", []uint64{1}))
+	seqs, err := s.Load("chain-a", "transfer", "channel-1")
+	require.NoError(t, err)
+	require.Empty(t, seqs)
+	require.NoError(t, s.Close())
+}
+
+func TestStorePrune(t *testing.T) {
+	s, err := Open(t.TempDir())
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Add("chain-a", "transfer", "channel-1", []uint64{1, 2}))
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, s.Add("chain-a", "transfer", "channel-1", []uint64{3}))
+	require.NoError(t, s.Add("chain-b", "transfer", "channel-0", []uint64{5, 6}))
+
+	st, err := s.Stats()
+	require.NoError(t, err)
+	require.Equal(t, 5, st.Entries)
+	require.Equal(t, 2, st.Channels)
+	require.Positive(t, st.SizeBytes)
+
+	// No limit, nothing pruned.
+	pruned, err := s.Prune(0, 0, time.Now())
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+
+	// The oldest sequences go first.
+	pruned, err = s.Prune(0, 3, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 2, pruned)
+	seqs, err := s.Load("chain-a", "transfer", "channel-1")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3}, seqs)
+
+	pruned, err = s.Prune(time.Hour, 0, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 3, pruned)
+
+	st, err = s.Stats()
+	require.NoError(t, err)
+	require.Zero(t, st.Entries)
+	hw, err := s.HighWater("chain-a", "transfer", "channel-1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), hw)
+}
+
+func TestStoreCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Open(dir)
+	require.NoError(t, err)
+	cp, err := s.Checkpoint("chain-a", "transfer", "channel-1")
+	require.NoError(t, err)
+	require.Empty(t, cp.Sequences)
+
+	at := time.Unix(1700000000, 42)
+	require.NoError(t, s.SetCheckpoint("chain-a", "transfer", "channel-1", Checkpoint{Sequences: []uint64{4, 9}, Time: at}))
+	require.NoError(t, s.Close())
+
+	// The checkpoint survives a restart, and is kept by pruning.
+	s, err = Open(dir)
+	require.NoError(t, err)
+	defer s.Close()
+	_, err = s.Prune(time.Nanosecond, 1, time.Now())
+	require.NoError(t, err)
+	cp, err = s.Checkpoint("chain-a", "transfer", "channel-1")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{4, 9}, cp.Sequences)
+	require.True(t, cp.Time.Equal(at))
+
+	cp, err = s.Checkpoint("chain-a", "transfer", "channel-10")
+	require.NoError(t, err)
+	require.Empty(t, cp.Sequences)
+
+	require.NoError(t, s.SetCheckpoint("chain-a", "transfer", "channel-1", Checkpoint{}))
+	cp, err = s.Checkpoint("chain-a", "transfer", "channel-1")
+	require.NoError(t, err)
+	require.Empty(t, cp.Sequences)
+}
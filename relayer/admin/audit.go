@@ -0,0 +1,268 @@
+package admin
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// OperatorHeader identifies the operator performing a state-changing request.
+	OperatorHeader = "Admin-Operator"
+
+	// NonceHeader carries the single-use nonce of a state-changing request.
+	NonceHeader = "Admin-Nonce"
+
+	// TimestampHeader carries the time a state-changing request was sent at, in unix seconds.
+	TimestampHeader = "Admin-Timestamp"
+
+	// SignatureHeader carries the signature of a state-changing request, as returned by Sign,
+	// when the admin server is configured with a secret.
+	SignatureHeader = "Admin-Signature"
+
+	// timestampWindow is how far the timestamp of a request may be from the time it is received at.
+	timestampWindow = 5 * time.Minute
+
+	// nonceRetention is how long used nonces are remembered to reject replays.
+	// It covers the whole timestamp window, so a nonce can't be reused until its request is too old to be accepted.
+	nonceRetention = 2 * timestampWindow
+
+	// maxNonces bounds the nonces remembered within the retention period.
+	maxNonces = 100_000
+
+	// auditLogSize is the number of entries kept in the audit log.
+	auditLogSize = 1000
+)
+
+var (
+	errMissingOperator = errors.New("state-changing requests must identify the operator with the " + OperatorHeader + " header")
+	errMissingNonce    = errors.New("state-changing requests must carry a unique nonce in the " + NonceHeader + " header")
+	errReplayedNonce   = errors.New("nonce was already used, request rejected as a replay")
+	errTooManyNonces   = errors.New("too many state-changing requests, try again later")
+	errMissingSig      = errors.New("state-changing requests must be signed in the " + SignatureHeader + " header")
+	errInvalidSig      = errors.New("invalid request signature")
+)
+
+// Sign returns the hex-encoded HMAC-SHA256 with secret of a state-changing request,
+// covering its method, path and query, operator, nonce, timestamp and body.
+func Sign(secret []byte, method, uri, operator, nonce, timestamp string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	for _, s := range []string{method, uri, operator, nonce, timestamp, hex.EncodeToString(bodyHash[:])} {
+		mac.Write([]byte(s))
+		mac.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AuditEntry records a state-changing request made to the admin API, whether it was accepted or not.
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	Operator   string    `json:"operator"`
+	RemoteAddr string    `json:"remote_addr"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Nonce      string    `json:"nonce"`
+	Status     int       `json:"status"`
+}
+
+// auditLog keeps the most recent audit entries and the nonces used within the retention period.
+type auditLog struct {
+	log *zap.Logger
+
+	mu      sync.Mutex
+	entries []AuditEntry
+	nonces  map[string]struct{}
+
+	// used lists the nonces in the order they were used in, to expire them from the oldest.
+	used []usedNonce
+}
+
+type usedNonce struct {
+	nonce string
+	time  time.Time
+}
+
+func newAuditLog(log *zap.Logger) *auditLog {
+	return &auditLog{
+		log:    log,
+		nonces: make(map[string]struct{}),
+	}
+}
+
+// useNonce marks nonce as used. It returns errReplayedNonce if it was already used within the retention period,
+// or errTooManyNonces if too many nonces were used within it.
+func (a *auditLog) useNonce(nonce string, now time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	expired := 0
+	for _, u := range a.used {
+		if now.Sub(u.time) <= nonceRetention {
+			break
+		}
+		delete(a.nonces, u.nonce)
+		expired++
+	}
+	a.used = a.used[expired:]
+
+	if _, ok := a.nonces[nonce]; ok {
+		return errReplayedNonce
+	}
+	if len(a.nonces) >= maxNonces {
+		return errTooManyNonces
+	}
+	a.nonces[nonce] = struct{}{}
+	a.used = append(a.used, usedNonce{nonce: nonce, time: now})
+	return nil
+}
+
+func (a *auditLog) record(e AuditEntry) {
+	a.log.Info("Admin operation",
+		zap.String("operator", e.Operator),
+		zap.String("remote_addr", e.RemoteAddr),
+		zap.String("method", e.Method),
+		zap.String("path", e.Path),
+		zap.String("nonce", e.Nonce),
+		zap.Int("status", e.Status),
+	)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, e)
+	if len(a.entries) > auditLogSize {
+		a.entries = a.entries[len(a.entries)-auditLogSize:]
+	}
+}
+
+// snapshot returns the audit entries, oldest first.
+func (a *auditLog) snapshot() []AuditEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]AuditEntry{}, a.entries...)
+}
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// isStateChanging returns true for requests that may change the state of the relayer.
+func isStateChanging(req *http.Request) bool {
+	return req.Method != http.MethodGet && req.Method != http.MethodHead && req.Method != http.MethodOptions
+}
+
+// checkTimestamp returns an error unless timestamp, in unix seconds, is within the timestamp window of now.
+func checkTimestamp(timestamp string, now time.Time) error {
+	if timestamp == "" {
+		return errors.New("state-changing requests must carry the unix time they are sent at in the " + TimestampHeader + " header")
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s header %q: %w", TimestampHeader, timestamp, err)
+	}
+	if d := now.Sub(time.Unix(sec, 0)); d > timestampWindow || d < -timestampWindow {
+		return fmt.Errorf("request timestamp is more than %s away from the time of the relayer", timestampWindow)
+	}
+	return nil
+}
+
+// checkSignature returns an error unless req is signed with the secret of the server.
+// Requests are not signed if the server has no secret.
+func (s *Server) checkSignature(req *http.Request, e AuditEntry, timestamp string) error {
+	if len(s.secret) == 0 {
+		return nil
+	}
+	sig := req.Header.Get(SignatureHeader)
+	if sig == "" {
+		return errMissingSig
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	want := Sign(s.secret, e.Method, req.URL.RequestURI(), e.Operator, e.Nonce, timestamp, body)
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return errInvalidSig
+	}
+	return nil
+}
+
+// audited requires state-changing requests to carry an operator, a recent timestamp and an unused nonce,
+// signed if the server has a secret, and records them in the audit log along with the outcome.
+func (s *Server) audited(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !isStateChanging(req) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		e := AuditEntry{
+			Time:       time.Now().UTC(),
+			Operator:   req.Header.Get(OperatorHeader),
+			RemoteAddr: req.RemoteAddr,
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			Nonce:      req.Header.Get(NonceHeader),
+		}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			e.Status = rec.status
+			s.audit.record(e)
+		}()
+
+		timestamp := req.Header.Get(TimestampHeader)
+		if e.Operator == "" {
+			WriteError(rec, http.StatusBadRequest, errMissingOperator)
+			return
+		}
+		if e.Nonce == "" {
+			WriteError(rec, http.StatusBadRequest, errMissingNonce)
+			return
+		}
+		if err := checkTimestamp(timestamp, e.Time); err != nil {
+			WriteError(rec, http.StatusBadRequest, err)
+			return
+		}
+		if err := s.checkSignature(req, e, timestamp); err != nil {
+			WriteError(rec, http.StatusUnauthorized, err)
+			return
+		}
+		switch err := s.audit.useNonce(e.Nonce, e.Time); {
+		case errors.Is(err, errReplayedNonce):
+			WriteError(rec, http.StatusConflict, err)
+		case err != nil:
+			WriteError(rec, http.StatusTooManyRequests, err)
+		default:
+			next.ServeHTTP(rec, req)
+		}
+	})
+}
+
+func (s *Server) handleAudit(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		return
+	}
+	WriteJSON(w, http.StatusOK, s.audit.snapshot())
+}
@@ -0,0 +1,23 @@
+package admin
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+)
+
+// dashboard is a read-only web page rendering the admin API in the browser,
+// refreshing itself from the status, processor, channels, pending, balances, clients and audit endpoints.
+//
+//go:embed dashboard.html
+var dashboard []byte
+
+func (s *Server) handleDashboard(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(dashboard)
+}
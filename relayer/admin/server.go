@@ -0,0 +1,133 @@
+// Package admin implements the relayer's admin HTTP API.
+//
+// The admin server only provides the transport and a few helpers;
+// the relayer package registers the actual endpoints against the state it owns.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Server is a dedicated HTTP server for the admin API.
+type Server struct {
+	log *zap.Logger
+	mux *http.ServeMux
+
+	statusMu sync.RWMutex
+	status   map[string]StatusFunc
+
+	audit  *auditLog
+	secret []byte
+}
+
+// ServerOption configures optional behavior of a Server.
+type ServerOption func(*Server)
+
+// WithSecret requires state-changing requests to be signed with secret, as returned by Sign.
+func WithSecret(secret []byte) ServerOption {
+	return func(s *Server) {
+		s.secret = secret
+	}
+}
+
+// StatusFunc returns a JSON-serializable snapshot of one section of the status API.
+type StatusFunc func() any
+
+// NewServer returns a new admin server with only the status, audit and dashboard endpoints registered.
+//
+//	GET /status returns the snapshot of every registered status section, keyed by section name.
+//	GET /audit returns the most recent state-changing requests, oldest first.
+//	GET /dashboard serves a read-only web page rendering the admin API.
+//
+// Every request other than GET, HEAD and OPTIONS is state-changing: it must identify the operator
+// with the Admin-Operator header, carry the time it is sent at in the Admin-Timestamp header and a nonce
+// in the Admin-Nonce header that was not used before, and be signed in the Admin-Signature header
+// if the server has a secret. Stale timestamps and replayed nonces are rejected,
+// and all state-changing requests are recorded in the audit log.
+func NewServer(log *zap.Logger, opts ...ServerOption) *Server {
+	s := &Server{
+		log:    log,
+		mux:    http.NewServeMux(),
+		status: make(map[string]StatusFunc),
+		audit:  newAuditLog(log.With(zap.String("sys", "adminaudit"))),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc("/status", s.handleStatus)
+	s.mux.HandleFunc("/audit", s.handleAudit)
+	s.mux.HandleFunc("/dashboard", s.handleDashboard)
+	return s
+}
+
+// RegisterStatus adds a section to the status API.
+// Registering a section under an existing name replaces it.
+func (s *Server) RegisterStatus(name string, fn StatusFunc) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.status[name] = fn
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		return
+	}
+
+	s.statusMu.RLock()
+	defer s.statusMu.RUnlock()
+
+	res := make(map[string]any, len(s.status))
+	for name, fn := range s.status {
+		res[name] = fn()
+	}
+	WriteJSON(w, http.StatusOK, res)
+}
+
+// HandleFunc registers the handler for the given pattern.
+func (s *Server) HandleFunc(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// Start serves the admin API in a background goroutine, accepting connections on the given listener.
+// The server will be forcefully shut down when ctx finishes.
+func (s *Server) Start(ctx context.Context, ln net.Listener) {
+	srv := &http.Server{
+		Handler:  s.audited(s.mux),
+		ErrorLog: zap.NewStdLog(s.log),
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	go srv.Serve(ln)
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+}
+
+// ErrorResponse is the body returned by the admin API for any failed request.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// WriteJSON writes v as the JSON body of the response with the given status code.
+func WriteJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes err as an ErrorResponse with the given status code.
+func WriteError(w http.ResponseWriter, code int, err error) {
+	WriteJSON(w, code, ErrorResponse{Error: err.Error()})
+}
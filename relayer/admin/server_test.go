@@ -0,0 +1,163 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestAuditedRequests(t *testing.T) {
+	s := NewServer(zaptest.NewLogger(t))
+	calls := 0
+	s.HandleFunc("/flush", func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		WriteJSON(w, http.StatusOK, struct{}{})
+	})
+	h := s.audited(s.mux)
+
+	do := func(method, target, operator, nonce string) int {
+		req := httptest.NewRequest(method, target, nil)
+		if operator != "" {
+			req.Header.Set(OperatorHeader, operator)
+		}
+		if nonce != "" {
+			req.Header.Set(NonceHeader, nonce)
+		}
+		req.Header.Set(TimestampHeader, strconv.FormatInt(time.Now().Unix(), 10))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/flush", "", "n1"))
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/flush", "alice", ""))
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/flush", "alice", "n1"))
+	require.Equal(t, http.StatusConflict, do(http.MethodPost, "/flush", "alice", "n1"))
+
+	// A nonce can't be replayed by another operator either.
+	require.Equal(t, http.StatusConflict, do(http.MethodPost, "/flush", "bob", "n1"))
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/flush", "bob", "n2"))
+	require.Equal(t, 2, calls)
+
+	// Reads need neither, and are not audited.
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/status", "", ""))
+
+	req := httptest.NewRequest(http.MethodGet, "/audit", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var entries []AuditEntry
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&entries))
+	require.Len(t, entries, 6)
+	require.Equal(t, "alice", entries[2].Operator)
+	require.Equal(t, "n1", entries[2].Nonce)
+	require.Equal(t, "/flush", entries[2].Path)
+	require.Equal(t, http.StatusOK, entries[2].Status)
+	require.Equal(t, "bob", entries[4].Operator)
+	require.Equal(t, http.StatusConflict, entries[4].Status)
+}
+
+func TestAuditedTimestamps(t *testing.T) {
+	s := NewServer(zaptest.NewLogger(t))
+	s.HandleFunc("/flush", func(w http.ResponseWriter, req *http.Request) {
+		WriteJSON(w, http.StatusOK, struct{}{})
+	})
+	h := s.audited(s.mux)
+
+	do := func(nonce, timestamp string) int {
+		req := httptest.NewRequest(http.MethodPost, "/flush", nil)
+		req.Header.Set(OperatorHeader, "alice")
+		req.Header.Set(NonceHeader, nonce)
+		if timestamp != "" {
+			req.Header.Set(TimestampHeader, timestamp)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	at := func(d time.Duration) string {
+		return strconv.FormatInt(time.Now().Add(d).Unix(), 10)
+	}
+
+	require.Equal(t, http.StatusBadRequest, do("n1", ""))
+	require.Equal(t, http.StatusBadRequest, do("n1", "yesterday"))
+	require.Equal(t, http.StatusBadRequest, do("n1", at(-timestampWindow-time.Minute)))
+	require.Equal(t, http.StatusBadRequest, do("n1", at(timestampWindow+time.Minute)))
+	require.Equal(t, http.StatusOK, do("n1", at(-time.Minute)))
+	require.Equal(t, http.StatusConflict, do("n1", at(0)))
+}
+
+func TestAuditedSignatures(t *testing.T) {
+	secret := []byte("s3cret")
+	s := NewServer(zaptest.NewLogger(t), WithSecret(secret))
+	var got string
+	s.HandleFunc("/flush", func(w http.ResponseWriter, req *http.Request) {
+		var body struct{ Path string }
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		got = body.Path
+		WriteJSON(w, http.StatusOK, struct{}{})
+	})
+	h := s.audited(s.mux)
+
+	do := func(nonce, body, sig string) int {
+		req := httptest.NewRequest(http.MethodPost, "/flush?dry=true", strings.NewReader(body))
+		req.Header.Set(OperatorHeader, "alice")
+		req.Header.Set(NonceHeader, nonce)
+		req.Header.Set(TimestampHeader, strconv.FormatInt(time.Now().Unix(), 10))
+		if sig != "" {
+			req.Header.Set(SignatureHeader, sig)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	sign := func(key []byte, nonce, body string) string {
+		return Sign(key, http.MethodPost, "/flush?dry=true", "alice", nonce, strconv.FormatInt(time.Now().Unix(), 10), []byte(body))
+	}
+
+	body := `{"path":"demo-path"}`
+	require.Equal(t, http.StatusUnauthorized, do("n1", body, ""))
+	require.Equal(t, http.StatusUnauthorized, do("n1", body, sign([]byte("other"), "n1", body)))
+	require.Equal(t, http.StatusUnauthorized, do("n1", `{"path":"other-path"}`, sign(secret, "n1", body)))
+	require.Equal(t, http.StatusUnauthorized, do("n1", body, sign(secret, "n2", body)))
+
+	// The body is still readable by the handler once the signature is checked.
+	require.Equal(t, http.StatusOK, do("n1", body, sign(secret, "n1", body)))
+	require.Equal(t, "demo-path", got)
+}
+
+func TestUseNonce(t *testing.T) {
+	a := newAuditLog(zaptest.NewLogger(t))
+	now := time.Now()
+
+	require.NoError(t, a.useNonce("n1", now))
+	require.NoError(t, a.useNonce("n2", now.Add(time.Minute)))
+	require.ErrorIs(t, a.useNonce("n1", now.Add(nonceRetention)), errReplayedNonce)
+
+	// Nonces expire from the oldest once out of the retention period.
+	require.NoError(t, a.useNonce("n1", now.Add(nonceRetention+time.Second)))
+	require.Equal(t, []string{"n2", "n1"}, []string{a.used[0].nonce, a.used[1].nonce})
+	require.Len(t, a.nonces, 2)
+
+	for i := len(a.nonces); i < maxNonces; i++ {
+		require.NoError(t, a.useNonce(strconv.Itoa(i), now.Add(nonceRetention+time.Second)))
+	}
+	require.ErrorIs(t, a.useNonce("n3", now.Add(nonceRetention+time.Second)), errTooManyNonces)
+}
+
+func TestDashboard(t *testing.T) {
+	s := NewServer(zaptest.NewLogger(t))
+	rec := httptest.NewRecorder()
+	s.audited(s.mux).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), `fetch(path`)
+}
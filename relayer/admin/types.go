@@ -0,0 +1,271 @@
+package admin
+
+import "time"
+
+// PathProcessor reports which processor is currently relaying a path.
+type PathProcessor struct {
+	Path      string `json:"path"`
+	Processor string `json:"processor"`
+}
+
+// SetProcessorRequest is the body of a request to hand a path off to another processor.
+type SetProcessorRequest struct {
+	Path      string `json:"path"`
+	Processor string `json:"processor"`
+}
+
+// FlushRequest is the body of a request to relay all pending packets and acknowledgements of a path once.
+// Requests retried with the same idempotency key are only performed once.
+type FlushRequest struct {
+	Path           string `json:"path"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// Tx identifies a transaction broadcast by the relayer.
+type Tx struct {
+	ChainID string `json:"chain_id"`
+	TxHash  string `json:"tx_hash"`
+}
+
+// SequenceResult is the outcome of relaying a packet, or its acknowledgement, from a channel end of a chain:
+// relayed, skipped or failed, with the reason why, and the transaction that relayed it if any.
+type SequenceResult struct {
+	ChainID   string `json:"chain_id"`
+	ChannelID string `json:"channel_id"`
+	PortID    string `json:"port_id"`
+	Sequence  uint64 `json:"sequence"`
+	MsgType   string `json:"msg_type,omitempty"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	TxHash    string `json:"tx_hash,omitempty"`
+}
+
+// FlushResponse is the outcome of a flush.
+// Replayed is set when the result is that of an earlier request with the same idempotency key,
+// and Paused lists the channels left out of the flush because they are paused.
+type FlushResponse struct {
+	Path           string           `json:"path"`
+	IdempotencyKey string           `json:"idempotency_key,omitempty"`
+	Replayed       bool             `json:"replayed"`
+	Txs            []Tx             `json:"txs"`
+	Sequences      []SequenceResult `json:"sequences"`
+	Paused         []ChannelRequest `json:"paused,omitempty"`
+	Error          string           `json:"error,omitempty"`
+}
+
+// Channel is an open channel of a path, identified by its port and channel IDs on the src chain of the path.
+type Channel struct {
+	Path                  string `json:"path"`
+	ChainID               string `json:"chain_id"`
+	PortID                string `json:"port_id"`
+	ChannelID             string `json:"channel_id"`
+	CounterpartyChainID   string `json:"counterparty_chain_id,omitempty"`
+	CounterpartyPortID    string `json:"counterparty_port_id,omitempty"`
+	CounterpartyChannelID string `json:"counterparty_channel_id,omitempty"`
+	Paused                bool   `json:"paused"`
+}
+
+// ChannelRequest is the body of a request to pause or resume a channel of a path,
+// identified by its port and channel IDs on the src chain of the path.
+type ChannelRequest struct {
+	Path      string `json:"path"`
+	PortID    string `json:"port_id"`
+	ChannelID string `json:"channel_id"`
+}
+
+// PendingSequences lists the sequences of the packets and acknowledgements of a channel waiting to be relayed.
+// Src packets were sent on the src chain of the path and are yet to be received on the dst chain,
+// src acknowledgements were written on the src chain and are yet to be relayed to the dst chain, and conversely.
+type PendingSequences struct {
+	Path       string   `json:"path"`
+	PortID     string   `json:"port_id"`
+	ChannelID  string   `json:"channel_id"`
+	SrcPackets []uint64 `json:"src_packets"`
+	DstPackets []uint64 `json:"dst_packets"`
+	SrcAcks    []uint64 `json:"src_acks"`
+	DstAcks    []uint64 `json:"dst_acks"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// StuckPacket is a packet, or the acknowledgement of a packet if Ack, pending relay for longer than the threshold
+// of the stuck packet detector. ChainID, PortID and ChannelID identify the channel end the packet was sent from,
+// or the acknowledgement written on.
+type StuckPacket struct {
+	Path      string    `json:"path"`
+	ChainID   string    `json:"chain_id"`
+	PortID    string    `json:"port_id"`
+	ChannelID string    `json:"channel_id"`
+	Sequence  uint64    `json:"sequence"`
+	Ack       bool      `json:"ack,omitempty"`
+	FirstSeen time.Time `json:"first_seen"`
+	Age       string    `json:"age"`
+
+	// Level is warning once pending for the threshold, and critical once pending for the critical age.
+	Level string `json:"level"`
+
+	// Diagnosis tells why the packet is pending: unattempted, failing, in_flight or paused.
+	Diagnosis string `json:"diagnosis"`
+
+	// Attempts, LastAttempt, MsgType and LastError describe the attempts at relaying the packet since the relayer
+	// started, LastError being empty if its last message was sent.
+	Attempts    uint64     `json:"attempts,omitempty"`
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+	MsgType     string     `json:"msg_type,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+// UseKeyRequest is the body of a request to sign the transactions sent to a chain with another key of its keyring.
+type UseKeyRequest struct {
+	ChainID string `json:"chain_id"`
+	Key     string `json:"key"`
+}
+
+// ChainKey reports the key signing the transactions sent to a chain.
+type ChainKey struct {
+	ChainID string `json:"chain_id"`
+	Key     string `json:"key"`
+	Address string `json:"address"`
+}
+
+// PathLog reports the logging of a path, and is the body of a request to reconfigure it.
+// Empty fields fall back to the logging of the relayer.
+type PathLog struct {
+	Path   string `json:"path"`
+	Level  string `json:"level,omitempty"`
+	Format string `json:"format,omitempty"`
+	File   string `json:"file,omitempty"`
+}
+
+// PathPause reports whether the packets and acknowledgements of a path are relayed, and is the body of a request
+// to pause or resume the path.
+type PathPause struct {
+	Path   string `json:"path"`
+	Paused bool   `json:"paused"`
+}
+
+// PathPriority reports whether the fees of the transactions of a path are bumped, and is the body of a request
+// to enable or disable it, FeeMultiplier being ignored in requests.
+type PathPriority struct {
+	Path          string  `json:"path"`
+	Enabled       bool    `json:"enabled"`
+	FeeMultiplier float64 `json:"fee_multiplier,omitempty"`
+}
+
+// FeatureFlag reports whether an experimental feature is enabled by default and for each path, and is the body
+// of a request to enable or disable the feature for Path, or by default if Path is empty, Paths being ignored in requests.
+type FeatureFlag struct {
+	Name    string          `json:"name"`
+	Path    string          `json:"path,omitempty"`
+	Enabled bool            `json:"enabled"`
+	Paths   map[string]bool `json:"paths,omitempty"`
+}
+
+// MaintenanceTask reports the state of a periodic maintenance task of the relayer, and is the body of a request
+// to enable or disable the task named Name, or all tasks of that kind, the other fields being ignored in requests.
+type MaintenanceTask struct {
+	Name         string     `json:"name"`
+	Enabled      bool       `json:"enabled"`
+	Interval     string     `json:"interval,omitempty"`
+	Running      bool       `json:"running,omitempty"`
+	Runs         uint64     `json:"runs,omitempty"`
+	LastRun      *time.Time `json:"last_run,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	NextRun      *time.Time `json:"next_run,omitempty"`
+}
+
+// Coin is an amount of a denom.
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// WalletBalance reports the balance of the relayer wallet on a chain.
+type WalletBalance struct {
+	ChainID string `json:"chain_id"`
+	Address string `json:"address"`
+	Balance []Coin `json:"balance"`
+	Error   string `json:"error,omitempty"`
+}
+
+// IBCDenom is a voucher minted on a chain by the ICS-20 transfers received over a relayed channel,
+// identified by its port and channel IDs on the chain.
+type IBCDenom struct {
+	Path      string `json:"path"`
+	ChainID   string `json:"chain_id"`
+	PortID    string `json:"port_id"`
+	ChannelID string `json:"channel_id"`
+
+	// Denom is the ibc/ denom of the voucher, BaseDenom the denom on the chain it is native to,
+	// and Trace the ports and channels it was transferred over, the last one first.
+	Denom     string `json:"denom"`
+	BaseDenom string `json:"base_denom"`
+	Trace     string `json:"trace"`
+}
+
+// ClientExpiry reports when a client of a path expires unless it is updated,
+// identified by the chain hosting it. Reason is set once the client expired or is frozen.
+type ClientExpiry struct {
+	Path           string     `json:"path"`
+	ChainID        string     `json:"chain_id"`
+	ClientID       string     `json:"client_id"`
+	TrustingPeriod string     `json:"trusting_period,omitempty"`
+	LatestHeight   string     `json:"latest_height,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+	Error          string     `json:"error,omitempty"`
+}
+
+// LogEntry is a warning or error logged by the relayer.
+type LogEntry struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// ChainFaults are the faults injected into the RPC requests of a chain, for resilience testing.
+type ChainFaults struct {
+	ChainID        string  `json:"chain_id"`
+	DropRate       float64 `json:"drop_rate"`
+	BroadcastDelay string  `json:"broadcast_delay,omitempty"`
+	StaleHeights   int64   `json:"stale_heights"`
+}
+
+// PathIdentity reports the account of the relayer on both chains of a path.
+type PathIdentity struct {
+	Path   string          `json:"path"`
+	Chains []ChainIdentity `json:"chains"`
+}
+
+// ChainIdentity reports the account of the relayer on a chain of a path, with the address of its key on the chain
+// and on the counterparty chain, the accounts letting it relay on their behalf and its ICS-29 counterparty payees.
+// Empty account fields mean the account does not exist on the chain yet, or that the chain could not be queried.
+type ChainIdentity struct {
+	ChainID             string `json:"chain_id"`
+	Key                 string `json:"key"`
+	Address             string `json:"address"`
+	CounterpartyAddress string `json:"counterparty_address"`
+	Signer              string `json:"signer,omitempty"`
+
+	AccountType   string `json:"account_type,omitempty"`
+	AccountNumber uint64 `json:"account_number,omitempty"`
+	Sequence      uint64 `json:"sequence,omitempty"`
+	PubKeyType    string `json:"pub_key_type,omitempty"`
+
+	FeeGranter    string   `json:"fee_granter,omitempty"`
+	FeeGrant      string   `json:"fee_grant,omitempty"`
+	FeeGrantError string   `json:"fee_grant_error,omitempty"`
+	AuthzGrants   []string `json:"authz_grants,omitempty"`
+
+	Payees []PayeeRegistration `json:"payees,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// PayeeRegistration reports the counterparty payee of the relayer on a fee-enabled channel.
+// Registered is whether it is the address of the relayer on the counterparty chain.
+type PayeeRegistration struct {
+	PortID            string `json:"port_id"`
+	ChannelID         string `json:"channel_id"`
+	CounterpartyPayee string `json:"counterparty_payee,omitempty"`
+	Registered        bool   `json:"registered"`
+}
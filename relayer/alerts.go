@@ -0,0 +1,683 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// Defaults of the alerts.
+const (
+	defaultAlertsInterval       = time.Minute
+	defaultAlertRepeatInterval  = time.Hour
+	defaultAlertWebhookRateHour = 30
+
+	// alertCheckTimeout bounds the queries of each path or chain the alerts are checked on.
+	alertCheckTimeout = 30 * time.Second
+
+	// alertPostTimeout bounds each post of an alert to a webhook.
+	alertPostTimeout = 10 * time.Second
+)
+
+// Kinds of alerts.
+const (
+	AlertRelayErrors   = "relay_errors"
+	AlertClientExpiry  = "client_expiry"
+	AlertStuckPackets  = "stuck_packets"
+	AlertFinalityStall = "finality_stall"
+
+	// AlertIncompatibleUpgrade fires for an upgrade of a relayed chain requiring a newer relayer,
+	// as listed in the release manifest of the release check.
+	AlertIncompatibleUpgrade = "incompatible_upgrade"
+)
+
+// Formats of the payloads of the alert webhooks.
+const (
+	// AlertFormatJSON posts the Alert as JSON.
+	AlertFormatJSON = "json"
+
+	// AlertFormatSlack and AlertFormatDiscord post a message to a Slack or Discord incoming webhook.
+	AlertFormatSlack   = "slack"
+	AlertFormatDiscord = "discord"
+
+	// AlertFormatPagerDuty posts an event to the PagerDuty Events API v2, resolving the incident with the alert.
+	AlertFormatPagerDuty = "pagerduty"
+)
+
+// Statuses of the alerts posted to a webhook.
+const (
+	AlertSent        = "sent"
+	AlertFailed      = "failed"
+	AlertRateLimited = "rate_limited"
+)
+
+// alertTemplates are the templates of the payloads of the formats other than AlertFormatJSON.
+var alertTemplates = map[string]string{
+	AlertFormatSlack:   `{"text": {{ printf "[%s] %s" (upper .Status) .Summary | json }}}`,
+	AlertFormatDiscord: `{"content": {{ printf "[%s] %s" (upper .Status) .Summary | json }}}`,
+	AlertFormatPagerDuty: `{"routing_key": {{ json .RoutingKey }}, "dedup_key": {{ json .Key }}, ` +
+		`"event_action": {{ if .Resolved }}"resolve"{{ else }}"trigger"{{ end }}, ` +
+		`"payload": {"summary": {{ json .Summary }}, "source": {{ json .Source }}, "severity": {{ json .Severity }}, ` +
+		`"timestamp": {{ json .Time }}, "custom_details": {{ json .Alert }}}}`,
+}
+
+var alertTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		bz, err := json.Marshal(v)
+		return string(bz), err
+	},
+	"upper": strings.ToUpper,
+}
+
+// AlertsConfig configures the alerts of `rly start`, posted to webhooks while relaying fails. Only the conditions
+// set are checked, every Interval, as well as the incompatible upgrades found by the release check if configured.
+type AlertsConfig struct {
+	// Interval is how often the conditions are checked, every minute if unset.
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+
+	// RepeatInterval is how often an alert still firing is posted again, every hour if unset.
+	RepeatInterval time.Duration `yaml:"repeat-interval,omitempty" json:"repeat-interval,omitempty"`
+
+	Webhooks []AlertWebhook `yaml:"webhooks" json:"webhooks"`
+
+	RelayErrors   *RelayErrorsAlert   `yaml:"relay-errors,omitempty" json:"relay-errors,omitempty"`
+	ClientExpiry  *ClientExpiryAlert  `yaml:"client-expiry,omitempty" json:"client-expiry,omitempty"`
+	StuckPackets  *StuckPacketsAlert  `yaml:"stuck-packets,omitempty" json:"stuck-packets,omitempty"`
+	FinalityStall *FinalityStallAlert `yaml:"finality-stall,omitempty" json:"finality-stall,omitempty"`
+}
+
+// AlertWebhook is an HTTP endpoint the alerts are posted to, such as a Slack, Discord or PagerDuty integration.
+type AlertWebhook struct {
+	URL string `yaml:"url" json:"url"`
+
+	// Format is the payload posted, AlertFormatJSON if unset, unless Template is set.
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
+
+	// Template is a Go text/template rendering the payload from the Alert, overriding Format. The functions json
+	// and upper encode a value as JSON and upper-case a string, and the fields RoutingKey and Source are set as well.
+	Template string `yaml:"template,omitempty" json:"template,omitempty"`
+
+	// RoutingKey is the integration key of a PagerDuty service.
+	RoutingKey string `yaml:"routing-key,omitempty" json:"routing-key,omitempty"`
+
+	// Headers are added to the requests, such as an Authorization header.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// MaxPerHour is the number of alerts posted to the webhook in any hour, 30 if unset, the others being dropped.
+	MaxPerHour int `yaml:"max-per-hour,omitempty" json:"max-per-hour,omitempty"`
+}
+
+// RelayErrorsAlert fires for a path failing to send at least Count transactions within Window.
+type RelayErrorsAlert struct {
+	Count  int           `yaml:"count" json:"count"`
+	Window time.Duration `yaml:"window" json:"window"`
+}
+
+// ClientExpiryAlert fires for a client of a path expiring within Within unless updated, or expired.
+type ClientExpiryAlert struct {
+	Within time.Duration `yaml:"within" json:"within"`
+}
+
+// StuckPacketsAlert fires for a channel of a path with packets or acknowledgements pending for longer than Age.
+// Paused channels are not checked.
+type StuckPacketsAlert struct {
+	Age time.Duration `yaml:"age" json:"age"`
+}
+
+// FinalityStallAlert fires for a rollapp whose latest height finalized on its settlement layer has not increased
+// for After.
+type FinalityStallAlert struct {
+	After time.Duration `yaml:"after" json:"after"`
+}
+
+// Validate checks the alerts config.
+func (c *AlertsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 || c.RepeatInterval < 0 {
+		return errors.New("alert intervals must not be negative")
+	}
+	if len(c.Webhooks) == 0 {
+		return errors.New("alerts without webhooks")
+	}
+	for i, w := range c.Webhooks {
+		if _, err := w.template(); err != nil {
+			return fmt.Errorf("alert webhook %d: %w", i, err)
+		}
+	}
+	switch {
+	case c.RelayErrors != nil && (c.RelayErrors.Count <= 0 || c.RelayErrors.Window <= 0):
+		return errors.New("relay-errors alert must have a positive count and window")
+	case c.ClientExpiry != nil && c.ClientExpiry.Within <= 0:
+		return errors.New("client-expiry alert must have a positive within")
+	case c.StuckPackets != nil && c.StuckPackets.Age <= 0:
+		return errors.New("stuck-packets alert must have a positive age")
+	case c.FinalityStall != nil && c.FinalityStall.After <= 0:
+		return errors.New("finality-stall alert must have a positive after")
+	}
+	return nil
+}
+
+// template validates w and returns the template of its payload, nil for AlertFormatJSON.
+func (w AlertWebhook) template() (*template.Template, error) {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("url must be an http or https URL, got %s", u.Scheme)
+	}
+	if w.MaxPerHour < 0 {
+		return nil, errors.New("max-per-hour must not be negative")
+	}
+	text := w.Template
+	if text == "" {
+		switch w.Format {
+		case "", AlertFormatJSON:
+			return nil, nil
+		case AlertFormatPagerDuty:
+			if w.RoutingKey == "" {
+				return nil, errors.New("pagerduty format without routing-key")
+			}
+		}
+		var ok bool
+		if text, ok = alertTemplates[w.Format]; !ok {
+			return nil, fmt.Errorf("unknown format %s", w.Format)
+		}
+	}
+	tmpl, err := template.New("alert").Funcs(alertTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	// Executing the template once rejects the templates referring to fields alerts do not have.
+	if err := tmpl.Execute(new(bytes.Buffer), alertMessage{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Alert is a condition the relayer is failing on, posted to the webhooks when it starts and stops firing,
+// and again every repeat interval while firing.
+type Alert struct {
+	// Key identifies the alert, e.g. client_expiry/rollapp-hub/rollapp_1-1.
+	Key      string `json:"key"`
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Path     string `json:"path,omitempty"`
+	ChainID  string `json:"chain_id,omitempty"`
+
+	Resolved bool      `json:"resolved"`
+	Since    time.Time `json:"since"`
+	Time     time.Time `json:"time"`
+}
+
+// Status returns firing or resolved.
+func (a Alert) Status() string {
+	if a.Resolved {
+		return "resolved"
+	}
+	return "firing"
+}
+
+// alertMessage is the data the payloads of alert webhooks are rendered from.
+type alertMessage struct {
+	Alert
+
+	RoutingKey string
+	Source     string
+}
+
+// alertWebhook is an AlertWebhook with the times of the alerts posted to it over the last hour.
+// It is logged by host, the URL of Slack and Discord webhooks holding their token.
+type alertWebhook struct {
+	AlertWebhook
+	host string
+	tmpl *template.Template
+	sent []time.Time
+}
+
+// allow returns whether an alert may be posted to w at now, recording it if so.
+func (w *alertWebhook) allow(now time.Time) bool {
+	limit := w.MaxPerHour
+	if limit == 0 {
+		limit = defaultAlertWebhookRateHour
+	}
+	recent := w.sent[:0]
+	for _, t := range w.sent {
+		if now.Sub(t) < time.Hour {
+			recent = append(recent, t)
+		}
+	}
+	w.sent = recent
+	if len(w.sent) >= limit {
+		return false
+	}
+	w.sent = append(w.sent, now)
+	return true
+}
+
+// post posts the payload of m to w.
+func (w *alertWebhook) post(ctx context.Context, m alertMessage) error {
+	var body []byte
+	if w.tmpl == nil {
+		var err error
+		if body, err = json.Marshal(m.Alert); err != nil {
+			return err
+		}
+	} else {
+		var buf bytes.Buffer
+		if err := w.tmpl.Execute(&buf, m); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %s", res.Status)
+	}
+	return nil
+}
+
+// firingAlert is an alert firing, with when it was last posted.
+type firingAlert struct {
+	Alert
+	postedAt time.Time
+}
+
+// failureSample is the number of transactions a path failed to send since the relayer started, at a time.
+type failureSample struct {
+	at     time.Time
+	failed float64
+}
+
+// finalityProgress is the latest finalized height of a rollapp, and since when it is.
+type finalityProgress struct {
+	height int64
+	since  time.Time
+}
+
+// pendingKey identifies a packet or acknowledgement pending on a channel of a path.
+type pendingKey struct {
+	kind string
+	seq  uint64
+}
+
+// alerter evaluates the conditions of the alerts and posts the alerts that start, keep or stop firing
+// to the webhooks, at most MaxPerHour to each.
+type alerter struct {
+	log     *zap.Logger
+	metrics *processor.PrometheusMetrics
+	cfg     *AlertsConfig
+	source  string
+
+	// releases is the release check of the incompatible upgrades alerted, if configured.
+	releases *releaseChecker
+
+	webhooks []*alertWebhook
+
+	// The state of the conditions, only accessed by check.
+	failures map[string][]failureSample
+	pending  map[string]map[pendingKey]time.Time
+	finality map[string]finalityProgress
+
+	mu     sync.Mutex
+	firing map[string]*firingAlert
+}
+
+func newAlerter(log *zap.Logger, metrics *processor.PrometheusMetrics, cfg *AlertsConfig) *alerter {
+	a := &alerter{
+		log:      log,
+		metrics:  metrics,
+		cfg:      cfg,
+		source:   "rly",
+		failures: make(map[string][]failureSample),
+		pending:  make(map[string]map[pendingKey]time.Time),
+		finality: make(map[string]finalityProgress),
+		firing:   make(map[string]*firingAlert),
+	}
+	if host, err := os.Hostname(); err == nil {
+		a.source = host
+	}
+	for _, w := range cfg.Webhooks {
+		// The config is validated, so the template is valid.
+		tmpl, _ := w.template()
+		u, _ := url.Parse(w.URL)
+		a.webhooks = append(a.webhooks, &alertWebhook{AlertWebhook: w, host: u.Host, tmpl: tmpl})
+	}
+	return a
+}
+
+// relayErrors returns the alert of the path failing to send RelayErrors.Count transactions within its window,
+// failed being the transactions it failed to send since the relayer started, nil if it did not.
+func (a *alerter) relayErrors(path string, failed float64, now time.Time) *Alert {
+	cfg := a.cfg.RelayErrors
+	samples := append(a.failures[path], failureSample{at: now, failed: failed})
+	// The last sample older than the window is kept as the baseline of the failures within it.
+	for len(samples) > 1 && now.Sub(samples[1].at) >= cfg.Window {
+		samples = samples[1:]
+	}
+	a.failures[path] = samples
+	n := int(failed - samples[0].failed)
+	if n < cfg.Count {
+		return nil
+	}
+	return &Alert{
+		Key:      AlertRelayErrors + "/" + path,
+		Kind:     AlertRelayErrors,
+		Severity: "error",
+		Summary:  fmt.Sprintf("Path %s failed to send %d transactions in the last %s", path, n, cfg.Window),
+		Path:     path,
+	}
+}
+
+// clientExpiry returns the alert of the client e expiring within ClientExpiry.Within, nil if it does not.
+func (a *alerter) clientExpiry(e admin.ClientExpiry, now time.Time) *Alert {
+	if e.ExpiresAt == nil {
+		return nil
+	}
+	left := e.ExpiresAt.Sub(now)
+	if left > a.cfg.ClientExpiry.Within {
+		return nil
+	}
+	summary := fmt.Sprintf("Client %s on %s of path %s expires in %s unless updated", e.ClientID, e.ChainID, e.Path, left.Round(time.Minute))
+	if left <= 0 {
+		summary = fmt.Sprintf("Client %s on %s of path %s has expired", e.ClientID, e.ChainID, e.Path)
+	}
+	return &Alert{
+		Key:      AlertClientExpiry + "/" + e.Path + "/" + e.ChainID,
+		Kind:     AlertClientExpiry,
+		Severity: "critical",
+		Summary:  summary,
+		Path:     e.Path,
+		ChainID:  e.ChainID,
+	}
+}
+
+// stuckPackets returns the alert of the channel of p pending packets or acknowledgements for longer than
+// StuckPackets.Age, nil if it is not.
+func (a *alerter) stuckPackets(p admin.PendingSequences, now time.Time) *Alert {
+	k := p.Path + "/" + p.PortID + "/" + p.ChannelID
+	prev := a.pending[k]
+	seen := make(map[pendingKey]time.Time)
+	var stuck int
+	oldest := now
+	for kind, seqs := range map[string][]uint64{
+		"src_packet": p.SrcPackets, "dst_packet": p.DstPackets, "src_ack": p.SrcAcks, "dst_ack": p.DstAcks,
+	} {
+		for _, seq := range seqs {
+			pk := pendingKey{kind: kind, seq: seq}
+			since, ok := prev[pk]
+			if !ok {
+				since = now
+			}
+			seen[pk] = since
+			if now.Sub(since) >= a.cfg.StuckPackets.Age {
+				stuck++
+			}
+			if since.Before(oldest) {
+				oldest = since
+			}
+		}
+	}
+	a.pending[k] = seen
+	if stuck == 0 {
+		return nil
+	}
+	return &Alert{
+		Key:      AlertStuckPackets + "/" + k,
+		Kind:     AlertStuckPackets,
+		Severity: "warning",
+		Summary: fmt.Sprintf("%d packets and acknowledgements of %s/%s of path %s are pending for more than %s, the oldest for %s",
+			stuck, p.PortID, p.ChannelID, p.Path, a.cfg.StuckPackets.Age, now.Sub(oldest).Round(time.Minute)),
+		Path: p.Path,
+	}
+}
+
+// finalityStall returns the alert of the rollapp chainID whose latest finalized height has been height
+// for FinalityStall.After, nil if it has not.
+func (a *alerter) finalityStall(chainID string, height int64, now time.Time) *Alert {
+	p, ok := a.finality[chainID]
+	if !ok || height > p.height {
+		p = finalityProgress{height: height, since: now}
+		a.finality[chainID] = p
+	}
+	stalled := now.Sub(p.since)
+	if stalled < a.cfg.FinalityStall.After {
+		return nil
+	}
+	return &Alert{
+		Key:      AlertFinalityStall + "/" + chainID,
+		Kind:     AlertFinalityStall,
+		Severity: "error",
+		Summary:  fmt.Sprintf("Rollapp %s has not been finalized past height %d for %s", chainID, p.height, stalled.Round(time.Minute)),
+		ChainID:  chainID,
+	}
+}
+
+// incompatibleUpgrade returns the alert of the upgrade u of a relayed chain requiring a newer relayer than version.
+func (a *alerter) incompatibleUpgrade(u IncompatibleChainUpgrade, version string) Alert {
+	al := Alert{
+		Key:      AlertIncompatibleUpgrade + "/" + u.key(),
+		Kind:     AlertIncompatibleUpgrade,
+		Severity: "warning",
+		Summary: fmt.Sprintf("Chain %s upgrades at height %d, requiring relayer version %s or newer, running %s",
+			u.ChainID, u.Height, u.MinVersion, version),
+		ChainID: u.ChainID,
+	}
+	if u.Name != "" {
+		al.Summary = fmt.Sprintf("Chain %s upgrades to %s at height %d, requiring relayer version %s or newer, running %s",
+			u.ChainID, u.Name, u.Height, u.MinVersion, version)
+	}
+	if u.LatestHeight >= u.Height {
+		al.Severity = "critical"
+		al.Summary = fmt.Sprintf("Chain %s upgraded at height %d, requiring relayer version %s or newer, running %s",
+			u.ChainID, u.Height, u.MinVersion, version)
+	}
+	return al
+}
+
+// firingWithPrefix returns the alerts firing whose key starts with prefix, kept firing when they cannot be checked.
+func (a *alerter) firingWithPrefix(prefix string) []Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var res []Alert
+	for k, f := range a.firing {
+		if strings.HasPrefix(k, prefix) {
+			res = append(res, f.Alert)
+		}
+	}
+	return res
+}
+
+// update posts the alerts of active that start firing, or are still firing after the repeat interval,
+// and resolves the alerts firing that are no longer active.
+func (a *alerter) update(ctx context.Context, active []Alert, now time.Time) {
+	repeat := a.cfg.RepeatInterval
+	if repeat == 0 {
+		repeat = defaultAlertRepeatInterval
+	}
+	var posts []Alert
+	a.mu.Lock()
+	keys := make(map[string]bool, len(active))
+	for _, al := range active {
+		keys[al.Key] = true
+		f, ok := a.firing[al.Key]
+		switch {
+		case !ok:
+			al.Since, al.Time = now, now
+			f = &firingAlert{Alert: al, postedAt: now}
+			a.firing[al.Key] = f
+			posts = append(posts, al)
+			a.log.Warn("Alert firing", zap.String("key", al.Key), zap.String("summary", al.Summary))
+		case now.Sub(f.postedAt) >= repeat:
+			al.Since, al.Time = f.Since, now
+			f.Alert, f.postedAt = al, now
+			posts = append(posts, al)
+		default:
+			al.Since, al.Time = f.Since, f.Time
+			f.Alert = al
+		}
+	}
+	for k, f := range a.firing {
+		if keys[k] {
+			continue
+		}
+		delete(a.firing, k)
+		resolved := f.Alert
+		resolved.Resolved, resolved.Time = true, now
+		posts = append(posts, resolved)
+		a.log.Info("Alert resolved", zap.String("key", k), zap.Duration("fired_for", now.Sub(f.Since)))
+	}
+	a.mu.Unlock()
+
+	sort.Slice(posts, func(i, j int) bool { return posts[i].Key < posts[j].Key })
+	for _, al := range posts {
+		a.post(ctx, al, now)
+	}
+}
+
+// post posts al to each webhook under its rate limit.
+func (a *alerter) post(ctx context.Context, al Alert, now time.Time) {
+	for _, w := range a.webhooks {
+		if !w.allow(now) {
+			a.metrics.IncAlerts(al.Kind, AlertRateLimited)
+			a.log.Warn("Dropping alert over the rate limit of the webhook", zap.String("webhook", w.host), zap.String("key", al.Key))
+			continue
+		}
+		postCtx, cancel := context.WithTimeout(ctx, alertPostTimeout)
+		err := w.post(postCtx, alertMessage{Alert: al, RoutingKey: w.RoutingKey, Source: a.source})
+		cancel()
+		if err != nil {
+			a.metrics.IncAlerts(al.Kind, AlertFailed)
+			a.log.Warn("Failed to post alert to webhook", zap.String("webhook", w.host), zap.String("key", al.Key), zap.Error(err))
+			continue
+		}
+		a.metrics.IncAlerts(al.Kind, AlertSent)
+	}
+}
+
+// snapshot returns the alerts firing, sorted by key.
+func (a *alerter) snapshot() []Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	res := make([]Alert, 0, len(a.firing))
+	for _, f := range a.firing {
+		res = append(res, f.Alert)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Key < res[j].Key })
+	return res
+}
+
+// checkAlerts evaluates the conditions of the alerts of a on the paths and chains relayed, and updates the alerts.
+// The alerts whose condition cannot be checked, because a query fails, are kept as they are.
+func (s *supervisor) checkAlerts(ctx context.Context, a *alerter) {
+	cfg := a.cfg
+	var active []Alert
+	add := func(al *Alert) {
+		if al != nil {
+			active = append(active, *al)
+		}
+	}
+	for _, name := range s.names {
+		r := s.runners[name]
+		if cfg.RelayErrors != nil {
+			add(a.relayErrors(name, s.metrics.FailedRelaysOf(name), time.Now()))
+		}
+		if cfg.ClientExpiry != nil {
+			for _, c := range []*Chain{r.src, r.dst} {
+				checkCtx, cancel := context.WithTimeout(ctx, alertCheckTimeout)
+				e := clientExpiry(checkCtx, name, c)
+				cancel()
+				if e.Error != "" {
+					active = append(active, a.firingWithPrefix(AlertClientExpiry+"/"+name+"/"+c.ChainID())...)
+					continue
+				}
+				add(a.clientExpiry(e, time.Now()))
+			}
+		}
+		if cfg.StuckPackets != nil {
+			checkCtx, cancel := context.WithTimeout(ctx, alertCheckTimeout)
+			pending, err := r.pendingSequences(checkCtx)
+			cancel()
+			if err != nil {
+				active = append(active, a.firingWithPrefix(AlertStuckPackets+"/"+name+"/")...)
+				continue
+			}
+			for _, p := range pending {
+				if p.Error != "" {
+					active = append(active, a.firingWithPrefix(AlertStuckPackets+"/"+name+"/"+p.PortID+"/"+p.ChannelID)...)
+					continue
+				}
+				if r.pauses.isPaused(p.PortID, p.ChannelID) {
+					continue
+				}
+				add(a.stuckPackets(p, time.Now()))
+			}
+		}
+	}
+	if cfg.FinalityStall != nil {
+		for _, c := range s.chains() {
+			sp := settlementProvider(c.ChainProvider)
+			if sp == nil {
+				continue
+			}
+			checkCtx, cancel := context.WithTimeout(ctx, alertCheckTimeout)
+			h, err := sp.QueryLatestFinalizedHeight(checkCtx, c.ChainID())
+			cancel()
+			if err != nil {
+				active = append(active, a.firingWithPrefix(AlertFinalityStall+"/"+c.ChainID())...)
+				continue
+			}
+			add(a.finalityStall(c.ChainID(), h, time.Now()))
+		}
+	}
+	if a.releases != nil {
+		for _, u := range a.releases.snapshot().Incompatible {
+			active = append(active, a.incompatibleUpgrade(u, a.releases.version))
+		}
+	}
+	a.update(ctx, active, time.Now())
+}
+
+// startAlerts starts checking the conditions of cfg every interval, and the incompatible upgrades found by releases
+// if non-nil, posting the alerts to its webhooks.
+func (s *supervisor) startAlerts(ctx context.Context, cfg *AlertsConfig, releases *releaseChecker) *alerter {
+	a := newAlerter(s.log.With(zap.String("sys", "alerts")), s.metrics, cfg)
+	a.releases = releases
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultAlertsInterval
+	}
+	s.maintenance.schedule(ctx, TaskAlerts, interval, func(ctx context.Context) {
+		s.checkAlerts(ctx, a)
+	})
+	return a
+}
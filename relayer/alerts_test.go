@@ -0,0 +1,143 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAlertsConfigValidate(t *testing.T) {
+	webhook := AlertWebhook{URL: "https://hooks.slack.com/services/T000/B000/XXXX", Format: AlertFormatSlack}
+	valid := AlertsConfig{Webhooks: []AlertWebhook{webhook}, StuckPackets: &StuckPacketsAlert{Age: 15 * time.Minute}}
+	require.NoError(t, valid.Validate())
+	var none *AlertsConfig
+	require.NoError(t, none.Validate())
+
+	c := valid
+	c.Webhooks = nil
+	require.EqualError(t, c.Validate(), "alerts without webhooks")
+	c.Webhooks = []AlertWebhook{{URL: webhook.URL, Format: "teams"}}
+	require.EqualError(t, c.Validate(), "alert webhook 0: unknown format teams")
+	c.Webhooks = []AlertWebhook{{URL: webhook.URL, Format: AlertFormatPagerDuty}}
+	require.EqualError(t, c.Validate(), "alert webhook 0: pagerduty format without routing-key")
+	c.Webhooks = []AlertWebhook{{URL: webhook.URL, Template: `{"text": {{ json .Message }}}`}}
+	require.ErrorContains(t, c.Validate(), "can't evaluate field Message")
+	c.Webhooks = []AlertWebhook{{URL: "ftp://alerts.example.com"}}
+	require.EqualError(t, c.Validate(), "alert webhook 0: url must be an http or https URL, got ftp")
+	c = valid
+	c.RelayErrors = &RelayErrorsAlert{Count: 5}
+	require.EqualError(t, c.Validate(), "relay-errors alert must have a positive count and window")
+}
+
+func TestAlerterConditions(t *testing.T) {
+	a := newAlerter(zap.NewNop(), nil, &AlertsConfig{
+		RelayErrors:   &RelayErrorsAlert{Count: 3, Window: 10 * time.Minute},
+		ClientExpiry:  &ClientExpiryAlert{Within: 24 * time.Hour},
+		StuckPackets:  &StuckPacketsAlert{Age: 15 * time.Minute},
+		FinalityStall: &FinalityStallAlert{After: 30 * time.Minute},
+	})
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	// Failures are counted over the window only.
+	require.Nil(t, a.relayErrors("a-b", 10, at(0)))
+	require.Nil(t, a.relayErrors("a-b", 12, at(5*time.Minute)))
+	al := a.relayErrors("a-b", 13, at(9*time.Minute))
+	require.NotNil(t, al)
+	require.Equal(t, "relay_errors/a-b", al.Key)
+	require.Equal(t, "Path a-b failed to send 3 transactions in the last 10m0s", al.Summary)
+	require.Nil(t, a.relayErrors("a-b", 13, at(16*time.Minute)))
+
+	expiresAt := at(3 * time.Hour)
+	e := admin.ClientExpiry{Path: "a-b", ChainID: "chain-a", ClientID: "07-tendermint-0", ExpiresAt: &expiresAt}
+	al = a.clientExpiry(e, at(0))
+	require.Equal(t, "Client 07-tendermint-0 on chain-a of path a-b expires in 3h0m0s unless updated", al.Summary)
+	require.Equal(t, "Client 07-tendermint-0 on chain-a of path a-b has expired", a.clientExpiry(e, at(4*time.Hour)).Summary)
+	far := at(48 * time.Hour)
+	e.ExpiresAt = &far
+	require.Nil(t, a.clientExpiry(e, at(0)))
+
+	// Packets are stuck once pending for longer than the age, and no longer once relayed.
+	pending := admin.PendingSequences{Path: "a-b", PortID: "transfer", ChannelID: "channel-0", SrcPackets: []uint64{1, 2}}
+	require.Nil(t, a.stuckPackets(pending, at(0)))
+	pending.SrcPackets, pending.DstAcks = []uint64{2}, []uint64{7}
+	require.Nil(t, a.stuckPackets(pending, at(10*time.Minute)))
+	al = a.stuckPackets(pending, at(20*time.Minute))
+	require.Equal(t, "stuck_packets/a-b/transfer/channel-0", al.Key)
+	require.Equal(t, "1 packets and acknowledgements of transfer/channel-0 of path a-b are pending for more than 15m0s, the oldest for 20m0s", al.Summary)
+	pending.SrcPackets = nil
+	require.Nil(t, a.stuckPackets(pending, at(21*time.Minute)))
+
+	require.Nil(t, a.finalityStall("rollapp", 100, at(0)))
+	require.Nil(t, a.finalityStall("rollapp", 120, at(20*time.Minute)))
+	require.Nil(t, a.finalityStall("rollapp", 120, at(40*time.Minute)))
+	al = a.finalityStall("rollapp", 120, at(50*time.Minute))
+	require.Equal(t, "Rollapp rollapp has not been finalized past height 120 for 30m0s", al.Summary)
+}
+
+func TestAlerterPostsTransitions(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		slack []string
+		pd    []map[string]any
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch req.URL.Path {
+		case "/slack":
+			var body struct{ Text string }
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+			slack = append(slack, body.Text)
+		case "/pagerduty":
+			require.Equal(t, "Token secret", req.Header.Get("Authorization"))
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+			pd = append(pd, body)
+		}
+	}))
+	defer srv.Close()
+
+	a := newAlerter(zap.NewNop(), nil, &AlertsConfig{Webhooks: []AlertWebhook{
+		{URL: srv.URL + "/slack", Format: AlertFormatSlack, MaxPerHour: 2},
+		{URL: srv.URL + "/pagerduty", Format: AlertFormatPagerDuty, RoutingKey: "R0UT1NG", Headers: map[string]string{"Authorization": "Token secret"}},
+	}})
+	ctx := context.Background()
+	start := time.Now()
+	expiry := Alert{Key: "client_expiry/a-b/chain-a", Kind: AlertClientExpiry, Severity: "critical", Summary: "Client expires"}
+	errs := Alert{Key: "relay_errors/a-b", Kind: AlertRelayErrors, Severity: "error", Summary: "Path fails"}
+
+	a.update(ctx, []Alert{expiry}, start)
+	a.update(ctx, []Alert{expiry, errs}, start.Add(time.Minute))
+	require.Len(t, a.snapshot(), 2)
+	require.Equal(t, start, a.snapshot()[0].Since)
+
+	// Firing alerts are posted again after the repeat interval, and resolved once no longer active.
+	a.update(ctx, []Alert{errs}, start.Add(61*time.Minute))
+	require.Equal(t, []Alert{{
+		Key: errs.Key, Kind: errs.Kind, Severity: errs.Severity, Summary: errs.Summary,
+		Since: start.Add(time.Minute), Time: start.Add(61 * time.Minute),
+	}}, a.snapshot())
+	a.update(ctx, nil, start.Add(62*time.Minute))
+	require.Empty(t, a.snapshot())
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The slack webhook drops the alerts over its limit of 2 per hour.
+	require.Equal(t, []string{"[FIRING] Client expires", "[FIRING] Path fails", "[RESOLVED] Client expires", "[FIRING] Path fails"}, slack)
+	require.Len(t, pd, 5)
+	require.Equal(t, "R0UT1NG", pd[0]["routing_key"])
+	require.Equal(t, "client_expiry/a-b/chain-a", pd[0]["dedup_key"])
+	require.Equal(t, "trigger", pd[0]["event_action"])
+	require.Equal(t, "critical", pd[0]["payload"].(map[string]any)["severity"])
+	require.Equal(t, "resolve", pd[4]["event_action"])
+	require.Equal(t, "relay_errors/a-b", pd[4]["dedup_key"])
+}
@@ -0,0 +1,534 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	transfertypes "github.com/cosmos/ibc-go/v3/modules/apps/transfer/types"
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// BootstrapOptions configures the bootstrap of a path, see BootstrapPath.
+type BootstrapOptions struct {
+	// SrcPortID and DstPortID are the ports of the channel, transfer by default.
+	SrcPortID, DstPortID string
+
+	// Order and Version are those of the channel, an unordered ics20-1 channel by default.
+	Order, Version string
+
+	AllowUpdateAfterExpiry       bool
+	AllowUpdateAfterMisbehaviour bool
+
+	// MaxRetries bounds the attempts of each handshake step, retried while the step does not complete.
+	MaxRetries uint64
+
+	// Timeout is the time given to each attempt of a handshake step.
+	Timeout time.Duration
+
+	Memo string
+}
+
+// BootstrapResult holds the identifiers of the clients, connection and channel of a bootstrapped path.
+type BootstrapResult struct {
+	SrcClientID     string `json:"src_client_id"`
+	DstClientID     string `json:"dst_client_id"`
+	SrcConnectionID string `json:"src_connection_id"`
+	DstConnectionID string `json:"dst_connection_id"`
+	SrcChannelID    string `json:"src_channel_id"`
+	DstChannelID    string `json:"dst_channel_id"`
+
+	// Modified is set once the client or connection identifiers of the path ends changed,
+	// for the path to be saved to the config.
+	Modified bool `json:"-"`
+}
+
+// BootstrapPath creates the clients of c on dst and dst on c, opens a connection between them
+// and opens a channel on the connection, transfer channel by default.
+//
+// It is idempotent: the clients, connection and channel found on the chains are reused, and handshakes
+// are resumed from whichever state they were left in, e.g. by a previous bootstrap which timed out.
+// Each handshake goes one step at a time, the state of both ends being queried before each step,
+// so that a step which failed is retried, up to opts.MaxRetries times.
+//
+// The path ends of c and dst are updated with the identifiers of the clients and connection. On error,
+// the result holds the identifiers found so far, to be saved to the config if modified.
+func (c *Chain) BootstrapPath(ctx context.Context, dst *Chain, opts BootstrapOptions) (*BootstrapResult, error) {
+	if opts.SrcPortID == "" {
+		opts.SrcPortID = transfertypes.PortID
+	}
+	if opts.DstPortID == "" {
+		opts.DstPortID = transfertypes.PortID
+	}
+	if opts.Order == "" {
+		opts.Order = chantypes.UNORDERED.String()
+	}
+	if opts.Version == "" {
+		opts.Version = transfertypes.Version
+	}
+	if err := ValidateChannelParams(opts.SrcPortID, opts.DstPortID, opts.Order); err != nil {
+		return nil, err
+	}
+
+	res := &BootstrapResult{}
+	modified, err := c.CreateClients(ctx, dst, opts.AllowUpdateAfterExpiry, opts.AllowUpdateAfterMisbehaviour, false, opts.Memo)
+	res.Modified = modified
+	res.setPathEnds(c, dst)
+	if err != nil {
+		return res, fmt.Errorf("error creating clients: %w", err)
+	}
+
+	srcConn, dstConn, err := runHandshake(ctx, c.log, "connection", opts, func(ctx context.Context) (handshakeEnd, handshakeEnd, error) {
+		return c.queryConnectionHandshake(ctx, dst)
+	}, func(ctx context.Context, step handshakeStep, onSrc bool, srcEnd, dstEnd handshakeEnd) error {
+		if onSrc {
+			return c.runHandshakeStep(ctx, dst, opts, connectionHandshakeLifecycle(step, c, dst, srcEnd, dstEnd))
+		}
+		return c.runHandshakeStep(ctx, dst, opts, connectionHandshakeLifecycle(step, dst, c, dstEnd, srcEnd))
+	})
+	if err != nil {
+		return res, err
+	}
+	if c.PathEnd.ConnectionID != srcConn.ID || dst.PathEnd.ConnectionID != dstConn.ID {
+		c.PathEnd.ConnectionID, dst.PathEnd.ConnectionID = srcConn.ID, dstConn.ID
+		res.Modified = true
+	}
+	res.setPathEnds(c, dst)
+
+	srcChan, dstChan, err := runHandshake(ctx, c.log, "channel", opts, func(ctx context.Context) (handshakeEnd, handshakeEnd, error) {
+		return c.queryChannelHandshake(ctx, dst, opts.SrcPortID, opts.DstPortID)
+	}, func(ctx context.Context, step handshakeStep, onSrc bool, srcEnd, dstEnd handshakeEnd) error {
+		if onSrc {
+			return c.runHandshakeStep(ctx, dst, opts, channelHandshakeLifecycle(step, c, dst, srcEnd, dstEnd, opts.SrcPortID, opts.DstPortID, opts))
+		}
+		return c.runHandshakeStep(ctx, dst, opts, channelHandshakeLifecycle(step, dst, c, dstEnd, srcEnd, opts.DstPortID, opts.SrcPortID, opts))
+	})
+	if err != nil {
+		return res, err
+	}
+	res.SrcChannelID, res.DstChannelID = srcChan.ID, dstChan.ID
+
+	c.log.Info(
+		"Path bootstrapped",
+		zap.String("src_chain_id", c.ChainID()),
+		zap.String("src_client_id", res.SrcClientID),
+		zap.String("src_connection_id", res.SrcConnectionID),
+		zap.String("src_channel_id", res.SrcChannelID),
+		zap.String("dst_chain_id", dst.ChainID()),
+		zap.String("dst_client_id", res.DstClientID),
+		zap.String("dst_connection_id", res.DstConnectionID),
+		zap.String("dst_channel_id", res.DstChannelID),
+	)
+	return res, nil
+}
+
+func (res *BootstrapResult) setPathEnds(src, dst *Chain) {
+	res.SrcClientID, res.DstClientID = src.PathEnd.ClientID, dst.PathEnd.ClientID
+	res.SrcConnectionID, res.DstConnectionID = src.PathEnd.ConnectionID, dst.PathEnd.ConnectionID
+}
+
+// handshakeState is the state of one end of a connection or channel handshake,
+// numbered as the states of connections and channels.
+type handshakeState int32
+
+const (
+	handshakeNone handshakeState = iota
+	handshakeInit
+	handshakeTryOpen
+	handshakeOpen
+)
+
+func (s handshakeState) String() string {
+	switch s {
+	case handshakeNone:
+		return "NONE"
+	case handshakeInit:
+		return "INIT"
+	case handshakeTryOpen:
+		return "TRYOPEN"
+	case handshakeOpen:
+		return "OPEN"
+	}
+	return fmt.Sprintf("handshakeState(%d)", int32(s))
+}
+
+// handshakeEnd is one end of a connection or channel handshake, the zero value before the handshake reached its chain.
+type handshakeEnd struct {
+	ID             string
+	CounterpartyID string
+	State          handshakeState
+}
+
+// handshakeStep is the next message of a handshake.
+type handshakeStep int
+
+const (
+	handshakeDone handshakeStep = iota
+	stepOpenInit
+	stepOpenTry
+	stepOpenAck
+	stepOpenConfirm
+)
+
+func (s handshakeStep) String() string {
+	switch s {
+	case handshakeDone:
+		return "Done"
+	case stepOpenInit:
+		return "OpenInit"
+	case stepOpenTry:
+		return "OpenTry"
+	case stepOpenAck:
+		return "OpenAck"
+	case stepOpenConfirm:
+		return "OpenConfirm"
+	}
+	return fmt.Sprintf("handshakeStep(%d)", int(s))
+}
+
+// nextHandshakeStep returns the next step of the handshake with its ends in the states src and dst,
+// and whether its message is sent to src, or to dst. Handshakes are initiated on src.
+func nextHandshakeStep(src, dst handshakeState) (step handshakeStep, onSrc bool, err error) {
+	switch {
+	case src == handshakeOpen && dst == handshakeOpen:
+		return handshakeDone, false, nil
+	case src == handshakeNone && dst == handshakeNone:
+		return stepOpenInit, true, nil
+	case src == handshakeInit && dst == handshakeNone:
+		return stepOpenTry, false, nil
+	case src == handshakeNone && dst == handshakeInit:
+		return stepOpenTry, true, nil
+	case src == handshakeInit && dst == handshakeTryOpen:
+		return stepOpenAck, true, nil
+	case src == handshakeTryOpen && dst == handshakeInit:
+		return stepOpenAck, false, nil
+	case src == handshakeOpen && dst == handshakeTryOpen:
+		return stepOpenConfirm, false, nil
+	case src == handshakeTryOpen && dst == handshakeOpen:
+		return stepOpenConfirm, true, nil
+	}
+	return handshakeDone, false, fmt.Errorf("cannot resume handshake in state %s on src and %s on dst", src, dst)
+}
+
+// pairHandshakeEnds returns the most advanced handshake among the ends found on src and dst:
+// the ends referring to each other, or an end in INIT the counterparty has no end for yet.
+// Both ends are the zero value if there is no handshake to resume.
+func pairHandshakeEnds(srcs, dsts []handshakeEnd) (src, dst handshakeEnd) {
+	best := handshakeState(-1)
+	consider := func(s, d handshakeEnd) {
+		if s.State+d.State > best {
+			best = s.State + d.State
+			src, dst = s, d
+		}
+	}
+	for _, s := range srcs {
+		for _, d := range dsts {
+			switch {
+			case s.CounterpartyID != "" && s.CounterpartyID == d.ID && (d.CounterpartyID == "" || d.CounterpartyID == s.ID):
+				consider(s, d)
+			case s.CounterpartyID == "" && d.CounterpartyID == s.ID:
+				consider(s, d)
+			}
+		}
+	}
+	for _, s := range srcs {
+		if s.State == handshakeInit && s.CounterpartyID == "" {
+			consider(s, handshakeEnd{})
+		}
+	}
+	for _, d := range dsts {
+		if d.State == handshakeInit && d.CounterpartyID == "" {
+			consider(handshakeEnd{}, d)
+		}
+	}
+	return src, dst
+}
+
+// runHandshake drives a handshake to completion, querying the state of its ends with query before each step,
+// and sending the message of the step with send. It returns the ends of the open handshake.
+func runHandshake(
+	ctx context.Context,
+	log *zap.Logger,
+	kind string,
+	opts BootstrapOptions,
+	query func(context.Context) (src, dst handshakeEnd, err error),
+	send func(ctx context.Context, step handshakeStep, onSrc bool, src, dst handshakeEnd) error,
+) (handshakeEnd, handshakeEnd, error) {
+	maxAttempts := opts.MaxRetries
+	if maxAttempts == 0 {
+		maxAttempts = 1
+	}
+	var (
+		lastStep handshakeStep
+		lastSrc  bool
+		attempts uint64
+	)
+	for {
+		src, dst, err := query(ctx)
+		if err != nil {
+			return handshakeEnd{}, handshakeEnd{}, fmt.Errorf("error querying %s handshake: %w", kind, err)
+		}
+		step, onSrc, err := nextHandshakeStep(src.State, dst.State)
+		if err != nil {
+			return src, dst, fmt.Errorf("%s handshake between %q and %q: %w", kind, src.ID, dst.ID, err)
+		}
+		if step == handshakeDone {
+			return src, dst, nil
+		}
+
+		if step == lastStep && onSrc == lastSrc {
+			attempts++
+		} else {
+			lastStep, lastSrc, attempts = step, onSrc, 1
+		}
+		if attempts > maxAttempts {
+			return src, dst, fmt.Errorf("%s handshake step %s did not complete after %d attempts", kind, step, maxAttempts)
+		}
+
+		log.Info(
+			"Sending handshake step",
+			zap.String("handshake", kind),
+			zap.Stringer("step", step),
+			zap.Bool("on_src", onSrc),
+			zap.String("src_id", src.ID),
+			zap.Stringer("src_state", src.State),
+			zap.String("dst_id", dst.ID),
+			zap.Stringer("dst_state", dst.State),
+			zap.Uint64("attempt", attempts),
+			zap.Uint64("max_attempts", maxAttempts),
+		)
+		if err := send(ctx, step, onSrc, src, dst); err != nil {
+			if ctx.Err() != nil {
+				return src, dst, ctx.Err()
+			}
+			log.Info(
+				"Handshake step failed",
+				zap.String("handshake", kind),
+				zap.Stringer("step", step),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// runHandshakeStep runs the event processor between c and dst until the termination of lifecycle,
+// or opts.Timeout.
+func (c *Chain) runHandshakeStep(ctx context.Context, dst *Chain, opts BootstrapOptions, lifecycle processor.MessageLifecycle) error {
+	srcPathChain := pathChain{
+		provider: c.ChainProvider,
+		pathEnd:  processor.NewPathEnd(c.PathEnd.ChainID, c.PathEnd.ClientID, "", []processor.ChannelKey{}),
+	}
+	dstPathChain := pathChain{
+		provider: dst.ChainProvider,
+		pathEnd:  processor.NewPathEnd(dst.PathEnd.ChainID, dst.PathEnd.ClientID, "", []processor.ChannelKey{}),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	pp := processor.NewPathProcessor(
+		c.log,
+		srcPathChain.pathEnd,
+		dstPathChain.pathEnd,
+		opts.Memo,
+	)
+
+	return processor.NewEventProcessor().
+		WithChainProcessors(
+			srcPathChain.chainProcessor(c.log, nil, nil, nil),
+			dstPathChain.chainProcessor(c.log, nil, nil, nil),
+		).
+		WithPathProcessors(pp).
+		WithInitialBlockHistory(0).
+		WithMessageLifecycle(lifecycle).
+		Build().
+		Run(ctx)
+}
+
+var connectionHandshakeEventTypes = map[handshakeStep]string{
+	stepOpenInit:    conntypes.EventTypeConnectionOpenInit,
+	stepOpenTry:     conntypes.EventTypeConnectionOpenTry,
+	stepOpenAck:     conntypes.EventTypeConnectionOpenAck,
+	stepOpenConfirm: conntypes.EventTypeConnectionOpenConfirm,
+}
+
+// connectionHandshakeLifecycle returns the lifecycle sending the message of step to the chain of end,
+// counterparty being the other end of the connection, and terminating once its event is observed.
+func connectionHandshakeLifecycle(step handshakeStep, chain, counterparty *Chain, end, counterpartyEnd handshakeEnd) *processor.ConnectionMessageLifecycle {
+	info := provider.ConnectionInfo{
+		ClientID:             chain.PathEnd.ClientID,
+		CounterpartyClientID: counterparty.PathEnd.ClientID,
+	}
+	if step != stepOpenInit {
+		// assembled from the end of the counterparty, as relayed from its event
+		info = provider.ConnectionInfo{
+			ClientID:             counterparty.PathEnd.ClientID,
+			ConnID:               counterpartyEnd.ID,
+			CounterpartyClientID: chain.PathEnd.ClientID,
+			CounterpartyConnID:   end.ID,
+		}
+	}
+	return &processor.ConnectionMessageLifecycle{
+		Initial: &processor.ConnectionMessage{
+			ChainID:   chain.PathEnd.ChainID,
+			EventType: connectionHandshakeEventTypes[step],
+			Info:      info,
+		},
+		Termination: &processor.ConnectionMessage{
+			ChainID:   chain.PathEnd.ChainID,
+			EventType: connectionHandshakeEventTypes[step],
+			Info: provider.ConnectionInfo{
+				ClientID:             chain.PathEnd.ClientID,
+				CounterpartyClientID: counterparty.PathEnd.ClientID,
+			},
+		},
+	}
+}
+
+var channelHandshakeEventTypes = map[handshakeStep]string{
+	stepOpenInit:    chantypes.EventTypeChannelOpenInit,
+	stepOpenTry:     chantypes.EventTypeChannelOpenTry,
+	stepOpenAck:     chantypes.EventTypeChannelOpenAck,
+	stepOpenConfirm: chantypes.EventTypeChannelOpenConfirm,
+}
+
+// channelHandshakeLifecycle returns the lifecycle sending the message of step to the chain of end on portID,
+// counterparty being the other end of the channel on counterpartyPortID, and terminating once its event is observed.
+func channelHandshakeLifecycle(
+	step handshakeStep,
+	chain, counterparty *Chain,
+	end, counterpartyEnd handshakeEnd,
+	portID, counterpartyPortID string,
+	opts BootstrapOptions,
+) *processor.ChannelMessageLifecycle {
+	info := provider.ChannelInfo{
+		PortID:             portID,
+		CounterpartyPortID: counterpartyPortID,
+		ConnID:             chain.PathEnd.ConnectionID,
+		Version:            opts.Version,
+		Order:              OrderFromString(opts.Order),
+	}
+	if step != stepOpenInit {
+		// assembled from the end of the counterparty, as relayed from its event
+		info = provider.ChannelInfo{
+			PortID:                counterpartyPortID,
+			ChannelID:             counterpartyEnd.ID,
+			CounterpartyPortID:    portID,
+			CounterpartyChannelID: end.ID,
+			ConnID:                counterparty.PathEnd.ConnectionID,
+			CounterpartyConnID:    chain.PathEnd.ConnectionID,
+			Version:               opts.Version,
+			Order:                 OrderFromString(opts.Order),
+		}
+	}
+	return &processor.ChannelMessageLifecycle{
+		Initial: &processor.ChannelMessage{
+			ChainID:   chain.PathEnd.ChainID,
+			EventType: channelHandshakeEventTypes[step],
+			Info:      info,
+		},
+		Termination: &processor.ChannelMessage{
+			ChainID:   chain.PathEnd.ChainID,
+			EventType: channelHandshakeEventTypes[step],
+			Info: provider.ChannelInfo{
+				PortID:             portID,
+				CounterpartyPortID: counterpartyPortID,
+			},
+		},
+	}
+}
+
+// queryConnectionHandshake returns the ends of the connection handshake between the clients of c and dst,
+// restricted to the connections of the path ends if set.
+func (c *Chain) queryConnectionHandshake(ctx context.Context, dst *Chain) (src, dstEnd handshakeEnd, err error) {
+	srch, dsth, err := QueryLatestHeights(ctx, c, dst)
+	if err != nil {
+		return src, dstEnd, err
+	}
+	srcs, err := connectionHandshakeEnds(ctx, c, dst, srch)
+	if err != nil {
+		return src, dstEnd, err
+	}
+	dsts, err := connectionHandshakeEnds(ctx, dst, c, dsth)
+	if err != nil {
+		return src, dstEnd, err
+	}
+	src, dstEnd = pairHandshakeEnds(srcs, dsts)
+	return src, dstEnd, nil
+}
+
+// connectionHandshakeEnds returns the connections on the client of chain to the client of counterparty.
+func connectionHandshakeEnds(ctx context.Context, chain, counterparty *Chain, height int64) ([]handshakeEnd, error) {
+	var conns []*conntypes.IdentifiedConnection
+	if err := retry.Do(func() error {
+		var err error
+		conns, err = chain.ChainProvider.QueryConnectionsUsingClient(ctx, height, chain.PathEnd.ClientID)
+		return err
+	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr); err != nil {
+		return nil, err
+	}
+	var ends []handshakeEnd
+	for _, conn := range conns {
+		if conn.Counterparty.ClientId != counterparty.PathEnd.ClientID {
+			continue
+		}
+		if chain.PathEnd.ConnectionID != "" && conn.Id != chain.PathEnd.ConnectionID {
+			continue
+		}
+		ends = append(ends, handshakeEnd{
+			ID:             conn.Id,
+			CounterpartyID: conn.Counterparty.ConnectionId,
+			State:          handshakeState(conn.State),
+		})
+	}
+	return ends, nil
+}
+
+// queryChannelHandshake returns the ends of the channel handshake between srcPortID of c and dstPortID of dst
+// over the connection of the path.
+func (c *Chain) queryChannelHandshake(ctx context.Context, dst *Chain, srcPortID, dstPortID string) (src, dstEnd handshakeEnd, err error) {
+	srch, dsth, err := QueryLatestHeights(ctx, c, dst)
+	if err != nil {
+		return src, dstEnd, err
+	}
+	srcs, err := channelHandshakeEnds(ctx, c, srch, srcPortID, dstPortID)
+	if err != nil {
+		return src, dstEnd, err
+	}
+	dsts, err := channelHandshakeEnds(ctx, dst, dsth, dstPortID, srcPortID)
+	if err != nil {
+		return src, dstEnd, err
+	}
+	src, dstEnd = pairHandshakeEnds(srcs, dsts)
+	return src, dstEnd, nil
+}
+
+// channelHandshakeEnds returns the channels on portID of the connection of chain to counterpartyPortID,
+// closed channels aside.
+func channelHandshakeEnds(ctx context.Context, chain *Chain, height int64, portID, counterpartyPortID string) ([]handshakeEnd, error) {
+	var channels []*chantypes.IdentifiedChannel
+	if err := retry.Do(func() error {
+		var err error
+		channels, err = chain.ChainProvider.QueryConnectionChannels(ctx, height, chain.PathEnd.ConnectionID)
+		return err
+	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr); err != nil {
+		return nil, err
+	}
+	var ends []handshakeEnd
+	for _, channel := range channels {
+		if channel.PortId != portID || channel.Counterparty.PortId != counterpartyPortID || channel.State == chantypes.CLOSED {
+			continue
+		}
+		ends = append(ends, handshakeEnd{
+			ID:             channel.ChannelId,
+			CounterpartyID: channel.Counterparty.ChannelId,
+			State:          handshakeState(channel.State),
+		})
+	}
+	return ends, nil
+}
@@ -0,0 +1,148 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNextHandshakeStep(t *testing.T) {
+	for _, tc := range []struct {
+		src, dst handshakeState
+		step     handshakeStep
+		onSrc    bool
+	}{
+		{handshakeNone, handshakeNone, stepOpenInit, true},
+		{handshakeInit, handshakeNone, stepOpenTry, false},
+		{handshakeNone, handshakeInit, stepOpenTry, true},
+		{handshakeInit, handshakeTryOpen, stepOpenAck, true},
+		{handshakeTryOpen, handshakeInit, stepOpenAck, false},
+		{handshakeOpen, handshakeTryOpen, stepOpenConfirm, false},
+		{handshakeTryOpen, handshakeOpen, stepOpenConfirm, true},
+		{handshakeOpen, handshakeOpen, handshakeDone, false},
+	} {
+		step, onSrc, err := nextHandshakeStep(tc.src, tc.dst)
+		require.NoError(t, err, "%s/%s", tc.src, tc.dst)
+		require.Equal(t, tc.step, step, "%s/%s", tc.src, tc.dst)
+		require.Equal(t, tc.onSrc, onSrc, "%s/%s", tc.src, tc.dst)
+	}
+
+	// Crossing hellos and ends open without a counterparty are not resumed.
+	_, _, err := nextHandshakeStep(handshakeInit, handshakeInit)
+	require.Error(t, err)
+	_, _, err = nextHandshakeStep(handshakeOpen, handshakeNone)
+	require.Error(t, err)
+}
+
+func TestPairHandshakeEnds(t *testing.T) {
+	// An abandoned handshake and one where dst answered the INIT of src.
+	srcs := []handshakeEnd{
+		{ID: "connection-0", State: handshakeInit},
+		{ID: "connection-1", State: handshakeInit},
+	}
+	dsts := []handshakeEnd{{ID: "connection-7", CounterpartyID: "connection-1", State: handshakeTryOpen}}
+	src, dst := pairHandshakeEnds(srcs, dsts)
+	require.Equal(t, srcs[1], src)
+	require.Equal(t, dsts[0], dst)
+
+	// Once src acknowledged, both ends refer to each other.
+	srcs[1] = handshakeEnd{ID: "connection-1", CounterpartyID: "connection-7", State: handshakeOpen}
+	src, dst = pairHandshakeEnds(srcs, dsts)
+	require.Equal(t, srcs[1], src)
+	require.Equal(t, dsts[0], dst)
+
+	// A lone INIT is resumed, whichever chain it is on.
+	src, dst = pairHandshakeEnds(nil, []handshakeEnd{{ID: "channel-3", State: handshakeInit}})
+	require.Equal(t, handshakeEnd{}, src)
+	require.Equal(t, "channel-3", dst.ID)
+
+	// An end referring to a counterparty not found is not resumed.
+	src, dst = pairHandshakeEnds([]handshakeEnd{{ID: "channel-0", CounterpartyID: "channel-9", State: handshakeTryOpen}}, nil)
+	require.Equal(t, handshakeEnd{}, src)
+	require.Equal(t, handshakeEnd{}, dst)
+}
+
+func TestRunHandshakeResumesMidway(t *testing.T) {
+	// The connection was left with src in INIT and dst in TRYOPEN, the first OpenAck failing.
+	src := handshakeEnd{ID: "connection-1", State: handshakeInit}
+	dst := handshakeEnd{ID: "connection-7", CounterpartyID: "connection-1", State: handshakeTryOpen}
+	var sent []handshakeStep
+	failed := false
+	gotSrc, gotDst, err := runHandshake(context.Background(), zap.NewNop(), "connection", BootstrapOptions{MaxRetries: 2},
+		func(context.Context) (handshakeEnd, handshakeEnd, error) { return src, dst, nil },
+		func(_ context.Context, step handshakeStep, onSrc bool, _, _ handshakeEnd) error {
+			sent = append(sent, step)
+			switch {
+			case step == stepOpenAck && !failed:
+				failed = true
+				return errors.New("tx failed")
+			case step == stepOpenAck:
+				require.True(t, onSrc)
+				src.CounterpartyID, src.State = dst.ID, handshakeOpen
+			case step == stepOpenConfirm:
+				require.False(t, onSrc)
+				dst.State = handshakeOpen
+			}
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []handshakeStep{stepOpenAck, stepOpenAck, stepOpenConfirm}, sent)
+	require.Equal(t, "connection-1", gotSrc.ID)
+	require.Equal(t, "connection-7", gotDst.ID)
+}
+
+func TestRunHandshakeGivesUpAfterMaxRetries(t *testing.T) {
+	attempts := 0
+	_, _, err := runHandshake(context.Background(), zap.NewNop(), "channel", BootstrapOptions{MaxRetries: 3},
+		func(context.Context) (handshakeEnd, handshakeEnd, error) { return handshakeEnd{}, handshakeEnd{}, nil },
+		func(context.Context, handshakeStep, bool, handshakeEnd, handshakeEnd) error {
+			attempts++
+			return nil
+		},
+	)
+	require.ErrorContains(t, err, "channel handshake step OpenInit did not complete after 3 attempts")
+	require.Equal(t, 3, attempts)
+}
+
+func TestHandshakeLifecycles(t *testing.T) {
+	a := &Chain{PathEnd: &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0", ConnectionID: "connection-1"}}
+	b := &Chain{PathEnd: &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-4", ConnectionID: "connection-7"}}
+
+	// MsgConnectionOpenTry to b is assembled from the connection of a in INIT.
+	conn := connectionHandshakeLifecycle(stepOpenTry, b, a, handshakeEnd{}, handshakeEnd{ID: "connection-1", State: handshakeInit})
+	require.Equal(t, "chain-b", conn.Initial.ChainID)
+	require.Equal(t, conntypes.EventTypeConnectionOpenTry, conn.Initial.EventType)
+	require.Equal(t, provider.ConnectionInfo{
+		ClientID:             "07-tendermint-0",
+		ConnID:               "connection-1",
+		CounterpartyClientID: "07-tendermint-4",
+	}, conn.Initial.Info)
+	require.Equal(t, provider.ConnectionInfo{ClientID: "07-tendermint-4", CounterpartyClientID: "07-tendermint-0"}, conn.Termination.Info)
+
+	// MsgChannelOpenConfirm to a is assembled from the channel of b in OPEN.
+	opts := BootstrapOptions{Order: "unordered", Version: "ics20-1"}
+	ch := channelHandshakeLifecycle(stepOpenConfirm, a, b,
+		handshakeEnd{ID: "channel-0", CounterpartyID: "channel-5", State: handshakeTryOpen},
+		handshakeEnd{ID: "channel-5", CounterpartyID: "channel-0", State: handshakeOpen},
+		"transfer", "transfer", opts,
+	)
+	require.Equal(t, "chain-a", ch.Initial.ChainID)
+	require.Equal(t, chantypes.EventTypeChannelOpenConfirm, ch.Initial.EventType)
+	require.Equal(t, provider.ChannelInfo{
+		PortID:                "transfer",
+		ChannelID:             "channel-5",
+		CounterpartyPortID:    "transfer",
+		CounterpartyChannelID: "channel-0",
+		ConnID:                "connection-7",
+		CounterpartyConnID:    "connection-1",
+		Version:               "ics20-1",
+		Order:                 chantypes.UNORDERED,
+	}, ch.Initial.Info)
+}
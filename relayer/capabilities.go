@@ -0,0 +1,141 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// capabilityDetectTimeout bounds the time spent detecting the capabilities of the endpoint of each chain at startup.
+const capabilityDetectTimeout = 10 * time.Second
+
+// capabilityDetector is implemented by the providers detecting the capabilities of their endpoint.
+type capabilityDetector interface {
+	DetectCapabilities(ctx context.Context) provider.Capabilities
+}
+
+// detectCapabilities detects the capabilities of the endpoints of the chains at once, keyed by chain ID.
+// Chains whose provider does not detect its capabilities are left out.
+func detectCapabilities(ctx context.Context, log *zap.Logger, chains []*Chain) map[string]provider.Capabilities {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		caps = make(map[string]provider.Capabilities, len(chains))
+	)
+	for _, c := range chains {
+		d, ok := c.ChainProvider.(capabilityDetector)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(c *Chain) {
+			defer wg.Done()
+			detectCtx, cancel := context.WithTimeout(ctx, capabilityDetectTimeout)
+			defer cancel()
+			cc := d.DetectCapabilities(detectCtx)
+			mu.Lock()
+			caps[c.ChainID()] = cc
+			mu.Unlock()
+
+			fields := []zap.Field{
+				zap.String("chain_id", c.ChainID()),
+				zap.Bool("websocket_events", cc.WebsocketEvents),
+				zap.Bool("tx_indexing", cc.TxIndexing),
+				zap.Int64("earliest_height", cc.EarliestHeight),
+				zap.Bool("fee_market", cc.FeeMarket),
+				zap.Uint64("max_tx_bytes", cc.MaxTxBytes),
+			}
+			for capability, err := range cc.Errors {
+				fields = append(fields, zap.String(capability+"_error", err))
+			}
+			log.Info("Detected chain capabilities", fields...)
+		}(c)
+	}
+	wg.Wait()
+	return caps
+}
+
+// applyCapabilities adapts the relaying modes of the paths to the capabilities of their chains:
+// paths set to the legacy processor on chains whose endpoint does not index transactions are relayed
+// with the events processor instead, and the initial block history is capped to the blocks the endpoints keep.
+// It must be called before the supervisor runs.
+func (s *supervisor) applyCapabilities(caps map[string]provider.Capabilities) {
+	s.capabilities = caps
+
+	for _, name := range s.names {
+		r := s.runners[name]
+		if r.processorType != ProcessorLegacy {
+			continue
+		}
+		if err := s.supportsLegacy(r); err != nil {
+			r.log.Warn(
+				"Relaying path with the events processor instead of the legacy processor",
+				zap.String("path", r.name),
+				zap.Error(err),
+			)
+			r.processorType = ProcessorEvents
+		}
+	}
+
+	for chainID, c := range caps {
+		if c.HistoryDepth() < 0 {
+			continue
+		}
+		if s.backfill.EarliestHeights == nil {
+			s.backfill.EarliestHeights = make(map[string]int64)
+		}
+		s.backfill.EarliestHeights[chainID] = c.EarliestHeight
+		if uint64(c.HistoryDepth()) < s.initialBlockHistory {
+			s.log.Warn(
+				"Endpoint does not keep the initial block history, querying its earliest blocks instead",
+				zap.String("chain_id", chainID),
+				zap.Uint64("initial_block_history", s.initialBlockHistory),
+				zap.Int64("earliest_height", c.EarliestHeight),
+				zap.Int64("history_depth", c.HistoryDepth()),
+			)
+		}
+	}
+}
+
+// supportsLegacy returns an error if a chain of r can't be relayed with the legacy processor,
+// which searches the transactions sending packets by their events.
+func (s *supervisor) supportsLegacy(r *pathRunner) error {
+	for _, c := range []*Chain{r.src, r.dst} {
+		caps, ok := s.capabilities[c.ChainID()]
+		if !ok || caps.TxIndexing {
+			continue
+		}
+		if _, failed := caps.Errors["tx_indexing"]; failed {
+			continue
+		}
+		return fmt.Errorf("the %s processor needs transaction indexing, which the endpoint of %s has disabled", ProcessorLegacy, c.ChainID())
+	}
+	return nil
+}
+
+// maxTxSizeOf returns the max size of the transactions of the legacy processor relaying r: the configured max tx size,
+// lowered to the size of the largest transaction either chain of r accepts.
+func (s *supervisor) maxTxSizeOf(r *pathRunner) uint64 {
+	size := s.maxTxSize
+	for _, c := range []*Chain{r.src, r.dst} {
+		if n := s.capabilities[c.ChainID()].MaxTxBytes; n > 0 && (size == 0 || n < size) {
+			size = n
+		}
+	}
+	return size
+}
+
+// capabilitySnapshot returns the detected capabilities of the chains, sorted by chain ID, for the status API.
+func (s *supervisor) capabilitySnapshot() []provider.Capabilities {
+	caps := make([]provider.Capabilities, 0, len(s.capabilities))
+	for _, c := range s.capabilities {
+		caps = append(caps, c)
+	}
+	sort.Slice(caps, func(i, j int) bool { return caps[i].ChainID < caps[j].ChainID })
+	return caps
+}
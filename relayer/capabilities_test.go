@@ -0,0 +1,52 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestApplyCapabilities(t *testing.T) {
+	chains := make(map[string]*Chain)
+	for _, chainID := range []string{"chain-a", "chain-b", "chain-c"} {
+		chains[chainID] = &Chain{Chainid: chainID, ChainProvider: &cosmos.CosmosProvider{PCfg: cosmos.CosmosProviderConfig{ChainID: chainID}}}
+	}
+	paths := []NamedPath{
+		{Name: "a-b", Path: &Path{Src: &PathEnd{ChainID: "chain-a"}, Dst: &PathEnd{ChainID: "chain-b"}}},
+		{Name: "a-c", Path: &Path{Src: &PathEnd{ChainID: "chain-a"}, Dst: &PathEnd{ChainID: "chain-c"}}},
+	}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorLegacy, 1000)
+	require.NoError(t, err)
+
+	s.applyCapabilities(map[string]provider.Capabilities{
+		"chain-a": {ChainID: "chain-a", TxIndexing: true, EarliestHeight: 1, LatestHeight: 5000},
+		"chain-b": {ChainID: "chain-b", TxIndexing: false, EarliestHeight: 4500, LatestHeight: 5000, MaxTxBytes: 200_000},
+		// undetected capabilities are assumed supported
+		"chain-c": {ChainID: "chain-c", Errors: map[string]string{"tx_indexing": "connection refused"}},
+	})
+
+	// paths on chains without transaction indexing are relayed with the events processor
+	require.Equal(t, ProcessorEvents, s.ProcessorType(s.runners["a-b"]))
+	require.Equal(t, ProcessorLegacy, s.ProcessorType(s.runners["a-c"]))
+	err = s.SetProcessorType(context.Background(), "a-b", ProcessorLegacy)
+	require.ErrorContains(t, err, "the endpoint of chain-b has disabled")
+
+	// and the block history is only queried from the earliest block kept by the endpoint
+	require.Equal(t, map[string]int64{"chain-b": 4500}, s.backfill.EarliestHeights)
+	start, _ := s.backfill.Start("chain-b", 5000, s.initialBlockHistory)
+	require.Equal(t, int64(4499), start)
+
+	// transactions are kept within the largest transaction either chain accepts
+	require.Equal(t, uint64(200_000), s.maxTxSizeOf(s.runners["a-b"]))
+	require.Zero(t, s.maxTxSizeOf(s.runners["a-c"]))
+	s.maxTxSize = 100_000
+	require.Equal(t, uint64(100_000), s.maxTxSizeOf(s.runners["a-b"]))
+
+	caps := s.capabilitySnapshot()
+	require.Len(t, caps, 3)
+	require.Equal(t, "chain-a", caps[0].ChainID)
+}
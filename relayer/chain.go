@@ -87,6 +87,20 @@ func NewChain(log *zap.Logger, prov provider.ChainProvider, debug bool) *Chain {
 	}
 }
 
+// withPathEnd returns a copy of the chain set to the given path end, sharing the same ChainProvider.
+func (c *Chain) withPathEnd(pe *PathEnd) *Chain {
+	chain := *c
+	chain.PathEnd = pe
+	return &chain
+}
+
+// withConnectionID returns a copy of the chain set to the connection connID of its client, sharing the same ChainProvider.
+func (c *Chain) withConnectionID(connID string) *Chain {
+	pe := *c.PathEnd
+	pe.ConnectionID = connID
+	return c.withPathEnd(&pe)
+}
+
 func (c *Chain) ChainID() string {
 	return c.ChainProvider.ChainId()
 }
@@ -10,6 +10,7 @@ import (
 	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
 	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
 	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/feed"
 	"github.com/cosmos/relayer/v2/relayer/processor"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
@@ -29,6 +30,10 @@ type CosmosChainProcessor struct {
 	// indicates whether queries are in sync with latest height of the chain
 	inSync bool
 
+	// endpointLost is set when the endpoint could not be queried, so that the chain it serves is verified again
+	// once it is reachable, in case it was replaced by an endpoint of another chain.
+	endpointLost bool
+
 	// highest block
 	latestBlock provider.LatestBlock
 
@@ -46,12 +51,33 @@ type CosmosChainProcessor struct {
 
 	// map of channel ID to connection ID
 	channelConnections map[string]string
+
+	// records packets and acknowledgements delivered by any relayer on relayed channels, if non-nil
+	relayerActivity *processor.RelayerActivity
+
+	// publishes the packet lifecycle on relayed channels, if non-nil
+	publisher *feed.Publisher
+
+	// accounts for the fees paid out to the relayer, if non-nil
+	metrics *processor.PrometheusMetrics
+
+	// bounds the backfill of the initial block history
+	backfill processor.Backfill
 }
 
-func NewCosmosChainProcessor(log *zap.Logger, provider *cosmos.CosmosProvider) *CosmosChainProcessor {
+func NewCosmosChainProcessor(
+	log *zap.Logger,
+	provider *cosmos.CosmosProvider,
+	relayerActivity *processor.RelayerActivity,
+	publisher *feed.Publisher,
+	metrics *processor.PrometheusMetrics,
+) *CosmosChainProcessor {
 	return &CosmosChainProcessor{
 		log:                  log.With(zap.String("chain_name", provider.ChainName()), zap.String("chain_id", provider.ChainId())),
 		chainProvider:        provider,
+		relayerActivity:      relayerActivity,
+		publisher:            publisher,
+		metrics:              metrics,
 		latestClientState:    make(latestClientState),
 		connectionStateCache: make(processor.ConnectionStateCache),
 		channelStateCache:    make(processor.ChannelStateCache),
@@ -95,6 +121,11 @@ func (ccp *CosmosChainProcessor) SetPathProcessors(pathProcessors processor.Path
 	ccp.pathProcessors = pathProcessors
 }
 
+// SetBackfill bounds the backfill of the initial block history of the chain.
+func (ccp *CosmosChainProcessor) SetBackfill(backfill processor.Backfill) {
+	ccp.backfill = backfill.WithDefaults()
+}
+
 // latestHeightWithRetry will query for the latest height, retrying in case of failure.
 // It will delay by latestHeightQueryRetryDelay between attempts, up to latestHeightQueryRetries.
 func (ccp *CosmosChainProcessor) latestHeightWithRetry(ctx context.Context) (latestHeight int64, err error) {
@@ -137,6 +168,7 @@ type queryCyclePersistence struct {
 	latestHeight         int64
 	latestQueriedBlock   int64
 	minQueryLoopDuration time.Duration
+	backfillProgress     *processor.BackfillProgress
 }
 
 // Run starts the query loop for the chain which will gather applicable ibc messages and push events out to the relevant PathProcessors.
@@ -166,14 +198,18 @@ func (ccp *CosmosChainProcessor) Run(ctx context.Context, initialBlockHistory ui
 		break
 	}
 
-	// this will make initial QueryLoop iteration look back initialBlockHistory blocks in history
-	latestQueriedBlock := persistence.latestHeight - int64(initialBlockHistory)
-
-	if latestQueriedBlock < 0 {
-		latestQueriedBlock = 0
+	// this will make initial QueryLoop iteration look back initialBlockHistory blocks in history,
+	// unless an interrupted backfill is resumed from its checkpoint
+	latestQueriedBlock, resumed := ccp.backfill.Start(ccp.chainProvider.ChainId(), persistence.latestHeight, initialBlockHistory)
+	if resumed {
+		ccp.log.Info("Resuming block history backfill from checkpoint",
+			zap.Int64("latest_queried_block", latestQueriedBlock),
+			zap.Int64("latest_height", persistence.latestHeight),
+		)
 	}
 
 	persistence.latestQueriedBlock = latestQueriedBlock
+	persistence.backfillProgress = processor.NewBackfillProgress(ccp.chainProvider.ChainId(), latestQueriedBlock, persistence.latestHeight, ccp.backfill.Progress)
 
 	var eg errgroup.Group
 	eg.Go(func() error {
@@ -262,9 +298,21 @@ func (ccp *CosmosChainProcessor) queryCycle(ctx context.Context, persistence *qu
 			zap.Uint("attempts", latestHeightQueryRetries),
 			zap.Error(err),
 		)
+		ccp.endpointLost = true
 		return nil
 	}
 
+	if ccp.endpointLost {
+		if err := ccp.chainProvider.VerifyChainID(ctx); err != nil {
+			if errors.Is(err, provider.ErrChainIDMismatch) {
+				return err
+			}
+			ccp.log.Warn("Failed to verify the chain served by the endpoint", zap.Error(err))
+			return nil
+		}
+		ccp.endpointLost = false
+	}
+
 	ccp.log.Debug("Queried latest height",
 		zap.Int64("latest_height", persistence.latestHeight),
 	)
@@ -276,12 +324,10 @@ func (ccp *CosmosChainProcessor) queryCycle(ctx context.Context, persistence *qu
 		if (persistence.latestHeight - persistence.latestQueriedBlock) < inSyncNumBlocksThreshold {
 			ccp.inSync = true
 			firstTimeInSync = true
+			persistence.backfillProgress.InSync(persistence.latestQueriedBlock, persistence.latestHeight)
 			ccp.log.Info("Chain is in sync")
 		} else {
-			ccp.log.Info("Chain is not yet in sync",
-				zap.Int64("latest_queried_block", persistence.latestQueriedBlock),
-				zap.Int64("latest_height", persistence.latestHeight),
-			)
+			persistence.backfillProgress.Log(ccp.log, persistence.latestQueriedBlock, persistence.latestHeight)
 		}
 	}
 
@@ -295,30 +341,21 @@ func (ccp *CosmosChainProcessor) queryCycle(ctx context.Context, persistence *qu
 
 	newLatestQueriedBlock := persistence.latestQueriedBlock
 
-	for i := persistence.latestQueriedBlock + 1; i <= persistence.latestHeight; i++ {
-		var eg errgroup.Group
-		var blockRes *ctypes.ResultBlockResults
-		var ibcHeader provider.IBCHeader
-		i := i
-		eg.Go(func() (err error) {
-			queryCtx, cancelQueryCtx := context.WithTimeout(ctx, blockResultsQueryTimeout)
-			defer cancelQueryCtx()
-			blockRes, err = ccp.chainProvider.RPCClient.BlockResults(queryCtx, &i)
-			return err
-		})
-		eg.Go(func() (err error) {
-			queryCtx, cancelQueryCtx := context.WithTimeout(ctx, queryTimeout)
-			defer cancelQueryCtx()
-			ibcHeader, err = ccp.chainProvider.IBCHeaderAtHeight(queryCtx, i)
-			return err
-		})
+	// while backfilling, blocks are handed over to the path processors a chunk at a time
+	to := persistence.latestHeight
+	if !ccp.inSync {
+		to = ccp.backfill.ChunkEnd(persistence.latestQueriedBlock, persistence.latestHeight)
+	}
 
-		if err := eg.Wait(); err != nil {
-			ccp.log.Warn("Error querying block data", zap.Error(err))
-			break
-		}
+	blocks, err := ccp.queryBlocks(ctx, persistence.latestQueriedBlock+1, to)
+	if err != nil {
+		ccp.log.Warn("Error querying block data", zap.Error(err))
+	}
 
-		latestHeader = ibcHeader.(cosmos.CosmosIBCHeader)
+	for _, block := range blocks {
+		i := block.height
+		blockRes := block.res
+		latestHeader = block.header
 
 		heightUint64 := uint64(i)
 
@@ -336,10 +373,14 @@ func (ccp *CosmosChainProcessor) queryCycle(ctx context.Context, persistence *qu
 				continue
 			}
 			messages := ccp.ibcMessagesFromTransaction(tx, heightUint64)
+			signer := txSigner(tx)
 
 			for _, m := range messages {
 				ccp.handleMessage(m, ibcMessagesCache)
+				ccp.recordRelayerActivity(m, signer)
+				ccp.publishPacketEvent(m, signer)
 			}
+			ccp.handleFeeEvents(tx, heightUint64, signer)
 		}
 		newLatestQueriedBlock = i
 	}
@@ -385,5 +426,65 @@ func (ccp *CosmosChainProcessor) queryCycle(ctx context.Context, persistence *qu
 
 	persistence.latestQueriedBlock = newLatestQueriedBlock
 
+	ccp.backfill.Checkpoint(ccp.log, chainID, newLatestQueriedBlock, ccp.inSync)
+
 	return nil
 }
+
+// queriedBlock holds the results and the IBC header of a block.
+type queriedBlock struct {
+	height int64
+	res    *ctypes.ResultBlockResults
+	header cosmos.CosmosIBCHeader
+}
+
+// queryBlocks queries the blocks from height from to height to, at most backfill.MaxInFlight of them at once.
+// It returns the blocks in height order, up to the first block which failed to be queried along with its error.
+func (ccp *CosmosChainProcessor) queryBlocks(ctx context.Context, from, to int64) ([]queriedBlock, error) {
+	if to < from {
+		return nil, nil
+	}
+	blocks := make([]queriedBlock, to-from+1)
+	errs := make([]error, len(blocks))
+
+	var eg errgroup.Group
+	eg.SetLimit(ccp.backfill.WithDefaults().MaxInFlight)
+	for i := range blocks {
+		i := i
+		eg.Go(func() error {
+			blocks[i], errs[i] = ccp.queryBlock(ctx, from+int64(i))
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return blocks[:i], err
+		}
+	}
+	return blocks, nil
+}
+
+// queryBlock queries the results and the IBC header of the block at height.
+func (ccp *CosmosChainProcessor) queryBlock(ctx context.Context, height int64) (queriedBlock, error) {
+	var eg errgroup.Group
+	var blockRes *ctypes.ResultBlockResults
+	var ibcHeader provider.IBCHeader
+	eg.Go(func() (err error) {
+		queryCtx, cancelQueryCtx := context.WithTimeout(ctx, blockResultsQueryTimeout)
+		defer cancelQueryCtx()
+		blockRes, err = ccp.chainProvider.RPCClient.BlockResults(queryCtx, &height)
+		return err
+	})
+	eg.Go(func() (err error) {
+		queryCtx, cancelQueryCtx := context.WithTimeout(ctx, queryTimeout)
+		defer cancelQueryCtx()
+		ibcHeader, err = ccp.chainProvider.IBCHeaderAtHeight(queryCtx, height)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return queriedBlock{}, err
+	}
+	return queriedBlock{height: height, res: blockRes, header: ibcHeader.(cosmos.CosmosIBCHeader)}, nil
+}
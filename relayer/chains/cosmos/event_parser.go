@@ -36,6 +36,22 @@ func (ccp *CosmosChainProcessor) ibcMessagesFromTransaction(tx *abci.ResponseDel
 	return parseABCILogs(ccp.log, parsedLogs, height)
 }
 
+// txSigner returns the address that paid the fees of a transaction, which is the relayer who submitted it,
+// or an empty string if the transaction events do not include it.
+func txSigner(tx *abci.ResponseDeliverTx) string {
+	for _, event := range tx.Events {
+		if event.Type != sdk.EventTypeTx {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if string(attr.Key) == sdk.AttributeKeyFeePayer {
+				return string(attr.Value)
+			}
+		}
+	}
+	return ""
+}
+
 func parseABCILogs(log *zap.Logger, logs sdk.ABCIMessageLogs, height uint64) (messages []ibcMessage) {
 	for _, messageLog := range logs {
 		var info ibcMessageInfo
@@ -302,6 +318,8 @@ func (res *channelInfo) parseChannelAttribute(attr sdk.Attribute) {
 		res.CounterpartyChannelID = attr.Value
 	case chantypes.AttributeKeyConnectionID:
 		res.ConnID = attr.Value
+	case chantypes.AttributeVersion:
+		res.Version = attr.Value
 	}
 }
 
@@ -334,3 +352,90 @@ func (res *connectionInfo) parseConnectionAttribute(attr sdk.Attribute) {
 		res.CounterpartyClientID = attr.Value
 	}
 }
+
+// ICS-29 fee middleware events. The ibc-go version the relayer is built against does not ship the fee middleware,
+// so its event types and attribute keys are declared here.
+const (
+	eventTypeIncentivizedPacket = "incentivized_ibc_packet"
+	eventTypeDistributeFee      = "distribute_fee"
+
+	attributeKeyPortID         = "port_id"
+	attributeKeyChannelID      = "channel_id"
+	attributeKeyPacketSequence = "packet_sequence"
+	attributeKeyRecvFee        = "recv_fee"
+	attributeKeyAckFee         = "ack_fee"
+	attributeKeyTimeoutFee     = "timeout_fee"
+	attributeKeyReceiver       = "receiver"
+	attributeKeyFee            = "fee"
+)
+
+// packetFee is the total of the fees escrowed for relaying a packet, emitted whenever a fee is paid for it.
+type packetFee struct {
+	portID     string
+	channelID  string
+	sequence   uint64
+	recvFee    sdk.Coins
+	ackFee     sdk.Coins
+	timeoutFee sdk.Coins
+}
+
+// feeDistribution is a fee paid out to a relayer, once a packet is acknowledged or timed out.
+type feeDistribution struct {
+	receiver string
+	fee      sdk.Coins
+}
+
+// feeEventsFromTransaction parses the fees paid for packets, and the fees paid out to relayers, within a transaction.
+func feeEventsFromTransaction(log *zap.Logger, tx *abci.ResponseDeliverTx) (fees []packetFee, distributions []feeDistribution) {
+	for _, event := range tx.Events {
+		switch event.Type {
+		case eventTypeIncentivizedPacket:
+			var f packetFee
+			for _, attr := range event.Attributes {
+				var err error
+				switch string(attr.Key) {
+				case attributeKeyPortID:
+					f.portID = string(attr.Value)
+				case attributeKeyChannelID:
+					f.channelID = string(attr.Value)
+				case attributeKeyPacketSequence:
+					f.sequence, err = strconv.ParseUint(string(attr.Value), 10, 64)
+				case attributeKeyRecvFee:
+					f.recvFee, err = sdk.ParseCoinsNormalized(string(attr.Value))
+				case attributeKeyAckFee:
+					f.ackFee, err = sdk.ParseCoinsNormalized(string(attr.Value))
+				case attributeKeyTimeoutFee:
+					f.timeoutFee, err = sdk.ParseCoinsNormalized(string(attr.Value))
+				}
+				if err != nil {
+					log.Error("Error parsing packet fee",
+						zap.String("key", string(attr.Key)),
+						zap.String("value", string(attr.Value)),
+						zap.Error(err),
+					)
+				}
+			}
+			fees = append(fees, f)
+		case eventTypeDistributeFee:
+			var d feeDistribution
+			for _, attr := range event.Attributes {
+				switch string(attr.Key) {
+				case attributeKeyReceiver:
+					d.receiver = string(attr.Value)
+				case attributeKeyFee:
+					fee, err := sdk.ParseCoinsNormalized(string(attr.Value))
+					if err != nil {
+						log.Error("Error parsing distributed fee",
+							zap.String("value", string(attr.Value)),
+							zap.Error(err),
+						)
+						continue
+					}
+					d.fee = fee
+				}
+			}
+			distributions = append(distributions, d)
+		}
+	}
+	return fees, distributions
+}
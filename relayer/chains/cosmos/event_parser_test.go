@@ -11,6 +11,7 @@ import (
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"go.uber.org/zap"
 )
 
@@ -127,6 +128,7 @@ func TestParseChannel(t *testing.T) {
 		testPortID1       = "test-port-id-1"
 		testChannelID2    = "test-channel-id-2"
 		testPortID2       = "test-port-id-2"
+		testVersion       = `{"fee_version":"ics29-1","app_version":"ics20-1"}`
 	)
 
 	channelEventAttributes := []sdk.Attribute{
@@ -150,6 +152,10 @@ func TestParseChannel(t *testing.T) {
 			Key:   chantypes.AttributeCounterpartyPortID,
 			Value: testPortID2,
 		},
+		{
+			Key:   chantypes.AttributeVersion,
+			Value: testVersion,
+		},
 	}
 
 	parsed := new(channelInfo)
@@ -161,6 +167,7 @@ func TestParseChannel(t *testing.T) {
 		PortID:                testPortID1,
 		CounterpartyChannelID: testChannelID2,
 		CounterpartyPortID:    testPortID2,
+		Version:               testVersion,
 	}), "parsed channel info does not match expected")
 }
 
@@ -333,3 +340,67 @@ func TestParseEventLogs(t *testing.T) {
 		Ack:              packetAck,
 	}), "parsed packet info does not match expected")
 }
+
+func TestTxSigner(t *testing.T) {
+	tx := &abci.ResponseDeliverTx{
+		Events: []abci.Event{
+			{
+				Type: "coin_spent",
+				Attributes: []abci.EventAttribute{
+					{Key: []byte("spender"), Value: []byte("cosmos1spender")},
+				},
+			},
+			{
+				Type: sdk.EventTypeTx,
+				Attributes: []abci.EventAttribute{
+					{Key: []byte(sdk.AttributeKeyFee), Value: []byte("100stake")},
+					{Key: []byte(sdk.AttributeKeyFeePayer), Value: []byte("cosmos1relayer")},
+				},
+			},
+		},
+	}
+	require.Equal(t, "cosmos1relayer", txSigner(tx))
+	require.Empty(t, txSigner(&abci.ResponseDeliverTx{}))
+}
+
+func TestFeeEventsFromTransaction(t *testing.T) {
+	attr := func(k, v string) abci.EventAttribute {
+		return abci.EventAttribute{Key: []byte(k), Value: []byte(v)}
+	}
+	tx := &abci.ResponseDeliverTx{
+		Events: []abci.Event{
+			{
+				Type: eventTypeIncentivizedPacket,
+				Attributes: []abci.EventAttribute{
+					attr(attributeKeyPortID, "transfer"),
+					attr(attributeKeyChannelID, "channel-0"),
+					attr(attributeKeyPacketSequence, "7"),
+					attr(attributeKeyRecvFee, "100uatom"),
+					attr(attributeKeyAckFee, "50uatom"),
+					attr(attributeKeyTimeoutFee, ""),
+				},
+			},
+			{
+				Type: eventTypeDistributeFee,
+				Attributes: []abci.EventAttribute{
+					attr(attributeKeyReceiver, "cosmos1relayer"),
+					attr(attributeKeyFee, "50uatom,10uosmo"),
+				},
+			},
+			{Type: chantypes.EventTypeAcknowledgePacket},
+		},
+	}
+
+	fees, distributions := feeEventsFromTransaction(zap.NewNop(), tx)
+	require.Len(t, fees, 1)
+	require.Equal(t, "transfer", fees[0].portID)
+	require.Equal(t, "channel-0", fees[0].channelID)
+	require.Equal(t, uint64(7), fees[0].sequence)
+	require.Equal(t, "100uatom", fees[0].recvFee.String())
+	require.Equal(t, "50uatom", fees[0].ackFee.String())
+	require.True(t, fees[0].timeoutFee.IsZero())
+
+	require.Len(t, distributions, 1)
+	require.Equal(t, "cosmos1relayer", distributions[0].receiver)
+	require.Equal(t, "50uatom,10uosmo", distributions[0].fee.String())
+}
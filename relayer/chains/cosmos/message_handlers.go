@@ -3,8 +3,10 @@ package cosmos
 import (
 	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
 	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/feed"
 	"github.com/cosmos/relayer/v2/relayer/processor"
 	"github.com/cosmos/relayer/v2/relayer/provider"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -22,6 +24,62 @@ func (ccp *CosmosChainProcessor) handleMessage(m ibcMessage, c processor.IBCMess
 	}
 }
 
+// recordRelayerActivity accounts for recv and ack messages on relayed channels, including those submitted by other relayers.
+func (ccp *CosmosChainProcessor) recordRelayerActivity(m ibcMessage, signer string) {
+	if ccp.relayerActivity == nil {
+		return
+	}
+	if m.eventType != chantypes.EventTypeRecvPacket && m.eventType != chantypes.EventTypeAcknowledgePacket {
+		return
+	}
+	t, ok := m.info.(*packetInfo)
+	if !ok {
+		return
+	}
+	chainID := ccp.chainProvider.ChainId()
+	k, err := processor.PacketInfoChannelKey(m.eventType, provider.PacketInfo(*t))
+	if err != nil || !ccp.pathProcessors.IsRelayedChannel(k, chainID) {
+		return
+	}
+	ccp.relayerActivity.Record(chainID, k.ChannelID, k.PortID, m.eventType, signer)
+}
+
+// feedEventTypes maps the packet events published to the feed to their feed event type.
+var feedEventTypes = map[string]string{
+	chantypes.EventTypeSendPacket:           feed.EventPacketSent,
+	chantypes.EventTypeRecvPacket:           feed.EventPacketReceived,
+	chantypes.EventTypeAcknowledgePacket:    feed.EventPacketAcknowledged,
+	chantypes.EventTypeTimeoutPacket:        feed.EventPacketTimedOut,
+	chantypes.EventTypeTimeoutPacketOnClose: feed.EventPacketTimedOut,
+}
+
+// publishPacketEvent publishes the packet lifecycle events of relayed channels to the feed.
+func (ccp *CosmosChainProcessor) publishPacketEvent(m ibcMessage, signer string) {
+	if ccp.publisher == nil {
+		return
+	}
+	eventType, ok := feedEventTypes[m.eventType]
+	if !ok {
+		return
+	}
+	t, ok := m.info.(*packetInfo)
+	if !ok {
+		return
+	}
+	chainID := ccp.chainProvider.ChainId()
+	k, err := processor.PacketInfoChannelKey(m.eventType, provider.PacketInfo(*t))
+	if err != nil || !ccp.pathProcessors.IsRelayedChannel(k, chainID) {
+		return
+	}
+	ccp.publisher.Publish(feed.Event{
+		Type:    eventType,
+		ChainID: chainID,
+		Height:  t.Height,
+		Signer:  signer,
+		Packet:  feed.NewPacket(provider.PacketInfo(*t)),
+	})
+}
+
 func (ccp *CosmosChainProcessor) handlePacketMessage(eventType string, pi provider.PacketInfo, c processor.IBCMessagesCache) {
 	k, err := processor.PacketInfoChannelKey(eventType, pi)
 	if err != nil {
@@ -134,3 +192,74 @@ func (ccp *CosmosChainProcessor) logConnectionMessage(message string, ci provide
 		zap.String("counterparty_connection_id", ci.CounterpartyConnID),
 	)
 }
+
+// handleFeeEvents publishes the ICS-29 fees paid for packets sent on relayed channels,
+// and accounts for the fees paid out to the relayer.
+func (ccp *CosmosChainProcessor) handleFeeEvents(tx *abci.ResponseDeliverTx, height uint64, signer string) {
+	if ccp.publisher == nil && ccp.metrics == nil {
+		return
+	}
+	fees, distributions := feeEventsFromTransaction(ccp.log, tx)
+	chainID := ccp.chainProvider.ChainId()
+
+	for _, f := range fees {
+		if !ccp.isRelayedChannelEnd(f.channelID, f.portID) {
+			continue
+		}
+		ccp.publisher.Publish(feed.Event{
+			Type:    feed.EventPacketFeePaid,
+			ChainID: chainID,
+			Height:  height,
+			Signer:  signer,
+			Packet: &feed.Packet{
+				Sequence:      f.sequence,
+				SourceChannel: f.channelID,
+				SourcePort:    f.portID,
+			},
+			Fee: &feed.Fee{
+				RecvFee:    f.recvFee.String(),
+				AckFee:     f.ackFee.String(),
+				TimeoutFee: f.timeoutFee.String(),
+			},
+		})
+	}
+
+	if len(distributions) == 0 {
+		return
+	}
+	addr, err := ccp.chainProvider.Address()
+	if err != nil {
+		return
+	}
+	for _, d := range distributions {
+		if d.receiver != addr {
+			continue
+		}
+		for _, coin := range d.fee {
+			amount, err := coin.Amount.ToDec().Float64()
+			if err != nil {
+				continue
+			}
+			ccp.metrics.AddFeesEarned(chainID, coin.Denom, amount)
+		}
+		ccp.publisher.Publish(feed.Event{
+			Type:    feed.EventFeeDistributed,
+			ChainID: chainID,
+			Height:  height,
+			Fee: &feed.Fee{
+				Receiver: d.receiver,
+				Amount:   d.fee.String(),
+			},
+		})
+	}
+}
+
+// isRelayedChannelEnd returns true if the channel end on this chain is known and relayed by a path.
+func (ccp *CosmosChainProcessor) isRelayedChannelEnd(channelID, portID string) bool {
+	for k := range ccp.channelStateCache {
+		if k.ChannelID == channelID && k.PortID == portID {
+			return ccp.pathProcessors.IsRelayedChannel(k, ccp.chainProvider.ChainId())
+		}
+	}
+	return false
+}
@@ -0,0 +1,495 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/cosmos/relayer/v2/relayer/provider/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// EVMChainProcessor is the ChainProcessor of IBC-enabled EVM chains, observing the events of their IBC handler
+// contract through the logs queried from the Ethereum JSON-RPC.
+type EVMChainProcessor struct {
+	log *zap.Logger
+
+	chainProvider *evm.EVMProvider
+
+	pathProcessors processor.PathProcessors
+
+	// indicates whether queries are in sync with latest height of the chain
+	inSync bool
+
+	// endpointLost is set when the endpoint could not be queried, so that the chain it serves is verified again
+	// once it is reachable, in case it was replaced by an endpoint of another chain.
+	endpointLost bool
+
+	// highest block
+	latestBlock provider.LatestBlock
+
+	// holds highest consensus height for all clients
+	latestClientState
+
+	// holds open state for known connections
+	connectionStateCache processor.ConnectionStateCache
+
+	// holds open state for known channels
+	channelStateCache processor.ChannelStateCache
+
+	// map of connection ID to client ID
+	connectionClients map[string]string
+
+	// map of channel ID to connection ID
+	channelConnections map[string]string
+
+	// records packets and acknowledgements delivered by any relayer on relayed channels, if non-nil
+	relayerActivity *processor.RelayerActivity
+
+	// publishes the packet lifecycle on relayed channels, if non-nil
+	publisher *feed.Publisher
+
+	// unused until the IBC handler supports ICS-29 fees, kept for parity with the other chain processors
+	metrics *processor.PrometheusMetrics
+
+	// bounds the backfill of the initial block history
+	backfill processor.Backfill
+}
+
+func NewEVMChainProcessor(
+	log *zap.Logger,
+	provider *evm.EVMProvider,
+	relayerActivity *processor.RelayerActivity,
+	publisher *feed.Publisher,
+	metrics *processor.PrometheusMetrics,
+) *EVMChainProcessor {
+	return &EVMChainProcessor{
+		log:                  log.With(zap.String("chain_name", provider.ChainName()), zap.String("chain_id", provider.ChainId())),
+		chainProvider:        provider,
+		relayerActivity:      relayerActivity,
+		publisher:            publisher,
+		metrics:              metrics,
+		latestClientState:    make(latestClientState),
+		connectionStateCache: make(processor.ConnectionStateCache),
+		channelStateCache:    make(processor.ChannelStateCache),
+		connectionClients:    make(map[string]string),
+		channelConnections:   make(map[string]string),
+	}
+}
+
+const (
+	queryTimeout                = 5 * time.Second
+	logsQueryTimeout            = 2 * time.Minute
+	latestHeightQueryRetryDelay = 1 * time.Second
+	latestHeightQueryRetries    = 5
+
+	defaultMinQueryLoopDuration = 1 * time.Second
+	inSyncNumBlocksThreshold    = 2
+
+	// maxBlocksPerCycle bounds the block range of the logs queried in a cycle,
+	// as most endpoints limit the range of eth_getLogs.
+	maxBlocksPerCycle = 100
+)
+
+// latestClientState is a map of clientID to the latest client state for that client.
+type latestClientState map[string]provider.ClientState
+
+func (l latestClientState) update(cs provider.ClientState) {
+	existing, ok := l[cs.ClientID]
+	if ok && cs.ConsensusHeight.LT(existing.ConsensusHeight) {
+		// height is less than latest, so no-op
+		return
+	}
+
+	// update latest if no existing state or provided consensus height is newer
+	l[cs.ClientID] = cs
+}
+
+// Provider returns the ChainProvider, which provides the methods for querying, assembling IBC messages, and sending transactions.
+func (ecp *EVMChainProcessor) Provider() provider.ChainProvider {
+	return ecp.chainProvider
+}
+
+// Set the PathProcessors that this ChainProcessor should publish relevant IBC events to.
+// ChainProcessors need reference to their PathProcessors and vice-versa, handled by EventProcessorBuilder.Build().
+func (ecp *EVMChainProcessor) SetPathProcessors(pathProcessors processor.PathProcessors) {
+	ecp.pathProcessors = pathProcessors
+}
+
+// SetBackfill bounds the backfill of the initial block history of the chain.
+func (ecp *EVMChainProcessor) SetBackfill(backfill processor.Backfill) {
+	ecp.backfill = backfill.WithDefaults()
+}
+
+// latestHeightWithRetry will query for the latest height, retrying in case of failure.
+// It will delay by latestHeightQueryRetryDelay between attempts, up to latestHeightQueryRetries.
+func (ecp *EVMChainProcessor) latestHeightWithRetry(ctx context.Context) (latestHeight int64, err error) {
+	return latestHeight, retry.Do(func() error {
+		latestHeightQueryCtx, cancelLatestHeightQueryCtx := context.WithTimeout(ctx, queryTimeout)
+		defer cancelLatestHeightQueryCtx()
+		var err error
+		latestHeight, err = ecp.chainProvider.QueryLatestHeight(latestHeightQueryCtx)
+		return err
+	}, retry.Context(ctx), retry.Attempts(latestHeightQueryRetries), retry.Delay(latestHeightQueryRetryDelay), retry.LastErrorOnly(true), retry.OnRetry(func(n uint, err error) {
+		ecp.log.Info(
+			"Failed to query latest height",
+			zap.Uint("attempt", n+1),
+			zap.Uint("max_attempts", latestHeightQueryRetries),
+			zap.Error(err),
+		)
+	}))
+}
+
+// clientState will return the most recent client state if client messages
+// have already been observed for the clientID, otherwise it will query for it.
+func (ecp *EVMChainProcessor) clientState(ctx context.Context, clientID string) (provider.ClientState, error) {
+	if state, ok := ecp.latestClientState[clientID]; ok {
+		return state, nil
+	}
+	cs, err := ecp.chainProvider.QueryClientState(ctx, int64(ecp.latestBlock.Height), clientID)
+	if err != nil {
+		return provider.ClientState{}, err
+	}
+	clientState := provider.ClientState{
+		ClientID:        clientID,
+		ConsensusHeight: cs.GetLatestHeight().(clienttypes.Height),
+	}
+	ecp.latestClientState[clientID] = clientState
+	return clientState, nil
+}
+
+// queryCyclePersistence hold the variables that should be retained across queryCycles.
+type queryCyclePersistence struct {
+	latestHeight         int64
+	latestQueriedBlock   int64
+	minQueryLoopDuration time.Duration
+	backfillProgress     *processor.BackfillProgress
+}
+
+// Run starts the query loop for the chain which will gather applicable ibc messages and push events out to the relevant PathProcessors.
+// The initialBlockHistory parameter determines how many historical blocks should be fetched and processed before continuing with current blocks.
+// ChainProcessors should obey the context and return upon context cancellation.
+func (ecp *EVMChainProcessor) Run(ctx context.Context, initialBlockHistory uint64) error {
+	// this will be used for persistence across query cycle loop executions
+	persistence := queryCyclePersistence{
+		minQueryLoopDuration: defaultMinQueryLoopDuration,
+	}
+
+	// Infinite retry to get initial latest height
+	for {
+		latestHeight, err := ecp.latestHeightWithRetry(ctx)
+		if err != nil {
+			ecp.log.Error(
+				"Failed to query latest height after max attempts",
+				zap.Uint("attempts", latestHeightQueryRetries),
+				zap.Error(err),
+			)
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			continue
+		}
+		persistence.latestHeight = latestHeight
+		break
+	}
+
+	// this will make initial QueryLoop iteration look back initialBlockHistory blocks in history,
+	// unless an interrupted backfill is resumed from its checkpoint
+	latestQueriedBlock, resumed := ecp.backfill.Start(ecp.chainProvider.ChainId(), persistence.latestHeight, initialBlockHistory)
+	if resumed {
+		ecp.log.Info("Resuming block history backfill from checkpoint",
+			zap.Int64("latest_queried_block", latestQueriedBlock),
+			zap.Int64("latest_height", persistence.latestHeight),
+		)
+	}
+
+	persistence.latestQueriedBlock = latestQueriedBlock
+	persistence.backfillProgress = processor.NewBackfillProgress(ecp.chainProvider.ChainId(), latestQueriedBlock, persistence.latestHeight, ecp.backfill.Progress)
+
+	var eg errgroup.Group
+	eg.Go(func() error {
+		return ecp.initializeConnectionState(ctx)
+	})
+	eg.Go(func() error {
+		return ecp.initializeChannelState(ctx)
+	})
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	ecp.log.Debug("Entering main query loop")
+
+	ticker := time.NewTicker(persistence.minQueryLoopDuration)
+
+	for {
+		if err := ecp.queryCycle(ctx, &persistence); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			ticker.Reset(persistence.minQueryLoopDuration)
+		}
+	}
+}
+
+// initializeConnectionState will bootstrap the connectionStateCache with the open connection state.
+func (ecp *EVMChainProcessor) initializeConnectionState(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	connections, err := ecp.chainProvider.QueryConnections(ctx)
+	if err != nil {
+		return fmt.Errorf("error querying connections: %w", err)
+	}
+	for _, c := range connections {
+		ecp.connectionClients[c.Id] = c.ClientId
+		ecp.connectionStateCache[processor.ConnectionKey{
+			ConnectionID:         c.Id,
+			ClientID:             c.ClientId,
+			CounterpartyConnID:   c.Counterparty.ConnectionId,
+			CounterpartyClientID: c.Counterparty.ClientId,
+		}] = c.State == conntypes.OPEN
+	}
+	return nil
+}
+
+// initializeChannelState will bootstrap the channelStateCache with the open channel state.
+func (ecp *EVMChainProcessor) initializeChannelState(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	channels, err := ecp.chainProvider.QueryChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("error querying channels: %w", err)
+	}
+	for _, ch := range channels {
+		if len(ch.ConnectionHops) != 1 {
+			ecp.log.Error("Found channel using multiple connection hops. Not currently supported, ignoring.",
+				zap.String("channel_id", ch.ChannelId),
+				zap.String("port_id", ch.PortId),
+				zap.Strings("connection_hops", ch.ConnectionHops),
+			)
+			continue
+		}
+		ecp.channelConnections[ch.ChannelId] = ch.ConnectionHops[0]
+		ecp.channelStateCache[processor.ChannelKey{
+			ChannelID:             ch.ChannelId,
+			PortID:                ch.PortId,
+			CounterpartyChannelID: ch.Counterparty.ChannelId,
+			CounterpartyPortID:    ch.Counterparty.PortId,
+		}] = ch.State == chantypes.OPEN
+	}
+	return nil
+}
+
+func (ecp *EVMChainProcessor) queryCycle(ctx context.Context, persistence *queryCyclePersistence) error {
+	var err error
+	persistence.latestHeight, err = ecp.latestHeightWithRetry(ctx)
+
+	// don't want to cause EVMChainProcessor to quit here, can retry again next cycle.
+	if err != nil {
+		ecp.log.Error(
+			"Failed to query latest height after max attempts",
+			zap.Uint("attempts", latestHeightQueryRetries),
+			zap.Error(err),
+		)
+		ecp.endpointLost = true
+		return nil
+	}
+
+	if ecp.endpointLost {
+		if err := ecp.chainProvider.VerifyChainID(ctx); err != nil {
+			if errors.Is(err, provider.ErrChainIDMismatch) {
+				return err
+			}
+			ecp.log.Warn("Failed to verify the chain served by the endpoint", zap.Error(err))
+			return nil
+		}
+		ecp.endpointLost = false
+	}
+
+	ecp.log.Debug("Queried latest height",
+		zap.Int64("latest_height", persistence.latestHeight),
+	)
+
+	// used at the end of the cycle to send signal to path processors to start processing if both chains are in sync and no new messages came in this cycle
+	firstTimeInSync := false
+
+	if !ecp.inSync {
+		if (persistence.latestHeight - persistence.latestQueriedBlock) < inSyncNumBlocksThreshold {
+			ecp.inSync = true
+			firstTimeInSync = true
+			persistence.backfillProgress.InSync(persistence.latestQueriedBlock, persistence.latestHeight)
+			ecp.log.Info("Chain is in sync")
+		} else {
+			persistence.backfillProgress.Log(ecp.log, persistence.latestQueriedBlock, persistence.latestHeight)
+		}
+	}
+
+	if persistence.latestHeight <= persistence.latestQueriedBlock {
+		if firstTimeInSync {
+			for _, pp := range ecp.pathProcessors {
+				pp.ProcessBacklogIfReady()
+			}
+		}
+		return nil
+	}
+
+	from := persistence.latestQueriedBlock + 1
+	to := persistence.latestHeight
+	if !ecp.inSync {
+		to = ecp.backfill.ChunkEnd(persistence.latestQueriedBlock, persistence.latestHeight)
+	}
+	if to-from >= maxBlocksPerCycle {
+		to = from + maxBlocksPerCycle - 1
+	}
+
+	logsQueryCtx, cancelLogsQueryCtx := context.WithTimeout(ctx, logsQueryTimeout)
+	events, err := ecp.chainProvider.QueryIBCEvents(logsQueryCtx, uint64(from), uint64(to))
+	cancelLogsQueryCtx()
+	if err != nil {
+		ecp.log.Warn("Error querying IBC events",
+			zap.Int64("from_height", from),
+			zap.Int64("to_height", to),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	ibcMessagesCache := processor.NewIBCMessagesCache()
+
+	ibcHeaderCache := make(processor.IBCHeaderCache)
+
+	var latestHeader evm.EVMIBCHeader
+
+	newLatestQueriedBlock := persistence.latestQueriedBlock
+
+	signers := make(map[common.Hash]string)
+
+	headers, err := ecp.queryHeaders(ctx, from, to)
+	if err != nil {
+		ecp.log.Warn("Error querying block data", zap.Error(err))
+	}
+
+	for j, header := range headers {
+		i := from + int64(j)
+		latestHeader = header
+
+		heightUint64 := uint64(i)
+
+		ecp.latestBlock = provider.LatestBlock{
+			Height: heightUint64,
+			Time:   latestHeader.Time(),
+		}
+
+		ibcHeaderCache[heightUint64] = latestHeader
+
+		for len(events) > 0 && events[0].Height == heightUint64 {
+			e := events[0]
+			events = events[1:]
+			signer := ecp.txSigner(ctx, e, signers)
+			ecp.handleEvent(e, ibcMessagesCache)
+			ecp.recordRelayerActivity(e, signer)
+			ecp.publishPacketEvent(e, signer)
+		}
+		newLatestQueriedBlock = i
+	}
+
+	if newLatestQueriedBlock == persistence.latestQueriedBlock {
+		return nil
+	}
+
+	chainID := ecp.chainProvider.ChainId()
+
+	for _, pp := range ecp.pathProcessors {
+		clientID := pp.RelevantClientID(chainID)
+		clientState, err := ecp.clientState(ctx, clientID)
+		if err != nil {
+			ecp.log.Error("Error fetching client state",
+				zap.String("client_id", clientID),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		pp.HandleNewData(chainID, processor.ChainProcessorCacheData{
+			LatestBlock:          ecp.latestBlock,
+			LatestHeader:         latestHeader,
+			IBCMessagesCache:     ibcMessagesCache,
+			InSync:               ecp.inSync,
+			ClientState:          clientState,
+			ConnectionStateCache: ecp.connectionStateCache.FilterForClient(clientID),
+			ChannelStateCache:    ecp.channelStateCache.FilterForClient(clientID, ecp.channelConnections, ecp.connectionClients),
+			IBCHeaderCache:       ibcHeaderCache,
+		})
+	}
+
+	persistence.latestQueriedBlock = newLatestQueriedBlock
+
+	ecp.backfill.Checkpoint(ecp.log, chainID, newLatestQueriedBlock, ecp.inSync)
+
+	return nil
+}
+
+// queryHeaders queries the IBC headers of the blocks from height from to height to, at most backfill.MaxInFlight
+// of them at once. It returns the headers in height order, up to the first block which failed to be queried
+// along with its error.
+func (ecp *EVMChainProcessor) queryHeaders(ctx context.Context, from, to int64) ([]evm.EVMIBCHeader, error) {
+	headers := make([]evm.EVMIBCHeader, to-from+1)
+	errs := make([]error, len(headers))
+
+	var eg errgroup.Group
+	eg.SetLimit(ecp.backfill.WithDefaults().MaxInFlight)
+	for i := range headers {
+		i := i
+		eg.Go(func() error {
+			queryCtx, cancelQueryCtx := context.WithTimeout(ctx, queryTimeout)
+			defer cancelQueryCtx()
+			ibcHeader, err := ecp.chainProvider.IBCHeaderAtHeight(queryCtx, from+int64(i))
+			if err != nil {
+				errs[i] = err
+				return nil
+			}
+			headers[i] = ibcHeader.(evm.EVMIBCHeader)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return headers[:i], err
+		}
+	}
+	return headers, nil
+}
+
+// txSigner returns the address that signed the transaction emitting the packet event e, which is the relayer
+// who submitted it, if it is needed to record relayer activity or publish the event, or an empty string.
+// The signers of the transactions of the cycle are cached in signers.
+func (ecp *EVMChainProcessor) txSigner(ctx context.Context, e evm.IBCEvent, signers map[common.Hash]string) string {
+	if e.Packet == nil || (ecp.relayerActivity == nil && ecp.publisher == nil) {
+		return ""
+	}
+	if signer, ok := signers[e.TxHash]; ok {
+		return signer
+	}
+	queryCtx, cancelQueryCtx := context.WithTimeout(ctx, queryTimeout)
+	defer cancelQueryCtx()
+	signer, err := ecp.chainProvider.QueryTxSender(queryCtx, e.TxHash)
+	if err != nil {
+		ecp.log.Debug("Failed to query transaction signer", zap.Stringer("tx_hash", e.TxHash), zap.Error(err))
+	}
+	signers[e.TxHash] = signer
+	return signer
+}
@@ -0,0 +1,201 @@
+package evm
+
+import (
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/cosmos/relayer/v2/relayer/provider/evm"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func (ecp *EVMChainProcessor) handleEvent(e evm.IBCEvent, c processor.IBCMessagesCache) {
+	switch {
+	case e.Packet != nil:
+		ecp.handlePacketMessage(packetEventType(e), *e.Packet, c)
+	case e.Channel != nil:
+		ecp.handleChannelMessage(e.EventType, *e.Channel, c)
+	case e.Connection != nil:
+		ecp.handleConnectionMessage(e.EventType, *e.Connection, c)
+	case e.Client != nil:
+		ecp.handleClientMessage(e.EventType, *e.Client)
+	}
+}
+
+// packetEventType returns the event type the packet event e is retained as. An acknowledgement written
+// asynchronously, after the packet was received, is retained as the recv_packet event it completes.
+func packetEventType(e evm.IBCEvent) string {
+	if e.EventType == chantypes.EventTypeWriteAck {
+		return chantypes.EventTypeRecvPacket
+	}
+	return e.EventType
+}
+
+// recordRelayerActivity accounts for recv and ack messages on relayed channels, including those submitted by other relayers.
+func (ecp *EVMChainProcessor) recordRelayerActivity(e evm.IBCEvent, signer string) {
+	if ecp.relayerActivity == nil || e.Packet == nil {
+		return
+	}
+	if e.EventType != chantypes.EventTypeRecvPacket && e.EventType != chantypes.EventTypeAcknowledgePacket {
+		return
+	}
+	chainID := ecp.chainProvider.ChainId()
+	k, err := processor.PacketInfoChannelKey(e.EventType, *e.Packet)
+	if err != nil || !ecp.pathProcessors.IsRelayedChannel(k, chainID) {
+		return
+	}
+	ecp.relayerActivity.Record(chainID, k.ChannelID, k.PortID, e.EventType, signer)
+}
+
+// feedEventTypes maps the packet events published to the feed to their feed event type.
+var feedEventTypes = map[string]string{
+	chantypes.EventTypeSendPacket:           feed.EventPacketSent,
+	chantypes.EventTypeRecvPacket:           feed.EventPacketReceived,
+	chantypes.EventTypeAcknowledgePacket:    feed.EventPacketAcknowledged,
+	chantypes.EventTypeTimeoutPacket:        feed.EventPacketTimedOut,
+	chantypes.EventTypeTimeoutPacketOnClose: feed.EventPacketTimedOut,
+}
+
+// publishPacketEvent publishes the packet lifecycle events of relayed channels to the feed.
+func (ecp *EVMChainProcessor) publishPacketEvent(e evm.IBCEvent, signer string) {
+	if ecp.publisher == nil || e.Packet == nil {
+		return
+	}
+	eventType, ok := feedEventTypes[e.EventType]
+	if !ok {
+		return
+	}
+	chainID := ecp.chainProvider.ChainId()
+	k, err := processor.PacketInfoChannelKey(e.EventType, *e.Packet)
+	if err != nil || !ecp.pathProcessors.IsRelayedChannel(k, chainID) {
+		return
+	}
+	ecp.publisher.Publish(feed.Event{
+		Type:    eventType,
+		ChainID: chainID,
+		Height:  e.Packet.Height,
+		Signer:  signer,
+		Packet:  feed.NewPacket(*e.Packet),
+	})
+}
+
+func (ecp *EVMChainProcessor) handlePacketMessage(eventType string, pi provider.PacketInfo, c processor.IBCMessagesCache) {
+	k, err := processor.PacketInfoChannelKey(eventType, pi)
+	if err != nil {
+		ecp.log.Error("Unexpected error handling packet message",
+			zap.String("event_type", eventType),
+			zap.Uint64("sequence", pi.Sequence),
+			zap.Inline(k),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if !c.PacketFlow.ShouldRetainSequence(ecp.pathProcessors, k, ecp.chainProvider.ChainId(), eventType, pi.Sequence) {
+		ecp.log.Debug("Not retaining packet message",
+			zap.String("event_type", eventType),
+			zap.Uint64("sequence", pi.Sequence),
+			zap.Inline(k),
+		)
+		return
+	}
+
+	ecp.log.Debug("Retaining packet message",
+		zap.String("event_type", eventType),
+		zap.Uint64("sequence", pi.Sequence),
+		zap.Inline(k),
+	)
+
+	c.PacketFlow.Retain(k, eventType, pi)
+	ecp.logPacketMessage(eventType, pi)
+}
+
+func (ecp *EVMChainProcessor) handleChannelMessage(eventType string, ci provider.ChannelInfo, ibcMessagesCache processor.IBCMessagesCache) {
+	ecp.channelConnections[ci.ChannelID] = ci.ConnID
+	channelKey := processor.ChannelInfoChannelKey(ci)
+	switch eventType {
+	case chantypes.EventTypeChannelOpenInit, chantypes.EventTypeChannelOpenTry:
+		ecp.channelStateCache[channelKey] = false
+	case chantypes.EventTypeChannelOpenAck, chantypes.EventTypeChannelOpenConfirm:
+		ecp.channelStateCache[channelKey] = true
+	case chantypes.EventTypeChannelCloseInit, chantypes.EventTypeChannelCloseConfirm:
+		for k := range ecp.channelStateCache {
+			if k.PortID == ci.PortID && k.ChannelID == ci.ChannelID {
+				ecp.channelStateCache[k] = false
+				break
+			}
+		}
+	}
+	ibcMessagesCache.ChannelHandshake.Retain(channelKey, eventType, ci)
+
+	ecp.logChannelMessage(eventType, ci)
+}
+
+func (ecp *EVMChainProcessor) handleConnectionMessage(eventType string, ci provider.ConnectionInfo, ibcMessagesCache processor.IBCMessagesCache) {
+	ecp.connectionClients[ci.ConnID] = ci.ClientID
+	connectionKey := processor.ConnectionInfoConnectionKey(ci)
+	open := (eventType == conntypes.EventTypeConnectionOpenAck || eventType == conntypes.EventTypeConnectionOpenConfirm)
+	ecp.connectionStateCache[connectionKey] = open
+	ibcMessagesCache.ConnectionHandshake.Retain(connectionKey, eventType, ci)
+
+	ecp.logConnectionMessage(eventType, ci)
+}
+
+func (ecp *EVMChainProcessor) handleClientMessage(eventType string, ci evm.ClientEvent) {
+	ecp.latestClientState.update(provider.ClientState{
+		ClientID:        ci.ClientID,
+		ConsensusHeight: ci.ConsensusHeight,
+	})
+	ecp.logObservedIBCMessage(eventType,
+		zap.String("client_id", ci.ClientID),
+		zap.Stringer("consensus_height", ci.ConsensusHeight),
+	)
+}
+
+func (ecp *EVMChainProcessor) logObservedIBCMessage(m string, fields ...zap.Field) {
+	ecp.log.With(zap.String("event_type", m)).Debug("Observed IBC message", fields...)
+}
+
+func (ecp *EVMChainProcessor) logPacketMessage(message string, pi provider.PacketInfo) {
+	if !ecp.log.Core().Enabled(zapcore.DebugLevel) {
+		return
+	}
+	fields := []zap.Field{
+		zap.Uint64("sequence", pi.Sequence),
+		zap.String("src_channel", pi.SourceChannel),
+		zap.String("src_port", pi.SourcePort),
+		zap.String("dst_channel", pi.DestChannel),
+		zap.String("dst_port", pi.DestPort),
+	}
+	if pi.TimeoutHeight.RevisionHeight > 0 {
+		fields = append(fields, zap.Uint64("timeout_height", pi.TimeoutHeight.RevisionHeight))
+	}
+	if pi.TimeoutHeight.RevisionNumber > 0 {
+		fields = append(fields, zap.Uint64("timeout_height_revision", pi.TimeoutHeight.RevisionNumber))
+	}
+	if pi.TimeoutTimestamp > 0 {
+		fields = append(fields, zap.Uint64("timeout_timestamp", pi.TimeoutTimestamp))
+	}
+	ecp.logObservedIBCMessage(message, fields...)
+}
+
+func (ecp *EVMChainProcessor) logChannelMessage(message string, ci provider.ChannelInfo) {
+	ecp.logObservedIBCMessage(message,
+		zap.String("channel_id", ci.ChannelID),
+		zap.String("port_id", ci.PortID),
+		zap.String("counterparty_channel_id", ci.CounterpartyChannelID),
+		zap.String("counterparty_port_id", ci.CounterpartyPortID),
+		zap.String("connection_id", ci.ConnID),
+	)
+}
+
+func (ecp *EVMChainProcessor) logConnectionMessage(message string, ci provider.ConnectionInfo) {
+	ecp.logObservedIBCMessage(message,
+		zap.String("client_id", ci.ClientID),
+		zap.String("connection_id", ci.ConnID),
+		zap.String("counterparty_client_id", ci.CounterpartyClientID),
+		zap.String("counterparty_connection_id", ci.CounterpartyConnID),
+	)
+}
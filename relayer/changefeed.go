@@ -0,0 +1,111 @@
+package relayer
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/cosmos/relayer/v2/relayer/feed"
+)
+
+// changefeed publishes the changes of the configuration and runtime state of the relayed paths,
+// such as channels paused or paths handed off to another processor, along with the state of the paths changed.
+// A nil *changefeed publishes nothing.
+type changefeed struct {
+	publisher *feed.Publisher
+
+	// state returns the state of the path named path.
+	state func(path string) feed.PathState
+
+	seq uint64
+}
+
+// record publishes c, its sequence being set and its paths being the state of each of paths once changed.
+func (cf *changefeed) record(c feed.Change, paths ...string) {
+	if cf == nil {
+		return
+	}
+	c.Sequence = atomic.AddUint64(&cf.seq, 1)
+	c.Paths = make([]feed.PathState, 0, len(paths))
+	for _, name := range paths {
+		c.Paths = append(c.Paths, cf.state(name))
+	}
+	cf.publisher.Publish(feed.Event{Type: feed.EventConfigChanged, Change: &c})
+}
+
+// recordPath publishes a change of kind to the path r, for cause.
+func (cf *changefeed) recordPath(r *pathRunner, kind, cause string) {
+	cf.record(feed.Change{Kind: kind, Cause: cause, Path: r.name}, r.name)
+}
+
+// recordChannel publishes a change of kind to the channel of the path r, for cause.
+func (cf *changefeed) recordChannel(r *pathRunner, kind, cause, portID, channelID string) {
+	cf.record(feed.Change{Kind: kind, Cause: cause, Path: r.name, PortID: portID, ChannelID: channelID}, r.name)
+}
+
+// pathState returns the configuration and runtime state of the path of r.
+func (s *supervisor) pathState(r *pathRunner) feed.PathState {
+	st := feed.PathState{
+		Path:       r.name,
+		SrcChainID: r.src.ChainID(),
+		DstChainID: r.dst.ChainID(),
+		SrcKey:     r.src.ChainProvider.Key(),
+		DstKey:     r.dst.ChainProvider.Key(),
+		Processor:  s.ProcessorType(r),
+		Started:    s.started(r),
+		ChannelFilter: feed.ChannelFilter{
+			Rule:     r.filter.Rule,
+			Channels: r.filter.ChannelList,
+		},
+		Priority: r.priority.Enabled(),
+	}
+	if s.relayDirection != RelayBothDirections {
+		st.Direction = s.relayDirection.String()
+	}
+	st.PausedChannels, st.Holds = r.pauses.snapshot()
+	if r.logs != nil {
+		st.LogLevel = r.logs.config().Level
+	}
+	return st
+}
+
+// startChangefeed publishes the changes of the paths to sinks, starting with a snapshot of every path.
+func (s *supervisor) startChangefeed(publisher *feed.Publisher) {
+	s.changes = &changefeed{
+		publisher: publisher,
+		state: func(path string) feed.PathState {
+			return s.pathState(s.runners[path])
+		},
+	}
+	s.changes.record(feed.Change{Kind: feed.ChangeSnapshot, Cause: feed.CauseStartup}, s.names...)
+}
+
+// pathsOfChain returns the names of the paths relaying the chain with chainID.
+func (s *supervisor) pathsOfChain(chainID string) []string {
+	var names []string
+	for _, name := range s.names {
+		r := s.runners[name]
+		if r.src.ChainID() == chainID || r.dst.ChainID() == chainID {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// snapshot returns the channels paused, as port/channel, and the reasons of the holds placed, both sorted.
+func (p *channelPauses) snapshot() (paused, holds []string) {
+	paused = []string{}
+	if p == nil {
+		return paused, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for k := range p.paused {
+		paused = append(paused, k.portID+"/"+k.channelID)
+	}
+	for reason := range p.holds {
+		holds = append(holds, reason)
+	}
+	sort.Strings(paused)
+	sort.Strings(holds)
+	return paused, holds
+}
@@ -0,0 +1,103 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// changeSink passes the events published to it on.
+type changeSink chan feed.Event
+
+func (s changeSink) Name() string { return "changes" }
+
+func (s changeSink) Publish(_ context.Context, e feed.Event) error {
+	s <- e
+	return nil
+}
+
+func TestChangefeed(t *testing.T) {
+	chain := func(chainID string) *Chain {
+		return &Chain{Chainid: chainID, ChainProvider: &cosmosprovider.CosmosProvider{PCfg: cosmosprovider.CosmosProviderConfig{ChainID: chainID, Key: "relayer"}}}
+	}
+	chains := map[string]*Chain{"chain-a": chain("chain-a"), "chain-b": chain("chain-b"), "chain-c": chain("chain-c")}
+	paths := []NamedPath{
+		{Name: "a-b", Path: &Path{
+			Src:    &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"},
+			Dst:    &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-0"},
+			Filter: ChannelFilter{Rule: allowList, ChannelList: []string{"channel-0"}},
+		}},
+		{Name: "b-c", Path: &Path{
+			Src: &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-1"},
+			Dst: &PathEnd{ChainID: "chain-c", ClientID: "07-tendermint-0"},
+		}},
+	}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+	s.metrics = processor.NewPrometheusMetrics()
+	s.relayDirection = RelaySettlementDirection
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	sink := make(changeSink, 8)
+	publisher := feed.NewPublisher(zap.NewNop(), sink)
+	go publisher.Run(ctx)
+	next := func() *feed.Change {
+		e := <-sink
+		require.Equal(t, feed.EventConfigChanged, e.Type)
+		require.Equal(t, feed.SchemaVersion, e.SchemaVersion)
+		return e.Change
+	}
+
+	// The changefeed starts with the state of every path.
+	s.startChangefeed(publisher)
+	c := next()
+	require.Equal(t, uint64(1), c.Sequence)
+	require.Equal(t, feed.ChangeSnapshot, c.Kind)
+	require.Len(t, c.Paths, 2)
+	require.Equal(t, feed.PathState{
+		Path:           "a-b",
+		SrcChainID:     "chain-a",
+		DstChainID:     "chain-b",
+		SrcKey:         "relayer",
+		DstKey:         "relayer",
+		Processor:      ProcessorEvents,
+		Direction:      "settlement",
+		ChannelFilter:  feed.ChannelFilter{Rule: allowList, Channels: []string{"channel-0"}},
+		PausedChannels: []string{},
+	}, c.Paths[0])
+
+	// Changes carry the state of the paths they affected.
+	r := s.runners["a-b"]
+	s.rateLimitExceeded(r, "transfer", "channel-0", "over the limit")
+	c = next()
+	require.Equal(t, uint64(2), c.Sequence)
+	require.Equal(t, feed.ChangeChannelPaused, c.Kind)
+	require.Equal(t, feed.CauseRateLimit, c.Cause)
+	require.Equal(t, "channel-0", c.ChannelID)
+	require.Len(t, c.Paths, 1)
+	require.Equal(t, []string{"transfer/channel-0"}, c.Paths[0].PausedChannels)
+
+	// Channels paused already are not changed.
+	s.rateLimitExceeded(r, "transfer", "channel-0", "over the limit")
+	hp := newHealthProber(zap.NewNop(), s.metrics, nil, s.runners)
+	hp.changes = s.changes
+	hp.setPaused(HealthProbe{Name: "sequencer", Paths: []string{"b-c"}}, true)
+	c = next()
+	require.Equal(t, uint64(3), c.Sequence)
+	require.Equal(t, feed.ChangePathHeld, c.Kind)
+	require.Equal(t, "b-c", c.Path)
+	require.Equal(t, []string{"health probe sequencer"}, c.Paths[0].Holds)
+
+	require.ElementsMatch(t, []string{"a-b", "b-c"}, s.pathsOfChain("chain-b"))
+	require.Equal(t, []string{"b-c"}, s.pathsOfChain("chain-c"))
+
+	// Relayers without a changefeed publish nothing.
+	var unset *changefeed
+	unset.recordPath(r, feed.ChangeLog, feed.CauseAdmin)
+}
@@ -75,8 +75,8 @@ func (c *Chain) CreateOpenChannels(
 
 	return processor.NewEventProcessor().
 		WithChainProcessors(
-			srcPathChain.chainProcessor(c.log),
-			dstPathChain.chainProcessor(c.log),
+			srcPathChain.chainProcessor(c.log, nil, nil, nil),
+			dstPathChain.chainProcessor(c.log, nil, nil, nil),
 		).
 		WithPathProcessors(pp).
 		WithInitialBlockHistory(0).
@@ -132,8 +132,8 @@ func (c *Chain) CloseChannel(
 
 	return processor.NewEventProcessor().
 		WithChainProcessors(
-			srcPathChain.chainProcessor(c.log),
-			dstPathChain.chainProcessor(c.log),
+			srcPathChain.chainProcessor(c.log, nil, nil, nil),
+			dstPathChain.chainProcessor(c.log, nil, nil, nil),
 		).
 		WithPathProcessors(processor.NewPathProcessor(
 			c.log,
@@ -0,0 +1,212 @@
+package relayer
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// defaultChannelMonitorInterval is how often monitor-only channels are checked, unless configured otherwise.
+const defaultChannelMonitorInterval = time.Minute
+
+// MonitoredChannelEnd is the backlog last observed on an end of a monitor-only channel:
+// the packets sent and the acknowledgements written on it that are pending relay to the counterparty.
+type MonitoredChannelEnd struct {
+	Path      string `json:"path"`
+	ChainID   string `json:"chain_id"`
+	ChannelID string `json:"channel_id"`
+	PortID    string `json:"port_id"`
+
+	PendingPackets int `json:"pending_packets"`
+	PendingAcks    int `json:"pending_acks"`
+
+	// OldestPendingSeconds is how long the oldest pending packet or acknowledgement has been observed pending.
+	OldestPendingSeconds float64 `json:"oldest_pending_seconds"`
+
+	// Alerting is set while the backlog exceeds the thresholds of the monitor.
+	Alerting  bool      `json:"alerting"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type monitoredEnd struct {
+	MonitoredChannelEnd
+
+	// firstSeen is when each pending message, by type and sequence, was first observed pending.
+	firstSeen map[string]time.Time
+}
+
+// channelMonitor tracks the backlog of the monitor-only channels of every path, without relaying them,
+// and alerts when it exceeds the thresholds of a path.
+type channelMonitor struct {
+	log     *zap.Logger
+	metrics *processor.PrometheusMetrics
+
+	mu   sync.Mutex
+	ends map[string]*monitoredEnd
+}
+
+func newChannelMonitor(log *zap.Logger, metrics *processor.PrometheusMetrics) *channelMonitor {
+	return &channelMonitor{
+		log:     log,
+		metrics: metrics,
+		ends:    make(map[string]*monitoredEnd),
+	}
+}
+
+func (cm *channelMonitor) check(ctx context.Context, pathName string, src, dst *Chain, m *ChannelMonitor) {
+	channels, err := queryChannelsOnConnection(ctx, src)
+	if err != nil {
+		cm.log.Warn(
+			"Failed to query channels to monitor",
+			zap.String("path", pathName),
+			zap.String("chain_id", src.ChainID()),
+			zap.String("conn_id", src.ConnectionID()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, channel := range channels {
+		if channel.State != types.OPEN || !m.Matches(channel.PortId, channel.ChannelId) {
+			continue
+		}
+		srch, dsth, err := QueryLatestHeights(ctx, src, dst)
+		if err != nil {
+			cm.log.Warn(
+				"Failed to query latest heights to monitor channel",
+				zap.String("path", pathName),
+				zap.String("channel_id", channel.ChannelId),
+				zap.Error(err),
+			)
+			return
+		}
+		sp := UnrelayedSequences(ctx, src, dst, srch, dsth, channel)
+		ap := UnrelayedAcknowledgements(ctx, src, dst, srch, dsth, channel)
+
+		now := time.Now().UTC()
+		cm.record(pathName, src.ChainID(), channel.ChannelId, channel.PortId, sp.Src, ap.Src, m, now)
+		cm.record(pathName, dst.ChainID(), channel.Counterparty.ChannelId, channel.Counterparty.PortId, sp.Dst, ap.Dst, m, now)
+	}
+}
+
+// record updates the backlog of a channel end with the sequences of the packets and acknowledgements pending on it,
+// alerting when it starts exceeding the thresholds of m, and logging once it is back within them.
+func (cm *channelMonitor) record(pathName, chainID, channelID, portID string, packets, acks []uint64, m *ChannelMonitor, now time.Time) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	key := pathName + "/" + chainID + "/" + portID + "/" + channelID
+	end, ok := cm.ends[key]
+	if !ok {
+		end = &monitoredEnd{MonitoredChannelEnd: MonitoredChannelEnd{
+			Path:      pathName,
+			ChainID:   chainID,
+			ChannelID: channelID,
+			PortID:    portID,
+		}}
+		cm.ends[key] = end
+	}
+
+	firstSeen := make(map[string]time.Time, len(packets)+len(acks))
+	var oldest time.Duration
+	track := func(msgType string, seqs []uint64) {
+		for _, seq := range seqs {
+			k := msgType + "/" + strconv.FormatUint(seq, 10)
+			seen, ok := end.firstSeen[k]
+			if !ok {
+				seen = now
+			}
+			firstSeen[k] = seen
+			if age := now.Sub(seen); age > oldest {
+				oldest = age
+			}
+		}
+	}
+	track("packet", packets)
+	track("ack", acks)
+
+	pending := len(packets) + len(acks)
+	alerting := (m.MaxPending > 0 && pending > m.MaxPending) || (m.MaxPendingAge > 0 && oldest > m.MaxPendingAge)
+
+	fields := []zap.Field{
+		zap.String("path", pathName),
+		zap.String("chain_id", chainID),
+		zap.String("channel_id", channelID),
+		zap.String("port_id", portID),
+		zap.Int("pending_packets", len(packets)),
+		zap.Int("pending_acks", len(acks)),
+		zap.Duration("oldest_pending", oldest),
+	}
+	switch {
+	case alerting && !end.Alerting:
+		cm.log.Warn(
+			"Monitored channel exceeds its thresholds",
+			append(fields, zap.Int("max_pending", m.MaxPending), zap.Duration("max_pending_age", m.MaxPendingAge))...,
+		)
+	case !alerting && end.Alerting:
+		cm.log.Info("Monitored channel is back within its thresholds", fields...)
+	}
+
+	cm.metrics.SetMonitoredChannel(pathName, chainID, channelID, portID, pending, oldest)
+
+	end.firstSeen = firstSeen
+	end.PendingPackets = len(packets)
+	end.PendingAcks = len(acks)
+	end.OldestPendingSeconds = oldest.Seconds()
+	end.Alerting = alerting
+	end.CheckedAt = now
+}
+
+// snapshot returns the backlog last observed on every monitored channel end, by path, chain, port and channel.
+func (cm *channelMonitor) snapshot() []MonitoredChannelEnd {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	ends := make([]MonitoredChannelEnd, 0, len(cm.ends))
+	for _, end := range cm.ends {
+		ends = append(ends, end.MonitoredChannelEnd)
+	}
+	sort.Slice(ends, func(i, k int) bool {
+		a, b := ends[i], ends[k]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		if a.PortID != b.PortID {
+			return a.PortID < b.PortID
+		}
+		return a.ChannelID < b.ChannelID
+	})
+	return ends
+}
+
+// startChannelMonitor monitors the monitor-only channels of every path configuring some,
+// returning nil if there are none.
+func (s *supervisor) startChannelMonitor(ctx context.Context) *channelMonitor {
+	var cm *channelMonitor
+	for _, name := range s.names {
+		r := s.runners[name]
+		if r.monitor == nil || len(r.monitor.ChannelList) == 0 {
+			continue
+		}
+		if cm == nil {
+			cm = newChannelMonitor(s.log.With(zap.String("sys", "channelmonitor")), s.metrics)
+		}
+		interval := r.monitor.CheckInterval
+		if interval <= 0 {
+			interval = defaultChannelMonitorInterval
+		}
+		s.maintenance.schedule(provider.WithPathName(ctx, r.name), TaskChannelMonitor+"/"+r.name, interval, func(ctx context.Context) {
+			cm.check(ctx, r.name, r.src, r.dst, r.monitor)
+		})
+	}
+	return cm
+}
@@ -0,0 +1,49 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestChannelMonitorThresholds(t *testing.T) {
+	cm := newChannelMonitor(zaptest.NewLogger(t), processor.NewPrometheusMetrics())
+	m := &ChannelMonitor{ChannelList: []string{"channel-0"}, MaxPending: 3, MaxPendingAge: 10 * time.Minute}
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cm.record("demo-path", "chain-a", "channel-0", "transfer", []uint64{1, 2}, []uint64{7}, m, start)
+	ends := cm.snapshot()
+	require.Len(t, ends, 1)
+	require.Equal(t, 2, ends[0].PendingPackets)
+	require.Equal(t, 1, ends[0].PendingAcks)
+	require.False(t, ends[0].Alerting)
+
+	// Packet 1 was relayed, packet 2 is still pending after 5 minutes, and the backlog grew past the threshold.
+	cm.record("demo-path", "chain-a", "channel-0", "transfer", []uint64{2, 3, 4}, []uint64{7}, m, start.Add(5*time.Minute))
+	ends = cm.snapshot()
+	require.Equal(t, (5 * time.Minute).Seconds(), ends[0].OldestPendingSeconds)
+	require.True(t, ends[0].Alerting, "backlog exceeds max pending")
+
+	// Only packet 2 is left, pending since the first check.
+	cm.record("demo-path", "chain-a", "channel-0", "transfer", []uint64{2}, nil, m, start.Add(9*time.Minute))
+	require.False(t, cm.snapshot()[0].Alerting)
+	cm.record("demo-path", "chain-a", "channel-0", "transfer", []uint64{2}, nil, m, start.Add(11*time.Minute))
+	require.True(t, cm.snapshot()[0].Alerting, "packet pending for longer than max pending age")
+
+	cm.record("demo-path", "chain-a", "channel-0", "transfer", nil, nil, m, start.Add(12*time.Minute))
+	ends = cm.snapshot()
+	require.False(t, ends[0].Alerting)
+	require.Zero(t, ends[0].OldestPendingSeconds)
+}
+
+func TestChannelMonitorMatches(t *testing.T) {
+	var m *ChannelMonitor
+	require.False(t, m.Matches("transfer", "channel-0"))
+
+	m = &ChannelMonitor{ChannelList: []string{"transfer:channel-*"}}
+	require.True(t, m.Matches("transfer", "channel-0"))
+	require.False(t, m.Matches("icahost", "channel-0"))
+}
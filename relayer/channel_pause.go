@@ -0,0 +1,339 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// pausedChannel identifies a channel of a path by its port and channel IDs on the src chain.
+type pausedChannel struct {
+	portID, channelID string
+}
+
+// channelPauses holds the channels of a path paused through the admin API, and the holds pausing all of them,
+// e.g. while a health probe gating the path is failing. Paused channels are not relayed,
+// their pending packets and acknowledgements are relayed once they are resumed.
+// It is safe for concurrent use, and a nil *channelPauses pauses no channel.
+type channelPauses struct {
+	srcChainID string
+
+	mu     sync.Mutex
+	paused map[pausedChannel]bool
+	// holds pause every channel of the path, by the reason they were placed for.
+	holds map[string]bool
+
+	// resumed is signaled when a channel is resumed, so the legacy main loop schedules it again.
+	resumed chan struct{}
+}
+
+func newChannelPauses(srcChainID string) *channelPauses {
+	return &channelPauses{
+		srcChainID: srcChainID,
+		paused:     make(map[pausedChannel]bool),
+		holds:      make(map[string]bool),
+		resumed:    make(chan struct{}, 1),
+	}
+}
+
+// pause pauses the channel, returning false if it already was.
+func (p *channelPauses) pause(portID, channelID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	k := pausedChannel{portID: portID, channelID: channelID}
+	if p.paused[k] {
+		return false
+	}
+	p.paused[k] = true
+	return true
+}
+
+// resume resumes the channel, returning false if it was not paused.
+func (p *channelPauses) resume(portID, channelID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	k := pausedChannel{portID: portID, channelID: channelID}
+	if !p.paused[k] {
+		return false
+	}
+	delete(p.paused, k)
+	p.signalResumed()
+	return true
+}
+
+// hold pauses every channel of the path until the hold placed for reason is released, returning false if it already was placed.
+// Channels paused through the admin API stay paused once the hold is released.
+func (p *channelPauses) hold(reason string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.holds[reason] {
+		return false
+	}
+	p.holds[reason] = true
+	return true
+}
+
+// release releases the hold placed for reason, returning false if it was not placed.
+func (p *channelPauses) release(reason string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.holds[reason] {
+		return false
+	}
+	delete(p.holds, reason)
+	p.signalResumed()
+	return true
+}
+
+// held reports whether the hold placed for reason is.
+func (p *channelPauses) held(reason string) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.holds[reason]
+}
+
+// signalResumed wakes up the legacy main loop, p.mu being held.
+func (p *channelPauses) signalResumed() {
+	select {
+	case p.resumed <- struct{}{}:
+	default:
+	}
+}
+
+// isPaused reports whether the channel of the src chain is paused.
+func (p *channelPauses) isPaused(portID, channelID string) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.holds) > 0 || p.paused[pausedChannel{portID: portID, channelID: channelID}]
+}
+
+// IsChannelPaused implements processor.ChannelPauser, for channel keys from the perspective of either chain of the path.
+func (p *channelPauses) IsChannelPaused(chainID string, k processor.ChannelKey) bool {
+	if p == nil {
+		return false
+	}
+	if chainID == p.srcChainID {
+		return p.isPaused(k.PortID, k.ChannelID)
+	}
+	return p.isPaused(k.CounterpartyPortID, k.CounterpartyChannelID)
+}
+
+// unpaused returns the channels which are not paused.
+func (p *channelPauses) unpaused(channels map[string]*ActiveChannel) map[string]*ActiveChannel {
+	if p == nil {
+		return channels
+	}
+	res := make(map[string]*ActiveChannel, len(channels))
+	for id, c := range channels {
+		if !p.isPaused(c.channel.PortId, c.channel.ChannelId) {
+			res[id] = c
+		}
+	}
+	return res
+}
+
+// yield returns the yield function of the relaying goroutine of c, which also hands its turn over once c is paused.
+func (p *channelPauses) yield(c *ActiveChannel, shouldYield func() bool) func() bool {
+	return func() bool {
+		return shouldYield() || p.isPaused(c.channel.PortId, c.channel.ChannelId)
+	}
+}
+
+// resumedCh returns the channel signaled when a channel is resumed, nil, which blocks forever, if p is nil.
+func (p *channelPauses) resumedCh() <-chan struct{} {
+	if p == nil {
+		return nil
+	}
+	return p.resumed
+}
+
+// openChannels returns the open channels of the path relayed according to its channel filter, in channel ID order.
+func (r *pathRunner) openChannels(ctx context.Context) ([]*ActiveChannel, error) {
+	srcChannels, _, err := r.connections().channels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	open := filterOpenChannels(srcChannels)
+	channels := make([]*ActiveChannel, 0, len(open))
+	for _, c := range open {
+		channels = append(channels, c)
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].channel.ChannelId < channels[j].channel.ChannelId
+	})
+	return channels, nil
+}
+
+// pendingSequences returns the packets and acknowledgements of each open channel of the path waiting to be relayed.
+func (r *pathRunner) pendingSequences(ctx context.Context) ([]admin.PendingSequences, error) {
+	channels, err := r.openChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]admin.PendingSequences, 0, len(channels))
+	for _, c := range channels {
+		pending := admin.PendingSequences{Path: r.name, PortID: c.channel.PortId, ChannelID: c.channel.ChannelId}
+		srch, dsth, err := QueryLatestHeights(ctx, r.src, r.dst)
+		if err != nil {
+			pending.Error = err.Error()
+			res = append(res, pending)
+			continue
+		}
+		sp := UnrelayedSequences(ctx, r.src, r.dst, srch-1, dsth-1, c.channel)
+		ap := UnrelayedAcknowledgements(ctx, r.src, r.dst, srch-1, dsth-1, c.channel)
+		pending.SrcPackets, pending.DstPackets = sp.Src, sp.Dst
+		pending.SrcAcks, pending.DstAcks = ap.Src, ap.Dst
+		res = append(res, pending)
+	}
+	return res, nil
+}
+
+// runnersOf returns the runner of the path named in the path query parameter of req, or all runners if it is unset.
+func (s *supervisor) runnersOf(req *http.Request) ([]*pathRunner, error) {
+	if name := req.URL.Query().Get("path"); name != "" {
+		r, ok := s.runners[name]
+		if !ok {
+			return nil, fmt.Errorf("path %s not found", name)
+		}
+		return []*pathRunner{r}, nil
+	}
+	runners := make([]*pathRunner, 0, len(s.names))
+	for _, name := range s.names {
+		runners = append(runners, s.runners[name])
+	}
+	return runners, nil
+}
+
+// registerChannelHandlers exposes the channels of each path through the admin API.
+// Channels are identified by their port and channel IDs on the src chain of their path.
+//
+//	GET  /channels        lists the open channels relayed on each path, or on the path of the path query parameter.
+//	POST /channels/pause  stops relaying a channel until it is resumed.
+//	POST /channels/resume resumes relaying a paused channel.
+//	GET  /pending         lists the packets and acknowledgements waiting to be relayed on each channel.
+func registerChannelHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/channels", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		runners, err := s.runnersOf(req)
+		if err != nil {
+			admin.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		res := []admin.Channel{}
+		for _, r := range runners {
+			channels, err := r.openChannels(req.Context())
+			if err != nil {
+				admin.WriteError(w, http.StatusServiceUnavailable, err)
+				return
+			}
+			for _, c := range channels {
+				res = append(res, admin.Channel{
+					Path:                  r.name,
+					ChainID:               r.src.ChainID(),
+					PortID:                c.channel.PortId,
+					ChannelID:             c.channel.ChannelId,
+					CounterpartyChainID:   r.dst.ChainID(),
+					CounterpartyPortID:    c.channel.Counterparty.PortId,
+					CounterpartyChannelID: c.channel.Counterparty.ChannelId,
+					Paused:                r.pauses.isPaused(c.channel.PortId, c.channel.ChannelId),
+				})
+			}
+		}
+		admin.WriteJSON(w, http.StatusOK, res)
+	})
+
+	setPaused := func(paused bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodPost {
+				admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+				return
+			}
+			var body admin.ChannelRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			r, ok := s.runners[body.Path]
+			if !ok {
+				admin.WriteError(w, http.StatusNotFound, fmt.Errorf("path %s not found", body.Path))
+				return
+			}
+			if body.PortID == "" || body.ChannelID == "" {
+				admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("port_id and channel_id are required"))
+				return
+			}
+
+			var changed bool
+			if paused {
+				changed = r.pauses.pause(body.PortID, body.ChannelID)
+			} else {
+				changed = r.pauses.resume(body.PortID, body.ChannelID)
+				// Resuming a channel paused by its rate limit approves the transfers counted against it.
+				r.rateLimiter.Reset(body.PortID, body.ChannelID)
+			}
+			if changed {
+				s.log.Info(
+					"Changed channel pause state",
+					zap.String("path_name", r.name),
+					zap.String("chain_id", r.src.ChainID()),
+					zap.String("port_id", body.PortID),
+					zap.String("channel_id", body.ChannelID),
+					zap.Bool("paused", paused),
+				)
+				kind := feed.ChangeChannelResumed
+				if paused {
+					kind = feed.ChangeChannelPaused
+				}
+				s.changes.recordChannel(r, kind, feed.CauseAdmin, body.PortID, body.ChannelID)
+			}
+			admin.WriteJSON(w, http.StatusOK, admin.Channel{
+				Path:      r.name,
+				ChainID:   r.src.ChainID(),
+				PortID:    body.PortID,
+				ChannelID: body.ChannelID,
+				Paused:    paused,
+			})
+		}
+	}
+	srv.HandleFunc("/channels/pause", setPaused(true))
+	srv.HandleFunc("/channels/resume", setPaused(false))
+
+	srv.HandleFunc("/pending", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		runners, err := s.runnersOf(req)
+		if err != nil {
+			admin.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		res := []admin.PendingSequences{}
+		for _, r := range runners {
+			pending, err := r.pendingSequences(req.Context())
+			if err != nil {
+				admin.WriteError(w, http.StatusServiceUnavailable, err)
+				return
+			}
+			res = append(res, pending...)
+		}
+		admin.WriteJSON(w, http.StatusOK, res)
+	})
+}
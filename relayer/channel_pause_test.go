@@ -0,0 +1,131 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestChannelPauses(t *testing.T) {
+	p := newChannelPauses("chain-a")
+
+	require.True(t, p.pause("transfer", "channel-0"))
+	require.False(t, p.pause("transfer", "channel-0"), "already paused")
+	require.True(t, p.isPaused("transfer", "channel-0"))
+	require.False(t, p.isPaused("transfer", "channel-1"))
+
+	// Channel keys are matched from the perspective of either chain of the path.
+	require.True(t, p.IsChannelPaused("chain-a", processor.ChannelKey{PortID: "transfer", ChannelID: "channel-0", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-7"}))
+	require.True(t, p.IsChannelPaused("chain-b", processor.ChannelKey{PortID: "transfer", ChannelID: "channel-7", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-0"}))
+	require.False(t, p.IsChannelPaused("chain-b", processor.ChannelKey{PortID: "transfer", ChannelID: "channel-0", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-7"}))
+
+	channels := schedulerTestChannels("channel-0", "channel-1")
+	for _, c := range channels {
+		c.channel.PortId = "transfer"
+	}
+	require.Len(t, p.unpaused(channels), 1)
+	require.Contains(t, p.unpaused(channels), "channel-1")
+
+	// Relaying goroutines of paused channels yield their turn.
+	notWaiting := func() bool { return false }
+	require.True(t, p.yield(channels["channel-0"], notWaiting)())
+	require.False(t, p.yield(channels["channel-1"], notWaiting)())
+
+	require.True(t, p.resume("transfer", "channel-0"))
+	require.False(t, p.resume("transfer", "channel-0"), "not paused")
+	require.Len(t, p.unpaused(channels), 2)
+	select {
+	case <-p.resumedCh():
+	default:
+		t.Fatal("resuming a channel must wake up the main loop")
+	}
+
+	// A hold pauses every channel until it is released.
+	require.True(t, p.pause("transfer", "channel-0"))
+	require.True(t, p.hold("probe:sequencer"))
+	require.False(t, p.hold("probe:sequencer"), "already held")
+	require.True(t, p.isPaused("transfer", "channel-1"))
+	require.Empty(t, p.unpaused(channels))
+	require.True(t, p.release("probe:sequencer"))
+	require.False(t, p.release("probe:sequencer"), "not held")
+	require.False(t, p.isPaused("transfer", "channel-1"))
+	require.True(t, p.isPaused("transfer", "channel-0"), "paused through the admin API")
+	select {
+	case <-p.resumedCh():
+	default:
+		t.Fatal("releasing a hold must wake up the main loop")
+	}
+
+	var unset *channelPauses
+	require.False(t, unset.isPaused("transfer", "channel-0"))
+	require.False(t, unset.IsChannelPaused("chain-a", processor.ChannelKey{}))
+	require.Len(t, unset.unpaused(channels), 2)
+	require.Nil(t, unset.resumedCh())
+}
+
+func TestChannelPauseHandlers(t *testing.T) {
+	chain := func(chainID string) *Chain {
+		return &Chain{Chainid: chainID, ChainProvider: &cosmosprovider.CosmosProvider{PCfg: cosmosprovider.CosmosProviderConfig{ChainID: chainID}}}
+	}
+	chains := map[string]*Chain{"chain-a": chain("chain-a"), "chain-b": chain("chain-b")}
+	paths := []NamedPath{{Name: "a-b", Path: &Path{
+		Src: &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"},
+		Dst: &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-0"},
+	}}}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	srv := admin.NewServer(zaptest.NewLogger(t))
+	registerChannelHandlers(srv, s)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv.Start(ctx, ln)
+
+	nonce := 0
+	post := func(target string, body admin.ChannelRequest) (int, admin.Channel) {
+		bz, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "http://"+ln.Addr().String()+target, bytes.NewReader(bz))
+		require.NoError(t, err)
+		nonce++
+		req.Header.Set(admin.OperatorHeader, "alice")
+		req.Header.Set(admin.NonceHeader, strconv.Itoa(nonce))
+		req.Header.Set(admin.TimestampHeader, strconv.FormatInt(time.Now().Unix(), 10))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var res admin.Channel
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		return resp.StatusCode, res
+	}
+
+	code, res := post("/channels/pause", admin.ChannelRequest{Path: "a-b", PortID: "transfer", ChannelID: "channel-0"})
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, res.Paused)
+	require.Equal(t, "chain-a", res.ChainID)
+	require.True(t, s.runners["a-b"].pauses.isPaused("transfer", "channel-0"))
+
+	code, res = post("/channels/resume", admin.ChannelRequest{Path: "a-b", PortID: "transfer", ChannelID: "channel-0"})
+	require.Equal(t, http.StatusOK, code)
+	require.False(t, res.Paused)
+	require.False(t, s.runners["a-b"].pauses.isPaused("transfer", "channel-0"))
+
+	code, _ = post("/channels/pause", admin.ChannelRequest{Path: "unknown", PortID: "transfer", ChannelID: "channel-0"})
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = post("/channels/pause", admin.ChannelRequest{Path: "a-b", ChannelID: "channel-0"})
+	require.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,79 @@
+package relayer
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// channelScheduler hands the open channels of a path relayed by the legacy processor out to at most max
+// relaying goroutines at once, in the order they became ready to relay, so that connections with hundreds
+// of channels do not overwhelm the RPC nodes, while every channel still gets its turn.
+// It is only used by the main loop of the path, except for shouldYield.
+type channelScheduler struct {
+	// max is the maximum number of channels relayed concurrently, 0 for no limit.
+	max     int
+	running int
+	queue   []*ActiveChannel
+
+	// waiting is the length of the queue, read by the running goroutines.
+	waiting int32
+}
+
+func newChannelScheduler(max int) *channelScheduler {
+	return &channelScheduler{max: max}
+}
+
+// enqueue queues the channels which are neither running, queued nor dormant, e.g. discovered or restarted ones,
+// in channel ID order.
+func (cs *channelScheduler) enqueue(channels map[string]*ActiveChannel) {
+	ids := make([]string, 0, len(channels))
+	for id, c := range channels {
+		if !c.active && !c.queued && !c.dormant {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		cs.push(channels[id])
+	}
+}
+
+func (cs *channelScheduler) push(c *ActiveChannel) {
+	c.queued = true
+	cs.queue = append(cs.queue, c)
+	atomic.StoreInt32(&cs.waiting, int32(len(cs.queue)))
+}
+
+// next returns the next queued channel to relay and marks it active,
+// or nil if there is none or the maximum number of channels are already being relayed.
+func (cs *channelScheduler) next() *ActiveChannel {
+	if len(cs.queue) == 0 || (cs.max > 0 && cs.running >= cs.max) {
+		return nil
+	}
+	c := cs.queue[0]
+	cs.queue[0] = nil
+	cs.queue = cs.queue[1:]
+	atomic.StoreInt32(&cs.waiting, int32(len(cs.queue)))
+
+	c.queued = false
+	c.active = true
+	cs.running++
+	return c
+}
+
+// done marks the channel, whose goroutine exited, inactive.
+// A channel which yielded its turn goes back to the end of the queue.
+func (cs *channelScheduler) done(c *ActiveChannel) {
+	c.active = false
+	cs.running--
+	if c.yielded {
+		c.yielded = false
+		cs.push(c)
+	}
+}
+
+// shouldYield reports whether running goroutines should hand their turn over to queued channels
+// after relaying their pending packets and acknowledgements.
+func (cs *channelScheduler) shouldYield() bool {
+	return cs.max > 0 && atomic.LoadInt32(&cs.waiting) > 0
+}
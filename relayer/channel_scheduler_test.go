@@ -0,0 +1,67 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+)
+
+func schedulerTestChannels(ids ...string) map[string]*ActiveChannel {
+	channels := make(map[string]*ActiveChannel)
+	for _, id := range ids {
+		channels[id] = &ActiveChannel{channel: &types.IdentifiedChannel{ChannelId: id}}
+	}
+	return channels
+}
+
+func nextChannelIDs(cs *channelScheduler) []string {
+	var ids []string
+	for c := cs.next(); c != nil; c = cs.next() {
+		ids = append(ids, c.channel.ChannelId)
+	}
+	return ids
+}
+
+func TestChannelSchedulerTakesTurns(t *testing.T) {
+	channels := schedulerTestChannels("channel-0", "channel-1", "channel-2")
+	cs := newChannelScheduler(2)
+
+	cs.enqueue(channels)
+	require.Equal(t, []string{"channel-0", "channel-1"}, nextChannelIDs(cs))
+	require.True(t, cs.shouldYield(), "channel-2 is waiting")
+
+	// Re-enqueueing leaves running and queued channels alone.
+	cs.enqueue(channels)
+	require.Empty(t, nextChannelIDs(cs))
+
+	// channel-0 yields its turn to channel-2, and waits for its next turn.
+	channels["channel-0"].yielded = true
+	cs.done(channels["channel-0"])
+	require.Equal(t, []string{"channel-2"}, nextChannelIDs(cs))
+	require.True(t, cs.shouldYield(), "channel-0 is waiting")
+
+	// channel-1 exits on an error, it is only queued again by the main loop once still open.
+	cs.done(channels["channel-1"])
+	require.Equal(t, []string{"channel-0"}, nextChannelIDs(cs))
+	require.False(t, cs.shouldYield())
+
+	cs.enqueue(channels)
+	require.Empty(t, nextChannelIDs(cs), "the maximum number of channels are running")
+	cs.done(channels["channel-2"])
+	require.Equal(t, []string{"channel-1"}, nextChannelIDs(cs))
+}
+
+func TestChannelSchedulerWithoutLimit(t *testing.T) {
+	channels := schedulerTestChannels("channel-0", "channel-1", "channel-2")
+	cs := newChannelScheduler(0)
+
+	cs.enqueue(channels)
+	require.Equal(t, []string{"channel-0", "channel-1", "channel-2"}, nextChannelIDs(cs))
+	require.False(t, cs.shouldYield())
+
+	channels["channel-3"] = &ActiveChannel{channel: &types.IdentifiedChannel{ChannelId: "channel-3"}}
+	cs.enqueue(channels)
+	require.Equal(t, []string{"channel-3"}, nextChannelIDs(cs))
+	require.False(t, cs.shouldYield(), "channels never yield without a limit")
+}
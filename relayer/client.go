@@ -267,7 +267,7 @@ func (c *Chain) UpdateClients(ctx context.Context, dst *Chain, memo string) (err
 		return err
 	}
 
-	srcUpdateMsg, err := c.ChainProvider.MsgUpdateClient(c.ClientID(), dstUpdateHeader)
+	srcUpdateMsgs, err := msgUpdateClients(ctx, dst.ChainProvider, c.ChainProvider, c.ClientID(), dstUpdateHeader)
 	if err != nil {
 		c.log.Debug(
 			"Failed to update source client",
@@ -277,7 +277,7 @@ func (c *Chain) UpdateClients(ctx context.Context, dst *Chain, memo string) (err
 		return err
 	}
 
-	dstUpdateMsg, err := dst.ChainProvider.MsgUpdateClient(dst.ClientID(), srcUpdateHeader)
+	dstUpdateMsgs, err := msgUpdateClients(ctx, c.ChainProvider, dst.ChainProvider, dst.ClientID(), srcUpdateHeader)
 	if err != nil {
 		dst.log.Debug(
 			"Failed to update destination client",
@@ -288,8 +288,8 @@ func (c *Chain) UpdateClients(ctx context.Context, dst *Chain, memo string) (err
 	}
 
 	clients := &RelayMsgs{
-		Src: []provider.RelayerMessage{srcUpdateMsg},
-		Dst: []provider.RelayerMessage{dstUpdateMsg},
+		Src: srcUpdateMsgs,
+		Dst: dstUpdateMsgs,
 	}
 
 	// Send msgs to both chains
@@ -322,6 +322,34 @@ func (c *Chain) UpdateClients(ctx context.Context, dst *Chain, memo string) (err
 	return nil
 }
 
+// updateHeaderBisector is implemented by chain providers that can split a client update into updates through
+// intermediate headers, when the validators changed too much since the trusted height for the client to verify it.
+type updateHeaderBisector interface {
+	BisectUpdateClientHeader(ctx context.Context, header ibcexported.Header, dst provider.ChainProvider, dstClientID string) ([]ibcexported.Header, error)
+}
+
+// msgUpdateClients returns the messages updating the client of src on dst to header,
+// bisected through intermediate headers if src supports it.
+func msgUpdateClients(ctx context.Context, src, dst provider.ChainProvider, dstClientID string, header ibcexported.Header) ([]provider.RelayerMessage, error) {
+	headers := []ibcexported.Header{header}
+	if b, ok := src.(updateHeaderBisector); ok {
+		var err error
+		headers, err = b.BisectUpdateClientHeader(ctx, header, dst, dstClientID)
+		if err != nil {
+			return nil, fmt.Errorf("error bisecting client update: %w", err)
+		}
+	}
+	msgs := make([]provider.RelayerMessage, len(headers))
+	for i, h := range headers {
+		var err error
+		msgs[i], err = dst.MsgUpdateClient(dstClientID, h)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return msgs, nil
+}
+
 // UpgradeClients upgrades the client on src after dst chain has undergone an upgrade.
 func (c *Chain) UpgradeClients(ctx context.Context, dst *Chain, height int64, memo string) error {
 	dstHeader, err := dst.ChainProvider.GetLightSignedHeaderAtHeight(ctx, height)
@@ -0,0 +1,163 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// ClientChainIDCheck is the outcome of verifying that the client of a path end tracks its live counterparty.
+//
+// A client tracks a chain ID and the heights of one revision of it. Relaying against a client whose chain ID
+// or revision is not that of the counterparty, e.g. after a rollapp restarted from genesis without being announced,
+// fails with errors unrelated to the cause, so mismatches are reported on their own.
+type ClientChainIDCheck struct {
+	Path                 string    `json:"path"`
+	ChainID              string    `json:"chain_id"`
+	ClientID             string    `json:"client_id"`
+	CounterpartyChainID  string    `json:"counterparty_chain_id"`
+	ClientChainID        string    `json:"client_chain_id"`
+	ClientHeight         string    `json:"client_height"`
+	CounterpartyRevision uint64    `json:"counterparty_revision"`
+	CounterpartyHeight   int64     `json:"counterparty_height"`
+	Mismatch             string    `json:"mismatch,omitempty"`
+	CheckedAt            time.Time `json:"checked_at"`
+}
+
+// clientChainIDMonitor periodically verifies that the clients of the relayed paths track the chain ID,
+// revision and heights of their live counterparty, and reports the clients that do not.
+type clientChainIDMonitor struct {
+	log     *zap.Logger
+	metrics *processor.PrometheusMetrics
+
+	mu     sync.Mutex
+	checks map[string]ClientChainIDCheck
+}
+
+func newClientChainIDMonitor(log *zap.Logger, metrics *processor.PrometheusMetrics) *clientChainIDMonitor {
+	return &clientChainIDMonitor{
+		log:     log,
+		metrics: metrics,
+		checks:  make(map[string]ClientChainIDCheck),
+	}
+}
+
+// check verifies the client of c tracking counterparty.
+func (m *clientChainIDMonitor) check(ctx context.Context, path string, c, counterparty *Chain) {
+	h, err := c.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		m.warnFailed(path, c, err)
+		return
+	}
+	cs, err := c.ChainProvider.QueryClientState(ctx, h, c.ClientID())
+	if err != nil {
+		m.warnFailed(path, c, err)
+		return
+	}
+	counterpartyHeight, err := counterparty.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		m.warnFailed(path, c, err)
+		return
+	}
+
+	chk := ClientChainIDCheck{
+		Path:                 path,
+		ChainID:              c.ChainID(),
+		ClientID:             c.ClientID(),
+		CounterpartyChainID:  counterparty.ChainID(),
+		CounterpartyRevision: clienttypes.ParseChainID(counterparty.ChainID()),
+		CounterpartyHeight:   counterpartyHeight,
+		CheckedAt:            time.Now().UTC(),
+	}
+	if cc, ok := cs.(interface{ GetChainID() string }); ok {
+		chk.ClientChainID = cc.GetChainID()
+	}
+	latest := cs.GetLatestHeight()
+	chk.ClientHeight = latest.String()
+	chk.Mismatch = clientChainIDMismatch(chk, latest)
+	m.record(chk)
+}
+
+func (m *clientChainIDMonitor) warnFailed(path string, c *Chain, err error) {
+	m.log.Warn(
+		"Failed to verify the chain tracked by client",
+		zap.String("path", path),
+		zap.String("chain_id", c.ChainID()),
+		zap.String("client_id", c.ClientID()),
+		zap.Error(err),
+	)
+}
+
+// clientChainIDMismatch returns why the client of chk does not track its live counterparty, or an empty string.
+func clientChainIDMismatch(chk ClientChainIDCheck, latest ibcexported.Height) string {
+	switch {
+	case chk.ClientChainID != "" && chk.ClientChainID != chk.CounterpartyChainID:
+		return fmt.Sprintf("client tracks chain %s", chk.ClientChainID)
+	case latest.GetRevisionNumber() != chk.CounterpartyRevision:
+		return fmt.Sprintf("client tracks revision %d", latest.GetRevisionNumber())
+	case chk.CounterpartyHeight >= 0 && latest.GetRevisionHeight() > uint64(chk.CounterpartyHeight):
+		return fmt.Sprintf("client is at height %d, beyond the latest height of the counterparty", latest.GetRevisionHeight())
+	}
+	return ""
+}
+
+func (m *clientChainIDMonitor) record(chk ClientChainIDCheck) {
+	m.metrics.SetClientChainIDMismatch(chk.Path, chk.ChainID, chk.ClientID, chk.Mismatch != "")
+	if chk.Mismatch != "" {
+		m.log.Error(
+			"Client does not track the live counterparty chain",
+			zap.String("path", chk.Path),
+			zap.String("chain_id", chk.ChainID),
+			zap.String("client_id", chk.ClientID),
+			zap.String("counterparty_chain_id", chk.CounterpartyChainID),
+			zap.String("client_chain_id", chk.ClientChainID),
+			zap.String("client_height", chk.ClientHeight),
+			zap.Int64("counterparty_height", chk.CounterpartyHeight),
+			zap.String("mismatch", chk.Mismatch),
+		)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checks[chk.ChainID+"/"+chk.ClientID] = chk
+}
+
+// snapshot returns the last check of every client, by chain and client ID.
+func (m *clientChainIDMonitor) snapshot() []ClientChainIDCheck {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	checks := make([]ClientChainIDCheck, 0, len(m.checks))
+	for _, c := range m.checks {
+		checks = append(checks, c)
+	}
+	sort.Slice(checks, func(i, k int) bool {
+		if checks[i].ChainID != checks[k].ChainID {
+			return checks[i].ChainID < checks[k].ChainID
+		}
+		return checks[i].ClientID < checks[k].ClientID
+	})
+	return checks
+}
+
+// startClientChainIDMonitor verifies the clients of every path at the given interval, until ctx is done.
+func (s *supervisor) startClientChainIDMonitor(ctx context.Context, interval time.Duration) *clientChainIDMonitor {
+	m := newClientChainIDMonitor(s.log.With(zap.String("sys", "clientchainid")), s.metrics)
+	runners := make([]*pathRunner, 0, len(s.names))
+	for _, name := range s.names {
+		runners = append(runners, s.runners[name])
+	}
+	s.maintenance.schedule(ctx, TaskClientChainIDs, interval, func(ctx context.Context) {
+		for _, r := range runners {
+			m.check(ctx, r.name, r.src, r.dst)
+			m.check(ctx, r.name, r.dst, r.src)
+		}
+	})
+	return m
+}
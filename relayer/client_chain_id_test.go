@@ -0,0 +1,41 @@
+package relayer
+
+import (
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClientChainIDMismatch(t *testing.T) {
+	chk := ClientChainIDCheck{
+		ClientChainID:        "rollapp_1234-1",
+		CounterpartyChainID:  "rollapp_1234-1",
+		CounterpartyRevision: 1,
+		CounterpartyHeight:   100,
+	}
+	require.Empty(t, clientChainIDMismatch(chk, clienttypes.NewHeight(1, 90)))
+
+	// The rollapp restarted from genesis under the same chain ID.
+	require.Equal(t, "client is at height 120, beyond the latest height of the counterparty",
+		clientChainIDMismatch(chk, clienttypes.NewHeight(1, 120)))
+
+	require.Equal(t, "client tracks revision 0", clientChainIDMismatch(chk, clienttypes.NewHeight(0, 90)))
+
+	chk.ClientChainID = "rollapp_1234-2"
+	require.Equal(t, "client tracks chain rollapp_1234-2", clientChainIDMismatch(chk, clienttypes.NewHeight(1, 90)))
+}
+
+func TestClientChainIDMonitorRecord(t *testing.T) {
+	m := newClientChainIDMonitor(zap.NewNop(), nil)
+	m.record(ClientChainIDCheck{ChainID: "chain-b", ClientID: "07-tendermint-0"})
+	m.record(ClientChainIDCheck{ChainID: "chain-a", ClientID: "07-tendermint-0", Mismatch: "client tracks revision 0"})
+	m.record(ClientChainIDCheck{ChainID: "chain-a", ClientID: "07-tendermint-0"})
+
+	checks := m.snapshot()
+	require.Len(t, checks, 2)
+	require.Equal(t, "chain-a", checks[0].ChainID)
+	require.Empty(t, checks[0].Mismatch)
+	require.Equal(t, "chain-b", checks[1].ChainID)
+}
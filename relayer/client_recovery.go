@@ -0,0 +1,216 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// ErrClientActive is returned when proposing to recover a client which can still be updated,
+// governance refusing to recover an active client.
+var ErrClientActive = errors.New("client is neither expired nor frozen")
+
+// clientRecoveryProposer is implemented by the providers of chains recovering their expired or frozen clients by governance.
+type clientRecoveryProposer interface {
+	MsgClientRecoveryProposal(
+		title, description, subjectClientID, substituteClientID string,
+		deposit sdk.Coins,
+	) (provider.RelayerMessage, error)
+}
+
+// ClientRecovery is a governance proposal submitted to recover the expired or frozen client of one end of a path,
+// the subject client, with the state of an active client of the same counterparty, the substitute client.
+// Once the proposal passes, the subject client is updated again, and the path relayed on its identifiers unchanged.
+type ClientRecovery struct {
+	Path    string `json:"path"`
+	ChainID string `json:"chain_id"`
+
+	// Reason is why the subject client can no longer be updated.
+	Reason string `json:"reason"`
+
+	SubjectClientID    string `json:"subject_client_id"`
+	SubstituteClientID string `json:"substitute_client_id"`
+
+	Deposit    string    `json:"deposit"`
+	TxHash     string    `json:"tx_hash"`
+	ProposalID string    `json:"proposal_id,omitempty"`
+	ProposedAt time.Time `json:"proposed_at"`
+}
+
+// CreateSubstituteClient creates on c a new client of dst, to substitute for the expired or frozen client of c
+// in a recovery proposal, and returns its identifier. The path end of c is left untouched.
+func (c *Chain) CreateSubstituteClient(ctx context.Context, dst *Chain, memo string) (string, error) {
+	var srch, dsth int64
+	if err := retry.Do(func() error {
+		var err error
+		srch, dsth, err = QueryLatestHeights(ctx, c, dst)
+		return err
+	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr); err != nil {
+		return "", err
+	}
+
+	var srcUpdateHeader, dstUpdateHeader ibcexported.Header
+	if err := retry.Do(func() error {
+		var err error
+		srcUpdateHeader, dstUpdateHeader, err = GetLightSignedHeadersAtHeights(ctx, c, dst, srch, dsth)
+		return err
+	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr); err != nil {
+		return "", err
+	}
+
+	// CreateClient creates a client on c only if its path end has no client yet.
+	pe := c.PathEnd
+	substitute := *pe
+	substitute.ClientID, substitute.ConnectionID = "", ""
+	c.PathEnd = &substitute
+	defer func() { c.PathEnd = pe }()
+
+	// The substitute may in turn be recovered by governance, should it expire before the proposal passes.
+	if _, err := CreateClient(ctx, c, dst, srcUpdateHeader, dstUpdateHeader, true, true, true, memo); err != nil {
+		return "", err
+	}
+	return substitute.ClientID, nil
+}
+
+// ProposeClientRecovery submits on c a governance proposal to recover its expired or frozen client of the path pathName
+// with the state of the client substituteClientID, depositing deposit. The proposal is refused by governance unless
+// the substitute is active, has the parameters of the subject client other than its chain ID and trusting period,
+// and is at a greater height, which is checked beforehand.
+// ErrClientActive is returned if the client of c can still be updated.
+func (c *Chain) ProposeClientRecovery(
+	ctx context.Context,
+	pathName, substituteClientID string,
+	deposit sdk.Coins,
+	title, description, memo string,
+) (*ClientRecovery, error) {
+	proposer, ok := c.ChainProvider.(clientRecoveryProposer)
+	if !ok {
+		return nil, fmt.Errorf("chain %s does not support recovering clients by governance", c.ChainID())
+	}
+
+	now := time.Now()
+	subject, subjectTimestamp, err := latestTendermintClient(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query client %s on chain %s: %w", c.ClientID(), c.ChainID(), err)
+	}
+	reason := clientRepairReason(subject, subjectTimestamp, now)
+	if reason == "" {
+		return nil, ErrClientActive
+	}
+
+	substitute, substituteTimestamp, err := queryTendermintClient(ctx, c, substituteClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query client %s on chain %s: %w", substituteClientID, c.ChainID(), err)
+	}
+	if err := checkSubstituteClient(subject, substitute, substituteTimestamp, now); err != nil {
+		return nil, fmt.Errorf("client %s cannot substitute for client %s: %w", substituteClientID, c.ClientID(), err)
+	}
+
+	if title == "" {
+		title = fmt.Sprintf("Recover IBC client %s", c.ClientID())
+	}
+	if description == "" {
+		description = fmt.Sprintf(
+			"Recover the IBC client %s of chain %s, %s, with the state of the active client %s.",
+			c.ClientID(), subject.ChainId, reason, substituteClientID,
+		)
+	}
+	msg, err := proposer.MsgClientRecoveryProposal(title, description, c.ClientID(), substituteClientID, deposit)
+	if err != nil {
+		return nil, err
+	}
+
+	c.log.Warn(
+		"Proposing client recovery",
+		zap.String("path", pathName),
+		zap.String("chain_id", c.ChainID()),
+		zap.String("subject_client_id", c.ClientID()),
+		zap.String("substitute_client_id", substituteClientID),
+		zap.String("reason", reason),
+		zap.String("deposit", deposit.String()),
+	)
+
+	msgs := []provider.RelayerMessage{msg}
+	res, success, err := c.ChainProvider.SendMessages(ctx, msgs, memo)
+	if err != nil {
+		c.LogFailedTx(res, err, msgs)
+		return nil, fmt.Errorf("failed to submit proposal on chain %s: %w", c.ChainID(), err)
+	}
+	if !success {
+		c.LogFailedTx(res, nil, msgs)
+		return nil, fmt.Errorf("proposal tx failed on chain %s: %s", c.ChainID(), res.Data)
+	}
+
+	return &ClientRecovery{
+		Path:               pathName,
+		ChainID:            c.ChainID(),
+		Reason:             reason,
+		SubjectClientID:    c.ClientID(),
+		SubstituteClientID: substituteClientID,
+		Deposit:            deposit.String(),
+		TxHash:             res.TxHash,
+		ProposalID:         proposalIDFromEvents(res.Events),
+		ProposedAt:         now.UTC(),
+	}, nil
+}
+
+// checkSubstituteClient returns why the client substitute, whose latest consensus state is at substituteTimestamp,
+// cannot substitute for the client subject, as checked by governance when the recovery proposal passes.
+func checkSubstituteClient(subject, substitute *tmclient.ClientState, substituteTimestamp, now time.Time) error {
+	if reason := clientRepairReason(substitute, substituteTimestamp, now); reason != "" {
+		return fmt.Errorf("substitute client is %s", reason)
+	}
+	if !tmclient.IsMatchingClientState(*subject, *substitute) {
+		return errors.New("parameters of the clients other than their chain ID, trusting period and heights differ")
+	}
+	if !subject.LatestHeight.LT(substitute.LatestHeight) {
+		return fmt.Errorf(
+			"latest height %s of the substitute client is not above latest height %s of the subject client",
+			substitute.LatestHeight, subject.LatestHeight,
+		)
+	}
+	return nil
+}
+
+// proposalIDFromEvents returns the identifier of the proposal submitted by the transaction with events,
+// or an empty string if none is found.
+func proposalIDFromEvents(events []provider.RelayerEvent) string {
+	for _, e := range events {
+		if e.EventType == govtypes.EventTypeSubmitProposal {
+			if id, ok := e.Attributes[govtypes.AttributeKeyProposalID]; ok {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// RepointClient points the end of the path on chainID at the client clientID, such as the substitute of a client
+// whose recovery was rejected. The connection IDs of both ends, the connection being built on the previous client,
+// are cleared for rly tx link to create a connection on clientID.
+func (p *Path) RepointClient(chainID, clientID string) error {
+	var pe *PathEnd
+	switch chainID {
+	case p.Src.ChainID:
+		pe = p.Src
+	case p.Dst.ChainID:
+		pe = p.Dst
+	default:
+		return fmt.Errorf("chain %s is not an end of the path", chainID)
+	}
+	if pe.ClientID == clientID {
+		return nil
+	}
+	pe.ClientID = clientID
+	p.Src.ConnectionID, p.Dst.ConnectionID = "", ""
+	return nil
+}
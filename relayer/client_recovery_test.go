@@ -0,0 +1,72 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSubstituteClient(t *testing.T) {
+	now := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	subject := &tmclient.ClientState{
+		ChainId:         "osmosis-1",
+		TrustingPeriod:  24 * time.Hour,
+		UnbondingPeriod: 14 * 24 * time.Hour,
+		LatestHeight:    clienttypes.NewHeight(1, 100),
+		FrozenHeight:    clienttypes.NewHeight(1, 90),
+	}
+	substitute := func() *tmclient.ClientState {
+		return &tmclient.ClientState{
+			ChainId:                "osmosis-1",
+			TrustingPeriod:         48 * time.Hour,
+			UnbondingPeriod:        14 * 24 * time.Hour,
+			LatestHeight:           clienttypes.NewHeight(1, 500),
+			AllowUpdateAfterExpiry: true,
+		}
+	}
+
+	// The chain ID, trusting period, heights and the deprecated flags may differ.
+	require.NoError(t, checkSubstituteClient(subject, substitute(), now.Add(-time.Hour), now))
+
+	require.EqualError(t, checkSubstituteClient(subject, substitute(), now.Add(-49*time.Hour), now),
+		"substitute client is expired at 2022-06-30T23:00:00Z")
+
+	s := substitute()
+	s.UnbondingPeriod = 21 * 24 * time.Hour
+	require.EqualError(t, checkSubstituteClient(subject, s, now.Add(-time.Hour), now),
+		"parameters of the clients other than their chain ID, trusting period and heights differ")
+
+	s = substitute()
+	s.LatestHeight = clienttypes.NewHeight(1, 100)
+	require.EqualError(t, checkSubstituteClient(subject, s, now.Add(-time.Hour), now),
+		"latest height 1-100 of the substitute client is not above latest height 1-100 of the subject client")
+}
+
+func TestProposalIDFromEvents(t *testing.T) {
+	require.Equal(t, "42", proposalIDFromEvents([]provider.RelayerEvent{
+		{EventType: "message", Attributes: map[string]string{"module": "governance"}},
+		{EventType: "submit_proposal", Attributes: map[string]string{"proposal_id": "42"}},
+	}))
+	require.Empty(t, proposalIDFromEvents(nil))
+}
+
+func TestPathRepointClient(t *testing.T) {
+	p := &Path{
+		Src: &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0", ConnectionID: "connection-0"},
+		Dst: &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-3", ConnectionID: "connection-2"},
+	}
+
+	// The connection of the path is kept while it is pointed at the same client.
+	require.NoError(t, p.RepointClient("chain-b", "07-tendermint-3"))
+	require.Equal(t, "connection-0", p.Src.ConnectionID)
+
+	require.NoError(t, p.RepointClient("chain-b", "07-tendermint-7"))
+	require.Equal(t, &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"}, p.Src)
+	require.Equal(t, &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-7"}, p.Dst)
+
+	require.EqualError(t, p.RepointClient("chain-c", "07-tendermint-1"), "chain chain-c is not an end of the path")
+}
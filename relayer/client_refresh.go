@@ -0,0 +1,140 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+const (
+	// clientRefreshRetryDelay is how long to wait before checking a client again after failing to,
+	// and how often the clients due to be checked are checked.
+	clientRefreshRetryDelay = time.Minute
+
+	// clientRefreshMaxDelay bounds the time between two checks of a client, so that trusting period
+	// changes or updates made by other relayers are accounted for.
+	clientRefreshMaxDelay = 6 * time.Hour
+)
+
+// errClientNeverExpires is returned for the clients of the types without a trusting period, which do not expire.
+var errClientNeverExpires = errors.New("client does not expire")
+
+// refreshClient updates the client of c tracking counterparty if it is about to expire,
+// and returns how long to wait before checking it again, or false if the client does not expire
+// and is not to be checked again.
+func refreshClient(ctx context.Context, log *zap.Logger, c, counterparty *Chain, threshold float64) (time.Duration, bool) {
+	trustingPeriod, err := clientTrustingPeriod(ctx, c)
+	if errors.Is(err, errClientNeverExpires) {
+		log.Debug(
+			"Not keeping client from expiring",
+			zap.String("chain_id", c.ChainID()),
+			zap.String("client_id", c.ClientID()),
+			zap.Error(err),
+		)
+		return 0, false
+	}
+	if err != nil {
+		log.Warn(
+			"Failed to query client trusting period",
+			zap.String("chain_id", c.ChainID()),
+			zap.String("client_id", c.ClientID()),
+			zap.Error(err),
+		)
+		return clientRefreshRetryDelay, true
+	}
+	thresholdTime := time.Duration(float64(trustingPeriod) * threshold)
+
+	// AutoUpdateClient returns the time left until the client expires, or its trusting period once updated.
+	timeToExpiry, err := c.ChainProvider.AutoUpdateClient(ctx, counterparty.ChainProvider, thresholdTime, c.ClientID(), counterparty.ClientID())
+	if err != nil {
+		log.Warn(
+			"Failed to keep client from expiring",
+			zap.String("chain_id", c.ChainID()),
+			zap.String("client_id", c.ClientID()),
+			zap.Error(err),
+		)
+		return clientRefreshRetryDelay, true
+	}
+
+	delay := timeToExpiry - thresholdTime
+	switch {
+	case delay < clientRefreshRetryDelay:
+		return clientRefreshRetryDelay, true
+	case delay > clientRefreshMaxDelay:
+		return clientRefreshMaxDelay, true
+	}
+	return delay, true
+}
+
+// clientTrustingPeriod returns the trusting period of the client of c, tendermint or furyint,
+// or errClientNeverExpires for the clients of other types.
+func clientTrustingPeriod(ctx context.Context, c *Chain) (time.Duration, error) {
+	h, err := c.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	cs, err := c.ChainProvider.QueryClientState(ctx, h, c.ClientID())
+	if err != nil {
+		return 0, err
+	}
+	trustingPeriod, ok := provider.ClientTrustingPeriod(cs)
+	if !ok {
+		return 0, fmt.Errorf("client %s is of type %s: %w", c.ClientID(), cs.ClientType(), errClientNeverExpires)
+	}
+	return trustingPeriod, nil
+}
+
+// clientEnd identifies the client of one end of a path.
+type clientEnd struct {
+	path string
+	src  bool
+}
+
+// startClientRefresh keeps the clients of both ends of every path from expiring, independently of packet flow,
+// until ctx is done. A client is updated once the time left until it expires falls below threshold times its trusting
+// period. Each run of the task checks the clients due since the delay their previous check returned.
+func (s *supervisor) startClientRefresh(ctx context.Context, threshold float64) {
+	for _, name := range s.names {
+		r := s.runners[name]
+		s.log.Info(
+			"Keeping clients from expiring",
+			zap.String("path", r.name),
+			zap.String("src_chain_id", r.src.ChainID()),
+			zap.String("src_client_id", r.src.ClientID()),
+			zap.String("dst_chain_id", r.dst.ChainID()),
+			zap.String("dst_client_id", r.dst.ClientID()),
+			zap.Float64("threshold", threshold),
+		)
+	}
+
+	// due and neverExpire are only accessed by the task, whose runs never overlap.
+	var (
+		due         = make(map[clientEnd]time.Time)
+		neverExpire = make(map[clientEnd]bool)
+	)
+	s.maintenance.schedule(ctx, TaskClientKeepalive, clientRefreshRetryDelay, func(ctx context.Context) {
+		for _, name := range s.names {
+			r := s.runners[name]
+			pathCtx, log := provider.WithPathName(ctx, r.name), s.log.With(zap.String("path", r.name))
+			for _, end := range []clientEnd{{r.name, true}, {r.name, false}} {
+				if neverExpire[end] || time.Now().Before(due[end]) {
+					continue
+				}
+				c, counterparty := r.src, r.dst
+				if !end.src {
+					c, counterparty = r.dst, r.src
+				}
+				delay, ok := refreshClient(pathCtx, log, c, counterparty, threshold)
+				if !ok {
+					neverExpire[end] = true
+					continue
+				}
+				due[end] = time.Now().Add(delay)
+			}
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	furyintclient "github.com/cosmos/ibc-go/v3/modules/light-clients/01-furyint/types"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	localhost "github.com/cosmos/ibc-go/v3/modules/light-clients/09-localhost/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// expiringProvider serves a client state, and the time left until the client expires once kept from expiring.
+type expiringProvider struct {
+	provider.ChainProvider
+	client       ibcexported.ClientState
+	timeToExpiry time.Duration
+	err          error
+
+	// thresholds are the threshold times AutoUpdateClient was called with.
+	thresholds []time.Duration
+}
+
+func (p *expiringProvider) ChainId() string { return "chain-a" }
+
+func (p *expiringProvider) QueryLatestHeight(context.Context) (int64, error) { return 100, nil }
+
+func (p *expiringProvider) QueryClientState(context.Context, int64, string) (ibcexported.ClientState, error) {
+	if p.client == nil {
+		return nil, errors.New("client not found")
+	}
+	return p.client, nil
+}
+
+func (p *expiringProvider) AutoUpdateClient(_ context.Context, _ provider.ChainProvider, thresholdTime time.Duration, _, _ string) (time.Duration, error) {
+	p.thresholds = append(p.thresholds, thresholdTime)
+	return p.timeToExpiry, p.err
+}
+
+func TestRefreshClient(t *testing.T) {
+	ctx := context.Background()
+	counterparty := &Chain{Chainid: "chain-b", ChainProvider: &expiringProvider{}, PathEnd: &PathEnd{ClientID: "07-tendermint-0"}}
+	week := 7 * 24 * time.Hour
+
+	for _, tc := range []struct {
+		name         string
+		client       ibcexported.ClientState
+		timeToExpiry time.Duration
+		err          error
+		delay        time.Duration
+		threshold    time.Duration
+	}{
+		// The client is checked again once the time left falls to half its trusting period.
+		{"tendermint", &tmclient.ClientState{TrustingPeriod: 8 * time.Hour}, 6 * time.Hour, nil, 2 * time.Hour, 4 * time.Hour},
+		{"furyint", &furyintclient.ClientState{TrustingPeriod: 8 * time.Hour}, 5 * time.Hour, nil, time.Hour, 4 * time.Hour},
+		{"clamped to the max delay", &tmclient.ClientState{TrustingPeriod: 2 * week}, 2 * week, nil, clientRefreshMaxDelay, week},
+		{"clamped to the retry delay", &tmclient.ClientState{TrustingPeriod: 8 * time.Hour}, 4 * time.Hour, nil, clientRefreshRetryDelay, 4 * time.Hour},
+		{"update failed", &furyintclient.ClientState{TrustingPeriod: 8 * time.Hour}, 0, errors.New("tx failed"), clientRefreshRetryDelay, 4 * time.Hour},
+		{"client not found", nil, 0, nil, clientRefreshRetryDelay, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &expiringProvider{client: tc.client, timeToExpiry: tc.timeToExpiry, err: tc.err}
+			c := &Chain{Chainid: "chain-a", ChainProvider: p, PathEnd: &PathEnd{ClientID: "01-furyint-0"}}
+			delay, ok := refreshClient(ctx, zap.NewNop(), c, counterparty, 0.5)
+			require.True(t, ok)
+			require.Equal(t, tc.delay, delay)
+			if tc.threshold > 0 {
+				require.Equal(t, []time.Duration{tc.threshold}, p.thresholds)
+			} else {
+				require.Empty(t, p.thresholds)
+			}
+		})
+	}
+
+	// Clients of other types do not expire, and are left alone.
+	p := &expiringProvider{client: &localhost.ClientState{ChainId: "chain-a"}}
+	c := &Chain{Chainid: "chain-a", ChainProvider: p, PathEnd: &PathEnd{ClientID: "09-localhost"}}
+	_, ok := refreshClient(ctx, zap.NewNop(), c, counterparty, 0.5)
+	require.False(t, ok)
+	require.Empty(t, p.thresholds)
+}
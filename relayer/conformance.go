@@ -0,0 +1,566 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"go.uber.org/zap"
+)
+
+// Scenarios of the conformance suite, run in this order.
+const (
+	ConformanceClientUpdate    = "client-update"
+	ConformanceUnorderedPacket = "unordered-packet"
+	ConformanceAcknowledgement = "acknowledgement"
+	ConformanceTimeout         = "timeout"
+	ConformanceOrderedPackets  = "ordered-packets"
+	ConformanceFeePacket       = "fee-packet"
+)
+
+// ConformanceScenarios are all the scenarios of the conformance suite, in the order they are run.
+var ConformanceScenarios = []string{
+	ConformanceClientUpdate,
+	ConformanceUnorderedPacket,
+	ConformanceAcknowledgement,
+	ConformanceTimeout,
+	ConformanceOrderedPackets,
+	ConformanceFeePacket,
+}
+
+// Outcomes of a conformance scenario.
+const (
+	ConformancePassed  = "pass"
+	ConformanceFailed  = "fail"
+	ConformanceSkipped = "skip"
+)
+
+// conformancePollInterval is how often a scenario relays and checks whether the state it waits for is reached.
+const conformancePollInterval = 2 * time.Second
+
+// ConformanceOptions configure a run of the conformance suite.
+type ConformanceOptions struct {
+	// Scenarios to run, all of them if empty.
+	Scenarios []string
+
+	// Amount is transferred from the source chain of the path to the relayer address on the destination chain
+	// by each packet scenario.
+	Amount sdk.Coin
+
+	// Channel restricts the packet scenarios to this transfer channel of the source chain, if set.
+	Channel string
+
+	// TimeoutBlocks is the timeout height offset of the packet sent by the timeout scenario,
+	// which must not be relayed before it elapses.
+	TimeoutBlocks uint64
+
+	// WaitTimeout bounds how long each scenario waits for its packets to be relayed.
+	WaitTimeout time.Duration
+
+	MaxTxSize, MaxMsgLength uint64
+	Memo                    string
+}
+
+// ConformanceResult is the outcome of a scenario of the conformance suite.
+type ConformanceResult struct {
+	Scenario        string      `json:"scenario"`
+	Status          string      `json:"status"`
+	Channel         string      `json:"channel,omitempty"`
+	Sequence        uint64      `json:"sequence,omitempty"`
+	Detail          string      `json:"detail,omitempty"`
+	DurationSeconds float64     `json:"duration_seconds"`
+	Txs             []RelayedTx `json:"txs,omitempty"`
+}
+
+// ConformanceReport is the pass/fail report of a run of the conformance suite against a path.
+type ConformanceReport struct {
+	Path       string              `json:"path"`
+	SrcChainID string              `json:"src_chain_id"`
+	DstChainID string              `json:"dst_chain_id"`
+	StartedAt  time.Time           `json:"started_at"`
+	Results    []ConformanceResult `json:"results"`
+}
+
+// Failed returns the number of scenarios which failed.
+func (r ConformanceReport) Failed() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == ConformanceFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// conformanceSkip is returned by a scenario which cannot run against the path, e.g. for lack of a suitable channel.
+type conformanceSkip struct {
+	reason string
+}
+
+func (s conformanceSkip) Error() string {
+	return s.reason
+}
+
+func skipScenario(format string, args ...any) error {
+	return conformanceSkip{reason: fmt.Sprintf(format, args...)}
+}
+
+type conformanceScenario func(r *conformanceRun, ctx context.Context, res *ConformanceResult) error
+
+var conformanceScenarioFuncs = map[string]conformanceScenario{
+	ConformanceClientUpdate:    (*conformanceRun).clientUpdate,
+	ConformanceUnorderedPacket: (*conformanceRun).unorderedPacket,
+	ConformanceAcknowledgement: (*conformanceRun).acknowledgement,
+	ConformanceTimeout:         (*conformanceRun).timeout,
+	ConformanceOrderedPackets:  (*conformanceRun).orderedPackets,
+	ConformanceFeePacket:       (*conformanceRun).feePacket,
+}
+
+// ValidateConformanceScenarios returns an error if any of scenarios is not a scenario of the conformance suite.
+func ValidateConformanceScenarios(scenarios []string) error {
+	for _, s := range scenarios {
+		if _, ok := conformanceScenarioFuncs[s]; !ok {
+			return fmt.Errorf("unknown conformance scenario %q, expected one of %v", s, ConformanceScenarios)
+		}
+	}
+	return nil
+}
+
+type conformanceRun struct {
+	log      *zap.Logger
+	src, dst *Chain
+	opts     ConformanceOptions
+
+	// open channels of the connection of the path on src, sorted by ID
+	channels []*chantypes.IdentifiedChannel
+}
+
+// RunConformance runs the scenarios of the conformance suite against the path between src and dst,
+// relaying with the legacy processor logic, and returns the report of their outcomes.
+// Each scenario sends its own transactions on the live chains, so the path should link test chains.
+// An error is only returned if the suite could not run at all.
+func RunConformance(ctx context.Context, log *zap.Logger, pathName string, src, dst *Chain, opts ConformanceOptions) (ConformanceReport, error) {
+	report := ConformanceReport{
+		Path:       pathName,
+		SrcChainID: src.ChainID(),
+		DstChainID: dst.ChainID(),
+		StartedAt:  time.Now().UTC(),
+	}
+	scenarios := opts.Scenarios
+	if len(scenarios) == 0 {
+		scenarios = ConformanceScenarios
+	}
+	if err := ValidateConformanceScenarios(scenarios); err != nil {
+		return report, err
+	}
+
+	channels, err := queryChannelsOnConnection(ctx, src)
+	if err != nil {
+		return report, fmt.Errorf("error querying channels on chain{%s}@connection{%s}: %w", src.ChainID(), src.ConnectionID(), err)
+	}
+	r := &conformanceRun{log: log, src: src, dst: dst, opts: opts}
+	for _, c := range channels {
+		if c.State == chantypes.OPEN {
+			r.channels = append(r.channels, c)
+		}
+	}
+	sort.Slice(r.channels, func(i, j int) bool { return r.channels[i].ChannelId < r.channels[j].ChannelId })
+
+	// Scenarios run in suite order whatever the order they were selected in.
+	selected := make(map[string]bool, len(scenarios))
+	for _, s := range scenarios {
+		selected[s] = true
+	}
+	for _, s := range ConformanceScenarios {
+		if !selected[s] {
+			continue
+		}
+		report.Results = append(report.Results, r.runScenario(ctx, s))
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+	}
+	return report, nil
+}
+
+func (r *conformanceRun) runScenario(ctx context.Context, scenario string) ConformanceResult {
+	res := ConformanceResult{Scenario: scenario}
+	recorder := new(txRecorder)
+	start := time.Now()
+
+	r.log.Info("Running conformance scenario", zap.String("scenario", scenario))
+	err := conformanceScenarioFuncs[scenario](r, withTxRecorder(ctx, recorder), &res)
+
+	res.DurationSeconds = time.Since(start).Seconds()
+	res.Txs = append(res.Txs, recorder.relayedTxs()...)
+	var skip conformanceSkip
+	switch {
+	case errors.As(err, &skip):
+		res.Status = ConformanceSkipped
+		res.Detail = skip.reason
+	case err != nil:
+		res.Status = ConformanceFailed
+		res.Detail = err.Error()
+	default:
+		res.Status = ConformancePassed
+	}
+	r.log.Info("Conformance scenario done",
+		zap.String("scenario", scenario),
+		zap.String("status", res.Status),
+		zap.String("detail", res.Detail),
+	)
+	return res
+}
+
+// clientUpdate updates the clients of the path on both chains and checks that their consensus heights advanced.
+func (r *conformanceRun) clientUpdate(ctx context.Context, res *ConformanceResult) error {
+	srcBefore, dstBefore, err := r.clientHeights(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.src.UpdateClients(ctx, r.dst, r.opts.Memo); err != nil {
+		return fmt.Errorf("failed to update clients: %w", err)
+	}
+	srcAfter, dstAfter, err := r.clientHeights(ctx)
+	if err != nil {
+		return err
+	}
+	if !srcBefore.LT(srcAfter) {
+		return fmt.Errorf("client %s on %s not updated: consensus height stayed at %s", r.src.ClientID(), r.src.ChainID(), srcAfter)
+	}
+	if !dstBefore.LT(dstAfter) {
+		return fmt.Errorf("client %s on %s not updated: consensus height stayed at %s", r.dst.ClientID(), r.dst.ChainID(), dstAfter)
+	}
+	res.Detail = fmt.Sprintf("client %s on %s updated to %s, client %s on %s updated to %s",
+		r.src.ClientID(), r.src.ChainID(), srcAfter, r.dst.ClientID(), r.dst.ChainID(), dstAfter)
+	return nil
+}
+
+// clientHeights returns the latest consensus heights of the clients of the path on src and dst.
+func (r *conformanceRun) clientHeights(ctx context.Context) (src, dst clienttypes.Height, err error) {
+	srch, dsth, err := QueryLatestHeights(ctx, r.src, r.dst)
+	if err != nil {
+		return src, dst, err
+	}
+	srcCs, err := r.src.ChainProvider.QueryClientState(ctx, srch, r.src.ClientID())
+	if err != nil {
+		return src, dst, fmt.Errorf("failed to query client %s on %s: %w", r.src.ClientID(), r.src.ChainID(), err)
+	}
+	dstCs, err := r.dst.ChainProvider.QueryClientState(ctx, dsth, r.dst.ClientID())
+	if err != nil {
+		return src, dst, fmt.Errorf("failed to query client %s on %s: %w", r.dst.ClientID(), r.dst.ChainID(), err)
+	}
+	return MustGetHeight(srcCs.GetLatestHeight()), MustGetHeight(dstCs.GetLatestHeight()), nil
+}
+
+// unorderedPacket sends a transfer over an UNORDERED transfer channel and checks that it is received on dst.
+func (r *conformanceRun) unorderedPacket(ctx context.Context, res *ConformanceResult) error {
+	channel, err := r.transferChannel(false)
+	if err != nil {
+		return err
+	}
+	res.Channel = channel.ChannelId
+	sent, err := r.sendTransfer(ctx, res, channel, 0)
+	if err != nil {
+		return err
+	}
+	if err := r.waitUntil(ctx, "packet receipt on "+r.dst.ChainID(), r.flush(channel), r.received(channel, sent.Sequence)); err != nil {
+		return err
+	}
+	res.Detail = fmt.Sprintf("packet received on %s", r.dst.ChainID())
+	return nil
+}
+
+// acknowledgement sends a transfer over an UNORDERED transfer channel and checks that its acknowledgement
+// is relayed back, deleting the packet commitment on src.
+func (r *conformanceRun) acknowledgement(ctx context.Context, res *ConformanceResult) error {
+	channel, err := r.transferChannel(false)
+	if err != nil {
+		return err
+	}
+	res.Channel = channel.ChannelId
+	return r.relayAcknowledged(ctx, res, channel)
+}
+
+// relayAcknowledged sends a transfer over channel and waits for it to be received and acknowledged.
+func (r *conformanceRun) relayAcknowledged(ctx context.Context, res *ConformanceResult, channel *chantypes.IdentifiedChannel) error {
+	sent, err := r.sendTransfer(ctx, res, channel, 0)
+	if err != nil {
+		return err
+	}
+	if err := r.waitUntil(ctx, "packet receipt on "+r.dst.ChainID(), r.flush(channel), r.received(channel, sent.Sequence)); err != nil {
+		return err
+	}
+	if err := r.waitUntil(ctx, "acknowledgement on "+r.src.ChainID(), r.flush(channel), r.commitmentDeleted(channel, sent.Sequence)); err != nil {
+		return err
+	}
+	res.Detail = fmt.Sprintf("packet received on %s and acknowledged on %s", r.dst.ChainID(), r.src.ChainID())
+	return nil
+}
+
+// timeout sends a transfer with a short timeout height, lets it elapse without relaying the packet,
+// and checks that the timeout is relayed back, deleting the packet commitment on src.
+func (r *conformanceRun) timeout(ctx context.Context, res *ConformanceResult) error {
+	channel, err := r.transferChannel(false)
+	if err != nil {
+		return err
+	}
+	res.Channel = channel.ChannelId
+	if r.opts.TimeoutBlocks == 0 {
+		return errors.New("timeout blocks must be positive")
+	}
+	sent, err := r.sendTransfer(ctx, res, channel, r.opts.TimeoutBlocks)
+	if err != nil {
+		return err
+	}
+	if err := r.waitUntil(ctx, fmt.Sprintf("timeout height %s on %s", sent.TimeoutHeight, r.dst.ChainID()), nil, func(ctx context.Context) (bool, error) {
+		dsth, err := r.dst.ChainProvider.QueryLatestHeight(ctx)
+		if err != nil {
+			return false, err
+		}
+		return uint64(dsth) > sent.TimeoutHeight.RevisionHeight, nil
+	}); err != nil {
+		return err
+	}
+	relay := func(ctx context.Context) error {
+		return relayTimeouts(ctx, r.log, r.src, r.dst, r.opts.MaxTxSize, r.opts.MaxMsgLength, r.opts.Memo, false, channel)
+	}
+	if err := r.waitUntil(ctx, "timeout on "+r.src.ChainID(), relay, r.commitmentDeleted(channel, sent.Sequence)); err != nil {
+		return err
+	}
+	received, err := r.received(channel, sent.Sequence)(ctx)
+	if err != nil {
+		return err
+	}
+	if received {
+		return fmt.Errorf("packet timed out on %s was received on %s", r.src.ChainID(), r.dst.ChainID())
+	}
+	res.Detail = fmt.Sprintf("packet timed out at %s and its timeout relayed to %s", sent.TimeoutHeight, r.src.ChainID())
+	return nil
+}
+
+// orderedPackets relays the packets pending on an ORDERED channel of the path and checks that the counterparty
+// received them in order. The transfer application not supporting ORDERED channels, the suite cannot send packets
+// on them itself: they are sent by the application of the channel.
+func (r *conformanceRun) orderedPackets(ctx context.Context, res *ConformanceResult) error {
+	var channel *chantypes.IdentifiedChannel
+	for _, c := range r.channels {
+		if c.Ordering == chantypes.ORDERED {
+			channel = c
+			break
+		}
+	}
+	if channel == nil {
+		return skipScenario("no open ORDERED channel on connection %s of %s", r.src.ConnectionID(), r.src.ChainID())
+	}
+	res.Channel = channel.ChannelId
+
+	srch, dsth, err := QueryLatestHeights(ctx, r.src, r.dst)
+	if err != nil {
+		return err
+	}
+	sp := UnrelayedSequences(ctx, r.src, r.dst, srch-1, dsth-1, channel)
+	if len(sp.Src) == 0 {
+		return skipScenario("no packet pending relay on ORDERED channel %s, send packets on it to exercise ordered relaying", channel.ChannelId)
+	}
+	highest := sp.Src[0]
+	for _, seq := range sp.Src {
+		if seq > highest {
+			highest = seq
+		}
+	}
+	res.Sequence = highest
+
+	var next uint64
+	if err := r.waitUntil(ctx, fmt.Sprintf("in order receipt of packets up to %d on %s", highest, r.dst.ChainID()), r.flush(channel), func(ctx context.Context) (bool, error) {
+		dsth, err := r.dst.ChainProvider.QueryLatestHeight(ctx)
+		if err != nil {
+			return false, err
+		}
+		res, err := r.dst.ChainProvider.QueryNextSeqRecv(ctx, dsth, channel.Counterparty.ChannelId, channel.Counterparty.PortId)
+		if err != nil {
+			return false, err
+		}
+		next = res.NextSequenceReceive
+		return next > highest, nil
+	}); err != nil {
+		return err
+	}
+	res.Detail = fmt.Sprintf("%d packets received in order, next receive sequence on %s is %d", len(sp.Src), r.dst.ChainID(), next)
+	return nil
+}
+
+// feePacket registers the counterparty payees of the relayer on a fee-enabled transfer channel, then sends
+// a transfer over it and checks that it is received and acknowledged through the ICS-29 middleware.
+func (r *conformanceRun) feePacket(ctx context.Context, res *ConformanceResult) error {
+	channel, err := r.transferChannel(true)
+	if err != nil {
+		return err
+	}
+	res.Channel = channel.ChannelId
+
+	srcFilter := ChannelFilter{Rule: allowList, ChannelList: []string{channel.ChannelId}}
+	if err := registerCounterpartyPayee(ctx, r.log, r.src, r.dst, srcFilter, r.opts.Memo); err != nil {
+		return err
+	}
+	dstFilter := ChannelFilter{Rule: allowList, ChannelList: []string{channel.Counterparty.ChannelId}}
+	if err := registerCounterpartyPayee(ctx, r.log, r.dst, r.src, dstFilter, r.opts.Memo); err != nil {
+		return err
+	}
+	if err := r.relayAcknowledged(ctx, res, channel); err != nil {
+		return err
+	}
+	res.Detail = "counterparty payees registered, " + res.Detail
+	return nil
+}
+
+// transferChannel returns the open UNORDERED transfer channel the packet scenarios send transfers over:
+// the configured channel if any, else the first fee-enabled one if fee is set, else preferably one that is not.
+func (r *conformanceRun) transferChannel(fee bool) (*chantypes.IdentifiedChannel, error) {
+	var candidates []*chantypes.IdentifiedChannel
+	for _, c := range r.channels {
+		if c.Ordering != chantypes.UNORDERED || (r.opts.Channel != "" && c.ChannelId != r.opts.Channel) {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	if len(candidates) == 0 {
+		if r.opts.Channel != "" {
+			return nil, fmt.Errorf("channel %s is not an open UNORDERED channel of connection %s of %s", r.opts.Channel, r.src.ConnectionID(), r.src.ChainID())
+		}
+		return nil, skipScenario("no open UNORDERED channel on connection %s of %s", r.src.ConnectionID(), r.src.ChainID())
+	}
+	for _, c := range candidates {
+		if isFeeEnabledVersion(c.Version) == fee {
+			return c, nil
+		}
+	}
+	if fee {
+		return nil, skipScenario("no open fee-enabled channel on connection %s of %s", r.src.ConnectionID(), r.src.ChainID())
+	}
+	return candidates[0], nil
+}
+
+// sentPacket identifies a packet sent by the suite.
+type sentPacket struct {
+	Sequence      uint64
+	TimeoutHeight clienttypes.Height
+}
+
+// sendTransfer sends opts.Amount from src over channel to the relayer address on dst, with a timeout height
+// timeoutBlocks after the latest height of dst, or the default timeout if zero, and returns the packet sent.
+func (r *conformanceRun) sendTransfer(ctx context.Context, res *ConformanceResult, channel *chantypes.IdentifiedChannel, timeoutBlocks uint64) (sentPacket, error) {
+	if !r.opts.Amount.IsValid() || r.opts.Amount.IsZero() {
+		return sentPacket{}, fmt.Errorf("invalid transfer amount %q", r.opts.Amount)
+	}
+	dstAddr, err := r.dst.ChainProvider.Address()
+	if err != nil {
+		return sentPacket{}, fmt.Errorf("failed to get relayer address on %s: %w", r.dst.ChainID(), err)
+	}
+
+	recorder := new(txRecorder)
+	if err := r.src.SendTransferMsg(withTxRecorder(ctx, recorder), r.log, r.dst, r.opts.Amount, dstAddr, timeoutBlocks, 0, channel); err != nil {
+		return sentPacket{}, fmt.Errorf("failed to send transfer: %w", err)
+	}
+	txs := recorder.relayedTxs()
+	res.Txs = append(res.Txs, txs...)
+
+	for _, tx := range txs {
+		resp, err := r.src.ChainProvider.QueryTx(ctx, tx.TxHash)
+		if err != nil {
+			return sentPacket{}, fmt.Errorf("failed to query transfer tx %s: %w", tx.TxHash, err)
+		}
+		for _, e := range resp.Events {
+			if e.EventType != chantypes.EventTypeSendPacket || e.Attributes[chantypes.AttributeKeySrcChannel] != channel.ChannelId {
+				continue
+			}
+			seq, err := strconv.ParseUint(e.Attributes[chantypes.AttributeKeySequence], 10, 64)
+			if err != nil {
+				return sentPacket{}, fmt.Errorf("invalid sequence in send_packet event of tx %s: %w", tx.TxHash, err)
+			}
+			sent := sentPacket{Sequence: seq}
+			if h := e.Attributes[chantypes.AttributeKeyTimeoutHeight]; h != "" {
+				if sent.TimeoutHeight, err = clienttypes.ParseHeight(h); err != nil {
+					return sentPacket{}, fmt.Errorf("invalid timeout height in send_packet event of tx %s: %w", tx.TxHash, err)
+				}
+			}
+			res.Sequence = seq
+			return sent, nil
+		}
+	}
+	return sentPacket{}, fmt.Errorf("no send_packet event on channel %s in the transfer transactions", channel.ChannelId)
+}
+
+// flush returns the function relaying the pending packets and acknowledgements of channel in both directions.
+func (r *conformanceRun) flush(channel *chantypes.IdentifiedChannel) func(ctx context.Context) error {
+	filter := ChannelFilter{Rule: allowList, ChannelList: []string{channel.ChannelId}}
+	return func(ctx context.Context) error {
+		return Flush(ctx, r.log, r.src, r.dst, filter, r.opts.MaxTxSize, r.opts.MaxMsgLength, r.opts.Memo, false).Err
+	}
+}
+
+// received returns the check that the packet seq sent over channel was received on dst.
+func (r *conformanceRun) received(channel *chantypes.IdentifiedChannel, seq uint64) func(ctx context.Context) (bool, error) {
+	return func(ctx context.Context) (bool, error) {
+		dsth, err := r.dst.ChainProvider.QueryLatestHeight(ctx)
+		if err != nil {
+			return false, err
+		}
+		unreceived, err := r.dst.ChainProvider.QueryUnreceivedPackets(ctx, uint64(dsth), channel.Counterparty.ChannelId, channel.Counterparty.PortId, []uint64{seq})
+		if err != nil {
+			return false, err
+		}
+		return len(unreceived) == 0, nil
+	}
+}
+
+// commitmentDeleted returns the check that the commitment of the packet seq sent over channel was deleted on src,
+// once its acknowledgement or timeout was processed.
+func (r *conformanceRun) commitmentDeleted(channel *chantypes.IdentifiedChannel, seq uint64) func(ctx context.Context) (bool, error) {
+	return func(ctx context.Context) (bool, error) {
+		srch, err := r.src.ChainProvider.QueryLatestHeight(ctx)
+		if err != nil {
+			return false, err
+		}
+		pending, err := r.src.ChainProvider.QueryUnreceivedAcknowledgements(ctx, uint64(srch), channel.ChannelId, channel.PortId, []uint64{seq})
+		if err != nil {
+			return false, err
+		}
+		return len(pending) == 0, nil
+	}
+}
+
+// waitUntil runs relay, if non-nil, then done, every conformancePollInterval until done returns true,
+// for up to opts.WaitTimeout. Errors of relay and done are retried, the last one being returned on timeout.
+func (r *conformanceRun) waitUntil(ctx context.Context, what string, relay func(ctx context.Context) error, done func(ctx context.Context) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, r.opts.WaitTimeout)
+	defer cancel()
+	var lastErr error
+	for {
+		if relay != nil {
+			if err := relay(ctx); err != nil {
+				lastErr = err
+			}
+		}
+		ok, err := done(ctx)
+		if err != nil {
+			lastErr = err
+		} else if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%s not observed within %s: %w", what, r.opts.WaitTimeout, lastErr)
+			}
+			return fmt.Errorf("%s not observed within %s", what, r.opts.WaitTimeout)
+		case <-time.After(conformancePollInterval):
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// connectionChannelsProvider serves the channels of a connection.
+type connectionChannelsProvider struct {
+	provider.ChainProvider
+	chainID  string
+	channels []*chantypes.IdentifiedChannel
+}
+
+func (p *connectionChannelsProvider) ChainId() string { return p.chainID }
+
+func (p *connectionChannelsProvider) QueryLatestHeight(context.Context) (int64, error) {
+	return 100, nil
+}
+
+func (p *connectionChannelsProvider) QueryConnectionChannels(context.Context, int64, string) ([]*chantypes.IdentifiedChannel, error) {
+	return p.channels, nil
+}
+
+func testConformanceChannel(id string, order chantypes.Order, state chantypes.State, version string) *chantypes.IdentifiedChannel {
+	return &chantypes.IdentifiedChannel{
+		ChannelId:    id,
+		PortId:       "transfer",
+		State:        state,
+		Ordering:     order,
+		Version:      version,
+		Counterparty: chantypes.Counterparty{PortId: "transfer", ChannelId: id},
+	}
+}
+
+func TestValidateConformanceScenarios(t *testing.T) {
+	require.NoError(t, ValidateConformanceScenarios(nil))
+	require.NoError(t, ValidateConformanceScenarios(ConformanceScenarios))
+	require.ErrorContains(t, ValidateConformanceScenarios([]string{ConformanceTimeout, "misbehaviour"}), `"misbehaviour"`)
+}
+
+func TestConformanceTransferChannel(t *testing.T) {
+	feeVersion := `{"fee_version":"ics29-1","app_version":"ics20-1"}`
+	r := &conformanceRun{
+		src: &Chain{ChainProvider: &connectionChannelsProvider{chainID: "chain-a"}, PathEnd: &PathEnd{ConnectionID: "connection-0"}},
+		channels: []*chantypes.IdentifiedChannel{
+			testConformanceChannel("channel-0", chantypes.UNORDERED, chantypes.OPEN, feeVersion),
+			testConformanceChannel("channel-1", chantypes.ORDERED, chantypes.OPEN, "ics27-1"),
+			testConformanceChannel("channel-2", chantypes.UNORDERED, chantypes.OPEN, "ics20-1"),
+		},
+	}
+
+	// Plain packets prefer a channel without the fee middleware.
+	c, err := r.transferChannel(false)
+	require.NoError(t, err)
+	require.Equal(t, "channel-2", c.ChannelId)
+
+	c, err = r.transferChannel(true)
+	require.NoError(t, err)
+	require.Equal(t, "channel-0", c.ChannelId)
+
+	// The configured channel is used whatever its middleware.
+	r.opts.Channel = "channel-0"
+	c, err = r.transferChannel(false)
+	require.NoError(t, err)
+	require.Equal(t, "channel-0", c.ChannelId)
+
+	r.opts.Channel = "channel-2"
+	_, err = r.transferChannel(true)
+	var skip conformanceSkip
+	require.ErrorAs(t, err, &skip)
+
+	r.opts.Channel = "channel-1"
+	_, err = r.transferChannel(false)
+	require.ErrorContains(t, err, "not an open UNORDERED channel")
+}
+
+func TestRunConformanceSkips(t *testing.T) {
+	src := &Chain{
+		log: zap.NewNop(),
+		ChainProvider: &connectionChannelsProvider{chainID: "chain-a", channels: []*chantypes.IdentifiedChannel{
+			testConformanceChannel("channel-0", chantypes.UNORDERED, chantypes.OPEN, "ics20-1"),
+			testConformanceChannel("channel-1", chantypes.ORDERED, chantypes.CLOSED, "ics27-1"),
+		}},
+		PathEnd: &PathEnd{ChainID: "chain-a", ConnectionID: "connection-0"},
+	}
+	dst := &Chain{ChainProvider: &connectionChannelsProvider{chainID: "chain-b"}, PathEnd: &PathEnd{ChainID: "chain-b"}}
+
+	// Scenarios run in suite order, and are skipped without a channel to run on.
+	report, err := RunConformance(context.Background(), zap.NewNop(), "demo", src, dst, ConformanceOptions{
+		Scenarios: []string{ConformanceFeePacket, ConformanceOrderedPackets},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "chain-a", report.SrcChainID)
+	require.Len(t, report.Results, 2)
+	require.Equal(t, ConformanceOrderedPackets, report.Results[0].Scenario)
+	require.Equal(t, ConformanceSkipped, report.Results[0].Status)
+	require.Contains(t, report.Results[0].Detail, "no open ORDERED channel")
+	require.Equal(t, ConformanceFeePacket, report.Results[1].Scenario)
+	require.Equal(t, ConformanceSkipped, report.Results[1].Status)
+	require.Zero(t, report.Failed())
+
+	_, err = RunConformance(context.Background(), zap.NewNop(), "demo", src, dst, ConformanceOptions{Scenarios: []string{"misbehaviour"}})
+	require.Error(t, err)
+}
@@ -61,8 +61,8 @@ func (c *Chain) CreateOpenConnections(
 
 	return modified, processor.NewEventProcessor().
 		WithChainProcessors(
-			srcpathChain.chainProcessor(c.log),
-			dstpathChain.chainProcessor(c.log),
+			srcpathChain.chainProcessor(c.log, nil, nil, nil),
+			dstpathChain.chainProcessor(c.log, nil, nil, nil),
 		).
 		WithPathProcessors(pp).
 		WithInitialBlockHistory(0).
@@ -0,0 +1,127 @@
+package relayer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// ConsensusStateCount is the number of consensus states stored on a chain for a client of a relayed path.
+//
+// The ibc-go version of the chains relayed does not support pruning consensus states on demand,
+// tendermint clients only prune an expired consensus state when they are updated, so clients updated
+// more often than their trusting period accumulate consensus states, which are only reported.
+type ConsensusStateCount struct {
+	ChainID   string    `json:"chain_id"`
+	ClientID  string    `json:"client_id"`
+	Count     uint64    `json:"count"`
+	Excessive bool      `json:"excessive"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// consensusStateJanitor periodically counts the consensus states of the clients of the relayed paths,
+// and reports the clients holding more than maxStates.
+type consensusStateJanitor struct {
+	log       *zap.Logger
+	metrics   *processor.PrometheusMetrics
+	maxStates uint64
+
+	mu     sync.Mutex
+	counts map[string]ConsensusStateCount
+}
+
+func newConsensusStateJanitor(log *zap.Logger, metrics *processor.PrometheusMetrics, maxStates uint64) *consensusStateJanitor {
+	return &consensusStateJanitor{
+		log:       log,
+		metrics:   metrics,
+		maxStates: maxStates,
+		counts:    make(map[string]ConsensusStateCount),
+	}
+}
+
+// start counts the consensus states of the clients of chains at the given interval, until ctx is done.
+// Each chain must carry the path end of the client to count.
+func (j *consensusStateJanitor) start(ctx context.Context, sched *scheduler, interval time.Duration, chains []*Chain) {
+	sched.schedule(ctx, TaskConsensusStates, interval, func(ctx context.Context) {
+		for _, c := range chains {
+			j.check(ctx, c)
+		}
+	})
+}
+
+func (j *consensusStateJanitor) check(ctx context.Context, c *Chain) {
+	count, err := c.ChainProvider.QueryConsensusStateCount(ctx, c.ClientID())
+	if err != nil {
+		j.log.Warn(
+			"Failed to count client consensus states",
+			zap.String("chain_id", c.ChainID()),
+			zap.String("client_id", c.ClientID()),
+			zap.Error(err),
+		)
+		return
+	}
+	j.record(c.ChainID(), c.ClientID(), count, time.Now().UTC())
+}
+
+func (j *consensusStateJanitor) record(chainID, clientID string, count uint64, now time.Time) {
+	j.metrics.SetClientConsensusStates(chainID, clientID, count)
+
+	excessive := j.maxStates > 0 && count > j.maxStates
+	if excessive {
+		j.log.Warn(
+			"Client holds an excessive number of consensus states",
+			zap.String("chain_id", chainID),
+			zap.String("client_id", clientID),
+			zap.Uint64("consensus_states", count),
+			zap.Uint64("max_consensus_states", j.maxStates),
+		)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.counts[chainID+"/"+clientID] = ConsensusStateCount{
+		ChainID:   chainID,
+		ClientID:  clientID,
+		Count:     count,
+		Excessive: excessive,
+		CheckedAt: now,
+	}
+}
+
+// snapshot returns the last counts of every client, by chain and client ID.
+func (j *consensusStateJanitor) snapshot() []ConsensusStateCount {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	counts := make([]ConsensusStateCount, 0, len(j.counts))
+	for _, c := range j.counts {
+		counts = append(counts, c)
+	}
+	sort.Slice(counts, func(i, k int) bool {
+		if counts[i].ChainID != counts[k].ChainID {
+			return counts[i].ChainID < counts[k].ChainID
+		}
+		return counts[i].ClientID < counts[k].ClientID
+	})
+	return counts
+}
+
+// clientEnds returns both ends of every path, once per client.
+func (s *supervisor) clientEnds() []*Chain {
+	var ends []*Chain
+	seen := make(map[string]bool)
+	for _, name := range s.names {
+		r := s.runners[name]
+		for _, c := range []*Chain{r.src, r.dst} {
+			k := c.PathEnd.ChainID + "/" + c.ClientID()
+			if !seen[k] {
+				seen[k] = true
+				ends = append(ends, c)
+			}
+		}
+	}
+	return ends
+}
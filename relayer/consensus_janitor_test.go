@@ -0,0 +1,50 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConsensusStateJanitorRecord(t *testing.T) {
+	j := newConsensusStateJanitor(zap.NewNop(), nil, 100)
+	now := time.Now().UTC()
+
+	j.record("chain-b", "07-tendermint-0", 10, now)
+	j.record("chain-a", "07-tendermint-1", 101, now)
+	j.record("chain-a", "07-tendermint-0", 100, now)
+
+	require.Equal(t, []ConsensusStateCount{
+		{ChainID: "chain-a", ClientID: "07-tendermint-0", Count: 100, CheckedAt: now},
+		{ChainID: "chain-a", ClientID: "07-tendermint-1", Count: 101, Excessive: true, CheckedAt: now},
+		{ChainID: "chain-b", ClientID: "07-tendermint-0", Count: 10, CheckedAt: now},
+	}, j.snapshot())
+
+	// A later count replaces the previous one.
+	j.record("chain-a", "07-tendermint-1", 50, now)
+	require.False(t, j.snapshot()[1].Excessive)
+}
+
+func TestSupervisorClientEnds(t *testing.T) {
+	chains := map[string]*Chain{
+		"chain-a": {Chainid: "chain-a"},
+		"chain-b": {Chainid: "chain-b"},
+	}
+	end := func(chainID, clientID, connID string) *PathEnd {
+		return &PathEnd{ChainID: chainID, ClientID: clientID, ConnectionID: connID}
+	}
+	paths := []NamedPath{
+		{Name: "a-b", Path: &Path{Src: end("chain-a", "07-tendermint-0", "connection-0"), Dst: end("chain-b", "07-tendermint-0", "connection-0")}},
+		{Name: "a-b-2", Path: &Path{Src: end("chain-a", "07-tendermint-0", "connection-1"), Dst: end("chain-b", "07-tendermint-1", "connection-1")}},
+	}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+
+	var clients []string
+	for _, c := range s.clientEnds() {
+		clients = append(clients, c.PathEnd.ChainID+"/"+c.ClientID())
+	}
+	require.Equal(t, []string{"chain-a/07-tendermint-0", "chain-b/07-tendermint-0", "chain-b/07-tendermint-1"}, clients)
+}
@@ -0,0 +1,183 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	transfertypes "github.com/cosmos/ibc-go/v3/modules/apps/transfer/types"
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/provider/evm"
+)
+
+// registerDashboardHandlers exposes the state shown by the dashboard beyond the status API,
+// queried from the chains on every request.
+//
+//	GET /balances lists the balance of the relayer wallet on every chain.
+//	GET /clients  lists when each client of each path, or of the path of the path query parameter, expires unless updated.
+//	GET /denoms   lists the IBC denoms minted on the chains of each path, or of the path of the path query parameter,
+//	              by the transfers received over the channels relayed.
+func registerDashboardHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/balances", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		res := []admin.WalletBalance{}
+		for _, c := range s.chains() {
+			res = append(res, walletBalance(req.Context(), c))
+		}
+		admin.WriteJSON(w, http.StatusOK, res)
+	})
+
+	srv.HandleFunc("/clients", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		runners, err := s.runnersOf(req)
+		if err != nil {
+			admin.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		res := []admin.ClientExpiry{}
+		for _, r := range runners {
+			res = append(res, clientExpiry(req.Context(), r.name, r.src), clientExpiry(req.Context(), r.name, r.dst))
+		}
+		admin.WriteJSON(w, http.StatusOK, res)
+	})
+
+	srv.HandleFunc("/denoms", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		runners, err := s.runnersOf(req)
+		if err != nil {
+			admin.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		res, err := mintedDenoms(req.Context(), runners)
+		if err != nil {
+			admin.WriteError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		admin.WriteJSON(w, http.StatusOK, res)
+	})
+}
+
+// walletBalance returns the balance of the wallet of the relayer on c.
+func walletBalance(ctx context.Context, c *Chain) admin.WalletBalance {
+	b := admin.WalletBalance{ChainID: c.ChainID(), Balance: []admin.Coin{}}
+	addr, err := c.ChainProvider.Address()
+	if err != nil {
+		b.Error = err.Error()
+		return b
+	}
+	b.Address = addr
+	coins, err := c.ChainProvider.QueryBalance(ctx, c.ChainProvider.Key())
+	if err != nil {
+		b.Error = err.Error()
+		return b
+	}
+	for _, coin := range coins {
+		b.Balance = append(b.Balance, admin.Coin{Denom: coin.Denom, Amount: coin.Amount.String()})
+	}
+	return b
+}
+
+// clientExpiry returns when the client of c on the path pathName expires unless it is updated.
+func clientExpiry(ctx context.Context, pathName string, c *Chain) admin.ClientExpiry {
+	e := admin.ClientExpiry{Path: pathName, ChainID: c.ChainID(), ClientID: c.ClientID()}
+	tmcs, latestTimestamp, err := latestTendermintClient(ctx, c)
+	if err != nil {
+		e.Error = err.Error()
+		return e
+	}
+	expiresAt := latestTimestamp.Add(tmcs.TrustingPeriod).UTC()
+	e.TrustingPeriod = tmcs.TrustingPeriod.String()
+	e.LatestHeight = tmcs.LatestHeight.String()
+	e.ExpiresAt = &expiresAt
+	e.Reason = clientRepairReason(tmcs, latestTimestamp, time.Now())
+	return e
+}
+
+// relayedChannelEnd is an end of a relayed channel, identified by its chain and its port and channel IDs on the chain.
+type relayedChannelEnd struct {
+	chainID, portID, channelID string
+}
+
+// mintedDenoms returns the IBC denoms minted on the chains of the paths of runners by the ICS-20 transfers received
+// over their open channels, querying the denom traces of each chain once, whichever number of paths relay it.
+func mintedDenoms(ctx context.Context, runners []*pathRunner) ([]admin.IBCDenom, error) {
+	paths := make(map[relayedChannelEnd]string)
+	chains := make(map[string]*Chain)
+	var chainIDs []string
+	for _, r := range runners {
+		channels, err := r.openChannels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range channels {
+			paths[relayedChannelEnd{r.src.ChainID(), c.channel.PortId, c.channel.ChannelId}] = r.name
+			paths[relayedChannelEnd{r.dst.ChainID(), c.channel.Counterparty.PortId, c.channel.Counterparty.ChannelId}] = r.name
+		}
+		for _, c := range []*Chain{r.src, r.dst} {
+			if _, ok := chains[c.ChainID()]; !ok {
+				chains[c.ChainID()] = c
+				chainIDs = append(chainIDs, c.ChainID())
+			}
+		}
+	}
+	sort.Strings(chainIDs)
+
+	res := []admin.IBCDenom{}
+	for _, chainID := range chainIDs {
+		c := chains[chainID]
+		h, err := c.ChainProvider.QueryLatestHeight(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query latest height of chain %s: %w", chainID, err)
+		}
+		traces, err := c.ChainProvider.QueryDenomTraces(ctx, 0, 0, h)
+		if errors.Is(err, evm.ErrUnsupported) {
+			// The vouchers of EVM chains are held by their transfer application, not queried from the IBC handler.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to query denom traces of chain %s: %w", chainID, err)
+		}
+		res = append(res, denomsMintedOver(chainID, traces, paths)...)
+	}
+	return res, nil
+}
+
+// denomsMintedOver returns the denoms of traces on chainID minted by the transfers received over one of the channel ends
+// of paths, by denom. A voucher is minted over the channel end of the first hop of its trace, whatever the hops before.
+func denomsMintedOver(chainID string, traces []transfertypes.DenomTrace, paths map[relayedChannelEnd]string) []admin.IBCDenom {
+	var res []admin.IBCDenom
+	for _, t := range traces {
+		hops := strings.SplitN(t.Path, "/", 3)
+		if len(hops) < 2 {
+			continue
+		}
+		name, ok := paths[relayedChannelEnd{chainID, hops[0], hops[1]}]
+		if !ok {
+			continue
+		}
+		res = append(res, admin.IBCDenom{
+			Path:      name,
+			ChainID:   chainID,
+			PortID:    hops[0],
+			ChannelID: hops[1],
+			Denom:     t.IBCDenom(),
+			BaseDenom: t.BaseDenom,
+			Trace:     t.Path,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Denom < res[j].Denom })
+	return res
+}
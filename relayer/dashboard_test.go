@@ -0,0 +1,35 @@
+package relayer
+
+import (
+	"testing"
+
+	transfertypes "github.com/cosmos/ibc-go/v3/modules/apps/transfer/types"
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDenomsMintedOver(t *testing.T) {
+	paths := map[relayedChannelEnd]string{
+		{"hub", "transfer", "channel-0"}:     "hub-rollapp",
+		{"rollapp", "transfer", "channel-3"}: "hub-rollapp",
+	}
+	traces := []transfertypes.DenomTrace{
+		{Path: "transfer/channel-0", BaseDenom: "urax"},
+		{Path: "transfer/channel-0/transfer/channel-141", BaseDenom: "uosmo"},
+		// Vouchers minted over channels of other relayers, and native denoms, are left out.
+		{Path: "transfer/channel-1", BaseDenom: "uatom"},
+		{Path: "transfer/channel-9/transfer/channel-0", BaseDenom: "uusdc"},
+		{BaseDenom: "udym"},
+	}
+
+	rax := transfertypes.DenomTrace{Path: "transfer/channel-0", BaseDenom: "urax"}
+	osmo := transfertypes.DenomTrace{Path: "transfer/channel-0/transfer/channel-141", BaseDenom: "uosmo"}
+	expected := []admin.IBCDenom{
+		{Path: "hub-rollapp", ChainID: "hub", PortID: "transfer", ChannelID: "channel-0", Denom: rax.IBCDenom(), BaseDenom: "urax", Trace: rax.Path},
+		{Path: "hub-rollapp", ChainID: "hub", PortID: "transfer", ChannelID: "channel-0", Denom: osmo.IBCDenom(), BaseDenom: "uosmo", Trace: osmo.Path},
+	}
+	require.ElementsMatch(t, expected, denomsMintedOver("hub", traces, paths))
+
+	// Channel ends are matched on the chain the vouchers were minted on.
+	require.Empty(t, denomsMintedOver("rollapp", traces, paths))
+}
@@ -0,0 +1,66 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+)
+
+// decisionCaptureTimeout bounds how long a capture waits for the path processor to next process its messages.
+const decisionCaptureTimeout = time.Minute
+
+// registerDecisionHandlers exposes the packet flow decisions of the paths through the admin API,
+// so that a single decision can be captured into a bundle attached to a bug report.
+//
+//	GET /decision?path=&chain_id=&port_id=&channel_id= captures the next decision on the packets sent over a channel.
+func registerDecisionHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/decision", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		q := req.URL.Query()
+		r, ok := s.runners[q.Get("path")]
+		if !ok {
+			admin.WriteError(w, http.StatusNotFound, fmt.Errorf("path %s not found", q.Get("path")))
+			return
+		}
+		if s.ProcessorType(r) != ProcessorEvents {
+			admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("path %s is relayed with the %s processor, decisions are only captured with the %s processor", r.name, s.ProcessorType(r), ProcessorEvents))
+			return
+		}
+
+		chainID, portID, channelID := q.Get("chain_id"), q.Get("port_id"), q.Get("channel_id")
+		if chainID == "" {
+			chainID = r.src.ChainID()
+		}
+		if portID == "" {
+			portID = "transfer"
+		}
+		if chainID != r.src.ChainID() && chainID != r.dst.ChainID() {
+			admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("chain %s is not a chain of path %s", chainID, r.name))
+			return
+		}
+		if channelID == "" {
+			admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("channel_id is required"))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), decisionCaptureTimeout)
+		defer cancel()
+		b, err := r.decisions.Capture(ctx, chainID, portID, channelID)
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			admin.WriteError(w, http.StatusServiceUnavailable, err)
+			return
+		case err != nil:
+			admin.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		admin.WriteJSON(w, http.StatusOK, b)
+	})
+}
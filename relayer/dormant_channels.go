@@ -0,0 +1,251 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// dormantCheckInterval is how often the events of the chains are searched for traffic on dormant channels.
+	dormantCheckInterval = 30 * time.Second
+
+	// dormantSearchMarginBlocks is how many blocks before the height last searched are searched again,
+	// covering the blocks between the last pass of a channel going dormant and the search.
+	dormantSearchMarginBlocks = 20
+
+	// dormantSearchMaxPages bounds the pages of transactions searched for each event on each check.
+	dormantSearchMaxPages = 10
+	dormantSearchPageSize = 100
+)
+
+// DormantChannel is a channel of a path relayed by the legacy processor which is set dormant.
+type DormantChannel struct {
+	Path      string    `json:"path"`
+	ChainID   string    `json:"chain_id"`
+	PortID    string    `json:"port_id"`
+	ChannelID string    `json:"channel_id"`
+	Since     time.Time `json:"since"`
+}
+
+// dormantChannels holds the channels of a path relayed by the legacy processor which had no packet
+// or acknowledgement to relay for after. Dormant channels are not relayed, sparing the queries of their
+// packets and acknowledgements, until the events of either chain show new packets or acknowledgements on them.
+// It is safe for concurrent use, and a nil *dormantChannels never sets channels dormant.
+type dormantChannels struct {
+	after time.Duration
+
+	mu      sync.Mutex
+	dormant map[string]DormantChannel
+
+	// searched are the heights of the src and dst chains up to which events were last searched,
+	// only accessed by the main loop of the path.
+	srcSearched, dstSearched int64
+}
+
+func newDormantChannels(after time.Duration) *dormantChannels {
+	if after <= 0 {
+		return nil
+	}
+	return &dormantChannels{after: after, dormant: make(map[string]DormantChannel)}
+}
+
+// checks returns the ticker channel of the checks of dormant channels, nil if channels are never set dormant.
+func (d *dormantChannels) checks() (<-chan time.Time, func()) {
+	if d == nil {
+		return nil, func() {}
+	}
+	t := time.NewTicker(dormantCheckInterval)
+	return t.C, t.Stop
+}
+
+// idle reports whether c had nothing to relay for long enough to be set dormant.
+// Channels are idle from their first pass.
+func (d *dormantChannels) idle(c *ActiveChannel) bool {
+	if d == nil {
+		return false
+	}
+	if atomic.LoadInt64(&c.lastActivity) == 0 {
+		c.recordActivity()
+		return false
+	}
+	return time.Since(c.lastActivityTime()) >= d.after
+}
+
+// sleep sets c dormant.
+func (d *dormantChannels) sleep(c *ActiveChannel) {
+	c.dormant = true
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dormant[c.channel.ChannelId] = DormantChannel{PortID: c.channel.PortId, ChannelID: c.channel.ChannelId, Since: time.Now()}
+}
+
+// wake reactivates the dormant channel c.
+func (d *dormantChannels) wake(c *ActiveChannel) {
+	c.dormant = false
+	c.recordActivity()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.dormant, c.channel.ChannelId)
+}
+
+func (d *dormantChannels) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.dormant)
+}
+
+// snapshot returns the dormant channels of the path whose src chain is chainID, in channel ID order.
+func (d *dormantChannels) snapshot(path, chainID string) []DormantChannel {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	res := make([]DormantChannel, 0, len(d.dormant))
+	for _, c := range d.dormant {
+		c.Path, c.ChainID = path, chainID
+		res = append(res, c)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ChannelID < res[j].ChannelID })
+	return res
+}
+
+// wakeChannels searches the events of both chains since they were last searched for packets sent
+// or acknowledgements written on the dormant channels among channels of conns, waking those with traffic.
+// The latest heights are tracked while no channel is dormant, so that channels going dormant are searched
+// from around their last pass.
+func (d *dormantChannels) wakeChannels(ctx context.Context, log *zap.Logger, src, dst *Chain, conns []relayedConnection, channels map[string]*ActiveChannel) {
+	if d == nil {
+		return
+	}
+	srch, dsth, err := QueryLatestHeights(ctx, src, dst)
+	if err != nil {
+		log.Warn("Failed to query latest heights to check dormant channels", zap.Error(err))
+		return
+	}
+	if d.srcSearched == 0 || d.count() == 0 {
+		d.srcSearched, d.dstSearched = srch, dsth
+	}
+	if d.count() == 0 {
+		return
+	}
+
+	// Channels are identified by their ID on src, which is the source of the packets sent on src
+	// and the destination of those sent on dst, and conversely for acknowledgements.
+	searches := []struct {
+		chain        *Chain
+		from, to     int64
+		eventType    string
+		channelIDKey string
+	}{
+		{src, d.srcSearched, srch, chantypes.EventTypeSendPacket, chantypes.AttributeKeySrcChannel},
+		{src, d.srcSearched, srch, chantypes.EventTypeWriteAck, chantypes.AttributeKeyDstChannel},
+		{dst, d.dstSearched, dsth, chantypes.EventTypeSendPacket, chantypes.AttributeKeyDstChannel},
+		{dst, d.dstSearched, dsth, chantypes.EventTypeWriteAck, chantypes.AttributeKeySrcChannel},
+	}
+	active := make(map[string]bool)
+	for _, s := range searches {
+		for _, conn := range conns {
+			connID := conn.srcID
+			if s.chain == dst {
+				connID = conn.dstID
+			}
+			if err := searchChannelTraffic(ctx, s.chain.withConnectionID(connID), s.from, s.to, s.eventType, s.channelIDKey, active); err != nil {
+				log.Warn(
+					"Failed to search events for traffic on dormant channels",
+					zap.String("chain_id", s.chain.ChainID()),
+					zap.String("conn_id", connID),
+					zap.String("event", s.eventType),
+					zap.Error(err),
+				)
+				return
+			}
+		}
+	}
+	d.srcSearched, d.dstSearched = srch, dsth
+
+	for id := range active {
+		c, ok := channels[id]
+		if !ok || !c.dormant {
+			continue
+		}
+		d.wake(c)
+		log.Info(
+			"Waking dormant channel",
+			zap.String("src_chain_id", src.ChainID()),
+			zap.String("src_channel_id", id),
+			zap.String("src_port_id", c.channel.PortId),
+		)
+	}
+}
+
+// searchChannelTraffic adds to active the IDs found under channelIDKey in the events of eventType emitted
+// for the connection of c in the blocks after from, less the search margin, up to to.
+func searchChannelTraffic(ctx context.Context, c *Chain, from, to int64, eventType, channelIDKey string, active map[string]bool) error {
+	from -= dormantSearchMarginBlocks
+	if from < 0 {
+		from = 0
+	}
+	query := []string{
+		fmt.Sprintf("%s.%s='%s'", eventType, chantypes.AttributeKeyConnection, c.ConnectionID()),
+		fmt.Sprintf("tx.height>%d", from),
+		fmt.Sprintf("tx.height<=%d", to),
+	}
+	for page := 1; page <= dormantSearchMaxPages; page++ {
+		txs, err := c.ChainProvider.QueryTxs(ctx, page, dormantSearchPageSize, query)
+		if err != nil {
+			return err
+		}
+		for _, tx := range txs {
+			for _, ev := range tx.Events {
+				if ev.EventType == eventType && ev.Attributes[chantypes.AttributeKeyConnection] == c.ConnectionID() {
+					active[ev.Attributes[channelIDKey]] = true
+				}
+			}
+		}
+		if len(txs) < dormantSearchPageSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// recordActivity records that the channel had packets or acknowledgements to relay.
+func (c *ActiveChannel) recordActivity() {
+	atomic.StoreInt64(&c.lastActivity, time.Now().UnixNano())
+}
+
+func (c *ActiveChannel) lastActivityTime() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.lastActivity))
+}
+
+type channelActivityKey struct{}
+
+// withChannelActivity records the packets and acknowledgements relayed with ctx as activity of c.
+func withChannelActivity(ctx context.Context, c *ActiveChannel) context.Context {
+	return context.WithValue(ctx, channelActivityKey{}, c)
+}
+
+// recordChannelActivity records activity of the channel relayed with ctx, if any.
+func recordChannelActivity(ctx context.Context) {
+	if c, ok := ctx.Value(channelActivityKey{}).(*ActiveChannel); ok {
+		c.recordActivity()
+	}
+}
+
+// dormantChannelList reports the dormant channels of the paths relayed by the legacy processor.
+func (s *supervisor) dormantChannelList() []DormantChannel {
+	res := []DormantChannel{}
+	for _, name := range s.names {
+		r := s.runners[name]
+		res = append(res, r.dormant.snapshot(r.name, r.src.ChainID())...)
+	}
+	return res
+}
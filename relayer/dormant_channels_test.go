@@ -0,0 +1,58 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDormantChannelsDisabled(t *testing.T) {
+	d := newDormantChannels(0)
+	require.Nil(t, d)
+
+	c := schedulerTestChannels("channel-0")["channel-0"]
+	c.lastActivity = time.Now().Add(-time.Hour).UnixNano()
+	require.False(t, d.idle(c))
+	require.Nil(t, d.snapshot("path", "chain-a"))
+}
+
+func TestDormantChannelsSleepAndWake(t *testing.T) {
+	d := newDormantChannels(time.Minute)
+	channels := schedulerTestChannels("channel-0", "channel-1")
+	c0, c1 := channels["channel-0"], channels["channel-1"]
+
+	// Channels are idle from their first pass.
+	require.False(t, d.idle(c0))
+	require.NotZero(t, c0.lastActivity)
+	require.False(t, d.idle(c0))
+
+	// Relaying records the activity of the channel relayed with the context.
+	c0.lastActivity = time.Now().Add(-2 * time.Minute).UnixNano()
+	c1.lastActivity = c0.lastActivity
+	recordChannelActivity(withChannelActivity(context.Background(), c1))
+	recordChannelActivity(context.Background())
+	require.True(t, d.idle(c0))
+	require.False(t, d.idle(c1))
+
+	d.sleep(c0)
+	require.True(t, c0.dormant)
+	snapshot := d.snapshot("path", "chain-a")
+	require.Len(t, snapshot, 1)
+	require.Equal(t, "path", snapshot[0].Path)
+	require.Equal(t, "chain-a", snapshot[0].ChainID)
+	require.Equal(t, "channel-0", snapshot[0].ChannelID)
+
+	// Dormant channels are not relayed.
+	cs := newChannelScheduler(0)
+	cs.enqueue(channels)
+	require.Equal(t, []string{"channel-1"}, nextChannelIDs(cs))
+
+	d.wake(c0)
+	require.False(t, c0.dormant)
+	require.False(t, d.idle(c0))
+	require.Empty(t, d.snapshot("path", "chain-a"))
+	cs.enqueue(channels)
+	require.Equal(t, []string{"channel-0"}, nextChannelIDs(cs))
+}
@@ -0,0 +1,170 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v3/modules/apps/transfer/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// EscrowBalance is the balance last observed in the ICS-20 escrow account of an end of a relayed transfer channel.
+//
+// The escrow account of a channel end holds the native tokens of its chain sent over the channel,
+// so for each denom it must hold as much as the supply of the matching voucher on the counterparty chain.
+// A balance dropping without transfers coming back over the channel points to a supply inconsistency or an exploit.
+type EscrowBalance struct {
+	Path      string    `json:"path"`
+	ChainID   string    `json:"chain_id"`
+	ChannelID string    `json:"channel_id"`
+	PortID    string    `json:"port_id"`
+	Address   string    `json:"address"`
+	Balance   sdk.Coins `json:"balance"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type addressEncoder interface {
+	EncodeBech32AccAddr(addr sdk.AccAddress) (string, error)
+}
+
+// escrowMonitor periodically queries the escrow account balances of the transfer channels relayed on every path.
+type escrowMonitor struct {
+	log     *zap.Logger
+	metrics *processor.PrometheusMetrics
+
+	mu       sync.Mutex
+	balances map[string]EscrowBalance
+}
+
+func newEscrowMonitor(log *zap.Logger, metrics *processor.PrometheusMetrics) *escrowMonitor {
+	return &escrowMonitor{
+		log:      log,
+		metrics:  metrics,
+		balances: make(map[string]EscrowBalance),
+	}
+}
+
+func (em *escrowMonitor) check(ctx context.Context, r *pathRunner) {
+	channels, err := r.openChannels(ctx)
+	if err != nil {
+		em.log.Warn(
+			"Failed to query channels to check escrow balances",
+			zap.String("path", r.name),
+			zap.Error(err),
+		)
+		return
+	}
+	for _, c := range channels {
+		if c.channel.PortId != transfertypes.PortID {
+			continue
+		}
+		em.checkEnd(ctx, r.name, r.src, c.channel.PortId, c.channel.ChannelId)
+		em.checkEnd(ctx, r.name, r.dst, c.channel.Counterparty.PortId, c.channel.Counterparty.ChannelId)
+	}
+}
+
+func (em *escrowMonitor) checkEnd(ctx context.Context, pathName string, c *Chain, portID, channelID string) {
+	addr, err := escrowAddress(c, portID, channelID)
+	if err != nil {
+		em.log.Warn(
+			"Failed to derive escrow address",
+			zap.String("path", pathName),
+			zap.String("chain_id", c.ChainID()),
+			zap.String("channel_id", channelID),
+			zap.Error(err),
+		)
+		return
+	}
+	balance, err := c.ChainProvider.QueryBalanceWithAddress(ctx, addr)
+	if err != nil {
+		em.log.Warn(
+			"Failed to query escrow balance",
+			zap.String("path", pathName),
+			zap.String("chain_id", c.ChainID()),
+			zap.String("channel_id", channelID),
+			zap.String("address", addr),
+			zap.Error(err),
+		)
+		return
+	}
+	em.record(EscrowBalance{
+		Path:      pathName,
+		ChainID:   c.ChainID(),
+		ChannelID: channelID,
+		PortID:    portID,
+		Address:   addr,
+		Balance:   balance,
+		CheckedAt: time.Now().UTC(),
+	})
+}
+
+// escrowAddress returns the address of the escrow account of the channel end on c, in the address format of c.
+func escrowAddress(c *Chain, portID, channelID string) (string, error) {
+	enc, ok := c.ChainProvider.(addressEncoder)
+	if !ok {
+		return "", fmt.Errorf("chain %s does not support encoding addresses", c.ChainID())
+	}
+	return enc.EncodeBech32AccAddr(transfertypes.GetEscrowAddress(portID, channelID))
+}
+
+func (em *escrowMonitor) record(b EscrowBalance) {
+	k := b.ChainID + "/" + b.PortID + "/" + b.ChannelID
+
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	// Denoms no longer escrowed are reported as 0 rather than their last balance.
+	for _, coin := range em.balances[k].Balance {
+		if b.Balance.AmountOf(coin.Denom).IsZero() {
+			em.metrics.SetEscrowBalance(b.Path, b.ChainID, b.ChannelID, b.PortID, coin.Denom, 0)
+		}
+	}
+	for _, coin := range b.Balance {
+		amount, err := coin.Amount.ToDec().Float64()
+		if err != nil {
+			continue
+		}
+		em.metrics.SetEscrowBalance(b.Path, b.ChainID, b.ChannelID, b.PortID, coin.Denom, amount)
+	}
+	em.balances[k] = b
+}
+
+// snapshot returns the last balances of every escrow account, by chain, port and channel ID.
+func (em *escrowMonitor) snapshot() []EscrowBalance {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	balances := make([]EscrowBalance, 0, len(em.balances))
+	for _, b := range em.balances {
+		balances = append(balances, b)
+	}
+	sort.Slice(balances, func(i, k int) bool {
+		if balances[i].ChainID != balances[k].ChainID {
+			return balances[i].ChainID < balances[k].ChainID
+		}
+		if balances[i].PortID != balances[k].PortID {
+			return balances[i].PortID < balances[k].PortID
+		}
+		return balances[i].ChannelID < balances[k].ChannelID
+	})
+	return balances
+}
+
+// startEscrowMonitor starts checking the escrow balances of the transfer channels of every path at the given interval.
+func (s *supervisor) startEscrowMonitor(ctx context.Context, interval time.Duration) *escrowMonitor {
+	em := newEscrowMonitor(s.log.With(zap.String("sys", "escrowmonitor")), s.metrics)
+	runners := make([]*pathRunner, 0, len(s.names))
+	for _, name := range s.names {
+		runners = append(runners, s.runners[name])
+	}
+	s.maintenance.schedule(ctx, TaskEscrowAudit, interval, func(ctx context.Context) {
+		for _, r := range runners {
+			em.check(ctx, r)
+		}
+	})
+	return em
+}
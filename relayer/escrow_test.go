@@ -0,0 +1,50 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEscrowMonitorRecord(t *testing.T) {
+	m := processor.NewPrometheusMetrics()
+	em := newEscrowMonitor(zap.NewNop(), m)
+	now := time.Now().UTC()
+
+	balance := func(chainID, channelID string, coins ...sdk.Coin) EscrowBalance {
+		return EscrowBalance{Path: "a-b", ChainID: chainID, ChannelID: channelID, PortID: "transfer", Balance: sdk.NewCoins(coins...), CheckedAt: now}
+	}
+	em.record(balance("chain-b", "channel-7", sdk.NewInt64Coin("ubar", 5)))
+	em.record(balance("chain-a", "channel-1", sdk.NewInt64Coin("ufoo", 1)))
+	em.record(balance("chain-a", "channel-0", sdk.NewInt64Coin("ufoo", 10), sdk.NewInt64Coin("uqux", 3)))
+
+	snapshot := em.snapshot()
+	require.Len(t, snapshot, 3)
+	require.Equal(t, "channel-0", snapshot[0].ChannelID)
+	require.Equal(t, "channel-1", snapshot[1].ChannelID)
+	require.Equal(t, "chain-b", snapshot[2].ChainID)
+	require.Equal(t, 10.0, testutil.ToFloat64(m.EscrowBalance.WithLabelValues("a-b", "chain-a", "channel-0", "transfer", "ufoo")))
+
+	// A later balance replaces the previous one, and denoms no longer escrowed are reported as 0.
+	em.record(balance("chain-a", "channel-0", sdk.NewInt64Coin("ufoo", 4)))
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("ufoo", 4)), em.snapshot()[0].Balance)
+	require.Equal(t, 4.0, testutil.ToFloat64(m.EscrowBalance.WithLabelValues("a-b", "chain-a", "channel-0", "transfer", "ufoo")))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.EscrowBalance.WithLabelValues("a-b", "chain-a", "channel-0", "transfer", "uqux")))
+}
+
+func TestEscrowAddress(t *testing.T) {
+	cp := &cosmosprovider.CosmosProvider{PCfg: cosmosprovider.CosmosProviderConfig{ChainID: "chain-a"}}
+	cp.Config = &lens.ChainClientConfig{AccountPrefix: "cosmos"}
+
+	// Escrow address of transfer/channel-0, as derived by ibc-go.
+	addr, err := escrowAddress(&Chain{Chainid: "chain-a", ChainProvider: cp}, "transfer", "channel-0")
+	require.NoError(t, err)
+	require.Equal(t, "cosmos1a53udazy8ayufvy0s434pfwjcedzqv34kvz9tw", addr)
+}
@@ -0,0 +1,86 @@
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// faultInjector is a chain provider injecting faults into its RPC requests.
+type faultInjector interface {
+	Faults() provider.FaultConfig
+	SetFaults(cfg provider.FaultConfig) error
+}
+
+// faults returns the faults injected into the RPC requests of the chains of all paths.
+func (s *supervisor) faults() []admin.ChainFaults {
+	res := []admin.ChainFaults{}
+	for _, c := range s.chains() {
+		if fi, ok := c.ChainProvider.(faultInjector); ok {
+			res = append(res, chainFaults(c.ChainID(), fi.Faults()))
+		}
+	}
+	return res
+}
+
+func chainFaults(chainID string, cfg provider.FaultConfig) admin.ChainFaults {
+	return admin.ChainFaults{
+		ChainID:        chainID,
+		DropRate:       cfg.DropRate,
+		BroadcastDelay: cfg.BroadcastDelay,
+		StaleHeights:   cfg.StaleHeights,
+	}
+}
+
+// registerFaultHandlers exposes the faults injected into the RPC requests of each chain through the admin API,
+// in builds with the faultinjection build tag.
+//
+//	GET  /faults lists the faults injected on each chain.
+//	POST /faults sets the faults injected on a chain, zero values clearing them.
+func registerFaultHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/faults", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			admin.WriteJSON(w, http.StatusOK, s.faults())
+		case http.MethodPost:
+			var body admin.ChainFaults
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			var fi faultInjector
+			for _, c := range s.chains() {
+				if c.ChainID() == body.ChainID {
+					fi, _ = c.ChainProvider.(faultInjector)
+				}
+			}
+			if fi == nil {
+				admin.WriteError(w, http.StatusNotFound, fmt.Errorf("no chain %s injecting faults", body.ChainID))
+				return
+			}
+			cfg := provider.FaultConfig{
+				DropRate:       body.DropRate,
+				BroadcastDelay: body.BroadcastDelay,
+				StaleHeights:   body.StaleHeights,
+			}
+			if err := fi.SetFaults(cfg); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			s.log.Warn(
+				"Set injected RPC faults",
+				zap.String("chain_id", body.ChainID),
+				zap.Float64("drop_rate", cfg.DropRate),
+				zap.String("broadcast_delay", cfg.BroadcastDelay),
+				zap.Int64("stale_heights", cfg.StaleHeights),
+			)
+			admin.WriteJSON(w, http.StatusOK, chainFaults(body.ChainID, fi.Faults()))
+		default:
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// FeatureHybridProcessor lets a path be handed off at runtime to the processor the other paths are not relayed by,
+// through the admin API.
+const FeatureHybridProcessor = "hybrid-processor"
+
+// featureDefaults is whether each experimental feature is enabled for the paths it is not configured for.
+// Features already relied on default to enabled, so that they can be turned off path by path.
+var featureDefaults = map[string]bool{
+	processor.FeatureOptimisticRelaying: true,
+	FeatureHybridProcessor:              true,
+	processor.FeatureProofPipelining:    false,
+}
+
+// FeatureFlagsConfig enables or disables the experimental features of `rly start` by name, such as
+// `proof-pipelining`, so they are rolled out path by path across a fleet of relayers running the same build.
+type FeatureFlagsConfig map[string]FeatureFlag
+
+// FeatureFlag enables or disables a feature for all paths, or only some of them.
+type FeatureFlag struct {
+	// Enabled enables the feature for the paths not in Paths, the default of the feature if unset.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+
+	// Paths enables or disables the feature for the paths by name.
+	Paths map[string]bool `yaml:"paths,omitempty" json:"paths,omitempty"`
+}
+
+// Validate checks the feature flags config, paths being the paths of the config the flags may apply to.
+func (c FeatureFlagsConfig) Validate(paths Paths) error {
+	for name, f := range c {
+		if _, ok := featureDefaults[name]; !ok {
+			return fmt.Errorf("unknown feature %s", name)
+		}
+		for path := range f.Paths {
+			if _, ok := paths[path]; !ok {
+				return fmt.Errorf("feature %s: path %s not found", name, path)
+			}
+		}
+	}
+	return nil
+}
+
+// featureFlags is whether each experimental feature is enabled by default and for each path,
+// as configured and changed through the admin API.
+type featureFlags struct {
+	mu      sync.Mutex
+	enabled map[string]bool
+	paths   map[string]map[string]bool
+}
+
+func newFeatureFlags(cfg FeatureFlagsConfig) *featureFlags {
+	f := &featureFlags{
+		enabled: make(map[string]bool, len(featureDefaults)),
+		paths:   make(map[string]map[string]bool, len(featureDefaults)),
+	}
+	for name, enabled := range featureDefaults {
+		f.enabled[name] = enabled
+		f.paths[name] = make(map[string]bool)
+	}
+	for name, flag := range cfg {
+		if flag.Enabled != nil {
+			f.enabled[name] = *flag.Enabled
+		}
+		for path, enabled := range flag.Paths {
+			f.paths[name][path] = enabled
+		}
+	}
+	return f
+}
+
+// Enabled returns whether feature is enabled for path.
+func (f *featureFlags) Enabled(feature, path string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if enabled, ok := f.paths[feature][path]; ok {
+		return enabled
+	}
+	return f.enabled[feature]
+}
+
+// gate returns the FeatureGate of the processor of path.
+func (f *featureFlags) gate(path string) processor.FeatureGate {
+	return func(feature string) bool { return f.Enabled(feature, path) }
+}
+
+// set enables or disables feature for path, or by default if path is empty, returning whether it changed.
+func (f *featureFlags) set(feature, path string, enabled bool) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := featureDefaults[feature]; !ok {
+		return false, fmt.Errorf("unknown feature %s", feature)
+	}
+	if path == "" {
+		changed := f.enabled[feature] != enabled
+		f.enabled[feature] = enabled
+		return changed, nil
+	}
+	prev, ok := f.paths[feature][path]
+	if !ok {
+		prev = f.enabled[feature]
+	}
+	f.paths[feature][path] = enabled
+	return prev != enabled, nil
+}
+
+// snapshot returns whether each feature is enabled by default and for each of paths, sorted by feature.
+func (f *featureFlags) snapshot(paths []string) []admin.FeatureFlag {
+	names := make([]string, 0, len(featureDefaults))
+	for name := range featureDefaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	flags := make([]admin.FeatureFlag, 0, len(names))
+	for _, name := range names {
+		flag := admin.FeatureFlag{Name: name, Enabled: f.Enabled(name, ""), Paths: make(map[string]bool, len(paths))}
+		for _, path := range paths {
+			flag.Paths[path] = f.Enabled(name, path)
+		}
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+// registerFeatureHandlers exposes the experimental features of the paths through the admin API,
+// so they are turned on and off without restarting the relayer.
+//
+//	GET  /features lists whether each feature is enabled by default and for each path.
+//	POST /features enables or disables a feature for a path, or by default.
+func registerFeatureHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/features", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			admin.WriteJSON(w, http.StatusOK, s.features.snapshot(s.names))
+		case http.MethodPost:
+			var body admin.FeatureFlag
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			if _, ok := s.runners[body.Path]; body.Path != "" && !ok {
+				admin.WriteError(w, http.StatusNotFound, fmt.Errorf("path %s not found", body.Path))
+				return
+			}
+			changed, err := s.features.set(body.Name, body.Path, body.Enabled)
+			if err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			if changed {
+				s.log.Info(
+					"Set feature flag",
+					zap.String("feature", body.Name),
+					zap.String("path_name", body.Path),
+					zap.Bool("enabled", body.Enabled),
+				)
+			}
+			admin.WriteJSON(w, http.StatusOK, admin.FeatureFlag{Name: body.Name, Path: body.Path, Enabled: body.Enabled})
+		default:
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	})
+}
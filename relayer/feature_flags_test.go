@@ -0,0 +1,114 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestFeatureFlagsConfigValidate(t *testing.T) {
+	paths := Paths{"a-b": &Path{}}
+	enabled := true
+	require.NoError(t, FeatureFlagsConfig{processor.FeatureProofPipelining: {Enabled: &enabled, Paths: map[string]bool{"a-b": false}}}.Validate(paths))
+	require.NoError(t, FeatureFlagsConfig(nil).Validate(paths))
+	require.EqualError(t, FeatureFlagsConfig{"warp-drive": {}}.Validate(paths), "unknown feature warp-drive")
+	require.EqualError(t, FeatureFlagsConfig{FeatureHybridProcessor: {Paths: map[string]bool{"b-c": true}}}.Validate(paths),
+		"feature hybrid-processor: path b-c not found")
+}
+
+func TestFeatureFlags(t *testing.T) {
+	disabled := false
+	f := newFeatureFlags(FeatureFlagsConfig{
+		processor.FeatureProofPipelining:    {Paths: map[string]bool{"a-b": true}},
+		processor.FeatureOptimisticRelaying: {Enabled: &disabled, Paths: map[string]bool{"b-a": true}},
+	})
+	require.True(t, f.Enabled(processor.FeatureProofPipelining, "a-b"))
+	require.False(t, f.Enabled(processor.FeatureProofPipelining, "b-a"))
+	require.False(t, f.Enabled(processor.FeatureOptimisticRelaying, "a-b"))
+	require.True(t, f.Enabled(processor.FeatureOptimisticRelaying, "b-a"))
+	require.True(t, f.Enabled(FeatureHybridProcessor, "a-b"))
+
+	// Enabling a feature by default leaves the paths it is disabled for disabled.
+	changed, err := f.set(processor.FeatureProofPipelining, "b-a", false)
+	require.NoError(t, err)
+	require.False(t, changed)
+	changed, err = f.set(processor.FeatureProofPipelining, "", true)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.False(t, f.gate("b-a")(processor.FeatureProofPipelining))
+	require.True(t, f.gate("c-d")(processor.FeatureProofPipelining))
+
+	_, err = f.set("warp-drive", "", true)
+	require.EqualError(t, err, "unknown feature warp-drive")
+
+	require.Equal(t, []admin.FeatureFlag{
+		{Name: FeatureHybridProcessor, Enabled: true, Paths: map[string]bool{"a-b": true, "b-a": true}},
+		{Name: processor.FeatureOptimisticRelaying, Paths: map[string]bool{"a-b": false, "b-a": true}},
+		{Name: processor.FeatureProofPipelining, Enabled: true, Paths: map[string]bool{"a-b": true, "b-a": false}},
+	}, f.snapshot([]string{"a-b", "b-a"}))
+}
+
+func TestFeatureHandlers(t *testing.T) {
+	chain := func(chainID string) *Chain {
+		return &Chain{Chainid: chainID, ChainProvider: &cosmosprovider.CosmosProvider{PCfg: cosmosprovider.CosmosProviderConfig{ChainID: chainID}}}
+	}
+	chains := map[string]*Chain{"chain-a": chain("chain-a"), "chain-b": chain("chain-b")}
+	paths := []NamedPath{{Name: "a-b", Path: &Path{
+		Src: &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"},
+		Dst: &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-0"},
+	}}}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	srv := admin.NewServer(zaptest.NewLogger(t))
+	registerFeatureHandlers(srv, s)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv.Start(ctx, ln)
+
+	nonce := 0
+	post := func(body admin.FeatureFlag) int {
+		bz, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "http://"+ln.Addr().String()+"/features", bytes.NewReader(bz))
+		require.NoError(t, err)
+		nonce++
+		req.Header.Set(admin.OperatorHeader, "alice")
+		req.Header.Set(admin.NonceHeader, strconv.Itoa(nonce))
+		req.Header.Set(admin.TimestampHeader, strconv.FormatInt(time.Now().Unix(), 10))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, post(admin.FeatureFlag{Name: FeatureHybridProcessor, Path: "a-b"}))
+	require.Equal(t, http.StatusNotFound, post(admin.FeatureFlag{Name: FeatureHybridProcessor, Path: "b-c"}))
+	require.Equal(t, http.StatusBadRequest, post(admin.FeatureFlag{Name: "warp-drive"}))
+
+	// Paths the hybrid processor is disabled for are not handed off.
+	err = s.SetProcessorType(ctx, "a-b", ProcessorLegacy)
+	require.True(t, errors.Is(err, errHybridProcessorDisabled))
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/features")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var flags []admin.FeatureFlag
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&flags))
+	require.Equal(t, admin.FeatureFlag{Name: FeatureHybridProcessor, Enabled: true, Paths: map[string]bool{"a-b": false}}, flags[0])
+}
@@ -0,0 +1,106 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// feeVersion is the version of the ICS-29 fee middleware wrapping the application of fee-enabled channels.
+const feeVersion = "ics29-1"
+
+// isFeeEnabledVersion returns true if version is the version of a fee-enabled channel,
+// i.e. the ICS-29 metadata wrapping the version of the underlying application.
+func isFeeEnabledVersion(version string) bool {
+	var metadata struct {
+		FeeVersion string `json:"fee_version"`
+	}
+	if err := json.Unmarshal([]byte(version), &metadata); err != nil {
+		return false
+	}
+	return metadata.FeeVersion == feeVersion
+}
+
+// registerCounterpartyPayee makes sure that, on every open fee-enabled channel of the connection of c,
+// the relayer address on c has its address on counterparty registered as counterparty payee,
+// so that the forward relay fees of the packets it delivers to c are paid out on counterparty.
+// Channels already registered are left untouched, so it is safe to run on every start.
+func registerCounterpartyPayee(ctx context.Context, log *zap.Logger, c, counterparty *Chain, filter ChannelFilter, memo string) error {
+	relayerAddr, err := c.ChainProvider.Address()
+	if err != nil {
+		return fmt.Errorf("failed to get relayer address on %s: %w", c.ChainID(), err)
+	}
+	payee, err := counterparty.ChainProvider.Address()
+	if err != nil {
+		return fmt.Errorf("failed to get relayer address on %s: %w", counterparty.ChainID(), err)
+	}
+
+	channels, err := queryChannelsOnConnection(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	var msgs []provider.RelayerMessage
+	for id, channel := range filterOpenChannels(applyChannelFilterRule(filter, channels)) {
+		if !isFeeEnabledVersion(channel.channel.Version) {
+			continue
+		}
+		registered, err := c.ChainProvider.QueryCounterpartyPayee(ctx, id, relayerAddr)
+		if err != nil {
+			return err
+		}
+		if registered == payee {
+			continue
+		}
+		msg, err := c.ChainProvider.MsgRegisterCounterpartyPayee(channel.channel.PortId, id, relayerAddr, payee)
+		if err != nil {
+			return err
+		}
+		log.Info(
+			"Registering counterparty payee",
+			zap.String("chain_id", c.ChainID()),
+			zap.String("channel_id", id),
+			zap.String("port_id", channel.channel.PortId),
+			zap.String("counterparty_payee", payee),
+			zap.String("previous_counterparty_payee", registered),
+		)
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	res, success, err := c.ChainProvider.SendMessages(ctx, msgs, memo)
+	if err != nil {
+		return fmt.Errorf("failed to register counterparty payees on %s: %w", c.ChainID(), err)
+	}
+	if !success {
+		return fmt.Errorf("failed to register counterparty payees on %s: tx %s failed with code %d", c.ChainID(), res.TxHash, res.Code)
+	}
+	return nil
+}
+
+// registerCounterpartyPayees registers the counterparty payee of the relayer on both ends of the paths
+// configured to, or of every path if all is set.
+// Failures are logged rather than returned, as they only affect the fees the relayer is paid, not relaying.
+func (s *supervisor) registerCounterpartyPayees(ctx context.Context, all bool) {
+	for _, name := range s.names {
+		r := s.runners[name]
+		if !all && !r.registerPayee {
+			continue
+		}
+		log := s.log.With(zap.String("path", r.name))
+		for _, ends := range [][2]*Chain{{r.src, r.dst}, {r.dst, r.src}} {
+			if err := registerCounterpartyPayee(provider.WithPathName(ctx, r.name), log, ends[0], ends[1], r.filter, s.memo); err != nil {
+				log.Warn(
+					"Failed to register counterparty payee",
+					zap.String("chain_id", ends[0].ChainID()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
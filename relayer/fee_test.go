@@ -0,0 +1,14 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsFeeEnabledVersion(t *testing.T) {
+	require.True(t, isFeeEnabledVersion(`{"fee_version":"ics29-1","app_version":"ics20-1"}`))
+	require.False(t, isFeeEnabledVersion("ics20-1"))
+	require.False(t, isFeeEnabledVersion(`{"version":"ics27-1"}`))
+	require.False(t, isFeeEnabledVersion(`{"fee_version":"ics29-2","app_version":"ics20-1"}`))
+}
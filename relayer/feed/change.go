@@ -0,0 +1,84 @@
+package feed
+
+// EventConfigChanged is the event type of the changefeed, published when the configuration or the runtime state
+// of the relayed paths changes, e.g. for configuration drift detection.
+const EventConfigChanged = "config_changed"
+
+// Kinds of the changes of the changefeed.
+const (
+	// ChangeSnapshot reports the state of every path as the relayer starts.
+	ChangeSnapshot = "snapshot"
+
+	ChangePathStarted    = "path_started"
+	ChangeChannelPaused  = "channel_paused"
+	ChangeChannelResumed = "channel_resumed"
+	ChangePathHeld       = "path_held"
+	ChangePathReleased   = "path_released"
+	ChangePathPaused     = "path_paused"
+	ChangePathResumed    = "path_resumed"
+	ChangePriority       = "priority"
+	ChangeProcessor      = "processor"
+	ChangeLog            = "log"
+	ChangeSigningKey     = "signing_key"
+)
+
+// Causes of the changes of the changefeed.
+const (
+	CauseStartup      = "startup"
+	CauseAdmin        = "admin"
+	CauseDependencies = "dependencies"
+	CauseRateLimit    = "rate_limit"
+	CauseHealthProbe  = "health_probe"
+	CauseUpgrade      = "upgrade"
+)
+
+// Change describes a change of the configuration or runtime state of the relayer, along with the state
+// of the paths it affected once changed, so that consumers need not replay the changefeed to know the state.
+type Change struct {
+	// Sequence increases by one with every change published by a relayer process, starting at 1 with the snapshot,
+	// so that consumers detect the changes they missed.
+	Sequence uint64 `json:"sequence"`
+	Kind     string `json:"kind"`
+	Cause    string `json:"cause"`
+
+	// Path, PortID and ChannelID identify what changed, if not every path.
+	// Channels are identified by their port and channel IDs on the src chain of their path.
+	Path      string `json:"path,omitempty"`
+	PortID    string `json:"port_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+
+	// Reason details the cause, e.g. the health probe holding a path.
+	Reason string `json:"reason,omitempty"`
+
+	Paths []PathState `json:"paths"`
+}
+
+// PathState is the configuration and runtime state of a relayed path.
+type PathState struct {
+	Path       string `json:"path"`
+	SrcChainID string `json:"src_chain_id"`
+	DstChainID string `json:"dst_chain_id"`
+	SrcKey     string `json:"src_key,omitempty"`
+	DstKey     string `json:"dst_key,omitempty"`
+
+	Processor string `json:"processor"`
+	Started   bool   `json:"started"`
+
+	// Direction is the half of the packet flow relayed, empty for both.
+	Direction string `json:"direction,omitempty"`
+
+	ChannelFilter ChannelFilter `json:"channel_filter"`
+
+	// PausedChannels are the channels paused, as port/channel, and Holds the reasons every channel is held for.
+	PausedChannels []string `json:"paused_channels"`
+	Holds          []string `json:"holds,omitempty"`
+
+	Priority bool   `json:"priority"`
+	LogLevel string `json:"log_level,omitempty"`
+}
+
+// ChannelFilter is the channel filter of a path.
+type ChannelFilter struct {
+	Rule     string   `json:"rule,omitempty"`
+	Channels []string `json:"channels,omitempty"`
+}
@@ -0,0 +1,170 @@
+// Package feed publishes structured relayer events, such as the packet lifecycle observed on relayed paths,
+// to external sinks.
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// SchemaVersion is the version of the Event payload.
+// It is incremented on any change that is not backwards compatible for consumers.
+const SchemaVersion = 1
+
+// Event types of the packet lifecycle, as observed on chain.
+const (
+	EventPacketSent         = "packet_sent"
+	EventPacketReceived     = "packet_received"
+	EventPacketAcknowledged = "packet_acknowledged"
+	EventPacketTimedOut     = "packet_timed_out"
+)
+
+// Event types of the ICS-29 fees observed on chain.
+const (
+	// EventPacketFeePaid is published when a fee is paid for relaying a packet sent on a relayed channel.
+	EventPacketFeePaid = "packet_fee_paid"
+
+	// EventFeeDistributed is published when a fee is paid out to the relayer.
+	EventFeeDistributed = "fee_distributed"
+)
+
+// Event types of the transactions broadcast by the relayer.
+const (
+	EventPacketRelayed  = "packet_relayed"
+	EventAckRelayed     = "ack_relayed"
+	EventTimeoutRelayed = "timeout_relayed"
+	EventClientUpdated  = "client_updated"
+	EventRelayError     = "relay_error"
+)
+
+// Event is a single relayer event, serialized as JSON for sinks.
+type Event struct {
+	SchemaVersion int       `json:"schema_version"`
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	ChainID       string    `json:"chain_id"`
+	Height        uint64    `json:"height,omitempty"`
+	Signer        string    `json:"signer,omitempty"`
+	TxHash        string    `json:"tx_hash,omitempty"`
+	TxFee         string    `json:"tx_fee,omitempty"`
+	ClientID      string    `json:"client_id,omitempty"`
+	Packet        *Packet   `json:"packet,omitempty"`
+	Fee           *Fee      `json:"fee,omitempty"`
+	Change        *Change   `json:"change,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Packet identifies the packet an Event is about.
+type Packet struct {
+	Sequence         uint64 `json:"sequence"`
+	SourceChannel    string `json:"source_channel"`
+	SourcePort       string `json:"source_port"`
+	DestChannel      string `json:"dest_channel"`
+	DestPort         string `json:"dest_port"`
+	TimeoutHeight    string `json:"timeout_height,omitempty"`
+	TimeoutTimestamp uint64 `json:"timeout_timestamp,omitempty"`
+	Data             []byte `json:"data,omitempty"`
+	Ack              []byte `json:"ack,omitempty"`
+}
+
+// Fee describes the ICS-29 fees an Event is about, as coin strings.
+// The packet fees are the totals escrowed for the packet, the amount is what was paid out to the receiver.
+type Fee struct {
+	RecvFee    string `json:"recv_fee,omitempty"`
+	AckFee     string `json:"ack_fee,omitempty"`
+	TimeoutFee string `json:"timeout_fee,omitempty"`
+	Receiver   string `json:"receiver,omitempty"`
+	Amount     string `json:"amount,omitempty"`
+}
+
+// NewPacket returns the feed representation of a packet.
+func NewPacket(pi provider.PacketInfo) *Packet {
+	p := &Packet{
+		Sequence:         pi.Sequence,
+		SourceChannel:    pi.SourceChannel,
+		SourcePort:       pi.SourcePort,
+		DestChannel:      pi.DestChannel,
+		DestPort:         pi.DestPort,
+		TimeoutTimestamp: pi.TimeoutTimestamp,
+		Data:             pi.Data,
+		Ack:              pi.Ack,
+	}
+	if !pi.TimeoutHeight.IsZero() {
+		p.TimeoutHeight = pi.TimeoutHeight.String()
+	}
+	return p
+}
+
+// Sink is a destination for relayer events.
+type Sink interface {
+	// Name identifies the sink in logs.
+	Name() string
+
+	// Publish delivers the event to the sink.
+	Publish(ctx context.Context, e Event) error
+}
+
+const (
+	publisherBufferSize = 1024
+	publishTimeout      = 10 * time.Second
+)
+
+// Publisher delivers events to a set of sinks in the background,
+// so that slow or unavailable sinks never hold up relaying.
+// Events are dropped, with a warning, when the buffer is full.
+type Publisher struct {
+	log    *zap.Logger
+	sinks  []Sink
+	events chan Event
+}
+
+// NewPublisher returns a Publisher for the given sinks. Run must be called for events to be delivered.
+func NewPublisher(log *zap.Logger, sinks ...Sink) *Publisher {
+	return &Publisher{
+		log:    log,
+		sinks:  sinks,
+		events: make(chan Event, publisherBufferSize),
+	}
+}
+
+// Publish enqueues the event for delivery to all sinks. It is safe to call on a nil Publisher.
+func (p *Publisher) Publish(e Event) {
+	if p == nil || len(p.sinks) == 0 {
+		return
+	}
+	e.SchemaVersion = SchemaVersion
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	select {
+	case p.events <- e:
+	default:
+		p.log.Warn("Dropping relayer event, feed buffer is full", zap.String("type", e.Type), zap.String("chain_id", e.ChainID))
+	}
+}
+
+// Run delivers enqueued events until ctx is cancelled.
+func (p *Publisher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-p.events:
+			for _, s := range p.sinks {
+				publishCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+				if err := s.Publish(publishCtx, e); err != nil {
+					p.log.Warn(
+						"Failed to publish relayer event",
+						zap.String("sink", s.Name()),
+						zap.String("type", e.Type),
+						zap.Error(err),
+					)
+				}
+				cancel()
+			}
+		}
+	}
+}
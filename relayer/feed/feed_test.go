@@ -0,0 +1,232 @@
+package feed
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPublisherWebhook(t *testing.T) {
+	received := make(chan Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+		var e Event
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&e))
+		received <- e
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := NewPublisher(zaptest.NewLogger(t), NewWebhookSink(srv.URL))
+	go p.Run(ctx)
+
+	p.Publish(Event{
+		Type:    EventPacketSent,
+		ChainID: "chain-a",
+		Height:  10,
+		Packet: &Packet{
+			Sequence:      1,
+			SourceChannel: "channel-0",
+			SourcePort:    "transfer",
+			DestChannel:   "channel-1",
+			DestPort:      "transfer",
+		},
+	})
+
+	select {
+	case e := <-received:
+		require.Equal(t, SchemaVersion, e.SchemaVersion)
+		require.Equal(t, EventPacketSent, e.Type)
+		require.Equal(t, "chain-a", e.ChainID)
+		require.False(t, e.Time.IsZero())
+		require.NotNil(t, e.Packet)
+		require.Equal(t, uint64(1), e.Packet.Sequence)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not delivered to the webhook")
+	}
+}
+
+func TestWebhookSinkErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	require.Error(t, NewWebhookSink(srv.URL).Publish(context.Background(), Event{Type: EventPacketSent}))
+}
+
+func TestNilPublisher(t *testing.T) {
+	var p *Publisher
+	p.Publish(Event{Type: EventPacketSent})
+}
+
+func TestKafkaRESTSink(t *testing.T) {
+	received := make(chan kafkaRecords, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/topics/relayer-events", req.URL.Path)
+		require.Equal(t, kafkaRESTContentType, req.Header.Get("Content-Type"))
+
+		var records kafkaRecords
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&records))
+		received <- records
+	}))
+	defer srv.Close()
+
+	sink := NewKafkaRESTSink(srv.URL + "/topics/relayer-events")
+	require.NoError(t, sink.Publish(context.Background(), Event{SchemaVersion: SchemaVersion, Type: EventClientUpdated, ChainID: "chain-a"}))
+
+	records := <-received
+	require.Len(t, records.Records, 1)
+	require.Equal(t, "chain-a", records.Records[0].Key)
+	require.Equal(t, EventClientUpdated, records.Records[0].Value.Type)
+	require.Equal(t, SchemaVersion, records.Records[0].Value.SchemaVersion)
+}
+
+func TestNATSSink(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	type pub struct {
+		connect, pub, payload string
+	}
+	received := make(chan pub, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("INFO {}\r\n"))
+		r := bufio.NewReader(conn)
+		var p pub
+		p.connect, _ = r.ReadString('\n')
+		p.pub, _ = r.ReadString('\n')
+		p.payload, _ = r.ReadString('\n')
+		received <- p
+	}()
+
+	sink, err := NewNATSSink("nats://user:secret@" + ln.Addr().String() + "/relayer.events")
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(context.Background(), Event{SchemaVersion: SchemaVersion, Type: EventRelayError, ChainID: "chain-a"}))
+
+	p := <-received
+	require.True(t, strings.HasPrefix(p.connect, "CONNECT "))
+	require.Contains(t, p.connect, `"user":"user"`)
+	require.Contains(t, p.connect, `"pass":"secret"`)
+
+	payload := strings.TrimSuffix(p.payload, "\r\n")
+	require.Equal(t, fmt.Sprintf("PUB relayer.events %d\r\n", len(payload)), p.pub)
+
+	var e Event
+	require.NoError(t, json.Unmarshal([]byte(payload), &e))
+	require.Equal(t, EventRelayError, e.Type)
+}
+
+func TestNewNATSSinkInvalidURL(t *testing.T) {
+	_, err := NewNATSSink("http://localhost:4222/subject")
+	require.Error(t, err)
+
+	_, err = NewNATSSink("nats://localhost:4222")
+	require.Error(t, err)
+}
+
+func TestStreamSink(t *testing.T) {
+	var buf strings.Builder
+	s := NewStreamSink("stdout", &buf)
+	require.NoError(t, s.Publish(context.Background(), Event{Type: EventPacketRelayed, ChainID: "chain-b", TxHash: "ABC", TxFee: "10uatom"}))
+	require.NoError(t, s.Publish(context.Background(), Event{Type: EventAckRelayed, ChainID: "chain-a"}))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var e Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &e))
+	require.Equal(t, EventPacketRelayed, e.Type)
+	require.Equal(t, "10uatom", e.TxFee)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+	change := Event{Type: EventConfigChanged, Change: &Change{Sequence: 1, Kind: ChangeSnapshot, Cause: CauseStartup}}
+
+	// Events are appended to the file across runs.
+	for i := 0; i < 2; i++ {
+		s, err := OpenFileSink(path)
+		require.NoError(t, err)
+		require.Equal(t, "file "+path, s.Name())
+		require.NoError(t, s.Publish(context.Background(), change))
+		require.NoError(t, s.Close())
+	}
+
+	bz, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(bz), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var e Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &e))
+	require.Equal(t, ChangeSnapshot, e.Change.Kind)
+	require.Equal(t, uint64(1), e.Change.Sequence)
+}
+
+func TestSocketSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.sock")
+	s, err := ListenSocketSink(path)
+	require.NoError(t, err)
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The client is registered asynchronously, publish until it receives an event.
+	received := make(chan Event, 1)
+	go func() {
+		var e Event
+		if err := json.NewDecoder(conn).Decode(&e); err == nil {
+			received <- e
+		}
+	}()
+	require.Eventually(t, func() bool {
+		require.NoError(t, s.Publish(context.Background(), Event{Type: EventPacketRelayed, ChainID: "chain-b"}))
+		select {
+		case e := <-received:
+			require.Equal(t, EventPacketRelayed, e.Type)
+			return true
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, s.Close())
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+
+	// A stale socket is replaced, any other file is left alone.
+	ln, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, ln.Close())
+	s, err = ListenSocketSink(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+	regular := filepath.Join(t.TempDir(), "events.json")
+	require.NoError(t, os.WriteFile(regular, nil, 0o644))
+	_, err = ListenSocketSink(regular)
+	require.Error(t, err)
+}
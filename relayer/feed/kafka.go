@@ -0,0 +1,63 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const kafkaRESTContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaRESTSink produces every event as a JSON record to a Kafka topic through a Kafka REST proxy,
+// keyed by chain ID so that the events of a chain stay ordered within a partition.
+type KafkaRESTSink struct {
+	url    string
+	client *http.Client
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+// NewKafkaRESTSink returns a sink producing events to the topic at url,
+// e.g. http://localhost:8082/topics/relayer-events.
+func NewKafkaRESTSink(url string) *KafkaRESTSink {
+	return &KafkaRESTSink{
+		url:    url,
+		client: http.DefaultClient,
+	}
+}
+
+func (s *KafkaRESTSink) Name() string {
+	return "kafka " + s.url
+}
+
+func (s *KafkaRESTSink) Publish(ctx context.Context, e Event) error {
+	body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{{Key: e.ChainID, Value: e}}})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaRESTContentType)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("kafka rest proxy responded with status %s", res.Status)
+	}
+	return nil
+}
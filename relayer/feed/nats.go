@@ -0,0 +1,165 @@
+package feed
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const natsDialTimeout = 5 * time.Second
+
+// NATSSink publishes every event as a JSON message on a NATS subject.
+// It speaks the core NATS client protocol over plain TCP, reconnecting on the next event after a failure.
+type NATSSink struct {
+	addr    string
+	subject string
+	connect natsConnect
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// natsConnect is the payload of the CONNECT message of the NATS protocol.
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+}
+
+// NewNATSSink returns a sink publishing events to a NATS server.
+// rawURL has the form nats://[user:pass@|token@]host:port/subject.
+func NewNATSSink(rawURL string) (*NATSSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "nats" {
+		return nil, fmt.Errorf("unsupported NATS url scheme %q, expected nats://", u.Scheme)
+	}
+	subject := strings.TrimPrefix(u.Path, "/")
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return nil, fmt.Errorf("invalid NATS subject %q in url %s", subject, rawURL)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	s := &NATSSink{
+		addr:    addr,
+		subject: subject,
+		connect: natsConnect{Name: "rly"},
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			s.connect.User, s.connect.Pass = u.User.Username(), pass
+		} else {
+			s.connect.Token = u.User.Username()
+		}
+	}
+	return s, nil
+}
+
+func (s *NATSSink) Name() string {
+	return "nats " + s.addr + "/" + s.subject
+}
+
+func (s *NATSSink) Publish(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.dial(ctx); err != nil {
+			return err
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+	msg := fmt.Sprintf("PUB %s %d\r\n%s\r\n", s.subject, len(payload), payload)
+	if _, err := s.conn.Write([]byte(msg)); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	_ = s.conn.SetWriteDeadline(time.Time{})
+	return nil
+}
+
+// dial connects to the server and sends the CONNECT message. Must be called with s.mu held.
+func (s *NATSSink) dial(ctx context.Context) error {
+	d := net.Dialer{Timeout: natsDialTimeout}
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	// The server greets with its INFO before accepting any messages.
+	r := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(natsDialTimeout))
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to read NATS server info: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		conn.Close()
+		return fmt.Errorf("unexpected NATS server greeting: %q", strings.TrimSpace(line))
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	connect, err := json.Marshal(s.connect)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", connect); err != nil {
+		conn.Close()
+		return err
+	}
+
+	s.conn = conn
+	go s.readLoop(conn, r)
+	return nil
+}
+
+// readLoop answers the server's keepalive pings and drops the connection on protocol errors,
+// so that the next event reconnects.
+func (s *NATSSink) readLoop(conn net.Conn, r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil || strings.HasPrefix(line, "-ERR") {
+			s.mu.Lock()
+			if s.conn == conn {
+				s.conn = nil
+			}
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		if strings.HasPrefix(line, "PING") {
+			s.mu.Lock()
+			_, err = conn.Write([]byte("PONG\r\n"))
+			s.mu.Unlock()
+			if err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// StreamSink writes every event as a line of JSON to a writer, such as stdout.
+type StreamSink struct {
+	name string
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStreamSink returns a sink writing events to w, identified by name in logs.
+func NewStreamSink(name string, w io.Writer) *StreamSink {
+	return &StreamSink{name: name, w: w}
+}
+
+func (s *StreamSink) Name() string {
+	return s.name
+}
+
+func (s *StreamSink) Publish(_ context.Context, e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// FileSink appends every event as a line of JSON to a file.
+type FileSink struct {
+	*StreamSink
+	f *os.File
+}
+
+// OpenFileSink opens the file at path for appending events to, creating it if needed.
+// Close must be called to close the file.
+func OpenFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{StreamSink: NewStreamSink("file "+path, f), f: f}, nil
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	return s.f.Close()
+}
+
+// SocketSink listens on a unix socket and writes every event as a line of JSON to each connected client,
+// so that consumers attach and detach at will. Clients only receive the events published while connected,
+// and are disconnected when a write to them fails or times out.
+type SocketSink struct {
+	path string
+	ln   net.Listener
+
+	mu      sync.Mutex
+	clients map[net.Conn]struct{}
+}
+
+// ListenSocketSink listens on the unix socket at path, replacing a stale socket left by a previous run.
+// Close must be called to stop listening and disconnect the clients.
+func ListenSocketSink(path string) (*SocketSink, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("cannot listen on %s, file exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	s := &SocketSink{
+		path:    path,
+		ln:      ln,
+		clients: make(map[net.Conn]struct{}),
+	}
+	go s.accept()
+	return s, nil
+}
+
+func (s *SocketSink) accept() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		s.mu.Lock()
+		s.clients[conn] = struct{}{}
+		s.mu.Unlock()
+	}
+}
+
+func (s *SocketSink) Name() string {
+	return "unix socket " + s.path
+}
+
+func (s *SocketSink) Publish(ctx context.Context, e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deadline, _ := ctx.Deadline()
+	for conn := range s.clients {
+		_ = conn.SetWriteDeadline(deadline)
+		if _, err := conn.Write(line); err != nil {
+			conn.Close()
+			delete(s.clients, conn)
+			continue
+		}
+		_ = conn.SetWriteDeadline(time.Time{})
+	}
+	return nil
+}
+
+// Close stops listening, removing the socket, and disconnects the clients.
+func (s *SocketSink) Close() error {
+	err := s.ln.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.clients {
+		conn.Close()
+		delete(s.clients, conn)
+	}
+	return err
+}
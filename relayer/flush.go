@@ -0,0 +1,299 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// flushResultRetention is how long the result of a flush is kept for replay to callers retrying with the same idempotency key.
+const flushResultRetention = 24 * time.Hour
+
+// RelayedTx identifies a transaction broadcast by the relayer.
+type RelayedTx struct {
+	ChainID string `json:"chain_id"`
+	TxHash  string `json:"tx_hash"`
+}
+
+type txRecorderKey struct{}
+
+// txRecorder collects the transactions successfully broadcast by Send while it is attached to the context.
+type txRecorder struct {
+	mu  sync.Mutex
+	txs []RelayedTx
+}
+
+func withTxRecorder(ctx context.Context, r *txRecorder) context.Context {
+	return context.WithValue(ctx, txRecorderKey{}, r)
+}
+
+// recordTx adds the transaction to the txRecorder attached to ctx, if any.
+func recordTx(ctx context.Context, chainID string, resp *provider.RelayerTxResponse) {
+	r, ok := ctx.Value(txRecorderKey{}).(*txRecorder)
+	if !ok || resp == nil || resp.TxHash == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.txs = append(r.txs, RelayedTx{ChainID: chainID, TxHash: resp.TxHash})
+}
+
+func (r *txRecorder) relayedTxs() []RelayedTx {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RelayedTx(nil), r.txs...)
+}
+
+// Flush relays all pending packets and acknowledgements on the open channels of the path between src and dst once,
+// and returns the transactions that were broadcast to do so along with the result of relaying each sequence.
+// With finalityGating set, packets sent on a rollapp are only relayed once finalized on the settlement layer.
+// Packets are only relayed once buried under the confirmation depth of the chain they were sent on.
+func Flush(
+	ctx context.Context,
+	log *zap.Logger,
+	src, dst *Chain,
+	filter ChannelFilter,
+	maxTxSize, maxMsgLength uint64,
+	memo string,
+	finalityGating bool,
+) FlushResult {
+	return flushConnections(ctx, log, pathConnections{src: src, dst: dst, filter: filter}, nil, maxTxSize, maxMsgLength, memo, finalityGating)
+}
+
+// flushConnections is Flush on the open channels of every connection relayed by a path,
+// each narrowed down by the filter of its connection. Channels paused in pauses are left out and listed in the result.
+func flushConnections(
+	ctx context.Context,
+	log *zap.Logger,
+	conns pathConnections,
+	pauses *channelPauses,
+	maxTxSize, maxMsgLength uint64,
+	memo string,
+	finalityGating bool,
+) FlushResult {
+	recorder := new(txRecorder)
+	ctx = withTxRecorder(ctx, recorder)
+	src, dst := conns.src, conns.dst
+
+	srcChannels, _, err := conns.channels(ctx)
+	if err != nil {
+		return FlushResult{Err: err}
+	}
+
+	var (
+		sequences SequenceResults
+		paused    []PausedChannel
+		errs      error
+	)
+	result := func(err error) FlushResult {
+		sort.Slice(paused, func(i, j int) bool {
+			if paused[i].PortID != paused[j].PortID {
+				return paused[i].PortID < paused[j].PortID
+			}
+			return paused[i].ChannelID < paused[j].ChannelID
+		})
+		return FlushResult{Txs: recorder.relayedTxs(), Sequences: sequences, Paused: paused, Err: err}
+	}
+	for _, channel := range filterOpenChannels(srcChannels) {
+		if pauses.isPaused(channel.channel.PortId, channel.channel.ChannelId) {
+			paused = append(paused, PausedChannel{PortID: channel.channel.PortId, ChannelID: channel.channel.ChannelId})
+			continue
+		}
+		srch, dsth, err := QueryLatestHeights(ctx, src, dst)
+		if err != nil {
+			return result(err)
+		}
+
+		sp, err := unrelayedPackets(ctx, src, dst, srch-1, dsth-1, channel.channel, finalityGating)
+		if err != nil {
+			return result(err)
+		}
+		if !sp.Empty() {
+			res, err := RelayPackets(ctx, log, src, dst, srch, dsth, sp, maxTxSize, maxMsgLength, memo, channel.channel)
+			sequences = append(sequences, res...)
+			if err != nil {
+				multierr.AppendInto(&errs, fmt.Errorf("relay packets on %s: %w", channel.channel.ChannelId, err))
+			}
+		}
+
+		ap := relayedAcknowledgements(ctx, src, dst, UnrelayedAcknowledgements(ctx, src, dst, srch-1, dsth-1, channel.channel))
+		if !ap.Empty() {
+			res, err := RelayAcknowledgements(ctx, log, src, dst, srch, dsth, ap, maxTxSize, maxMsgLength, memo, channel.channel)
+			sequences = append(sequences, res...)
+			if err != nil {
+				multierr.AppendInto(&errs, fmt.Errorf("relay acknowledgements on %s: %w", channel.channel.ChannelId, err))
+			}
+		}
+	}
+
+	return result(errs)
+}
+
+// FlushResult is the outcome of a flush, retained for replay under its idempotency key.
+// Paused lists the channels left out of the flush because they are paused.
+type FlushResult struct {
+	Txs       []RelayedTx
+	Sequences SequenceResults
+	Paused    []PausedChannel
+	Err       error
+}
+
+// PausedChannel identifies a paused channel of a path by its port and channel IDs on the src chain of the path.
+type PausedChannel struct {
+	PortID    string `json:"port_id"`
+	ChannelID string `json:"channel_id"`
+}
+
+type flushOp struct {
+	done     chan struct{}
+	result   FlushResult
+	finished time.Time
+}
+
+// flushDeduplicator ensures that flushes requested under the same idempotency key are only performed once.
+// Concurrent and later requests with a key that was already used are given the result of the original flush.
+type flushDeduplicator struct {
+	mu  sync.Mutex
+	ops map[string]*flushOp
+}
+
+func newFlushDeduplicator() *flushDeduplicator {
+	return &flushDeduplicator{ops: make(map[string]*flushOp)}
+}
+
+// do runs flush unless a flush with the same key has already been started,
+// in which case it waits for and returns that flush's result.
+// The returned bool reports whether the result was replayed from an earlier flush.
+// An empty key disables deduplication.
+func (d *flushDeduplicator) do(ctx context.Context, key string, flush func() FlushResult) (FlushResult, bool, error) {
+	if key == "" {
+		return flush(), false, nil
+	}
+
+	d.mu.Lock()
+	d.prune(time.Now())
+	op, ok := d.ops[key]
+	if !ok {
+		op = &flushOp{done: make(chan struct{})}
+		d.ops[key] = op
+	}
+	d.mu.Unlock()
+
+	if ok {
+		select {
+		case <-op.done:
+			return op.result, true, nil
+		case <-ctx.Done():
+			return FlushResult{}, true, ctx.Err()
+		}
+	}
+
+	op.result = flush()
+
+	d.mu.Lock()
+	op.finished = time.Now()
+	d.mu.Unlock()
+	close(op.done)
+
+	return op.result, false, nil
+}
+
+// prune removes results that are older than flushResultRetention. d.mu must be held.
+func (d *flushDeduplicator) prune(now time.Time) {
+	for key, op := range d.ops {
+		if !op.finished.IsZero() && now.Sub(op.finished) > flushResultRetention {
+			delete(d.ops, key)
+		}
+	}
+}
+
+// flush relays all pending packets and acknowledgements on the open channels of every connection of the path of r once,
+// leaving out its paused channels.
+func (s *supervisor) flush(ctx context.Context, r *pathRunner) FlushResult {
+	return flushConnections(s.flushContext(ctx, r), r.log, r.connections(), r.pauses, s.maxTxSize, s.maxMsgLength, s.memo, s.finalityGating)
+}
+
+// flushContext returns the context flushing the path of r with, applying its packet filter, rate limits and priority,
+// and the relayed direction.
+func (s *supervisor) flushContext(ctx context.Context, r *pathRunner) context.Context {
+	ctx = provider.WithPriority(provider.WithPathName(ctx, r.name), r.priority)
+	ctx = withPacketFilter(withMetrics(withRelayDirection(ctx, s.relayDirection), s.metrics, r.dst.ChainID()), r.packetFilter)
+	return withRelayAttempts(withRateLimiter(ctx, r.rateLimiter), s.relayAttempts)
+}
+
+// registerFlushHandlers exposes flushes of each path through the admin API.
+// Flushes run under ctx rather than the request context so that a flush is not abandoned halfway
+// when the caller disconnects, and its result stays available to a retry with the same idempotency key.
+//
+//	POST /flush relays all pending packets and acknowledgements of a path once.
+func registerFlushHandlers(ctx context.Context, srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/flush", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		var body admin.FlushRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		if body.IdempotencyKey == "" {
+			body.IdempotencyKey = req.Header.Get("Idempotency-Key")
+		}
+		r, ok := s.runners[body.Path]
+		if !ok {
+			admin.WriteError(w, http.StatusNotFound, fmt.Errorf("path %s not found", body.Path))
+			return
+		}
+
+		result, replayed, err := r.flushes.do(req.Context(), body.IdempotencyKey, func() FlushResult {
+			return s.flush(ctx, r)
+		})
+		if err != nil {
+			admin.WriteError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+
+		res := admin.FlushResponse{
+			Path:           body.Path,
+			IdempotencyKey: body.IdempotencyKey,
+			Replayed:       replayed,
+			Txs:            make([]admin.Tx, len(result.Txs)),
+			Sequences:      make([]admin.SequenceResult, len(result.Sequences)),
+			Paused:         make([]admin.ChannelRequest, len(result.Paused)),
+		}
+		for i, c := range result.Paused {
+			res.Paused[i] = admin.ChannelRequest{Path: body.Path, PortID: c.PortID, ChannelID: c.ChannelID}
+		}
+		for i, tx := range result.Txs {
+			res.Txs[i] = admin.Tx{ChainID: tx.ChainID, TxHash: tx.TxHash}
+		}
+		for i, seq := range result.Sequences {
+			res.Sequences[i] = admin.SequenceResult{
+				ChainID:   seq.ChainID,
+				ChannelID: seq.ChannelID,
+				PortID:    seq.PortID,
+				Sequence:  seq.Sequence,
+				MsgType:   seq.MsgType,
+				Status:    seq.Status,
+				Reason:    seq.Reason,
+				TxHash:    seq.TxHash,
+			}
+		}
+		code := http.StatusOK
+		if result.Err != nil {
+			res.Error = result.Err.Error()
+			code = http.StatusInternalServerError
+		}
+		admin.WriteJSON(w, code, res)
+	})
+}
@@ -0,0 +1,134 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFlushDeduplicator(t *testing.T) {
+	d := newFlushDeduplicator()
+	ctx := context.Background()
+
+	var calls int
+	var mu sync.Mutex
+	flush := func() FlushResult {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return FlushResult{Txs: []RelayedTx{{ChainID: "chain-a", TxHash: "ABC"}}, Err: errors.New("partial")}
+	}
+
+	res, replayed, err := d.do(ctx, "key-1", flush)
+	require.NoError(t, err)
+	require.False(t, replayed)
+	require.Equal(t, []RelayedTx{{ChainID: "chain-a", TxHash: "ABC"}}, res.Txs)
+
+	// A retry with the same key is given the original result without flushing again.
+	res, replayed, err = d.do(ctx, "key-1", flush)
+	require.NoError(t, err)
+	require.True(t, replayed)
+	require.EqualError(t, res.Err, "partial")
+	require.Equal(t, 1, calls)
+
+	_, replayed, err = d.do(ctx, "key-2", flush)
+	require.NoError(t, err)
+	require.False(t, replayed)
+	require.Equal(t, 2, calls)
+
+	// Without a key, every request flushes.
+	_, _, _ = d.do(ctx, "", flush)
+	_, _, _ = d.do(ctx, "", flush)
+	require.Equal(t, 4, calls)
+}
+
+// flushProvider serves channels whose only packet was received and acknowledged, recording the channels queried.
+type flushProvider struct {
+	clientConnectionsProvider
+
+	mu      sync.Mutex
+	queried map[string]bool
+}
+
+func (p *flushProvider) QueryPacketCommitments(_ context.Context, _ uint64, channelID, _ string) ([]*chantypes.PacketState, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.queried == nil {
+		p.queried = make(map[string]bool)
+	}
+	p.queried[channelID] = true
+	return []*chantypes.PacketState{{ChannelId: channelID, Sequence: 1}}, nil
+}
+
+func (p *flushProvider) QueryUnreceivedPackets(context.Context, uint64, string, string, []uint64) ([]uint64, error) {
+	return []uint64{}, nil
+}
+
+func (p *flushProvider) QueryPacketAcknowledgements(context.Context, uint64, string, string, []uint64) ([]*chantypes.PacketState, error) {
+	return []*chantypes.PacketState{}, nil
+}
+
+// queriedChannels returns the sorted channels whose packet commitments were queried.
+func (p *flushProvider) queriedChannels() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	channels := make([]string, 0, len(p.queried))
+	for channelID := range p.queried {
+		channels = append(channels, channelID)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+func TestFlushConnections(t *testing.T) {
+	hub := &flushProvider{clientConnectionsProvider: clientConnectionsProvider{
+		connections: []*conntypes.IdentifiedConnection{
+			testConnection("connection-1", "07-tendermint-0", "07-tendermint-5", "connection-8", conntypes.OPEN),
+		},
+		channels: map[string][]*chantypes.IdentifiedChannel{
+			"connection-0": {testConnectionChannel("transfer", "channel-0", "connection-0")},
+			"connection-1": {
+				testConnectionChannel("transfer", "channel-1", "connection-1"),
+				testConnectionChannel("icahost", "channel-2", "connection-1"),
+			},
+		},
+	}}
+	src := NewChain(zap.NewNop(), hub, false).withPathEnd(&PathEnd{ChainID: "hub", ClientID: "07-tendermint-0", ConnectionID: "connection-0"})
+	dst := NewChain(zap.NewNop(), &flushProvider{}, false).withPathEnd(&PathEnd{ChainID: "rollapp", ClientID: "07-tendermint-5", ConnectionID: "connection-7"})
+	conns := pathConnections{
+		src:     src,
+		dst:     dst,
+		further: []PathConnection{{ID: "connection-1", Filter: &ChannelFilter{Rule: allowList, ChannelList: []string{"icahost:*"}}}},
+	}
+
+	// The channels of further connections are flushed as well, narrowed down by their own filter.
+	res := flushConnections(context.Background(), zap.NewNop(), conns, nil, 0, 0, "", false)
+	require.NoError(t, res.Err)
+	require.Empty(t, res.Txs)
+	require.Empty(t, res.Paused)
+	require.Equal(t, []string{"channel-0", "channel-2"}, hub.queriedChannels())
+
+	// Paused channels are left out and listed.
+	hub.queried = nil
+	pauses := newChannelPauses("hub")
+	pauses.pause("icahost", "channel-2")
+	res = flushConnections(context.Background(), zap.NewNop(), conns, pauses, 0, 0, "", false)
+	require.NoError(t, res.Err)
+	require.Equal(t, []PausedChannel{{PortID: "icahost", ChannelID: "channel-2"}}, res.Paused)
+	require.Equal(t, []string{"channel-0"}, hub.queriedChannels())
+
+	// A hold leaves out every channel of the path.
+	hub.queried = nil
+	pauses.hold(pathPausedReason)
+	res = flushConnections(context.Background(), zap.NewNop(), conns, pauses, 0, 0, "", false)
+	require.NoError(t, res.Err)
+	require.Equal(t, []PausedChannel{{PortID: "icahost", ChannelID: "channel-2"}, {PortID: "transfer", ChannelID: "channel-0"}}, res.Paused)
+	require.Empty(t, hub.queriedChannels())
+}
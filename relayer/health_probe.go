@@ -0,0 +1,394 @@
+package relayer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Defaults of the health probes.
+const (
+	defaultHealthProbeInterval         = 30 * time.Second
+	defaultHealthProbeTimeout          = 5 * time.Second
+	defaultHealthProbeFailureThreshold = 3
+)
+
+// Actions taken on the paths gated by a failing health probe.
+const (
+	// HealthProbeDegrade reports the paths degraded, still relaying them.
+	HealthProbeDegrade = "degrade"
+
+	// HealthProbePause stops relaying every channel of the paths until the probe succeeds again.
+	HealthProbePause = "pause"
+)
+
+// States of a path gated by health probes.
+const (
+	PathHealthy  = "healthy"
+	PathDegraded = "degraded"
+	PathPaused   = "paused"
+)
+
+// HealthProbesConfig configures the health probes of `rly start`, which check infrastructure the relayed paths depend on
+// besides the RPC endpoints of their chains, such as the heartbeat endpoint of the sequencer of a rollapp.
+type HealthProbesConfig struct {
+	Probes []HealthProbe `yaml:"probes" json:"probes"`
+
+	// Webhooks are posted a HealthProbeAlert every time a probe starts and stops failing.
+	Webhooks []string `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+}
+
+// HealthProbe is a check of either an HTTP endpoint or a gRPC health service.
+type HealthProbe struct {
+	Name string `yaml:"name" json:"name"`
+
+	// HTTP is the URL of an endpoint to GET, healthy while it responds with a 2xx status.
+	HTTP string `yaml:"http,omitempty" json:"http,omitempty"`
+
+	// GRPC is the address of a server implementing the grpc.health.v1 health checking protocol,
+	// healthy while it reports GRPCService, the whole server if empty, as SERVING.
+	GRPC        string `yaml:"grpc,omitempty" json:"grpc,omitempty"`
+	GRPCService string `yaml:"grpc-service,omitempty" json:"grpc-service,omitempty"`
+	GRPCTLS     bool   `yaml:"grpc-tls,omitempty" json:"grpc-tls,omitempty"`
+
+	// Interval is how often the probe is run, every 30 seconds if unset.
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+
+	// Timeout bounds each run of the probe, 5 seconds if unset.
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// FailureThreshold is how many consecutive runs must fail for the probe to be failing, 3 if unset.
+	FailureThreshold int `yaml:"failure-threshold,omitempty" json:"failure-threshold,omitempty"`
+
+	// Paths are the names of the paths gated by the probe, none if it only alerts.
+	Paths []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+
+	// Action is what happens to the paths while the probe is failing, HealthProbeDegrade if unset, or HealthProbePause.
+	Action string `yaml:"action,omitempty" json:"action,omitempty"`
+}
+
+// Validate checks the health probes config, paths being the paths of the config the probes may gate.
+func (c *HealthProbesConfig) Validate(paths Paths) error {
+	if c == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(c.Probes))
+	for _, p := range c.Probes {
+		if p.Name == "" {
+			return fmt.Errorf("health probe without name")
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("health probe %s is configured more than once", p.Name)
+		}
+		seen[p.Name] = true
+		if err := p.validate(paths); err != nil {
+			return fmt.Errorf("health probe %s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p HealthProbe) validate(paths Paths) error {
+	switch {
+	case p.HTTP != "" && p.GRPC != "":
+		return fmt.Errorf("only one of http and grpc can be set")
+	case p.HTTP != "":
+		u, err := url.Parse(p.HTTP)
+		if err != nil {
+			return err
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("http must be an http or https URL, got %s", p.HTTP)
+		}
+	case p.GRPC == "":
+		return fmt.Errorf("either http or grpc must be set")
+	}
+	if p.Interval < 0 || p.Timeout < 0 || p.FailureThreshold < 0 {
+		return fmt.Errorf("interval, timeout and failure-threshold must not be negative")
+	}
+	if p.Action != "" && p.Action != HealthProbeDegrade && p.Action != HealthProbePause {
+		return fmt.Errorf("action must be %s or %s, got %s", HealthProbeDegrade, HealthProbePause, p.Action)
+	}
+	for _, name := range p.Paths {
+		if _, ok := paths[name]; !ok {
+			return fmt.Errorf("path %s not found", name)
+		}
+	}
+	return nil
+}
+
+// withDefaults returns p with its unset interval, timeout, failure threshold and action defaulted.
+func (p HealthProbe) withDefaults() HealthProbe {
+	if p.Interval == 0 {
+		p.Interval = defaultHealthProbeInterval
+	}
+	if p.Timeout == 0 {
+		p.Timeout = defaultHealthProbeTimeout
+	}
+	if p.FailureThreshold == 0 {
+		p.FailureThreshold = defaultHealthProbeFailureThreshold
+	}
+	if p.Action == "" {
+		p.Action = HealthProbeDegrade
+	}
+	return p
+}
+
+// target returns the endpoint checked by p.
+func (p HealthProbe) target() string {
+	if p.HTTP != "" {
+		return p.HTTP
+	}
+	return p.GRPC
+}
+
+// check runs p once, returning why it failed.
+func (p HealthProbe) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+	if p.HTTP != "" {
+		return checkHTTP(ctx, p.HTTP)
+	}
+	return checkGRPCHealth(ctx, p.GRPC, p.GRPCService, p.GRPCTLS)
+}
+
+func checkHTTP(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return nil
+}
+
+func checkGRPCHealth(ctx context.Context, addr, service string, useTLS bool) error {
+	creds := insecure.NewCredentials()
+	if useTLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return err
+	}
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("service reported %s", res.Status)
+	}
+	return nil
+}
+
+// HealthProbeStatus is the state of a health probe as last run.
+type HealthProbeStatus struct {
+	Name   string   `json:"name"`
+	Target string   `json:"target"`
+	Action string   `json:"action"`
+	Paths  []string `json:"paths,omitempty"`
+
+	// Failing is whether the probe failed as many consecutive times as its failure threshold, and has not succeeded since.
+	Failing             bool       `json:"failing"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailingSince        *time.Time `json:"failing_since,omitempty"`
+	Error               string     `json:"error,omitempty"`
+	CheckedAt           time.Time  `json:"checked_at"`
+}
+
+// PathHealth is the state of a path gated by health probes.
+type PathHealth struct {
+	Path  string `json:"path"`
+	State string `json:"state"`
+
+	// FailingProbes are the names of the failing probes gating the path.
+	FailingProbes []string `json:"failing_probes,omitempty"`
+}
+
+// HealthProbeAlert is posted to the webhooks of the health probes when a probe starts failing, or recovers.
+type HealthProbeAlert struct {
+	Resolved bool              `json:"resolved"`
+	Probe    HealthProbeStatus `json:"probe"`
+}
+
+// HealthProbes is the state of the health probes reported in the admin API status.
+type HealthProbes struct {
+	Probes []HealthProbeStatus `json:"probes"`
+	Paths  []PathHealth        `json:"paths"`
+}
+
+// healthProber runs the health probes, pausing the paths gated by failing probes with the pause action.
+type healthProber struct {
+	log      *zap.Logger
+	metrics  *processor.PrometheusMetrics
+	webhooks []string
+
+	// runners are the runners of the relayed paths, by path name.
+	runners map[string]*pathRunner
+
+	// changes publishes the paths held and released, if non-nil.
+	changes *changefeed
+
+	mu       sync.Mutex
+	statuses map[string]HealthProbeStatus
+}
+
+func newHealthProber(log *zap.Logger, metrics *processor.PrometheusMetrics, webhooks []string, runners map[string]*pathRunner) *healthProber {
+	return &healthProber{
+		log:      log,
+		metrics:  metrics,
+		webhooks: webhooks,
+		runners:  runners,
+		statuses: make(map[string]HealthProbeStatus),
+	}
+}
+
+// record records the result of a run of p, pausing or resuming its paths and alerting as it starts or stops failing.
+func (hp *healthProber) record(ctx context.Context, p HealthProbe, err error) {
+	if ctx.Err() != nil {
+		// runs interrupted by the shutdown are not failures
+		return
+	}
+	now := time.Now().UTC()
+
+	hp.mu.Lock()
+	prev, ok := hp.statuses[p.Name]
+	if !ok {
+		prev = HealthProbeStatus{Name: p.Name, Target: p.target(), Action: p.Action, Paths: p.Paths}
+	}
+	st := prev
+	st.CheckedAt = now
+	if err != nil {
+		st.ConsecutiveFailures++
+		st.Error = err.Error()
+		if !st.Failing && st.ConsecutiveFailures >= p.FailureThreshold {
+			st.Failing, st.FailingSince = true, &now
+		}
+	} else {
+		st.ConsecutiveFailures, st.Error = 0, ""
+		st.Failing, st.FailingSince = false, nil
+	}
+	hp.statuses[p.Name] = st
+	hp.mu.Unlock()
+
+	if err != nil && !st.Failing {
+		hp.log.Warn(
+			"Health probe failed",
+			zap.String("probe", p.Name),
+			zap.String("target", st.Target),
+			zap.Int("consecutive_failures", st.ConsecutiveFailures),
+			zap.Error(err),
+		)
+	}
+	if st.Failing == prev.Failing {
+		return
+	}
+
+	hp.metrics.SetHealthProbeFailing(p.Name, st.Failing)
+	if p.Action == HealthProbePause {
+		hp.setPaused(p, st.Failing)
+	}
+	fields := []zap.Field{
+		zap.String("probe", p.Name),
+		zap.String("target", st.Target),
+		zap.String("action", p.Action),
+		zap.Strings("paths", p.Paths),
+	}
+	if st.Failing {
+		hp.log.Error("Health probe failing", append(fields, zap.Int("consecutive_failures", st.ConsecutiveFailures), zap.String("error", st.Error))...)
+	} else {
+		hp.log.Info("Health probe recovered", fields...)
+	}
+	if err := postWebhooks(ctx, hp.webhooks, HealthProbeAlert{Resolved: !st.Failing, Probe: st}); err != nil {
+		hp.log.Warn("Failed to post health probe alert", zap.String("probe", p.Name), zap.Error(err))
+	}
+}
+
+// setPaused holds or releases every channel of the relayed paths gated by p.
+func (hp *healthProber) setPaused(p HealthProbe, paused bool) {
+	reason := "health probe " + p.Name
+	for _, name := range p.Paths {
+		r, ok := hp.runners[name]
+		if !ok {
+			continue
+		}
+		if paused {
+			if r.pauses.hold(reason) {
+				hp.changes.record(feed.Change{Kind: feed.ChangePathHeld, Cause: feed.CauseHealthProbe, Path: name, Reason: reason}, name)
+			}
+		} else if r.pauses.release(reason) {
+			hp.changes.record(feed.Change{Kind: feed.ChangePathReleased, Cause: feed.CauseHealthProbe, Path: name, Reason: reason}, name)
+		}
+	}
+}
+
+// snapshot returns the last state of every probe, by name, and the state of the relayed paths they gate, by path name.
+func (hp *healthProber) snapshot() HealthProbes {
+	hp.mu.Lock()
+	defer hp.mu.Unlock()
+
+	res := HealthProbes{Probes: make([]HealthProbeStatus, 0, len(hp.statuses)), Paths: []PathHealth{}}
+	paths := make(map[string]*PathHealth)
+	for _, st := range hp.statuses {
+		res.Probes = append(res.Probes, st)
+		for _, name := range st.Paths {
+			if _, ok := hp.runners[name]; !ok {
+				continue
+			}
+			ph, ok := paths[name]
+			if !ok {
+				ph = &PathHealth{Path: name, State: PathHealthy}
+				paths[name] = ph
+			}
+			if !st.Failing {
+				continue
+			}
+			ph.FailingProbes = append(ph.FailingProbes, st.Name)
+			if st.Action == HealthProbePause {
+				ph.State = PathPaused
+			} else if ph.State == PathHealthy {
+				ph.State = PathDegraded
+			}
+		}
+	}
+	sort.Slice(res.Probes, func(i, k int) bool { return res.Probes[i].Name < res.Probes[k].Name })
+	for _, ph := range paths {
+		sort.Strings(ph.FailingProbes)
+		res.Paths = append(res.Paths, *ph)
+	}
+	sort.Slice(res.Paths, func(i, k int) bool { return res.Paths[i].Path < res.Paths[k].Path })
+	return res
+}
+
+// startHealthProbes starts running the health probes of cfg.
+func (s *supervisor) startHealthProbes(ctx context.Context, cfg *HealthProbesConfig) *healthProber {
+	hp := newHealthProber(s.log.With(zap.String("sys", "healthprobes")), s.metrics, cfg.Webhooks, s.runners)
+	hp.changes = s.changes
+	for _, p := range cfg.Probes {
+		p := p.withDefaults()
+		s.maintenance.schedule(ctx, TaskHealthProbe+"/"+p.Name, p.Interval, func(ctx context.Context) {
+			hp.record(ctx, p, p.check(ctx))
+		})
+	}
+	return hp
+}
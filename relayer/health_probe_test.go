@@ -0,0 +1,139 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthProbesConfigValidate(t *testing.T) {
+	paths := Paths{"hub-rollapp": &Path{}}
+	valid := HealthProbe{Name: "sequencer", HTTP: "http://sequencer:8080/health", Paths: []string{"hub-rollapp"}, Action: HealthProbePause}
+	require.NoError(t, (&HealthProbesConfig{Probes: []HealthProbe{valid}}).Validate(paths))
+
+	var unset *HealthProbesConfig
+	require.NoError(t, unset.Validate(paths))
+
+	for _, tc := range []struct {
+		probe HealthProbe
+		err   string
+	}{
+		{HealthProbe{HTTP: valid.HTTP}, "without name"},
+		{HealthProbe{Name: "sequencer"}, "either http or grpc"},
+		{HealthProbe{Name: "sequencer", HTTP: valid.HTTP, GRPC: "sequencer:9090"}, "only one of http and grpc"},
+		{HealthProbe{Name: "sequencer", HTTP: "tcp://sequencer:8080"}, "http or https URL"},
+		{HealthProbe{Name: "sequencer", GRPC: "sequencer:9090", Action: "halt"}, "action must be"},
+		{HealthProbe{Name: "sequencer", GRPC: "sequencer:9090", Interval: -time.Second}, "must not be negative"},
+		{HealthProbe{Name: "sequencer", GRPC: "sequencer:9090", Paths: []string{"unknown"}}, "path unknown not found"},
+	} {
+		require.ErrorContains(t, (&HealthProbesConfig{Probes: []HealthProbe{tc.probe}}).Validate(paths), tc.err)
+	}
+
+	dup := &HealthProbesConfig{Probes: []HealthProbe{valid, valid}}
+	require.ErrorContains(t, dup.Validate(paths), "configured more than once")
+}
+
+func TestHealthProbeCheckHTTP(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	p := HealthProbe{Name: "sequencer", HTTP: srv.URL}.withDefaults()
+	require.NoError(t, p.check(context.Background()))
+	status = http.StatusServiceUnavailable
+	require.ErrorContains(t, p.check(context.Background()), "503")
+}
+
+func TestHealthProbeCheckGRPC(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	hs := health.NewServer()
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Stop()
+
+	p := HealthProbe{Name: "da-layer", GRPC: ln.Addr().String(), GRPCService: "celestia"}.withDefaults()
+	hs.SetServingStatus("celestia", healthpb.HealthCheckResponse_SERVING)
+	require.NoError(t, p.check(context.Background()))
+	hs.SetServingStatus("celestia", healthpb.HealthCheckResponse_NOT_SERVING)
+	require.ErrorContains(t, p.check(context.Background()), "NOT_SERVING")
+}
+
+func TestHealthProberGatesPaths(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		alerts []HealthProbeAlert
+	)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var a HealthProbeAlert
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&a))
+		mu.Lock()
+		alerts = append(alerts, a)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	runners := map[string]*pathRunner{
+		"hub-rollapp": {name: "hub-rollapp", pauses: newChannelPauses("hub")},
+		"hub-osmosis": {name: "hub-osmosis", pauses: newChannelPauses("hub")},
+	}
+	hp := newHealthProber(zap.NewNop(), nil, []string{webhook.URL}, runners)
+	sequencer := HealthProbe{Name: "sequencer", HTTP: "http://sequencer/health", Paths: []string{"hub-rollapp"}, Action: HealthProbePause, FailureThreshold: 2}.withDefaults()
+	explorer := HealthProbe{Name: "explorer", GRPC: "explorer:9090", Paths: []string{"hub-rollapp", "hub-osmosis"}}.withDefaults()
+	ctx := context.Background()
+
+	// Failures below the threshold do not gate the paths.
+	hp.record(ctx, sequencer, errors.New("connection refused"))
+	hp.record(ctx, explorer, nil)
+	require.False(t, runners["hub-rollapp"].pauses.isPaused("transfer", "channel-0"))
+	require.Equal(t, []PathHealth{
+		{Path: "hub-osmosis", State: PathHealthy},
+		{Path: "hub-rollapp", State: PathHealthy},
+	}, hp.snapshot().Paths)
+
+	hp.record(ctx, sequencer, errors.New("connection refused"))
+	for i := 0; i < explorer.FailureThreshold; i++ {
+		hp.record(ctx, explorer, errors.New("deadline exceeded"))
+	}
+	require.True(t, runners["hub-rollapp"].pauses.isPaused("transfer", "channel-0"))
+	require.False(t, runners["hub-osmosis"].pauses.isPaused("transfer", "channel-0"), "degraded paths are still relayed")
+	snap := hp.snapshot()
+	require.Equal(t, []PathHealth{
+		{Path: "hub-osmosis", State: PathDegraded, FailingProbes: []string{"explorer"}},
+		{Path: "hub-rollapp", State: PathPaused, FailingProbes: []string{"explorer", "sequencer"}},
+	}, snap.Paths)
+	require.Len(t, snap.Probes, 2)
+	require.Equal(t, "sequencer", snap.Probes[1].Name)
+	require.True(t, snap.Probes[1].Failing)
+	require.Equal(t, 2, snap.Probes[1].ConsecutiveFailures)
+	require.NotNil(t, snap.Probes[1].FailingSince)
+
+	// A single success recovers the probe.
+	hp.record(ctx, sequencer, nil)
+	require.False(t, runners["hub-rollapp"].pauses.isPaused("transfer", "channel-0"))
+	require.Equal(t, PathDegraded, hp.snapshot().Paths[1].State)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, alerts, 3)
+	require.Equal(t, "sequencer", alerts[0].Probe.Name)
+	require.False(t, alerts[0].Resolved)
+	require.Equal(t, "explorer", alerts[1].Probe.Name)
+	require.Equal(t, "sequencer", alerts[2].Probe.Name)
+	require.True(t, alerts[2].Resolved)
+}
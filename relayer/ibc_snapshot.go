@@ -0,0 +1,281 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+)
+
+// IBCStateSnapshotVersion is the version of the format of IBCStateSnapshot, increased on breaking changes.
+const IBCStateSnapshotVersion = 1
+
+// IBCStateSnapshot is the IBC state of paths on both of their chains, each pinned at a single height,
+// for third-party audits of the health of the bridges. It only holds plain values, so that it can be checked
+// without the relayer nor the protobuf definitions of IBC.
+type IBCStateSnapshot struct {
+	Version   int                 `json:"version"`
+	CreatedAt time.Time           `json:"created_at"`
+	Paths     []PathStateSnapshot `json:"paths"`
+}
+
+// PathStateSnapshot is the IBC state of a path on both of its chains.
+type PathStateSnapshot struct {
+	Path string             `json:"path"`
+	Src  ChainStateSnapshot `json:"src"`
+	Dst  ChainStateSnapshot `json:"dst"`
+}
+
+// ChainStateSnapshot is the IBC state of an end of a path on its chain at Height: its client and connection,
+// and its channels relayed according to the channel filter of the path.
+type ChainStateSnapshot struct {
+	ChainID    string             `json:"chain_id"`
+	Height     int64              `json:"height"`
+	Client     ClientSnapshot     `json:"client"`
+	Connection ConnectionSnapshot `json:"connection"`
+	Channels   []ChannelSnapshot  `json:"channels"`
+}
+
+// ClientSnapshot is the state of the client of an end of a path.
+type ClientSnapshot struct {
+	ClientID     string `json:"client_id"`
+	ClientType   string `json:"client_type"`
+	LatestHeight string `json:"latest_height"`
+
+	// ChainID, TrustingPeriod and FrozenHeight are only set for tendermint clients, FrozenHeight once frozen.
+	ChainID        string `json:"chain_id,omitempty"`
+	TrustingPeriod string `json:"trusting_period,omitempty"`
+	FrozenHeight   string `json:"frozen_height,omitempty"`
+}
+
+// ConnectionSnapshot is the state of the connection of an end of a path.
+type ConnectionSnapshot struct {
+	ConnectionID             string `json:"connection_id"`
+	State                    string `json:"state"`
+	CounterpartyClientID     string `json:"counterparty_client_id"`
+	CounterpartyConnectionID string `json:"counterparty_connection_id"`
+}
+
+// ChannelSnapshot is the state of a channel end, with the sequences of the packets sent on it whose commitments
+// are still stored, i.e. neither acknowledged nor timed out.
+type ChannelSnapshot struct {
+	PortID                string   `json:"port_id"`
+	ChannelID             string   `json:"channel_id"`
+	State                 string   `json:"state"`
+	Ordering              string   `json:"ordering"`
+	Version               string   `json:"version"`
+	CounterpartyPortID    string   `json:"counterparty_port_id"`
+	CounterpartyChannelID string   `json:"counterparty_channel_id"`
+	PacketCommitments     []uint64 `json:"packet_commitments"`
+}
+
+// SnapshotIBCState returns the IBC state of paths, whose chains are in chains by chain ID.
+// The state of each chain is queried at the height of heights by chain ID, which is set to the latest height
+// of the chains missing from it, so that every path of a chain is snapshotted at the same height.
+func SnapshotIBCState(ctx context.Context, paths []NamedPath, chains map[string]*Chain, heights map[string]int64) (IBCStateSnapshot, error) {
+	snapshot := IBCStateSnapshot{Version: IBCStateSnapshotVersion, CreatedAt: time.Now().UTC(), Paths: make([]PathStateSnapshot, 0, len(paths))}
+	for _, p := range paths {
+		ends := make([]*Chain, 0, 2)
+		for _, pe := range []*PathEnd{p.Path.Src, p.Path.Dst} {
+			c, ok := chains[pe.ChainID]
+			if !ok {
+				return IBCStateSnapshot{}, fmt.Errorf("chain %s of path %s is not configured", pe.ChainID, p.Name)
+			}
+			if _, ok := heights[pe.ChainID]; !ok {
+				h, err := c.ChainProvider.QueryLatestHeight(ctx)
+				if err != nil {
+					return IBCStateSnapshot{}, fmt.Errorf("failed to query latest height of %s: %w", pe.ChainID, err)
+				}
+				heights[pe.ChainID] = h
+			}
+			ends = append(ends, c.withPathEnd(pe))
+		}
+		ps, err := snapshotPath(ctx, p, ends[0], ends[1], heights)
+		if err != nil {
+			return IBCStateSnapshot{}, fmt.Errorf("failed to snapshot path %s: %w", p.Name, err)
+		}
+		snapshot.Paths = append(snapshot.Paths, ps)
+	}
+	return snapshot, nil
+}
+
+// snapshotPath returns the IBC state of the path p between src and dst.
+func snapshotPath(ctx context.Context, p NamedPath, src, dst *Chain, heights map[string]int64) (PathStateSnapshot, error) {
+	ps := PathStateSnapshot{Path: p.Name}
+	var err error
+	if ps.Src, err = snapshotChainEnd(ctx, src, heights[src.ChainID()]); err != nil {
+		return ps, err
+	}
+	if ps.Dst, err = snapshotChainEnd(ctx, dst, heights[dst.ChainID()]); err != nil {
+		return ps, err
+	}
+
+	srch := heights[src.ChainID()]
+	channels, err := src.ChainProvider.QueryConnectionChannels(ctx, srch, src.ConnectionID())
+	if err != nil {
+		return ps, fmt.Errorf("failed to query channels of %s on %s: %w", src.ConnectionID(), src.ChainID(), err)
+	}
+	channels = applyChannelFilterRule(p.Path.Filter, channels)
+	sort.Slice(channels, func(i, k int) bool { return channels[i].ChannelId < channels[k].ChannelId })
+	for _, ch := range channels {
+		cs, err := snapshotChannel(ctx, src, srch, ch.PortId, ch.ChannelId, chantypes.NewChannel(ch.State, ch.Ordering, ch.Counterparty, ch.ConnectionHops, ch.Version))
+		if err != nil {
+			return ps, err
+		}
+		ps.Src.Channels = append(ps.Src.Channels, cs)
+
+		if ch.Counterparty.ChannelId == "" {
+			// The channel is still being opened, its counterparty channel end is not created yet.
+			continue
+		}
+		dsth := heights[dst.ChainID()]
+		res, err := dst.ChainProvider.QueryChannel(ctx, dsth, ch.Counterparty.ChannelId, ch.Counterparty.PortId)
+		if err != nil {
+			return ps, fmt.Errorf("failed to query channel %s on %s: %w", ch.Counterparty.ChannelId, dst.ChainID(), err)
+		}
+		cs, err = snapshotChannel(ctx, dst, dsth, ch.Counterparty.PortId, ch.Counterparty.ChannelId, *res.Channel)
+		if err != nil {
+			return ps, err
+		}
+		ps.Dst.Channels = append(ps.Dst.Channels, cs)
+	}
+	return ps, nil
+}
+
+// snapshotChainEnd returns the state of the client and connection of the path end of c at height.
+func snapshotChainEnd(ctx context.Context, c *Chain, height int64) (ChainStateSnapshot, error) {
+	cs := ChainStateSnapshot{ChainID: c.ChainID(), Height: height, Channels: []ChannelSnapshot{}}
+	clientState, err := c.ChainProvider.QueryClientState(ctx, height, c.ClientID())
+	if err != nil {
+		return cs, fmt.Errorf("failed to query client %s on %s: %w", c.ClientID(), c.ChainID(), err)
+	}
+	cs.Client = ClientSnapshot{
+		ClientID:     c.ClientID(),
+		ClientType:   clientState.ClientType(),
+		LatestHeight: clientState.GetLatestHeight().String(),
+	}
+	if tmcs, ok := clientState.(*tmclient.ClientState); ok {
+		cs.Client.ChainID = tmcs.ChainId
+		cs.Client.TrustingPeriod = tmcs.TrustingPeriod.String()
+		if !tmcs.FrozenHeight.IsZero() {
+			cs.Client.FrozenHeight = tmcs.FrozenHeight.String()
+		}
+	}
+
+	conn, err := c.ChainProvider.QueryConnection(ctx, height, c.ConnectionID())
+	if err != nil {
+		return cs, fmt.Errorf("failed to query connection %s on %s: %w", c.ConnectionID(), c.ChainID(), err)
+	}
+	cs.Connection = ConnectionSnapshot{
+		ConnectionID:             c.ConnectionID(),
+		State:                    conn.Connection.State.String(),
+		CounterpartyClientID:     conn.Connection.Counterparty.ClientId,
+		CounterpartyConnectionID: conn.Connection.Counterparty.ConnectionId,
+	}
+	return cs, nil
+}
+
+// snapshotChannel returns the state of the channel end of c at height, with its pending packet commitments.
+func snapshotChannel(ctx context.Context, c *Chain, height int64, portID, channelID string, ch chantypes.Channel) (ChannelSnapshot, error) {
+	cs := ChannelSnapshot{
+		PortID:                portID,
+		ChannelID:             channelID,
+		State:                 ch.State.String(),
+		Ordering:              ch.Ordering.String(),
+		Version:               ch.Version,
+		CounterpartyPortID:    ch.Counterparty.PortId,
+		CounterpartyChannelID: ch.Counterparty.ChannelId,
+		PacketCommitments:     []uint64{},
+	}
+	commitments, err := c.ChainProvider.QueryPacketCommitments(ctx, uint64(height), channelID, portID)
+	if err != nil {
+		return cs, fmt.Errorf("failed to query packet commitments of %s/%s on %s: %w", portID, channelID, c.ChainID(), err)
+	}
+	for _, pc := range commitments {
+		cs.PacketCommitments = append(cs.PacketCommitments, pc.Sequence)
+	}
+	sort.Slice(cs.PacketCommitments, func(i, k int) bool { return cs.PacketCommitments[i] < cs.PacketCommitments[k] })
+	return cs, nil
+}
+
+// SignedIBCStateSnapshot is an IBCStateSnapshot along with the signature of its exact bytes, kept as they were signed.
+type SignedIBCStateSnapshot struct {
+	Snapshot  json.RawMessage   `json:"snapshot"`
+	Signature SnapshotSignature `json:"signature"`
+}
+
+// SnapshotSignature is the signature of a snapshot by the key of the relayer on a chain.
+// Secp256k1 signatures are the 64 bytes r || s of the ECDSA signature of the SHA-256 digest of the snapshot,
+// ed25519 signatures are those of the snapshot itself. Byte fields are base64 encoded.
+type SnapshotSignature struct {
+	KeyType   string `json:"key_type"`
+	PubKey    []byte `json:"pub_key"`
+	Signature []byte `json:"signature"`
+
+	// ChainID and Address are the chain of the key and its address on the chain, which auditors may know the relayer by.
+	ChainID string `json:"chain_id"`
+	Address string `json:"address"`
+}
+
+// snapshotSigner is implemented by the providers signing arbitrary bytes with the key of the relayer.
+type snapshotSigner interface {
+	SignBytes(msg []byte) ([]byte, cryptotypes.PubKey, error)
+}
+
+// SignIBCStateSnapshot signs snapshot with the key of the relayer on c.
+func SignIBCStateSnapshot(snapshot IBCStateSnapshot, c *Chain) (SignedIBCStateSnapshot, error) {
+	signer, ok := c.ChainProvider.(snapshotSigner)
+	if !ok {
+		return SignedIBCStateSnapshot{}, fmt.Errorf("chain %s cannot sign snapshots, its provider is %s", c.ChainID(), c.ChainProvider.Type())
+	}
+	bz, err := json.Marshal(snapshot)
+	if err != nil {
+		return SignedIBCStateSnapshot{}, err
+	}
+	sig, pk, err := signer.SignBytes(bz)
+	if err != nil {
+		return SignedIBCStateSnapshot{}, fmt.Errorf("failed to sign snapshot with the key of %s: %w", c.ChainID(), err)
+	}
+	addr, err := c.ChainProvider.Address()
+	if err != nil {
+		return SignedIBCStateSnapshot{}, err
+	}
+	return SignedIBCStateSnapshot{
+		Snapshot: bz,
+		Signature: SnapshotSignature{
+			KeyType:   pk.Type(),
+			PubKey:    pk.Bytes(),
+			Signature: sig,
+			ChainID:   c.ChainID(),
+			Address:   addr,
+		},
+	}, nil
+}
+
+// Verify returns the snapshot of s if its signature is valid for its public key.
+func (s SignedIBCStateSnapshot) Verify() (IBCStateSnapshot, error) {
+	var pk cryptotypes.PubKey
+	switch s.Signature.KeyType {
+	case (&secp256k1.PubKey{}).Type():
+		pk = &secp256k1.PubKey{Key: s.Signature.PubKey}
+	case (&ed25519.PubKey{}).Type():
+		pk = &ed25519.PubKey{Key: s.Signature.PubKey}
+	default:
+		return IBCStateSnapshot{}, fmt.Errorf("unsupported key type %q", s.Signature.KeyType)
+	}
+	if !pk.VerifySignature(s.Snapshot, s.Signature.Signature) {
+		return IBCStateSnapshot{}, fmt.Errorf("invalid signature of snapshot")
+	}
+	var snapshot IBCStateSnapshot
+	if err := json.Unmarshal(s.Snapshot, &snapshot); err != nil {
+		return IBCStateSnapshot{}, err
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,155 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotProvider serves the IBC state of a chain, recording the heights it is queried at.
+type snapshotProvider struct {
+	provider.ChainProvider
+	chainID      string
+	counterparty string
+	channels     []*chantypes.IdentifiedChannel
+	commitments  map[string][]uint64
+	key          *secp256k1.PrivKey
+
+	heights map[int64]bool
+}
+
+func (p *snapshotProvider) ChainId() string { return p.chainID }
+
+func (p *snapshotProvider) Address() (string, error) { return "cosmos1relayer", nil }
+
+func (p *snapshotProvider) QueryLatestHeight(context.Context) (int64, error) { return 42, nil }
+
+func (p *snapshotProvider) QueryClientState(_ context.Context, height int64, _ string) (ibcexported.ClientState, error) {
+	p.heights[height] = true
+	return &tmclient.ClientState{ChainId: p.counterparty, TrustingPeriod: time.Hour, LatestHeight: clienttypes.NewHeight(1, 30)}, nil
+}
+
+func (p *snapshotProvider) QueryConnection(_ context.Context, height int64, _ string) (*conntypes.QueryConnectionResponse, error) {
+	p.heights[height] = true
+	return &conntypes.QueryConnectionResponse{Connection: &conntypes.ConnectionEnd{
+		State:        conntypes.OPEN,
+		Counterparty: conntypes.Counterparty{ClientId: "07-tendermint-0", ConnectionId: "connection-0"},
+	}}, nil
+}
+
+func (p *snapshotProvider) QueryConnectionChannels(_ context.Context, height int64, _ string) ([]*chantypes.IdentifiedChannel, error) {
+	p.heights[height] = true
+	return p.channels, nil
+}
+
+func (p *snapshotProvider) QueryChannel(_ context.Context, height int64, channelID, portID string) (*chantypes.QueryChannelResponse, error) {
+	p.heights[height] = true
+	for _, c := range p.channels {
+		if c.ChannelId == channelID && c.PortId == portID {
+			ch := chantypes.NewChannel(c.State, c.Ordering, c.Counterparty, c.ConnectionHops, c.Version)
+			return &chantypes.QueryChannelResponse{Channel: &ch}, nil
+		}
+	}
+	return nil, chantypes.ErrChannelNotFound
+}
+
+func (p *snapshotProvider) QueryPacketCommitments(_ context.Context, height uint64, channelID, _ string) ([]*chantypes.PacketState, error) {
+	p.heights[int64(height)] = true
+	var res []*chantypes.PacketState
+	for _, seq := range p.commitments[channelID] {
+		res = append(res, &chantypes.PacketState{Sequence: seq})
+	}
+	return res, nil
+}
+
+func (p *snapshotProvider) SignBytes(msg []byte) ([]byte, cryptotypes.PubKey, error) {
+	sig, err := p.key.Sign(msg)
+	return sig, p.key.PubKey(), err
+}
+
+func TestSnapshotIBCState(t *testing.T) {
+	identifiedChannel := func(channelID, counterpartyChannelID string) *chantypes.IdentifiedChannel {
+		return &chantypes.IdentifiedChannel{
+			State: chantypes.OPEN, Ordering: chantypes.UNORDERED, Version: "ics20-1",
+			Counterparty: chantypes.Counterparty{PortId: "transfer", ChannelId: counterpartyChannelID},
+			PortId:       "transfer", ChannelId: channelID,
+		}
+	}
+	a := &snapshotProvider{
+		chainID: "chain-a", counterparty: "chain-b",
+		channels:    []*chantypes.IdentifiedChannel{identifiedChannel("channel-1", "channel-3"), identifiedChannel("channel-0", "channel-2")},
+		commitments: map[string][]uint64{"channel-0": {7, 5}},
+		key:         secp256k1.GenPrivKey(),
+		heights:     map[int64]bool{},
+	}
+	b := &snapshotProvider{
+		chainID: "chain-b", counterparty: "chain-a",
+		channels:    []*chantypes.IdentifiedChannel{identifiedChannel("channel-2", "channel-0"), identifiedChannel("channel-3", "channel-1")},
+		commitments: map[string][]uint64{"channel-2": {1}},
+		heights:     map[int64]bool{},
+	}
+	chains := map[string]*Chain{"chain-a": {Chainid: "chain-a", ChainProvider: a}, "chain-b": {Chainid: "chain-b", ChainProvider: b}}
+	paths := []NamedPath{{Name: "a-b", Path: &Path{
+		Src:    &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0", ConnectionID: "connection-0"},
+		Dst:    &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-0", ConnectionID: "connection-0"},
+		Filter: ChannelFilter{Rule: denyList, ChannelList: []string{"channel-1"}},
+	}}}
+
+	// Chains are queried at their pinned height, or at their latest height.
+	heights := map[string]int64{"chain-a": 10}
+	snapshot, err := SnapshotIBCState(context.Background(), paths, chains, heights)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]bool{10: true}, a.heights)
+	require.Equal(t, map[int64]bool{42: true}, b.heights)
+	require.Equal(t, map[string]int64{"chain-a": 10, "chain-b": 42}, heights)
+
+	require.Equal(t, IBCStateSnapshotVersion, snapshot.Version)
+	require.Len(t, snapshot.Paths, 1)
+	ps := snapshot.Paths[0]
+	require.Equal(t, "a-b", ps.Path)
+	require.Equal(t, ClientSnapshot{
+		ClientID:       "07-tendermint-0",
+		ClientType:     ibcexported.Tendermint,
+		LatestHeight:   "1-30",
+		ChainID:        "chain-b",
+		TrustingPeriod: "1h0m0s",
+	}, ps.Src.Client)
+	require.Equal(t, "STATE_OPEN", ps.Src.Connection.State)
+
+	// Channels are those relayed according to the channel filter, with their pending packet commitments in order.
+	require.Equal(t, []ChannelSnapshot{{
+		PortID: "transfer", ChannelID: "channel-0", State: "STATE_OPEN", Ordering: "ORDER_UNORDERED", Version: "ics20-1",
+		CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-2", PacketCommitments: []uint64{5, 7},
+	}}, ps.Src.Channels)
+	require.Len(t, ps.Dst.Channels, 1)
+	require.Equal(t, "channel-2", ps.Dst.Channels[0].ChannelID)
+	require.Equal(t, []uint64{1}, ps.Dst.Channels[0].PacketCommitments)
+
+	// Signed snapshots are verified against their exact bytes.
+	signed, err := SignIBCStateSnapshot(snapshot, chains["chain-a"])
+	require.NoError(t, err)
+	require.Equal(t, "secp256k1", signed.Signature.KeyType)
+	require.Equal(t, "cosmos1relayer", signed.Signature.Address)
+	bz, err := json.Marshal(signed)
+	require.NoError(t, err)
+	var decoded SignedIBCStateSnapshot
+	require.NoError(t, json.Unmarshal(bz, &decoded))
+	verified, err := decoded.Verify()
+	require.NoError(t, err)
+	require.Equal(t, snapshot.Paths, verified.Paths)
+
+	decoded.Snapshot = json.RawMessage(`{"version":1,"paths":[]}`)
+	_, err = decoded.Verify()
+	require.EqualError(t, err, "invalid signature of snapshot")
+}
@@ -0,0 +1,119 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+type accountIdentityQuerier interface {
+	QueryAccountIdentity(ctx context.Context) (provider.AccountIdentity, error)
+}
+
+// identity returns the account of the relayer on both chains of the path of r, with its counterparty payees
+// on the open fee-enabled channels of the path.
+func (r *pathRunner) identity(ctx context.Context) admin.PathIdentity {
+	src, dst := chainIdentity(ctx, r.src, r.dst), chainIdentity(ctx, r.dst, r.src)
+	channels, err := r.openChannels(ctx)
+	if err != nil {
+		src.fail(err)
+		dst.fail(err)
+	}
+	for _, c := range channels {
+		if !isFeeEnabledVersion(c.channel.Version) {
+			continue
+		}
+		src.addPayee(ctx, r.src, c.channel.PortId, c.channel.ChannelId)
+		dst.addPayee(ctx, r.dst, c.channel.Counterparty.PortId, c.channel.Counterparty.ChannelId)
+	}
+	return admin.PathIdentity{Path: r.name, Chains: []admin.ChainIdentity{src.ChainIdentity, dst.ChainIdentity}}
+}
+
+// chainIdentityReport is the identity of the relayer on a chain being queried, keeping the first error.
+type chainIdentityReport struct {
+	admin.ChainIdentity
+}
+
+func (id *chainIdentityReport) fail(err error) {
+	if id.Error == "" {
+		id.Error = err.Error()
+	}
+}
+
+// chainIdentity returns the account of the relayer on c, whose counterparty on the path is counterparty.
+// Chains which cannot be queried for the account only report its address.
+func chainIdentity(ctx context.Context, c, counterparty *Chain) chainIdentityReport {
+	id := chainIdentityReport{admin.ChainIdentity{ChainID: c.ChainID(), Key: c.ChainProvider.Key()}}
+	addr, err := c.ChainProvider.Address()
+	if err != nil {
+		id.fail(fmt.Errorf("failed to get relayer address on %s: %w", c.ChainID(), err))
+		return id
+	}
+	id.Address = addr
+	if id.CounterpartyAddress, err = counterparty.ChainProvider.Address(); err != nil {
+		id.fail(fmt.Errorf("failed to get relayer address on %s: %w", counterparty.ChainID(), err))
+	}
+
+	q, ok := c.ChainProvider.(accountIdentityQuerier)
+	if !ok {
+		return id
+	}
+	acc, err := q.QueryAccountIdentity(ctx)
+	if err != nil {
+		id.fail(err)
+	}
+	id.Signer = acc.Signer
+	id.AccountType = acc.AccountType
+	id.AccountNumber = acc.AccountNumber
+	id.Sequence = acc.Sequence
+	id.PubKeyType = acc.PubKeyType
+	id.FeeGranter = acc.FeeGranter
+	id.FeeGrant = acc.FeeGrant
+	id.FeeGrantError = acc.FeeGrantError
+	id.AuthzGrants = acc.AuthzGrants
+	return id
+}
+
+// addPayee reports the counterparty payee of the relayer on the channel of c.
+func (id *chainIdentityReport) addPayee(ctx context.Context, c *Chain, portID, channelID string) {
+	if id.Address == "" {
+		return
+	}
+	payee, err := c.ChainProvider.QueryCounterpartyPayee(ctx, channelID, id.Address)
+	if err != nil {
+		id.fail(err)
+		return
+	}
+	id.Payees = append(id.Payees, admin.PayeeRegistration{
+		PortID:            portID,
+		ChannelID:         channelID,
+		CounterpartyPayee: payee,
+		Registered:        payee != "" && payee == id.CounterpartyAddress,
+	})
+}
+
+// registerIdentityHandlers exposes the account of the relayer on each chain of the paths,
+// queried from the chains on every request.
+//
+//	GET /identity lists the account of the relayer on both chains of each path, or of the path of the path query parameter.
+func registerIdentityHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/identity", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		runners, err := s.runnersOf(req)
+		if err != nil {
+			admin.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		res := make([]admin.PathIdentity, 0, len(runners))
+		for _, r := range runners {
+			res = append(res, r.identity(req.Context()))
+		}
+		admin.WriteJSON(w, http.StatusOK, res)
+	})
+}
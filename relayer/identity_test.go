@@ -0,0 +1,98 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+)
+
+// identityProvider serves the account of the relayer on a chain and its counterparty payees, by channel.
+type identityProvider struct {
+	provider.ChainProvider
+	chainID, address string
+	account          *provider.AccountIdentity
+	payees           map[string]string
+}
+
+func (p *identityProvider) ChainId() string { return p.chainID }
+
+func (p *identityProvider) Key() string { return "relayer" }
+
+func (p *identityProvider) Address() (string, error) {
+	if p.address == "" {
+		return "", errors.New("key relayer not found")
+	}
+	return p.address, nil
+}
+
+func (p *identityProvider) QueryCounterpartyPayee(_ context.Context, channelID, _ string) (string, error) {
+	payee, ok := p.payees[channelID]
+	if !ok {
+		return "", errors.New("channel not found")
+	}
+	return payee, nil
+}
+
+// accountIdentityProvider also queries the account of the relayer.
+type accountIdentityProvider struct {
+	*identityProvider
+}
+
+func (p accountIdentityProvider) QueryAccountIdentity(context.Context) (provider.AccountIdentity, error) {
+	return *p.account, nil
+}
+
+func TestChainIdentity(t *testing.T) {
+	ctx := context.Background()
+	hub := &identityProvider{
+		chainID: "hub",
+		address: "dym1relayer",
+		account: &provider.AccountIdentity{
+			Signer:        provider.SignerLocal,
+			AccountType:   "/ethermint.types.v1.EthAccount",
+			FeeGranter:    "dym1treasury",
+			FeeGrant:      "/cosmos.feegrant.v1beta1.BasicAllowance",
+			FeeGrantError: "fee grant of dym1treasury to dym1relayer expired",
+			AuthzGrants:   []string{"/ibc.core.channel.v1.MsgRecvPacket"},
+		},
+		payees: map[string]string{"channel-0": "rol1relayer", "channel-1": "rol1other", "channel-2": ""},
+	}
+	rollapp := &identityProvider{chainID: "rollapp", address: "rol1relayer"}
+	hubChain := &Chain{ChainProvider: accountIdentityProvider{hub}}
+	rollappChain := &Chain{ChainProvider: rollapp}
+
+	id := chainIdentity(ctx, hubChain, rollappChain)
+	require.Empty(t, id.Error)
+	require.Equal(t, "dym1relayer", id.Address)
+	require.Equal(t, "rol1relayer", id.CounterpartyAddress)
+	require.Equal(t, "/ethermint.types.v1.EthAccount", id.AccountType)
+	require.Equal(t, "dym1treasury", id.FeeGranter)
+	require.Contains(t, id.FeeGrantError, "expired")
+	require.Equal(t, []string{"/ibc.core.channel.v1.MsgRecvPacket"}, id.AuthzGrants)
+
+	// The payee is registered once it is the address of the relayer on the counterparty.
+	for _, channelID := range []string{"channel-0", "channel-1", "channel-2", "channel-3"} {
+		id.addPayee(ctx, hubChain, "transfer", channelID)
+	}
+	require.Len(t, id.Payees, 3)
+	require.True(t, id.Payees[0].Registered)
+	require.False(t, id.Payees[1].Registered)
+	require.Equal(t, "rol1other", id.Payees[1].CounterpartyPayee)
+	require.False(t, id.Payees[2].Registered)
+	require.Equal(t, "channel not found", id.Error)
+
+	// Chains which cannot be queried for the account only report its address.
+	id = chainIdentity(ctx, rollappChain, hubChain)
+	require.Empty(t, id.Error)
+	require.Equal(t, "rol1relayer", id.Address)
+	require.Empty(t, id.Signer)
+
+	rollapp.address = ""
+	id = chainIdentity(ctx, rollappChain, hubChain)
+	require.Contains(t, id.Error, "failed to get relayer address on rollapp")
+	id.addPayee(ctx, rollappChain, "transfer", "channel-0")
+	require.Empty(t, id.Payees)
+}
@@ -0,0 +1,137 @@
+package relayer
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// metricsMonitorInterval is how often wallet balances and finalized rollapp heights are refreshed.
+const metricsMonitorInterval = time.Minute
+
+type metricsKey struct{}
+
+// pathMetrics are the metrics transactions are recorded to, along with the destination chain of the path
+// they are sent for, which tells the direction of each transaction.
+type pathMetrics struct {
+	m          *processor.PrometheusMetrics
+	dstChainID string
+}
+
+// withMetrics records the transactions sent by Send with ctx to m, for the path with the given destination chain.
+func withMetrics(ctx context.Context, m *processor.PrometheusMetrics, dstChainID string) context.Context {
+	if m == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, metricsKey{}, pathMetrics{m: m, dstChainID: dstChainID})
+}
+
+// recordMetrics records a transaction sent to chainID to the metrics attached to ctx, if any.
+func recordMetrics(ctx context.Context, chainID string, resp *provider.RelayerTxResponse, success bool, msgs []provider.RelayerMessage) {
+	pm, ok := ctx.Value(metricsKey{}).(pathMetrics)
+	if !ok {
+		return
+	}
+	m, pathName := pm.m, provider.PathNameFromContext(ctx)
+	direction := processor.DirectionDstToSrc
+	if chainID == pm.dstChainID {
+		direction = processor.DirectionSrcToDst
+	}
+	if !success {
+		m.IncFailedRelays(pathName, chainID, direction)
+		return
+	}
+	if resp != nil {
+		m.AddGasUsed(pathName, chainID, direction, resp.GasUsed)
+	}
+	for _, msg := range msgs {
+		cm, ok := msg.(cosmosprovider.CosmosMessage)
+		if !ok {
+			continue
+		}
+		switch sdkMsg := cm.Msg.(type) {
+		case *chantypes.MsgRecvPacket:
+			m.IncRelayedPackets(pathName, chainID, direction, sdkMsg.Packet.DestinationChannel, sdkMsg.Packet.DestinationPort, processor.MetricRecvPacket)
+		case *chantypes.MsgAcknowledgement:
+			m.IncRelayedPackets(pathName, chainID, direction, sdkMsg.Packet.SourceChannel, sdkMsg.Packet.SourcePort, processor.MetricAckPacket)
+		case *chantypes.MsgTimeout:
+			m.IncRelayedPackets(pathName, chainID, direction, sdkMsg.Packet.SourceChannel, sdkMsg.Packet.SourcePort, processor.MetricTimeout)
+		case *chantypes.MsgTimeoutOnClose:
+			m.IncRelayedPackets(pathName, chainID, direction, sdkMsg.Packet.SourceChannel, sdkMsg.Packet.SourcePort, processor.MetricTimeout)
+		}
+	}
+}
+
+// recordSequenceResult counts the result of relaying a sequence in the metrics attached to ctx, if any.
+func recordSequenceResult(ctx context.Context, res SequenceResult) {
+	pm, ok := ctx.Value(metricsKey{}).(pathMetrics)
+	if !ok {
+		return
+	}
+	pm.m.IncSequenceResults(provider.PathNameFromContext(ctx), res.ChainID, res.ChannelID, res.PortID, res.Status)
+}
+
+// startMetricsMonitor periodically records the wallet balance of the relayer on every chain
+// and the latest finalized height of every rollapp with a settlement layer, until ctx is done.
+func (s *supervisor) startMetricsMonitor(ctx context.Context) {
+	log := s.log.With(zap.String("sys", "metrics"))
+	chains := s.chains()
+	s.maintenance.schedule(ctx, TaskBalanceCheck, metricsMonitorInterval, func(ctx context.Context) {
+		for _, c := range chains {
+			updateChainMetrics(ctx, log, s.metrics, c)
+		}
+	})
+}
+
+func updateChainMetrics(ctx context.Context, log *zap.Logger, m *processor.PrometheusMetrics, c *Chain) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if addr, err := c.ChainProvider.Address(); err == nil {
+		coins, err := c.ChainProvider.QueryBalance(ctx, c.ChainProvider.Key())
+		if err != nil {
+			log.Debug("Failed to query wallet balance for metrics", zap.String("chain_id", c.ChainID()), zap.Error(err))
+		}
+		for _, coin := range coins {
+			amount, err := coin.Amount.ToDec().Float64()
+			if err != nil {
+				continue
+			}
+			m.SetWalletBalance(c.ChainID(), addr, coin.Denom, amount)
+		}
+	}
+
+	if sp := settlementProvider(c.ChainProvider); sp != nil {
+		h, err := sp.QueryLatestFinalizedHeight(ctx, c.ChainID())
+		if err != nil {
+			log.Debug("Failed to query latest finalized height for metrics", zap.String("chain_id", c.ChainID()), zap.Error(err))
+			return
+		}
+		m.SetLatestFinalizedHeight(c.ChainID(), h)
+	}
+}
+
+// serveMetrics serves m at /metrics on ln until ctx is done.
+func serveMetrics(ctx context.Context, log *zap.Logger, m *processor.PrometheusMetrics, ln net.Listener) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{}))
+	srv := &http.Server{
+		Handler:  mux,
+		ErrorLog: zap.NewStdLog(log),
+	}
+
+	go srv.Serve(ln)
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+}
@@ -0,0 +1,42 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordMetrics(t *testing.T) {
+	m := processor.NewPrometheusMetrics()
+	ctx := withMetrics(provider.WithPathName(context.Background(), "demo-path"), m, "chain-b")
+
+	packet := chantypes.Packet{
+		SourcePort:         "transfer",
+		SourceChannel:      "channel-0",
+		DestinationPort:    "transfer",
+		DestinationChannel: "channel-1",
+	}
+	msgs := []provider.RelayerMessage{
+		cosmosprovider.NewCosmosMessage(&chantypes.MsgRecvPacket{Packet: packet}),
+		cosmosprovider.NewCosmosMessage(&chantypes.MsgRecvPacket{Packet: packet}),
+		cosmosprovider.NewCosmosMessage(&chantypes.MsgAcknowledgement{Packet: packet}),
+	}
+
+	recordMetrics(ctx, "chain-b", &provider.RelayerTxResponse{GasUsed: 1000}, true, msgs)
+	recordMetrics(ctx, "chain-b", nil, false, msgs)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.RelayedPackets.WithLabelValues("demo-path", "chain-b", processor.DirectionSrcToDst, "channel-1", "transfer", processor.MetricRecvPacket)))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.RelayedPackets.WithLabelValues("demo-path", "chain-b", processor.DirectionSrcToDst, "channel-0", "transfer", processor.MetricAckPacket)))
+	require.Equal(t, 1000.0, testutil.ToFloat64(m.GasUsed.WithLabelValues("demo-path", "chain-b", processor.DirectionSrcToDst)))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.FailedRelays.WithLabelValues("demo-path", "chain-b", processor.DirectionSrcToDst)))
+
+	// Sending without metrics attached records nothing.
+	recordMetrics(context.Background(), "chain-b", nil, false, msgs)
+	require.Equal(t, 1.0, testutil.ToFloat64(m.FailedRelays.WithLabelValues("demo-path", "chain-b", processor.DirectionSrcToDst)))
+}
@@ -0,0 +1,162 @@
+package relayer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"go.uber.org/zap"
+)
+
+type msgBatcherKey struct{}
+
+// msgBatcher aggregates the messages the channels of a path relayed by the legacy processor send to the same chain
+// within a window into shared transactions, still bounded by the max tx size and message length,
+// rather than sending a few messages per channel in dozens of transactions per block on busy hubs.
+type msgBatcher struct {
+	// ctx outlives the submissions, the merged messages are sent with it.
+	ctx    context.Context
+	log    *zap.Logger
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingBatch
+}
+
+// pendingBatch holds the submissions for a chain until the window closes.
+type pendingBatch struct {
+	s                       RelayMsgSender
+	memo                    string
+	maxMsgLength, maxTxSize uint64
+	submissions             []*batchSubmission
+}
+
+type batchSubmission struct {
+	key  string
+	msgs []provider.RelayerMessage
+	done chan batchResult
+}
+
+type batchResult struct {
+	successes int
+	err       error
+	sent      *sentMsgs
+}
+
+// withMsgBatcher batches the messages sent with the returned context, see msgBatcher.
+// A zero window leaves ctx untouched, so every channel sends its own transactions.
+func withMsgBatcher(ctx context.Context, log *zap.Logger, window time.Duration) context.Context {
+	if window <= 0 {
+		return ctx
+	}
+	b := &msgBatcher{
+		ctx:     ctx,
+		log:     log,
+		window:  window,
+		pending: make(map[string]*pendingBatch),
+	}
+	return context.WithValue(ctx, msgBatcherKey{}, b)
+}
+
+func msgBatcherFromContext(ctx context.Context) *msgBatcher {
+	b, _ := ctx.Value(msgBatcherKey{}).(*msgBatcher)
+	return b
+}
+
+// submit queues msgs to be sent to s along with the messages submitted by other channels within the window,
+// and returns the outcome of the merged send, shared by all of its submissions.
+func (b *msgBatcher) submit(ctx context.Context, s RelayMsgSender, msgs []provider.RelayerMessage, memo string, maxMsgLength, maxTxSize uint64) (int, error) {
+	sub := &batchSubmission{
+		key:  batchKey(msgs),
+		msgs: msgs,
+		done: make(chan batchResult, 1),
+	}
+
+	b.mu.Lock()
+	p, ok := b.pending[s.ChainID]
+	if !ok {
+		p = &pendingBatch{s: s, memo: memo, maxMsgLength: maxMsgLength, maxTxSize: maxTxSize}
+		b.pending[s.ChainID] = p
+		time.AfterFunc(b.window, func() { b.flush(s.ChainID) })
+	}
+	p.submissions = append(p.submissions, sub)
+	b.mu.Unlock()
+
+	select {
+	case res := <-sub.done:
+		res.sent.forward(ctx, msgs)
+		return res.successes, res.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// flush sends the messages submitted for chainID, ordered by channel so that the transactions are deterministic.
+// The messages of each submission stay in order, behind the client update they were submitted with.
+// Identical messages, such as the same client update submitted by several channels, are only sent once.
+func (b *msgBatcher) flush(chainID string) {
+	b.mu.Lock()
+	p := b.pending[chainID]
+	delete(b.pending, chainID)
+	b.mu.Unlock()
+
+	sort.SliceStable(p.submissions, func(i, j int) bool {
+		return p.submissions[i].key < p.submissions[j].key
+	})
+
+	var msgs []provider.RelayerMessage
+	seen := make(map[string]bool)
+	for _, sub := range p.submissions {
+		for _, msg := range sub.msgs {
+			bz, err := msg.MsgBytes()
+			if err != nil {
+				panic(err)
+			}
+			if seen[string(bz)] {
+				continue
+			}
+			seen[string(bz)] = true
+			msgs = append(msgs, msg)
+		}
+	}
+
+	b.log.Debug(
+		"Sending cross-channel batch",
+		zap.String("chain_id", chainID),
+		zap.Int("submissions", len(p.submissions)),
+		zap.Int("msgs", len(msgs)),
+	)
+
+	var res batchResult
+	ctx, sent := withSentMsgs(b.ctx)
+	res.sent = sent
+	sendBatches(ctx, b.log, p.s, msgs, p.memo, &res.successes, &res.err, p.maxMsgLength, p.maxTxSize)
+	for _, sub := range p.submissions {
+		sub.done <- res
+	}
+}
+
+// batchKey orders submissions by the channel end their first packet message is for.
+func batchKey(msgs []provider.RelayerMessage) string {
+	for _, msg := range msgs {
+		cm, ok := msg.(cosmosprovider.CosmosMessage)
+		if !ok {
+			continue
+		}
+		switch m := cm.Msg.(type) {
+		case *chantypes.MsgRecvPacket:
+			return m.Packet.DestinationPort + "/" + m.Packet.DestinationChannel
+		case *chantypes.MsgAcknowledgement:
+			return m.Packet.SourcePort + "/" + m.Packet.SourceChannel
+		case *chantypes.MsgTimeout:
+			return m.Packet.SourcePort + "/" + m.Packet.SourceChannel
+		case *chantypes.MsgTimeoutOnClose:
+			return m.Packet.SourcePort + "/" + m.Packet.SourceChannel
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package relayer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMsgBatcherMergesChannels(t *testing.T) {
+	update := cosmosprovider.NewCosmosMessage(&clienttypes.MsgUpdateClient{ClientId: "07-tendermint-0"})
+	recv := func(channelID string, seq uint64) provider.RelayerMessage {
+		return cosmosprovider.NewCosmosMessage(&chantypes.MsgRecvPacket{Packet: chantypes.Packet{
+			Sequence:           seq,
+			DestinationPort:    "transfer",
+			DestinationChannel: channelID,
+		}})
+	}
+
+	var (
+		mu    sync.Mutex
+		calls [][]provider.RelayerMessage
+	)
+	dst := RelayMsgSender{
+		ChainID: "dst",
+		SendMessages: func(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, msgs)
+			return nil, true, nil
+		},
+	}
+
+	log := zaptest.NewLogger(t)
+	ctx := withMsgBatcher(context.Background(), log, 50*time.Millisecond)
+
+	// Channels submit concurrently, in any order, with the same client update.
+	submissions := [][]provider.RelayerMessage{
+		{update, recv("channel-2", 1), recv("channel-2", 2)},
+		{update, recv("channel-1", 7)},
+	}
+	successes := make([]int, len(submissions))
+	errs := make([]error, len(submissions))
+	var wg sync.WaitGroup
+	for i, msgs := range submissions {
+		wg.Add(1)
+		go func(i int, msgs []provider.RelayerMessage) {
+			defer wg.Done()
+			time.Sleep(time.Duration(len(submissions)-i) * 5 * time.Millisecond)
+			Send(ctx, log, dst, msgs, "", &successes[i], &errs[i], 10, 0)
+		}(i, msgs)
+	}
+	wg.Wait()
+
+	for i := range submissions {
+		require.NoError(t, errs[i])
+		require.Equal(t, 1, successes[i], "submissions share the outcome of the merged send")
+	}
+
+	require.Equal(t, [][]provider.RelayerMessage{
+		{update, recv("channel-1", 7), recv("channel-2", 1), recv("channel-2", 2)},
+	}, calls)
+}
+
+func TestMsgBatcherDisabled(t *testing.T) {
+	ctx := withMsgBatcher(context.Background(), zaptest.NewLogger(t), 0)
+	require.Nil(t, msgBatcherFromContext(ctx))
+}
@@ -3,6 +3,7 @@ package relayer
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/avast/retry-go/v4"
@@ -10,84 +11,27 @@ import (
 	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
-// UnrelayedSequences returns the unrelayed sequence numbers between two chains
+// unrelayedSequences returns the sequences of the packets committed on src at srch that dst has not received yet.
+// The commitments are listed a page at a time while the pages already listed are checked unreceived on dst,
+// up to UnrelayedQueryConcurrency chunks of sequences at once, and concurrent callers share the queries in flight.
 func unrelayedSequences(ctx context.Context,
 	src *Chain, srcChannelId, srcPortId string, srch int64,
 	dst *Chain, dstChannelId, dstPortId string, ordering chantypes.Order,
 ) []uint64 {
-
-	var (
-		srcPacketSeq         = []uint64{}
-		srcUnreceivedPackets = []uint64{}
-		commitments          []*chantypes.PacketState
-		err                  error
-	)
-	if err = retry.Do(func() error {
-		// Query the packet commitment
-		commitments, err = src.ChainProvider.QueryPacketCommitments(ctx, uint64(srch), srcChannelId, srcPortId)
-		switch {
-		case err != nil:
-			return err
-		case len(commitments) == 0:
-			return fmt.Errorf("no error on QueryPacketCommitments for %s, however response is nil", src.ChainID())
-		default:
-			return nil
-		}
-	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
-		src.log.Info(
-			"Failed to query packet commitments",
-			zap.String("channel_id", srcChannelId),
-			zap.String("port_id", srcPortId),
-			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
-			zap.Error(err),
-		)
-	})); err != nil {
-		src.log.Error(
-			"Failed to query packet commitments after max retries",
-			zap.String("channel_id", srcChannelId),
-			zap.String("port_id", srcPortId),
-			zap.Uint("attempts", RtyAttNum),
-			zap.Error(err),
-		)
-		return srcUnreceivedPackets
-	}
-
-	for _, pc := range commitments {
-		srcPacketSeq = append(srcPacketSeq, pc.Sequence)
-	}
-
-	if len(srcPacketSeq) > 0 {
-		// Query all packets sent by src that have not been received by dst.
-		if err := retry.Do(func() error {
-			var err error
-			// we are using height 0 because we want to check vs the latest height
-			srcUnreceivedPackets, err = dst.ChainProvider.QueryUnreceivedPackets(ctx, 0, dstChannelId, dstPortId, srcPacketSeq)
-			return err
-		}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
-			dst.log.Info(
-				"Failed to query unreceived packets",
-				zap.String("channel_id", dstChannelId),
-				zap.String("port_id", dstPortId),
-				zap.Uint("attempt", n+1),
-				zap.Uint("max_attempts", RtyAttNum),
-				zap.Error(err),
-			)
-		})); err != nil {
-			dst.log.Error(
-				"Failed to query unreceived packets after max retries",
-				zap.String("channel_id", dstChannelId),
-				zap.String("port_id", dstPortId),
-				zap.Uint("attempts", RtyAttNum),
-				zap.Error(err),
-			)
-			return srcUnreceivedPackets
-		}
+	key := unrelayedQueryKey("packets", src, srcChannelId, srcPortId, srch, dst, dstChannelId, dstPortId, 0)
+	srcUnreceivedPackets, err := sharedUnrelayedQuery(ctx, key, func() ([]uint64, error) {
+		return unreceivedPackets(ctx, src, srcChannelId, srcPortId, srch, dst, dstChannelId, dstPortId)
+	})
+	if err != nil {
+		return []uint64{}
 	}
 
 	// If this is an UNORDERED channel we can return at this point.
@@ -95,10 +39,9 @@ func unrelayedSequences(ctx context.Context,
 		return srcUnreceivedPackets
 	}
 
-	// For ordered channels we want to only relay the packet whose sequence number is equal to
-	// the expected next packet receive sequence from the counterparty.
+	// For ordered channels dst only receives the packet whose sequence number is equal to its next
+	// packet receive sequence, so only the packets following on from it are relayed, in order.
 	if len(srcUnreceivedPackets) > 0 {
-		var srcUnreceivedPacketsOrdered = []uint64{}
 		// we are using height 0 because we want to check vs the latest height
 		nextSeqResp, err := dst.ChainProvider.QueryNextSeqRecv(ctx, 0, dstChannelId, dstPortId)
 		if err != nil {
@@ -108,21 +51,162 @@ func unrelayedSequences(ctx context.Context,
 				zap.String("port_id", dstPortId),
 				zap.Error(err),
 			)
-			return srcUnreceivedPacketsOrdered
+			return []uint64{}
 		}
+		next := nextSeqResp.NextSequenceReceive
 
-		for _, seq := range srcUnreceivedPackets {
-			if seq == nextSeqResp.NextSequenceReceive {
-				srcUnreceivedPacketsOrdered = append(srcUnreceivedPacketsOrdered, seq)
-				break
-			}
+		ordered, gap := orderedSequences(srcUnreceivedPackets, next)
+		if gap {
+			ordered = recoverOrderedSequence(ctx, src, srcChannelId, srcPortId, srch, dst, dstChannelId, dstPortId, srcUnreceivedPackets, next)
 		}
-		srcUnreceivedPackets = srcUnreceivedPacketsOrdered
+		srcUnreceivedPackets = ordered
 	}
 
 	return srcUnreceivedPackets
 }
 
+// unreceivedPackets lists the packets committed on src at srch and checks them unreceived on dst at its latest height,
+// logging the queries failing after max retries.
+func unreceivedPackets(ctx context.Context,
+	src *Chain, srcChannelId, srcPortId string, srch int64,
+	dst *Chain, dstChannelId, dstPortId string,
+) ([]uint64, error) {
+	p := sequencePipeline{
+		chunkSize:   AckChunkSize,
+		concurrency: UnrelayedQueryConcurrency,
+		list: func(ctx context.Context, emit func([]uint64) error) error {
+			listed := 0
+			emitCounted := func(seqs []uint64) error {
+				listed += len(seqs)
+				return emit(seqs)
+			}
+			err := retry.Do(func() error {
+				// Query the packet commitments
+				if err := listCommitments(ctx, src, srcChannelId, srcPortId, srch, emitCounted); err != nil {
+					return err
+				}
+				if listed == 0 {
+					return fmt.Errorf("no error on QueryPacketCommitments for %s, however response is nil", src.ChainID())
+				}
+				return nil
+			}, src.retryOptions(ctx, retry.OnRetry(func(n uint, err error) {
+				src.log.Info(
+					"Failed to query packet commitments",
+					zap.String("channel_id", srcChannelId),
+					zap.String("port_id", srcPortId),
+					zap.Uint("attempt", n+1),
+					zap.Uint("max_attempts", src.retryAttempts()),
+					zap.Error(err),
+				)
+			}))...)
+			if err != nil && ctx.Err() == nil {
+				src.log.Error(
+					"Failed to query packet commitments after max retries",
+					zap.String("channel_id", srcChannelId),
+					zap.String("port_id", srcPortId),
+					zap.Uint("attempts", src.retryAttempts()),
+					zap.Error(err),
+				)
+			}
+			return err
+		},
+		check: func(ctx context.Context, seqs []uint64) ([]uint64, error) {
+			// Query the packets sent by src that have not been received by dst.
+			var unreceived []uint64
+			err := retry.Do(func() error {
+				var err error
+				// we are using height 0 because we want to check vs the latest height
+				unreceived, err = dst.ChainProvider.QueryUnreceivedPackets(ctx, 0, dstChannelId, dstPortId, seqs)
+				return err
+			}, dst.retryOptions(ctx, retry.OnRetry(func(n uint, err error) {
+				dst.log.Info(
+					"Failed to query unreceived packets",
+					zap.String("channel_id", dstChannelId),
+					zap.String("port_id", dstPortId),
+					zap.Uint("attempt", n+1),
+					zap.Uint("max_attempts", dst.retryAttempts()),
+					zap.Error(err),
+				)
+			}))...)
+			if err != nil && ctx.Err() == nil {
+				dst.log.Error(
+					"Failed to query unreceived packets after max retries",
+					zap.String("channel_id", dstChannelId),
+					zap.String("port_id", dstPortId),
+					zap.Uint("attempts", dst.retryAttempts()),
+					zap.Error(err),
+				)
+			}
+			return unreceived, err
+		},
+	}
+	return p.run(ctx)
+}
+
+// orderedSequences returns the run of consecutive sequences of unreceived starting at next,
+// the next packet receive sequence of an ordered channel, which can be received in a single batch.
+// It returns true if unreceived only holds sequences past next, leaving a gap before them.
+func orderedSequences(unreceived []uint64, next uint64) ([]uint64, bool) {
+	sorted := append([]uint64(nil), unreceived...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	ordered := []uint64{}
+	for _, seq := range sorted {
+		switch {
+		case seq < next:
+			// Received meanwhile.
+			continue
+		case seq == next:
+			ordered = append(ordered, seq)
+			next++
+		default:
+			return ordered, len(ordered) == 0
+		}
+	}
+	return ordered, false
+}
+
+// recoverOrderedSequence recovers an ordered channel whose next packet receive sequence on dst is missing from the
+// unreceived packets sent by src, e.g. because its commitment was not yet queryable when they were listed,
+// so that the channel is not stuck relaying packets dst refuses out of order.
+// If src still holds the commitment of the missing packet, the packet is relayed first, followed by the
+// unreceived packets consecutive to it. Otherwise the gap is reported and nothing is relayed.
+func recoverOrderedSequence(ctx context.Context,
+	src *Chain, srcChannelId, srcPortId string, srch int64,
+	dst *Chain, dstChannelId, dstPortId string,
+	unreceived []uint64, next uint64,
+) []uint64 {
+	if _, err := src.ChainProvider.QueryPacketCommitment(ctx, srch, srcChannelId, srcPortId, next); err != nil {
+		src.log.Warn(
+			"Ordered channel is missing the next packet to receive",
+			zap.String("src_chain_id", src.ChainID()),
+			zap.String("src_channel_id", srcChannelId),
+			zap.String("src_port_id", srcPortId),
+			zap.String("dst_chain_id", dst.ChainID()),
+			zap.String("dst_channel_id", dstChannelId),
+			zap.String("dst_port_id", dstPortId),
+			zap.Uint64("next_sequence_recv", next),
+			zap.Uint64s("unreceived", unreceived),
+			zap.Error(err),
+		)
+		return []uint64{}
+	}
+
+	ordered, _ := orderedSequences(append([]uint64{next}, unreceived...), next)
+	src.log.Info(
+		"Recovering ordered channel from sequence gap",
+		zap.String("src_chain_id", src.ChainID()),
+		zap.String("src_channel_id", srcChannelId),
+		zap.String("src_port_id", srcPortId),
+		zap.String("dst_chain_id", dst.ChainID()),
+		zap.String("dst_channel_id", dstChannelId),
+		zap.String("dst_port_id", dstPortId),
+		zap.Uint64("next_sequence_recv", next),
+		zap.Uint64s("seqs", ordered),
+	)
+	return ordered
+}
+
 // UnrelayedSequences returns the unrelayed sequence numbers between two chains
 func UnrelayedSequences(ctx context.Context, src, dst *Chain, srch, dsth int64, srcChannel *chantypes.IdentifiedChannel) RelaySequences {
 	var (
@@ -148,110 +232,193 @@ func UnrelayedSequences(ctx context.Context, src, dst *Chain, srch, dsth int64,
 	return rs
 }
 
-// UnrelayedAcknowledgements returns the unrelayed sequence numbers between two chains
-func unrelayedAcknowledgements(ctx context.Context,
-	src *Chain, srcChannelId, srcPortId string, srch int64,
-	dst *Chain, dstChannelId, dstPortId string, dsth int64,
-	relayedAckSequences *[]uint64,
-) ([]uint64, error) {
-	var (
-		srcPacketSeq = []uint64{}
-		rs           = []uint64{}
-		res          []*chantypes.PacketState
-		err          error
-		totalAcks    uint64
-		// by defult, query only latest acks
-		onlyLatest = true
-	)
-
-	if err = retry.Do(func() error {
-		res, totalAcks, err = src.ChainProvider.QueryPacketAcknowledgements(ctx, uint64(srch), srcChannelId, srcPortId, onlyLatest)
-		// heuristic: if we lag in more than AckGapForFullScan, make a full scan
-		if onlyLatest && int(totalAcks) > len(*relayedAckSequences)+AckGapForFullScan {
-			onlyLatest = false
-			return fmt.Errorf("retry QueryPacketAcknowledgements with full scan totalAcks=%d, totalKnownAcks=%d", totalAcks, len(*relayedAckSequences))
-		}
-		switch {
-		case err != nil:
-			return err
-		default:
-			return nil
-		}
-	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr); err != nil {
-		src.log.Error(
-			"Failed to query packet acknowledgement commitments after max attempts",
-			zap.String("channel_id", srcChannelId),
-			zap.String("port_id", srcPortId),
-			zap.Uint("attempts", RtyAttNum),
-			zap.Error(err),
-		)
+// finalizedQueryHeight caps h at the latest height of c finalized on the settlement layer when c is a rollapp,
+// so that only packets which were sent in finalized blocks are found at the returned height.
+// It returns false if no state of the rollapp has been finalized yet.
+func finalizedQueryHeight(ctx context.Context, c *Chain, h int64) (int64, bool, error) {
+	if !isRollapp(c.ChainProvider) {
+		return h, true, nil
 	}
-	if res == nil || err != nil || len(res) == 0 {
-		return rs, err
+	sp := settlementProvider(c.ChainProvider)
+	if sp == nil {
+		return 0, false, fmt.Errorf("no settlement layer is configured for %s, cannot query its finalized height", c.ChainID())
 	}
-
-	// find max seqence number from result
-	maxSeq := uint64(0)
-	for i, r := range res {
-		if i == 0 || r.Sequence > maxSeq {
-			maxSeq = r.Sequence
-		}
+	finalized, err := sp.QueryLatestFinalizedHeight(ctx, c.ChainID())
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to query latest finalized height of %s: %w", c.ChainID(), err)
+	}
+	if finalized < 0 {
+		return 0, false, nil
 	}
+	if finalized < h {
+		return finalized, true, nil
+	}
+	return h, true, nil
+}
+
+// confirmationDepth is a chain provider relaying the packets sent on its chain once enough blocks were built on top of theirs.
+type confirmationDepth interface {
+	Confirmations() uint64
+}
 
-	// increase capacity if needed
-	requiredCapacityGrowth := int(maxSeq) - cap(*relayedAckSequences)
-	if requiredCapacityGrowth > 0 {
-		// add buffer of 1000
-		*relayedAckSequences = append(*relayedAckSequences, make([]uint64, requiredCapacityGrowth+1000)...)
-	} else {
-		requiredCapacityGrowth = 0
+// confirmations returns the number of blocks built on top of the block of a packet sent on the chain of p
+// before it is relayed, zero if p does not configure any.
+func confirmations(p provider.ChainProvider) uint64 {
+	if d, ok := p.(confirmationDepth); ok {
+		return d.Confirmations()
 	}
+	return 0
+}
 
-	for _, pc := range res {
-		seq := pc.Sequence
+// confirmedQueryHeight returns the height at which to query the packets sent on c instead of h,
+// leaving out the packets of the blocks above h not yet buried under the confirmation depth of c.
+func confirmedQueryHeight(c *Chain, h int64) int64 {
+	n := int64(confirmations(c.ChainProvider))
+	if n == 0 {
+		return h
+	}
+	if h-n < 1 {
+		return 1
+	}
+	return h - n
+}
 
-		// check if we saw that sequence, if not add it
-		if (*relayedAckSequences)[seq] == 0 {
-			// seq in new, need to relay it
-			srcPacketSeq = append(srcPacketSeq, seq)
+// unrelayedPackets returns the unrelayed sequence numbers between two chains queried at srch and dsth, leaving out
+// the packets of the blocks not yet confirmed on the chain they were sent on, and with finalityGating set,
+// the packets sent on a rollapp not yet finalized on the settlement layer, see UnrelayedFinalizedSequences.
+// The packets of the other direction than the one attached to ctx are left out, see withRelayDirection.
+func unrelayedPackets(ctx context.Context, src, dst *Chain, srch, dsth int64, srcChannel *chantypes.IdentifiedChannel, finalityGating bool) (RelaySequences, error) {
+	srch, dsth = confirmedQueryHeight(src, srch), confirmedQueryHeight(dst, dsth)
+	if finalityGating {
+		sp, err := UnrelayedFinalizedSequences(ctx, src, dst, srch, dsth, srcChannel)
+		return relayedPackets(ctx, src, dst, sp), err
+	}
+	return relayedPackets(ctx, src, dst, UnrelayedSequences(ctx, src, dst, srch, dsth, srcChannel)), nil
+}
 
-			// mark as seen
-			(*relayedAckSequences)[seq] = seq
-			//fmt.Println(seq)
-		}
+// UnrelayedFinalizedSequences returns the unrelayed sequence numbers between two chains, like UnrelayedSequences,
+// but leaves out packets sent on a rollapp above its latest height finalized on the settlement layer.
+// Packets sent on chains which are not rollapps are not gated.
+func UnrelayedFinalizedSequences(ctx context.Context, src, dst *Chain, srch, dsth int64, srcChannel *chantypes.IdentifiedChannel) (RelaySequences, error) {
+	srcFinalizedH, srcFinalized, err := finalizedQueryHeight(ctx, src, srch)
+	if err != nil {
+		return RelaySequences{}, err
 	}
-	if int(totalAcks) >= len(*relayedAckSequences) {
-		panic(fmt.Sprintf("totalAcks >= len(*relayedAckSequences), (%d), (%d)", totalAcks, len(*relayedAckSequences)))
+	dstFinalizedH, dstFinalized, err := finalizedQueryHeight(ctx, dst, dsth)
+	if err != nil {
+		return RelaySequences{}, err
 	}
 
-	// on first run, the number of sequences is large
-	// split it to bulks
-	for i := 0; i < len(srcPacketSeq) && err == nil; i += AckChunkSize {
-		rsChunk := []uint64{}
+	rs := RelaySequences{Src: []uint64{}, Dst: []uint64{}}
+	if !srcFinalized && !dstFinalized {
+		return rs, nil
+	}
 
-		end := i + AckChunkSize
-		if end > len(srcPacketSeq) {
-			end = len(srcPacketSeq)
-		}
+	rs = UnrelayedSequences(ctx, src, dst, srcFinalizedH, dstFinalizedH, srcChannel)
+	if !srcFinalized {
+		rs.Src = []uint64{}
+	}
+	if !dstFinalized {
+		rs.Dst = []uint64{}
+	}
+	return rs, nil
+}
 
-		// Query all packets sent by dst that have been received by src
-		if err = retry.Do(func() error {
-			// we check unreceived vs the latest height
-			rsChunk, err = dst.ChainProvider.QueryUnreceivedAcknowledgements(ctx, 0, dstChannelId, dstPortId, srcPacketSeq[i:end])
+// unrelayedAcknowledgements returns the sequences of the acknowledgements written on src that dst has not received yet,
+// verified against the state of both chains rather than against the acknowledgements relayed so far:
+// dst holds the commitment of every packet it sent until the acknowledgement or timeout of the packet is received,
+// so the unrelayed acknowledgements are those src wrote for the packets still committed on dst.
+// The acknowledgements are checked unreceived again at the latest height of dst, as another relayer may have relayed them meanwhile.
+// The commitments are listed a page at a time while the pages already listed are checked, up to UnrelayedQueryConcurrency
+// chunks of AckChunkSize sequences at once, and concurrent callers share the queries in flight.
+func unrelayedAcknowledgements(ctx context.Context,
+	src *Chain, srcChannelId, srcPortId string, srch int64,
+	dst *Chain, dstChannelId, dstPortId string, dsth int64,
+) ([]uint64, error) {
+	key := unrelayedQueryKey("acks", src, srcChannelId, srcPortId, srch, dst, dstChannelId, dstPortId, dsth)
+	rs, err := sharedUnrelayedQuery(ctx, key, func() ([]uint64, error) {
+		return unreceivedAcknowledgements(ctx, src, srcChannelId, srcPortId, srch, dst, dstChannelId, dstPortId, dsth)
+	})
+	if err != nil {
+		return []uint64{}, err
+	}
+	return rs, nil
+}
+
+// unreceivedAcknowledgements lists the packets committed on dst at dsth, queries the acknowledgements src wrote
+// for them at srch and checks those unreceived on dst at its latest height, logging the queries failing after max attempts.
+func unreceivedAcknowledgements(ctx context.Context,
+	src *Chain, srcChannelId, srcPortId string, srch int64,
+	dst *Chain, dstChannelId, dstPortId string, dsth int64,
+) ([]uint64, error) {
+	p := sequencePipeline{
+		chunkSize:   AckChunkSize,
+		concurrency: UnrelayedQueryConcurrency,
+		list: func(ctx context.Context, emit func([]uint64) error) error {
+			err := retry.Do(func() error {
+				return listCommitments(ctx, dst, dstChannelId, dstPortId, dsth, emit)
+			}, dst.retryOptions(ctx)...)
+			if err != nil && ctx.Err() == nil {
+				dst.log.Error(
+					"Failed to query packet commitments after max attempts",
+					zap.String("channel_id", dstChannelId),
+					zap.String("port_id", dstPortId),
+					zap.Uint("attempts", dst.retryAttempts()),
+					zap.Error(err),
+				)
+			}
 			return err
-		}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr); err != nil {
-			dst.log.Error(
-				"Failed to query unreceived acknowledgements after max attempts",
-				zap.String("channel_id", dstChannelId),
-				zap.String("port_id", dstPortId),
-				zap.Uint("attempts", RtyAttNum),
-				zap.Error(err),
+		},
+		check: func(ctx context.Context, committed []uint64) ([]uint64, error) {
+			// Query the acknowledgements src wrote for the packets still committed on dst.
+			var (
+				acks []*chantypes.PacketState
+				err  error
 			)
-		}
-		rs = append(rs, rsChunk...)
-	}
+			if err = retry.Do(func() error {
+				acks, err = src.ChainProvider.QueryPacketAcknowledgements(ctx, uint64(srch), srcChannelId, srcPortId, committed)
+				return err
+			}, src.retryOptions(ctx)...); err != nil {
+				if ctx.Err() == nil {
+					src.log.Error(
+						"Failed to query packet acknowledgements after max attempts",
+						zap.String("channel_id", srcChannelId),
+						zap.String("port_id", srcPortId),
+						zap.Uint("attempts", src.retryAttempts()),
+						zap.Error(err),
+					)
+				}
+				return nil, err
+			}
+			if len(acks) == 0 {
+				return nil, nil
+			}
+			srcPacketSeq := make([]uint64, 0, len(acks))
+			for _, ack := range acks {
+				srcPacketSeq = append(srcPacketSeq, ack.Sequence)
+			}
 
-	return rs, err
+			var unreceived []uint64
+			if err = retry.Do(func() error {
+				// we check unreceived vs the latest height
+				unreceived, err = dst.ChainProvider.QueryUnreceivedAcknowledgements(ctx, 0, dstChannelId, dstPortId, srcPacketSeq)
+				return err
+			}, dst.retryOptions(ctx)...); err != nil {
+				if ctx.Err() == nil {
+					dst.log.Error(
+						"Failed to query unreceived acknowledgements after max attempts",
+						zap.String("channel_id", dstChannelId),
+						zap.String("port_id", dstPortId),
+						zap.Uint("attempts", dst.retryAttempts()),
+						zap.Error(err),
+					)
+				}
+				return nil, err
+			}
+			return unreceived, nil
+		},
+	}
+	return p.run(ctx)
 }
 
 // UnrelayedAcknowledgements returns the unrelayed sequence numbers between two chains
@@ -259,28 +426,23 @@ func UnrelayedAcknowledgements(ctx context.Context, src, dst *Chain, srch, dsth
 	var (
 		rs = RelaySequences{Src: []uint64{}, Dst: []uint64{}}
 	)
-	var (
-		errSrc, errDst                                 error
-		relayedAckSequencesSrc, relayedAckSequencesDst = []uint64{}, []uint64{}
-	)
 
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		rs.Src, errSrc = unrelayedAcknowledgements(ctx,
+		rs.Src, _ = unrelayedAcknowledgements(ctx,
 			src, srcChannel.ChannelId, srcChannel.PortId, srch,
-			dst, srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId, dsth, &relayedAckSequencesSrc)
+			dst, srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId, dsth)
 	}()
 	go func() {
 		defer wg.Done()
-		rs.Dst, errDst = unrelayedAcknowledgements(ctx,
+		rs.Dst, _ = unrelayedAcknowledgements(ctx,
 			dst, srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId, dsth,
-			src, srcChannel.ChannelId, srcChannel.PortId, srch, &relayedAckSequencesDst)
+			src, srcChannel.ChannelId, srcChannel.PortId, srch)
 	}()
 	wg.Wait()
 
-	_, _ = errSrc, errDst
 	return rs
 }
 
@@ -298,21 +460,27 @@ func (rs *RelaySequences) Empty() bool {
 }
 
 // relayAcknowledgements creates transactions to relay acknowledgements from src
-// to dst following the sequences of the packets that were acked on src
+// to dst following the sequences of the packets that were acked on src,
+// and returns the result of relaying each sequence.
 func relayAcknowledgements(ctx context.Context, log *zap.Logger,
 	src *Chain, srcChannelId, srcPortId string, srch int64, sequences []uint64,
 	dst *Chain, dstChannelId, dstPortId string,
 	maxTxSize, maxMsgLength uint64, memo string,
-) error {
+) (SequenceResults, error) {
 	// set the maximum relay transaction constraints
 	msgs := []provider.RelayerMessage{}
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
+		var (
+			results SequenceResults
+			errs    error
+		)
 		// add messages for received packets on src
 		for _, seq := range sequences {
+			res := SequenceResult{ChainID: src.ChainID(), ChannelID: srcChannelId, PortID: srcPortId, Sequence: seq, ack: true}
 			// src wrote the ack. acknowledgementFromSequence will query the acknowledgement
 			// from the counterparty chain (second chain provided in the arguments). The message
 			// should be sent to dst.
@@ -321,14 +489,18 @@ func relayAcknowledgements(ctx context.Context, log *zap.Logger,
 				ctx,
 				src.ChainProvider, uint64(srch), seq, srcChannelId, srcPortId,
 				dstChannelId, dstPortId)
-			if err != nil {
-				return err
-			}
-
-			// Do not allow nil messages to the queued, or else we will panic in send()
-			if relayAckMsgs != nil {
+			switch {
+			case err != nil:
+				res.Status, res.Reason = SequenceFailed, err.Error()
+				multierr.AppendInto(&errs, err)
+			case relayAckMsgs == nil:
+				// Do not allow nil messages to the queued, or else we will panic in send()
+				res.Status, res.Reason = SequenceSkipped, "no acknowledgement to relay"
+			default:
+				res.MsgType, res.msg = packetMsgType(relayAckMsgs), relayAckMsgs
 				msgs = append(msgs, relayAckMsgs)
 			}
+			results = append(results, res)
 		}
 
 		if len(msgs) == 0 {
@@ -339,18 +511,25 @@ func relayAcknowledgements(ctx context.Context, log *zap.Logger,
 				zap.String("dst_chain_id", dst.ChainID()),
 				zap.String("dst_port_id", dstPortId),
 			)
-			return nil
+			results.report(ctx, log, src.ChainID(), dst.ChainID())
+			return results, errs
 		}
 
-		err := PrependUpdateClientMsg(ctx, &msgs, src, dst, srch)
-
-		if err != nil {
-			return err
+		if err := PrependUpdateClientMsg(ctx, &msgs, src, dst, srch); err != nil {
+			results.fail(err)
+			results.report(ctx, log, src.ChainID(), dst.ChainID())
+			return results, multierr.Append(errs, err)
 		}
 
 		// send messages to their respective chains
-		var successfulBatches int
-		Send(ctx, log, AsRelayMsgSender(dst), msgs, memo, &successfulBatches, &err, maxMsgLength, maxTxSize)
+		var (
+			successfulBatches int
+			err               error
+		)
+		sendCtx, sent := withSentMsgs(ctx)
+		Send(sendCtx, log, AsRelayMsgSender(dst), msgs, memo, &successfulBatches, &err, maxMsgLength, maxTxSize)
+		results.resolve(sent, err)
+		results.report(ctx, log, src.ChainID(), dst.ChainID())
 
 		if (successfulBatches > 0) && (err != nil) {
 			log.Info(
@@ -361,55 +540,83 @@ func relayAcknowledgements(ctx context.Context, log *zap.Logger,
 				zap.String("dst_port_id", dstPortId),
 				zap.Error(err),
 			)
-			return err
 		}
 
-		if successfulBatches > 0 {
+		if successfulBatches > 0 && err == nil {
 			dst.logPacketsRelayed(src, successfulBatches, dstPortId, srcPortId)
 		}
+		return results, multierr.Append(errs, err)
 	}
+}
 
-	return nil
+// startRelaySpan starts the span named name of relaying the sequences of sp over srcChannel between src and dst.
+func startRelaySpan(ctx context.Context, name string, src, dst *Chain, sp RelaySequences, srcChannel *chantypes.IdentifiedChannel) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		attribute.String("src_chain_id", src.ChainID()),
+		attribute.String("src_channel_id", srcChannel.ChannelId),
+		attribute.String("src_port_id", srcChannel.PortId),
+		attribute.String("dst_chain_id", dst.ChainID()),
+		attribute.String("dst_channel_id", srcChannel.Counterparty.ChannelId),
+		attribute.String("dst_port_id", srcChannel.Counterparty.PortId),
+		attribute.Int("src_sequences", len(sp.Src)),
+		attribute.Int("dst_sequences", len(sp.Dst)),
+	)
 }
 
-// RelayAcknowledgements creates transactions to relay acknowledgements from src to dst and from dst to src
-func RelayAcknowledgements(ctx context.Context, log *zap.Logger, src, dst *Chain, srch, dsth int64, sp RelaySequences, maxTxSize, maxMsgLength uint64, memo string, srcChannel *chantypes.IdentifiedChannel) error {
-	var errors error
+// RelayAcknowledgements creates transactions to relay acknowledgements from src to dst and from dst to src,
+// and returns the result of relaying each sequence, in either direction.
+// The error joins the errors of the sequences that failed to be relayed.
+func RelayAcknowledgements(ctx context.Context, log *zap.Logger, src, dst *Chain, srch, dsth int64, sp RelaySequences, maxTxSize, maxMsgLength uint64, memo string, srcChannel *chantypes.IdentifiedChannel) (_ SequenceResults, err error) {
+	ctx, span := startRelaySpan(ctx, "RelayAcknowledgements", src, dst, sp, srcChannel)
+	defer func() { tracing.End(span, err) }()
+
+	var (
+		mu      sync.Mutex
+		results SequenceResults
+		errors  error
+	)
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
 		var wg sync.WaitGroup
 
 		wg.Add(2)
 		go func() {
 			defer wg.Done()
-			err := relayAcknowledgements(ctx, log,
+			res, err := relayAcknowledgements(ctx, log,
 				src, srcChannel.ChannelId, srcChannel.PortId, srch, sp.Src,
 				dst, srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId,
 				maxTxSize, maxMsgLength, memo)
-			if err != nil {
-				multierr.AppendInto(&errors, err)
-			}
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, res...)
+			multierr.AppendInto(&errors, err)
 		}()
 		go func() {
 			defer wg.Done()
-			err := relayAcknowledgements(ctx, log,
+			res, err := relayAcknowledgements(ctx, log,
 				dst, srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId, dsth, sp.Dst,
 				src, srcChannel.ChannelId, srcChannel.PortId,
 				maxTxSize, maxMsgLength, memo)
-			if err != nil {
-				multierr.AppendInto(&errors, err)
-			}
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, res...)
+			multierr.AppendInto(&errors, err)
 		}()
 		wg.Wait()
 
 	}
-	return errors
+	return results, errors
 }
 
-// RelayPackets creates transactions to relay packets from src to dst and from dst to src
-func RelayPackets(ctx context.Context, log *zap.Logger, src, dst *Chain, srch, dsth int64, sp RelaySequences, maxTxSize, maxMsgLength uint64, memo string, srcChannel *chantypes.IdentifiedChannel) error {
+// RelayPackets creates transactions to relay packets from src to dst and from dst to src,
+// and returns the result of relaying each sequence, in either direction.
+// The error joins the errors of the sequences that failed to be relayed.
+func RelayPackets(ctx context.Context, log *zap.Logger, src, dst *Chain, srch, dsth int64, sp RelaySequences, maxTxSize, maxMsgLength uint64, memo string, srcChannel *chantypes.IdentifiedChannel) (_ SequenceResults, err error) {
+	ctx, span := startRelaySpan(ctx, "RelayPackets", src, dst, sp, srcChannel)
+	defer func() { tracing.End(span, err) }()
+
 	// set the maximum relay transaction constraints
 	msgs := &RelayMsgs{
 		Src:          []provider.RelayerMessage{},
@@ -420,24 +627,35 @@ func RelayPackets(ctx context.Context, log *zap.Logger, src, dst *Chain, srch, d
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
+		var (
+			srcResults, dstResults SequenceResults
+			srcErr, dstErr         error
+		)
 
 		eg, egCtx := errgroup.WithContext(ctx)
 		// add messages for sequences on src
 		eg.Go(func() error {
-			return AddMessagesForSequences(ctx, sp.Src, src, dst, srch, dsth, &msgs.Src, &msgs.Dst,
+			srcResults, srcErr = AddMessagesForSequences(ctx, sp.Src, src, dst, srch, dsth, &msgs.Src, &msgs.Dst,
 				srcChannel.ChannelId, srcChannel.PortId, srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId, srcChannel.Ordering)
+			return nil
 		})
 
 		// add messages for sequences on dst
 		eg.Go(func() error {
-			return AddMessagesForSequences(ctx, sp.Dst, dst, src, dsth, srch, &msgs.Dst, &msgs.Src,
+			dstResults, dstErr = AddMessagesForSequences(ctx, sp.Dst, dst, src, dsth, srch, &msgs.Dst, &msgs.Src,
 				srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId, srcChannel.ChannelId, srcChannel.PortId, srcChannel.Ordering)
+			return nil
 		})
 
-		if err := eg.Wait(); err != nil {
-			return err
+		_ = eg.Wait()
+		// The sequences that could not be queried are failed, the others are relayed regardless.
+		errs := multierr.Append(srcErr, dstErr)
+
+		report := func() {
+			srcResults.report(ctx, log, src.ChainID(), dst.ChainID())
+			dstResults.report(ctx, log, dst.ChainID(), src.ChainID())
 		}
 
 		if !msgs.Ready() {
@@ -448,7 +666,8 @@ func RelayPackets(ctx context.Context, log *zap.Logger, src, dst *Chain, srch, d
 				zap.String("dst_chain_id", dst.ChainID()),
 				zap.String("dst_port_id", srcChannel.Counterparty.PortId),
 			)
-			return nil
+			report()
+			return append(srcResults, dstResults...), errs
 		}
 
 		// Prepend non-empty msg lists with UpdateClient
@@ -463,11 +682,21 @@ func RelayPackets(ctx context.Context, log *zap.Logger, src, dst *Chain, srch, d
 		})
 
 		if err := eg.Wait(); err != nil {
-			return err
+			srcResults.fail(err)
+			dstResults.fail(err)
+			report()
+			return append(srcResults, dstResults...), multierr.Append(errs, err)
 		}
 
 		// send messages to their respective chains
-		result := msgs.Send(ctx, log, AsRelayMsgSender(src), AsRelayMsgSender(dst), memo)
+		sendCtx, sent := withSentMsgs(ctx)
+		result := msgs.Send(sendCtx, log, AsRelayMsgSender(src), AsRelayMsgSender(dst), memo)
+		// The messages relaying the packets of src are sent to dst, and their timeouts to src.
+		srcResults.resolve(sent, multierr.Append(result.DstSendError, result.SrcSendError))
+		dstResults.resolve(sent, multierr.Append(result.SrcSendError, result.DstSendError))
+		report()
+		results := append(srcResults, dstResults...)
+
 		if err := result.Error(); err != nil {
 			if result.PartiallySent() {
 				log.Info(
@@ -479,7 +708,7 @@ func RelayPackets(ctx context.Context, log *zap.Logger, src, dst *Chain, srch, d
 					zap.Error(err),
 				)
 			}
-			return err
+			return results, multierr.Append(errs, err)
 		}
 
 		if result.SuccessfulSrcBatches > 0 {
@@ -489,12 +718,15 @@ func RelayPackets(ctx context.Context, log *zap.Logger, src, dst *Chain, srch, d
 			dst.logPacketsRelayed(src, result.SuccessfulDstBatches, srcChannel.PortId, srcChannel.Counterparty.PortId)
 		}
 
-		return nil
+		return results, errs
 	}
 }
 
 // AddMessagesForSequences constructs RecvMsgs and TimeoutMsgs from sequence numbers on a src chain
-// and adds them to the appropriate queue of msgs for both src and dst
+// and adds them to the appropriate queue of msgs for both src and dst.
+// It returns a result for every sequence, those of the sequences with a queued message being completed
+// once the messages are sent. A sequence that fails to be queried is failed, along with the sequences following it
+// on an ordered channel, and returned in the error.
 func AddMessagesForSequences(
 	ctx context.Context,
 	sequences []uint64,
@@ -503,8 +735,23 @@ func AddMessagesForSequences(
 	srcMsgs, dstMsgs *[]provider.RelayerMessage,
 	srcChanID, srcPortID, dstChanID, dstPortID string,
 	order chantypes.Order,
-) error {
-	for _, seq := range sequences {
+) (SequenceResults, error) {
+	var (
+		results SequenceResults
+		errs    error
+	)
+	for i, seq := range sequences {
+		res := SequenceResult{ChainID: src.ChainID(), ChannelID: srcChanID, PortID: srcPortID, Sequence: seq}
+		if skipFilteredPacket(ctx, src, srcChanID, srcPortID, seq) {
+			res.Status, res.Reason = SequenceSkipped, "filtered out by the packet filter"
+			results = append(results, res)
+			// The packets following a skipped one can't be received on an ordered channel.
+			if order == chantypes.ORDERED {
+				results.skip(src.ChainID(), srcChanID, srcPortID, sequences[i+1:], fmt.Sprintf("follows skipped packet %d on an ordered channel", seq))
+				break
+			}
+			continue
+		}
 		recvMsg, timeoutMsg, err := src.ChainProvider.RelayPacketFromSequence(
 			ctx,
 			src.ChainProvider, dst.ChainProvider,
@@ -524,22 +771,49 @@ func AddMessagesForSequences(
 				zap.String("dst_channel_id", dstChanID),
 				zap.String("dst_port_id", dstPortID),
 				zap.String("channel_order", order.String()),
+				zap.Uint64("sequence", seq),
 				zap.Error(err),
 			)
-			return err
+			res.Status, res.Reason = SequenceFailed, err.Error()
+			results = append(results, res)
+			multierr.AppendInto(&errs, err)
+			// The packets following a failed one can't be received on an ordered channel either.
+			if order == chantypes.ORDERED {
+				results.skip(src.ChainID(), srcChanID, srcPortID, sequences[i+1:], fmt.Sprintf("follows failed packet %d on an ordered channel", seq))
+				break
+			}
+			continue
 		}
 
 		// Depending on the type of message to be relayed, we need to send to different chains
 		if recvMsg != nil {
+			if rateLimitedPacket(ctx, src, srcChanID, srcPortID, dstChanID, dstPortID, seq) {
+				// The channel is paused, its remaining packets are relayed once it is resumed.
+				results.skip(src.ChainID(), srcChanID, srcPortID, sequences[i:], "channel paused by a rate limit")
+				break
+			}
+			res.MsgType, res.msg = packetMsgType(recvMsg), recvMsg
 			*dstMsgs = append(*dstMsgs, recvMsg)
 		}
 
 		if timeoutMsg != nil {
+			res.MsgType, res.msg = packetMsgType(timeoutMsg), timeoutMsg
 			*srcMsgs = append(*srcMsgs, timeoutMsg)
+			// Timing out a packet closes an ordered channel, the packets following it can't be received anymore.
+			if order == chantypes.ORDERED {
+				results = append(results, res)
+				results.skip(src.ChainID(), srcChanID, srcPortID, sequences[i+1:], fmt.Sprintf("ordered channel closes on the timeout of packet %d", seq))
+				break
+			}
+		}
+
+		if res.msg == nil {
+			res.Status, res.Reason = SequenceSkipped, "no message to relay"
 		}
+		results = append(results, res)
 	}
 
-	return nil
+	return results, errs
 }
 
 // PrependUpdateClientMsg adds an UpdateClient msg to the front of non-empty msg lists
@@ -554,48 +828,49 @@ func PrependUpdateClientMsg(ctx context.Context, msgs *[]provider.RelayerMessage
 		var err error
 		srcHeader, err = src.ChainProvider.GetIBCUpdateHeader(ctx, srch, dst.ChainProvider, dst.PathEnd.ClientID)
 		return err
-	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, src.retryOptions(ctx, retry.OnRetry(func(n uint, err error) {
 		src.log.Info(
 			"PrependUpdateClientMsg: failed to get IBC update header",
 			zap.String("src_chain_id", src.ChainID()),
 			zap.String("dst_chain_id", dst.ChainID()),
 			zap.Uint("attempt", n+1),
-			zap.Uint("attempt_limit", RtyAttNum),
+			zap.Uint("attempt_limit", src.retryAttempts()),
 			zap.Error(err),
 		)
 
-	})); err != nil {
+	}))...); err != nil {
 		return err
 	}
 
-	// Construct UpdateClient msg
-	var updateMsg provider.RelayerMessage
+	trustedHeight, err := cosmos.GetTrustedHeight(srcHeader)
+	if err != nil {
+		return err
+	}
+	// no need to append update message, the client is already updated
+	if srcHeader.GetHeight().LTE(*trustedHeight) {
+		return nil
+	}
+
+	// Construct UpdateClient msgs
+	var updateMsgs []provider.RelayerMessage
 	if err := retry.Do(func() error {
 		var err error
-		updateMsg, err = dst.ChainProvider.MsgUpdateClient(dst.PathEnd.ClientID, srcHeader)
+		updateMsgs, err = msgUpdateClients(ctx, src.ChainProvider, dst.ChainProvider, dst.PathEnd.ClientID, srcHeader)
 		return err
-	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, dst.retryOptions(ctx, retry.OnRetry(func(n uint, err error) {
 		dst.log.Info(
 			"PrependUpdateClientMsg: failed to build message",
 			zap.String("dst_chain_id", dst.ChainID()),
 			zap.Uint("attempt", n+1),
-			zap.Uint("attempt_limit", RtyAttNum),
+			zap.Uint("attempt_limit", dst.retryAttempts()),
 			zap.Error(err),
 		)
-	})); err != nil {
+	}))...); err != nil {
 		return err
 	}
 
-	trustedHeight, err := cosmos.GetTrustedHeight(srcHeader)
-	if err != nil {
-		return err
-	}
-	// no need to append update message, the client is already updated
-	if srcHeader.GetHeight().LTE(*trustedHeight) {
-		return nil
-	}
-	// Prepend UpdateClient msg to the slice of msgs
-	*msgs = append([]provider.RelayerMessage{updateMsg}, *msgs...)
+	// Prepend UpdateClient msgs to the slice of msgs
+	*msgs = append(updateMsgs, *msgs...)
 
 	return nil
 }
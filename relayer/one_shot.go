@@ -0,0 +1,169 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// UnrelayedChannel lists the packets and acknowledgements of a channel left unrelayed by a one-shot run,
+// by the chain they are to be relayed from.
+type UnrelayedChannel struct {
+	Path      string              `json:"path"`
+	ChainID   string              `json:"chain_id"`
+	PortID    string              `json:"port_id"`
+	ChannelID string              `json:"channel_id"`
+	Packets   map[string][]uint64 `json:"packets,omitempty"`
+	Acks      map[string][]uint64 `json:"acks,omitempty"`
+}
+
+// UnrelayedError is sent by StartRelayer WithOneShot when packets or acknowledgements remain unrelayed on the
+// filtered channels of the paths once they were flushed, e.g. because relaying them failed, their rate limit was
+// reached or, with WithSettlementFinality, they were finalized while flushing.
+// Packets skipped by the packet filter of their path are never reported.
+type UnrelayedError struct {
+	Channels []UnrelayedChannel
+}
+
+func (e *UnrelayedError) Error() string {
+	var packets, acks int
+	for _, c := range e.Channels {
+		for _, seqs := range c.Packets {
+			packets += len(seqs)
+		}
+		for _, seqs := range c.Acks {
+			acks += len(seqs)
+		}
+	}
+	channels := make([]string, len(e.Channels))
+	for i, c := range e.Channels {
+		channels[i] = fmt.Sprintf("%s:%s/%s", c.Path, c.ChainID, c.ChannelID)
+	}
+	return fmt.Sprintf("%d packets and %d acknowledgements remain unrelayed on %s", packets, acks, strings.Join(channels, ", "))
+}
+
+// runOnce flushes every path once, in the order of their names, then reports the packets and acknowledgements
+// still pending on their filtered channels as an *UnrelayedError, along with the errors relaying them.
+func (s *supervisor) runOnce(ctx context.Context, errCh chan<- error) {
+	defer close(errCh)
+	defer s.closePathLogs()
+
+	names := make([]string, 0, len(s.runners))
+	for name := range s.runners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		errs      error
+		unrelayed UnrelayedError
+	)
+	for _, name := range names {
+		r := s.runners[name]
+		res := s.flush(ctx, r)
+		ctx := s.flushContext(ctx, r)
+		if res.Err != nil {
+			multierr.AppendInto(&errs, fmt.Errorf("flush path %s: %w", name, res.Err))
+		}
+		if ctx.Err() != nil {
+			multierr.AppendInto(&errs, ctx.Err())
+			break
+		}
+		channels, err := unrelayedChannels(ctx, r, s.finalityGating)
+		if err != nil {
+			multierr.AppendInto(&errs, fmt.Errorf("query unrelayed packets of path %s: %w", name, err))
+			continue
+		}
+		r.log.Info(
+			"Flushed path",
+			zap.String("path_name", name),
+			zap.Int("txs", len(res.Txs)),
+			zap.Int("relayed", res.Sequences.Count(SequenceRelayed)),
+			zap.Int("failed", res.Sequences.Count(SequenceFailed)),
+			zap.Int("paused_channels", len(res.Paused)),
+			zap.Int("unrelayed_channels", len(channels)),
+		)
+		unrelayed.Channels = append(unrelayed.Channels, channels...)
+	}
+
+	if len(unrelayed.Channels) > 0 {
+		multierr.AppendInto(&errs, &unrelayed)
+	}
+	if errs != nil {
+		errCh <- errs
+	}
+}
+
+// unrelayedChannels returns the open filtered channels of the path of r with packets or acknowledgements
+// left to relay, leaving out its paused channels and the packets skipped by its packet filter.
+func unrelayedChannels(ctx context.Context, r *pathRunner, finalityGating bool) ([]UnrelayedChannel, error) {
+	srcChannels, _, err := r.connections().channels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	open := filterOpenChannels(srcChannels)
+	ids := make([]string, 0, len(open))
+	for id := range open {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var unrelayed []UnrelayedChannel
+	for _, id := range ids {
+		channel := open[id].channel
+		if r.pauses.isPaused(channel.PortId, channel.ChannelId) {
+			continue
+		}
+		srch, dsth, err := QueryLatestHeights(ctx, r.src, r.dst)
+		if err != nil {
+			return nil, err
+		}
+		sp, err := unrelayedPackets(ctx, r.src, r.dst, srch-1, dsth-1, channel, finalityGating)
+		if err != nil {
+			return nil, err
+		}
+		ap := relayedAcknowledgements(ctx, r.src, r.dst, UnrelayedAcknowledgements(ctx, r.src, r.dst, srch-1, dsth-1, channel))
+
+		c := UnrelayedChannel{Path: r.name, ChainID: r.src.ChainID(), PortID: channel.PortId, ChannelID: channel.ChannelId}
+		c.Packets = sequencesByChain(
+			r.src.ChainID(), unfilteredPackets(ctx, r.src, channel.ChannelId, channel.PortId, sp.Src),
+			r.dst.ChainID(), unfilteredPackets(ctx, r.dst, channel.Counterparty.ChannelId, channel.Counterparty.PortId, sp.Dst),
+		)
+		c.Acks = sequencesByChain(r.src.ChainID(), ap.Src, r.dst.ChainID(), ap.Dst)
+		if c.Packets != nil || c.Acks != nil {
+			unrelayed = append(unrelayed, c)
+		}
+	}
+	return unrelayed, nil
+}
+
+// unfilteredPackets returns the sequences of the packets sent on the channel of c which are not skipped
+// by the packet filter attached to ctx.
+func unfilteredPackets(ctx context.Context, c *Chain, channelID, portID string, seqs []uint64) []uint64 {
+	var unfiltered []uint64
+	for _, seq := range seqs {
+		if !skipFilteredPacket(ctx, c, channelID, portID, seq) {
+			unfiltered = append(unfiltered, seq)
+		}
+	}
+	return unfiltered
+}
+
+// sequencesByChain returns the sequences by chain ID, or nil if there are none.
+func sequencesByChain(srcChainID string, src []uint64, dstChainID string, dst []uint64) map[string][]uint64 {
+	if len(src) == 0 && len(dst) == 0 {
+		return nil
+	}
+	seqs := make(map[string][]uint64)
+	if len(src) > 0 {
+		seqs[srcChainID] = src
+	}
+	if len(dst) > 0 {
+		seqs[dstChainID] = dst
+	}
+	return seqs
+}
@@ -0,0 +1,55 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOneShotReadOnly(t *testing.T) {
+	err := <-StartRelayer(context.Background(), zap.NewNop(), nil, nil, 0, 0, "", ProcessorLegacy, 0, WithOneShot(), WithReadOnly())
+	require.ErrorContains(t, err, "read-only")
+}
+
+func TestUnrelayedError(t *testing.T) {
+	require.Nil(t, sequencesByChain("chain-a", nil, "chain-b", []uint64{}))
+	err := &UnrelayedError{Channels: []UnrelayedChannel{
+		{
+			Path: "demo", ChainID: "chain-a", PortID: "transfer", ChannelID: "channel-0",
+			Packets: sequencesByChain("chain-a", []uint64{1, 2}, "chain-b", []uint64{7}),
+		},
+		{
+			Path: "demo2", ChainID: "chain-a", PortID: "transfer", ChannelID: "channel-1",
+			Acks: sequencesByChain("chain-a", nil, "chain-c", []uint64{3}),
+		},
+	}}
+	require.Equal(t, map[string][]uint64{"chain-a": {1, 2}, "chain-b": {7}}, err.Channels[0].Packets)
+	require.Equal(t, "3 packets and 1 acknowledgements remain unrelayed on demo:chain-a/channel-0, demo2:chain-a/channel-1", err.Error())
+}
+
+func TestUnrelayedChannelsSkipsPaused(t *testing.T) {
+	hub := &flushProvider{clientConnectionsProvider: clientConnectionsProvider{
+		channels: map[string][]*chantypes.IdentifiedChannel{
+			"connection-0": {
+				testConnectionChannel("transfer", "channel-0", "connection-0"),
+				testConnectionChannel("transfer", "channel-1", "connection-0"),
+			},
+		},
+	}}
+	r := &pathRunner{
+		name:   "hub-rollapp",
+		src:    NewChain(zap.NewNop(), hub, false).withPathEnd(&PathEnd{ChainID: "hub", ClientID: "07-tendermint-0", ConnectionID: "connection-0"}),
+		dst:    NewChain(zap.NewNop(), &flushProvider{}, false).withPathEnd(&PathEnd{ChainID: "rollapp", ClientID: "07-tendermint-5", ConnectionID: "connection-7"}),
+		pauses: newChannelPauses("hub"),
+	}
+	r.pauses.pause("transfer", "channel-1")
+
+	// Paused channels are not reported as unrelayed, nor even queried.
+	unrelayed, err := unrelayedChannels(context.Background(), r, false)
+	require.NoError(t, err)
+	require.Empty(t, unrelayed)
+	require.Equal(t, []string{"channel-0"}, hub.queriedChannels())
+}
@@ -0,0 +1,388 @@
+package relayer
+
+import (
+	"net"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/ackstore"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/oracle"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+)
+
+// StartOption configures optional behavior of StartRelayer.
+type StartOption func(*startOptions)
+
+type startOptions struct {
+	adminListener   net.Listener
+	adminSecret     []byte
+	metricsListener net.Listener
+	readOnly        bool
+	oneShot         bool
+	finalityGating  bool
+	relayDirection  RelayDirection
+	feedSinks       []feed.Sink
+	changefeedSinks []feed.Sink
+	ackStore        *ackstore.Store
+
+	registerCounterpartyPayee bool
+
+	channelDiscoveryInterval time.Duration
+	timeoutScanInterval      time.Duration
+	maxConcurrentChannels    int
+	batchWindow              time.Duration
+	dormantAfter             time.Duration
+	clientRefreshThreshold   float64
+
+	consensusStateCheckInterval time.Duration
+	maxConsensusStates          uint64
+
+	escrowCheckInterval time.Duration
+
+	clientChainIDCheckInterval time.Duration
+
+	timeoutReportInterval time.Duration
+	timeoutReportBlocks   uint64
+	timeoutReportWindow   time.Duration
+
+	orderedChannelCheckInterval time.Duration
+	orderedChannelStallAfter    time.Duration
+
+	upgradeCheckInterval time.Duration
+	upgradeHaltMargin    int64
+
+	storeCompactionInterval time.Duration
+	retention               RetentionPolicy
+	repairLog               string
+
+	logFormat string
+
+	priceOracle         oracle.Oracle
+	priceOracleInterval time.Duration
+
+	healthProbes *HealthProbesConfig
+
+	walletMonitor *WalletMonitorConfig
+
+	featureFlags FeatureFlagsConfig
+
+	alerts *AlertsConfig
+
+	releaseCheck *ReleaseCheckConfig
+
+	stuckPackets *StuckPacketsConfig
+
+	maintenance *MaintenanceConfig
+
+	backfill processor.Backfill
+}
+
+func newStartOptions(opts []StartOption) startOptions {
+	var o startOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithAdminListener starts the admin API on the given listener for as long as the relayer runs.
+func WithAdminListener(ln net.Listener) StartOption {
+	return func(o *startOptions) {
+		o.adminListener = ln
+	}
+}
+
+// WithAdminSecret requires the state-changing requests to the admin API to be signed with secret.
+func WithAdminSecret(secret []byte) StartOption {
+	return func(o *startOptions) {
+		o.adminSecret = secret
+	}
+}
+
+// WithMetricsListener serves the Prometheus metrics of the relayer at /metrics on the given listener
+// for as long as the relayer runs.
+func WithMetricsListener(ln net.Listener) StartOption {
+	return func(o *startOptions) {
+		o.metricsListener = ln
+	}
+}
+
+// WithReadOnly only observes the paths, without sending any transactions, so no keys are required.
+// Read-only paths are always relayed with the events processor.
+func WithReadOnly() StartOption {
+	return func(o *startOptions) {
+		o.readOnly = true
+	}
+}
+
+// WithOneShot relays the pending packets and acknowledgements of the filtered channels of the paths once,
+// instead of relaying continuously. The returned error channel is closed once done, after receiving an
+// *UnrelayedError if any packets or acknowledgements remain unrelayed.
+func WithOneShot() StartOption {
+	return func(o *startOptions) {
+		o.oneShot = true
+	}
+}
+
+// WithFeedSinks publishes the packet lifecycle observed on the relayed channels to the given sinks,
+// along with the transactions sent by the events processor.
+func WithFeedSinks(sinks ...feed.Sink) StartOption {
+	return func(o *startOptions) {
+		o.feedSinks = append(o.feedSinks, sinks...)
+	}
+}
+
+// WithChangefeedSinks publishes the changes of the configuration and runtime state of the paths to the given sinks,
+// such as channels paused or paths handed off to another processor, starting with a snapshot of every path.
+func WithChangefeedSinks(sinks ...feed.Sink) StartOption {
+	return func(o *startOptions) {
+		o.changefeedSinks = append(o.changefeedSinks, sinks...)
+	}
+}
+
+// WithSettlementFinality only relays packets sent on a rollapp once the block they were sent in
+// has been finalized on the settlement layer. It applies to both processors and to flushes.
+func WithSettlementFinality() StartOption {
+	return func(o *startOptions) {
+		o.finalityGating = true
+	}
+}
+
+// WithRelayDirection only relays the packets of direction d, along with their acknowledgements and timeouts,
+// leaving the other direction to another process. It applies to both processors and to flushes.
+func WithRelayDirection(d RelayDirection) StartOption {
+	return func(o *startOptions) {
+		o.relayDirection = d
+	}
+}
+
+// WithAckStore persists the acknowledgements relayed by the legacy processor to st,
+// so that a restarted relayer resumes from them instead of rescanning every acknowledgement.
+// The caller remains responsible for closing st once the relayer stopped.
+func WithAckStore(st *ackstore.Store) StartOption {
+	return func(o *startOptions) {
+		o.ackStore = st
+	}
+}
+
+// WithStoreRetention applies policy to the state persisted by the relayer at the given interval, deleting the entries
+// it no longer retains and compacting the stores, and publishes the size of the stores in the metrics and the admin API status.
+func WithStoreRetention(interval time.Duration, policy RetentionPolicy) StartOption {
+	return func(o *startOptions) {
+		o.storeCompactionInterval = interval
+		o.retention = policy
+	}
+}
+
+// WithRepairLog retains and measures the repair log at file along with the other persisted state,
+// see WithStoreRetention.
+func WithRepairLog(file string) StartOption {
+	return func(o *startOptions) {
+		o.repairLog = file
+	}
+}
+
+// WithChannelDiscoveryInterval queries the channels of the paths relayed by the legacy processor at the given interval,
+// so that channels opened after the relayer started are relayed without restarting it.
+func WithChannelDiscoveryInterval(d time.Duration) StartOption {
+	return func(o *startOptions) {
+		o.channelDiscoveryInterval = d
+	}
+}
+
+// WithTimeoutScanInterval scans the channels of the paths relayed by the legacy processor for packets whose timeout
+// elapsed on the receiving chain, or whose receiving channel end was closed, at the given interval,
+// and relays MsgTimeout, or MsgTimeoutOnClose, back to the chain they were sent from.
+func WithTimeoutScanInterval(d time.Duration) StartOption {
+	return func(o *startOptions) {
+		o.timeoutScanInterval = d
+	}
+}
+
+// WithClientRefresh keeps the clients of every path from expiring, independently of packet flow,
+// by updating a client once the time left until it expires falls below threshold times its trusting period.
+// The threshold must be between 0 and 1, e.g. 1/3. Only tendermint and furyint clients expire and are refreshed,
+// and clients are not refreshed in read-only mode.
+func WithClientRefresh(threshold float64) StartOption {
+	return func(o *startOptions) {
+		o.clientRefreshThreshold = threshold
+	}
+}
+
+// WithCounterpartyPayeeRegistration registers, before relaying starts, the relayer address on the other end
+// of every path as the counterparty payee of the relayer on both ends of each open fee-enabled (ICS-29) channel,
+// so that forward relay fees are paid out without a manual registration. Registered channels are left untouched.
+func WithCounterpartyPayeeRegistration() StartOption {
+	return func(o *startOptions) {
+		o.registerCounterpartyPayee = true
+	}
+}
+
+// WithConsensusStateJanitor counts the consensus states stored for the clients of every path at the given interval,
+// publishing them in the metrics and the admin API status, and warns about clients holding more than maxStates,
+// 0 to never warn.
+func WithConsensusStateJanitor(interval time.Duration, maxStates uint64) StartOption {
+	return func(o *startOptions) {
+		o.consensusStateCheckInterval = interval
+		o.maxConsensusStates = maxStates
+	}
+}
+
+// WithEscrowMonitor queries the escrow account balances of the transfer channels of every path at the given interval,
+// publishing them in the metrics and the admin API status.
+func WithEscrowMonitor(interval time.Duration) StartOption {
+	return func(o *startOptions) {
+		o.escrowCheckInterval = interval
+	}
+}
+
+// WithClientChainIDCheck verifies at the given interval that the clients of every path track the chain ID,
+// revision and heights of their live counterparty, publishing the clients that do not in the metrics
+// and the admin API status, e.g. after a rollapp restarted from genesis.
+func WithClientChainIDCheck(interval time.Duration) StartOption {
+	return func(o *startOptions) {
+		o.clientChainIDCheckInterval = interval
+	}
+}
+
+// WithHealthProbes runs the health probes of cfg, degrading or pausing the relayed paths they gate while they fail,
+// publishing their state in the metrics and the admin API status.
+func WithHealthProbes(cfg *HealthProbesConfig) StartOption {
+	return func(o *startOptions) {
+		o.healthProbes = cfg
+	}
+}
+
+// WithWalletMonitor checks the balances of the wallets of cfg, alerting while they are below their threshold
+// and topping them up from their funding account if configured, publishing their state in the metrics and the admin API status.
+func WithWalletMonitor(cfg *WalletMonitorConfig) StartOption {
+	return func(o *startOptions) {
+		o.walletMonitor = cfg
+	}
+}
+
+// WithAlerts posts the alerts of cfg to its webhooks while relaying fails, such as on sustained relay errors
+// or clients nearing expiry, publishing the alerts firing in the admin API status.
+func WithAlerts(cfg *AlertsConfig) StartOption {
+	return func(o *startOptions) {
+		o.alerts = cfg
+	}
+}
+
+// WithReleaseCheck checks the running version of the relayer against the release manifest of cfg, warning of
+// new releases and of the upgrades of the relayed chains requiring a newer relayer, in the logs and the alerts.
+func WithReleaseCheck(cfg *ReleaseCheckConfig) StartOption {
+	return func(o *startOptions) {
+		o.releaseCheck = cfg
+	}
+}
+
+// WithStuckPackets detects the packets and acknowledgements pending for longer than the threshold of cfg,
+// escalating them as they stay pending, and reports them with the last attempt at relaying them
+// in the metrics and the admin API.
+func WithStuckPackets(cfg *StuckPacketsConfig) StartOption {
+	return func(o *startOptions) {
+		o.stuckPackets = cfg
+	}
+}
+
+// WithFeatureFlags enables or disables the experimental features of the paths per cfg, such as relaying
+// the packets of trusted rollapp paths before finalization, until changed through the admin API.
+func WithFeatureFlags(cfg FeatureFlagsConfig) StartOption {
+	return func(o *startOptions) {
+		o.featureFlags = cfg
+	}
+}
+
+// WithMaintenance configures the scheduler running the periodic maintenance tasks, such as keeping clients from
+// expiring and auditing escrow accounts, with the jitter of their runs and which tasks are disabled.
+func WithMaintenance(cfg *MaintenanceConfig) StartOption {
+	return func(o *startOptions) {
+		o.maintenance = cfg
+	}
+}
+
+// WithPriceOracle prices tokens in USD with o, refreshing the prices at the given interval,
+// for the paths filtering transfers by their USD value, the metrics and the admin API status.
+func WithPriceOracle(po oracle.Oracle, interval time.Duration) StartOption {
+	return func(o *startOptions) {
+		o.priceOracle = po
+		o.priceOracleInterval = interval
+	}
+}
+
+// WithPendingTimeoutReport reports at the given interval the packets of every path pending relay
+// that are within blocks or window of their timeout on the counterparty, or past it, in the metrics
+// and the admin API status, so that operators can intervene before the transfers expire.
+func WithPendingTimeoutReport(interval time.Duration, blocks uint64, window time.Duration) StartOption {
+	return func(o *startOptions) {
+		o.timeoutReportInterval = interval
+		o.timeoutReportBlocks = blocks
+		o.timeoutReportWindow = window
+	}
+}
+
+// WithOrderedChannelStallDetection tracks at the given interval the next sequence to receive of both ends of the
+// ordered channels of every path, alerting in the logs, the metrics and the admin API status when it did not advance
+// for stallAfter while packets were waiting for it, with the sequence blocking the channel.
+func WithOrderedChannelStallDetection(interval, stallAfter time.Duration) StartOption {
+	return func(o *startOptions) {
+		o.orderedChannelCheckInterval = interval
+		o.orderedChannelStallAfter = stallAfter
+	}
+}
+
+// WithUpgradeWatch checks at the given interval the software upgrades scheduled in the upgrade module of the chains
+// of every path, pausing the paths of a chain from haltMargin blocks before the height of its upgrade until it commits
+// blocks at that height, running the upgraded software. The paths are then resumed once the event subscriptions over
+// the websocket of the chain are established again and the clients of the paths are updated.
+func WithUpgradeWatch(interval time.Duration, haltMargin int64) StartOption {
+	return func(o *startOptions) {
+		o.upgradeCheckInterval = interval
+		o.upgradeHaltMargin = haltMargin
+	}
+}
+
+// WithMaxConcurrentChannels relays at most n channels of each path relayed by the legacy processor at once,
+// unless the path configures its own limit. Channels beyond the limit are queued and take turns,
+// each channel relaying its pending packets and acknowledgements before handing over to the next.
+func WithMaxConcurrentChannels(n int) StartOption {
+	return func(o *startOptions) {
+		o.maxConcurrentChannels = n
+	}
+}
+
+// WithCrossChannelBatching gathers, for the given window, the messages the channels of each path relayed by
+// the legacy processor send to the same chain, and sends them together, in channel order, in as few transactions
+// as the max tx size and message length allow. The events processor already sends the messages of all channels
+// of a path together.
+func WithCrossChannelBatching(window time.Duration) StartOption {
+	return func(o *startOptions) {
+		o.batchWindow = window
+	}
+}
+
+// WithDormantChannels stops relaying the channels of the paths relayed by the legacy processor which had
+// no packet or acknowledgement to relay for after, sparing the queries of their packets and acknowledgements.
+// Dormant channels are relayed again as soon as the events of either chain show packets sent or acknowledgements
+// written on them. The events processor does not query channels one by one and relays all of them.
+func WithDormantChannels(after time.Duration) StartOption {
+	return func(o *startOptions) {
+		o.dormantAfter = after
+	}
+}
+
+// WithLogFormat sets the format of the logs of the paths configuring their own logging but no format,
+// that of the relayer's logs, which defaults to auto.
+func WithLogFormat(format string) StartOption {
+	return func(o *startOptions) {
+		o.logFormat = format
+	}
+}
+
+// WithBackfill bounds the backfill of the initial block history by the events processor, see processor.Backfill.
+func WithBackfill(backfill processor.Backfill) StartOption {
+	return func(o *startOptions) {
+		o.backfill = backfill
+	}
+}
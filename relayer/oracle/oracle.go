@@ -0,0 +1,138 @@
+// Package oracle prices the tokens of the relayed chains in USD through external price oracles,
+// so that amounts of the heterogeneous gas tokens of rollapps can be compared with one another.
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNoPrice is returned by an Oracle which has no price for a token.
+var ErrNoPrice = errors.New("no price for token")
+
+// Oracle prices tokens in USD.
+type Oracle interface {
+	// Price returns the USD price of one base unit of denom on the chain chainID, e.g. of 1urax rather than 1RAX.
+	Price(ctx context.Context, chainID, denom string) (float64, error)
+}
+
+// Static prices the tokens it lists at fixed prices, by chain ID and denom.
+type Static map[string]map[string]float64
+
+func (s Static) Price(_ context.Context, chainID, denom string) (float64, error) {
+	price, ok := s[chainID][denom]
+	if !ok {
+		return 0, fmt.Errorf("%w %s on %s", ErrNoPrice, denom, chainID)
+	}
+	return price, nil
+}
+
+// HTTP queries the prices from an external oracle over HTTP.
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTP returns an oracle sending a GET request to url for each price, after replacing {chain_id} and {denom}
+// in url with the chain ID and denom of the token. The oracle must respond with a JSON object whose usd field
+// is the price of one base unit of the token, e.g. {"usd": 0.0000012}.
+func NewHTTP(url string) *HTTP {
+	return &HTTP{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (h *HTTP) Price(ctx context.Context, chainID, denom string) (float64, error) {
+	u := strings.NewReplacer("{chain_id}", url.QueryEscape(chainID), "{denom}", url.QueryEscape(denom)).Replace(h.url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := h.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return 0, fmt.Errorf("%w %s on %s", ErrNoPrice, denom, chainID)
+	case res.StatusCode >= 300:
+		return 0, fmt.Errorf("price oracle responded with status %s", res.Status)
+	}
+	var body struct {
+		USD *float64 `json:"usd"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("invalid price oracle response: %w", err)
+	}
+	if body.USD == nil {
+		return 0, fmt.Errorf("%w %s on %s", ErrNoPrice, denom, chainID)
+	}
+	return *body.USD, nil
+}
+
+// Fallback prices tokens with the first of its oracles which has a price for them.
+type Fallback []Oracle
+
+func (f Fallback) Price(ctx context.Context, chainID, denom string) (float64, error) {
+	err := fmt.Errorf("%w %s on %s", ErrNoPrice, denom, chainID)
+	for _, o := range f {
+		var price float64
+		if price, err = o.Price(ctx, chainID, denom); err == nil {
+			return price, nil
+		}
+	}
+	return 0, err
+}
+
+// Config configures the price oracle of the relayer.
+type Config struct {
+	// URL is the URL of an external oracle, see NewHTTP.
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Prices are fixed USD prices of one base unit of tokens, by chain ID and denom, taking precedence over URL,
+	// e.g. to price a rollapp gas token the external oracle does not know.
+	Prices map[string]map[string]float64 `yaml:"prices,omitempty" json:"prices,omitempty"`
+
+	// RefreshInterval is how often the prices are refreshed from the oracles, every minute by default.
+	RefreshInterval string `yaml:"refresh-interval,omitempty" json:"refresh-interval,omitempty"`
+}
+
+// Oracle returns the oracle configured by c and the interval to refresh its prices at,
+// or a nil Oracle if c configures none.
+func (c *Config) Oracle() (Oracle, time.Duration, error) {
+	if c == nil || (c.URL == "" && len(c.Prices) == 0) {
+		return nil, 0, nil
+	}
+	interval := time.Minute
+	if c.RefreshInterval != "" {
+		d, err := time.ParseDuration(c.RefreshInterval)
+		if err != nil || d <= 0 {
+			return nil, 0, fmt.Errorf("invalid price oracle refresh interval %q", c.RefreshInterval)
+		}
+		interval = d
+	}
+
+	var f Fallback
+	if len(c.Prices) > 0 {
+		f = append(f, Static(c.Prices))
+	}
+	if c.URL != "" {
+		if _, err := url.Parse(c.URL); err != nil {
+			return nil, 0, fmt.Errorf("invalid price oracle url: %w", err)
+		}
+		f = append(f, NewHTTP(c.URL))
+	}
+	if len(f) == 1 {
+		return f[0], interval, nil
+	}
+	return f, interval, nil
+}
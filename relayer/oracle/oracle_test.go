@@ -0,0 +1,97 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPOracle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Query().Get("denom") {
+		case "urax":
+			require.Equal(t, "rollappx_1234-1", req.URL.Query().Get("chain"))
+			_, _ = w.Write([]byte(`{"usd": 0.0000012}`))
+		case "ibc/ABC":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	o := NewHTTP(srv.URL + "/price?chain={chain_id}&denom={denom}")
+	price, err := o.Price(ctx, "rollappx_1234-1", "urax")
+	require.NoError(t, err)
+	require.Equal(t, 0.0000012, price)
+
+	_, err = o.Price(ctx, "rollappx_1234-1", "ibc/ABC")
+	require.ErrorIs(t, err, ErrNoPrice)
+	_, err = o.Price(ctx, "rollappx_1234-1", "uspam")
+	require.ErrorIs(t, err, ErrNoPrice)
+}
+
+func TestConfigOracle(t *testing.T) {
+	var c *Config
+	o, _, err := c.Oracle()
+	require.NoError(t, err)
+	require.Nil(t, o)
+
+	c = &Config{Prices: map[string]map[string]float64{"hub": {"ufury": 0.5}}, RefreshInterval: "30s"}
+	o, interval, err := c.Oracle()
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, interval)
+	price, err := o.Price(context.Background(), "hub", "ufury")
+	require.NoError(t, err)
+	require.Equal(t, 0.5, price)
+
+	// Fixed prices take precedence over the external oracle, which prices the other tokens.
+	c.URL = "http://127.0.0.1:0/price"
+	o, _, err = c.Oracle()
+	require.NoError(t, err)
+	price, err = o.Price(context.Background(), "hub", "ufury")
+	require.NoError(t, err)
+	require.Equal(t, 0.5, price)
+	_, err = o.Price(context.Background(), "hub", "uatom")
+	require.Error(t, err)
+
+	c.RefreshInterval = "soon"
+	_, _, err = c.Oracle()
+	require.Error(t, err)
+}
+
+func TestPrices(t *testing.T) {
+	o := Static{"hub": {"ufury": 0.5}}
+	p := NewPrices(o)
+
+	// Tokens are priced once refreshed.
+	_, ok := p.USD("hub", "ufury")
+	require.False(t, ok)
+	p.Track("hub", "uatom")
+	p.Refresh(context.Background())
+	usd, ok := p.USD("hub", "ufury")
+	require.True(t, ok)
+	require.Equal(t, 0.5, usd)
+
+	s := p.Snapshot()
+	require.Len(t, s, 2)
+	require.Equal(t, "uatom", s[0].Denom)
+	require.Contains(t, s[0].Error, "no price")
+
+	// A token which can no longer be priced keeps its last price.
+	delete(o["hub"], "ufury")
+	p.Refresh(context.Background())
+	usd, ok = p.USD("hub", "ufury")
+	require.True(t, ok)
+	require.Equal(t, 0.5, usd)
+	require.NotEmpty(t, p.Snapshot()[1].Error)
+
+	var none *Prices
+	_, ok = none.USD("hub", "ufury")
+	require.False(t, ok)
+}
@@ -0,0 +1,112 @@
+package oracle
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Price is the last USD price of one base unit of a token, or why the token could not be priced.
+type Price struct {
+	ChainID   string    `json:"chain_id"`
+	Denom     string    `json:"denom"`
+	USD       float64   `json:"usd"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type token struct {
+	chainID, denom string
+}
+
+// Prices caches the prices of the tokens it was asked for, refreshed from an oracle with Refresh,
+// so that they can be read while relaying without waiting on the oracle.
+// A nil Prices prices nothing.
+type Prices struct {
+	oracle Oracle
+
+	mu     sync.RWMutex
+	prices map[token]Price
+}
+
+// NewPrices returns the prices of the tokens tracked, refreshed from o.
+func NewPrices(o Oracle) *Prices {
+	return &Prices{oracle: o, prices: make(map[token]Price)}
+}
+
+// Track has the price of denom on chainID refreshed from now on.
+func (p *Prices) Track(chainID, denom string) {
+	if p == nil {
+		return
+	}
+	t := token{chainID, denom}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.prices[t]; !ok {
+		p.prices[t] = Price{ChainID: chainID, Denom: denom}
+	}
+}
+
+// USD returns the last USD price of one base unit of denom on chainID, and false if it is not known yet,
+// in which case the token is tracked to be priced at the next refresh.
+func (p *Prices) USD(chainID, denom string) (float64, bool) {
+	if p == nil {
+		return 0, false
+	}
+	p.mu.RLock()
+	price, ok := p.prices[token{chainID, denom}]
+	p.mu.RUnlock()
+	if !ok {
+		p.Track(chainID, denom)
+		return 0, false
+	}
+	return price.USD, !price.UpdatedAt.IsZero()
+}
+
+// Refresh queries the prices of all tracked tokens from the oracle. A token which can no longer be priced
+// keeps its last price, along with the error.
+func (p *Prices) Refresh(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	p.mu.RLock()
+	tokens := make([]token, 0, len(p.prices))
+	for t := range p.prices {
+		tokens = append(tokens, t)
+	}
+	p.mu.RUnlock()
+
+	for _, t := range tokens {
+		usd, err := p.oracle.Price(ctx, t.chainID, t.denom)
+		p.mu.Lock()
+		price := p.prices[t]
+		if err != nil {
+			price.Error = err.Error()
+		} else {
+			price.USD, price.UpdatedAt, price.Error = usd, time.Now().UTC(), ""
+		}
+		p.prices[t] = price
+		p.mu.Unlock()
+	}
+}
+
+// Snapshot returns the prices of all tracked tokens, by chain ID and denom.
+func (p *Prices) Snapshot() []Price {
+	if p == nil {
+		return nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	prices := make([]Price, 0, len(p.prices))
+	for _, price := range p.prices {
+		prices = append(prices, price)
+	}
+	sort.Slice(prices, func(i, k int) bool {
+		if prices[i].ChainID != prices[k].ChainID {
+			return prices[i].ChainID < prices[k].ChainID
+		}
+		return prices[i].Denom < prices[k].Denom
+	})
+	return prices
+}
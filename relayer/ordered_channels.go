@@ -0,0 +1,232 @@
+package relayer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// OrderedChannelEnd is the progress last observed of an end of an ordered channel receiving the packets sent
+// on its counterparty: its next sequence to receive and the packets committed on the counterparty waiting for it.
+type OrderedChannelEnd struct {
+	Path                string `json:"path"`
+	ChainID             string `json:"chain_id"`
+	ChannelID           string `json:"channel_id"`
+	PortID              string `json:"port_id"`
+	CounterpartyChainID string `json:"counterparty_chain_id"`
+
+	NextSequenceRecv uint64 `json:"next_sequence_recv"`
+	Pending          int    `json:"pending"`
+
+	// Since is when the next sequence to receive last advanced, or packets started waiting for it.
+	Since time.Time `json:"since"`
+
+	// Stalled is set while packets waited for longer than the stall threshold without the next sequence
+	// to receive advancing. BlockingSequence is the packet the channel waits for, and BlockingCommitted whether
+	// its commitment is found on the counterparty: if not, the packet can no longer be relayed.
+	Stalled           bool   `json:"stalled"`
+	BlockingSequence  uint64 `json:"blocking_sequence,omitempty"`
+	BlockingCommitted bool   `json:"blocking_committed,omitempty"`
+
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// orderedChannelMonitor periodically tracks the next sequence to receive of both ends of the ordered channels
+// of every path, and alerts when it stops advancing while packets are waiting, a stall which blocks every later
+// packet of the channel.
+type orderedChannelMonitor struct {
+	log        *zap.Logger
+	metrics    *processor.PrometheusMetrics
+	stallAfter time.Duration
+
+	mu   sync.Mutex
+	ends map[string]*OrderedChannelEnd
+}
+
+func newOrderedChannelMonitor(log *zap.Logger, metrics *processor.PrometheusMetrics, stallAfter time.Duration) *orderedChannelMonitor {
+	return &orderedChannelMonitor{
+		log:        log,
+		metrics:    metrics,
+		stallAfter: stallAfter,
+		ends:       make(map[string]*OrderedChannelEnd),
+	}
+}
+
+func (m *orderedChannelMonitor) check(ctx context.Context, r *pathRunner) {
+	channels, err := r.openChannels(ctx)
+	if err != nil {
+		m.log.Warn(
+			"Failed to query channels to check ordered channels",
+			zap.String("path", r.name),
+			zap.Error(err),
+		)
+		return
+	}
+	for _, c := range channels {
+		if c.channel.Ordering != chantypes.ORDERED {
+			continue
+		}
+		srch, dsth, err := QueryLatestHeights(ctx, r.src, r.dst)
+		if err != nil {
+			m.log.Warn(
+				"Failed to query latest heights to check ordered channel",
+				zap.String("path", r.name),
+				zap.String("channel_id", c.channel.ChannelId),
+				zap.Error(err),
+			)
+			return
+		}
+		m.checkEnd(ctx, r.name, r.src, c.channel.ChannelId, c.channel.PortId, srch, r.dst, c.channel.Counterparty.ChannelId, c.channel.Counterparty.PortId)
+		m.checkEnd(ctx, r.name, r.dst, c.channel.Counterparty.ChannelId, c.channel.Counterparty.PortId, dsth, r.src, c.channel.ChannelId, c.channel.PortId)
+	}
+}
+
+// checkEnd records the progress of the channel end of dst receiving the packets committed on the channel end of src at srch.
+func (m *orderedChannelMonitor) checkEnd(ctx context.Context, pathName string,
+	src *Chain, srcChannelID, srcPortID string, srch int64,
+	dst *Chain, dstChannelID, dstPortID string,
+) {
+	fields := []zap.Field{
+		zap.String("path", pathName),
+		zap.String("chain_id", dst.ChainID()),
+		zap.String("channel_id", dstChannelID),
+		zap.String("port_id", dstPortID),
+	}
+	// we are using height 0 because we want to check vs the latest height
+	next, err := dst.ChainProvider.QueryNextSeqRecv(ctx, 0, dstChannelID, dstPortID)
+	if err != nil {
+		m.log.Warn("Failed to query next sequence to receive of ordered channel", append(fields, zap.Error(err))...)
+		return
+	}
+	// Channels without packets committed are expected, so unlike unrelayedSequences the listing is not retried
+	// when empty: a failed check is retried at the next interval.
+	unreceived, err := sequencePipeline{
+		chunkSize:   AckChunkSize,
+		concurrency: UnrelayedQueryConcurrency,
+		list: func(ctx context.Context, emit func([]uint64) error) error {
+			return listCommitments(ctx, src, srcChannelID, srcPortID, srch, emit)
+		},
+		check: func(ctx context.Context, seqs []uint64) ([]uint64, error) {
+			return dst.ChainProvider.QueryUnreceivedPackets(ctx, 0, dstChannelID, dstPortID, seqs)
+		},
+	}.run(ctx)
+	if err != nil {
+		m.log.Warn("Failed to query packets waiting on ordered channel", append(fields, zap.Error(err))...)
+		return
+	}
+
+	m.record(OrderedChannelEnd{
+		Path:                pathName,
+		ChainID:             dst.ChainID(),
+		ChannelID:           dstChannelID,
+		PortID:              dstPortID,
+		CounterpartyChainID: src.ChainID(),
+		NextSequenceRecv:    next.NextSequenceReceive,
+	}, unreceived, time.Now().UTC())
+}
+
+// record updates the progress of a channel end with its next sequence to receive and the sequences of the packets
+// committed on its counterparty that it has not received, alerting once it stalls and logging once it advances again.
+func (m *orderedChannelMonitor) record(end OrderedChannelEnd, unreceived []uint64, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, seq := range unreceived {
+		if seq < end.NextSequenceRecv {
+			// Received meanwhile.
+			continue
+		}
+		end.Pending++
+		if seq == end.NextSequenceRecv {
+			end.BlockingCommitted = true
+		}
+	}
+
+	key := end.Path + "/" + end.ChainID + "/" + end.PortID + "/" + end.ChannelID
+	prev, ok := m.ends[key]
+	switch {
+	case !ok, prev.NextSequenceRecv != end.NextSequenceRecv, prev.Pending == 0:
+		end.Since = now
+	default:
+		end.Since = prev.Since
+	}
+	end.Stalled = end.Pending > 0 && now.Sub(end.Since) >= m.stallAfter
+	if end.Stalled {
+		end.BlockingSequence = end.NextSequenceRecv
+	} else {
+		end.BlockingCommitted = false
+	}
+	end.CheckedAt = now
+
+	fields := []zap.Field{
+		zap.String("path", end.Path),
+		zap.String("chain_id", end.ChainID),
+		zap.String("channel_id", end.ChannelID),
+		zap.String("port_id", end.PortID),
+		zap.String("counterparty_chain_id", end.CounterpartyChainID),
+		zap.Uint64("next_sequence_recv", end.NextSequenceRecv),
+		zap.Int("pending", end.Pending),
+	}
+	wasStalled := ok && prev.Stalled
+	switch {
+	case end.Stalled && !wasStalled:
+		m.log.Warn(
+			"Ordered channel is stalled",
+			append(fields,
+				zap.Uint64("blocking_sequence", end.BlockingSequence),
+				zap.Bool("blocking_committed", end.BlockingCommitted),
+				zap.Duration("stalled_for", now.Sub(end.Since)),
+			)...,
+		)
+	case !end.Stalled && wasStalled:
+		m.log.Info("Ordered channel is no longer stalled", fields...)
+	}
+	m.metrics.SetOrderedChannel(end.Path, end.ChainID, end.ChannelID, end.PortID, end.NextSequenceRecv, end.Stalled)
+
+	m.ends[key] = &end
+}
+
+// snapshot returns the progress last observed of every ordered channel end, by path, chain, port and channel ID.
+func (m *orderedChannelMonitor) snapshot() []OrderedChannelEnd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ends := make([]OrderedChannelEnd, 0, len(m.ends))
+	for _, end := range m.ends {
+		ends = append(ends, *end)
+	}
+	sort.Slice(ends, func(i, k int) bool {
+		a, b := ends[i], ends[k]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		if a.PortID != b.PortID {
+			return a.PortID < b.PortID
+		}
+		return a.ChannelID < b.ChannelID
+	})
+	return ends
+}
+
+// startOrderedChannelMonitor starts tracking the ordered channels of every path at the given interval,
+// reporting those whose next sequence to receive did not advance for stallAfter while packets were waiting.
+func (s *supervisor) startOrderedChannelMonitor(ctx context.Context, interval, stallAfter time.Duration) *orderedChannelMonitor {
+	m := newOrderedChannelMonitor(s.log.With(zap.String("sys", "orderedchannels")), s.metrics, stallAfter)
+	runners := make([]*pathRunner, 0, len(s.names))
+	for _, name := range s.names {
+		runners = append(runners, s.runners[name])
+	}
+	s.maintenance.schedule(ctx, TaskOrderedChannels, interval, func(ctx context.Context) {
+		for _, r := range runners {
+			m.check(ctx, r)
+		}
+	})
+	return m
+}
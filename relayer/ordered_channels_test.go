@@ -0,0 +1,51 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestOrderedChannelStalls(t *testing.T) {
+	m := newOrderedChannelMonitor(zaptest.NewLogger(t), processor.NewPrometheusMetrics(), 10*time.Minute)
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := func(next uint64) OrderedChannelEnd {
+		return OrderedChannelEnd{Path: "demo-path", ChainID: "chain-b", ChannelID: "channel-1", PortID: "icahost", CounterpartyChainID: "chain-a", NextSequenceRecv: next}
+	}
+
+	// Nothing waits on the channel, however long its next sequence to receive stays put.
+	m.record(end(4), nil, start)
+	m.record(end(4), []uint64{3}, start.Add(20*time.Minute))
+	ends := m.snapshot()
+	require.Len(t, ends, 1)
+	require.Zero(t, ends[0].Pending, "packets below the next sequence to receive were received meanwhile")
+	require.False(t, ends[0].Stalled)
+
+	// Packets start waiting, and keep accumulating without the channel advancing.
+	m.record(end(4), []uint64{4, 5}, start.Add(21*time.Minute))
+	require.Equal(t, start.Add(21*time.Minute), m.snapshot()[0].Since)
+	m.record(end(4), []uint64{4, 5, 6}, start.Add(30*time.Minute))
+	require.False(t, m.snapshot()[0].Stalled)
+	m.record(end(4), []uint64{4, 5, 6, 7}, start.Add(31*time.Minute))
+	ends = m.snapshot()
+	require.True(t, ends[0].Stalled)
+	require.Equal(t, 4, ends[0].Pending)
+	require.Equal(t, uint64(4), ends[0].BlockingSequence)
+	require.True(t, ends[0].BlockingCommitted)
+
+	// A stall on a packet whose commitment is gone is reported as such.
+	m.record(end(4), []uint64{5, 6, 7}, start.Add(32*time.Minute))
+	ends = m.snapshot()
+	require.True(t, ends[0].Stalled)
+	require.False(t, ends[0].BlockingCommitted)
+
+	// The stall clears once the channel advances.
+	m.record(end(5), []uint64{5, 6, 7}, start.Add(33*time.Minute))
+	ends = m.snapshot()
+	require.False(t, ends[0].Stalled)
+	require.Zero(t, ends[0].BlockingSequence)
+	require.Equal(t, start.Add(33*time.Minute), ends[0].Since)
+}
@@ -0,0 +1,157 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// PacketFilter skips the ICS-20 transfers of a path that are not worth the gas of relaying them,
+// e.g. dust transfers. Packets of other applications are always relayed.
+type PacketFilter struct {
+	// MinAmounts is the minimum amount of a transfer relayed, by denom, e.g. {urax: "1000000"}.
+	// Denoms are matched as they appear in the packet data, i.e. prefixed with the trace of the channels
+	// they were transferred over unless native to the sending chain, e.g. transfer/channel-0/uatom.
+	MinAmounts map[string]string `yaml:"min-amounts,omitempty" json:"min-amounts,omitempty"`
+
+	// DenyDenoms lists the denoms whose transfers are never relayed, matched as MinAmounts.
+	DenyDenoms []string `yaml:"deny-denoms,omitempty" json:"deny-denoms,omitempty"`
+
+	// MinUSD is the minimum value in USD of a transfer relayed, according to the price oracle of the relayer.
+	// Denoms are priced as they appear in the packet data, on the chain the transfer was sent from,
+	// and the transfers of denoms without a known price are relayed.
+	MinUSD float64 `yaml:"min-usd,omitempty" json:"min-usd,omitempty"`
+
+	// MemoPolicies handle the packets with a user-supplied memo, such as oversized memos or memos with forwarding
+	// instructions, the first policy matching a memo applying. Packets whose memo matches no policy are relayed.
+	MemoPolicies []MemoPolicy `yaml:"memo-policies,omitempty" json:"memo-policies,omitempty"`
+}
+
+// MemoPolicy passes, truncates in the feed or blocks the packets sent over a channel with a memo matching the policy.
+type MemoPolicy struct {
+	// Channel follows the syntax of ChannelFilter entries, matching the channels of the src chain of the path,
+	// the packets of both directions of a channel being handled. The policy applies to every channel if empty.
+	Channel string `yaml:"channel,omitempty" json:"channel,omitempty"`
+
+	// MaxBytes restricts the policy to memos longer than MaxBytes, every memo matching if 0.
+	// The memos of policies truncating them are truncated to MaxBytes.
+	MaxBytes int `yaml:"max-bytes,omitempty" json:"max-bytes,omitempty"`
+
+	// Forwarding restricts the policy to memos with instructions for the middlewares of the receiving chain,
+	// with a forward key for the packet-forward-middleware or a wasm key for ibc-hooks.
+	Forwarding bool `yaml:"forwarding,omitempty" json:"forwarding,omitempty"`
+
+	// Action is pass to relay the packet, truncate to relay it with its memo truncated in the packet data
+	// published to the feed, or block to skip it.
+	Action string `yaml:"action" json:"action"`
+}
+
+// memoPolicies returns the processor memo policies of policies, or an error if a policy is invalid.
+func memoPolicies(policies []MemoPolicy) ([]processor.MemoPolicy, error) {
+	res := make([]processor.MemoPolicy, 0, len(policies))
+	for _, p := range policies {
+		switch p.Action {
+		case processor.MemoPass, processor.MemoBlock:
+		case processor.MemoTruncate:
+			if p.MaxBytes == 0 {
+				return nil, fmt.Errorf("memo policy of channel %q truncates memos without max-bytes", p.Channel)
+			}
+		default:
+			return nil, fmt.Errorf("invalid action %q of memo policy of channel %q, expected %s, %s or %s",
+				p.Action, p.Channel, processor.MemoPass, processor.MemoTruncate, processor.MemoBlock)
+		}
+		if p.MaxBytes < 0 {
+			return nil, fmt.Errorf("invalid max-bytes %d of memo policy of channel %q", p.MaxBytes, p.Channel)
+		}
+		portID, channelID := parseChannelFilterEntry(p.Channel)
+		res = append(res, processor.MemoPolicy{
+			PortID:     portID,
+			ChannelID:  channelID,
+			MaxBytes:   p.MaxBytes,
+			Forwarding: p.Forwarding,
+			Action:     p.Action,
+		})
+	}
+	return res, nil
+}
+
+// filter returns the processor filter of f for the path whose src chain is srcChainID,
+// or an error if a minimum amount or a memo policy is invalid.
+func (f *PacketFilter) filter(srcChainID string) (*processor.PacketFilter, error) {
+	if f == nil {
+		return nil, nil
+	}
+	minAmounts := make(map[string]*big.Int, len(f.MinAmounts))
+	for denom, amount := range f.MinAmounts {
+		a, ok := new(big.Int).SetString(amount, 10)
+		if !ok || a.Sign() < 0 {
+			return nil, fmt.Errorf("invalid minimum amount %q of denom %s", amount, denom)
+		}
+		minAmounts[denom] = a
+	}
+	if f.MinUSD < 0 {
+		return nil, fmt.Errorf("invalid minimum USD value %v", f.MinUSD)
+	}
+	policies, err := memoPolicies(f.MemoPolicies)
+	if err != nil {
+		return nil, err
+	}
+	pf := processor.NewPacketFilter(minAmounts, f.DenyDenoms)
+	pf.SetMinUSD(f.MinUSD)
+	pf.SetMemoPolicies(srcChainID, policies)
+	return pf, nil
+}
+
+type sendPacketQuerier interface {
+	QuerySendPacket(ctx context.Context, srcChanID, srcPortID string, seq uint64) (provider.PacketInfo, error)
+}
+
+type packetFilterKey struct{}
+
+// withPacketFilter skips the packets skipped by f in the messages relayed with ctx, see skipFilteredPacket.
+func withPacketFilter(ctx context.Context, f *processor.PacketFilter) context.Context {
+	if f == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, packetFilterKey{}, f)
+}
+
+// skipFilteredPacket returns true if the packet sent on the channel of src with seq is skipped
+// by the packet filter attached to ctx. Packets are relayed when they cannot be looked up.
+func skipFilteredPacket(ctx context.Context, src *Chain, channelID, portID string, seq uint64) bool {
+	f, ok := ctx.Value(packetFilterKey{}).(*processor.PacketFilter)
+	if !ok {
+		return false
+	}
+	q, ok := src.ChainProvider.(sendPacketQuerier)
+	if !ok {
+		return false
+	}
+	packet, err := q.QuerySendPacket(ctx, channelID, portID, seq)
+	if err != nil {
+		return false
+	}
+	k := processor.ChannelKey{
+		ChannelID:             channelID,
+		PortID:                portID,
+		CounterpartyChannelID: packet.DestChannel,
+		CounterpartyPortID:    packet.DestPort,
+	}
+	reason := f.Skip(src.ChainID(), k, packet.Data)
+	if reason == "" {
+		return false
+	}
+	src.log.Debug(
+		"Skipping packet filtered out",
+		zap.String("chain_id", src.ChainID()),
+		zap.String("channel_id", channelID),
+		zap.String("port_id", portID),
+		zap.Uint64("sequence", seq),
+		zap.String("reason", reason),
+	)
+	return true
+}
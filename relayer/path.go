@@ -3,9 +3,12 @@ package relayer
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
 	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v3"
 )
@@ -93,13 +96,100 @@ type Path struct {
 	Src    *PathEnd      `yaml:"src" json:"src"`
 	Dst    *PathEnd      `yaml:"dst" json:"dst"`
 	Filter ChannelFilter `yaml:"src-channel-filter" json:"src-channel-filter"`
+
+	// Connections are further connections of the src chain relayed by the path, between the same clients as the
+	// connection of its src path end, each with its own channel filter, e.g. a connection opened for another application.
+	// AllConnections relays every open connection between the clients of the path, those opened after the relayer
+	// started being picked up by channel discovery. Only the legacy processor relays a single connection otherwise,
+	// the events processor relaying every connection of the clients of the path.
+	Connections    []PathConnection `yaml:"connections,omitempty" json:"connections,omitempty"`
+	AllConnections bool             `yaml:"all-connections,omitempty" json:"all-connections,omitempty"`
+
+	// RegisterCounterpartyPayee registers the relayer as counterparty payee on both ends
+	// of the fee-enabled (ICS-29) channels of the path when the relayer starts.
+	RegisterCounterpartyPayee bool `yaml:"register-counterparty-payee,omitempty" json:"register-counterparty-payee,omitempty"`
+
+	// MaxConcurrentChannels is the maximum number of channels of the path relayed at once by the legacy processor,
+	// overriding the relayer-wide limit. Channels beyond it take turns.
+	MaxConcurrentChannels int `yaml:"max-concurrent-channels,omitempty" json:"max-concurrent-channels,omitempty"`
+
+	// Monitor lists channels of the path which are watched but never relayed.
+	Monitor *ChannelMonitor `yaml:"monitor,omitempty" json:"monitor,omitempty"`
+
+	// Trusted paths relay the packets sent on their rollapps at soft confirmation when gating on finality,
+	// without waiting for the settlement layer to finalize them, tracking them until it does.
+	// Only the events processor relays preconfirmations, the legacy processor gates trusted paths as any other.
+	Trusted bool `yaml:"trusted,omitempty" json:"trusted,omitempty"`
+
+	// PacketFilter skips the ICS-20 transfers of both directions of the path not worth relaying, e.g. dust transfers.
+	PacketFilter *PacketFilter `yaml:"packet-filter,omitempty" json:"packet-filter,omitempty"`
+
+	// RateLimits caps the amounts transferred over the channels of the path within a window, see RateLimit.
+	RateLimits []RateLimit `yaml:"rate-limits,omitempty" json:"rate-limits,omitempty"`
+
+	// AckAggregation holds back the acknowledgements of the path to relay them together, see AckAggregation.
+	AckAggregation *AckAggregation `yaml:"ack-aggregation,omitempty" json:"ack-aggregation,omitempty"`
+
+	// Paused stops relaying the packets and acknowledgements of the path, e.g. during an upgrade of either chain,
+	// while its clients are still kept updated. It is set with rly paths pause and toggled at runtime through the admin API.
+	Paused bool `yaml:"paused,omitempty" json:"paused,omitempty"`
+
+	// Priority expedites the transactions of the path, e.g. critical rollapp withdrawals during congestion windows,
+	// multiplying their fees, or their priority fields where the chain supports them, by PriorityFeeMultiplier.
+	// It is toggled at runtime through the admin API.
+	Priority bool `yaml:"priority,omitempty" json:"priority,omitempty"`
+
+	// PriorityFeeMultiplier multiplies the fees of the transactions of the path while its priority is enabled,
+	// provider.DefaultPriorityFeeMultiplier if zero.
+	PriorityFeeMultiplier float64 `yaml:"priority-fee-multiplier,omitempty" json:"priority-fee-multiplier,omitempty"`
+
+	// Log configures the logs of the path independently of the other paths, see PathLogConfig.
+	Log *PathLogConfig `yaml:"log,omitempty" json:"log,omitempty"`
+
+	// DependsOn names the paths which must be ready before this path is started, e.g. the hub path whose client
+	// a rollapp channel handshake relies on. A path is ready once its clients exist and its connection is open.
+	DependsOn []string `yaml:"depends-on,omitempty" json:"depends-on,omitempty"`
+}
+
+// ChannelMonitor configures "monitor only" channels: channels handled by another operator, for which the relayer
+// serves as a watchdog, tracking their backlog of pending packets and acknowledgements and how long it has been
+// pending, and alerting once either exceeds its threshold, without ever relaying them.
+// Monitored channels are excluded from relaying whatever the channel filter of the path.
+type ChannelMonitor struct {
+	// ChannelList entries follow the syntax of ChannelFilter entries.
+	ChannelList []string `yaml:"channel-list" json:"channel-list"`
+
+	// MaxPending is the number of pending packets and acknowledgements on a channel end above which to alert,
+	// 0 to never alert on the backlog.
+	MaxPending int `yaml:"max-pending,omitempty" json:"max-pending,omitempty"`
+
+	// MaxPendingAge is how long a packet or acknowledgement may stay pending before alerting, 0 to never alert on it.
+	MaxPendingAge time.Duration `yaml:"max-pending-age,omitempty" json:"max-pending-age,omitempty"`
+
+	// CheckInterval is how often the channels are checked, every minute if unset.
+	CheckInterval time.Duration `yaml:"check-interval,omitempty" json:"check-interval,omitempty"`
+}
+
+// Matches returns true if the channel identified by portID and channelID is monitored only.
+func (m *ChannelMonitor) Matches(portID, channelID string) bool {
+	if m == nil {
+		return false
+	}
+	return (&ChannelFilter{ChannelList: m.ChannelList}).Matches(portID, channelID)
 }
 
 // ChannelFilter provides the means for either creating an allowlist or a denylist of channels on the src chain
 // which will be used to narrow down the list of channels a user wants to relay on.
+//
+// Entries of the ChannelList are either a channel ID, matching the channel on any port, or "port:channel".
+// Both IDs can be patterns, a glob such as "transfer:channel-*" or "icahost:*", or a regular expression
+// delimited by slashes such as "transfer:/^channel-(0|12)$/".
 type ChannelFilter struct {
 	Rule        string   `yaml:"rule" json:"rule"`
 	ChannelList []string `yaml:"channel-list" json:"channel-list"`
+
+	// monitor holds the monitor-only channels of the path, which are filtered out whatever the rule.
+	monitor *ChannelMonitor
 }
 
 type IBCdata struct {
@@ -134,15 +224,68 @@ type IBCdata struct {
 	} `json:"channels"`
 }
 
-// ValidateChannelFilterRule verifies that the configured ChannelFilter rule is set to an appropriate value.
+// ValidateChannelFilterRule verifies that the configured ChannelFilter rule is set to an appropriate value,
+// and that its channel list, as well as the monitor-only channel list and the filters of its further connections,
+// only hold valid patterns.
 func (p *Path) ValidateChannelFilterRule() error {
 	if p.Filter.Rule != allowList && p.Filter.Rule != denyList && p.Filter.Rule != "" {
 		return fmt.Errorf("%s is not a valid channel filter rule, please "+
 			"ensure your channel filter rule is `%s` or '%s'", p.Filter.Rule, allowList, denyList)
 	}
+	if err := validateChannelList("channel filter", p.Filter.ChannelList); err != nil {
+		return err
+	}
+	if p.Monitor != nil {
+		if err := validateChannelList("monitor", p.Monitor.ChannelList); err != nil {
+			return err
+		}
+		if p.Monitor.MaxPending < 0 || p.Monitor.MaxPendingAge < 0 || p.Monitor.CheckInterval < 0 {
+			return fmt.Errorf("monitor thresholds and check interval must not be negative")
+		}
+	}
+	return p.validateConnections()
+}
+
+func validateChannelList(kind string, list []string) error {
+	for _, entry := range list {
+		portID, channelID := parseChannelFilterEntry(entry)
+		if channelID == "" {
+			return fmt.Errorf("%s entry %q has no channel", kind, entry)
+		}
+		for _, pattern := range []string{portID, channelID} {
+			if err := processor.ValidateChannelPattern(pattern); err != nil {
+				return fmt.Errorf("invalid %s entry %q: %w", kind, entry, err)
+			}
+		}
+	}
 	return nil
 }
 
+// parseChannelFilterEntry splits a channel list entry into its port and channel ID patterns,
+// the port being empty if the entry only has a channel.
+func parseChannelFilterEntry(entry string) (portID, channelID string) {
+	// A regular expression alone only applies to the channel, even if it contains a colon.
+	isRegex := len(entry) >= 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/")
+	if i := strings.Index(entry, ":"); i >= 0 && !isRegex {
+		return entry[:i], entry[i+1:]
+	}
+	return "", entry
+}
+
+// Matches returns true if the channel identified by portID and channelID matches an entry of the channel list.
+func (cf *ChannelFilter) Matches(portID, channelID string) bool {
+	for _, entry := range cf.ChannelList {
+		entryPort, entryChannel := parseChannelFilterEntry(entry)
+		if !processor.MatchChannelPattern(entryChannel, channelID) {
+			continue
+		}
+		if entryPort == "" || processor.MatchChannelPattern(entryPort, portID) {
+			return true
+		}
+	}
+	return false
+}
+
 // InChannelList returns true if the channelID argument is in the ChannelFilter's ChannelList or false otherwise.
 func (cf *ChannelFilter) InChannelList(channelID string) bool {
 	for _, channel := range cf.ChannelList {
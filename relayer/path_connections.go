@@ -0,0 +1,151 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	"github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+)
+
+// PathConnection is a connection of the src chain relayed by a path besides the connection of its src path end,
+// between the same clients.
+type PathConnection struct {
+	ID string `yaml:"id" json:"id"`
+
+	// Filter narrows down the channels of the connection relayed, the channel filter of the path if unset.
+	Filter *ChannelFilter `yaml:"src-channel-filter,omitempty" json:"src-channel-filter,omitempty"`
+}
+
+// validateConnections checks the further connections of the path.
+func (p *Path) validateConnections() error {
+	seen := make(map[string]bool, len(p.Connections))
+	for _, c := range p.Connections {
+		switch {
+		case c.ID == "":
+			return fmt.Errorf("connection without id")
+		case p.Src != nil && c.ID == p.Src.ConnectionID:
+			return fmt.Errorf("connection %s is the connection of the src path end", c.ID)
+		case seen[c.ID]:
+			return fmt.Errorf("connection %s is configured more than once", c.ID)
+		}
+		seen[c.ID] = true
+		if c.Filter == nil {
+			continue
+		}
+		if c.Filter.Rule != allowList && c.Filter.Rule != denyList && c.Filter.Rule != "" {
+			return fmt.Errorf("connection %s: %s is not a valid channel filter rule", c.ID, c.Filter.Rule)
+		}
+		if err := validateChannelList("channel filter", c.Filter.ChannelList); err != nil {
+			return fmt.Errorf("connection %s: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// relayedConnection is a connection relayed by a path, with its counterparty on dst and its channel filter.
+type relayedConnection struct {
+	srcID, dstID string
+	filter       ChannelFilter
+}
+
+// pathConnections are the connections of the src chain relayed by a path: the connection of its src path end,
+// its further connections and, with all set, every other open connection between the clients of the path.
+type pathConnections struct {
+	src, dst *Chain
+	filter   ChannelFilter
+	further  []PathConnection
+	all      bool
+}
+
+// connections returns the connections relayed by the path of r.
+func (r *pathRunner) connections() pathConnections {
+	return pathConnections{src: r.src, dst: r.dst, filter: r.filter, further: r.furtherConnections, all: r.allConnections}
+}
+
+// single returns whether only the connection of the src path end is relayed, sparing the queries of the connections.
+func (pc pathConnections) single() bool {
+	return len(pc.further) == 0 && !pc.all
+}
+
+// resolve queries the connections relayed, checking that they are between the clients of the path.
+func (pc pathConnections) resolve(ctx context.Context) ([]relayedConnection, error) {
+	conns := []relayedConnection{{srcID: pc.src.ConnectionID(), dstID: pc.dst.ConnectionID(), filter: pc.filter}}
+	if pc.single() {
+		return conns, nil
+	}
+	h, err := pc.src.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{pc.src.ConnectionID(): true}
+	for _, c := range pc.further {
+		res, err := pc.src.ChainProvider.QueryConnection(ctx, h, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query connection %s on %s: %w", c.ID, pc.src.ChainID(), err)
+		}
+		conn := &conntypes.IdentifiedConnection{
+			Id:           c.ID,
+			ClientId:     res.Connection.ClientId,
+			State:        res.Connection.State,
+			Counterparty: res.Connection.Counterparty,
+		}
+		if err := pc.check(conn); err != nil {
+			return nil, err
+		}
+		filter := pc.filter
+		if c.Filter != nil {
+			filter = *c.Filter
+			filter.monitor = pc.filter.monitor
+		}
+		conns = append(conns, relayedConnection{srcID: c.ID, dstID: conn.Counterparty.ConnectionId, filter: filter})
+		seen[c.ID] = true
+	}
+
+	if pc.all {
+		clientConns, err := pc.src.ChainProvider.QueryConnectionsUsingClient(ctx, h, pc.src.ClientID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to query connections of client %s on %s: %w", pc.src.ClientID(), pc.src.ChainID(), err)
+		}
+		for _, conn := range clientConns {
+			if seen[conn.Id] || conn.State != conntypes.OPEN || conn.Counterparty.ClientId != pc.dst.ClientID() {
+				continue
+			}
+			conns = append(conns, relayedConnection{srcID: conn.Id, dstID: conn.Counterparty.ConnectionId, filter: pc.filter})
+			seen[conn.Id] = true
+		}
+	}
+	return conns, nil
+}
+
+// check returns an error unless conn is an open connection between the clients of the path.
+func (pc pathConnections) check(conn *conntypes.IdentifiedConnection) error {
+	if conn.ClientId != pc.src.ClientID() || conn.Counterparty.ClientId != pc.dst.ClientID() {
+		return fmt.Errorf("connection %s on %s is between clients %s and %s, not the clients %s and %s of the path",
+			conn.Id, pc.src.ChainID(), conn.ClientId, conn.Counterparty.ClientId, pc.src.ClientID(), pc.dst.ClientID())
+	}
+	if conn.State != conntypes.OPEN {
+		return fmt.Errorf("connection %s on %s is not open", conn.Id, pc.src.ChainID())
+	}
+	return nil
+}
+
+// channels queries the channels of the connections relayed, each narrowed down by the filter of its connection,
+// and returns them along with the connections.
+func (pc pathConnections) channels(ctx context.Context) ([]*types.IdentifiedChannel, []relayedConnection, error) {
+	conns, err := pc.resolve(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var channels []*types.IdentifiedChannel
+	for _, conn := range conns {
+		connChannels, err := queryChannelsOnConnection(ctx, pc.src.withConnectionID(conn.srcID))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error querying all channels on chain{%s}@connection{%s}: %w",
+				pc.src.ChainID(), conn.srcID, err)
+		}
+		channels = append(channels, applyChannelFilterRule(conn.filter, connChannels)...)
+	}
+	return channels, conns, nil
+}
@@ -0,0 +1,150 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+)
+
+// clientConnectionsProvider serves the connections of the clients of a chain and their channels.
+type clientConnectionsProvider struct {
+	provider.ChainProvider
+	connections []*conntypes.IdentifiedConnection
+	channels    map[string][]*chantypes.IdentifiedChannel
+}
+
+func (p *clientConnectionsProvider) ChainId() string { return "hub" }
+
+func (p *clientConnectionsProvider) QueryLatestHeight(context.Context) (int64, error) { return 10, nil }
+
+func (p *clientConnectionsProvider) QueryConnection(_ context.Context, _ int64, connectionID string) (*conntypes.QueryConnectionResponse, error) {
+	for _, c := range p.connections {
+		if c.Id == connectionID {
+			return &conntypes.QueryConnectionResponse{Connection: &conntypes.ConnectionEnd{
+				ClientId:     c.ClientId,
+				State:        c.State,
+				Counterparty: c.Counterparty,
+			}}, nil
+		}
+	}
+	return nil, errors.New("connection not found")
+}
+
+func (p *clientConnectionsProvider) QueryConnectionsUsingClient(_ context.Context, _ int64, clientID string) ([]*conntypes.IdentifiedConnection, error) {
+	var conns []*conntypes.IdentifiedConnection
+	for _, c := range p.connections {
+		if c.ClientId == clientID {
+			conns = append(conns, c)
+		}
+	}
+	return conns, nil
+}
+
+func (p *clientConnectionsProvider) QueryConnectionChannels(_ context.Context, _ int64, connectionID string) ([]*chantypes.IdentifiedChannel, error) {
+	return p.channels[connectionID], nil
+}
+
+func testConnection(id, clientID, counterpartyClientID, counterpartyID string, state conntypes.State) *conntypes.IdentifiedConnection {
+	return &conntypes.IdentifiedConnection{
+		Id:           id,
+		ClientId:     clientID,
+		State:        state,
+		Counterparty: conntypes.Counterparty{ClientId: counterpartyClientID, ConnectionId: counterpartyID},
+	}
+}
+
+func testConnectionChannel(portID, channelID, connectionID string) *chantypes.IdentifiedChannel {
+	return &chantypes.IdentifiedChannel{PortId: portID, ChannelId: channelID, State: chantypes.OPEN, ConnectionHops: []string{connectionID}}
+}
+
+func TestPathValidateConnections(t *testing.T) {
+	p := &Path{Src: &PathEnd{ConnectionID: "connection-0"}, Connections: []PathConnection{
+		{ID: "connection-1"},
+		{ID: "connection-2", Filter: &ChannelFilter{Rule: allowList, ChannelList: []string{"icahost:*"}}},
+	}}
+	require.NoError(t, p.ValidateChannelFilterRule())
+
+	for _, tc := range []struct {
+		conn PathConnection
+		err  string
+	}{
+		{PathConnection{}, "connection without id"},
+		{PathConnection{ID: "connection-0"}, "is the connection of the src path end"},
+		{PathConnection{ID: "connection-1"}, "configured more than once"},
+		{PathConnection{ID: "connection-3", Filter: &ChannelFilter{Rule: "blocklist"}}, "not a valid channel filter rule"},
+		{PathConnection{ID: "connection-3", Filter: &ChannelFilter{ChannelList: []string{"transfer:/(/"}}}, "invalid channel filter entry"},
+	} {
+		invalid := *p
+		invalid.Connections = append([]PathConnection{{ID: "connection-1"}}, tc.conn)
+		require.ErrorContains(t, invalid.ValidateChannelFilterRule(), tc.err)
+	}
+}
+
+func TestPathConnectionsChannels(t *testing.T) {
+	ctx := context.Background()
+	hub := &clientConnectionsProvider{
+		connections: []*conntypes.IdentifiedConnection{
+			testConnection("connection-0", "07-tendermint-0", "07-tendermint-5", "connection-7", conntypes.OPEN),
+			testConnection("connection-1", "07-tendermint-0", "07-tendermint-5", "connection-8", conntypes.OPEN),
+			testConnection("connection-2", "07-tendermint-0", "07-tendermint-5", "connection-9", conntypes.OPEN),
+			testConnection("connection-3", "07-tendermint-0", "07-tendermint-5", "", conntypes.INIT),
+			testConnection("connection-4", "07-tendermint-0", "07-tendermint-6", "connection-2", conntypes.OPEN),
+		},
+		channels: map[string][]*chantypes.IdentifiedChannel{
+			"connection-0": {testConnectionChannel("transfer", "channel-0", "connection-0")},
+			"connection-1": {
+				testConnectionChannel("transfer", "channel-1", "connection-1"),
+				testConnectionChannel("icahost", "channel-2", "connection-1"),
+			},
+			"connection-2": {testConnectionChannel("transfer", "channel-3", "connection-2")},
+		},
+	}
+	pc := pathConnections{
+		src:    &Chain{ChainProvider: hub, PathEnd: &PathEnd{ChainID: "hub", ClientID: "07-tendermint-0", ConnectionID: "connection-0"}},
+		dst:    &Chain{PathEnd: &PathEnd{ChainID: "rollapp", ClientID: "07-tendermint-5", ConnectionID: "connection-7"}},
+		filter: ChannelFilter{Rule: denyList, ChannelList: []string{"channel-3"}},
+	}
+
+	// Only the connection of the path end is relayed by default.
+	channels, conns, err := pc.channels(ctx)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	require.Equal(t, []relayedConnection{{srcID: "connection-0", dstID: "connection-7", filter: pc.filter}}, conns)
+
+	// Further connections apply their own filter.
+	pc.further = []PathConnection{{ID: "connection-1", Filter: &ChannelFilter{Rule: allowList, ChannelList: []string{"icahost:*"}}}}
+	channels, conns, err = pc.channels(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"channel-0", "channel-2"}, channelIDs(channels))
+	require.Len(t, conns, 2)
+	require.Equal(t, "connection-8", conns[1].dstID)
+
+	// All connections adds the other open connections between the clients of the path, with the filter of the path.
+	pc.all = true
+	channels, conns, err = pc.channels(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"channel-0", "channel-2"}, channelIDs(channels))
+	require.Len(t, conns, 3)
+	require.Equal(t, "connection-2", conns[2].srcID)
+
+	// Further connections must be open and between the clients of the path.
+	pc.further = []PathConnection{{ID: "connection-4"}}
+	_, _, err = pc.channels(ctx)
+	require.ErrorContains(t, err, "not the clients 07-tendermint-0 and 07-tendermint-5 of the path")
+	pc.further = []PathConnection{{ID: "connection-3"}}
+	_, _, err = pc.channels(ctx)
+	require.ErrorContains(t, err, "connection connection-3 on hub is not open")
+}
+
+func channelIDs(channels []*chantypes.IdentifiedChannel) []string {
+	ids := make([]string, 0, len(channels))
+	for _, c := range channels {
+		ids = append(ids, c.ChannelId)
+	}
+	return ids
+}
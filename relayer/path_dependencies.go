@@ -0,0 +1,246 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	"go.uber.org/zap"
+)
+
+// dependencyCheckInterval is how often a path waiting for the paths it depends on checks whether they are ready.
+const dependencyCheckInterval = 10 * time.Second
+
+const (
+	pathStartupWaiting = "waiting"
+	pathStartupStarted = "started"
+)
+
+// PathStartup reports the progress of starting a path after the paths it depends on.
+type PathStartup struct {
+	Path      string   `json:"path"`
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// State is "waiting" until the paths the path depends on are ready, then "started".
+	State string `json:"state"`
+
+	// WaitingOn is the path the path is waiting for, and Reason why that path is not ready yet, while waiting.
+	WaitingOn string `json:"waiting_on,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+// ValidateDependencies returns an error if a path depends on a path which is not configured, or on itself
+// through a cycle of dependencies.
+func (p Paths) ValidateDependencies() error {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	deps := make(map[string][]string, len(p))
+	for _, name := range names {
+		for _, dep := range p[name].DependsOn {
+			if _, ok := p[dep]; !ok {
+				return fmt.Errorf("path %s depends on path %s, which is not configured", name, dep)
+			}
+		}
+		deps[name] = p[name].DependsOn
+	}
+	_, err := dependencyOrder(names, deps)
+	return err
+}
+
+// dependencyOrder orders names so that every path comes after the paths it depends on,
+// keeping the given order otherwise. It returns an error if paths depend on each other in a cycle.
+func dependencyOrder(names []string, deps map[string][]string) ([]string, error) {
+	ordered := make([]string, 0, len(names))
+	placed := make(map[string]bool, len(names))
+	for len(ordered) < len(names) {
+		progress := false
+		for _, name := range names {
+			if placed[name] {
+				continue
+			}
+			ready := true
+			for _, dep := range deps[name] {
+				if !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, name)
+				placed[name] = true
+				progress = true
+			}
+		}
+		if !progress {
+			var cycle []string
+			for _, name := range names {
+				if !placed[name] {
+					cycle = append(cycle, name)
+				}
+			}
+			return nil, fmt.Errorf("paths %s depend on each other in a cycle", strings.Join(cycle, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+// PathReady returns why the path between src and dst is not ready for the paths depending on it yet,
+// or nil once the clients of both path ends exist and their connection, if configured, is open.
+func PathReady(ctx context.Context, src, dst *Chain) error {
+	for _, c := range []*Chain{src, dst} {
+		if c.ClientID() == "" {
+			return fmt.Errorf("no client configured on %s", c.ChainID())
+		}
+		h, err := c.ChainProvider.QueryLatestHeight(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to query latest height of %s: %w", c.ChainID(), err)
+		}
+		if _, err := c.ChainProvider.QueryClientState(ctx, h, c.ClientID()); err != nil {
+			return fmt.Errorf("client %s not found on %s: %w", c.ClientID(), c.ChainID(), err)
+		}
+		if c.ConnectionID() == "" {
+			continue
+		}
+		res, err := c.ChainProvider.QueryConnection(ctx, h, c.ConnectionID())
+		if err != nil {
+			return fmt.Errorf("failed to query connection %s on %s: %w", c.ConnectionID(), c.ChainID(), err)
+		}
+		if res.Connection == nil || res.Connection.State != conntypes.OPEN {
+			return fmt.Errorf("connection %s on %s is not open", c.ConnectionID(), c.ChainID())
+		}
+	}
+	return nil
+}
+
+// PathDependenciesReady returns an error if a path the named path depends on is not ready, see PathReady.
+func PathDependenciesReady(ctx context.Context, paths Paths, chains Chains, name string) error {
+	p, err := paths.Get(name)
+	if err != nil {
+		return err
+	}
+	for _, dep := range p.DependsOn {
+		dp, err := paths.Get(dep)
+		if err != nil {
+			return err
+		}
+		src, err := chains.Get(dp.Src.ChainID)
+		if err != nil {
+			return err
+		}
+		dst, err := chains.Get(dp.Dst.ChainID)
+		if err != nil {
+			return err
+		}
+		if err := PathReady(ctx, src.withPathEnd(dp.Src), dst.withPathEnd(dp.Dst)); err != nil {
+			return fmt.Errorf("path %s depends on path %s, which is not ready: %w", name, dep, err)
+		}
+	}
+	return nil
+}
+
+// pendingDependency returns the first path r depends on which is not ready yet, and why,
+// or an empty name once all of them are. A path is only ready once it was started itself.
+func (s *supervisor) pendingDependency(ctx context.Context, r *pathRunner) (string, error) {
+	for _, dep := range r.dependsOn {
+		dr := s.runners[dep]
+		if !s.started(dr) {
+			return dep, fmt.Errorf("path %s has not started yet", dep)
+		}
+		if err := PathReady(ctx, dr.src, dr.dst); err != nil {
+			return dep, err
+		}
+	}
+	return "", nil
+}
+
+// awaitDependencies sends r to ready once the paths it depends on are ready, checking them every dependencyCheckInterval.
+func (s *supervisor) awaitDependencies(ctx context.Context, r *pathRunner, ready chan<- *pathRunner) {
+	for {
+		dep, err := s.pendingDependency(ctx, r)
+		if dep == "" {
+			select {
+			case ready <- r:
+			case <-s.stopped:
+			case <-ctx.Done():
+			}
+			return
+		}
+		s.setWaiting(r, dep, err)
+
+		select {
+		case <-time.After(dependencyCheckInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// setWaiting records that r is waiting for the path dep, logging whenever the path or the reason changes.
+func (s *supervisor) setWaiting(r *pathRunner, dep string, reason error) {
+	s.mu.Lock()
+	changed := r.waitingOn != dep || r.waitReason != reason.Error()
+	r.waitingOn, r.waitReason = dep, reason.Error()
+	s.mu.Unlock()
+
+	if changed {
+		r.log.Info(
+			"Waiting for path dependency",
+			zap.String("path_name", r.name),
+			zap.String("depends_on", dep),
+			zap.String("reason", reason.Error()),
+		)
+	}
+}
+
+// setStarted records that r was started, once the paths it depends on are ready.
+func (s *supervisor) setStarted(r *pathRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r.startedAt = time.Now().UTC()
+	r.waitingOn, r.waitReason = "", ""
+}
+
+// started returns whether r was started.
+func (s *supervisor) started(r *pathRunner) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !r.startedAt.IsZero()
+}
+
+// hasDependencies returns whether any path depends on another.
+func (s *supervisor) hasDependencies() bool {
+	for _, r := range s.runners {
+		if len(r.dependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// startup returns the startup progress of every path, in the order the paths are started.
+func (s *supervisor) startup() []PathStartup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	startup := make([]PathStartup, 0, len(s.names))
+	for _, name := range s.names {
+		r := s.runners[name]
+		ps := PathStartup{Path: name, DependsOn: r.dependsOn, State: pathStartupWaiting}
+		if !r.startedAt.IsZero() {
+			startedAt := r.startedAt
+			ps.State, ps.StartedAt = pathStartupStarted, &startedAt
+		} else {
+			ps.WaitingOn, ps.Reason = r.waitingOn, r.waitReason
+		}
+		startup = append(startup, ps)
+	}
+	return startup
+}
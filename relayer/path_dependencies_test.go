@@ -0,0 +1,134 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDependencyOrder(t *testing.T) {
+	deps := map[string][]string{
+		"rollapp-hub":  {"hub-osmosis"},
+		"rollapp-osmo": {"rollapp-hub", "hub-osmosis"},
+	}
+	names, err := dependencyOrder([]string{"rollapp-osmo", "rollapp-hub", "hub-osmosis", "hub-cosmos"}, deps)
+	require.NoError(t, err)
+	require.Equal(t, []string{"hub-osmosis", "hub-cosmos", "rollapp-hub", "rollapp-osmo"}, names)
+
+	deps["hub-osmosis"] = []string{"rollapp-osmo"}
+	_, err = dependencyOrder([]string{"rollapp-osmo", "rollapp-hub", "hub-osmosis", "hub-cosmos"}, deps)
+	require.EqualError(t, err, "paths rollapp-osmo, rollapp-hub, hub-osmosis depend on each other in a cycle")
+}
+
+func TestPathsValidateDependencies(t *testing.T) {
+	paths := Paths{
+		"hub":     &Path{},
+		"rollapp": &Path{DependsOn: []string{"hub"}},
+	}
+	require.NoError(t, paths.ValidateDependencies())
+
+	paths["rollapp"].DependsOn = []string{"hub", "other"}
+	require.EqualError(t, paths.ValidateDependencies(), "path rollapp depends on path other, which is not configured")
+
+	paths["rollapp"].DependsOn = []string{"rollapp"}
+	require.Error(t, paths.ValidateDependencies())
+}
+
+func TestNewSupervisorDependencies(t *testing.T) {
+	chains := map[string]*Chain{
+		"hub":     {Chainid: "hub"},
+		"rollapp": {Chainid: "rollapp"},
+	}
+	paths := []NamedPath{
+		{Name: "rollapp-hub", Path: &Path{
+			Src:       &PathEnd{ChainID: "rollapp"},
+			Dst:       &PathEnd{ChainID: "hub"},
+			DependsOn: []string{"hub-rollapp"},
+		}},
+		{Name: "hub-rollapp", Path: &Path{
+			Src: &PathEnd{ChainID: "hub"},
+			Dst: &PathEnd{ChainID: "rollapp"},
+		}},
+	}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"hub-rollapp", "rollapp-hub"}, s.names)
+	require.True(t, s.hasDependencies())
+
+	// Dependent paths wait for the paths they depend on to start.
+	dep, err := s.pendingDependency(context.Background(), s.runners["rollapp-hub"])
+	require.Equal(t, "hub-rollapp", dep)
+	require.Error(t, err)
+	s.setWaiting(s.runners["rollapp-hub"], dep, err)
+	s.setStarted(s.runners["hub-rollapp"])
+
+	startup := s.startup()
+	require.Len(t, startup, 2)
+	require.Equal(t, pathStartupStarted, startup[0].State)
+	require.NotNil(t, startup[0].StartedAt)
+	require.Equal(t, pathStartupWaiting, startup[1].State)
+	require.Equal(t, "hub-rollapp", startup[1].WaitingOn)
+	require.Equal(t, "path hub-rollapp has not started yet", startup[1].Reason)
+
+	// Paths can only depend on paths relayed alongside them.
+	_, err = newSupervisor(zap.NewNop(), chains, paths[:1], 0, 0, "", ProcessorEvents, 0)
+	require.EqualError(t, err, "path rollapp-hub depends on path hub-rollapp, which is not relayed")
+}
+
+// pathStateProvider serves the clients and connections of a chain.
+type pathStateProvider struct {
+	provider.ChainProvider
+	chainID     string
+	clients     map[string]bool
+	connections map[string]conntypes.State
+}
+
+func (p *pathStateProvider) ChainId() string { return p.chainID }
+
+func (p *pathStateProvider) QueryLatestHeight(context.Context) (int64, error) { return 10, nil }
+
+func (p *pathStateProvider) QueryClientState(_ context.Context, _ int64, clientID string) (ibcexported.ClientState, error) {
+	if !p.clients[clientID] {
+		return nil, errors.New("client not found")
+	}
+	return nil, nil
+}
+
+func (p *pathStateProvider) QueryConnection(_ context.Context, _ int64, connectionID string) (*conntypes.QueryConnectionResponse, error) {
+	state, ok := p.connections[connectionID]
+	if !ok {
+		return nil, errors.New("connection not found")
+	}
+	return &conntypes.QueryConnectionResponse{Connection: &conntypes.ConnectionEnd{State: state}}, nil
+}
+
+func TestPathReady(t *testing.T) {
+	ctx := context.Background()
+	hub := &pathStateProvider{chainID: "hub", clients: map[string]bool{"07-tendermint-0": true}}
+	rollapp := &pathStateProvider{chainID: "rollapp", clients: map[string]bool{}}
+	src := &Chain{ChainProvider: hub, PathEnd: &PathEnd{ChainID: "hub", ClientID: "07-tendermint-0"}}
+	dst := &Chain{ChainProvider: rollapp, PathEnd: &PathEnd{ChainID: "rollapp"}}
+
+	require.EqualError(t, PathReady(ctx, src, dst), "no client configured on rollapp")
+
+	dst.PathEnd.ClientID = "07-tendermint-0"
+	require.ErrorContains(t, PathReady(ctx, src, dst), "client 07-tendermint-0 not found on rollapp")
+
+	// Without connections, the path is ready once both clients exist.
+	rollapp.clients["07-tendermint-0"] = true
+	require.NoError(t, PathReady(ctx, src, dst))
+
+	src.PathEnd.ConnectionID, dst.PathEnd.ConnectionID = "connection-0", "connection-0"
+	hub.connections = map[string]conntypes.State{"connection-0": conntypes.OPEN}
+	rollapp.connections = map[string]conntypes.State{"connection-0": conntypes.TRYOPEN}
+	require.EqualError(t, PathReady(ctx, src, dst), "connection connection-0 on rollapp is not open")
+
+	rollapp.connections["connection-0"] = conntypes.OPEN
+	require.NoError(t, PathReady(ctx, src, dst))
+}
@@ -0,0 +1,287 @@
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	zaplogfmt "github.com/jsternberg/zap-logfmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/term"
+)
+
+// PathLogConfig configures the logs of a single path independently of the other paths,
+// e.g. to debug one path without flooding the logs of the others. Empty fields fall back to the logging of the relayer.
+// The chain processors shared by the paths relayed with the events processor keep logging with the relayer.
+type PathLogConfig struct {
+	// Level is the minimum level logged for the path: debug, info, warn or error.
+	Level string `yaml:"level,omitempty" json:"level,omitempty"`
+
+	// Format is the format of the logs of the path: auto, logfmt, json or console.
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
+
+	// File is the file the logs of the path are appended to, instead of stderr.
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+}
+
+// Validate returns an error if the level or format of the config is unknown.
+func (c PathLogConfig) Validate() error {
+	if c.Level != "" {
+		var l zapcore.Level
+		if err := l.UnmarshalText([]byte(c.Level)); err != nil {
+			return fmt.Errorf("invalid log level %q: %w", c.Level, err)
+		}
+	}
+	if c.Format != "" {
+		if _, err := NewLogEncoder(c.Format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewLogEncoder returns the encoder of the given log format: auto, logfmt, json or console.
+// Auto logs to the console format on a terminal and to logfmt otherwise.
+func NewLogEncoder(format string) (zapcore.Encoder, error) {
+	config := zap.NewProductionEncoderConfig()
+	config.EncodeTime = func(ts time.Time, encoder zapcore.PrimitiveArrayEncoder) {
+		encoder.AppendString(ts.UTC().Format("2006-01-02T15:04:05.000000Z07:00"))
+	}
+	config.LevelKey = "lvl"
+
+	switch format {
+	case "json":
+		return zapcore.NewJSONEncoder(config), nil
+	case "console":
+		return zapcore.NewConsoleEncoder(config), nil
+	case "logfmt":
+		return zaplogfmt.NewEncoder(config), nil
+	case "auto":
+		if term.IsTerminal(int(os.Stderr.Fd())) {
+			// When a user runs relayer in the foreground, use easier to read output.
+			return zapcore.NewConsoleEncoder(config), nil
+		}
+		// Otherwise, use consistent logfmt format for simplistic machine processing.
+		return zaplogfmt.NewEncoder(config), nil
+	default:
+		return nil, fmt.Errorf("unrecognized log format %q", format)
+	}
+}
+
+// pathLogger holds the logging of a path, which can be reconfigured while the path is relayed.
+// Until the path configures its own logging, it logs with the relayer.
+type pathLogger struct {
+	root          zapcore.Core
+	defaultFormat string
+
+	// tap also receives the logs of the path once it logs to an output of its own, if non-nil.
+	tap zapcore.Core
+
+	mu   sync.RWMutex
+	cfg  PathLogConfig
+	core zapcore.Core
+	file *os.File
+}
+
+func newPathLogger(root zapcore.Core, defaultFormat string) *pathLogger {
+	return &pathLogger{root: root, defaultFormat: defaultFormat, core: root}
+}
+
+// logger returns a logger of the path derived from log, whose output follows the configuration of the path.
+func (l *pathLogger) logger(log *zap.Logger) *zap.Logger {
+	return log.WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return &pathLogCore{l: l}
+	}))
+}
+
+// configure switches the logs of the path to cfg, a zero config logging with the relayer again.
+func (l *pathLogger) configure(cfg PathLogConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	core, file := l.root, (*os.File)(nil)
+	if cfg != (PathLogConfig{}) {
+		format := cfg.Format
+		if format == "" {
+			format = l.defaultFormat
+		}
+		enc, err := NewLogEncoder(format)
+		if err != nil {
+			return err
+		}
+
+		// Without a level of its own, the path logs the levels the relayer logs.
+		var level zapcore.LevelEnabler = l.root
+		if cfg.Level != "" {
+			var lvl zapcore.Level
+			if err := lvl.UnmarshalText([]byte(cfg.Level)); err != nil {
+				return err
+			}
+			level = lvl
+		}
+
+		var out zapcore.WriteSyncer = os.Stderr
+		if cfg.File != "" {
+			file, err = os.OpenFile(cfg.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+			if err != nil {
+				return err
+			}
+			out = file
+		}
+		core = zapcore.NewCore(enc, zapcore.Lock(out), level)
+		if l.tap != nil {
+			core = zapcore.NewTee(core, l.tap)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		_ = l.file.Close()
+	}
+	l.cfg, l.core, l.file = cfg, core, file
+	return nil
+}
+
+func (l *pathLogger) config() PathLogConfig {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.cfg
+}
+
+// close closes the file the path logs to, if any.
+func (l *pathLogger) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		_ = l.file.Sync()
+		_ = l.file.Close()
+		l.file = nil
+	}
+	l.core = l.root
+}
+
+// pathLogCore writes to the current core of a path logger, with the fields added to the logger.
+type pathLogCore struct {
+	l      *pathLogger
+	fields []zapcore.Field
+}
+
+func (c *pathLogCore) Enabled(lvl zapcore.Level) bool {
+	c.l.mu.RLock()
+	defer c.l.mu.RUnlock()
+	return c.l.core.Enabled(lvl)
+}
+
+func (c *pathLogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &pathLogCore{l: c.l, fields: append(c.fields[:len(c.fields):len(c.fields)], fields...)}
+}
+
+func (c *pathLogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *pathLogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.l.mu.RLock()
+	defer c.l.mu.RUnlock()
+	return c.l.core.Write(ent, append(c.fields[:len(c.fields):len(c.fields)], fields...))
+}
+
+func (c *pathLogCore) Sync() error {
+	c.l.mu.RLock()
+	defer c.l.mu.RUnlock()
+	return c.l.core.Sync()
+}
+
+// setupPathLogs gives every path a logger of its own, configured by the log section of the path,
+// falling back to the relayer's log and to defaultFormat for the paths configuring an output but no format.
+func (s *supervisor) setupPathLogs(paths []NamedPath, defaultFormat string) error {
+	if defaultFormat == "" {
+		defaultFormat = "auto"
+	}
+	for _, p := range paths {
+		r := s.runners[p.Name]
+		r.logs = newPathLogger(s.log.Core(), defaultFormat)
+		if s.recentErrors != nil {
+			// The recent errors keep the warnings and errors of the paths logging to an output of their own too.
+			r.logs.tap = s.recentErrors
+		}
+		if p.Path.Log != nil {
+			if err := r.logs.configure(*p.Path.Log); err != nil {
+				s.closePathLogs()
+				return fmt.Errorf("invalid log config of path %s: %w", p.Name, err)
+			}
+		}
+		r.log = r.logs.logger(s.log)
+	}
+	return nil
+}
+
+func (s *supervisor) closePathLogs() {
+	for _, r := range s.runners {
+		if r.logs != nil {
+			r.logs.close()
+		}
+	}
+}
+
+// pathLogs returns the logging configured for each path.
+func (s *supervisor) pathLogs() []admin.PathLog {
+	logs := make([]admin.PathLog, 0, len(s.names))
+	for _, name := range s.names {
+		cfg := s.runners[name].logs.config()
+		logs = append(logs, admin.PathLog{Path: name, Level: cfg.Level, Format: cfg.Format, File: cfg.File})
+	}
+	return logs
+}
+
+// registerLogHandlers exposes the logging of each path through the admin API,
+// so that a path can be debugged without restarting the relayer.
+//
+//	GET  /log lists the logging configured for each path.
+//	POST /log reconfigures the logging of a path, empty fields falling back to the logging of the relayer.
+func registerLogHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/log", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			admin.WriteJSON(w, http.StatusOK, s.pathLogs())
+		case http.MethodPost:
+			var body admin.PathLog
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			r, ok := s.runners[body.Path]
+			if !ok {
+				admin.WriteError(w, http.StatusNotFound, fmt.Errorf("path %s not found", body.Path))
+				return
+			}
+			cfg := PathLogConfig{Level: body.Level, Format: body.Format, File: body.File}
+			if err := r.logs.configure(cfg); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			s.log.Info(
+				"Reconfigured path logging",
+				zap.String("path_name", body.Path),
+				zap.String("level", cfg.Level),
+				zap.String("format", cfg.Format),
+				zap.String("file", cfg.File),
+			)
+			s.changes.recordPath(r, feed.ChangeLog, feed.CauseAdmin)
+			admin.WriteJSON(w, http.StatusOK, body)
+		default:
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package relayer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestPathLogger(t *testing.T) {
+	root, logs := observer.New(zapcore.InfoLevel)
+	l := newPathLogger(root, "logfmt")
+	log := l.logger(zap.New(root)).With(zap.String("path_name", "demo-path"))
+
+	// Paths log with the relayer until they configure their own logging.
+	log.Debug("hidden")
+	log.Info("relayer")
+	require.Equal(t, 1, logs.Len())
+
+	file := filepath.Join(t.TempDir(), "demo-path.log")
+	require.NoError(t, l.configure(PathLogConfig{Level: "debug", Format: "json", File: file}))
+	log.Debug("debugging", zap.Uint64("sequence", 1))
+	require.Equal(t, 1, logs.Len())
+
+	bz, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(bz, &entry))
+	require.Equal(t, "debugging", entry["msg"])
+	require.Equal(t, "debug", entry["lvl"])
+	require.Equal(t, "demo-path", entry["path_name"])
+	require.Equal(t, float64(1), entry["sequence"])
+
+	// Invalid configs leave the logging unchanged.
+	require.Error(t, l.configure(PathLogConfig{Level: "verbose"}))
+	require.Error(t, l.configure(PathLogConfig{Format: "xml"}))
+	require.Equal(t, "debug", l.config().Level)
+
+	// Without a level of its own, the path logs the levels of the relayer.
+	require.NoError(t, l.configure(PathLogConfig{Format: "json", File: file}))
+	log.Debug("hidden")
+	log.Warn("warning")
+	bz, err = os.ReadFile(file)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(bz)), "\n"), 2)
+
+	require.NoError(t, l.configure(PathLogConfig{}))
+	log.Info("relayer again")
+	require.Equal(t, 2, logs.Len())
+	require.Equal(t, "demo-path", logs.All()[1].ContextMap()["path_name"])
+	l.close()
+}
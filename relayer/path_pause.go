@@ -0,0 +1,70 @@
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"go.uber.org/zap"
+)
+
+// pathPausedReason is the reason of the hold placed on the channels of the paths paused in the config or through the admin API.
+const pathPausedReason = "paused"
+
+// pathPauses returns whether each path is paused.
+func (s *supervisor) pathPauses() []admin.PathPause {
+	pauses := make([]admin.PathPause, 0, len(s.names))
+	for _, name := range s.names {
+		pauses = append(pauses, admin.PathPause{Path: name, Paused: s.runners[name].pauses.held(pathPausedReason)})
+	}
+	return pauses
+}
+
+// registerPathPauseHandlers exposes the pause of each path through the admin API, so that paths are paused
+// during chain upgrades without restarting the relayer. Paused paths relay no packets nor acknowledgements,
+// on any of their channels and with either processor, while their clients are still kept updated.
+//
+//	GET  /paused lists whether each path is paused.
+//	POST /paused pauses or resumes a path.
+func registerPathPauseHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/paused", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			admin.WriteJSON(w, http.StatusOK, s.pathPauses())
+		case http.MethodPost:
+			var body admin.PathPause
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			r, ok := s.runners[body.Path]
+			if !ok {
+				admin.WriteError(w, http.StatusNotFound, fmt.Errorf("path %s not found", body.Path))
+				return
+			}
+			var changed bool
+			if body.Paused {
+				changed = r.pauses.hold(pathPausedReason)
+			} else {
+				changed = r.pauses.release(pathPausedReason)
+			}
+			if changed {
+				s.log.Info(
+					"Changed path pause state",
+					zap.String("path_name", r.name),
+					zap.Bool("paused", body.Paused),
+				)
+				kind := feed.ChangePathResumed
+				if body.Paused {
+					kind = feed.ChangePathPaused
+				}
+				s.changes.recordPath(r, kind, feed.CauseAdmin)
+			}
+			admin.WriteJSON(w, http.StatusOK, admin.PathPause{Path: r.name, Paused: body.Paused})
+		default:
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	})
+}
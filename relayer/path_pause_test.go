@@ -0,0 +1,91 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPathPauseHandlers(t *testing.T) {
+	chain := func(chainID string) *Chain {
+		return &Chain{Chainid: chainID, ChainProvider: &cosmosprovider.CosmosProvider{PCfg: cosmosprovider.CosmosProviderConfig{ChainID: chainID}}}
+	}
+	chains := map[string]*Chain{"chain-a": chain("chain-a"), "chain-b": chain("chain-b")}
+	paths := []NamedPath{
+		{Name: "a-b", Path: &Path{
+			Src: &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"},
+			Dst: &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-0"},
+		}},
+		{Name: "b-a", Path: &Path{
+			Src:    &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-1"},
+			Dst:    &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-1"},
+			Paused: true,
+		}},
+	}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+
+	// Paths paused in the config start paused.
+	require.True(t, s.runners["b-a"].pauses.isPaused("transfer", "channel-0"))
+	require.Equal(t, []admin.PathPause{{Path: "a-b"}, {Path: "b-a", Paused: true}}, s.pathPauses())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	srv := admin.NewServer(zaptest.NewLogger(t))
+	registerPathPauseHandlers(srv, s)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv.Start(ctx, ln)
+
+	nonce := 0
+	post := func(body admin.PathPause) (int, admin.PathPause) {
+		bz, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, "http://"+ln.Addr().String()+"/paused", bytes.NewReader(bz))
+		require.NoError(t, err)
+		nonce++
+		req.Header.Set(admin.OperatorHeader, "alice")
+		req.Header.Set(admin.NonceHeader, strconv.Itoa(nonce))
+		req.Header.Set(admin.TimestampHeader, strconv.FormatInt(time.Now().Unix(), 10))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var res admin.PathPause
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		return resp.StatusCode, res
+	}
+
+	code, res := post(admin.PathPause{Path: "a-b", Paused: true})
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, res.Paused)
+	require.True(t, s.runners["a-b"].pauses.isPaused("transfer", "channel-0"))
+
+	// Resuming a path leaves its channels paused through the admin API paused.
+	require.True(t, s.runners["b-a"].pauses.pause("transfer", "channel-1"))
+	code, res = post(admin.PathPause{Path: "b-a"})
+	require.Equal(t, http.StatusOK, code)
+	require.False(t, res.Paused)
+	require.False(t, s.runners["b-a"].pauses.isPaused("transfer", "channel-0"))
+	require.True(t, s.runners["b-a"].pauses.isPaused("transfer", "channel-1"))
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/paused")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var pauses []admin.PathPause
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&pauses))
+	require.Equal(t, []admin.PathPause{{Path: "a-b", Paused: true}, {Path: "b-a"}}, pauses)
+
+	code, _ = post(admin.PathPause{Path: "unknown", Paused: true})
+	require.Equal(t, http.StatusNotFound, code)
+}
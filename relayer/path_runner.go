@@ -0,0 +1,737 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/ackstore"
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// NamedPath combines a path and its name.
+type NamedPath struct {
+	Name string
+	Path *Path
+}
+
+// pathRunner holds the state of a single relayed path.
+type pathRunner struct {
+	name string
+
+	// log is the logger of the path, following the logging configured in logs.
+	log  *zap.Logger
+	logs *pathLogger
+
+	// src and dst are copies of the configured chains carrying the path ends of this path,
+	// so multiple paths can share the same chain.
+	src, dst *Chain
+	filter   ChannelFilter
+
+	// furtherConnections and allConnections are the connections relayed by the legacy processor
+	// besides the connection of src, see Path.Connections.
+	furtherConnections []PathConnection
+	allConnections     bool
+
+	// monitor holds the monitor-only channels of the path, also excluded from relaying by filter.
+	monitor *ChannelMonitor
+
+	// registerPayee registers the relayer as counterparty payee on the fee-enabled channels of the path at startup.
+	registerPayee bool
+
+	// maxConcurrentChannels overrides the supervisor's limit of channels relayed at once by the legacy processor, if set.
+	maxConcurrentChannels int
+
+	// trusted relays the packets sent on the rollapps of the path before they are finalized, see Path.Trusted.
+	trusted bool
+
+	// packetFilter skips the transfers of the path not worth relaying, if non-nil.
+	packetFilter *processor.PacketFilter
+
+	// rateLimiter holds back the transfers of the path over its rate limits, pausing their channel, if non-nil.
+	rateLimiter *processor.RateLimiter
+
+	// ackAggregator holds back the acknowledgements of the path to relay them together, if non-nil.
+	ackAggregator *processor.AckAggregator
+
+	// decisions captures the packet flow decisions of the path when relayed with the events processor.
+	decisions *processor.DecisionCapture
+
+	// priority bumps the fees of the transactions of the path while enabled, toggled through the admin API.
+	priority *provider.Priority
+
+	// processorType is guarded by the supervisor's mutex.
+	processorType string
+
+	flushes *flushDeduplicator
+
+	// pauses holds the channels of the path paused through the admin API, whichever processor relays it.
+	pauses *channelPauses
+
+	// dormant holds the channels of the path the legacy processor set dormant, nil if channels are never set dormant.
+	dormant *dormantChannels
+
+	// dependsOn names the paths which must be ready before the path is started, see Path.DependsOn.
+	dependsOn []string
+
+	// startedAt, waitingOn and waitReason report the startup of the path, and are guarded by the supervisor's mutex.
+	startedAt             time.Time
+	waitingOn, waitReason string
+}
+
+// supervisor relays a set of paths. All paths relayed with the events processor share a single event processor,
+// so chain processors are deduplicated across paths touching the same chain, while every path relayed with the
+// legacy processor has its own main loop. Paths can be handed off from one processor to the other without
+// restarting the relayer.
+type supervisor struct {
+	log *zap.Logger
+
+	maxTxSize           uint64
+	maxMsgLength        uint64
+	memo                string
+	initialBlockHistory uint64
+	backfill            processor.Backfill
+	relayerActivity     *processor.RelayerActivity
+	feed                *feed.Publisher
+	changes             *changefeed
+	metrics             *processor.PrometheusMetrics
+	ackStore            *ackstore.Store
+
+	// channelDiscoveryInterval is how often legacy paths look for newly opened channels, 0 to never look.
+	channelDiscoveryInterval time.Duration
+
+	// timeoutScanInterval is how often legacy paths scan their channels for timed out packets, 0 to never scan.
+	timeoutScanInterval time.Duration
+
+	// maxConcurrentChannels is the maximum number of channels of a path relayed at once by the legacy processor,
+	// 0 for no limit.
+	maxConcurrentChannels int
+
+	// batchWindow is how long legacy paths gather the messages of their channels before sending them together,
+	// 0 for every channel to send its own transactions.
+	batchWindow time.Duration
+
+	// finalityGating only relays packets sent on rollapps once finalized on the settlement layer.
+	finalityGating bool
+
+	// relayDirection is the half of the packet flow of the paths relayed, see RelayDirection.
+	relayDirection RelayDirection
+
+	// preconfirmations tracks the packets trusted paths relayed from rollapps before they were finalized.
+	preconfirmations *processor.PreconfirmationTracker
+
+	// heldPackets tracks the packets held back on finality with the state update of the settlement layer holding them.
+	heldPackets *processor.HeldPacketTracker
+
+	// relayAttempts records the last attempt at relaying each packet, if the stuck packets are detected.
+	relayAttempts *processor.RelayAttempts
+
+	// maintenance runs the periodic maintenance tasks, such as keeping clients from expiring.
+	maintenance *scheduler
+
+	// readOnly paths are observed without sending transactions, which restricts them to the events processor.
+	readOnly bool
+
+	// recentErrors keeps the last warnings and errors logged by the paths for the admin API, if non-nil.
+	recentErrors *recentErrors
+
+	// capabilities are the capabilities of the endpoints of the chains detected at startup, keyed by chain ID.
+	capabilities map[string]provider.Capabilities
+
+	// features enables or disables the experimental features of the paths.
+	features *featureFlags
+
+	// runners and names are not modified after construction.
+	runners map[string]*pathRunner
+	names   []string
+
+	// relayEvents and relayLegacy run the events processor of the paths relayed with it and the legacy processor
+	// of a path, until ctx is cancelled or they fail, writing their error to errCh.
+	relayEvents func(ctx context.Context, paths []path, errCh chan<- error)
+	relayLegacy func(ctx context.Context, r *pathRunner, errCh chan<- error)
+
+	mu sync.Mutex
+
+	handoff chan handoffRequest
+	stopped chan struct{}
+}
+
+type handoffRequest struct {
+	path          string
+	processorType string
+	done          chan struct{}
+}
+
+// runningProcessor is a processor that was started by the supervisor.
+type runningProcessor struct {
+	cancel context.CancelFunc
+
+	// stopping is set when the supervisor stops the processor, as opposed to it exiting on its own.
+	stopping bool
+
+	err    error
+	exited chan struct{}
+}
+
+func newSupervisor(
+	log *zap.Logger,
+	chains map[string]*Chain,
+	paths []NamedPath,
+	maxTxSize, maxMsgLength uint64,
+	memo string,
+	processorType string,
+	initialBlockHistory uint64,
+) (*supervisor, error) {
+	s := &supervisor{
+		log:                 log,
+		maxTxSize:           maxTxSize,
+		maxMsgLength:        maxMsgLength,
+		memo:                memo,
+		initialBlockHistory: initialBlockHistory,
+		features:            newFeatureFlags(nil),
+		runners:             make(map[string]*pathRunner, len(paths)),
+		handoff:             make(chan handoffRequest),
+		stopped:             make(chan struct{}),
+	}
+	s.relayEvents, s.relayLegacy = s.startEventProcessor, s.startMainLoop
+
+	for _, p := range paths {
+		if _, ok := s.runners[p.Name]; ok {
+			return nil, fmt.Errorf("path %s is configured more than once", p.Name)
+		}
+		src, ok := chains[p.Path.Src.ChainID]
+		if !ok {
+			return nil, fmt.Errorf("chain %s of path %s is not configured", p.Path.Src.ChainID, p.Name)
+		}
+		dst, ok := chains[p.Path.Dst.ChainID]
+		if !ok {
+			return nil, fmt.Errorf("chain %s of path %s is not configured", p.Path.Dst.ChainID, p.Name)
+		}
+		filter := p.Path.Filter
+		filter.monitor = p.Path.Monitor
+		packetFilter, err := p.Path.PacketFilter.filter(p.Path.Src.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid packet filter of path %s: %w", p.Name, err)
+		}
+		limiter, err := rateLimiter(p.Path.Src.ChainID, p.Path.RateLimits)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limits of path %s: %w", p.Name, err)
+		}
+		ackAggregator, err := p.Path.AckAggregation.aggregator(p.Path.Src.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ack aggregation of path %s: %w", p.Name, err)
+		}
+		priority, err := provider.NewPriority(p.Path.Priority, p.Path.PriorityFeeMultiplier)
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority of path %s: %w", p.Name, err)
+		}
+		pauses := newChannelPauses(p.Path.Src.ChainID)
+		if p.Path.Paused {
+			pauses.hold(pathPausedReason)
+		}
+		s.runners[p.Name] = &pathRunner{
+			name:          p.Name,
+			log:           log,
+			src:           src.withPathEnd(p.Path.Src),
+			dst:           dst.withPathEnd(p.Path.Dst),
+			filter:        filter,
+			monitor:       p.Path.Monitor,
+			registerPayee: p.Path.RegisterCounterpartyPayee,
+			processorType: processorType,
+			flushes:       newFlushDeduplicator(),
+			pauses:        pauses,
+
+			furtherConnections:    p.Path.Connections,
+			allConnections:        p.Path.AllConnections,
+			maxConcurrentChannels: p.Path.MaxConcurrentChannels,
+			trusted:               p.Path.Trusted,
+			packetFilter:          packetFilter,
+			rateLimiter:           limiter,
+			ackAggregator:         ackAggregator,
+			decisions:             processor.NewDecisionCapture(),
+			priority:              priority,
+			dependsOn:             p.Path.DependsOn,
+		}
+		if limiter != nil {
+			r := s.runners[p.Name]
+			limiter.SetOnExceeded(func(portID, channelID, reason string) {
+				s.rateLimitExceeded(r, portID, channelID, reason)
+			})
+		}
+		s.names = append(s.names, p.Name)
+	}
+
+	// Paths are started after the paths they depend on.
+	deps := make(map[string][]string, len(s.runners))
+	for _, name := range s.names {
+		for _, dep := range s.runners[name].dependsOn {
+			if _, ok := s.runners[dep]; !ok {
+				return nil, fmt.Errorf("path %s depends on path %s, which is not relayed", name, dep)
+			}
+		}
+		deps[name] = s.runners[name].dependsOn
+	}
+	names, err := dependencyOrder(s.names, deps)
+	if err != nil {
+		return nil, err
+	}
+	s.names = names
+
+	return s, nil
+}
+
+// chains returns the distinct chains of all paths.
+func (s *supervisor) chains() []*Chain {
+	var chains []*Chain
+	seen := make(map[string]bool)
+	for _, name := range s.names {
+		r := s.runners[name]
+		for _, c := range []*Chain{r.src, r.dst} {
+			if !seen[c.ChainID()] {
+				seen[c.ChainID()] = true
+				chains = append(chains, c)
+			}
+		}
+	}
+	return chains
+}
+
+// concurrentChannels returns the maximum number of channels of r relayed at once by the legacy processor.
+func (s *supervisor) concurrentChannels(r *pathRunner) int {
+	if r.maxConcurrentChannels > 0 {
+		return r.maxConcurrentChannels
+	}
+	return s.maxConcurrentChannels
+}
+
+var errReadOnlyProcessor = fmt.Errorf("read-only paths can only be relayed with the %s processor", ProcessorEvents)
+
+var errHybridProcessorDisabled = fmt.Errorf("feature %s is disabled, paths cannot be handed off to another processor", FeatureHybridProcessor)
+
+func validateProcessorType(processorType string) error {
+	switch processorType {
+	case ProcessorEvents, ProcessorLegacy:
+		return nil
+	default:
+		return fmt.Errorf("unexpected processor type: %s, supports one of: [%s, %s]", processorType, ProcessorEvents, ProcessorLegacy)
+	}
+}
+
+// ProcessorType returns the processor currently relaying the path.
+func (s *supervisor) ProcessorType(r *pathRunner) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return r.processorType
+}
+
+func (s *supervisor) setProcessorType(r *pathRunner, processorType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r.processorType = processorType
+}
+
+// SetProcessorType drains the processor currently relaying the path and hands the path off to the given processor.
+// It blocks until the new processor has been started.
+func (s *supervisor) SetProcessorType(ctx context.Context, path, processorType string) error {
+	if err := validateProcessorType(processorType); err != nil {
+		return err
+	}
+	if s.readOnly && processorType != ProcessorEvents {
+		return errReadOnlyProcessor
+	}
+	r, ok := s.runners[path]
+	if !ok {
+		return fmt.Errorf("path %s not found", path)
+	}
+	if s.ProcessorType(r) == processorType {
+		return nil
+	}
+	if !s.features.Enabled(FeatureHybridProcessor, path) {
+		return fmt.Errorf("%w for path %s", errHybridProcessorDisabled, path)
+	}
+	if processorType == ProcessorLegacy {
+		if err := s.supportsLegacy(r); err != nil {
+			return err
+		}
+	}
+
+	req := handoffRequest{
+		path:          path,
+		processorType: processorType,
+		done:          make(chan struct{}),
+	}
+	select {
+	case s.handoff <- req:
+	case <-s.stopped:
+		return fmt.Errorf("relayer is no longer running")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-req.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run relays the paths until ctx is cancelled or any of the running processors exits on its own,
+// then stops the remaining processors, writes the final error to errCh and closes it.
+func (s *supervisor) run(ctx context.Context, errCh chan<- error) {
+	defer close(errCh)
+	defer close(s.stopped)
+	defer s.closePathLogs()
+
+	exits := make(chan *runningProcessor)
+	legacy := make(map[string]*runningProcessor)
+	var events *runningProcessor
+
+	start := func(fn func(ctx context.Context, errCh chan<- error)) *runningProcessor {
+		runCtx, cancel := context.WithCancel(ctx)
+		rp := &runningProcessor{cancel: cancel, exited: make(chan struct{})}
+		done := make(chan error, 1)
+		go fn(runCtx, done)
+		go func() {
+			rp.err = <-done
+			close(rp.exited)
+			select {
+			case exits <- rp:
+			case <-s.stopped:
+			}
+		}()
+		return rp
+	}
+
+	// stop cancels the processor and waits for its in-flight work to exit.
+	stop := func(rp *runningProcessor) {
+		if rp == nil {
+			return
+		}
+		rp.stopping = true
+		rp.cancel()
+		<-rp.exited
+	}
+
+	startEvents := func() {
+		paths := s.eventPaths()
+		if len(paths) == 0 {
+			events = nil
+			return
+		}
+		events = start(func(ctx context.Context, errCh chan<- error) {
+			s.relayEvents(ctx, paths, errCh)
+		})
+	}
+
+	startLegacy := func(r *pathRunner) {
+		legacy[r.name] = start(func(ctx context.Context, errCh chan<- error) {
+			s.relayLegacy(ctx, r, errCh)
+		})
+	}
+
+	stopAll := func() {
+		stop(events)
+		for _, rp := range legacy {
+			stop(rp)
+		}
+	}
+
+	// Paths with dependencies are started once the paths they depend on are ready, the others right away.
+	ready := make(chan *pathRunner)
+	for _, name := range s.names {
+		r := s.runners[name]
+		if len(r.dependsOn) == 0 {
+			s.setStarted(r)
+		} else {
+			go s.awaitDependencies(ctx, r, ready)
+		}
+	}
+
+	startEvents()
+	for _, name := range s.names {
+		if r := s.runners[name]; s.started(r) && s.ProcessorType(r) == ProcessorLegacy {
+			startLegacy(r)
+		}
+	}
+
+	for {
+		select {
+		case rp := <-exits:
+			if rp.stopping {
+				continue
+			}
+			stopAll()
+			errCh <- rp.err
+			return
+		case r := <-ready:
+			s.setStarted(r)
+			s.log.Info(
+				"Path dependencies ready, starting path",
+				zap.String("path_name", r.name),
+				zap.Strings("depends_on", r.dependsOn),
+			)
+			s.changes.recordPath(r, feed.ChangePathStarted, feed.CauseDependencies)
+			if s.ProcessorType(r) == ProcessorLegacy {
+				startLegacy(r)
+				continue
+			}
+			// The event processor is shared by all paths relayed with it, so it is restarted with the path.
+			stop(events)
+			events = nil
+			if ctx.Err() != nil {
+				stopAll()
+				errCh <- ctx.Err()
+				return
+			}
+			startEvents()
+		case req := <-s.handoff:
+			r := s.runners[req.path]
+			from := s.ProcessorType(r)
+			s.log.Info(
+				"Draining processor for handoff",
+				zap.String("path_name", r.name),
+				zap.String("from", from),
+				zap.String("to", req.processorType),
+			)
+
+			// Both processors return only once their in-flight work has exited.
+			// The event processor is shared by all paths relayed with it,
+			// so it is restarted with or without the path.
+			if from == ProcessorLegacy {
+				stop(legacy[r.name])
+				delete(legacy, r.name)
+			}
+			stop(events)
+			events = nil
+
+			if ctx.Err() != nil {
+				close(req.done)
+				stopAll()
+				errCh <- ctx.Err()
+				return
+			}
+
+			s.setProcessorType(r, req.processorType)
+			startEvents()
+			if req.processorType == ProcessorLegacy && s.started(r) {
+				startLegacy(r)
+			}
+
+			s.log.Info(
+				"Handed off path to processor",
+				zap.String("path_name", r.name),
+				zap.String("processor", req.processorType),
+			)
+			s.changes.recordPath(r, feed.ChangeProcessor, feed.CauseAdmin)
+			close(req.done)
+		case <-ctx.Done():
+			stopAll()
+			errCh <- ctx.Err()
+			return
+		}
+	}
+}
+
+// startEventProcessor relays paths with the events processor.
+func (s *supervisor) startEventProcessor(ctx context.Context, paths []path, errCh chan<- error) {
+	relayerStartEventProcessor(ctx, s.log, paths, s.initialBlockHistory, s.backfill, s.maxTxSize, s.maxMsgLength, s.memo, s.relayerActivity, s.feed, s.metrics, s.readOnly, s.finalityGating, s.relayDirection, s.preconfirmations, s.heldPackets, s.relayAttempts, errCh)
+}
+
+// startMainLoop relays r with the legacy processor.
+func (s *supervisor) startMainLoop(ctx context.Context, r *pathRunner, errCh chan<- error) {
+	ctx = withAckStore(withMetrics(provider.WithPriority(provider.WithPathName(ctx, r.name), r.priority), s.metrics, r.dst.ChainID()), s.ackStore)
+	ctx = withRateLimiter(withPacketFilter(withRelayDirection(ctx, s.relayDirection), r.packetFilter), r.rateLimiter)
+	ctx = withRelayAttempts(withMsgBatcher(ctx, r.log.With(zap.String("path", r.name)), s.batchWindow), s.relayAttempts)
+	relayerMainLoop(ctx, r.log, r.src, r.dst, r.connections(), s.maxTxSizeOf(r), s.maxMsgLength, s.memo, s.finalityGating, s.channelDiscoveryInterval, s.timeoutScanInterval, s.concurrentChannels(r), r.pauses, r.dormant, errCh)
+}
+
+// eventPaths builds the event processor representation of the paths currently relayed with the events processor.
+func (s *supervisor) eventPaths() []path {
+	var paths []path
+	for _, name := range s.names {
+		r := s.runners[name]
+		if s.ProcessorType(r) != ProcessorEvents || !s.started(r) {
+			continue
+		}
+
+		var filterSrc, filterDst []processor.ChannelKey
+
+		for _, entry := range r.filter.ChannelList {
+			port, ch := parseChannelFilterEntry(entry)
+			ruleSrc := processor.ChannelKey{ChannelID: ch, PortID: port}
+			ruleDst := processor.ChannelKey{CounterpartyChannelID: ch, CounterpartyPortID: port}
+			filterSrc = append(filterSrc, ruleSrc)
+			filterDst = append(filterDst, ruleDst)
+		}
+		src := processor.NewPathEnd(r.src.ChainProvider.ChainId(), r.src.ClientID(), r.filter.Rule, filterSrc)
+		dst := processor.NewPathEnd(r.dst.ChainProvider.ChainId(), r.dst.ClientID(), r.filter.Rule, filterDst)
+		if r.monitor != nil {
+			for _, entry := range r.monitor.ChannelList {
+				port, ch := parseChannelFilterEntry(entry)
+				src.ExcludeList = append(src.ExcludeList, processor.ChannelKey{ChannelID: ch, PortID: port})
+				dst.ExcludeList = append(dst.ExcludeList, processor.ChannelKey{CounterpartyChannelID: ch, CounterpartyPortID: port})
+			}
+		}
+		paths = append(paths, path{
+			name:    r.name,
+			log:     r.log,
+			src:     pathChain{provider: r.src.ChainProvider, pathEnd: src, maxTxBytes: s.capabilities[r.src.ChainID()].MaxTxBytes},
+			dst:     pathChain{provider: r.dst.ChainProvider, pathEnd: dst, maxTxBytes: s.capabilities[r.dst.ChainID()].MaxTxBytes},
+			pauses:  r.pauses,
+			trusted: r.trusted,
+			gate:    s.features.gate(r.name),
+
+			packetFilter:  r.packetFilter,
+			rateLimiter:   r.rateLimiter,
+			ackAggregator: r.ackAggregator,
+			decisions:     r.decisions,
+			priority:      r.priority,
+		})
+	}
+	return paths
+}
+
+// SigningQueue reports the transactions waiting to be signed with the key of a chain, by path.
+type SigningQueue struct {
+	ChainID string         `json:"chain_id"`
+	Paths   map[string]int `json:"paths"`
+}
+
+type signingQueuer interface {
+	SigningQueueDepths() map[string]int
+}
+
+// signingQueues returns the signing queues of the chains of all paths.
+func (s *supervisor) signingQueues() []SigningQueue {
+	var queues []SigningQueue
+	for _, c := range s.chains() {
+		if q, ok := c.ChainProvider.(signingQueuer); ok {
+			queues = append(queues, SigningQueue{ChainID: c.ChainID(), Paths: q.SigningQueueDepths()})
+		}
+	}
+	return queues
+}
+
+type rpcHealthChecker interface {
+	RunRPCHealthChecks(ctx context.Context)
+	RPCEndpointHealth() []provider.RPCEndpointHealth
+}
+
+// startRPCHealthChecks checks the health of the RPC endpoints of the chains of all paths until ctx is done,
+// failing over from unhealthy endpoints on chains configuring several.
+func (s *supervisor) startRPCHealthChecks(ctx context.Context) {
+	for _, c := range s.chains() {
+		if hc, ok := c.ChainProvider.(rpcHealthChecker); ok {
+			go hc.RunRPCHealthChecks(ctx)
+		}
+	}
+}
+
+type remoteSignerChecker interface {
+	RunRemoteSignerHealthChecks(ctx context.Context)
+	RemoteSignerHealth() (provider.RemoteSignerHealth, bool)
+}
+
+// startRemoteSignerHealthChecks checks the remote signers of the chains of all paths until ctx is done.
+func (s *supervisor) startRemoteSignerHealthChecks(ctx context.Context) {
+	for _, c := range s.chains() {
+		if rs, ok := c.ChainProvider.(remoteSignerChecker); ok {
+			go rs.RunRemoteSignerHealthChecks(ctx)
+		}
+	}
+}
+
+// remoteSigners returns the health of the remote signers of the chains of all paths configuring one.
+func (s *supervisor) remoteSigners() []provider.RemoteSignerHealth {
+	signers := []provider.RemoteSignerHealth{}
+	for _, c := range s.chains() {
+		if rs, ok := c.ChainProvider.(remoteSignerChecker); ok {
+			if h, ok := rs.RemoteSignerHealth(); ok {
+				signers = append(signers, h)
+			}
+		}
+	}
+	return signers
+}
+
+type txTrackerProvider interface {
+	TrackedTxs() []provider.TrackedTx
+}
+
+// trackedTxs returns the transactions broadcast to the chains of all paths pending inclusion,
+// and the last ones included, failed or timed out.
+func (s *supervisor) trackedTxs() []provider.TrackedTx {
+	txs := []provider.TrackedTx{}
+	for _, c := range s.chains() {
+		if tp, ok := c.ChainProvider.(txTrackerProvider); ok {
+			txs = append(txs, tp.TrackedTxs()...)
+		}
+	}
+	return txs
+}
+
+// rpcEndpoints returns the health of the RPC endpoints of the chains of all paths configuring several.
+func (s *supervisor) rpcEndpoints() []provider.RPCEndpointHealth {
+	endpoints := []provider.RPCEndpointHealth{}
+	for _, c := range s.chains() {
+		if hc, ok := c.ChainProvider.(rpcHealthChecker); ok {
+			endpoints = append(endpoints, hc.RPCEndpointHealth()...)
+		}
+	}
+	return endpoints
+}
+
+// registerProcessorHandlers exposes the processor of each path through the admin API.
+//
+//	GET  /processor lists the processor relaying each path.
+//	POST /processor hands a path off to another processor.
+func registerProcessorHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/processor", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			res := make([]admin.PathProcessor, 0, len(s.names))
+			for _, name := range s.names {
+				res = append(res, admin.PathProcessor{Path: name, Processor: s.ProcessorType(s.runners[name])})
+			}
+			admin.WriteJSON(w, http.StatusOK, res)
+		case http.MethodPost:
+			var body admin.SetProcessorRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			r, ok := s.runners[body.Path]
+			if !ok {
+				admin.WriteError(w, http.StatusNotFound, fmt.Errorf("path %s not found", body.Path))
+				return
+			}
+			if err := validateProcessorType(body.Processor); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			if s.readOnly && body.Processor != ProcessorEvents {
+				admin.WriteError(w, http.StatusBadRequest, errReadOnlyProcessor)
+				return
+			}
+			if err := s.SetProcessorType(req.Context(), body.Path, body.Processor); errors.Is(err, errHybridProcessorDisabled) {
+				admin.WriteError(w, http.StatusConflict, err)
+				return
+			} else if err != nil {
+				admin.WriteError(w, http.StatusInternalServerError, err)
+				return
+			}
+			admin.WriteJSON(w, http.StatusOK, admin.PathProcessor{Path: body.Path, Processor: s.ProcessorType(r)})
+		default:
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewSupervisorSharesChainsAcrossPaths(t *testing.T) {
+	chains := map[string]*Chain{
+		"chain-a": {Chainid: "chain-a"},
+		"chain-b": {Chainid: "chain-b"},
+		"chain-c": {Chainid: "chain-c"},
+	}
+	paths := []NamedPath{
+		{Name: "a-b", Path: &Path{
+			Src: &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"},
+			Dst: &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-0"},
+		}},
+		{Name: "a-c", Path: &Path{
+			Src: &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-1"},
+			Dst: &PathEnd{ChainID: "chain-c", ClientID: "07-tendermint-0"},
+		}},
+	}
+
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a-b", "a-c"}, s.names)
+
+	ab, ac := s.runners["a-b"], s.runners["a-c"]
+	require.Equal(t, "07-tendermint-0", ab.src.ClientID())
+	require.Equal(t, "07-tendermint-1", ac.src.ClientID())
+	require.Nil(t, chains["chain-a"].PathEnd, "configured chains must not be modified")
+
+	_, err = newSupervisor(zap.NewNop(), chains, append(paths, paths[0]), 0, 0, "", ProcessorEvents, 0)
+	require.Error(t, err)
+
+	delete(chains, "chain-c")
+	_, err = newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.Error(t, err)
+}
+
+func TestSupervisorPacketFilter(t *testing.T) {
+	chains := map[string]*Chain{
+		"chain-a": {Chainid: "chain-a"},
+		"chain-b": {Chainid: "chain-b"},
+	}
+	path := &Path{
+		Src:          &PathEnd{ChainID: "chain-a"},
+		Dst:          &PathEnd{ChainID: "chain-b"},
+		PacketFilter: &PacketFilter{MinAmounts: map[string]string{"urax": "1000"}},
+	}
+	s, err := newSupervisor(zap.NewNop(), chains, []NamedPath{{Name: "demo-path", Path: path}}, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, s.runners["demo-path"].packetFilter.Skip("chain-a", processor.ChannelKey{}, []byte(`{"denom":"urax","amount":"1"}`)))
+
+	path.PacketFilter.MinAmounts["urax"] = "1k"
+	_, err = newSupervisor(zap.NewNop(), chains, []NamedPath{{Name: "demo-path", Path: path}}, 0, 0, "", ProcessorEvents, 0)
+	require.ErrorContains(t, err, `invalid minimum amount "1k" of denom urax`)
+
+	path.PacketFilter = &PacketFilter{MemoPolicies: []MemoPolicy{{Channel: "channel-0", Action: "truncate"}}}
+	_, err = newSupervisor(zap.NewNop(), chains, []NamedPath{{Name: "demo-path", Path: path}}, 0, 0, "", ProcessorEvents, 0)
+	require.ErrorContains(t, err, `memo policy of channel "channel-0" truncates memos without max-bytes`)
+}
+
+// fakeProcessors stands in for the processors started by a supervisor, keeping track of those running.
+// Processors run until cancelled, legacy processors until failed through fail as well.
+type fakeProcessors struct {
+	mu      sync.Mutex
+	current map[string]bool
+	starts  int
+
+	fail chan error
+}
+
+func newFakeProcessors(s *supervisor) *fakeProcessors {
+	f := &fakeProcessors{current: make(map[string]bool), fail: make(chan error)}
+	s.relayEvents = func(ctx context.Context, paths []path, errCh chan<- error) {
+		names := make([]string, 0, len(paths))
+		for _, p := range paths {
+			names = append(names, p.name)
+		}
+		defer f.started("events:" + strings.Join(names, ","))()
+		<-ctx.Done()
+		errCh <- ctx.Err()
+	}
+	s.relayLegacy = func(ctx context.Context, r *pathRunner, errCh chan<- error) {
+		defer f.started("legacy:" + r.name)()
+		select {
+		case <-ctx.Done():
+			errCh <- ctx.Err()
+		case err := <-f.fail:
+			errCh <- err
+		}
+	}
+	return f
+}
+
+// started records that the processor named is running, until the function returned is called.
+func (f *fakeProcessors) started(name string) func() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.current[name] = true
+	f.starts++
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.current, name)
+	}
+}
+
+func (f *fakeProcessors) running() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := []string{}
+	for name := range f.current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *fakeProcessors) startCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.starts
+}
+
+func (f *fakeProcessors) requireRunning(t *testing.T, want ...string) {
+	require.Eventually(t, func() bool { return reflect.DeepEqual(want, f.running()) }, time.Second, time.Millisecond,
+		"expected %v running", want)
+}
+
+// newHandoffSupervisor returns a supervisor relaying the path a-b with the events processor
+// and the path a-c with the legacy processor.
+func newHandoffSupervisor(t *testing.T) (*supervisor, *fakeProcessors) {
+	chains := map[string]*Chain{
+		"chain-a": {Chainid: "chain-a", ChainProvider: &upgradingProvider{chainID: "chain-a"}},
+		"chain-b": {Chainid: "chain-b", ChainProvider: &upgradingProvider{chainID: "chain-b"}},
+		"chain-c": {Chainid: "chain-c", ChainProvider: &upgradingProvider{chainID: "chain-c"}},
+	}
+	paths := []NamedPath{
+		{Name: "a-b", Path: &Path{
+			Src: &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"},
+			Dst: &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-0"},
+		}},
+		{Name: "a-c", Path: &Path{
+			Src: &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-1"},
+			Dst: &PathEnd{ChainID: "chain-c", ClientID: "07-tendermint-0"},
+		}},
+	}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+	s.runners["a-c"].processorType = ProcessorLegacy
+	return s, newFakeProcessors(s)
+}
+
+func TestSupervisorHandoff(t *testing.T) {
+	s, f := newHandoffSupervisor(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go s.run(ctx, errCh)
+	f.requireRunning(t, "events:a-b", "legacy:a-c")
+
+	// The event processor is drained before the handoff returns, and restarted without the path.
+	require.NoError(t, s.SetProcessorType(ctx, "a-b", ProcessorLegacy))
+	require.NotContains(t, f.running(), "events:a-b")
+	require.Equal(t, ProcessorLegacy, s.ProcessorType(s.runners["a-b"]))
+	f.requireRunning(t, "legacy:a-b", "legacy:a-c")
+
+	// The legacy processor of the path is drained before the handoff returns, and the event processor restarted with it.
+	require.NoError(t, s.SetProcessorType(ctx, "a-c", ProcessorEvents))
+	require.NotContains(t, f.running(), "legacy:a-c")
+	require.Equal(t, ProcessorEvents, s.ProcessorType(s.runners["a-c"]))
+	f.requireRunning(t, "events:a-c", "legacy:a-b")
+
+	// Handing a path off to the processor relaying it restarts nothing.
+	starts := f.startCount()
+	require.NoError(t, s.SetProcessorType(ctx, "a-c", ProcessorEvents))
+	require.Equal(t, starts, f.startCount())
+
+	require.ErrorContains(t, s.SetProcessorType(ctx, "a-d", ProcessorEvents), "path a-d not found")
+	require.ErrorContains(t, s.SetProcessorType(ctx, "a-b", "naive"), "unexpected processor type: naive")
+	f.requireRunning(t, "events:a-c", "legacy:a-b")
+}
+
+func TestSupervisorShutdown(t *testing.T) {
+	s, f := newHandoffSupervisor(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go s.run(ctx, errCh)
+	f.requireRunning(t, "events:a-b", "legacy:a-c")
+
+	// All processors have exited once the supervisor reports it stopped.
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+	_, open := <-errCh
+	require.False(t, open)
+	require.Empty(t, f.running())
+
+	require.EqualError(t, s.SetProcessorType(context.Background(), "a-b", ProcessorLegacy), "relayer is no longer running")
+}
+
+func TestSupervisorStopsOnProcessorFailure(t *testing.T) {
+	s, f := newHandoffSupervisor(t)
+	errCh := make(chan error, 1)
+	go s.run(context.Background(), errCh)
+	f.requireRunning(t, "events:a-b", "legacy:a-c")
+
+	// A processor exiting on its own stops the others, and its error is reported.
+	f.fail <- errors.New("there are no open channels to relay on")
+	require.EqualError(t, <-errCh, "there are no open channels to relay on")
+	require.Empty(t, f.running())
+}
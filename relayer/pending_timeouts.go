@@ -0,0 +1,244 @@
+package relayer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// maxPendingTimeoutPackets bounds the pending packets of a channel end looked up per check, the oldest first,
+// as each of them costs a query of the transaction which sent it.
+const maxPendingTimeoutPackets = 100
+
+// PendingTimeout is a packet pending relay whose timeout on the chain it was sent to is near, or elapsed.
+type PendingTimeout struct {
+	Sequence uint64 `json:"sequence"`
+
+	// TimeoutHeight and TimeoutTimestamp are the timeouts of the packet, BlocksLeft and SecondsLeft
+	// how far the counterparty is from them, each omitted if the packet has no such timeout.
+	TimeoutHeight    string     `json:"timeout_height,omitempty"`
+	TimeoutTimestamp *time.Time `json:"timeout_timestamp,omitempty"`
+	BlocksLeft       *int64     `json:"blocks_left,omitempty"`
+	SecondsLeft      *float64   `json:"seconds_left,omitempty"`
+
+	// Expired is set once the packet can no longer be received, only timed out.
+	Expired bool `json:"expired"`
+}
+
+// PendingTimeouts is the report of the packets sent on a channel end and pending relay to the counterparty
+// that are approaching their timeout, so that operators can intervene before the transfers expire.
+type PendingTimeouts struct {
+	Path                string           `json:"path"`
+	ChainID             string           `json:"chain_id"`
+	ChannelID           string           `json:"channel_id"`
+	PortID              string           `json:"port_id"`
+	CounterpartyChainID string           `json:"counterparty_chain_id"`
+	Packets             []PendingTimeout `json:"packets"`
+	CheckedAt           time.Time        `json:"checked_at"`
+}
+
+// pendingTimeoutMonitor periodically reports the packets pending relay on the channels of every path
+// that are within blocks or window of their timeout on the counterparty.
+type pendingTimeoutMonitor struct {
+	log     *zap.Logger
+	metrics *processor.PrometheusMetrics
+	blocks  uint64
+	window  time.Duration
+
+	mu      sync.Mutex
+	reports map[string]PendingTimeouts
+}
+
+func newPendingTimeoutMonitor(log *zap.Logger, metrics *processor.PrometheusMetrics, blocks uint64, window time.Duration) *pendingTimeoutMonitor {
+	return &pendingTimeoutMonitor{
+		log:     log,
+		metrics: metrics,
+		blocks:  blocks,
+		window:  window,
+		reports: make(map[string]PendingTimeouts),
+	}
+}
+
+func (m *pendingTimeoutMonitor) check(ctx context.Context, r *pathRunner) {
+	channels, err := r.openChannels(ctx)
+	if err != nil {
+		m.log.Warn(
+			"Failed to query channels to check pending timeouts",
+			zap.String("path", r.name),
+			zap.Error(err),
+		)
+		return
+	}
+	for _, c := range channels {
+		srch, dsth, err := QueryLatestHeights(ctx, r.src, r.dst)
+		if err != nil {
+			m.log.Warn(
+				"Failed to query latest heights to check pending timeouts",
+				zap.String("path", r.name),
+				zap.String("channel_id", c.channel.ChannelId),
+				zap.Error(err),
+			)
+			return
+		}
+		sp := UnrelayedSequences(ctx, r.src, r.dst, srch, dsth, c.channel)
+		m.checkEnd(ctx, r.name, r.src, r.dst, dsth, c.channel.ChannelId, c.channel.PortId, sp.Src)
+		m.checkEnd(ctx, r.name, r.dst, r.src, srch, c.channel.Counterparty.ChannelId, c.channel.Counterparty.PortId, sp.Dst)
+	}
+}
+
+// checkEnd reports the packets of seqs, sent on the channel end of src and pending relay to dst,
+// which are near their timeout at height dsth of dst.
+func (m *pendingTimeoutMonitor) checkEnd(ctx context.Context, pathName string, src, dst *Chain, dsth int64, channelID, portID string, seqs []uint64) {
+	report := PendingTimeouts{
+		Path:                pathName,
+		ChainID:             src.ChainID(),
+		ChannelID:           channelID,
+		PortID:              portID,
+		CounterpartyChainID: dst.ChainID(),
+		Packets:             []PendingTimeout{},
+		CheckedAt:           time.Now().UTC(),
+	}
+
+	q, ok := src.ChainProvider.(sendPacketQuerier)
+	if ok && len(seqs) > 0 {
+		blockTime, err := dst.ChainProvider.BlockTime(ctx, dsth)
+		if err != nil {
+			m.log.Warn(
+				"Failed to query block time to check pending timeouts",
+				zap.String("path", pathName),
+				zap.String("chain_id", dst.ChainID()),
+				zap.Int64("height", dsth),
+				zap.Error(err),
+			)
+			return
+		}
+		latest := provider.LatestBlock{Height: uint64(dsth), Time: time.Unix(0, blockTime)}
+		revision := clienttypes.ParseChainID(dst.ChainID())
+
+		sorted := append([]uint64(nil), seqs...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		if len(sorted) > maxPendingTimeoutPackets {
+			sorted = sorted[:maxPendingTimeoutPackets]
+		}
+		for _, seq := range sorted {
+			packet, err := q.QuerySendPacket(ctx, channelID, portID, seq)
+			if err != nil {
+				m.log.Info(
+					"Failed to query packet to check its timeout",
+					zap.String("path", pathName),
+					zap.String("chain_id", src.ChainID()),
+					zap.String("channel_id", channelID),
+					zap.String("port_id", portID),
+					zap.Uint64("sequence", seq),
+					zap.Error(err),
+				)
+				continue
+			}
+			if pt, near := pendingTimeout(packet, revision, latest, m.blocks, m.window); near {
+				report.Packets = append(report.Packets, pt)
+			}
+		}
+	}
+	m.record(report)
+}
+
+// pendingTimeout returns the timeout of the packet as of the latest block of the chain it was sent to, at the given revision,
+// and whether the packet is within blocks or window of its timeout there.
+func pendingTimeout(packet provider.PacketInfo, revision uint64, latest provider.LatestBlock, blocks uint64, window time.Duration) (PendingTimeout, bool) {
+	pt := PendingTimeout{Sequence: packet.Sequence}
+	var near bool
+
+	if th := packet.TimeoutHeight; !th.IsZero() {
+		pt.TimeoutHeight = th.String()
+		switch {
+		case th.RevisionNumber < revision:
+			pt.Expired, near = true, true
+		case th.RevisionNumber == revision:
+			left := int64(th.RevisionHeight) - int64(latest.Height)
+			pt.BlocksLeft = &left
+			pt.Expired = left <= 0
+			near = left <= int64(blocks)
+		}
+	}
+	if packet.TimeoutTimestamp != 0 {
+		ts := time.Unix(0, int64(packet.TimeoutTimestamp)).UTC()
+		left := ts.Sub(latest.Time)
+		secs := left.Seconds()
+		pt.TimeoutTimestamp, pt.SecondsLeft = &ts, &secs
+		pt.Expired = pt.Expired || left <= 0
+		near = near || left <= window
+	}
+	return pt, near || pt.Expired
+}
+
+func (m *pendingTimeoutMonitor) record(report PendingTimeouts) {
+	var expired int
+	for _, p := range report.Packets {
+		if p.Expired {
+			expired++
+		}
+	}
+	m.metrics.SetPendingTimeouts(report.Path, report.ChainID, report.ChannelID, report.PortID, len(report.Packets)-expired, expired)
+
+	k := report.Path + "/" + report.ChainID + "/" + report.PortID + "/" + report.ChannelID
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(report.Packets) > 0 && len(m.reports[k].Packets) == 0 {
+		m.log.Warn(
+			"Packets pending relay are approaching their timeout",
+			zap.String("path", report.Path),
+			zap.String("chain_id", report.ChainID),
+			zap.String("channel_id", report.ChannelID),
+			zap.String("port_id", report.PortID),
+			zap.String("counterparty_chain_id", report.CounterpartyChainID),
+			zap.Int("count", len(report.Packets)),
+			zap.Int("expired", expired),
+		)
+	}
+	m.reports[k] = report
+}
+
+// snapshot returns the last report of every channel end, by path, chain, port and channel ID.
+func (m *pendingTimeoutMonitor) snapshot() []PendingTimeouts {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	reports := make([]PendingTimeouts, 0, len(m.reports))
+	for _, r := range m.reports {
+		reports = append(reports, r)
+	}
+	sort.Slice(reports, func(i, k int) bool {
+		a, b := reports[i], reports[k]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		if a.PortID != b.PortID {
+			return a.PortID < b.PortID
+		}
+		return a.ChannelID < b.ChannelID
+	})
+	return reports
+}
+
+// startPendingTimeoutMonitor starts reporting the packets of every path near their timeout at the given interval.
+func (s *supervisor) startPendingTimeoutMonitor(ctx context.Context, interval time.Duration, blocks uint64, window time.Duration) *pendingTimeoutMonitor {
+	m := newPendingTimeoutMonitor(s.log.With(zap.String("sys", "pendingtimeouts")), s.metrics, blocks, window)
+	runners := make([]*pathRunner, 0, len(s.names))
+	for _, name := range s.names {
+		runners = append(runners, s.runners[name])
+	}
+	s.maintenance.schedule(ctx, TaskPendingTimeouts, interval, func(ctx context.Context) {
+		for _, r := range runners {
+			m.check(ctx, r)
+		}
+	})
+	return m
+}
@@ -0,0 +1,63 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPendingTimeout(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	latest := provider.LatestBlock{Height: 1000, Time: now}
+	at := func(d time.Duration) uint64 { return uint64(now.Add(d).UnixNano()) }
+
+	for _, tc := range []struct {
+		name    string
+		packet  provider.PacketInfo
+		near    bool
+		expired bool
+	}{
+		{"far height", provider.PacketInfo{TimeoutHeight: clienttypes.NewHeight(1, 2000)}, false, false},
+		{"near height", provider.PacketInfo{TimeoutHeight: clienttypes.NewHeight(1, 1050)}, true, false},
+		{"elapsed height", provider.PacketInfo{TimeoutHeight: clienttypes.NewHeight(1, 1000)}, true, true},
+		{"past revision", provider.PacketInfo{TimeoutHeight: clienttypes.NewHeight(0, 5000)}, true, true},
+		{"future revision", provider.PacketInfo{TimeoutHeight: clienttypes.NewHeight(2, 1)}, false, false},
+		{"far timestamp", provider.PacketInfo{TimeoutTimestamp: at(time.Hour)}, false, false},
+		{"near timestamp", provider.PacketInfo{TimeoutTimestamp: at(10 * time.Minute)}, true, false},
+		{"elapsed timestamp", provider.PacketInfo{TimeoutTimestamp: at(-time.Second)}, true, true},
+		{"near timestamp, far height", provider.PacketInfo{TimeoutHeight: clienttypes.NewHeight(1, 2000), TimeoutTimestamp: at(time.Minute)}, true, false},
+		{"no timeout", provider.PacketInfo{}, false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pt, near := pendingTimeout(tc.packet, 1, latest, 100, 30*time.Minute)
+			require.Equal(t, tc.near, near)
+			require.Equal(t, tc.expired, pt.Expired)
+		})
+	}
+
+	pt, _ := pendingTimeout(provider.PacketInfo{Sequence: 7, TimeoutHeight: clienttypes.NewHeight(1, 1050), TimeoutTimestamp: at(time.Minute)}, 1, latest, 100, 0)
+	require.Equal(t, uint64(7), pt.Sequence)
+	require.Equal(t, "1-1050", pt.TimeoutHeight)
+	require.Equal(t, int64(50), *pt.BlocksLeft)
+	require.Equal(t, 60.0, *pt.SecondsLeft)
+}
+
+func TestPendingTimeoutMonitorSnapshot(t *testing.T) {
+	m := newPendingTimeoutMonitor(zaptest.NewLogger(t), processor.NewPrometheusMetrics(), 100, time.Minute)
+	m.record(PendingTimeouts{Path: "demo-path", ChainID: "chain-b", ChannelID: "channel-1", PortID: "transfer", Packets: []PendingTimeout{}})
+	m.record(PendingTimeouts{Path: "demo-path", ChainID: "chain-a", ChannelID: "channel-0", PortID: "transfer", Packets: []PendingTimeout{{Sequence: 1, Expired: true}}})
+
+	s := m.snapshot()
+	require.Len(t, s, 2)
+	require.Equal(t, "chain-a", s[0].ChainID)
+	require.Len(t, s[0].Packets, 1)
+
+	// A channel end whose packets were relayed reports none again.
+	m.record(PendingTimeouts{Path: "demo-path", ChainID: "chain-a", ChannelID: "channel-0", PortID: "transfer", Packets: []PendingTimeout{}})
+	require.Empty(t, m.snapshot()[0].Packets)
+}
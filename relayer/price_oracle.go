@@ -0,0 +1,77 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/oracle"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"go.uber.org/zap"
+)
+
+// gasDenoms returns the denoms the fees of c are paid in, according to its gas prices.
+func gasDenoms(c *Chain) []string {
+	cp, ok := c.ChainProvider.(*cosmosprovider.CosmosProvider)
+	if !ok {
+		return nil
+	}
+	gasPrices, err := sdk.ParseDecCoins(cp.PCfg.GasPrices)
+	if err != nil {
+		return nil
+	}
+	denoms := make([]string, 0, len(gasPrices))
+	for _, p := range gasPrices {
+		denoms = append(denoms, p.Denom)
+	}
+	return denoms
+}
+
+// validatePriceOracle returns an error if a path filters transfers by their USD value without a price oracle.
+func (s *supervisor) validatePriceOracle(o oracle.Oracle) error {
+	if o != nil {
+		return nil
+	}
+	for _, name := range s.names {
+		if s.runners[name].packetFilter.MinUSD() > 0 {
+			return fmt.Errorf("path %s filters transfers by their USD value, but no price oracle is configured", name)
+		}
+	}
+	return nil
+}
+
+// startPriceOracle prices in USD the gas tokens of every chain and the tokens transferred over the paths filtering
+// transfers by their USD value, refreshing the prices from o at the given interval until ctx is done.
+func (s *supervisor) startPriceOracle(ctx context.Context, o oracle.Oracle, interval time.Duration) *oracle.Prices {
+	prices := oracle.NewPrices(o)
+	for _, c := range s.chains() {
+		for _, denom := range gasDenoms(c) {
+			prices.Track(c.ChainID(), denom)
+		}
+	}
+	for _, r := range s.runners {
+		if r.packetFilter != nil {
+			r.packetFilter.SetPrices(prices)
+		}
+	}
+
+	log := s.log.With(zap.String("sys", "priceoracle"))
+	s.maintenance.schedule(ctx, TaskPriceOracle, interval, func(ctx context.Context) {
+		prices.Refresh(ctx)
+		for _, p := range prices.Snapshot() {
+			if p.Error != "" {
+				log.Debug(
+					"Failed to price token",
+					zap.String("chain_id", p.ChainID),
+					zap.String("denom", p.Denom),
+					zap.String("error", p.Error),
+				)
+			}
+			if !p.UpdatedAt.IsZero() {
+				s.metrics.SetTokenPrice(p.ChainID, p.Denom, p.USD)
+			}
+		}
+	})
+	return prices
+}
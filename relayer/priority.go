@@ -0,0 +1,60 @@
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"go.uber.org/zap"
+)
+
+// pathPriorities returns whether the fees of the transactions of each path are bumped.
+func (s *supervisor) pathPriorities() []admin.PathPriority {
+	priorities := make([]admin.PathPriority, 0, len(s.names))
+	for _, name := range s.names {
+		p := s.runners[name].priority
+		priorities = append(priorities, admin.PathPriority{Path: name, Enabled: p.Enabled(), FeeMultiplier: p.FeeMultiplier()})
+	}
+	return priorities
+}
+
+// registerPriorityHandlers exposes the priority of each path through the admin API,
+// so that critical paths can be expedited during congestion windows without restarting the relayer.
+//
+//	GET  /priority lists whether the fees of the transactions of each path are bumped.
+//	POST /priority enables or disables the priority of a path.
+func registerPriorityHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/priority", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			admin.WriteJSON(w, http.StatusOK, s.pathPriorities())
+		case http.MethodPost:
+			var body admin.PathPriority
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			r, ok := s.runners[body.Path]
+			if !ok {
+				admin.WriteError(w, http.StatusNotFound, fmt.Errorf("path %s not found", body.Path))
+				return
+			}
+			changed := r.priority.Enabled() != body.Enabled
+			r.priority.SetEnabled(body.Enabled)
+			s.log.Info(
+				"Set path priority",
+				zap.String("path_name", body.Path),
+				zap.Bool("enabled", body.Enabled),
+				zap.Float64("fee_multiplier", r.priority.FeeMultiplier()),
+			)
+			if changed {
+				s.changes.recordPath(r, feed.ChangePriority, feed.CauseAdmin)
+			}
+			admin.WriteJSON(w, http.StatusOK, admin.PathPriority{Path: body.Path, Enabled: body.Enabled, FeeMultiplier: r.priority.FeeMultiplier()})
+		default:
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	})
+}
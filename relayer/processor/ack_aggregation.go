@@ -0,0 +1,108 @@
+package processor
+
+import (
+	"sync"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+)
+
+// AckWindow overrides the aggregation window of the acknowledgements relayed over a channel.
+type AckWindow struct {
+	// PortID and ChannelID identify the channel on the src chain of the path, matched as channel filter entries.
+	PortID, ChannelID string
+
+	// Window is how long the acknowledgements of the channel are aggregated, 0 to relay them right away.
+	Window time.Duration
+}
+
+type ackAggregationKey struct {
+	chainID           string
+	portID, channelID string
+}
+
+// AckAggregator holds back the acknowledgements to relay over each channel of a path until the oldest of them
+// has waited for the aggregation window of the channel, so that they are relayed together in fewer transactions,
+// trading a little latency for less gas on high-throughput channels. Channels are identified from the perspective
+// of the src chain of the path, and a window applies to the acknowledgements of both directions of its channel.
+// It is safe for concurrent use, and a nil *AckAggregator relays acknowledgements right away.
+type AckAggregator struct {
+	srcChainID string
+	window     time.Duration
+	channels   []AckWindow
+	now        func() time.Time
+
+	mu sync.Mutex
+	// oldest is when the oldest acknowledgement held back per channel and direction was first held back.
+	oldest map[ackAggregationKey]time.Time
+}
+
+// NewAckAggregator returns an aggregator holding back the acknowledgements of the path whose src chain is srcChainID
+// for window, or the window of the first entry of channels matching their channel.
+func NewAckAggregator(srcChainID string, window time.Duration, channels []AckWindow) *AckAggregator {
+	return &AckAggregator{
+		srcChainID: srcChainID,
+		window:     window,
+		channels:   channels,
+		now:        time.Now,
+		oldest:     make(map[ackAggregationKey]time.Time),
+	}
+}
+
+// Window returns the aggregation window of the channel identified by portID and channelID on the src chain.
+func (a *AckAggregator) Window(portID, channelID string) time.Duration {
+	if a == nil {
+		return 0
+	}
+	for _, c := range a.channels {
+		if (c.PortID == "" || MatchChannelPattern(c.PortID, portID)) && MatchChannelPattern(c.ChannelID, channelID) {
+			return c.Window
+		}
+	}
+	return a.window
+}
+
+// hold returns the messages of msgs to relay to chainID over the channel identified by k from its perspective,
+// holding back its acknowledgements until the oldest of them has waited for the window of the channel,
+// and how long until they are released if they are held back.
+func (a *AckAggregator) hold(chainID string, k ChannelKey, msgs []packetIBCMessage) ([]packetIBCMessage, time.Duration) {
+	if a == nil {
+		return msgs, 0
+	}
+	key := ackAggregationKey{chainID: chainID, portID: k.PortID, channelID: k.ChannelID}
+	window := a.Window(k.PortID, k.ChannelID)
+	if chainID != a.srcChainID {
+		window = a.Window(k.CounterpartyPortID, k.CounterpartyChannelID)
+	}
+
+	var acks int
+	for _, m := range msgs {
+		if m.eventType == chantypes.EventTypeAcknowledgePacket {
+			acks++
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if acks == 0 || window <= 0 {
+		delete(a.oldest, key)
+		return msgs, 0
+	}
+	now := a.now()
+	oldest, ok := a.oldest[key]
+	if !ok {
+		oldest = now
+		a.oldest[key] = now
+	}
+	if waited := now.Sub(oldest); waited < window {
+		res := make([]packetIBCMessage, 0, len(msgs)-acks)
+		for _, m := range msgs {
+			if m.eventType != chantypes.EventTypeAcknowledgePacket {
+				res = append(res, m)
+			}
+		}
+		return res, window - waited
+	}
+	delete(a.oldest, key)
+	return msgs, 0
+}
@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckAggregator(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	a := NewAckAggregator("chain-a", 5*time.Second, []AckWindow{{PortID: "transfer", ChannelID: "channel-1", Window: 0}})
+	a.now = func() time.Time { return now }
+
+	k := ChannelKey{PortID: "transfer", ChannelID: "channel-0", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-7"}
+	ack := packetIBCMessage{eventType: chantypes.EventTypeAcknowledgePacket}
+	recv := packetIBCMessage{eventType: chantypes.EventTypeRecvPacket}
+
+	// acknowledgements are held back until the oldest of them waited for the window
+	msgs, wait := a.hold("chain-a", k, []packetIBCMessage{ack, recv})
+	require.Equal(t, []packetIBCMessage{recv}, msgs)
+	require.Equal(t, 5*time.Second, wait)
+
+	now = now.Add(3 * time.Second)
+	msgs, wait = a.hold("chain-a", k, []packetIBCMessage{ack, ack})
+	require.Empty(t, msgs)
+	require.Equal(t, 2*time.Second, wait)
+
+	now = now.Add(2 * time.Second)
+	msgs, wait = a.hold("chain-a", k, []packetIBCMessage{ack, ack, ack})
+	require.Len(t, msgs, 3)
+	require.Zero(t, wait)
+
+	// each direction has its own window, taken from the channel on the src chain
+	msgs, _ = a.hold("chain-b", k.Counterparty(), []packetIBCMessage{ack})
+	require.Empty(t, msgs)
+
+	// channels overriding the window relay right away
+	k1 := ChannelKey{PortID: "transfer", ChannelID: "channel-1"}
+	msgs, wait = a.hold("chain-a", k1, []packetIBCMessage{ack})
+	require.Len(t, msgs, 1)
+	require.Zero(t, wait)
+
+	// as does a nil aggregator
+	var none *AckAggregator
+	msgs, _ = none.hold("chain-a", k, []packetIBCMessage{ack})
+	require.Len(t, msgs, 1)
+}
@@ -0,0 +1,281 @@
+package processor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Defaults of the backfill of the initial block history.
+const (
+	DefaultBackfillChunkSize   = 500
+	DefaultBackfillMaxInFlight = 8
+)
+
+// Backfill bounds the backfill of the initial block history by the ChainProcessors, which query at most
+// MaxInFlight blocks at once and hand the blocks over to their PathProcessors ChunkSize blocks at a time,
+// waiting for the PathProcessors to take each chunk before querying the next one, so that large block
+// histories neither pile up in memory nor flood the RPC node.
+// Zero values stand for the defaults.
+type Backfill struct {
+	ChunkSize   uint64
+	MaxInFlight int
+
+	// Checkpoints persists the progress of the backfill of each chain, if non-nil, so that a restarted
+	// relayer resumes the backfill instead of querying the blocks it already handled again.
+	Checkpoints *BackfillCheckpoints
+
+	// Progress tracks the progress of the backfill of each chain, if non-nil, for the status API.
+	Progress *SyncProgress
+
+	// EarliestHeights are the earliest blocks the endpoints of the chains keep, keyed by chain ID,
+	// before which the block history is not queried.
+	EarliestHeights map[string]int64
+}
+
+// WithDefaults returns b with the defaults in place of its zero values.
+func (b Backfill) WithDefaults() Backfill {
+	if b.ChunkSize == 0 {
+		b.ChunkSize = DefaultBackfillChunkSize
+	}
+	if b.MaxInFlight <= 0 {
+		b.MaxInFlight = DefaultBackfillMaxInFlight
+	}
+	return b
+}
+
+// Start returns the latest block already handled by a chain with the given latest height before its query loop
+// starts, looking back initialBlockHistory blocks unless the checkpoint of the chain resumes an interrupted
+// backfill further along its block history, in which case resumed is true.
+// The block history is cut short by the earliest block the endpoint of the chain keeps, if known.
+func (b Backfill) Start(chainID string, latestHeight int64, initialBlockHistory uint64) (start int64, resumed bool) {
+	start = latestHeight - int64(initialBlockHistory)
+	if start < 0 {
+		start = 0
+	}
+	if earliest, ok := b.EarliestHeights[chainID]; ok && start < earliest-1 && earliest-1 <= latestHeight {
+		start = earliest - 1
+	}
+	if cp, ok := b.Checkpoints.Height(chainID); ok && cp > start && cp <= latestHeight {
+		return cp, true
+	}
+	return start, false
+}
+
+// ChunkEnd returns the last block of the chunk of the block history starting after latestQueriedBlock.
+func (b Backfill) ChunkEnd(latestQueriedBlock, latestHeight int64) int64 {
+	chunkSize := b.WithDefaults().ChunkSize
+	if latestHeight-latestQueriedBlock > int64(chunkSize) {
+		return latestQueriedBlock + int64(chunkSize)
+	}
+	return latestHeight
+}
+
+// Checkpoint records the progress of the backfill of the chain once the PathProcessors took the blocks
+// up to latestQueriedBlock, and clears it once the chain is in sync. Failing to persist it is only logged,
+// the backfill carrying on regardless.
+func (b Backfill) Checkpoint(log *zap.Logger, chainID string, latestQueriedBlock int64, inSync bool) {
+	var err error
+	if inSync {
+		err = b.Checkpoints.Clear(chainID)
+	} else {
+		err = b.Checkpoints.Set(chainID, latestQueriedBlock)
+	}
+	if err != nil {
+		log.Warn("Failed to checkpoint block history backfill", zap.Int64("latest_queried_block", latestQueriedBlock), zap.Error(err))
+	}
+}
+
+// BackfillProgress logs the progress of the backfill of the block history of a chain, from the block it started
+// after to the latest height of the chain, with an estimate of the time left until the chain is in sync,
+// and reports it to the SyncProgress of the Backfill, if any.
+type BackfillProgress struct {
+	chainID   string
+	tracker   *SyncProgress
+	start     int64
+	startTime time.Time
+
+	// remaining is the number of blocks left when the backfill started, the rate at which the backlog shrinks,
+	// rather than the rate at which blocks are queried, telling how long until the chain is caught up.
+	remaining int64
+
+	latestQueriedBlock int64
+	lastProgress       time.Time
+}
+
+// NewBackfillProgress starts logging the progress of the backfill of the chain starting after block start,
+// latestHeight being the latest height of the chain then, reporting it to tracker if non-nil.
+func NewBackfillProgress(chainID string, start, latestHeight int64, tracker *SyncProgress) *BackfillProgress {
+	now := time.Now()
+	p := &BackfillProgress{
+		chainID:            chainID,
+		tracker:            tracker,
+		start:              start,
+		startTime:          now,
+		remaining:          latestHeight - start,
+		latestQueriedBlock: start,
+		lastProgress:       now,
+	}
+	p.report(start, latestHeight, false)
+	return p
+}
+
+// Log logs the progress of the backfill, having handled the blocks up to latestQueriedBlock.
+func (p *BackfillProgress) Log(log *zap.Logger, latestQueriedBlock, latestHeight int64) {
+	s := p.report(latestQueriedBlock, latestHeight, false)
+	fields := []zap.Field{
+		zap.Int64("latest_queried_block", latestQueriedBlock),
+		zap.Int64("latest_height", latestHeight),
+		zap.Int64("remaining_blocks", s.RemainingBlocks),
+		zap.String("progress", fmt.Sprintf("%.1f%%", s.Percent)),
+	}
+	if s.BlocksPerSecond > 0 {
+		fields = append(fields, zap.Float64("blocks_per_second", s.BlocksPerSecond))
+	}
+	if s.ETASeconds != nil {
+		fields = append(fields, zap.Duration("eta", time.Duration(*s.ETASeconds*float64(time.Second)).Round(time.Second)))
+	} else {
+		fields = append(fields, zap.String("eta", "unknown, not catching up"))
+	}
+	fields = append(fields, zap.Duration("since_last_progress", time.Since(p.lastProgress).Round(time.Second)))
+	log.Info("Backfilling block history", fields...)
+}
+
+// InSync records the completion of the backfill, the chain being in sync having handled the blocks
+// up to latestQueriedBlock.
+func (p *BackfillProgress) InSync(latestQueriedBlock, latestHeight int64) {
+	p.report(latestQueriedBlock, latestHeight, true)
+}
+
+// report computes the progress of the backfill and reports it to the tracker.
+func (p *BackfillProgress) report(latestQueriedBlock, latestHeight int64, inSync bool) ChainSyncProgress {
+	now := time.Now()
+	if latestQueriedBlock > p.latestQueriedBlock {
+		p.latestQueriedBlock = latestQueriedBlock
+		p.lastProgress = now
+	}
+	s := ChainSyncProgress{
+		ChainID:            p.chainID,
+		InSync:             inSync,
+		StartHeight:        p.start,
+		LatestQueriedBlock: latestQueriedBlock,
+		LatestHeight:       latestHeight,
+		RemainingBlocks:    latestHeight - latestQueriedBlock,
+		Percent:            100,
+		StartedAt:          p.startTime,
+		LastProgressAt:     p.lastProgress,
+	}
+	if total := latestHeight - p.start; total > 0 && !inSync {
+		s.Percent = 100 * float64(latestQueriedBlock-p.start) / float64(total)
+	}
+	if elapsed := now.Sub(p.startTime).Seconds(); elapsed > 0 {
+		if done := latestQueriedBlock - p.start; done > 0 {
+			s.BlocksPerSecond = float64(done) / elapsed
+		}
+		if !inSync {
+			if caughtUp := p.remaining - s.RemainingBlocks; caughtUp > 0 {
+				eta := float64(s.RemainingBlocks) / (float64(caughtUp) / elapsed)
+				s.ETASeconds = &eta
+			}
+		}
+	}
+	p.tracker.set(s)
+	return s
+}
+
+// backfiller is implemented by the ChainProcessors supporting a bounded backfill of their block history.
+type backfiller interface {
+	SetBackfill(Backfill)
+}
+
+// BackfillCheckpoints persists, for each chain, the latest block the backfill of its block history handled,
+// in a JSON file.
+// A nil BackfillCheckpoints persists nothing.
+type BackfillCheckpoints struct {
+	mu      sync.Mutex
+	file    string
+	heights map[string]int64
+}
+
+// OpenBackfillCheckpoints loads the checkpoints persisted in file, which is created once a checkpoint is set.
+func OpenBackfillCheckpoints(file string) (*BackfillCheckpoints, error) {
+	c := &BackfillCheckpoints{file: file, heights: make(map[string]int64)}
+	bz, err := os.ReadFile(file)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return c, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read backfill checkpoints: %w", err)
+	}
+	if err := json.Unmarshal(bz, &c.heights); err != nil {
+		return nil, fmt.Errorf("failed to decode backfill checkpoints at %s: %w", file, err)
+	}
+	return c, nil
+}
+
+// Height returns the latest block handled by the backfill of the chain, if it was checkpointed.
+func (c *BackfillCheckpoints) Height(chainID string) (int64, bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := c.heights[chainID]
+	return h, ok
+}
+
+// Set checkpoints the latest block handled by the backfill of the chain.
+func (c *BackfillCheckpoints) Set(chainID string, height int64) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.heights[chainID] = height
+	return c.save()
+}
+
+// Clear deletes the checkpoint of the chain, once its backfill completed.
+func (c *BackfillCheckpoints) Clear(chainID string) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.heights[chainID]; !ok {
+		return nil
+	}
+	delete(c.heights, chainID)
+	return c.save()
+}
+
+// save replaces the file of the checkpoints, through a temporary file so that it is never left half written.
+func (c *BackfillCheckpoints) save() error {
+	bz, err := json.Marshal(c.heights)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.file), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.file), filepath.Base(c.file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.file)
+}
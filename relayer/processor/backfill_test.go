@@ -0,0 +1,108 @@
+package processor_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+)
+
+func TestBackfillChunks(t *testing.T) {
+	b := processor.Backfill{ChunkSize: 100}
+	require.Equal(t, int64(100), b.ChunkEnd(0, 1000))
+	require.Equal(t, int64(1000), b.ChunkEnd(950, 1000))
+
+	b = processor.Backfill{}.WithDefaults()
+	require.Equal(t, uint64(processor.DefaultBackfillChunkSize), b.ChunkSize)
+	require.Equal(t, processor.DefaultBackfillMaxInFlight, b.MaxInFlight)
+
+	start, resumed := b.Start("chain-a", 1000, 100)
+	require.Equal(t, int64(900), start)
+	require.False(t, resumed)
+	start, _ = b.Start("chain-a", 50, 100)
+	require.Equal(t, int64(0), start)
+
+	// endpoints which pruned their block history are queried from their earliest block
+	b.EarliestHeights = map[string]int64{"chain-a": 951}
+	start, _ = b.Start("chain-a", 1000, 100)
+	require.Equal(t, int64(950), start)
+	start, _ = b.Start("chain-b", 1000, 100)
+	require.Equal(t, int64(900), start)
+}
+
+func TestBackfillCheckpoints(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data", "backfill.json")
+	cp, err := processor.OpenBackfillCheckpoints(file)
+	require.NoError(t, err)
+	b := processor.Backfill{Checkpoints: cp}
+
+	b.Checkpoint(zap.NewNop(), "chain-a", 500, false)
+	b.Checkpoint(zap.NewNop(), "chain-b", 700, false)
+
+	// a restarted relayer resumes the backfill further along the block history
+	cp, err = processor.OpenBackfillCheckpoints(file)
+	require.NoError(t, err)
+	b = processor.Backfill{Checkpoints: cp}
+	start, resumed := b.Start("chain-a", 1000, 600)
+	require.Equal(t, int64(500), start)
+	require.True(t, resumed)
+
+	// but not from a checkpoint older than the requested block history
+	start, resumed = b.Start("chain-b", 1000, 100)
+	require.Equal(t, int64(900), start)
+	require.False(t, resumed)
+
+	// checkpoints are cleared once the chain is in sync
+	b.Checkpoint(zap.NewNop(), "chain-a", 1000, true)
+	cp, err = processor.OpenBackfillCheckpoints(file)
+	require.NoError(t, err)
+	_, ok := cp.Height("chain-a")
+	require.False(t, ok)
+	h, ok := cp.Height("chain-b")
+	require.True(t, ok)
+	require.Equal(t, int64(700), h)
+
+	var nilCheckpoints *processor.BackfillCheckpoints
+	require.NoError(t, nilCheckpoints.Set("chain-a", 1))
+	_, ok = nilCheckpoints.Height("chain-a")
+	require.False(t, ok)
+}
+
+func TestBackfillProgress(t *testing.T) {
+	tracker := processor.NewSyncProgress()
+	p := processor.NewBackfillProgress("chain-a", 0, 1000, tracker)
+	s := tracker.Snapshot()
+	require.Len(t, s, 1)
+	require.False(t, s[0].InSync)
+	require.Equal(t, int64(1000), s[0].RemainingBlocks)
+	require.Nil(t, s[0].ETASeconds)
+
+	time.Sleep(10 * time.Millisecond)
+	p.Log(zap.NewNop(), 500, 1000)
+	s = tracker.Snapshot()
+	require.Equal(t, 50.0, s[0].Percent)
+	require.Equal(t, int64(500), s[0].RemainingBlocks)
+	require.NotNil(t, s[0].ETASeconds)
+	require.Greater(t, *s[0].ETASeconds, 0.0)
+
+	// the chain producing blocks as fast as they are queried is not catching up
+	p.Log(zap.NewNop(), 1000, 2000)
+	s = tracker.Snapshot()
+	require.Equal(t, 50.0, s[0].Percent)
+	require.Nil(t, s[0].ETASeconds)
+
+	p.InSync(2000, 2001)
+	s = tracker.Snapshot()
+	require.True(t, s[0].InSync)
+	require.Equal(t, 100.0, s[0].Percent)
+	require.Nil(t, s[0].ETASeconds)
+
+	// a nil tracker tracks nothing
+	processor.NewBackfillProgress("chain-b", 0, 1000, nil).Log(zap.NewNop(), 10, 1000)
+	var nilTracker *processor.SyncProgress
+	require.Empty(t, nilTracker.Snapshot())
+}
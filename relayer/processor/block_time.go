@@ -0,0 +1,146 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBlockTime is assumed for chains that do not have enough samples for an estimate yet.
+	DefaultBlockTime = 6 * time.Second
+
+	defaultBlockTimeSampleInterval = 10 * time.Second
+	defaultBlockTimeWindow         = 30
+	blockTimeQueryTimeout          = 5 * time.Second
+)
+
+type blockTimeSample struct {
+	height int64
+	time   time.Time
+}
+
+// BlockTimeEstimate is a point in time estimate of the block time of a chain.
+type BlockTimeEstimate struct {
+	ChainID      string  `json:"chain_id"`
+	Seconds      float64 `json:"block_time_seconds"`
+	Samples      int     `json:"samples"`
+	LatestHeight int64   `json:"latest_height"`
+}
+
+// BlockTimeEstimator maintains a rolling estimate of the block time of each chain,
+// based on the block timestamps of the most recently observed heights.
+type BlockTimeEstimator struct {
+	log    *zap.Logger
+	window int
+
+	mu      sync.RWMutex
+	samples map[string][]blockTimeSample
+}
+
+// NewBlockTimeEstimator returns a BlockTimeEstimator with no samples.
+func NewBlockTimeEstimator(log *zap.Logger) *BlockTimeEstimator {
+	return &BlockTimeEstimator{
+		log:     log,
+		window:  defaultBlockTimeWindow,
+		samples: make(map[string][]blockTimeSample),
+	}
+}
+
+// Observe records the timestamp of the block at the given height.
+// Observations for heights that are not newer than the latest sample are ignored.
+func (e *BlockTimeEstimator) Observe(chainID string, height int64, t time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	samples := e.samples[chainID]
+	if len(samples) > 0 && height <= samples[len(samples)-1].height {
+		return
+	}
+	samples = append(samples, blockTimeSample{height: height, time: t})
+	if len(samples) > e.window {
+		samples = samples[len(samples)-e.window:]
+	}
+	e.samples[chainID] = samples
+}
+
+// Estimate returns the average block time of the chain over the sample window,
+// or false if there are not enough samples for an estimate yet.
+func (e *BlockTimeEstimator) Estimate(chainID string) (time.Duration, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return estimateBlockTime(e.samples[chainID])
+}
+
+// EstimateOrDefault returns the estimated block time of the chain, or DefaultBlockTime if there is no estimate yet.
+func (e *BlockTimeEstimator) EstimateOrDefault(chainID string) time.Duration {
+	if d, ok := e.Estimate(chainID); ok {
+		return d
+	}
+	return DefaultBlockTime
+}
+
+// Estimates returns the current estimate for every chain that has been observed.
+func (e *BlockTimeEstimator) Estimates() []BlockTimeEstimate {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	estimates := make([]BlockTimeEstimate, 0, len(e.samples))
+	for chainID, samples := range e.samples {
+		estimate := BlockTimeEstimate{
+			ChainID:      chainID,
+			Samples:      len(samples),
+			LatestHeight: samples[len(samples)-1].height,
+		}
+		if d, ok := estimateBlockTime(samples); ok {
+			estimate.Seconds = d.Seconds()
+		}
+		estimates = append(estimates, estimate)
+	}
+	return estimates
+}
+
+func estimateBlockTime(samples []blockTimeSample) (time.Duration, bool) {
+	if len(samples) < 2 {
+		return 0, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	return last.time.Sub(first.time) / time.Duration(last.height-first.height), true
+}
+
+// Run periodically samples the latest block of each chain until ctx is cancelled.
+func (e *BlockTimeEstimator) Run(ctx context.Context, chains ...provider.ChainProvider) {
+	ticker := time.NewTicker(defaultBlockTimeSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, c := range chains {
+			e.sample(ctx, c)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *BlockTimeEstimator) sample(ctx context.Context, c provider.ChainProvider) {
+	ctx, cancel := context.WithTimeout(ctx, blockTimeQueryTimeout)
+	defer cancel()
+
+	height, err := c.QueryLatestHeight(ctx)
+	if err != nil {
+		e.log.Debug("Failed to query latest height for block time estimate", zap.String("chain_id", c.ChainId()), zap.Error(err))
+		return
+	}
+	blockTime, err := c.BlockTime(ctx, height)
+	if err != nil {
+		e.log.Debug("Failed to query block time for block time estimate", zap.String("chain_id", c.ChainId()), zap.Error(err))
+		return
+	}
+	e.Observe(c.ChainId(), height, time.Unix(0, blockTime))
+}
@@ -0,0 +1,41 @@
+package processor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+)
+
+func TestBlockTimeEstimator(t *testing.T) {
+	e := processor.NewBlockTimeEstimator(zap.NewNop())
+
+	_, ok := e.Estimate("chain-a")
+	require.False(t, ok)
+	require.Equal(t, processor.DefaultBlockTime, e.EstimateOrDefault("chain-a"))
+
+	start := time.Unix(1000, 0)
+	e.Observe("chain-a", 10, start)
+	_, ok = e.Estimate("chain-a")
+	require.False(t, ok, "a single sample is not enough for an estimate")
+
+	e.Observe("chain-a", 20, start.Add(20*time.Second))
+	// stale heights are ignored
+	e.Observe("chain-a", 15, start.Add(time.Hour))
+
+	d, ok := e.Estimate("chain-a")
+	require.True(t, ok)
+	require.Equal(t, 2*time.Second, d)
+
+	estimates := e.Estimates()
+	require.Len(t, estimates, 1)
+	require.Equal(t, processor.BlockTimeEstimate{
+		ChainID:      "chain-a",
+		Seconds:      2,
+		Samples:      2,
+		LatestHeight: 20,
+	}, estimates[0])
+}
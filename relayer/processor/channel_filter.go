@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Channel and port IDs in channel filters are patterns: either an identifier, matched exactly,
+// a glob pattern as accepted by path.Match, e.g. "channel-*", or a regular expression delimited by slashes,
+// e.g. "/^channel-(0|12)$/".
+
+// compiledPatterns caches the regular expressions of channel filters, by pattern.
+var compiledPatterns sync.Map
+
+func isRegexPattern(pattern string) bool {
+	return len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/")
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := compiledPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+	if err != nil {
+		return nil, err
+	}
+	compiledPatterns.Store(pattern, re)
+	return re, nil
+}
+
+// ValidateChannelPattern returns an error if pattern is not a valid channel or port ID pattern.
+func ValidateChannelPattern(pattern string) error {
+	if isRegexPattern(pattern) {
+		if _, err := compilePattern(pattern); err != nil {
+			return fmt.Errorf("invalid regular expression %s: %w", pattern, err)
+		}
+		return nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	return nil
+}
+
+// MatchChannelPattern returns true if the channel or port ID id matches pattern.
+// An empty ID never matches, and an invalid pattern matches nothing.
+func MatchChannelPattern(pattern, id string) bool {
+	if id == "" {
+		return false
+	}
+	if isRegexPattern(pattern) {
+		re, err := compilePattern(pattern)
+		return err == nil && re.MatchString(id)
+	}
+	matched, err := path.Match(pattern, id)
+	return err == nil && matched
+}
@@ -0,0 +1,580 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// decisionBundleVersion is the version of the format of decision bundles, bumped on incompatible changes.
+const decisionBundleVersion = 1
+
+// Outcomes of the validation of a packet against the latest block of its destination chain, see DecisionBundle.
+const (
+	validationTimeoutHeight    = "timeout_height"
+	validationTimeoutTimestamp = "timeout_timestamp"
+	validationTimeoutOnClose   = "timeout_on_close"
+	validationInvalidPrefix    = "invalid: "
+)
+
+// DecisionBundle holds all the inputs of a single packet flow decision of a path processor, i.e. which packets,
+// acknowledgements and timeouts of a channel to relay, and the decision that was taken.
+// It is meant to be attached to bug reports, and replayed in tests with ReplayDecision.
+type DecisionBundle struct {
+	Version    int       `json:"version"`
+	CapturedAt time.Time `json:"captured_at"`
+	Path       string    `json:"path,omitempty"`
+
+	// Channel is the channel the packets were sent over, from the perspective of the src chain.
+	Channel DecisionChannel `json:"channel"`
+
+	Src DecisionPathEnd `json:"src"`
+	Dst DecisionPathEnd `json:"dst"`
+
+	// The packet flow observed by the path processor: the packets sent on the src chain, received on the dst chain,
+	// and acknowledged or timed out on the src chain.
+	SendPackets      []DecisionPacket `json:"send_packets"`
+	RecvPackets      []DecisionPacket `json:"recv_packets"`
+	Acknowledgements []DecisionPacket `json:"acknowledgements"`
+	Timeouts         []DecisionPacket `json:"timeouts"`
+	TimeoutsOnClose  []DecisionPacket `json:"timeouts_on_close"`
+
+	// Validations holds, by sequence, why the dst chain provider refused to relay a sent packet, or nothing
+	// if it is valid: timeout_height, timeout_timestamp, timeout_on_close, or "invalid: " and the error.
+	Validations map[uint64]string `json:"validations,omitempty"`
+
+	// PacketFilter is the packet filter of the path, if any.
+	PacketFilter *DecisionPacketFilter `json:"packet_filter,omitempty"`
+
+	// RateLimited holds, by sequence, why the rate limiter of the path held back a packet.
+	// The rate limiter counts the transfers relayed over time, so only its verdicts are captured.
+	RateLimited map[uint64]string `json:"rate_limited,omitempty"`
+
+	Decision Decision `json:"decision"`
+}
+
+// DecisionChannel identifies a channel from the perspective of one of its ends.
+type DecisionChannel struct {
+	PortID                string `json:"port_id"`
+	ChannelID             string `json:"channel_id"`
+	CounterpartyPortID    string `json:"counterparty_port_id"`
+	CounterpartyChannelID string `json:"counterparty_channel_id"`
+}
+
+// DecisionPathEnd is the state of a path end the decision was taken on.
+type DecisionPathEnd struct {
+	ChainID      string    `json:"chain_id"`
+	ClientID     string    `json:"client_id"`
+	LatestHeight uint64    `json:"latest_height"`
+	LatestTime   time.Time `json:"latest_time"`
+
+	// FinalizedHeight is the latest height of the chain finalized on its settlement layer, -1 if none,
+	// set if the packets of the chain are gated on finality.
+	FinalizedHeight *int64 `json:"finalized_height,omitempty"`
+
+	// Preconfirmations is set if the packets of the chain are relayed before they are finalized.
+	Preconfirmations bool `json:"preconfirmations,omitempty"`
+
+	// Confirmations is the number of blocks built since the block of a packet of the chain before it is relayed.
+	Confirmations uint64 `json:"confirmations,omitempty"`
+
+	// ChannelOpen is whether the channel is open on the chain.
+	ChannelOpen bool `json:"channel_open"`
+
+	// InProgress are the messages of the channel sent to the chain and not yet observed on it.
+	InProgress []DecisionInProgress `json:"in_progress,omitempty"`
+}
+
+// DecisionInProgress is a packet message being relayed to a chain.
+type DecisionInProgress struct {
+	EventType           string `json:"event_type"`
+	Sequence            uint64 `json:"sequence"`
+	Assembled           bool   `json:"assembled"`
+	LastProcessedHeight uint64 `json:"last_processed_height"`
+	RetryCount          uint64 `json:"retry_count"`
+}
+
+// DecisionPacket is a packet observed by the path processor.
+type DecisionPacket struct {
+	Height           uint64             `json:"height"`
+	Sequence         uint64             `json:"sequence"`
+	SourcePort       string             `json:"source_port"`
+	SourceChannel    string             `json:"source_channel"`
+	DestPort         string             `json:"dest_port"`
+	DestChannel      string             `json:"dest_channel"`
+	Data             []byte             `json:"data,omitempty"`
+	TimeoutHeight    clienttypes.Height `json:"timeout_height"`
+	TimeoutTimestamp uint64             `json:"timeout_timestamp,omitempty"`
+	Ack              []byte             `json:"ack,omitempty"`
+}
+
+// DecisionPacketFilter is the configuration of a packet filter, with the USD prices of the denoms of the packets sent.
+type DecisionPacketFilter struct {
+	MinAmounts map[string]string  `json:"min_amounts,omitempty"`
+	DenyDenoms []string           `json:"deny_denoms,omitempty"`
+	MinUSD     float64            `json:"min_usd,omitempty"`
+	Prices     map[string]float64 `json:"prices,omitempty"`
+
+	// MemoChainID is the src chain of the path the channels of the MemoPolicies are identified on.
+	MemoChainID  string       `json:"memo_chain_id,omitempty"`
+	MemoPolicies []MemoPolicy `json:"memo_policies,omitempty"`
+}
+
+// Decision is the outcome of a packet flow decision: the messages to relay to each chain,
+// and the cached packet flow messages to forget as their flow is complete.
+type Decision struct {
+	SrcMessages []DecisionMessage   `json:"src_messages"`
+	DstMessages []DecisionMessage   `json:"dst_messages"`
+	ToDeleteSrc map[string][]uint64 `json:"to_delete_src,omitempty"`
+	ToDeleteDst map[string][]uint64 `json:"to_delete_dst,omitempty"`
+}
+
+// DecisionMessage is a packet message decided to be relayed.
+type DecisionMessage struct {
+	EventType string `json:"event_type"`
+	Sequence  uint64 `json:"sequence"`
+}
+
+// DecisionCapture captures packet flow decisions of the path processor it is set on, see PathProcessor.SetDecisionCapture.
+type DecisionCapture struct {
+	requests chan decisionRequest
+}
+
+type decisionRequest struct {
+	chainID           string
+	portID, channelID string
+	res               chan decisionResult
+}
+
+type decisionResult struct {
+	bundle *DecisionBundle
+	err    error
+}
+
+// NewDecisionCapture returns a capture to set on a path processor.
+func NewDecisionCapture() *DecisionCapture {
+	return &DecisionCapture{requests: make(chan decisionRequest)}
+}
+
+// Capture returns the bundle of the next decision the path processor takes on the packets sent from chainID
+// over the channel portID/channelID. It blocks until the path processor next processes its messages, or ctx is done.
+func (c *DecisionCapture) Capture(ctx context.Context, chainID, portID, channelID string) (*DecisionBundle, error) {
+	req := decisionRequest{chainID: chainID, portID: portID, channelID: channelID, res: make(chan decisionResult, 1)}
+	select {
+	case c.requests <- req:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("path processor did not process messages in time: %w", ctx.Err())
+	}
+	select {
+	case res := <-req.res:
+		return res.bundle, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// pending returns the capture requests waiting for the path processor, without blocking.
+func (c *DecisionCapture) pending() []decisionRequest {
+	if c == nil {
+		return nil
+	}
+	var reqs []decisionRequest
+	for {
+		select {
+		case req := <-c.requests:
+			reqs = append(reqs, req)
+		default:
+			return reqs
+		}
+	}
+}
+
+// decisionRecorder records the verdicts of the rate limiter of the path processor for a captured decision.
+type decisionRecorder struct {
+	packetAllower
+	verdicts map[uint64]string
+}
+
+func (r *decisionRecorder) Allow(chainID string, k ChannelKey, seq uint64, data []byte) string {
+	reason := r.packetAllower.Allow(chainID, k, seq, data)
+	if reason != "" {
+		r.verdicts[seq] = reason
+	}
+	return reason
+}
+
+// decidePacketFlow takes the packet flow decision on m, capturing it for the requests of captures for its channel.
+func (pp *PathProcessor) decidePacketFlow(ctx context.Context, m pathEndPacketFlowMessages, captures []decisionRequest) pathEndPacketFlowResponse {
+	var reqs []decisionRequest
+	for _, req := range captures {
+		if req.chainID == m.Src.info.ChainID && req.portID == m.ChannelKey.PortID && req.channelID == m.ChannelKey.ChannelID {
+			reqs = append(reqs, req)
+		}
+	}
+	if len(reqs) == 0 {
+		return pp.getUnrelayedPacketsAndAcksAndToDelete(ctx, m)
+	}
+	res, b := pp.captureDecision(ctx, m)
+	for _, req := range reqs {
+		req.res <- decisionResult{bundle: b}
+	}
+	return res
+}
+
+// captureDecision takes the packet flow decision on m, capturing its inputs and outcome into a bundle.
+func (pp *PathProcessor) captureDecision(ctx context.Context, m pathEndPacketFlowMessages) (pathEndPacketFlowResponse, *DecisionBundle) {
+	b := &DecisionBundle{
+		Version:    decisionBundleVersion,
+		CapturedAt: time.Now().UTC(),
+		Path:       pp.pathName,
+		Channel: DecisionChannel{
+			PortID:                m.ChannelKey.PortID,
+			ChannelID:             m.ChannelKey.ChannelID,
+			CounterpartyPortID:    m.ChannelKey.CounterpartyPortID,
+			CounterpartyChannelID: m.ChannelKey.CounterpartyChannelID,
+		},
+		Src:              pp.capturePathEnd(m.Src, m.ChannelKey),
+		Dst:              pp.capturePathEnd(m.Dst, m.ChannelKey.Counterparty()),
+		SendPackets:      captureDecisionPackets(m.SrcMsgTransfer),
+		RecvPackets:      captureDecisionPackets(m.DstMsgRecvPacket),
+		Acknowledgements: captureDecisionPackets(m.SrcMsgAcknowledgement),
+		Timeouts:         captureDecisionPackets(m.SrcMsgTimeout),
+		TimeoutsOnClose:  captureDecisionPackets(m.SrcMsgTimeoutOnClose),
+		Validations:      make(map[uint64]string),
+		RateLimited:      make(map[uint64]string),
+	}
+	for seq, p := range m.SrcMsgTransfer {
+		if err := m.Dst.chainProvider.ValidatePacket(p, m.Dst.latestBlock); err != nil {
+			b.Validations[seq] = validationOutcome(err)
+		}
+	}
+	if pp.packetFilter != nil {
+		b.PacketFilter = captureDecisionPacketFilter(pp.packetFilter, m.Src.info.ChainID, m.SrcMsgTransfer)
+	}
+
+	res := pp.packetFlowDecision(ctx, m, pp.packetFilter, &decisionRecorder{packetAllower: pp.rateLimiter, verdicts: b.RateLimited})
+	b.Decision = newDecision(res)
+	return res, b
+}
+
+func (pp *PathProcessor) capturePathEnd(pathEnd *pathEndRuntime, k ChannelKey) DecisionPathEnd {
+	pe := DecisionPathEnd{
+		ChainID:          pathEnd.info.ChainID,
+		ClientID:         pathEnd.info.ClientID,
+		LatestHeight:     pathEnd.latestBlock.Height,
+		LatestTime:       pathEnd.latestBlock.Time,
+		Preconfirmations: pp.preconfirms(pathEnd),
+		Confirmations:    pathEnd.confirmations,
+		ChannelOpen:      pathEnd.channelStateCache[k],
+	}
+	if pathEnd.finalityGater != nil {
+		h := pathEnd.finalizedHeight
+		pe.FinalizedHeight = &h
+	}
+	for eventType, msgs := range pathEnd.packetProcessing[k] {
+		for seq, m := range msgs {
+			pe.InProgress = append(pe.InProgress, DecisionInProgress{
+				EventType:           eventType,
+				Sequence:            seq,
+				Assembled:           m.assembled,
+				LastProcessedHeight: m.lastProcessedHeight,
+				RetryCount:          m.retryCount,
+			})
+		}
+	}
+	sort.Slice(pe.InProgress, func(i, j int) bool {
+		if pe.InProgress[i].Sequence != pe.InProgress[j].Sequence {
+			return pe.InProgress[i].Sequence < pe.InProgress[j].Sequence
+		}
+		return pe.InProgress[i].EventType < pe.InProgress[j].EventType
+	})
+	return pe
+}
+
+func captureDecisionPackets(c PacketSequenceCache) []DecisionPacket {
+	packets := make([]DecisionPacket, 0, len(c))
+	for _, p := range c {
+		packets = append(packets, DecisionPacket{
+			Height:           p.Height,
+			Sequence:         p.Sequence,
+			SourcePort:       p.SourcePort,
+			SourceChannel:    p.SourceChannel,
+			DestPort:         p.DestPort,
+			DestChannel:      p.DestChannel,
+			Data:             p.Data,
+			TimeoutHeight:    p.TimeoutHeight,
+			TimeoutTimestamp: p.TimeoutTimestamp,
+			Ack:              p.Ack,
+		})
+	}
+	sort.Slice(packets, func(i, j int) bool { return packets[i].Sequence < packets[j].Sequence })
+	return packets
+}
+
+func captureDecisionPacketFilter(f *PacketFilter, chainID string, sent PacketSequenceCache) *DecisionPacketFilter {
+	df := &DecisionPacketFilter{MinUSD: f.minUSD, MemoChainID: f.memoChainID, MemoPolicies: f.memoPolicies}
+	if len(f.minAmounts) > 0 {
+		df.MinAmounts = make(map[string]string, len(f.minAmounts))
+		for denom, min := range f.minAmounts {
+			df.MinAmounts[denom] = min.String()
+		}
+	}
+	for denom := range f.denyDenoms {
+		df.DenyDenoms = append(df.DenyDenoms, denom)
+	}
+	sort.Strings(df.DenyDenoms)
+	if f.minUSD > 0 && f.prices != nil {
+		for _, p := range sent {
+			denom, _, ok := transferData(p.Data)
+			if !ok {
+				continue
+			}
+			if price, ok := f.prices.USD(chainID, denom); ok {
+				if df.Prices == nil {
+					df.Prices = make(map[string]float64)
+				}
+				df.Prices[denom] = price
+			}
+		}
+	}
+	return df
+}
+
+// validationOutcome returns the outcome recorded in a bundle for the error of ValidatePacket.
+func validationOutcome(err error) string {
+	var timeoutHeightErr *provider.TimeoutHeightError
+	var timeoutTimestampErr *provider.TimeoutTimestampError
+	var timeoutOnCloseErr *provider.TimeoutOnCloseError
+	switch {
+	case errors.As(err, &timeoutHeightErr):
+		return validationTimeoutHeight
+	case errors.As(err, &timeoutTimestampErr):
+		return validationTimeoutTimestamp
+	case errors.As(err, &timeoutOnCloseErr):
+		return validationTimeoutOnClose
+	default:
+		return validationInvalidPrefix + err.Error()
+	}
+}
+
+// newDecision returns the decision of res, sorted so that decisions can be compared.
+func newDecision(res pathEndPacketFlowResponse) Decision {
+	d := Decision{
+		SrcMessages: decisionMessages(res.SrcMessages),
+		DstMessages: decisionMessages(res.DstMessages),
+		ToDeleteSrc: sortedToDelete(res.ToDeleteSrc),
+		ToDeleteDst: sortedToDelete(res.ToDeleteDst),
+	}
+	return d
+}
+
+func decisionMessages(msgs []packetIBCMessage) []DecisionMessage {
+	res := make([]DecisionMessage, 0, len(msgs))
+	for _, m := range msgs {
+		res = append(res, DecisionMessage{EventType: m.eventType, Sequence: m.info.Sequence})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Sequence != res[j].Sequence {
+			return res[i].Sequence < res[j].Sequence
+		}
+		return res[i].EventType < res[j].EventType
+	})
+	return res
+}
+
+func sortedToDelete(toDelete map[string][]uint64) map[string][]uint64 {
+	res := make(map[string][]uint64)
+	for eventType, seqs := range toDelete {
+		if len(seqs) == 0 {
+			continue
+		}
+		// Completed flows may be listed twice, once per observed message.
+		unique := make(map[uint64]bool, len(seqs))
+		for _, seq := range seqs {
+			if !unique[seq] {
+				unique[seq] = true
+				res[eventType] = append(res[eventType], seq)
+			}
+		}
+		sort.Slice(res[eventType], func(i, j int) bool { return res[eventType][i] < res[eventType][j] })
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+// LoadDecisionBundle reads a decision bundle from the JSON file at path.
+func LoadDecisionBundle(path string) (*DecisionBundle, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b DecisionBundle
+	if err := json.Unmarshal(bz, &b); err != nil {
+		return nil, fmt.Errorf("invalid decision bundle %s: %w", path, err)
+	}
+	if b.Version != decisionBundleVersion {
+		return nil, fmt.Errorf("decision bundle %s has version %d, expected %d", path, b.Version, decisionBundleVersion)
+	}
+	return &b, nil
+}
+
+// replayProvider is the dst chain provider of a replayed decision, validating packets as recorded in the bundle.
+type replayProvider struct {
+	provider.ChainProvider
+	validations map[uint64]string
+}
+
+func (p replayProvider) ValidatePacket(msgTransfer provider.PacketInfo, latest provider.LatestBlock) error {
+	outcome, ok := p.validations[msgTransfer.Sequence]
+	if !ok {
+		return nil
+	}
+	switch outcome {
+	case validationTimeoutHeight:
+		return provider.NewTimeoutHeightError(latest.Height, msgTransfer.TimeoutHeight.RevisionHeight)
+	case validationTimeoutTimestamp:
+		return provider.NewTimeoutTimestampError(uint64(latest.Time.UnixNano()), msgTransfer.TimeoutTimestamp)
+	case validationTimeoutOnClose:
+		return provider.NewTimeoutOnCloseError("channel closed")
+	default:
+		return errors.New(strings.TrimPrefix(outcome, validationInvalidPrefix))
+	}
+}
+
+// replayAllower holds back the packets the rate limiter held back when the decision was captured.
+type replayAllower map[uint64]string
+
+func (a replayAllower) Allow(_ string, _ ChannelKey, seq uint64, _ []byte) string {
+	return a[seq]
+}
+
+// staticPricer prices the denoms of a replayed decision as recorded in the bundle.
+type staticPricer map[string]float64
+
+func (p staticPricer) USD(_, denom string) (float64, bool) {
+	price, ok := p[denom]
+	return price, ok
+}
+
+// staticFinalityGater is the finality gater of a replayed path end, whose finalized height is set from the bundle.
+type staticFinalityGater struct{}
+
+func (staticFinalityGater) QueryLatestFinalizedHeight(context.Context, string) (int64, error) {
+	return 0, errors.New("finalized height of a replayed decision is not queried")
+}
+
+// ReplayDecision takes the packet flow decision again on the inputs captured in b, and returns it,
+// so that it can be compared to b.Decision.
+func ReplayDecision(log *zap.Logger, b *DecisionBundle) (Decision, error) {
+	k := ChannelKey{
+		PortID:                b.Channel.PortID,
+		ChannelID:             b.Channel.ChannelID,
+		CounterpartyPortID:    b.Channel.CounterpartyPortID,
+		CounterpartyChannelID: b.Channel.CounterpartyChannelID,
+	}
+	pp := NewPathProcessor(log, PathEnd{ChainID: b.Src.ChainID, ClientID: b.Src.ClientID}, PathEnd{ChainID: b.Dst.ChainID, ClientID: b.Dst.ClientID}, "")
+	pp.SetPathName(b.Path)
+	src, dst := pp.pathEnd1, pp.pathEnd2
+	replayPathEnd(src, b.Src, k)
+	replayPathEnd(dst, b.Dst, k.Counterparty())
+	dst.chainProvider = replayProvider{validations: b.Validations}
+
+	var filter packetSkipper = (*PacketFilter)(nil)
+	if b.PacketFilter != nil {
+		minAmounts := make(map[string]*big.Int, len(b.PacketFilter.MinAmounts))
+		for denom, amount := range b.PacketFilter.MinAmounts {
+			min, ok := new(big.Int).SetString(amount, 10)
+			if !ok {
+				return Decision{}, fmt.Errorf("invalid minimum amount %q of denom %s", amount, denom)
+			}
+			minAmounts[denom] = min
+		}
+		f := NewPacketFilter(minAmounts, b.PacketFilter.DenyDenoms)
+		f.SetMinUSD(b.PacketFilter.MinUSD)
+		f.SetPrices(staticPricer(b.PacketFilter.Prices))
+		f.SetMemoPolicies(b.PacketFilter.MemoChainID, b.PacketFilter.MemoPolicies)
+		filter = f
+	}
+
+	m := pathEndPacketFlowMessages{
+		Src:                   src,
+		Dst:                   dst,
+		ChannelKey:            k,
+		SrcMsgTransfer:        replayPackets(b.SendPackets),
+		DstMsgRecvPacket:      replayPackets(b.RecvPackets),
+		SrcMsgAcknowledgement: replayPackets(b.Acknowledgements),
+		SrcMsgTimeout:         replayPackets(b.Timeouts),
+		SrcMsgTimeoutOnClose:  replayPackets(b.TimeoutsOnClose),
+	}
+	// Giving up on a message deletes it from the message caches of the path ends.
+	src.messageCache.PacketFlow[k] = PacketMessagesCache{
+		chantypes.EventTypeSendPacket:           m.SrcMsgTransfer,
+		chantypes.EventTypeAcknowledgePacket:    m.SrcMsgAcknowledgement,
+		chantypes.EventTypeTimeoutPacket:        m.SrcMsgTimeout,
+		chantypes.EventTypeTimeoutPacketOnClose: m.SrcMsgTimeoutOnClose,
+	}
+	dst.messageCache.PacketFlow[k.Counterparty()] = PacketMessagesCache{
+		chantypes.EventTypeRecvPacket: m.DstMsgRecvPacket,
+	}
+
+	res := pp.packetFlowDecision(context.Background(), m, filter, replayAllower(b.RateLimited))
+	return newDecision(res), nil
+}
+
+func replayPathEnd(pathEnd *pathEndRuntime, pe DecisionPathEnd, k ChannelKey) {
+	pathEnd.latestBlock = provider.LatestBlock{Height: pe.LatestHeight, Time: pe.LatestTime}
+	if pe.FinalizedHeight != nil {
+		pathEnd.finalityGater = staticFinalityGater{}
+		pathEnd.finalizedHeight = *pe.FinalizedHeight
+	}
+	if pe.Preconfirmations {
+		pathEnd.preconfirmations = NewPreconfirmationTracker(pathEnd.log, nil)
+	}
+	pathEnd.confirmations = pe.Confirmations
+	pathEnd.channelStateCache[k] = pe.ChannelOpen
+	if len(pe.InProgress) > 0 {
+		pathEnd.packetProcessing[k] = make(packetChannelMessageCache)
+	}
+	for _, m := range pe.InProgress {
+		if _, ok := pathEnd.packetProcessing[k][m.EventType]; !ok {
+			pathEnd.packetProcessing[k][m.EventType] = make(packetMessageSendCache)
+		}
+		pathEnd.packetProcessing[k][m.EventType][m.Sequence] = processingMessage{
+			assembled:           m.Assembled,
+			lastProcessedHeight: m.LastProcessedHeight,
+			retryCount:          m.RetryCount,
+		}
+	}
+}
+
+func replayPackets(packets []DecisionPacket) PacketSequenceCache {
+	c := make(PacketSequenceCache, len(packets))
+	for _, p := range packets {
+		c[p.Sequence] = provider.PacketInfo{
+			Height:           p.Height,
+			Sequence:         p.Sequence,
+			SourcePort:       p.SourcePort,
+			SourceChannel:    p.SourceChannel,
+			DestPort:         p.DestPort,
+			DestChannel:      p.DestChannel,
+			Data:             p.Data,
+			TimeoutHeight:    p.TimeoutHeight,
+			TimeoutTimestamp: p.TimeoutTimestamp,
+			Ack:              p.Ack,
+		}
+	}
+	return c
+}
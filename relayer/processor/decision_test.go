@@ -0,0 +1,140 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// timeoutHeightProvider validates packets against the latest height of the chain only.
+type timeoutHeightProvider struct {
+	provider.ChainProvider
+}
+
+func (timeoutHeightProvider) ValidatePacket(p provider.PacketInfo, latest provider.LatestBlock) error {
+	if !p.TimeoutHeight.IsZero() && latest.Height >= p.TimeoutHeight.RevisionHeight {
+		return provider.NewTimeoutHeightError(latest.Height, p.TimeoutHeight.RevisionHeight)
+	}
+	return nil
+}
+
+func TestCaptureAndReplayDecision(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	pp := NewPathProcessor(log, PathEnd{ChainID: "rollapp", ClientID: "07-tendermint-0"}, PathEnd{ChainID: "hub", ClientID: "07-tendermint-1"}, "")
+	pp.SetPathName("demo")
+	pp.SetPacketFilter(NewPacketFilter(map[string]*big.Int{"urax": big.NewInt(5)}, nil))
+	pp.SetRateLimiter(NewRateLimiter("rollapp", []RateLimit{{Denom: "urax", Amount: big.NewInt(500), Window: time.Hour}}))
+	pp.SetFinalityGater("rollapp", &mockFinalityGater{})
+	c := NewDecisionCapture()
+	pp.SetDecisionCapture(c)
+
+	src, dst := pp.pathEnd1, pp.pathEnd2
+	k := ChannelKey{PortID: "transfer", ChannelID: "channel-0", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-1"}
+	src.latestBlock = provider.LatestBlock{Height: 30, Time: time.Unix(1700000000, 0).UTC()}
+	dst.latestBlock = provider.LatestBlock{Height: 50, Time: time.Unix(1700000100, 0).UTC()}
+	src.finalizedHeight = 20
+	src.channelStateCache[k] = true
+	dst.channelStateCache[k.Counterparty()] = true
+	dst.chainProvider = timeoutHeightProvider{}
+
+	packet := func(seq, height uint64, amount string, timeout uint64) provider.PacketInfo {
+		return provider.PacketInfo{
+			Height: height, Sequence: seq,
+			SourcePort: "transfer", SourceChannel: "channel-0", DestPort: "transfer", DestChannel: "channel-1",
+			Data:          []byte(`{"denom":"urax","amount":"` + amount + `"}`),
+			TimeoutHeight: clienttypes.NewHeight(1, timeout),
+		}
+	}
+	m := pathEndPacketFlowMessages{
+		Src:        src,
+		Dst:        dst,
+		ChannelKey: k,
+		SrcMsgTransfer: PacketSequenceCache{
+			1: packet(1, 10, "100", 1000), // relayed
+			2: packet(2, 25, "100", 1000), // not yet finalized
+			3: packet(3, 10, "100", 40),   // timed out
+			4: packet(4, 10, "100", 1000), // received, acknowledged back
+			5: packet(5, 10, "1", 1000),   // filtered out
+			6: packet(6, 10, "600", 1000), // over rate limit
+		},
+		DstMsgRecvPacket:      PacketSequenceCache{4: packet(4, 45, "100", 1000)},
+		SrcMsgAcknowledgement: PacketSequenceCache{7: packet(7, 28, "100", 1000)},
+	}
+
+	done := make(chan *DecisionBundle)
+	go func() {
+		b, err := c.Capture(context.Background(), "rollapp", "transfer", "channel-0")
+		require.NoError(t, err)
+		done <- b
+	}()
+	var captures []decisionRequest
+	require.Eventually(t, func() bool {
+		captures = c.pending()
+		return len(captures) == 1
+	}, time.Second, time.Millisecond)
+	pp.decidePacketFlow(context.Background(), m, captures)
+	b := <-done
+
+	require.Equal(t, Decision{
+		SrcMessages: []DecisionMessage{
+			{EventType: chantypes.EventTypeTimeoutPacket, Sequence: 3},
+			{EventType: chantypes.EventTypeAcknowledgePacket, Sequence: 4},
+		},
+		DstMessages: []DecisionMessage{{EventType: chantypes.EventTypeRecvPacket, Sequence: 1}},
+		ToDeleteSrc: map[string][]uint64{
+			chantypes.EventTypeSendPacket:        {5, 7},
+			chantypes.EventTypeAcknowledgePacket: {7},
+		},
+		ToDeleteDst: map[string][]uint64{chantypes.EventTypeRecvPacket: {5, 7}},
+	}, b.Decision)
+	require.Equal(t, int64(20), *b.Src.FinalizedHeight)
+	require.Nil(t, b.Dst.FinalizedHeight)
+	require.Equal(t, map[uint64]string{3: validationTimeoutHeight}, b.Validations)
+	require.Contains(t, b.RateLimited[6], "limit")
+	require.Equal(t, map[string]string{"urax": "5"}, b.PacketFilter.MinAmounts)
+
+	// The bundle replays to the same decision once attached to a bug report.
+	bz, err := json.Marshal(b)
+	require.NoError(t, err)
+	var loaded DecisionBundle
+	require.NoError(t, json.Unmarshal(bz, &loaded))
+	replayed, err := ReplayDecision(log, &loaded)
+	require.NoError(t, err)
+	require.Equal(t, b.Decision, replayed)
+
+	// Inputs changed in the bundle change the decision, e.g. a later finalized height.
+	h := int64(30)
+	loaded.Src.FinalizedHeight = &h
+	replayed, err = ReplayDecision(log, &loaded)
+	require.NoError(t, err)
+	require.Equal(t, []DecisionMessage{
+		{EventType: chantypes.EventTypeRecvPacket, Sequence: 1},
+		{EventType: chantypes.EventTypeRecvPacket, Sequence: 2},
+	}, replayed.DstMessages)
+}
+
+// TestReplayDecisionBundles replays the decision bundles attached to bug reports, in testdata/decisions,
+// which hold the decision expected once the bug is fixed.
+func TestReplayDecisionBundles(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "decisions", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, f := range files {
+		t.Run(filepath.Base(f), func(t *testing.T) {
+			b, err := LoadDecisionBundle(f)
+			require.NoError(t, err)
+			replayed, err := ReplayDecision(zaptest.NewLogger(t), b)
+			require.NoError(t, err)
+			require.Equal(t, b.Decision, replayed)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 type EventProcessorBuilder struct {
 	chainProcessors     ChainProcessors
 	initialBlockHistory uint64
+	backfill            Backfill
 	pathProcessors      PathProcessors
 	messageLifecycle    MessageLifecycle
 }
@@ -18,6 +19,7 @@ type EventProcessorBuilder struct {
 type EventProcessor struct {
 	chainProcessors     ChainProcessors
 	initialBlockHistory uint64
+	backfill            Backfill
 	pathProcessors      PathProcessors
 	messageLifecycle    MessageLifecycle
 }
@@ -48,6 +50,12 @@ func (ep EventProcessorBuilder) WithInitialBlockHistory(initialBlockHistory uint
 	return ep
 }
 
+// WithBackfill bounds the backfill of the initial block history by the ChainProcessors supporting it.
+func (ep EventProcessorBuilder) WithBackfill(backfill Backfill) EventProcessorBuilder {
+	ep.backfill = backfill
+	return ep
+}
+
 // WithPathProcessors adds to the list of PathProcessors to be used.
 func (ep EventProcessorBuilder) WithPathProcessors(pathProcessors ...*PathProcessor) EventProcessorBuilder {
 	ep.pathProcessors = append(ep.pathProcessors, pathProcessors...)
@@ -71,6 +79,9 @@ func (ep EventProcessorBuilder) Build() EventProcessor {
 			}
 		}
 		chainProcessor.SetPathProcessors(pathProcessorsForThisChain)
+		if b, ok := chainProcessor.(backfiller); ok {
+			b.SetBackfill(ep.backfill)
+		}
 	}
 
 	return EventProcessor(ep)
@@ -0,0 +1,35 @@
+package processor
+
+// Experimental features of the PathProcessor, gated at runtime with SetFeatureGate.
+const (
+	// FeatureOptimisticRelaying relays the packets sent on the path ends set with SetPreconfirmation
+	// before their block is finalized. Packets of path ends without preconfirmations are held back regardless.
+	FeatureOptimisticRelaying = "optimistic-relaying"
+
+	// FeatureProofPipelining queries the proofs of the messages of a transaction while its client update
+	// is assembled, instead of once it is.
+	FeatureProofPipelining = "proof-pipelining"
+)
+
+// defaultFeatures is whether each feature is enabled for PathProcessors without a FeatureGate.
+var defaultFeatures = map[string]bool{
+	FeatureOptimisticRelaying: true,
+	FeatureProofPipelining:    false,
+}
+
+// FeatureGate reports whether an experimental feature is enabled, checked every time the feature applies.
+type FeatureGate func(feature string) bool
+
+// SetFeatureGate enables or disables the experimental features of the path with g,
+// so they can be turned on and off without restarting the processor. Must be called before Run.
+func (pp *PathProcessor) SetFeatureGate(g FeatureGate) {
+	pp.features = g
+}
+
+// featureEnabled returns whether the experimental feature is enabled for the path.
+func (pp *PathProcessor) featureEnabled(feature string) bool {
+	if pp.features == nil {
+		return defaultFeatures[feature]
+	}
+	return pp.features(feature)
+}
@@ -0,0 +1,43 @@
+package processor
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// FinalityGater reports which blocks of a chain have been finalized on its settlement layer,
+// such as the settlement hub of a rollapp.
+type FinalityGater interface {
+	// QueryLatestFinalizedHeight returns the latest finalized height of the chain,
+	// or -1 if none of its blocks have been finalized yet.
+	QueryLatestFinalizedHeight(ctx context.Context, chainID string) (int64, error)
+}
+
+// updateFinalizedHeight refreshes the latest finalized height of the path end, if it is gated on finality.
+// Failing to query it holds back all packets of the path end until the next successful query.
+func (pathEnd *pathEndRuntime) updateFinalizedHeight(ctx context.Context) {
+	if pathEnd.finalityGater == nil {
+		return
+	}
+	h, err := pathEnd.finalityGater.QueryLatestFinalizedHeight(ctx, pathEnd.info.ChainID)
+	if err != nil {
+		pathEnd.log.Warn("Failed to query latest finalized height, holding back packets", zap.Error(err))
+		h = -1
+	}
+	pathEnd.finalizedHeight = h
+}
+
+// isFinalized returns true if the block at height is final, always the case for path ends not gated on finality.
+func (pathEnd *pathEndRuntime) isFinalized(height uint64) bool {
+	if pathEnd.finalityGater == nil {
+		return true
+	}
+	return pathEnd.finalizedHeight >= 0 && height <= uint64(pathEnd.finalizedHeight)
+}
+
+// isConfirmed returns true if confirmations blocks of the path end were built since height,
+// always the case for path ends without a confirmation depth.
+func (pathEnd *pathEndRuntime) isConfirmed(height uint64) bool {
+	return pathEnd.confirmations == 0 || pathEnd.latestBlock.Height >= height+pathEnd.confirmations
+}
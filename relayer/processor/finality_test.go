@@ -0,0 +1,108 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type mockFinalityGater struct {
+	height int64
+	err    error
+}
+
+func (g *mockFinalityGater) QueryLatestFinalizedHeight(context.Context, string) (int64, error) {
+	return g.height, g.err
+}
+
+func TestFinalityGating(t *testing.T) {
+	ctx := context.Background()
+	log := zaptest.NewLogger(t)
+
+	pp := NewPathProcessor(log, PathEnd{ChainID: "rollapp"}, PathEnd{ChainID: "hub"}, "")
+	rollapp, hub := pp.pathEnd1, pp.pathEnd2
+
+	g := &mockFinalityGater{height: -1}
+	pp.SetFinalityGater("rollapp", g)
+	require.Nil(t, hub.finalityGater)
+
+	// Chains which are not gated are always final.
+	hub.updateFinalizedHeight(ctx)
+	require.True(t, hub.isFinalized(1000))
+
+	// Nothing is final before the first finalized state.
+	rollapp.updateFinalizedHeight(ctx)
+	require.False(t, rollapp.isFinalized(1))
+
+	g.height = 10
+	rollapp.updateFinalizedHeight(ctx)
+	require.True(t, rollapp.isFinalized(10))
+	require.False(t, rollapp.isFinalized(11))
+
+	// Query failures hold back all packets.
+	g.err = errors.New("settlement unavailable")
+	rollapp.updateFinalizedHeight(ctx)
+	require.False(t, rollapp.isFinalized(1))
+}
+
+func TestConfirmations(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "evm"}, PathEnd{ChainID: "hub"}, "")
+	pp.SetConfirmations("evm", 3)
+	evm, hub := pp.pathEnd1, pp.pathEnd2
+	evm.latestBlock = provider.LatestBlock{Height: 12}
+	hub.latestBlock = provider.LatestBlock{Height: 100}
+	hub.chainProvider = validPacketProvider{}
+
+	// Chains without a confirmation depth relay packets of their latest block.
+	require.True(t, hub.isConfirmed(1000))
+	require.True(t, evm.isConfirmed(9))
+	require.False(t, evm.isConfirmed(10))
+
+	packet := func(seq, height uint64) provider.PacketInfo {
+		return provider.PacketInfo{
+			Sequence: seq, Height: height,
+			SourcePort: "transfer", SourceChannel: "channel-0", DestPort: "transfer", DestChannel: "channel-7",
+		}
+	}
+	k, err := packetIBCMessage{eventType: chantypes.EventTypeRecvPacket, info: packet(1, 9)}.channelKey()
+	require.NoError(t, err)
+	hub.channelStateCache[k] = true
+
+	res := pp.getUnrelayedPacketsAndAcksAndToDelete(context.Background(), pathEndPacketFlowMessages{
+		Src:            evm,
+		Dst:            hub,
+		ChannelKey:     k.Counterparty(),
+		SrcMsgTransfer: PacketSequenceCache{1: packet(1, 9), 2: packet(2, 10)},
+	})
+	require.Len(t, res.DstMessages, 1)
+	require.Equal(t, uint64(1), res.DstMessages[0].info.Sequence)
+	require.Empty(t, res.Held, "packets held back on confirmations are not held on finality")
+}
+
+func TestSkipPacketsSentOn(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "hub"}, PathEnd{ChainID: "rollapp"}, "")
+	pp.SkipPacketsSentOn("hub")
+	hub, rollapp := pp.pathEnd1, pp.pathEnd2
+	require.True(t, hub.skipPackets)
+	require.False(t, rollapp.skipPackets)
+
+	res := pathEndPacketFlowResponse{
+		SrcMessages: []packetIBCMessage{{eventType: chantypes.EventTypeAcknowledgePacket}},
+		DstMessages: []packetIBCMessage{{eventType: chantypes.EventTypeRecvPacket}},
+		ToDeleteSrc: map[string][]uint64{chantypes.EventTypeSendPacket: {1}},
+	}
+	kept := res
+	skipPacketFlow(rollapp, &kept)
+	require.Equal(t, res, kept)
+
+	// The packet flow of the packets sent on the hub is left to another relayer, but still forgotten once complete.
+	skipPacketFlow(hub, &res)
+	require.Empty(t, res.SrcMessages)
+	require.Empty(t, res.DstMessages)
+	require.Equal(t, []uint64{1}, res.ToDeleteSrc[chantypes.EventTypeSendPacket])
+}
@@ -0,0 +1,262 @@
+package processor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxHeldPacketStateQueries bounds the settlement layer queries resolving the state updates of held packets
+	// each time the path processor processes messages.
+	maxHeldPacketStateQueries = 10
+
+	// heldPacketStateRefresh is how long the pending state update of a held packet is trusted before it is queried again.
+	heldPacketStateRefresh = 30 * time.Second
+)
+
+// Statuses of the state updates of rollapps on their settlement layer.
+const (
+	SettlementStatePending   = "PENDING"
+	SettlementStateFinalized = "FINALIZED"
+	SettlementStateReverted  = "REVERTED"
+)
+
+// SettlementState is a state update a rollapp posted to its settlement layer, holding a range of its blocks.
+// A pending state update can be disputed until its dispute window ends, when it is finalized unless it was reverted.
+type SettlementState struct {
+	Index       uint64 `json:"index"`
+	StartHeight uint64 `json:"start_height"`
+	EndHeight   uint64 `json:"end_height"`
+	Status      string `json:"status"`
+
+	// CreationHeight is the height of the settlement layer the state update was posted at.
+	CreationHeight uint64 `json:"creation_height"`
+
+	// DisputeWindowEnd is the height of the settlement layer the dispute window of the state update ends at,
+	// and SettlementHeight the latest height of the settlement layer when it was queried, 0 if unknown.
+	DisputeWindowEnd uint64 `json:"dispute_window_end,omitempty"`
+	SettlementHeight int64  `json:"settlement_height,omitempty"`
+
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// SettlementStateQuerier queries the state updates rollapps post to their settlement layer.
+type SettlementStateQuerier interface {
+	// QuerySettlementState returns the state update of the rollapp holding its block at height,
+	// or false if no state update holds it yet.
+	QuerySettlementState(ctx context.Context, chainID string, height uint64) (SettlementState, bool, error)
+}
+
+// HeldPacket is a packet sent on a rollapp held back until the block it was sent in is finalized on the settlement layer.
+type HeldPacket struct {
+	Path      string    `json:"path"`
+	ChainID   string    `json:"chain_id"`
+	ChannelID string    `json:"channel_id"`
+	PortID    string    `json:"port_id"`
+	Sequence  uint64    `json:"sequence"`
+	Height    uint64    `json:"height"`
+	HeldSince time.Time `json:"held_since"`
+
+	// State is the state update holding the block of the packet, nil until it is posted to the settlement layer.
+	State *SettlementState `json:"state,omitempty"`
+}
+
+// heldPackets resolves the state updates of the packets held back on a path end, see PathProcessor.SetHeldPackets.
+type heldPackets struct {
+	querier SettlementStateQuerier
+	tracker *HeldPacketTracker
+}
+
+type heldPacketKey struct {
+	path, chainID, channelID, portID string
+	sequence                         uint64
+}
+
+func (p HeldPacket) key() heldPacketKey {
+	return heldPacketKey{p.Path, p.ChainID, p.ChannelID, p.PortID, p.Sequence}
+}
+
+// HeldPacketTracker records the packets held back on finality by the path processors it is set on with
+// PathProcessor.SetHeldPackets, resolving the state update each is part of, so that packets of reverted
+// state updates are dropped rather than relayed once the rollapp is finalized again.
+// A tracker may be shared by the path processors of several paths.
+type HeldPacketTracker struct {
+	log *zap.Logger
+
+	mu   sync.Mutex
+	held map[heldPacketKey]HeldPacket
+}
+
+// NewHeldPacketTracker returns an empty tracker.
+func NewHeldPacketTracker(log *zap.Logger) *HeldPacketTracker {
+	return &HeldPacketTracker{
+		log:  log,
+		held: make(map[heldPacketKey]HeldPacket),
+	}
+}
+
+// update replaces the packets of path held back on the channel k of chainID with held, returning the sequences
+// of those whose state update was reverted, which are no longer tracked.
+func (t *HeldPacketTracker) update(path, chainID string, k ChannelKey, held []provider.PacketInfo) []uint64 {
+	now := time.Now().UTC()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev := make(map[uint64]HeldPacket)
+	for key, p := range t.held {
+		if key.path == path && key.chainID == chainID && key.channelID == k.ChannelID && key.portID == k.PortID {
+			prev[key.sequence] = p
+			delete(t.held, key)
+		}
+	}
+	var reverted []uint64
+	for _, info := range held {
+		p, ok := prev[info.Sequence]
+		if !ok || p.Height != info.Height {
+			p = HeldPacket{
+				Path:      path,
+				ChainID:   chainID,
+				ChannelID: k.ChannelID,
+				PortID:    k.PortID,
+				Sequence:  info.Sequence,
+				Height:    info.Height,
+				HeldSince: now,
+			}
+		}
+		if p.State != nil && p.State.Status == SettlementStateReverted {
+			t.log.Warn(
+				"Dropping packet of reverted state update",
+				zap.String("path", path),
+				zap.String("chain_id", chainID),
+				zap.String("channel_id", p.ChannelID),
+				zap.String("port_id", p.PortID),
+				zap.Uint64("sequence", p.Sequence),
+				zap.Uint64("height", p.Height),
+				zap.Uint64("state_index", p.State.Index),
+			)
+			reverted = append(reverted, p.Sequence)
+			continue
+		}
+		t.held[p.key()] = p
+	}
+	return reverted
+}
+
+// unresolved returns up to limit packets of path held back on chainID whose state update is unknown or due
+// to be queried again, lowest first.
+func (t *HeldPacketTracker) unresolved(path, chainID string, limit int) []HeldPacket {
+	refreshed := time.Now().Add(-heldPacketStateRefresh)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var due []HeldPacket
+	for _, p := range t.held {
+		if p.Path != path || p.ChainID != chainID {
+			continue
+		}
+		if p.State == nil || (p.State.Status == SettlementStatePending && p.State.CheckedAt.Before(refreshed)) {
+			due = append(due, p)
+		}
+	}
+	sortHeldPackets(due)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due
+}
+
+// setState records the state update of the held packets of path on chainID it holds.
+func (t *HeldPacketTracker) setState(path, chainID string, s SettlementState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k, p := range t.held {
+		if p.Path == path && p.ChainID == chainID && p.Height >= s.StartHeight && p.Height <= s.EndHeight {
+			st := s
+			p.State = &st
+			t.held[k] = p
+		}
+	}
+}
+
+// Snapshot returns the packets held back, lowest height first.
+func (t *HeldPacketTracker) Snapshot() []HeldPacket {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	held := make([]HeldPacket, 0, len(t.held))
+	for _, p := range t.held {
+		held = append(held, p)
+	}
+	sortHeldPackets(held)
+	return held
+}
+
+func sortHeldPackets(ps []HeldPacket) {
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Height != ps[j].Height {
+			return ps[i].Height < ps[j].Height
+		}
+		if ps[i].ChannelID != ps[j].ChannelID {
+			return ps[i].ChannelID < ps[j].ChannelID
+		}
+		return ps[i].Sequence < ps[j].Sequence
+	})
+}
+
+// trackHeldPackets records the packets of res held back on finality on the channel k of pathEnd,
+// deleting those of reverted state updates from its cache.
+func (pp *PathProcessor) trackHeldPackets(pathEnd *pathEndRuntime, k ChannelKey, res *pathEndPacketFlowResponse) {
+	if pathEnd.heldPackets == nil {
+		return
+	}
+	reverted := pathEnd.heldPackets.tracker.update(pp.pathName, pathEnd.info.ChainID, k, res.Held)
+	res.ToDeleteSrc[chantypes.EventTypeSendPacket] = append(res.ToDeleteSrc[chantypes.EventTypeSendPacket], reverted...)
+}
+
+// resolveHeldPackets queries the state updates of the packets held back on pathEnd whose state update is not known
+// or still pending. Each state update queried resolves all the held packets it holds.
+func (pp *PathProcessor) resolveHeldPackets(ctx context.Context, pathEnd *pathEndRuntime) {
+	h := pathEnd.heldPackets
+	if h == nil {
+		return
+	}
+	var resolved []SettlementState
+	for _, p := range h.tracker.unresolved(pp.pathName, pathEnd.info.ChainID, maxHeldPacketStateQueries) {
+		if stateHolds(resolved, p.Height) {
+			continue
+		}
+		queryCtx, cancel := context.WithTimeout(ctx, packetProofQueryTimeout)
+		s, ok, err := h.querier.QuerySettlementState(queryCtx, pathEnd.info.ChainID, p.Height)
+		cancel()
+		if err != nil {
+			pathEnd.log.Debug(
+				"Failed to query state update of held packet, retrying",
+				zap.Uint64("sequence", p.Sequence),
+				zap.Uint64("height", p.Height),
+				zap.Error(err),
+			)
+			return
+		}
+		if !ok {
+			// later packets are not posted either
+			return
+		}
+		s.CheckedAt = time.Now().UTC()
+		h.tracker.setState(pp.pathName, pathEnd.info.ChainID, s)
+		resolved = append(resolved, s)
+	}
+}
+
+// stateHolds returns true if one of states holds the block at height.
+func stateHolds(states []SettlementState, height uint64) bool {
+	for _, s := range states {
+		if height >= s.StartHeight && height <= s.EndHeight {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// mockSettlementStates serves states of 10 blocks each, from height 1 up to posted.
+type mockSettlementStates struct {
+	posted   uint64
+	statuses map[uint64]string
+	queries  int
+	err      error
+}
+
+func (m *mockSettlementStates) QuerySettlementState(_ context.Context, _ string, height uint64) (SettlementState, bool, error) {
+	m.queries++
+	if m.err != nil {
+		return SettlementState{}, false, m.err
+	}
+	if height > m.posted {
+		return SettlementState{}, false, nil
+	}
+	index := (height-1)/10 + 1
+	status, ok := m.statuses[index]
+	if !ok {
+		status = SettlementStatePending
+	}
+	return SettlementState{
+		Index:            index,
+		StartHeight:      (index-1)*10 + 1,
+		EndHeight:        index * 10,
+		Status:           status,
+		CreationHeight:   100 + index,
+		DisputeWindowEnd: 220 + index,
+	}, true, nil
+}
+
+func TestHeldPackets(t *testing.T) {
+	ctx := context.Background()
+	log := zaptest.NewLogger(t)
+
+	pp := NewPathProcessor(log, PathEnd{ChainID: "rollapp"}, PathEnd{ChainID: "hub"}, "")
+	pp.pathName = "demo-path"
+	rollapp, hub := pp.pathEnd1, pp.pathEnd2
+	pp.SetFinalityGater("rollapp", &mockFinalityGater{height: -1})
+	states := &mockSettlementStates{posted: 20, statuses: make(map[uint64]string)}
+	tracker := NewHeldPacketTracker(log)
+	pp.SetHeldPackets("rollapp", states, tracker)
+	require.Nil(t, hub.heldPackets)
+
+	k := ChannelKey{PortID: "transfer", ChannelID: "channel-0", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-7"}
+	packet := func(seq, height uint64) provider.PacketInfo {
+		return provider.PacketInfo{Sequence: seq, Height: height, SourcePort: "transfer", SourceChannel: "channel-0"}
+	}
+	track := func(held ...provider.PacketInfo) []uint64 {
+		res := pathEndPacketFlowResponse{ToDeleteSrc: make(map[string][]uint64), Held: held}
+		pp.trackHeldPackets(rollapp, k, &res)
+		return res.ToDeleteSrc[chantypes.EventTypeSendPacket]
+	}
+
+	require.Empty(t, track(packet(1, 5), packet(2, 7), packet(3, 15), packet(4, 25)))
+	held := tracker.Snapshot()
+	require.Len(t, held, 4)
+	require.Nil(t, held[0].State)
+	since := held[0].HeldSince
+
+	// A state resolves all the held packets it holds, packets not yet posted stay unresolved.
+	pp.resolveHeldPackets(ctx, rollapp)
+	require.Equal(t, 3, states.queries)
+	held = tracker.Snapshot()
+	require.Equal(t, uint64(1), held[0].State.Index)
+	require.Equal(t, uint64(1), held[1].State.Index)
+	require.Equal(t, uint64(2), held[2].State.Index)
+	require.Equal(t, uint64(222), held[2].State.DisputeWindowEnd)
+	require.Nil(t, held[3].State)
+
+	// Pending states are not queried again until refreshed.
+	pp.resolveHeldPackets(ctx, rollapp)
+	require.Equal(t, 4, states.queries, "only the packet not yet posted")
+
+	// Packets still held keep their state, released ones are forgotten.
+	require.Empty(t, track(packet(2, 7), packet(3, 15), packet(4, 25)))
+	held = tracker.Snapshot()
+	require.Len(t, held, 3)
+	require.Equal(t, since, held[0].HeldSince)
+	require.NotNil(t, held[0].State)
+
+	// Packets of a reverted state are dropped from the cache once resolved.
+	states.statuses[2] = SettlementStateReverted
+	tracker.mu.Lock()
+	for key, p := range tracker.held {
+		if p.State != nil {
+			p.State.CheckedAt = time.Now().Add(-2 * heldPacketStateRefresh)
+			tracker.held[key] = p
+		}
+	}
+	tracker.mu.Unlock()
+	pp.resolveHeldPackets(ctx, rollapp)
+	require.Equal(t, []uint64{3}, track(packet(2, 7), packet(3, 15), packet(4, 25)))
+	held = tracker.Snapshot()
+	require.Len(t, held, 2)
+	require.Equal(t, uint64(2), held[0].Sequence)
+	require.Equal(t, uint64(4), held[1].Sequence)
+
+	// Query failures leave the packets unresolved.
+	states.err = errors.New("settlement unavailable")
+	pp.resolveHeldPackets(ctx, rollapp)
+	require.Nil(t, tracker.Snapshot()[1].State)
+}
+
+func TestPacketFlowDecisionReportsHeldPackets(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "rollapp"}, PathEnd{ChainID: "hub"}, "")
+	pp.SetFinalityGater("rollapp", &mockFinalityGater{height: 10})
+	rollapp, hub := pp.pathEnd1, pp.pathEnd2
+	rollapp.updateFinalizedHeight(context.Background())
+	hub.chainProvider = validPacketProvider{}
+
+	res := pp.getUnrelayedPacketsAndAcksAndToDelete(context.Background(), pathEndPacketFlowMessages{
+		Src:        rollapp,
+		Dst:        hub,
+		ChannelKey: ChannelKey{PortID: "transfer", ChannelID: "channel-0"},
+		SrcMsgTransfer: PacketSequenceCache{
+			1: provider.PacketInfo{Sequence: 1, Height: 10},
+			2: provider.PacketInfo{Sequence: 2, Height: 11},
+		},
+	})
+	require.Len(t, res.Held, 1)
+	require.Equal(t, uint64(2), res.Held[0].Sequence)
+}
@@ -0,0 +1,142 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Actions of a MemoPolicy.
+const (
+	// MemoPass relays the packet with its memo as is.
+	MemoPass = "pass"
+
+	// MemoTruncate relays the packet with its memo as is, truncated to the MaxBytes of the policy
+	// in the packet data published to the feed.
+	MemoTruncate = "truncate"
+
+	// MemoBlock skips the packet, which is neither received nor timed out by the relayer.
+	MemoBlock = "block"
+)
+
+// forwardingMemoKeys are the top-level keys of the memos carrying instructions for middlewares of the receiving chain:
+// forward of the packet-forward-middleware, and wasm of ibc-hooks.
+var forwardingMemoKeys = []string{"forward", "wasm"}
+
+// MemoPolicy handles the packets sent over a channel with a user-supplied memo matching the policy,
+// as middlewares increasingly overload the memo of the packet data.
+type MemoPolicy struct {
+	// PortID and ChannelID restrict the policy to a channel of the src chain of the path, matched as channel filter
+	// entries, the packets of both directions of the channel being handled. Every channel matches if ChannelID is empty.
+	PortID    string `json:"port_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+
+	// MaxBytes restricts the policy to the memos longer than MaxBytes, every memo matching if 0.
+	MaxBytes int `json:"max_bytes,omitempty"`
+
+	// Forwarding restricts the policy to the memos with forwarding instructions, see forwardingMemoKeys.
+	Forwarding bool `json:"forwarding,omitempty"`
+
+	// Action is MemoPass, MemoTruncate or MemoBlock.
+	Action string `json:"action"`
+}
+
+// SetMemoPolicies handles the packets with a memo of the path whose src chain is srcChainID according to policies,
+// the first policy matching a memo applying. The packets whose memo matches no policy are passed.
+func (f *PacketFilter) SetMemoPolicies(srcChainID string, policies []MemoPolicy) {
+	f.memoChainID, f.memoPolicies = srcChainID, policies
+}
+
+// MemoAction returns the memo of the packet with the given data, sent from the chain chainID over the channel
+// identified by k from the perspective of chainID, and the action of the first memo policy matching it.
+// The action is empty if the packet has no memo, and MemoPass if no policy matches.
+func (f *PacketFilter) MemoAction(chainID string, k ChannelKey, data []byte) (memo string, policy *MemoPolicy) {
+	memo = packetMemo(data)
+	if memo == "" || f == nil {
+		return memo, nil
+	}
+	portID, channelID := k.PortID, k.ChannelID
+	if chainID != f.memoChainID {
+		portID, channelID = k.CounterpartyPortID, k.CounterpartyChannelID
+	}
+	for i, p := range f.memoPolicies {
+		if p.ChannelID != "" && (!MatchChannelPattern(p.ChannelID, channelID) || (p.PortID != "" && !MatchChannelPattern(p.PortID, portID))) {
+			continue
+		}
+		if len(memo) <= p.MaxBytes || (p.Forwarding && !hasForwarding(memo)) {
+			continue
+		}
+		return memo, &f.memoPolicies[i]
+	}
+	return memo, nil
+}
+
+// memoAction returns the action applying to the memo of the packet, empty if it has none, see MemoAction.
+func (f *PacketFilter) memoAction(chainID string, k ChannelKey, data []byte) string {
+	memo, p := f.MemoAction(chainID, k, data)
+	switch {
+	case memo == "":
+		return ""
+	case p == nil:
+		return MemoPass
+	}
+	return p.Action
+}
+
+// blockedMemo returns why the memo of the packet is blocked, or an empty string if it is not.
+func (f *PacketFilter) blockedMemo(chainID string, k ChannelKey, data []byte) string {
+	memo, p := f.MemoAction(chainID, k, data)
+	if p == nil || p.Action != MemoBlock {
+		return ""
+	}
+	if p.Forwarding {
+		return fmt.Sprintf("memo of %d bytes with forwarding instructions is blocked", len(memo))
+	}
+	return fmt.Sprintf("memo of %d bytes is blocked, over the %d bytes allowed", len(memo), p.MaxBytes)
+}
+
+// LoggedData returns the packet data as published, with its memo truncated if a MemoTruncate policy matches it.
+func (f *PacketFilter) LoggedData(chainID string, k ChannelKey, data []byte) []byte {
+	memo, p := f.MemoAction(chainID, k, data)
+	if p == nil || p.Action != MemoTruncate || len(memo) <= p.MaxBytes {
+		return data
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return data
+	}
+	truncated, err := json.Marshal(fmt.Sprintf("%s... (%d bytes truncated)", memo[:p.MaxBytes], len(memo)-p.MaxBytes))
+	if err != nil {
+		return data
+	}
+	fields["memo"] = truncated
+	bz, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return bz
+}
+
+// packetMemo returns the memo of JSON packet data, such as of ICS-20 transfers, or an empty string if it has none.
+func packetMemo(data []byte) string {
+	var packet struct {
+		Memo string `json:"memo"`
+	}
+	if json.Unmarshal(data, &packet) != nil {
+		return ""
+	}
+	return packet.Memo
+}
+
+// hasForwarding returns whether the memo is a JSON object with instructions for a middleware, see forwardingMemoKeys.
+func hasForwarding(memo string) bool {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal([]byte(memo), &fields) != nil {
+		return false
+	}
+	for _, k := range forwardingMemoKeys {
+		if _, ok := fields[k]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMemoPolicies(t *testing.T) {
+	f := NewPacketFilter(nil, nil)
+	f.SetMemoPolicies("hub", []MemoPolicy{
+		{ChannelID: "channel-0", Forwarding: true, Action: MemoBlock},
+		{ChannelID: "channel-0", MaxBytes: 16, Action: MemoTruncate},
+		{MaxBytes: 64, Action: MemoBlock},
+	})
+	hubChannel := ChannelKey{PortID: "transfer", ChannelID: "channel-0", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-5"}
+	otherChannel := ChannelKey{PortID: "transfer", ChannelID: "channel-1", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-6"}
+	transfer := func(memo string) []byte {
+		bz, err := json.Marshal(map[string]string{"denom": "urax", "amount": "1", "memo": memo})
+		require.NoError(t, err)
+		return bz
+	}
+	forward := transfer(`{"forward":{"receiver":"osmo1...","channel":"channel-2"}}`)
+
+	// Packets without a memo, or whose memo matches no policy, are relayed.
+	require.Empty(t, f.memoAction("hub", hubChannel, []byte(`{"denom":"urax","amount":"1"}`)))
+	require.Equal(t, MemoPass, f.memoAction("hub", hubChannel, transfer("short")))
+	require.Empty(t, f.Skip("hub", hubChannel, transfer("short")))
+
+	// Channels are identified on the src chain of the path, for the packets of both of its directions.
+	require.Equal(t, "memo of 57 bytes with forwarding instructions is blocked", f.Skip("hub", hubChannel, forward))
+	require.Equal(t, MemoBlock, f.memoAction("rollapp", hubChannel.Counterparty(), forward))
+	require.Equal(t, MemoPass, f.memoAction("hub", otherChannel, forward))
+	require.Equal(t, "memo of 65 bytes is blocked, over the 64 bytes allowed", f.Skip("hub", otherChannel, transfer(string(make([]byte, 65)))))
+
+	// Truncated memos are relayed as is, and truncated in the packet data published.
+	long := transfer("0123456789abcdefghij")
+	require.Empty(t, f.Skip("hub", hubChannel, long))
+	require.Equal(t, MemoTruncate, f.memoAction("hub", hubChannel, long))
+	require.JSONEq(t, `{"denom":"urax","amount":"1","memo":"0123456789abcdef... (4 bytes truncated)"}`, string(f.LoggedData("hub", hubChannel, long)))
+	require.Equal(t, long, f.LoggedData("hub", otherChannel, long))
+
+	var none *PacketFilter
+	require.Equal(t, forward, none.LoggedData("hub", hubChannel, forward))
+}
+
+func TestMemoPoliciesBlockPackets(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "chain-a"}, PathEnd{ChainID: "chain-b"}, "")
+	f := NewPacketFilter(nil, nil)
+	f.SetMemoPolicies("chain-a", []MemoPolicy{{Forwarding: true, Action: MemoBlock}})
+	pp.SetPacketFilter(f)
+	pp.SetMetrics(NewPrometheusMetrics())
+
+	forward := provider.PacketInfo{
+		Sequence: 1, SourceChannel: "channel-0", SourcePort: "transfer",
+		Data: []byte(`{"denom":"urax","amount":"1","memo":"{\"wasm\":{}}"}`),
+	}
+	res := pp.getUnrelayedPacketsAndAcksAndToDelete(context.Background(), pathEndPacketFlowMessages{
+		Src:            pp.pathEnd1,
+		Dst:            pp.pathEnd2,
+		SrcMsgTransfer: PacketSequenceCache{1: forward},
+	})
+
+	// Blocked packets are neither received nor timed out, and are counted once forgotten.
+	require.Empty(t, res.SrcMessages)
+	require.Empty(t, res.DstMessages)
+	require.Equal(t, []uint64{1}, res.ToDeleteSrc[chantypes.EventTypeSendPacket])
+	families, err := pp.metrics.Registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Equal(t, "cosmos_relayer_memo_packets_total", families[0].GetName())
+	require.Equal(t, 1.0, families[0].GetMetric()[0].GetCounter().GetValue())
+}
@@ -0,0 +1,771 @@
+package processor
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metric label names. They are the contract dashboards and alerts are built against:
+// a label keeps its name and meaning on every metric carrying it, see MetricsCatalog.
+const (
+	// LabelPath is the name of the path in the relayer config.
+	LabelPath = "path"
+
+	// LabelChainID is the chain a transaction was sent to, or whose state is reported.
+	LabelChainID = "chain_id"
+
+	// LabelDirection is either DirectionSrcToDst or DirectionDstToSrc,
+	// the side of the path a transaction was sent from and to.
+	LabelDirection = "direction"
+
+	// LabelChannel and LabelPort identify the channel end on chain_id a packet message was delivered to,
+	// or the end of a monitor-only channel pending messages are to be relayed from,
+	// or the end of a channel of chain_id packets and acknowledgements were relayed from.
+	LabelChannel = "channel"
+	LabelPort    = "port"
+
+	// LabelType is the type of a relayed packet message, one of MetricRecvPacket, MetricAckPacket or MetricTimeout.
+	LabelType = "type"
+
+	// LabelAddress and LabelDenom identify a balance of the relayer wallet on chain_id.
+	LabelAddress = "address"
+	LabelDenom   = "denom"
+
+	// LabelClientID is a light client hosted on chain_id.
+	LabelClientID = "client_id"
+
+	// LabelStore is a store of state persisted by the relayer, such as StoreAcks.
+	LabelStore = "store"
+
+	// LabelStatus is the outcome of relaying a packet or acknowledgement, one of the sequence result statuses,
+	// or of a wallet top-up or an alert.
+	LabelStatus = "status"
+
+	// LabelKind is the kind of condition an alert is about, such as sustained relay errors.
+	LabelKind = "kind"
+
+	// LabelLevel is the escalation level of packets pending for long, warning or critical.
+	LabelLevel = "level"
+
+	// LabelProbe is the name of a health probe configured by the operator.
+	LabelProbe = "probe"
+
+	// LabelAction is the action of the memo policy applying to the memo of a packet, one of the MemoPolicy actions.
+	LabelAction = "action"
+)
+
+// Values of the direction label.
+const (
+	DirectionSrcToDst = "src_to_dst"
+	DirectionDstToSrc = "dst_to_src"
+)
+
+// Relayed message types of the relayed_packets_total metric.
+const (
+	MetricRecvPacket = "recv_packet"
+	MetricAckPacket  = "ack_packet"
+	MetricTimeout    = "timeout"
+)
+
+// Stores of the store label.
+const (
+	StoreAcks    = "acks"
+	StoreRepairs = "repairs"
+)
+
+const metricsNamespace = "cosmos_relayer"
+
+// MetricSpec describes a metric published by the relayer.
+type MetricSpec struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Help   string   `json:"help"`
+	Labels []string `json:"labels"`
+}
+
+var (
+	relayedPacketsSpec = MetricSpec{
+		Name:   metricsNamespace + "_relayed_packets_total",
+		Type:   "counter",
+		Help:   "Packet messages successfully relayed, by the channel of the chain they were delivered to",
+		Labels: []string{LabelPath, LabelChainID, LabelDirection, LabelChannel, LabelPort, LabelType},
+	}
+	failedRelaysSpec = MetricSpec{
+		Name:   metricsNamespace + "_failed_relays_total",
+		Type:   "counter",
+		Help:   "Transactions the relayer failed to send",
+		Labels: []string{LabelPath, LabelChainID, LabelDirection},
+	}
+	gasUsedSpec = MetricSpec{
+		Name:   metricsNamespace + "_gas_used_total",
+		Type:   "counter",
+		Help:   "Gas used by the transactions sent by the relayer",
+		Labels: []string{LabelPath, LabelChainID, LabelDirection},
+	}
+	feesEarnedSpec = MetricSpec{
+		Name:   metricsNamespace + "_fees_earned_total",
+		Type:   "counter",
+		Help:   "ICS-29 fees paid out to the relayer wallet on a chain, in the given denom",
+		Labels: []string{LabelChainID, LabelDenom},
+	}
+	walletBalanceSpec = MetricSpec{
+		Name:   metricsNamespace + "_wallet_balance",
+		Type:   "gauge",
+		Help:   "Balance of the relayer wallet on a chain, in the given denom",
+		Labels: []string{LabelChainID, LabelAddress, LabelDenom},
+	}
+	walletLowBalanceSpec = MetricSpec{
+		Name:   metricsNamespace + "_wallet_low_balance",
+		Type:   "gauge",
+		Help:   "1 while the balance of a monitored relayer wallet on a chain is below its threshold, in the given denom",
+		Labels: []string{LabelChainID, LabelAddress, LabelDenom},
+	}
+	walletTopUpsSpec = MetricSpec{
+		Name:   metricsNamespace + "_wallet_top_ups_total",
+		Type:   "counter",
+		Help:   "Top-ups of a low relayer wallet on a chain from its funding account, sent or failed",
+		Labels: []string{LabelChainID, LabelAddress, LabelDenom, LabelStatus},
+	}
+	clientConsensusStatesSpec = MetricSpec{
+		Name:   metricsNamespace + "_client_consensus_states",
+		Type:   "gauge",
+		Help:   "Consensus states stored on a chain for a client of a relayed path, as last counted",
+		Labels: []string{LabelChainID, LabelClientID},
+	}
+	latestFinalizedHeightSpec = MetricSpec{
+		Name:   metricsNamespace + "_latest_finalized_height",
+		Type:   "gauge",
+		Help:   "Latest height of a rollapp finalized on its settlement layer, as last observed",
+		Labels: []string{LabelChainID},
+	}
+	monitoredChannelPendingSpec = MetricSpec{
+		Name:   metricsNamespace + "_monitored_channel_pending",
+		Type:   "gauge",
+		Help:   "Packets and acknowledgements pending relay from an end of a monitor-only channel, as last observed",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort},
+	}
+	monitoredChannelOldestPendingSpec = MetricSpec{
+		Name:   metricsNamespace + "_monitored_channel_oldest_pending_seconds",
+		Type:   "gauge",
+		Help:   "Time the oldest packet or acknowledgement pending relay from an end of a monitor-only channel has been observed pending",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort},
+	}
+	escrowBalanceSpec = MetricSpec{
+		Name:   metricsNamespace + "_escrow_balance",
+		Type:   "gauge",
+		Help:   "Balance of the ICS-20 escrow account of an end of a relayed transfer channel, in the given denom, as last observed",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort, LabelDenom},
+	}
+	storeEntriesSpec = MetricSpec{
+		Name:   metricsNamespace + "_store_entries",
+		Type:   "gauge",
+		Help:   "Entries kept in a store of state persisted by the relayer, as last counted",
+		Labels: []string{LabelStore},
+	}
+	storeSizeSpec = MetricSpec{
+		Name:   metricsNamespace + "_store_size_bytes",
+		Type:   "gauge",
+		Help:   "Size on disk of a store of state persisted by the relayer, as last measured",
+		Labels: []string{LabelStore},
+	}
+	preconfirmedPacketsSpec = MetricSpec{
+		Name:   metricsNamespace + "_preconfirmed_packets",
+		Type:   "gauge",
+		Help:   "Packets sent on a rollapp of a trusted path relayed before their block was finalized, awaiting finalization",
+		Labels: []string{LabelPath, LabelChainID},
+	}
+	preconfirmationMismatchesSpec = MetricSpec{
+		Name:   metricsNamespace + "_preconfirmation_mismatches_total",
+		Type:   "counter",
+		Help:   "Packets relayed before finalization that the finalized blocks of their rollapp do not include as relayed",
+		Labels: []string{LabelPath, LabelChainID},
+	}
+	clientChainIDMismatchSpec = MetricSpec{
+		Name:   metricsNamespace + "_client_chain_id_mismatch",
+		Type:   "gauge",
+		Help:   "1 if a client of a relayed path does not track the chain ID, revision or heights of its live counterparty, as last verified",
+		Labels: []string{LabelPath, LabelChainID, LabelClientID},
+	}
+	watchedClientAlertingSpec = MetricSpec{
+		Name:   metricsNamespace + "_watched_client_alerting",
+		Type:   "gauge",
+		Help:   "1 if an alert is raised on a client watched by the watchtower, as last checked",
+		Labels: []string{LabelChainID, LabelClientID},
+	}
+	watchedClientExpirySpec = MetricSpec{
+		Name:   metricsNamespace + "_watched_client_expiry_seconds",
+		Type:   "gauge",
+		Help:   "Time left before a client watched by the watchtower expires unless it is updated, negative once expired",
+		Labels: []string{LabelChainID, LabelClientID},
+	}
+	packetsNearingTimeoutSpec = MetricSpec{
+		Name:   metricsNamespace + "_packets_nearing_timeout",
+		Type:   "gauge",
+		Help:   "Packets sent on a channel end and pending relay that are approaching their timeout on the counterparty, as last checked",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort},
+	}
+	packetsExpiredSpec = MetricSpec{
+		Name:   metricsNamespace + "_packets_expired",
+		Type:   "gauge",
+		Help:   "Packets sent on a channel end and never relayed whose timeout on the counterparty elapsed, as last checked",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort},
+	}
+	orderedChannelNextSequenceRecvSpec = MetricSpec{
+		Name:   metricsNamespace + "_ordered_channel_next_sequence_recv",
+		Type:   "gauge",
+		Help:   "Next sequence to receive of an end of an ordered channel relayed, as last checked",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort},
+	}
+	orderedChannelStalledSpec = MetricSpec{
+		Name:   metricsNamespace + "_ordered_channel_stalled",
+		Type:   "gauge",
+		Help:   "1 while the next sequence to receive of an end of an ordered channel does not advance although packets are waiting",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort},
+	}
+	tokenPriceSpec = MetricSpec{
+		Name:   metricsNamespace + "_token_price_usd",
+		Type:   "gauge",
+		Help:   "USD price of one base unit of a token of a relayed chain according to the price oracle, as last refreshed",
+		Labels: []string{LabelChainID, LabelDenom},
+	}
+	rateLimitPausesSpec = MetricSpec{
+		Name:   metricsNamespace + "_rate_limit_pauses_total",
+		Type:   "counter",
+		Help:   "Times a channel end was paused as a transfer over it exceeded a rate limit of the path",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort},
+	}
+	sequenceResultsSpec = MetricSpec{
+		Name:   metricsNamespace + "_sequence_results_total",
+		Type:   "counter",
+		Help:   "Packets and acknowledgements relayed, skipped or failed, by the channel end of chain_id they were relayed from",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort, LabelStatus},
+	}
+	clientUpdateBisectionDepthSpec = MetricSpec{
+		Name:   metricsNamespace + "_client_update_bisection_depth",
+		Type:   "histogram",
+		Help:   "Intermediate headers a client update was bisected through as the validators changed beyond the trust level of the client, 0 if updated directly",
+		Labels: []string{LabelPath, LabelChainID, LabelClientID},
+	}
+	healthProbeFailingSpec = MetricSpec{
+		Name:   metricsNamespace + "_health_probe_failing",
+		Type:   "gauge",
+		Help:   "1 if a health probe failed as many consecutive times as its failure threshold, degrading or pausing the paths it gates",
+		Labels: []string{LabelProbe},
+	}
+	memoPacketsSpec = MetricSpec{
+		Name:   metricsNamespace + "_memo_packets_total",
+		Type:   "counter",
+		Help:   "Packets with a memo relayed or blocked, by the channel end of chain_id they were sent from and the action of the memo policy applying",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort, LabelAction},
+	}
+	oversizedPacketMessagesSpec = MetricSpec{
+		Name:   metricsNamespace + "_oversized_packet_messages_total",
+		Type:   "counter",
+		Help:   "Packet messages exceeding on their own the max tx bytes of chain_id they are sent to, which cannot be relayed",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort, LabelType},
+	}
+	alertsSpec = MetricSpec{
+		Name:   metricsNamespace + "_alerts_total",
+		Type:   "counter",
+		Help:   "Alerts posted to the alert webhooks, failed or dropped over the rate limit of a webhook, by kind",
+		Labels: []string{LabelKind, LabelStatus},
+	}
+	stuckPacketsSpec = MetricSpec{
+		Name:   metricsNamespace + "_stuck_packets",
+		Type:   "gauge",
+		Help:   "Packets and acknowledgements pending for longer than the stuck packet threshold, by the channel end of chain_id they were sent from or written on, and level",
+		Labels: []string{LabelPath, LabelChainID, LabelChannel, LabelPort, LabelLevel},
+	}
+	storePrunedEntriesSpec = MetricSpec{
+		Name:   metricsNamespace + "_store_pruned_entries_total",
+		Type:   "counter",
+		Help:   "Entries deleted from a store of state persisted by the relayer by its retention policy",
+		Labels: []string{LabelStore},
+	}
+)
+
+// MetricsCatalog returns the specs of all metrics published by the relayer.
+func MetricsCatalog() []MetricSpec {
+	return []MetricSpec{
+		relayedPacketsSpec,
+		failedRelaysSpec,
+		gasUsedSpec,
+		feesEarnedSpec,
+		walletBalanceSpec,
+		clientConsensusStatesSpec,
+		latestFinalizedHeightSpec,
+		monitoredChannelPendingSpec,
+		monitoredChannelOldestPendingSpec,
+		escrowBalanceSpec,
+		storeEntriesSpec,
+		storeSizeSpec,
+		storePrunedEntriesSpec,
+		preconfirmedPacketsSpec,
+		preconfirmationMismatchesSpec,
+		clientChainIDMismatchSpec,
+		watchedClientAlertingSpec,
+		watchedClientExpirySpec,
+		packetsNearingTimeoutSpec,
+		packetsExpiredSpec,
+		orderedChannelNextSequenceRecvSpec,
+		orderedChannelStalledSpec,
+		tokenPriceSpec,
+		rateLimitPausesSpec,
+		sequenceResultsSpec,
+		clientUpdateBisectionDepthSpec,
+		healthProbeFailingSpec,
+		memoPacketsSpec,
+		oversizedPacketMessagesSpec,
+		walletLowBalanceSpec,
+		walletTopUpsSpec,
+		alertsSpec,
+		stuckPacketsSpec,
+	}
+}
+
+// MarshalMetricsCatalog returns the MetricsCatalog as indented JSON, as published in docs/metrics_catalog.json.
+func MarshalMetricsCatalog() ([]byte, error) {
+	bz, err := json.MarshalIndent(MetricsCatalog(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bz, '\n'), nil
+}
+
+func newCounterVec(spec MetricSpec) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{Name: spec.Name, Help: spec.Help}, spec.Labels)
+}
+
+func newGaugeVec(spec MetricSpec) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: spec.Name, Help: spec.Help}, spec.Labels)
+}
+
+// clientUpdateBisectionDepthBuckets cover updates up to the most intermediate headers a client update is bisected through.
+var clientUpdateBisectionDepthBuckets = []float64{0, 1, 2, 4, 8, 16, 31}
+
+func newHistogramVec(spec MetricSpec, buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: spec.Name, Help: spec.Help, Buckets: buckets}, spec.Labels)
+}
+
+// PrometheusMetrics holds the metrics published by the relayer.
+// All methods are safe to call on a nil PrometheusMetrics, which records nothing.
+type PrometheusMetrics struct {
+	Registry *prometheus.Registry
+
+	RelayedPackets        *prometheus.CounterVec
+	FailedRelays          *prometheus.CounterVec
+	GasUsed               *prometheus.CounterVec
+	FeesEarned            *prometheus.CounterVec
+	WalletBalance         *prometheus.GaugeVec
+	ClientConsensusStates *prometheus.GaugeVec
+	LatestFinalizedHeight *prometheus.GaugeVec
+
+	MonitoredChannelPending       *prometheus.GaugeVec
+	MonitoredChannelOldestPending *prometheus.GaugeVec
+
+	EscrowBalance *prometheus.GaugeVec
+
+	StoreEntries       *prometheus.GaugeVec
+	StoreSize          *prometheus.GaugeVec
+	StorePrunedEntries *prometheus.CounterVec
+
+	PreconfirmedPackets       *prometheus.GaugeVec
+	PreconfirmationMismatches *prometheus.CounterVec
+
+	ClientChainIDMismatch *prometheus.GaugeVec
+
+	WatchedClientAlerting *prometheus.GaugeVec
+	WatchedClientExpiry   *prometheus.GaugeVec
+
+	PacketsNearingTimeout *prometheus.GaugeVec
+	PacketsExpired        *prometheus.GaugeVec
+
+	OrderedChannelNextSequenceRecv *prometheus.GaugeVec
+	OrderedChannelStalled          *prometheus.GaugeVec
+
+	TokenPrice *prometheus.GaugeVec
+
+	RateLimitPauses *prometheus.CounterVec
+
+	SequenceResults *prometheus.CounterVec
+
+	ClientUpdateBisectionDepth *prometheus.HistogramVec
+
+	HealthProbeFailing *prometheus.GaugeVec
+
+	MemoPackets *prometheus.CounterVec
+
+	OversizedPacketMessages *prometheus.CounterVec
+
+	WalletLowBalance *prometheus.GaugeVec
+	WalletTopUps     *prometheus.CounterVec
+
+	Alerts *prometheus.CounterVec
+
+	StuckPackets *prometheus.GaugeVec
+}
+
+// NewPrometheusMetrics returns the relayer metrics, registered with a new registry.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		Registry:              prometheus.NewRegistry(),
+		RelayedPackets:        newCounterVec(relayedPacketsSpec),
+		FailedRelays:          newCounterVec(failedRelaysSpec),
+		GasUsed:               newCounterVec(gasUsedSpec),
+		FeesEarned:            newCounterVec(feesEarnedSpec),
+		WalletBalance:         newGaugeVec(walletBalanceSpec),
+		ClientConsensusStates: newGaugeVec(clientConsensusStatesSpec),
+		LatestFinalizedHeight: newGaugeVec(latestFinalizedHeightSpec),
+
+		MonitoredChannelPending:       newGaugeVec(monitoredChannelPendingSpec),
+		MonitoredChannelOldestPending: newGaugeVec(monitoredChannelOldestPendingSpec),
+
+		EscrowBalance: newGaugeVec(escrowBalanceSpec),
+
+		StoreEntries:       newGaugeVec(storeEntriesSpec),
+		StoreSize:          newGaugeVec(storeSizeSpec),
+		StorePrunedEntries: newCounterVec(storePrunedEntriesSpec),
+
+		PreconfirmedPackets:       newGaugeVec(preconfirmedPacketsSpec),
+		PreconfirmationMismatches: newCounterVec(preconfirmationMismatchesSpec),
+
+		ClientChainIDMismatch: newGaugeVec(clientChainIDMismatchSpec),
+
+		WatchedClientAlerting: newGaugeVec(watchedClientAlertingSpec),
+		WatchedClientExpiry:   newGaugeVec(watchedClientExpirySpec),
+
+		PacketsNearingTimeout: newGaugeVec(packetsNearingTimeoutSpec),
+		PacketsExpired:        newGaugeVec(packetsExpiredSpec),
+
+		OrderedChannelNextSequenceRecv: newGaugeVec(orderedChannelNextSequenceRecvSpec),
+		OrderedChannelStalled:          newGaugeVec(orderedChannelStalledSpec),
+
+		TokenPrice: newGaugeVec(tokenPriceSpec),
+
+		RateLimitPauses: newCounterVec(rateLimitPausesSpec),
+
+		SequenceResults: newCounterVec(sequenceResultsSpec),
+
+		ClientUpdateBisectionDepth: newHistogramVec(clientUpdateBisectionDepthSpec, clientUpdateBisectionDepthBuckets),
+
+		HealthProbeFailing: newGaugeVec(healthProbeFailingSpec),
+
+		MemoPackets: newCounterVec(memoPacketsSpec),
+
+		OversizedPacketMessages: newCounterVec(oversizedPacketMessagesSpec),
+
+		WalletLowBalance: newGaugeVec(walletLowBalanceSpec),
+		WalletTopUps:     newCounterVec(walletTopUpsSpec),
+
+		Alerts: newCounterVec(alertsSpec),
+
+		StuckPackets: newGaugeVec(stuckPacketsSpec),
+	}
+	m.Registry.MustRegister(
+		m.RelayedPackets, m.FailedRelays, m.GasUsed, m.FeesEarned, m.WalletBalance, m.ClientConsensusStates, m.LatestFinalizedHeight,
+		m.MonitoredChannelPending, m.MonitoredChannelOldestPending,
+		m.EscrowBalance,
+		m.StoreEntries, m.StoreSize, m.StorePrunedEntries,
+		m.PreconfirmedPackets, m.PreconfirmationMismatches,
+		m.ClientChainIDMismatch,
+		m.WatchedClientAlerting, m.WatchedClientExpiry,
+		m.PacketsNearingTimeout, m.PacketsExpired,
+		m.OrderedChannelNextSequenceRecv, m.OrderedChannelStalled,
+		m.TokenPrice,
+		m.RateLimitPauses,
+		m.SequenceResults,
+		m.ClientUpdateBisectionDepth,
+		m.HealthProbeFailing,
+		m.MemoPackets,
+		m.OversizedPacketMessages,
+		m.WalletLowBalance, m.WalletTopUps,
+		m.Alerts,
+		m.StuckPackets,
+	)
+	return m
+}
+
+// IncRelayedPackets counts packet messages of the given type delivered on a channel of chainID.
+func (m *PrometheusMetrics) IncRelayedPackets(path, chainID, direction, channelID, portID, msgType string) {
+	if m == nil {
+		return
+	}
+	m.RelayedPackets.WithLabelValues(path, chainID, direction, channelID, portID, msgType).Inc()
+}
+
+// IncFailedRelays counts a transaction that could not be sent to chainID.
+func (m *PrometheusMetrics) IncFailedRelays(path, chainID, direction string) {
+	if m == nil {
+		return
+	}
+	m.FailedRelays.WithLabelValues(path, chainID, direction).Inc()
+}
+
+// FailedRelaysOf returns the transactions of path that could not be sent since the relayer started,
+// in both directions.
+func (m *PrometheusMetrics) FailedRelaysOf(path string) float64 {
+	if m == nil {
+		return 0
+	}
+	families, err := m.Registry.Gather()
+	if err != nil {
+		return 0
+	}
+	var total float64
+	for _, f := range families {
+		if f.GetName() != failedRelaysSpec.Name {
+			continue
+		}
+		for _, metric := range f.GetMetric() {
+			for _, l := range metric.GetLabel() {
+				if l.GetName() == LabelPath && l.GetValue() == path {
+					total += metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return total
+}
+
+// AddGasUsed accounts for the gas used by a transaction sent to chainID.
+func (m *PrometheusMetrics) AddGasUsed(path, chainID, direction string, gas int64) {
+	if m == nil || gas <= 0 {
+		return
+	}
+	m.GasUsed.WithLabelValues(path, chainID, direction).Add(float64(gas))
+}
+
+// AddFeesEarned accounts for an ICS-29 fee paid out to the relayer wallet on chainID.
+func (m *PrometheusMetrics) AddFeesEarned(chainID, denom string, amount float64) {
+	if m == nil || amount <= 0 {
+		return
+	}
+	m.FeesEarned.WithLabelValues(chainID, denom).Add(amount)
+}
+
+// SetWalletBalance records the balance of the relayer wallet on chainID.
+func (m *PrometheusMetrics) SetWalletBalance(chainID, address, denom string, amount float64) {
+	if m == nil {
+		return
+	}
+	m.WalletBalance.WithLabelValues(chainID, address, denom).Set(amount)
+}
+
+// SetWalletLowBalance records whether the balance of the monitored relayer wallet on chainID is below its threshold.
+func (m *PrometheusMetrics) SetWalletLowBalance(chainID, address, denom string, low bool) {
+	if m == nil {
+		return
+	}
+	v := 0.0
+	if low {
+		v = 1
+	}
+	m.WalletLowBalance.WithLabelValues(chainID, address, denom).Set(v)
+}
+
+// IncWalletTopUps counts a top-up of the relayer wallet on chainID from its funding account, with its status.
+func (m *PrometheusMetrics) IncWalletTopUps(chainID, address, denom, status string) {
+	if m == nil {
+		return
+	}
+	m.WalletTopUps.WithLabelValues(chainID, address, denom, status).Inc()
+}
+
+// SetClientConsensusStates records the number of consensus states stored on chainID for clientID.
+func (m *PrometheusMetrics) SetClientConsensusStates(chainID, clientID string, count uint64) {
+	if m == nil {
+		return
+	}
+	m.ClientConsensusStates.WithLabelValues(chainID, clientID).Set(float64(count))
+}
+
+// SetLatestFinalizedHeight records the latest finalized height observed for a rollapp.
+func (m *PrometheusMetrics) SetLatestFinalizedHeight(chainID string, height int64) {
+	if m == nil || height < 0 {
+		return
+	}
+	m.LatestFinalizedHeight.WithLabelValues(chainID).Set(float64(height))
+}
+
+// SetMonitoredChannel records the backlog last observed on an end of a monitor-only channel of path,
+// and how long its oldest message has been pending.
+func (m *PrometheusMetrics) SetMonitoredChannel(path, chainID, channelID, portID string, pending int, oldestPending time.Duration) {
+	if m == nil {
+		return
+	}
+	m.MonitoredChannelPending.WithLabelValues(path, chainID, channelID, portID).Set(float64(pending))
+	m.MonitoredChannelOldestPending.WithLabelValues(path, chainID, channelID, portID).Set(oldestPending.Seconds())
+}
+
+// SetEscrowBalance records the balance last observed in the escrow account of a transfer channel end on chainID.
+func (m *PrometheusMetrics) SetEscrowBalance(path, chainID, channelID, portID, denom string, amount float64) {
+	if m == nil {
+		return
+	}
+	m.EscrowBalance.WithLabelValues(path, chainID, channelID, portID, denom).Set(amount)
+}
+
+// SetStoreSize records the entries last counted in a persisted store and its size on disk.
+func (m *PrometheusMetrics) SetStoreSize(store string, entries int, sizeBytes int64) {
+	if m == nil {
+		return
+	}
+	m.StoreEntries.WithLabelValues(store).Set(float64(entries))
+	m.StoreSize.WithLabelValues(store).Set(float64(sizeBytes))
+}
+
+// AddStorePruned counts entries deleted from a persisted store by its retention policy.
+func (m *PrometheusMetrics) AddStorePruned(store string, entries int) {
+	if m == nil || entries <= 0 {
+		return
+	}
+	m.StorePrunedEntries.WithLabelValues(store).Add(float64(entries))
+}
+
+// SetPreconfirmedPackets records the packets sent on chainID relayed for path before finalization, awaiting it.
+func (m *PrometheusMetrics) SetPreconfirmedPackets(path, chainID string, n int) {
+	if m == nil {
+		return
+	}
+	m.PreconfirmedPackets.WithLabelValues(path, chainID).Set(float64(n))
+}
+
+// IncPreconfirmationMismatches counts a packet relayed for path before finalization that the finalized blocks
+// of chainID do not include as relayed.
+func (m *PrometheusMetrics) IncPreconfirmationMismatches(path, chainID string) {
+	if m == nil {
+		return
+	}
+	m.PreconfirmationMismatches.WithLabelValues(path, chainID).Inc()
+}
+
+// SetClientChainIDMismatch records whether the client clientID on chainID was last found not to track its live counterparty.
+func (m *PrometheusMetrics) SetClientChainIDMismatch(path, chainID, clientID string, mismatch bool) {
+	if m == nil {
+		return
+	}
+	v := 0.0
+	if mismatch {
+		v = 1
+	}
+	m.ClientChainIDMismatch.WithLabelValues(path, chainID, clientID).Set(v)
+}
+
+// SetWatchedClient records whether an alert is raised on a client hosted on chainID watched by the watchtower,
+// and the time left before it expires.
+func (m *PrometheusMetrics) SetWatchedClient(chainID, clientID string, alerting bool, expiresIn time.Duration) {
+	if m == nil {
+		return
+	}
+	v := 0.0
+	if alerting {
+		v = 1
+	}
+	m.WatchedClientAlerting.WithLabelValues(chainID, clientID).Set(v)
+	m.WatchedClientExpiry.WithLabelValues(chainID, clientID).Set(expiresIn.Seconds())
+}
+
+// SetPendingTimeouts records the packets sent on a channel end of path on chainID and pending relay
+// that were last found approaching their timeout, and those whose timeout elapsed.
+func (m *PrometheusMetrics) SetPendingTimeouts(path, chainID, channelID, portID string, nearing, expired int) {
+	if m == nil {
+		return
+	}
+	m.PacketsNearingTimeout.WithLabelValues(path, chainID, channelID, portID).Set(float64(nearing))
+	m.PacketsExpired.WithLabelValues(path, chainID, channelID, portID).Set(float64(expired))
+}
+
+// SetOrderedChannel records the next sequence to receive of an end of an ordered channel of path on chainID,
+// and whether it is stalled.
+func (m *PrometheusMetrics) SetOrderedChannel(path, chainID, channelID, portID string, nextSequenceRecv uint64, stalled bool) {
+	if m == nil {
+		return
+	}
+	m.OrderedChannelNextSequenceRecv.WithLabelValues(path, chainID, channelID, portID).Set(float64(nextSequenceRecv))
+	var v float64
+	if stalled {
+		v = 1
+	}
+	m.OrderedChannelStalled.WithLabelValues(path, chainID, channelID, portID).Set(v)
+}
+
+// SetTokenPrice records the USD price of one base unit of denom on chainID.
+func (m *PrometheusMetrics) SetTokenPrice(chainID, denom string, usd float64) {
+	if m == nil {
+		return
+	}
+	m.TokenPrice.WithLabelValues(chainID, denom).Set(usd)
+}
+
+// IncRateLimitPauses counts a pause of a channel end of path on chainID by a rate limit of the path.
+func (m *PrometheusMetrics) IncRateLimitPauses(path, chainID, channelID, portID string) {
+	if m == nil {
+		return
+	}
+	m.RateLimitPauses.WithLabelValues(path, chainID, channelID, portID).Inc()
+}
+
+// IncSequenceResults counts the outcome of relaying a packet or acknowledgement from a channel end of path on chainID.
+func (m *PrometheusMetrics) IncSequenceResults(path, chainID, channelID, portID, status string) {
+	if m == nil {
+		return
+	}
+	m.SequenceResults.WithLabelValues(path, chainID, channelID, portID, status).Inc()
+}
+
+// ObserveClientUpdateBisectionDepth records the intermediate headers an update of a client of path on chainID was bisected through.
+func (m *PrometheusMetrics) ObserveClientUpdateBisectionDepth(path, chainID, clientID string, depth int) {
+	if m == nil {
+		return
+	}
+	m.ClientUpdateBisectionDepth.WithLabelValues(path, chainID, clientID).Observe(float64(depth))
+}
+
+// SetHealthProbeFailing records whether the health probe is failing.
+func (m *PrometheusMetrics) SetHealthProbeFailing(probe string, failing bool) {
+	if m == nil {
+		return
+	}
+	v := 0.0
+	if failing {
+		v = 1
+	}
+	m.HealthProbeFailing.WithLabelValues(probe).Set(v)
+}
+
+// IncMemoPackets counts a packet with a memo sent from a channel end of path on chainID, relayed or blocked
+// according to action.
+func (m *PrometheusMetrics) IncMemoPackets(path, chainID, channelID, portID, action string) {
+	if m == nil {
+		return
+	}
+	m.MemoPackets.WithLabelValues(path, chainID, channelID, portID, action).Inc()
+}
+
+// IncOversizedPacketMessages counts a packet message of msgType sent to chainID over a channel end of path,
+// which exceeds the max tx bytes of chainID on its own.
+func (m *PrometheusMetrics) IncOversizedPacketMessages(path, chainID, channelID, portID, msgType string) {
+	if m == nil {
+		return
+	}
+	m.OversizedPacketMessages.WithLabelValues(path, chainID, channelID, portID, msgType).Inc()
+}
+
+// IncAlerts counts an alert of kind posted to a webhook, with its status.
+func (m *PrometheusMetrics) IncAlerts(kind, status string) {
+	if m == nil {
+		return
+	}
+	m.Alerts.WithLabelValues(kind, status).Inc()
+}
+
+// SetStuckPackets records the packets and acknowledgements of a channel end of path on chainID stuck at each level.
+func (m *PrometheusMetrics) SetStuckPackets(path, chainID, channelID, portID string, warning, critical int) {
+	if m == nil {
+		return
+	}
+	m.StuckPackets.WithLabelValues(path, chainID, channelID, portID, "warning").Set(float64(warning))
+	m.StuckPackets.WithLabelValues(path, chainID, channelID, portID, "critical").Set(float64(critical))
+}
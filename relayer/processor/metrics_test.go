@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The published catalog is the contract dashboards and alerts rely on,
+// changing a metric must be a deliberate change of docs/metrics_catalog.json.
+func TestMetricsCatalogUpToDate(t *testing.T) {
+	published, err := os.ReadFile("../../docs/metrics_catalog.json")
+	require.NoError(t, err)
+
+	generated, err := MarshalMetricsCatalog()
+	require.NoError(t, err)
+	require.Equal(t, string(published), string(generated), "metrics changed, run make metrics-catalog")
+}
+
+func TestMetricsCatalogLabels(t *testing.T) {
+	contract := map[string]bool{
+		LabelPath:      true,
+		LabelChainID:   true,
+		LabelDirection: true,
+		LabelChannel:   true,
+		LabelPort:      true,
+		LabelType:      true,
+		LabelAddress:   true,
+		LabelDenom:     true,
+		LabelClientID:  true,
+		LabelStore:     true,
+		LabelStatus:    true,
+		LabelProbe:     true,
+		LabelAction:    true,
+		LabelKind:      true,
+		LabelLevel:     true,
+	}
+	m := NewPrometheusMetrics()
+	m.IncRelayedPackets("demo-path", "chain-a", DirectionSrcToDst, "channel-0", "transfer", MetricRecvPacket)
+	m.IncFailedRelays("demo-path", "chain-a", DirectionSrcToDst)
+	m.AddGasUsed("demo-path", "chain-a", DirectionSrcToDst, 1)
+	m.AddFeesEarned("chain-a", "uatom", 1)
+	m.SetWalletBalance("chain-a", "cosmos1...", "uatom", 1)
+	m.SetClientConsensusStates("chain-a", "07-tendermint-0", 1)
+	m.SetLatestFinalizedHeight("chain-a", 1)
+	m.SetMonitoredChannel("demo-path", "chain-a", "channel-0", "transfer", 1, time.Minute)
+	m.SetEscrowBalance("demo-path", "chain-a", "channel-0", "transfer", "uatom", 1)
+	m.SetStoreSize(StoreAcks, 1, 1)
+	m.AddStorePruned(StoreAcks, 1)
+	m.SetPreconfirmedPackets("demo-path", "rollapp", 1)
+	m.IncPreconfirmationMismatches("demo-path", "rollapp")
+	m.SetClientChainIDMismatch("demo-path", "chain-a", "07-tendermint-0", true)
+	m.SetWatchedClient("chain-a", "07-tendermint-0", true, time.Hour)
+	m.SetPendingTimeouts("demo-path", "chain-a", "channel-0", "transfer", 1, 1)
+	m.SetOrderedChannel("demo-path", "chain-a", "channel-0", "icahost", 1, true)
+	m.SetTokenPrice("chain-a", "uatom", 0.00001)
+	m.IncRateLimitPauses("demo-path", "chain-a", "channel-0", "transfer")
+	m.IncSequenceResults("demo-path", "chain-a", "channel-0", "transfer", "relayed")
+	m.ObserveClientUpdateBisectionDepth("demo-path", "chain-a", "07-tendermint-0", 2)
+	m.SetHealthProbeFailing("sequencer-heartbeat", true)
+	m.IncMemoPackets("demo-path", "chain-a", "channel-0", "transfer", MemoTruncate)
+	m.IncOversizedPacketMessages("demo-path", "chain-a", "channel-0", "transfer", MetricAckPacket)
+	m.SetWalletLowBalance("chain-a", "cosmos1...", "uatom", true)
+	m.IncWalletTopUps("chain-a", "cosmos1...", "uatom", "sent")
+	m.IncAlerts("relay_errors", "sent")
+	m.SetStuckPackets("demo-path", "chain-a", "channel-0", "transfer", 1, 0)
+
+	require.Equal(t, float64(1), m.FailedRelaysOf("demo-path"))
+	require.Zero(t, m.FailedRelaysOf("other-path"))
+
+	families, err := m.Registry.Gather()
+	require.NoError(t, err)
+
+	catalog := make(map[string]MetricSpec)
+	for _, spec := range MetricsCatalog() {
+		catalog[spec.Name] = spec
+		for _, l := range spec.Labels {
+			require.True(t, contract[l], "metric %s has label %s outside of the contract", spec.Name, l)
+		}
+	}
+
+	// Every registered metric is in the catalog, with the labels it is published with.
+	require.Len(t, families, len(catalog))
+	for _, f := range families {
+		spec, ok := catalog[f.GetName()]
+		require.True(t, ok, "metric %s is missing from the catalog", f.GetName())
+		var labels []string
+		for _, l := range f.GetMetric()[0].GetLabel() {
+			labels = append(labels, l.GetName())
+		}
+		require.ElementsMatch(t, spec.Labels, labels)
+	}
+}
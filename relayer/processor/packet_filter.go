@@ -0,0 +1,105 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// USDPricer prices one base unit of denom on the chain chainID in USD, returning false if the price is not known.
+type USDPricer interface {
+	USD(chainID, denom string) (float64, bool)
+}
+
+// PacketFilter skips the ICS-20 transfers that are not worth the gas of relaying them:
+// transfers of a denylisted denom, and transfers of less than the minimum amount of their denom,
+// or worth less than a minimum in USD. Packets with a memo blocked by a MemoPolicy are skipped too.
+// Other packets, including those of other applications, are always relayed.
+// Skipped packets are not relayed at all, neither received nor timed out.
+type PacketFilter struct {
+	minAmounts map[string]*big.Int
+	denyDenoms map[string]bool
+
+	minUSD float64
+	prices USDPricer
+
+	memoChainID  string
+	memoPolicies []MemoPolicy
+}
+
+// NewPacketFilter returns a filter skipping the transfers of the denoms in denyDenoms, and those
+// of less than minAmounts of their denom. Denoms are matched as they appear in the packet data,
+// i.e. prefixed with the trace of the channels they were transferred over unless native to the sender.
+func NewPacketFilter(minAmounts map[string]*big.Int, denyDenoms []string) *PacketFilter {
+	f := &PacketFilter{minAmounts: minAmounts, denyDenoms: make(map[string]bool, len(denyDenoms))}
+	for _, d := range denyDenoms {
+		f.denyDenoms[d] = true
+	}
+	return f
+}
+
+// SetMinUSD skips the transfers worth less than minUSD according to the prices set with SetPrices.
+// Transfers of tokens without a known price are relayed.
+func (f *PacketFilter) SetMinUSD(minUSD float64) {
+	f.minUSD = minUSD
+}
+
+// MinUSD returns the minimum USD value of the transfers relayed, 0 if there is none.
+func (f *PacketFilter) MinUSD() float64 {
+	if f == nil {
+		return 0
+	}
+	return f.minUSD
+}
+
+// SetPrices prices the transfers in USD with prices, see SetMinUSD.
+func (f *PacketFilter) SetPrices(prices USDPricer) {
+	f.prices = prices
+}
+
+// Skip returns why the packet with the given data, sent from the chain chainID over the channel identified by k
+// from the perspective of chainID, is skipped, or an empty string if it is relayed.
+func (f *PacketFilter) Skip(chainID string, k ChannelKey, data []byte) string {
+	if f == nil {
+		return ""
+	}
+	if reason := f.blockedMemo(chainID, k, data); reason != "" {
+		return reason
+	}
+	denom, amount, ok := transferData(data)
+	if !ok {
+		return ""
+	}
+	if f.denyDenoms[denom] {
+		return fmt.Sprintf("denom %s is denylisted", denom)
+	}
+	if min, ok := f.minAmounts[denom]; ok {
+		a, ok := new(big.Int).SetString(amount, 10)
+		if ok && a.Cmp(min) < 0 {
+			return fmt.Sprintf("amount %s%s is below the minimum of %s%s", amount, denom, min, denom)
+		}
+	}
+	if f.minUSD > 0 && f.prices != nil {
+		price, ok := f.prices.USD(chainID, denom)
+		a, valid := new(big.Float).SetString(amount)
+		if ok && valid {
+			usd, _ := new(big.Float).Mul(a, big.NewFloat(price)).Float64()
+			if usd < f.minUSD {
+				return fmt.Sprintf("amount %s%s is worth %.2f USD, below the minimum of %.2f USD", amount, denom, usd, f.minUSD)
+			}
+		}
+	}
+	return ""
+}
+
+// transferData returns the denom and amount of ICS-20 fungible token packet data.
+func transferData(data []byte) (denom, amount string, ok bool) {
+	var transfer struct {
+		Denom  string `json:"denom"`
+		Amount string `json:"amount"`
+	}
+	if json.Unmarshal(data, &transfer) != nil || transfer.Denom == "" || transfer.Amount == "" {
+		return "", "", false
+	}
+	return transfer.Denom, transfer.Amount, true
+}
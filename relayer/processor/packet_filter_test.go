@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPacketFilter(t *testing.T) {
+	f := NewPacketFilter(map[string]*big.Int{"urax": big.NewInt(1000)}, []string{"transfer/channel-9/uspam"})
+
+	require.Empty(t, f.Skip("chain-a", ChannelKey{}, []byte(`{"denom":"urax","amount":"1000","sender":"a","receiver":"b"}`)))
+	require.Equal(t, "amount 999urax is below the minimum of 1000urax", f.Skip("chain-a", ChannelKey{}, []byte(`{"denom":"urax","amount":"999"}`)))
+	require.Equal(t, "denom transfer/channel-9/uspam is denylisted", f.Skip("chain-a", ChannelKey{}, []byte(`{"denom":"transfer/channel-9/uspam","amount":"1000000"}`)))
+
+	// Denoms without a minimum and packets of other applications are relayed.
+	require.Empty(t, f.Skip("chain-a", ChannelKey{}, []byte(`{"denom":"uatom","amount":"1"}`)))
+	require.Empty(t, f.Skip("chain-a", ChannelKey{}, []byte(`not a transfer`)))
+
+	var none *PacketFilter
+	require.Empty(t, none.Skip("chain-a", ChannelKey{}, []byte(`{"denom":"urax","amount":"1"}`)))
+}
+
+type staticPrices map[string]float64
+
+func (p staticPrices) USD(chainID, denom string) (float64, bool) {
+	price, ok := p[chainID+"/"+denom]
+	return price, ok
+}
+
+func TestPacketFilterMinUSD(t *testing.T) {
+	f := NewPacketFilter(nil, nil)
+	f.SetMinUSD(1)
+
+	// Without prices, nothing is skipped.
+	require.Empty(t, f.Skip("rollapp", ChannelKey{}, []byte(`{"denom":"urax","amount":"1"}`)))
+
+	f.SetPrices(staticPrices{"rollapp/urax": 0.000001})
+	require.Empty(t, f.Skip("rollapp", ChannelKey{}, []byte(`{"denom":"urax","amount":"1000000"}`)))
+	require.Equal(t, "amount 999999urax is worth 1.00 USD, below the minimum of 1.00 USD", f.Skip("rollapp", ChannelKey{}, []byte(`{"denom":"urax","amount":"999999"}`)))
+
+	// Tokens are priced on the chain they were sent from, and relayed when their price is unknown.
+	require.Empty(t, f.Skip("hub", ChannelKey{}, []byte(`{"denom":"urax","amount":"1"}`)))
+}
+
+func TestPacketFilterSkipsPackets(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "chain-a"}, PathEnd{ChainID: "chain-b"}, "")
+	pp.SetPacketFilter(NewPacketFilter(map[string]*big.Int{"urax": big.NewInt(1000)}, nil))
+
+	dust := provider.PacketInfo{Sequence: 1, SourceChannel: "channel-0", SourcePort: "transfer", Data: []byte(`{"denom":"urax","amount":"1"}`)}
+	res := pp.getUnrelayedPacketsAndAcksAndToDelete(context.Background(), pathEndPacketFlowMessages{
+		Src:              pp.pathEnd1,
+		Dst:              pp.pathEnd2,
+		SrcMsgTransfer:   PacketSequenceCache{1: dust},
+		DstMsgRecvPacket: PacketSequenceCache{1: dust},
+	})
+
+	// Skipped packets are neither relayed nor acknowledged, and are forgotten.
+	require.Empty(t, res.SrcMessages)
+	require.Empty(t, res.DstMessages)
+	require.Equal(t, []uint64{1}, res.ToDeleteSrc[chantypes.EventTypeSendPacket])
+	require.Equal(t, []uint64{1}, res.ToDeleteDst[chantypes.EventTypeRecvPacket])
+}
@@ -10,6 +10,9 @@ type PathEnd struct {
 	// Can be either "allowlist" or "denylist"
 	Rule       string
 	FilterList []ChannelKey // which channels to allow or deny
+
+	// ExcludeList holds channels which are never relayed, whatever the rule, e.g. monitor-only channels.
+	ExcludeList []ChannelKey
 }
 
 // NewPathEnd constructs a PathEnd, validating initial parameters.
@@ -27,23 +30,25 @@ const (
 	RuleDenyList  = "denylist"
 )
 
+// checkChannelMatch returns true if either end of channelKey matches the channel and port IDs of a filter list entry,
+// which are patterns, see MatchChannelPattern.
 func (pe PathEnd) checkChannelMatch(listChannelID, listPortID string, channelKey ChannelKey) bool {
 	if listChannelID == "" {
 		return false
 	}
-	if listChannelID == channelKey.ChannelID {
+	if MatchChannelPattern(listChannelID, channelKey.ChannelID) {
 		if listPortID == "" {
 			return true
 		}
-		if listPortID == channelKey.PortID {
+		if MatchChannelPattern(listPortID, channelKey.PortID) {
 			return true
 		}
 	}
-	if listChannelID == channelKey.CounterpartyChannelID {
+	if MatchChannelPattern(listChannelID, channelKey.CounterpartyChannelID) {
 		if listPortID == "" {
 			return true
 		}
-		if listPortID == channelKey.CounterpartyPortID {
+		if MatchChannelPattern(listPortID, channelKey.CounterpartyPortID) {
 			return true
 		}
 	}
@@ -65,6 +70,11 @@ func (pe PathEnd) shouldRelayChannelSingle(channelKey ChannelKey, listChannel Ch
 // if port ID is empty on blocklist channel, block all ports
 // if port ID is non-empty on blocklist channel, block only that specific port
 func (pe PathEnd) ShouldRelayChannel(channelKey ChannelKey) bool {
+	for _, excludedChannel := range pe.ExcludeList {
+		if !pe.shouldRelayChannelSingle(channelKey, excludedChannel, false) {
+			return false
+		}
+	}
 	if pe.Rule == RuleAllowList {
 		for _, allowedChannel := range pe.FilterList {
 			if pe.shouldRelayChannelSingle(channelKey, allowedChannel, true) {
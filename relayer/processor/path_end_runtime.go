@@ -43,6 +43,27 @@ type pathEndRuntime struct {
 
 	// inSync indicates whether queries are in sync with latest height of the chain.
 	inSync bool
+
+	// Packets sent on a path end with a finality gater are only relayed once their block is finalized.
+	finalityGater   FinalityGater
+	finalizedHeight int64
+
+	// Packets sent on a path end with preconfirmations are relayed before their block is finalized,
+	// and recorded until reconciled with the finalized blocks.
+	preconfirmations *PreconfirmationTracker
+
+	// Packets held back on finality on a path end with held packets are tracked with the state update holding them.
+	heldPackets *heldPackets
+
+	// Packets sent on a path end are only relayed once confirmations blocks were built on top of their block.
+	confirmations uint64
+
+	// The packets sent on a path end skipping packets are relayed by another relayer, see PathProcessor.SkipPacketsSentOn.
+	skipPackets bool
+
+	// The transactions sent to a path end with maxTxBytes are filled with packet messages up to that size,
+	// see PathProcessor.SetMaxTxBytes.
+	maxTxBytes uint64
 }
 
 func newPathEndRuntime(log *zap.Logger, pathEnd PathEnd) *pathEndRuntime {
@@ -82,6 +103,17 @@ func (pathEnd *pathEndRuntime) isRelevantChannel(channelID string) bool {
 	return false
 }
 
+// isChannelOpening reports whether the channel of k is known to this chain and has yet to open.
+// Channels in INIT are known by their key without counterparty channel ID.
+func (pathEnd *pathEndRuntime) isChannelOpening(k ChannelKey) bool {
+	open, ok := pathEnd.channelStateCache[k]
+	if ok {
+		return !open
+	}
+	open, ok = pathEnd.channelStateCache[k.msgInitKey()]
+	return ok && !open
+}
+
 // mergeMessageCache merges relevant IBC messages for packet flows, connection handshakes, and channel handshakes.
 func (pathEnd *pathEndRuntime) mergeMessageCache(messageCache IBCMessagesCache) {
 	packetMessages := make(ChannelPacketMessagesCache)
@@ -172,3 +172,50 @@ func TestBlockSpecificPortForChannel(t *testing.T) {
 	}
 	require.True(t, mockPathEnd.ShouldRelayChannel(mockAllowedChannel2), "does not allow counterparty channel to be relayed, even though portID is not in block list")
 }
+
+func TestChannelPatterns(t *testing.T) {
+	mockPathEnd := processor.PathEnd{
+		Rule: processor.RuleAllowList,
+		FilterList: []processor.ChannelKey{
+			{ChannelID: "channel-*", PortID: "transfer"},
+			{ChannelID: "*", PortID: "icahost"},
+			{ChannelID: "/^channel-(7|12)$/", PortID: "wasm.*"},
+		},
+	}
+
+	require.True(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-42", PortID: "transfer"}))
+	require.True(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-3", PortID: "icahost"}))
+	require.True(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-12", PortID: "wasm.osmo1contract"}))
+	require.False(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-120", PortID: "wasm.osmo1contract"}))
+	require.False(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-42", PortID: "oracle"}))
+
+	// test counterparty
+	require.True(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{CounterpartyChannelID: "channel-1", CounterpartyPortID: "transfer"}))
+
+	mockPathEnd.Rule = processor.RuleDenyList
+	require.False(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-42", PortID: "transfer"}))
+	require.True(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-42", PortID: "oracle"}))
+}
+
+func TestExcludedChannels(t *testing.T) {
+	mockPathEnd := processor.PathEnd{
+		ExcludeList: []processor.ChannelKey{{ChannelID: "channel-1", PortID: "transfer"}},
+	}
+	require.False(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-1", PortID: "transfer"}))
+	require.True(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-1", PortID: "icahost"}))
+
+	// Excluded channels are not relayed even if allowed.
+	mockPathEnd.Rule = processor.RuleAllowList
+	mockPathEnd.FilterList = []processor.ChannelKey{{ChannelID: "channel-*"}}
+	require.False(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-1", PortID: "transfer"}))
+	require.True(t, mockPathEnd.ShouldRelayChannel(processor.ChannelKey{ChannelID: "channel-2", PortID: "transfer"}))
+}
+
+func TestValidateChannelPattern(t *testing.T) {
+	for _, pattern := range []string{"channel-0", "channel-*", "chan?el-[0-9]", "/^channel-\\d+$/"} {
+		require.NoError(t, processor.ValidateChannelPattern(pattern), pattern)
+	}
+	for _, pattern := range []string{"channel-[", "/channel-(/"} {
+		require.Error(t, processor.ValidateChannelPattern(pattern), pattern)
+	}
+}
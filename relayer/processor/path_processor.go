@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cosmos/relayer/v2/relayer/feed"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"go.uber.org/zap"
 )
@@ -55,10 +56,67 @@ type PathProcessor struct {
 
 	memo string
 
+	// name of the path, used to schedule the transactions sent for it fairly with other paths
+	pathName string
+
 	// Signals to retry.
 	retryProcess chan struct{}
 
 	sentInitialMsg bool
+
+	// readOnly paths keep track of the packet flow without assembling or sending any messages.
+	readOnly bool
+
+	// publishes the transactions sent by the PathProcessor, if non-nil
+	feed *feed.Publisher
+
+	// records the transactions sent by the PathProcessor, if non-nil
+	metrics *PrometheusMetrics
+
+	// channels it reports as paused are not relayed, if non-nil
+	pauser ChannelPauser
+
+	// packets it skips are not relayed, if non-nil
+	packetFilter *PacketFilter
+
+	// transfers over its limits are held back, if non-nil
+	rateLimiter *RateLimiter
+
+	// captures the packet flow decisions requested through it, if non-nil
+	decisionCapture *DecisionCapture
+
+	// bumps the fees of the transactions of the path while enabled, if non-nil
+	priority *provider.Priority
+
+	// enables the experimental features of the path, the default ones if nil
+	features FeatureGate
+
+	// records the attempts at relaying the packets of the path, if non-nil
+	relayAttempts *RelayAttempts
+
+	// acknowledgements are held back to be relayed together, if non-nil
+	ackAggregator *AckAggregator
+
+	// ackRelease retries processing once the acknowledgements held back by ackAggregator are released.
+	// Only accessed by the Run loop.
+	ackRelease   *time.Timer
+	ackReleaseAt time.Time
+}
+
+// packetSkipper skips the packets not worth relaying, see PacketFilter.
+type packetSkipper interface {
+	Skip(chainID string, k ChannelKey, data []byte) string
+}
+
+// packetAllower holds back the transfers over a limit, see RateLimiter.
+type packetAllower interface {
+	Allow(chainID string, k ChannelKey, seq uint64, data []byte) string
+}
+
+// ChannelPauser reports which channels of a path are paused, e.g. by an operator.
+type ChannelPauser interface {
+	// IsChannelPaused reports whether the channel, identified by its key from the perspective of chainID, is paused.
+	IsChannelPaused(chainID string, k ChannelKey) bool
 }
 
 // PathProcessors is a slice of PathProcessor instances
@@ -83,6 +141,130 @@ func NewPathProcessor(log *zap.Logger, pathEnd1 PathEnd, pathEnd2 PathEnd, memo
 	}
 }
 
+// SetPathName names the path relayed by the PathProcessor. Must be called before Run.
+func (pp *PathProcessor) SetPathName(name string) {
+	pp.pathName = name
+}
+
+// SetReadOnly configures the PathProcessor to only observe the path, never sending messages to either chain.
+// Keys are not required for observing a path. Must be called before Run.
+func (pp *PathProcessor) SetReadOnly(readOnly bool) {
+	pp.readOnly = readOnly
+}
+
+// SetFeed publishes the client updates, packets and acknowledgements relayed by the PathProcessor,
+// as well as failures to relay them, to the given feed. Must be called before Run.
+func (pp *PathProcessor) SetFeed(p *feed.Publisher) {
+	pp.feed = p
+}
+
+// SetMetrics records the packet messages relayed by the PathProcessor, the gas used to relay them
+// and failures to relay them to the given metrics. Must be called before Run.
+func (pp *PathProcessor) SetMetrics(m *PrometheusMetrics) {
+	pp.metrics = m
+}
+
+// SetChannelPauser skips the channels paused according to the pauser until they are resumed,
+// keeping track of their packet flow so that it is relayed once resumed. Must be called before Run.
+func (pp *PathProcessor) SetChannelPauser(p ChannelPauser) {
+	pp.pauser = p
+}
+
+// SetPacketFilter skips the packets of both directions of the path that f skips, forgetting them.
+// Must be called before Run.
+func (pp *PathProcessor) SetPacketFilter(f *PacketFilter) {
+	pp.packetFilter = f
+}
+
+// SetRateLimiter holds back the transfers of both directions of the path exceeding the limits of l,
+// keeping track of them so that they are relayed once allowed. Must be called before Run.
+func (pp *PathProcessor) SetRateLimiter(l *RateLimiter) {
+	pp.rateLimiter = l
+}
+
+// SetDecisionCapture captures the packet flow decisions requested through c, see DecisionBundle.
+// Must be called before Run.
+func (pp *PathProcessor) SetDecisionCapture(c *DecisionCapture) {
+	pp.decisionCapture = c
+}
+
+// SetPriority bumps the fees of the transactions sent for the path while p is enabled, see provider.Priority.
+// Must be called before Run.
+func (pp *PathProcessor) SetPriority(p *provider.Priority) {
+	pp.priority = p
+}
+
+// SetAckAggregator holds back the acknowledgements of both directions of the path to relay them together,
+// see AckAggregator. Must be called before Run.
+func (pp *PathProcessor) SetAckAggregator(a *AckAggregator) {
+	pp.ackAggregator = a
+}
+
+// SetFinalityGater only relays packets sent on the given chain of the path to the counterparty
+// once the block they were sent in has been finalized according to the gater. Must be called before Run.
+func (pp *PathProcessor) SetFinalityGater(chainID string, g FinalityGater) {
+	for _, pathEnd := range []*pathEndRuntime{pp.pathEnd1, pp.pathEnd2} {
+		if pathEnd.info.ChainID == chainID {
+			pathEnd.finalityGater = g
+		}
+	}
+}
+
+// SetConfirmations only relays packets sent on the given chain of the path to the counterparty once n blocks
+// were built since the block they were sent in, protecting against relaying packets of blocks reorganized away
+// on chains with fast blocks or unstable heads. Must be called before Run.
+func (pp *PathProcessor) SetConfirmations(chainID string, n uint64) {
+	for _, pathEnd := range []*pathEndRuntime{pp.pathEnd1, pp.pathEnd2} {
+		if pathEnd.info.ChainID == chainID {
+			pathEnd.confirmations = n
+		}
+	}
+}
+
+// SetMaxTxBytes fills the transactions sent to the given chain of the path with packet messages up to maxTxBytes,
+// the size of the largest transaction it accepts, deferring the packet messages in excess to the next transactions.
+// Must be called before Run.
+func (pp *PathProcessor) SetMaxTxBytes(chainID string, maxTxBytes uint64) {
+	for _, pathEnd := range []*pathEndRuntime{pp.pathEnd1, pp.pathEnd2} {
+		if pathEnd.info.ChainID == chainID {
+			pathEnd.maxTxBytes = maxTxBytes
+		}
+	}
+}
+
+// SkipPacketsSentOn leaves the packets sent on the given chain of the path to another relayer: they are neither
+// received on the counterparty nor acknowledged or timed out, though their packet flow is still followed to forget it
+// once complete. Must be called before Run.
+func (pp *PathProcessor) SkipPacketsSentOn(chainID string) {
+	for _, pathEnd := range []*pathEndRuntime{pp.pathEnd1, pp.pathEnd2} {
+		if pathEnd.info.ChainID == chainID {
+			pathEnd.skipPackets = true
+		}
+	}
+}
+
+// SetPreconfirmation relays the packets sent on the given chain of the path, gated on finality with SetFinalityGater,
+// without waiting for their block to be finalized. Each packet relayed before its block is finalized is recorded in t
+// until the block is, when the packet is reconciled with the finalized blocks. Must be called before Run.
+func (pp *PathProcessor) SetPreconfirmation(chainID string, t *PreconfirmationTracker) {
+	for _, pathEnd := range []*pathEndRuntime{pp.pathEnd1, pp.pathEnd2} {
+		if pathEnd.info.ChainID == chainID {
+			pathEnd.preconfirmations = t
+		}
+	}
+}
+
+// SetHeldPackets records in t the packets sent on the given chain of the path, gated on finality with SetFinalityGater,
+// while they are held back, resolving with q the state update of the settlement layer holding each of them.
+// Packets of reverted state updates are dropped. Must be called before Run.
+func (pp *PathProcessor) SetHeldPackets(chainID string, q SettlementStateQuerier, t *HeldPacketTracker) {
+	for _, pathEnd := range []*pathEndRuntime{pp.pathEnd1, pp.pathEnd2} {
+		if pathEnd.info.ChainID == chainID {
+			pathEnd.heldPackets = &heldPackets{querier: q, tracker: t}
+		}
+	}
+}
+
 // TEST USE ONLY
 func (pp *PathProcessor) PathEnd1Messages(channelKey ChannelKey, message string) PacketSequenceCache {
 	return pp.pathEnd1.messageCache.PacketFlow[channelKey][message]
@@ -127,17 +309,15 @@ func (pp *PathProcessor) channelPairs() []channelPair {
 	for k, open := range pp.pathEnd2.channelStateCache {
 		channels[k.Counterparty()] = open
 	}
-	pairs := make([]channelPair, len(channels))
-	i := 0
+	pairs := make([]channelPair, 0, len(channels))
 	for k, open := range channels {
-		if !open {
+		if !open || (pp.pauser != nil && pp.pauser.IsChannelPaused(pp.pathEnd1.info.ChainID, k)) {
 			continue
 		}
-		pairs[i] = channelPair{
+		pairs = append(pairs, channelPair{
 			pathEnd1ChannelKey: k,
 			pathEnd2ChannelKey: k.Counterparty(),
-		}
-		i++
+		})
 	}
 	return pairs
 }
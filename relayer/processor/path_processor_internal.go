@@ -5,10 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
 	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/feed"
 	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/cosmos/relayer/v2/relayer/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -34,6 +39,13 @@ func (pp *PathProcessor) assemblePacketIBCMessage(
 }
 
 func (pp *PathProcessor) getUnrelayedPacketsAndAcksAndToDelete(ctx context.Context, pathEndPacketFlowMessages pathEndPacketFlowMessages) pathEndPacketFlowResponse {
+	return pp.packetFlowDecision(ctx, pathEndPacketFlowMessages, pp.packetFilter, pp.rateLimiter)
+}
+
+// packetFlowDecision decides which packet messages of a channel to relay, skipping packets with filter and
+// holding them back with limiter. It only depends on its arguments and the state of the path ends, so that
+// a decision can be captured and replayed, see DecisionBundle.
+func (pp *PathProcessor) packetFlowDecision(ctx context.Context, pathEndPacketFlowMessages pathEndPacketFlowMessages, filter packetSkipper, limiter packetAllower) pathEndPacketFlowResponse {
 	res := pathEndPacketFlowResponse{
 		ToDeleteSrc: make(map[string][]uint64),
 		ToDeleteDst: make(map[string][]uint64),
@@ -69,6 +81,22 @@ MsgTransferLoop:
 				continue MsgTransferLoop
 			}
 		}
+		if reason := filter.Skip(pathEndPacketFlowMessages.Src.info.ChainID, packetInfoChannelKey(msgTransfer), msgTransfer.Data); reason != "" {
+			pp.log.Debug("Skipping packet filtered out",
+				zap.String("chain_id", pathEndPacketFlowMessages.Src.info.ChainID),
+				zap.String("channel_id", msgTransfer.SourceChannel),
+				zap.String("port_id", msgTransfer.SourcePort),
+				zap.Uint64("sequence", transferSeq),
+				zap.String("reason", reason),
+			)
+			// Skipped packets are forgotten, so counted once.
+			if pp.packetFilter.memoAction(pathEndPacketFlowMessages.Src.info.ChainID, packetInfoChannelKey(msgTransfer), msgTransfer.Data) == MemoBlock {
+				pp.metrics.IncMemoPackets(pp.pathName, pathEndPacketFlowMessages.Src.info.ChainID, msgTransfer.SourceChannel, msgTransfer.SourcePort, MemoBlock)
+			}
+			res.ToDeleteSrc[chantypes.EventTypeSendPacket] = append(res.ToDeleteSrc[chantypes.EventTypeSendPacket], transferSeq)
+			res.ToDeleteDst[chantypes.EventTypeRecvPacket] = append(res.ToDeleteDst[chantypes.EventTypeRecvPacket], transferSeq)
+			continue MsgTransferLoop
+		}
 		for msgRecvSeq, msgAcknowledgement := range pathEndPacketFlowMessages.DstMsgRecvPacket {
 			if transferSeq == msgRecvSeq {
 				// msg is received by dst chain, but no ack yet. Need to relay ack from dst to src!
@@ -113,6 +141,36 @@ MsgTransferLoop:
 			}
 			continue MsgTransferLoop
 		}
+		if !pathEndPacketFlowMessages.Src.isFinalized(msgTransfer.Height) && !pp.preconfirms(pathEndPacketFlowMessages.Src) {
+			pp.log.Debug("Holding back packet until finalized",
+				zap.String("chain_id", pathEndPacketFlowMessages.Src.info.ChainID),
+				zap.Uint64("sequence", transferSeq),
+				zap.Uint64("height", msgTransfer.Height),
+				zap.Int64("finalized_height", pathEndPacketFlowMessages.Src.finalizedHeight),
+			)
+			res.Held = append(res.Held, msgTransfer)
+			continue MsgTransferLoop
+		}
+		if !pathEndPacketFlowMessages.Src.isConfirmed(msgTransfer.Height) {
+			pp.log.Debug("Holding back packet until confirmed",
+				zap.String("chain_id", pathEndPacketFlowMessages.Src.info.ChainID),
+				zap.Uint64("sequence", transferSeq),
+				zap.Uint64("height", msgTransfer.Height),
+				zap.Uint64("latest_height", pathEndPacketFlowMessages.Src.latestBlock.Height),
+				zap.Uint64("confirmations", pathEndPacketFlowMessages.Src.confirmations),
+			)
+			continue MsgTransferLoop
+		}
+		if reason := limiter.Allow(pathEndPacketFlowMessages.Src.info.ChainID, packetInfoChannelKey(msgTransfer), transferSeq, msgTransfer.Data); reason != "" {
+			pp.log.Debug("Holding back packet over rate limit",
+				zap.String("chain_id", pathEndPacketFlowMessages.Src.info.ChainID),
+				zap.String("channel_id", msgTransfer.SourceChannel),
+				zap.String("port_id", msgTransfer.SourcePort),
+				zap.Uint64("sequence", transferSeq),
+				zap.String("reason", reason),
+			)
+			continue MsgTransferLoop
+		}
 		recvPacketMsg := packetIBCMessage{
 			eventType: chantypes.EventTypeRecvPacket,
 			info:      msgTransfer,
@@ -295,27 +353,89 @@ ChannelHandshakeLoop:
 			continue ChannelHandshakeLoop
 		}
 		// handshake is complete for this channel, remove all retention.
-		res.ToDeleteDst[chantypes.EventTypeChannelOpenTry] = append(res.ToDeleteDst[chantypes.EventTypeChannelOpenTry], openInitKey)
+		// Messages of dst are keyed from the perspective of dst.
+		res.ToDeleteDst[chantypes.EventTypeChannelOpenTry] = append(res.ToDeleteDst[chantypes.EventTypeChannelOpenTry], openInitKey.Counterparty())
 		res.ToDeleteSrc[chantypes.EventTypeChannelOpenAck] = append(res.ToDeleteSrc[chantypes.EventTypeChannelOpenAck], openInitKey)
-		res.ToDeleteDst[chantypes.EventTypeChannelOpenConfirm] = append(res.ToDeleteDst[chantypes.EventTypeChannelOpenConfirm], openInitKey)
+		res.ToDeleteDst[chantypes.EventTypeChannelOpenConfirm] = append(res.ToDeleteDst[chantypes.EventTypeChannelOpenConfirm], openInitKey.Counterparty())
 		// MsgChannelOpenInit does not have CounterpartyChannelID
 		res.ToDeleteSrc[chantypes.EventTypeChannelOpenInit] = append(res.ToDeleteSrc[chantypes.EventTypeChannelOpenInit], openInitKey.msgInitKey())
 	}
 
+	// Handshakes initiated before the block history the relayer started from, or whose MsgChannelOpenInit retention
+	// was given up on, have no MsgChannelOpenInit to start from. Resume them from the latest step observed,
+	// as long as the channel is still opening on the chain the next message is for.
+OpenTryLoop:
+	for openTryKey, openTryMsg := range pathEndChannelHandshakeMessages.DstMsgChannelOpenTry {
+		srcKey := openTryKey.Counterparty()
+		if _, ok := pathEndChannelHandshakeMessages.SrcMsgChannelOpenInit[srcKey.msgInitKey()]; ok {
+			// handled above
+			continue
+		}
+		for openAckKey := range pathEndChannelHandshakeMessages.SrcMsgChannelOpenAck {
+			if openAckKey == srcKey {
+				continue OpenTryLoop
+			}
+		}
+		if !pathEndChannelHandshakeMessages.Src.isChannelOpening(srcKey) {
+			continue
+		}
+		// need to send an open ack to src
+		msgOpenAck := channelIBCMessage{
+			eventType: chantypes.EventTypeChannelOpenAck,
+			info:      openTryMsg,
+		}
+		if pathEndChannelHandshakeMessages.Src.shouldSendChannelMessage(msgOpenAck, pathEndChannelHandshakeMessages.Dst) {
+			res.SrcMessages = append(res.SrcMessages, msgOpenAck)
+		}
+	}
+
+OpenAckLoop:
+	for openAckKey, openAckMsg := range pathEndChannelHandshakeMessages.SrcMsgChannelOpenAck {
+		if _, ok := pathEndChannelHandshakeMessages.SrcMsgChannelOpenInit[openAckKey.msgInitKey()]; ok {
+			// handled above
+			continue
+		}
+		dstKey := openAckKey.Counterparty()
+		for openConfirmKey := range pathEndChannelHandshakeMessages.DstMsgChannelOpenConfirm {
+			if openConfirmKey == dstKey {
+				continue OpenAckLoop
+			}
+		}
+		if !pathEndChannelHandshakeMessages.Dst.isChannelOpening(dstKey) {
+			continue
+		}
+		// need to send an open confirm to dst
+		msgOpenConfirm := channelIBCMessage{
+			eventType: chantypes.EventTypeChannelOpenConfirm,
+			info:      openAckMsg,
+		}
+		if pathEndChannelHandshakeMessages.Dst.shouldSendChannelMessage(msgOpenConfirm, pathEndChannelHandshakeMessages.Src) {
+			res.DstMessages = append(res.DstMessages, msgOpenConfirm)
+		}
+	}
+
 	// now iterate through channel-handshake-complete messages and remove any leftover messages
 	for openConfirmKey := range pathEndChannelHandshakeMessages.DstMsgChannelOpenConfirm {
+		srcKey := openConfirmKey.Counterparty()
 		res.ToDeleteDst[chantypes.EventTypeChannelOpenTry] = append(res.ToDeleteDst[chantypes.EventTypeChannelOpenTry], openConfirmKey)
-		res.ToDeleteSrc[chantypes.EventTypeChannelOpenAck] = append(res.ToDeleteSrc[chantypes.EventTypeChannelOpenAck], openConfirmKey)
+		res.ToDeleteSrc[chantypes.EventTypeChannelOpenAck] = append(res.ToDeleteSrc[chantypes.EventTypeChannelOpenAck], srcKey)
 		res.ToDeleteDst[chantypes.EventTypeChannelOpenConfirm] = append(res.ToDeleteDst[chantypes.EventTypeChannelOpenConfirm], openConfirmKey)
 		// MsgChannelOpenInit does not have CounterpartyChannelID
-		res.ToDeleteSrc[chantypes.EventTypeChannelOpenInit] = append(res.ToDeleteSrc[chantypes.EventTypeChannelOpenInit], openConfirmKey.msgInitKey())
+		res.ToDeleteSrc[chantypes.EventTypeChannelOpenInit] = append(res.ToDeleteSrc[chantypes.EventTypeChannelOpenInit], srcKey.msgInitKey())
 	}
 	return res
 }
 
+// updateHeaderBisector is implemented by chain providers that can split a client update into updates through
+// intermediate headers, when the validators changed too much since the trusted height for the client to verify it.
+type updateHeaderBisector interface {
+	BisectUpdateClientHeader(ctx context.Context, header ibcexported.Header, dst provider.ChainProvider, dstClientID string) ([]ibcexported.Header, error)
+}
+
 // assembleMsgUpdateClient uses the ChainProvider from both pathEnds to assemble the client update header
-// from the source and then assemble the update client message in the correct format for the destination.
-func (pp *PathProcessor) assembleMsgUpdateClient(ctx context.Context, src, dst *pathEndRuntime) (provider.RelayerMessage, error) {
+// from the source and then assemble the update client messages in the correct format for the destination,
+// more than one if the update is bisected through intermediate headers.
+func (pp *PathProcessor) assembleMsgUpdateClient(ctx context.Context, src, dst *pathEndRuntime) ([]provider.RelayerMessage, error) {
 	clientID := dst.info.ClientID
 	clientConsensusHeight := dst.clientState.ConsensusHeight
 	trustedConsensusHeight := dst.clientTrustedState.ClientState.ConsensusHeight
@@ -357,12 +477,24 @@ func (pp *PathProcessor) assembleMsgUpdateClient(ctx context.Context, src, dst *
 		return nil, fmt.Errorf("error assembling new client header: %w", err)
 	}
 
-	msgUpdateClient, err := dst.chainProvider.MsgUpdateClient(clientID, msgUpdateClientHeader)
-	if err != nil {
-		return nil, fmt.Errorf("error assembling MsgUpdateClient: %w", err)
+	headers := []ibcexported.Header{msgUpdateClientHeader}
+	if b, ok := src.chainProvider.(updateHeaderBisector); ok {
+		headers, err = b.BisectUpdateClientHeader(ctx, msgUpdateClientHeader, dst.chainProvider, clientID)
+		if err != nil {
+			return nil, fmt.Errorf("error bisecting client update: %w", err)
+		}
+		pp.metrics.ObserveClientUpdateBisectionDepth(pp.pathName, dst.info.ChainID, clientID, len(headers)-1)
 	}
 
-	return msgUpdateClient, nil
+	msgs := make([]provider.RelayerMessage, len(headers))
+	for i, header := range headers {
+		msgs[i], err = dst.chainProvider.MsgUpdateClient(clientID, header)
+		if err != nil {
+			return nil, fmt.Errorf("error assembling MsgUpdateClient: %w", err)
+		}
+	}
+
+	return msgs, nil
 }
 
 // updateClientTrustedState combines the counterparty chains trusted IBC header
@@ -388,6 +520,14 @@ func (pp *PathProcessor) updateClientTrustedState(src *pathEndRuntime, dst *path
 	}
 }
 
+// counterpartyChainID returns the chain ID of the path end counterparty to chainID.
+func (pp *PathProcessor) counterpartyChainID(chainID string) string {
+	if chainID == pp.pathEnd1.info.ChainID {
+		return pp.pathEnd2.info.ChainID
+	}
+	return pp.pathEnd1.info.ChainID
+}
+
 func (pp *PathProcessor) appendInitialMessageIfNecessary(msg MessageLifecycle, pathEnd1Messages, pathEnd2Messages *pathEndMessages) {
 	if msg == nil || pp.sentInitialMsg {
 		return
@@ -425,7 +565,13 @@ func (pp *PathProcessor) appendInitialMessageIfNecessary(msg MessageLifecycle, p
 		if m.Initial == nil {
 			return
 		}
-		if !pp.IsRelevantClient(m.Initial.ChainID, m.Initial.Info.ClientID) {
+		// Messages past the first step of a handshake carry the info of the counterparty end, e.g. MsgConnectionOpenTry
+		// is assembled from the MsgConnectionOpenInit of the counterparty, so that handshakes can be resumed midway.
+		clientChainID := m.Initial.ChainID
+		if m.Initial.EventType != conntypes.EventTypeConnectionOpenInit {
+			clientChainID = pp.counterpartyChainID(m.Initial.ChainID)
+		}
+		if !pp.IsRelevantClient(clientChainID, m.Initial.Info.ClientID) {
 			return
 		}
 		if m.Initial.ChainID == pp.pathEnd1.info.ChainID {
@@ -443,7 +589,11 @@ func (pp *PathProcessor) appendInitialMessageIfNecessary(msg MessageLifecycle, p
 		if m.Initial == nil {
 			return
 		}
-		if !pp.IsRelevantConnection(m.Initial.ChainID, m.Initial.Info.ConnID) {
+		connChainID := m.Initial.ChainID
+		if m.Initial.EventType != chantypes.EventTypeChannelOpenInit {
+			connChainID = pp.counterpartyChainID(m.Initial.ChainID)
+		}
+		if !pp.IsRelevantConnection(connChainID, m.Initial.Info.ConnID) {
 			return
 		}
 		if m.Initial.ChainID == pp.pathEnd1.info.ChainID {
@@ -466,6 +616,13 @@ func (pp *PathProcessor) processLatestMessages(ctx context.Context, messageLifec
 	pp.updateClientTrustedState(pp.pathEnd1, pp.pathEnd2)
 	pp.updateClientTrustedState(pp.pathEnd2, pp.pathEnd1)
 
+	pp.pathEnd1.updateFinalizedHeight(ctx)
+	pp.pathEnd2.updateFinalizedHeight(ctx)
+	pp.reconcilePreconfirmations(ctx, pp.pathEnd1)
+	pp.reconcilePreconfirmations(ctx, pp.pathEnd2)
+	pp.resolveHeldPackets(ctx, pp.pathEnd1)
+	pp.resolveHeldPackets(ctx, pp.pathEnd2)
+
 	channelPairs := pp.channelPairs()
 
 	pathEnd1ConnectionHandshakeMessages := pathEndConnectionHandshakeMessages{
@@ -507,6 +664,7 @@ func (pp *PathProcessor) processLatestMessages(ctx context.Context, messageLifec
 	pathEnd2ChannelHandshakeRes := pp.getUnrelayedChannelHandshakeMessagesAndToDelete(pathEnd2ChannelHandshakeMessages)
 
 	// process the packet flows for both path ends to determine what needs to be relayed
+	captures := pp.decisionCapture.pending()
 	pathEnd1ProcessRes := make([]pathEndPacketFlowResponse, len(channelPairs))
 	pathEnd2ProcessRes := make([]pathEndPacketFlowResponse, len(channelPairs))
 
@@ -532,8 +690,21 @@ func (pp *PathProcessor) processLatestMessages(ctx context.Context, messageLifec
 			SrcMsgTimeoutOnClose:  pp.pathEnd2.messageCache.PacketFlow[pair.pathEnd2ChannelKey][chantypes.EventTypeTimeoutPacketOnClose],
 		}
 
-		pathEnd1ProcessRes[i] = pp.getUnrelayedPacketsAndAcksAndToDelete(ctx, pathEnd1PacketFlowMessages)
-		pathEnd2ProcessRes[i] = pp.getUnrelayedPacketsAndAcksAndToDelete(ctx, pathEnd2PacketFlowMessages)
+		pathEnd1ProcessRes[i] = pp.decidePacketFlow(ctx, pathEnd1PacketFlowMessages, captures)
+		pathEnd2ProcessRes[i] = pp.decidePacketFlow(ctx, pathEnd2PacketFlowMessages, captures)
+		skipPacketFlow(pp.pathEnd1, &pathEnd1ProcessRes[i])
+		skipPacketFlow(pp.pathEnd2, &pathEnd2ProcessRes[i])
+		pp.aggregateAcks(pp.pathEnd1, pair.pathEnd1ChannelKey, &pathEnd1ProcessRes[i])
+		pp.aggregateAcks(pp.pathEnd2, pair.pathEnd2ChannelKey, &pathEnd2ProcessRes[i])
+		pp.trackHeldPackets(pp.pathEnd1, pair.pathEnd1ChannelKey, &pathEnd1ProcessRes[i])
+		pp.trackHeldPackets(pp.pathEnd2, pair.pathEnd2ChannelKey, &pathEnd2ProcessRes[i])
+	}
+	// Requests answered above already hold their result.
+	for _, req := range captures {
+		select {
+		case req.res <- decisionResult{err: fmt.Errorf("channel %s/%s of %s is not relayed by the path", req.portID, req.channelID, req.chainID)}:
+		default:
+		}
 	}
 
 	// concatenate applicable messages for pathend
@@ -541,6 +712,11 @@ func (pp *PathProcessor) processLatestMessages(ctx context.Context, messageLifec
 	pathEnd1ChannelMessages, pathEnd2ChannelMessages := pp.channelMessagesToSend(pathEnd1ChannelHandshakeRes, pathEnd2ChannelHandshakeRes)
 	pathEnd1PacketMessages, pathEnd2PacketMessages := pp.packetMessagesToSend(channelPairs, pathEnd1ProcessRes, pathEnd2ProcessRes)
 
+	if pp.readOnly {
+		// the caches have been pruned of completed flows, nothing is relayed.
+		return nil
+	}
+
 	pathEnd1Messages := pathEndMessages{
 		connectionMessages: pathEnd1ConnectionMessages,
 		channelMessages:    pathEnd1ChannelMessages,
@@ -575,11 +751,36 @@ func (pp *PathProcessor) processLatestMessages(ctx context.Context, messageLifec
 	return eg.Wait()
 }
 
+// aggregateAcks holds back the acknowledgements of res to relay to pathEnd over the channel k, see AckAggregator,
+// scheduling the processing of the path for when they are released.
+func (pp *PathProcessor) aggregateAcks(pathEnd *pathEndRuntime, k ChannelKey, res *pathEndPacketFlowResponse) {
+	var wait time.Duration
+	res.SrcMessages, wait = pp.ackAggregator.hold(pathEnd.info.ChainID, k, res.SrcMessages)
+	if wait <= 0 {
+		return
+	}
+	at := time.Now().Add(wait)
+	if pp.ackRelease != nil && pp.ackReleaseAt.After(time.Now()) && !pp.ackReleaseAt.After(at) {
+		return
+	}
+	if pp.ackRelease != nil {
+		pp.ackRelease.Stop()
+	}
+	pp.ackRelease, pp.ackReleaseAt = time.AfterFunc(wait, pp.ProcessBacklogIfReady), at
+}
+
 func (pp *PathProcessor) logFailedTx(src, dst PathEnd, err error) {
 	if errors.Is(err, chantypes.ErrRedundantTx) {
 		pp.log.Debug("Packet(s) already handled by another relayer")
 		return
 	}
+	pp.metrics.IncFailedRelays(pp.pathName, dst.ChainID, pp.metricsDirection(dst.ChainID))
+	pp.feed.Publish(feed.Event{
+		Type:     feed.EventRelayError,
+		ChainID:  dst.ChainID,
+		ClientID: dst.ClientID,
+		Error:    err.Error(),
+	})
 	pp.log.Error("Error sending messages",
 		zap.String("src_chain_id", src.ChainID),
 		zap.String("dst_chain_id", dst.ChainID),
@@ -606,6 +807,10 @@ func (pp *PathProcessor) assembleMessage(
 		om.pktMsgs[i] = packetMessageToTrack{
 			msg:       m,
 			assembled: err == nil,
+			message:   message,
+		}
+		if err != nil {
+			pp.recordAttempt(src, dst, m, err)
 		}
 	case connectionIBCMessage:
 		message, err = pp.assembleConnectionMessage(ctx, m, src, dst)
@@ -624,6 +829,10 @@ func (pp *PathProcessor) assembleMessage(
 		pp.log.Error("Error assembling channel message", zap.Error(err))
 		return
 	}
+	if _, ok := msg.(packetIBCMessage); ok {
+		// Packet messages are added in order once assembled, see fitMaxTxBytes.
+		return
+	}
 	om.Append(message)
 }
 
@@ -631,10 +840,19 @@ func (pp *PathProcessor) assembleAndSendMessages(
 	ctx context.Context,
 	src, dst *pathEndRuntime,
 	messages pathEndMessages,
-) error {
+) (err error) {
 	if len(messages.packetMessages) == 0 && len(messages.connectionMessages) == 0 && len(messages.channelMessages) == 0 {
 		return nil
 	}
+	ctx, span := tracing.Start(ctx, "RelayMessages",
+		attribute.String("path", pp.pathName),
+		attribute.String("src_chain_id", src.info.ChainID),
+		attribute.String("dst_chain_id", dst.info.ChainID),
+		attribute.Int("packet_messages", len(messages.packetMessages)),
+		attribute.Int("connection_messages", len(messages.connectionMessages)),
+		attribute.Int("channel_messages", len(messages.channelMessages)),
+	)
+	defer func() { tracing.End(span, err) }()
 	om := outgoingMessages{
 		msgs: make(
 			[]provider.RelayerMessage,
@@ -645,11 +863,14 @@ func (pp *PathProcessor) assembleAndSendMessages(
 		connMsgs: make([]connectionMessageToTrack, len(messages.connectionMessages)),
 		chanMsgs: make([]channelMessageToTrack, len(messages.channelMessages)),
 	}
-	msgUpdateClient, err := pp.assembleMsgUpdateClient(ctx, src, dst)
-	if err != nil {
-		return err
+	// With proof pipelining, the proofs are queried while the client update is assembled.
+	pipelined := pp.featureEnabled(FeatureProofPipelining)
+	var msgUpdateClients []provider.RelayerMessage
+	if !pipelined {
+		if msgUpdateClients, err = pp.assembleMsgUpdateClient(ctx, src, dst); err != nil {
+			return err
+		}
 	}
-	om.Append(msgUpdateClient)
 
 	// Each assembleMessage call below will make a query on the source chain, so these operations can run in parallel.
 	var wg sync.WaitGroup
@@ -669,9 +890,25 @@ func (pp *PathProcessor) assembleAndSendMessages(
 		go pp.assembleMessage(ctx, msg, src, dst, &om, i, &wg)
 	}
 
+	if pipelined {
+		msgUpdateClients, err = pp.assembleMsgUpdateClient(ctx, src, dst)
+	}
 	wg.Wait()
+	if err != nil {
+		pp.recordAttempts(src, dst, om.pktMsgs, err)
+		return err
+	}
+	// The client updates come first, for the messages to be verified against them.
+	om.msgs = append(msgUpdateClients, om.msgs...)
+
+	pp.fitMaxTxBytes(dst, &om)
+	for _, m := range om.pktMsgs {
+		if m.assembled {
+			om.msgs = append(om.msgs, m.message)
+		}
+	}
 
-	if len(om.msgs) == 1 {
+	if len(om.msgs) == len(msgUpdateClients) {
 		// only msgUpdateClient, don't need to send
 		return errors.New("all messages failed to assemble")
 	}
@@ -693,17 +930,128 @@ func (pp *PathProcessor) assembleAndSendMessages(
 		defer cancel()
 	}
 
-	_, txSuccess, err := dst.chainProvider.SendMessages(ctx, om.msgs, pp.memo)
+	res, txSuccess, err := dst.chainProvider.SendMessages(provider.WithPriority(provider.WithPathName(ctx, pp.pathName), pp.priority), om.msgs, pp.memo)
 	if err != nil {
-		return fmt.Errorf("error sending messages: %w", err)
+		err = fmt.Errorf("error sending messages: %w", err)
+	} else if !txSuccess {
+		err = errors.New("error sending messages, transeventType was not successful")
 	}
-	if !txSuccess {
-		return errors.New("error sending messages, transeventType was not successful")
+	pp.recordAttempts(src, dst, om.pktMsgs, err)
+	if err != nil {
+		return err
 	}
 
+	pp.publishRelayed(dst, res, om.pktMsgs)
+	pp.recordPreconfirmed(src, dst, res, om.pktMsgs)
+	pp.recordRelayed(dst, res.GasUsed, om.pktMsgs)
+
 	return nil
 }
 
+// metricRelayedTypes maps the packet messages sent by the PathProcessor to their relayed_packets_total type.
+var metricRelayedTypes = map[string]string{
+	chantypes.EventTypeRecvPacket:           MetricRecvPacket,
+	chantypes.EventTypeAcknowledgePacket:    MetricAckPacket,
+	chantypes.EventTypeTimeoutPacket:        MetricTimeout,
+	chantypes.EventTypeTimeoutPacketOnClose: MetricTimeout,
+}
+
+// recordRelayed records the gas used by and the packet messages of a transaction successfully sent to dst.
+func (pp *PathProcessor) recordRelayed(dst *pathEndRuntime, gasUsed int64, pktMsgs []packetMessageToTrack) {
+	if pp.metrics == nil {
+		return
+	}
+	direction := pp.metricsDirection(dst.info.ChainID)
+	pp.metrics.AddGasUsed(pp.pathName, dst.info.ChainID, direction, gasUsed)
+	for _, m := range pktMsgs {
+		msgType, ok := metricRelayedTypes[m.msg.eventType]
+		if !ok || !m.assembled {
+			continue
+		}
+		// MsgRecvPacket is delivered to the destination channel of the packet,
+		// acknowledgements and timeouts to its source channel.
+		channelID, portID := m.msg.info.SourceChannel, m.msg.info.SourcePort
+		if msgType == MetricRecvPacket {
+			channelID, portID = m.msg.info.DestChannel, m.msg.info.DestPort
+		}
+		pp.metrics.IncRelayedPackets(pp.pathName, dst.info.ChainID, direction, channelID, portID, msgType)
+		if msgType == MetricRecvPacket {
+			chainID := pp.packetSenderChainID(m.msg.eventType, dst)
+			if action := pp.packetFilter.memoAction(chainID, packetInfoChannelKey(m.msg.info), m.msg.info.Data); action != "" {
+				pp.metrics.IncMemoPackets(pp.pathName, chainID, m.msg.info.SourceChannel, m.msg.info.SourcePort, action)
+			}
+		}
+	}
+}
+
+// packetSenderChainID returns the chain the packet of a message of eventType sent to dst was sent from:
+// the counterparty of dst for MsgRecvPacket, and dst itself for acknowledgements and timeouts.
+func (pp *PathProcessor) packetSenderChainID(eventType string, dst *pathEndRuntime) string {
+	if eventType != chantypes.EventTypeRecvPacket {
+		return dst.info.ChainID
+	}
+	if dst.info.ChainID == pp.pathEnd1.info.ChainID {
+		return pp.pathEnd2.info.ChainID
+	}
+	return pp.pathEnd1.info.ChainID
+}
+
+// skipPacketFlow drops the messages of the packet flow of the packets sent on src if they are relayed by another relayer,
+// keeping the packets to forget.
+func skipPacketFlow(src *pathEndRuntime, res *pathEndPacketFlowResponse) {
+	if !src.skipPackets {
+		return
+	}
+	res.SrcMessages, res.DstMessages, res.Held = nil, nil, nil
+}
+
+// metricsDirection returns the direction label of transactions sent to dstChainID, pathEnd1 being the source of the path.
+func (pp *PathProcessor) metricsDirection(dstChainID string) string {
+	if dstChainID == pp.pathEnd1.info.ChainID {
+		return DirectionDstToSrc
+	}
+	return DirectionSrcToDst
+}
+
+// feedRelayedEventTypes maps the packet messages sent by the PathProcessor to their feed event type.
+var feedRelayedEventTypes = map[string]string{
+	chantypes.EventTypeRecvPacket:           feed.EventPacketRelayed,
+	chantypes.EventTypeAcknowledgePacket:    feed.EventAckRelayed,
+	chantypes.EventTypeTimeoutPacket:        feed.EventTimeoutRelayed,
+	chantypes.EventTypeTimeoutPacketOnClose: feed.EventTimeoutRelayed,
+}
+
+// publishRelayed publishes the client update and the packet messages of a transaction successfully sent to dst.
+func (pp *PathProcessor) publishRelayed(dst *pathEndRuntime, res *provider.RelayerTxResponse, pktMsgs []packetMessageToTrack) {
+	if pp.feed == nil {
+		return
+	}
+	pp.feed.Publish(feed.Event{
+		Type:     feed.EventClientUpdated,
+		ChainID:  dst.info.ChainID,
+		Height:   uint64(res.Height),
+		TxHash:   res.TxHash,
+		TxFee:    res.Fee,
+		ClientID: dst.info.ClientID,
+	})
+	for _, m := range pktMsgs {
+		eventType, ok := feedRelayedEventTypes[m.msg.eventType]
+		if !ok || !m.assembled {
+			continue
+		}
+		packet := feed.NewPacket(m.msg.info)
+		packet.Data = pp.packetFilter.LoggedData(pp.packetSenderChainID(m.msg.eventType, dst), packetInfoChannelKey(m.msg.info), packet.Data)
+		pp.feed.Publish(feed.Event{
+			Type:    eventType,
+			ChainID: dst.info.ChainID,
+			Height:  uint64(res.Height),
+			TxHash:  res.TxHash,
+			TxFee:   res.Fee,
+			Packet:  packet,
+		})
+	}
+}
+
 func (pp *PathProcessor) assemblePacketMessage(
 	ctx context.Context,
 	msg packetIBCMessage,
@@ -0,0 +1,147 @@
+package processor
+
+import (
+	"testing"
+
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type mockChannelPauser map[ChannelKey]bool
+
+func (p mockChannelPauser) IsChannelPaused(chainID string, k ChannelKey) bool {
+	return chainID == "chain-a" && p[k]
+}
+
+func TestChannelPairsSkipsPausedChannels(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "chain-a"}, PathEnd{ChainID: "chain-b"}, "")
+
+	open := ChannelKey{ChannelID: "channel-0", PortID: "transfer", CounterpartyChannelID: "channel-5", CounterpartyPortID: "transfer"}
+	paused := ChannelKey{ChannelID: "channel-1", PortID: "transfer", CounterpartyChannelID: "channel-6", CounterpartyPortID: "transfer"}
+	closed := ChannelKey{ChannelID: "channel-2", PortID: "transfer", CounterpartyChannelID: "channel-7", CounterpartyPortID: "transfer"}
+	pp.pathEnd1.channelStateCache = ChannelStateCache{open: true, paused: true, closed: false}
+	pp.pathEnd2.channelStateCache = ChannelStateCache{paused.Counterparty(): true}
+
+	require.Len(t, pp.channelPairs(), 2)
+
+	pp.SetChannelPauser(mockChannelPauser{paused: true})
+	pairs := pp.channelPairs()
+	require.Equal(t, []channelPair{{pathEnd1ChannelKey: open, pathEnd2ChannelKey: open.Counterparty()}}, pairs)
+}
+
+func TestChannelHandshakeResumesWithoutOpenInit(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "chain-a"}, PathEnd{ChainID: "chain-b"}, "")
+	pp.pathEnd1.latestBlock = provider.LatestBlock{Height: 20}
+	pp.pathEnd2.latestBlock = provider.LatestBlock{Height: 20}
+
+	// channel-0 on chain-a was initiated before the history the relayer started from,
+	// and chain-b has a channel-5 in TRYOPEN for it.
+	srcKey := ChannelKey{ChannelID: "channel-0", PortID: "icacontroller-a", CounterpartyChannelID: "channel-5", CounterpartyPortID: "icahost"}
+	dstKey := srcKey.Counterparty()
+	pp.pathEnd1.channelStateCache = ChannelStateCache{srcKey.msgInitKey(): false}
+	pp.pathEnd2.channelStateCache = ChannelStateCache{dstKey: false}
+	openTry := provider.ChannelInfo{
+		Height:                10,
+		PortID:                dstKey.PortID,
+		ChannelID:             dstKey.ChannelID,
+		CounterpartyPortID:    srcKey.PortID,
+		CounterpartyChannelID: srcKey.ChannelID,
+		Order:                 chantypes.ORDERED,
+		Version:               "ics27-1",
+	}
+	messages := pathEndChannelHandshakeMessages{
+		Src:                      pp.pathEnd1,
+		Dst:                      pp.pathEnd2,
+		SrcMsgChannelOpenInit:    ChannelMessageCache{},
+		DstMsgChannelOpenTry:     ChannelMessageCache{dstKey: openTry},
+		SrcMsgChannelOpenAck:     ChannelMessageCache{},
+		DstMsgChannelOpenConfirm: ChannelMessageCache{},
+	}
+
+	res := pp.getUnrelayedChannelHandshakeMessagesAndToDelete(messages)
+	require.Equal(t, []channelIBCMessage{{eventType: chantypes.EventTypeChannelOpenAck, info: openTry}}, res.SrcMessages)
+	require.Empty(t, res.DstMessages)
+
+	// Once chain-a acknowledged the handshake, chain-b is sent the confirmation.
+	openAck := provider.ChannelInfo{
+		Height:                12,
+		PortID:                srcKey.PortID,
+		ChannelID:             srcKey.ChannelID,
+		CounterpartyPortID:    dstKey.PortID,
+		CounterpartyChannelID: dstKey.ChannelID,
+	}
+	pp.pathEnd1.channelStateCache[srcKey] = true
+	messages.SrcMsgChannelOpenAck[srcKey] = openAck
+
+	res = pp.getUnrelayedChannelHandshakeMessagesAndToDelete(messages)
+	require.Empty(t, res.SrcMessages)
+	require.Equal(t, []channelIBCMessage{{eventType: chantypes.EventTypeChannelOpenConfirm, info: openAck}}, res.DstMessages)
+
+	// Once confirmed, the retention of the handshake is removed on both chains.
+	pp.pathEnd2.channelStateCache[dstKey] = true
+	messages.DstMsgChannelOpenConfirm[dstKey] = provider.ChannelInfo{Height: 14}
+
+	res = pp.getUnrelayedChannelHandshakeMessagesAndToDelete(messages)
+	require.Empty(t, res.SrcMessages)
+	require.Empty(t, res.DstMessages)
+	require.Equal(t, []ChannelKey{srcKey}, res.ToDeleteSrc[chantypes.EventTypeChannelOpenAck])
+	require.Equal(t, []ChannelKey{srcKey.msgInitKey()}, res.ToDeleteSrc[chantypes.EventTypeChannelOpenInit])
+	require.Equal(t, []ChannelKey{dstKey}, res.ToDeleteDst[chantypes.EventTypeChannelOpenTry])
+	require.Equal(t, []ChannelKey{dstKey}, res.ToDeleteDst[chantypes.EventTypeChannelOpenConfirm])
+}
+
+func TestChannelHandshakeNotResumedOnceOpen(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "chain-a"}, PathEnd{ChainID: "chain-b"}, "")
+	pp.pathEnd1.latestBlock = provider.LatestBlock{Height: 20}
+	pp.pathEnd2.latestBlock = provider.LatestBlock{Height: 20}
+
+	srcKey := ChannelKey{ChannelID: "channel-0", PortID: "transfer", CounterpartyChannelID: "channel-5", CounterpartyPortID: "transfer"}
+	dstKey := srcKey.Counterparty()
+	// Another relayer already acknowledged the handshake on chain-a.
+	pp.pathEnd1.channelStateCache = ChannelStateCache{srcKey.msgInitKey(): false, srcKey: true}
+	pp.pathEnd2.channelStateCache = ChannelStateCache{dstKey: false}
+
+	res := pp.getUnrelayedChannelHandshakeMessagesAndToDelete(pathEndChannelHandshakeMessages{
+		Src:                      pp.pathEnd1,
+		Dst:                      pp.pathEnd2,
+		SrcMsgChannelOpenInit:    ChannelMessageCache{},
+		DstMsgChannelOpenTry:     ChannelMessageCache{dstKey: {Height: 10}},
+		SrcMsgChannelOpenAck:     ChannelMessageCache{},
+		DstMsgChannelOpenConfirm: ChannelMessageCache{},
+	})
+	require.Empty(t, res.SrcMessages)
+	require.Empty(t, res.DstMessages)
+}
+
+func TestInitialConnectionMessageResumesHandshake(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"}, PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-4"}, "")
+
+	// MsgConnectionOpenTry to chain-b carries the info of the connection of chain-a in INIT.
+	openTry := &ConnectionMessage{
+		ChainID:   "chain-b",
+		EventType: conntypes.EventTypeConnectionOpenTry,
+		Info: provider.ConnectionInfo{
+			ClientID:             "07-tendermint-0",
+			ConnID:               "connection-1",
+			CounterpartyClientID: "07-tendermint-4",
+		},
+	}
+	var pathEnd1Messages, pathEnd2Messages pathEndMessages
+	pp.appendInitialMessageIfNecessary(&ConnectionMessageLifecycle{Initial: openTry}, &pathEnd1Messages, &pathEnd2Messages)
+	require.Empty(t, pathEnd1Messages.connectionMessages)
+	require.Equal(t, []connectionIBCMessage{{eventType: openTry.EventType, info: openTry.Info}}, pathEnd2Messages.connectionMessages)
+
+	// MsgConnectionOpenInit carries the info of the chain it is sent to.
+	pp = NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"}, PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-4"}, "")
+	openInit := &ConnectionMessage{
+		ChainID:   "chain-b",
+		EventType: conntypes.EventTypeConnectionOpenInit,
+		Info:      openTry.Info,
+	}
+	pathEnd2Messages = pathEndMessages{}
+	pp.appendInitialMessageIfNecessary(&ConnectionMessageLifecycle{Initial: openInit}, &pathEnd1Messages, &pathEnd2Messages)
+	require.Empty(t, pathEnd2Messages.connectionMessages)
+}
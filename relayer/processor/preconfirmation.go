@@ -0,0 +1,273 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxPreconfirmationsReconciled bounds the packets reconciled with the finalized state of a rollapp
+	// each time the path processor processes messages, as each takes a query.
+	maxPreconfirmationsReconciled = 10
+
+	// maxPreconfirmationMismatches is the number of mismatched preconfirmations kept for the admin API.
+	maxPreconfirmationMismatches = 100
+)
+
+// Preconfirmation is a packet sent on a rollapp and relayed to its counterparty before the block it was sent in
+// was finalized on the settlement layer, exposing the counterparty to the rollapp reverting that block.
+type Preconfirmation struct {
+	Path      string    `json:"path"`
+	ChainID   string    `json:"chain_id"`
+	ChannelID string    `json:"channel_id"`
+	PortID    string    `json:"port_id"`
+	Sequence  uint64    `json:"sequence"`
+	Height    uint64    `json:"height"`
+	TxHash    string    `json:"tx_hash"`
+	RelayedAt time.Time `json:"relayed_at"`
+
+	// Denom and Amount are those of ICS-20 transfers.
+	Denom  string `json:"denom,omitempty"`
+	Amount string `json:"amount,omitempty"`
+
+	// Mismatch is why the finalized blocks of the rollapp do not include the packet as relayed, once reconciled.
+	Mismatch string `json:"mismatch,omitempty"`
+
+	data []byte
+}
+
+type preconfirmationKey struct {
+	path, chainID, channelID, portID string
+	sequence                         uint64
+}
+
+func (p Preconfirmation) key() preconfirmationKey {
+	return preconfirmationKey{p.Path, p.ChainID, p.ChannelID, p.PortID, p.Sequence}
+}
+
+// PreconfirmationSnapshot lists the preconfirmed packets awaiting finalization, oldest first,
+// and the latest ones found not to be part of the finalized blocks of their rollapp.
+type PreconfirmationSnapshot struct {
+	Pending    []Preconfirmation `json:"pending"`
+	Mismatches []Preconfirmation `json:"mismatches"`
+}
+
+// PreconfirmationTracker records the packets relayed from rollapps before they were finalized, see
+// PathProcessor.SetPreconfirmation, until their block is finalized and they are reconciled with the finalized state.
+// A tracker may be shared by the path processors of several paths.
+type PreconfirmationTracker struct {
+	log     *zap.Logger
+	metrics *PrometheusMetrics
+
+	mu         sync.Mutex
+	pending    map[preconfirmationKey]Preconfirmation
+	mismatches []Preconfirmation
+}
+
+// NewPreconfirmationTracker returns an empty tracker, publishing the exposure of every path in metrics, which may be nil.
+func NewPreconfirmationTracker(log *zap.Logger, metrics *PrometheusMetrics) *PreconfirmationTracker {
+	return &PreconfirmationTracker{
+		log:     log,
+		metrics: metrics,
+		pending: make(map[preconfirmationKey]Preconfirmation),
+	}
+}
+
+func (t *PreconfirmationTracker) record(p Preconfirmation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[p.key()] = p
+	t.updateExposure(p.Path, p.ChainID)
+}
+
+// due returns up to limit packets of the rollapp relayed for path at or below its finalized height, lowest first.
+func (t *PreconfirmationTracker) due(path, chainID string, finalizedHeight uint64, limit int) []Preconfirmation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var due []Preconfirmation
+	for _, p := range t.pending {
+		if p.Path == path && p.ChainID == chainID && p.Height <= finalizedHeight {
+			due = append(due, p)
+		}
+	}
+	sortPreconfirmations(due)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due
+}
+
+// confirm forgets a packet found in the finalized blocks of its rollapp.
+func (t *PreconfirmationTracker) confirm(p Preconfirmation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, p.key())
+	t.updateExposure(p.Path, p.ChainID)
+}
+
+// mismatch reports a packet relayed although the finalized blocks of its rollapp do not include it as relayed.
+func (t *PreconfirmationTracker) mismatch(p Preconfirmation, reason string) {
+	p.Mismatch = reason
+	t.log.Error(
+		"Preconfirmed packet is not part of the finalized state of the rollapp",
+		zap.String("path", p.Path),
+		zap.String("chain_id", p.ChainID),
+		zap.String("channel_id", p.ChannelID),
+		zap.String("port_id", p.PortID),
+		zap.Uint64("sequence", p.Sequence),
+		zap.Uint64("height", p.Height),
+		zap.String("tx_hash", p.TxHash),
+		zap.String("denom", p.Denom),
+		zap.String("amount", p.Amount),
+		zap.String("mismatch", reason),
+	)
+	t.metrics.IncPreconfirmationMismatches(p.Path, p.ChainID)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, p.key())
+	t.updateExposure(p.Path, p.ChainID)
+	t.mismatches = append(t.mismatches, p)
+	if len(t.mismatches) > maxPreconfirmationMismatches {
+		t.mismatches = t.mismatches[len(t.mismatches)-maxPreconfirmationMismatches:]
+	}
+}
+
+// updateExposure publishes the number of packets of the rollapp pending for path. Must be called with t.mu held.
+func (t *PreconfirmationTracker) updateExposure(path, chainID string) {
+	n := 0
+	for k := range t.pending {
+		if k.path == path && k.chainID == chainID {
+			n++
+		}
+	}
+	t.metrics.SetPreconfirmedPackets(path, chainID, n)
+}
+
+// Snapshot returns the packets pending finalization and the latest mismatches.
+func (t *PreconfirmationTracker) Snapshot() PreconfirmationSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := PreconfirmationSnapshot{
+		Pending:    make([]Preconfirmation, 0, len(t.pending)),
+		Mismatches: append([]Preconfirmation{}, t.mismatches...),
+	}
+	for _, p := range t.pending {
+		s.Pending = append(s.Pending, p)
+	}
+	sortPreconfirmations(s.Pending)
+	return s
+}
+
+func sortPreconfirmations(ps []Preconfirmation) {
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Height != ps[j].Height {
+			return ps[i].Height < ps[j].Height
+		}
+		if ps[i].ChannelID != ps[j].ChannelID {
+			return ps[i].ChannelID < ps[j].ChannelID
+		}
+		return ps[i].Sequence < ps[j].Sequence
+	})
+}
+
+// sendPacketQuerier is implemented by chain providers that can look up the packet sent on a channel with a sequence.
+type sendPacketQuerier interface {
+	QuerySendPacket(ctx context.Context, srcChanID, srcPortID string, seq uint64) (provider.PacketInfo, error)
+}
+
+// preconfirms returns whether the packets sent on pathEnd are relayed before their block is finalized.
+func (pp *PathProcessor) preconfirms(pathEnd *pathEndRuntime) bool {
+	return pathEnd.preconfirmations != nil && pp.featureEnabled(FeatureOptimisticRelaying)
+}
+
+// recordPreconfirmed records the packets sent on src, a path end relayed at preconfirmation,
+// that were delivered to dst by the transaction res before their block was finalized.
+func (pp *PathProcessor) recordPreconfirmed(src, dst *pathEndRuntime, res *provider.RelayerTxResponse, pktMsgs []packetMessageToTrack) {
+	if src.preconfirmations == nil {
+		return
+	}
+	now := time.Now().UTC()
+	for _, m := range pktMsgs {
+		if m.msg.eventType != chantypes.EventTypeRecvPacket || !m.assembled || src.isFinalized(m.msg.info.Height) {
+			continue
+		}
+		p := Preconfirmation{
+			Path:      pp.pathName,
+			ChainID:   src.info.ChainID,
+			ChannelID: m.msg.info.SourceChannel,
+			PortID:    m.msg.info.SourcePort,
+			Sequence:  m.msg.info.Sequence,
+			Height:    m.msg.info.Height,
+			TxHash:    res.TxHash,
+			RelayedAt: now,
+			data:      m.msg.info.Data,
+		}
+		if denom, amount, ok := transferData(m.msg.info.Data); ok {
+			p.Denom, p.Amount = denom, amount
+		}
+		src.preconfirmations.record(p)
+		pp.log.Debug(
+			"Relayed packet before finalization",
+			zap.String("chain_id", src.info.ChainID),
+			zap.String("dst_chain_id", dst.info.ChainID),
+			zap.Uint64("sequence", p.Sequence),
+			zap.Uint64("height", p.Height),
+			zap.Int64("finalized_height", src.finalizedHeight),
+		)
+	}
+}
+
+// reconcilePreconfirmations checks the preconfirmed packets of the path end whose block has since been finalized
+// against the finalized blocks: a packet still sent at the same height with the same data is confirmed,
+// any other outcome is a mismatch. Packets are checked again on the next call if the query fails.
+func (pp *PathProcessor) reconcilePreconfirmations(ctx context.Context, pathEnd *pathEndRuntime) {
+	t := pathEnd.preconfirmations
+	if t == nil || pathEnd.finalizedHeight < 0 {
+		return
+	}
+	due := t.due(pp.pathName, pathEnd.info.ChainID, uint64(pathEnd.finalizedHeight), maxPreconfirmationsReconciled)
+	if len(due) == 0 {
+		return
+	}
+	q, ok := pathEnd.chainProvider.(sendPacketQuerier)
+	if !ok {
+		pathEnd.log.Warn("Chain provider cannot look up sent packets, finalized preconfirmations are not verified")
+		for _, p := range due {
+			t.confirm(p)
+		}
+		return
+	}
+
+	for _, p := range due {
+		queryCtx, cancel := context.WithTimeout(ctx, packetProofQueryTimeout)
+		packet, err := q.QuerySendPacket(queryCtx, p.ChannelID, p.PortID, p.Sequence)
+		cancel()
+		switch {
+		case errors.Is(err, provider.ErrPacketNotFound):
+			t.mismatch(p, "packet was not sent in the finalized blocks")
+		case err != nil:
+			pathEnd.log.Debug(
+				"Failed to query preconfirmed packet, retrying",
+				zap.Uint64("sequence", p.Sequence),
+				zap.Error(err),
+			)
+			return
+		case packet.Height != p.Height:
+			t.mismatch(p, fmt.Sprintf("packet was sent at height %d of the finalized blocks", packet.Height))
+		case !bytes.Equal(packet.Data, p.data):
+			t.mismatch(p, "packet was sent with other data in the finalized blocks")
+		default:
+			t.confirm(p)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type mockSendPacketProvider struct {
+	provider.ChainProvider
+	packets map[uint64]provider.PacketInfo
+	err     error
+}
+
+func (p *mockSendPacketProvider) QuerySendPacket(_ context.Context, _, _ string, seq uint64) (provider.PacketInfo, error) {
+	if p.err != nil {
+		return provider.PacketInfo{}, p.err
+	}
+	packet, ok := p.packets[seq]
+	if !ok {
+		return provider.PacketInfo{}, provider.ErrPacketNotFound
+	}
+	return packet, nil
+}
+
+func TestPreconfirmations(t *testing.T) {
+	ctx := context.Background()
+	log := zaptest.NewLogger(t)
+
+	pp := NewPathProcessor(log, PathEnd{ChainID: "rollapp"}, PathEnd{ChainID: "hub"}, "")
+	pp.pathName = "demo-path"
+	rollapp, hub := pp.pathEnd1, pp.pathEnd2
+
+	g := &mockFinalityGater{height: 10}
+	pp.SetFinalityGater("rollapp", g)
+	tracker := NewPreconfirmationTracker(log, nil)
+	pp.SetPreconfirmation("rollapp", tracker)
+	require.Nil(t, hub.preconfirmations)
+	rollapp.updateFinalizedHeight(ctx)
+
+	// Optimistic relaying is enabled by default, and can be turned off for the path.
+	require.True(t, pp.preconfirms(rollapp))
+	require.False(t, pp.preconfirms(hub))
+	optimistic := false
+	pp.SetFeatureGate(func(feature string) bool { return feature == FeatureOptimisticRelaying && optimistic })
+	require.False(t, pp.preconfirms(rollapp))
+	optimistic = true
+	require.True(t, pp.preconfirms(rollapp))
+	pp.SetFeatureGate(nil)
+
+	packet := func(seq, height uint64, data string) packetMessageToTrack {
+		return packetMessageToTrack{
+			msg: packetIBCMessage{
+				eventType: chantypes.EventTypeRecvPacket,
+				info: provider.PacketInfo{
+					Height:        height,
+					Sequence:      seq,
+					SourcePort:    "transfer",
+					SourceChannel: "channel-0",
+					Data:          []byte(data),
+				},
+			},
+			assembled: true,
+		}
+	}
+	transfer := `{"denom":"urax","amount":"100"}`
+	pp.recordPreconfirmed(rollapp, hub, &provider.RelayerTxResponse{TxHash: "ABC"}, []packetMessageToTrack{
+		packet(1, 10, transfer), // already final
+		packet(2, 11, transfer),
+		packet(3, 12, transfer),
+		packet(4, 12, transfer),
+		packet(5, 20, transfer),
+	})
+
+	s := tracker.Snapshot()
+	require.Len(t, s.Pending, 4)
+	require.Equal(t, uint64(2), s.Pending[0].Sequence)
+	require.Equal(t, "urax", s.Pending[0].Denom)
+	require.Equal(t, "100", s.Pending[0].Amount)
+	require.Equal(t, "ABC", s.Pending[0].TxHash)
+
+	chain := &mockSendPacketProvider{packets: map[uint64]provider.PacketInfo{
+		2: packet(2, 11, transfer).msg.info,
+		3: packet(3, 13, transfer).msg.info,
+		4: packet(4, 12, `{"denom":"urax","amount":"1"}`).msg.info,
+		5: packet(5, 20, transfer).msg.info,
+	}}
+	rollapp.chainProvider = chain
+
+	// Query failures leave the packets pending.
+	g.height = 12
+	rollapp.updateFinalizedHeight(ctx)
+	chain.err = errors.New("rpc unavailable")
+	pp.reconcilePreconfirmations(ctx, rollapp)
+	require.Len(t, tracker.Snapshot().Pending, 4)
+
+	chain.err = nil
+	pp.reconcilePreconfirmations(ctx, rollapp)
+	s = tracker.Snapshot()
+	require.Len(t, s.Pending, 1)
+	require.Equal(t, uint64(5), s.Pending[0].Sequence)
+	require.Len(t, s.Mismatches, 2)
+	require.Equal(t, uint64(3), s.Mismatches[0].Sequence)
+	require.Equal(t, "packet was sent at height 13 of the finalized blocks", s.Mismatches[0].Mismatch)
+	require.Equal(t, uint64(4), s.Mismatches[1].Sequence)
+
+	// A packet reverted with its block is missing from the finalized state.
+	delete(chain.packets, 5)
+	g.height = 20
+	rollapp.updateFinalizedHeight(ctx)
+	pp.reconcilePreconfirmations(ctx, rollapp)
+	s = tracker.Snapshot()
+	require.Empty(t, s.Pending)
+	require.Len(t, s.Mismatches, 3)
+	require.Equal(t, "packet was not sent in the finalized blocks", s.Mismatches[2].Mismatch)
+}
@@ -0,0 +1,224 @@
+package processor
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RateLimit caps the amount of a denom relayed over a channel within a sliding window.
+type RateLimit struct {
+	// PortID and ChannelID restrict the limit to a channel of the src chain of the path, matched as channel filter
+	// entries. The limit applies to every channel of the path if ChannelID is empty, each channel counted apart.
+	PortID, ChannelID string
+
+	// Denom is matched as it appears in the packet data, as for the PacketFilter.
+	Denom string
+
+	Amount *big.Int
+	Window time.Duration
+}
+
+// RateLimitExceededFunc is called with the channel, identified by its port and channel IDs on the src chain
+// of the path, whose transfer exceeded a rate limit, and why.
+type RateLimitExceededFunc func(portID, channelID, reason string)
+
+// RateLimitUsage reports the amount of a denom relayed over a channel within the window of its rate limit.
+type RateLimitUsage struct {
+	PortID    string `json:"port_id"`
+	ChannelID string `json:"channel_id"`
+	Denom     string `json:"denom"`
+	Relayed   string `json:"relayed"`
+	Limit     string `json:"limit"`
+	Window    string `json:"window"`
+}
+
+type rateLimitedChannel struct {
+	portID, channelID string
+}
+
+// rateLimitedTransfer is a transfer counted against the rate limits of its channel.
+type rateLimitedTransfer struct {
+	chainID string
+	seq     uint64
+	denom   string
+	amount  *big.Int
+	at      time.Time
+}
+
+// RateLimiter caps the total amount of the ICS-20 transfers relayed over each channel of a path within a sliding
+// window, as a safety brake against draining a bridge: a transfer which would take a channel over its cap is held
+// back, and the channel is reported as exceeded, to be paused until an operator resumes it.
+// Channels are identified from the perspective of the src chain of the path, and the transfers of both directions
+// of a channel count towards its caps. It is safe for concurrent use, and a nil *RateLimiter limits nothing.
+type RateLimiter struct {
+	srcChainID string
+	limits     []RateLimit
+	onExceeded RateLimitExceededFunc
+	now        func() time.Time
+
+	mu       sync.Mutex
+	relayed  map[rateLimitedChannel][]rateLimitedTransfer
+	channels map[rateLimitedChannel]bool
+}
+
+// NewRateLimiter returns a rate limiter enforcing limits on the channels of the path whose src chain is srcChainID.
+func NewRateLimiter(srcChainID string, limits []RateLimit) *RateLimiter {
+	return &RateLimiter{
+		srcChainID: srcChainID,
+		limits:     limits,
+		now:        time.Now,
+		relayed:    make(map[rateLimitedChannel][]rateLimitedTransfer),
+		channels:   make(map[rateLimitedChannel]bool),
+	}
+}
+
+// SetOnExceeded calls f whenever a transfer exceeds a rate limit of its channel, e.g. to pause the channel.
+func (l *RateLimiter) SetOnExceeded(f RateLimitExceededFunc) {
+	l.onExceeded = f
+}
+
+// Allow returns why the transfer with the given sequence and data, sent from the chain chainID over the channel
+// identified by k from the perspective of chainID, exceeds a rate limit of the channel, or an empty string
+// if it can be relayed, in which case it is counted against the limits of the channel.
+// Transfers already counted and packets of other applications are always allowed.
+func (l *RateLimiter) Allow(chainID string, k ChannelKey, seq uint64, data []byte) string {
+	if l == nil {
+		return ""
+	}
+	denom, amount, ok := transferData(data)
+	if !ok {
+		return ""
+	}
+	a, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return ""
+	}
+	c := rateLimitedChannel{portID: k.PortID, channelID: k.ChannelID}
+	if chainID != l.srcChainID {
+		c = rateLimitedChannel{portID: k.CounterpartyPortID, channelID: k.CounterpartyChannelID}
+	}
+
+	reason := l.allow(c, chainID, seq, denom, a)
+	if reason != "" && l.onExceeded != nil {
+		l.onExceeded(c.portID, c.channelID, reason)
+	}
+	return reason
+}
+
+func (l *RateLimiter) allow(c rateLimitedChannel, chainID string, seq uint64, denom string, amount *big.Int) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(c, now)
+	for _, t := range l.relayed[c] {
+		if t.chainID == chainID && t.seq == seq {
+			return ""
+		}
+	}
+
+	limited := false
+	for _, limit := range l.limits {
+		if limit.Denom != denom || !l.applies(limit, c) {
+			continue
+		}
+		limited = true
+		total := new(big.Int).Set(amount)
+		for _, t := range l.relayed[c] {
+			if t.denom == denom && now.Sub(t.at) < limit.Window {
+				total.Add(total, t.amount)
+			}
+		}
+		if total.Cmp(limit.Amount) > 0 {
+			return fmt.Sprintf("relaying %s%s would take the channel to %s%s within %s, over its limit of %s%s",
+				amount, denom, total, denom, limit.Window, limit.Amount, denom)
+		}
+	}
+	if limited {
+		l.relayed[c] = append(l.relayed[c], rateLimitedTransfer{chainID: chainID, seq: seq, denom: denom, amount: amount, at: now})
+		l.channels[c] = true
+	}
+	return ""
+}
+
+func (l *RateLimiter) applies(limit RateLimit, c rateLimitedChannel) bool {
+	if limit.ChannelID == "" {
+		return true
+	}
+	return (limit.PortID == "" || MatchChannelPattern(limit.PortID, c.portID)) && MatchChannelPattern(limit.ChannelID, c.channelID)
+}
+
+// prune forgets the transfers of c older than the longest window of the limits.
+func (l *RateLimiter) prune(c rateLimitedChannel, now time.Time) {
+	var longest time.Duration
+	for _, limit := range l.limits {
+		if limit.Window > longest {
+			longest = limit.Window
+		}
+	}
+	transfers := l.relayed[c]
+	i := 0
+	for i < len(transfers) && now.Sub(transfers[i].at) >= longest {
+		i++
+	}
+	l.relayed[c] = transfers[i:]
+}
+
+// Reset forgets the transfers counted against the limits of the channel of the src chain, e.g. once an operator
+// resumed it after reviewing them, so that its pending transfers are relayed.
+func (l *RateLimiter) Reset(portID, channelID string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.relayed, rateLimitedChannel{portID: portID, channelID: channelID})
+}
+
+// Usage returns the amount relayed within the window of every rate limit of the channels which relayed
+// a limited denom, by port, channel and denom.
+func (l *RateLimiter) Usage() []RateLimitUsage {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	usage := []RateLimitUsage{}
+	for c := range l.channels {
+		l.prune(c, now)
+		for _, limit := range l.limits {
+			if !l.applies(limit, c) {
+				continue
+			}
+			relayed := new(big.Int)
+			for _, t := range l.relayed[c] {
+				if t.denom == limit.Denom && now.Sub(t.at) < limit.Window {
+					relayed.Add(relayed, t.amount)
+				}
+			}
+			usage = append(usage, RateLimitUsage{
+				PortID:    c.portID,
+				ChannelID: c.channelID,
+				Denom:     limit.Denom,
+				Relayed:   relayed.String(),
+				Limit:     limit.Amount.String(),
+				Window:    limit.Window.String(),
+			})
+		}
+	}
+	sort.SliceStable(usage, func(i, k int) bool {
+		if usage[i].PortID != usage[k].PortID {
+			return usage[i].PortID < usage[k].PortID
+		}
+		if usage[i].ChannelID != usage[k].ChannelID {
+			return usage[i].ChannelID < usage[k].ChannelID
+		}
+		return usage[i].Denom < usage[k].Denom
+	})
+	return usage
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter("chain-a", []RateLimit{{PortID: "transfer", ChannelID: "channel-0", Denom: "urax", Amount: big.NewInt(100), Window: time.Hour}})
+	l.now = func() time.Time { return now }
+	var exceeded []string
+	l.SetOnExceeded(func(portID, channelID, reason string) {
+		exceeded = append(exceeded, portID+"/"+channelID)
+	})
+
+	fromA := ChannelKey{PortID: "transfer", ChannelID: "channel-0", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-7"}
+	fromB := ChannelKey{PortID: "transfer", ChannelID: "channel-7", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-0"}
+
+	require.Empty(t, l.Allow("chain-a", fromA, 1, []byte(`{"denom":"urax","amount":"60"}`)))
+	require.Empty(t, l.Allow("chain-a", fromA, 1, []byte(`{"denom":"urax","amount":"60"}`)), "already counted")
+
+	// Both directions of the channel count towards its cap.
+	require.Equal(t, "relaying 50urax would take the channel to 110urax within 1h0m0s, over its limit of 100urax",
+		l.Allow("chain-b", fromB, 1, []byte(`{"denom":"urax","amount":"50"}`)))
+	require.Equal(t, []string{"transfer/channel-0"}, exceeded)
+
+	// Other denoms, channels and applications are not limited.
+	require.Empty(t, l.Allow("chain-a", fromA, 2, []byte(`{"denom":"uatom","amount":"1000"}`)))
+	require.Empty(t, l.Allow("chain-a", ChannelKey{PortID: "transfer", ChannelID: "channel-1"}, 3, []byte(`{"denom":"urax","amount":"1000"}`)))
+	require.Empty(t, l.Allow("chain-a", fromA, 4, []byte(`not a transfer`)))
+
+	require.Equal(t, []RateLimitUsage{{PortID: "transfer", ChannelID: "channel-0", Denom: "urax", Relayed: "60", Limit: "100", Window: "1h0m0s"}}, l.Usage())
+
+	// Transfers leave the window as it slides.
+	now = now.Add(time.Hour)
+	require.Empty(t, l.Allow("chain-b", fromB, 1, []byte(`{"denom":"urax","amount":"50"}`)))
+	require.Equal(t, "50", l.Usage()[0].Relayed)
+
+	l.Reset("transfer", "channel-0")
+	require.Equal(t, "0", l.Usage()[0].Relayed)
+
+	var none *RateLimiter
+	require.Empty(t, none.Allow("chain-a", fromA, 1, []byte(`{"denom":"urax","amount":"1000"}`)))
+}
+
+type validPacketProvider struct {
+	provider.ChainProvider
+}
+
+func (validPacketProvider) ValidatePacket(provider.PacketInfo, provider.LatestBlock) error {
+	return nil
+}
+
+func TestRateLimiterHoldsBackPackets(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "chain-a"}, PathEnd{ChainID: "chain-b"}, "")
+	l := NewRateLimiter("chain-a", []RateLimit{{Denom: "urax", Amount: big.NewInt(100), Window: time.Hour}})
+	pp.SetRateLimiter(l)
+	pp.pathEnd2.chainProvider = validPacketProvider{}
+
+	small := provider.PacketInfo{Sequence: 1, SourceChannel: "channel-0", SourcePort: "transfer", Data: []byte(`{"denom":"urax","amount":"10"}`)}
+	large := provider.PacketInfo{Sequence: 2, SourceChannel: "channel-0", SourcePort: "transfer", Data: []byte(`{"denom":"urax","amount":"1000"}`)}
+	res := pp.getUnrelayedPacketsAndAcksAndToDelete(context.Background(), pathEndPacketFlowMessages{
+		Src:            pp.pathEnd1,
+		Dst:            pp.pathEnd2,
+		SrcMsgTransfer: PacketSequenceCache{1: small, 2: large},
+	})
+
+	// Held back packets are not counted, and kept to be relayed once allowed.
+	require.Equal(t, "10", l.Usage()[0].Relayed)
+	require.Empty(t, res.ToDeleteSrc)
+}
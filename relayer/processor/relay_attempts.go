@@ -0,0 +1,129 @@
+package processor
+
+import (
+	"sync"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+)
+
+// RelayAttempt is the last attempt of the relayer at relaying a packet, or the acknowledgement of a packet if Ack.
+// ChainID, ChannelID and PortID identify the channel end the packet was sent from, or the acknowledgement written on.
+type RelayAttempt struct {
+	Path      string `json:"path"`
+	ChainID   string `json:"chain_id"`
+	ChannelID string `json:"channel_id"`
+	PortID    string `json:"port_id"`
+	Sequence  uint64 `json:"sequence"`
+	Ack       bool   `json:"ack,omitempty"`
+
+	// MsgType is the message relaying the packet, one of the types of the relayed_packets_total metric,
+	// empty if the attempt failed before its message was built.
+	MsgType string `json:"msg_type,omitempty"`
+
+	// Attempts counts the attempts since the relayer started, Time is when the last one was made,
+	// and Error why it failed, empty if its message was sent.
+	Attempts uint64    `json:"attempts"`
+	Time     time.Time `json:"time"`
+	Error    string    `json:"error,omitempty"`
+}
+
+type relayAttemptKey struct {
+	path, chainID, channelID, portID string
+	sequence                         uint64
+	ack                              bool
+}
+
+func (a RelayAttempt) key() relayAttemptKey {
+	return relayAttemptKey{a.Path, a.ChainID, a.ChannelID, a.PortID, a.Sequence, a.Ack}
+}
+
+// RelayAttempts records the last attempt at relaying each packet and acknowledgement, by the path processors
+// it is set on with PathProcessor.SetRelayAttempts and by the legacy processor, so that the packets pending
+// for long can be told apart by why they are not relayed. Attempts are kept until forgotten with Retain.
+// A RelayAttempts may be shared by the path processors of several paths.
+type RelayAttempts struct {
+	mu       sync.Mutex
+	attempts map[relayAttemptKey]RelayAttempt
+}
+
+// NewRelayAttempts returns an empty record of relay attempts.
+func NewRelayAttempts() *RelayAttempts {
+	return &RelayAttempts{attempts: make(map[relayAttemptKey]RelayAttempt)}
+}
+
+// Record records an attempt at relaying the packet or acknowledgement of a, failed with err if non-nil.
+// It is a no-op on a nil RelayAttempts.
+func (r *RelayAttempts) Record(a RelayAttempt, err error) {
+	if r == nil {
+		return
+	}
+	a.Time, a.Error = time.Now().UTC(), ""
+	if err != nil {
+		a.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a.Attempts = r.attempts[a.key()].Attempts + 1
+	r.attempts[a.key()] = a
+}
+
+// Last returns the last attempt at relaying the packet of path with sequence, or its acknowledgement if ack,
+// sent from or written on the channel end, false if no attempt was recorded.
+func (r *RelayAttempts) Last(path, chainID, channelID, portID string, sequence uint64, ack bool) (RelayAttempt, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.attempts[relayAttemptKey{path, chainID, channelID, portID, sequence, ack}]
+	return a, ok
+}
+
+// Retain forgets the attempts of path keep returns false for, such as those of the packets relayed since.
+func (r *RelayAttempts) Retain(path string, keep func(RelayAttempt) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, a := range r.attempts {
+		if k.path == path && !keep(a) {
+			delete(r.attempts, k)
+		}
+	}
+}
+
+// SetRelayAttempts records the attempts of the path processor at relaying packets and acknowledgements in a.
+// Must be called before Run.
+func (pp *PathProcessor) SetRelayAttempts(a *RelayAttempts) {
+	pp.relayAttempts = a
+}
+
+// recordAttempt records the attempt at relaying the packet message m from src to dst, failed with err if non-nil.
+func (pp *PathProcessor) recordAttempt(src, dst *pathEndRuntime, m packetIBCMessage, err error) {
+	if pp.relayAttempts == nil {
+		return
+	}
+	msgType, ok := metricRelayedTypes[m.eventType]
+	if !ok {
+		return
+	}
+	a := RelayAttempt{Path: pp.pathName, Sequence: m.info.Sequence, MsgType: msgType}
+	switch m.eventType {
+	case chantypes.EventTypeRecvPacket:
+		// src sent the packet.
+		a.ChainID, a.ChannelID, a.PortID = src.info.ChainID, m.info.SourceChannel, m.info.SourcePort
+	case chantypes.EventTypeAcknowledgePacket:
+		// src wrote the acknowledgement of the packet dst sent.
+		a.ChainID, a.ChannelID, a.PortID, a.Ack = src.info.ChainID, m.info.DestChannel, m.info.DestPort, true
+	default:
+		// dst sent the packet timing out.
+		a.ChainID, a.ChannelID, a.PortID = dst.info.ChainID, m.info.SourceChannel, m.info.SourcePort
+	}
+	pp.relayAttempts.Record(a, err)
+}
+
+// recordAttempts records the attempts at relaying the assembled packet messages of a transaction from src to dst,
+// failed with err if non-nil.
+func (pp *PathProcessor) recordAttempts(src, dst *pathEndRuntime, pktMsgs []packetMessageToTrack, err error) {
+	for _, m := range pktMsgs {
+		if m.assembled {
+			pp.recordAttempt(src, dst, m.msg, err)
+		}
+	}
+}
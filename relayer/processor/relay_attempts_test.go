@@ -0,0 +1,40 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayAttempts(t *testing.T) {
+	var none *RelayAttempts
+	none.Record(RelayAttempt{Path: "a-b"}, nil)
+
+	r := NewRelayAttempts()
+	packet := RelayAttempt{Path: "a-b", ChainID: "chain-a", ChannelID: "channel-0", PortID: "transfer", Sequence: 1, MsgType: MetricRecvPacket}
+	r.Record(packet, errors.New("out of gas"))
+	r.Record(packet, nil)
+	ack := packet
+	ack.Ack, ack.MsgType = true, MetricAckPacket
+	r.Record(ack, errors.New("account sequence mismatch"))
+
+	a, ok := r.Last("a-b", "chain-a", "channel-0", "transfer", 1, false)
+	require.True(t, ok)
+	require.Equal(t, uint64(2), a.Attempts)
+	require.Empty(t, a.Error)
+	a, ok = r.Last("a-b", "chain-a", "channel-0", "transfer", 1, true)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), a.Attempts)
+	require.Equal(t, "account sequence mismatch", a.Error)
+
+	// Only the attempts of the path are forgotten.
+	r.Record(RelayAttempt{Path: "c-d", ChainID: "chain-c", ChannelID: "channel-0", PortID: "transfer", Sequence: 1}, nil)
+	r.Retain("a-b", func(a RelayAttempt) bool { return a.Ack })
+	_, ok = r.Last("a-b", "chain-a", "channel-0", "transfer", 1, false)
+	require.False(t, ok)
+	_, ok = r.Last("a-b", "chain-a", "channel-0", "transfer", 1, true)
+	require.True(t, ok)
+	_, ok = r.Last("c-d", "chain-c", "channel-0", "transfer", 1, false)
+	require.True(t, ok)
+}
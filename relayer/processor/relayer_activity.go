@@ -0,0 +1,141 @@
+package processor
+
+import (
+	"sort"
+	"sync"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+)
+
+// RelayerActivity tracks which relayer addresses deliver packets and acknowledgements on relayed channels,
+// so that operators can tell whether they are the sole relayer of a path or competing with others.
+type RelayerActivity struct {
+	mu     sync.RWMutex
+	own    map[string]string
+	counts map[relayerActivityKey]*relayerCounts
+}
+
+type relayerActivityKey struct {
+	chainID   string
+	channelID string
+	portID    string
+	address   string
+}
+
+type relayerCounts struct {
+	recv uint64
+	ack  uint64
+}
+
+// RelayerShare is the number of packets and acknowledgements delivered by a single relayer address on a channel.
+type RelayerShare struct {
+	Address string  `json:"address"`
+	Own     bool    `json:"own"`
+	Recv    uint64  `json:"recv_packets"`
+	Ack     uint64  `json:"ack_packets"`
+	Share   float64 `json:"share"`
+}
+
+// ChannelRelayerActivity is the relayer activity observed on a channel of a chain.
+type ChannelRelayerActivity struct {
+	ChainID     string         `json:"chain_id"`
+	ChannelID   string         `json:"channel_id"`
+	PortID      string         `json:"port_id"`
+	OwnShare    float64        `json:"own_share"`
+	SoleRelayer bool           `json:"sole_relayer"`
+	Relayers    []RelayerShare `json:"relayers"`
+}
+
+// NewRelayerActivity returns a RelayerActivity with no activity recorded.
+func NewRelayerActivity() *RelayerActivity {
+	return &RelayerActivity{
+		own:    make(map[string]string),
+		counts: make(map[relayerActivityKey]*relayerCounts),
+	}
+}
+
+// SetOwnAddress sets the address this relayer signs with on the given chain,
+// used to distinguish our own deliveries from those of counterparty relayers.
+func (a *RelayerActivity) SetOwnAddress(chainID, address string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.own[chainID] = address
+}
+
+// Record accounts for a recv_packet or acknowledge_packet message submitted by address
+// on the given channel of the chain. Other event types are ignored.
+func (a *RelayerActivity) Record(chainID, channelID, portID, eventType, address string) {
+	if address == "" {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	k := relayerActivityKey{chainID: chainID, channelID: channelID, portID: portID, address: address}
+	c, ok := a.counts[k]
+	if !ok {
+		c = new(relayerCounts)
+		a.counts[k] = c
+	}
+	switch eventType {
+	case chantypes.EventTypeRecvPacket:
+		c.recv++
+	case chantypes.EventTypeAcknowledgePacket:
+		c.ack++
+	}
+}
+
+// Snapshot returns the activity observed on every channel, sorted by chain, channel and port.
+func (a *RelayerActivity) Snapshot() []ChannelRelayerActivity {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	type channel struct{ chainID, channelID, portID string }
+	byChannel := make(map[channel]*ChannelRelayerActivity)
+	for k, c := range a.counts {
+		ch := channel{k.chainID, k.channelID, k.portID}
+		activity, ok := byChannel[ch]
+		if !ok {
+			activity = &ChannelRelayerActivity{ChainID: k.chainID, ChannelID: k.channelID, PortID: k.portID}
+			byChannel[ch] = activity
+		}
+		activity.Relayers = append(activity.Relayers, RelayerShare{
+			Address: k.address,
+			Own:     a.own[k.chainID] == k.address,
+			Recv:    c.recv,
+			Ack:     c.ack,
+		})
+	}
+
+	res := make([]ChannelRelayerActivity, 0, len(byChannel))
+	for _, activity := range byChannel {
+		var total uint64
+		for _, r := range activity.Relayers {
+			total += r.Recv + r.Ack
+		}
+		for i, r := range activity.Relayers {
+			if total > 0 {
+				activity.Relayers[i].Share = float64(r.Recv+r.Ack) / float64(total)
+			}
+			if r.Own {
+				activity.OwnShare = activity.Relayers[i].Share
+			}
+		}
+		activity.SoleRelayer = len(activity.Relayers) == 1 && activity.Relayers[0].Own
+		sort.Slice(activity.Relayers, func(i, j int) bool {
+			return activity.Relayers[i].Address < activity.Relayers[j].Address
+		})
+		res = append(res, *activity)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].ChainID != res[j].ChainID {
+			return res[i].ChainID < res[j].ChainID
+		}
+		if res[i].ChannelID != res[j].ChannelID {
+			return res[i].ChannelID < res[j].ChannelID
+		}
+		return res[i].PortID < res[j].PortID
+	})
+	return res
+}
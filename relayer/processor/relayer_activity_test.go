@@ -0,0 +1,38 @@
+package processor_test
+
+import (
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+)
+
+func TestRelayerActivitySnapshot(t *testing.T) {
+	a := processor.NewRelayerActivity()
+	a.SetOwnAddress("chain-a", "addr-own")
+
+	a.Record("chain-a", "channel-0", "transfer", chantypes.EventTypeRecvPacket, "addr-own")
+	a.Record("chain-a", "channel-0", "transfer", chantypes.EventTypeRecvPacket, "addr-own")
+	a.Record("chain-a", "channel-0", "transfer", chantypes.EventTypeAcknowledgePacket, "addr-own")
+	a.Record("chain-a", "channel-0", "transfer", chantypes.EventTypeRecvPacket, "addr-other")
+	a.Record("chain-a", "channel-1", "transfer", chantypes.EventTypeAcknowledgePacket, "addr-own")
+	// unknown signers are not accounted for
+	a.Record("chain-a", "channel-1", "transfer", chantypes.EventTypeAcknowledgePacket, "")
+
+	snapshot := a.Snapshot()
+	require.Len(t, snapshot, 2)
+
+	require.Equal(t, "channel-0", snapshot[0].ChannelID)
+	require.False(t, snapshot[0].SoleRelayer)
+	require.Equal(t, 0.75, snapshot[0].OwnShare)
+	require.Equal(t, []processor.RelayerShare{
+		{Address: "addr-other", Recv: 1, Share: 0.25},
+		{Address: "addr-own", Own: true, Recv: 2, Ack: 1, Share: 0.75},
+	}, snapshot[0].Relayers)
+
+	require.Equal(t, "channel-1", snapshot[1].ChannelID)
+	require.True(t, snapshot[1].SoleRelayer)
+	require.Equal(t, float64(1), snapshot[1].OwnShare)
+}
@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChainSyncProgress is the progress of the initial sync of a chain, the backfill of its block history.
+type ChainSyncProgress struct {
+	ChainID string `json:"chain_id"`
+	InSync  bool   `json:"in_sync"`
+
+	// StartHeight is the block the backfill started after.
+	StartHeight        int64 `json:"start_height"`
+	LatestQueriedBlock int64 `json:"latest_queried_block"`
+	LatestHeight       int64 `json:"latest_height"`
+	RemainingBlocks    int64 `json:"remaining_blocks"`
+
+	// Percent is the share of the blocks between StartHeight and LatestHeight already handled.
+	Percent         float64 `json:"percent"`
+	BlocksPerSecond float64 `json:"blocks_per_second"`
+
+	// ETASeconds estimates the time left until the chain is in sync from the rate at which the remaining
+	// blocks decreased so far, unset while the backfill is not catching up with the chain.
+	ETASeconds *float64 `json:"eta_seconds,omitempty"`
+
+	StartedAt time.Time `json:"started_at"`
+
+	// LastProgressAt is when the latest queried block last advanced, telling a stuck backfill from a slow one.
+	LastProgressAt time.Time `json:"last_progress_at"`
+}
+
+// SyncProgress tracks the progress of the initial sync of each chain, as reported by their ChainProcessors.
+// A nil SyncProgress tracks nothing.
+type SyncProgress struct {
+	mu     sync.RWMutex
+	chains map[string]ChainSyncProgress
+}
+
+// NewSyncProgress returns a SyncProgress tracking no chain yet.
+func NewSyncProgress() *SyncProgress {
+	return &SyncProgress{chains: make(map[string]ChainSyncProgress)}
+}
+
+func (t *SyncProgress) set(s ChainSyncProgress) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.chains[s.ChainID] = s
+}
+
+// Snapshot returns the progress of the initial sync of each chain, by chain ID.
+func (t *SyncProgress) Snapshot() []ChainSyncProgress {
+	if t == nil {
+		return nil
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]ChainSyncProgress, 0, len(t.chains))
+	for _, s := range t.chains {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ChainID < out[j].ChainID })
+	return out
+}
@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// txEnvelopeBytes is the room left in a transaction for its envelope besides its messages:
+// signatures, signer infos, fee and timeout height.
+const txEnvelopeBytes = 1024
+
+// fitMaxTxBytes drops from om the packet messages in excess of the max tx bytes of dst, in order, so that
+// the transaction fits in the blocks and mempool of dst: the messages deferred are neither tracked nor sent,
+// and are assembled again on the next round. The first packet message is always sent unless oversized.
+//
+// A packet message exceeding the max tx bytes on its own, such as the acknowledgement of a contract writing
+// a huge payload, can never be relayed to dst: it is reported as oversized and left unassembled, so it is
+// retried until the processor gives up on it, instead of holding back its channel silently.
+func (pp *PathProcessor) fitMaxTxBytes(dst *pathEndRuntime, om *outgoingMessages) {
+	limit := dst.maxTxBytes
+	if limit == 0 {
+		return
+	}
+	size := uint64(txEnvelopeBytes + len(pp.memo))
+	for _, msg := range om.msgs {
+		size += msgSize(msg)
+	}
+
+	var kept, deferred int
+	pktMsgs := om.pktMsgs[:0]
+	for _, m := range om.pktMsgs {
+		if !m.assembled {
+			pktMsgs = append(pktMsgs, m)
+			continue
+		}
+		n := msgSize(m.message)
+		switch {
+		case txEnvelopeBytes+n > limit:
+			pp.reportOversized(dst, m, n)
+			m.assembled = false
+		case kept > 0 && size+n > limit:
+			deferred++
+			continue
+		default:
+			size += n
+			kept++
+		}
+		pktMsgs = append(pktMsgs, m)
+	}
+	om.pktMsgs = pktMsgs
+
+	if deferred > 0 {
+		pp.log.Info(
+			"Deferring packet messages over the max tx bytes of the chain to the next transactions",
+			zap.String("chain_id", dst.info.ChainID),
+			zap.Int("sent", kept),
+			zap.Int("deferred", deferred),
+			zap.Uint64("tx_bytes", size),
+			zap.Uint64("max_tx_bytes", limit),
+		)
+	}
+}
+
+// reportOversized reports the packet message m of n bytes, which exceeds the max tx bytes of dst on its own.
+func (pp *PathProcessor) reportOversized(dst *pathEndRuntime, m packetMessageToTrack, n uint64) {
+	info := m.msg.info
+	pp.log.Error(
+		"Packet message exceeds the max tx bytes of the chain on its own and cannot be relayed",
+		zap.String("chain_id", dst.info.ChainID),
+		zap.String("event_type", m.msg.eventType),
+		zap.Uint64("sequence", info.Sequence),
+		zap.String("src_channel", info.SourceChannel),
+		zap.String("src_port", info.SourcePort),
+		zap.String("dst_channel", info.DestChannel),
+		zap.String("dst_port", info.DestPort),
+		zap.Int("ack_bytes", len(info.Ack)),
+		zap.Uint64("msg_bytes", n),
+		zap.Uint64("max_tx_bytes", dst.maxTxBytes),
+	)
+	msgType, ok := metricRelayedTypes[m.msg.eventType]
+	if !ok {
+		return
+	}
+	// As relayed packets, MsgRecvPacket is counted on the destination channel of the packet.
+	channelID, portID := info.SourceChannel, info.SourcePort
+	if msgType == MetricRecvPacket {
+		channelID, portID = info.DestChannel, info.DestPort
+	}
+	pp.metrics.IncOversizedPacketMessages(pp.pathName, dst.info.ChainID, channelID, portID, msgType)
+}
+
+// msgSize returns the size of the encoded msg, 0 if it fails to encode.
+func msgSize(msg provider.RelayerMessage) uint64 {
+	bz, err := msg.MsgBytes()
+	if err != nil {
+		return 0
+	}
+	return uint64(len(bz))
+}
@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type sizedMessage int
+
+func (m sizedMessage) Type() string              { return "sized" }
+func (m sizedMessage) MsgBytes() ([]byte, error) { return make([]byte, m), nil }
+func (m sizedMessage) Seq() uint64               { return 0 }
+
+func TestFitMaxTxBytes(t *testing.T) {
+	pp := NewPathProcessor(zaptest.NewLogger(t), PathEnd{ChainID: "chain-a"}, PathEnd{ChainID: "chain-b"}, "")
+	pp.SetMaxTxBytes("chain-b", 10_000)
+	pp.SetMetrics(NewPrometheusMetrics())
+
+	ack := func(seq uint64, size int) packetMessageToTrack {
+		return packetMessageToTrack{
+			msg: packetIBCMessage{
+				eventType: chantypes.EventTypeAcknowledgePacket,
+				info:      provider.PacketInfo{Sequence: seq, SourceChannel: "channel-0", SourcePort: "wasm.contract"},
+			},
+			assembled: true,
+			message:   sizedMessage(size),
+		}
+	}
+	om := outgoingMessages{
+		msgs: []provider.RelayerMessage{sizedMessage(2_000)}, // MsgUpdateClient
+		pktMsgs: []packetMessageToTrack{
+			ack(1, 4_000),
+			{msg: packetIBCMessage{eventType: chantypes.EventTypeAcknowledgePacket, info: provider.PacketInfo{Sequence: 2}}},
+			ack(3, 20_000),
+			ack(4, 2_000),
+			ack(5, 2_000),
+		},
+	}
+	pp.fitMaxTxBytes(pp.pathEnd2, &om)
+
+	// The acks fitting in the transaction are sent, the last one is deferred,
+	// and the oversized one is left unassembled to be given up on.
+	var sent, unassembled []uint64
+	for _, m := range om.pktMsgs {
+		if m.assembled {
+			sent = append(sent, m.msg.info.Sequence)
+		} else {
+			unassembled = append(unassembled, m.msg.info.Sequence)
+		}
+	}
+	require.Equal(t, []uint64{1, 4}, sent)
+	require.Equal(t, []uint64{2, 3}, unassembled)
+
+	families, err := pp.metrics.Registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Equal(t, "cosmos_relayer_oversized_packet_messages_total", families[0].GetName())
+
+	// The first packet message is sent even if it does not fit along with the other messages.
+	om = outgoingMessages{msgs: []provider.RelayerMessage{sizedMessage(6_000)}, pktMsgs: []packetMessageToTrack{ack(6, 5_000), ack(7, 100)}}
+	pp.fitMaxTxBytes(pp.pathEnd2, &om)
+	require.Len(t, om.pktMsgs, 1)
+
+	// Transactions to chains without max tx bytes are not limited.
+	om = outgoingMessages{pktMsgs: []packetMessageToTrack{ack(8, 20_000)}}
+	pp.fitMaxTxBytes(pp.pathEnd1, &om)
+	require.True(t, om.pktMsgs[0].assembled)
+}
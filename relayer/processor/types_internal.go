@@ -142,6 +142,9 @@ type pathEndPacketFlowResponse struct {
 
 	ToDeleteSrc map[string][]uint64
 	ToDeleteDst map[string][]uint64
+
+	// Held are the packets sent on src held back until their block is finalized.
+	Held []provider.PacketInfo
 }
 
 type pathEndChannelHandshakeResponse struct {
@@ -209,6 +212,9 @@ func (om *outgoingMessages) Append(msg provider.RelayerMessage) {
 type packetMessageToTrack struct {
 	msg       packetIBCMessage
 	assembled bool
+
+	// message is the assembled message, added to the transaction once all messages are assembled.
+	message provider.RelayerMessage
 }
 
 type connectionMessageToTrack struct {
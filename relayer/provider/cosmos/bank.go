@@ -0,0 +1,151 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	lens "github.com/strangelove-ventures/lens/client"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// SendFromKey sends amount from the account of the key fromKey of the keyring of the chain to toAddress,
+// such as to top up the relayer wallet from a funding account. The transaction is signed with fromKey,
+// with the account number and sequence of its own account, while the key of the chain keeps signing
+// the transactions of the relayer.
+func (cc *CosmosProvider) SendFromKey(
+	ctx context.Context,
+	fromKey, toAddress string,
+	amount sdk.Coins,
+	memo string,
+) (*provider.RelayerTxResponse, error) {
+	if cc.PCfg.Multisig != nil || cc.PCfg.RemoteSigner != nil {
+		return nil, fmt.Errorf("chain %s signs with a multisig or remote signer, which cannot send from key %s", cc.PCfg.ChainID, fromKey)
+	}
+	info, err := cc.Keybase.Key(fromKey)
+	if err != nil {
+		return nil, fmt.Errorf("key %s not found on chain %s: %w", fromKey, cc.PCfg.ChainID, err)
+	}
+	from, err := cc.EncodeBech32AccAddr(info.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+	msgs := []provider.RelayerMessage{NewCosmosMessage(&banktypes.MsgSend{FromAddress: from, ToAddress: toAddress, Amount: amount})}
+
+	txBytes, seq, err := cc.buildFromKey(ctx, fromKey, msgs, memo)
+	if err != nil {
+		if isSequenceMismatch(err) {
+			cc.recordSequenceMismatch(from, seq, err)
+		}
+		return nil, err
+	}
+	resp, err := cc.broadcastTx(ctx, txBytes)
+	switch {
+	case err == nil, errors.Is(err, lens.ErrTimeoutAfterWaitingForTxBroadcast):
+		cc.sequences.accepted(from, seq)
+	case isSequenceMismatch(err):
+		cc.recordSequenceMismatch(from, seq, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rlyResp := &provider.RelayerTxResponse{
+		Height:  resp.Height,
+		TxHash:  resp.TxHash,
+		Code:    resp.Code,
+		Data:    resp.Data,
+		GasUsed: resp.GasUsed,
+		Events:  parseEventsFromTxResponse(resp),
+		Fee:     txFee(resp),
+	}
+	if resp.Code != 0 {
+		cc.LogFailedTx(rlyResp, nil, msgs)
+		return rlyResp, fmt.Errorf("transaction failed with code: %d", resp.Code)
+	}
+	cc.LogSuccessTx(resp, msgs)
+	return rlyResp, nil
+}
+
+// buildFromKey returns the transaction of msgs signed with the key fromKey, along with the sequence it is signed with.
+// Unlike buildMessages, the factory of the transaction is bound to the account of fromKey rather than the key of the chain.
+func (cc *CosmosProvider) buildFromKey(ctx context.Context, fromKey string, msgs []provider.RelayerMessage, memo string) ([]byte, uint64, error) {
+	info, err := cc.Keybase.Key(fromKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	address, err := cc.EncodeBech32AccAddr(info.GetAddress())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	txf := cc.TxFactory()
+	cliCtx := client.Context{}.
+		WithClient(cc.RPCClient).
+		WithInterfaceRegistry(cc.Codec.InterfaceRegistry).
+		WithChainID(cc.PCfg.ChainID).
+		WithCodec(cc.Codec.Marshaler)
+	num, seq, err := txf.AccountRetriever().GetAccountNumberSequence(cliCtx, info.GetAddress())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query account of key %s: %w", fromKey, err)
+	}
+	seq = cc.sequences.next(address, seq)
+	txf = txf.WithAccountNumber(num).WithSequence(seq)
+
+	memo, err = provider.RenderMemo(memo, provider.NewMemoData(ctx, cc.PCfg.ChainID, msgs))
+	if err != nil {
+		return nil, seq, err
+	}
+	if memo != "" {
+		txf = txf.WithMemo(memo)
+	}
+	timeoutHeight, err := cc.txTimeoutHeight(ctx, 0)
+	if err != nil {
+		return nil, seq, err
+	}
+	if timeoutHeight != 0 {
+		txf = txf.WithTimeoutHeight(timeoutHeight)
+	}
+
+	// Simulate the transaction with the public key of fromKey, as CalculateGas only knows the key of the chain.
+	simTx, err := lens.BuildSimTx(info, txf, CosmosMsgs(msgs...)...)
+	if err != nil {
+		return nil, seq, err
+	}
+	res, err := cc.QueryABCI(ctx, abci.RequestQuery{Path: "/cosmos.tx.v1beta1.Service/Simulate", Data: simTx})
+	if err != nil {
+		return nil, seq, err
+	}
+	var simRes txtypes.SimulateResponse
+	if err := simRes.Unmarshal(res.Value); err != nil {
+		return nil, seq, err
+	}
+	txf = txf.WithGas(uint64(txf.GasAdjustment() * float64(simRes.GasInfo.GasUsed)))
+
+	txb, err := tx.BuildUnsignedTx(txf, CosmosMsgs(msgs...)...)
+	if err != nil {
+		return nil, seq, err
+	}
+	if err := cc.setExtensionOptions(txb, 1); err != nil {
+		return nil, seq, err
+	}
+
+	done := cc.SetSDKContext()
+	err = tx.Sign(txf, fromKey, txb, false)
+	done()
+	if err != nil {
+		return nil, seq, err
+	}
+
+	txBytes, err := cc.Codec.TxConfig.TxEncoder()(txb.GetTx())
+	if err != nil {
+		return nil, seq, err
+	}
+	return txBytes, seq, nil
+}
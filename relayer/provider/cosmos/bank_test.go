@@ -0,0 +1,53 @@
+package cosmos
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFromKey(t *testing.T) {
+	cc, relayer := newTxTimeoutProvider(t, 0)
+	funder := addTxNodeAccount(t, cc, "funder", 9, 5)
+
+	// The relayer keeps building its own transactions meanwhile.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			address, err := cc.Address()
+			require.NoError(t, err)
+			require.Equal(t, cc.MustEncodeAccAddr(relayer), address)
+		}
+	}()
+
+	msg := NewCosmosMessage(&banktypes.MsgSend{
+		FromAddress: cc.MustEncodeAccAddr(funder),
+		ToAddress:   cc.MustEncodeAccAddr(relayer),
+		Amount:      sdk.NewCoins(sdk.NewInt64Coin("uatom", 1000)),
+	})
+	txBytes, seq, err := cc.buildFromKey(context.Background(), "funder", []provider.RelayerMessage{msg}, "")
+	<-done
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), seq)
+	require.Equal(t, "relayer", cc.PCfg.Key)
+
+	// The transaction is signed by the funding key, with the sequence of its account.
+	decoded, err := cc.Codec.TxConfig.TxDecoder()(txBytes)
+	require.NoError(t, err)
+	sigTx := decoded.(authsigning.SigVerifiableTx)
+	require.Equal(t, []sdk.AccAddress{funder}, sigTx.GetSigners())
+	sigs, err := sigTx.GetSignaturesV2()
+	require.NoError(t, err)
+	require.Len(t, sigs, 1)
+	require.Equal(t, funder, sdk.AccAddress(sigs[0].PubKey.Address()))
+	require.Equal(t, uint64(5), sigs[0].Sequence)
+
+	_, _, err = cc.buildFromKey(context.Background(), "treasury", []provider.RelayerMessage{msg}, "")
+	require.Error(t, err)
+}
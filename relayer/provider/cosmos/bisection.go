@@ -0,0 +1,255 @@
+package cosmos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/cosmos/ibc-go/v3/modules/light-clients/01-furyint/types"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	tmmath "github.com/tendermint/tendermint/libs/math"
+	"github.com/tendermint/tendermint/light"
+	types2 "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxBisectionHeaders bounds the headers a client update is split into, all sent in the same transaction.
+	maxBisectionHeaders = 32
+
+	// maxCachedBisectionHeaders bounds the intermediate headers cached across bisections.
+	maxCachedBisectionHeaders = 256
+)
+
+// BisectUpdateClientHeader splits the client update of header, carrying its trusted height and validators,
+// into the updates through intermediate headers the client of the chain on dst needs to verify it.
+//
+// Clients skip heights as long as the trusted validators signed the new header with more than the trust level
+// of the client, 1/3 by default, of their voting power. On chains changing most of their validator set between
+// two updates, such as rollapps rotating sequencers, the update is bisected instead of failing: each header returned
+// is signed by enough of the validators trusted by the previous one, the last one being header. The headers are
+// to be sent in order, in the same transaction.
+func (cc *CosmosProvider) BisectUpdateClientHeader(ctx context.Context, header ibcexported.Header, dst provider.ChainProvider, dstClientID string) ([]ibcexported.Header, error) {
+	trustLevel, err := cc.clientTrustLevel(ctx, dst, dstClientID)
+	if err != nil {
+		return nil, err
+	}
+	return cc.bisectUpdateClientHeader(ctx, header, trustLevel)
+}
+
+func (cc *CosmosProvider) bisectUpdateClientHeader(ctx context.Context, header ibcexported.Header, trustLevel tmmath.Fraction) ([]ibcexported.Header, error) {
+	trustedHeight, err := GetTrustedHeight(header)
+	if err != nil {
+		return nil, err
+	}
+	target, err := cosmosIBCHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	trustedValidatorsProto, err := getTrustedValidators(header)
+	if err != nil {
+		return nil, err
+	}
+	trustedValidators, err := tmtypes.ValidatorSetFromProto(trustedValidatorsProto)
+	if err != nil {
+		return nil, fmt.Errorf("error converting trusted validators from proto object: %w", err)
+	}
+
+	// candidates are the headers fetched and not verified yet, from the target down to the closest to the trusted height.
+	// As in the skipping verification of Tendermint light clients, each time a candidate is verified the candidates
+	// above it are tried again, from the target down, before fetching any new header halfway.
+	candidates := []CosmosIBCHeader{target}
+	var headers []ibcexported.Header
+	trusted := *trustedHeight
+	fetched := 0
+	for depth := 0; ; {
+		candidate := candidates[depth]
+		err := trustedValidators.VerifyCommitLightTrusting(candidate.SignedHeader.ChainID, candidate.SignedHeader.Commit, trustLevel)
+		if err != nil {
+			if candidate.Height() <= trusted.RevisionHeight+1 {
+				return nil, fmt.Errorf("failed to verify header at height %d with the validators trusted at height %d: %w",
+					candidate.Height(), trusted.RevisionHeight, err)
+			}
+			if depth == len(candidates)-1 {
+				// The validator set changed too much since the trusted height, try halfway instead.
+				pivot := trusted.RevisionHeight + (candidate.Height()-trusted.RevisionHeight)/2
+				h, err := cc.bisectionHeader(ctx, pivot)
+				if err != nil {
+					return nil, fmt.Errorf("error getting intermediate header at height %d: %w", pivot, err)
+				}
+				fetched++
+				candidates = append(candidates, h)
+			}
+			depth++
+			continue
+		}
+
+		if depth == 0 && len(headers) == 0 {
+			// the client can be updated to header directly
+			return []ibcexported.Header{header}, nil
+		}
+		h, err := cc.MsgUpdateClientHeader(candidate, trusted, CosmosIBCHeader{ValidatorSet: trustedValidators})
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, h)
+		if depth == 0 {
+			cc.log.Debug("Bisected client update through intermediate headers",
+				zap.String("chain_id", cc.ChainId()),
+				zap.Uint64("trusted_height", trustedHeight.RevisionHeight),
+				zap.Uint64("height", target.Height()),
+				zap.Int("headers", len(headers)),
+				zap.Int("headers_fetched", fetched),
+			)
+			return headers, nil
+		}
+		if len(headers) == maxBisectionHeaders-1 {
+			return nil, fmt.Errorf("client update from height %d to %d needs more than %d intermediate headers",
+				trustedHeight.RevisionHeight, target.Height(), maxBisectionHeaders-1)
+		}
+
+		trustedValidators, err = cc.nextValidators(ctx, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("error getting trusted validators at height %d: %w", candidate.Height()+1, err)
+		}
+		trusted = clienttypes.NewHeight(trusted.RevisionNumber, candidate.Height())
+		candidates, depth = candidates[:depth], 0
+	}
+}
+
+// nextValidators returns the validators trusted for an update from h, the next validators it commits to,
+// i.e. the validators of the block after it, sparing the fetch of that block if the validators did not change.
+func (cc *CosmosProvider) nextValidators(ctx context.Context, h CosmosIBCHeader) (*tmtypes.ValidatorSet, error) {
+	if bytes.Equal(h.SignedHeader.NextValidatorsHash, h.ValidatorSet.Hash()) {
+		return h.ValidatorSet, nil
+	}
+	next, err := cc.bisectionHeader(ctx, h.Height()+1)
+	if err != nil {
+		return nil, err
+	}
+	return next.ValidatorSet, nil
+}
+
+// bisectionHeader returns the header at height h, cached for the next bisections:
+// while a client is not updated, e.g. as updates fail or the relayer is catching up after days offline,
+// each update from the same trusted height goes through most of the same intermediate heights.
+func (cc *CosmosProvider) bisectionHeader(ctx context.Context, h uint64) (CosmosIBCHeader, error) {
+	if header, ok := cc.bisectionHeaders.get(h); ok {
+		return header, nil
+	}
+	header, err := cc.IBCHeaderAtHeight(ctx, int64(h))
+	if err != nil {
+		return CosmosIBCHeader{}, err
+	}
+	ch := header.(CosmosIBCHeader)
+	cc.bisectionHeaders.add(ch)
+	return ch, nil
+}
+
+// headerCache holds the most recent headers fetched for bisections, up to maxCachedBisectionHeaders.
+// The zero value is ready to use.
+type headerCache struct {
+	mu      sync.Mutex
+	headers map[uint64]CosmosIBCHeader
+}
+
+func (c *headerCache) get(h uint64) (CosmosIBCHeader, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header, ok := c.headers[h]
+	return header, ok
+}
+
+// add caches header, evicting the lowest header cached if full, as trusted heights only move up.
+func (c *headerCache) add(header CosmosIBCHeader) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.headers == nil {
+		c.headers = make(map[uint64]CosmosIBCHeader)
+	}
+	if len(c.headers) >= maxCachedBisectionHeaders {
+		lowest := header.Height()
+		for h := range c.headers {
+			if h < lowest {
+				lowest = h
+			}
+		}
+		if lowest == header.Height() {
+			return
+		}
+		delete(c.headers, lowest)
+	}
+	c.headers[header.Height()] = header
+}
+
+// clientTrustLevel returns the trust level of the client of the chain on dst, cached once queried.
+func (cc *CosmosProvider) clientTrustLevel(ctx context.Context, dst provider.ChainProvider, dstClientID string) (tmmath.Fraction, error) {
+	key := dst.ChainId() + "/" + dstClientID
+	if v, ok := cc.trustLevels.Load(key); ok {
+		return v.(tmmath.Fraction), nil
+	}
+	cs, err := dst.QueryClientState(ctx, 0, dstClientID)
+	if err != nil {
+		return tmmath.Fraction{}, fmt.Errorf("error querying client state of %s on %s: %w", dstClientID, dst.ChainId(), err)
+	}
+	trustLevel := ClientStateTrustLevel(cs)
+	cc.trustLevels.Store(key, trustLevel)
+	return trustLevel, nil
+}
+
+// ClientStateTrustLevel returns the fraction of the trusted voting power which must sign the headers
+// updating a client skipping heights, the default trust level for client types without one.
+func ClientStateTrustLevel(cs ibcexported.ClientState) tmmath.Fraction {
+	switch cs := cs.(type) {
+	case *tmclient.ClientState:
+		return cs.TrustLevel.ToTendermint()
+	case *types.ClientState:
+		return tmmath.Fraction{Numerator: cs.TrustLevel.Numerator, Denominator: cs.TrustLevel.Denominator}
+	}
+	return light.DefaultTrustLevel
+}
+
+// cosmosIBCHeader returns the signed header and validators of header.
+func cosmosIBCHeader(header ibcexported.Header) (CosmosIBCHeader, error) {
+	signedHeaderProto, err := getSignedHeader(header)
+	if err != nil {
+		return CosmosIBCHeader{}, err
+	}
+	signedHeader, err := tmtypes.SignedHeaderFromProto(signedHeaderProto)
+	if err != nil {
+		return CosmosIBCHeader{}, fmt.Errorf("error converting signed header from proto object: %w", err)
+	}
+	validatorSetProto, err := getValidatorSet(header)
+	if err != nil {
+		return CosmosIBCHeader{}, err
+	}
+	validatorSet, err := tmtypes.ValidatorSetFromProto(validatorSetProto)
+	if err != nil {
+		return CosmosIBCHeader{}, fmt.Errorf("error converting validator set from proto object: %w", err)
+	}
+	return CosmosIBCHeader{SignedHeader: signedHeader, ValidatorSet: validatorSet}, nil
+}
+
+// getTrustedValidators retrieves the trusted validator-set from the specified header according to its client-type
+func getTrustedValidators(header ibcexported.Header) (*types2.ValidatorSet, error) {
+	if header.ClientType() == ibcexported.Furyint {
+		dmHeader, ok := header.(*types.Header)
+		if !ok {
+			return nil, fmt.Errorf("got data of type %T but wanted dmclient.Header", header)
+		}
+		return dmHeader.TrustedValidators, nil
+	}
+	if header.ClientType() == ibcexported.Tendermint {
+		tmHeader, ok := header.(*tmclient.Header)
+		if !ok {
+			return nil, fmt.Errorf("got data of type %T but wanted tmclient.Header", header)
+		}
+		return tmHeader.TrustedValidators, nil
+	}
+	return nil, fmt.Errorf("unsupported header client type %s", header.ClientType())
+}
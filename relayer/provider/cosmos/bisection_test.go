@@ -0,0 +1,187 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/light"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmversion "github.com/tendermint/tendermint/proto/tendermint/version"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+	"go.uber.org/zap"
+)
+
+const bisectionTestChainID = "rollapp_1-1"
+
+// fakeLightProvider serves the light blocks of a chain whose validators are rotated at the configured heights.
+type fakeLightProvider struct {
+	blocks  map[int64]*tmtypes.LightBlock
+	fetches int
+}
+
+func (p *fakeLightProvider) ChainID() string { return bisectionTestChainID }
+
+func (p *fakeLightProvider) LightBlock(_ context.Context, height int64) (*tmtypes.LightBlock, error) {
+	p.fetches++
+	b, ok := p.blocks[height]
+	if !ok {
+		return nil, fmt.Errorf("no light block at height %d", height)
+	}
+	return b, nil
+}
+
+func (p *fakeLightProvider) ReportEvidence(context.Context, tmtypes.Evidence) error { return nil }
+
+// newFakeLightProvider returns the light blocks up to height, a new set of validators signing from each rotation height.
+func newFakeLightProvider(t *testing.T, height int64, rotations ...int64) *fakeLightProvider {
+	vals := make([]*tmtypes.ValidatorSet, height+2)
+	privVals := make([][]tmtypes.PrivValidator, height+2)
+	vals[0], privVals[0] = tmtypes.RandValidatorSet(4, 10)
+	for h := int64(1); h <= height+1; h++ {
+		vals[h], privVals[h] = vals[h-1], privVals[h-1]
+		for _, r := range rotations {
+			if h == r {
+				vals[h], privVals[h] = tmtypes.RandValidatorSet(4, 10)
+			}
+		}
+	}
+
+	p := &fakeLightProvider{blocks: make(map[int64]*tmtypes.LightBlock)}
+	now := time.Now()
+	for h := int64(1); h <= height; h++ {
+		header := tmtypes.Header{
+			Version:            tmversion.Consensus{Block: version.BlockProtocol},
+			ChainID:            bisectionTestChainID,
+			Height:             h,
+			Time:               now.Add(time.Duration(h) * time.Second),
+			ValidatorsHash:     vals[h].Hash(),
+			NextValidatorsHash: vals[h+1].Hash(),
+			ProposerAddress:    vals[h].Proposer.Address,
+		}
+		blockID := tmtypes.BlockID{Hash: header.Hash(), PartSetHeader: tmtypes.PartSetHeader{Total: 1, Hash: header.Hash()}}
+		voteSet := tmtypes.NewVoteSet(bisectionTestChainID, h, 0, tmproto.PrecommitType, vals[h])
+		commit, err := tmtypes.MakeCommit(blockID, h, 0, voteSet, privVals[h], header.Time)
+		require.NoError(t, err)
+		p.blocks[h] = &tmtypes.LightBlock{
+			SignedHeader: &tmtypes.SignedHeader{Header: &header, Commit: commit},
+			ValidatorSet: vals[h],
+		}
+	}
+	return p
+}
+
+// updateHeader returns the header updating a client trusting trustedHeight to height.
+func (p *fakeLightProvider) updateHeader(t *testing.T, cc *CosmosProvider, trustedHeight, height int64) ibcexported.Header {
+	header, err := cc.MsgUpdateClientHeader(
+		CosmosIBCHeader{SignedHeader: p.blocks[height].SignedHeader, ValidatorSet: p.blocks[height].ValidatorSet},
+		clienttypes.NewHeight(1, uint64(trustedHeight)),
+		CosmosIBCHeader{ValidatorSet: p.blocks[trustedHeight+1].ValidatorSet},
+	)
+	require.NoError(t, err)
+	return header
+}
+
+func newBisectionTestProvider(lp *fakeLightProvider) *CosmosProvider {
+	return &CosmosProvider{
+		log:         zap.NewNop(),
+		PCfg:        CosmosProviderConfig{ChainID: bisectionTestChainID, ClientType: ibcexported.Tendermint},
+		ChainClient: lens.ChainClient{LightProvider: lp},
+	}
+}
+
+func TestBisectUpdateClientHeaderDirect(t *testing.T) {
+	lp := newFakeLightProvider(t, 16)
+	cc := newBisectionTestProvider(lp)
+	header := lp.updateHeader(t, cc, 1, 16)
+
+	headers, err := cc.bisectUpdateClientHeader(context.Background(), header, light.DefaultTrustLevel)
+	require.NoError(t, err)
+	require.Equal(t, []ibcexported.Header{header}, headers)
+}
+
+func TestBisectUpdateClientHeaderThroughIntermediateHeaders(t *testing.T) {
+	// The validators trusted at height 1 signed up to height 8, those signing height 16 from height 9 on.
+	lp := newFakeLightProvider(t, 16, 9)
+	cc := newBisectionTestProvider(lp)
+	header := lp.updateHeader(t, cc, 1, 16)
+
+	headers, err := cc.bisectUpdateClientHeader(context.Background(), header, light.DefaultTrustLevel)
+	require.NoError(t, err)
+	require.Len(t, headers, 2)
+
+	first, last := headers[0].(*tmclient.Header), headers[1].(*tmclient.Header)
+	require.Equal(t, int64(8), first.SignedHeader.Header.Height)
+	require.Equal(t, clienttypes.NewHeight(1, 1), first.TrustedHeight)
+	require.Equal(t, int64(16), last.SignedHeader.Header.Height)
+	require.Equal(t, clienttypes.NewHeight(1, 8), last.TrustedHeight)
+
+	// Each header is signed by enough of the validators trusted by the previous one.
+	for _, h := range headers {
+		trusted, err := tmtypes.ValidatorSetFromProto(h.(*tmclient.Header).TrustedValidators)
+		require.NoError(t, err)
+		signed, err := tmtypes.SignedHeaderFromProto(h.(*tmclient.Header).SignedHeader)
+		require.NoError(t, err)
+		require.NoError(t, trusted.VerifyCommitLightTrusting(bisectionTestChainID, signed.Commit, light.DefaultTrustLevel))
+	}
+}
+
+func TestBisectUpdateClientHeaderCachesHeaders(t *testing.T) {
+	// The validators rotate three times.
+	lp := newFakeLightProvider(t, 64, 17, 33, 49)
+	cc := newBisectionTestProvider(lp)
+	header := lp.updateHeader(t, cc, 1, 64)
+
+	headers, err := cc.bisectUpdateClientHeader(context.Background(), header, light.DefaultTrustLevel)
+	require.NoError(t, err)
+	heights := make([]int64, len(headers))
+	for i, h := range headers {
+		heights[i] = h.(*tmclient.Header).SignedHeader.Header.Height
+	}
+	require.Equal(t, []int64{16, 32, 48, 64}, heights)
+	fetched := lp.fetches
+	// Halfway at 32 then 16, 32 being verified from 16 without fetching it again, then 48,
+	// and the next validators of 16, 32 and 48 at the rotations.
+	require.Equal(t, 6, fetched)
+
+	// Updating again from the same trusted height, e.g. after the transaction failed, fetches nothing.
+	_, err = cc.bisectUpdateClientHeader(context.Background(), header, light.DefaultTrustLevel)
+	require.NoError(t, err)
+	require.Equal(t, fetched, lp.fetches)
+}
+
+func TestHeaderCacheEvictsLowest(t *testing.T) {
+	var c headerCache
+	for h := int64(1); h <= maxCachedBisectionHeaders+1; h++ {
+		c.add(CosmosIBCHeader{SignedHeader: &tmtypes.SignedHeader{Header: &tmtypes.Header{Height: h}}})
+	}
+	_, ok := c.get(1)
+	require.False(t, ok)
+	_, ok = c.get(maxCachedBisectionHeaders + 1)
+	require.True(t, ok)
+	require.Len(t, c.headers, maxCachedBisectionHeaders)
+}
+
+func TestBisectUpdateClientHeaderMissingIntermediateHeader(t *testing.T) {
+	lp := newFakeLightProvider(t, 16, 9)
+	cc := newBisectionTestProvider(lp)
+	header := lp.updateHeader(t, cc, 1, 16)
+	delete(lp.blocks, 8)
+
+	_, err := cc.bisectUpdateClientHeader(context.Background(), header, light.DefaultTrustLevel)
+	require.ErrorContains(t, err, "intermediate header at height 8")
+}
+
+func TestClientStateTrustLevel(t *testing.T) {
+	require.Equal(t, light.DefaultTrustLevel, ClientStateTrustLevel(nil))
+	cs := &tmclient.ClientState{TrustLevel: tmclient.Fraction{Numerator: 2, Denominator: 3}}
+	require.Equal(t, uint64(2), ClientStateTrustLevel(cs).Numerator)
+	require.Equal(t, uint64(3), ClientStateTrustLevel(cs).Denominator)
+}
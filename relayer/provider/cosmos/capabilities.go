@@ -0,0 +1,123 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// feeMarketParamsQueries are the gRPC queries of the parameters of the fee market modules setting a base fee.
+var feeMarketParamsQueries = []string{
+	"/ethermint.feemarket.v1.Query/Params",
+	"/feemarket.feemarket.v1.Query/Params",
+}
+
+// DetectCapabilities detects the capabilities of the endpoint of the chain: whether its node indexes transactions
+// and serves its events over a websocket, the blocks it keeps, whether the chain has a fee market,
+// and the size of the largest transaction it accepts.
+func (cc *CosmosProvider) DetectCapabilities(ctx context.Context) provider.Capabilities {
+	caps := provider.Capabilities{
+		ChainID:         cc.PCfg.ChainID,
+		WebsocketEvents: true,
+		TxIndexing:      true,
+		DetectedAt:      time.Now(),
+	}
+	fail := func(capability string, err error) {
+		if caps.Errors == nil {
+			caps.Errors = make(map[string]string)
+		}
+		caps.Errors[capability] = err.Error()
+	}
+
+	if status, err := cc.RPCClient.Status(ctx); err != nil {
+		fail("tx_indexing", err)
+		fail("history", err)
+	} else {
+		caps.TxIndexing = status.NodeInfo.Other.TxIndex == "on"
+		caps.EarliestHeight = status.SyncInfo.EarliestBlockHeight
+		caps.LatestHeight = status.SyncInfo.LatestBlockHeight
+	}
+
+	if err := probeWebsocket(ctx, cc.PCfg.RPCAddr); err != nil {
+		caps.WebsocketEvents = false
+		fail("websocket_events", err)
+	}
+
+	feeMarket, err := cc.hasFeeMarket(ctx)
+	if err != nil {
+		fail("fee_market", err)
+	}
+	caps.FeeMarket = feeMarket
+
+	var blockMaxBytes int64
+	if params, err := cc.RPCClient.ConsensusParams(ctx, nil); err != nil {
+		fail("max_tx_bytes", err)
+	} else {
+		blockMaxBytes = params.ConsensusParams.Block.MaxBytes
+	}
+	caps.MaxTxBytes = maxTxBytes(blockMaxBytes, cc.PCfg.MaxTxBytes)
+	if !feeMarket && err == nil {
+		for _, o := range cc.PCfg.ExtensionOptions {
+			if o.Type == ExtensionOptionDynamicFee {
+				cc.log.Warn(
+					"The dynamic fee extension option is configured but the chain has no fee market, transactions may be rejected",
+					zap.String("chain_id", cc.PCfg.ChainID),
+				)
+				break
+			}
+		}
+	}
+	return caps
+}
+
+// maxTxBytes returns the size of the largest transaction fitting in the blocks of blockMaxBytes and in the mempool
+// limit mempoolMaxBytes, either being unknown if not positive, or 0 if both are unknown.
+func maxTxBytes(blockMaxBytes int64, mempoolMaxBytes uint64) uint64 {
+	if blockMaxBytes <= 0 || (mempoolMaxBytes != 0 && mempoolMaxBytes < uint64(blockMaxBytes)) {
+		return mempoolMaxBytes
+	}
+	return uint64(blockMaxBytes)
+}
+
+// hasFeeMarket reports whether the chain has one of the fee market modules of feeMarketParamsQueries.
+func (cc *CosmosProvider) hasFeeMarket(ctx context.Context) (bool, error) {
+	var lastErr error
+	for _, path := range feeMarketParamsQueries {
+		res, err := cc.RPCClient.ABCIQuery(ctx, path, nil)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if res.Response.Code == 0 {
+			return true, nil
+		}
+		lastErr = nil
+	}
+	return false, lastErr
+}
+
+// probeWebsocket checks that the websocket of the Tendermint RPC endpoint at rpcAddr accepts connections.
+func probeWebsocket(ctx context.Context, rpcAddr string) error {
+	u, err := url.Parse(rpcAddr)
+	if err != nil {
+		return fmt.Errorf("invalid rpc address %s: %w", rpcAddr, err)
+	}
+	switch u.Scheme {
+	case "http", "tcp", "":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/websocket"
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to websocket %s: %w", u.Redacted(), err)
+	}
+	return conn.Close()
+}
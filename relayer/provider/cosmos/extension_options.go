@@ -0,0 +1,68 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
+)
+
+// ExtensionOptionDynamicFee is the type of the dynamic fee extension option of Ethermint chains,
+// whose value is the max priority price, the tip per gas paid on top of the base fee.
+const ExtensionOptionDynamicFee = "ethermint_dynamic_fee"
+
+const dynamicFeeTypeURL = "/ethermint.types.v1.ExtensionOptionDynamicFeeTx"
+
+// TxExtensionOption is an extension option added to every transaction sent to the chain,
+// as required by some EVM rollapps.
+//
+// Type is either ExtensionOptionDynamicFee, with an integer Value, or the protobuf type URL of the option,
+// such as "/ethermint.types.v1.ExtensionOptionsWeb3Tx", with its protobuf encoding in base64 as Value.
+type TxExtensionOption struct {
+	Type  string `json:"type" yaml:"type"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// toAny returns the extension option packed in an Any, as set on transactions,
+// the max priority price of the dynamic fee option being multiplied by boost.
+func (o TxExtensionOption) toAny(boost float64) (*codectypes.Any, error) {
+	switch {
+	case o.Type == ExtensionOptionDynamicFee:
+		price, ok := sdk.NewIntFromString(o.Value)
+		if !ok || price.IsNegative() {
+			return nil, fmt.Errorf("invalid %s extension option value %q, expected a non-negative integer", o.Type, o.Value)
+		}
+		if boost > 1 {
+			price = sdk.NewDecFromInt(price).Mul(sdk.MustNewDecFromStr(strconv.FormatFloat(boost, 'f', 6, 64))).Ceil().TruncateInt()
+		}
+		// ExtensionOptionDynamicFeeTx has a single field, max_priority_price = 1, an sdk.Int encoded as a string.
+		s := price.String()
+		value := append([]byte{0x0a}, proto.EncodeVarint(uint64(len(s)))...)
+		return &codectypes.Any{TypeUrl: dynamicFeeTypeURL, Value: append(value, s...)}, nil
+	case strings.HasPrefix(o.Type, "/"):
+		value, err := base64.StdEncoding.DecodeString(o.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s extension option value, expected base64 encoded protobuf: %w", o.Type, err)
+		}
+		return &codectypes.Any{TypeUrl: o.Type, Value: value}, nil
+	default:
+		return nil, fmt.Errorf("unknown extension option type %q, expected %q or a protobuf type URL", o.Type, ExtensionOptionDynamicFee)
+	}
+}
+
+// txExtensionOptions returns the configured extension options packed in Anys, see TxExtensionOption.toAny.
+func (pc CosmosProviderConfig) txExtensionOptions(boost float64) ([]*codectypes.Any, error) {
+	var anys []*codectypes.Any
+	for _, o := range pc.ExtensionOptions {
+		a, err := o.toAny(boost)
+		if err != nil {
+			return nil, err
+		}
+		anys = append(anys, a)
+	}
+	return anys, nil
+}
@@ -0,0 +1,47 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/ante"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxExtensionOptions(t *testing.T) {
+	pc := CosmosProviderConfig{ExtensionOptions: []TxExtensionOption{
+		{Type: ExtensionOptionDynamicFee, Value: "1000000"},
+		{Type: "/ethermint.types.v1.ExtensionOptionsWeb3Tx", Value: base64.StdEncoding.EncodeToString([]byte{0x08, 0x01})},
+	}}
+	anys, err := pc.txExtensionOptions(1)
+	require.NoError(t, err)
+	require.Equal(t, []*codectypes.Any{
+		{TypeUrl: "/ethermint.types.v1.ExtensionOptionDynamicFeeTx", Value: append([]byte{0x0a, 0x07}, "1000000"...)},
+		{TypeUrl: "/ethermint.types.v1.ExtensionOptionsWeb3Tx", Value: []byte{0x08, 0x01}},
+	}, anys)
+
+	// The max priority price of priority paths is multiplied by their fee boost.
+	anys, err = pc.txExtensionOptions(2.5)
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0x0a, 0x07}, "2500000"...), anys[0].Value)
+
+	cc := &CosmosProvider{PCfg: pc}
+	txb := authtx.NewTxConfig(codec.NewProtoCodec(codectypes.NewInterfaceRegistry()), authtx.DefaultSignModes).NewTxBuilder()
+	require.NoError(t, cc.setExtensionOptions(txb, 1))
+	extTx, ok := txb.GetTx().(ante.HasExtensionOptionsTx)
+	require.True(t, ok)
+	require.Len(t, extTx.GetExtensionOptions(), 2)
+
+	for _, invalid := range []TxExtensionOption{
+		{Type: ExtensionOptionDynamicFee, Value: "-1"},
+		{Type: ExtensionOptionDynamicFee, Value: "1.5"},
+		{Type: "/ethermint.types.v1.ExtensionOptionsWeb3Tx", Value: "not base64"},
+		{Type: "dynamic_fee", Value: "1"},
+	} {
+		pc := CosmosProviderConfig{Timeout: "10s", ExtensionOptions: []TxExtensionOption{invalid}}
+		require.Error(t, pc.Validate(), "%+v", invalid)
+	}
+}
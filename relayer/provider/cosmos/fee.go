@@ -0,0 +1,177 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The ibc-go version the relayer is built against does not ship the ICS-29 fee middleware,
+// so the few fee messages the relayer needs are encoded by hand, following ibc/applications/fee/v1.
+// The relayer only registers its payees to collect fees: MsgPayPacketFee and MsgPayPacketFeeAsync are not supported.
+
+const (
+	msgRegisterCounterpartyPayeeName = "ibc.applications.fee.v1.MsgRegisterCounterpartyPayee"
+	queryCounterpartyPayeePath       = "/ibc.applications.fee.v1.Query/CounterpartyPayee"
+)
+
+var _ sdk.Msg = &MsgRegisterCounterpartyPayee{}
+
+// MsgRegisterCounterpartyPayee registers, on the chain it is sent to, the address on the counterparty chain
+// that is paid the forward relay fees of the packets the relayer delivers on the given channel.
+type MsgRegisterCounterpartyPayee struct {
+	PortId            string `protobuf:"bytes,1,opt,name=port_id,json=portId,proto3" json:"port_id,omitempty"`
+	ChannelId         string `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Relayer           string `protobuf:"bytes,3,opt,name=relayer,proto3" json:"relayer,omitempty"`
+	CounterpartyPayee string `protobuf:"bytes,4,opt,name=counterparty_payee,json=counterpartyPayee,proto3" json:"counterparty_payee,omitempty"`
+}
+
+func (m *MsgRegisterCounterpartyPayee) Reset()         { *m = MsgRegisterCounterpartyPayee{} }
+func (m *MsgRegisterCounterpartyPayee) String() string { return fmt.Sprintf("%+v", *m) }
+func (*MsgRegisterCounterpartyPayee) ProtoMessage()    {}
+
+// XXX_MessageName gives the message its type URL without registering it with the proto registry.
+func (*MsgRegisterCounterpartyPayee) XXX_MessageName() string {
+	return msgRegisterCounterpartyPayeeName
+}
+
+// Marshal encodes the message in its protobuf wire format.
+func (m *MsgRegisterCounterpartyPayee) Marshal() ([]byte, error) {
+	var bz []byte
+	bz = appendStringField(bz, 1, m.PortId)
+	bz = appendStringField(bz, 2, m.ChannelId)
+	bz = appendStringField(bz, 3, m.Relayer)
+	bz = appendStringField(bz, 4, m.CounterpartyPayee)
+	return bz, nil
+}
+
+func (m *MsgRegisterCounterpartyPayee) MarshalTo(dAtA []byte) (int, error) {
+	bz, _ := m.Marshal()
+	return copy(dAtA, bz), nil
+}
+
+func (m *MsgRegisterCounterpartyPayee) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	bz, _ := m.Marshal()
+	return copy(dAtA[len(dAtA)-len(bz):], bz), nil
+}
+
+func (m *MsgRegisterCounterpartyPayee) Size() int {
+	bz, _ := m.Marshal()
+	return len(bz)
+}
+
+// Unmarshal decodes the message from its protobuf wire format.
+func (m *MsgRegisterCounterpartyPayee) Unmarshal(bz []byte) error {
+	m.Reset()
+	return consumeStringFields(bz, map[protowire.Number]*string{
+		1: &m.PortId,
+		2: &m.ChannelId,
+		3: &m.Relayer,
+		4: &m.CounterpartyPayee,
+	})
+}
+
+func (m *MsgRegisterCounterpartyPayee) ValidateBasic() error {
+	switch {
+	case m.PortId == "":
+		return errors.New("port id cannot be empty")
+	case m.ChannelId == "":
+		return errors.New("channel id cannot be empty")
+	case m.Relayer == "":
+		return errors.New("relayer address cannot be empty")
+	case m.CounterpartyPayee == "":
+		return errors.New("counterparty payee cannot be empty")
+	}
+	return nil
+}
+
+// GetSigners returns the relayer address. It does not depend on the bech32 prefix configured in the SDK,
+// so that messages can be built for any chain.
+func (m *MsgRegisterCounterpartyPayee) GetSigners() []sdk.AccAddress {
+	_, bz, err := bech32.DecodeAndConvert(m.Relayer)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{bz}
+}
+
+// MsgRegisterCounterpartyPayee assembles a message registering counterpartyPayee as the payee,
+// on the counterparty chain, of the fees earned by relayerAddr delivering packets on the given channel.
+func (cc *CosmosProvider) MsgRegisterCounterpartyPayee(portID, channelID, relayerAddr, counterpartyPayee string) (provider.RelayerMessage, error) {
+	msg := &MsgRegisterCounterpartyPayee{
+		PortId:            portID,
+		ChannelId:         channelID,
+		Relayer:           relayerAddr,
+		CounterpartyPayee: counterpartyPayee,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return NewCosmosMessage(msg), nil
+}
+
+// QueryCounterpartyPayee returns the counterparty payee registered for relayerAddr on the given channel,
+// or an empty string if there is none.
+func (cc *CosmosProvider) QueryCounterpartyPayee(ctx context.Context, channelID, relayerAddr string) (string, error) {
+	var req []byte
+	req = appendStringField(req, 1, channelID)
+	req = appendStringField(req, 2, relayerAddr)
+
+	res, err := cc.QueryABCI(ctx, abci.RequestQuery{Path: queryCounterpartyPayeePath, Data: req})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to query counterparty payee on channel %s: %w", channelID, err)
+	}
+
+	var payee string
+	if err := consumeStringFields(res.Value, map[protowire.Number]*string{1: &payee}); err != nil {
+		return "", fmt.Errorf("failed to decode counterparty payee on channel %s: %w", channelID, err)
+	}
+	return payee, nil
+}
+
+func appendStringField(bz []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return bz
+	}
+	bz = protowire.AppendTag(bz, num, protowire.BytesType)
+	return protowire.AppendString(bz, v)
+}
+
+// consumeStringFields decodes the string fields of a message into fields, skipping unknown fields.
+func consumeStringFields(bz []byte, fields map[protowire.Number]*string) error {
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+
+		if f, ok := fields[num]; ok && typ == protowire.BytesType {
+			v, n := protowire.ConsumeString(bz)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			*f = v
+			bz = bz[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+	}
+	return nil
+}
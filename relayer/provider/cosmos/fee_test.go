@@ -0,0 +1,53 @@
+package cosmos
+
+import (
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestMsgRegisterCounterpartyPayee(t *testing.T) {
+	relayer, err := bech32.ConvertAndEncode("osmo", make([]byte, 20))
+	require.NoError(t, err)
+
+	msg := &MsgRegisterCounterpartyPayee{
+		PortId:            "transfer",
+		ChannelId:         "channel-0",
+		Relayer:           relayer,
+		CounterpartyPayee: "cosmos1payee",
+	}
+	require.NoError(t, msg.ValidateBasic())
+	require.Len(t, msg.GetSigners(), 1)
+
+	bz, err := msg.Marshal()
+	require.NoError(t, err)
+	var decoded MsgRegisterCounterpartyPayee
+	require.NoError(t, decoded.Unmarshal(bz))
+	require.Equal(t, *msg, decoded)
+
+	any, err := codectypes.NewAnyWithValue(msg)
+	require.NoError(t, err)
+	require.Equal(t, "/ibc.applications.fee.v1.MsgRegisterCounterpartyPayee", any.TypeUrl)
+	require.Equal(t, bz, any.Value)
+
+	// Messages are marshaled to JSON before signing.
+	cdc := lens.MakeCodec(lens.ModuleBasics)
+	_, err = cdc.Marshaler.MarshalJSON(msg)
+	require.NoError(t, err)
+
+	require.Error(t, (&MsgRegisterCounterpartyPayee{PortId: "transfer", ChannelId: "channel-0", Relayer: relayer}).ValidateBasic())
+}
+
+func TestConsumeStringFieldsSkipsUnknownFields(t *testing.T) {
+	var bz []byte
+	bz = appendStringField(bz, 2, "ignored")
+	bz = appendStringField(bz, 1, "cosmos1payee")
+
+	var payee string
+	require.NoError(t, consumeStringFields(bz, map[protowire.Number]*string{1: &payee}))
+	require.Equal(t, "cosmos1payee", payee)
+}
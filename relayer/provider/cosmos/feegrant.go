@@ -0,0 +1,124 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+)
+
+// relayMsgTypeURLs are the messages sent to relay packets, which a fee grant restricted to some messages must allow.
+var relayMsgTypeURLs = []string{
+	sdk.MsgTypeURL(&clienttypes.MsgUpdateClient{}),
+	sdk.MsgTypeURL(&chantypes.MsgRecvPacket{}),
+	sdk.MsgTypeURL(&chantypes.MsgAcknowledgement{}),
+	sdk.MsgTypeURL(&chantypes.MsgTimeout{}),
+	sdk.MsgTypeURL(&chantypes.MsgTimeoutOnClose{}),
+}
+
+// FeeGrant is the allowance the fee granter of the chain grants the relayer to pay the fees of its transactions.
+type FeeGrant struct {
+	Granter string `json:"granter"`
+	Grantee string `json:"grantee"`
+
+	// Type is the type URL of the allowance.
+	Type string `json:"type"`
+
+	// SpendLimit is the amount left to spend, nil if unlimited.
+	SpendLimit sdk.Coins  `json:"spend_limit,omitempty"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+
+	// PeriodCanSpend is the amount left to spend in the current period of a periodic allowance, until PeriodReset.
+	PeriodCanSpend sdk.Coins  `json:"period_can_spend,omitempty"`
+	PeriodReset    *time.Time `json:"period_reset,omitempty"`
+
+	// AllowedMessages are the messages the allowance is restricted to, if any.
+	AllowedMessages []string `json:"allowed_messages,omitempty"`
+}
+
+// Check returns an error if the grant does not let the relayer spend minAllowance on the fees of its transactions
+// at time now: it expired, the messages relaying packets are not allowed, or less than minAllowance is left to spend.
+func (g FeeGrant) Check(now time.Time, minAllowance sdk.Coins) error {
+	if g.Expiration != nil && !now.Before(*g.Expiration) {
+		return fmt.Errorf("fee grant of %s to %s expired at %s", g.Granter, g.Grantee, g.Expiration.Format(time.RFC3339))
+	}
+	if len(g.AllowedMessages) > 0 {
+		allowed := make(map[string]bool, len(g.AllowedMessages))
+		for _, m := range g.AllowedMessages {
+			allowed[m] = true
+		}
+		for _, m := range relayMsgTypeURLs {
+			if !allowed[m] {
+				return fmt.Errorf("fee grant of %s to %s does not allow %s", g.Granter, g.Grantee, m)
+			}
+		}
+	}
+	if g.SpendLimit != nil && !g.SpendLimit.IsAllGTE(minAllowance) {
+		return fmt.Errorf("fee grant of %s to %s has %s left to spend, less than %s", g.Granter, g.Grantee, g.SpendLimit, minAllowance)
+	}
+	periodReset := g.PeriodReset != nil && !now.Before(*g.PeriodReset)
+	if g.PeriodCanSpend != nil && !periodReset && !g.PeriodCanSpend.IsAllGTE(minAllowance) {
+		return fmt.Errorf("fee grant of %s to %s has %s left to spend until %s, less than %s",
+			g.Granter, g.Grantee, g.PeriodCanSpend, g.PeriodReset.Format(time.RFC3339), minAllowance)
+	}
+	return nil
+}
+
+// QueryFeeGrant queries the allowance granted by the configured fee granter to the account of the relayer.
+func (cc *CosmosProvider) QueryFeeGrant(ctx context.Context) (*FeeGrant, error) {
+	if cc.PCfg.FeeGranter == "" {
+		return nil, fmt.Errorf("no fee-granter is configured for %s", cc.PCfg.ChainID)
+	}
+	grantee, err := cc.Address()
+	if err != nil {
+		return nil, err
+	}
+	res, err := feegrant.NewQueryClient(cc).Allowance(ctx, &feegrant.QueryAllowanceRequest{
+		Granter: cc.PCfg.FeeGranter,
+		Grantee: grantee,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fee grant of %s to %s: %w", cc.PCfg.FeeGranter, grantee, err)
+	}
+	allowance, err := res.Allowance.GetGrant()
+	if err != nil {
+		return nil, err
+	}
+	g := &FeeGrant{Granter: cc.PCfg.FeeGranter, Grantee: grantee, Type: res.Allowance.Allowance.TypeUrl}
+	if err := g.setAllowance(allowance); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// setAllowance describes allowance in g, unwrapping the allowances restricting the messages of another.
+func (g *FeeGrant) setAllowance(allowance feegrant.FeeAllowanceI) error {
+	switch a := allowance.(type) {
+	case *feegrant.BasicAllowance:
+		g.SpendLimit = a.SpendLimit
+		g.Expiration = a.Expiration
+	case *feegrant.PeriodicAllowance:
+		g.SpendLimit = a.Basic.SpendLimit
+		g.Expiration = a.Basic.Expiration
+		g.PeriodCanSpend = a.PeriodCanSpend
+		if g.PeriodCanSpend == nil {
+			g.PeriodCanSpend = sdk.Coins{}
+		}
+		reset := a.PeriodReset
+		g.PeriodReset = &reset
+	case *feegrant.AllowedMsgAllowance:
+		g.AllowedMessages = a.AllowedMessages
+		inner, err := a.GetAllowance()
+		if err != nil {
+			return err
+		}
+		return g.setAllowance(inner)
+	default:
+		return fmt.Errorf("unsupported fee allowance %T", allowance)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cosmos
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeGrantCheck(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiration := now.Add(24 * time.Hour)
+	minAllowance := sdk.NewCoins(sdk.NewInt64Coin("urax", 1000))
+
+	basic := &feegrant.BasicAllowance{SpendLimit: sdk.NewCoins(sdk.NewInt64Coin("urax", 5000)), Expiration: &expiration}
+	var g FeeGrant
+	require.NoError(t, g.setAllowance(basic))
+	require.NoError(t, g.Check(now, minAllowance))
+	require.ErrorContains(t, g.Check(now, sdk.NewCoins(sdk.NewInt64Coin("urax", 6000))), "left to spend")
+	require.ErrorContains(t, g.Check(expiration, minAllowance), "expired")
+
+	// unlimited allowances restricted to messages must allow relaying packets
+	restricted, err := feegrant.NewAllowedMsgAllowance(&feegrant.BasicAllowance{}, relayMsgTypeURLs[:2])
+	require.NoError(t, err)
+	g = FeeGrant{}
+	require.NoError(t, g.setAllowance(restricted))
+	require.ErrorContains(t, g.Check(now, minAllowance), "does not allow /ibc.core.channel.v1.MsgAcknowledgement")
+	restricted, err = feegrant.NewAllowedMsgAllowance(&feegrant.BasicAllowance{}, relayMsgTypeURLs)
+	require.NoError(t, err)
+	g = FeeGrant{}
+	require.NoError(t, g.setAllowance(restricted))
+	require.NoError(t, g.Check(now, minAllowance))
+
+	// periodic allowances must have enough left in the current period, unless it resets
+	periodic := &feegrant.PeriodicAllowance{
+		Period:           time.Hour,
+		PeriodSpendLimit: sdk.NewCoins(sdk.NewInt64Coin("urax", 2000)),
+		PeriodReset:      now.Add(time.Minute),
+	}
+	g = FeeGrant{}
+	require.NoError(t, g.setAllowance(periodic))
+	require.ErrorContains(t, g.Check(now, minAllowance), "left to spend until")
+	require.NoError(t, g.Check(now.Add(time.Minute), minAllowance))
+}
@@ -0,0 +1,35 @@
+package cosmos
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+// MsgClientRecoveryProposal builds a message submitting a governance proposal to recover the expired or frozen client
+// subjectClientID with the state of the active client substituteClientID, proposed by the key of the chain with deposit.
+//
+// The proposal is a ClientUpdateProposal of the gov v1beta1 module, MsgRecoverClient of ibc-go v8
+// not being supported by the ibc-go version of the relayer.
+func (cc *CosmosProvider) MsgClientRecoveryProposal(
+	title, description, subjectClientID, substituteClientID string,
+	deposit sdk.Coins,
+) (provider.RelayerMessage, error) {
+	acc, err := cc.Address()
+	if err != nil {
+		return nil, err
+	}
+
+	// The proposer is set from the address of the key rather than with NewMsgSubmitProposal,
+	// which would encode it with the bech32 prefix of the global config.
+	msg := &govtypes.MsgSubmitProposal{InitialDeposit: deposit, Proposer: acc}
+	content := clienttypes.NewClientUpdateProposal(title, description, subjectClientID, substituteClientID)
+	if err := content.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	if err := msg.SetContent(content); err != nil {
+		return nil, err
+	}
+	return NewCosmosMessage(msg), nil
+}
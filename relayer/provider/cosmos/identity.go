@@ -0,0 +1,103 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// signer returns how the transactions of the relayer on the chain are signed.
+func (cc *CosmosProvider) signer() string {
+	switch {
+	case cc.remoteSigner != nil:
+		return provider.SignerRemote
+	case cc.PCfg.Multisig != nil:
+		return provider.SignerMultisig
+	default:
+		return provider.SignerLocal
+	}
+}
+
+// QueryAccountIdentity queries the account of the relayer on the chain, by its address with the account prefix of the chain,
+// along with the fee grant and authz grants of the configured fee granter to it.
+// Failures to query the grants are reported in the identity rather than returned, as they do not affect the account.
+func (cc *CosmosProvider) QueryAccountIdentity(ctx context.Context) (provider.AccountIdentity, error) {
+	id := provider.AccountIdentity{Signer: cc.signer(), FeeGranter: cc.PCfg.FeeGranter}
+	addr, err := cc.Address()
+	if err != nil {
+		return id, err
+	}
+
+	res, err := authtypes.NewQueryClient(cc).Account(ctx, &authtypes.QueryAccountRequest{Address: addr})
+	switch {
+	case status.Code(err) == codes.NotFound:
+	case err != nil:
+		return id, fmt.Errorf("failed to query account %s: %w", addr, err)
+	default:
+		cc.setAccount(&id, res.Account)
+	}
+
+	if cc.PCfg.FeeGranter == "" {
+		return id, nil
+	}
+	g, err := cc.QueryFeeGrant(ctx)
+	if err != nil {
+		id.FeeGrantError = err.Error()
+	} else {
+		id.FeeGrant = g.Type
+		if err := g.Check(time.Now(), nil); err != nil {
+			id.FeeGrantError = err.Error()
+		}
+	}
+
+	grants, err := authz.NewQueryClient(cc).Grants(ctx, &authz.QueryGrantsRequest{Granter: cc.PCfg.FeeGranter, Grantee: addr})
+	if err != nil {
+		return id, fmt.Errorf("failed to query authz grants of %s to %s: %w", cc.PCfg.FeeGranter, addr, err)
+	}
+	id.AuthzGrants = cc.authzMessages(grants.Grants)
+	return id, nil
+}
+
+// setAccount describes account in id. Accounts of types unknown to the codec of the chain, such as the accounts of
+// chains with a custom auth module, are only described by their type URL.
+func (cc *CosmosProvider) setAccount(id *provider.AccountIdentity, account *codectypes.Any) {
+	if account == nil {
+		return
+	}
+	id.AccountType = account.TypeUrl
+	var acc authtypes.AccountI
+	if err := cc.Codec.InterfaceRegistry.UnpackAny(account, &acc); err != nil {
+		return
+	}
+	id.AccountNumber = acc.GetAccountNumber()
+	id.Sequence = acc.GetSequence()
+	if pk := acc.GetPubKey(); pk != nil {
+		id.PubKeyType = "/" + proto.MessageName(pk)
+	}
+}
+
+// authzMessages returns the messages authorized by grants, or the type URL of the authorizations
+// which are not restricted to a single message type known to the codec of the chain.
+func (cc *CosmosProvider) authzMessages(grants []*authz.Grant) []string {
+	var msgs []string
+	for _, g := range grants {
+		if g.Authorization == nil {
+			continue
+		}
+		var a authz.Authorization
+		if err := cc.Codec.InterfaceRegistry.UnpackAny(g.Authorization, &a); err != nil {
+			msgs = append(msgs, g.Authorization.TypeUrl)
+			continue
+		}
+		msgs = append(msgs, a.MsgTypeURL())
+	}
+	return msgs
+}
@@ -0,0 +1,51 @@
+package cosmos
+
+import (
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountIdentity(t *testing.T) {
+	cc := &CosmosProvider{ChainClient: lens.ChainClient{Codec: lens.MakeCodec(lens.ModuleBasics)}}
+	require.Equal(t, "local", cc.signer())
+	cc.PCfg.Multisig = &MultisigConfig{}
+	require.Equal(t, "multisig", cc.signer())
+
+	pk := secp256k1.GenPrivKey().PubKey()
+	acc := authtypes.NewBaseAccount(sdk.AccAddress(pk.Address()), pk, 7, 42)
+	account, err := codectypes.NewAnyWithValue(acc)
+	require.NoError(t, err)
+	var id provider.AccountIdentity
+	cc.setAccount(&id, account)
+	require.Equal(t, "/cosmos.auth.v1beta1.BaseAccount", id.AccountType)
+	require.Equal(t, uint64(7), id.AccountNumber)
+	require.Equal(t, uint64(42), id.Sequence)
+	require.Equal(t, "/cosmos.crypto.secp256k1.PubKey", id.PubKeyType)
+
+	// Accounts unknown to the codec are only described by their type URL.
+	id = provider.AccountIdentity{}
+	cc.setAccount(&id, &codectypes.Any{TypeUrl: "/rollapp.auth.v1.Account"})
+	require.Equal(t, "/rollapp.auth.v1.Account", id.AccountType)
+	require.Zero(t, id.AccountNumber)
+
+	expiration := time.Now().Add(time.Hour)
+	generic, err := authz.NewGrant(authz.NewGenericAuthorization("/ibc.core.channel.v1.MsgRecvPacket"), expiration)
+	require.NoError(t, err)
+	send, err := authz.NewGrant(types.NewSendAuthorization(sdk.NewCoins(sdk.NewInt64Coin("urax", 10))), expiration)
+	require.NoError(t, err)
+	unknown := authz.Grant{Authorization: &codectypes.Any{TypeUrl: "/rollapp.Authorization"}}
+	require.Equal(t,
+		[]string{"/ibc.core.channel.v1.MsgRecvPacket", "/cosmos.bank.v1beta1.MsgSend", "/rollapp.Authorization"},
+		cc.authzMessages([]*authz.Grant{&generic, &send, &unknown}),
+	)
+}
@@ -0,0 +1,576 @@
+package cosmos
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMultisigTimeout = 2 * time.Minute
+
+	// multisigSignMode is the sign mode of the signatures of multisig members. Amino JSON, as used by the
+	// multisig commands of the SDK, is not supported by the IBC messages, so members sign the transaction directly,
+	// which requires the members signing it to be known before it is signed.
+	multisigSignMode = signing.SignMode_SIGN_MODE_DIRECT
+
+	// multisigPollInterval is how often the payload directory is checked for new co-signatures.
+	multisigPollInterval = time.Second
+
+	// coSignableMsgPrefix is the type URL prefix of the messages co-signers accept to sign:
+	// IBC client, connection and channel messages, which cannot move the funds of the multisig account.
+	coSignableMsgPrefix = "/ibc.core."
+)
+
+// MultisigConfig relays from a multisig account, configured as the key of the chain, the relayer signing with
+// MemberKey, one of the keys of the multisig, and gathering the signatures of the first CoSigners up to the threshold
+// of the multisig before broadcasting each transaction.
+type MultisigConfig struct {
+	MemberKey  string     `json:"member-key" yaml:"member-key"`
+	CoSigners  []CoSigner `json:"co-signers" yaml:"co-signers"`
+	PayloadDir string     `json:"payload-dir,omitempty" yaml:"payload-dir,omitempty"`
+	// Timeout bounds the wait for the co-signatures of a transaction, 2m by default.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// CoSigner is a member of the multisig, by address, co-signing the transactions of the relayer either
+// over the unix Socket it listens on, see ServeCoSigner, or, without a socket, by signing the payloads
+// exported to the payload directory, see CoSignPayloadFile.
+type CoSigner struct {
+	Member string `json:"member" yaml:"member"`
+	Socket string `json:"socket,omitempty" yaml:"socket,omitempty"`
+}
+
+// Validate validates the multisig config, nil meaning the key of the chain is a regular key.
+func (c *MultisigConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MemberKey == "" {
+		return fmt.Errorf("multisig: member-key is required")
+	}
+	for _, cs := range c.CoSigners {
+		if cs.Member == "" {
+			return fmt.Errorf("multisig: co-signers require a member address")
+		}
+		if cs.Socket == "" && c.PayloadDir == "" {
+			return fmt.Errorf("multisig: co-signer %s has no socket and no payload-dir is configured", cs.Member)
+		}
+	}
+	if c.Timeout != "" {
+		if _, err := time.ParseDuration(c.Timeout); err != nil {
+			return fmt.Errorf("multisig: invalid timeout: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *MultisigConfig) timeout() time.Duration {
+	if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return defaultMultisigTimeout
+}
+
+// SignPayload is a transaction of a multisig account to be signed by its members.
+// Tx is the transaction in its protobuf encoding, with empty signatures of the members expected to sign it,
+// from which co-signers derive what they sign; TxJSON is its JSON encoding, for operators reviewing it.
+type SignPayload struct {
+	ChainID       string          `json:"chain_id"`
+	Address       string          `json:"address"`
+	AccountNumber uint64          `json:"account_number"`
+	Sequence      uint64          `json:"sequence"`
+	Tx            []byte          `json:"tx"`
+	TxJSON        json.RawMessage `json:"tx_json"`
+}
+
+// ID identifies the payload, as found in the names of its files in the payload directory.
+func (p SignPayload) ID() string {
+	sum := sha256.Sum256(p.Tx)
+	return fmt.Sprintf("%s-%d-%s", p.ChainID, p.Sequence, hex.EncodeToString(sum[:8]))
+}
+
+// CoSignature is the signature of a SignPayload by a member of the multisig,
+// PubKey being the JSON encoding of the public key of the member.
+type CoSignature struct {
+	PubKey    json.RawMessage `json:"pub_key"`
+	Signature []byte          `json:"signature"`
+}
+
+// coSignResponse is the answer of a co-signer to a payload sent over its socket.
+type coSignResponse struct {
+	CoSignature *CoSignature `json:"co_signature,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// multisigPubKey returns the public key of the multisig configured as the key of the chain.
+func (cc *CosmosProvider) multisigPubKey() (*kmultisig.LegacyAminoPubKey, error) {
+	info, err := cc.Keybase.Key(cc.PCfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	pk, ok := info.GetPubKey().(*kmultisig.LegacyAminoPubKey)
+	if !ok {
+		return nil, fmt.Errorf("key %s of chain %s is not a multisig", cc.PCfg.Key, cc.PCfg.ChainID)
+	}
+	return pk, nil
+}
+
+// simulateMultisig returns the adjusted gas of the transaction of msgs sent from the multisig account,
+// simulated with placeholder signatures of the threshold of members so that their verification is accounted for.
+func (cc *CosmosProvider) simulateMultisig(ctx context.Context, txf tx.Factory, msgs ...sdk.Msg) (uint64, error) {
+	pk, err := cc.multisigPubKey()
+	if err != nil {
+		return 0, err
+	}
+	txb, err := tx.BuildUnsignedTx(txf, msgs...)
+	if err != nil {
+		return 0, err
+	}
+	sig := multisig.NewMultisig(len(pk.GetPubKeys()))
+	for i := 0; i < int(pk.Threshold); i++ {
+		multisig.AddSignature(sig, &signing.SingleSignatureData{SignMode: multisigSignMode, Signature: make([]byte, 64)}, i)
+	}
+	if err := txb.SetSignatures(signing.SignatureV2{PubKey: pk, Data: sig, Sequence: txf.Sequence()}); err != nil {
+		return 0, err
+	}
+	txBytes, err := cc.Codec.TxConfig.TxEncoder()(txb.GetTx())
+	if err != nil {
+		return 0, err
+	}
+	req, err := (&txtypes.SimulateRequest{TxBytes: txBytes}).Marshal()
+	if err != nil {
+		return 0, err
+	}
+	res, err := cc.QueryABCI(ctx, abci.RequestQuery{Path: "/cosmos.tx.v1beta1.Service/Simulate", Data: req})
+	if err != nil {
+		return 0, err
+	}
+	var simRes txtypes.SimulateResponse
+	if err := simRes.Unmarshal(res.Value); err != nil {
+		return 0, err
+	}
+	return uint64(txf.GasAdjustment() * float64(simRes.GasInfo.GasUsed)), nil
+}
+
+// signMultisig signs the transaction of txb from the multisig account with the member key of the relayer
+// and the co-signatures of the first co-signers up to the threshold of the multisig.
+func (cc *CosmosProvider) signMultisig(ctx context.Context, txf tx.Factory, txb client.TxBuilder) error {
+	cfg := cc.PCfg.Multisig
+	pk, err := cc.multisigPubKey()
+	if err != nil {
+		return err
+	}
+	if len(cfg.CoSigners)+1 < int(pk.Threshold) {
+		return fmt.Errorf("multisig %s requires %d signatures, only %d co-signers are configured", cc.PCfg.Key, pk.Threshold, len(cfg.CoSigners))
+	}
+	info, err := cc.Keybase.Key(cfg.MemberKey)
+	if err != nil {
+		return fmt.Errorf("member key %s: %w", cfg.MemberKey, err)
+	}
+	signers := []cryptotypes.PubKey{info.GetPubKey()}
+	coSigners := cfg.CoSigners[:pk.Threshold-1]
+	for _, c := range coSigners {
+		member, err := multisigMember(cc, pk, c.Member)
+		if err != nil {
+			return err
+		}
+		signers = append(signers, member)
+	}
+
+	// The signer infos of the transaction, part of what is signed, tell which members sign it.
+	placeholder := multisig.NewMultisig(len(pk.GetPubKeys()))
+	for _, signer := range signers {
+		if err := multisig.AddSignatureFromPubKey(placeholder, &signing.SingleSignatureData{SignMode: multisigSignMode}, signer, pk.GetPubKeys()); err != nil {
+			return err
+		}
+	}
+	if err := txb.SetSignatures(signing.SignatureV2{PubKey: pk, Data: placeholder, Sequence: txf.Sequence()}); err != nil {
+		return err
+	}
+
+	// The SDK context is only held while deriving the sign bytes, not while waiting for the co-signers.
+	done := cc.SetSDKContext()
+	payload, signBytes, err := cc.signPayload(txf, txb)
+	done()
+	if err != nil {
+		return err
+	}
+
+	sig := multisig.NewMultisig(len(pk.GetPubKeys()))
+	signed := make(map[string]bool)
+	add := func(cs CoSignature) error {
+		var member cryptotypes.PubKey
+		if err := cc.Codec.Marshaler.UnmarshalInterfaceJSON(cs.PubKey, &member); err != nil {
+			return fmt.Errorf("invalid public key: %w", err)
+		}
+		expected := false
+		for _, signer := range signers {
+			expected = expected || signer.Equals(member)
+		}
+		if !expected {
+			return fmt.Errorf("%s is not a signer of the transaction", member.Address())
+		}
+		if signed[member.Address().String()] {
+			return nil
+		}
+		if !member.VerifySignature(signBytes, cs.Signature) {
+			return fmt.Errorf("invalid signature of %s", member.Address())
+		}
+		if err := multisig.AddSignatureFromPubKey(sig, &signing.SingleSignatureData{SignMode: multisigSignMode, Signature: cs.Signature}, member, pk.GetPubKeys()); err != nil {
+			return err
+		}
+		signed[member.Address().String()] = true
+		return nil
+	}
+
+	own, err := cc.coSignature(cfg.MemberKey, signBytes)
+	if err != nil {
+		return fmt.Errorf("failed to sign with member key %s: %w", cfg.MemberKey, err)
+	}
+	if err := add(own); err != nil {
+		return fmt.Errorf("member key %s: %w", cfg.MemberKey, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout())
+	defer cancel()
+	coSignatures := cc.requestCoSignatures(ctx, coSigners, payload)
+	for len(signed) < len(signers) {
+		select {
+		case cs := <-coSignatures:
+			if err := add(cs); err != nil {
+				cc.log.Warn("Rejected co-signature", zap.String("chain_id", cc.PCfg.ChainID), zap.String("payload", payload.ID()), zap.Error(err))
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("gathered %d of the %d signatures required by multisig %s: %w", len(signed), pk.Threshold, payload.Address, ctx.Err())
+		}
+	}
+
+	return txb.SetSignatures(signing.SignatureV2{PubKey: pk, Data: sig, Sequence: txf.Sequence()})
+}
+
+// multisigMember returns the public key of the member of the multisig with the given address.
+func multisigMember(cc *CosmosProvider, pk *kmultisig.LegacyAminoPubKey, address string) (cryptotypes.PubKey, error) {
+	for _, member := range pk.GetPubKeys() {
+		addr, err := cc.EncodeBech32AccAddr(sdk.AccAddress(member.Address()))
+		if err != nil {
+			return nil, err
+		}
+		if addr == address {
+			return member, nil
+		}
+	}
+	return nil, fmt.Errorf("co-signer %s is not a member of multisig %s", address, cc.PCfg.Key)
+}
+
+// signPayload returns the payload of the transaction of txb for the co-signers, along with the bytes they sign.
+func (cc *CosmosProvider) signPayload(txf tx.Factory, txb client.TxBuilder) (SignPayload, []byte, error) {
+	addr, err := cc.Address()
+	if err != nil {
+		return SignPayload{}, nil, err
+	}
+	txBytes, err := cc.Codec.TxConfig.TxEncoder()(txb.GetTx())
+	if err != nil {
+		return SignPayload{}, nil, err
+	}
+	txJSON, err := cc.Codec.TxConfig.TxJSONEncoder()(txb.GetTx())
+	if err != nil {
+		return SignPayload{}, nil, err
+	}
+	signBytes, err := cc.Codec.TxConfig.SignModeHandler().GetSignBytes(multisigSignMode, authsigning.SignerData{
+		ChainID:       cc.PCfg.ChainID,
+		AccountNumber: txf.AccountNumber(),
+		Sequence:      txf.Sequence(),
+	}, txb.GetTx())
+	if err != nil {
+		return SignPayload{}, nil, err
+	}
+	return SignPayload{
+		ChainID:       cc.PCfg.ChainID,
+		Address:       addr,
+		AccountNumber: txf.AccountNumber(),
+		Sequence:      txf.Sequence(),
+		Tx:            txBytes,
+		TxJSON:        txJSON,
+	}, signBytes, nil
+}
+
+// coSignature signs signBytes with the given key of the keyring.
+func (cc *CosmosProvider) coSignature(keyName string, signBytes []byte) (CoSignature, error) {
+	bz, pk, err := cc.Keybase.Sign(keyName, signBytes)
+	if err != nil {
+		return CoSignature{}, err
+	}
+	pkJSON, err := cc.Codec.Marshaler.MarshalInterfaceJSON(pk)
+	if err != nil {
+		return CoSignature{}, err
+	}
+	return CoSignature{PubKey: pkJSON, Signature: bz}, nil
+}
+
+// CoSign signs the payload with the given key of the keyring, as a member of the multisig of the payload.
+// The bytes signed are derived from the transaction of the payload rather than taken from it, and only transactions
+// of IBC client, connection and channel messages of the multisig, paying at most maxFee if set, are signed.
+func (cc *CosmosProvider) CoSign(payload SignPayload, keyName string, maxFee sdk.Coins) (CoSignature, error) {
+	if payload.ChainID != cc.PCfg.ChainID {
+		return CoSignature{}, fmt.Errorf("payload is for chain %s, not %s", payload.ChainID, cc.PCfg.ChainID)
+	}
+	decoded, err := cc.Codec.TxConfig.TxDecoder()(payload.Tx)
+	if err != nil {
+		return CoSignature{}, fmt.Errorf("invalid payload transaction: %w", err)
+	}
+	signingTx, ok := decoded.(authsigning.Tx)
+	if !ok {
+		return CoSignature{}, fmt.Errorf("payload transaction cannot be signed")
+	}
+	done := cc.SetSDKContext()
+	defer done()
+	for _, msg := range signingTx.GetMsgs() {
+		if typeURL := sdk.MsgTypeURL(msg); !strings.HasPrefix(typeURL, coSignableMsgPrefix) {
+			return CoSignature{}, fmt.Errorf("refusing to co-sign %s, only IBC client, connection and channel messages are", typeURL)
+		}
+		for _, signer := range msg.GetSigners() {
+			if signer.String() != payload.Address {
+				return CoSignature{}, fmt.Errorf("refusing to co-sign a message of %s for multisig %s", signer, payload.Address)
+			}
+		}
+	}
+	if fee := signingTx.GetFee(); !maxFee.Empty() && !fee.IsAllLTE(maxFee) {
+		return CoSignature{}, fmt.Errorf("refusing to co-sign fee %s above %s", fee, maxFee)
+	}
+	signBytes, err := cc.Codec.TxConfig.SignModeHandler().GetSignBytes(multisigSignMode, authsigning.SignerData{
+		ChainID:       payload.ChainID,
+		AccountNumber: payload.AccountNumber,
+		Sequence:      payload.Sequence,
+	}, signingTx)
+	if err != nil {
+		return CoSignature{}, err
+	}
+	return cc.coSignature(keyName, signBytes)
+}
+
+// requestCoSignatures asks the co-signers to sign payload, and returns their co-signatures as they come in,
+// until ctx is done.
+func (cc *CosmosProvider) requestCoSignatures(ctx context.Context, coSigners []CoSigner, payload SignPayload) <-chan CoSignature {
+	out := make(chan CoSignature, len(coSigners)+1)
+	export := false
+	for _, c := range coSigners {
+		if c.Socket == "" {
+			export = true
+			continue
+		}
+		go func(c CoSigner) {
+			cs, err := requestCoSignature(ctx, c.Socket, payload)
+			if err != nil {
+				cc.log.Warn("Failed to get co-signature", zap.String("chain_id", cc.PCfg.ChainID), zap.String("co_signer", c.Member), zap.Error(err))
+				return
+			}
+			out <- cs
+		}(c)
+	}
+	if export {
+		go cc.pollCoSignatures(ctx, cc.PCfg.Multisig.PayloadDir, payload, out)
+	}
+	return out
+}
+
+// requestCoSignature sends payload to the co-signer listening on the unix socket, and returns its co-signature.
+func requestCoSignature(ctx context.Context, socket string, payload SignPayload) (CoSignature, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", socket)
+	if err != nil {
+		return CoSignature{}, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if err := json.NewEncoder(conn).Encode(payload); err != nil {
+		return CoSignature{}, err
+	}
+	var res coSignResponse
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&res); err != nil {
+		return CoSignature{}, err
+	}
+	if res.Error != "" {
+		return CoSignature{}, errors.New(res.Error)
+	}
+	if res.CoSignature == nil {
+		return CoSignature{}, fmt.Errorf("empty co-signer response")
+	}
+	return *res.CoSignature, nil
+}
+
+// pollCoSignatures exports payload to dir, as <id>.payload.json, and sends the co-signatures written next to it
+// to out as they appear, until ctx is done, removing the files of the payload then.
+func (cc *CosmosProvider) pollCoSignatures(ctx context.Context, dir string, payload SignPayload, out chan<- CoSignature) {
+	log := cc.log.With(zap.String("chain_id", cc.PCfg.ChainID), zap.String("payload", payload.ID()))
+	file := filepath.Join(dir, payload.ID()+".payload.json")
+	sigPattern := filepath.Join(dir, payload.ID()+".*.sig.json")
+	defer func() {
+		os.Remove(file)
+		sigFiles, _ := filepath.Glob(sigPattern)
+		for _, f := range sigFiles {
+			os.Remove(f)
+		}
+	}()
+
+	bz, err := json.MarshalIndent(payload, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(dir, 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(file, bz, 0o644)
+	}
+	if err != nil {
+		log.Warn("Failed to export multisig payload", zap.Error(err))
+		return
+	}
+	log.Info("Exported multisig payload, awaiting co-signatures", zap.String("file", file))
+
+	seen := make(map[string]bool)
+	ticker := time.NewTicker(multisigPollInterval)
+	defer ticker.Stop()
+	for {
+		sigFiles, _ := filepath.Glob(sigPattern)
+		for _, f := range sigFiles {
+			if seen[f] {
+				continue
+			}
+			var cs CoSignature
+			bz, err := os.ReadFile(f)
+			if err == nil {
+				err = json.Unmarshal(bz, &cs)
+			}
+			if err != nil {
+				// possibly still being written, read it again on the next tick
+				continue
+			}
+			seen[f] = true
+			select {
+			case out <- cs:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CoSignPayloadFile co-signs the payload exported to file, see CoSign, writing the co-signature next to it
+// as <id>.<keyName>.sig.json, where the relayer picks it up.
+func (cc *CosmosProvider) CoSignPayloadFile(file, keyName string, maxFee sdk.Coins) (string, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	var payload SignPayload
+	if err := json.Unmarshal(bz, &payload); err != nil {
+		return "", fmt.Errorf("invalid payload %s: %w", file, err)
+	}
+	cs, err := cc.CoSign(payload, keyName, maxFee)
+	if err != nil {
+		return "", err
+	}
+	bz, err = json.Marshal(cs)
+	if err != nil {
+		return "", err
+	}
+	sigFile := filepath.Join(filepath.Dir(file), payload.ID()+"."+keyName+".sig.json")
+	tmp := sigFile + ".tmp"
+	if err := os.WriteFile(tmp, bz, 0o644); err != nil {
+		return "", err
+	}
+	return sigFile, os.Rename(tmp, sigFile)
+}
+
+// ServeCoSigner co-signs the payloads sent by relayers over ln with the given key of the keyring, see CoSign,
+// until ctx is done.
+func (cc *CosmosProvider) ServeCoSigner(ctx context.Context, ln net.Listener, keyName string, maxFee sdk.Coins) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(time.Minute))
+			var (
+				payload SignPayload
+				res     coSignResponse
+			)
+			if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&payload); err != nil {
+				res.Error = fmt.Sprintf("invalid payload: %v", err)
+			} else if cs, err := cc.CoSign(payload, keyName, maxFee); err != nil {
+				res.Error = err.Error()
+				cc.log.Warn("Refused to co-sign payload", zap.String("payload", payload.ID()), zap.Error(err))
+			} else {
+				res.CoSignature = &cs
+				cc.log.Info("Co-signed payload", zap.String("payload", payload.ID()), zap.String("address", payload.Address))
+			}
+			_ = json.NewEncoder(conn).Encode(res)
+		}(conn)
+	}
+}
+
+// AddMultisigKey saves the multisig of the given threshold of member public keys to the keyring, as name,
+// and returns its address.
+func (cc *CosmosProvider) AddMultisigKey(name string, threshold int, members []cryptotypes.PubKey) (string, error) {
+	if threshold < 1 || threshold > len(members) {
+		return "", fmt.Errorf("invalid threshold %d of %d members", threshold, len(members))
+	}
+	info, err := cc.Keybase.SaveMultisig(name, kmultisig.NewLegacyAminoPubKey(threshold, members))
+	if err != nil {
+		return "", err
+	}
+	return cc.EncodeBech32AccAddr(info.GetAddress())
+}
+
+// MultisigMember returns the public key of a member of a multisig, either the name of a key of the keyring
+// or the JSON encoding of a public key, as in {"@type":"/cosmos.crypto.secp256k1.PubKey","key":"..."}.
+func (cc *CosmosProvider) MultisigMember(member string) (cryptotypes.PubKey, error) {
+	if strings.HasPrefix(strings.TrimSpace(member), "{") {
+		var pk cryptotypes.PubKey
+		if err := cc.Codec.Marshaler.UnmarshalInterfaceJSON([]byte(member), &pk); err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", member, err)
+		}
+		return pk, nil
+	}
+	info, err := cc.Keybase.Key(member)
+	if err != nil {
+		return nil, err
+	}
+	return info.GetPubKey(), nil
+}
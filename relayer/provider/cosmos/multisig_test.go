@@ -0,0 +1,152 @@
+package cosmos
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// newMultisigProvider returns a provider relaying from a 2 of 3 multisig of alice, bob and carol, as alice,
+// co-signed by bob over bobSocket, or through the payload directory without a socket.
+func newMultisigProvider(t *testing.T, cfg MultisigConfig, bobSocket string) *CosmosProvider {
+	kr := keyring.NewInMemory()
+	var members []cryptotypes.PubKey
+	for _, name := range []string{"alice", "bob", "carol"} {
+		info, _, err := kr.NewMnemonic(name, keyring.English, sdk.FullFundraiserPath, "", hd.Secp256k1)
+		require.NoError(t, err)
+		members = append(members, info.GetPubKey())
+	}
+	cfg.MemberKey = "alice"
+	cc := &CosmosProvider{
+		log: zaptest.NewLogger(t),
+		ChainClient: lens.ChainClient{
+			Config:  &lens.ChainClientConfig{Key: "treasury", ChainID: "ibc-0", AccountPrefix: "cosmos"},
+			Keybase: kr,
+			Codec:   lens.MakeCodec(lens.ModuleBasics),
+		},
+		PCfg: CosmosProviderConfig{Key: "treasury", ChainID: "ibc-0", AccountPrefix: "cosmos", Multisig: &cfg},
+	}
+	_, err := cc.AddMultisigKey("treasury", 2, members)
+	require.NoError(t, err)
+	bob, err := cc.EncodeBech32AccAddr(sdk.AccAddress(members[1].Address()))
+	require.NoError(t, err)
+	cfg.CoSigners = []CoSigner{{Member: bob, Socket: bobSocket}}
+	return cc
+}
+
+func multisigTx(t *testing.T, cc *CosmosProvider, msg sdk.Msg) (tx.Factory, client.TxBuilder) {
+	txf := tx.Factory{}.
+		WithChainID("ibc-0").
+		WithTxConfig(cc.Codec.TxConfig).
+		WithAccountNumber(7).
+		WithSequence(3).
+		WithGas(200000).
+		WithFees("1000uatom")
+	txb, err := tx.BuildUnsignedTx(txf, msg)
+	require.NoError(t, err)
+	return txf, txb
+}
+
+func requireValidMultisig(t *testing.T, cc *CosmosProvider, txf tx.Factory, txb client.TxBuilder) {
+	sigs, err := txb.GetTx().GetSignaturesV2()
+	require.NoError(t, err)
+	require.Len(t, sigs, 1)
+	require.NoError(t, authsigning.VerifySignature(sigs[0].PubKey, authsigning.SignerData{
+		ChainID:       "ibc-0",
+		AccountNumber: txf.AccountNumber(),
+		Sequence:      txf.Sequence(),
+	}, sigs[0].Data, cc.Codec.TxConfig.SignModeHandler(), txb.GetTx()))
+}
+
+func TestMultisigPayloadDir(t *testing.T) {
+	dir := t.TempDir()
+	cc := newMultisigProvider(t, MultisigConfig{PayloadDir: dir, Timeout: "10s"}, "")
+	addr, err := cc.Address()
+	require.NoError(t, err)
+	txf, txb := multisigTx(t, cc, &chantypes.MsgRecvPacket{Packet: chantypes.Packet{Sequence: 1}, Signer: addr})
+
+	signed := make(chan error, 1)
+	go func() { signed <- cc.signMultisig(context.Background(), txf, txb) }()
+
+	// bob co-signs the exported payload
+	var payloads []string
+	require.Eventually(t, func() bool {
+		payloads, _ = filepath.Glob(filepath.Join(dir, "*.payload.json"))
+		return len(payloads) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = cc.CoSignPayloadFile(payloads[0], "bob", sdk.NewCoins(sdk.NewInt64Coin("uatom", 5000)))
+	require.NoError(t, err)
+
+	require.NoError(t, <-signed)
+	requireValidMultisig(t, cc, txf, txb)
+
+	// the payload and its co-signatures are cleaned up
+	require.Eventually(t, func() bool {
+		entries, _ := os.ReadDir(dir)
+		return len(entries) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestMultisigCoSignerSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "bob.sock")
+	cc := newMultisigProvider(t, MultisigConfig{Timeout: "10s"}, socket)
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = cc.ServeCoSigner(ctx, ln, "bob", nil) }()
+
+	addr, err := cc.Address()
+	require.NoError(t, err)
+	txf, txb := multisigTx(t, cc, &chantypes.MsgRecvPacket{Packet: chantypes.Packet{Sequence: 1}, Signer: addr})
+	require.NoError(t, cc.signMultisig(ctx, txf, txb))
+	requireValidMultisig(t, cc, txf, txb)
+}
+
+func TestCoSignRefusals(t *testing.T) {
+	cc := newMultisigProvider(t, MultisigConfig{PayloadDir: t.TempDir()}, "")
+	addr, err := cc.Address()
+	require.NoError(t, err)
+
+	payload := func(msg sdk.Msg) SignPayload {
+		txf, txb := multisigTx(t, cc, msg)
+		p, _, err := cc.signPayload(txf, txb)
+		require.NoError(t, err)
+		return p
+	}
+
+	recv := payload(&chantypes.MsgRecvPacket{Packet: chantypes.Packet{Sequence: 1}, Signer: addr})
+	_, err = cc.CoSign(recv, "bob", nil)
+	require.NoError(t, err)
+
+	// fees above the cap
+	_, err = cc.CoSign(recv, "bob", sdk.NewCoins(sdk.NewInt64Coin("uatom", 10)))
+	require.Error(t, err)
+
+	// other chains
+	other := recv
+	other.ChainID = "ibc-1"
+	_, err = cc.CoSign(other, "bob", nil)
+	require.Error(t, err)
+
+	// messages moving the funds of the multisig
+	send := payload(&banktypes.MsgSend{FromAddress: addr, ToAddress: addr, Amount: sdk.NewCoins(sdk.NewInt64Coin("uatom", 1))})
+	_, err = cc.CoSign(send, "bob", nil)
+	require.ErrorContains(t, err, "refusing to co-sign /cosmos.bank.v1beta1.MsgSend")
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
 	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
 	"github.com/cosmos/relayer/v2/relayer/provider"
@@ -36,12 +39,88 @@ type CosmosProviderConfig struct {
 	OutputFormat   string  `json:"output-format" yaml:"output-format"`
 	SignModeStr    string  `json:"sign-mode" yaml:"sign-mode"`
 	ClientType     string  `json:"client-type" yaml:"client-type"`
+	// Settlement is the name of the chain the rollapp settles on, overriding the global settlement chain.
+	Settlement string `json:"settlement,omitempty" yaml:"settlement,omitempty"`
+	// SettlementType is the settlement layer implemented by the chain when rollapps settle on it,
+	// one of SettlementTypes, defaulting to gridiron.
+	SettlementType string `json:"settlement-type,omitempty" yaml:"settlement-type,omitempty"`
+	// TxTimeoutHeightOffset, if non-zero, sets the timeout height of every transaction
+	// to the latest height of the chain plus the offset when the transaction is built.
+	TxTimeoutHeightOffset uint64 `json:"tx-timeout-height-offset,omitempty" yaml:"tx-timeout-height-offset,omitempty"`
+	// Retry overrides how failing queries to the chain are retried while relaying.
+	Retry *provider.RetryPolicy `json:"retry,omitempty" yaml:"retry,omitempty"`
+	// RPCAddrs are the RPC endpoints failed over to, in order, while RPCAddr is unhealthy.
+	RPCAddrs []string `json:"rpc-addrs,omitempty" yaml:"rpc-addrs,omitempty"`
+	// RPCHealthCheck overrides how the RPC endpoints are health checked when RPCAddrs are set.
+	RPCHealthCheck *RPCHealthCheck `json:"rpc-health-check,omitempty" yaml:"rpc-health-check,omitempty"`
+	// ExtensionOptions are added to every transaction sent to the chain, e.g. the dynamic fee extension of EVM rollapps.
+	ExtensionOptions []TxExtensionOption `json:"extension-options,omitempty" yaml:"extension-options,omitempty"`
+	// Multisig, if set, relays from the multisig account of Key, see MultisigConfig.
+	Multisig *MultisigConfig `json:"multisig,omitempty" yaml:"multisig,omitempty"`
+	// RemoteSigner, if set, signs with an external signer instead of the local keyring, see RemoteSignerConfig.
+	RemoteSigner *RemoteSignerConfig `json:"remote-signer,omitempty" yaml:"remote-signer,omitempty"`
+	// FeeGranter, if set, is the address of the account paying the fees of the transactions of the relayer
+	// through the allowance it granted the relayer with the feegrant module.
+	FeeGranter string `json:"fee-granter,omitempty" yaml:"fee-granter,omitempty"`
+	// MaxGas and MaxFee, if set, cap the simulated gas and the fee of every transaction sent to the chain,
+	// e.g. "5000000urax" for MaxFee. Batches over the caps are split, and single messages over them skipped.
+	MaxGas uint64 `json:"max-gas,omitempty" yaml:"max-gas,omitempty"`
+	MaxFee string `json:"max-fee,omitempty" yaml:"max-fee,omitempty"`
+	// MaxTxBytes, if set, is the size of the largest transaction accepted by the mempool of the nodes of the chain
+	// (max_tx_bytes of their config, 1MiB by default), which cannot be queried unlike the max bytes of its blocks.
+	MaxTxBytes uint64 `json:"max-tx-bytes,omitempty" yaml:"max-tx-bytes,omitempty"`
+	// BroadcastMode is "sync", the default, broadcasting transactions once checked by the mempool of the endpoint,
+	// or "async", broadcasting them without waiting for the check. Either way, transactions are then followed
+	// until included in a block as configured by TxTracker.
+	BroadcastMode string           `json:"broadcast-mode,omitempty" yaml:"broadcast-mode,omitempty"`
+	TxTracker     *TxTrackerConfig `json:"tx-tracker,omitempty" yaml:"tx-tracker,omitempty"`
+	// Confirmations is the number of blocks built on top of the block of a packet sent on the chain
+	// before the packet is relayed, none if zero.
+	Confirmations uint64 `json:"confirmations,omitempty" yaml:"confirmations,omitempty"`
+	// Faults, if set, are injected into the RPC requests of the chain to test the resilience of the relayer,
+	// in builds with the faultinjection build tag only.
+	Faults *provider.FaultConfig `json:"faults,omitempty" yaml:"faults,omitempty"`
 }
 
 func (pc CosmosProviderConfig) Validate() error {
 	if _, err := time.ParseDuration(pc.Timeout); err != nil {
 		return fmt.Errorf("invalid Timeout: %w", err)
 	}
+	if err := pc.Retry.Validate(); err != nil {
+		return err
+	}
+	if err := pc.RPCHealthCheck.Validate(); err != nil {
+		return err
+	}
+	if _, err := pc.txExtensionOptions(1); err != nil {
+		return err
+	}
+	if err := validateSettlementType(pc.SettlementType); err != nil {
+		return err
+	}
+	if err := pc.Multisig.Validate(); err != nil {
+		return err
+	}
+	if err := pc.RemoteSigner.Validate(); err != nil {
+		return err
+	}
+	if err := pc.Faults.Validate(); err != nil {
+		return err
+	}
+	if err := validateBroadcastMode(pc.BroadcastMode); err != nil {
+		return err
+	}
+	if err := pc.TxTracker.Validate(); err != nil {
+		return err
+	}
+	if _, err := pc.maxFee(); err != nil {
+		return err
+	}
+	if pc.FeeGranter != "" {
+		if _, err := sdk.GetFromBech32(pc.FeeGranter, pc.AccountPrefix); err != nil {
+			return fmt.Errorf("invalid fee-granter %q: %w", pc.FeeGranter, err)
+		}
+	}
 	return nil
 }
 
@@ -60,12 +139,59 @@ func (pc CosmosProviderConfig) NewProvider(log *zap.Logger, homepath string, deb
 	if err != nil {
 		return nil, err
 	}
+	var faults *rpcFaults
+	if provider.FaultInjection {
+		if faults, err = newRPCFaults(pc.Faults); err != nil {
+			return nil, err
+		}
+		if pc.Faults.Enabled() {
+			log.Warn(
+				"Injecting RPC faults",
+				zap.String("chain_id", pc.ChainID),
+				zap.Float64("drop_rate", pc.Faults.DropRate),
+				zap.String("broadcast_delay", pc.Faults.BroadcastDelay),
+				zap.Int64("stale_heights", pc.Faults.StaleHeights),
+			)
+		}
+	}
+	cc.RPCClient = injectFaults(faults, cc.RPCClient)
+	if len(pc.RPCAddrs) > 0 {
+		endpoints := []*rpcEndpoint{{addr: pc.RPCAddr, client: cc.RPCClient}}
+		timeout, _ := time.ParseDuration(pc.Timeout)
+		for _, addr := range pc.RPCAddrs {
+			c, err := lens.NewRPCClient(addr, timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rpc-addrs endpoint %s: %w", addr, err)
+			}
+			endpoints = append(endpoints, &rpcEndpoint{addr: addr, client: injectFaults(faults, c)})
+		}
+		cc.RPCClient = newFailoverClient(log.With(zap.String("sys", "rpc_failover")), pc.ChainID, pc.RPCHealthCheck, endpoints)
+	}
+	cc.RPCClient = newTracingClient(pc.ChainID, cc.RPCClient)
+	var signer *remoteSigner
+	if pc.RemoteSigner != nil {
+		cfg := *pc.RemoteSigner
+		if cfg.KeyName == "" {
+			cfg.KeyName = pc.Key
+		}
+		if cfg.KeyID == "" {
+			cfg.KeyID = cfg.KeyName
+		}
+		signer = newRemoteSigner(log.With(zap.String("sys", "remote_signer")), pc.ChainID, cfg, cc.Codec.Marshaler)
+		cc.Keybase = &remoteSignerKeyring{Keyring: cc.Keybase, name: cfg.KeyName, signer: signer}
+	}
+	txs, _ := pc.TxTracker.settings()
 	pc.ChainName = chainName
 	return &CosmosProvider{
 		log: log,
 
-		ChainClient: *cc,
-		PCfg:        pc,
+		ChainClient:  *cc,
+		PCfg:         pc,
+		signing:      provider.NewSigningScheduler(),
+		sequences:    newAccountSequences(),
+		remoteSigner: signer,
+		faults:       faults,
+		txs:          newTxTracker(log.With(zap.String("sys", "tx_tracker")), pc.ChainID, txs),
 	}, nil
 }
 
@@ -93,6 +219,33 @@ type CosmosProvider struct {
 
 	lens.ChainClient
 	PCfg CosmosProviderConfig
+
+	// settlement hub of the chain, if it is a rollapp
+	settlement SettlementProvider
+
+	// serializes the transactions signed with the key of the chain
+	signing *provider.SigningScheduler
+
+	// tracks the sequences of the transactions signed with the keys of the chain, pending in the mempool
+	sequences *accountSequences
+
+	// signs with the key of an external signer, if configured
+	remoteSigner *remoteSigner
+
+	// faults injected into the RPC requests of the chain, nil in builds without fault injection
+	faults *rpcFaults
+
+	// follows the transactions broadcast to the chain until included in a block
+	txs *txTracker
+
+	// trust levels of the clients of the chain, by counterparty chain ID and client ID
+	trustLevels sync.Map
+
+	// intermediate headers of the chain fetched to bisect client updates
+	bisectionHeaders headerCache
+
+	// signals the event subscriptions over the websocket of the chain to be established again
+	subscriptions eventSubscriptions
 }
 
 type CosmosIBCHeader struct {
@@ -111,14 +264,29 @@ func (cc *CosmosProvider) ProviderConfig() provider.ProviderConfig {
 	return cc.PCfg
 }
 
+// SigningQueueDepths returns the number of transactions waiting to be signed with the key of the chain, by path.
+func (cc *CosmosProvider) SigningQueueDepths() map[string]int {
+	return cc.signing.QueueDepths()
+}
+
 func (cc *CosmosProvider) ChainId() string {
 	return cc.PCfg.ChainID
 }
 
+// RetryPolicy returns the retry policy configured for the chain, nil if none is.
+func (cc *CosmosProvider) RetryPolicy() *provider.RetryPolicy {
+	return cc.PCfg.Retry
+}
+
 func (cc *CosmosProvider) ChainName() string {
 	return cc.PCfg.ChainName
 }
 
+// Confirmations returns the number of blocks built on top of the block of a packet sent on the chain before it is relayed.
+func (cc *CosmosProvider) Confirmations() uint64 {
+	return cc.PCfg.Confirmations
+}
+
 func (cc *CosmosProvider) Type() string {
 	return "cosmos"
 }
@@ -127,6 +295,26 @@ func (cc *CosmosProvider) Key() string {
 	return cc.PCfg.Key
 }
 
+// UseKey signs the transactions sent to the chain with the given key of the keyring from now on,
+// once the transaction being signed, if any, has been sent. The key of the config file is left unchanged.
+func (cc *CosmosProvider) UseKey(ctx context.Context, name string) error {
+	if !cc.KeyExists(name) {
+		return fmt.Errorf("key %s not found on chain %s", name, cc.PCfg.ChainID)
+	}
+	return cc.signing.Do(ctx, func() error {
+		cc.setKey(name)
+		return nil
+	})
+}
+
+// setKey sets the key the transactions are signed with, which must be called from the signing scheduler.
+func (cc *CosmosProvider) setKey(name string) {
+	cc.PCfg.Key = name
+	if cc.Config != nil {
+		cc.Config.Key = name
+	}
+}
+
 func (cc *CosmosProvider) Timeout() string {
 	return cc.PCfg.Timeout
 }
@@ -148,6 +336,11 @@ func (cc *CosmosProvider) AddKey(name string, coinType uint32) (*provider.KeyOut
 	}, nil
 }
 
+// SignBytes signs msg with the configured key of the chain, returning the signature and the public key verifying it.
+func (cc *CosmosProvider) SignBytes(msg []byte) ([]byte, cryptotypes.PubKey, error) {
+	return cc.Keybase.Sign(cc.PCfg.Key, msg)
+}
+
 // Address returns the chains configured address as a string
 func (cc *CosmosProvider) Address() (string, error) {
 	var (
@@ -193,6 +386,19 @@ func (cc *CosmosProvider) Sprint(toPrint proto.Message) (string, error) {
 	return string(out), nil
 }
 
+// VerifyChainID returns an error wrapping provider.ErrChainIDMismatch if the RPC endpoint does not serve the configured chain.
+func (cc *CosmosProvider) VerifyChainID(ctx context.Context) error {
+	stat, err := cc.RPCClient.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query status of %s: %w", cc.PCfg.RPCAddr, err)
+	}
+	if stat.NodeInfo.Network != cc.PCfg.ChainID {
+		return fmt.Errorf("%w: rpc endpoint %s serves chain %s, expected %s",
+			provider.ErrChainIDMismatch, cc.PCfg.RPCAddr, stat.NodeInfo.Network, cc.PCfg.ChainID)
+	}
+	return nil
+}
+
 // WaitForNBlocks blocks until the next block on a given chain
 func (cc *CosmosProvider) WaitForNBlocks(ctx context.Context, n int64) error {
 	var initial int64
@@ -325,6 +325,15 @@ func (cc *CosmosProvider) QueryUpgradeProof(ctx context.Context, key []byte, hei
 }
 
 // QueryUpgradedClient returns upgraded client info
+// QueryUpgradePlan returns the software upgrade scheduled on the chain, nil if none is.
+func (cc *CosmosProvider) QueryUpgradePlan(ctx context.Context) (*upgradetypes.Plan, error) {
+	res, err := upgradetypes.NewQueryClient(cc).CurrentPlan(ctx, &upgradetypes.QueryCurrentPlanRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return res.Plan, nil
+}
+
 func (cc *CosmosProvider) QueryUpgradedClient(ctx context.Context, height int64) (*clienttypes.QueryClientStateResponse, error) {
 	req := clienttypes.QueryUpgradedClientStateRequest{}
 
@@ -404,6 +413,22 @@ func (cc *CosmosProvider) QueryConsensusState(ctx context.Context, height int64)
 	return state, height, nil
 }
 
+// QueryConsensusStateCount returns the number of consensus states stored for a client.
+func (cc *CosmosProvider) QueryConsensusStateCount(ctx context.Context, clientID string) (uint64, error) {
+	qc := clienttypes.NewQueryClient(cc)
+	res, err := qc.ConsensusStates(ctx, &clienttypes.QueryConsensusStatesRequest{
+		ClientId: clientID,
+		Pagination: &querytypes.PageRequest{
+			Limit:      1,
+			CountTotal: true,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.Pagination.Total, nil
+}
+
 // QueryClients queries all the clients!
 func (cc *CosmosProvider) QueryClients(ctx context.Context) (clienttypes.IdentifiedClientStates, error) {
 	qc := clienttypes.NewQueryClient(cc)
@@ -673,9 +698,21 @@ func (cc *CosmosProvider) QueryChannels(ctx context.Context) ([]*chantypes.Ident
 
 // QueryPacketCommitments returns an array of packet commitments
 func (cc *CosmosProvider) QueryPacketCommitments(ctx context.Context, height uint64, channelid, portid string) (commitments []*chantypes.PacketState, err error) {
+	total := []*chantypes.PacketState{}
+	if err := cc.QueryPacketCommitmentPages(ctx, height, channelid, portid, func(page []*chantypes.PacketState) error {
+		total = append(total, page...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return total, nil
+}
+
+// QueryPacketCommitmentPages passes the packet commitments of the channel to fn a page at a time, as they are queried,
+// stopping at the first error fn returns.
+func (cc *CosmosProvider) QueryPacketCommitmentPages(ctx context.Context, height uint64, channelid, portid string, fn func(page []*chantypes.PacketState) error) error {
 	qc := chantypes.NewQueryClient(cc)
 	ctxWithHeight := lens.SetHeightOnContext(ctx, int64(height))
-	total := []*chantypes.PacketState{}
 	pagination := DefaultPageRequest()
 
 	for {
@@ -685,26 +722,40 @@ func (cc *CosmosProvider) QueryPacketCommitments(ctx context.Context, height uin
 			Pagination: pagination,
 		})
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := fn(res.Commitments); err != nil {
+			return err
 		}
-		total = append(total, res.Commitments...)
 		if len(res.Pagination.NextKey) == 0 {
-			break
+			return nil
 		}
 		pagination = DefaultPageRequest()
 		pagination.Key = res.Pagination.NextKey
 	}
-	return total, nil
 }
 
-// QueryPacketAcknowledgements returns an array of packet acks
-func (cc *CosmosProvider) QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string, onlyLatest bool) (acknowledgements []*chantypes.PacketState, totalAcks uint64, err error) {
+// QueryPacketAcknowledgements returns the packet acks written for the given sequences, or all of them if seqs is empty.
+func (cc *CosmosProvider) QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) (acknowledgements []*chantypes.PacketState, err error) {
 	qc := chantypes.NewQueryClient(cc)
 	ctxWithHeight := lens.SetHeightOnContext(ctx, int64(height))
+	if len(seqs) > 0 {
+		res, err := qc.PacketAcknowledgements(ctxWithHeight, &chantypes.QueryPacketAcknowledgementsRequest{
+			PortId:                    portid,
+			ChannelId:                 channelid,
+			PacketCommitmentSequences: seqs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		// Chains predating the query by sequences answer with the first page of all acks instead.
+		if res.Pagination == nil {
+			return res.Acknowledgements, nil
+		}
+	}
+
 	total := []*chantypes.PacketState{}
 	pagination := DefaultPageRequest()
-	pagination.Reverse = true
-
 	for {
 		res, err := qc.PacketAcknowledgements(ctxWithHeight, &chantypes.QueryPacketAcknowledgementsRequest{
 			PortId:     portid,
@@ -712,23 +763,30 @@ func (cc *CosmosProvider) QueryPacketAcknowledgements(ctx context.Context, heigh
 			Pagination: pagination,
 		})
 		if err != nil {
-			return nil, 0, err
-		}
-		if totalAcks == 0 {
-			totalAcks = res.Pagination.Total
+			return nil, err
 		}
 		total = append(total, res.Acknowledgements...)
-		if onlyLatest {
-			break
-		}
 		if len(res.Pagination.NextKey) == 0 {
 			break
 		}
 		pagination = DefaultPageRequest()
 		pagination.Key = res.Pagination.NextKey
-		pagination.Reverse = true
 	}
-	return total, totalAcks, nil
+	if len(seqs) == 0 {
+		return total, nil
+	}
+
+	wanted := make(map[uint64]bool, len(seqs))
+	for _, seq := range seqs {
+		wanted[seq] = true
+	}
+	acknowledgements = []*chantypes.PacketState{}
+	for _, ack := range total {
+		if wanted[ack.Sequence] {
+			acknowledgements = append(acknowledgements, ack)
+		}
+	}
+	return acknowledgements, nil
 }
 
 // QueryUnreceivedPackets returns a list of unrelayed packet commitments
@@ -865,7 +923,7 @@ func (cc *CosmosProvider) QueryLatestHeight(ctx context.Context) (srch int64, er
 	if cc.ClientType() == ibcexported.Furyint {
 		eg.Go(func() error {
 			var err error
-			srcFinalizedStateH, err = GetLatestFinalizedStateHeight(egCtx, cc.ChainId())
+			srcFinalizedStateH, err = GetLatestFinalizedStateHeight(egCtx, cc.settlement, cc.ChainId())
 			return err
 		})
 	}
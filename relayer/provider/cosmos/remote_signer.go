@@ -0,0 +1,488 @@
+package cosmos
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// Defaults of a RemoteSignerConfig.
+const (
+	defaultRemoteSignerTimeout             = 5 * time.Second
+	defaultRemoteSignerMaxAttempts         = 5
+	defaultRemoteSignerHealthCheckInterval = 30 * time.Second
+	remoteSignerRetryDelay                 = time.Second
+)
+
+// Methods of the remote signer protocol.
+const (
+	remoteSignerPubKey = "pubkey"
+	remoteSignerSign   = "sign"
+)
+
+// RemoteSignerConfig signs the transactions sent to the chain with an external signer, such as a KMS, instead of
+// the local keyring, so that the private key never lives on the relayer host. The key of the signer is known to the
+// relayer as KeyName, its address being derived from the public key the signer serves.
+//
+// The signer is reached at Addr, either unix:///path/to/socket or tcp://host:port, and speaks the protocol of
+// ServeRemoteSigner: a JSON request per connection, answered by a JSON response. External signers such as TMKMS
+// or HashiCorp Vault are plugged in through an adapter implementing the protocol.
+type RemoteSignerConfig struct {
+	Addr string `json:"addr" yaml:"addr"`
+
+	// KeyName is the name of the key of the signer on the relayer, the key of the chain by default.
+	KeyName string `json:"key-name,omitempty" yaml:"key-name,omitempty"`
+
+	// KeyID is the key the signer signs with, KeyName by default.
+	KeyID string `json:"key-id,omitempty" yaml:"key-id,omitempty"`
+
+	// Timeout bounds each request to the signer, 5s by default.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// MaxAttempts is how many times a request is sent to an unavailable signer before giving up, 5 by default.
+	MaxAttempts uint `json:"max-attempts,omitempty" yaml:"max-attempts,omitempty"`
+
+	// HealthCheckInterval is how often the signer is checked to be reachable and serving the same key, 30s by default.
+	HealthCheckInterval string `json:"health-check-interval,omitempty" yaml:"health-check-interval,omitempty"`
+}
+
+// Validate validates the remote signer config, nil meaning transactions are signed with the local keyring.
+func (c *RemoteSignerConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if _, _, err := c.endpoint(); err != nil {
+		return err
+	}
+	for _, d := range []struct{ name, value string }{
+		{"timeout", c.Timeout},
+		{"health-check-interval", c.HealthCheckInterval},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d.value); err != nil || v <= 0 {
+			return fmt.Errorf("invalid remote signer %s %q", d.name, d.value)
+		}
+	}
+	return nil
+}
+
+// endpoint returns the network and address to dial the signer at.
+func (c *RemoteSignerConfig) endpoint() (network, address string, err error) {
+	u, err := url.Parse(c.Addr)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid remote signer addr %q: %w", c.Addr, err)
+	}
+	switch u.Scheme {
+	case "unix":
+		return "unix", u.Host + u.Path, nil
+	case "tcp":
+		return "tcp", u.Host, nil
+	}
+	return "", "", fmt.Errorf("invalid remote signer addr %q, expected unix:///path or tcp://host:port", c.Addr)
+}
+
+func (c *RemoteSignerConfig) timeout() time.Duration {
+	return healthCheckDuration(c.Timeout, defaultRemoteSignerTimeout)
+}
+
+func (c *RemoteSignerConfig) maxAttempts() uint {
+	if c.MaxAttempts == 0 {
+		return defaultRemoteSignerMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+func (c *RemoteSignerConfig) healthCheckInterval() time.Duration {
+	return healthCheckDuration(c.HealthCheckInterval, defaultRemoteSignerHealthCheckInterval)
+}
+
+// remoteSignerRequest asks the signer for the public key of KeyID, or to sign SignBytes with it for ChainID.
+type remoteSignerRequest struct {
+	Method    string `json:"method"`
+	KeyID     string `json:"key_id"`
+	ChainID   string `json:"chain_id"`
+	SignBytes []byte `json:"sign_bytes,omitempty"`
+}
+
+// remoteSignerResponse is the answer of the signer, PubKey being the JSON encoding of the public key of the key,
+// as in {"@type":"/cosmos.crypto.secp256k1.PubKey","key":"..."}, returned along with each signature.
+type remoteSignerResponse struct {
+	PubKey    json.RawMessage `json:"pub_key,omitempty"`
+	Signature []byte          `json:"signature,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// remoteSigner is the client of the remote signer of a chain.
+type remoteSigner struct {
+	log     *zap.Logger
+	chainID string
+	cfg     RemoteSignerConfig
+	cdc     codec.Codec
+
+	mu     sync.Mutex
+	pubKey cryptotypes.PubKey
+	health provider.RemoteSignerHealth
+}
+
+func newRemoteSigner(log *zap.Logger, chainID string, cfg RemoteSignerConfig, cdc codec.Codec) *remoteSigner {
+	return &remoteSigner{
+		log:     log,
+		chainID: chainID,
+		cfg:     cfg,
+		cdc:     cdc,
+		health:  provider.RemoteSignerHealth{ChainID: chainID, Address: cfg.Addr, KeyID: cfg.KeyID},
+	}
+}
+
+// call sends req to the signer once.
+func (s *remoteSigner) call(ctx context.Context, req remoteSignerRequest) (remoteSignerResponse, error) {
+	network, address, err := s.cfg.endpoint()
+	if err != nil {
+		return remoteSignerResponse{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.timeout())
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, address)
+	if err != nil {
+		return remoteSignerResponse{}, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return remoteSignerResponse{}, err
+	}
+	var res remoteSignerResponse
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&res); err != nil {
+		return remoteSignerResponse{}, err
+	}
+	return res, nil
+}
+
+// do sends req to the signer, retrying while it is unavailable, and returns its public key and signature, if any.
+// Requests refused by the signer are not retried.
+func (s *remoteSigner) do(ctx context.Context, req remoteSignerRequest) (pk cryptotypes.PubKey, sig []byte, err error) {
+	req.KeyID, req.ChainID = s.cfg.KeyID, s.chainID
+	err = retry.Do(func() error {
+		res, err := s.call(ctx, req)
+		if err != nil {
+			s.fail(err)
+			return err
+		}
+		if res.Error != "" {
+			return retry.Unrecoverable(fmt.Errorf("remote signer refused to %s: %s", req.Method, res.Error))
+		}
+		if err := s.cdc.UnmarshalInterfaceJSON(res.PubKey, &pk); err != nil {
+			return retry.Unrecoverable(fmt.Errorf("invalid public key from remote signer: %w", err))
+		}
+		sig = res.Signature
+		return nil
+	}, retry.Context(ctx), retry.Attempts(s.cfg.maxAttempts()), retry.Delay(remoteSignerRetryDelay), retry.LastErrorOnly(true),
+		retry.OnRetry(func(n uint, err error) {
+			s.log.Info("Remote signer unavailable, retrying", zap.String("addr", s.cfg.Addr), zap.Uint("attempt", n+1), zap.Error(err))
+		}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("remote signer %s: %w", s.cfg.Addr, err)
+	}
+	return pk, sig, nil
+}
+
+// PubKey returns the public key of the key of the signer, fetched once.
+func (s *remoteSigner) PubKey(ctx context.Context) (cryptotypes.PubKey, error) {
+	s.mu.Lock()
+	pk := s.pubKey
+	s.mu.Unlock()
+	if pk != nil {
+		return pk, nil
+	}
+	pk, _, err := s.do(ctx, remoteSignerRequest{Method: remoteSignerPubKey})
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pubKey == nil {
+		s.pubKey = pk
+	}
+	return s.pubKey, nil
+}
+
+// Sign signs msg with the key of the signer, verifying the signature against the public key first fetched,
+// so that a signer serving another key fails the transaction rather than signing it.
+func (s *remoteSigner) Sign(ctx context.Context, msg []byte) ([]byte, cryptotypes.PubKey, error) {
+	expected, err := s.PubKey(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, sig, err := s.do(ctx, remoteSignerRequest{Method: remoteSignerSign, SignBytes: msg})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !expected.VerifySignature(msg, sig) {
+		return nil, nil, fmt.Errorf("remote signer %s returned a signature not matching key %s", s.cfg.Addr, s.cfg.KeyID)
+	}
+	return sig, expected, nil
+}
+
+// fail records a failed request to the signer.
+func (s *remoteSigner) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health.Failures++
+	s.health.Healthy = false
+	s.health.Error = err.Error()
+}
+
+// check checks that the signer is reachable and serves the same key as first fetched.
+func (s *remoteSigner) check(ctx context.Context) {
+	start := time.Now()
+	res, err := s.call(ctx, remoteSignerRequest{Method: remoteSignerPubKey, KeyID: s.cfg.KeyID, ChainID: s.chainID})
+	latency := time.Since(start)
+	if err == nil && res.Error != "" {
+		err = errors.New(res.Error)
+	}
+	var pk cryptotypes.PubKey
+	if err == nil {
+		err = s.cdc.UnmarshalInterfaceJSON(res.PubKey, &pk)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil && s.pubKey != nil && !s.pubKey.Equals(pk) {
+		err = fmt.Errorf("signer now serves key %s instead of %s", sdk.AccAddress(pk.Address()), sdk.AccAddress(s.pubKey.Address()))
+	}
+	wasHealthy := s.health.Healthy || s.health.CheckedAt.IsZero()
+	s.health.CheckedAt = time.Now()
+	s.health.LatencySeconds = latency.Seconds()
+	if err != nil {
+		s.health.Failures++
+		s.health.Healthy = false
+		s.health.Error = err.Error()
+		if wasHealthy {
+			s.log.Warn("Remote signer is unhealthy", zap.String("addr", s.cfg.Addr), zap.String("key_id", s.cfg.KeyID), zap.Error(err))
+		}
+		return
+	}
+	if s.pubKey == nil {
+		s.pubKey = pk
+	}
+	if !wasHealthy {
+		s.log.Info("Remote signer is healthy again", zap.String("addr", s.cfg.Addr), zap.String("key_id", s.cfg.KeyID))
+	}
+	s.health.Healthy = true
+	s.health.Error = ""
+}
+
+// run checks the signer every health check interval until ctx is done.
+func (s *remoteSigner) run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.healthCheckInterval())
+	defer ticker.Stop()
+	for {
+		s.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *remoteSigner) snapshot() provider.RemoteSignerHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.health
+}
+
+// remoteSignerKeyring serves the key of the remote signer under its key name, and all other keys from the local keyring.
+type remoteSignerKeyring struct {
+	keyring.Keyring
+	name   string
+	signer *remoteSigner
+}
+
+// remoteKeyInfo is the key of a remote signer, seen by the relayer as an offline key.
+type remoteKeyInfo struct {
+	name   string
+	pubKey cryptotypes.PubKey
+}
+
+func (i remoteKeyInfo) GetType() keyring.KeyType      { return keyring.TypeOffline }
+func (i remoteKeyInfo) GetName() string               { return i.name }
+func (i remoteKeyInfo) GetPubKey() cryptotypes.PubKey { return i.pubKey }
+func (i remoteKeyInfo) GetAddress() sdk.AccAddress    { return i.pubKey.Address().Bytes() }
+func (i remoteKeyInfo) GetPath() (*hd.BIP44Params, error) {
+	return nil, fmt.Errorf("BIP44 Paths are not available for remote keys")
+}
+func (i remoteKeyInfo) GetAlgo() hd.PubKeyType { return hd.PubKeyType(i.pubKey.Type()) }
+
+func (k *remoteSignerKeyring) info() (keyring.Info, error) {
+	pk, err := k.signer.PubKey(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return remoteKeyInfo{name: k.name, pubKey: pk}, nil
+}
+
+func (k *remoteSignerKeyring) List() ([]keyring.Info, error) {
+	infos, err := k.Keyring.List()
+	if err != nil {
+		return nil, err
+	}
+	info, err := k.info()
+	if err != nil {
+		return nil, err
+	}
+	return append(infos, info), nil
+}
+
+func (k *remoteSignerKeyring) Key(uid string) (keyring.Info, error) {
+	if uid == k.name {
+		return k.info()
+	}
+	return k.Keyring.Key(uid)
+}
+
+func (k *remoteSignerKeyring) KeyByAddress(address sdk.Address) (keyring.Info, error) {
+	if info, err := k.info(); err == nil && info.GetAddress().Equals(address) {
+		return info, nil
+	}
+	return k.Keyring.KeyByAddress(address)
+}
+
+func (k *remoteSignerKeyring) Sign(uid string, msg []byte) ([]byte, cryptotypes.PubKey, error) {
+	if uid == k.name {
+		return k.signer.Sign(context.Background(), msg)
+	}
+	return k.Keyring.Sign(uid, msg)
+}
+
+func (k *remoteSignerKeyring) SignByAddress(address sdk.Address, msg []byte) ([]byte, cryptotypes.PubKey, error) {
+	if info, err := k.info(); err == nil && info.GetAddress().Equals(address) {
+		return k.signer.Sign(context.Background(), msg)
+	}
+	return k.Keyring.SignByAddress(address, msg)
+}
+
+func (k *remoteSignerKeyring) Delete(uid string) error {
+	if uid == k.name {
+		return fmt.Errorf("key %s is served by the remote signer and cannot be deleted", uid)
+	}
+	return k.Keyring.Delete(uid)
+}
+
+// RunRemoteSignerHealthChecks checks the remote signer of the chain until ctx is done, if one is configured.
+func (cc *CosmosProvider) RunRemoteSignerHealthChecks(ctx context.Context) {
+	if cc.remoteSigner != nil {
+		cc.remoteSigner.run(ctx)
+	}
+}
+
+// RemoteSignerHealth returns the health of the remote signer of the chain, false unless one is configured.
+func (cc *CosmosProvider) RemoteSignerHealth() (provider.RemoteSignerHealth, bool) {
+	if cc.remoteSigner == nil {
+		return provider.RemoteSignerHealth{}, false
+	}
+	return cc.remoteSigner.snapshot(), true
+}
+
+// ListenRemoteSigner listens for relayers at addr, as given in the addr of their RemoteSignerConfig.
+// A unix socket left behind by an interrupted signer is replaced.
+func ListenRemoteSigner(addr string) (net.Listener, error) {
+	network, address, err := (&RemoteSignerConfig{Addr: addr}).endpoint()
+	if err != nil {
+		return nil, err
+	}
+	if network == "unix" {
+		_ = os.Remove(address)
+	}
+	return net.Listen(network, address)
+}
+
+// ServeRemoteSigner serves the given keys of the keyring to relayers over ln, see RemoteSignerConfig,
+// until ctx is done, signing whatever the relayers send for the chain with them.
+func (cc *CosmosProvider) ServeRemoteSigner(ctx context.Context, ln net.Listener, keyNames []string) error {
+	served := make(map[string]bool, len(keyNames))
+	for _, name := range keyNames {
+		served[name] = true
+	}
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(time.Minute))
+			var (
+				req remoteSignerRequest
+				res remoteSignerResponse
+			)
+			if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+				res.Error = fmt.Sprintf("invalid request: %v", err)
+			} else if err := cc.serveRemoteSignerRequest(req, served, &res); err != nil {
+				res.Error = err.Error()
+				cc.log.Warn("Refused remote signer request", zap.String("method", req.Method), zap.String("key_id", req.KeyID), zap.Error(err))
+			}
+			_ = json.NewEncoder(conn).Encode(res)
+		}(conn)
+	}
+}
+
+func (cc *CosmosProvider) serveRemoteSignerRequest(req remoteSignerRequest, served map[string]bool, res *remoteSignerResponse) error {
+	if req.ChainID != cc.PCfg.ChainID {
+		return fmt.Errorf("chain %s is not served, only %s is", req.ChainID, cc.PCfg.ChainID)
+	}
+	if !served[req.KeyID] {
+		return fmt.Errorf("key %s is not served", req.KeyID)
+	}
+	var pk cryptotypes.PubKey
+	switch req.Method {
+	case remoteSignerPubKey:
+		info, err := cc.Keybase.Key(req.KeyID)
+		if err != nil {
+			return err
+		}
+		pk = info.GetPubKey()
+	case remoteSignerSign:
+		sig, signer, err := cc.Keybase.Sign(req.KeyID, req.SignBytes)
+		if err != nil {
+			return err
+		}
+		res.Signature, pk = sig, signer
+	default:
+		return fmt.Errorf("unknown method %q", req.Method)
+	}
+	bz, err := cc.Codec.Marshaler.MarshalInterfaceJSON(pk)
+	if err != nil {
+		return err
+	}
+	res.PubKey = bz
+	return nil
+}
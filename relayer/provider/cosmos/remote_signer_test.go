@@ -0,0 +1,112 @@
+package cosmos
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// newKeyringProvider returns a provider of chain ibc-0 with the given keys in an in-memory keyring.
+func newKeyringProvider(t *testing.T, keys ...string) *CosmosProvider {
+	kr := keyring.NewInMemory()
+	for _, name := range keys {
+		_, _, err := kr.NewMnemonic(name, keyring.English, sdk.FullFundraiserPath, "", hd.Secp256k1)
+		require.NoError(t, err)
+	}
+	return &CosmosProvider{
+		log: zaptest.NewLogger(t),
+		ChainClient: lens.ChainClient{
+			Config:  &lens.ChainClientConfig{Key: "relayer", ChainID: "ibc-0", AccountPrefix: "cosmos"},
+			Keybase: kr,
+			Codec:   lens.MakeCodec(lens.ModuleBasics),
+		},
+		PCfg: CosmosProviderConfig{Key: "relayer", ChainID: "ibc-0", AccountPrefix: "cosmos"},
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr := "unix://" + filepath.Join(t.TempDir(), "signer.sock")
+	kms := newKeyringProvider(t, "relayer", "other")
+	cc := newKeyringProvider(t)
+	cfg := RemoteSignerConfig{Addr: addr, KeyName: "relayer", KeyID: "relayer", MaxAttempts: 5}
+	require.NoError(t, cfg.Validate())
+	cc.remoteSigner = newRemoteSigner(cc.log, "ibc-0", cfg, cc.Codec.Marshaler)
+	cc.Keybase = &remoteSignerKeyring{Keyring: cc.Keybase, name: "relayer", signer: cc.remoteSigner}
+
+	// the signer becoming available while the relayer retries
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		ln, err := ListenRemoteSigner(addr)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_ = kms.ServeRemoteSigner(ctx, ln, []string{"relayer"})
+	}()
+
+	want, err := kms.Address()
+	require.NoError(t, err)
+	got, err := cc.Address()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.True(t, cc.KeyExists("relayer"))
+
+	msg := []byte("sign doc")
+	sig, pk, err := cc.Keybase.Sign("relayer", msg)
+	require.NoError(t, err)
+	require.True(t, pk.VerifySignature(msg, sig))
+
+	cc.remoteSigner.check(ctx)
+	h, ok := cc.RemoteSignerHealth()
+	require.True(t, ok)
+	require.True(t, h.Healthy)
+	require.Empty(t, h.Error)
+
+	// keys which are not served are refused rather than retried
+	other := newRemoteSigner(cc.log, "ibc-0", RemoteSignerConfig{Addr: addr, KeyID: "other"}, cc.Codec.Marshaler)
+	_, _, err = other.Sign(ctx, msg)
+	require.ErrorContains(t, err, "key other is not served")
+
+	// and so are other chains
+	wrongChain := newRemoteSigner(cc.log, "ibc-1", RemoteSignerConfig{Addr: addr, KeyID: "relayer"}, cc.Codec.Marshaler)
+	_, err = wrongChain.PubKey(ctx)
+	require.ErrorContains(t, err, "chain ibc-1 is not served")
+
+	// a signer swapping the key it serves is unhealthy
+	swapped := newRemoteSigner(cc.log, "ibc-0", RemoteSignerConfig{Addr: addr, KeyID: "relayer"}, cc.Codec.Marshaler)
+	otherInfo, err := kms.Keybase.Key("other")
+	require.NoError(t, err)
+	swapped.pubKey = otherInfo.GetPubKey()
+	swapped.check(ctx)
+	require.False(t, swapped.snapshot().Healthy)
+	require.Contains(t, swapped.snapshot().Error, "signer now serves key")
+
+	// an unavailable signer is unhealthy
+	cancel()
+	require.Eventually(t, func() bool {
+		cc.remoteSigner.check(context.Background())
+		h, _ := cc.RemoteSignerHealth()
+		return !h.Healthy && h.Failures > 0
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestRemoteSignerConfigValidate(t *testing.T) {
+	for _, addr := range []string{"unix:///run/signer.sock", "tcp://10.0.0.5:26660"} {
+		require.NoError(t, (&RemoteSignerConfig{Addr: addr}).Validate())
+	}
+	for _, addr := range []string{"", "10.0.0.5:26660", "http://signer"} {
+		require.Error(t, (&RemoteSignerConfig{Addr: addr}).Validate(), addr)
+	}
+	require.Error(t, (&RemoteSignerConfig{Addr: "tcp://signer:1", Timeout: "soon"}).Validate())
+}
@@ -0,0 +1,100 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+// maxConsecutiveBroadcastFailures is how many broadcasts in a row may fail on an endpoint
+// before it is unhealthy until its next health check.
+const maxConsecutiveBroadcastFailures = 3
+
+// broadcastFailure returns why the broadcast of a transaction to an endpoint failed in a way another endpoint
+// may not: the endpoint could not be reached or its mempool is full. It returns nil otherwise, including when
+// the transaction was rejected, which any other endpoint would do as well.
+func broadcastFailure(ctx context.Context, res *ctypes.ResultBroadcastTx, err error) error {
+	if err != nil {
+		if isEndpointError(ctx, err) || strings.Contains(err.Error(), "mempool is full") {
+			return err
+		}
+		return nil
+	}
+	if res != nil && res.Codespace == sdkerrors.ErrMempoolIsFull.Codespace() && res.Code == sdkerrors.ErrMempoolIsFull.ABCICode() {
+		return fmt.Errorf("%w: %s", sdkerrors.ErrMempoolIsFull, res.Log)
+	}
+	return nil
+}
+
+// broadcastOrder returns the indexes of the endpoints in the order broadcasts are tried in: healthy endpoints
+// first, then by fewest broadcasts failed since their last successful one, then in configured order.
+func (f *failoverClient) broadcastOrder() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	order := make([]int, len(f.endpoints))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := f.endpoints[order[i]], f.endpoints[order[j]]
+		if a.healthy != b.healthy {
+			return a.healthy
+		}
+		return a.consecutiveBroadcastFailures < b.consecutiveBroadcastFailures
+	})
+	return order
+}
+
+// recordBroadcast accounts for the outcome of a broadcast to the endpoint. An endpoint failing
+// maxConsecutiveBroadcastFailures broadcasts in a row is unhealthy until its next health check.
+func (f *failoverClient) recordBroadcast(idx int, failure error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e := f.endpoints[idx]
+	if failure == nil {
+		e.consecutiveBroadcastFailures = 0
+		return
+	}
+	e.broadcastFailures++
+	e.consecutiveBroadcastFailures++
+	e.lastErr = failure
+	f.log.Info(
+		"Failed to broadcast transaction to RPC endpoint",
+		zap.String("chain_id", f.chainID),
+		zap.String("rpc_addr", e.addr),
+		zap.Int("consecutive_failures", e.consecutiveBroadcastFailures),
+		zap.Error(failure),
+	)
+	if e.healthy && e.consecutiveBroadcastFailures >= maxConsecutiveBroadcastFailures {
+		e.healthy = false
+		f.log.Warn(
+			"RPC endpoint is unhealthy",
+			zap.String("chain_id", f.chainID),
+			zap.String("rpc_addr", e.addr),
+			zap.Error(fmt.Errorf("%d broadcasts failed in a row: %w", e.consecutiveBroadcastFailures, failure)),
+		)
+		if idx == f.active {
+			f.selectActive()
+		}
+	}
+}
+
+// BroadcastTxSync broadcasts the transaction to the endpoints in broadcast order,
+// moving on to the next one for as long as the broadcast fails because of the endpoint.
+func (f *failoverClient) BroadcastTxSync(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	for _, idx := range f.broadcastOrder() {
+		res, err = f.endpoints[idx].client.BroadcastTxSync(ctx, tx)
+		failure := broadcastFailure(ctx, res, err)
+		f.recordBroadcast(idx, failure)
+		if failure == nil {
+			return res, err
+		}
+	}
+	return res, err
+}
@@ -0,0 +1,53 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/require"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+func TestFailoverClientBroadcast(t *testing.T) {
+	mempoolFull := &ctypes.ResultBroadcastTx{Codespace: sdkerrors.ErrMempoolIsFull.Codespace(), Code: sdkerrors.ErrMempoolIsFull.ABCICode()}
+	primary := &fakeRPCClient{network: "chain-a", broadcastRes: mempoolFull}
+	backup := &fakeRPCClient{network: "chain-a", broadcastErr: errors.New("connection refused")}
+	spare := &fakeRPCClient{network: "chain-a"}
+	f := newTestFailoverClient(primary, backup, spare)
+	ctx := context.Background()
+
+	res, err := f.BroadcastTxSync(ctx, []byte("tx"))
+	require.NoError(t, err)
+	require.Zero(t, res.Code)
+	require.Equal(t, []int{1, 1, 1}, []int{primary.broadcasts, backup.broadcasts, spare.broadcasts})
+
+	// Endpoints which failed since their last successful broadcast are tried last.
+	require.Equal(t, []int{2, 0, 1}, f.broadcastOrder())
+	health := f.health()
+	require.Equal(t, uint64(1), health[0].BroadcastFailures)
+	require.Equal(t, uint64(1), health[1].BroadcastFailures)
+	require.Zero(t, health[2].BroadcastFailures)
+
+	// Transactions rejected by the node are not broadcast elsewhere.
+	spare.broadcastErr = fmt.Errorf("response error: %w", &rpctypes.RPCError{Code: -32603, Message: "tx already exists in cache"})
+	_, err = f.BroadcastTxSync(ctx, []byte("tx"))
+	require.Error(t, err)
+	require.Equal(t, 2, spare.broadcasts)
+	require.Equal(t, 1, primary.broadcasts)
+
+	// An endpoint failing maxConsecutiveBroadcastFailures broadcasts in a row is failed over from.
+	spare.broadcastErr = errors.New("connection refused")
+	for i := 0; i < maxConsecutiveBroadcastFailures; i++ {
+		_, _ = f.BroadcastTxSync(ctx, []byte("tx"))
+	}
+	require.False(t, f.health()[0].Healthy)
+	require.NotEqual(t, 0, f.active)
+
+	// A successful broadcast resets the consecutive failures of an endpoint.
+	f.recordBroadcast(0, nil)
+	require.Zero(t, f.endpoints[0].consecutiveBroadcastFailures)
+}
@@ -0,0 +1,468 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+// Defaults of an RPCHealthCheck.
+const (
+	defaultRPCHealthCheckInterval = 15 * time.Second
+	defaultRPCMaxLag              = 5
+	defaultRPCStallTimeout        = time.Minute
+	defaultRPCMaxLatency          = 5 * time.Second
+)
+
+// RPCHealthCheck configures how the RPC endpoints of a chain are checked when rpc-addrs are configured.
+// Unset fields keep the defaults of the relayer. Durations are strings such as "15s" or "1m".
+type RPCHealthCheck struct {
+	// Interval is how often every endpoint is checked.
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"`
+
+	// MaxLag is how many blocks an endpoint may be behind the highest endpoint before it is unhealthy.
+	MaxLag uint64 `json:"max-lag,omitempty" yaml:"max-lag,omitempty"`
+
+	// StallTimeout is how long the latest height of an endpoint may not progress before it is unhealthy.
+	StallTimeout string `json:"stall-timeout,omitempty" yaml:"stall-timeout,omitempty"`
+
+	// MaxLatency is how long an endpoint may take to report its status before it is unhealthy.
+	MaxLatency string `json:"max-latency,omitempty" yaml:"max-latency,omitempty"`
+}
+
+// Validate returns an error if any field of the health check is invalid.
+func (hc *RPCHealthCheck) Validate() error {
+	if hc == nil {
+		return nil
+	}
+	for _, d := range []struct{ name, value string }{
+		{"interval", hc.Interval},
+		{"stall-timeout", hc.StallTimeout},
+		{"max-latency", hc.MaxLatency},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d.value); err != nil || v <= 0 {
+			return fmt.Errorf("invalid rpc health check %s %q", d.name, d.value)
+		}
+	}
+	return nil
+}
+
+func healthCheckDuration(value string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	return def
+}
+
+func (hc *RPCHealthCheck) interval() time.Duration {
+	if hc == nil {
+		return defaultRPCHealthCheckInterval
+	}
+	return healthCheckDuration(hc.Interval, defaultRPCHealthCheckInterval)
+}
+
+func (hc *RPCHealthCheck) maxLag() int64 {
+	if hc == nil || hc.MaxLag == 0 {
+		return defaultRPCMaxLag
+	}
+	return int64(hc.MaxLag)
+}
+
+func (hc *RPCHealthCheck) stallTimeout() time.Duration {
+	if hc == nil {
+		return defaultRPCStallTimeout
+	}
+	return healthCheckDuration(hc.StallTimeout, defaultRPCStallTimeout)
+}
+
+func (hc *RPCHealthCheck) maxLatency() time.Duration {
+	if hc == nil {
+		return defaultRPCMaxLatency
+	}
+	return healthCheckDuration(hc.MaxLatency, defaultRPCMaxLatency)
+}
+
+// rpcEndpoint is an RPC endpoint of a chain, along with its health as last checked.
+type rpcEndpoint struct {
+	addr   string
+	client rpcclient.Client
+
+	// The fields below are guarded by the mutex of the failoverClient.
+	healthy         bool
+	height          int64
+	heightChangedAt time.Time
+	latency         time.Duration
+	lastErr         error
+	checkedAt       time.Time
+	failures        uint64
+
+	// broadcastFailures counts the broadcasts that failed on the endpoint, consecutiveBroadcastFailures those since
+	// its last successful broadcast, which rank it behind the other endpoints for broadcasts.
+	broadcastFailures            uint64
+	consecutiveBroadcastFailures int
+}
+
+// failoverClient sends the RPC requests of a chain to the first healthy of its endpoints, in configured order,
+// and fails over to the next one when an endpoint cannot be reached.
+//
+// The embedded client is that of the first endpoint, which serves the service lifecycle and event subscriptions.
+type failoverClient struct {
+	rpcclient.Client
+
+	log     *zap.Logger
+	chainID string
+	check   *RPCHealthCheck
+
+	mu        sync.Mutex
+	endpoints []*rpcEndpoint
+	active    int
+}
+
+func newFailoverClient(log *zap.Logger, chainID string, check *RPCHealthCheck, endpoints []*rpcEndpoint) *failoverClient {
+	now := time.Now()
+	for _, e := range endpoints {
+		// Endpoints are assumed healthy until checked.
+		e.healthy = true
+		e.heightChangedAt = now
+	}
+	return &failoverClient{
+		Client:    endpoints[0].client,
+		log:       log,
+		chainID:   chainID,
+		check:     check,
+		endpoints: endpoints,
+	}
+}
+
+// isEndpointError reports whether err prevented the endpoint from answering the request,
+// as opposed to an error returned by the node, which any other endpoint would return as well.
+func isEndpointError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var rpcErr *rpctypes.RPCError
+	return !errors.As(err, &rpcErr)
+}
+
+// do calls fn with the client of the active endpoint, failing over to the next healthy endpoint,
+// in configured order, for as long as fn fails because the endpoint could not answer.
+func (f *failoverClient) do(ctx context.Context, fn func(rpcclient.Client) error) error {
+	f.mu.Lock()
+	first := f.active
+	f.mu.Unlock()
+
+	var err error
+	for i := 0; i < len(f.endpoints); i++ {
+		idx := (first + i) % len(f.endpoints)
+		e := f.endpoints[idx]
+
+		f.mu.Lock()
+		skip := i > 0 && !e.healthy
+		f.mu.Unlock()
+		if skip {
+			continue
+		}
+
+		err = fn(e.client)
+		if !isEndpointError(ctx, err) {
+			return err
+		}
+		f.markFailed(idx, err)
+	}
+	return err
+}
+
+// markFailed marks the endpoint unhealthy until its next successful check, and fails over to the next healthy one.
+func (f *failoverClient) markFailed(idx int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e := f.endpoints[idx]
+	e.failures++
+	e.lastErr = err
+	if !e.healthy {
+		return
+	}
+	e.healthy = false
+	f.log.Warn(
+		"RPC endpoint failed",
+		zap.String("chain_id", f.chainID),
+		zap.String("rpc_addr", e.addr),
+		zap.Error(err),
+	)
+	if idx == f.active {
+		f.selectActive()
+	}
+}
+
+// selectActive makes the first healthy endpoint in configured order the active one,
+// keeping the current one when none is healthy. f.mu must be held.
+func (f *failoverClient) selectActive() {
+	for i, e := range f.endpoints {
+		if !e.healthy {
+			continue
+		}
+		if i != f.active {
+			f.log.Info(
+				"Switched RPC endpoint",
+				zap.String("chain_id", f.chainID),
+				zap.String("from", f.endpoints[f.active].addr),
+				zap.String("to", e.addr),
+			)
+			f.active = i
+		}
+		return
+	}
+}
+
+// run checks the health of every endpoint at the configured interval, until ctx is done.
+func (f *failoverClient) run(ctx context.Context) {
+	ticker := time.NewTicker(f.check.interval())
+	defer ticker.Stop()
+	for {
+		f.checkHealth(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+type endpointStatus struct {
+	height  int64
+	latency time.Duration
+	err     error
+}
+
+// checkHealth queries the status of every endpoint, and fails over from the active endpoint if it is unhealthy:
+// unreachable, catching up, serving another chain, slower than the max latency, lagging behind the highest endpoint
+// by more than the max lag, or stuck at the same height for longer than the stall timeout.
+func (f *failoverClient) checkHealth(ctx context.Context) {
+	statuses := make([]endpointStatus, len(f.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range f.endpoints {
+		wg.Add(1)
+		go func(i int, e *rpcEndpoint) {
+			defer wg.Done()
+			statuses[i] = f.queryStatus(ctx, e)
+		}(i, e)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+	f.recordHealth(statuses, time.Now())
+}
+
+func (f *failoverClient) queryStatus(ctx context.Context, e *rpcEndpoint) endpointStatus {
+	maxLatency := f.check.maxLatency()
+	ctx, cancel := context.WithTimeout(ctx, maxLatency)
+	defer cancel()
+
+	start := time.Now()
+	stat, err := e.client.Status(ctx)
+	s := endpointStatus{latency: time.Since(start)}
+	switch {
+	case err != nil:
+		s.err = err
+	case stat.NodeInfo.Network != f.chainID:
+		s.err = fmt.Errorf("%w: rpc endpoint serves chain %s, expected %s", provider.ErrChainIDMismatch, stat.NodeInfo.Network, f.chainID)
+	case stat.SyncInfo.CatchingUp:
+		s.err = fmt.Errorf("node is catching up")
+	default:
+		s.height = stat.SyncInfo.LatestBlockHeight
+	}
+	if s.err == nil && s.latency > maxLatency {
+		s.err = fmt.Errorf("latency %s exceeds %s", s.latency, maxLatency)
+	}
+	return s
+}
+
+func (f *failoverClient) recordHealth(statuses []endpointStatus, now time.Time) {
+	var maxHeight int64
+	for _, s := range statuses {
+		if s.err == nil && s.height > maxHeight {
+			maxHeight = s.height
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, s := range statuses {
+		e := f.endpoints[i]
+		e.checkedAt = now
+		e.latency = s.latency
+		if s.err == nil && s.height != e.height {
+			e.height = s.height
+			e.heightChangedAt = now
+		}
+
+		err := s.err
+		switch {
+		case err != nil:
+		case maxHeight-e.height > f.check.maxLag():
+			err = fmt.Errorf("height %d lags %d blocks behind %d", e.height, maxHeight-e.height, maxHeight)
+		case now.Sub(e.heightChangedAt) > f.check.stallTimeout():
+			err = fmt.Errorf("height %d has not progressed for %s", e.height, now.Sub(e.heightChangedAt).Truncate(time.Second))
+		}
+
+		healthy := err == nil
+		if e.healthy && !healthy {
+			f.log.Warn(
+				"RPC endpoint is unhealthy",
+				zap.String("chain_id", f.chainID),
+				zap.String("rpc_addr", e.addr),
+				zap.Error(err),
+			)
+		} else if !e.healthy && healthy {
+			f.log.Info(
+				"RPC endpoint is healthy again",
+				zap.String("chain_id", f.chainID),
+				zap.String("rpc_addr", e.addr),
+			)
+		}
+		e.healthy = healthy
+		e.lastErr = err
+	}
+	f.selectActive()
+}
+
+// health returns the health of every endpoint, in configured order.
+func (f *failoverClient) health() []provider.RPCEndpointHealth {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := make([]provider.RPCEndpointHealth, len(f.endpoints))
+	for i, e := range f.endpoints {
+		h := provider.RPCEndpointHealth{
+			ChainID:           f.chainID,
+			Address:           e.addr,
+			Active:            i == f.active,
+			Healthy:           e.healthy,
+			Height:            e.height,
+			LatencySeconds:    e.latency.Seconds(),
+			Failures:          e.failures,
+			BroadcastFailures: e.broadcastFailures,
+			CheckedAt:         e.checkedAt,
+		}
+		if e.lastErr != nil {
+			h.Error = e.lastErr.Error()
+		}
+		res[i] = h
+	}
+	return res
+}
+
+// RunRPCHealthChecks checks the health of the RPC endpoints of the chain until ctx is done, failing over from
+// unhealthy endpoints. It returns immediately unless rpc-addrs are configured.
+func (cc *CosmosProvider) RunRPCHealthChecks(ctx context.Context) {
+	if f := cc.failover(); f != nil {
+		f.run(ctx)
+	}
+}
+
+// RPCEndpointHealth returns the health of the RPC endpoints of the chain, nil unless rpc-addrs are configured.
+func (cc *CosmosProvider) RPCEndpointHealth() []provider.RPCEndpointHealth {
+	if f := cc.failover(); f != nil {
+		return f.health()
+	}
+	return nil
+}
+
+func (f *failoverClient) ABCIInfo(ctx context.Context) (res *ctypes.ResultABCIInfo, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.ABCIInfo(ctx); return })
+	return
+}
+
+func (f *failoverClient) ABCIQuery(ctx context.Context, path string, data bytes.HexBytes) (res *ctypes.ResultABCIQuery, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.ABCIQuery(ctx, path, data); return })
+	return
+}
+
+func (f *failoverClient) ABCIQueryWithOptions(ctx context.Context, path string, data bytes.HexBytes, opts rpcclient.ABCIQueryOptions) (res *ctypes.ResultABCIQuery, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.ABCIQueryWithOptions(ctx, path, data, opts); return })
+	return
+}
+
+func (f *failoverClient) BroadcastTxCommit(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTxCommit, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.BroadcastTxCommit(ctx, tx); return })
+	return
+}
+
+func (f *failoverClient) BroadcastTxAsync(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.BroadcastTxAsync(ctx, tx); return })
+	return
+}
+
+func (f *failoverClient) Block(ctx context.Context, height *int64) (res *ctypes.ResultBlock, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.Block(ctx, height); return })
+	return
+}
+
+func (f *failoverClient) BlockByHash(ctx context.Context, hash []byte) (res *ctypes.ResultBlock, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.BlockByHash(ctx, hash); return })
+	return
+}
+
+func (f *failoverClient) BlockResults(ctx context.Context, height *int64) (res *ctypes.ResultBlockResults, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.BlockResults(ctx, height); return })
+	return
+}
+
+func (f *failoverClient) Commit(ctx context.Context, height *int64) (res *ctypes.ResultCommit, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.Commit(ctx, height); return })
+	return
+}
+
+func (f *failoverClient) Validators(ctx context.Context, height *int64, page, perPage *int) (res *ctypes.ResultValidators, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.Validators(ctx, height, page, perPage); return })
+	return
+}
+
+func (f *failoverClient) Tx(ctx context.Context, hash []byte, prove bool) (res *ctypes.ResultTx, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.Tx(ctx, hash, prove); return })
+	return
+}
+
+func (f *failoverClient) TxSearch(ctx context.Context, query string, prove bool, page, perPage *int, orderBy string) (res *ctypes.ResultTxSearch, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) {
+		res, err = c.TxSearch(ctx, query, prove, page, perPage, orderBy)
+		return
+	})
+	return
+}
+
+func (f *failoverClient) BlockSearch(ctx context.Context, query string, page, perPage *int, orderBy string) (res *ctypes.ResultBlockSearch, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) {
+		res, err = c.BlockSearch(ctx, query, page, perPage, orderBy)
+		return
+	})
+	return
+}
+
+func (f *failoverClient) Status(ctx context.Context) (res *ctypes.ResultStatus, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.Status(ctx); return })
+	return
+}
+
+func (f *failoverClient) ConsensusParams(ctx context.Context, height *int64) (res *ctypes.ResultConsensusParams, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.ConsensusParams(ctx, height); return })
+	return
+}
+
+func (f *failoverClient) CheckTx(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultCheckTx, err error) {
+	err = f.do(ctx, func(c rpcclient.Client) (err error) { res, err = c.CheckTx(ctx, tx); return })
+	return
+}
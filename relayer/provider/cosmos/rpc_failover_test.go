@@ -0,0 +1,134 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/p2p"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+type fakeRPCClient struct {
+	rpcclient.Client
+
+	network string
+	height  int64
+	err     error
+
+	broadcastRes *ctypes.ResultBroadcastTx
+	broadcastErr error
+	broadcasts   int
+}
+
+func (c *fakeRPCClient) BroadcastTxSync(context.Context, tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	c.broadcasts++
+	if c.broadcastErr != nil {
+		return nil, c.broadcastErr
+	}
+	if c.broadcastRes != nil {
+		return c.broadcastRes, nil
+	}
+	return &ctypes.ResultBroadcastTx{}, nil
+}
+
+func (c *fakeRPCClient) Status(context.Context) (*ctypes.ResultStatus, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &ctypes.ResultStatus{
+		NodeInfo: p2p.DefaultNodeInfo{Network: c.network},
+		SyncInfo: ctypes.SyncInfo{LatestBlockHeight: c.height},
+	}, nil
+}
+
+func newTestFailoverClient(clients ...*fakeRPCClient) *failoverClient {
+	endpoints := make([]*rpcEndpoint, len(clients))
+	for i, c := range clients {
+		endpoints[i] = &rpcEndpoint{addr: fmt.Sprintf("http://node-%d:26657", i), client: c}
+	}
+	return newFailoverClient(zap.NewNop(), "chain-a", nil, endpoints)
+}
+
+func TestFailoverClientFailsOverOnEndpointErrors(t *testing.T) {
+	primary := &fakeRPCClient{network: "chain-a", height: 10, err: errors.New("connection refused")}
+	backup := &fakeRPCClient{network: "chain-a", height: 11}
+	f := newTestFailoverClient(primary, backup)
+	ctx := context.Background()
+
+	stat, err := f.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(11), stat.SyncInfo.LatestBlockHeight)
+	require.Equal(t, 1, f.active)
+	require.False(t, f.health()[0].Healthy)
+	require.Equal(t, uint64(1), f.health()[0].Failures)
+
+	// Errors returned by the node would be returned by any endpoint.
+	backup.err = fmt.Errorf("response error: %w", &rpctypes.RPCError{Code: -32603, Message: "tx not found"})
+	_, err = f.Status(ctx)
+	require.Error(t, err)
+	require.Equal(t, 1, f.active)
+	require.Zero(t, f.health()[1].Failures)
+}
+
+func TestFailoverClientHealthChecks(t *testing.T) {
+	primary := &fakeRPCClient{network: "chain-a", height: 100}
+	backup := &fakeRPCClient{network: "chain-a", height: 100}
+	other := &fakeRPCClient{network: "chain-b", height: 100}
+	f := newTestFailoverClient(primary, backup, other)
+	ctx := context.Background()
+	now := time.Now()
+
+	check := func(at time.Time) {
+		statuses := make([]endpointStatus, len(f.endpoints))
+		for i, e := range f.endpoints {
+			statuses[i] = f.queryStatus(ctx, e)
+		}
+		f.recordHealth(statuses, at)
+	}
+
+	check(now)
+	require.Equal(t, 0, f.active)
+	require.False(t, f.health()[2].Healthy, "endpoints serving another chain are never used")
+
+	// The primary lags behind the backup by more than the max lag.
+	backup.height = 100 + defaultRPCMaxLag + 1
+	check(now.Add(time.Second))
+	require.Equal(t, 1, f.active)
+	require.Contains(t, f.health()[0].Error, "lags")
+
+	// The backup stalls while the primary catches up.
+	primary.height = backup.height
+	check(now.Add(defaultRPCStallTimeout))
+	require.Equal(t, 0, f.active)
+	require.True(t, f.health()[1].Healthy, "the backup is not stalled until the stall timeout elapsed")
+	primary.height++
+	check(now.Add(defaultRPCStallTimeout + 2*time.Second))
+	require.Equal(t, 0, f.active)
+	require.False(t, f.health()[1].Healthy)
+	require.Contains(t, f.health()[1].Error, "not progressed")
+
+	// The active endpoint is kept when no endpoint is healthy.
+	primary.err = errors.New("connection refused")
+	check(now.Add(defaultRPCStallTimeout + 3*time.Second))
+	require.Equal(t, 0, f.active)
+}
+
+func TestRPCHealthCheckValidate(t *testing.T) {
+	require.NoError(t, (*RPCHealthCheck)(nil).Validate())
+	require.NoError(t, (&RPCHealthCheck{Interval: "10s", StallTimeout: "2m", MaxLatency: "1s", MaxLag: 3}).Validate())
+	require.Error(t, (&RPCHealthCheck{Interval: "soon"}).Validate())
+	require.Error(t, (&RPCHealthCheck{MaxLatency: "0s"}).Validate())
+
+	hc := &RPCHealthCheck{Interval: "10s"}
+	require.Equal(t, 10*time.Second, hc.interval())
+	require.Equal(t, defaultRPCStallTimeout, hc.stallTimeout())
+	require.Equal(t, int64(defaultRPCMaxLag), hc.maxLag())
+}
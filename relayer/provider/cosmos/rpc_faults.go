@@ -0,0 +1,226 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// errInjectedFault is returned in place of the RPC responses dropped by fault injection.
+var errInjectedFault = errors.New("injected fault: rpc response dropped")
+
+// rpcFaults are the faults injected into the RPC requests of a chain, shared by the clients of its endpoints.
+type rpcFaults struct {
+	mu    sync.Mutex
+	cfg   provider.FaultConfig
+	delay time.Duration
+	rnd   *rand.Rand
+}
+
+func newRPCFaults(cfg *provider.FaultConfig) (*rpcFaults, error) {
+	f := &rpcFaults{rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	if cfg != nil {
+		if err := f.set(*cfg); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (f *rpcFaults) set(cfg provider.FaultConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	delay, _ := cfg.Delay()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cfg = cfg
+	f.delay = delay
+	return nil
+}
+
+func (f *rpcFaults) config() provider.FaultConfig {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cfg
+}
+
+// dropped returns whether to drop the next response.
+func (f *rpcFaults) dropped() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cfg.DropRate > 0 && f.rnd.Float64() < f.cfg.DropRate
+}
+
+func (f *rpcFaults) broadcastDelay() time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.delay
+}
+
+func (f *rpcFaults) staleHeights() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cfg.StaleHeights
+}
+
+// faultClient injects the faults of its chain into the RPC requests sent to an endpoint.
+// Responses are dropped once the endpoint served the request, so that dropped broadcasts may still be included.
+type faultClient struct {
+	rpcclient.Client
+
+	faults *rpcFaults
+}
+
+func (c *faultClient) do(fn func() error) error {
+	if err := fn(); err != nil {
+		return err
+	}
+	if c.faults.dropped() {
+		return errInjectedFault
+	}
+	return nil
+}
+
+func (c *faultClient) broadcast(ctx context.Context, fn func() error) error {
+	if d := c.faults.broadcastDelay(); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return c.do(fn)
+}
+
+func (c *faultClient) ABCIInfo(ctx context.Context) (res *ctypes.ResultABCIInfo, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.ABCIInfo(ctx); return })
+	return
+}
+
+func (c *faultClient) ABCIQuery(ctx context.Context, path string, data bytes.HexBytes) (res *ctypes.ResultABCIQuery, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.ABCIQuery(ctx, path, data); return })
+	return
+}
+
+func (c *faultClient) ABCIQueryWithOptions(ctx context.Context, path string, data bytes.HexBytes, opts rpcclient.ABCIQueryOptions) (res *ctypes.ResultABCIQuery, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.ABCIQueryWithOptions(ctx, path, data, opts); return })
+	return
+}
+
+func (c *faultClient) BroadcastTxSync(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	err = c.broadcast(ctx, func() (err error) { res, err = c.Client.BroadcastTxSync(ctx, tx); return })
+	return
+}
+
+func (c *faultClient) BroadcastTxCommit(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTxCommit, err error) {
+	err = c.broadcast(ctx, func() (err error) { res, err = c.Client.BroadcastTxCommit(ctx, tx); return })
+	return
+}
+
+func (c *faultClient) BroadcastTxAsync(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	err = c.broadcast(ctx, func() (err error) { res, err = c.Client.BroadcastTxAsync(ctx, tx); return })
+	return
+}
+
+func (c *faultClient) Block(ctx context.Context, height *int64) (res *ctypes.ResultBlock, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.Block(ctx, height); return })
+	return
+}
+
+func (c *faultClient) BlockByHash(ctx context.Context, hash []byte) (res *ctypes.ResultBlock, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.BlockByHash(ctx, hash); return })
+	return
+}
+
+func (c *faultClient) BlockResults(ctx context.Context, height *int64) (res *ctypes.ResultBlockResults, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.BlockResults(ctx, height); return })
+	return
+}
+
+func (c *faultClient) Commit(ctx context.Context, height *int64) (res *ctypes.ResultCommit, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.Commit(ctx, height); return })
+	return
+}
+
+func (c *faultClient) Validators(ctx context.Context, height *int64, page, perPage *int) (res *ctypes.ResultValidators, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.Validators(ctx, height, page, perPage); return })
+	return
+}
+
+func (c *faultClient) Tx(ctx context.Context, hash []byte, prove bool) (res *ctypes.ResultTx, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.Tx(ctx, hash, prove); return })
+	return
+}
+
+func (c *faultClient) TxSearch(ctx context.Context, query string, prove bool, page, perPage *int, orderBy string) (res *ctypes.ResultTxSearch, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.TxSearch(ctx, query, prove, page, perPage, orderBy); return })
+	return
+}
+
+func (c *faultClient) BlockSearch(ctx context.Context, query string, page, perPage *int, orderBy string) (res *ctypes.ResultBlockSearch, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.BlockSearch(ctx, query, page, perPage, orderBy); return })
+	return
+}
+
+// Status reports the latest height of the endpoint lagging behind by the configured number of blocks.
+func (c *faultClient) Status(ctx context.Context) (res *ctypes.ResultStatus, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.Status(ctx); return })
+	if err != nil {
+		return nil, err
+	}
+	if n := c.faults.staleHeights(); n > 0 {
+		stale := *res
+		stale.SyncInfo.LatestBlockHeight -= n
+		if stale.SyncInfo.LatestBlockHeight < 1 {
+			stale.SyncInfo.LatestBlockHeight = 1
+		}
+		res = &stale
+	}
+	return res, nil
+}
+
+func (c *faultClient) ConsensusParams(ctx context.Context, height *int64) (res *ctypes.ResultConsensusParams, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.ConsensusParams(ctx, height); return })
+	return
+}
+
+func (c *faultClient) CheckTx(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultCheckTx, err error) {
+	err = c.do(func() (err error) { res, err = c.Client.CheckTx(ctx, tx); return })
+	return
+}
+
+// injectFaults wraps the client of an endpoint to inject faults, leaving it as is if faults is nil,
+// as in builds without fault injection.
+func injectFaults(faults *rpcFaults, c rpcclient.Client) rpcclient.Client {
+	if faults == nil {
+		return c
+	}
+	return &faultClient{Client: c, faults: faults}
+}
+
+// Faults returns the faults injected into the RPC requests of the chain.
+func (cc *CosmosProvider) Faults() provider.FaultConfig {
+	if cc.faults == nil {
+		return provider.FaultConfig{}
+	}
+	return cc.faults.config()
+}
+
+// SetFaults sets the faults injected into the RPC requests of the chain, clearing them if cfg is empty.
+func (cc *CosmosProvider) SetFaults(cfg provider.FaultConfig) error {
+	if cc.faults == nil {
+		return provider.ErrFaultInjectionDisabled
+	}
+	return cc.faults.set(cfg)
+}
@@ -0,0 +1,69 @@
+//go:build faultinjection
+
+package cosmos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultConfigValidate(t *testing.T) {
+	var unset *provider.FaultConfig
+	require.NoError(t, unset.Validate())
+	require.False(t, unset.Enabled())
+	require.NoError(t, (&provider.FaultConfig{DropRate: 0.5, BroadcastDelay: "2s", StaleHeights: 3}).Validate())
+	require.False(t, (&provider.FaultConfig{BroadcastDelay: "0s"}).Enabled())
+
+	require.ErrorContains(t, (&provider.FaultConfig{DropRate: 1.5}).Validate(), "drop-rate must be between 0 and 1")
+	require.ErrorContains(t, (&provider.FaultConfig{BroadcastDelay: "soon"}).Validate(), "invalid fault broadcast-delay")
+	require.ErrorContains(t, (&provider.FaultConfig{StaleHeights: -1}).Validate(), "must not be negative")
+}
+
+func TestFaultClient(t *testing.T) {
+	ctx := context.Background()
+	node := &fakeRPCClient{network: "chain-a", height: 10}
+	faults, err := newRPCFaults(nil)
+	require.NoError(t, err)
+	cc := &CosmosProvider{ChainClient: lens.ChainClient{RPCClient: injectFaults(faults, node)}, faults: faults}
+
+	// No faults are injected unless configured.
+	status, err := cc.RPCClient.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), status.SyncInfo.LatestBlockHeight)
+
+	require.NoError(t, cc.SetFaults(provider.FaultConfig{StaleHeights: 4}))
+	status, err = cc.RPCClient.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), status.SyncInfo.LatestBlockHeight)
+
+	// Dropped responses fail once the endpoint served the request.
+	require.NoError(t, cc.SetFaults(provider.FaultConfig{DropRate: 1, BroadcastDelay: "20ms"}))
+	start := time.Now()
+	_, err = cc.RPCClient.BroadcastTxSync(ctx, nil)
+	require.ErrorIs(t, err, errInjectedFault)
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	require.Equal(t, 1, node.broadcasts)
+
+	// Broadcasts are not sent once their context is done while delayed.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = cc.RPCClient.BroadcastTxSync(cancelled, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, node.broadcasts)
+
+	require.ErrorContains(t, cc.SetFaults(provider.FaultConfig{DropRate: 2}), "drop-rate")
+	require.Equal(t, "20ms", cc.Faults().BroadcastDelay)
+
+	// Clearing the faults restores the responses of the endpoint.
+	require.NoError(t, cc.SetFaults(provider.FaultConfig{}))
+	_, err = cc.RPCClient.BroadcastTxSync(ctx, nil)
+	require.NoError(t, err)
+
+	// Providers built without fault injection refuse faults.
+	require.ErrorIs(t, (&CosmosProvider{}).SetFaults(provider.FaultConfig{DropRate: 0.1}), provider.ErrFaultInjectionDisabled)
+}
@@ -0,0 +1,150 @@
+package cosmos
+
+import (
+	"context"
+
+	"github.com/cosmos/relayer/v2/relayer/tracing"
+	"github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingClient traces the RPC requests of a chain, which all the queries of the provider go through:
+// gRPC queries are spans named by their ABCI query path, e.g. /ibc.core.channel.v1.Query/Channel,
+// and other requests by their Tendermint RPC method, e.g. tendermint.TxSearch.
+//
+// The embedded client serves the service lifecycle and event subscriptions, which are not traced.
+type tracingClient struct {
+	rpcclient.Client
+
+	chainID string
+}
+
+func newTracingClient(chainID string, c rpcclient.Client) *tracingClient {
+	return &tracingClient{Client: c, chainID: chainID}
+}
+
+func (t *tracingClient) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name, append(attrs, attribute.String("chain_id", t.chainID))...)
+}
+
+func heightAttr(height *int64) attribute.KeyValue {
+	if height == nil {
+		return attribute.Int64("height", 0)
+	}
+	return attribute.Int64("height", *height)
+}
+
+func (t *tracingClient) ABCIInfo(ctx context.Context) (res *ctypes.ResultABCIInfo, err error) {
+	ctx, span := t.start(ctx, "tendermint.ABCIInfo")
+	defer func() { tracing.End(span, err) }()
+	return t.Client.ABCIInfo(ctx)
+}
+
+func (t *tracingClient) ABCIQuery(ctx context.Context, path string, data bytes.HexBytes) (res *ctypes.ResultABCIQuery, err error) {
+	ctx, span := t.start(ctx, path)
+	defer func() { tracing.End(span, err) }()
+	return t.Client.ABCIQuery(ctx, path, data)
+}
+
+func (t *tracingClient) ABCIQueryWithOptions(ctx context.Context, path string, data bytes.HexBytes, opts rpcclient.ABCIQueryOptions) (res *ctypes.ResultABCIQuery, err error) {
+	ctx, span := t.start(ctx, path, attribute.Int64("height", opts.Height), attribute.Bool("prove", opts.Prove))
+	defer func() { tracing.End(span, err) }()
+	return t.Client.ABCIQueryWithOptions(ctx, path, data, opts)
+}
+
+func (t *tracingClient) BroadcastTxSync(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	ctx, span := t.start(ctx, "tendermint.BroadcastTxSync")
+	defer func() {
+		if res != nil {
+			span.SetAttributes(attribute.String("tx_hash", res.Hash.String()), attribute.Int64("code", int64(res.Code)))
+		}
+		tracing.End(span, err)
+	}()
+	return t.Client.BroadcastTxSync(ctx, tx)
+}
+
+func (t *tracingClient) BroadcastTxCommit(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTxCommit, err error) {
+	ctx, span := t.start(ctx, "tendermint.BroadcastTxCommit")
+	defer func() { tracing.End(span, err) }()
+	return t.Client.BroadcastTxCommit(ctx, tx)
+}
+
+func (t *tracingClient) BroadcastTxAsync(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	ctx, span := t.start(ctx, "tendermint.BroadcastTxAsync")
+	defer func() { tracing.End(span, err) }()
+	return t.Client.BroadcastTxAsync(ctx, tx)
+}
+
+func (t *tracingClient) Block(ctx context.Context, height *int64) (res *ctypes.ResultBlock, err error) {
+	ctx, span := t.start(ctx, "tendermint.Block", heightAttr(height))
+	defer func() { tracing.End(span, err) }()
+	return t.Client.Block(ctx, height)
+}
+
+func (t *tracingClient) BlockResults(ctx context.Context, height *int64) (res *ctypes.ResultBlockResults, err error) {
+	ctx, span := t.start(ctx, "tendermint.BlockResults", heightAttr(height))
+	defer func() { tracing.End(span, err) }()
+	return t.Client.BlockResults(ctx, height)
+}
+
+func (t *tracingClient) Commit(ctx context.Context, height *int64) (res *ctypes.ResultCommit, err error) {
+	ctx, span := t.start(ctx, "tendermint.Commit", heightAttr(height))
+	defer func() { tracing.End(span, err) }()
+	return t.Client.Commit(ctx, height)
+}
+
+func (t *tracingClient) Validators(ctx context.Context, height *int64, page, perPage *int) (res *ctypes.ResultValidators, err error) {
+	ctx, span := t.start(ctx, "tendermint.Validators", heightAttr(height))
+	defer func() { tracing.End(span, err) }()
+	return t.Client.Validators(ctx, height, page, perPage)
+}
+
+func (t *tracingClient) Tx(ctx context.Context, hash []byte, prove bool) (res *ctypes.ResultTx, err error) {
+	ctx, span := t.start(ctx, "tendermint.Tx", attribute.String("tx_hash", bytes.HexBytes(hash).String()))
+	defer func() { tracing.End(span, err) }()
+	return t.Client.Tx(ctx, hash, prove)
+}
+
+func (t *tracingClient) TxSearch(ctx context.Context, query string, prove bool, page, perPage *int, orderBy string) (res *ctypes.ResultTxSearch, err error) {
+	ctx, span := t.start(ctx, "tendermint.TxSearch", attribute.String("query", query))
+	defer func() { tracing.End(span, err) }()
+	return t.Client.TxSearch(ctx, query, prove, page, perPage, orderBy)
+}
+
+func (t *tracingClient) BlockSearch(ctx context.Context, query string, page, perPage *int, orderBy string) (res *ctypes.ResultBlockSearch, err error) {
+	ctx, span := t.start(ctx, "tendermint.BlockSearch", attribute.String("query", query))
+	defer func() { tracing.End(span, err) }()
+	return t.Client.BlockSearch(ctx, query, page, perPage, orderBy)
+}
+
+func (t *tracingClient) Status(ctx context.Context) (res *ctypes.ResultStatus, err error) {
+	ctx, span := t.start(ctx, "tendermint.Status")
+	defer func() { tracing.End(span, err) }()
+	return t.Client.Status(ctx)
+}
+
+func (t *tracingClient) ConsensusParams(ctx context.Context, height *int64) (res *ctypes.ResultConsensusParams, err error) {
+	ctx, span := t.start(ctx, "tendermint.ConsensusParams", heightAttr(height))
+	defer func() { tracing.End(span, err) }()
+	return t.Client.ConsensusParams(ctx, height)
+}
+
+func (t *tracingClient) CheckTx(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultCheckTx, err error) {
+	ctx, span := t.start(ctx, "tendermint.CheckTx")
+	defer func() { tracing.End(span, err) }()
+	return t.Client.CheckTx(ctx, tx)
+}
+
+// failover returns the failover client of the chain, nil unless rpc-addrs are configured.
+func (cc *CosmosProvider) failover() *failoverClient {
+	c := cc.RPCClient
+	if t, ok := c.(*tracingClient); ok {
+		c = t.Client
+	}
+	f, _ := c.(*failoverClient)
+	return f
+}
@@ -0,0 +1,47 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingClient(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	healthy := &fakeRPCClient{network: "chain-a", height: 10}
+	cc := &CosmosProvider{ChainClient: lens.ChainClient{
+		RPCClient: newTracingClient("chain-a", newTestFailoverClient(healthy)),
+	}}
+
+	status, err := cc.RPCClient.Status(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(10), status.SyncInfo.LatestBlockHeight)
+
+	healthy.err = errors.New("connection refused")
+	_, err = cc.RPCClient.Status(context.Background())
+	require.Error(t, err)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	for _, s := range spans {
+		require.Equal(t, "tendermint.Status", s.Name())
+		require.Contains(t, s.Attributes(), attribute.String("chain_id", "chain-a"))
+	}
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+
+	// The endpoints behind the traced client are still reported.
+	require.Len(t, cc.RPCEndpointHealth(), 1)
+}
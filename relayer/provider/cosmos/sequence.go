@@ -0,0 +1,103 @@
+package cosmos
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	// sequenceResyncAfter is how long the sequence of an account may be tracked ahead of its sequence on chain,
+	// for transactions accepted in the mempool but not yet included, before they are presumed evicted
+	// and the sequence is resynced from the chain.
+	sequenceResyncAfter = 2 * time.Minute
+
+	// maxSequenceMismatchAttempts is how many times a transaction is rebuilt within a turn of the signing scheduler
+	// when the chain reports a sequence mismatch, before falling back to the regular retries.
+	maxSequenceMismatchAttempts = 3
+)
+
+// sequenceMismatchRegex matches the expected sequence in the account sequence mismatch errors of the ante handler.
+var sequenceMismatchRegex = regexp.MustCompile(`account sequence mismatch, expected (\d+), got \d+`)
+
+// isSequenceMismatch reports whether err is an account sequence mismatch reported by the chain.
+func isSequenceMismatch(err error) bool {
+	return err != nil && strings.Contains(err.Error(), sdkerrors.ErrWrongSequence.Error())
+}
+
+// accountSequences tracks the expected sequence of the next transaction of each signing account of a chain,
+// so that transactions pending in the mempool are accounted for while the sequence of the account on chain
+// only reflects the transactions included in blocks.
+// Transactions of an account are serialized by the signing scheduler, accountSequences only keeps its state.
+type accountSequences struct {
+	mu       sync.Mutex
+	accounts map[string]*accountSequence
+}
+
+type accountSequence struct {
+	// next is the sequence the next transaction of the account is expected to be signed with.
+	next uint64
+
+	// updatedAt is when next was last learned, from an accepted transaction or a mismatch.
+	updatedAt time.Time
+}
+
+func newAccountSequences() *accountSequences {
+	return &accountSequences{accounts: make(map[string]*accountSequence)}
+}
+
+// next returns the sequence to sign the next transaction of address with, chainSeq being the sequence
+// of the account on chain: the tracked sequence while it is ahead of the chain and recent, chainSeq otherwise.
+func (s *accountSequences) next(address string, chainSeq uint64) uint64 {
+	if s == nil {
+		return chainSeq
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[address]
+	if !ok {
+		return chainSeq
+	}
+	if chainSeq >= a.next || time.Since(a.updatedAt) > sequenceResyncAfter {
+		delete(s.accounts, address)
+		return chainSeq
+	}
+	return a.next
+}
+
+// accepted records that a transaction of address signed with seq was accepted in the mempool.
+func (s *accountSequences) accepted(address string, seq uint64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[address] = &accountSequence{next: seq + 1, updatedAt: time.Now()}
+}
+
+// mismatch records the sequence mismatch err reported by the chain for a transaction of address, tracking
+// the sequence the chain expected if err tells it, resyncing from the chain otherwise. It returns the expected
+// sequence, if known.
+func (s *accountSequences) mismatch(address string, err error) (uint64, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := sequenceMismatchRegex.FindStringSubmatch(err.Error())
+	if m == nil {
+		delete(s.accounts, address)
+		return 0, false
+	}
+	expected, perr := strconv.ParseUint(m[1], 10, 64)
+	if perr != nil {
+		delete(s.accounts, address)
+		return 0, false
+	}
+	s.accounts[address] = &accountSequence{next: expected, updatedAt: time.Now()}
+	return expected, true
+}
@@ -0,0 +1,50 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountSequences(t *testing.T) {
+	s := newAccountSequences()
+	const addr = "cosmos1relayer"
+
+	// untracked accounts use the sequence of the chain
+	require.Equal(t, uint64(4), s.next(addr, 4))
+
+	// transactions pending in the mempool are accounted for
+	s.accepted(addr, 4)
+	require.Equal(t, uint64(5), s.next(addr, 4))
+	s.accepted(addr, 5)
+	require.Equal(t, uint64(6), s.next(addr, 4))
+
+	// until the chain catches up
+	require.Equal(t, uint64(7), s.next(addr, 7))
+	require.Equal(t, uint64(7), s.next(addr, 7))
+
+	// mismatches resync to the sequence the chain expects
+	simErr := fmt.Errorf("rpc error: code = Unknown desc = account sequence mismatch, expected 12, got 9: %w", sdkerrors.ErrWrongSequence)
+	require.True(t, isSequenceMismatch(simErr))
+	expected, ok := s.mismatch(addr, simErr)
+	require.True(t, ok)
+	require.Equal(t, uint64(12), expected)
+	require.Equal(t, uint64(12), s.next(addr, 9))
+
+	// or from the chain when the expected sequence is not told
+	_, ok = s.mismatch(addr, errors.New("incorrect account sequence"))
+	require.False(t, ok)
+	require.Equal(t, uint64(9), s.next(addr, 9))
+
+	// pending transactions unseen for too long are presumed evicted
+	s.accepted(addr, 9)
+	s.accounts[addr].updatedAt = time.Now().Add(-sequenceResyncAfter - time.Second)
+	require.Equal(t, uint64(9), s.next(addr, 9))
+
+	require.False(t, isSequenceMismatch(errors.New("out of gas")))
+	require.False(t, isSequenceMismatch(nil))
+}
@@ -3,37 +3,36 @@ package cosmos
 import (
 	"context"
 	"fmt"
-	"sync"
 
 	rollapptypes "github.com/furychain/furya/x/rollapp/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-var (
-	lock                       = &sync.Mutex{}
-	furyaProviderSingleton *GridironSettlementProvider
-)
-
+// GridironSettlementProvider queries the state of rollapps on their Gridiron settlement hub.
+// Each rollapp chain is given the settlement provider of its hub with CosmosProvider.SetSettlementProvider,
+// so rollapps settling on different hubs can be relayed by the same process.
 type GridironSettlementProvider struct {
 	*CosmosProvider
 }
 
-// NewSettlementProvider is creating a settlement provider which is a warrper for CosmosProvider
+var (
+	_ SettlementProvider    = (*GridironSettlementProvider)(nil)
+	_ DisputePeriodProvider = (*GridironSettlementProvider)(nil)
+)
+
+// NewGridironSettlementProvider is creating a settlement provider which is a warrper for CosmosProvider
 // and provides QueryLatestFinalizedHeight
-func NewSettlementProvider(cp *CosmosProvider) (*GridironSettlementProvider, error) {
-	lock.Lock()
-	defer lock.Unlock()
-	if furyaProviderSingleton != nil {
-		return nil, fmt.Errorf("settlement was already initialized as %s. Cannot be initialized twich as %s",
-			furyaProviderSingleton.ChainName(), cp.ChainName())
-	}
-	furyaProviderSingleton = &GridironSettlementProvider{cp}
-	return furyaProviderSingleton, nil
+func NewGridironSettlementProvider(cp *CosmosProvider) *GridironSettlementProvider {
+	return &GridironSettlementProvider{cp}
 }
 
 // QueryLatestFinalizedHeight return the latest finalized height of a rollapp
+// It implements processor.FinalityGater.
 func (cc *GridironSettlementProvider) QueryLatestFinalizedHeight(ctx context.Context, rollapId string) (int64, error) {
+	if cc == nil {
+		return -1, fmt.Errorf("no settlement layer is configured for %s, cannot query its finalized height", rollapId)
+	}
 	qc := rollapptypes.NewQueryClient(cc)
 	res, err := qc.LatestFinalizedStateInfo(ctx,
 		&rollapptypes.QueryGetLatestFinalizedStateInfoRequest{RollappId: rollapId})
@@ -52,6 +51,70 @@ func (cc *GridironSettlementProvider) QueryLatestFinalizedHeight(ctx context.Con
 
 }
 
-func GetLatestFinalizedStateHeight(ctx context.Context, rollapId string) (int64, error) {
-	return furyaProviderSingleton.QueryLatestFinalizedHeight(ctx, rollapId)
+// SubscribeFinality returns a channel receiving the latest finalized height of the rollapp whenever
+// new states of it are finalized on the Gridiron hub, see subscribeFinality.
+func (cc *GridironSettlementProvider) SubscribeFinality(ctx context.Context, rollappID string) (<-chan int64, error) {
+	if cc == nil {
+		return nil, fmt.Errorf("no settlement layer is configured for %s, cannot subscribe to its finalized height", rollappID)
+	}
+	return subscribeFinality(ctx, cc.CosmosProvider, cc, rollappID)
+}
+
+// QueryStateInfo returns the state of a rollapp holding its block at height on the Gridiron hub.
+func (cc *GridironSettlementProvider) QueryStateInfo(ctx context.Context, rollappID string, height uint64) (StateInfo, error) {
+	if cc == nil {
+		return StateInfo{}, fmt.Errorf("no settlement layer is configured for %s, cannot query its states", rollappID)
+	}
+	qc := rollapptypes.NewQueryClient(cc)
+	res, err := qc.StateInfo(ctx, &rollapptypes.QueryGetStateInfoRequest{RollappId: rollappID, Height: height})
+	if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+		return StateInfo{}, fmt.Errorf("no state of %s holds height %d on %s: %w", rollappID, height, cc.PCfg.ChainID, ErrStateNotFound)
+	}
+	if err != nil {
+		return StateInfo{}, fmt.Errorf("failed to query state of %s at height %d on %s: %w", rollappID, height, cc.PCfg.ChainID, err)
+	}
+	if res == nil {
+		return StateInfo{}, fmt.Errorf("no state of %s holds height %d on %s", rollappID, height, cc.PCfg.ChainID)
+	}
+	s := res.StateInfo
+	return StateInfo{
+		RollappID:      s.StateInfoIndex.RollappId,
+		Index:          s.StateInfoIndex.Index,
+		Sequencer:      s.Sequencer,
+		StartHeight:    s.StartHeight,
+		NumBlocks:      s.NumBlocks,
+		DAPath:         s.DAPath,
+		CreationHeight: s.CreationHeight,
+		Status:         gridironStateStatus(s.Status),
+	}, nil
+}
+
+// QueryDisputePeriod returns the dispute period of the states of rollapps on the Gridiron hub, in blocks of the hub.
+// The dispute period is a parameter of the rollapp module of the hub, the same for all rollapps.
+func (cc *GridironSettlementProvider) QueryDisputePeriod(ctx context.Context, rollappID string) (uint64, error) {
+	if cc == nil {
+		return 0, fmt.Errorf("no settlement layer is configured for %s, cannot query its dispute period", rollappID)
+	}
+	qc := rollapptypes.NewQueryClient(cc)
+	res, err := qc.Params(ctx, &rollapptypes.QueryParamsRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query rollapp params on %s: %w", cc.PCfg.ChainID, err)
+	}
+	if res == nil {
+		return 0, fmt.Errorf("no rollapp params on %s", cc.PCfg.ChainID)
+	}
+	return res.Params.DisputePeriodInBlocks, nil
+}
+
+// gridironStateStatus returns the status of a state of a rollapp on a Gridiron hub,
+// on which states are pending finalization once received.
+func gridironStateStatus(s rollapptypes.StateStatus) string {
+	switch s {
+	case rollapptypes.STATE_STATUS_FINALIZED:
+		return StateStatusFinalized
+	case rollapptypes.STATE_STATUS_RECEIVED:
+		return StateStatusPending
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", s)
+	}
 }
@@ -0,0 +1,330 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// gRPC queries of the rollapp module of the Dymension hub, returning a state of a rollapp and the module params.
+const (
+	dymensionStateInfoMethod = "/dymensionxyz.dymension.rollapp.Query/StateInfo"
+	dymensionParamsMethod    = "/dymensionxyz.dymension.rollapp.Query/Params"
+)
+
+// DymensionSettlementProvider queries the state of rollapps on their Dymension settlement hub.
+// The rollapp module of the Dymension hub is queried over the gRPC queries of the hub, its messages being
+// encoded here rather than depending on the modules of the hub.
+type DymensionSettlementProvider struct {
+	*CosmosProvider
+}
+
+var (
+	_ SettlementProvider    = (*DymensionSettlementProvider)(nil)
+	_ DisputePeriodProvider = (*DymensionSettlementProvider)(nil)
+)
+
+// NewDymensionSettlementProvider returns the settlement provider of rollapps settling on the Dymension hub cp.
+func NewDymensionSettlementProvider(cp *CosmosProvider) *DymensionSettlementProvider {
+	return &DymensionSettlementProvider{cp}
+}
+
+// QueryLatestFinalizedHeight returns the latest finalized height of a rollapp,
+// or -1 if no state of the rollapp has been finalized yet.
+func (cc *DymensionSettlementProvider) QueryLatestFinalizedHeight(ctx context.Context, rollappID string) (int64, error) {
+	if cc == nil {
+		return -1, fmt.Errorf("no settlement layer is configured for %s, cannot query its finalized height", rollappID)
+	}
+	s, err := cc.queryStateInfo(ctx, &dymensionStateInfoRequest{RollappID: rollappID, Finalized: true})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok && st.Code() == codes.NotFound {
+			return -1, nil
+		}
+		return -1, err
+	}
+	if s.NumBlocks == 0 {
+		return -1, fmt.Errorf("can't get latest-finalized-state info")
+	}
+	return int64(s.EndHeight()), nil
+}
+
+// SubscribeFinality returns a channel receiving the latest finalized height of the rollapp whenever
+// new states of it are finalized on the Dymension hub, see subscribeFinality.
+func (cc *DymensionSettlementProvider) SubscribeFinality(ctx context.Context, rollappID string) (<-chan int64, error) {
+	if cc == nil {
+		return nil, fmt.Errorf("no settlement layer is configured for %s, cannot subscribe to its finalized height", rollappID)
+	}
+	return subscribeFinality(ctx, cc.CosmosProvider, cc, rollappID)
+}
+
+// QueryStateInfo returns the state of a rollapp holding its block at height on the Dymension hub.
+func (cc *DymensionSettlementProvider) QueryStateInfo(ctx context.Context, rollappID string, height uint64) (StateInfo, error) {
+	if cc == nil {
+		return StateInfo{}, fmt.Errorf("no settlement layer is configured for %s, cannot query its states", rollappID)
+	}
+	s, err := cc.queryStateInfo(ctx, &dymensionStateInfoRequest{RollappID: rollappID, Height: height})
+	if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+		return StateInfo{}, fmt.Errorf("no state of %s holds height %d on %s: %w", rollappID, height, cc.PCfg.ChainID, ErrStateNotFound)
+	}
+	if err != nil {
+		return StateInfo{}, fmt.Errorf("failed to query state of %s at height %d on %s: %w", rollappID, height, cc.PCfg.ChainID, err)
+	}
+	return s, nil
+}
+
+func (cc *DymensionSettlementProvider) queryStateInfo(ctx context.Context, req *dymensionStateInfoRequest) (StateInfo, error) {
+	var res dymensionStateInfoResponse
+	if err := cc.Invoke(ctx, dymensionStateInfoMethod, req, &res); err != nil {
+		return StateInfo{}, err
+	}
+	return res.StateInfo, nil
+}
+
+// QueryDisputePeriod returns the dispute period of the states of rollapps on the Dymension hub, in blocks of the hub.
+// The dispute period is a parameter of the rollapp module of the hub, the same for all rollapps.
+func (cc *DymensionSettlementProvider) QueryDisputePeriod(ctx context.Context, rollappID string) (uint64, error) {
+	if cc == nil {
+		return 0, fmt.Errorf("no settlement layer is configured for %s, cannot query its dispute period", rollappID)
+	}
+	var res dymensionParamsResponse
+	if err := cc.Invoke(ctx, dymensionParamsMethod, &dymensionParamsRequest{}, &res); err != nil {
+		return 0, fmt.Errorf("failed to query rollapp params on %s: %w", cc.PCfg.ChainID, err)
+	}
+	return res.DisputePeriodInBlocks, nil
+}
+
+// dymensionStateInfoRequest is the request of the StateInfo query of the rollapp module of the Dymension hub:
+//
+//	message QueryGetStateInfoRequest {
+//	  string rollappId = 1;
+//	  uint64 index = 2;
+//	  uint64 height = 3;
+//	  bool finalized = 4;
+//	}
+type dymensionStateInfoRequest struct {
+	RollappID string
+	Index     uint64
+	Height    uint64
+	Finalized bool
+}
+
+func (r *dymensionStateInfoRequest) Reset() { *r = dymensionStateInfoRequest{} }
+func (r *dymensionStateInfoRequest) String() string {
+	return fmt.Sprintf("state info of %s", r.RollappID)
+}
+func (*dymensionStateInfoRequest) ProtoMessage() {}
+
+// Marshal encodes the request as protobuf.
+func (r *dymensionStateInfoRequest) Marshal() ([]byte, error) {
+	var b []byte
+	if r.RollappID != "" {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, r.RollappID)
+	}
+	if r.Index != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, r.Index)
+	}
+	if r.Height != 0 {
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, r.Height)
+	}
+	if r.Finalized {
+		b = protowire.AppendTag(b, 4, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(true))
+	}
+	return b, nil
+}
+
+// dymensionStateInfoResponse is the response of the StateInfo query of the rollapp module of the Dymension hub,
+// holding the fields of the state relevant to relaying:
+//
+//	message QueryGetStateInfoResponse {
+//	  StateInfo stateInfo = 1;
+//	}
+//
+//	message StateInfo {
+//	  StateInfoIndex stateInfoIndex = 1; // {string rollappId = 1; uint64 index = 2;}
+//	  string sequencer = 2;
+//	  uint64 startHeight = 3;
+//	  uint64 numBlocks = 4;
+//	  string DAPath = 5;
+//	  uint64 creationHeight = 7;
+//	  Status status = 8; // PENDING = 0, FINALIZED = 1, REVERTED = 2
+//	}
+type dymensionStateInfoResponse struct {
+	StateInfo StateInfo
+}
+
+func (r *dymensionStateInfoResponse) Reset() { *r = dymensionStateInfoResponse{} }
+func (r *dymensionStateInfoResponse) String() string {
+	return fmt.Sprintf("state info of %s", r.StateInfo.RollappID)
+}
+func (*dymensionStateInfoResponse) ProtoMessage() {}
+
+// Unmarshal decodes the response from protobuf, skipping unknown fields.
+func (r *dymensionStateInfoResponse) Unmarshal(b []byte) error {
+	r.Reset()
+	return consumeMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, bool) {
+		if num != 1 || typ != protowire.BytesType {
+			return 0, false
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n, true
+		}
+		if err := unmarshalDymensionStateInfo(v, &r.StateInfo); err != nil {
+			return -1, true
+		}
+		return n, true
+	})
+}
+
+// unmarshalDymensionStateInfo decodes a state of a rollapp on the Dymension hub into s.
+func unmarshalDymensionStateInfo(b []byte, s *StateInfo) error {
+	s.Status = StateStatusPending
+	return consumeMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, bool) {
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return n, true
+			}
+			err := consumeMessage(v, func(num protowire.Number, typ protowire.Type, b []byte) (int, bool) {
+				switch {
+				case num == 1 && typ == protowire.BytesType:
+					v, n := protowire.ConsumeString(b)
+					s.RollappID = v
+					return n, true
+				case num == 2 && typ == protowire.VarintType:
+					v, n := protowire.ConsumeVarint(b)
+					s.Index = v
+					return n, true
+				}
+				return 0, false
+			})
+			if err != nil {
+				return -1, true
+			}
+			return n, true
+		case (num == 2 || num == 5) && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if num == 2 {
+				s.Sequencer = v
+			} else {
+				s.DAPath = v
+			}
+			return n, true
+		case (num == 3 || num == 4 || num == 7 || num == 8) && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			switch num {
+			case 3:
+				s.StartHeight = v
+			case 4:
+				s.NumBlocks = v
+			case 7:
+				s.CreationHeight = v
+			case 8:
+				s.Status = dymensionStateStatus(v)
+			}
+			return n, true
+		}
+		return 0, false
+	})
+}
+
+// dymensionParamsRequest is the request of the Params query of the rollapp module of the Dymension hub,
+// an empty message.
+type dymensionParamsRequest struct{}
+
+func (r *dymensionParamsRequest) Reset()         { *r = dymensionParamsRequest{} }
+func (r *dymensionParamsRequest) String() string { return "rollapp params" }
+func (*dymensionParamsRequest) ProtoMessage()    {}
+
+// Marshal encodes the request as protobuf.
+func (r *dymensionParamsRequest) Marshal() ([]byte, error) {
+	return nil, nil
+}
+
+// dymensionParamsResponse is the response of the Params query of the rollapp module of the Dymension hub,
+// holding the params relevant to relaying:
+//
+//	message QueryParamsResponse {
+//	  Params params = 1;
+//	}
+//
+//	message Params {
+//	  uint64 dispute_period_in_blocks = 1;
+//	}
+type dymensionParamsResponse struct {
+	DisputePeriodInBlocks uint64
+}
+
+func (r *dymensionParamsResponse) Reset()         { *r = dymensionParamsResponse{} }
+func (r *dymensionParamsResponse) String() string { return "rollapp params" }
+func (*dymensionParamsResponse) ProtoMessage()    {}
+
+// Unmarshal decodes the response from protobuf, skipping unknown fields.
+func (r *dymensionParamsResponse) Unmarshal(b []byte) error {
+	r.Reset()
+	return consumeMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, bool) {
+		if num != 1 || typ != protowire.BytesType {
+			return 0, false
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n, true
+		}
+		err := consumeMessage(v, func(num protowire.Number, typ protowire.Type, b []byte) (int, bool) {
+			if num != 1 || typ != protowire.VarintType {
+				return 0, false
+			}
+			v, n := protowire.ConsumeVarint(b)
+			r.DisputePeriodInBlocks = v
+			return n, true
+		})
+		if err != nil {
+			return -1, true
+		}
+		return n, true
+	})
+}
+
+// dymensionStateStatus returns the status of a state of a rollapp on the Dymension hub.
+func dymensionStateStatus(v uint64) string {
+	switch v {
+	case 0:
+		return StateStatusPending
+	case 1:
+		return StateStatusFinalized
+	case 2:
+		return StateStatusReverted
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", v)
+	}
+}
+
+// consumeMessage decodes the fields of the protobuf message b with field, which consumes the value of a field
+// and returns its length, or returns false to skip the field.
+func consumeMessage(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) (int, bool)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		n, ok := field(num, typ, b)
+		if !ok {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
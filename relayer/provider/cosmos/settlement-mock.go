@@ -0,0 +1,152 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// mockFinalityPollInterval is how often MockSettlementProvider notifies subscribers of new finalized heights.
+const mockFinalityPollInterval = time.Second
+
+// MockSettlementProvider is a settlement layer finalizing the blocks of rollapps as soon as they are committed,
+// for devnets and tests of rollapps without a settlement hub to settle on.
+// Finalized heights can instead be pinned with SetFinalizedHeight, e.g. to hold back the packets of a rollapp,
+// and states reverted with RevertState.
+type MockSettlementProvider struct {
+	mu sync.Mutex
+	// rollapps by chain ID, whose latest height is the finalized height unless pinned
+	rollapps map[string]*CosmosProvider
+	pinned   map[string]int64
+	// reverted heights by chain ID
+	reverted       map[string]map[uint64]bool
+	disputePeriods map[string]uint64
+}
+
+var (
+	_ SettlementProvider    = (*MockSettlementProvider)(nil)
+	_ DisputePeriodProvider = (*MockSettlementProvider)(nil)
+)
+
+// NewMockSettlementProvider returns a settlement layer on which no state of any rollapp is finalized
+// until the rollapp is added or its finalized height is set.
+func NewMockSettlementProvider() *MockSettlementProvider {
+	return &MockSettlementProvider{
+		rollapps:       make(map[string]*CosmosProvider),
+		pinned:         make(map[string]int64),
+		reverted:       make(map[string]map[uint64]bool),
+		disputePeriods: make(map[string]uint64),
+	}
+}
+
+// AddRollapp finalizes the blocks of the rollapp cp as soon as they are committed.
+func (m *MockSettlementProvider) AddRollapp(cp *CosmosProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rollapps[cp.ChainId()] = cp
+}
+
+// SetFinalizedHeight pins the latest finalized height of a rollapp, -1 meaning that none of its states is finalized.
+func (m *MockSettlementProvider) SetFinalizedHeight(rollappID string, h int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pinned[rollappID] = h
+}
+
+// RevertState reverts the pending state of the rollapp holding its block at height.
+func (m *MockSettlementProvider) RevertState(rollappID string, height uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.reverted[rollappID] == nil {
+		m.reverted[rollappID] = make(map[uint64]bool)
+	}
+	m.reverted[rollappID][height] = true
+}
+
+// SetDisputePeriod sets the dispute period of the states of a rollapp, 0 unless set.
+func (m *MockSettlementProvider) SetDisputePeriod(rollappID string, blocks uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.disputePeriods[rollappID] = blocks
+}
+
+// QueryDisputePeriod returns the dispute period set for the states of a rollapp.
+func (m *MockSettlementProvider) QueryDisputePeriod(_ context.Context, rollappID string) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.disputePeriods[rollappID], nil
+}
+
+// QueryLatestFinalizedHeight returns the pinned finalized height of a rollapp, or else its latest height.
+func (m *MockSettlementProvider) QueryLatestFinalizedHeight(ctx context.Context, rollappID string) (int64, error) {
+	m.mu.Lock()
+	h, pinned := m.pinned[rollappID]
+	cp, ok := m.rollapps[rollappID]
+	m.mu.Unlock()
+	switch {
+	case pinned:
+		return h, nil
+	case ok:
+		return cp.queryLatestHeight(ctx)
+	default:
+		return -1, nil
+	}
+}
+
+// SubscribeFinality returns a channel receiving the latest finalized height of the rollapp whenever it increases.
+func (m *MockSettlementProvider) SubscribeFinality(ctx context.Context, rollappID string) (<-chan int64, error) {
+	heights := make(chan int64)
+	go func() {
+		defer close(heights)
+		ticker := time.NewTicker(mockFinalityPollInterval)
+		defer ticker.Stop()
+
+		var latest int64 = -1
+		for {
+			if h, err := m.QueryLatestFinalizedHeight(ctx, rollappID); err == nil && h > latest {
+				select {
+				case heights <- h:
+					latest = h
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return heights, nil
+}
+
+// QueryStateInfo returns a state of a single block of the rollapp at height,
+// finalized if the height is not above its latest finalized height, unless it was reverted.
+func (m *MockSettlementProvider) QueryStateInfo(ctx context.Context, rollappID string, height uint64) (StateInfo, error) {
+	if height == 0 {
+		return StateInfo{}, fmt.Errorf("no state of %s holds height 0", rollappID)
+	}
+	finalized, err := m.QueryLatestFinalizedHeight(ctx, rollappID)
+	if err != nil {
+		return StateInfo{}, err
+	}
+	s := StateInfo{
+		RollappID:   rollappID,
+		Index:       height,
+		StartHeight: height,
+		NumBlocks:   1,
+		Status:      StateStatusPending,
+	}
+	m.mu.Lock()
+	reverted := m.reverted[rollappID][height]
+	m.mu.Unlock()
+	switch {
+	case reverted:
+		s.Status = StateStatusReverted
+	case int64(height) <= finalized:
+		s.Status = StateStatusFinalized
+	}
+	return s, nil
+}
@@ -0,0 +1,216 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/service"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"go.uber.org/zap"
+)
+
+// Events emitted by the rollapp module of the settlement hub when the status of a state of a rollapp changes,
+// such as when it is finalized at the end of its dispute period.
+const (
+	rollappEventStatusChange = "status_change"
+
+	rollappAttributeRollappID   = "rollapp_id"
+	rollappAttributeStartHeight = "start_height"
+	rollappAttributeNumBlocks   = "num_blocks"
+	rollappAttributeStatus      = "status"
+
+	rollappStatusFinalized = "FINALIZED"
+)
+
+// finalizedHeightPollInterval is how often the latest finalized height is also queried while subscribed,
+// so that a dropped websocket or a missed event only delays notifications.
+const finalizedHeightPollInterval = time.Minute
+
+// finalizedHeightFallbackPollInterval is how often the latest finalized height is queried when finalizations
+// can't be subscribed to.
+const finalizedHeightFallbackPollInterval = 10 * time.Second
+
+// eventSubscriptions signals the event subscriptions over the websocket of a chain to be established again,
+// e.g. once the chain restarted after a software upgrade, its websocket having been dropped. The zero value is ready to use.
+type eventSubscriptions struct {
+	mu      sync.Mutex
+	renewed chan struct{}
+}
+
+// renewedCh returns a channel closed once the subscriptions are to be established again.
+func (s *eventSubscriptions) renewedCh() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.renewed == nil {
+		s.renewed = make(chan struct{})
+	}
+	return s.renewed
+}
+
+// renew closes the channel returned by renewedCh until then, later calls returning a new one.
+func (s *eventSubscriptions) renew() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.renewed != nil {
+		close(s.renewed)
+	}
+	s.renewed = make(chan struct{})
+}
+
+// RenewEventSubscriptions establishes the event subscriptions over the websocket of the chain again,
+// such as those to the finalized states of rollapps settling on it.
+func (cc *CosmosProvider) RenewEventSubscriptions() {
+	cc.subscriptions.renew()
+}
+
+// subscribeFinality returns a channel receiving the latest finalized height of the rollapp whenever
+// new states of it are finalized on the settlement hub, starting with its latest finalized height, if any.
+// Finalizations are subscribed to over the websocket of the hub, so they are notified as soon as the block
+// finalizing them is committed, and the latest finalized height is also queried from sp in case events are missed.
+// Endpoints of the hub without a websocket are polled every finalizedHeightFallbackPollInterval instead.
+// The subscription is established again when renewed with RenewEventSubscriptions.
+// Heights only ever increase, and the channel is closed once ctx is done.
+func subscribeFinality(ctx context.Context, hub *CosmosProvider, sp SettlementProvider, rollappID string) (<-chan int64, error) {
+	subscriber := "rly-finalized-" + rollappID
+	renewed := hub.subscriptions.renewedCh()
+	subscribe := func() (<-chan ctypes.ResultEvent, time.Duration) {
+		events, err := subscribeFinalizedStates(ctx, hub, subscriber, rollappID)
+		if err != nil {
+			hub.log.Warn(
+				"Failed to subscribe to finalized states over the websocket of the settlement hub, polling them instead",
+				zap.String("chain_id", rollappID),
+				zap.String("settlement_chain_id", hub.PCfg.ChainID),
+				zap.Error(err),
+			)
+			return nil, finalizedHeightFallbackPollInterval
+		}
+		return events, finalizedHeightPollInterval
+	}
+	unsubscribe := func() {
+		unsubscribeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = hub.RPCClient.UnsubscribeAll(unsubscribeCtx, subscriber)
+	}
+	events, pollInterval := subscribe()
+
+	heights := make(chan int64)
+	go func() {
+		defer close(heights)
+		defer func() {
+			if events != nil {
+				unsubscribe()
+			}
+		}()
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		var latest int64 = -1
+		notify := func(h int64) bool {
+			if h <= latest {
+				return true
+			}
+			select {
+			case heights <- h:
+				latest = h
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		poll := func() bool {
+			h, err := sp.QueryLatestFinalizedHeight(ctx, rollappID)
+			if err != nil {
+				hub.log.Debug(
+					"Failed to query latest finalized height",
+					zap.String("chain_id", rollappID),
+					zap.String("settlement_chain_id", hub.PCfg.ChainID),
+					zap.Error(err),
+				)
+				return true
+			}
+			return notify(h)
+		}
+
+		if !poll() {
+			return
+		}
+		for {
+			select {
+			case ev := <-events:
+				if h, ok := finalizedHeightFromEvent(ev, rollappID); ok && !notify(h) {
+					return
+				}
+			case <-ticker.C:
+				if !poll() {
+					return
+				}
+			case <-renewed:
+				renewed = hub.subscriptions.renewedCh()
+				if events != nil {
+					unsubscribe()
+				}
+				events, pollInterval = subscribe()
+				ticker.Reset(pollInterval)
+				if !poll() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return heights, nil
+}
+
+// subscribeFinalizedStates subscribes to the status changes of the states of the rollapp over the websocket of the hub.
+func subscribeFinalizedStates(ctx context.Context, hub *CosmosProvider, subscriber, rollappID string) (<-chan ctypes.ResultEvent, error) {
+	if err := hub.RPCClient.Start(); err != nil && !errors.Is(err, service.ErrAlreadyStarted) {
+		return nil, fmt.Errorf("failed to start websocket of settlement hub %s: %w", hub.PCfg.ChainID, err)
+	}
+	query := fmt.Sprintf("tm.event='NewBlock' AND %s.%s='%s'", rollappEventStatusChange, rollappAttributeRollappID, rollappID)
+	events, err := hub.RPCClient.Subscribe(ctx, subscriber, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to finalized states of %s on %s: %w", rollappID, hub.PCfg.ChainID, err)
+	}
+	return events, nil
+}
+
+// finalizedHeightFromEvent returns the highest rollapp height finalized by the status changes of the event.
+func finalizedHeightFromEvent(ev ctypes.ResultEvent, rollappID string) (int64, bool) {
+	attr := func(key string) []string {
+		return ev.Events[rollappEventStatusChange+"."+key]
+	}
+	rollappIDs, startHeights, numBlocks, statuses := attr(rollappAttributeRollappID), attr(rollappAttributeStartHeight),
+		attr(rollappAttributeNumBlocks), attr(rollappAttributeStatus)
+
+	var (
+		finalized int64
+		found     bool
+	)
+	// Attributes of the status changes of a block are listed in the order of the events.
+	for i := range rollappIDs {
+		if rollappIDs[i] != rollappID || i >= len(startHeights) || i >= len(numBlocks) || i >= len(statuses) {
+			continue
+		}
+		if statuses[i] != rollappStatusFinalized {
+			continue
+		}
+		start, err := strconv.ParseUint(startHeights[i], 10, 64)
+		if err != nil {
+			continue
+		}
+		n, err := strconv.ParseUint(numBlocks[i], 10, 64)
+		if err != nil || n == 0 {
+			continue
+		}
+		if h := int64(start + n - 1); h > finalized {
+			finalized, found = h, true
+		}
+	}
+	return finalized, found
+}
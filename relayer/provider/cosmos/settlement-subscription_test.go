@@ -0,0 +1,58 @@
+package cosmos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+func TestFinalizedHeightFromEvent(t *testing.T) {
+	ev := ctypes.ResultEvent{Events: map[string][]string{
+		"status_change.rollapp_id":   {"rollapp-a", "rollapp-b", "rollapp-a", "rollapp-a"},
+		"status_change.start_height": {"1", "1", "11", "21"},
+		"status_change.num_blocks":   {"10", "50", "10", "5"},
+		"status_change.status":       {"FINALIZED", "FINALIZED", "FINALIZED", "RECEIVED"},
+	}}
+
+	h, ok := finalizedHeightFromEvent(ev, "rollapp-a")
+	require.True(t, ok)
+	require.Equal(t, int64(20), h)
+
+	h, ok = finalizedHeightFromEvent(ev, "rollapp-b")
+	require.True(t, ok)
+	require.Equal(t, int64(50), h)
+
+	_, ok = finalizedHeightFromEvent(ev, "rollapp-c")
+	require.False(t, ok)
+
+	_, ok = finalizedHeightFromEvent(ctypes.ResultEvent{}, "rollapp-a")
+	require.False(t, ok)
+}
+
+func TestSubscribeFinalityWithoutSettlement(t *testing.T) {
+	var sp *GridironSettlementProvider
+	_, err := sp.SubscribeFinality(context.Background(), "rollapp-a")
+	require.Error(t, err)
+}
+
+func TestEventSubscriptionsRenewal(t *testing.T) {
+	var s eventSubscriptions
+	renewed := s.renewedCh()
+	select {
+	case <-renewed:
+		t.Fatal("subscriptions must not be renewed yet")
+	default:
+	}
+
+	s.renew()
+	<-renewed
+	next := s.renewedCh()
+	require.NotEqual(t, renewed, next)
+	select {
+	case <-next:
+		t.Fatal("subscriptions renewed once only")
+	default:
+	}
+}
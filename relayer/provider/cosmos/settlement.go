@@ -0,0 +1,116 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Settlement layers rollapps can settle on, selected by the settlement-type of the chain config of their hub.
+const (
+	SettlementTypeGridiron  = "gridiron"
+	SettlementTypeDymension = "dymension"
+	SettlementTypeMock      = "mock"
+)
+
+// SettlementTypes lists the supported settlement layers.
+var SettlementTypes = []string{SettlementTypeGridiron, SettlementTypeDymension, SettlementTypeMock}
+
+// Statuses of the states of rollapps on their settlement layer.
+const (
+	StateStatusPending   = "PENDING"
+	StateStatusFinalized = "FINALIZED"
+	StateStatusReverted  = "REVERTED"
+)
+
+// ErrStateNotFound is returned by SettlementProvider.QueryStateInfo when no state of the rollapp holds the height yet.
+var ErrStateNotFound = errors.New("state not found")
+
+// StateInfo is a state of a rollapp posted to its settlement layer, holding a range of its blocks.
+type StateInfo struct {
+	RollappID string `json:"rollapp_id"`
+	// Index of the state among the states of the rollapp.
+	Index       uint64 `json:"index"`
+	Sequencer   string `json:"sequencer"`
+	StartHeight uint64 `json:"start_height"`
+	NumBlocks   uint64 `json:"num_blocks"`
+	DAPath      string `json:"da_path"`
+	// CreationHeight is the height of the settlement layer the state was posted at.
+	CreationHeight uint64 `json:"creation_height"`
+	Status         string `json:"status"`
+}
+
+// EndHeight returns the last rollapp height held by the state.
+func (s StateInfo) EndHeight() uint64 {
+	return s.StartHeight + s.NumBlocks - 1
+}
+
+// SettlementProvider queries the states rollapps post to their settlement layer.
+// It implements processor.FinalityGater.
+type SettlementProvider interface {
+	// QueryLatestFinalizedHeight returns the latest finalized height of a rollapp,
+	// or -1 if no state of the rollapp has been finalized yet.
+	QueryLatestFinalizedHeight(ctx context.Context, rollappID string) (int64, error)
+
+	// SubscribeFinality returns a channel receiving the latest finalized height of the rollapp whenever
+	// new states of it are finalized, starting with its latest finalized height, if any.
+	// Heights only ever increase, and the channel is closed once ctx is done.
+	SubscribeFinality(ctx context.Context, rollappID string) (<-chan int64, error)
+
+	// QueryStateInfo returns the state of a rollapp holding its block at height.
+	QueryStateInfo(ctx context.Context, rollappID string, height uint64) (StateInfo, error)
+}
+
+// DisputePeriodProvider is implemented by settlement layers reporting how long the states of rollapps can be disputed.
+type DisputePeriodProvider interface {
+	// QueryDisputePeriod returns the number of blocks of the settlement layer after a state of the rollapp is posted
+	// during which it can be disputed, before it is finalized.
+	QueryDisputePeriod(ctx context.Context, rollappID string) (uint64, error)
+}
+
+// NewSettlementProvider returns the settlement provider of rollapps settling on hub,
+// implementing the settlement layer selected by the settlement-type of its chain config.
+func NewSettlementProvider(hub *CosmosProvider) (SettlementProvider, error) {
+	switch hub.PCfg.SettlementType {
+	case "", SettlementTypeGridiron:
+		return NewGridironSettlementProvider(hub), nil
+	case SettlementTypeDymension:
+		return NewDymensionSettlementProvider(hub), nil
+	case SettlementTypeMock:
+		return NewMockSettlementProvider(), nil
+	default:
+		return nil, validateSettlementType(hub.PCfg.SettlementType)
+	}
+}
+
+// validateSettlementType checks that t is one of SettlementTypes, if set.
+func validateSettlementType(t string) error {
+	if t == "" {
+		return nil
+	}
+	for _, st := range SettlementTypes {
+		if t == st {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid settlement-type %q, expected one of %v", t, SettlementTypes)
+}
+
+// GetLatestFinalizedStateHeight returns the latest finalized height of a rollapp on the settlement layer sp,
+// or -1 if no state of the rollapp has been finalized yet.
+func GetLatestFinalizedStateHeight(ctx context.Context, sp SettlementProvider, rollapId string) (int64, error) {
+	if sp == nil {
+		return -1, fmt.Errorf("no settlement layer is configured for %s, cannot query its finalized height", rollapId)
+	}
+	return sp.QueryLatestFinalizedHeight(ctx, rollapId)
+}
+
+// SetSettlementProvider sets the settlement layer of the chain, which must be a rollapp.
+func (cc *CosmosProvider) SetSettlementProvider(sp SettlementProvider) {
+	cc.settlement = sp
+}
+
+// SettlementProvider returns the settlement layer of the chain, or nil if the chain has none.
+func (cc *CosmosProvider) SettlementProvider() SettlementProvider {
+	return cc.settlement
+}
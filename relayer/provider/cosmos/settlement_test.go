@@ -0,0 +1,126 @@
+package cosmos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestNewSettlementProvider(t *testing.T) {
+	for settlementType, expected := range map[string]SettlementProvider{
+		"":                      &GridironSettlementProvider{},
+		SettlementTypeGridiron:  &GridironSettlementProvider{},
+		SettlementTypeDymension: &DymensionSettlementProvider{},
+		SettlementTypeMock:      &MockSettlementProvider{},
+	} {
+		sp, err := NewSettlementProvider(&CosmosProvider{PCfg: CosmosProviderConfig{SettlementType: settlementType}})
+		require.NoError(t, err)
+		require.IsType(t, expected, sp)
+	}
+
+	_, err := NewSettlementProvider(&CosmosProvider{PCfg: CosmosProviderConfig{SettlementType: "celestia"}})
+	require.ErrorContains(t, err, `"celestia"`)
+	require.Error(t, validateSettlementType("celestia"))
+}
+
+func TestDymensionStateInfo(t *testing.T) {
+	req, err := (&dymensionStateInfoRequest{RollappID: "rollapp-a", Finalized: true}).Marshal()
+	require.NoError(t, err)
+	expected := protowire.AppendTag(nil, 1, protowire.BytesType)
+	expected = protowire.AppendString(expected, "rollapp-a")
+	expected = protowire.AppendTag(expected, 4, protowire.VarintType)
+	expected = protowire.AppendVarint(expected, 1)
+	require.Equal(t, expected, req)
+
+	var index, s []byte
+	index = protowire.AppendTag(index, 1, protowire.BytesType)
+	index = protowire.AppendString(index, "rollapp-a")
+	index = protowire.AppendTag(index, 2, protowire.VarintType)
+	index = protowire.AppendVarint(index, 7)
+	s = protowire.AppendTag(s, 1, protowire.BytesType)
+	s = protowire.AppendBytes(s, index)
+	s = protowire.AppendTag(s, 2, protowire.BytesType)
+	s = protowire.AppendString(s, "sequencer")
+	for num, v := range map[protowire.Number]uint64{3: 61, 4: 10, 7: 1200, 8: 1} {
+		s = protowire.AppendTag(s, num, protowire.VarintType)
+		s = protowire.AppendVarint(s, v)
+	}
+	// unknown fields, such as the block descriptors of the state, are skipped
+	s = protowire.AppendTag(s, 9, protowire.BytesType)
+	s = protowire.AppendBytes(s, []byte{0x0a, 0x00})
+	res := protowire.AppendTag(nil, 1, protowire.BytesType)
+	res = protowire.AppendBytes(res, s)
+
+	var r dymensionStateInfoResponse
+	require.NoError(t, r.Unmarshal(res))
+	require.Equal(t, StateInfo{
+		RollappID:      "rollapp-a",
+		Index:          7,
+		Sequencer:      "sequencer",
+		StartHeight:    61,
+		NumBlocks:      10,
+		CreationHeight: 1200,
+		Status:         StateStatusFinalized,
+	}, r.StateInfo)
+	require.Equal(t, uint64(70), r.StateInfo.EndHeight())
+
+	require.Error(t, r.Unmarshal(res[:len(res)-1]))
+}
+
+func TestDymensionParams(t *testing.T) {
+	req, err := (&dymensionParamsRequest{}).Marshal()
+	require.NoError(t, err)
+	require.Empty(t, req)
+
+	params := protowire.AppendTag(nil, 1, protowire.VarintType)
+	params = protowire.AppendVarint(params, 120)
+	// unknown fields, such as the deployer whitelist, are skipped
+	params = protowire.AppendTag(params, 2, protowire.BytesType)
+	params = protowire.AppendBytes(params, []byte{0x0a, 0x00})
+	res := protowire.AppendTag(nil, 1, protowire.BytesType)
+	res = protowire.AppendBytes(res, params)
+
+	var r dymensionParamsResponse
+	require.NoError(t, r.Unmarshal(res))
+	require.Equal(t, uint64(120), r.DisputePeriodInBlocks)
+}
+
+func TestMockSettlementProvider(t *testing.T) {
+	ctx := context.Background()
+	m := NewMockSettlementProvider()
+
+	h, err := m.QueryLatestFinalizedHeight(ctx, "rollapp-a")
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), h)
+
+	m.SetFinalizedHeight("rollapp-a", 20)
+	h, err = m.QueryLatestFinalizedHeight(ctx, "rollapp-a")
+	require.NoError(t, err)
+	require.Equal(t, int64(20), h)
+
+	s, err := m.QueryStateInfo(ctx, "rollapp-a", 20)
+	require.NoError(t, err)
+	require.Equal(t, StateStatusFinalized, s.Status)
+	s, err = m.QueryStateInfo(ctx, "rollapp-a", 21)
+	require.NoError(t, err)
+	require.Equal(t, StateStatusPending, s.Status)
+	m.RevertState("rollapp-a", 21)
+	s, err = m.QueryStateInfo(ctx, "rollapp-a", 21)
+	require.NoError(t, err)
+	require.Equal(t, StateStatusReverted, s.Status)
+
+	m.SetDisputePeriod("rollapp-a", 120)
+	period, err := m.QueryDisputePeriod(ctx, "rollapp-a")
+	require.NoError(t, err)
+	require.Equal(t, uint64(120), period)
+
+	ctx, cancel := context.WithCancel(ctx)
+	heights, err := m.SubscribeFinality(ctx, "rollapp-a")
+	require.NoError(t, err)
+	require.Equal(t, int64(20), <-heights)
+	cancel()
+	for range heights {
+	}
+}
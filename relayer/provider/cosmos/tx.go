@@ -11,9 +11,9 @@ import (
 	"github.com/avast/retry-go/v4"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/tx"
-	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	transfertypes "github.com/cosmos/ibc-go/v3/modules/apps/transfer/types"
 	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
 	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
@@ -24,9 +24,12 @@ import (
 	"github.com/cosmos/ibc-go/v3/modules/light-clients/01-furyint/types"
 	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
 	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/cosmos/relayer/v2/relayer/tracing"
+	lens "github.com/strangelove-ventures/lens/client"
 	"github.com/tendermint/tendermint/light"
 	types2 "github.com/tendermint/tendermint/proto/tendermint/types"
 	tmtypes "github.com/tendermint/tendermint/types"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -68,11 +71,53 @@ func (cc *CosmosProvider) SendMessage(ctx context.Context, msg provider.RelayerM
 // BuildAndBroadcast attempts to sign, encode, & send a slice of RelayerMessages
 // A ewsponse is returned in case of success. In case of failure, the response is null
 // and boolean indicating if a transaction should be retried on case of failure is returned together with the error.
-func (cc *CosmosProvider) BuildAndBroadcast(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*sdk.TxResponse, bool, error) {
-	txBytes, err := cc.buildMessages(ctx, msgs, memo)
+//
+// Concurrent calls, e.g. for multiple paths sharing the key of the chain, are serialized by the signing scheduler
+// of the provider, serving the paths named in ctx round-robin.
+//
+// A non-zero timeoutHeight is set as the timeout height of the transaction,
+// otherwise it is derived from the configured TxTimeoutHeightOffset, if any.
+func (cc *CosmosProvider) BuildAndBroadcast(ctx context.Context, msgs []provider.RelayerMessage, memo string, timeoutHeight uint64) (resp *sdk.TxResponse, shouldRetry bool, err error) {
+	if schedErr := cc.signing.Do(ctx, func() error {
+		// A sequence mismatch is recovered from right away, the sequence being resynced by buildAndBroadcast.
+		for attempt := 1; ; attempt++ {
+			resp, shouldRetry, err = cc.buildAndBroadcast(ctx, msgs, memo, timeoutHeight)
+			if !isSequenceMismatch(err) || attempt == maxSequenceMismatchAttempts || ctx.Err() != nil {
+				return nil
+			}
+			cc.log.Info(
+				"Rebuilding transaction after account sequence mismatch",
+				zap.String("chain_id", cc.PCfg.ChainID),
+				zap.Int("attempt", attempt+1),
+				zap.Error(err),
+			)
+		}
+	}); schedErr != nil {
+		return nil, false, schedErr
+	}
+	return resp, shouldRetry, err
+}
+
+func (cc *CosmosProvider) buildAndBroadcast(ctx context.Context, msgs []provider.RelayerMessage, memo string, timeoutHeight uint64) (*sdk.TxResponse, bool, error) {
+	address, err := cc.Address()
+	if err != nil {
+		return nil, true, err
+	}
+	txBytes, seq, err := cc.buildMessages(ctx, msgs, memo, timeoutHeight)
 	if err != nil {
 		errMsg := err.Error()
 
+		// Rebuilding the transaction would simulate the same gas, it is split by the caller instead.
+		if errors.Is(err, ErrTxCapExceeded) {
+			return nil, false, err
+		}
+
+		// Simulating the transaction reports a sequence mismatch along with the sequence the chain expects.
+		if isSequenceMismatch(err) {
+			cc.recordSequenceMismatch(address, seq, err)
+			return nil, true, err
+		}
+
 		// Occasionally the client will be out of date,
 		// and we will receive an RPC error like:
 		//     rpc error: code = InvalidArgument desc = failed to execute message; message index: 1: channel handshake open try failed: failed channel state verification for client (07-tendermint-0): client state height < proof height ({0 58} < {0 59}), please ensure the client has been updated: invalid height: invalid request
@@ -137,7 +182,14 @@ func (cc *CosmosProvider) BuildAndBroadcast(ctx context.Context, msgs []provider
 		return nil, true, err
 	}
 
-	resp, err := cc.BroadcastTx(ctx, txBytes)
+	resp, err := cc.broadcastTx(ctx, txBytes)
+	switch {
+	case err == nil, errors.Is(err, lens.ErrTimeoutAfterWaitingForTxBroadcast):
+		// the transaction passed CheckTx, so the sequence is used even if it is not included yet
+		cc.sequences.accepted(address, seq)
+	case isSequenceMismatch(err):
+		cc.recordSequenceMismatch(address, seq, err)
+	}
 	if err != nil {
 		err = fmt.Errorf(err.Error())
 		// Packet(s) already handled by another relayer
@@ -160,16 +212,30 @@ func (cc *CosmosProvider) BuildAndBroadcast(ctx context.Context, msgs []provider
 // of that transaction will be logged. A boolean indicating if a transaction was successfully
 // sent and executed successfully is returned.
 func (cc *CosmosProvider) SendMessages(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+	return cc.SendMessagesWithTimeoutHeight(ctx, msgs, memo, 0)
+}
+
+// SendMessagesWithTimeoutHeight is SendMessages with the transaction only includable at or below timeoutHeight.
+// With a timeoutHeight of 0, the timeout height is derived from the configured TxTimeoutHeightOffset, if any,
+// on every attempt to build the transaction.
+func (cc *CosmosProvider) SendMessagesWithTimeoutHeight(ctx context.Context, msgs []provider.RelayerMessage, memo string, timeoutHeight uint64) (_ *provider.RelayerTxResponse, _ bool, err error) {
+	ctx, span := tracing.Start(ctx, "SendMessages", attribute.String("chain_id", cc.PCfg.ChainID), attribute.Int("messages", len(msgs)))
+	defer func() { tracing.End(span, err) }()
+
 	// When bringing up a connection with a rollapp, there may be many failures until
 	// the state is accepted and finalized. The messages should be sent only when
 	// the furya hub finalize the corresponding state to which the message belongs to.
 	// This is a special case of rollapp messages relying
 
 	var resp *sdk.TxResponse = nil
+	var capErr error
 
 	if err := retry.Do(func() error {
-		txResponse, bRetry, err := cc.BuildAndBroadcast(ctx, msgs, memo)
+		txResponse, bRetry, err := cc.BuildAndBroadcast(ctx, msgs, memo, timeoutHeight)
 		if err != nil {
+			if errors.Is(err, ErrTxCapExceeded) {
+				capErr = err
+			}
 
 			if !bRetry {
 				cc.log.Info(
@@ -192,13 +258,15 @@ func (cc *CosmosProvider) SendMessages(ctx context.Context, msgs []provider.Rela
 			zap.Uint("max_attempts", rtyAttNum),
 			zap.Error(err),
 		)
-	})); err != nil || resp == nil {
+	})); capErr != nil {
+		return cc.sendSplit(ctx, msgs, memo, timeoutHeight, capErr)
+	} else if err != nil || resp == nil {
 		// try one by one
 		var err error = nil
 		cc.log.Info("Try to send messages one by one:")
 		for _, msg := range msgs {
 			cc.log.Info(msg.Type(), zap.String("chain_id", cc.PCfg.ChainID), zap.String("seq", fmt.Sprintf("%d", msg.Seq())))
-			resp, _, err = cc.BuildAndBroadcast(ctx, []provider.RelayerMessage{msg}, memo)
+			resp, _, err = cc.BuildAndBroadcast(ctx, []provider.RelayerMessage{msg}, memo, timeoutHeight)
 			if err != nil {
 				cc.log.Info(msg.Type(),
 					zap.String("chain_id", cc.PCfg.ChainID),
@@ -214,12 +282,19 @@ func (cc *CosmosProvider) SendMessages(ctx context.Context, msgs []provider.Rela
 	}
 
 	rlyResp := &provider.RelayerTxResponse{
-		Height: resp.Height,
-		TxHash: resp.TxHash,
-		Code:   resp.Code,
-		Data:   resp.Data,
-		Events: parseEventsFromTxResponse(resp),
-	}
+		Height:  resp.Height,
+		TxHash:  resp.TxHash,
+		Code:    resp.Code,
+		Data:    resp.Data,
+		GasUsed: resp.GasUsed,
+		Events:  parseEventsFromTxResponse(resp),
+		Fee:     txFee(resp),
+	}
+	span.SetAttributes(
+		attribute.String("tx_hash", rlyResp.TxHash),
+		attribute.Int64("height", rlyResp.Height),
+		attribute.Int64("code", int64(rlyResp.Code)),
+	)
 
 	// transaction was executed, log the success or failure using the tx response code
 	// NOTE: error is nil, logic should use the returned error to determine if the
@@ -233,6 +308,34 @@ func (cc *CosmosProvider) SendMessages(ctx context.Context, msgs []provider.Rela
 	return rlyResp, true, nil
 }
 
+// txTimeoutHeight returns timeoutHeight if set, otherwise the latest height of the chain
+// plus the configured TxTimeoutHeightOffset, or 0 for no timeout if there is no offset.
+func (cc *CosmosProvider) txTimeoutHeight(ctx context.Context, timeoutHeight uint64) (uint64, error) {
+	if timeoutHeight != 0 || cc.PCfg.TxTimeoutHeightOffset == 0 {
+		return timeoutHeight, nil
+	}
+	h, err := cc.queryLatestHeight(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query latest height for the tx timeout height: %w", err)
+	}
+	return uint64(h) + cc.PCfg.TxTimeoutHeightOffset, nil
+}
+
+// txFee returns the fee deducted for the transaction, as emitted by the ante handler, or an empty string.
+func txFee(resp *sdk.TxResponse) string {
+	for _, event := range resp.Events {
+		if event.Type != sdk.EventTypeTx {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if string(attr.Key) == sdk.AttributeKeyFee {
+				return string(attr.Value)
+			}
+		}
+	}
+	return ""
+}
+
 func parseEventsFromTxResponse(resp *sdk.TxResponse) []provider.RelayerEvent {
 	var events []provider.RelayerEvent
 
@@ -255,24 +358,57 @@ func parseEventsFromTxResponse(resp *sdk.TxResponse) []provider.RelayerEvent {
 	return events
 }
 
-func (cc *CosmosProvider) buildMessages(ctx context.Context, msgs []provider.RelayerMessage, memo string) ([]byte, error) {
+// buildMessages returns the signed transaction of msgs, along with the sequence it is signed with.
+func (cc *CosmosProvider) buildMessages(ctx context.Context, msgs []provider.RelayerMessage, memo string, timeoutHeight uint64) ([]byte, uint64, error) {
 	// Query account details
 	txf, err := cc.PrepareFactory(cc.TxFactory())
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
+	// Account for the transactions of the key still pending in the mempool.
+	if address, err := cc.Address(); err == nil {
+		txf = txf.WithSequence(cc.sequences.next(address, txf.Sequence()))
+	}
+	seq := txf.Sequence()
+
+	memo, err = provider.RenderMemo(memo, provider.NewMemoData(ctx, cc.PCfg.ChainID, msgs))
+	if err != nil {
+		return nil, seq, err
+	}
 	if memo != "" {
 		txf = txf.WithMemo(memo)
 	}
 
+	timeoutHeight, err = cc.txTimeoutHeight(ctx, timeoutHeight)
+	if err != nil {
+		return nil, seq, err
+	}
+	if timeoutHeight != 0 {
+		txf = txf.WithTimeoutHeight(timeoutHeight)
+	}
+
+	boost := provider.FeeBoost(ctx)
+	if boost > 1 {
+		txf = boostFees(txf, boost)
+	}
+
 	// TODO: Make this work with new CalculateGas method
 	// TODO: This is related to GRPC client stuff?
 	// https://github.com/cosmos/cosmos-sdk/blob/5725659684fc93790a63981c653feee33ecf3225/client/tx/tx.go#L297
 	// If users pass gas adjustment, then calculate gas
-	_, adjusted, err := cc.CalculateGas(ctx, txf, CosmosMsgs(msgs...)...)
+	var adjusted uint64
+	if cc.PCfg.Multisig != nil {
+		adjusted, err = cc.simulateMultisig(ctx, txf, CosmosMsgs(msgs...)...)
+	} else {
+		_, adjusted, err = cc.CalculateGas(ctx, txf, CosmosMsgs(msgs...)...)
+	}
 	if err != nil {
-		return nil, err
+		return nil, seq, err
+	}
+
+	if err := cc.checkTxCaps(txf, adjusted, msgs); err != nil {
+		return nil, seq, err
 	}
 
 	// Set the gas amount on the transaction factory
@@ -287,7 +423,18 @@ func (cc *CosmosProvider) buildMessages(ctx context.Context, msgs []provider.Rel
 		}
 		return nil
 	}, retry.Context(ctx), rtyAtt, rtyDel, rtyErr); err != nil {
-		return nil, err
+		return nil, seq, err
+	}
+
+	if err := cc.setExtensionOptions(txb, boost); err != nil {
+		return nil, seq, err
+	}
+	if cc.PCfg.FeeGranter != "" {
+		granter, err := sdk.GetFromBech32(cc.PCfg.FeeGranter, cc.PCfg.AccountPrefix)
+		if err != nil {
+			return nil, seq, err
+		}
+		txb.SetFeeGranter(granter)
 	}
 
 	// Attach the signature to the transaction
@@ -296,18 +443,26 @@ func (cc *CosmosProvider) buildMessages(ctx context.Context, msgs []provider.Rel
 		cc.Codec.Marshaler.MustMarshalJSON(CosmosMsg(msg))
 	}
 
-	done := cc.SetSDKContext()
+	if cc.PCfg.Multisig != nil {
+		// Co-signers are not asked again on failure, the transaction is rebuilt by the caller instead.
+		if err := cc.signMultisig(ctx, txf, txb); err != nil {
+			return nil, seq, err
+		}
+	} else {
+		done := cc.SetSDKContext()
 
-	if err := retry.Do(func() error {
-		if err := tx.Sign(txf, cc.PCfg.Key, txb, false); err != nil {
-			return err
+		if err := retry.Do(func() error {
+			if err := tx.Sign(txf, cc.PCfg.Key, txb, false); err != nil {
+				return err
+			}
+			return nil
+		}, retry.Context(ctx), rtyAtt, rtyDel, rtyErr); err != nil {
+			done()
+			return nil, seq, err
 		}
-		return nil
-	}, retry.Context(ctx), rtyAtt, rtyDel, rtyErr); err != nil {
-		return nil, err
-	}
 
-	done()
+		done()
+	}
 
 	var txBytes []byte
 	// Generate the transaction bytes
@@ -319,10 +474,56 @@ func (cc *CosmosProvider) buildMessages(ctx context.Context, msgs []provider.Rel
 		}
 		return nil
 	}, retry.Context(ctx), rtyAtt, rtyDel, rtyErr); err != nil {
-		return nil, err
+		return nil, seq, err
+	}
+
+	return txBytes, seq, nil
+}
+
+// recordSequenceMismatch resyncs the sequence of address after the chain reported a mismatch for a transaction
+// signed with seq.
+func (cc *CosmosProvider) recordSequenceMismatch(address string, seq uint64, err error) {
+	fields := []zap.Field{
+		zap.String("chain_id", cc.PCfg.ChainID),
+		zap.String("address", address),
+		zap.Uint64("sequence", seq),
+	}
+	if expected, ok := cc.sequences.mismatch(address, err); ok {
+		cc.log.Info("Account sequence mismatch, resyncing to the sequence expected by the chain", append(fields, zap.Uint64("expected_sequence", expected))...)
+		return
+	}
+	cc.log.Info("Account sequence mismatch, resyncing from the chain", fields...)
+}
+
+// boostFees multiplies the fees of the transaction, or its gas prices, by boost, see provider.FeeBoost.
+func boostFees(txf tx.Factory, boost float64) tx.Factory {
+	d := sdk.MustNewDecFromStr(strconv.FormatFloat(boost, 'f', 6, 64))
+	if fees := txf.Fees(); !fees.IsZero() {
+		boosted := make(sdk.Coins, len(fees))
+		for i, c := range fees {
+			boosted[i] = sdk.NewCoin(c.Denom, sdk.NewDecFromInt(c.Amount).Mul(d).Ceil().TruncateInt())
+		}
+		return txf.WithFees(boosted.String())
 	}
+	if prices := txf.GasPrices(); !prices.IsZero() {
+		return txf.WithGasPrices(prices.MulDec(d).String())
+	}
+	return txf
+}
 
-	return txBytes, nil
+// setExtensionOptions sets the configured extension options on the transaction, before it is signed,
+// multiplying the priority fields of the options supporting one by boost, see provider.FeeBoost.
+func (cc *CosmosProvider) setExtensionOptions(txb client.TxBuilder, boost float64) error {
+	opts, err := cc.PCfg.txExtensionOptions(boost)
+	if err != nil || len(opts) == 0 {
+		return err
+	}
+	etxb, ok := txb.(authtx.ExtensionOptionsTxBuilder)
+	if !ok {
+		return fmt.Errorf("tx builder of chain %s does not support extension options", cc.PCfg.ChainID)
+	}
+	etxb.SetExtensionOptions(opts...)
+	return nil
 }
 
 // CreateClient creates an sdk.Msg to update the client on src with consensus state from dst
@@ -776,12 +977,12 @@ func (cc *CosmosProvider) AutoUpdateClient(ctx context.Context, dst provider.Cha
 		return 0, err
 	}
 
-	clientState, err := cc.queryTMClientState(ctx, srch, srcClientId)
+	clientState, trustingPeriod, err := cc.queryExpiringClientState(ctx, srch, srcClientId)
 	if err != nil {
 		return 0, err
 	}
 
-	if clientState.TrustingPeriod <= thresholdTime {
+	if trustingPeriod <= thresholdTime {
 		return 0, fmt.Errorf("client (%s) trusting period time is less than or equal to threshold time", srcClientId)
 	}
 
@@ -801,11 +1002,11 @@ func (cc *CosmosProvider) AutoUpdateClient(ctx context.Context, dst provider.Cha
 			zap.Uint("max_attempts", rtyAttNum),
 			zap.Error(err),
 		)
-		clientState, err = cc.queryTMClientState(ctx, srch, srcClientId)
+		clientState, trustingPeriod, err = cc.queryExpiringClientState(ctx, srch, srcClientId)
 		if err != nil {
 			clientState = nil
 			cc.log.Info(
-				"Failed to refresh tendermint client state in order to re-query consensus state ABCI",
+				"Failed to refresh client state in order to re-query consensus state ABCI",
 				zap.String("chain_id", cc.PCfg.ChainID),
 				zap.Error(err),
 			)
@@ -819,13 +1020,9 @@ func (cc *CosmosProvider) AutoUpdateClient(ctx context.Context, dst provider.Cha
 		return 0, err
 	}
 
-	consensusState, ok := exportedConsState.(*tmclient.ConsensusState)
-	if !ok {
-		return 0, fmt.Errorf("consensus state with clientID %s from chain %s is not IBC tendermint type",
-			srcClientId, cc.PCfg.ChainID)
-	}
-
-	expirationTime := consensusState.Timestamp.Add(clientState.TrustingPeriod)
+	// Tendermint and furyint consensus states both time their block in unix nanoseconds.
+	consensusTime := time.Unix(0, int64(exportedConsState.GetTimestamp()))
+	expirationTime := consensusTime.Add(trustingPeriod)
 
 	timeToExpiry := time.Until(expirationTime)
 
@@ -833,7 +1030,7 @@ func (cc *CosmosProvider) AutoUpdateClient(ctx context.Context, dst provider.Cha
 		return timeToExpiry, nil
 	}
 
-	if clientState.IsExpired(consensusState.Timestamp, time.Now()) {
+	if !expirationTime.After(time.Now()) {
 		return 0, fmt.Errorf("client (%s) is already expired on chain: %s", srcClientId, cc.PCfg.ChainID)
 	}
 
@@ -870,7 +1067,7 @@ func (cc *CosmosProvider) AutoUpdateClient(ctx context.Context, dst provider.Cha
 		zap.Uint64("cur_height", srcUpdateHeader.GetHeight().GetRevisionHeight()),
 	)
 
-	return clientState.TrustingPeriod, nil
+	return trustingPeriod, nil
 }
 
 // mustGetHeight takes the height inteface and returns the actual height
@@ -1644,6 +1841,132 @@ func (cc *CosmosProvider) RelayPacketFromSequence(
 	return nil, nil, fmt.Errorf("should have errored before here")
 }
 
+// QuerySendPacket returns the packet sent on the channel of the chain with the given sequence,
+// as found in the events of the transaction that sent it.
+func (cc *CosmosProvider) QuerySendPacket(ctx context.Context, srcChanId, srcPortId string, seq uint64) (provider.PacketInfo, error) {
+	txs, err := cc.QueryTxs(ctx, 1, 1000, rcvPacketQuery(srcChanId, int(seq)))
+	switch {
+	case err != nil:
+		return provider.PacketInfo{}, err
+	case len(txs) == 0:
+		return provider.PacketInfo{}, fmt.Errorf("%w: no transactions returned with query", provider.ErrPacketNotFound)
+	}
+
+	for _, tx := range txs {
+		for _, event := range tx.Events {
+			if event.EventType != spTag {
+				continue
+			}
+			packet, err := packetInfoFromEvent(uint64(tx.Height), event.Attributes)
+			if err != nil {
+				return provider.PacketInfo{}, err
+			}
+			if packet.Sequence == seq && packet.SourceChannel == srcChanId && packet.SourcePort == srcPortId {
+				return packet, nil
+			}
+		}
+	}
+	return provider.PacketInfo{}, fmt.Errorf("%w: sequence %d not found in the transaction sending it", provider.ErrPacketNotFound, seq)
+}
+
+func packetInfoFromEvent(height uint64, attributes map[string]string) (provider.PacketInfo, error) {
+	packet := provider.PacketInfo{Height: height}
+	for attributeKey, attributeValue := range attributes {
+		var err error
+		switch attributeKey {
+		case srcChanTag:
+			packet.SourceChannel = attributeValue
+		case srcPortTag:
+			packet.SourcePort = attributeValue
+		case dstChanTag:
+			packet.DestChannel = attributeValue
+		case dstPortTag:
+			packet.DestPort = attributeValue
+		case dataTag:
+			packet.Data = []byte(attributeValue)
+		case toHeightTag:
+			packet.TimeoutHeight, err = clienttypes.ParseHeight(attributeValue)
+		case toTSTag:
+			packet.TimeoutTimestamp, err = strconv.ParseUint(attributeValue, 10, 64)
+		case seqTag:
+			packet.Sequence, err = strconv.ParseUint(attributeValue, 10, 64)
+		}
+		if err != nil {
+			return provider.PacketInfo{}, fmt.Errorf("error parsing %s of packet: %w", attributeKey, err)
+		}
+	}
+	return packet, nil
+}
+
+// MsgTimeoutPacket constructs the MsgTimeout of a packet sent on the chain that was never received on dst,
+// proven at height dsth of dst. With onClose, it constructs the MsgTimeoutOnClose of a packet sent to
+// a channel end that was closed on dst instead.
+func (cc *CosmosProvider) MsgTimeoutPacket(
+	ctx context.Context,
+	dst provider.ChainProvider,
+	dsth int64,
+	packet provider.PacketInfo,
+	order chantypes.Order,
+	onClose bool,
+) (provider.RelayerMessage, error) {
+	signer, err := cc.Address()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		proofUnreceived []byte
+		proofHeight     clienttypes.Height
+		nextSeqRecv     = packet.Sequence
+	)
+	switch order {
+	case chantypes.ORDERED:
+		res, err := dst.QueryNextSeqRecv(ctx, dsth, packet.DestChannel, packet.DestPort)
+		if err != nil {
+			return nil, err
+		}
+		proofUnreceived, proofHeight, nextSeqRecv = res.Proof, res.ProofHeight, res.NextSequenceReceive
+	case chantypes.UNORDERED:
+		res, err := dst.QueryPacketReceipt(ctx, dsth, packet.DestChannel, packet.DestPort, packet.Sequence)
+		if err != nil {
+			return nil, err
+		}
+		proofUnreceived, proofHeight = res.Proof, res.ProofHeight
+	default:
+		return nil, fmt.Errorf("invalid order type %s, order should be %s or %s",
+			order, chantypes.ORDERED, chantypes.UNORDERED)
+	}
+	if proofUnreceived == nil {
+		return nil, fmt.Errorf("timeout packet [%s]seq{%d} has no proof of non-receipt", cc.PCfg.ChainID, packet.Sequence)
+	}
+
+	if !onClose {
+		return NewCosmosMessage(&chantypes.MsgTimeout{
+			Packet:           toCosmosPacket(packet),
+			ProofUnreceived:  proofUnreceived,
+			ProofHeight:      proofHeight,
+			NextSequenceRecv: nextSeqRecv,
+			Signer:           signer,
+		}), nil
+	}
+
+	chanRes, err := dst.QueryChannel(ctx, dsth, packet.DestChannel, packet.DestPort)
+	if err != nil {
+		return nil, err
+	}
+	if chanRes.Proof == nil {
+		return nil, fmt.Errorf("timeout on close packet [%s]seq{%d} has no proof of channel closure", cc.PCfg.ChainID, packet.Sequence)
+	}
+	return NewCosmosMessage(&chantypes.MsgTimeoutOnClose{
+		Packet:           toCosmosPacket(packet),
+		ProofUnreceived:  proofUnreceived,
+		ProofClose:       chanRes.Proof,
+		ProofHeight:      proofHeight,
+		NextSequenceRecv: nextSeqRecv,
+		Signer:           signer,
+	}), nil
+}
+
 // AcknowledgementFromSequence relays an acknowledgement with a given seq on src, source is the sending chain, destination is the receiving chain
 func (cc *CosmosProvider) AcknowledgementFromSequence(ctx context.Context,
 	dst provider.ChainProvider, dsth, seq uint64, dstChanId, dstPortId,
@@ -2017,15 +2340,25 @@ func (cc *CosmosProvider) InjectTrustedFields(ctx context.Context, header ibcexp
 	return NewClientHeader(header.ClientType(), signedHeader, validatorSet, trustedValidators, latestTrustedHeight)
 }
 
-// queryTMClientState retrieves the latest consensus state for a client in state at a given height
-// and unpacks/cast it to tendermint clientstate
-func (cc *CosmosProvider) queryTMClientState(ctx context.Context, srch int64, srcClientId string) (*tmclient.ClientState, error) {
+// queryExpiringClientState retrieves the state of a client at a given height, along with its trusting period,
+// failing for the clients other than tendermint and furyint ones, which do not expire.
+func (cc *CosmosProvider) queryExpiringClientState(ctx context.Context, srch int64, srcClientId string) (ibcexported.ClientState, time.Duration, error) {
 	clientStateRes, err := cc.QueryClientStateResponse(ctx, srch, srcClientId)
 	if err != nil {
-		return &tmclient.ClientState{}, err
+		return nil, 0, err
+	}
+
+	clientState, err := clienttypes.UnpackClientState(clientStateRes.ClientState)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	trustingPeriod, ok := provider.ClientTrustingPeriod(clientState)
+	if !ok {
+		return nil, 0, fmt.Errorf("client (%s) is of type %s, which does not expire", srcClientId, clientState.ClientType())
 	}
 
-	return castClientStateToTMType(clientStateRes.ClientState)
+	return clientState, trustingPeriod, nil
 }
 
 // NewClientState Create the ClientState we want on 'c' tracking 'dst'
@@ -2170,22 +2503,5 @@ func getValidatorSet(header ibcexported.Header) (*types2.ValidatorSet, error) {
 	return nil, fmt.Errorf("unsupported headeer client type %s", header.ClientType())
 }
 
-// castClientStateToTMType casts client state to tendermint type
-func castClientStateToTMType(cs *codectypes.Any) (*tmclient.ClientState, error) {
-	clientStateExported, err := clienttypes.UnpackClientState(cs)
-	if err != nil {
-		return &tmclient.ClientState{}, err
-	}
-
-	// cast from interface to concrete type
-	clientState, ok := clientStateExported.(*tmclient.ClientState)
-	if !ok {
-		return &tmclient.ClientState{},
-			fmt.Errorf("error when casting exported clientstate to tendermint type")
-	}
-
-	return clientState, nil
-}
-
 // DefaultUpgradePath is the default IBC upgrade path set for an on-chain light client
 var defaultUpgradePath = []string{"upgrade", "upgradedIBCState"}
@@ -0,0 +1,156 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// ErrTxCapExceeded is returned when the simulated gas or the fee of a transaction exceeds the max-gas or max-fee
+// of the chain, the transaction being refused instead of signed.
+var ErrTxCapExceeded = errors.New("transaction exceeds the gas or fee caps of the chain")
+
+// maxFee returns the parsed MaxFee of the chain, nil if fees are not capped.
+func (pc CosmosProviderConfig) maxFee() (sdk.Coins, error) {
+	if pc.MaxFee == "" {
+		return nil, nil
+	}
+	fee, err := sdk.ParseCoinsNormalized(pc.MaxFee)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max-fee %q: %w", pc.MaxFee, err)
+	}
+	if fee.IsZero() {
+		return nil, fmt.Errorf("invalid max-fee %q: must be positive", pc.MaxFee)
+	}
+	return fee, nil
+}
+
+// txFeeOf returns the fee of a transaction of gas built with txf: its fixed fees, or its gas prices times gas.
+func txFeeOf(txf tx.Factory, gas uint64) sdk.Coins {
+	if fees := txf.Fees(); !fees.IsZero() {
+		return fees
+	}
+	g := sdk.NewDecFromInt(sdk.NewIntFromUint64(gas))
+	var fee sdk.Coins
+	for _, p := range txf.GasPrices() {
+		fee = append(fee, sdk.NewCoin(p.Denom, p.Amount.Mul(g).Ceil().TruncateInt()))
+	}
+	return sdk.NewCoins(fee...)
+}
+
+// exceedsTxCaps returns an ErrTxCapExceeded error if gas is over maxGas or fee over maxFee, either being uncapped
+// if zero. Once fees are capped, the fee denoms missing from maxFee are refused.
+func exceedsTxCaps(gas, maxGas uint64, fee, maxFee sdk.Coins) error {
+	if maxGas != 0 && gas > maxGas {
+		return fmt.Errorf("%w: gas %d over max-gas %d", ErrTxCapExceeded, gas, maxGas)
+	}
+	if maxFee.IsZero() {
+		return nil
+	}
+	for _, c := range fee {
+		if c.Amount.GT(maxFee.AmountOf(c.Denom)) {
+			return fmt.Errorf("%w: fee %s over max-fee %s", ErrTxCapExceeded, fee, maxFee)
+		}
+	}
+	return nil
+}
+
+// checkTxCaps returns an ErrTxCapExceeded error, logging a warning, if the transaction of msgs built with txf
+// and simulated to use gas exceeds the max-gas or max-fee of the chain.
+func (cc *CosmosProvider) checkTxCaps(txf tx.Factory, gas uint64, msgs []provider.RelayerMessage) error {
+	maxFee, err := cc.PCfg.maxFee()
+	if err != nil {
+		return err
+	}
+	fee := txFeeOf(txf, gas)
+	if err := exceedsTxCaps(gas, cc.PCfg.MaxGas, fee, maxFee); err != nil {
+		types := make([]string, len(msgs))
+		for i, msg := range msgs {
+			types[i] = msg.Type()
+		}
+		cc.log.Warn(
+			"Refusing transaction over the gas or fee caps",
+			zap.String("chain_id", cc.PCfg.ChainID),
+			zap.Int("messages", len(msgs)),
+			zap.Strings("message_types", types),
+			zap.Uint64("gas", gas),
+			zap.Uint64("max_gas", cc.PCfg.MaxGas),
+			zap.String("fee", fee.String()),
+			zap.String("max_fee", cc.PCfg.MaxFee),
+		)
+		return err
+	}
+	return nil
+}
+
+// sendSplit sends msgs, refused as a single transaction over the gas or fee caps with capErr, as two transactions
+// of half the messages each, split again while they exceed the caps. A single message over the caps is skipped,
+// so a packet crafted to be expensive to relay cannot burn the wallet of the relayer.
+// The responses of the transactions sent are merged, see mergeTxResponses.
+func (cc *CosmosProvider) sendSplit(
+	ctx context.Context,
+	msgs []provider.RelayerMessage,
+	memo string,
+	timeoutHeight uint64,
+	capErr error,
+) (*provider.RelayerTxResponse, bool, error) {
+	if len(msgs) == 1 {
+		cc.log.Warn(
+			"Skipping message over the gas or fee caps",
+			zap.String("chain_id", cc.PCfg.ChainID),
+			zap.String("message_type", msgs[0].Type()),
+			zap.Uint64("seq", msgs[0].Seq()),
+			zap.Error(capErr),
+		)
+		return nil, false, capErr
+	}
+
+	half := len(msgs) / 2
+	cc.log.Warn(
+		"Splitting batch over the gas or fee caps",
+		zap.String("chain_id", cc.PCfg.ChainID),
+		zap.Int("messages", len(msgs)),
+		zap.Error(capErr),
+	)
+	var (
+		sent []*provider.RelayerTxResponse
+		err  error
+	)
+	for _, part := range [][]provider.RelayerMessage{msgs[:half], msgs[half:]} {
+		res, _, partErr := cc.SendMessagesWithTimeoutHeight(ctx, part, memo, timeoutHeight)
+		if partErr != nil {
+			err = partErr
+			continue
+		}
+		sent = append(sent, res)
+	}
+	if len(sent) == 0 {
+		return nil, false, err
+	}
+	return mergeTxResponses(sent), true, nil
+}
+
+// mergeTxResponses merges the responses of the transactions a batch was split into: the last transaction
+// identifies the merged response, whose gas used, events and fee are those of all the transactions.
+func mergeTxResponses(responses []*provider.RelayerTxResponse) *provider.RelayerTxResponse {
+	if len(responses) == 1 {
+		return responses[0]
+	}
+	merged := *responses[len(responses)-1]
+	merged.GasUsed, merged.Events = 0, nil
+	var fee sdk.Coins
+	for _, res := range responses {
+		merged.GasUsed += res.GasUsed
+		merged.Events = append(merged.Events, res.Events...)
+		if coins, err := sdk.ParseCoinsNormalized(res.Fee); err == nil {
+			fee = fee.Add(coins...)
+		}
+	}
+	merged.Fee = fee.String()
+	return &merged
+}
@@ -0,0 +1,51 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxCaps(t *testing.T) {
+	require.Equal(t, "2501urax", txFeeOf(tx.Factory{}.WithGasPrices("0.025urax"), 100_001).String())
+	require.Equal(t, "1000urax", txFeeOf(tx.Factory{}.WithGasPrices("0.025urax").WithFees("1000urax"), 100_001).String())
+
+	maxFee, err := CosmosProviderConfig{MaxFee: "5000urax"}.maxFee()
+	require.NoError(t, err)
+	require.NoError(t, exceedsTxCaps(200_000, 200_000, sdk.NewCoins(sdk.NewInt64Coin("urax", 5000)), maxFee))
+	require.NoError(t, exceedsTxCaps(10_000_000, 0, nil, nil))
+
+	err = exceedsTxCaps(200_001, 200_000, nil, maxFee)
+	require.ErrorIs(t, err, ErrTxCapExceeded)
+	require.EqualError(t, err, "transaction exceeds the gas or fee caps of the chain: gas 200001 over max-gas 200000")
+
+	err = exceedsTxCaps(200_000, 0, sdk.NewCoins(sdk.NewInt64Coin("urax", 5001)), maxFee)
+	require.EqualError(t, err, "transaction exceeds the gas or fee caps of the chain: fee 5001urax over max-fee 5000urax")
+
+	// Fees in denoms the caps do not cover are refused.
+	require.ErrorIs(t, exceedsTxCaps(200_000, 0, sdk.NewCoins(sdk.NewInt64Coin("uatom", 1)), maxFee), ErrTxCapExceeded)
+
+	_, err = CosmosProviderConfig{MaxFee: "0urax"}.maxFee()
+	require.EqualError(t, err, `invalid max-fee "0urax": must be positive`)
+	_, err = CosmosProviderConfig{MaxFee: "urax"}.maxFee()
+	require.Error(t, err)
+}
+
+func TestMergeTxResponses(t *testing.T) {
+	first := &provider.RelayerTxResponse{
+		Height: 10, TxHash: "A", GasUsed: 100, Fee: "3urax",
+		Events: []provider.RelayerEvent{{EventType: "update_client"}},
+	}
+	second := &provider.RelayerTxResponse{
+		Height: 11, TxHash: "B", GasUsed: 250, Fee: "5urax",
+		Events: []provider.RelayerEvent{{EventType: "recv_packet"}},
+	}
+	require.Same(t, first, mergeTxResponses([]*provider.RelayerTxResponse{first}))
+	require.Equal(t, &provider.RelayerTxResponse{
+		Height: 11, TxHash: "B", GasUsed: 350, Fee: "8urax",
+		Events: []provider.RelayerEvent{{EventType: "update_client"}, {EventType: "recv_packet"}},
+	}, mergeTxResponses([]*provider.RelayerTxResponse{first, second}))
+}
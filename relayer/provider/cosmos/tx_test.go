@@ -0,0 +1,57 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client/tx"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketInfoFromEvent(t *testing.T) {
+	packet, err := packetInfoFromEvent(42, map[string]string{
+		srcChanTag:  "channel-0",
+		srcPortTag:  "transfer",
+		dstChanTag:  "channel-7",
+		dstPortTag:  "transfer",
+		dataTag:     `{"amount":"1"}`,
+		toHeightTag: "1-1000",
+		toTSTag:     "1700000000000000000",
+		seqTag:      "12",
+	})
+	require.NoError(t, err)
+	require.Equal(t, provider.PacketInfo{
+		Height:           42,
+		Sequence:         12,
+		SourcePort:       "transfer",
+		SourceChannel:    "channel-0",
+		DestPort:         "transfer",
+		DestChannel:      "channel-7",
+		Data:             []byte(`{"amount":"1"}`),
+		TimeoutHeight:    clienttypes.NewHeight(1, 1000),
+		TimeoutTimestamp: 1700000000000000000,
+	}, packet)
+
+	_, err = packetInfoFromEvent(42, map[string]string{seqTag: "twelve"})
+	require.Error(t, err)
+}
+
+func TestBoostFees(t *testing.T) {
+	txf := tx.Factory{}.WithGasPrices("0.025uatom")
+	require.Equal(t, "0.050000000000000000uatom", boostFees(txf, 2).GasPrices().String())
+
+	txf = tx.Factory{}.WithFees("1001uatom")
+	require.Equal(t, "1502uatom", boostFees(txf, 1.5).Fees().String())
+
+	require.True(t, boostFees(tx.Factory{}, 2).GasPrices().IsZero())
+}
+
+func TestMaxTxBytes(t *testing.T) {
+	require.Equal(t, uint64(22020096), maxTxBytes(22020096, 0))
+	require.Equal(t, uint64(1048576), maxTxBytes(22020096, 1048576))
+	require.Equal(t, uint64(1048576), maxTxBytes(-1, 1048576))
+	require.Equal(t, uint64(500000), maxTxBytes(500000, 1048576))
+	require.Zero(t, maxTxBytes(0, 0))
+}
@@ -0,0 +1,126 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"go.uber.org/zap/zaptest"
+)
+
+// txNodeRPCClient is a node serving the accounts of the keys of the relayer, by address,
+// and the simulations of their transactions.
+type txNodeRPCClient struct {
+	fakeRPCClient
+	accounts map[string][]byte
+}
+
+func (c *txNodeRPCClient) ABCIQueryWithOptions(_ context.Context, path string, data bytes.HexBytes, _ rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	var value []byte
+	switch path {
+	case "/cosmos.auth.v1beta1.Query/Account":
+		var req authtypes.QueryAccountRequest
+		if err := req.Unmarshal(data); err != nil {
+			return nil, err
+		}
+		if value = c.accounts[req.Address]; value == nil {
+			return nil, errors.New("account " + req.Address + " not found")
+		}
+	case "/cosmos.tx.v1beta1.Service/Simulate":
+		var err error
+		if value, err = (&txtypes.SimulateResponse{GasInfo: &sdk.GasInfo{GasUsed: 100_000}}).Marshal(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unexpected query " + path)
+	}
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Value: value, Height: c.height}}, nil
+}
+
+// newTxTimeoutProvider returns a provider signing with the key relayer on a node at height 100,
+// along with the address of its key.
+func newTxTimeoutProvider(t *testing.T, offset uint64) (*CosmosProvider, sdk.AccAddress) {
+	kr := keyring.NewInMemory()
+	cfg := &lens.ChainClientConfig{Key: "relayer", ChainID: "ibc-0", AccountPrefix: "cosmos", GasAdjustment: 1.5, GasPrices: "0.01uatom"}
+	cc := &CosmosProvider{
+		log: zaptest.NewLogger(t),
+		ChainClient: lens.ChainClient{
+			Config:  cfg,
+			Keybase: kr,
+			Codec:   lens.MakeCodec(lens.ModuleBasics),
+		},
+		PCfg: CosmosProviderConfig{Key: "relayer", ChainID: "ibc-0", AccountPrefix: "cosmos", TxTimeoutHeightOffset: offset},
+	}
+
+	cc.RPCClient = &txNodeRPCClient{fakeRPCClient: fakeRPCClient{network: "ibc-0", height: 100}, accounts: make(map[string][]byte)}
+	return cc, addTxNodeAccount(t, cc, "relayer", 7, 3)
+}
+
+// addTxNodeAccount adds the key name to the keyring of cc, with an account on the node of cc, returning its address.
+func addTxNodeAccount(t *testing.T, cc *CosmosProvider, name string, number, sequence uint64) sdk.AccAddress {
+	info, _, err := cc.Keybase.NewMnemonic(name, keyring.English, sdk.FullFundraiserPath, "", hd.Secp256k1)
+	require.NoError(t, err)
+	address, err := cc.EncodeBech32AccAddr(info.GetAddress())
+	require.NoError(t, err)
+	account, err := codectypes.NewAnyWithValue(&authtypes.BaseAccount{Address: address, AccountNumber: number, Sequence: sequence})
+	require.NoError(t, err)
+	res, err := cc.Codec.Marshaler.Marshal(&authtypes.QueryAccountResponse{Account: account})
+	require.NoError(t, err)
+	cc.RPCClient.(*txNodeRPCClient).accounts[address] = res
+	return info.GetAddress()
+}
+
+func TestTxTimeoutHeight(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name          string
+		offset        uint64
+		timeoutHeight uint64
+		want          uint64
+	}{
+		{"explicit height", 0, 150, 150},
+		{"explicit height over offset", 20, 150, 150},
+		{"offset from latest height", 20, 0, 120},
+		{"no timeout", 0, 0, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cc, from := newTxTimeoutProvider(t, tc.offset)
+			h, err := cc.txTimeoutHeight(ctx, tc.timeoutHeight)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, h)
+
+			// The timeout height is set on the transaction built.
+			msg := NewCosmosMessage(&banktypes.MsgSend{
+				FromAddress: from.String(),
+				ToAddress:   from.String(),
+				Amount:      sdk.NewCoins(sdk.NewInt64Coin("uatom", 1)),
+			})
+			txBytes, seq, err := cc.buildMessages(ctx, []provider.RelayerMessage{msg}, "", tc.timeoutHeight)
+			require.NoError(t, err)
+			require.Equal(t, uint64(3), seq)
+			decoded, err := cc.Codec.TxConfig.TxDecoder()(txBytes)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, decoded.(sdk.TxWithTimeoutHeight).GetTimeoutHeight())
+		})
+	}
+
+	// The latest height is required to derive the timeout height from the offset.
+	cc, _ := newTxTimeoutProvider(t, 20)
+	cc.RPCClient.(*txNodeRPCClient).err = errors.New("connection refused")
+	_, err := cc.txTimeoutHeight(ctx, 0)
+	require.ErrorContains(t, err, "failed to query latest height for the tx timeout height")
+}
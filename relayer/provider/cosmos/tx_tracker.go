@@ -0,0 +1,412 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/tendermint/tendermint/mempool"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+// Broadcast modes of a chain.
+const (
+	// BroadcastModeSync broadcasts transactions once checked by the mempool of the endpoint, the default.
+	BroadcastModeSync = "sync"
+
+	// BroadcastModeAsync broadcasts transactions without waiting for the mempool of the endpoint to check them.
+	BroadcastModeAsync = "async"
+)
+
+const (
+	defaultTxPollInterval     = 500 * time.Millisecond
+	defaultTxEvictionTimeout  = time.Minute
+	defaultTxInclusionTimeout = 10 * time.Minute
+	defaultTxMaxRebroadcasts  = 2
+
+	// txQueryTimeout bounds each query and rebroadcast of a tracked transaction.
+	txQueryTimeout = 10 * time.Second
+
+	// maxFinishedTxs is how many of the last finished transactions are reported along with the pending ones.
+	maxFinishedTxs = 100
+)
+
+// TxTrackerConfig configures how the transactions broadcast to a chain are followed until included in a block.
+// Unset fields keep the defaults. Durations are strings such as "500ms" or "1m".
+type TxTrackerConfig struct {
+	// PollInterval is how often the pending transactions are looked up, 500ms by default.
+	PollInterval string `json:"poll-interval,omitempty" yaml:"poll-interval,omitempty"`
+
+	// EvictionTimeout is how long a transaction may stay pending before it is presumed evicted from the mempool
+	// and broadcast again, 1m by default.
+	EvictionTimeout string `json:"eviction-timeout,omitempty" yaml:"eviction-timeout,omitempty"`
+
+	// InclusionTimeout is how long a transaction is followed before giving up on its inclusion, 10m by default.
+	InclusionTimeout string `json:"inclusion-timeout,omitempty" yaml:"inclusion-timeout,omitempty"`
+
+	// MaxRebroadcasts is how many times a transaction is broadcast again, 2 if zero, none if negative.
+	MaxRebroadcasts int `json:"max-rebroadcasts,omitempty" yaml:"max-rebroadcasts,omitempty"`
+}
+
+// Validate returns an error if any field of the config is invalid.
+func (c *TxTrackerConfig) Validate() error {
+	_, err := c.settings()
+	return err
+}
+
+type txTrackerSettings struct {
+	pollInterval     time.Duration
+	evictionTimeout  time.Duration
+	inclusionTimeout time.Duration
+	maxRebroadcasts  uint
+}
+
+func (c *TxTrackerConfig) settings() (txTrackerSettings, error) {
+	s := txTrackerSettings{
+		pollInterval:     defaultTxPollInterval,
+		evictionTimeout:  defaultTxEvictionTimeout,
+		inclusionTimeout: defaultTxInclusionTimeout,
+		maxRebroadcasts:  defaultTxMaxRebroadcasts,
+	}
+	if c == nil {
+		return s, nil
+	}
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"poll-interval", c.PollInterval, &s.pollInterval},
+		{"eviction-timeout", c.EvictionTimeout, &s.evictionTimeout},
+		{"inclusion-timeout", c.InclusionTimeout, &s.inclusionTimeout},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return s, fmt.Errorf("invalid tx-tracker %s: %w", d.name, err)
+		}
+		if v <= 0 {
+			return s, fmt.Errorf("tx-tracker %s must be positive, got %s", d.name, v)
+		}
+		*d.dst = v
+	}
+	switch {
+	case c.MaxRebroadcasts < 0:
+		s.maxRebroadcasts = 0
+	case c.MaxRebroadcasts > 0:
+		s.maxRebroadcasts = uint(c.MaxRebroadcasts)
+	}
+	return s, nil
+}
+
+func validateBroadcastMode(mode string) error {
+	switch mode {
+	case "", BroadcastModeSync, BroadcastModeAsync:
+		return nil
+	default:
+		return fmt.Errorf("invalid broadcast-mode %q, must be %q or %q", mode, BroadcastModeSync, BroadcastModeAsync)
+	}
+}
+
+// txTracker follows the transactions broadcast to a chain until they are included in a block, broadcasting again
+// those presumed evicted from the mempool, and hands the result of each back to the caller which broadcast it.
+// The pending transactions are all looked up by a single goroutine, running while any is pending.
+type txTracker struct {
+	log     *zap.Logger
+	chainID string
+	cfg     txTrackerSettings
+
+	mu       sync.Mutex
+	pending  map[string]*trackedTx
+	finished []provider.TrackedTx
+	running  bool
+}
+
+type trackedTx struct {
+	tx      tmtypes.Tx
+	client  rpcclient.Client
+	decoder sdk.TxDecoder
+	done    chan txResult
+
+	// the fields below are guarded by the mutex of the txTracker
+	report        provider.TrackedTx
+	lastBroadcast time.Time
+	deadline      time.Time
+}
+
+type txResult struct {
+	resp *sdk.TxResponse
+	err  error
+}
+
+func newTxTracker(log *zap.Logger, chainID string, cfg txTrackerSettings) *txTracker {
+	return &txTracker{
+		log:     log,
+		chainID: chainID,
+		cfg:     cfg,
+		pending: make(map[string]*trackedTx),
+	}
+}
+
+// broadcast broadcasts tx through c with the broadcast mode, and waits until it is included in a block,
+// it fails the check of the mempool or the inclusion timeout passes.
+func (t *txTracker) broadcast(ctx context.Context, c rpcclient.Client, decoder sdk.TxDecoder, mode string, tx tmtypes.Tx) (*sdk.TxResponse, error) {
+	var (
+		res *ctypes.ResultBroadcastTx
+		err error
+	)
+	if mode == BroadcastModeAsync {
+		res, err = c.BroadcastTxAsync(ctx, tx)
+	} else {
+		res, err = c.BroadcastTxSync(ctx, tx)
+	}
+	if err != nil {
+		if res == nil {
+			return nil, err
+		}
+		return &sdk.TxResponse{Code: res.Code, Codespace: res.Codespace, TxHash: res.Hash.String()}, err
+	}
+	if err := checkTxError(res); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pt := &trackedTx{
+		tx:      tx,
+		client:  c,
+		decoder: decoder,
+		done:    make(chan txResult, 1),
+		report: provider.TrackedTx{
+			ChainID:     t.chainID,
+			TxHash:      res.Hash.String(),
+			Status:      provider.TxPending,
+			BroadcastAt: now.UTC(),
+		},
+		lastBroadcast: now,
+		deadline:      now.Add(t.cfg.inclusionTimeout),
+	}
+	t.track(pt)
+
+	// Transactions stay tracked once the caller gave up on them, so that they are still reported.
+	select {
+	case r := <-pt.done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// checkTxError returns the error of a transaction failing the check of the mempool, if it did.
+func checkTxError(res *ctypes.ResultBroadcastTx) error {
+	if res.Code == 0 {
+		return nil
+	}
+	if err := errors.Unwrap(sdkerrors.ABCIError(res.Codespace, res.Code, "error broadcasting transaction")); err != nil && err.Error() != "unknown" {
+		return err
+	}
+	return fmt.Errorf("transaction failed the mempool check with code %d in codespace %s: %s", res.Code, res.Codespace, res.Log)
+}
+
+func (t *txTracker) track(pt *trackedTx) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[pt.report.TxHash] = pt
+	if !t.running {
+		t.running = true
+		go t.run()
+	}
+}
+
+func (t *txTracker) run() {
+	ticker := time.NewTicker(t.cfg.pollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !t.poll() {
+			return
+		}
+	}
+}
+
+// poll checks each pending transaction once, returning false once none is pending.
+func (t *txTracker) poll() bool {
+	t.mu.Lock()
+	txs := make([]*trackedTx, 0, len(t.pending))
+	for _, pt := range t.pending {
+		txs = append(txs, pt)
+	}
+	t.mu.Unlock()
+
+	for _, pt := range txs {
+		t.check(pt)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.pending) == 0 {
+		t.running = false
+		return false
+	}
+	return true
+}
+
+// check looks up a pending transaction, finishing it once included or timed out,
+// and broadcasts it again once presumed evicted from the mempool.
+func (t *txTracker) check(pt *trackedTx) {
+	ctx, cancel := context.WithTimeout(context.Background(), txQueryTimeout)
+	defer cancel()
+
+	if res, err := pt.client.Tx(ctx, pt.tx.Hash(), false); err == nil {
+		resp, err := txResponse(pt.decoder, res)
+		t.finish(pt, resp, err)
+		return
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	deadline, lastBroadcast, rebroadcasts := pt.deadline, pt.lastBroadcast, pt.report.Rebroadcasts
+	t.mu.Unlock()
+	switch {
+	case now.After(deadline):
+		t.finish(pt, nil, fmt.Errorf("tx %s not included after %s: %w",
+			pt.report.TxHash, now.Sub(pt.report.BroadcastAt).Truncate(time.Second), lens.ErrTimeoutAfterWaitingForTxBroadcast))
+	case now.Sub(lastBroadcast) >= t.cfg.evictionTimeout && rebroadcasts < t.cfg.maxRebroadcasts:
+		t.rebroadcast(ctx, pt)
+	}
+}
+
+// rebroadcast broadcasts a transaction presumed evicted from the mempool again.
+func (t *txTracker) rebroadcast(ctx context.Context, pt *trackedTx) {
+	res, err := pt.client.BroadcastTxSync(ctx, pt.tx)
+	if err == nil {
+		err = checkTxError(res)
+	}
+
+	t.mu.Lock()
+	pt.lastBroadcast = time.Now()
+	pt.report.Rebroadcasts++
+	rebroadcasts := pt.report.Rebroadcasts
+	if isSequenceMismatch(err) {
+		// The sequence of the transaction was used, by the transaction itself if it was included since
+		// or by another one: give the transaction one last chance to be found before giving up on it.
+		if last := pt.lastBroadcast.Add(t.cfg.evictionTimeout); last.Before(pt.deadline) {
+			pt.deadline = last
+		}
+	}
+	t.mu.Unlock()
+
+	switch {
+	case err == nil:
+		t.log.Info(
+			"Broadcast transaction presumed evicted from the mempool again",
+			zap.String("chain_id", t.chainID),
+			zap.String("tx_hash", pt.report.TxHash),
+			zap.Uint("rebroadcasts", rebroadcasts),
+		)
+	case strings.Contains(err.Error(), mempool.ErrTxInCache.Error()), isSequenceMismatch(err):
+		// still pending in the mempool, or used its sequence
+	default:
+		t.log.Warn(
+			"Failed to broadcast transaction presumed evicted from the mempool again",
+			zap.String("chain_id", t.chainID),
+			zap.String("tx_hash", pt.report.TxHash),
+			zap.Uint("rebroadcasts", rebroadcasts),
+			zap.Error(err),
+		)
+	}
+}
+
+func (t *txTracker) finish(pt *trackedTx, resp *sdk.TxResponse, err error) {
+	t.mu.Lock()
+	delete(t.pending, pt.report.TxHash)
+	r := &pt.report
+	switch {
+	case errors.Is(err, lens.ErrTimeoutAfterWaitingForTxBroadcast):
+		r.Status = provider.TxTimedOut
+	case err != nil, resp.Code != 0:
+		r.Status = provider.TxFailed
+	default:
+		r.Status = provider.TxIncluded
+	}
+	if resp != nil {
+		r.Height, r.Code, r.GasWanted, r.GasUsed = resp.Height, resp.Code, resp.GasWanted, resp.GasUsed
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	t.finished = append(t.finished, *r)
+	if len(t.finished) > maxFinishedTxs {
+		t.finished = t.finished[len(t.finished)-maxFinishedTxs:]
+	}
+	report := *r
+	t.mu.Unlock()
+
+	t.log.Debug(
+		"Tracked transaction finished",
+		zap.String("chain_id", t.chainID),
+		zap.String("tx_hash", report.TxHash),
+		zap.String("status", report.Status),
+		zap.Int64("height", report.Height),
+		zap.Uint32("code", report.Code),
+		zap.Int64("gas_wanted", report.GasWanted),
+		zap.Int64("gas_used", report.GasUsed),
+		zap.Uint("rebroadcasts", report.Rebroadcasts),
+	)
+	pt.done <- txResult{resp: resp, err: err}
+}
+
+// snapshot returns the pending transactions, oldest first, followed by the last finished ones.
+func (t *txTracker) snapshot() []provider.TrackedTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := make([]provider.TrackedTx, 0, len(t.pending)+len(t.finished))
+	for _, pt := range t.pending {
+		res = append(res, pt.report)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].BroadcastAt.Before(res[j].BroadcastAt) })
+	return append(res, t.finished...)
+}
+
+// txResponse decodes the transaction included in a block.
+func txResponse(decoder sdk.TxDecoder, res *ctypes.ResultTx) (*sdk.TxResponse, error) {
+	tx, err := decoder(res.Tx)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := tx.(interface{ AsAny() *codectypes.Any })
+	if !ok {
+		return nil, fmt.Errorf("expecting a type implementing AsAny, got: %T", tx)
+	}
+	return sdk.NewResponseResultTx(res, p.AsAny(), time.Now().Format(time.RFC3339)), nil
+}
+
+// broadcastTx broadcasts the signed transaction txBytes with the broadcast mode of the chain,
+// and waits until it is included in a block.
+func (cc *CosmosProvider) broadcastTx(ctx context.Context, txBytes []byte) (*sdk.TxResponse, error) {
+	if cc.txs == nil {
+		return cc.BroadcastTx(ctx, txBytes)
+	}
+	return cc.txs.broadcast(ctx, cc.RPCClient, cc.Codec.TxConfig.TxDecoder(), cc.PCfg.BroadcastMode, txBytes)
+}
+
+// TrackedTxs returns the transactions broadcast to the chain still pending, oldest first,
+// followed by the last included, failed or timed out ones.
+func (cc *CosmosProvider) TrackedTxs() []provider.TrackedTx {
+	if cc.txs == nil {
+		return nil
+	}
+	return cc.txs.snapshot()
+}
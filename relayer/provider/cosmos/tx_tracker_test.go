@@ -0,0 +1,144 @@
+package cosmos
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+// mempoolClient includes the transactions broadcast to it once broadcast includeAfter times.
+type mempoolClient struct {
+	rpcclient.Client
+
+	mu           sync.Mutex
+	includeAfter int
+	checkCode    uint32
+	syncs        int
+	asyncs       int
+}
+
+func (c *mempoolClient) BroadcastTxSync(_ context.Context, tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.syncs++
+	return &ctypes.ResultBroadcastTx{Hash: tx.Hash(), Code: c.checkCode, Codespace: "sdk"}, nil
+}
+
+func (c *mempoolClient) BroadcastTxAsync(_ context.Context, tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.asyncs++
+	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+}
+
+func (c *mempoolClient) Tx(_ context.Context, hash []byte, _ bool) (*ctypes.ResultTx, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.includeAfter == 0 || c.syncs+c.asyncs < c.includeAfter {
+		return nil, lens.ErrTimeoutAfterWaitingForTxBroadcast
+	}
+	return &ctypes.ResultTx{Hash: hash, Height: 42, TxResult: abci.ResponseDeliverTx{GasWanted: 100, GasUsed: 80}}, nil
+}
+
+func (c *mempoolClient) broadcasts() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.syncs, c.asyncs
+}
+
+type decodedTx struct{}
+
+func (decodedTx) GetMsgs() []sdk.Msg     { return nil }
+func (decodedTx) ValidateBasic() error   { return nil }
+func (decodedTx) AsAny() *codectypes.Any { return &codectypes.Any{} }
+func decodeTx([]byte) (sdk.Tx, error)    { return decodedTx{}, nil }
+
+func testTxTracker(cfg *TxTrackerConfig) *txTracker {
+	s, _ := cfg.settings()
+	return newTxTracker(zap.NewNop(), "chain-a", s)
+}
+
+func TestTxTrackerConfigValidate(t *testing.T) {
+	var unset *TxTrackerConfig
+	s, err := unset.settings()
+	require.NoError(t, err)
+	require.Equal(t, defaultTxInclusionTimeout, s.inclusionTimeout)
+	require.Equal(t, uint(defaultTxMaxRebroadcasts), s.maxRebroadcasts)
+
+	s, err = (&TxTrackerConfig{PollInterval: "1s", MaxRebroadcasts: -1}).settings()
+	require.NoError(t, err)
+	require.Equal(t, time.Second, s.pollInterval)
+	require.Zero(t, s.maxRebroadcasts)
+
+	require.ErrorContains(t, (&TxTrackerConfig{EvictionTimeout: "soon"}).Validate(), "invalid tx-tracker eviction-timeout")
+	require.ErrorContains(t, (&TxTrackerConfig{InclusionTimeout: "-1s"}).Validate(), "must be positive")
+	require.NoError(t, validateBroadcastMode(BroadcastModeAsync))
+	require.ErrorContains(t, validateBroadcastMode("block"), "invalid broadcast-mode")
+}
+
+func TestTxTrackerIncludes(t *testing.T) {
+	ctx := context.Background()
+	tracker := testTxTracker(&TxTrackerConfig{PollInterval: "5ms"})
+	c := &mempoolClient{includeAfter: 1}
+
+	resp, err := tracker.broadcast(ctx, c, decodeTx, "", tmtypes.Tx("tx-1"))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), resp.Height)
+	require.Equal(t, int64(80), resp.GasUsed)
+	syncs, asyncs := c.broadcasts()
+	require.Equal(t, 1, syncs)
+	require.Zero(t, asyncs)
+
+	txs := tracker.snapshot()
+	require.Len(t, txs, 1)
+	require.Equal(t, provider.TxIncluded, txs[0].Status)
+	require.Equal(t, int64(100), txs[0].GasWanted)
+	require.Equal(t, tmbytes.HexBytes(tmtypes.Tx("tx-1").Hash()).String(), txs[0].TxHash)
+}
+
+func TestTxTrackerRebroadcastsEvictedTxs(t *testing.T) {
+	ctx := context.Background()
+	tracker := testTxTracker(&TxTrackerConfig{PollInterval: "5ms", EvictionTimeout: "10ms"})
+
+	// Transactions broadcast asynchronously are broadcast again synchronously once presumed evicted.
+	c := &mempoolClient{includeAfter: 2}
+	resp, err := tracker.broadcast(ctx, c, decodeTx, BroadcastModeAsync, tmtypes.Tx("tx-1"))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), resp.Height)
+	syncs, asyncs := c.broadcasts()
+	require.Equal(t, 1, syncs)
+	require.Equal(t, 1, asyncs)
+	require.Equal(t, uint(1), tracker.snapshot()[0].Rebroadcasts)
+
+	// Transactions never included time out once broadcast again as many times as configured.
+	tracker = testTxTracker(&TxTrackerConfig{PollInterval: "5ms", EvictionTimeout: "10ms", InclusionTimeout: "100ms", MaxRebroadcasts: 2})
+	c = &mempoolClient{}
+	_, err = tracker.broadcast(ctx, c, decodeTx, "", tmtypes.Tx("tx-2"))
+	require.ErrorIs(t, err, lens.ErrTimeoutAfterWaitingForTxBroadcast)
+	syncs, _ = c.broadcasts()
+	require.Equal(t, 3, syncs)
+	txs := tracker.snapshot()
+	require.Equal(t, provider.TxTimedOut, txs[0].Status)
+	require.Equal(t, uint(2), txs[0].Rebroadcasts)
+}
+
+func TestTxTrackerCheckFailures(t *testing.T) {
+	tracker := testTxTracker(nil)
+	c := &mempoolClient{checkCode: 32}
+	_, err := tracker.broadcast(context.Background(), c, decodeTx, "", tmtypes.Tx("tx-1"))
+	require.True(t, isSequenceMismatch(err))
+	require.Empty(t, tracker.snapshot(), "transactions failing the mempool check are not tracked")
+}
@@ -0,0 +1,107 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+// defaultBaseFeeMultiplier is the headroom kept over the base fee of the latest block, so that a transaction
+// remains includable while the base fee rises over the next blocks: it can grow by 12.5% per full block.
+const defaultBaseFeeMultiplier = 2
+
+// txFees are the gas prices of a transaction, either the fee cap and tip of an EIP-1559 transaction
+// or the gas price of a legacy transaction if gasPrice is set.
+type txFees struct {
+	feeCap, tip *big.Int
+	gasPrice    *big.Int
+}
+
+// dynamicFees returns the fee cap and tip of an EIP-1559 transaction included in a block of the given base fee:
+// the suggested tip capped by maxTip, plus the base fee scaled by multiplier as headroom, capped by maxFeeCap.
+// Caps are ignored if nil. It returns an error if the base fee already exceeds maxFeeCap,
+// since the transaction could not be included.
+func dynamicFees(baseFee, suggestedTip, maxFeeCap, maxTip *big.Int, multiplier float64) (feeCap, tip *big.Int, err error) {
+	if maxFeeCap != nil && baseFee.Cmp(maxFeeCap) > 0 {
+		return nil, nil, fmt.Errorf("base fee %s exceeds max-fee-per-gas %s", baseFee, maxFeeCap)
+	}
+	tip = new(big.Int).Set(suggestedTip)
+	if maxTip != nil && tip.Cmp(maxTip) > 0 {
+		tip.Set(maxTip)
+	}
+	if multiplier <= 0 {
+		multiplier = defaultBaseFeeMultiplier
+	}
+	headroom, _ := new(big.Float).Mul(new(big.Float).SetInt(baseFee), big.NewFloat(multiplier)).Int(nil)
+	if headroom.Cmp(baseFee) < 0 {
+		headroom.Set(baseFee)
+	}
+	feeCap = headroom.Add(headroom, tip)
+	if maxFeeCap != nil && feeCap.Cmp(maxFeeCap) > 0 {
+		feeCap.Set(maxFeeCap)
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip.Set(feeCap)
+	}
+	return feeCap, tip, nil
+}
+
+// legacyGasPrice returns the suggested gas price capped by maxGasPrice, if non-nil.
+func legacyGasPrice(suggested, maxGasPrice *big.Int) *big.Int {
+	if maxGasPrice != nil && suggested.Cmp(maxGasPrice) > 0 {
+		return new(big.Int).Set(maxGasPrice)
+	}
+	return new(big.Int).Set(suggested)
+}
+
+// boostPrice returns price multiplied by boost, see provider.FeeBoost, rounded up.
+func boostPrice(price *big.Int, boost float64) *big.Int {
+	if boost <= 1 {
+		return price
+	}
+	boosted, acc := new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(boost)).Int(nil)
+	if acc == big.Below {
+		boosted.Add(boosted, big.NewInt(1))
+	}
+	return boosted
+}
+
+// fees returns the gas prices of the next transaction: EIP-1559 fees if the latest block has a base fee,
+// a legacy gas price otherwise.
+func (p *EVMProvider) fees(ctx context.Context) (txFees, error) {
+	maxFeeCap, maxTip, err := p.PCfg.feeCaps()
+	if err != nil {
+		return txFees{}, err
+	}
+	head, err := p.rpc.headerByNumber(ctx, 0)
+	if err != nil {
+		return txFees{}, fmt.Errorf("failed to query latest block: %w", err)
+	}
+	if head.BaseFee == nil {
+		suggested, err := p.rpc.gasPrice(ctx)
+		if err != nil {
+			return txFees{}, fmt.Errorf("failed to query gas price: %w", err)
+		}
+		return txFees{gasPrice: legacyGasPrice(boostPrice(suggested, provider.FeeBoost(ctx)), maxFeeCap)}, nil
+	}
+	suggestedTip, err := p.rpc.maxPriorityFeePerGas(ctx)
+	if err != nil {
+		return txFees{}, fmt.Errorf("failed to query priority fee: %w", err)
+	}
+	suggestedTip = boostPrice(suggestedTip, provider.FeeBoost(ctx))
+	feeCap, tip, err := dynamicFees(head.BaseFee, suggestedTip, maxFeeCap, maxTip, p.PCfg.BaseFeeMultiplier)
+	if err != nil {
+		return txFees{}, err
+	}
+	return txFees{feeCap: feeCap, tip: tip}, nil
+}
+
+// gasLimit returns the gas estimated for a transaction, scaled by the gas adjustment of the chain.
+func (p *EVMProvider) gasLimit(estimated uint64) uint64 {
+	if p.PCfg.GasAdjustment <= 1 {
+		return estimated
+	}
+	return uint64(float64(estimated) * p.PCfg.GasAdjustment)
+}
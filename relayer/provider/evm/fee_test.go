@@ -0,0 +1,55 @@
+package evm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicFees(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		baseFee, tip      int64
+		maxFeeCap, maxTip int64
+		multiplier        float64
+		feeCap, wantTip   int64
+	}{
+		{name: "default headroom", baseFee: 100, tip: 2, feeCap: 202, wantTip: 2},
+		{name: "custom multiplier", baseFee: 100, tip: 2, multiplier: 1.5, feeCap: 152, wantTip: 2},
+		{name: "multiplier below one keeps the base fee", baseFee: 100, tip: 2, multiplier: 0.5, feeCap: 102, wantTip: 2},
+		{name: "tip capped", baseFee: 100, tip: 50, maxTip: 10, feeCap: 210, wantTip: 10},
+		{name: "fee cap capped", baseFee: 100, tip: 2, maxFeeCap: 150, feeCap: 150, wantTip: 2},
+		{name: "tip capped by fee cap", baseFee: 100, tip: 80, maxFeeCap: 150, feeCap: 150, wantTip: 80},
+		{name: "tip over fee cap", baseFee: 100, tip: 200, maxFeeCap: 150, feeCap: 150, wantTip: 150},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var maxFeeCap, maxTip *big.Int
+			if tc.maxFeeCap > 0 {
+				maxFeeCap = big.NewInt(tc.maxFeeCap)
+			}
+			if tc.maxTip > 0 {
+				maxTip = big.NewInt(tc.maxTip)
+			}
+			feeCap, tip, err := dynamicFees(big.NewInt(tc.baseFee), big.NewInt(tc.tip), maxFeeCap, maxTip, tc.multiplier)
+			require.NoError(t, err)
+			require.Equal(t, tc.feeCap, feeCap.Int64(), "fee cap")
+			require.Equal(t, tc.wantTip, tip.Int64(), "tip")
+		})
+	}
+
+	_, _, err := dynamicFees(big.NewInt(200), big.NewInt(1), big.NewInt(150), nil, 0)
+	require.ErrorContains(t, err, "exceeds max-fee-per-gas")
+}
+
+func TestLegacyGasPrice(t *testing.T) {
+	require.Equal(t, int64(100), legacyGasPrice(big.NewInt(100), nil).Int64())
+	require.Equal(t, int64(100), legacyGasPrice(big.NewInt(100), big.NewInt(150)).Int64())
+	require.Equal(t, int64(50), legacyGasPrice(big.NewInt(100), big.NewInt(50)).Int64())
+}
+
+func TestBoostPrice(t *testing.T) {
+	require.Equal(t, int64(100), boostPrice(big.NewInt(100), 1).Int64())
+	require.Equal(t, int64(200), boostPrice(big.NewInt(100), 2).Int64())
+	require.Equal(t, int64(151), boostPrice(big.NewInt(101), 1.49).Int64())
+}
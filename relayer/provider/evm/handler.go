@@ -0,0 +1,248 @@
+package evm
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// handlerABI is the interface of the IBC handler contract: its methods take the ibc-go messages protobuf encoded,
+// its view functions return the IBC state protobuf encoded, and its events are the IBC events,
+// with the same content as the events of the ibc-go module.
+//
+//go:embed ibc_handler.abi.json
+var handlerABIJSON []byte
+
+var handlerABI = mustParseABI(handlerABIJSON)
+
+func mustParseABI(bz []byte) abi.ABI {
+	a, err := abi.JSON(bytes.NewReader(bz))
+	if err != nil {
+		panic(fmt.Errorf("invalid ibc handler abi: %w", err))
+	}
+	return a
+}
+
+// handlerEventTypes maps the events of the IBC handler to the IBC event types of ibc-go.
+var handlerEventTypes = map[string]string{
+	"SendPacket":            chantypes.EventTypeSendPacket,
+	"RecvPacket":            chantypes.EventTypeRecvPacket,
+	"WriteAcknowledgement":  chantypes.EventTypeWriteAck,
+	"AcknowledgePacket":     chantypes.EventTypeAcknowledgePacket,
+	"TimeoutPacket":         chantypes.EventTypeTimeoutPacket,
+	"TimeoutOnClosePacket":  chantypes.EventTypeTimeoutPacketOnClose,
+	"CreateClient":          clienttypes.EventTypeCreateClient,
+	"UpdateClient":          clienttypes.EventTypeUpdateClient,
+	"UpgradeClient":         clienttypes.EventTypeUpgradeClient,
+	"ConnectionOpenInit":    conntypes.EventTypeConnectionOpenInit,
+	"ConnectionOpenTry":     conntypes.EventTypeConnectionOpenTry,
+	"ConnectionOpenAck":     conntypes.EventTypeConnectionOpenAck,
+	"ConnectionOpenConfirm": conntypes.EventTypeConnectionOpenConfirm,
+	"ChannelOpenInit":       chantypes.EventTypeChannelOpenInit,
+	"ChannelOpenTry":        chantypes.EventTypeChannelOpenTry,
+	"ChannelOpenAck":        chantypes.EventTypeChannelOpenAck,
+	"ChannelOpenConfirm":    chantypes.EventTypeChannelOpenConfirm,
+	"ChannelCloseInit":      chantypes.EventTypeChannelCloseInit,
+	"ChannelCloseConfirm":   chantypes.EventTypeChannelCloseConfirm,
+}
+
+// ClientEvent holds the content of a client event.
+type ClientEvent struct {
+	ClientID        string
+	ClientType      string
+	ConsensusHeight clienttypes.Height
+	Header          []byte
+}
+
+// IBCEvent is an IBC event emitted by the IBC handler contract, holding either a packet, channel,
+// connection or client, depending on its type.
+type IBCEvent struct {
+	// EventType is the ibc-go event type of the event. A RecvPacket event and the WriteAcknowledgement event
+	// of the packet emitted by the same transaction are merged into one recv_packet event holding the ack.
+	EventType string
+	Height    uint64
+	TxHash    common.Hash
+
+	Packet     *provider.PacketInfo
+	Channel    *provider.ChannelInfo
+	Connection *provider.ConnectionInfo
+	Client     *ClientEvent
+}
+
+// ParseIBCEvents returns the IBC events of the logs emitted by the IBC handler at address, in order.
+// Logs of other contracts and unknown events are skipped.
+func ParseIBCEvents(address common.Address, logs []types.Log) ([]IBCEvent, error) {
+	var events []IBCEvent
+	for _, l := range logs {
+		if l.Address != address || len(l.Topics) == 0 || l.Removed {
+			continue
+		}
+		e, err := handlerABI.EventByID(l.Topics[0])
+		if err != nil {
+			continue
+		}
+		eventType, ok := handlerEventTypes[e.Name]
+		if !ok {
+			continue
+		}
+		values, err := e.Inputs.Unpack(l.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s event in tx %s: %w", e.Name, l.TxHash, err)
+		}
+		event := IBCEvent{EventType: eventType, Height: l.BlockNumber, TxHash: l.TxHash}
+		switch eventType {
+		case chantypes.EventTypeSendPacket, chantypes.EventTypeRecvPacket, chantypes.EventTypeWriteAck,
+			chantypes.EventTypeAcknowledgePacket, chantypes.EventTypeTimeoutPacket, chantypes.EventTypeTimeoutPacketOnClose:
+			event.Packet = packetFromValues(l.BlockNumber, values)
+		case clienttypes.EventTypeCreateClient, clienttypes.EventTypeUpdateClient, clienttypes.EventTypeUpgradeClient:
+			event.Client = clientFromValues(values)
+		case conntypes.EventTypeConnectionOpenInit, conntypes.EventTypeConnectionOpenTry,
+			conntypes.EventTypeConnectionOpenAck, conntypes.EventTypeConnectionOpenConfirm:
+			event.Connection = connectionFromValues(l.BlockNumber, values)
+		default:
+			event.Channel = channelFromValues(l.BlockNumber, values)
+		}
+
+		if eventType == chantypes.EventTypeWriteAck {
+			if recv := matchingRecvPacket(events, event); recv != nil {
+				recv.Packet.Ack = event.Packet.Ack
+				continue
+			}
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// matchingRecvPacket returns the recv_packet event of the packet acknowledged by the write_acknowledgement event ack
+// in the same transaction, if any.
+func matchingRecvPacket(events []IBCEvent, ack IBCEvent) *IBCEvent {
+	for i := len(events) - 1; i >= 0; i-- {
+		e := &events[i]
+		if e.TxHash != ack.TxHash {
+			break
+		}
+		if e.EventType == chantypes.EventTypeRecvPacket && e.Packet.Sequence == ack.Packet.Sequence &&
+			e.Packet.DestChannel == ack.Packet.DestChannel && e.Packet.DestPort == ack.Packet.DestPort {
+			return e
+		}
+	}
+	return nil
+}
+
+func packetFromValues(height uint64, v []any) *provider.PacketInfo {
+	p := &provider.PacketInfo{
+		Height:        height,
+		Sequence:      v[0].(uint64),
+		SourcePort:    v[1].(string),
+		SourceChannel: v[2].(string),
+		DestPort:      v[3].(string),
+		DestChannel:   v[4].(string),
+		Data:          v[5].([]byte),
+		TimeoutHeight: clienttypes.Height{
+			RevisionNumber: v[6].(uint64),
+			RevisionHeight: v[7].(uint64),
+		},
+		TimeoutTimestamp: v[8].(uint64),
+	}
+	if len(v) > 9 {
+		p.Ack = v[9].([]byte)
+	}
+	return p
+}
+
+func clientFromValues(v []any) *ClientEvent {
+	c := &ClientEvent{
+		ClientID:   v[0].(string),
+		ClientType: v[1].(string),
+		ConsensusHeight: clienttypes.Height{
+			RevisionNumber: v[2].(uint64),
+			RevisionHeight: v[3].(uint64),
+		},
+	}
+	if len(v) > 4 {
+		c.Header = v[4].([]byte)
+	}
+	return c
+}
+
+func connectionFromValues(height uint64, v []any) *provider.ConnectionInfo {
+	return &provider.ConnectionInfo{
+		Height:               height,
+		ConnID:               v[0].(string),
+		ClientID:             v[1].(string),
+		CounterpartyConnID:   v[2].(string),
+		CounterpartyClientID: v[3].(string),
+	}
+}
+
+func channelFromValues(height uint64, v []any) *provider.ChannelInfo {
+	return &provider.ChannelInfo{
+		Height:                height,
+		PortID:                v[0].(string),
+		ChannelID:             v[1].(string),
+		CounterpartyPortID:    v[2].(string),
+		CounterpartyChannelID: v[3].(string),
+		ConnID:                v[4].(string),
+		Order:                 chantypes.Order(v[5].(uint8)),
+		Version:               v[6].(string),
+	}
+}
+
+// RelayerEvent returns the event with the attributes of the equivalent ibc-go event.
+func (e IBCEvent) RelayerEvent() provider.RelayerEvent {
+	attrs := make(map[string]string)
+	switch {
+	case e.Packet != nil:
+		attrs[chantypes.AttributeKeySequence] = strconv.FormatUint(e.Packet.Sequence, 10)
+		attrs[chantypes.AttributeKeySrcPort] = e.Packet.SourcePort
+		attrs[chantypes.AttributeKeySrcChannel] = e.Packet.SourceChannel
+		attrs[chantypes.AttributeKeyDstPort] = e.Packet.DestPort
+		attrs[chantypes.AttributeKeyDstChannel] = e.Packet.DestChannel
+		attrs[chantypes.AttributeKeyDataHex] = hex.EncodeToString(e.Packet.Data)
+		attrs[chantypes.AttributeKeyTimeoutHeight] = e.Packet.TimeoutHeight.String()
+		attrs[chantypes.AttributeKeyTimeoutTimestamp] = strconv.FormatUint(e.Packet.TimeoutTimestamp, 10)
+		if len(e.Packet.Ack) > 0 {
+			attrs[chantypes.AttributeKeyAckHex] = hex.EncodeToString(e.Packet.Ack)
+		}
+	case e.Channel != nil:
+		attrs[chantypes.AttributeKeyPortID] = e.Channel.PortID
+		attrs[chantypes.AttributeKeyChannelID] = e.Channel.ChannelID
+		attrs[chantypes.AttributeCounterpartyPortID] = e.Channel.CounterpartyPortID
+		attrs[chantypes.AttributeCounterpartyChannelID] = e.Channel.CounterpartyChannelID
+		attrs[chantypes.AttributeKeyConnectionID] = e.Channel.ConnID
+		attrs[chantypes.AttributeVersion] = e.Channel.Version
+	case e.Connection != nil:
+		attrs[conntypes.AttributeKeyConnectionID] = e.Connection.ConnID
+		attrs[conntypes.AttributeKeyClientID] = e.Connection.ClientID
+		attrs[conntypes.AttributeKeyCounterpartyConnectionID] = e.Connection.CounterpartyConnID
+		attrs[conntypes.AttributeKeyCounterpartyClientID] = e.Connection.CounterpartyClientID
+	case e.Client != nil:
+		attrs[clienttypes.AttributeKeyClientID] = e.Client.ClientID
+		attrs[clienttypes.AttributeKeyClientType] = e.Client.ClientType
+		attrs[clienttypes.AttributeKeyConsensusHeight] = e.Client.ConsensusHeight.String()
+		if len(e.Client.Header) > 0 {
+			attrs[clienttypes.AttributeKeyHeader] = hex.EncodeToString(e.Client.Header)
+		}
+	}
+	return provider.RelayerEvent{EventType: e.EventType, Attributes: attrs}
+}
+
+// commitmentSlot returns the storage slot of the commitment of the IBC path in the commitments mapping
+// of the IBC handler at slot: keccak256(keccak256(path) . slot).
+func commitmentSlot(path string, slot uint64) common.Hash {
+	key := crypto.Keccak256(append(crypto.Keccak256([]byte(path)), common.BigToHash(new(big.Int).SetUint64(slot)).Bytes()...))
+	return common.BytesToHash(key)
+}
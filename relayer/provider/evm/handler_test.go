@@ -0,0 +1,101 @@
+package evm
+
+import (
+	"math/big"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+var testHandler = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+// testLog returns the log of the IBC handler event name with the given values emitted by the transaction tx.
+func testLog(t *testing.T, address common.Address, tx common.Hash, name string, values ...any) types.Log {
+	e := handlerABI.Events[name]
+	data, err := e.Inputs.Pack(values...)
+	require.NoError(t, err)
+	return types.Log{Address: address, Topics: []common.Hash{e.ID}, Data: data, BlockNumber: 10, TxHash: tx}
+}
+
+func testPacketValues(seq uint64) []any {
+	return []any{seq, "transfer", "channel-0", "transfer", "channel-1", []byte("data"), uint64(1), uint64(500), uint64(0)}
+}
+
+func TestParseIBCEvents(t *testing.T) {
+	tx1, tx2 := common.HexToHash("0x01"), common.HexToHash("0x02")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+
+	logs := []types.Log{
+		testLog(t, testHandler, tx1, "SendPacket", testPacketValues(1)...),
+		testLog(t, other, tx1, "SendPacket", testPacketValues(2)...),
+		testLog(t, testHandler, tx2, "UpdateClient", "07-tendermint-0", "07-tendermint", uint64(1), uint64(42), []byte{0x01}),
+		testLog(t, testHandler, tx2, "RecvPacket", testPacketValues(3)...),
+		testLog(t, testHandler, tx2, "WriteAcknowledgement", append(testPacketValues(3), []byte("ack"))...),
+		testLog(t, testHandler, tx2, "WriteAcknowledgement", append(testPacketValues(4), []byte("async"))...),
+		testLog(t, testHandler, tx2, "ChannelOpenInit", "transfer", "channel-1", "transfer", "", "connection-0", uint8(chantypes.UNORDERED), "ics20-1"),
+		testLog(t, testHandler, tx2, "ConnectionOpenAck", "connection-0", "07-tendermint-0", "connection-3", "client-9"),
+	}
+	removed := testLog(t, testHandler, tx2, "SendPacket", testPacketValues(5)...)
+	removed.Removed = true
+	logs = append(logs, removed)
+
+	events, err := ParseIBCEvents(testHandler, logs)
+	require.NoError(t, err)
+	require.Len(t, events, 6)
+
+	require.Equal(t, chantypes.EventTypeSendPacket, events[0].EventType)
+	require.Equal(t, uint64(1), events[0].Packet.Sequence)
+	require.Equal(t, uint64(10), events[0].Packet.Height)
+	require.Equal(t, clienttypes.NewHeight(1, 500), events[0].Packet.TimeoutHeight)
+	require.Equal(t, tx1, events[0].TxHash)
+
+	require.Equal(t, clienttypes.EventTypeUpdateClient, events[1].EventType)
+	require.Equal(t, "07-tendermint-0", events[1].Client.ClientID)
+	require.Equal(t, clienttypes.NewHeight(1, 42), events[1].Client.ConsensusHeight)
+
+	require.Equal(t, chantypes.EventTypeRecvPacket, events[2].EventType)
+	require.Equal(t, uint64(3), events[2].Packet.Sequence)
+	require.Equal(t, []byte("ack"), events[2].Packet.Ack, "write_acknowledgement merged into recv_packet")
+
+	require.Equal(t, chantypes.EventTypeWriteAck, events[3].EventType)
+	require.Equal(t, uint64(4), events[3].Packet.Sequence)
+	require.Equal(t, []byte("async"), events[3].Packet.Ack)
+
+	require.Equal(t, chantypes.EventTypeChannelOpenInit, events[4].EventType)
+	require.Equal(t, "channel-1", events[4].Channel.ChannelID)
+	require.Equal(t, "connection-0", events[4].Channel.ConnID)
+	require.Equal(t, chantypes.UNORDERED, events[4].Channel.Order)
+
+	require.Equal(t, "connection-3", events[5].Connection.CounterpartyConnID)
+
+	attrs := events[2].RelayerEvent().Attributes
+	require.Equal(t, "3", attrs[chantypes.AttributeKeySequence])
+	require.Equal(t, "1-500", attrs[chantypes.AttributeKeyTimeoutHeight])
+	require.Equal(t, "61636b", attrs[chantypes.AttributeKeyAckHex])
+}
+
+func TestEVMHeaderRoundTrip(t *testing.T) {
+	h, err := NewEVMHeader("evm_9000-2", "10-evm", &types.Header{
+		Number:     big.NewInt(77),
+		Difficulty: big.NewInt(0),
+		BaseFee:    big.NewInt(7),
+		Time:       1700000000,
+	})
+	require.NoError(t, err)
+	require.NoError(t, h.ValidateBasic())
+	require.Equal(t, clienttypes.NewHeight(2, 77), h.GetHeight())
+
+	bz, err := h.Marshal()
+	require.NoError(t, err)
+	var decoded EVMHeader
+	require.NoError(t, decoded.Unmarshal(bz))
+	require.Equal(t, *h, decoded)
+
+	packed, err := clienttypes.PackHeader(h)
+	require.NoError(t, err)
+	require.Equal(t, "/relayer.evm.v1.Header", packed.TypeUrl)
+}
@@ -0,0 +1,160 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var _ ibcexported.Header = &EVMHeader{}
+
+func init() {
+	proto.RegisterType((*EVMHeader)(nil), "relayer.evm.v1.Header")
+}
+
+// EVMHeader is the header of a block of an EVM chain submitted to update its light client on a counterparty chain,
+// the light client verifying the storage proofs of the IBC handler against the state root of the header.
+// It is encoded as the protobuf message relayer.evm.v1.Header:
+//
+//	message Header {
+//	  string chain_id = 1;
+//	  uint64 revision_number = 2;
+//	  bytes header = 3; // RLP encoded Ethereum block header
+//	  string client_type = 4;
+//	}
+type EVMHeader struct {
+	ChainID        string
+	RevisionNumber uint64
+	Header         []byte
+	Type           string
+}
+
+// NewEVMHeader returns the header of the block h of the chain chainID, for a light client of type clientType.
+func NewEVMHeader(chainID, clientType string, h *types.Header) (*EVMHeader, error) {
+	bz, err := rlp.EncodeToBytes(h)
+	if err != nil {
+		return nil, err
+	}
+	return &EVMHeader{
+		ChainID:        chainID,
+		RevisionNumber: clienttypes.ParseChainID(chainID),
+		Header:         bz,
+		Type:           clientType,
+	}, nil
+}
+
+// EthHeader decodes the Ethereum block header.
+func (h *EVMHeader) EthHeader() (*types.Header, error) {
+	var eh types.Header
+	if err := rlp.DecodeBytes(h.Header, &eh); err != nil {
+		return nil, err
+	}
+	return &eh, nil
+}
+
+func (h *EVMHeader) Reset()         { *h = EVMHeader{} }
+func (h *EVMHeader) String() string { return fmt.Sprintf("%s header at %s", h.ChainID, h.GetHeight()) }
+func (*EVMHeader) ProtoMessage()    {}
+
+func (h *EVMHeader) ClientType() string {
+	return h.Type
+}
+
+func (h *EVMHeader) GetChainID() string {
+	return h.ChainID
+}
+
+func (h *EVMHeader) GetHeight() ibcexported.Height {
+	eh, err := h.EthHeader()
+	if err != nil {
+		return clienttypes.NewHeight(h.RevisionNumber, 0)
+	}
+	return clienttypes.NewHeight(h.RevisionNumber, eh.Number.Uint64())
+}
+
+func (h *EVMHeader) ValidateBasic() error {
+	if h.ChainID == "" {
+		return errors.New("header has no chain id")
+	}
+	eh, err := h.EthHeader()
+	if err != nil {
+		return fmt.Errorf("invalid block header: %w", err)
+	}
+	if eh.Number == nil || eh.Number.Sign() <= 0 {
+		return errors.New("block header has no height")
+	}
+	return nil
+}
+
+// Marshal encodes the header as protobuf.
+func (h *EVMHeader) Marshal() ([]byte, error) {
+	var b []byte
+	if h.ChainID != "" {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, h.ChainID)
+	}
+	if h.RevisionNumber != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, h.RevisionNumber)
+	}
+	if len(h.Header) > 0 {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, h.Header)
+	}
+	if h.Type != "" {
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendString(b, h.Type)
+	}
+	return b, nil
+}
+
+// Unmarshal decodes the header from protobuf, skipping unknown fields.
+func (h *EVMHeader) Unmarshal(b []byte) error {
+	h.Reset()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			h.ChainID, b = v, b[n:]
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			h.RevisionNumber, b = v, b[n:]
+		case num == 3 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			h.Header, b = append([]byte(nil), v...), b[n:]
+		case num == 4 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			h.Type, b = v, b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package evm
+
+import (
+	"errors"
+	"os"
+
+	ckeys "github.com/cosmos/cosmos-sdk/client/keys"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/ethereum/go-ethereum/common"
+	lens "github.com/strangelove-ventures/lens/client"
+	ethhd "github.com/tharsis/ethermint/crypto/hd"
+)
+
+// Keys are eth_secp256k1 keys whatever the coin type, their addresses being the Ethereum addresses
+// which sign the transactions.
+
+func (p *EVMProvider) CreateKeystore(path string) error {
+	keybase, err := keyring.New(p.PCfg.ChainID, p.PCfg.KeyringBackend, p.KeyDirectory, os.Stdin, lens.LensKeyringAlgoOptions())
+	if err != nil {
+		return err
+	}
+	p.Keybase = keybase
+	return nil
+}
+
+func (p *EVMProvider) KeystoreCreated(path string) bool {
+	if _, err := os.Stat(p.KeyDirectory); errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+	return p.Keybase != nil
+}
+
+func (p *EVMProvider) AddKey(name string, coinType uint32) (*provider.KeyOutput, error) {
+	mnemonic, err := lens.CreateMnemonic()
+	if err != nil {
+		return nil, err
+	}
+	addr, err := p.RestoreKey(name, mnemonic, coinType)
+	if err != nil {
+		return nil, err
+	}
+	return &provider.KeyOutput{Mnemonic: mnemonic, Address: addr}, nil
+}
+
+func (p *EVMProvider) RestoreKey(name, mnemonic string, coinType uint32) (string, error) {
+	info, err := p.Keybase.NewAccount(name, mnemonic, "", hd.CreateHDPath(coinType, 0, 0).String(), ethhd.EthSecp256k1)
+	if err != nil {
+		return "", err
+	}
+	return keyAddress(info).Hex(), nil
+}
+
+func (p *EVMProvider) ShowAddress(name string) (string, error) {
+	info, err := p.Keybase.Key(name)
+	if err != nil {
+		return "", err
+	}
+	return keyAddress(info).Hex(), nil
+}
+
+func (p *EVMProvider) ListAddresses() (map[string]string, error) {
+	out := map[string]string{}
+	infos, err := p.Keybase.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		out[info.GetName()] = keyAddress(info).Hex()
+	}
+	return out, nil
+}
+
+func (p *EVMProvider) DeleteKey(name string) error {
+	return p.Keybase.Delete(name)
+}
+
+func (p *EVMProvider) KeyExists(name string) bool {
+	info, err := p.Keybase.Key(name)
+	if err != nil {
+		return false
+	}
+	return info.GetName() == name
+}
+
+func (p *EVMProvider) ExportPrivKeyArmor(keyName string) (string, error) {
+	return p.Keybase.ExportPrivKeyArmor(keyName, ckeys.DefaultKeyPass)
+}
+
+// Address returns the Ethereum address of the configured key.
+func (p *EVMProvider) Address() (string, error) {
+	addr, err := p.address()
+	if err != nil {
+		return "", err
+	}
+	return addr.Hex(), nil
+}
+
+func (p *EVMProvider) address() (common.Address, error) {
+	info, err := p.Keybase.Key(p.PCfg.Key)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return keyAddress(info), nil
+}
+
+// keyAddress returns the Ethereum address of the key, the last 20 bytes of the keccak256 hash of its public key.
+func keyAddress(info keyring.Info) common.Address {
+	return common.BytesToAddress(info.GetPubKey().Address())
+}
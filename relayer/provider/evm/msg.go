@@ -0,0 +1,105 @@
+package evm
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v3/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ provider.RelayerMessage = &EVMMessage{}
+
+// EVMMessage is an ibc-go message submitted to the IBC handler, protobuf encoded, as the argument of the method
+// handling its type.
+type EVMMessage struct {
+	Msg sdk.Msg
+}
+
+func NewEVMMessage(msg sdk.Msg) provider.RelayerMessage {
+	return EVMMessage{
+		Msg: msg,
+	}
+}
+
+// handlerMethods maps the type URLs of the messages to the IBC handler methods they are submitted to.
+var handlerMethods = map[string]string{
+	sdk.MsgTypeURL(&clienttypes.MsgCreateClient{}):        "createClient",
+	sdk.MsgTypeURL(&clienttypes.MsgUpdateClient{}):        "updateClient",
+	sdk.MsgTypeURL(&clienttypes.MsgUpgradeClient{}):       "upgradeClient",
+	sdk.MsgTypeURL(&conntypes.MsgConnectionOpenInit{}):    "connectionOpenInit",
+	sdk.MsgTypeURL(&conntypes.MsgConnectionOpenTry{}):     "connectionOpenTry",
+	sdk.MsgTypeURL(&conntypes.MsgConnectionOpenAck{}):     "connectionOpenAck",
+	sdk.MsgTypeURL(&conntypes.MsgConnectionOpenConfirm{}): "connectionOpenConfirm",
+	sdk.MsgTypeURL(&chantypes.MsgChannelOpenInit{}):       "channelOpenInit",
+	sdk.MsgTypeURL(&chantypes.MsgChannelOpenTry{}):        "channelOpenTry",
+	sdk.MsgTypeURL(&chantypes.MsgChannelOpenAck{}):        "channelOpenAck",
+	sdk.MsgTypeURL(&chantypes.MsgChannelOpenConfirm{}):    "channelOpenConfirm",
+	sdk.MsgTypeURL(&chantypes.MsgChannelCloseInit{}):      "channelCloseInit",
+	sdk.MsgTypeURL(&chantypes.MsgChannelCloseConfirm{}):   "channelCloseConfirm",
+	sdk.MsgTypeURL(&chantypes.MsgRecvPacket{}):            "recvPacket",
+	sdk.MsgTypeURL(&chantypes.MsgAcknowledgement{}):       "acknowledgePacket",
+	sdk.MsgTypeURL(&chantypes.MsgTimeout{}):               "timeoutPacket",
+	sdk.MsgTypeURL(&chantypes.MsgTimeoutOnClose{}):        "timeoutOnClose",
+	sdk.MsgTypeURL(&transfertypes.MsgTransfer{}):          "sendTransfer",
+}
+
+func (m EVMMessage) Type() string {
+	return sdk.MsgTypeURL(m.Msg)
+}
+
+func (m EVMMessage) Seq() uint64 {
+	switch msg := m.Msg.(type) {
+	case *chantypes.MsgRecvPacket:
+		return msg.Packet.Sequence
+	case *chantypes.MsgTimeout:
+		return msg.Packet.Sequence
+	case *chantypes.MsgAcknowledgement:
+		return msg.Packet.Sequence
+	}
+	return 0
+}
+
+func (m EVMMessage) MsgBytes() ([]byte, error) {
+	return proto.Marshal(m.Msg)
+}
+
+// callData returns the call data of the transaction submitting the message to the IBC handler.
+func (m EVMMessage) callData() ([]byte, error) {
+	method, ok := handlerMethods[m.Type()]
+	if !ok {
+		return nil, fmt.Errorf("%w: message %s", ErrUnsupported, m.Type())
+	}
+	bz, err := m.MsgBytes()
+	if err != nil {
+		return nil, err
+	}
+	return handlerABI.Pack(method, bz)
+}
+
+// MarshalLogObject is used to encode m to a zap logger with the zap.Object field type.
+func (m EVMMessage) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("msg_type", m.Type())
+	if seq := m.Seq(); seq != 0 {
+		enc.AddUint64("sequence", seq)
+	}
+	return nil
+}
+
+// evmMessages returns the EVM messages of msgs.
+func evmMessages(msgs []provider.RelayerMessage) ([]EVMMessage, error) {
+	res := make([]EVMMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		m, ok := msg.(EVMMessage)
+		if !ok {
+			return nil, fmt.Errorf("got message of type %T but wanted evm.EVMMessage", msg)
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
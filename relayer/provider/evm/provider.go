@@ -0,0 +1,281 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gogo/protobuf/proto"
+	lens "github.com/strangelove-ventures/lens/client"
+	"go.uber.org/zap"
+)
+
+var (
+	_ provider.ChainProvider  = &EVMProvider{}
+	_ provider.KeyProvider    = &EVMProvider{}
+	_ provider.ProviderConfig = &EVMProviderConfig{}
+)
+
+// ErrUnsupported is returned by the EVMProvider methods which have no equivalent on EVM chains,
+// or which only the legacy processor uses.
+var ErrUnsupported = errors.New("not supported by the evm provider")
+
+// EVMProviderConfig configures a chain whose IBC module is an IBC handler contract of an EVM chain,
+// reached through its Ethereum JSON-RPC endpoint.
+type EVMProviderConfig struct {
+	Key       string `json:"key" yaml:"key"`
+	ChainName string `json:"-" yaml:"-"`
+	// ChainID is the IBC chain ID of the chain, from which the revision number of its heights is parsed.
+	ChainID string `json:"chain-id" yaml:"chain-id"`
+	// EVMChainID is the EIP-155 chain ID the transactions are signed for.
+	EVMChainID uint64 `json:"evm-chain-id" yaml:"evm-chain-id"`
+	RPCAddr    string `json:"rpc-addr" yaml:"rpc-addr"`
+	// IBCHandler is the address of the IBC handler contract.
+	IBCHandler string `json:"ibc-handler" yaml:"ibc-handler"`
+	// CommitmentsSlot is the storage slot of the mapping of the IBC handler holding the commitments of the IBC paths,
+	// keyed by the keccak256 hash of the path, which are proven to the counterparty chain.
+	CommitmentsSlot uint64 `json:"commitments-slot" yaml:"commitments-slot"`
+	KeyringBackend  string `json:"keyring-backend" yaml:"keyring-backend"`
+	// Denom is the denom of the native token, in which balances and fees are reported.
+	Denom         string  `json:"denom" yaml:"denom"`
+	GasAdjustment float64 `json:"gas-adjustment" yaml:"gas-adjustment"`
+	// MaxFeePerGas and MaxPriorityFeePerGas cap, in wei, the fee cap and tip of the EIP-1559 transactions,
+	// MaxFeePerGas also capping the gas price of legacy transactions. They are uncapped if empty.
+	MaxFeePerGas         string `json:"max-fee-per-gas,omitempty" yaml:"max-fee-per-gas,omitempty"`
+	MaxPriorityFeePerGas string `json:"max-priority-fee-per-gas,omitempty" yaml:"max-priority-fee-per-gas,omitempty"`
+	// BaseFeeMultiplier scales the base fee of the latest block in the fee cap, 2 if zero.
+	BaseFeeMultiplier float64 `json:"base-fee-multiplier,omitempty" yaml:"base-fee-multiplier,omitempty"`
+	Debug             bool    `json:"debug" yaml:"debug"`
+	Timeout           string  `json:"timeout" yaml:"timeout"`
+	// ClientType is the type of the light client of the chain on its counterparty chains.
+	ClientType string `json:"client-type" yaml:"client-type"`
+	// Confirmations is the number of blocks built on top of the block of a packet sent on the chain
+	// before the packet is relayed, protecting against reorgs of the latest blocks. None if zero.
+	Confirmations uint64 `json:"confirmations,omitempty" yaml:"confirmations,omitempty"`
+}
+
+func (pc EVMProviderConfig) Validate() error {
+	if _, err := time.ParseDuration(pc.Timeout); err != nil {
+		return fmt.Errorf("invalid Timeout: %w", err)
+	}
+	if pc.EVMChainID == 0 {
+		return errors.New("evm-chain-id must be set")
+	}
+	if !common.IsHexAddress(pc.IBCHandler) {
+		return fmt.Errorf("invalid ibc-handler address %q", pc.IBCHandler)
+	}
+	if err := sdk.ValidateDenom(pc.Denom); err != nil {
+		return fmt.Errorf("invalid denom: %w", err)
+	}
+	if pc.BaseFeeMultiplier < 0 {
+		return fmt.Errorf("invalid base-fee-multiplier %v", pc.BaseFeeMultiplier)
+	}
+	if _, _, err := pc.feeCaps(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// feeCaps returns the configured fee cap and tip caps, nil if uncapped.
+func (pc EVMProviderConfig) feeCaps() (maxFeeCap, maxTip *big.Int, err error) {
+	parse := func(name, v string) (*big.Int, error) {
+		if v == "" {
+			return nil, nil
+		}
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok || n.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid %s %q", name, v)
+		}
+		return n, nil
+	}
+	if maxFeeCap, err = parse("max-fee-per-gas", pc.MaxFeePerGas); err != nil {
+		return nil, nil, err
+	}
+	if maxTip, err = parse("max-priority-fee-per-gas", pc.MaxPriorityFeePerGas); err != nil {
+		return nil, nil, err
+	}
+	return maxFeeCap, maxTip, nil
+}
+
+// NewProvider validates the EVMProviderConfig and instantiates an EVMProvider.
+func (pc EVMProviderConfig) NewProvider(log *zap.Logger, homepath string, debug bool, chainName string) (provider.ChainProvider, error) {
+	if err := pc.Validate(); err != nil {
+		return nil, err
+	}
+	pc.ChainName = chainName
+	return &EVMProvider{
+		log:          log,
+		PCfg:         pc,
+		KeyDirectory: filepath.Join(homepath, "keys", pc.ChainID),
+		handler:      common.HexToAddress(pc.IBCHandler),
+		codec:        lens.MakeCodec(lens.ModuleBasics),
+		signing:      provider.NewSigningScheduler(),
+	}, nil
+}
+
+// EVMProvider relays through the IBC handler contract of an EVM chain: the IBC messages are submitted
+// as protobuf encoded ibc-go messages to the methods of the handler, whose events are parsed from the logs,
+// and the IBC state is proven by Merkle proofs of the storage of the handler.
+type EVMProvider struct {
+	log *zap.Logger
+
+	PCfg         EVMProviderConfig
+	Keybase      keyring.Keyring
+	KeyDirectory string
+
+	rpc     *rpcClient
+	handler common.Address
+	codec   lens.Codec
+
+	// serializes the transactions signed with the key of the chain
+	signing *provider.SigningScheduler
+}
+
+// EVMIBCHeader is the header of a block of an EVM chain.
+type EVMIBCHeader struct {
+	Header *types.Header
+}
+
+// noop to implement processor.IBCHeader
+func (h EVMIBCHeader) IBCHeaderIndicator() {}
+
+func (h EVMIBCHeader) Height() uint64 {
+	return h.Header.Number.Uint64()
+}
+
+// Time returns the time of the block.
+func (h EVMIBCHeader) Time() time.Time {
+	return time.Unix(int64(h.Header.Time), 0)
+}
+
+func (p *EVMProvider) Init() error {
+	keybase, err := keyring.New(p.PCfg.ChainID, p.PCfg.KeyringBackend, p.KeyDirectory, os.Stdin, lens.LensKeyringAlgoOptions())
+	if err != nil {
+		return err
+	}
+	timeout, _ := time.ParseDuration(p.PCfg.Timeout)
+	p.Keybase = keybase
+	p.rpc = newRPCClient(p.PCfg.ChainID, p.PCfg.RPCAddr, timeout)
+	return nil
+}
+
+func (p *EVMProvider) ProviderConfig() provider.ProviderConfig {
+	return p.PCfg
+}
+
+// SigningQueueDepths returns the number of transactions waiting to be signed with the key of the chain, by path.
+func (p *EVMProvider) SigningQueueDepths() map[string]int {
+	return p.signing.QueueDepths()
+}
+
+func (p *EVMProvider) ChainId() string {
+	return p.PCfg.ChainID
+}
+
+func (p *EVMProvider) ChainName() string {
+	return p.PCfg.ChainName
+}
+
+// Confirmations returns the number of blocks built on top of the block of a packet sent on the chain before it is relayed.
+func (p *EVMProvider) Confirmations() uint64 {
+	return p.PCfg.Confirmations
+}
+
+func (p *EVMProvider) Type() string {
+	return "evm"
+}
+
+func (p *EVMProvider) Key() string {
+	return p.PCfg.Key
+}
+
+func (p *EVMProvider) Timeout() string {
+	return p.PCfg.Timeout
+}
+
+func (p *EVMProvider) ClientType() string {
+	return p.PCfg.ClientType
+}
+
+// revision returns the revision number of the heights of the chain.
+func (p *EVMProvider) revision() uint64 {
+	return clienttypes.ParseChainID(p.PCfg.ChainID)
+}
+
+// height returns the IBC height of the block at height.
+func (p *EVMProvider) height(height uint64) clienttypes.Height {
+	return clienttypes.NewHeight(p.revision(), height)
+}
+
+// TrustingPeriod is unsupported: EVM chains have no unbonding period to derive it from.
+func (p *EVMProvider) TrustingPeriod(ctx context.Context) (time.Duration, error) {
+	return 0, fmt.Errorf("%w: trusting period", ErrUnsupported)
+}
+
+// Sprint returns the json representation of the specified proto message.
+func (p *EVMProvider) Sprint(toPrint proto.Message) (string, error) {
+	out, err := p.codec.Marshaler.MarshalJSON(toPrint)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// VerifyChainID returns an error wrapping provider.ErrChainIDMismatch if the RPC endpoint does not serve the configured EVM chain.
+func (p *EVMProvider) VerifyChainID(ctx context.Context) error {
+	id, err := p.rpc.chainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query chain id of %s: %w", p.PCfg.RPCAddr, err)
+	}
+	if !id.IsUint64() || id.Uint64() != p.PCfg.EVMChainID {
+		return fmt.Errorf("%w: rpc endpoint %s serves evm chain %s, expected %d",
+			provider.ErrChainIDMismatch, p.PCfg.RPCAddr, id, p.PCfg.EVMChainID)
+	}
+	return nil
+}
+
+// WaitForNBlocks blocks until n blocks are produced on the chain.
+func (p *EVMProvider) WaitForNBlocks(ctx context.Context, n int64) error {
+	initial, err := p.rpc.blockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		h, err := p.rpc.blockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		if int64(h) > int64(initial)+n {
+			return nil
+		}
+		select {
+		case <-time.After(100 * time.Millisecond):
+			// Nothing to do.
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *EVMProvider) BlockTime(ctx context.Context, height int64) (int64, error) {
+	h, err := p.rpc.headerByNumber(ctx, height)
+	if err != nil {
+		return 0, err
+	}
+	return time.Unix(int64(h.Time), 0).UnixNano(), nil
+}
+
+// coins returns amount of the native token as coins of the configured denom.
+func (p *EVMProvider) coins(amount *big.Int) sdk.Coins {
+	return sdk.NewCoins(sdk.NewCoin(p.PCfg.Denom, sdk.NewIntFromBigInt(amount)))
+}
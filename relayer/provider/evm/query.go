@@ -0,0 +1,634 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v3/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	host "github.com/cosmos/ibc-go/v3/modules/core/24-host"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// storageProofs is the proof of the commitment of an IBC path in the storage of the IBC handler, RLP encoded:
+// the Merkle proof of the account of the handler against the state root of the block,
+// and the Merkle proof of the commitment against the storage root of the account.
+type storageProofs struct {
+	AccountProof [][]byte
+	StorageProof [][]byte
+}
+
+// latestHeight returns height, or the latest height of the chain if height is not positive,
+// so that the proofs and the state they prove are queried at the same height.
+func (p *EVMProvider) latestHeight(ctx context.Context, height int64) (int64, error) {
+	if height > 0 {
+		return height, nil
+	}
+	return p.QueryLatestHeight(ctx)
+}
+
+// callHandler calls the view function method of the IBC handler at height, the latest block if not positive,
+// and returns its outputs.
+func (p *EVMProvider) callHandler(ctx context.Context, height int64, method string, args ...any) ([]any, error) {
+	data, err := handlerABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := p.rpc.ethCall(ctx, p.handler, data, height)
+	if err != nil {
+		return nil, err
+	}
+	return handlerABI.Unpack(method, bz)
+}
+
+// handlerBytes calls the view function method of the IBC handler returning protobuf encoded state,
+// returning an error if the state is empty, i.e. does not exist.
+func (p *EVMProvider) handlerBytes(ctx context.Context, height int64, method string, args ...any) ([]byte, error) {
+	out, err := p.callHandler(ctx, height, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	bz := out[0].([]byte)
+	if len(bz) == 0 {
+		return nil, fmt.Errorf("%s%v: %w", method, args, errNotFound)
+	}
+	return bz, nil
+}
+
+// proveCommitment returns the proof of the commitment of path in the storage of the IBC handler at height,
+// along with the proof height.
+func (p *EVMProvider) proveCommitment(ctx context.Context, height int64, path string) ([]byte, clienttypes.Height, error) {
+	slot := commitmentSlot(path, p.PCfg.CommitmentsSlot)
+	res, err := p.rpc.proof(ctx, p.handler, slot, height)
+	if err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed to query proof of %s: %w", path, err)
+	}
+	if len(res.StorageProof) != 1 {
+		return nil, clienttypes.Height{}, fmt.Errorf("expected 1 storage proof of %s, got %d", path, len(res.StorageProof))
+	}
+	proofs := storageProofs{
+		AccountProof: make([][]byte, len(res.AccountProof)),
+		StorageProof: make([][]byte, len(res.StorageProof[0].Proof)),
+	}
+	for i, node := range res.AccountProof {
+		proofs.AccountProof[i] = node
+	}
+	for i, node := range res.StorageProof[0].Proof {
+		proofs.StorageProof[i] = node
+	}
+	bz, err := rlp.EncodeToBytes(proofs)
+	if err != nil {
+		return nil, clienttypes.Height{}, err
+	}
+	return bz, p.height(uint64(height)), nil
+}
+
+func (p *EVMProvider) QueryTx(ctx context.Context, hashHex string) (*provider.RelayerTxResponse, error) {
+	r, err := p.rpc.receipt(ctx, common.HexToHash(hashHex))
+	if err != nil {
+		return nil, err
+	}
+	return p.txResponse(r), nil
+}
+
+// QueryTxs is unsupported: transactions cannot be searched by events through the Ethereum JSON-RPC.
+func (p *EVMProvider) QueryTxs(ctx context.Context, page, limit int, events []string) ([]*provider.RelayerTxResponse, error) {
+	return nil, fmt.Errorf("%w: querying transactions by events", ErrUnsupported)
+}
+
+// QueryIBCEvents returns the IBC events emitted by the IBC handler in the blocks from to to, inclusive, in order.
+func (p *EVMProvider) QueryIBCEvents(ctx context.Context, from, to uint64) ([]IBCEvent, error) {
+	logs, err := p.rpc.logs(ctx, from, to, p.handler)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIBCEvents(p.handler, logs)
+}
+
+// QueryTxSender returns the address that signed the transaction hash, which is the relayer who submitted it.
+func (p *EVMProvider) QueryTxSender(ctx context.Context, hash common.Hash) (string, error) {
+	r, err := p.rpc.receipt(ctx, hash)
+	if err != nil {
+		return "", err
+	}
+	return r.From.Hex(), nil
+}
+
+// QueryLatestHeight queries the latest height of the chain, retrying briefly on failure.
+func (p *EVMProvider) QueryLatestHeight(ctx context.Context) (int64, error) {
+	var h uint64
+	err := retry.Do(func() error {
+		var err error
+		h, err = p.rpc.blockNumber(ctx)
+		return err
+	}, retry.Context(ctx), retry.Attempts(latestHeightQueryRetries), retry.Delay(latestHeightQueryRetryDelay), retry.LastErrorOnly(true))
+	return int64(h), err
+}
+
+const (
+	latestHeightQueryRetries    = 3
+	latestHeightQueryRetryDelay = 500 * time.Millisecond
+)
+
+// QueryHeaderAtHeight returns the header of the block at height, for the light client of the chain.
+func (p *EVMProvider) QueryHeaderAtHeight(ctx context.Context, height int64) (ibcexported.Header, error) {
+	h, err := p.rpc.headerByNumber(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMHeader(p.PCfg.ChainID, p.PCfg.ClientType, h)
+}
+
+func (p *EVMProvider) QueryBalance(ctx context.Context, keyName string) (sdk.Coins, error) {
+	info, err := p.Keybase.Key(keyName)
+	if err != nil {
+		return nil, err
+	}
+	return p.QueryBalanceWithAddress(ctx, keyAddress(info).Hex())
+}
+
+func (p *EVMProvider) QueryBalanceWithAddress(ctx context.Context, addr string) (sdk.Coins, error) {
+	if !common.IsHexAddress(addr) {
+		return nil, fmt.Errorf("invalid address %q", addr)
+	}
+	b, err := p.rpc.balance(ctx, common.HexToAddress(addr))
+	if err != nil {
+		return nil, err
+	}
+	return p.coins(b), nil
+}
+
+// QueryUnbondingPeriod is unsupported: EVM chains have no staking module.
+func (p *EVMProvider) QueryUnbondingPeriod(context.Context) (time.Duration, error) {
+	return 0, fmt.Errorf("%w: unbonding period", ErrUnsupported)
+}
+
+func (p *EVMProvider) QueryClientStateResponse(ctx context.Context, height int64, srcClientId string) (*clienttypes.QueryClientStateResponse, error) {
+	height, err := p.latestHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := p.handlerBytes(ctx, height, "getClientState", srcClientId)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := clienttypes.UnmarshalClientState(p.codec.Marshaler, bz)
+	if err != nil {
+		return nil, err
+	}
+	anyClientState, err := clienttypes.PackClientState(cs)
+	if err != nil {
+		return nil, err
+	}
+	proof, proofHeight, err := p.proveCommitment(ctx, height, host.FullClientStatePath(srcClientId))
+	if err != nil {
+		return nil, err
+	}
+	return clienttypes.NewQueryClientStateResponse(anyClientState, proof, proofHeight), nil
+}
+
+func (p *EVMProvider) QueryClientState(ctx context.Context, height int64, clientid string) (ibcexported.ClientState, error) {
+	bz, err := p.handlerBytes(ctx, height, "getClientState", clientid)
+	if err != nil {
+		return nil, err
+	}
+	return clienttypes.UnmarshalClientState(p.codec.Marshaler, bz)
+}
+
+func (p *EVMProvider) QueryClientConsensusState(ctx context.Context, chainHeight int64, clientid string, clientHeight ibcexported.Height) (*clienttypes.QueryConsensusStateResponse, error) {
+	chainHeight, err := p.latestHeight(ctx, chainHeight)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := p.handlerBytes(ctx, chainHeight, "getConsensusState", clientid, clientHeight.GetRevisionNumber(), clientHeight.GetRevisionHeight())
+	if err != nil {
+		return nil, err
+	}
+	cs, err := clienttypes.UnmarshalConsensusState(p.codec.Marshaler, bz)
+	if err != nil {
+		return nil, err
+	}
+	anyConsensusState, err := clienttypes.PackConsensusState(cs)
+	if err != nil {
+		return nil, err
+	}
+	proof, proofHeight, err := p.proveCommitment(ctx, chainHeight, host.FullConsensusStatePath(clientid, clientHeight))
+	if err != nil {
+		return nil, err
+	}
+	return clienttypes.NewQueryConsensusStateResponse(anyConsensusState, proof, proofHeight), nil
+}
+
+// QueryUpgradedClient is unsupported: EVM chains are not upgraded through an upgrade plan.
+func (p *EVMProvider) QueryUpgradedClient(ctx context.Context, height int64) (*clienttypes.QueryClientStateResponse, error) {
+	return nil, fmt.Errorf("%w: upgraded client", ErrUnsupported)
+}
+
+// QueryUpgradedConsState is unsupported: EVM chains are not upgraded through an upgrade plan.
+func (p *EVMProvider) QueryUpgradedConsState(ctx context.Context, height int64) (*clienttypes.QueryConsensusStateResponse, error) {
+	return nil, fmt.Errorf("%w: upgraded consensus state", ErrUnsupported)
+}
+
+// QueryConsensusState is unsupported: the consensus state of the light client of the chain
+// is defined by the light client implementation on the counterparty chain.
+func (p *EVMProvider) QueryConsensusState(ctx context.Context, height int64) (ibcexported.ConsensusState, int64, error) {
+	return nil, 0, fmt.Errorf("%w: self consensus state", ErrUnsupported)
+}
+
+func (p *EVMProvider) QueryClients(ctx context.Context) (clienttypes.IdentifiedClientStates, error) {
+	out, err := p.callHandler(ctx, 0, "getClientIds")
+	if err != nil {
+		return nil, err
+	}
+	var res clienttypes.IdentifiedClientStates
+	for _, id := range out[0].([]string) {
+		cs, err := p.QueryClientState(ctx, 0, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query client %s: %w", id, err)
+		}
+		res = append(res, clienttypes.NewIdentifiedClientState(id, cs))
+	}
+	return res, nil
+}
+
+// QueryConsensusStateCount is unsupported: the IBC handler does not enumerate the consensus states of a client.
+func (p *EVMProvider) QueryConsensusStateCount(ctx context.Context, clientID string) (uint64, error) {
+	return 0, fmt.Errorf("%w: consensus state count", ErrUnsupported)
+}
+
+func (p *EVMProvider) QueryConnection(ctx context.Context, height int64, connectionid string) (*conntypes.QueryConnectionResponse, error) {
+	height, err := p.latestHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := p.connection(ctx, height, connectionid)
+	if err != nil {
+		return nil, err
+	}
+	proof, proofHeight, err := p.proveCommitment(ctx, height, host.ConnectionPath(connectionid))
+	if err != nil {
+		return nil, err
+	}
+	return conntypes.NewQueryConnectionResponse(*conn, proof, proofHeight), nil
+}
+
+func (p *EVMProvider) connection(ctx context.Context, height int64, connectionID string) (*conntypes.ConnectionEnd, error) {
+	bz, err := p.handlerBytes(ctx, height, "getConnection", connectionID)
+	if err != nil {
+		return nil, err
+	}
+	var conn conntypes.ConnectionEnd
+	if err := conn.Unmarshal(bz); err != nil {
+		return nil, err
+	}
+	return &conn, nil
+}
+
+func (p *EVMProvider) QueryConnections(ctx context.Context) ([]*conntypes.IdentifiedConnection, error) {
+	out, err := p.callHandler(ctx, 0, "getConnectionIds")
+	if err != nil {
+		return nil, err
+	}
+	var res []*conntypes.IdentifiedConnection
+	for _, id := range out[0].([]string) {
+		conn, err := p.connection(ctx, 0, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query connection %s: %w", id, err)
+		}
+		ic := conntypes.NewIdentifiedConnection(id, *conn)
+		res = append(res, &ic)
+	}
+	return res, nil
+}
+
+func (p *EVMProvider) QueryConnectionsUsingClient(ctx context.Context, height int64, clientid string) ([]*conntypes.IdentifiedConnection, error) {
+	conns, err := p.QueryConnections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var res []*conntypes.IdentifiedConnection
+	for _, c := range conns {
+		if c.ClientId == clientid {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+func (p *EVMProvider) GenerateConnHandshakeProof(ctx context.Context, height int64, clientId, connId string) (clientState ibcexported.ClientState,
+	clientStateProof []byte, consensusProof []byte, connectionProof []byte,
+	connectionProofHeight ibcexported.Height, err error) {
+	clientRes, err := p.QueryClientStateResponse(ctx, height, clientId)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	clientState, err = clienttypes.UnpackClientState(clientRes.ClientState)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	consRes, err := p.QueryClientConsensusState(ctx, height, clientId, clientState.GetLatestHeight())
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	connRes, err := p.QueryConnection(ctx, height, connId)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	return clientState, clientRes.Proof, consRes.Proof, connRes.Proof, connRes.ProofHeight, nil
+}
+
+// NewClientState is unsupported: the client state of the light client of the chain
+// is defined by the light client implementation on the counterparty chain.
+func (p *EVMProvider) NewClientState(dstUpdateHeader ibcexported.Header, dstTrustingPeriod, dstUbdPeriod time.Duration, allowUpdateAfterExpiry, allowUpdateAfterMisbehaviour bool) (ibcexported.ClientState, error) {
+	return nil, fmt.Errorf("%w: creating a client state", ErrUnsupported)
+}
+
+func (p *EVMProvider) QueryChannel(ctx context.Context, height int64, channelid, portid string) (*chantypes.QueryChannelResponse, error) {
+	height, err := p.latestHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := p.channel(ctx, height, portid, channelid)
+	if err != nil {
+		return nil, err
+	}
+	proof, proofHeight, err := p.proveCommitment(ctx, height, host.ChannelPath(portid, channelid))
+	if err != nil {
+		return nil, err
+	}
+	return chantypes.NewQueryChannelResponse(*ch, proof, proofHeight), nil
+}
+
+func (p *EVMProvider) channel(ctx context.Context, height int64, portID, channelID string) (*chantypes.Channel, error) {
+	bz, err := p.handlerBytes(ctx, height, "getChannel", portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	var ch chantypes.Channel
+	if err := ch.Unmarshal(bz); err != nil {
+		return nil, err
+	}
+	return &ch, nil
+}
+
+func (p *EVMProvider) QueryChannelClient(ctx context.Context, height int64, channelid, portid string) (*clienttypes.IdentifiedClientState, error) {
+	ch, err := p.channel(ctx, height, portid, channelid)
+	if err != nil {
+		return nil, err
+	}
+	if len(ch.ConnectionHops) != 1 {
+		return nil, fmt.Errorf("channel %s/%s has %d connection hops", portid, channelid, len(ch.ConnectionHops))
+	}
+	conn, err := p.connection(ctx, height, ch.ConnectionHops[0])
+	if err != nil {
+		return nil, err
+	}
+	cs, err := p.QueryClientState(ctx, height, conn.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	ics := clienttypes.NewIdentifiedClientState(conn.ClientId, cs)
+	return &ics, nil
+}
+
+func (p *EVMProvider) QueryConnectionChannels(ctx context.Context, height int64, connectionid string) ([]*chantypes.IdentifiedChannel, error) {
+	channels, err := p.QueryChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var res []*chantypes.IdentifiedChannel
+	for _, ch := range channels {
+		if len(ch.ConnectionHops) > 0 && ch.ConnectionHops[0] == connectionid {
+			res = append(res, ch)
+		}
+	}
+	return res, nil
+}
+
+func (p *EVMProvider) QueryChannels(ctx context.Context) ([]*chantypes.IdentifiedChannel, error) {
+	out, err := p.callHandler(ctx, 0, "getChannelIds")
+	if err != nil {
+		return nil, err
+	}
+	portIDs, channelIDs := out[0].([]string), out[1].([]string)
+	if len(portIDs) != len(channelIDs) {
+		return nil, fmt.Errorf("ibc handler returned %d port ids for %d channel ids", len(portIDs), len(channelIDs))
+	}
+	var res []*chantypes.IdentifiedChannel
+	for i := range channelIDs {
+		ch, err := p.channel(ctx, 0, portIDs[i], channelIDs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to query channel %s/%s: %w", portIDs[i], channelIDs[i], err)
+		}
+		ic := chantypes.NewIdentifiedChannel(portIDs[i], channelIDs[i], *ch)
+		res = append(res, &ic)
+	}
+	return res, nil
+}
+
+func (p *EVMProvider) QueryPacketCommitments(ctx context.Context, height uint64, channelid, portid string) ([]*chantypes.PacketState, error) {
+	out, err := p.callHandler(ctx, int64(height), "getPacketCommitmentSequences", portid, channelid)
+	if err != nil {
+		return nil, err
+	}
+	var res []*chantypes.PacketState
+	for _, seq := range out[0].([]uint64) {
+		commitment, err := p.packetCommitment(ctx, int64(height), portid, channelid, seq)
+		if err != nil {
+			return nil, err
+		}
+		ps := chantypes.NewPacketState(portid, channelid, seq, commitment)
+		res = append(res, &ps)
+	}
+	return res, nil
+}
+
+// packetCommitment returns the commitment of the packet sent with seq, empty if there is none.
+func (p *EVMProvider) packetCommitment(ctx context.Context, height int64, portID, channelID string, seq uint64) ([]byte, error) {
+	return p.commitment(ctx, height, "getPacketCommitment", portID, channelID, seq)
+}
+
+// ackCommitment returns the commitment of the acknowledgement of the packet received with seq, empty if there is none.
+func (p *EVMProvider) ackCommitment(ctx context.Context, height int64, portID, channelID string, seq uint64) ([]byte, error) {
+	return p.commitment(ctx, height, "getPacketAcknowledgementCommitment", portID, channelID, seq)
+}
+
+func (p *EVMProvider) commitment(ctx context.Context, height int64, method, portID, channelID string, seq uint64) ([]byte, error) {
+	out, err := p.callHandler(ctx, height, method, portID, channelID, seq)
+	if err != nil {
+		return nil, err
+	}
+	c := out[0].([32]byte)
+	if c == ([32]byte{}) {
+		return nil, nil
+	}
+	return c[:], nil
+}
+
+func (p *EVMProvider) QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]*chantypes.PacketState, error) {
+	var res []*chantypes.PacketState
+	for _, seq := range seqs {
+		ack, err := p.ackCommitment(ctx, int64(height), portid, channelid, seq)
+		if err != nil {
+			return nil, err
+		}
+		if len(ack) == 0 {
+			continue
+		}
+		ps := chantypes.NewPacketState(portid, channelid, seq, ack)
+		res = append(res, &ps)
+	}
+	return res, nil
+}
+
+func (p *EVMProvider) QueryUnreceivedPackets(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error) {
+	var res []uint64
+	for _, seq := range seqs {
+		received, err := p.packetReceived(ctx, int64(height), portid, channelid, seq)
+		if err != nil {
+			return nil, err
+		}
+		if !received {
+			res = append(res, seq)
+		}
+	}
+	return res, nil
+}
+
+func (p *EVMProvider) packetReceived(ctx context.Context, height int64, portID, channelID string, seq uint64) (bool, error) {
+	out, err := p.callHandler(ctx, height, "hasPacketReceipt", portID, channelID, seq)
+	if err != nil {
+		return false, err
+	}
+	return out[0].(bool), nil
+}
+
+// QueryUnreceivedAcknowledgements returns the sequences of seqs whose packets sent on the channel are not acknowledged,
+// i.e. whose packet commitments still exist.
+func (p *EVMProvider) QueryUnreceivedAcknowledgements(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error) {
+	var res []uint64
+	for _, seq := range seqs {
+		commitment, err := p.packetCommitment(ctx, int64(height), portid, channelid, seq)
+		if err != nil {
+			return nil, err
+		}
+		if len(commitment) > 0 {
+			res = append(res, seq)
+		}
+	}
+	return res, nil
+}
+
+func (p *EVMProvider) QueryNextSeqRecv(ctx context.Context, height int64, channelid, portid string) (*chantypes.QueryNextSequenceReceiveResponse, error) {
+	height, err := p.latestHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	out, err := p.callHandler(ctx, height, "getNextSequenceRecv", portid, channelid)
+	if err != nil {
+		return nil, err
+	}
+	proof, proofHeight, err := p.proveCommitment(ctx, height, host.NextSequenceRecvPath(portid, channelid))
+	if err != nil {
+		return nil, err
+	}
+	return chantypes.NewQueryNextSequenceReceiveResponse(out[0].(uint64), proof, proofHeight), nil
+}
+
+func (p *EVMProvider) QueryPacketCommitment(ctx context.Context, height int64, channelid, portid string, seq uint64) (*chantypes.QueryPacketCommitmentResponse, error) {
+	height, err := p.latestHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	commitment, err := p.packetCommitment(ctx, height, portid, channelid, seq)
+	if err != nil {
+		return nil, err
+	}
+	if len(commitment) == 0 {
+		return nil, chantypes.ErrPacketCommitmentNotFound
+	}
+	proof, proofHeight, err := p.proveCommitment(ctx, height, host.PacketCommitmentPath(portid, channelid, seq))
+	if err != nil {
+		return nil, err
+	}
+	return chantypes.NewQueryPacketCommitmentResponse(commitment, proof, proofHeight), nil
+}
+
+func (p *EVMProvider) QueryPacketAcknowledgement(ctx context.Context, height int64, channelid, portid string, seq uint64) (*chantypes.QueryPacketAcknowledgementResponse, error) {
+	height, err := p.latestHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	ack, err := p.ackCommitment(ctx, height, portid, channelid, seq)
+	if err != nil {
+		return nil, err
+	}
+	if len(ack) == 0 {
+		return nil, chantypes.ErrInvalidAcknowledgement
+	}
+	proof, proofHeight, err := p.proveCommitment(ctx, height, host.PacketAcknowledgementPath(portid, channelid, seq))
+	if err != nil {
+		return nil, err
+	}
+	return chantypes.NewQueryPacketAcknowledgementResponse(ack, proof, proofHeight), nil
+}
+
+func (p *EVMProvider) QueryPacketReceipt(ctx context.Context, height int64, channelid, portid string, seq uint64) (*chantypes.QueryPacketReceiptResponse, error) {
+	height, err := p.latestHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	received, err := p.packetReceived(ctx, height, portid, channelid, seq)
+	if err != nil {
+		return nil, err
+	}
+	proof, proofHeight, err := p.proveCommitment(ctx, height, host.PacketReceiptPath(portid, channelid, seq))
+	if err != nil {
+		return nil, err
+	}
+	return chantypes.NewQueryPacketReceiptResponse(received, proof, proofHeight), nil
+}
+
+// QueryDenomTrace is unsupported: the denom traces are held by the transfer application, not the IBC handler.
+func (p *EVMProvider) QueryDenomTrace(ctx context.Context, denom string) (*transfertypes.DenomTrace, error) {
+	return nil, fmt.Errorf("%w: denom traces", ErrUnsupported)
+}
+
+// QueryDenomTraces is unsupported: the denom traces are held by the transfer application, not the IBC handler.
+func (p *EVMProvider) QueryDenomTraces(ctx context.Context, offset, limit uint64, height int64) ([]transfertypes.DenomTrace, error) {
+	return nil, fmt.Errorf("%w: denom traces", ErrUnsupported)
+}
+
+// QueryCounterpartyPayee is unsupported: the IBC handler has no ICS-29 fee middleware.
+func (p *EVMProvider) QueryCounterpartyPayee(ctx context.Context, channelID, relayerAddr string) (string, error) {
+	return "", fmt.Errorf("%w: counterparty payee", ErrUnsupported)
+}
+
+// AutoUpdateClient is unsupported: it is only used by the legacy processor.
+func (p *EVMProvider) AutoUpdateClient(ctx context.Context, dst provider.ChainProvider, thresholdTime time.Duration, srcClientId, dstClientId string) (time.Duration, error) {
+	return 0, fmt.Errorf("%w: auto updating clients with the legacy processor", ErrUnsupported)
+}
+
+// DetectCapabilities detects the capabilities of the endpoint of the chain. Transactions cannot be searched
+// by events and logs are polled rather than subscribed to, so only the latest height and whether blocks
+// have a base fee are detected.
+func (p *EVMProvider) DetectCapabilities(ctx context.Context) provider.Capabilities {
+	caps := provider.Capabilities{ChainID: p.PCfg.ChainID, DetectedAt: time.Now()}
+	h, err := p.rpc.headerByNumber(ctx, 0)
+	if err != nil {
+		caps.FeeMarket = true
+		caps.Errors = map[string]string{"fee_market": err.Error(), "history": err.Error()}
+		return caps
+	}
+	caps.FeeMarket = h.BaseFee != nil
+	caps.LatestHeight = h.Number.Int64()
+	return caps
+}
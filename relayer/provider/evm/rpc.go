@@ -0,0 +1,279 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/tracing"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// errNotFound is returned by the RPC client when the requested block, transaction or receipt does not exist (yet).
+var errNotFound = errors.New("not found")
+
+// rpcClient is a minimal Ethereum JSON-RPC client over HTTP, covering the methods used to relay.
+// Every call is traced as a span named by its method, e.g. evm.eth_getLogs.
+type rpcClient struct {
+	// chain is the chain ID of the chain the client is for, as configured.
+	chain string
+	addr  string
+	http  *http.Client
+	id    uint64
+}
+
+func newRPCClient(chain, addr string, timeout time.Duration) *rpcClient {
+	return &rpcClient{chain: chain, addr: addr, http: &http.Client{Timeout: timeout}}
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	if e.Data != nil {
+		return fmt.Sprintf("%s (code %d): %v", e.Message, e.Code, e.Data)
+	}
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// call calls method with params, decoding its result into result unless nil.
+// It returns errNotFound if the result is null.
+func (c *rpcClient) call(ctx context.Context, result any, method string, params ...any) (err error) {
+	ctx, span := tracing.Start(ctx, "evm."+method, attribute.String("chain_id", c.chain))
+	defer func() {
+		// Results not found yet, e.g. receipts of pending transactions, are expected.
+		if errors.Is(err, errNotFound) {
+			tracing.End(span, nil)
+			return
+		}
+		tracing.End(span, err)
+	}()
+
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: atomic.AddUint64(&c.id, 1), Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.addr, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer res.Body.Close()
+	bz, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %s: %s", method, res.Status, bytes.TrimSpace(bz))
+	}
+	var resp rpcResponse
+	if err := json.Unmarshal(bz, &resp); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s: %w", method, resp.Error)
+	}
+	if len(resp.Result) == 0 || string(resp.Result) == "null" {
+		return fmt.Errorf("%s: %w", method, errNotFound)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("%s: invalid result: %w", method, err)
+	}
+	return nil
+}
+
+// blockArg returns the block parameter of height, the latest block if height is not positive.
+func blockArg(height int64) string {
+	if height <= 0 {
+		return "latest"
+	}
+	return hexutil.EncodeUint64(uint64(height))
+}
+
+func (c *rpcClient) chainID(ctx context.Context) (*big.Int, error) {
+	var res hexutil.Big
+	if err := c.call(ctx, &res, "eth_chainId"); err != nil {
+		return nil, err
+	}
+	return res.ToInt(), nil
+}
+
+func (c *rpcClient) blockNumber(ctx context.Context) (uint64, error) {
+	var res hexutil.Uint64
+	if err := c.call(ctx, &res, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	return uint64(res), nil
+}
+
+// headerByNumber returns the header of the block at height, the latest block if height is not positive.
+func (c *rpcClient) headerByNumber(ctx context.Context, height int64) (*types.Header, error) {
+	var res types.Header
+	if err := c.call(ctx, &res, "eth_getBlockByNumber", blockArg(height), false); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+type logFilter struct {
+	FromBlock string           `json:"fromBlock"`
+	ToBlock   string           `json:"toBlock"`
+	Address   []common.Address `json:"address"`
+}
+
+// logs returns the logs emitted by address in the blocks from, to inclusive.
+func (c *rpcClient) logs(ctx context.Context, from, to uint64, address common.Address) ([]types.Log, error) {
+	var res []types.Log
+	filter := logFilter{
+		FromBlock: hexutil.EncodeUint64(from),
+		ToBlock:   hexutil.EncodeUint64(to),
+		Address:   []common.Address{address},
+	}
+	if err := c.call(ctx, &res, "eth_getLogs", filter); err != nil && !errors.Is(err, errNotFound) {
+		return nil, err
+	}
+	return res, nil
+}
+
+type callMsg struct {
+	From *common.Address `json:"from,omitempty"`
+	To   common.Address  `json:"to"`
+	Data hexutil.Bytes   `json:"data"`
+}
+
+// ethCall executes a read-only call of the contract to at height, the latest block if height is not positive.
+func (c *rpcClient) ethCall(ctx context.Context, to common.Address, data []byte, height int64) ([]byte, error) {
+	var res hexutil.Bytes
+	if err := c.call(ctx, &res, "eth_call", callMsg{To: to, Data: data}, blockArg(height)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *rpcClient) estimateGas(ctx context.Context, from, to common.Address, data []byte) (uint64, error) {
+	var res hexutil.Uint64
+	if err := c.call(ctx, &res, "eth_estimateGas", callMsg{From: &from, To: to, Data: data}); err != nil {
+		return 0, err
+	}
+	return uint64(res), nil
+}
+
+func (c *rpcClient) gasPrice(ctx context.Context) (*big.Int, error) {
+	var res hexutil.Big
+	if err := c.call(ctx, &res, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return res.ToInt(), nil
+}
+
+func (c *rpcClient) maxPriorityFeePerGas(ctx context.Context) (*big.Int, error) {
+	var res hexutil.Big
+	if err := c.call(ctx, &res, "eth_maxPriorityFeePerGas"); err != nil {
+		return nil, err
+	}
+	return res.ToInt(), nil
+}
+
+func (c *rpcClient) pendingNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	var res hexutil.Uint64
+	if err := c.call(ctx, &res, "eth_getTransactionCount", addr, "pending"); err != nil {
+		return 0, err
+	}
+	return uint64(res), nil
+}
+
+func (c *rpcClient) balance(ctx context.Context, addr common.Address) (*big.Int, error) {
+	var res hexutil.Big
+	if err := c.call(ctx, &res, "eth_getBalance", addr, "latest"); err != nil {
+		return nil, err
+	}
+	return res.ToInt(), nil
+}
+
+func (c *rpcClient) sendRawTransaction(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
+	bz, err := tx.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var res common.Hash
+	if err := c.call(ctx, &res, "eth_sendRawTransaction", hexutil.Bytes(bz)); err != nil {
+		return common.Hash{}, err
+	}
+	return res, nil
+}
+
+// receipt is the subset of a transaction receipt needed to report the outcome of a transaction.
+type receipt struct {
+	TxHash            common.Hash    `json:"transactionHash"`
+	From              common.Address `json:"from"`
+	BlockNumber       hexutil.Uint64 `json:"blockNumber"`
+	Status            hexutil.Uint64 `json:"status"`
+	GasUsed           hexutil.Uint64 `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big   `json:"effectiveGasPrice"`
+	Logs              []types.Log    `json:"logs"`
+}
+
+// receipt returns the receipt of the transaction hash, errNotFound if it is not included yet.
+func (c *rpcClient) receipt(ctx context.Context, hash common.Hash) (*receipt, error) {
+	var res receipt
+	if err := c.call(ctx, &res, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// accountProof is the result of eth_getProof, the Merkle proofs of an account and of slots of its storage.
+type accountProof struct {
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []storageProof  `json:"storageProof"`
+}
+
+type storageProof struct {
+	Key   hexutil.Big     `json:"key"`
+	Value hexutil.Big     `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+func (c *rpcClient) proof(ctx context.Context, addr common.Address, slot common.Hash, height int64) (*accountProof, error) {
+	var res accountProof
+	if err := c.call(ctx, &res, "eth_getProof", addr, []common.Hash{slot}, blockArg(height)); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,748 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v3/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v3/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v3/modules/core/23-commitment/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/cosmos/relayer/v2/relayer/tracing"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+var (
+	// The IBC handler stores its state under the same prefix as the ibc-go module.
+	defaultChainPrefix = commitmenttypes.NewMerklePrefix([]byte("ibc"))
+	defaultDelayPeriod = uint64(0)
+)
+
+const (
+	// receiptPollInterval is how often the receipts of sent transactions are polled.
+	receiptPollInterval = time.Second
+
+	// receiptTimeout is how long sent transactions are waited for before giving up on them.
+	receiptTimeout = 2 * time.Minute
+)
+
+// SendMessage attempts to sign, encode & send a RelayerMessage.
+func (p *EVMProvider) SendMessage(ctx context.Context, msg provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+	return p.SendMessages(ctx, []provider.RelayerMessage{msg}, memo)
+}
+
+// SendMessages submits each message to the IBC handler in its own transaction, signed with consecutive nonces,
+// and waits for the transactions to be included. The memo is ignored, EVM transactions having none.
+// A boolean indicating if every transaction was included and executed successfully is returned,
+// along with the response of the last transaction included, whose events are the events of all the transactions.
+//
+// Concurrent calls, e.g. for multiple paths sharing the key of the chain, are serialized by the signing scheduler
+// of the provider, serving the paths named in ctx round-robin.
+func (p *EVMProvider) SendMessages(ctx context.Context, msgs []provider.RelayerMessage, memo string) (_ *provider.RelayerTxResponse, _ bool, err error) {
+	ctx, span := tracing.Start(ctx, "SendMessages", attribute.String("chain_id", p.PCfg.ChainID), attribute.Int("messages", len(msgs)))
+	defer func() { tracing.End(span, err) }()
+
+	evmMsgs, err := evmMessages(msgs)
+	if err != nil {
+		return nil, false, err
+	}
+	var resp *provider.RelayerTxResponse
+	if schedErr := p.signing.Do(ctx, func() error {
+		resp, err = p.sendMessages(ctx, evmMsgs)
+		return nil
+	}); schedErr != nil {
+		return nil, false, schedErr
+	}
+	if err != nil {
+		p.log.Info(
+			"Error sending messages",
+			zap.String("chain_id", p.PCfg.ChainID),
+			zap.Error(err),
+		)
+		return resp, false, err
+	}
+	span.SetAttributes(attribute.String("tx_hash", resp.TxHash), attribute.Int64("height", resp.Height))
+	if resp.Code != 0 {
+		return resp, false, fmt.Errorf("transaction %s reverted", resp.TxHash)
+	}
+	p.log.Info(
+		"Successful transaction",
+		zap.String("chain_id", p.PCfg.ChainID),
+		zap.Int64("height", resp.Height),
+		zap.String("tx_hash", resp.TxHash),
+		zap.Int("msgs", len(msgs)),
+		zap.String("fees", resp.Fee),
+	)
+	return resp, true, nil
+}
+
+// SendMessagesWithTimeoutHeight is SendMessages: EVM transactions cannot expire, they are replaced
+// by the next transaction signed with the same nonce.
+func (p *EVMProvider) SendMessagesWithTimeoutHeight(ctx context.Context, msgs []provider.RelayerMessage, memo string, timeoutHeight uint64) (*provider.RelayerTxResponse, bool, error) {
+	return p.SendMessages(ctx, msgs, memo)
+}
+
+// sendMessages sends a transaction per message and waits for them to be included. The gas of a message
+// depending on the state changed by the previous ones, e.g. a packet proven against the consensus state added
+// by a previous client update, can only be estimated once they are included, which is then waited for.
+func (p *EVMProvider) sendMessages(ctx context.Context, msgs []EVMMessage) (*provider.RelayerTxResponse, error) {
+	from, err := p.address()
+	if err != nil {
+		return nil, err
+	}
+	fees, err := p.fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := p.rpc.pendingNonce(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nonce of %s: %w", from, err)
+	}
+
+	var pending []common.Hash
+	var receipts []*receipt
+	wait := func() error {
+		for _, hash := range pending {
+			r, err := p.waitForReceipt(ctx, hash)
+			if err != nil {
+				return err
+			}
+			receipts = append(receipts, r)
+		}
+		pending = nil
+		return nil
+	}
+
+	for _, msg := range msgs {
+		data, err := msg.callData()
+		if err != nil {
+			return nil, err
+		}
+		gas, err := p.rpc.estimateGas(ctx, from, p.handler, data)
+		if err != nil && len(pending) > 0 {
+			if err := wait(); err != nil {
+				return p.txsResponse(receipts), err
+			}
+			gas, err = p.rpc.estimateGas(ctx, from, p.handler, data)
+		}
+		if err != nil {
+			return p.txsResponse(receipts), fmt.Errorf("failed to estimate gas of %s: %w", msg.Type(), err)
+		}
+		tx, err := p.signTx(from, nonce, p.gasLimit(gas), fees, data)
+		if err != nil {
+			return p.txsResponse(receipts), err
+		}
+		hash, err := p.rpc.sendRawTransaction(ctx, tx)
+		if err != nil {
+			return p.txsResponse(receipts), fmt.Errorf("failed to send transaction of %s: %w", msg.Type(), err)
+		}
+		pending = append(pending, hash)
+		nonce++
+	}
+	if err := wait(); err != nil {
+		return p.txsResponse(receipts), err
+	}
+	return p.txsResponse(receipts), nil
+}
+
+// signTx signs the transaction calling the IBC handler with data with the key of the chain:
+// an EIP-1559 transaction, or a legacy transaction if the chain has no base fee.
+func (p *EVMProvider) signTx(from common.Address, nonce, gas uint64, fees txFees, data []byte) (*types.Transaction, error) {
+	chainID := new(big.Int).SetUint64(p.PCfg.EVMChainID)
+	var txData types.TxData
+	if fees.gasPrice != nil {
+		txData = &types.LegacyTx{Nonce: nonce, GasPrice: fees.gasPrice, Gas: gas, To: &p.handler, Data: data}
+	} else {
+		txData = &types.DynamicFeeTx{ChainID: chainID, Nonce: nonce, GasTipCap: fees.tip, GasFeeCap: fees.feeCap, Gas: gas, To: &p.handler, Data: data}
+	}
+	tx := types.NewTx(txData)
+	signer := types.NewLondonSigner(chainID)
+	sig, _, err := p.Keybase.Sign(p.PCfg.Key, signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction with key %s: %w", p.PCfg.Key, err)
+	}
+	signed, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		return nil, err
+	}
+	if sender, err := types.Sender(signer, signed); err != nil || sender != from {
+		return nil, fmt.Errorf("key %s does not sign for %s, is it an eth_secp256k1 key?", p.PCfg.Key, from)
+	}
+	return signed, nil
+}
+
+// waitForReceipt waits for the transaction hash to be included and returns its receipt.
+func (p *EVMProvider) waitForReceipt(ctx context.Context, hash common.Hash) (*receipt, error) {
+	ctx, cancel := context.WithTimeout(ctx, receiptTimeout)
+	defer cancel()
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+	for {
+		r, err := p.rpc.receipt(ctx, hash)
+		if err == nil {
+			return r, nil
+		}
+		if !errors.Is(err, errNotFound) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s not included: %w", hash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// txResponse returns the relayer response of the transaction of r.
+func (p *EVMProvider) txResponse(r *receipt) *provider.RelayerTxResponse {
+	return p.txsResponse([]*receipt{r})
+}
+
+// txsResponse returns the relayer response of the transactions of receipts: the response of the last one,
+// with the events of all, reverted if any reverted, nil if there is none.
+func (p *EVMProvider) txsResponse(receipts []*receipt) *provider.RelayerTxResponse {
+	if len(receipts) == 0 {
+		return nil
+	}
+	resp := &provider.RelayerTxResponse{}
+	fee := new(big.Int)
+	for _, r := range receipts {
+		resp.Height = int64(r.BlockNumber)
+		resp.TxHash = r.TxHash.Hex()
+		resp.GasUsed += int64(r.GasUsed)
+		if uint64(r.Status) != types.ReceiptStatusSuccessful {
+			resp.Code = 1
+		}
+		if r.EffectiveGasPrice != nil {
+			fee.Add(fee, new(big.Int).Mul(r.EffectiveGasPrice.ToInt(), new(big.Int).SetUint64(uint64(r.GasUsed))))
+		}
+		events, err := ParseIBCEvents(p.handler, r.Logs)
+		if err != nil {
+			p.log.Info("Failed to parse transaction logs", zap.String("tx_hash", r.TxHash.Hex()), zap.Error(err))
+			continue
+		}
+		for _, e := range events {
+			resp.Events = append(resp.Events, e.RelayerEvent())
+		}
+	}
+	if fee.Sign() > 0 {
+		resp.Fee = p.coins(fee).String()
+	}
+	return resp
+}
+
+// CreateClient assembles the message creating a Tendermint light client of the counterparty chain.
+func (p *EVMProvider) CreateClient(clientState ibcexported.ClientState, dstHeader ibcexported.Header, signer string) (provider.RelayerMessage, error) {
+	tmHeader, ok := dstHeader.(*tmclient.Header)
+	if !ok {
+		return nil, fmt.Errorf("%w: creating a client from a %T", ErrUnsupported, dstHeader)
+	}
+	msg, err := clienttypes.NewMsgCreateClient(clientState, tmHeader.ConsensusState(), signer)
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(msg), nil
+}
+
+func (p *EVMProvider) SubmitMisbehavior( /*TBD*/ ) (provider.RelayerMessage, error) {
+	return nil, nil
+}
+
+func (p *EVMProvider) MsgUpdateClient(srcClientId string, dstHeader ibcexported.Header) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := clienttypes.NewMsgUpdateClient(srcClientId, dstHeader, signer)
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(msg), nil
+}
+
+// MsgUpdateClientHeader returns the latest header: the light client of an EVM chain verifies it on its own.
+func (p *EVMProvider) MsgUpdateClientHeader(latestHeader provider.IBCHeader, trustedHeight clienttypes.Height, trustedHeader provider.IBCHeader) (ibcexported.Header, error) {
+	h, ok := latestHeader.(EVMIBCHeader)
+	if !ok {
+		return nil, fmt.Errorf("unsupported IBC header type, expected: EVMIBCHeader, actual: %T", latestHeader)
+	}
+	return NewEVMHeader(p.PCfg.ChainID, p.PCfg.ClientType, h.Header)
+}
+
+// MsgUpgradeClient is unsupported: EVM chains are not upgraded through an upgrade plan.
+func (p *EVMProvider) MsgUpgradeClient(srcClientId string, consRes *clienttypes.QueryConsensusStateResponse, clientRes *clienttypes.QueryClientStateResponse) (provider.RelayerMessage, error) {
+	return nil, fmt.Errorf("%w: upgrading clients", ErrUnsupported)
+}
+
+// MsgRegisterCounterpartyPayee is unsupported: the IBC handler has no ICS-29 fee middleware.
+func (p *EVMProvider) MsgRegisterCounterpartyPayee(portID, channelID, relayerAddr, counterpartyPayee string) (provider.RelayerMessage, error) {
+	return nil, fmt.Errorf("%w: registering counterparty payees", ErrUnsupported)
+}
+
+func (p *EVMProvider) MsgTransfer(amount sdk.Coin, dstChainId, dstAddr, srcPortId, srcChanId string, timeoutHeight, timeoutTimestamp uint64) (provider.RelayerMessage, error) {
+	acc, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	msg := &transfertypes.MsgTransfer{
+		SourcePort:       srcPortId,
+		SourceChannel:    srcChanId,
+		Token:            amount,
+		Sender:           acc,
+		Receiver:         dstAddr,
+		TimeoutTimestamp: timeoutTimestamp,
+	}
+	// If the timeoutHeight is 0 then we don't need to explicitly set it on the MsgTransfer
+	if timeoutHeight != 0 {
+		msg.TimeoutHeight = clienttypes.NewHeight(clienttypes.ParseChainID(dstChainId), timeoutHeight)
+	}
+	return NewEVMMessage(msg), nil
+}
+
+func (p *EVMProvider) ValidatePacket(msgTransfer provider.PacketInfo, latest provider.LatestBlock) error {
+	if msgTransfer.Sequence == 0 {
+		return errors.New("refusing to relay packet with sequence: 0")
+	}
+
+	if len(msgTransfer.Data) == 0 {
+		return errors.New("refusing to relay packet with empty data")
+	}
+
+	// This should not be possible, as it violates IBC spec
+	if msgTransfer.TimeoutHeight.IsZero() && msgTransfer.TimeoutTimestamp == 0 {
+		return errors.New("refusing to relay packet without a timeout (height or timestamp must be set)")
+	}
+
+	latestClientTypesHeight := p.height(latest.Height)
+	if !msgTransfer.TimeoutHeight.IsZero() && latestClientTypesHeight.GTE(msgTransfer.TimeoutHeight) {
+		return provider.NewTimeoutHeightError(latest.Height, msgTransfer.TimeoutHeight.RevisionHeight)
+	}
+	latestTimestamp := uint64(latest.Time.UnixNano())
+	if msgTransfer.TimeoutTimestamp > 0 && latestTimestamp > msgTransfer.TimeoutTimestamp {
+		return provider.NewTimeoutTimestampError(latestTimestamp, msgTransfer.TimeoutTimestamp)
+	}
+
+	return nil
+}
+
+func (p *EVMProvider) PacketCommitment(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	res, err := p.QueryPacketCommitment(ctx, int64(height), msgTransfer.SourceChannel, msgTransfer.SourcePort, msgTransfer.Sequence)
+	if err != nil {
+		return provider.PacketProof{}, fmt.Errorf("error querying proof for packet commitment: %w", err)
+	}
+	return provider.PacketProof{
+		Proof:       res.Proof,
+		ProofHeight: res.ProofHeight,
+	}, nil
+}
+
+func (p *EVMProvider) PacketAcknowledgement(ctx context.Context, msgRecvPacket provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	res, err := p.QueryPacketAcknowledgement(ctx, int64(height), msgRecvPacket.DestChannel, msgRecvPacket.DestPort, msgRecvPacket.Sequence)
+	if err != nil {
+		return provider.PacketProof{}, fmt.Errorf("error querying proof for packet acknowledgement: %w", err)
+	}
+	return provider.PacketProof{
+		Proof:       res.Proof,
+		ProofHeight: res.ProofHeight,
+	}, nil
+}
+
+func (p *EVMProvider) PacketReceipt(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	res, err := p.QueryPacketReceipt(ctx, int64(height), msgTransfer.DestChannel, msgTransfer.DestPort, msgTransfer.Sequence)
+	if err != nil {
+		return provider.PacketProof{}, fmt.Errorf("error querying proof for packet receipt: %w", err)
+	}
+	return provider.PacketProof{
+		Proof:       res.Proof,
+		ProofHeight: res.ProofHeight,
+	}, nil
+}
+
+func toCosmosPacket(pi provider.PacketInfo) chantypes.Packet {
+	return chantypes.Packet{
+		Sequence:           pi.Sequence,
+		SourcePort:         pi.SourcePort,
+		SourceChannel:      pi.SourceChannel,
+		DestinationPort:    pi.DestPort,
+		DestinationChannel: pi.DestChannel,
+		Data:               pi.Data,
+		TimeoutHeight:      pi.TimeoutHeight,
+		TimeoutTimestamp:   pi.TimeoutTimestamp,
+	}
+}
+
+func (p *EVMProvider) MsgRecvPacket(msgTransfer provider.PacketInfo, proof provider.PacketProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgRecvPacket{
+		Packet:          toCosmosPacket(msgTransfer),
+		ProofCommitment: proof.Proof,
+		ProofHeight:     proof.ProofHeight,
+		Signer:          signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgAcknowledgement(msgRecvPacket provider.PacketInfo, proof provider.PacketProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgAcknowledgement{
+		Packet:          toCosmosPacket(msgRecvPacket),
+		Acknowledgement: msgRecvPacket.Ack,
+		ProofAcked:      proof.Proof,
+		ProofHeight:     proof.ProofHeight,
+		Signer:          signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgTimeout(msgTransfer provider.PacketInfo, proof provider.PacketProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgTimeout{
+		Packet:           toCosmosPacket(msgTransfer),
+		ProofUnreceived:  proof.Proof,
+		ProofHeight:      proof.ProofHeight,
+		NextSequenceRecv: msgTransfer.Sequence,
+		Signer:           signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgTimeoutOnClose(msgTransfer provider.PacketInfo, proof provider.PacketProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgTimeoutOnClose{
+		Packet:           toCosmosPacket(msgTransfer),
+		ProofUnreceived:  proof.Proof,
+		ProofHeight:      proof.ProofHeight,
+		NextSequenceRecv: msgTransfer.Sequence,
+		Signer:           signer,
+	}), nil
+}
+
+func (p *EVMProvider) ConnectionHandshakeProof(ctx context.Context, msgOpenInit provider.ConnectionInfo, height uint64) (provider.ConnectionProof, error) {
+	clientState, clientStateProof, consensusStateProof, connStateProof, proofHeight, err := p.GenerateConnHandshakeProof(ctx, int64(height), msgOpenInit.ClientID, msgOpenInit.ConnID)
+	if err != nil {
+		return provider.ConnectionProof{}, err
+	}
+	return provider.ConnectionProof{
+		ClientState:          clientState,
+		ClientStateProof:     clientStateProof,
+		ConsensusStateProof:  consensusStateProof,
+		ConnectionStateProof: connStateProof,
+		ProofHeight:          proofHeight.(clienttypes.Height),
+	}, nil
+}
+
+func (p *EVMProvider) ConnectionProof(ctx context.Context, msgOpenAck provider.ConnectionInfo, height uint64) (provider.ConnectionProof, error) {
+	connState, err := p.QueryConnection(ctx, int64(height), msgOpenAck.ConnID)
+	if err != nil {
+		return provider.ConnectionProof{}, err
+	}
+	return provider.ConnectionProof{
+		ConnectionStateProof: connState.Proof,
+		ProofHeight:          connState.ProofHeight,
+	}, nil
+}
+
+func (p *EVMProvider) MsgConnectionOpenInit(info provider.ConnectionInfo, proof provider.ConnectionProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&conntypes.MsgConnectionOpenInit{
+		ClientId: info.ClientID,
+		Counterparty: conntypes.Counterparty{
+			ClientId:     info.CounterpartyClientID,
+			ConnectionId: "",
+			Prefix:       defaultChainPrefix,
+		},
+		Version:     nil,
+		DelayPeriod: defaultDelayPeriod,
+		Signer:      signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgConnectionOpenTry(msgOpenInit provider.ConnectionInfo, proof provider.ConnectionProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	csAny, err := clienttypes.PackClientState(proof.ClientState)
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&conntypes.MsgConnectionOpenTry{
+		ClientId:             msgOpenInit.CounterpartyClientID,
+		PreviousConnectionId: msgOpenInit.CounterpartyConnID,
+		ClientState:          csAny,
+		Counterparty: conntypes.Counterparty{
+			ClientId:     msgOpenInit.ClientID,
+			ConnectionId: msgOpenInit.ConnID,
+			Prefix:       defaultChainPrefix,
+		},
+		DelayPeriod:          defaultDelayPeriod,
+		CounterpartyVersions: conntypes.ExportedVersionsToProto(conntypes.GetCompatibleVersions()),
+		ProofHeight:          proof.ProofHeight,
+		ProofInit:            proof.ConnectionStateProof,
+		ProofClient:          proof.ClientStateProof,
+		ProofConsensus:       proof.ConsensusStateProof,
+		ConsensusHeight:      proof.ClientState.GetLatestHeight().(clienttypes.Height),
+		Signer:               signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgConnectionOpenAck(msgOpenTry provider.ConnectionInfo, proof provider.ConnectionProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	csAny, err := clienttypes.PackClientState(proof.ClientState)
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&conntypes.MsgConnectionOpenAck{
+		ConnectionId:             msgOpenTry.CounterpartyConnID,
+		CounterpartyConnectionId: msgOpenTry.ConnID,
+		Version:                  conntypes.DefaultIBCVersion,
+		ClientState:              csAny,
+		ProofHeight:              proof.ProofHeight,
+		ProofTry:                 proof.ConnectionStateProof,
+		ProofClient:              proof.ClientStateProof,
+		ProofConsensus:           proof.ConsensusStateProof,
+		ConsensusHeight:          proof.ClientState.GetLatestHeight().(clienttypes.Height),
+		Signer:                   signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgConnectionOpenConfirm(msgOpenAck provider.ConnectionInfo, proof provider.ConnectionProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&conntypes.MsgConnectionOpenConfirm{
+		ConnectionId: msgOpenAck.CounterpartyConnID,
+		ProofAck:     proof.ConnectionStateProof,
+		ProofHeight:  proof.ProofHeight,
+		Signer:       signer,
+	}), nil
+}
+
+func (p *EVMProvider) ChannelProof(ctx context.Context, msg provider.ChannelInfo, height uint64) (provider.ChannelProof, error) {
+	channelRes, err := p.QueryChannel(ctx, int64(height), msg.ChannelID, msg.PortID)
+	if err != nil {
+		return provider.ChannelProof{}, err
+	}
+	return provider.ChannelProof{
+		Proof:       channelRes.Proof,
+		ProofHeight: channelRes.ProofHeight,
+		Version:     channelRes.Channel.Version,
+		Ordering:    channelRes.Channel.Ordering,
+	}, nil
+}
+
+func (p *EVMProvider) MsgChannelOpenInit(info provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgChannelOpenInit{
+		PortId: info.PortID,
+		Channel: chantypes.Channel{
+			State:    chantypes.INIT,
+			Ordering: info.Order,
+			Counterparty: chantypes.Counterparty{
+				PortId:    info.CounterpartyPortID,
+				ChannelId: "",
+			},
+			ConnectionHops: []string{info.ConnID},
+			Version:        info.Version,
+		},
+		Signer: signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgChannelOpenTry(msgOpenInit provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgChannelOpenTry{
+		PortId:            msgOpenInit.CounterpartyPortID,
+		PreviousChannelId: msgOpenInit.CounterpartyChannelID,
+		Channel: chantypes.Channel{
+			State:    chantypes.TRYOPEN,
+			Ordering: proof.Ordering,
+			Counterparty: chantypes.Counterparty{
+				PortId:    msgOpenInit.PortID,
+				ChannelId: msgOpenInit.ChannelID,
+			},
+			ConnectionHops: []string{msgOpenInit.CounterpartyConnID},
+			Version:        proof.Version,
+		},
+		CounterpartyVersion: proof.Version,
+		ProofInit:           proof.Proof,
+		ProofHeight:         proof.ProofHeight,
+		Signer:              signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgChannelOpenAck(msgOpenTry provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgChannelOpenAck{
+		PortId:                msgOpenTry.CounterpartyPortID,
+		ChannelId:             msgOpenTry.CounterpartyChannelID,
+		CounterpartyChannelId: msgOpenTry.ChannelID,
+		CounterpartyVersion:   proof.Version,
+		ProofTry:              proof.Proof,
+		ProofHeight:           proof.ProofHeight,
+		Signer:                signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgChannelOpenConfirm(msgOpenAck provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgChannelOpenConfirm{
+		PortId:      msgOpenAck.CounterpartyPortID,
+		ChannelId:   msgOpenAck.CounterpartyChannelID,
+		ProofAck:    proof.Proof,
+		ProofHeight: proof.ProofHeight,
+		Signer:      signer,
+	}), nil
+}
+
+func (p *EVMProvider) MsgChannelCloseInit(info provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	return p.ChannelCloseInit(info.PortID, info.ChannelID)
+}
+
+func (p *EVMProvider) MsgChannelCloseConfirm(msgCloseInit provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgChannelCloseConfirm{
+		PortId:      msgCloseInit.CounterpartyPortID,
+		ChannelId:   msgCloseInit.CounterpartyChannelID,
+		ProofInit:   proof.Proof,
+		ProofHeight: proof.ProofHeight,
+		Signer:      signer,
+	}), nil
+}
+
+func (p *EVMProvider) ChannelCloseInit(srcPortId, srcChanId string) (provider.RelayerMessage, error) {
+	signer, err := p.Address()
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMMessage(&chantypes.MsgChannelCloseInit{
+		PortId:    srcPortId,
+		ChannelId: srcChanId,
+		Signer:    signer,
+	}), nil
+}
+
+// IBCHeaderAtHeight returns the header of the block at height.
+func (p *EVMProvider) IBCHeaderAtHeight(ctx context.Context, h int64) (provider.IBCHeader, error) {
+	header, err := p.rpc.headerByNumber(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	return EVMIBCHeader{Header: header}, nil
+}
+
+// GetLightSignedHeaderAtHeight returns the header of the block at h, for the light client of the chain.
+func (p *EVMProvider) GetLightSignedHeaderAtHeight(ctx context.Context, h int64) (ibcexported.Header, error) {
+	return p.QueryHeaderAtHeight(ctx, h)
+}
+
+// GetIBCUpdateHeader returns the header of the block at srch updating the client of the chain on dst:
+// the light client of an EVM chain needs no trusted fields.
+func (p *EVMProvider) GetIBCUpdateHeader(ctx context.Context, srch int64, dst provider.ChainProvider, dstClientId string) (ibcexported.Header, error) {
+	return p.QueryHeaderAtHeight(ctx, srch)
+}
+
+// The handshake and relay methods below are only used by the legacy processor, which does not support EVM chains.
+
+func (p *EVMProvider) ConnectionOpenInit(srcClientId, dstClientId string, dstHeader ibcexported.Header) ([]provider.RelayerMessage, error) {
+	return nil, errLegacy("ConnectionOpenInit")
+}
+
+func (p *EVMProvider) ConnectionOpenTry(ctx context.Context, dstQueryProvider provider.QueryProvider, dstHeader ibcexported.Header, srcClientId, dstClientId, srcConnId, dstConnId string) ([]provider.RelayerMessage, error) {
+	return nil, errLegacy("ConnectionOpenTry")
+}
+
+func (p *EVMProvider) ConnectionOpenAck(ctx context.Context, dstQueryProvider provider.QueryProvider, dstHeader ibcexported.Header, srcClientId, srcConnId, dstClientId, dstConnId string) ([]provider.RelayerMessage, error) {
+	return nil, errLegacy("ConnectionOpenAck")
+}
+
+func (p *EVMProvider) ConnectionOpenConfirm(ctx context.Context, dstQueryProvider provider.QueryProvider, dstHeader ibcexported.Header, dstConnId, srcClientId, srcConnId string) ([]provider.RelayerMessage, error) {
+	return nil, errLegacy("ConnectionOpenConfirm")
+}
+
+func (p *EVMProvider) ChannelOpenInit(srcClientId, srcConnId, srcPortId, srcVersion, dstPortId string, order chantypes.Order, dstHeader ibcexported.Header) ([]provider.RelayerMessage, error) {
+	return nil, errLegacy("ChannelOpenInit")
+}
+
+func (p *EVMProvider) ChannelOpenTry(ctx context.Context, dstQueryProvider provider.QueryProvider, dstHeader ibcexported.Header, srcPortId, dstPortId, srcChanId, dstChanId, srcVersion, srcConnectionId, srcClientId string) ([]provider.RelayerMessage, error) {
+	return nil, errLegacy("ChannelOpenTry")
+}
+
+func (p *EVMProvider) ChannelOpenAck(ctx context.Context, dstQueryProvider provider.QueryProvider, dstHeader ibcexported.Header, srcClientId, srcPortId, srcChanId, dstChanId, dstPortId string) ([]provider.RelayerMessage, error) {
+	return nil, errLegacy("ChannelOpenAck")
+}
+
+func (p *EVMProvider) ChannelOpenConfirm(ctx context.Context, dstQueryProvider provider.QueryProvider, dstHeader ibcexported.Header, srcClientId, srcPortId, srcChanId, dstPortId, dstChannId string) ([]provider.RelayerMessage, error) {
+	return nil, errLegacy("ChannelOpenConfirm")
+}
+
+func (p *EVMProvider) ChannelCloseConfirm(ctx context.Context, dstQueryProvider provider.QueryProvider, dsth int64, dstChanId, dstPortId, srcPortId, srcChanId string) (provider.RelayerMessage, error) {
+	return nil, errLegacy("ChannelCloseConfirm")
+}
+
+func (p *EVMProvider) MsgRelayAcknowledgement(ctx context.Context, dst provider.ChainProvider, dstChanId, dstPortId, srcChanId, srcPortId string, dsth int64, packet provider.RelayPacket) (provider.RelayerMessage, error) {
+	return nil, errLegacy("MsgRelayAcknowledgement")
+}
+
+func (p *EVMProvider) MsgRelayTimeout(ctx context.Context, dst provider.ChainProvider, dsth int64, packet provider.RelayPacket, dstChanId, dstPortId, srcChanId, srcPortId string, order chantypes.Order) (provider.RelayerMessage, error) {
+	return nil, errLegacy("MsgRelayTimeout")
+}
+
+func (p *EVMProvider) MsgRelayRecvPacket(ctx context.Context, dst provider.ChainProvider, dsth int64, packet provider.RelayPacket, dstChanId, dstPortId, srcChanId, srcPortId string) (provider.RelayerMessage, error) {
+	return nil, errLegacy("MsgRelayRecvPacket")
+}
+
+func (p *EVMProvider) RelayPacketFromSequence(ctx context.Context, src, dst provider.ChainProvider, srch, dsth, seq uint64, dstChanId, dstPortId, dstClientId, srcChanId, srcPortId, srcClientId string, order chantypes.Order) (provider.RelayerMessage, provider.RelayerMessage, error) {
+	return nil, nil, errLegacy("RelayPacketFromSequence")
+}
+
+func (p *EVMProvider) AcknowledgementFromSequence(ctx context.Context, dst provider.ChainProvider, dsth, seq uint64, dstChanId, dstPortId, srcChanId, srcPortId string) (provider.RelayerMessage, error) {
+	return nil, errLegacy("AcknowledgementFromSequence")
+}
+
+// errLegacy returns the error of the method of the legacy processor.
+func errLegacy(method string) error {
+	return fmt.Errorf("%w: %s is only used by the legacy processor, relay with the events processor", ErrUnsupported, method)
+}
@@ -0,0 +1,164 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// fakeNode is an Ethereum JSON-RPC endpoint including the transactions it is sent in the next block.
+type fakeNode struct {
+	t       *testing.T
+	chainID *big.Int
+
+	mu       sync.Mutex
+	nonce    uint64
+	sent     []*types.Transaction
+	receipts map[common.Hash]map[string]any
+	// reverts makes the transactions it is sent revert.
+	reverts bool
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	require.NoError(n.t, json.NewDecoder(r.Body).Decode(&req))
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var result any
+	switch req.Method {
+	case "eth_getBlockByNumber":
+		result = &types.Header{Number: big.NewInt(100), Difficulty: big.NewInt(0), BaseFee: big.NewInt(1000)}
+	case "eth_maxPriorityFeePerGas":
+		result = (*hexutil.Big)(big.NewInt(10))
+	case "eth_getTransactionCount":
+		result = hexutil.Uint64(n.nonce)
+	case "eth_estimateGas":
+		result = hexutil.Uint64(50000)
+	case "eth_sendRawTransaction":
+		var bz hexutil.Bytes
+		require.NoError(n.t, json.Unmarshal(req.Params[0], &bz))
+		var tx types.Transaction
+		require.NoError(n.t, tx.UnmarshalBinary(bz))
+		n.sent = append(n.sent, &tx)
+		status := hexutil.Uint64(types.ReceiptStatusSuccessful)
+		if n.reverts {
+			status = hexutil.Uint64(types.ReceiptStatusFailed)
+		}
+		n.receipts[tx.Hash()] = map[string]any{
+			"transactionHash":   tx.Hash(),
+			"blockNumber":       hexutil.Uint64(101),
+			"status":            status,
+			"gasUsed":           hexutil.Uint64(40000),
+			"effectiveGasPrice": (*hexutil.Big)(big.NewInt(1010)),
+			"logs":              []types.Log{},
+		}
+		result = tx.Hash()
+	case "eth_getTransactionReceipt":
+		var hash common.Hash
+		require.NoError(n.t, json.Unmarshal(req.Params[0], &hash))
+		if r, ok := n.receipts[hash]; ok {
+			result = r
+		}
+	default:
+		n.t.Fatalf("unexpected method %s", req.Method)
+	}
+	require.NoError(n.t, json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result}))
+}
+
+func newTestProvider(t *testing.T) (*EVMProvider, *fakeNode) {
+	node := &fakeNode{t: t, chainID: big.NewInt(9000), nonce: 7, receipts: make(map[common.Hash]map[string]any)}
+	srv := httptest.NewServer(node)
+	t.Cleanup(srv.Close)
+
+	kr, err := keyring.New("evm_9000-1", keyring.BackendMemory, "", nil, lens.LensKeyringAlgoOptions())
+	require.NoError(t, err)
+	p := &EVMProvider{
+		log: zap.NewNop(),
+		PCfg: EVMProviderConfig{
+			Key:        "default",
+			ChainID:    "evm_9000-1",
+			EVMChainID: 9000,
+			IBCHandler: testHandler.Hex(),
+			Denom:      "aevm",
+		},
+		Keybase: kr,
+		rpc:     newRPCClient("evm_9000-1", srv.URL, 10*time.Second),
+		handler: testHandler,
+		signing: provider.NewSigningScheduler(),
+	}
+	_, err = p.RestoreKey("default", testMnemonic, 60)
+	require.NoError(t, err)
+	return p, node
+}
+
+func testRecvPacket(p *EVMProvider, seq uint64) provider.RelayerMessage {
+	addr, _ := p.Address()
+	return NewEVMMessage(&chantypes.MsgRecvPacket{
+		Packet: chantypes.Packet{Sequence: seq, SourcePort: "transfer", SourceChannel: "channel-0", DestinationPort: "transfer", DestinationChannel: "channel-1"},
+		Signer: addr,
+	})
+}
+
+func TestSendMessages(t *testing.T) {
+	p, node := newTestProvider(t)
+	from, err := p.address()
+	require.NoError(t, err)
+
+	resp, success, err := p.SendMessages(context.Background(), []provider.RelayerMessage{testRecvPacket(p, 1), testRecvPacket(p, 2)}, "")
+	require.NoError(t, err)
+	require.True(t, success)
+	require.Equal(t, int64(101), resp.Height)
+	require.Equal(t, int64(80000), resp.GasUsed)
+	require.Equal(t, "80800000aevm", resp.Fee)
+
+	require.Len(t, node.sent, 2)
+	signer := types.NewLondonSigner(node.chainID)
+	for i, tx := range node.sent {
+		require.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+		require.Equal(t, uint64(7+i), tx.Nonce())
+		require.Equal(t, testHandler, *tx.To())
+		require.Equal(t, int64(10), tx.GasTipCap().Int64())
+		require.Equal(t, int64(2010), tx.GasFeeCap().Int64())
+		require.Equal(t, uint64(50000), tx.Gas())
+		sender, err := types.Sender(signer, tx)
+		require.NoError(t, err)
+		require.Equal(t, from, sender)
+	}
+	require.Equal(t, resp.TxHash, node.sent[1].Hash().Hex())
+
+	method, err := handlerABI.MethodById(node.sent[0].Data()[:4])
+	require.NoError(t, err)
+	require.Equal(t, "recvPacket", method.Name)
+}
+
+func TestSendMessagesReverted(t *testing.T) {
+	p, node := newTestProvider(t)
+	node.reverts = true
+
+	resp, success, err := p.SendMessages(context.Background(), []provider.RelayerMessage{testRecvPacket(p, 1)}, "")
+	require.ErrorContains(t, err, "reverted")
+	require.False(t, success)
+	require.Equal(t, uint32(1), resp.Code)
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrFaultInjectionDisabled is returned when configuring faults in a build of the relayer without fault injection.
+var ErrFaultInjectionDisabled = errors.New("fault injection is not available, build the relayer with -tags faultinjection")
+
+// FaultConfig configures the faults injected into the RPC requests of a chain, to test how the relayer copes
+// with flaky endpoints: its retries, RPC failover and transaction journal. Faults are only injected by builds
+// with the faultinjection build tag, and none are injected unless configured.
+type FaultConfig struct {
+	// DropRate is the fraction of RPC responses dropped after the request was served, in [0, 1],
+	// the request failing as if the connection to the endpoint was lost.
+	DropRate float64 `json:"drop-rate,omitempty" yaml:"drop-rate,omitempty"`
+
+	// BroadcastDelay delays every broadcast of a transaction, e.g. "5s".
+	BroadcastDelay string `json:"broadcast-delay,omitempty" yaml:"broadcast-delay,omitempty"`
+
+	// StaleHeights is the number of blocks the latest height reported by the endpoints lags behind.
+	StaleHeights int64 `json:"stale-heights,omitempty" yaml:"stale-heights,omitempty"`
+}
+
+// Validate returns an error if any field of the config is invalid, or if faults are configured in a build without fault injection.
+func (c *FaultConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.DropRate < 0 || c.DropRate > 1 {
+		return fmt.Errorf("fault drop-rate must be between 0 and 1, got %v", c.DropRate)
+	}
+	if _, err := c.Delay(); err != nil {
+		return err
+	}
+	if c.StaleHeights < 0 {
+		return fmt.Errorf("fault stale-heights must not be negative, got %d", c.StaleHeights)
+	}
+	if !FaultInjection && c.Enabled() {
+		return ErrFaultInjectionDisabled
+	}
+	return nil
+}
+
+// Delay returns the delay of the broadcasts.
+func (c *FaultConfig) Delay() (time.Duration, error) {
+	if c == nil || c.BroadcastDelay == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.BroadcastDelay)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fault broadcast-delay: %w", err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("fault broadcast-delay must not be negative, got %s", d)
+	}
+	return d, nil
+}
+
+// Enabled returns whether any fault is configured.
+func (c *FaultConfig) Enabled() bool {
+	delay, _ := c.Delay()
+	return c != nil && (c.DropRate > 0 || c.StaleHeights > 0 || delay > 0)
+}
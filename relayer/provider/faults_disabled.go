@@ -0,0 +1,7 @@
+//go:build !faultinjection
+
+package provider
+
+// FaultInjection reports whether the build injects the faults configured for the chains, which builds without
+// the faultinjection build tag, such as the released binaries and images, never do.
+const FaultInjection = false
@@ -0,0 +1,18 @@
+//go:build !faultinjection
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjectionDisabledByDefault(t *testing.T) {
+	require.False(t, FaultInjection)
+	require.ErrorIs(t, (&FaultConfig{DropRate: 0.1}).Validate(), ErrFaultInjectionDisabled)
+	// Faults left unset or cleared are accepted.
+	require.NoError(t, (&FaultConfig{}).Validate())
+	var unset *FaultConfig
+	require.NoError(t, unset.Validate())
+}
@@ -0,0 +1,7 @@
+//go:build faultinjection
+
+package provider
+
+// FaultInjection reports whether the build injects the faults configured for the chains, which it does
+// when built with the faultinjection build tag.
+const FaultInjection = true
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// RelayerVersion is the version of the relayer, substituted for {{.Version}} in memo templates.
+// It is set by the rly command.
+var RelayerVersion string
+
+// MemoData holds the values a memo template is executed with, for each transaction.
+type MemoData struct {
+	// Version is the version of the relayer.
+	Version string
+
+	// PathName is the path the transaction is sent for, see WithPathName, empty if not sent for a path.
+	PathName string
+
+	// ChainID is the chain the transaction is sent to.
+	ChainID string
+
+	// Sequence is the sequence of the first packet message of the transaction, 0 if there is none,
+	// and Sequences those of all its packet messages, in order.
+	Sequence  uint64
+	Sequences []uint64
+}
+
+// NewMemoData returns the values a memo template is executed with for a transaction of msgs sent to chainID with ctx.
+func NewMemoData(ctx context.Context, chainID string, msgs []RelayerMessage) MemoData {
+	d := MemoData{
+		Version:  RelayerVersion,
+		PathName: PathNameFromContext(ctx),
+		ChainID:  chainID,
+	}
+	for _, msg := range msgs {
+		if seq := msg.Seq(); seq != 0 {
+			d.Sequences = append(d.Sequences, seq)
+		}
+	}
+	if len(d.Sequences) > 0 {
+		d.Sequence = d.Sequences[0]
+	}
+	return d
+}
+
+// RenderMemo executes memo as a text/template with d, e.g. "{{.PathName}}/{{.Sequence}}".
+// A memo without actions is returned as is.
+func RenderMemo(memo string, d MemoData) (string, error) {
+	if !strings.Contains(memo, "{{") {
+		return memo, nil
+	}
+	tmpl, err := template.New("memo").Option("missingkey=error").Parse(memo)
+	if err != nil {
+		return "", fmt.Errorf("invalid memo template %q: %w", memo, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("invalid memo template %q: %w", memo, err)
+	}
+	return buf.String(), nil
+}
+
+// ValidateMemo returns an error if memo is not a valid memo template.
+func ValidateMemo(memo string) error {
+	_, err := RenderMemo(memo, MemoData{})
+	return err
+}
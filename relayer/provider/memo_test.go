@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type seqMessage uint64
+
+func (m seqMessage) Type() string              { return "seq" }
+func (m seqMessage) MsgBytes() ([]byte, error) { return nil, nil }
+func (m seqMessage) Seq() uint64               { return uint64(m) }
+
+func TestRenderMemo(t *testing.T) {
+	RelayerVersion = "v2.1.0"
+	defer func() { RelayerVersion = "" }()
+
+	d := NewMemoData(WithPathName(context.Background(), "demo-path"), "chain-b", []RelayerMessage{seqMessage(0), seqMessage(7), seqMessage(9)})
+	require.Equal(t, MemoData{Version: "v2.1.0", PathName: "demo-path", ChainID: "chain-b", Sequence: 7, Sequences: []uint64{7, 9}}, d)
+
+	memo, err := RenderMemo("{{.PathName}}/{{.ChainID}}/{{.Sequence}} | rly({{.Version}})", d)
+	require.NoError(t, err)
+	require.Equal(t, "demo-path/chain-b/7 | rly(v2.1.0)", memo)
+
+	memo, err = RenderMemo(`{{range $i, $s := .Sequences}}{{if $i}},{{end}}{{$s}}{{end}}`, d)
+	require.NoError(t, err)
+	require.Equal(t, "7,9", memo)
+
+	// Memos without actions are left untouched.
+	memo, err = RenderMemo("rly(v2.1.0)", d)
+	require.NoError(t, err)
+	require.Equal(t, "rly(v2.1.0)", memo)
+
+	require.NoError(t, ValidateMemo("{{.PathName}}"))
+	require.Error(t, ValidateMemo("{{.PathName"))
+	require.Error(t, ValidateMemo("{{.Channel}}"))
+}
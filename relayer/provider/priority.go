@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultPriorityFeeMultiplier multiplies the fees of the transactions of a path while its priority is enabled,
+// unless the path configures its own multiplier.
+const DefaultPriorityFeeMultiplier = 2.0
+
+// Priority expedites the transactions sent for a path while enabled, e.g. critical rollapp withdrawals during
+// congestion windows, by multiplying their fees, or the priority fields of chains supporting them, such as the tip
+// of EVM and Ethermint transactions. It is toggled at runtime.
+// A nil Priority is never enabled.
+type Priority struct {
+	mu            sync.Mutex
+	enabled       bool
+	feeMultiplier float64
+}
+
+// NewPriority returns the priority of a path multiplying the fees of its transactions by feeMultiplier while enabled,
+// or by DefaultPriorityFeeMultiplier if feeMultiplier is zero.
+func NewPriority(enabled bool, feeMultiplier float64) (*Priority, error) {
+	if feeMultiplier == 0 {
+		feeMultiplier = DefaultPriorityFeeMultiplier
+	}
+	if feeMultiplier < 1 {
+		return nil, fmt.Errorf("priority fee multiplier must be at least 1, got %v", feeMultiplier)
+	}
+	return &Priority{enabled: enabled, feeMultiplier: feeMultiplier}, nil
+}
+
+// Enabled returns true if the fees of the transactions of the path are currently multiplied.
+func (p *Priority) Enabled() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.enabled
+}
+
+// SetEnabled enables or disables the priority of the path, applying to the transactions built from then on.
+func (p *Priority) SetEnabled(enabled bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enabled = enabled
+}
+
+// FeeMultiplier returns the multiplier of the fees of the transactions of the path while its priority is enabled.
+func (p *Priority) FeeMultiplier() float64 {
+	if p == nil {
+		return 1
+	}
+	return p.feeMultiplier
+}
+
+type priorityKey struct{}
+
+// WithPriority annotates ctx with the priority of the path that transactions built with ctx are sent for.
+func WithPriority(ctx context.Context, p *Priority) context.Context {
+	if p == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// FeeBoost returns the multiplier of the fees of the transactions built with ctx: the fee multiplier
+// of the priority set with WithPriority if it is enabled, 1 otherwise.
+func FeeBoost(ctx context.Context) float64 {
+	p, _ := ctx.Value(priorityKey{}).(*Priority)
+	if !p.Enabled() {
+		return 1
+	}
+	return p.FeeMultiplier()
+}
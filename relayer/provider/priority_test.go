@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityFeeBoost(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, 1.0, FeeBoost(ctx))
+	require.Equal(t, 1.0, FeeBoost(WithPriority(ctx, nil)))
+
+	p, err := NewPriority(false, 0)
+	require.NoError(t, err)
+	require.Equal(t, DefaultPriorityFeeMultiplier, p.FeeMultiplier())
+
+	// The priority is read when transactions are built, so toggling it applies to existing contexts.
+	ctx = WithPriority(ctx, p)
+	require.Equal(t, 1.0, FeeBoost(ctx))
+	p.SetEnabled(true)
+	require.Equal(t, DefaultPriorityFeeMultiplier, FeeBoost(ctx))
+
+	p, err = NewPriority(true, 1.5)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, FeeBoost(WithPriority(context.Background(), p)))
+
+	_, err = NewPriority(true, 0.5)
+	require.Error(t, err)
+}
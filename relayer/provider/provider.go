@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,6 +17,125 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// ErrChainIDMismatch is returned when an endpoint serves a different chain than the one it is configured for,
+// e.g. a mainnet relayer pointed at a testnet RPC.
+var ErrChainIDMismatch = errors.New("endpoint serves a different chain than configured")
+
+// ErrPacketNotFound is returned when looking up a packet that was not sent on the chain.
+var ErrPacketNotFound = errors.New("packet not found")
+
+// RPCEndpointHealth is the health of an RPC endpoint of a chain as last checked.
+// Requests are sent to the active endpoint, the first healthy one in configured order.
+type RPCEndpointHealth struct {
+	ChainID           string    `json:"chain_id"`
+	Address           string    `json:"address"`
+	Active            bool      `json:"active"`
+	Healthy           bool      `json:"healthy"`
+	Height            int64     `json:"height"`
+	LatencySeconds    float64   `json:"latency_seconds"`
+	Failures          uint64    `json:"failures"`
+	BroadcastFailures uint64    `json:"broadcast_failures"`
+	Error             string    `json:"error,omitempty"`
+	CheckedAt         time.Time `json:"checked_at"`
+}
+
+// Statuses of a TrackedTx.
+const (
+	TxPending  = "pending"
+	TxIncluded = "included"
+	TxFailed   = "failed"
+	TxTimedOut = "timed_out"
+)
+
+// TrackedTx is a transaction broadcast to a chain, followed until it is included in a block.
+type TrackedTx struct {
+	ChainID      string    `json:"chain_id"`
+	TxHash       string    `json:"tx_hash"`
+	Status       string    `json:"status"`
+	BroadcastAt  time.Time `json:"broadcast_at"`
+	Rebroadcasts uint      `json:"rebroadcasts,omitempty"`
+	Height       int64     `json:"height,omitempty"`
+	Code         uint32    `json:"code,omitempty"`
+	GasWanted    int64     `json:"gas_wanted,omitempty"`
+	GasUsed      int64     `json:"gas_used,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Signers of the account of the relayer on a chain.
+const (
+	SignerLocal    = "local"
+	SignerRemote   = "remote-signer"
+	SignerMultisig = "multisig"
+)
+
+// AccountIdentity is the account of the relayer on a chain, as queried from the chain,
+// and the accounts letting it relay on their behalf.
+type AccountIdentity struct {
+	// Signer is how the transactions of the account are signed, one of the Signer constants.
+	Signer string
+
+	// AccountType is the type URL of the account, empty if the account does not exist on the chain yet,
+	// i.e. it never received funds.
+	AccountType   string
+	AccountNumber uint64
+	Sequence      uint64
+	PubKeyType    string
+
+	// FeeGranter is the account paying the fees of the relayer, if any, with the type URL of its allowance
+	// and why the allowance does not cover relaying, if it does not.
+	FeeGranter    string
+	FeeGrant      string
+	FeeGrantError string
+
+	// AuthzGrants are the messages the fee granter authorized the relayer to execute on its behalf.
+	AuthzGrants []string
+}
+
+// RemoteSignerHealth is the health of the remote signer of a chain, as last checked.
+type RemoteSignerHealth struct {
+	ChainID        string    `json:"chain_id"`
+	Address        string    `json:"address"`
+	KeyID          string    `json:"key_id"`
+	Healthy        bool      `json:"healthy"`
+	LatencySeconds float64   `json:"latency_seconds"`
+	Failures       uint64    `json:"failures"`
+	Error          string    `json:"error,omitempty"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
+// Capabilities are the features of the endpoint of a chain which relaying modes depend on, as detected at startup.
+// Capabilities which could not be detected are assumed to be supported, with the detection error kept in Errors.
+type Capabilities struct {
+	ChainID string `json:"chain_id"`
+
+	// WebsocketEvents is whether events can be subscribed to over the websocket of the endpoint.
+	WebsocketEvents bool `json:"websocket_events"`
+
+	// TxIndexing is whether transactions can be searched by their events, which the legacy processor needs.
+	TxIndexing bool `json:"tx_indexing"`
+
+	// EarliestHeight is the earliest block the endpoint can serve, and prove state at, 0 if unknown.
+	EarliestHeight int64 `json:"earliest_height"`
+	LatestHeight   int64 `json:"latest_height"`
+
+	// FeeMarket is whether gas prices follow a base fee set by the chain, such as with EIP-1559.
+	FeeMarket bool `json:"fee_market"`
+
+	// MaxTxBytes is the size of the largest transaction the chain accepts, 0 if unknown.
+	MaxTxBytes uint64 `json:"max_tx_bytes,omitempty"`
+
+	Errors     map[string]string `json:"errors,omitempty"`
+	DetectedAt time.Time         `json:"detected_at"`
+}
+
+// HistoryDepth returns how many blocks before the latest one the endpoint can serve, -1 for its whole history.
+func (c Capabilities) HistoryDepth() int64 {
+	if c.EarliestHeight <= 1 {
+		return -1
+	}
+	return c.LatestHeight - c.EarliestHeight
+}
+
 type ProviderConfig interface {
 	NewProvider(log *zap.Logger, homepath string, debug bool, chainName string) (ChainProvider, error)
 	Validate() error
@@ -28,11 +148,15 @@ type RelayerMessage interface {
 }
 
 type RelayerTxResponse struct {
-	Height int64
-	TxHash string
-	Code   uint32
-	Data   string
-	Events []RelayerEvent
+	Height  int64
+	TxHash  string
+	Code    uint32
+	Data    string
+	GasUsed int64
+	Events  []RelayerEvent
+
+	// Fee is the fee paid for the transaction, as a coin string, empty if unknown.
+	Fee string
 }
 
 type RelayerEvent struct {
@@ -310,12 +434,22 @@ type ChainProvider interface {
 	MsgRelayTimeout(ctx context.Context, dst ChainProvider, dsth int64, packet RelayPacket, dstChanId, dstPortId, srcChanId, srcPortId string, order chantypes.Order) (RelayerMessage, error)
 	MsgRelayRecvPacket(ctx context.Context, dst ChainProvider, dsth int64, packet RelayPacket, dstChanId, dstPortId, srcChanId, srcPortId string) (RelayerMessage, error)
 	MsgUpgradeClient(srcClientId string, consRes *clienttypes.QueryConsensusStateResponse, clientRes *clienttypes.QueryClientStateResponse) (RelayerMessage, error)
+
+	// MsgRegisterCounterpartyPayee assembles an ICS-29 message registering counterpartyPayee as the address,
+	// on the counterparty chain, paid the forward relay fees of the packets relayerAddr delivers on the channel.
+	MsgRegisterCounterpartyPayee(portID, channelID, relayerAddr, counterpartyPayee string) (RelayerMessage, error)
 	RelayPacketFromSequence(ctx context.Context, src, dst ChainProvider, srch, dsth, seq uint64, dstChanId, dstPortId, dstClientId, srcChanId, srcPortId, srcClientId string, order chantypes.Order) (RelayerMessage, RelayerMessage, error)
 	AcknowledgementFromSequence(ctx context.Context, dst ChainProvider, dsth, seq uint64, dstChanId, dstPortId, srcChanId, srcPortId string) (RelayerMessage, error)
 
 	SendMessage(ctx context.Context, msg RelayerMessage, memo string) (*RelayerTxResponse, bool, error)
 	SendMessages(ctx context.Context, msgs []RelayerMessage, memo string) (*RelayerTxResponse, bool, error)
 
+	// SendMessagesWithTimeoutHeight is SendMessages with a transaction that can only be included
+	// in a block at or below timeoutHeight, so that it expires deterministically instead of lingering
+	// in the mempool, and can be resubmitted once expired without risk of double inclusion.
+	// A timeoutHeight of 0 behaves like SendMessages.
+	SendMessagesWithTimeoutHeight(ctx context.Context, msgs []RelayerMessage, memo string, timeoutHeight uint64) (*RelayerTxResponse, bool, error)
+
 	// TODO consolidate with IBCHeaderAtHeight
 	GetLightSignedHeaderAtHeight(ctx context.Context, h int64) (ibcexported.Header, error)
 	GetIBCUpdateHeader(ctx context.Context, srch int64, dst ChainProvider, dstClientId string) (ibcexported.Header, error)
@@ -323,6 +457,10 @@ type ChainProvider interface {
 	// IBCHeaderAtHeight returns the IBC compatible block header at a specific height.
 	IBCHeaderAtHeight(ctx context.Context, h int64) (IBCHeader, error)
 
+	// VerifyChainID returns an error wrapping ErrChainIDMismatch if the endpoint of the provider
+	// does not serve the configured chain, or any error preventing to tell.
+	VerifyChainID(ctx context.Context) error
+
 	ChainName() string
 	ChainId() string
 	ClientType() string
@@ -360,6 +498,7 @@ type QueryProvider interface {
 	QueryUpgradedConsState(ctx context.Context, height int64) (*clienttypes.QueryConsensusStateResponse, error)
 	QueryConsensusState(ctx context.Context, height int64) (ibcexported.ConsensusState, int64, error)
 	QueryClients(ctx context.Context) (clienttypes.IdentifiedClientStates, error)
+	QueryConsensusStateCount(ctx context.Context, clientID string) (uint64, error)
 	AutoUpdateClient(ctx context.Context, dst ChainProvider, thresholdTime time.Duration, srcClientId, dstClientId string) (time.Duration, error)
 
 	// ics 03 - connection
@@ -377,7 +516,7 @@ type QueryProvider interface {
 	QueryConnectionChannels(ctx context.Context, height int64, connectionid string) ([]*chantypes.IdentifiedChannel, error)
 	QueryChannels(ctx context.Context) ([]*chantypes.IdentifiedChannel, error)
 	QueryPacketCommitments(ctx context.Context, height uint64, channelid, portid string) (commitments []*chantypes.PacketState, err error)
-	QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string, onlyLatest bool) (acknowledgements []*chantypes.PacketState, totalAcks uint64, err error)
+	QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) (acknowledgements []*chantypes.PacketState, err error)
 	QueryUnreceivedPackets(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error)
 	QueryUnreceivedAcknowledgements(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error)
 	QueryNextSeqRecv(ctx context.Context, height int64, channelid, portid string) (recvRes *chantypes.QueryNextSequenceReceiveResponse, err error)
@@ -388,6 +527,9 @@ type QueryProvider interface {
 	// ics 20 - transfer
 	QueryDenomTrace(ctx context.Context, denom string) (*transfertypes.DenomTrace, error)
 	QueryDenomTraces(ctx context.Context, offset, limit uint64, height int64) ([]transfertypes.DenomTrace, error)
+
+	// ics 29 - fee
+	QueryCounterpartyPayee(ctx context.Context, channelID, relayerAddr string) (string, error)
 }
 
 type RelayPacket interface {
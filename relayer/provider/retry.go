@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+)
+
+// Backoff types of a RetryPolicy.
+const (
+	BackoffExponential = "exponential"
+	BackoffFixed       = "fixed"
+)
+
+// RetryPolicy configures how the relayer retries the queries it makes to a chain, and the messages it builds from them,
+// so that it can be tuned for flaky RPC endpoints. Unset fields keep the defaults of the relayer.
+// Durations are strings such as "400ms" or "2s".
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first one.
+	MaxAttempts uint `json:"max-attempts,omitempty" yaml:"max-attempts,omitempty"`
+
+	// InitialDelay is the delay before the first retry, doubled after each retry with the exponential backoff.
+	InitialDelay string `json:"initial-delay,omitempty" yaml:"initial-delay,omitempty"`
+
+	// MaxDelay bounds the delay between two attempts.
+	MaxDelay string `json:"max-delay,omitempty" yaml:"max-delay,omitempty"`
+
+	// Jitter is the maximum random delay added to each delay, "0s" disabling it.
+	Jitter string `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+
+	// Backoff is either "exponential", the default, or "fixed".
+	Backoff string `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+}
+
+// Validate returns an error if any field of the policy is invalid.
+func (p *RetryPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, d := range []struct{ name, value string }{
+		{"initial-delay", p.InitialDelay},
+		{"max-delay", p.MaxDelay},
+		{"jitter", p.Jitter},
+	} {
+		if _, err := parseRetryDuration(d.value); err != nil {
+			return fmt.Errorf("invalid retry %s: %w", d.name, err)
+		}
+	}
+	switch p.Backoff {
+	case "", BackoffExponential, BackoffFixed:
+	default:
+		return fmt.Errorf("invalid retry backoff %q, must be %q or %q", p.Backoff, BackoffExponential, BackoffFixed)
+	}
+	return nil
+}
+
+func parseRetryDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration %s cannot be negative", s)
+	}
+	return d, nil
+}
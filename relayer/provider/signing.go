@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"sync"
+)
+
+type pathNameKey struct{}
+
+// WithPathName annotates ctx with the name of the path that transactions built with ctx are sent for.
+func WithPathName(ctx context.Context, pathName string) context.Context {
+	return context.WithValue(ctx, pathNameKey{}, pathName)
+}
+
+// PathNameFromContext returns the name of the path set with WithPathName, or an empty string.
+func PathNameFromContext(ctx context.Context) string {
+	pathName, _ := ctx.Value(pathNameKey{}).(string)
+	return pathName
+}
+
+// SigningScheduler serializes signing and broadcasting transactions with a single key,
+// so that concurrent senders don't race on the account sequence.
+// Waiting senders are queued per path, and paths are served round-robin,
+// so a busy path cannot starve the other paths sharing the key.
+// A nil SigningScheduler does not serialize anything.
+type SigningScheduler struct {
+	mu sync.Mutex
+
+	// busy is set while a sender holds the key.
+	busy bool
+
+	// queues holds the waiting senders of each path, in arrival order.
+	queues map[string][]chan struct{}
+
+	// ring holds the paths with waiting senders, served from next onwards.
+	ring []string
+	next int
+}
+
+// NewSigningScheduler returns an idle SigningScheduler.
+func NewSigningScheduler() *SigningScheduler {
+	return &SigningScheduler{
+		queues: make(map[string][]chan struct{}),
+	}
+}
+
+// Do waits for the turn of the path named in ctx, see WithPathName, to use the key and calls fn.
+// It returns the error of fn, or the error of ctx if it is done before the turn comes.
+func (s *SigningScheduler) Do(ctx context.Context, fn func() error) error {
+	if s == nil {
+		return fn()
+	}
+	if err := s.acquire(ctx, PathNameFromContext(ctx)); err != nil {
+		return err
+	}
+	defer s.release()
+	return fn()
+}
+
+// QueueDepths returns the number of senders waiting for the key, by path.
+func (s *SigningScheduler) QueueDepths() map[string]int {
+	depths := make(map[string]int)
+	if s == nil {
+		return depths
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path, q := range s.queues {
+		depths[path] = len(q)
+	}
+	return depths
+}
+
+func (s *SigningScheduler) acquire(ctx context.Context, path string) error {
+	s.mu.Lock()
+	if !s.busy && len(s.ring) == 0 {
+		s.busy = true
+		s.mu.Unlock()
+		return nil
+	}
+	turn := make(chan struct{})
+	if len(s.queues[path]) == 0 {
+		s.ring = append(s.ring, path)
+	}
+	s.queues[path] = append(s.queues[path], turn)
+	s.mu.Unlock()
+
+	select {
+	case <-turn:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	select {
+	case <-turn:
+		// The turn was handed over concurrently, pass it on.
+		s.mu.Unlock()
+		s.release()
+		return ctx.Err()
+	default:
+	}
+	q := s.queues[path]
+	for i, t := range q {
+		if t == turn {
+			s.queues[path] = append(q[:i], q[i+1:]...)
+			break
+		}
+	}
+	if len(s.queues[path]) == 0 {
+		s.removePath(path)
+	}
+	s.mu.Unlock()
+	return ctx.Err()
+}
+
+// release hands the key over to the first waiting sender of the next path, or marks it idle.
+func (s *SigningScheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.ring) == 0 {
+		s.busy = false
+		return
+	}
+	if s.next >= len(s.ring) {
+		s.next = 0
+	}
+	path := s.ring[s.next]
+	q := s.queues[path]
+	turn := q[0]
+	s.queues[path] = q[1:]
+	if len(s.queues[path]) == 0 {
+		s.removePath(path)
+	} else {
+		s.next++
+	}
+	close(turn)
+}
+
+// removePath drops a path without waiting senders from the ring. Must be called with s.mu held.
+func (s *SigningScheduler) removePath(path string) {
+	delete(s.queues, path)
+	for i, p := range s.ring {
+		if p == path {
+			s.ring = append(s.ring[:i], s.ring[i+1:]...)
+			if i < s.next {
+				s.next--
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningSchedulerRoundRobin(t *testing.T) {
+	s := NewSigningScheduler()
+
+	// Hold the key while the other senders queue up.
+	release := make(chan struct{})
+	held := make(chan struct{})
+	go func() {
+		_ = s.Do(WithPathName(context.Background(), "a"), func() error {
+			close(held)
+			<-release
+			return nil
+		})
+	}()
+	<-held
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	enqueue := func(path string) {
+		want := s.QueueDepths()[path] + 1
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, s.Do(WithPathName(context.Background(), path), func() error {
+				mu.Lock()
+				order = append(order, path)
+				mu.Unlock()
+				return nil
+			}))
+		}()
+		require.Eventually(t, func() bool { return s.QueueDepths()[path] == want }, time.Second, time.Millisecond)
+	}
+
+	// Path a queues three transactions before path b queues one,
+	// path b must not wait for all of them.
+	enqueue("a")
+	enqueue("a")
+	enqueue("a")
+	enqueue("b")
+	require.Equal(t, map[string]int{"a": 3, "b": 1}, s.QueueDepths())
+
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, []string{"a", "b", "a", "a"}, order)
+	require.Empty(t, s.QueueDepths())
+}
+
+func TestSigningSchedulerContextDone(t *testing.T) {
+	s := NewSigningScheduler()
+
+	release := make(chan struct{})
+	held := make(chan struct{})
+	go func() {
+		_ = s.Do(context.Background(), func() error {
+			close(held)
+			<-release
+			return nil
+		})
+	}()
+	<-held
+
+	ctx, cancel := context.WithTimeout(WithPathName(context.Background(), "a"), 10*time.Millisecond)
+	defer cancel()
+	err := s.Do(ctx, func() error {
+		t.Fatal("must not run after the context is done")
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Empty(t, s.QueueDepths())
+
+	close(release)
+
+	// The key is usable again once released.
+	require.Eventually(t, func() bool {
+		return s.Do(context.Background(), func() error { return nil }) == nil
+	}, time.Second, time.Millisecond)
+}
+
+func TestNilSigningScheduler(t *testing.T) {
+	var s *SigningScheduler
+	called := false
+	require.NoError(t, s.Do(context.Background(), func() error {
+		called = true
+		return nil
+	}))
+	require.True(t, called)
+	require.Empty(t, s.QueueDepths())
+}
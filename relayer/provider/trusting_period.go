@@ -0,0 +1,21 @@
+package provider
+
+import (
+	"time"
+
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	furyintclient "github.com/cosmos/ibc-go/v3/modules/light-clients/01-furyint/types"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+)
+
+// ClientTrustingPeriod returns the trusting period of a tendermint or furyint client state,
+// past which the client expires unless updated, and false for the clients of other types, which do not expire.
+func ClientTrustingPeriod(cs ibcexported.ClientState) (time.Duration, bool) {
+	switch cs := cs.(type) {
+	case *tmclient.ClientState:
+		return cs.TrustingPeriod, true
+	case *furyintclient.ClientState:
+		return cs.TrustingPeriod, true
+	}
+	return 0, false
+}
@@ -0,0 +1,143 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// RateLimit caps the total amount of a denom transferred over a channel of a path within a sliding window,
+// as a safety brake against draining a bridge. A transfer which would take the channel over its cap is held back
+// and the channel is paused, until an operator reviews it and resumes the channel through the admin API,
+// which also resets the amounts counted against its limits.
+type RateLimit struct {
+	// Channel follows the syntax of ChannelFilter entries, matching the channels of the src chain of the path.
+	// The limit applies to every channel of the path if empty, each channel counted apart.
+	Channel string `yaml:"channel,omitempty" json:"channel,omitempty"`
+
+	// Denom is matched as it appears in the packet data, as for the PacketFilter. The transfers of both directions
+	// of the channel count towards its cap, so a limit of a voucher denom only caps transfers back to its origin.
+	Denom string `yaml:"denom" json:"denom"`
+
+	// Amount is the maximum amount of Denom relayed over the channel within Window.
+	Amount string        `yaml:"amount" json:"amount"`
+	Window time.Duration `yaml:"window" json:"window"`
+}
+
+// rateLimiter returns the processor rate limiter enforcing limits on the path whose src chain is srcChainID,
+// nil if there is no limit, or an error if a limit is invalid.
+func rateLimiter(srcChainID string, limits []RateLimit) (*processor.RateLimiter, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+	res := make([]processor.RateLimit, 0, len(limits))
+	for _, l := range limits {
+		if l.Denom == "" {
+			return nil, fmt.Errorf("rate limit of channel %q has no denom", l.Channel)
+		}
+		a, ok := new(big.Int).SetString(l.Amount, 10)
+		if !ok || a.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid rate limit amount %q of denom %s", l.Amount, l.Denom)
+		}
+		if l.Window <= 0 {
+			return nil, fmt.Errorf("invalid rate limit window %s of denom %s", l.Window, l.Denom)
+		}
+		portID, channelID := parseChannelFilterEntry(l.Channel)
+		res = append(res, processor.RateLimit{PortID: portID, ChannelID: channelID, Denom: l.Denom, Amount: a, Window: l.Window})
+	}
+	return processor.NewRateLimiter(srcChainID, res), nil
+}
+
+// PathRateLimits reports the amounts relayed over the rate limited channels of a path.
+type PathRateLimits struct {
+	Path     string                     `json:"path"`
+	ChainID  string                     `json:"chain_id"`
+	Channels []processor.RateLimitUsage `json:"channels"`
+}
+
+// rateLimitExceeded pauses the channel of r whose transfer exceeded a rate limit, alerting on the first time.
+func (s *supervisor) rateLimitExceeded(r *pathRunner, portID, channelID, reason string) {
+	if !r.pauses.pause(portID, channelID) {
+		return
+	}
+	s.metrics.IncRateLimitPauses(r.name, r.src.ChainID(), channelID, portID)
+	s.changes.record(feed.Change{Kind: feed.ChangeChannelPaused, Cause: feed.CauseRateLimit, Path: r.name, PortID: portID, ChannelID: channelID, Reason: reason}, r.name)
+	r.log.Error(
+		"Paused channel exceeding its rate limit, resume it through the admin API once reviewed",
+		zap.String("path_name", r.name),
+		zap.String("chain_id", r.src.ChainID()),
+		zap.String("port_id", portID),
+		zap.String("channel_id", channelID),
+		zap.String("reason", reason),
+	)
+}
+
+// hasRateLimits returns whether any path is rate limited.
+func (s *supervisor) hasRateLimits() bool {
+	for _, r := range s.runners {
+		if r.rateLimiter != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimits returns the amounts relayed over the rate limited channels of every rate limited path.
+func (s *supervisor) rateLimits() []PathRateLimits {
+	res := []PathRateLimits{}
+	for _, name := range s.names {
+		r := s.runners[name]
+		if r.rateLimiter == nil {
+			continue
+		}
+		res = append(res, PathRateLimits{Path: name, ChainID: r.src.ChainID(), Channels: r.rateLimiter.Usage()})
+	}
+	return res
+}
+
+type rateLimiterKey struct{}
+
+// withRateLimiter holds back the transfers exceeding the limits of l in the messages relayed with ctx,
+// see rateLimitedPacket.
+func withRateLimiter(ctx context.Context, l *processor.RateLimiter) context.Context {
+	if l == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, rateLimiterKey{}, l)
+}
+
+// rateLimitedPacket returns true if the packet sent on the channel of src with seq exceeds a rate limit
+// of the rate limiter attached to ctx. Packets are relayed when they cannot be looked up.
+func rateLimitedPacket(ctx context.Context, src *Chain, srcChanID, srcPortID, dstChanID, dstPortID string, seq uint64) bool {
+	l, ok := ctx.Value(rateLimiterKey{}).(*processor.RateLimiter)
+	if !ok {
+		return false
+	}
+	q, ok := src.ChainProvider.(sendPacketQuerier)
+	if !ok {
+		return false
+	}
+	packet, err := q.QuerySendPacket(ctx, srcChanID, srcPortID, seq)
+	if err != nil {
+		return false
+	}
+	k := processor.ChannelKey{ChannelID: srcChanID, PortID: srcPortID, CounterpartyChannelID: dstChanID, CounterpartyPortID: dstPortID}
+	reason := l.Allow(src.ChainID(), k, seq, packet.Data)
+	if reason == "" {
+		return false
+	}
+	src.log.Debug(
+		"Holding back packet over rate limit",
+		zap.String("chain_id", src.ChainID()),
+		zap.String("channel_id", srcChanID),
+		zap.String("port_id", srcPortID),
+		zap.Uint64("sequence", seq),
+		zap.String("reason", reason),
+	)
+	return true
+}
@@ -0,0 +1,53 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRateLimiterConfig(t *testing.T) {
+	l, err := rateLimiter("chain-a", nil)
+	require.NoError(t, err)
+	require.Nil(t, l)
+
+	_, err = rateLimiter("chain-a", []RateLimit{{Denom: "urax", Amount: "-1", Window: time.Hour}})
+	require.EqualError(t, err, `invalid rate limit amount "-1" of denom urax`)
+	_, err = rateLimiter("chain-a", []RateLimit{{Denom: "urax", Amount: "100"}})
+	require.EqualError(t, err, "invalid rate limit window 0s of denom urax")
+	_, err = rateLimiter("chain-a", []RateLimit{{Channel: "transfer:channel-0", Amount: "100", Window: time.Hour}})
+	require.EqualError(t, err, `rate limit of channel "transfer:channel-0" has no denom`)
+}
+
+func TestRateLimitPausesChannel(t *testing.T) {
+	chain := func(chainID string) *Chain {
+		return &Chain{Chainid: chainID, ChainProvider: &cosmosprovider.CosmosProvider{PCfg: cosmosprovider.CosmosProviderConfig{ChainID: chainID}}}
+	}
+	chains := map[string]*Chain{"chain-a": chain("chain-a"), "chain-b": chain("chain-b")}
+	paths := []NamedPath{{Name: "a-b", Path: &Path{
+		Src:        &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"},
+		Dst:        &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-0"},
+		RateLimits: []RateLimit{{Channel: "transfer:channel-0", Denom: "urax", Amount: "100", Window: time.Hour}},
+	}}}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+	s.metrics = processor.NewPrometheusMetrics()
+	r := s.runners["a-b"]
+	require.True(t, s.hasRateLimits())
+
+	// Transfers sent from the dst chain are limited on the channel of the src chain they arrive on.
+	k := processor.ChannelKey{PortID: "transfer", ChannelID: "channel-7", CounterpartyPortID: "transfer", CounterpartyChannelID: "channel-0"}
+	require.Empty(t, r.rateLimiter.Allow("chain-b", k, 1, []byte(`{"denom":"urax","amount":"100"}`)))
+	require.False(t, r.pauses.isPaused("transfer", "channel-0"))
+	require.NotEmpty(t, r.rateLimiter.Allow("chain-b", k, 2, []byte(`{"denom":"urax","amount":"1"}`)))
+	require.True(t, r.pauses.isPaused("transfer", "channel-0"))
+
+	limits := s.rateLimits()
+	require.Len(t, limits, 1)
+	require.Equal(t, "a-b", limits[0].Path)
+	require.Equal(t, []processor.RateLimitUsage{{PortID: "transfer", ChannelID: "channel-0", Denom: "urax", Relayed: "100", Limit: "100", Window: "1h0m0s"}}, limits[0].Channels)
+}
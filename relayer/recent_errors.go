@@ -0,0 +1,76 @@
+package relayer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxRecentErrors is the number of warnings and errors kept for the admin API.
+const maxRecentErrors = 100
+
+// recentErrors is a zap core keeping the last warnings and errors logged by the relayer, oldest first,
+// so that they can be reviewed through the admin API without access to the logs.
+type recentErrors struct {
+	buf    *recentErrorsBuffer
+	fields []zapcore.Field
+}
+
+type recentErrorsBuffer struct {
+	mu      sync.Mutex
+	entries []admin.LogEntry
+}
+
+func newRecentErrors() *recentErrors {
+	return &recentErrors{buf: &recentErrorsBuffer{}}
+}
+
+func (e *recentErrors) Enabled(lvl zapcore.Level) bool {
+	return lvl >= zapcore.WarnLevel
+}
+
+func (e *recentErrors) With(fields []zapcore.Field) zapcore.Core {
+	return &recentErrors{buf: e.buf, fields: append(e.fields[:len(e.fields):len(e.fields)], fields...)}
+}
+
+func (e *recentErrors) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if e.Enabled(ent.Level) {
+		return ce.AddCore(ent, e)
+	}
+	return ce
+}
+
+func (e *recentErrors) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range append(e.fields[:len(e.fields):len(e.fields)], fields...) {
+		f.AddTo(enc)
+	}
+	entry := admin.LogEntry{Time: ent.Time.UTC(), Level: ent.Level.String(), Message: ent.Message}
+	if len(enc.Fields) > 0 {
+		entry.Fields = make(map[string]string, len(enc.Fields))
+		for k, v := range enc.Fields {
+			entry.Fields[k] = fmt.Sprint(v)
+		}
+	}
+
+	e.buf.mu.Lock()
+	defer e.buf.mu.Unlock()
+	e.buf.entries = append(e.buf.entries, entry)
+	if len(e.buf.entries) > maxRecentErrors {
+		e.buf.entries = append([]admin.LogEntry(nil), e.buf.entries[len(e.buf.entries)-maxRecentErrors:]...)
+	}
+	return nil
+}
+
+func (e *recentErrors) Sync() error {
+	return nil
+}
+
+// snapshot returns the warnings and errors kept, oldest first.
+func (e *recentErrors) snapshot() []admin.LogEntry {
+	e.buf.mu.Lock()
+	defer e.buf.mu.Unlock()
+	return append([]admin.LogEntry{}, e.buf.entries...)
+}
@@ -0,0 +1,44 @@
+package relayer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRecentErrors(t *testing.T) {
+	errs := newRecentErrors()
+	log := zap.New(zapcore.NewTee(zapcore.NewNopCore(), errs)).With(zap.String("path", "demo-path"))
+
+	log.Info("Relayed packets")
+	log.Warn("Failed to query packet commitments", zap.String("channel_id", "channel-0"), zap.Uint("attempt", 2))
+	entries := errs.snapshot()
+	require.Len(t, entries, 1)
+	require.Equal(t, "warn", entries[0].Level)
+	require.Equal(t, "Failed to query packet commitments", entries[0].Message)
+	require.Equal(t, map[string]string{"path": "demo-path", "channel_id": "channel-0", "attempt": "2"}, entries[0].Fields)
+
+	// Only the most recent entries are kept, oldest first.
+	for i := 0; i < maxRecentErrors+5; i++ {
+		log.Error(fmt.Sprintf("error %d", i))
+	}
+	entries = errs.snapshot()
+	require.Len(t, entries, maxRecentErrors)
+	require.Equal(t, "error 5", entries[0].Message)
+	require.Equal(t, fmt.Sprintf("error %d", maxRecentErrors+4), entries[maxRecentErrors-1].Message)
+}
+
+func TestPathLoggerTap(t *testing.T) {
+	errs := newRecentErrors()
+	l := newPathLogger(zapcore.NewNopCore(), "logfmt")
+	l.tap = errs
+	require.NoError(t, l.configure(PathLogConfig{Level: "debug", File: t.TempDir() + "/path.log"}))
+	defer l.close()
+
+	// Paths logging to an output of their own still report their errors.
+	l.logger(zap.NewNop()).Error("Relay packets error")
+	require.Len(t, errs.snapshot(), 1)
+}
@@ -2,6 +2,7 @@ package relayer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -147,6 +148,14 @@ func IsMaxTx(MaxMsgLength, MaxTxSize, msgLen, txSize uint64) bool {
 		(MaxTxSize != 0 && txSize > MaxTxSize)
 }
 
+// ErrOversizedMessage is returned by Send for a message that exceeds the maximum tx size on its own,
+// and failed to be delivered in a transaction of its own.
+var ErrOversizedMessage = errors.New("message exceeds max tx size")
+
+// Send sends msgs to s in batches of at most MaxMsgLength messages and MaxTxSize bytes.
+// A message exceeding MaxTxSize on its own, e.g. a MsgRecvPacket carrying a large proof, never fits in a batch,
+// so it is sent alone, in a transaction of its own, and reported if that transaction fails.
+// With a cross-channel batcher attached to ctx, msgs are merged with the messages other channels send to s.
 func Send(
 	ctx context.Context,
 	log *zap.Logger,
@@ -157,6 +166,41 @@ func Send(
 	errors *error,
 	MaxMsgLength, MaxTxSize uint64,
 ) {
+	if b := msgBatcherFromContext(ctx); b != nil && len(msgs) > 0 {
+		n, err := b.submit(ctx, s, msgs, memo, MaxMsgLength, MaxTxSize)
+		*successes += n
+		multierr.AppendInto(errors, err)
+		return
+	}
+	sendBatches(ctx, log, s, msgs, memo, successes, errors, MaxMsgLength, MaxTxSize)
+}
+
+func sendBatches(
+	ctx context.Context,
+	log *zap.Logger,
+	s RelayMsgSender,
+	msgs []provider.RelayerMessage,
+	memo string,
+	successes *int,
+	errors *error,
+	MaxMsgLength, MaxTxSize uint64,
+) {
+	sendBatch := func(batchMsgs []provider.RelayerMessage) error {
+		batchCtx, batchCtxCancel := context.WithTimeout(ctx, batchSendMessageTimeout)
+		resp, success, err := s.SendMessages(batchCtx, batchMsgs, memo)
+		batchCtxCancel()
+		recordMetrics(ctx, s.ChainID, resp, success, batchMsgs)
+		recordSentMsgs(ctx, batchMsgs, resp, success, err)
+		if success {
+			recordTx(ctx, s.ChainID, resp)
+			*successes++
+		}
+		if err != nil {
+			logFailedTx(log, s.ChainID, resp, err, batchMsgs)
+		}
+		return err
+	}
+
 	var txSize, batchStartIdx uint64
 
 	for i, msg := range msgs {
@@ -171,6 +215,32 @@ func Send(
 			panic(err)
 		}
 
+		if MaxTxSize != 0 && uint64(len(bz)) > MaxTxSize {
+			// Send out the messages batched so far, then the oversized message alone.
+			if batchStartIdx < uint64(i) {
+				// TODO: check chain ordering.
+				multierr.AppendInto(errors, sendBatch(msgs[batchStartIdx:i]))
+			}
+			log.Warn(
+				"Message exceeds max tx size, sending it in a transaction of its own",
+				zap.String("chain_id", s.ChainID),
+				zap.String("msg_type", msg.Type()),
+				zap.Uint64("sequence", msg.Seq()),
+				zap.Int("msg_size", len(bz)),
+				zap.Uint64("max_tx_size", MaxTxSize),
+			)
+			if err := sendBatch(msgs[i : i+1]); err != nil {
+				multierr.AppendInto(errors, fmt.Errorf(
+					"%w: %s with sequence %d of %d bytes (max tx size %d) is undeliverable on %s: %v",
+					ErrOversizedMessage, msg.Type(), msg.Seq(), len(bz), MaxTxSize, s.ChainID, err,
+				))
+			}
+
+			batchStartIdx = uint64(i) + 1
+			txSize = 0
+			continue
+		}
+
 		if !IsMaxTx(MaxMsgLength, MaxTxSize, uint64(i)+1-batchStartIdx, txSize+uint64(len(bz))) {
 			// We can add another transaction, so increase the transaction size counter
 			// and proceed to the next message.
@@ -180,20 +250,10 @@ func Send(
 
 		// Otherwise, we have reached the message count limit or the byte size limit.
 		// Send out this batch now.
-		batchMsgs := msgs[batchStartIdx:i]
-		batchCtx, batchCtxCancel := context.WithTimeout(ctx, batchSendMessageTimeout)
-		resp, success, err := s.SendMessages(batchCtx, batchMsgs, memo)
-		batchCtxCancel()
-		if err != nil {
-			logFailedTx(log, s.ChainID, resp, err, batchMsgs)
-			multierr.AppendInto(errors, err)
-			// TODO: check chain ordering.
-			// If chain is unordered, we can keep sending;
-			// otherwise we need to stop now.
-		}
-		if success {
-			*successes++
-		}
+		// TODO: check chain ordering.
+		// If chain is unordered, we can keep sending;
+		// otherwise we need to stop now.
+		multierr.AppendInto(errors, sendBatch(msgs[batchStartIdx:i]))
 
 		// Reset counters.
 		batchStartIdx = uint64(i)
@@ -202,16 +262,6 @@ func Send(
 
 	// If there are any messages left over, send those out too.
 	if batchStartIdx < uint64(len(msgs)) {
-		batchMsgs := msgs[batchStartIdx:]
-		batchCtx, batchCtxCancel := context.WithTimeout(ctx, batchSendMessageTimeout)
-		resp, success, err := s.SendMessages(batchCtx, batchMsgs, memo)
-		batchCtxCancel()
-		if err != nil {
-			logFailedTx(log, s.ChainID, resp, err, batchMsgs)
-			multierr.AppendInto(errors, err)
-		}
-		if success {
-			*successes++
-		}
+		multierr.AppendInto(errors, sendBatch(msgs[batchStartIdx:]))
 	}
 }
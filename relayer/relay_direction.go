@@ -0,0 +1,114 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// RelayDirection selects the half of the packet flow of the paths relayed by the process, so that the packets gated
+// on the finality of rollapps and the packets relayed as soon as they are sent can be relayed by separate deployments.
+type RelayDirection string
+
+const (
+	// RelayBothDirections relays all the packets of the paths.
+	RelayBothDirections RelayDirection = ""
+
+	// RelaySettlementDirection only relays the packets sent on rollapps, e.g. rollapp to hub,
+	// along with their acknowledgements and timeouts.
+	RelaySettlementDirection RelayDirection = "settlement"
+
+	// RelayInstantDirection only relays the packets sent on chains which are not rollapps, e.g. hub to rollapp,
+	// along with their acknowledgements and timeouts.
+	RelayInstantDirection RelayDirection = "instant"
+)
+
+// ParseRelayDirection returns the direction named s, "both" or empty selecting both directions.
+func ParseRelayDirection(s string) (RelayDirection, error) {
+	switch d := RelayDirection(s); d {
+	case RelayBothDirections, RelaySettlementDirection, RelayInstantDirection:
+		return d, nil
+	case "both":
+		return RelayBothDirections, nil
+	default:
+		return "", fmt.Errorf("invalid relay direction %q, expected one of both, %s or %s", s, RelaySettlementDirection, RelayInstantDirection)
+	}
+}
+
+// String returns the name of d.
+func (d RelayDirection) String() string {
+	if d == RelayBothDirections {
+		return "both"
+	}
+	return string(d)
+}
+
+// relaysPacketsSentOn returns true if the packets sent on the chain of cp are relayed in the direction d.
+func (d RelayDirection) relaysPacketsSentOn(cp provider.ChainProvider) bool {
+	switch d {
+	case RelaySettlementDirection:
+		return isRollapp(cp)
+	case RelayInstantDirection:
+		return !isRollapp(cp)
+	default:
+		return true
+	}
+}
+
+type relayDirectionKey struct{}
+
+// withRelayDirection only relays the packets of direction d with ctx, see relayedPackets and relayedAcknowledgements.
+func withRelayDirection(ctx context.Context, d RelayDirection) context.Context {
+	if d == RelayBothDirections {
+		return ctx
+	}
+	return context.WithValue(ctx, relayDirectionKey{}, d)
+}
+
+// relaysPacketsSentOn returns true if the packets sent on c are relayed with ctx.
+func relaysPacketsSentOn(ctx context.Context, c *Chain) bool {
+	d, _ := ctx.Value(relayDirectionKey{}).(RelayDirection)
+	return d.relaysPacketsSentOn(c.ChainProvider)
+}
+
+// relayedPackets leaves out of sp, the packets sent over a channel between src and dst, those relayed in the other
+// direction than the one attached to ctx.
+func relayedPackets(ctx context.Context, src, dst *Chain, sp RelaySequences) RelaySequences {
+	if !relaysPacketsSentOn(ctx, src) {
+		sp.Src = nil
+	}
+	if !relaysPacketsSentOn(ctx, dst) {
+		sp.Dst = nil
+	}
+	return sp
+}
+
+// relayedAcknowledgements leaves out of ap, the acknowledgements written over a channel between src and dst,
+// those of packets relayed in the other direction than the one attached to ctx. The acknowledgements written on src
+// are those of the packets sent on dst.
+func relayedAcknowledgements(ctx context.Context, src, dst *Chain, ap RelaySequences) RelaySequences {
+	if !relaysPacketsSentOn(ctx, dst) {
+		ap.Src = nil
+	}
+	if !relaysPacketsSentOn(ctx, src) {
+		ap.Dst = nil
+	}
+	return ap
+}
+
+// logRelayDirection logs the direction relayed by the process, warning about the paths with no packets to relay in it.
+func (s *supervisor) logRelayDirection() {
+	s.log.Info("Only relaying one direction of the paths", zap.Stringer("direction", s.relayDirection))
+	for _, name := range s.names {
+		r := s.runners[name]
+		if !s.relayDirection.relaysPacketsSentOn(r.src.ChainProvider) && !s.relayDirection.relaysPacketsSentOn(r.dst.ChainProvider) {
+			s.log.Warn(
+				"No packets of path are relayed in the direction",
+				zap.String("path", name),
+				zap.Stringer("direction", s.relayDirection),
+			)
+		}
+	}
+}
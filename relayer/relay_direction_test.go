@@ -0,0 +1,52 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayDirection(t *testing.T) {
+	for s, want := range map[string]RelayDirection{
+		"":           RelayBothDirections,
+		"both":       RelayBothDirections,
+		"settlement": RelaySettlementDirection,
+		"instant":    RelayInstantDirection,
+	} {
+		d, err := ParseRelayDirection(s)
+		require.NoError(t, err)
+		require.Equal(t, want, d)
+	}
+	_, err := ParseRelayDirection("rollapp")
+	require.Error(t, err)
+
+	hub := &Chain{ChainProvider: &cosmos.CosmosProvider{PCfg: cosmos.CosmosProviderConfig{
+		ChainID:    "hub",
+		ClientType: ibcexported.Tendermint,
+	}}}
+	rollapp := &Chain{ChainProvider: &cosmos.CosmosProvider{PCfg: cosmos.CosmosProviderConfig{
+		ChainID:    "rollapp",
+		ClientType: ibcexported.Furyint,
+	}}}
+	sp := RelaySequences{Src: []uint64{1, 2}, Dst: []uint64{3}}
+	ap := RelaySequences{Src: []uint64{4}, Dst: []uint64{5, 6}}
+
+	// Both directions are relayed by default.
+	ctx := withRelayDirection(context.Background(), RelayBothDirections)
+	require.Equal(t, sp, relayedPackets(ctx, rollapp, hub, sp))
+	require.Equal(t, ap, relayedAcknowledgements(ctx, rollapp, hub, ap))
+
+	// The settlement direction relays the packets sent on the rollapp, and their acknowledgements written on the hub.
+	ctx = withRelayDirection(context.Background(), RelaySettlementDirection)
+	require.Equal(t, RelaySequences{Src: []uint64{1, 2}}, relayedPackets(ctx, rollapp, hub, sp))
+	require.Equal(t, RelaySequences{Dst: []uint64{5, 6}}, relayedAcknowledgements(ctx, rollapp, hub, ap))
+	require.Equal(t, RelaySequences{Dst: []uint64{3}}, relayedPackets(ctx, hub, rollapp, sp))
+
+	// The instant direction relays the others.
+	ctx = withRelayDirection(context.Background(), RelayInstantDirection)
+	require.Equal(t, RelaySequences{Dst: []uint64{3}}, relayedPackets(ctx, rollapp, hub, sp))
+	require.Equal(t, RelaySequences{Src: []uint64{4}}, relayedAcknowledgements(ctx, rollapp, hub, ap))
+}
@@ -0,0 +1,222 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"go.uber.org/zap"
+)
+
+// Statuses of a SequenceResult.
+const (
+	SequenceRelayed = "relayed"
+	SequenceSkipped = "skipped"
+	SequenceFailed  = "failed"
+)
+
+// SequenceResult is the outcome of relaying a packet, or the acknowledgement of a packet, by its sequence.
+// ChainID, ChannelID and PortID identify the channel end the packet or acknowledgement was relayed from.
+type SequenceResult struct {
+	ChainID   string `json:"chain_id"`
+	ChannelID string `json:"channel_id"`
+	PortID    string `json:"port_id"`
+	Sequence  uint64 `json:"sequence"`
+
+	// MsgType is the message relaying the sequence, one of the types of the relayed_packets_total metric,
+	// empty if the sequence was given up before its message was built.
+	MsgType string `json:"msg_type,omitempty"`
+
+	Status string `json:"status"`
+
+	// Reason tells why the sequence was skipped or failed.
+	Reason string `json:"reason,omitempty"`
+
+	// TxHash is the transaction the message was included in, also set for a transaction that failed on chain.
+	TxHash string `json:"tx_hash,omitempty"`
+
+	// msg is the queued message of the sequence, awaiting its outcome, see SequenceResults.resolve.
+	msg provider.RelayerMessage
+
+	// ack is whether the acknowledgement of the packet was relayed, rather than the packet.
+	ack bool
+}
+
+// SequenceResults are the outcomes of relaying a batch of sequences.
+type SequenceResults []SequenceResult
+
+// Count returns the number of sequences with the given status.
+func (r SequenceResults) Count(status string) int {
+	n := 0
+	for _, res := range r {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// skip adds the results of the sequences given up for reason.
+func (r *SequenceResults) skip(chainID, channelID, portID string, sequences []uint64, reason string) {
+	for _, seq := range sequences {
+		*r = append(*r, SequenceResult{
+			ChainID:   chainID,
+			ChannelID: channelID,
+			PortID:    portID,
+			Sequence:  seq,
+			Status:    SequenceSkipped,
+			Reason:    reason,
+		})
+	}
+}
+
+// fail marks the sequences with a queued message as failed for reason, when the messages could not be sent at all.
+func (r SequenceResults) fail(err error) {
+	for i := range r {
+		if r[i].msg != nil {
+			r[i].Status, r[i].Reason, r[i].msg = SequenceFailed, err.Error(), nil
+		}
+	}
+}
+
+// resolve completes the results of the sequences with a queued message with the outcome of sending it.
+// A message without outcome was not sent, because of sendErr if set.
+func (r SequenceResults) resolve(sent *sentMsgs, sendErr error) {
+	for i := range r {
+		if r[i].msg == nil {
+			continue
+		}
+		out, ok := sent.outcome(r[i].msg)
+		switch {
+		case ok && out.err == nil:
+			r[i].Status = SequenceRelayed
+		case ok:
+			r[i].Status, r[i].Reason = SequenceFailed, out.err.Error()
+		case sendErr != nil:
+			r[i].Status, r[i].Reason = SequenceFailed, sendErr.Error()
+		default:
+			r[i].Status, r[i].Reason = SequenceFailed, "message was not sent"
+		}
+		r[i].TxHash = out.txHash
+		r[i].msg = nil
+	}
+}
+
+// report logs the results relaying sequences from srcChainID to dstChainID and counts them in the metrics attached to ctx.
+func (r SequenceResults) report(ctx context.Context, log *zap.Logger, srcChainID, dstChainID string) {
+	if len(r) == 0 {
+		return
+	}
+	for _, res := range r {
+		recordSequenceResult(ctx, res)
+		recordRelayAttempt(ctx, res)
+		fields := []zap.Field{
+			zap.String("src_chain_id", res.ChainID),
+			zap.String("src_channel_id", res.ChannelID),
+			zap.String("src_port_id", res.PortID),
+			zap.Uint64("sequence", res.Sequence),
+			zap.String("msg_type", res.MsgType),
+			zap.String("reason", res.Reason),
+		}
+		switch res.Status {
+		case SequenceFailed:
+			log.Info("Failed to relay sequence", fields...)
+		case SequenceSkipped:
+			log.Debug("Skipped relaying sequence", fields...)
+		}
+	}
+	log.Info(
+		"Relayed sequences",
+		zap.String("src_chain_id", srcChainID),
+		zap.String("dst_chain_id", dstChainID),
+		zap.Int("relayed", r.Count(SequenceRelayed)),
+		zap.Int("skipped", r.Count(SequenceSkipped)),
+		zap.Int("failed", r.Count(SequenceFailed)),
+	)
+}
+
+// packetMsgType returns the relayed_packets_total type of a packet message, empty for other messages.
+func packetMsgType(msg provider.RelayerMessage) string {
+	cm, ok := msg.(cosmosprovider.CosmosMessage)
+	if !ok {
+		return ""
+	}
+	switch cm.Msg.(type) {
+	case *chantypes.MsgRecvPacket:
+		return processor.MetricRecvPacket
+	case *chantypes.MsgAcknowledgement:
+		return processor.MetricAckPacket
+	case *chantypes.MsgTimeout, *chantypes.MsgTimeoutOnClose:
+		return processor.MetricTimeout
+	}
+	return ""
+}
+
+type sentMsgsKey struct{}
+
+// sentMsgs collects the outcome of each message sent by Send while it is attached to the context, by message bytes.
+type sentMsgs struct {
+	mu       sync.Mutex
+	outcomes map[string]sentMsg
+}
+
+type sentMsg struct {
+	txHash string
+	err    error
+}
+
+func withSentMsgs(ctx context.Context) (context.Context, *sentMsgs) {
+	s := &sentMsgs{outcomes: make(map[string]sentMsg)}
+	return context.WithValue(ctx, sentMsgsKey{}, s), s
+}
+
+// recordSentMsgs records the outcome of the transaction sending msgs to the sentMsgs attached to ctx, if any.
+func recordSentMsgs(ctx context.Context, msgs []provider.RelayerMessage, resp *provider.RelayerTxResponse, success bool, err error) {
+	s, ok := ctx.Value(sentMsgsKey{}).(*sentMsgs)
+	if !ok {
+		return
+	}
+	out := sentMsg{err: err}
+	if resp != nil {
+		out.txHash = resp.TxHash
+	}
+	if !success && err == nil {
+		out.err = fmt.Errorf("transaction failed")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, msg := range msgs {
+		bz, err := msg.MsgBytes()
+		if err != nil {
+			continue
+		}
+		s.outcomes[string(bz)] = out
+	}
+}
+
+// outcome returns the outcome of sending msg, if it was sent.
+func (s *sentMsgs) outcome(msg provider.RelayerMessage) (sentMsg, bool) {
+	bz, err := msg.MsgBytes()
+	if err != nil {
+		return sentMsg{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out, ok := s.outcomes[string(bz)]
+	return out, ok
+}
+
+// forward records the outcomes of msgs to the sentMsgs attached to ctx, if any,
+// for the messages a msgBatcher sent on behalf of a submission.
+func (s *sentMsgs) forward(ctx context.Context, msgs []provider.RelayerMessage) {
+	for _, msg := range msgs {
+		if out, ok := s.outcome(msg); ok {
+			success := out.err == nil
+			recordSentMsgs(ctx, []provider.RelayerMessage{msg}, &provider.RelayerTxResponse{TxHash: out.txHash}, success, out.err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSequenceResultsResolve(t *testing.T) {
+	recv := func(seq uint64) provider.RelayerMessage {
+		return cosmosprovider.NewCosmosMessage(&chantypes.MsgRecvPacket{Packet: chantypes.Packet{
+			Sequence:      seq,
+			SourcePort:    "transfer",
+			SourceChannel: "channel-0",
+		}})
+	}
+	dst := RelayMsgSender{
+		ChainID: "dst",
+		SendMessages: func(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+			if msgs[0].Seq() == 2 {
+				return &provider.RelayerTxResponse{TxHash: "FAILED", Code: 11}, false, errors.New("out of gas")
+			}
+			return &provider.RelayerTxResponse{TxHash: "OK"}, true, nil
+		},
+	}
+	log := zaptest.NewLogger(t)
+
+	for name, batchWindow := range map[string]time.Duration{"direct": 0, "batched": 10 * time.Millisecond} {
+		t.Run(name, func(t *testing.T) {
+			var results SequenceResults
+			var msgs []provider.RelayerMessage
+			for seq := uint64(1); seq <= 2; seq++ {
+				msg := recv(seq)
+				msgs = append(msgs, msg)
+				results = append(results, SequenceResult{ChainID: "src", ChannelID: "channel-0", PortID: "transfer", Sequence: seq, MsgType: packetMsgType(msg), msg: msg})
+			}
+			results.skip("src", "channel-0", "transfer", []uint64{3}, "filtered out by the packet filter")
+
+			ctx, sent := withSentMsgs(withMsgBatcher(context.Background(), log, batchWindow))
+			var (
+				successes int
+				err       error
+			)
+			// one message per transaction
+			Send(ctx, log, dst, msgs, "", &successes, &err, 1, 0)
+			require.Error(t, err)
+			results.resolve(sent, err)
+
+			require.Equal(t, SequenceResults{
+				{ChainID: "src", ChannelID: "channel-0", PortID: "transfer", Sequence: 1, MsgType: "recv_packet", Status: SequenceRelayed, TxHash: "OK"},
+				{ChainID: "src", ChannelID: "channel-0", PortID: "transfer", Sequence: 2, MsgType: "recv_packet", Status: SequenceFailed, Reason: "out of gas", TxHash: "FAILED"},
+				{ChainID: "src", ChannelID: "channel-0", PortID: "transfer", Sequence: 3, Status: SequenceSkipped, Reason: "filtered out by the packet filter"},
+			}, results)
+			require.Equal(t, 1, results.Count(SequenceFailed))
+		})
+	}
+}
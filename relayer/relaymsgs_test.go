@@ -288,3 +288,59 @@ func TestRelayMsgs_Send_Errors(t *testing.T) {
 		require.ErrorIs(t, result.DstSendError, dstErr)
 	})
 }
+
+func TestRelayMsgs_Send_OversizedMessage(t *testing.T) {
+	smallMsg := fakeRelayerMessage{t: "small", b: "data"}
+	bigMsg := fakeRelayerMessage{t: "big", b: "a proof way bigger than the max tx size"}
+
+	t.Run("sends oversized message alone", func(t *testing.T) {
+		var batches [][]provider.RelayerMessage
+		src := relayer.RelayMsgSender{
+			ChainID: "src",
+			SendMessages: func(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+				batches = append(batches, msgs)
+				return nil, true, nil
+			},
+		}
+
+		rm := relayer.RelayMsgs{
+			Src: []provider.RelayerMessage{bigMsg, smallMsg, smallMsg, bigMsg, smallMsg},
+
+			MaxTxSize: 10,
+		}
+
+		result := rm.Send(context.Background(), zaptest.NewLogger(t), src, relayer.RelayMsgSender{}, "")
+		require.NoError(t, result.SrcSendError)
+		require.Equal(t, 4, result.SuccessfulSrcBatches)
+		require.Equal(t, [][]provider.RelayerMessage{
+			{bigMsg},
+			{smallMsg, smallMsg},
+			{bigMsg},
+			{smallMsg},
+		}, batches)
+	})
+
+	t.Run("reports undeliverable oversized message", func(t *testing.T) {
+		srcErr := fmt.Errorf("tx too large")
+		src := relayer.RelayMsgSender{
+			ChainID: "src",
+			SendMessages: func(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+				if msgs[0] == bigMsg {
+					return nil, false, srcErr
+				}
+				return nil, true, nil
+			},
+		}
+
+		rm := relayer.RelayMsgs{
+			Src: []provider.RelayerMessage{smallMsg, bigMsg},
+
+			MaxTxSize: 10,
+		}
+
+		result := rm.Send(context.Background(), zaptest.NewLogger(t), src, relayer.RelayMsgSender{}, "")
+		require.Equal(t, 1, result.SuccessfulSrcBatches)
+		require.ErrorIs(t, result.SrcSendError, relayer.ErrOversizedMessage)
+		require.ErrorContains(t, result.SrcSendError, "tx too large")
+	})
+}
@@ -0,0 +1,334 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultReleaseCheckInterval is how often the release manifest is fetched, unless configured.
+	defaultReleaseCheckInterval = 6 * time.Hour
+
+	// releaseCheckTimeout bounds the fetch of the release manifest, and the query of the latest height
+	// of each chain scheduling an incompatible upgrade.
+	releaseCheckTimeout = 30 * time.Second
+)
+
+// ReleaseCheckConfig configures the checks of the running version of `rly start` against a release manifest,
+// at startup and every Interval, warning of new releases and of the upgrades of the relayed chains
+// the running version cannot relay past.
+type ReleaseCheckConfig struct {
+	// URL is the http or https URL of the release manifest, a JSON ReleaseManifest.
+	URL string `yaml:"url" json:"url"`
+
+	// Interval is how often the manifest is fetched, every 6 hours if unset.
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+
+	// Headers are added to the requests, such as an Authorization header.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// Validate checks the release check config.
+func (c *ReleaseCheckConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("release-check url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("release-check url must be an http or https URL, got %q", c.URL)
+	}
+	if c.Interval < 0 {
+		return errors.New("release-check interval must not be negative")
+	}
+	return nil
+}
+
+// ReleaseManifest is published by the maintainers of the relayer, listing its latest release
+// and the chain upgrades requiring a newer relayer than some of its releases.
+type ReleaseManifest struct {
+	Latest               Release               `json:"latest"`
+	IncompatibleUpgrades []IncompatibleUpgrade `json:"incompatible_upgrades,omitempty"`
+}
+
+// Release is a release of the relayer.
+type Release struct {
+	Version string `json:"version"`
+	URL     string `json:"url,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// IncompatibleUpgrade is a software upgrade of a chain the relayers older than MinVersion cannot relay past,
+// e.g. an upgrade of the hub changing the finalized states of its rollapps.
+type IncompatibleUpgrade struct {
+	ChainID    string `json:"chain_id"`
+	Name       string `json:"name,omitempty"`
+	Height     int64  `json:"height"`
+	MinVersion string `json:"min_version"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// key identifies the upgrade among the upgrades of the manifest.
+func (u IncompatibleUpgrade) key() string {
+	return u.ChainID + "/" + strconv.FormatInt(u.Height, 10)
+}
+
+// ReleaseStatus is the running version of the relayer as last checked against the release manifest.
+type ReleaseStatus struct {
+	Version string `json:"version"`
+
+	// Latest is the latest release of the manifest, and UpdateAvailable whether it is newer than the running version.
+	Latest          *Release `json:"latest,omitempty"`
+	UpdateAvailable bool     `json:"update_available"`
+
+	// Incompatible are the upgrades of the relayed chains requiring a newer version than the running one,
+	// by chain ID and height.
+	Incompatible []IncompatibleChainUpgrade `json:"incompatible,omitempty"`
+
+	// CheckedAt is when the manifest was last fetched, and Error why the last fetch failed, if it did.
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// IncompatibleChainUpgrade is an incompatible upgrade of a relayed chain.
+type IncompatibleChainUpgrade struct {
+	IncompatibleUpgrade
+
+	// LatestHeight is the latest height of the chain as last queried, 0 if the query failed.
+	LatestHeight int64 `json:"latest_height,omitempty"`
+
+	// Paths are the names of the paths relaying the chain.
+	Paths []string `json:"paths"`
+}
+
+// releaseChecker checks the running version of the relayer against the release manifest.
+type releaseChecker struct {
+	log     *zap.Logger
+	cfg     *ReleaseCheckConfig
+	version string
+
+	// chains are the relayed chains by chain ID, and paths the names of the paths relaying each.
+	chains map[string]*Chain
+	paths  map[string][]string
+
+	// announced is the latest release last logged as available, only accessed by check.
+	announced string
+
+	mu     sync.Mutex
+	status ReleaseStatus
+}
+
+func newReleaseChecker(log *zap.Logger, cfg *ReleaseCheckConfig, version string, runners map[string]*pathRunner, names []string) *releaseChecker {
+	c := &releaseChecker{
+		log:     log,
+		cfg:     cfg,
+		version: version,
+		chains:  make(map[string]*Chain),
+		paths:   make(map[string][]string),
+		status:  ReleaseStatus{Version: version},
+	}
+	for _, name := range names {
+		r := runners[name]
+		for _, ch := range []*Chain{r.src, r.dst} {
+			c.chains[ch.ChainID()] = ch
+			c.paths[ch.ChainID()] = append(c.paths[ch.ChainID()], name)
+		}
+	}
+	return c
+}
+
+// check fetches the release manifest, logging the latest release once when newer than the running version,
+// and the incompatible upgrades of the relayed chains at every check until the relayer is updated.
+// The status of the previous check is kept when the manifest can't be fetched.
+func (c *releaseChecker) check(ctx context.Context) {
+	fetchCtx, cancel := context.WithTimeout(ctx, releaseCheckTimeout)
+	m, err := fetchReleaseManifest(fetchCtx, c.cfg)
+	cancel()
+	if err != nil {
+		c.log.Warn("Failed to fetch release manifest", zap.Error(err))
+		c.mu.Lock()
+		c.status.Error = err.Error()
+		c.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	status := ReleaseStatus{Version: c.version, Latest: &m.Latest, CheckedAt: &now}
+	if _, _, ok := parseVersion(c.version); !ok {
+		c.log.Debug("Not checking unreleased relayer version against release manifest", zap.String("version", c.version))
+		c.set(status)
+		return
+	}
+
+	if cmp, ok := compareVersions(c.version, m.Latest.Version); ok && cmp < 0 {
+		status.UpdateAvailable = true
+		if m.Latest.Version != c.announced {
+			c.announced = m.Latest.Version
+			c.log.Warn(
+				"New relayer version available",
+				zap.String("version", c.version),
+				zap.String("latest", m.Latest.Version),
+				zap.String("url", m.Latest.URL),
+				zap.String("notes", m.Latest.Notes),
+			)
+		}
+	}
+
+	for _, u := range m.IncompatibleUpgrades {
+		ch, ok := c.chains[u.ChainID]
+		if !ok {
+			continue
+		}
+		if cmp, ok := compareVersions(c.version, u.MinVersion); !ok || cmp >= 0 {
+			continue
+		}
+		inc := IncompatibleChainUpgrade{IncompatibleUpgrade: u, Paths: c.paths[u.ChainID]}
+		queryCtx, cancel := context.WithTimeout(ctx, releaseCheckTimeout)
+		h, err := ch.ChainProvider.QueryLatestHeight(queryCtx)
+		cancel()
+		if err == nil {
+			inc.LatestHeight = h
+		}
+		status.Incompatible = append(status.Incompatible, inc)
+
+		fields := []zap.Field{
+			zap.String("chain_id", u.ChainID),
+			zap.String("upgrade", u.Name),
+			zap.Int64("upgrade_height", u.Height),
+			zap.Int64("latest_height", inc.LatestHeight),
+			zap.String("version", c.version),
+			zap.String("min_version", u.MinVersion),
+			zap.String("reason", u.Reason),
+			zap.Strings("paths", inc.Paths),
+		}
+		if inc.LatestHeight >= u.Height {
+			c.log.Error("Relayer version incompatible with chain upgrade already applied, update the relayer", fields...)
+		} else {
+			c.log.Warn("Relayer version incompatible with upcoming chain upgrade, update the relayer before its height", fields...)
+		}
+	}
+	sort.Slice(status.Incompatible, func(i, j int) bool {
+		return status.Incompatible[i].key() < status.Incompatible[j].key()
+	})
+	c.set(status)
+}
+
+func (c *releaseChecker) set(status ReleaseStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
+// snapshot returns the status of the last check.
+func (c *releaseChecker) snapshot() ReleaseStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// fetchReleaseManifest fetches the release manifest of cfg.
+func fetchReleaseManifest(ctx context.Context, cfg *ReleaseCheckConfig) (*ReleaseManifest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("release manifest responded with status %s", res.Status)
+	}
+	var m ReleaseManifest
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid release manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// gitDescribeSuffix matches the suffix of the versions built from a commit after a tag, e.g. 4-g1a2b3c4d.
+var gitDescribeSuffix = regexp.MustCompile(`^\d+-g[0-9a-f]+$`)
+
+// parseVersion parses a version such as v2.3.1, 2.3.1-rc.1, or 2.3.1-4-g1a2b3c4d built after the tag v2.3.1,
+// which is parsed as 2.3.1.
+func parseVersion(v string) (core [3]int, pre string, ok bool) {
+	v = strings.TrimPrefix(v, "v")
+	v, pre, _ = strings.Cut(v, "-")
+	if gitDescribeSuffix.MatchString(pre) {
+		pre = ""
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != len(core) {
+		return core, "", false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return core, "", false
+		}
+		core[i] = n
+	}
+	return core, pre, true
+}
+
+// compareVersions returns -1, 0 or 1 as version a is older than, the same as or newer than b,
+// and false if either can't be parsed. Pre-releases are older than their release.
+func compareVersions(a, b string) (int, bool) {
+	ca, pa, ok := parseVersion(a)
+	if !ok {
+		return 0, false
+	}
+	cb, pb, ok := parseVersion(b)
+	if !ok {
+		return 0, false
+	}
+	for i := range ca {
+		switch {
+		case ca[i] < cb[i]:
+			return -1, true
+		case ca[i] > cb[i]:
+			return 1, true
+		}
+	}
+	switch {
+	case pa == pb:
+		return 0, true
+	case pa == "":
+		return 1, true
+	case pb == "":
+		return -1, true
+	}
+	return strings.Compare(pa, pb), true
+}
+
+// startReleaseCheck checks the running version of the relayer against the release manifest of cfg at startup,
+// and then every interval of cfg.
+func (s *supervisor) startReleaseCheck(ctx context.Context, cfg *ReleaseCheckConfig, version string) *releaseChecker {
+	c := newReleaseChecker(s.log.With(zap.String("sys", "releasecheck")), cfg, version, s.runners, s.names)
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultReleaseCheckInterval
+	}
+	go func() {
+		c.check(ctx)
+		s.maintenance.schedule(ctx, TaskReleaseCheck, interval, c.check)
+	}()
+	return c
+}
@@ -0,0 +1,109 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		cmp  int
+		ok   bool
+	}{
+		{"2.3.1", "v2.3.1", 0, true},
+		{"2.3.1", "2.4.0", -1, true},
+		{"v2.10.0", "v2.9.3", 1, true},
+		{"2.4.0-rc.1", "2.4.0", -1, true},
+		{"2.4.0-rc.2", "2.4.0-rc.1", 1, true},
+		// Builds after a tag are the release of the tag.
+		{"2.3.1-4-g1a2b3c4d", "2.3.1", 0, true},
+		{"", "2.3.1", 0, false},
+		{"2.3", "2.3.1", 0, false},
+	} {
+		cmp, ok := compareVersions(tc.a, tc.b)
+		require.Equal(t, tc.ok, ok, "%s %s", tc.a, tc.b)
+		require.Equal(t, tc.cmp, cmp, "%s %s", tc.a, tc.b)
+	}
+}
+
+func TestReleaseCheckConfigValidate(t *testing.T) {
+	require.NoError(t, (&ReleaseCheckConfig{URL: "https://releases.example.com/rly.json"}).Validate())
+	var none *ReleaseCheckConfig
+	require.NoError(t, none.Validate())
+	require.EqualError(t, (&ReleaseCheckConfig{URL: "releases.json"}).Validate(),
+		`release-check url must be an http or https URL, got "releases.json"`)
+}
+
+func TestReleaseChecker(t *testing.T) {
+	manifest := ReleaseManifest{
+		Latest: Release{Version: "v2.5.0", URL: "https://github.com/furychain/furya-relayer/releases/tag/v2.5.0"},
+		IncompatibleUpgrades: []IncompatibleUpgrade{
+			{ChainID: "hub", Name: "v3", Height: 100, MinVersion: "v2.5.0", Reason: "new rollapp state updates"},
+			{ChainID: "hub", Name: "v2", Height: 50, MinVersion: "v2.2.0"},
+			{ChainID: "osmosis-1", Height: 100, MinVersion: "v3.0.0"},
+		},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(manifest))
+	}))
+	defer srv.Close()
+
+	hub := &upgradingProvider{chainID: "hub", height: 90}
+	chains := map[string]*Chain{"hub": {Chainid: "hub", ChainProvider: hub}, "rollapp": {Chainid: "rollapp", ChainProvider: &upgradingProvider{chainID: "rollapp"}}}
+	paths := []NamedPath{{Name: "hub-rollapp", Path: &Path{
+		Src: &PathEnd{ChainID: "hub", ClientID: "07-tendermint-0"},
+		Dst: &PathEnd{ChainID: "rollapp", ClientID: "07-tendermint-0"},
+	}}}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cfg := &ReleaseCheckConfig{URL: srv.URL, Headers: map[string]string{"Authorization": "Token secret"}}
+	c := newReleaseChecker(zap.NewNop(), cfg, "2.4.1", s.runners, s.names)
+	c.check(ctx)
+	status := c.snapshot()
+	require.True(t, status.UpdateAvailable)
+	require.Equal(t, "v2.5.0", status.Latest.Version)
+	require.Empty(t, status.Error)
+	// Only the upgrades of relayed chains newer than the running version are incompatible.
+	require.Equal(t, []IncompatibleChainUpgrade{{
+		IncompatibleUpgrade: manifest.IncompatibleUpgrades[0],
+		LatestHeight:        90,
+		Paths:               []string{"hub-rollapp"},
+	}}, status.Incompatible)
+
+	a := newAlerter(zap.NewNop(), nil, &AlertsConfig{})
+	al := a.incompatibleUpgrade(status.Incompatible[0], c.version)
+	require.Equal(t, "incompatible_upgrade/hub/100", al.Key)
+	require.Equal(t, "warning", al.Severity)
+	require.Equal(t, "Chain hub upgrades to v3 at height 100, requiring relayer version v2.5.0 or newer, running 2.4.1", al.Summary)
+	status.Incompatible[0].LatestHeight = 100
+	require.Equal(t, "critical", a.incompatibleUpgrade(status.Incompatible[0], c.version).Severity)
+
+	// The last status is kept when the manifest can't be fetched.
+	cfg.Headers = nil
+	c.check(ctx)
+	status = c.snapshot()
+	require.Equal(t, "release manifest responded with status 401 Unauthorized", status.Error)
+	require.Len(t, status.Incompatible, 1)
+
+	// Unreleased versions are not checked.
+	cfg.Headers = map[string]string{"Authorization": "Token secret"}
+	c = newReleaseChecker(zap.NewNop(), cfg, "", s.runners, s.names)
+	c.check(ctx)
+	status = c.snapshot()
+	require.False(t, status.UpdateAvailable)
+	require.Empty(t, status.Incompatible)
+	require.NotNil(t, status.CheckedAt)
+}
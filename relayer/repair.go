@@ -0,0 +1,312 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// ErrPathHealthy is returned when repairing a path whose clients can both still be updated.
+var ErrPathHealthy = errors.New("clients of the path are neither expired nor frozen")
+
+// RepairRecord maps the clients, connection and channels of a path beyond recovery to the ones created to replace them,
+// so that applications can migrate to the new channels.
+type RepairRecord struct {
+	Path       string      `json:"path"`
+	RepairedAt time.Time   `json:"repaired_at"`
+	Src        RepairedEnd `json:"src"`
+	Dst        RepairedEnd `json:"dst"`
+}
+
+// RepairedEnd maps the identifiers of one end of a repaired path.
+type RepairedEnd struct {
+	ChainID string `json:"chain_id"`
+
+	// Reason is why the old client could not be recovered, empty if only its counterparty could not be.
+	Reason string `json:"reason,omitempty"`
+
+	OldClientID     string            `json:"old_client_id"`
+	NewClientID     string            `json:"new_client_id"`
+	OldConnectionID string            `json:"old_connection_id"`
+	NewConnectionID string            `json:"new_connection_id"`
+	Channels        []RepairedChannel `json:"channels"`
+}
+
+// RepairedChannel maps an open channel of the old connection to the channel reopened on the new connection.
+type RepairedChannel struct {
+	PortID       string `json:"port_id"`
+	OldChannelID string `json:"old_channel_id"`
+	NewChannelID string `json:"new_channel_id"`
+}
+
+// ClientRepairReason returns why the client of c can no longer be updated, it being expired or frozen,
+// or an empty string if it can still be.
+func ClientRepairReason(ctx context.Context, c *Chain) (string, error) {
+	tmcs, latestTimestamp, err := latestTendermintClient(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	return clientRepairReason(tmcs, latestTimestamp, time.Now()), nil
+}
+
+// latestTendermintClient returns the state of the tendermint client of c and the timestamp of its latest consensus state.
+func latestTendermintClient(ctx context.Context, c *Chain) (*tmclient.ClientState, time.Time, error) {
+	return queryTendermintClient(ctx, c, c.ClientID())
+}
+
+// queryTendermintClient returns the state of the tendermint client hosted on c with clientID
+// and the timestamp of its latest consensus state.
+func queryTendermintClient(ctx context.Context, c *Chain, clientID string) (*tmclient.ClientState, time.Time, error) {
+	h, err := c.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	cs, err := c.ChainProvider.QueryClientState(ctx, h, clientID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	tmcs, ok := cs.(*tmclient.ClientState)
+	if !ok {
+		return nil, time.Time{}, fmt.Errorf("client %s is of type %s, only tendermint clients are supported", clientID, cs.ClientType())
+	}
+	consRes, err := c.ChainProvider.QueryClientConsensusState(ctx, h, clientID, tmcs.LatestHeight)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	consState, err := clienttypes.UnpackConsensusState(consRes.ConsensusState)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	tmConsState, ok := consState.(*tmclient.ConsensusState)
+	if !ok {
+		return nil, time.Time{}, fmt.Errorf("consensus state of client %s is not of tendermint type", clientID)
+	}
+	return tmcs, tmConsState.Timestamp, nil
+}
+
+func clientRepairReason(cs *tmclient.ClientState, latestTimestamp, now time.Time) string {
+	switch {
+	case !cs.FrozenHeight.IsZero():
+		return fmt.Sprintf("frozen at height %s", cs.FrozenHeight)
+	case cs.IsExpired(latestTimestamp, now):
+		return fmt.Sprintf("expired at %s", latestTimestamp.Add(cs.TrustingPeriod).UTC().Format(time.RFC3339))
+	}
+	return ""
+}
+
+// RepairPath replaces the clients of the path between c and dst once either is beyond recovery:
+// it creates a fresh pair of clients and a connection on top of them, and reopens every open channel
+// of the old connection on the new one, with the same ports, ordering and version.
+// The path ends of c and dst are updated with the new identifiers, and the mapping from the old ones is returned.
+// Unless force is set, ErrPathHealthy is returned if both clients can still be updated.
+func (c *Chain) RepairPath(
+	ctx context.Context,
+	dst *Chain,
+	pathName string,
+	maxRetries uint64,
+	timeout time.Duration,
+	force bool,
+	memo string,
+) (*RepairRecord, error) {
+	rec := &RepairRecord{
+		Path: pathName,
+		Src:  RepairedEnd{ChainID: c.ChainID(), OldClientID: c.ClientID(), OldConnectionID: c.ConnectionID()},
+		Dst:  RepairedEnd{ChainID: dst.ChainID(), OldClientID: dst.ClientID(), OldConnectionID: dst.ConnectionID()},
+	}
+
+	var err error
+	if rec.Src.Reason, err = ClientRepairReason(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to check client %s on chain %s: %w", c.ClientID(), c.ChainID(), err)
+	}
+	if rec.Dst.Reason, err = ClientRepairReason(ctx, dst); err != nil {
+		return nil, fmt.Errorf("failed to check client %s on chain %s: %w", dst.ClientID(), dst.ChainID(), err)
+	}
+	if rec.Src.Reason == "" && rec.Dst.Reason == "" && !force {
+		return nil, ErrPathHealthy
+	}
+
+	// Channels are looked up on the old connection before its identifiers are cleared.
+	oldChannels, err := queryChannelsOnConnection(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query channels of connection %s on chain %s: %w", c.ConnectionID(), c.ChainID(), err)
+	}
+
+	c.log.Warn(
+		"Repairing path",
+		zap.String("path", pathName),
+		zap.String("src_chain_id", c.ChainID()),
+		zap.String("src_client_id", c.ClientID()),
+		zap.String("src_reason", rec.Src.Reason),
+		zap.String("dst_chain_id", dst.ChainID()),
+		zap.String("dst_client_id", dst.ClientID()),
+		zap.String("dst_reason", rec.Dst.Reason),
+	)
+
+	for _, pe := range []*PathEnd{c.PathEnd, dst.PathEnd} {
+		pe.ClientID = ""
+		pe.ConnectionID = ""
+	}
+
+	if _, err := c.CreateClients(ctx, dst, false, false, true, memo); err != nil {
+		return rec, fmt.Errorf("error creating clients: %w", err)
+	}
+	rec.Src.NewClientID, rec.Dst.NewClientID = c.ClientID(), dst.ClientID()
+
+	if _, err := c.CreateOpenConnections(ctx, dst, maxRetries, timeout, memo); err != nil {
+		return rec, fmt.Errorf("error creating connection: %w", err)
+	}
+	rec.Src.NewConnectionID, rec.Dst.NewConnectionID = c.ConnectionID(), dst.ConnectionID()
+
+	reopened := make(map[string]bool)
+	for _, old := range oldChannels {
+		if old.State != chantypes.OPEN {
+			continue
+		}
+		if err := c.CreateOpenChannels(
+			ctx, dst, maxRetries, timeout,
+			old.PortId, old.Counterparty.PortId, StringFromOrder(old.Ordering), old.Version,
+			true, memo,
+		); err != nil {
+			return rec, fmt.Errorf("error reopening channel %s on port %s: %w", old.ChannelId, old.PortId, err)
+		}
+
+		newChannels, err := queryChannelsOnConnection(ctx, c)
+		if err != nil {
+			return rec, err
+		}
+		reopenedChannel := newRepairedChannel(newChannels, reopened, old.PortId)
+		if reopenedChannel == nil {
+			return rec, fmt.Errorf("reopened channel on port %s not found on connection %s", old.PortId, c.ConnectionID())
+		}
+		reopened[reopenedChannel.ChannelId] = true
+
+		rec.Src.Channels = append(rec.Src.Channels, RepairedChannel{
+			PortID:       old.PortId,
+			OldChannelID: old.ChannelId,
+			NewChannelID: reopenedChannel.ChannelId,
+		})
+		rec.Dst.Channels = append(rec.Dst.Channels, RepairedChannel{
+			PortID:       old.Counterparty.PortId,
+			OldChannelID: old.Counterparty.ChannelId,
+			NewChannelID: reopenedChannel.Counterparty.ChannelId,
+		})
+	}
+
+	rec.RepairedAt = time.Now().UTC()
+	return rec, nil
+}
+
+// newRepairedChannel returns the open channel on portID not yet claimed by a previously reopened channel.
+func newRepairedChannel(channels []*chantypes.IdentifiedChannel, reopened map[string]bool, portID string) *chantypes.IdentifiedChannel {
+	for _, ch := range channels {
+		if ch.State == chantypes.OPEN && ch.PortId == portID && !reopened[ch.ChannelId] {
+			return ch
+		}
+	}
+	return nil
+}
+
+// ApplyRepair points the path at the clients and connection of rec, and replaces the channels of the old connection
+// listed literally in its channel filter and monitor with their reopened counterparts. Patterns are left untouched.
+func (p *Path) ApplyRepair(rec *RepairRecord) {
+	p.Src.ClientID, p.Src.ConnectionID = rec.Src.NewClientID, rec.Src.NewConnectionID
+	p.Dst.ClientID, p.Dst.ConnectionID = rec.Dst.NewClientID, rec.Dst.NewConnectionID
+
+	remapChannelList(p.Filter.ChannelList, rec.Src.Channels)
+	if p.Monitor != nil {
+		remapChannelList(p.Monitor.ChannelList, rec.Src.Channels)
+	}
+}
+
+func remapChannelList(list []string, channels []RepairedChannel) {
+	for i, entry := range list {
+		portID, channelID := parseChannelFilterEntry(entry)
+		for _, ch := range channels {
+			if channelID != ch.OldChannelID || (portID != "" && portID != ch.PortID) {
+				continue
+			}
+			if portID == "" {
+				list[i] = ch.NewChannelID
+			} else {
+				list[i] = portID + ":" + ch.NewChannelID
+			}
+			break
+		}
+	}
+}
+
+// SaveRepairRecord appends rec, as a line of JSON, to the repair log at file.
+func SaveRepairRecord(file string, rec *RepairRecord) error {
+	bz, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(bz, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// NotifyRepair posts rec as JSON to each of the webhooks, so that applications can migrate to the new channels.
+// Every webhook is notified even if some fail, the errors being joined.
+func NotifyRepair(ctx context.Context, webhooks []string, rec *RepairRecord) error {
+	return postWebhooks(ctx, webhooks, rec)
+}
+
+// postWebhooks posts v as JSON to each of the webhooks, the errors being joined.
+func postWebhooks(ctx context.Context, webhooks []string, v any) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var errs error
+	for _, url := range webhooks {
+		if err := postWebhook(ctx, url, body); err != nil {
+			multierr.AppendInto(&errs, fmt.Errorf("failed to notify webhook %s: %w", url, err))
+		}
+	}
+	return errs
+}
+
+// postWebhook posts the JSON body to url.
+func postWebhook(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package relayer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRepairReason(t *testing.T) {
+	now := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	cs := &tmclient.ClientState{TrustingPeriod: 24 * time.Hour}
+
+	require.Empty(t, clientRepairReason(cs, now.Add(-time.Hour), now))
+	require.Equal(t, "expired at 2022-06-30T23:00:00Z", clientRepairReason(cs, now.Add(-25*time.Hour), now))
+
+	cs.FrozenHeight = clienttypes.NewHeight(1, 100)
+	require.Equal(t, "frozen at height 1-100", clientRepairReason(cs, now.Add(-time.Hour), now))
+}
+
+func TestNewRepairedChannel(t *testing.T) {
+	channels := []*chantypes.IdentifiedChannel{
+		{PortId: "transfer", ChannelId: "channel-7", State: chantypes.OPEN},
+		{PortId: "icahost", ChannelId: "channel-8", State: chantypes.OPEN},
+		{PortId: "transfer", ChannelId: "channel-9", State: chantypes.INIT},
+		{PortId: "transfer", ChannelId: "channel-10", State: chantypes.OPEN},
+	}
+	reopened := map[string]bool{"channel-7": true}
+
+	require.Equal(t, "channel-10", newRepairedChannel(channels, reopened, "transfer").ChannelId)
+	require.Equal(t, "channel-8", newRepairedChannel(channels, reopened, "icahost").ChannelId)
+	require.Nil(t, newRepairedChannel(channels, reopened, "wasm.juno1"))
+}
+
+func TestPathApplyRepair(t *testing.T) {
+	p := &Path{
+		Src:     &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0", ConnectionID: "connection-0"},
+		Dst:     &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-3", ConnectionID: "connection-2"},
+		Filter:  ChannelFilter{Rule: allowList, ChannelList: []string{"channel-0", "transfer:channel-1", "icahost:channel-1", "channel-*"}},
+		Monitor: &ChannelMonitor{ChannelList: []string{"channel-2"}},
+	}
+	p.ApplyRepair(&RepairRecord{
+		Src: RepairedEnd{
+			NewClientID:     "07-tendermint-5",
+			NewConnectionID: "connection-4",
+			Channels: []RepairedChannel{
+				{PortID: "transfer", OldChannelID: "channel-0", NewChannelID: "channel-10"},
+				{PortID: "transfer", OldChannelID: "channel-1", NewChannelID: "channel-11"},
+				{PortID: "transfer", OldChannelID: "channel-2", NewChannelID: "channel-12"},
+			},
+		},
+		Dst: RepairedEnd{NewClientID: "07-tendermint-9", NewConnectionID: "connection-6"},
+	})
+
+	require.Equal(t, &PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-5", ConnectionID: "connection-4"}, p.Src)
+	require.Equal(t, &PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-9", ConnectionID: "connection-6"}, p.Dst)
+	require.Equal(t, []string{"channel-10", "transfer:channel-11", "icahost:channel-1", "channel-*"}, p.Filter.ChannelList)
+	require.Equal(t, []string{"channel-12"}, p.Monitor.ChannelList)
+}
+
+func TestSaveAndNotifyRepair(t *testing.T) {
+	rec := &RepairRecord{
+		Path: "demo-path",
+		Src: RepairedEnd{
+			ChainID:     "chain-a",
+			Reason:      "expired at 2022-06-30T23:00:00Z",
+			OldClientID: "07-tendermint-0",
+			NewClientID: "07-tendermint-5",
+			Channels:    []RepairedChannel{{PortID: "transfer", OldChannelID: "channel-0", NewChannelID: "channel-10"}},
+		},
+	}
+
+	file := filepath.Join(t.TempDir(), "data", "repairs.jsonl")
+	require.NoError(t, SaveRepairRecord(file, rec))
+	require.NoError(t, SaveRepairRecord(file, rec))
+
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+	var lines int
+	for scanner := bufio.NewScanner(f); scanner.Scan(); lines++ {
+		var got RepairRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &got))
+		require.Equal(t, *rec, got)
+	}
+	require.Equal(t, 2, lines)
+
+	var notified RepairRecord
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&notified))
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	err = NotifyRepair(context.Background(), []string{failing.URL, ok.URL}, rec)
+	require.ErrorContains(t, err, failing.URL)
+	require.Equal(t, *rec, notified, "remaining webhooks are notified after a failure")
+}
@@ -0,0 +1,197 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/ackstore"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// RetentionPolicy bounds the entries kept in the state persisted by the relayer:
+// the acknowledgements of the ack store and the records of the repair log.
+// A zero MaxAge or MaxEntries does not limit them.
+type RetentionPolicy struct {
+	// MaxAge is how long entries are kept after they were added.
+	MaxAge time.Duration
+
+	// MaxEntries is the number of entries kept in each store, the oldest ones being deleted first.
+	MaxEntries int
+}
+
+func (p RetentionPolicy) enabled() bool {
+	return p.MaxAge > 0 || p.MaxEntries > 0
+}
+
+// StoreSize is the size of a store of state persisted by the relayer, as last measured.
+type StoreSize struct {
+	Store     string    `json:"store"`
+	Path      string    `json:"path"`
+	Entries   int       `json:"entries"`
+	SizeBytes int64     `json:"size_bytes"`
+	Pruned    uint64    `json:"pruned"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// storeJanitor periodically applies the retention policy to the persisted stores and measures them.
+type storeJanitor struct {
+	log       *zap.Logger
+	metrics   *processor.PrometheusMetrics
+	policy    RetentionPolicy
+	ackStore  *ackstore.Store
+	repairLog string
+
+	mu    sync.Mutex
+	sizes map[string]StoreSize
+}
+
+func newStoreJanitor(log *zap.Logger, metrics *processor.PrometheusMetrics, policy RetentionPolicy, ackStore *ackstore.Store, repairLog string) *storeJanitor {
+	return &storeJanitor{
+		log:       log,
+		metrics:   metrics,
+		policy:    policy,
+		ackStore:  ackStore,
+		repairLog: repairLog,
+		sizes:     make(map[string]StoreSize),
+	}
+}
+
+// start prunes and measures the stores at the given interval, until ctx is done.
+func (j *storeJanitor) start(ctx context.Context, sched *scheduler, interval time.Duration) {
+	sched.schedule(ctx, TaskStorePruning, interval, func(context.Context) {
+		j.check(time.Now().UTC())
+	})
+}
+
+func (j *storeJanitor) check(now time.Time) {
+	if j.ackStore != nil {
+		pruned, err := j.ackStore.Prune(j.policy.MaxAge, j.policy.MaxEntries, now)
+		if err != nil {
+			j.log.Warn("Failed to prune ack store", zap.String("path", j.ackStore.Dir()), zap.Error(err))
+		}
+		st, err := j.ackStore.Stats()
+		if err != nil {
+			j.log.Warn("Failed to measure ack store", zap.String("path", j.ackStore.Dir()), zap.Error(err))
+		} else {
+			j.record(processor.StoreAcks, j.ackStore.Dir(), st.Entries, st.SizeBytes, pruned, now)
+		}
+	}
+	if j.repairLog != "" {
+		entries, size, pruned, err := pruneRepairLog(j.repairLog, j.policy, now)
+		if err != nil {
+			j.log.Warn("Failed to prune repair log", zap.String("path", j.repairLog), zap.Error(err))
+		} else {
+			j.record(processor.StoreRepairs, j.repairLog, entries, size, pruned, now)
+		}
+	}
+}
+
+func (j *storeJanitor) record(store, path string, entries int, size int64, pruned int, now time.Time) {
+	j.metrics.SetStoreSize(store, entries, size)
+	j.metrics.AddStorePruned(store, pruned)
+	if pruned > 0 {
+		j.log.Info(
+			"Pruned store",
+			zap.String("store", store),
+			zap.String("path", path),
+			zap.Int("pruned", pruned),
+			zap.Int("entries", entries),
+		)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	total := j.sizes[store].Pruned + uint64(pruned)
+	j.sizes[store] = StoreSize{
+		Store:     store,
+		Path:      path,
+		Entries:   entries,
+		SizeBytes: size,
+		Pruned:    total,
+		CheckedAt: now,
+	}
+}
+
+// snapshot returns the last size of every store, by name.
+func (j *storeJanitor) snapshot() []StoreSize {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	sizes := make([]StoreSize, 0, len(j.sizes))
+	for _, s := range j.sizes {
+		sizes = append(sizes, s)
+	}
+	sort.Slice(sizes, func(i, k int) bool { return sizes[i].Store < sizes[k].Store })
+	return sizes
+}
+
+// pruneRepairLog deletes the records of the repair log at file that are older than the policy allows,
+// and returns the number of records kept, the size of the log and the number of records deleted.
+// The log is rewritten to a temporary file first, so it is never left truncated. A missing log is empty.
+func pruneRepairLog(file string, policy RetentionPolicy, now time.Time) (entries int, size int64, pruned int, err error) {
+	bz, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	// Records are appended as they are made, so the log is ordered oldest first.
+	var (
+		lines [][]byte
+		read  int
+	)
+	for _, line := range bytes.Split(bz, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		read++
+		if policy.MaxAge > 0 {
+			var rec RepairRecord
+			if err := json.Unmarshal(line, &rec); err == nil && now.Sub(rec.RepairedAt) > policy.MaxAge {
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	if policy.MaxEntries > 0 && len(lines) > policy.MaxEntries {
+		lines = lines[len(lines)-policy.MaxEntries:]
+	}
+
+	if !policy.enabled() || len(lines) == read {
+		return len(lines), int64(len(bz)), 0, nil
+	}
+
+	var out bytes.Buffer
+	for _, line := range lines {
+		out.Write(line)
+		out.WriteByte('\n')
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(out.Bytes()); err != nil {
+		tmp.Close()
+		return 0, 0, 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, 0, 0, err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return 0, 0, 0, err
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return 0, 0, 0, err
+	}
+	return len(lines), int64(out.Len()), read - len(lines), nil
+}
@@ -0,0 +1,100 @@
+package relayer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/ackstore"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPruneRepairLog(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "repairs.jsonl")
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	// A missing log is empty.
+	entries, size, pruned, err := pruneRepairLog(file, RetentionPolicy{MaxAge: time.Hour}, now)
+	require.NoError(t, err)
+	require.Zero(t, entries+pruned)
+	require.Zero(t, size)
+
+	for _, days := range []int{9, 5, 2, 1} {
+		require.NoError(t, SaveRepairRecord(file, &RepairRecord{Path: "demo-path", RepairedAt: now.AddDate(0, 0, -days)}))
+	}
+
+	// Without a policy the log is only measured.
+	entries, size, pruned, err = pruneRepairLog(file, RetentionPolicy{}, now)
+	require.NoError(t, err)
+	require.Equal(t, 4, entries)
+	require.Zero(t, pruned)
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	require.Equal(t, info.Size(), size)
+
+	entries, _, pruned, err = pruneRepairLog(file, RetentionPolicy{MaxAge: 72 * time.Hour}, now)
+	require.NoError(t, err)
+	require.Equal(t, 2, entries)
+	require.Equal(t, 2, pruned)
+
+	// The oldest records go first.
+	entries, size, pruned, err = pruneRepairLog(file, RetentionPolicy{MaxEntries: 1}, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, entries)
+	require.Equal(t, 1, pruned)
+
+	recs := readRepairLog(t, file)
+	require.Len(t, recs, 1)
+	require.Equal(t, now.AddDate(0, 0, -1), recs[0].RepairedAt)
+	info, err = os.Stat(file)
+	require.NoError(t, err)
+	require.Equal(t, info.Size(), size)
+
+	// Records can still be appended to the rewritten log.
+	require.NoError(t, SaveRepairRecord(file, &RepairRecord{Path: "demo-path", RepairedAt: now}))
+	require.Len(t, readRepairLog(t, file), 2)
+}
+
+func TestStoreJanitorCheck(t *testing.T) {
+	dir := t.TempDir()
+	st, err := ackstore.Open(filepath.Join(dir, "acks"))
+	require.NoError(t, err)
+	defer st.Close()
+	require.NoError(t, st.Add("chain-a", "transfer", "channel-0", []uint64{1, 2, 3}))
+
+	repairLog := filepath.Join(dir, "repairs.jsonl")
+	now := time.Now().UTC()
+	require.NoError(t, SaveRepairRecord(repairLog, &RepairRecord{Path: "demo-path", RepairedAt: now}))
+
+	j := newStoreJanitor(zap.NewNop(), nil, RetentionPolicy{MaxEntries: 2}, st, repairLog)
+	j.check(now)
+	j.check(now)
+
+	sizes := j.snapshot()
+	require.Len(t, sizes, 2)
+	require.Equal(t, processor.StoreAcks, sizes[0].Store)
+	require.Equal(t, 2, sizes[0].Entries)
+	require.Equal(t, uint64(1), sizes[0].Pruned)
+	require.Positive(t, sizes[0].SizeBytes)
+	require.Equal(t, processor.StoreRepairs, sizes[1].Store)
+	require.Equal(t, 1, sizes[1].Entries)
+	require.Zero(t, sizes[1].Pruned)
+}
+
+func readRepairLog(t *testing.T, file string) []RepairRecord {
+	t.Helper()
+	bz, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var recs []RepairRecord
+	for _, line := range bytes.Split(bytes.TrimSpace(bz), []byte("\n")) {
+		var rec RepairRecord
+		require.NoError(t, json.Unmarshal(line, &rec))
+		recs = append(recs, rec)
+	}
+	return recs
+}
@@ -0,0 +1,71 @@
+package relayer
+
+import (
+	"context"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+// defaultRetryDelay is the delay before the first retry, unless configured otherwise for the chain.
+const defaultRetryDelay = 400 * time.Millisecond
+
+type retryPolicyProvider interface {
+	RetryPolicy() *provider.RetryPolicy
+}
+
+// retryPolicy returns the retry policy configured for the chain, nil if it keeps the defaults.
+func (c *Chain) retryPolicy() *provider.RetryPolicy {
+	if rp, ok := c.ChainProvider.(retryPolicyProvider); ok {
+		return rp.RetryPolicy()
+	}
+	return nil
+}
+
+// retryAttempts returns the number of attempts made by the queries to the chain.
+func (c *Chain) retryAttempts() uint {
+	if p := c.retryPolicy(); p != nil && p.MaxAttempts > 0 {
+		return p.MaxAttempts
+	}
+	return RtyAttNum
+}
+
+// retryOptions returns the options retrying the queries to the chain according to its retry policy until ctx is done,
+// followed by extra options such as retry.OnRetry.
+func (c *Chain) retryOptions(ctx context.Context, extra ...retry.Option) []retry.Option {
+	opts := []retry.Option{retry.Context(ctx), retry.Attempts(c.retryAttempts()), RtyErr}
+
+	p := c.retryPolicy()
+	if p == nil {
+		return append(append(opts, RtyDel), extra...)
+	}
+
+	delay := defaultRetryDelay
+	if p.InitialDelay != "" {
+		delay, _ = time.ParseDuration(p.InitialDelay)
+	}
+	opts = append(opts, retry.Delay(delay))
+	if p.MaxDelay != "" {
+		d, _ := time.ParseDuration(p.MaxDelay)
+		opts = append(opts, retry.MaxDelay(d))
+	}
+
+	// Like the defaults of retry-go, delays get a random jitter of up to 100ms unless configured otherwise.
+	var jitter time.Duration = -1
+	if p.Jitter != "" {
+		jitter, _ = time.ParseDuration(p.Jitter)
+		opts = append(opts, retry.MaxJitter(jitter))
+	}
+	backoff := retry.BackOffDelay
+	if p.Backoff == provider.BackoffFixed {
+		backoff = retry.FixedDelay
+	}
+	if jitter == 0 {
+		opts = append(opts, retry.DelayType(backoff))
+	} else {
+		opts = append(opts, retry.DelayType(retry.CombineDelay(backoff, retry.RandomDelay)))
+	}
+
+	return append(opts, extra...)
+}
@@ -0,0 +1,61 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainRetryOptions(t *testing.T) {
+	chainWithPolicy := func(p *provider.RetryPolicy) *Chain {
+		return &Chain{ChainProvider: &cosmosprovider.CosmosProvider{PCfg: cosmosprovider.CosmosProviderConfig{Retry: p}}}
+	}
+
+	attempts := func(c *Chain) (n uint, elapsed time.Duration) {
+		start := time.Now()
+		err := retry.Do(func() error {
+			n++
+			return errors.New("rpc unavailable")
+		}, c.retryOptions(context.Background())...)
+		require.EqualError(t, err, "rpc unavailable", "only the last error is returned")
+		return n, time.Since(start)
+	}
+
+	require.Equal(t, RtyAttNum, chainWithPolicy(nil).retryAttempts())
+
+	fixed := chainWithPolicy(&provider.RetryPolicy{
+		MaxAttempts:  4,
+		InitialDelay: "20ms",
+		Jitter:       "0s",
+		Backoff:      provider.BackoffFixed,
+	})
+	n, elapsed := attempts(fixed)
+	require.Equal(t, uint(4), n)
+	require.GreaterOrEqual(t, elapsed, 60*time.Millisecond)
+	require.Less(t, elapsed, 130*time.Millisecond, "the delay does not grow")
+
+	capped := chainWithPolicy(&provider.RetryPolicy{
+		MaxAttempts:  5,
+		InitialDelay: "20ms",
+		MaxDelay:     "25ms",
+		Jitter:       "1ms",
+	})
+	n, elapsed = attempts(capped)
+	require.Equal(t, uint(5), n)
+	require.Less(t, elapsed, 250*time.Millisecond, "the backoff is capped at the max delay")
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	var unset *provider.RetryPolicy
+	require.NoError(t, unset.Validate())
+	require.NoError(t, (&provider.RetryPolicy{MaxAttempts: 10, InitialDelay: "1s", MaxDelay: "30s", Jitter: "0s"}).Validate())
+	require.Error(t, (&provider.RetryPolicy{InitialDelay: "soon"}).Validate())
+	require.Error(t, (&provider.RetryPolicy{MaxDelay: "-1s"}).Validate())
+	require.Error(t, (&provider.RetryPolicy{Backoff: "linear"}).Validate())
+}
@@ -0,0 +1,327 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"go.uber.org/zap"
+)
+
+// Kinds of the maintenance tasks run by the scheduler of `rly start`. Tasks run once for each of several items,
+// such as the health probes, are named after their kind and item, e.g. health-probe/sequencer.
+const (
+	TaskClientKeepalive = "client-keepalive"
+	TaskBalanceCheck    = "balance-check"
+	TaskConsensusStates = "consensus-states"
+	TaskChannelMonitor  = "channel-monitor"
+	TaskClientChainIDs  = "client-chain-ids"
+	TaskPriceOracle     = "price-oracle"
+	TaskPendingTimeouts = "pending-timeouts"
+	TaskOrderedChannels = "ordered-channels"
+	TaskEscrowAudit     = "escrow-audit"
+	TaskHealthProbe     = "health-probe"
+	TaskStorePruning    = "store-pruning"
+	TaskUpgradeWatch    = "upgrade-watch"
+	TaskWalletMonitor   = "wallet-monitor"
+	TaskAlerts          = "alerts"
+	TaskReleaseCheck    = "release-check"
+	TaskStuckPackets    = "stuck-packets"
+)
+
+var maintenanceTaskKinds = []string{
+	TaskClientKeepalive, TaskBalanceCheck, TaskConsensusStates, TaskChannelMonitor, TaskClientChainIDs,
+	TaskPriceOracle, TaskPendingTimeouts, TaskOrderedChannels, TaskEscrowAudit, TaskHealthProbe, TaskStorePruning,
+	TaskUpgradeWatch, TaskWalletMonitor, TaskAlerts, TaskReleaseCheck, TaskStuckPackets,
+}
+
+const (
+	// defaultMaintenanceJitter is the fraction of their interval the runs of maintenance tasks are shifted by if unset.
+	defaultMaintenanceJitter = 0.1
+
+	// maxMaintenanceJitter bounds the jitter, so that tasks still run about as often as configured.
+	maxMaintenanceJitter = 0.5
+)
+
+// MaintenanceConfig configures the scheduler running the periodic maintenance tasks of `rly start`.
+type MaintenanceConfig struct {
+	// Jitter is the fraction of its interval each run of a task is randomly shifted by, spreading the queries
+	// of the tasks over time, 0.1 if unset. The first run of a task is delayed by up to that fraction of its interval.
+	Jitter float64 `yaml:"jitter,omitempty" json:"jitter,omitempty"`
+
+	// Tasks configures the tasks by kind, e.g. escrow-audit, or by name, e.g. health-probe/sequencer,
+	// the config of a name taking precedence over the config of its kind.
+	Tasks map[string]MaintenanceTaskConfig `yaml:"tasks,omitempty" json:"tasks,omitempty"`
+}
+
+// MaintenanceTaskConfig configures a maintenance task.
+type MaintenanceTaskConfig struct {
+	// Disabled tasks are scheduled but not run, until enabled through the admin API.
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+
+	// Interval overrides the interval of the task set by the flags of `rly start` or its own config.
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+}
+
+// Validate checks the maintenance config.
+func (c *MaintenanceConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Jitter < 0 || c.Jitter > maxMaintenanceJitter {
+		return fmt.Errorf("maintenance jitter must be between 0 and %v, got %v", maxMaintenanceJitter, c.Jitter)
+	}
+	for name, t := range c.Tasks {
+		if !isMaintenanceTaskKind(taskKind(name)) {
+			return fmt.Errorf("unknown maintenance task %s, must be one of %s or one of their tasks",
+				name, strings.Join(maintenanceTaskKinds, ", "))
+		}
+		if t.Interval < 0 {
+			return fmt.Errorf("maintenance task %s: interval must not be negative", name)
+		}
+	}
+	return nil
+}
+
+func isMaintenanceTaskKind(kind string) bool {
+	for _, k := range maintenanceTaskKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// taskKind returns the kind of the task named name.
+func taskKind(name string) string {
+	kind, _, _ := strings.Cut(name, "/")
+	return kind
+}
+
+// scheduler runs the periodic maintenance tasks of the relayer, each in its own goroutine,
+// and reports when they last ran.
+type scheduler struct {
+	log    *zap.Logger
+	jitter float64
+	config map[string]MaintenanceTaskConfig
+
+	// rnd returns a random number in [0, 1).
+	rnd func() float64
+
+	mu    sync.Mutex
+	tasks map[string]*scheduledTask
+}
+
+type scheduledTask struct {
+	name     string
+	interval time.Duration
+	fn       func(ctx context.Context)
+
+	// the fields below are guarded by the mutex of the scheduler
+	enabled      bool
+	running      bool
+	runs         uint64
+	lastRun      time.Time
+	lastDuration time.Duration
+	nextRun      time.Time
+}
+
+func newScheduler(log *zap.Logger, cfg *MaintenanceConfig) *scheduler {
+	s := &scheduler{
+		log:    log,
+		jitter: defaultMaintenanceJitter,
+		tasks:  make(map[string]*scheduledTask),
+	}
+	if cfg != nil {
+		if cfg.Jitter > 0 {
+			s.jitter = cfg.Jitter
+		}
+		s.config = cfg.Tasks
+	}
+	src := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var mu sync.Mutex
+	s.rnd = func() float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return src.Float64()
+	}
+	return s
+}
+
+// schedule runs fn every interval, unless overridden by the config of the task, until ctx is done.
+// Runs of a task never overlap: the next run is scheduled once the previous one returns.
+func (s *scheduler) schedule(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context)) {
+	cfg, ok := s.config[name]
+	if !ok {
+		cfg = s.config[taskKind(name)]
+	}
+	if cfg.Interval > 0 {
+		interval = cfg.Interval
+	}
+	t := &scheduledTask{
+		name:     name,
+		interval: interval,
+		fn:       fn,
+		enabled:  !cfg.Disabled,
+	}
+
+	s.mu.Lock()
+	s.tasks[name] = t
+	s.mu.Unlock()
+
+	s.log.Debug(
+		"Scheduled maintenance task",
+		zap.String("task", name),
+		zap.Duration("interval", interval),
+		zap.Bool("enabled", t.enabled),
+	)
+	go s.run(ctx, t)
+}
+
+func (s *scheduler) run(ctx context.Context, t *scheduledTask) {
+	timer := time.NewTimer(s.wait(t, s.firstDelay(t.interval)))
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+		if s.begin(t) {
+			start := time.Now()
+			t.fn(ctx)
+			s.end(t, start)
+		}
+		timer.Reset(s.wait(t, s.delay(t.interval)))
+	}
+}
+
+// firstDelay returns how long to wait before the first run of a task, up to the jitter fraction of its interval.
+func (s *scheduler) firstDelay(interval time.Duration) time.Duration {
+	return time.Duration(s.jitter * s.rnd() * float64(interval))
+}
+
+// delay returns how long to wait between two runs of a task, its interval shifted by up to the jitter fraction of it.
+func (s *scheduler) delay(interval time.Duration) time.Duration {
+	return time.Duration(float64(interval) * (1 + s.jitter*(2*s.rnd()-1)))
+}
+
+// wait records when t next runs, in d.
+func (s *scheduler) wait(t *scheduledTask, d time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.nextRun = time.Now().Add(d).UTC()
+	return d
+}
+
+// begin marks t running, returning false if it is disabled.
+func (s *scheduler) begin(t *scheduledTask) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !t.enabled {
+		return false
+	}
+	t.running = true
+	return true
+}
+
+func (s *scheduler) end(t *scheduledTask, start time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.running = false
+	t.runs++
+	t.lastRun = start.UTC()
+	t.lastDuration = time.Since(start)
+}
+
+// setEnabled enables or disables the tasks named name, or of kind name, returning the tasks changed.
+func (s *scheduler) setEnabled(name string, enabled bool) ([]admin.MaintenanceTask, error) {
+	s.mu.Lock()
+	var changed []*scheduledTask
+	for _, t := range s.tasks {
+		if t.name == name || taskKind(t.name) == name {
+			t.enabled = enabled
+			changed = append(changed, t)
+		}
+	}
+	s.mu.Unlock()
+	if len(changed) == 0 {
+		return nil, fmt.Errorf("maintenance task %s not found", name)
+	}
+	return s.report(changed), nil
+}
+
+// snapshot returns the state of every task, by name.
+func (s *scheduler) snapshot() []admin.MaintenanceTask {
+	s.mu.Lock()
+	tasks := make([]*scheduledTask, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		tasks = append(tasks, t)
+	}
+	s.mu.Unlock()
+	return s.report(tasks)
+}
+
+func (s *scheduler) report(tasks []*scheduledTask) []admin.MaintenanceTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]admin.MaintenanceTask, 0, len(tasks))
+	for _, t := range tasks {
+		mt := admin.MaintenanceTask{
+			Name:     t.name,
+			Enabled:  t.enabled,
+			Interval: t.interval.String(),
+			Running:  t.running,
+			Runs:     t.runs,
+		}
+		if !t.lastRun.IsZero() {
+			lastRun := t.lastRun
+			mt.LastRun = &lastRun
+			mt.LastDuration = t.lastDuration.String()
+		}
+		if t.enabled && !t.nextRun.IsZero() {
+			nextRun := t.nextRun
+			mt.NextRun = &nextRun
+		}
+		res = append(res, mt)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// registerMaintenanceHandlers serves the maintenance tasks of s.
+func registerMaintenanceHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/maintenance", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			admin.WriteJSON(w, http.StatusOK, s.maintenance.snapshot())
+		case http.MethodPost:
+			var body admin.MaintenanceTask
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			tasks, err := s.maintenance.setEnabled(body.Name, body.Enabled)
+			if err != nil {
+				admin.WriteError(w, http.StatusNotFound, err)
+				return
+			}
+			s.log.Info(
+				"Set maintenance task",
+				zap.String("task", body.Name),
+				zap.Bool("enabled", body.Enabled),
+				zap.Int("tasks", len(tasks)),
+			)
+			admin.WriteJSON(w, http.StatusOK, tasks)
+		default:
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	})
+}
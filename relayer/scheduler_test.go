@@ -0,0 +1,84 @@
+package relayer
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMaintenanceConfigValidate(t *testing.T) {
+	var unset *MaintenanceConfig
+	require.NoError(t, unset.Validate())
+	require.NoError(t, (&MaintenanceConfig{Jitter: 0.2, Tasks: map[string]MaintenanceTaskConfig{
+		TaskEscrowAudit:                {Disabled: true},
+		TaskHealthProbe + "/sequencer": {Interval: time.Minute},
+	}}).Validate())
+
+	require.ErrorContains(t, (&MaintenanceConfig{Jitter: 0.8}).Validate(), "jitter must be between 0 and 0.5")
+	require.ErrorContains(t, (&MaintenanceConfig{Tasks: map[string]MaintenanceTaskConfig{"vacuum": {}}}).Validate(), "unknown maintenance task vacuum")
+	require.ErrorContains(t, (&MaintenanceConfig{Tasks: map[string]MaintenanceTaskConfig{TaskStorePruning: {Interval: -time.Second}}}).Validate(), "must not be negative")
+}
+
+func TestSchedulerJitter(t *testing.T) {
+	s := newScheduler(zap.NewNop(), &MaintenanceConfig{Jitter: 0.2})
+	s.rnd = func() float64 { return 0 }
+	require.Equal(t, time.Duration(0), s.firstDelay(time.Minute))
+	require.Equal(t, 48*time.Second, s.delay(time.Minute))
+
+	s.rnd = func() float64 { return 0.5 }
+	require.Equal(t, 6*time.Second, s.firstDelay(time.Minute))
+	require.Equal(t, time.Minute, s.delay(time.Minute))
+
+	// Jitter defaults to a tenth of the interval.
+	require.Equal(t, defaultMaintenanceJitter, newScheduler(zap.NewNop(), nil).jitter)
+}
+
+func TestSchedulerRunsTasks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := newScheduler(zap.NewNop(), &MaintenanceConfig{Tasks: map[string]MaintenanceTaskConfig{
+		TaskHealthProbe:                {Disabled: true},
+		TaskHealthProbe + "/sequencer": {Interval: 5 * time.Millisecond},
+	}})
+	s.rnd = func() float64 { return 0.5 }
+
+	var escrows, sequencer, explorer int64
+	s.schedule(ctx, TaskEscrowAudit, 5*time.Millisecond, func(context.Context) { atomic.AddInt64(&escrows, 1) })
+	s.schedule(ctx, TaskHealthProbe+"/sequencer", time.Hour, func(context.Context) { atomic.AddInt64(&sequencer, 1) })
+	s.schedule(ctx, TaskHealthProbe+"/explorer", 5*time.Millisecond, func(context.Context) { atomic.AddInt64(&explorer, 1) })
+
+	require.Eventually(t, func() bool { return atomic.LoadInt64(&escrows) >= 2 && atomic.LoadInt64(&sequencer) >= 2 }, time.Second, time.Millisecond)
+	require.Zero(t, atomic.LoadInt64(&explorer), "tasks of a disabled kind are not run")
+
+	tasks := s.snapshot()
+	require.Len(t, tasks, 3)
+	require.Equal(t, TaskEscrowAudit, tasks[0].Name)
+	require.True(t, tasks[0].Enabled)
+	require.Equal(t, "5ms", tasks[0].Interval)
+	require.NotNil(t, tasks[0].LastRun)
+	require.NotZero(t, tasks[0].Runs)
+	require.Equal(t, TaskHealthProbe+"/explorer", tasks[1].Name)
+	require.False(t, tasks[1].Enabled)
+	require.Nil(t, tasks[1].LastRun)
+	require.Nil(t, tasks[1].NextRun)
+	require.Equal(t, "5ms", tasks[2].Interval, "the config of a name takes precedence")
+
+	// Tasks are enabled and disabled by name or kind.
+	changed, err := s.setEnabled(TaskHealthProbe, true)
+	require.NoError(t, err)
+	require.Len(t, changed, 2)
+	require.Eventually(t, func() bool { return atomic.LoadInt64(&explorer) > 0 }, time.Second, time.Millisecond)
+
+	changed, err = s.setEnabled(TaskEscrowAudit, false)
+	require.NoError(t, err)
+	require.Len(t, changed, 1)
+	require.False(t, changed[0].Enabled)
+
+	_, err = s.setEnabled(TaskStorePruning, true)
+	require.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,84 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"go.uber.org/zap"
+)
+
+type keyUser interface {
+	UseKey(ctx context.Context, name string) error
+}
+
+// chainKeys returns the key signing the transactions sent to each chain of the paths.
+func (s *supervisor) chainKeys() []admin.ChainKey {
+	var keys []admin.ChainKey
+	for _, c := range s.chains() {
+		// The address is left empty if the key is missing from the keyring.
+		addr, _ := c.ChainProvider.Address()
+		keys = append(keys, admin.ChainKey{ChainID: c.ChainID(), Key: c.ChainProvider.Key(), Address: addr})
+	}
+	return keys
+}
+
+// registerKeyHandlers exposes the signing key of each chain through the admin API,
+// so that keys can be rotated without restarting the relayer.
+//
+//	GET  /key lists the key signing the transactions sent to each chain.
+//	POST /key signs the transactions sent to a chain with another key of its keyring.
+func registerKeyHandlers(srv *admin.Server, s *supervisor) {
+	srv.HandleFunc("/key", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			admin.WriteJSON(w, http.StatusOK, s.chainKeys())
+		case http.MethodPost:
+			var body admin.UseKeyRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			var chain *Chain
+			for _, c := range s.chains() {
+				if c.ChainID() == body.ChainID {
+					chain = c
+				}
+			}
+			if chain == nil {
+				admin.WriteError(w, http.StatusNotFound, fmt.Errorf("chain %s not found", body.ChainID))
+				return
+			}
+			ku, ok := chain.ChainProvider.(keyUser)
+			if !ok {
+				admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("chain %s does not support switching keys", body.ChainID))
+				return
+			}
+			if !chain.ChainProvider.KeyExists(body.Key) {
+				admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("key %s not found on chain %s", body.Key, body.ChainID))
+				return
+			}
+
+			from := chain.ChainProvider.Key()
+			if err := ku.UseKey(req.Context(), body.Key); err != nil {
+				admin.WriteError(w, http.StatusInternalServerError, err)
+				return
+			}
+			addr, _ := chain.ChainProvider.Address()
+			s.log.Info(
+				"Switched signing key",
+				zap.String("chain_id", body.ChainID),
+				zap.String("from", from),
+				zap.String("to", body.Key),
+				zap.String("address", addr),
+			)
+			s.changes.record(feed.Change{Kind: feed.ChangeSigningKey, Cause: feed.CauseAdmin}, s.pathsOfChain(body.ChainID)...)
+			admin.WriteJSON(w, http.StatusOK, admin.ChainKey{ChainID: body.ChainID, Key: body.Key, Address: addr})
+		default:
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		}
+	})
+}
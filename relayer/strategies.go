@@ -10,102 +10,561 @@ import (
 
 	"github.com/avast/retry-go/v4"
 	"github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/ackstore"
+	"github.com/cosmos/relayer/v2/relayer/admin"
 	cosmosprocessor "github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	evmprocessor "github.com/cosmos/relayer/v2/relayer/chains/evm"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/oracle"
 	"github.com/cosmos/relayer/v2/relayer/processor"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	evmprovider "github.com/cosmos/relayer/v2/relayer/provider/evm"
+	"github.com/cosmos/relayer/v2/relayer/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // ActiveChannel represents an IBC channel and whether there is an active goroutine relaying packets against it.
 type ActiveChannel struct {
 	channel *types.IdentifiedChannel
 	active  bool
+
+	// queued is set while the channel waits for its turn in the channelScheduler.
+	queued bool
+
+	// yielded is set when the goroutine relaying the channel handed its turn over to other channels,
+	// and resuming until the next goroutine carries on relaying it.
+	yielded, resuming bool
+
+	// lastTimeoutScan is when the channel was last scanned for timed out packets.
+	lastTimeoutScan time.Time
+
+	// dormant is set while the channel is not relayed for lack of traffic, see dormantChannels.
+	dormant bool
+
+	// lastActivity is when the channel last had packets or acknowledgements to relay, in unix nanoseconds.
+	lastActivity int64
 }
 
 const (
-	ProcessorEvents   string = "events"
-	ProcessorLegacy          = "legacy"
-	AckChunkSize             = 1000
-	AckGapForFullScan        = 20
+	ProcessorEvents string = "events"
+	ProcessorLegacy        = "legacy"
+	AckChunkSize           = 1000
 )
 
-// StartRelayer starts the main relaying loop and returns a channel that will contain any control-flow related errors.
+// StartRelayer starts the main relaying loop for the given paths and returns a channel that will contain any control-flow related errors.
+// The chains map is keyed by chain ID and must contain the chains of all paths.
+// Paths relayed with the events processor share a single event processor, with one chain processor per chain.
 func StartRelayer(
 	ctx context.Context,
 	log *zap.Logger,
-	src, dst *Chain,
-	filter ChannelFilter,
+	chains map[string]*Chain,
+	paths []NamedPath,
 	maxTxSize, maxMsgLength uint64,
 	memo string,
 	processorType string,
 	initialBlockHistory uint64,
+	opts ...StartOption,
 ) chan error {
+	if err := validateProcessorType(processorType); err != nil {
+		panic(err)
+	}
+
+	o := newStartOptions(opts)
+	if o.readOnly {
+		processorType = ProcessorEvents
+	}
+
+	// The startup of the relayer is traced on its own, the spans of relaying are the roots of their own traces.
+	startCtx, span := tracing.Start(ctx, "StartRelayer",
+		attribute.Int("chains", len(chains)),
+		attribute.Int("paths", len(paths)),
+		attribute.String("processor", processorType),
+	)
+	defer span.End()
+
 	errorChan := make(chan error, 1)
+	if o.readOnly && o.oneShot {
+		errorChan <- fmt.Errorf("cannot relay once in read-only mode")
+		close(errorChan)
+		return errorChan
+	}
 
-	switch processorType {
-	case ProcessorEvents:
-		var filterSrc, filterDst []processor.ChannelKey
-
-		for _, ch := range filter.ChannelList {
-			ruleSrc := processor.ChannelKey{ChannelID: ch}
-			ruleDst := processor.ChannelKey{CounterpartyChannelID: ch}
-			filterSrc = append(filterSrc, ruleSrc)
-			filterDst = append(filterDst, ruleDst)
-		}
-		paths := []path{{
-			src: pathChain{
-				provider: src.ChainProvider,
-				pathEnd:  processor.NewPathEnd(src.ChainProvider.ChainId(), src.ClientID(), filter.Rule, filterSrc),
-			},
-			dst: pathChain{
-				provider: dst.ChainProvider,
-				pathEnd:  processor.NewPathEnd(dst.ChainProvider.ChainId(), dst.ClientID(), filter.Rule, filterDst),
-			},
-		}}
-
-		go relayerStartEventProcessor(ctx, log, paths, initialBlockHistory, maxTxSize, maxMsgLength, memo, errorChan)
+	// The last warnings and errors are kept for the admin API.
+	var errs *recentErrors
+	if o.adminListener != nil {
+		errs = newRecentErrors()
+		log = log.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core { return zapcore.NewTee(c, errs) }))
+	}
+
+	s, err := newSupervisor(log, chains, paths, maxTxSize, maxMsgLength, memo, processorType, initialBlockHistory)
+	if err != nil {
+		errorChan <- err
+		close(errorChan)
 		return errorChan
-	case ProcessorLegacy:
-		go relayerMainLoop(ctx, log, src, dst, filter, maxTxSize, maxMsgLength, memo, errorChan)
+	}
+	s.recentErrors = errs
+
+	if err := s.setupPathLogs(paths, o.logFormat); err != nil {
+		errorChan <- err
+		close(errorChan)
 		return errorChan
-	default:
-		panic(fmt.Errorf("unexpected processor type: %s, supports one of: [%s, %s]", processorType, ProcessorEvents, ProcessorLegacy))
 	}
+
+	s.readOnly = o.readOnly
+	s.features = newFeatureFlags(o.featureFlags)
+	s.ackStore = o.ackStore
+	s.channelDiscoveryInterval = o.channelDiscoveryInterval
+	s.timeoutScanInterval = o.timeoutScanInterval
+	s.maxConcurrentChannels = o.maxConcurrentChannels
+	s.batchWindow = o.batchWindow
+	for _, r := range s.runners {
+		r.dormant = newDormantChannels(o.dormantAfter)
+	}
+	s.backfill = o.backfill
+	if s.backfill.Progress == nil {
+		s.backfill.Progress = processor.NewSyncProgress()
+	}
+	s.finalityGating = o.finalityGating
+	s.relayDirection = o.relayDirection
+	if s.relayDirection != RelayBothDirections {
+		s.logRelayDirection()
+	}
+	if s.finalityGating {
+		for _, c := range s.chains() {
+			if isRollapp(c.ChainProvider) && settlementProvider(c.ChainProvider) == nil {
+				errorChan <- fmt.Errorf("no settlement layer is configured for rollapp %s, cannot gate packets on finality", c.ChainID())
+				close(errorChan)
+				return errorChan
+			}
+		}
+	}
+	if err := verifyChainIDs(startCtx, log, s.chains()); err != nil {
+		tracing.End(span, err)
+		errorChan <- err
+		close(errorChan)
+		return errorChan
+	}
+	s.applyCapabilities(detectCapabilities(startCtx, log, s.chains()))
+	if o.clientRefreshThreshold < 0 || o.clientRefreshThreshold >= 1 {
+		errorChan <- fmt.Errorf("client refresh threshold must be between 0 and 1, got %v", o.clientRefreshThreshold)
+		close(errorChan)
+		return errorChan
+	}
+	if err := s.validatePriceOracle(o.priceOracle); err != nil {
+		errorChan <- err
+		close(errorChan)
+		return errorChan
+	}
+	if !s.readOnly {
+		s.registerCounterpartyPayees(startCtx, o.registerCounterpartyPayee)
+	}
+	if o.oneShot {
+		go s.runOnce(ctx, errorChan)
+		return errorChan
+	}
+	s.maintenance = newScheduler(log.With(zap.String("sys", "maintenance")), o.maintenance)
+	if o.clientRefreshThreshold > 0 && !s.readOnly {
+		s.startClientRefresh(ctx, o.clientRefreshThreshold)
+	}
+	if len(o.feedSinks) > 0 {
+		s.feed = feed.NewPublisher(log.With(zap.String("sys", "feed")), o.feedSinks...)
+		go s.feed.Run(ctx)
+	}
+	if len(o.changefeedSinks) > 0 {
+		changes := feed.NewPublisher(log.With(zap.String("sys", "changefeed")), o.changefeedSinks...)
+		go changes.Run(ctx)
+		s.startChangefeed(changes)
+	}
+
+	var providers []provider.ChainProvider
+	s.relayerActivity = processor.NewRelayerActivity()
+	for _, c := range s.chains() {
+		providers = append(providers, c.ChainProvider)
+		if addr, err := c.ChainProvider.Address(); err == nil {
+			s.relayerActivity.SetOwnAddress(c.ChainID(), addr)
+		}
+	}
+
+	blockTimes := processor.NewBlockTimeEstimator(log)
+	go blockTimes.Run(ctx, providers...)
+	s.startRPCHealthChecks(ctx)
+	s.startRemoteSignerHealthChecks(ctx)
+
+	if o.metricsListener != nil {
+		s.metrics = processor.NewPrometheusMetrics()
+		s.startMetricsMonitor(ctx)
+		serveMetrics(ctx, log.With(zap.String("sys", "metricshttp")), s.metrics, o.metricsListener)
+	}
+	if s.metrics == nil && o.alerts != nil && o.alerts.RelayErrors != nil {
+		// Relay errors are alerted on from the failed relays recorded in the metrics, even if they are not served.
+		s.metrics = processor.NewPrometheusMetrics()
+	}
+
+	if s.finalityGating {
+		s.preconfirmations = processor.NewPreconfirmationTracker(log.With(zap.String("sys", "preconfirmations")), s.metrics)
+		s.heldPackets = processor.NewHeldPacketTracker(log.With(zap.String("sys", "heldpackets")))
+	}
+	if o.stuckPackets != nil {
+		s.relayAttempts = processor.NewRelayAttempts()
+	}
+
+	var janitor *consensusStateJanitor
+	if o.consensusStateCheckInterval > 0 {
+		janitor = newConsensusStateJanitor(log.With(zap.String("sys", "consensusjanitor")), s.metrics, o.maxConsensusStates)
+		janitor.start(ctx, s.maintenance, o.consensusStateCheckInterval, s.clientEnds())
+	}
+
+	monitor := s.startChannelMonitor(ctx)
+
+	var clientChainIDs *clientChainIDMonitor
+	if o.clientChainIDCheckInterval > 0 {
+		clientChainIDs = s.startClientChainIDMonitor(ctx, o.clientChainIDCheckInterval)
+	}
+
+	var prices *oracle.Prices
+	if o.priceOracle != nil {
+		prices = s.startPriceOracle(ctx, o.priceOracle, o.priceOracleInterval)
+	}
+
+	var pendingTimeouts *pendingTimeoutMonitor
+	if o.timeoutReportInterval > 0 {
+		pendingTimeouts = s.startPendingTimeoutMonitor(ctx, o.timeoutReportInterval, o.timeoutReportBlocks, o.timeoutReportWindow)
+	}
+
+	var orderedChannels *orderedChannelMonitor
+	if o.orderedChannelCheckInterval > 0 {
+		orderedChannels = s.startOrderedChannelMonitor(ctx, o.orderedChannelCheckInterval, o.orderedChannelStallAfter)
+	}
+
+	var upgrades *upgradeWatcher
+	if o.upgradeCheckInterval > 0 {
+		upgrades = s.startUpgradeWatch(ctx, o.upgradeCheckInterval, o.upgradeHaltMargin)
+	}
+
+	var escrows *escrowMonitor
+	if o.escrowCheckInterval > 0 {
+		escrows = s.startEscrowMonitor(ctx, o.escrowCheckInterval)
+	}
+
+	var probes *healthProber
+	if o.healthProbes != nil && len(o.healthProbes.Probes) > 0 {
+		probes = s.startHealthProbes(ctx, o.healthProbes)
+	}
+
+	var wallets *walletMonitor
+	if o.walletMonitor != nil && len(o.walletMonitor.Wallets) > 0 {
+		wallets = s.startWalletMonitor(ctx, o.walletMonitor)
+	}
+
+	var releases *releaseChecker
+	if o.releaseCheck != nil {
+		releases = s.startReleaseCheck(ctx, o.releaseCheck, provider.RelayerVersion)
+	}
+
+	var alerts *alerter
+	if o.alerts != nil {
+		alerts = s.startAlerts(ctx, o.alerts, releases)
+	}
+
+	var stuck *stuckPacketDetector
+	if o.stuckPackets != nil {
+		stuck = s.startStuckPacketDetector(ctx, o.stuckPackets)
+	}
+
+	var stores *storeJanitor
+	if o.storeCompactionInterval > 0 && (o.ackStore != nil || o.repairLog != "") {
+		stores = newStoreJanitor(log.With(zap.String("sys", "storejanitor")), s.metrics, o.retention, o.ackStore, o.repairLog)
+		stores.start(ctx, s.maintenance, o.storeCompactionInterval)
+	}
+
+	if o.adminListener != nil {
+		srv := admin.NewServer(log.With(zap.String("sys", "adminhttp")), admin.WithSecret(o.adminSecret))
+		registerProcessorHandlers(srv, s)
+		registerChannelHandlers(srv, s)
+		registerPathPauseHandlers(srv, s)
+		registerLogHandlers(srv, s)
+		registerDashboardHandlers(srv, s)
+		registerDecisionHandlers(srv, s)
+		registerMaintenanceHandlers(srv, s)
+		registerIdentityHandlers(srv, s)
+		registerFeatureHandlers(srv, s)
+		if stuck != nil {
+			registerStuckPacketHandlers(srv, s, stuck)
+		}
+		if !s.readOnly {
+			registerFlushHandlers(ctx, srv, s)
+			registerKeyHandlers(srv, s)
+			registerPriorityHandlers(srv, s)
+			if provider.FaultInjection {
+				registerFaultHandlers(srv, s)
+			}
+		}
+		srv.RegisterStatus("block_times", func() any { return blockTimes.Estimates() })
+		srv.RegisterStatus("relayer_activity", func() any { return s.relayerActivity.Snapshot() })
+		srv.RegisterStatus("signing_queues", func() any { return s.signingQueues() })
+		srv.RegisterStatus("rpc_endpoints", func() any { return s.rpcEndpoints() })
+		srv.RegisterStatus("remote_signers", func() any { return s.remoteSigners() })
+		srv.RegisterStatus("tracked_txs", func() any { return s.trackedTxs() })
+		srv.RegisterStatus("recent_errors", func() any { return errs.snapshot() })
+		srv.RegisterStatus("sync_progress", func() any { return s.backfill.Progress.Snapshot() })
+		srv.RegisterStatus("capabilities", func() any { return s.capabilitySnapshot() })
+		srv.RegisterStatus("dormant_channels", func() any { return s.dormantChannelList() })
+		srv.RegisterStatus("maintenance", func() any { return s.maintenance.snapshot() })
+		if janitor != nil {
+			srv.RegisterStatus("consensus_states", func() any { return janitor.snapshot() })
+		}
+		if clientChainIDs != nil {
+			srv.RegisterStatus("client_chain_ids", func() any { return clientChainIDs.snapshot() })
+		}
+		if s.hasRateLimits() {
+			srv.RegisterStatus("rate_limits", func() any { return s.rateLimits() })
+		}
+		if s.hasDependencies() {
+			srv.RegisterStatus("path_startup", func() any { return s.startup() })
+		}
+		if monitor != nil {
+			srv.RegisterStatus("monitored_channels", func() any { return monitor.snapshot() })
+		}
+		if prices != nil {
+			srv.RegisterStatus("prices", func() any { return prices.Snapshot() })
+		}
+		if pendingTimeouts != nil {
+			srv.RegisterStatus("pending_timeouts", func() any { return pendingTimeouts.snapshot() })
+		}
+		if orderedChannels != nil {
+			srv.RegisterStatus("ordered_channels", func() any { return orderedChannels.snapshot() })
+		}
+		if upgrades != nil {
+			srv.RegisterStatus("upgrades", func() any { return upgrades.snapshot() })
+		}
+		if escrows != nil {
+			srv.RegisterStatus("escrow_balances", func() any { return escrows.snapshot() })
+		}
+		if s.preconfirmations != nil {
+			srv.RegisterStatus("preconfirmations", func() any { return s.preconfirmations.Snapshot() })
+		}
+		if s.heldPackets != nil {
+			srv.RegisterStatus("held_packets", func() any { return s.heldPackets.Snapshot() })
+		}
+		if stores != nil {
+			srv.RegisterStatus("stores", func() any { return stores.snapshot() })
+		}
+		if probes != nil {
+			srv.RegisterStatus("health_probes", func() any { return probes.snapshot() })
+		}
+		if wallets != nil {
+			srv.RegisterStatus("wallets", func() any { return wallets.snapshot() })
+		}
+		if alerts != nil {
+			srv.RegisterStatus("alerts", func() any { return alerts.snapshot() })
+		}
+		if releases != nil {
+			srv.RegisterStatus("release", func() any { return releases.snapshot() })
+		}
+		srv.Start(ctx, o.adminListener)
+	}
+
+	go s.run(ctx, errorChan)
+	return errorChan
 }
 
 // TODO: intermediate types. Should combine/replace with the relayer.Chain, relayer.Path, and relayer.PathEnd structs
 // as the stateless and stateful/event-based relaying mechanisms are consolidated.
 type path struct {
-	src pathChain
-	dst pathChain
+	name string
+	src  pathChain
+	dst  pathChain
+
+	// log is the logger of the path processor of the path.
+	log *zap.Logger
+
+	// pauses holds the channels of the path paused through the admin API, if any.
+	pauses *channelPauses
+
+	// trusted relays the packets sent on the rollapps of the path at preconfirmation, see Path.Trusted.
+	trusted bool
+
+	// gate enables or disables the experimental features of the path processor, if non-nil.
+	gate processor.FeatureGate
+
+	// packetFilter skips the transfers of the path not worth relaying, if non-nil.
+	packetFilter *processor.PacketFilter
+
+	// rateLimiter holds back the transfers of the path over its rate limits, if non-nil.
+	rateLimiter *processor.RateLimiter
+
+	// ackAggregator holds back the acknowledgements of the path to relay them together, if non-nil.
+	ackAggregator *processor.AckAggregator
+
+	// decisions captures the packet flow decisions of the path requested through the admin API.
+	decisions *processor.DecisionCapture
+
+	// priority bumps the fees of the transactions of the path while enabled.
+	priority *provider.Priority
 }
 
 type pathChain struct {
 	provider provider.ChainProvider
 	pathEnd  processor.PathEnd
+
+	// maxTxBytes is the size of the largest transaction the chain accepts, 0 if unknown.
+	maxTxBytes uint64
 }
 
 // chainProcessor returns the corresponding ChainProcessor implementation instance for a pathChain.
-func (chain pathChain) chainProcessor(log *zap.Logger) processor.ChainProcessor {
+func (chain pathChain) chainProcessor(log *zap.Logger, relayerActivity *processor.RelayerActivity, publisher *feed.Publisher, metrics *processor.PrometheusMetrics) processor.ChainProcessor {
 	// Handle new ChainProcessor implementations as cases here
 	switch p := chain.provider.(type) {
 	case *cosmosprovider.CosmosProvider:
-		return cosmosprocessor.NewCosmosChainProcessor(log, p)
+		return cosmosprocessor.NewCosmosChainProcessor(log, p, relayerActivity, publisher, metrics)
+	case *evmprovider.EVMProvider:
+		return evmprocessor.NewEVMChainProcessor(log, p, relayerActivity, publisher, metrics)
 	default:
 		panic(fmt.Errorf("unsupported chain provider type: %T", chain.provider))
 	}
 }
 
+// Settlement providers gate packets sent on rollapps on their finality.
+var _ processor.FinalityGater = cosmosprovider.SettlementProvider(nil)
+
+// isRollapp returns true if the chain is a rollapp, i.e. its blocks are finalized on a settlement layer.
+func isRollapp(cp provider.ChainProvider) bool {
+	p, ok := cp.(*cosmosprovider.CosmosProvider)
+	return ok && p.ClientType() == ibcexported.Furyint
+}
+
+// settlementProvider returns the settlement provider of a rollapp, or nil if none is configured.
+func settlementProvider(cp provider.ChainProvider) cosmosprovider.SettlementProvider {
+	p, ok := cp.(*cosmosprovider.CosmosProvider)
+	if !ok {
+		return nil
+	}
+	return p.SettlementProvider()
+}
+
+// disputePeriodRefresh is how long the dispute period of the states of a rollapp is cached,
+// as it is a parameter of its settlement layer which rarely changes.
+const disputePeriodRefresh = time.Hour
+
+// settlementStates resolves the states of a rollapp on its settlement layer for the path processor,
+// with the end of their dispute window where the settlement layer reports their dispute period.
+type settlementStates struct {
+	sp cosmosprovider.SettlementProvider
+
+	mu             sync.Mutex
+	disputePeriods map[string]uint64
+	queriedAt      map[string]time.Time
+}
+
+var _ processor.SettlementStateQuerier = (*settlementStates)(nil)
+
+func newSettlementStates(sp cosmosprovider.SettlementProvider) *settlementStates {
+	return &settlementStates{
+		sp:             sp,
+		disputePeriods: make(map[string]uint64),
+		queriedAt:      make(map[string]time.Time),
+	}
+}
+
+// QuerySettlementState implements processor.SettlementStateQuerier.
+func (s *settlementStates) QuerySettlementState(ctx context.Context, chainID string, height uint64) (processor.SettlementState, bool, error) {
+	si, err := s.sp.QueryStateInfo(ctx, chainID, height)
+	if errors.Is(err, cosmosprovider.ErrStateNotFound) {
+		return processor.SettlementState{}, false, nil
+	}
+	if err != nil {
+		return processor.SettlementState{}, false, err
+	}
+	st := processor.SettlementState{
+		Index:          si.Index,
+		StartHeight:    si.StartHeight,
+		EndHeight:      si.EndHeight(),
+		Status:         si.Status,
+		CreationHeight: si.CreationHeight,
+	}
+	if period, ok := s.disputePeriod(ctx, chainID); ok {
+		st.DisputeWindowEnd = si.CreationHeight + period
+	}
+	if hub, ok := s.sp.(interface {
+		QueryLatestHeight(ctx context.Context) (int64, error)
+	}); ok {
+		if h, err := hub.QueryLatestHeight(ctx); err == nil {
+			st.SettlementHeight = h
+		}
+	}
+	return st, true, nil
+}
+
+// disputePeriod returns the dispute period of the states of the rollapp, false if the settlement layer does not report it.
+func (s *settlementStates) disputePeriod(ctx context.Context, chainID string) (uint64, bool) {
+	dp, ok := s.sp.(cosmosprovider.DisputePeriodProvider)
+	if !ok {
+		return 0, false
+	}
+	s.mu.Lock()
+	period, queriedAt := s.disputePeriods[chainID], s.queriedAt[chainID]
+	s.mu.Unlock()
+	if time.Since(queriedAt) < disputePeriodRefresh {
+		return period, true
+	}
+	period, err := dp.QueryDisputePeriod(ctx, chainID)
+	if err != nil {
+		// the end of the dispute window is only reported, the state is queried again later
+		return 0, false
+	}
+	s.mu.Lock()
+	s.disputePeriods[chainID], s.queriedAt[chainID] = period, time.Now()
+	s.mu.Unlock()
+	return period, true
+}
+
+// chainIDVerifyTimeout bounds the time spent verifying the chain served by the endpoint of each chain at startup.
+const chainIDVerifyTimeout = 10 * time.Second
+
+// verifyChainIDs refuses to relay chains whose endpoint serves a different chain than configured.
+// Endpoints that can't be reached are only logged, they are verified again once relaying resumes after a failure.
+func verifyChainIDs(ctx context.Context, log *zap.Logger, chains []*Chain) error {
+	for _, c := range chains {
+		verifyCtx, cancel := context.WithTimeout(ctx, chainIDVerifyTimeout)
+		err := c.ChainProvider.VerifyChainID(verifyCtx)
+		cancel()
+		switch {
+		case errors.Is(err, provider.ErrChainIDMismatch):
+			return fmt.Errorf("refusing to relay %s: %w", c.ChainID(), err)
+		case err != nil:
+			log.Warn(
+				"Failed to verify the chain served by the endpoint",
+				zap.String("chain_id", c.ChainID()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
 // relayerStartEventProcessor is the main relayer process when using the event processor.
 func relayerStartEventProcessor(
 	ctx context.Context,
 	log *zap.Logger,
 	paths []path,
 	initialBlockHistory uint64,
+	backfill processor.Backfill,
 	maxTxSize,
 	maxMsgLength uint64,
 	memo string,
+	relayerActivity *processor.RelayerActivity,
+	publisher *feed.Publisher,
+	metrics *processor.PrometheusMetrics,
+	readOnly bool,
+	finalityGating bool,
+	direction RelayDirection,
+	preconfirmations *processor.PreconfirmationTracker,
+	heldPackets *processor.HeldPacketTracker,
+	relayAttempts *processor.RelayAttempts,
 	errCh chan<- error,
 ) {
 	defer close(errCh)
@@ -115,45 +574,95 @@ func relayerStartEventProcessor(
 	for _, p := range paths {
 		epb = epb.
 			WithChainProcessors(
-				p.src.chainProcessor(log),
-				p.dst.chainProcessor(log),
-			).
-			WithPathProcessors(processor.NewPathProcessor(
-				log,
-				p.src.pathEnd,
-				p.dst.pathEnd,
-				memo,
-			))
+				p.src.chainProcessor(log, relayerActivity, publisher, metrics),
+				p.dst.chainProcessor(log, relayerActivity, publisher, metrics),
+			)
+		pp := processor.NewPathProcessor(
+			p.log,
+			p.src.pathEnd,
+			p.dst.pathEnd,
+			memo,
+		)
+		pp.SetPathName(p.name)
+		pp.SetReadOnly(readOnly)
+		pp.SetFeed(publisher)
+		pp.SetMetrics(metrics)
+		pp.SetChannelPauser(p.pauses)
+		pp.SetPacketFilter(p.packetFilter)
+		pp.SetRateLimiter(p.rateLimiter)
+		pp.SetAckAggregator(p.ackAggregator)
+		pp.SetDecisionCapture(p.decisions)
+		pp.SetPriority(p.priority)
+		pp.SetFeatureGate(p.gate)
+		pp.SetRelayAttempts(relayAttempts)
+		for _, pc := range []pathChain{p.src, p.dst} {
+			if n := confirmations(pc.provider); n > 0 {
+				pp.SetConfirmations(pc.provider.ChainId(), n)
+			}
+			if !direction.relaysPacketsSentOn(pc.provider) {
+				pp.SkipPacketsSentOn(pc.provider.ChainId())
+			}
+			if pc.maxTxBytes > 0 {
+				pp.SetMaxTxBytes(pc.provider.ChainId(), pc.maxTxBytes)
+			}
+		}
+		if finalityGating {
+			for _, pc := range []pathChain{p.src, p.dst} {
+				if isRollapp(pc.provider) {
+					pp.SetFinalityGater(pc.provider.ChainId(), settlementProvider(pc.provider))
+					if heldPackets != nil {
+						pp.SetHeldPackets(pc.provider.ChainId(), newSettlementStates(settlementProvider(pc.provider)), heldPackets)
+					}
+					if p.trusted {
+						pp.SetPreconfirmation(pc.provider.ChainId(), preconfirmations)
+					}
+				}
+			}
+		}
+		epb = epb.WithPathProcessors(pp)
 	}
 
 	ep := epb.
 		WithInitialBlockHistory(initialBlockHistory).
+		WithBackfill(backfill).
 		Build()
 
 	errCh <- ep.Run(ctx)
 }
 
 // relayerMainLoop is the main loop of the relayer.
-func relayerMainLoop(ctx context.Context, log *zap.Logger, src, dst *Chain, filter ChannelFilter, maxTxSize, maxMsgLength uint64, memo string, errCh chan<- error) {
-	// Query the list of channels on the src connection.
-	srcChannels, err := queryChannelsOnConnection(ctx, src)
+// With finalityGating set, packets sent on a rollapp are only relayed once finalized on the settlement layer.
+// With a non-zero discoveryInterval, the channels of the connection are queried again at that interval,
+// and channels opened since are relayed as well; otherwise the loop exits once there are no open channels left.
+// With a non-zero timeoutScanInterval, each channel is scanned for timed out packets at that interval,
+// and their timeouts are relayed back to the chain they were sent from.
+// With a non-zero maxConcurrentChannels, at most that many channels are relayed at once, taking turns.
+// Channels paused in pauses are not relayed until they are resumed.
+// Channels idle for long enough are set dormant in dormant until the events of either chain show traffic on them.
+func relayerMainLoop(ctx context.Context, log *zap.Logger, src, dst *Chain, conns pathConnections, maxTxSize, maxMsgLength uint64, memo string, finalityGating bool, discoveryInterval, timeoutScanInterval time.Duration, maxConcurrentChannels int, pauses *channelPauses, dormant *dormantChannels, errCh chan<- error) {
+	// Query the list of channels on the src connections, applying the channel filter rule of each connection
+	// (i.e. build allowlist, denylist or relay on all channels available), then filter out only the channels in the OPEN state.
+	srcChannels, relayedConns, err := conns.channels(ctx)
 	if err != nil {
-		if errors.Is(err, context.Canceled) {
-			errCh <- err
-		} else {
-			errCh <- fmt.Errorf("error querying all channels on chain{%s}@connection{%s}: %w",
-				src.ChainID(), src.ConnectionID(), err)
-		}
+		errCh <- err
 		return
 	}
 
 	channels := make(chan *ActiveChannel, len(srcChannels))
-
-	// Apply the channel filter rule (i.e. build allowlist, denylist or relay on all channels available),
-	// then filter out only the channels in the OPEN state.
-	srcChannels = applyChannelFilterRule(filter, srcChannels)
 	srcOpenChannels := filterOpenChannels(srcChannels)
 
+	var discovery <-chan time.Time
+	if discoveryInterval > 0 {
+		ticker := time.NewTicker(discoveryInterval)
+		defer ticker.Stop()
+		discovery = ticker.C
+	}
+
+	scheduler := newChannelScheduler(maxConcurrentChannels)
+
+	dormantChecks, stopDormantChecks := dormant.checks()
+	defer stopDormantChecks()
+
 	var wg sync.WaitGroup
 	for {
 		// TODO once upstream changes are merged for emitting the channel version in ibc-go,
@@ -162,22 +671,24 @@ func relayerMainLoop(ctx context.Context, log *zap.Logger, src, dst *Chain, filt
 		// So we will occasionally query recent txs and check the events for `ChannelOpenInit`, at which point
 		// we will attempt to finish opening the channel.
 
-		// TODO when we have completed the Chain/Path processor refactor we will be listening for
-		// new channels that are being opened/created so it's possible we have no open channels to relay on
-		// at startup but after some time has passed a channel needs opened and relayed on. At this point we
-		// could choose to loop here until some action is needed.
-		if len(srcOpenChannels) == 0 {
+		// Without channel discovery, there is nothing left to do once there are no open channels.
+		if len(srcOpenChannels) == 0 && discovery == nil {
 			errCh <- fmt.Errorf("there are no open channels to relay on")
 			return
 		}
 
-		// Spin up a goroutine to relay packets & acks for each channel that isn't already being relayed against.
-		for _, channel := range srcOpenChannels {
-			if !channel.active {
-				channel.active = true
-				wg.Add(1)
-				go relayUnrelayedPacketsAndAcks(ctx, log, &wg, src, dst, maxTxSize, maxMsgLength, memo, channel, channels)
+		// Spin up a goroutine to relay packets & acks for each channel that isn't already being relayed against,
+		// as long as the maximum number of concurrently relayed channels is not reached.
+		// Paused channels are left out until they are resumed.
+		scheduler.enqueue(pauses.unpaused(srcOpenChannels))
+		for channel := scheduler.next(); channel != nil; channel = scheduler.next() {
+			if pauses.isPaused(channel.channel.PortId, channel.channel.ChannelId) {
+				// Paused while waiting for its turn.
+				scheduler.done(channel)
+				continue
 			}
+			wg.Add(1)
+			go relayUnrelayedPacketsAndAcks(ctx, log, &wg, src, dst, maxTxSize, maxMsgLength, memo, finalityGating, timeoutScanInterval, channel, channels, pauses.yield(channel, scheduler.shouldYield), dormant)
 		}
 
 		// Block here until one of the running goroutines exits, while accounting for the case where
@@ -186,13 +697,54 @@ func relayerMainLoop(ctx context.Context, log *zap.Logger, src, dst *Chain, filt
 		select {
 		case channel = <-channels:
 			break
+		case <-discovery:
+			if discovered := discoverChannels(ctx, src, conns, srcOpenChannels); discovered != nil {
+				relayedConns = discovered
+			}
+			continue
+		case <-pauses.resumedCh():
+			continue
+		case <-dormantChecks:
+			dormant.wakeChannels(ctx, log, src, dst, relayedConns, srcOpenChannels)
+			continue
 		case <-ctx.Done():
 			wg.Wait() // Wait here for the running goroutines to finish
 			errCh <- ctx.Err()
 			return
 		}
 
-		channel.active = false
+		// A channel set dormant stops until woken, nothing went wrong.
+		if channel.dormant {
+			scheduler.done(channel)
+			log.Info(
+				"Channel set dormant",
+				zap.String("src_chain_id", src.ChainID()),
+				zap.String("src_channel_id", channel.channel.ChannelId),
+				zap.String("src_port_id", channel.channel.PortId),
+				zap.Duration("idle", time.Since(channel.lastActivityTime())),
+			)
+			continue
+		}
+
+		// A paused channel stops after its current pass until resumed.
+		if channel.yielded && pauses.isPaused(channel.channel.PortId, channel.channel.ChannelId) {
+			channel.yielded = false
+			scheduler.done(channel)
+			continue
+		}
+
+		// A channel which handed its turn over to queued channels is queued again, nothing went wrong.
+		yielded := channel.yielded
+		scheduler.done(channel)
+		if yielded {
+			continue
+		}
+
+		// Goroutines exit on errors, which may come from an endpoint replaced by one serving another chain.
+		if err := verifyChainIDs(ctx, log, []*Chain{src, dst}); err != nil {
+			errCh <- err
+			return
+		}
 
 		// When a goroutine exits we need to query the channel and check that it is still in OPEN state.
 		var queryChannelResp *types.QueryChannelResponse
@@ -202,22 +754,33 @@ func relayerMainLoop(ctx context.Context, log *zap.Logger, src, dst *Chain, filt
 				return err
 			}
 			return nil
-		}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+		}, src.retryOptions(ctx, retry.OnRetry(func(n uint, err error) {
 			src.log.Info(
 				"Failed to query channel for updated state",
 				zap.String("src_chain_id", src.ChainID()),
 				zap.String("src_channel_id", channel.channel.ChannelId),
 				zap.Uint("attempt", n+1),
-				zap.Uint("max_attempts", RtyAttNum),
+				zap.Uint("max_attempts", src.retryAttempts()),
 				zap.Error(err),
 			)
-		})); err != nil {
+		}))...); err != nil {
 			errCh <- err
 			return
 		}
 
 		// If the channel is no longer in OPEN state then we remove it from the map of open channels.
 		if queryChannelResp.Channel.State != types.OPEN {
+			// The packets left pending on a closed channel can only be timed out on close, one last time.
+			if timeoutScanInterval > 0 && queryChannelResp.Channel.State == types.CLOSED {
+				if err := relayTimeouts(ctx, log, src, dst, maxTxSize, maxMsgLength, memo, finalityGating, channel.channel); err != nil {
+					log.Warn(
+						"Failed to relay packet timeouts on close",
+						zap.String("src_chain_id", src.ChainID()),
+						zap.String("src_channel_id", channel.channel.ChannelId),
+						zap.Error(err),
+					)
+				}
+			}
 			delete(srcOpenChannels, channel.channel.ChannelId)
 			src.log.Info(
 				"Channel is no longer in open state",
@@ -229,6 +792,36 @@ func relayerMainLoop(ctx context.Context, log *zap.Logger, src, dst *Chain, filt
 	}
 }
 
+// discoverChannels adds the channels of the src connections which were opened since they were last queried
+// to openChannels, applying the channel filter of their connection, and returns the connections relayed, nil if
+// they could not be queried. They are relayed from the next iteration of the main loop.
+func discoverChannels(ctx context.Context, src *Chain, conns pathConnections, openChannels map[string]*ActiveChannel) []relayedConnection {
+	srcChannels, relayedConns, err := conns.channels(ctx)
+	if err != nil {
+		src.log.Warn(
+			"Failed to query channels for discovery",
+			zap.String("chain_id", src.ChainID()),
+			zap.String("conn_id", src.ConnectionID()),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	for id, channel := range filterOpenChannels(srcChannels) {
+		if _, ok := openChannels[id]; ok {
+			continue
+		}
+		openChannels[id] = channel
+		src.log.Info(
+			"Discovered open channel",
+			zap.String("chain_id", src.ChainID()),
+			zap.String("channel_id", id),
+			zap.String("port_id", channel.channel.PortId),
+		)
+	}
+	return relayedConns
+}
+
 // queryChannelsOnConnection queries all the channels associated with a connection on the src chain.
 func queryChannelsOnConnection(ctx context.Context, src *Chain) ([]*types.IdentifiedChannel, error) {
 	// Query the latest heights on src & dst
@@ -243,15 +836,15 @@ func queryChannelsOnConnection(ctx context.Context, src *Chain) ([]*types.Identi
 	if err = retry.Do(func() error {
 		srcChannels, err = src.ChainProvider.QueryConnectionChannels(ctx, srch, src.ConnectionID())
 		return err
-	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, src.retryOptions(ctx, retry.OnRetry(func(n uint, err error) {
 		src.log.Info(
 			"Failed to query connection channels",
 			zap.String("conn_id", src.ConnectionID()),
 			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
+			zap.Uint("max_attempts", src.retryAttempts()),
 			zap.Error(err),
 		)
-	})); err != nil {
+	}))...); err != nil {
 		return nil, err
 	}
 
@@ -276,13 +869,23 @@ func filterOpenChannels(channels []*types.IdentifiedChannel) map[string]*ActiveC
 }
 
 // applyChannelFilterRule will use the given ChannelFilter's rule and channel list to build the appropriate list of
-// channels to relay on.
+// channels to relay on. Monitor-only channels are never relayed on.
 func applyChannelFilterRule(filter ChannelFilter, channels []*types.IdentifiedChannel) []*types.IdentifiedChannel {
+	if filter.monitor != nil {
+		var relayed []*types.IdentifiedChannel
+		for _, c := range channels {
+			if !filter.monitor.Matches(c.PortId, c.ChannelId) {
+				relayed = append(relayed, c)
+			}
+		}
+		channels = relayed
+	}
+
 	switch filter.Rule {
 	case allowList:
 		var filteredChans []*types.IdentifiedChannel
 		for _, c := range channels {
-			if filter.InChannelList(c.ChannelId) {
+			if filter.Matches(c.PortId, c.ChannelId) {
 				filteredChans = append(filteredChans, c)
 			}
 		}
@@ -290,7 +893,7 @@ func applyChannelFilterRule(filter ChannelFilter, channels []*types.IdentifiedCh
 	case denyList:
 		var filteredChans []*types.IdentifiedChannel
 		for _, c := range channels {
-			if filter.InChannelList(c.ChannelId) {
+			if filter.Matches(c.PortId, c.ChannelId) {
 				continue
 			}
 			filteredChans = append(filteredChans, c)
@@ -303,34 +906,67 @@ func applyChannelFilterRule(filter ChannelFilter, channels []*types.IdentifiedCh
 }
 
 // relayUnrelayedPacketsAndAcks will relay all the pending packets and acknowledgements on both the src and dst chains.
-func relayUnrelayedPacketsAndAcks(ctx context.Context, log *zap.Logger, wg *sync.WaitGroup, src, dst *Chain, maxTxSize, maxMsgLength uint64, memo string, srcChannel *ActiveChannel, channels chan<- *ActiveChannel) {
+// Once they are relayed, it returns if yield reports that other channels are waiting for their turn,
+// or once the channel is set dormant in dormant after having nothing to relay for long enough.
+func relayUnrelayedPacketsAndAcks(ctx context.Context, log *zap.Logger, wg *sync.WaitGroup, src, dst *Chain, maxTxSize, maxMsgLength uint64, memo string, finalityGating bool, timeoutScanInterval time.Duration, srcChannel *ActiveChannel, channels chan<- *ActiveChannel, yield func() bool, dormant *dormantChannels) {
 	// make goroutine signal its death, whether it's a panic or a return
 	defer func() {
 		wg.Done()
-		channels <- srcChannel
+		// Channels discovered after startup may outnumber the buffer of channels,
+		// don't block forever once the main loop is gone.
+		select {
+		case channels <- srcChannel:
+		case <-ctx.Done():
+		}
 	}()
 
-	var relayedAckSequencesSrc, relayedAckSequencesDst []uint64 = []uint64{}, []uint64{}
-
-	log.Info(
-		"Restart relaying",
-		zap.String("src_chain_id", src.ChainID()),
-		zap.String("src_channel_id", srcChannel.channel.ChannelId),
-		zap.String("src_port_id", srcChannel.channel.PortId),
-		zap.String("dst_chain_id", dst.ChainID()),
-		zap.String("dst_channel_id", srcChannel.channel.Counterparty.ChannelId),
-		zap.String("dst_port_id", srcChannel.channel.Counterparty.PortId),
-	)
+	// A channel resuming after yielding its turn carries on relaying.
+	resuming := srcChannel.resuming
+	srcChannel.resuming = false
+	if !resuming {
+		log.Info(
+			"Restart relaying",
+			zap.String("src_chain_id", src.ChainID()),
+			zap.String("src_channel_id", srcChannel.channel.ChannelId),
+			zap.String("src_port_id", srcChannel.channel.PortId),
+			zap.String("dst_chain_id", dst.ChainID()),
+			zap.String("dst_channel_id", srcChannel.channel.Counterparty.ChannelId),
+			zap.String("dst_port_id", srcChannel.channel.Counterparty.PortId),
+		)
+	}
+	ctx = withChannelActivity(ctx, srcChannel)
 	for {
+		if timeoutScanInterval > 0 && time.Since(srcChannel.lastTimeoutScan) >= timeoutScanInterval {
+			srcChannel.lastTimeoutScan = time.Now()
+			if err := relayTimeouts(ctx, log, src, dst, maxTxSize, maxMsgLength, memo, finalityGating, srcChannel.channel); err != nil {
+				log.Warn(
+					"Failed to relay packet timeouts",
+					zap.String("src_chain_id", src.ChainID()),
+					zap.String("src_channel_id", srcChannel.channel.ChannelId),
+					zap.String("dst_chain_id", dst.ChainID()),
+					zap.String("dst_channel_id", srcChannel.channel.Counterparty.ChannelId),
+					zap.Error(err),
+				)
+			}
+		}
 		if ok := relayUnrelayedPackets(ctx, log, src, dst,
-			maxTxSize, maxMsgLength, memo,
+			maxTxSize, maxMsgLength, memo, finalityGating,
 			srcChannel.channel); !ok {
 			return
 		}
 		if ok := relayUnrelayedAcks(ctx, log, src, dst,
 			maxTxSize, maxMsgLength, memo,
-			srcChannel.channel,
-			&relayedAckSequencesSrc, &relayedAckSequencesDst); !ok {
+			srcChannel.channel); !ok {
+			return
+		}
+
+		if dormant.idle(srcChannel) {
+			dormant.sleep(srcChannel)
+			return
+		}
+
+		if yield() {
+			srcChannel.yielded, srcChannel.resuming = true, true
 			return
 		}
 
@@ -347,7 +983,10 @@ func relayUnrelayedPacketsAndAcks(ctx context.Context, log *zap.Logger, wg *sync
 // relayUnrelayedPackets fetches unrelayed packet sequence numbers and attempts to relay the associated packets.
 // relayUnrelayedPackets returns true if packets were empty or were successfully relayed.
 // Otherwise, it logs the errors and returns false.
-func relayUnrelayedPackets(ctx context.Context, log *zap.Logger, src, dst *Chain, maxTxSize, maxMsgLength uint64, memo string, srcChannel *types.IdentifiedChannel) bool {
+// With finalityGating set, only packets sent at or below the latest height of their source rollapp
+// finalized on the settlement layer are relayed. Packets are only relayed once buried under the confirmation depth
+// of the chain they were sent on.
+func relayUnrelayedPackets(ctx context.Context, log *zap.Logger, src, dst *Chain, maxTxSize, maxMsgLength uint64, memo string, finalityGating bool, srcChannel *types.IdentifiedChannel) bool {
 	srch, dsth, err := QueryLatestHeights(ctx, src, dst)
 	if err != nil {
 		log.Warn(
@@ -359,7 +998,19 @@ func relayUnrelayedPackets(ctx context.Context, log *zap.Logger, src, dst *Chain
 	// Fetch any unrelayed sequences depending on the channel order
 	// we are quering the previous heights because later
 	// when we query tendermint proof, the proof is in the following  height
-	sp := UnrelayedSequences(ctx, src, dst, srch-1, dsth-1, srcChannel)
+	sp, err := unrelayedPackets(ctx, src, dst, srch-1, dsth-1, srcChannel, finalityGating)
+	if err != nil {
+		log.Warn(
+			"Failed to query finalized unrelayed packets",
+			zap.String("src_chain_id", src.ChainID()),
+			zap.String("src_channel_id", srcChannel.ChannelId),
+			zap.String("dst_chain_id", dst.ChainID()),
+			zap.String("dst_channel_id", srcChannel.Counterparty.ChannelId),
+			zap.Error(err),
+		)
+		// Try again on the next iteration.
+		return true
+	}
 
 	// If there are no unrelayed packets, stop early.
 	if sp.Empty() {
@@ -374,6 +1025,7 @@ func relayUnrelayedPackets(ctx context.Context, log *zap.Logger, src, dst *Chain
 		)
 		return true
 	}
+	recordChannelActivity(ctx)
 
 	if len(sp.Src) > 0 {
 		src.log.Info(
@@ -393,7 +1045,7 @@ func relayUnrelayedPackets(ctx context.Context, log *zap.Logger, src, dst *Chain
 		)
 	}
 
-	if err := RelayPackets(ctx, log, src, dst, srch, dsth, sp, maxTxSize, maxMsgLength, memo, srcChannel); err != nil {
+	if _, err := RelayPackets(ctx, log, src, dst, srch, dsth, sp, maxTxSize, maxMsgLength, memo, srcChannel); err != nil {
 		// If there was a context cancellation or deadline while attempting to relay packets,
 		// log that and indicate failure.
 		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
@@ -444,7 +1096,6 @@ func relayUnrelayedAcks(ctx context.Context,
 	log *zap.Logger, src, dst *Chain,
 	maxTxSize, maxMsgLength uint64, memo string,
 	srcChannel *types.IdentifiedChannel,
-	relayedAckSequencesSrc, relayedAckSequencesDst *[]uint64,
 ) bool {
 
 	srch, dsth, err := QueryLatestHeights(ctx, src, dst)
@@ -463,14 +1114,14 @@ func relayUnrelayedAcks(ctx context.Context,
 		srcErr = relayUnrelayedAcksHelper(ctx, log,
 			src, srcChannel.ChannelId, srcChannel.PortId, srch,
 			dst, srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId, dsth,
-			maxTxSize, maxMsgLength, memo, relayedAckSequencesSrc)
+			maxTxSize, maxMsgLength, memo)
 	}()
 	go func() {
 		defer wg.Done()
 		DstErr = relayUnrelayedAcksHelper(ctx, log,
 			dst, srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId, dsth,
 			src, srcChannel.ChannelId, srcChannel.PortId, srch,
-			maxTxSize, maxMsgLength, memo, relayedAckSequencesDst)
+			maxTxSize, maxMsgLength, memo)
 	}()
 	wg.Wait()
 	if srcErr != nil {
@@ -484,72 +1135,63 @@ func relayUnrelayedAcks(ctx context.Context,
 	return true
 }
 
+// relayUnrelayedAcksHelper relays the acknowledgements written on src that dst has not received yet.
+// The acknowledgements are checkpointed in the ack store while they are relayed, so that a relayer restarted
+// before the relay completed does not relay them again while the transaction carrying them may still be included.
 func relayUnrelayedAcksHelper(ctx context.Context, log *zap.Logger,
 	src *Chain, srcChannelId, srcPortId string, srch int64,
 	dst *Chain, dstChannelId, dstPortId string, dsth int64,
 	maxTxSize, maxMsgLength uint64, memo string,
-	relayedAckSequences *[]uint64,
 ) error {
+	if !relaysPacketsSentOn(ctx, dst) {
+		// The acknowledgements written on src are those of packets sent on dst, relayed in the other direction.
+		return nil
+	}
+
 	// we are quering the previous heights because later
 	// when we query tendermint proof, the proof is in the following  height
 	adjustedSrch := srch - 1
 	adjustedDsth := dsth - 1
 
-	var err error = nil
-	var sequences []uint64
-
-	relayedAckSequencesCandidated := *relayedAckSequences
-
 	// Fetch any unrelayed acks generated on src
-	// depending on the channel order
-	sequences, err = unrelayedAcknowledgements(ctx,
+	sequences, err := unrelayedAcknowledgements(ctx,
 		src, srcChannelId, srcPortId, adjustedSrch,
 		dst, dstChannelId, dstPortId, adjustedDsth,
-		&relayedAckSequencesCandidated,
 	)
+	if err != nil {
+		log.Warn(
+			"unrelayedAcknowledgements failed",
+			zap.String("src_chain_id", src.ChainID()),
+			zap.String("src_channel_id", srcChannelId),
+			zap.String("dst_chain_id", dst.ChainID()),
+			zap.String("dst_channel_id", dstChannelId),
+			zap.Error(err),
+		)
+		return err
+	}
 
-	// If there are no unrelayed acks, stop early.
-	if len(sequences) != 0 {
-		if err != nil {
-			log.Error("unrelayedAcknowledgements returned: len(sequences)>0, but err != nil",
-				zap.String("sequences", fmt.Sprint(sequences)),
-				zap.Error(err))
-			panic(err)
-		}
-
-		// send acks generated on dst to src
-		err = relayAcknowledgements(ctx, log,
-			src, srcChannelId, srcPortId, srch, sequences,
-			dst, dstChannelId, dstPortId,
-			maxTxSize, maxMsgLength, memo)
-
-		if err != nil {
-			// If there was a context cancellation or deadline while attempting to relay acknowledgements,
-			// log that and indicate failure.
-			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
-				log.Warn(
-					"Context finished while waiting for RelayAcknowledgements to complete",
-					zap.String("src_chain_id", src.ChainID()),
-					zap.String("src_channel_id", srcChannelId),
-					zap.String("dst_chain_id", dst.ChainID()),
-					zap.String("dst_channel_id", dstChannelId),
-					zap.Error(ctx.Err()),
-				)
-				return err
+	if inFlight := inFlightAckSequences(ctx, log, src, srcChannelId, srcPortId, time.Now()); len(inFlight) > 0 {
+		pending := sequences[:0:0]
+		for _, seq := range sequences {
+			if !inFlight[seq] {
+				pending = append(pending, seq)
 			}
-
-			// Otherwise, not a context error, but an application-level error.
-			log.Warn(
-				"Relay acknowledgements error",
+		}
+		if len(pending) < len(sequences) {
+			log.Info(
+				"Deferring acknowledgements of an interrupted relay",
 				zap.String("src_chain_id", src.ChainID()),
 				zap.String("src_channel_id", srcChannelId),
 				zap.String("dst_chain_id", dst.ChainID()),
 				zap.String("dst_channel_id", dstChannelId),
-				zap.Error(err),
+				zap.Int("count", len(sequences)-len(pending)),
 			)
 		}
+		sequences = pending
+	}
 
-	} else {
+	// If there are no unrelayed acks, stop early.
+	if len(sequences) == 0 {
 		log.Debug(
 			"No acknowledgements in queue",
 			zap.String("src_chain_id", src.ChainID()),
@@ -559,21 +1201,48 @@ func relayUnrelayedAcksHelper(ctx context.Context, log *zap.Logger,
 			zap.String("dst_channel_id", dstChannelId),
 			zap.String("dst_port_id", dstPortId),
 		)
+		return nil
 	}
+	recordChannelActivity(ctx)
+
+	checkpointAckSequences(ctx, log, src, srcChannelId, srcPortId, ackstore.Checkpoint{Sequences: sequences, Time: time.Now()})
+
+	// send acks generated on dst to src
+	_, err = relayAcknowledgements(ctx, log,
+		src, srcChannelId, srcPortId, srch, sequences,
+		dst, dstChannelId, dstPortId,
+		maxTxSize, maxMsgLength, memo)
+
+	// The relay completed one way or the other, acknowledgements left unreceived are found again on chain.
+	checkpointAckSequences(ctx, log, src, srcChannelId, srcPortId, ackstore.Checkpoint{})
 
 	if err != nil {
+		// If there was a context cancellation or deadline while attempting to relay acknowledgements,
+		// log that and indicate failure.
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			log.Warn(
+				"Context finished while waiting for RelayAcknowledgements to complete",
+				zap.String("src_chain_id", src.ChainID()),
+				zap.String("src_channel_id", srcChannelId),
+				zap.String("dst_chain_id", dst.ChainID()),
+				zap.String("dst_channel_id", dstChannelId),
+				zap.Error(ctx.Err()),
+			)
+			return err
+		}
+
+		// Otherwise, not a context error, but an application-level error.
 		log.Warn(
-			"unrelayedAcknowledgements failed",
+			"Relay acknowledgements error",
 			zap.String("src_chain_id", src.ChainID()),
 			zap.String("src_channel_id", srcChannelId),
 			zap.String("dst_chain_id", dst.ChainID()),
 			zap.String("dst_channel_id", dstChannelId),
-			zap.Error(ctx.Err()),
+			zap.Error(err),
 		)
-	} else {
-		// update relayed sequences
-		*relayedAckSequences = relayedAckSequencesCandidated
+		return err
 	}
 
-	return err
+	storeRelayedAckSequences(ctx, log, src, srcChannelId, srcPortId, sequences)
+	return nil
 }
@@ -1,10 +1,21 @@
 package relayer
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/provider/evm"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestApplyChannelFilterAllowRule(t *testing.T) {
@@ -97,4 +108,342 @@ func TestValidateChannelFilterRule(t *testing.T) {
 		},
 	}
 	require.Error(t, p.ValidateChannelFilterRule())
+
+	p = &Path{Filter: ChannelFilter{Rule: "allowlist", ChannelList: []string{"channel-0", "transfer:channel-*", "icahost:*", "transfer:/^channel-(0|12)$/"}}}
+	require.NoError(t, p.ValidateChannelFilterRule())
+
+	p = &Path{Filter: ChannelFilter{Rule: "allowlist", ChannelList: []string{"transfer:channel-["}}}
+	require.Error(t, p.ValidateChannelFilterRule())
+
+	p = &Path{Filter: ChannelFilter{Rule: "allowlist", ChannelList: []string{"transfer:"}}}
+	require.Error(t, p.ValidateChannelFilterRule())
+
+	p = &Path{Monitor: &ChannelMonitor{ChannelList: []string{"transfer:/[/"}}}
+	require.Error(t, p.ValidateChannelFilterRule())
+
+	p = &Path{Monitor: &ChannelMonitor{ChannelList: []string{"transfer:channel-*"}, MaxPendingAge: time.Minute}}
+	require.NoError(t, p.ValidateChannelFilterRule())
+}
+
+func TestApplyChannelFilterPatterns(t *testing.T) {
+	channels := []*chantypes.IdentifiedChannel{
+		{PortId: "transfer", ChannelId: "channel-0"},
+		{PortId: "transfer", ChannelId: "channel-12"},
+		{PortId: "icahost", ChannelId: "channel-3"},
+		{PortId: "oracle", ChannelId: "channel-4"},
+	}
+	ids := func(chans []*chantypes.IdentifiedChannel) []string {
+		var ids []string
+		for _, c := range chans {
+			ids = append(ids, c.PortId+":"+c.ChannelId)
+		}
+		return ids
+	}
+
+	filter := ChannelFilter{Rule: allowList, ChannelList: []string{"transfer:/^channel-1[0-9]$/", "icahost:*"}}
+	require.Equal(t, []string{"transfer:channel-12", "icahost:channel-3"}, ids(applyChannelFilterRule(filter, channels)))
+
+	filter = ChannelFilter{Rule: denyList, ChannelList: []string{"transfer:*", "channel-3"}}
+	require.Equal(t, []string{"oracle:channel-4"}, ids(applyChannelFilterRule(filter, channels)))
+
+	// Monitor-only channels are never relayed, whatever the rule.
+	monitor := &ChannelMonitor{ChannelList: []string{"transfer:channel-12", "oracle:*"}}
+	filter = ChannelFilter{Rule: allowList, ChannelList: []string{"transfer:*"}, monitor: monitor}
+	require.Equal(t, []string{"transfer:channel-0"}, ids(applyChannelFilterRule(filter, channels)))
+
+	filter = ChannelFilter{monitor: monitor}
+	require.Equal(t, []string{"transfer:channel-0", "icahost:channel-3"}, ids(applyChannelFilterRule(filter, channels)))
+}
+
+func TestFinalizedQueryHeight(t *testing.T) {
+	ctx := context.Background()
+
+	// Packets sent on chains which are not rollapps are not gated.
+	hub := &Chain{ChainProvider: &cosmos.CosmosProvider{PCfg: cosmos.CosmosProviderConfig{
+		ChainID:    "hub",
+		ClientType: ibcexported.Tendermint,
+	}}}
+	h, finalized, err := finalizedQueryHeight(ctx, hub, 100)
+	require.NoError(t, err)
+	require.True(t, finalized)
+	require.Equal(t, int64(100), h)
+
+	// Rollapps can't be gated without a settlement layer.
+	rollapp := &Chain{ChainProvider: &cosmos.CosmosProvider{PCfg: cosmos.CosmosProviderConfig{
+		ChainID:    "rollapp",
+		ClientType: ibcexported.Furyint,
+	}}}
+	_, finalized, err = finalizedQueryHeight(ctx, rollapp, 100)
+	require.Error(t, err)
+	require.False(t, finalized)
+}
+
+func TestConfirmedQueryHeight(t *testing.T) {
+	// Packets of chains without a confirmation depth are queried at the latest height.
+	hub := &Chain{ChainProvider: &cosmos.CosmosProvider{PCfg: cosmos.CosmosProviderConfig{ChainID: "hub"}}}
+	require.Equal(t, int64(100), confirmedQueryHeight(hub, 100))
+
+	eth := &Chain{ChainProvider: &evm.EVMProvider{PCfg: evm.EVMProviderConfig{ChainID: "evm", Confirmations: 12}}}
+	require.Equal(t, int64(88), confirmedQueryHeight(eth, 100))
+	require.Equal(t, int64(1), confirmedQueryHeight(eth, 5))
+}
+
+// chainIDProvider is a chain provider whose endpoint verification returns err.
+type chainIDProvider struct {
+	provider.ChainProvider
+	chainID string
+	err     error
+}
+
+func (p chainIDProvider) ChainId() string                         { return p.chainID }
+func (p chainIDProvider) VerifyChainID(ctx context.Context) error { return p.err }
+
+func TestVerifyChainIDs(t *testing.T) {
+	ok := &Chain{ChainProvider: chainIDProvider{chainID: "chain-a"}}
+	unreachable := &Chain{ChainProvider: chainIDProvider{chainID: "chain-b", err: errors.New("connection refused")}}
+	mismatch := &Chain{ChainProvider: chainIDProvider{chainID: "chain-c", err: fmt.Errorf("%w: serves chain-c-testnet", provider.ErrChainIDMismatch)}}
+
+	require.NoError(t, verifyChainIDs(context.Background(), zap.NewNop(), []*Chain{ok, unreachable}))
+
+	err := verifyChainIDs(context.Background(), zap.NewNop(), []*Chain{ok, mismatch})
+	require.ErrorIs(t, err, provider.ErrChainIDMismatch)
+	require.Contains(t, err.Error(), "chain-c")
+}
+
+func TestOrderedSequences(t *testing.T) {
+	// Packets are relayed in order, up to the first gap.
+	ordered, gap := orderedSequences([]uint64{7, 5, 6, 9}, 5)
+	require.False(t, gap)
+	require.Equal(t, []uint64{5, 6, 7}, ordered)
+
+	// Packets received since they were listed are left out.
+	ordered, gap = orderedSequences([]uint64{3, 4, 5}, 4)
+	require.False(t, gap)
+	require.Equal(t, []uint64{4, 5}, ordered)
+
+	ordered, gap = orderedSequences([]uint64{6, 7}, 5)
+	require.True(t, gap)
+	require.Empty(t, ordered)
+
+	ordered, gap = orderedSequences([]uint64{3}, 5)
+	require.False(t, gap)
+	require.Empty(t, ordered)
+}
+
+// orderedChannelProvider serves the packets committed on, and received by, an end of an ordered channel.
+type orderedChannelProvider struct {
+	provider.ChainProvider
+	chainID     string
+	commitments []uint64
+	missing     map[uint64]bool
+	nextRecv    uint64
+}
+
+func (p *orderedChannelProvider) ChainId() string { return p.chainID }
+
+// QueryPacketCommitments leaves out the missing commitments, as if they were not yet queryable.
+func (p *orderedChannelProvider) QueryPacketCommitments(_ context.Context, _ uint64, channelID, portID string) ([]*chantypes.PacketState, error) {
+	commitments := []*chantypes.PacketState{}
+	for _, seq := range p.commitments {
+		if !p.missing[seq] {
+			commitments = append(commitments, &chantypes.PacketState{PortId: portID, ChannelId: channelID, Sequence: seq})
+		}
+	}
+	return commitments, nil
+}
+
+func (p *orderedChannelProvider) QueryPacketCommitment(_ context.Context, _ int64, _, _ string, seq uint64) (*chantypes.QueryPacketCommitmentResponse, error) {
+	for _, c := range p.commitments {
+		if c == seq {
+			return &chantypes.QueryPacketCommitmentResponse{Commitment: []byte{1}}, nil
+		}
+	}
+	return nil, chantypes.ErrPacketCommitmentNotFound
+}
+
+func (p *orderedChannelProvider) QueryUnreceivedPackets(_ context.Context, _ uint64, _, _ string, seqs []uint64) ([]uint64, error) {
+	unreceived := []uint64{}
+	for _, seq := range seqs {
+		if seq >= p.nextRecv {
+			unreceived = append(unreceived, seq)
+		}
+	}
+	return unreceived, nil
+}
+
+func (p *orderedChannelProvider) QueryNextSeqRecv(context.Context, int64, string, string) (*chantypes.QueryNextSequenceReceiveResponse, error) {
+	return &chantypes.QueryNextSequenceReceiveResponse{NextSequenceReceive: p.nextRecv}, nil
+}
+
+func TestUnrelayedSequencesOrderedGap(t *testing.T) {
+	ctx := context.Background()
+	srcp := &orderedChannelProvider{chainID: "chain-a", commitments: []uint64{4, 5, 6, 8}}
+	dstp := &orderedChannelProvider{chainID: "chain-b", nextRecv: 4}
+	src := &Chain{log: zap.NewNop(), ChainProvider: srcp}
+	dst := &Chain{log: zap.NewNop(), ChainProvider: dstp}
+	unrelayed := func() []uint64 {
+		return unrelayedSequences(ctx,
+			src, "channel-0", "transfer", 10,
+			dst, "channel-1", "transfer", chantypes.ORDERED,
+		)
+	}
+
+	require.Equal(t, []uint64{4, 5, 6}, unrelayed())
+
+	// The next packet to receive is relayed first although it was missing from the commitments listed.
+	srcp.missing = map[uint64]bool{4: true}
+	require.Equal(t, []uint64{4, 5, 6}, unrelayed())
+
+	// Nothing can be relayed once the next packet to receive is gone.
+	srcp.commitments = []uint64{5, 6, 8}
+	srcp.missing = nil
+	require.Empty(t, unrelayed())
+}
+
+func TestSettlementStates(t *testing.T) {
+	ctx := context.Background()
+	sp := cosmos.NewMockSettlementProvider()
+	sp.SetFinalizedHeight("rollapp", 5)
+	sp.SetDisputePeriod("rollapp", 120)
+	states := newSettlementStates(sp)
+
+	s, ok, err := states.QuerySettlementState(ctx, "rollapp", 10)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, processor.SettlementStatePending, s.Status)
+	require.Equal(t, uint64(10), s.StartHeight)
+	require.Equal(t, uint64(10), s.EndHeight)
+	require.Equal(t, uint64(120), s.DisputeWindowEnd)
+
+	sp.RevertState("rollapp", 10)
+	s, _, err = states.QuerySettlementState(ctx, "rollapp", 10)
+	require.NoError(t, err)
+	require.Equal(t, processor.SettlementStateReverted, s.Status)
+
+	s, _, err = states.QuerySettlementState(ctx, "rollapp", 5)
+	require.NoError(t, err)
+	require.Equal(t, processor.SettlementStateFinalized, s.Status)
+}
+
+// discoveryProvider serves the channels of a connection, opened over time. Its channels close once queried,
+// as the main loop does when their relaying exits, and the latest height of a halted chain can't be queried.
+type discoveryProvider struct {
+	provider.ChainProvider
+	chainID string
+	halted  bool
+
+	mu       sync.Mutex
+	channels []*chantypes.IdentifiedChannel
+	queried  []string
+}
+
+func (p *discoveryProvider) ChainId() string { return p.chainID }
+
+func (p *discoveryProvider) VerifyChainID(context.Context) error { return nil }
+
+func (p *discoveryProvider) QueryLatestHeight(context.Context) (int64, error) {
+	if p.halted {
+		return 0, errors.New("connection refused")
+	}
+	return 10, nil
+}
+
+func (p *discoveryProvider) QueryConnectionChannels(context.Context, int64, string) ([]*chantypes.IdentifiedChannel, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	channels := make([]*chantypes.IdentifiedChannel, 0, len(p.channels))
+	for _, c := range p.channels {
+		c := *c
+		channels = append(channels, &c)
+	}
+	return channels, nil
+}
+
+func (p *discoveryProvider) QueryChannel(_ context.Context, _ int64, channelID, _ string) (*chantypes.QueryChannelResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queried = append(p.queried, channelID)
+	for _, c := range p.channels {
+		if c.ChannelId == channelID {
+			c.State = chantypes.CLOSED
+		}
+	}
+	return &chantypes.QueryChannelResponse{Channel: &chantypes.Channel{State: chantypes.CLOSED}}, nil
+}
+
+func (p *discoveryProvider) open(channelID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.channels = append(p.channels, testConnectionChannel("transfer", channelID, "connection-0"))
+}
+
+func (p *discoveryProvider) queriedChannels() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.queried...)
+}
+
+func discoveryChains(hub, rollapp *discoveryProvider) (*Chain, *Chain, pathConnections) {
+	src := NewChain(zap.NewNop(), hub, false)
+	src.PathEnd = &PathEnd{ChainID: "hub", ClientID: "07-tendermint-0", ConnectionID: "connection-0"}
+	dst := NewChain(zap.NewNop(), rollapp, false)
+	dst.PathEnd = &PathEnd{ChainID: "rollapp", ClientID: "07-tendermint-0", ConnectionID: "connection-0"}
+	return src, dst, pathConnections{src: src, dst: dst}
+}
+
+func TestDiscoverChannels(t *testing.T) {
+	ctx := context.Background()
+	hub := &discoveryProvider{chainID: "hub"}
+	hub.open("channel-0")
+	src, _, conns := discoveryChains(hub, &discoveryProvider{chainID: "rollapp"})
+
+	open := map[string]*ActiveChannel{}
+	require.Len(t, discoverChannels(ctx, src, conns, open), 1)
+	require.Contains(t, open, "channel-0")
+	relayed := open["channel-0"]
+
+	// Channels opened since are added, the channels already relayed are left as they are.
+	hub.open("channel-1")
+	discoverChannels(ctx, src, conns, open)
+	require.Len(t, open, 2)
+	require.Same(t, relayed, open["channel-0"])
+
+	// The channels are left as they are when they can't be queried.
+	hub.halted = true
+	require.Nil(t, discoverChannels(ctx, src, conns, open))
+	require.Len(t, open, 2)
+}
+
+func TestRelayerMainLoopNoOpenChannels(t *testing.T) {
+	src, dst, conns := discoveryChains(&discoveryProvider{chainID: "hub"}, &discoveryProvider{chainID: "rollapp"})
+	errCh := make(chan error, 1)
+	relayerMainLoop(context.Background(), zap.NewNop(), src, dst, conns, 0, 0, "", false, 0, 0, 0, nil, nil, errCh)
+	require.EqualError(t, <-errCh, "there are no open channels to relay on")
+}
+
+func TestRelayerMainLoopDiscoversChannels(t *testing.T) {
+	hub := &discoveryProvider{chainID: "hub"}
+	hub.open("channel-0")
+	// Relaying exits at once as the latest height of the rollapp can't be queried, closing the channel.
+	src, dst, conns := discoveryChains(hub, &discoveryProvider{chainID: "rollapp", halted: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go relayerMainLoop(ctx, zap.NewNop(), src, dst, conns, 0, 0, "", false, 10*time.Millisecond, 0, 0, nil, nil, errCh)
+
+	require.Eventually(t, func() bool { return len(hub.queriedChannels()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// The loop keeps running without open channels, relaying the channels opened since.
+	select {
+	case err := <-errCh:
+		t.Fatalf("main loop exited without open channels: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	hub.open("channel-1")
+	require.Eventually(t, func() bool { return len(hub.queriedChannels()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"channel-0", "channel-1"}, hub.queriedChannels())
+
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
 }
@@ -0,0 +1,429 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultStuckPacketsInterval  = time.Minute
+	defaultStuckPacketsThreshold = 15 * time.Minute
+
+	// defaultStuckPacketsEscalation is how many times the threshold packets are pending for before they are critical,
+	// unless configured otherwise.
+	defaultStuckPacketsEscalation = 4
+)
+
+// Levels of the stuck packets, escalated as they stay pending.
+const (
+	StuckWarning  = "warning"
+	StuckCritical = "critical"
+)
+
+// Diagnoses of the stuck packets, telling a chain slow to include or observe the relayed messages
+// from a channel the relayer fails to relay.
+const (
+	// StuckUnattempted packets were not attempted since the relayer started, e.g. held back on finality,
+	// filtered out, or sent on a chain whose blocks are not observed.
+	StuckUnattempted = "unattempted"
+
+	// StuckFailing packets failed to be relayed at their last attempt, whose error tells why.
+	StuckFailing = "failing"
+
+	// StuckInFlight packets were relayed at their last attempt, without the counterparty chain committing them yet.
+	StuckInFlight = "in_flight"
+
+	// StuckPaused packets are on a channel paused, by an operator or a rate limit.
+	StuckPaused = "paused"
+)
+
+// StuckPacketsConfig configures the detection of the packets and acknowledgements pending for long on the paths
+// relayed by `rly start`, reported with the last attempt at relaying them in the metrics and the admin API.
+type StuckPacketsConfig struct {
+	// Interval is how often the pending packets are queried, every minute if unset.
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+
+	// Threshold is how long a packet is pending before it is stuck, at the warning level, 15 minutes if unset.
+	Threshold time.Duration `yaml:"threshold,omitempty" json:"threshold,omitempty"`
+
+	// CriticalAfter is how long a packet is pending before it is escalated to the critical level,
+	// 4 times the threshold if unset.
+	CriticalAfter time.Duration `yaml:"critical-after,omitempty" json:"critical-after,omitempty"`
+}
+
+// Validate checks the stuck packets config.
+func (c *StuckPacketsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 || c.Threshold < 0 || c.CriticalAfter < 0 {
+		return errors.New("stuck-packets durations must not be negative")
+	}
+	if c.CriticalAfter > 0 && c.CriticalAfter < c.threshold() {
+		return fmt.Errorf("stuck-packets critical-after %s is shorter than the threshold %s", c.CriticalAfter, c.threshold())
+	}
+	return nil
+}
+
+func (c *StuckPacketsConfig) threshold() time.Duration {
+	if c.Threshold == 0 {
+		return defaultStuckPacketsThreshold
+	}
+	return c.Threshold
+}
+
+func (c *StuckPacketsConfig) criticalAfter() time.Duration {
+	if c.CriticalAfter == 0 {
+		return defaultStuckPacketsEscalation * c.threshold()
+	}
+	return c.CriticalAfter
+}
+
+// level returns the level of a packet pending for age, empty if it is not stuck.
+func (c *StuckPacketsConfig) level(age time.Duration) string {
+	switch {
+	case age >= c.criticalAfter():
+		return StuckCritical
+	case age >= c.threshold():
+		return StuckWarning
+	}
+	return ""
+}
+
+type relayAttemptsKey struct{}
+
+// withRelayAttempts records the attempts of the legacy processor with ctx at relaying packets in a, if non-nil.
+func withRelayAttempts(ctx context.Context, a *processor.RelayAttempts) context.Context {
+	if a == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, relayAttemptsKey{}, a)
+}
+
+// recordRelayAttempt records the result of relaying a sequence in the relay attempts attached to ctx, if any.
+// Skipped sequences were not attempted.
+func recordRelayAttempt(ctx context.Context, res SequenceResult) {
+	a, ok := ctx.Value(relayAttemptsKey{}).(*processor.RelayAttempts)
+	if !ok || res.Status == SequenceSkipped {
+		return
+	}
+	var err error
+	if res.Status == SequenceFailed {
+		err = errors.New(res.Reason)
+	}
+	a.Record(processor.RelayAttempt{
+		Path:      provider.PathNameFromContext(ctx),
+		ChainID:   res.ChainID,
+		ChannelID: res.ChannelID,
+		PortID:    res.PortID,
+		Sequence:  res.Sequence,
+		Ack:       res.ack,
+		MsgType:   res.MsgType,
+	}, err)
+}
+
+// pendingSeq is a packet, or the acknowledgement of a packet if ack, pending on a channel end.
+type pendingSeq struct {
+	sequence uint64
+	ack      bool
+}
+
+// stuckEnd is the channel end of a path packets were sent from, and acknowledgements written on, pending relay.
+type stuckEnd struct {
+	path, chainID, channelID, portID string
+
+	// srcPortID and srcChannelID identify the channel on the src chain of the path, which it is paused by.
+	srcPortID, srcChannelID string
+
+	// firstSeen is when each pending packet and acknowledgement was first observed pending.
+	firstSeen map[pendingSeq]time.Time
+
+	// level is the highest level of the packets stuck at the last check, empty if none.
+	level string
+}
+
+// stuckPacketDetector tracks how long the packets and acknowledgements of every path are pending,
+// escalating the packets pending for long from warning to critical.
+type stuckPacketDetector struct {
+	log      *zap.Logger
+	metrics  *processor.PrometheusMetrics
+	cfg      *StuckPacketsConfig
+	attempts *processor.RelayAttempts
+
+	mu   sync.Mutex
+	ends map[string]*stuckEnd
+}
+
+func newStuckPacketDetector(log *zap.Logger, metrics *processor.PrometheusMetrics, cfg *StuckPacketsConfig, attempts *processor.RelayAttempts) *stuckPacketDetector {
+	return &stuckPacketDetector{
+		log:      log,
+		metrics:  metrics,
+		cfg:      cfg,
+		attempts: attempts,
+		ends:     make(map[string]*stuckEnd),
+	}
+}
+
+// check queries the packets and acknowledgements pending on every open channel of the path of r. The attempts
+// at relaying the packets of the path no longer pending are forgotten once every channel is queried.
+func (d *stuckPacketDetector) check(ctx context.Context, r *pathRunner) {
+	channels, err := r.openChannels(ctx)
+	if err != nil {
+		d.log.Debug("Failed to query channels to detect stuck packets", zap.String("path", r.name), zap.Error(err))
+		return
+	}
+	srch, dsth, err := QueryLatestHeights(ctx, r.src, r.dst)
+	if err != nil {
+		d.log.Debug("Failed to query latest heights to detect stuck packets", zap.String("path", r.name), zap.Error(err))
+		return
+	}
+	now := time.Now().UTC()
+	seen := make(map[string]bool, 2*len(channels))
+	for _, c := range channels {
+		sp := UnrelayedSequences(ctx, r.src, r.dst, srch-1, dsth-1, c.channel)
+		ap := UnrelayedAcknowledgements(ctx, r.src, r.dst, srch-1, dsth-1, c.channel)
+		src := d.record(r, r.src.ChainID(), c.channel.ChannelId, c.channel.PortId, c.channel.PortId, c.channel.ChannelId, sp.Src, ap.Src, now)
+		dst := d.record(r, r.dst.ChainID(), c.channel.Counterparty.ChannelId, c.channel.Counterparty.PortId, c.channel.PortId, c.channel.ChannelId, sp.Dst, ap.Dst, now)
+		seen[src], seen[dst] = true, true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, end := range d.ends {
+		if end.path == r.name && !seen[k] {
+			// The channel was closed since.
+			delete(d.ends, k)
+			d.metrics.SetStuckPackets(end.path, end.chainID, end.channelID, end.portID, 0, 0)
+		}
+	}
+	d.attempts.Retain(r.name, func(a processor.RelayAttempt) bool {
+		end, ok := d.ends[r.name+"/"+a.ChainID+"/"+a.PortID+"/"+a.ChannelID]
+		if !ok {
+			return false
+		}
+		_, pending := end.firstSeen[pendingSeq{sequence: a.Sequence, ack: a.Ack}]
+		return pending
+	})
+}
+
+// record updates the packets and acknowledgements pending on a channel end of the path of r, logging when its
+// packets escalate to a higher level and once none is stuck anymore, and returns the key of the channel end.
+func (d *stuckPacketDetector) record(
+	r *pathRunner,
+	chainID, channelID, portID, srcPortID, srcChannelID string,
+	packets, acks []uint64,
+	now time.Time,
+) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := r.name + "/" + chainID + "/" + portID + "/" + channelID
+	end, ok := d.ends[key]
+	if !ok {
+		end = &stuckEnd{
+			path:         r.name,
+			chainID:      chainID,
+			channelID:    channelID,
+			portID:       portID,
+			srcPortID:    srcPortID,
+			srcChannelID: srcChannelID,
+		}
+		d.ends[key] = end
+	}
+
+	firstSeen := make(map[pendingSeq]time.Time, len(packets)+len(acks))
+	var (
+		warning, critical int
+		oldest            pendingSeq
+		oldestSeen        = now
+	)
+	track := func(seqs []uint64, ack bool) {
+		for _, seq := range seqs {
+			ps := pendingSeq{sequence: seq, ack: ack}
+			since, ok := end.firstSeen[ps]
+			if !ok {
+				since = now
+			}
+			firstSeen[ps] = since
+			switch d.cfg.level(now.Sub(since)) {
+			case StuckCritical:
+				critical++
+			case StuckWarning:
+				warning++
+			}
+			if since.Before(oldestSeen) {
+				oldest, oldestSeen = ps, since
+			}
+		}
+	}
+	track(packets, false)
+	track(acks, true)
+	end.firstSeen = firstSeen
+
+	level := ""
+	switch {
+	case critical > 0:
+		level = StuckCritical
+	case warning > 0:
+		level = StuckWarning
+	}
+	if level != end.level {
+		fields := []zap.Field{
+			zap.String("path", r.name),
+			zap.String("chain_id", chainID),
+			zap.String("channel_id", channelID),
+			zap.String("port_id", portID),
+			zap.Int("stuck_warning", warning),
+			zap.Int("stuck_critical", critical),
+		}
+		switch {
+		case level == "":
+			d.log.Info("Packets no longer stuck on channel", fields...)
+		case level == StuckWarning && end.level == StuckCritical:
+			// Only escalations are logged until no packet is stuck anymore.
+		default:
+			p := d.stuckPacket(r, end, oldest, oldestSeen, now)
+			fields = append(fields,
+				zap.Uint64("oldest_sequence", p.Sequence),
+				zap.Bool("oldest_ack", p.Ack),
+				zap.String("oldest_age", p.Age),
+				zap.String("diagnosis", p.Diagnosis),
+				zap.String("last_error", p.LastError),
+			)
+			if level == StuckCritical {
+				d.log.Error("Packets stuck on channel", fields...)
+			} else {
+				d.log.Warn("Packets stuck on channel", fields...)
+			}
+		}
+		end.level = level
+	}
+	d.metrics.SetStuckPackets(r.name, chainID, channelID, portID, warning, critical)
+	return key
+}
+
+// stuckPacket returns the pending packet or acknowledgement ps of end, first seen pending at since,
+// with the last attempt at relaying it.
+func (d *stuckPacketDetector) stuckPacket(r *pathRunner, end *stuckEnd, ps pendingSeq, since, now time.Time) admin.StuckPacket {
+	age := now.Sub(since)
+	p := admin.StuckPacket{
+		Path:      end.path,
+		ChainID:   end.chainID,
+		PortID:    end.portID,
+		ChannelID: end.channelID,
+		Sequence:  ps.sequence,
+		Ack:       ps.ack,
+		FirstSeen: since,
+		Age:       age.Round(time.Second).String(),
+		Level:     d.cfg.level(age),
+		Diagnosis: StuckUnattempted,
+	}
+	if a, ok := d.attempts.Last(end.path, end.chainID, end.channelID, end.portID, ps.sequence, ps.ack); ok {
+		at := a.Time
+		p.Attempts, p.LastAttempt, p.MsgType, p.LastError = a.Attempts, &at, a.MsgType, a.Error
+		p.Diagnosis = StuckInFlight
+		if a.Error != "" {
+			p.Diagnosis = StuckFailing
+		}
+	}
+	if r.pauses.isPaused(end.srcPortID, end.srcChannelID) {
+		p.Diagnosis = StuckPaused
+	}
+	return p
+}
+
+// snapshot returns the packets and acknowledgements of the runners stuck for at least minAge, or the threshold
+// if longer, as of the last check, oldest first.
+func (d *stuckPacketDetector) snapshot(runners []*pathRunner, minAge time.Duration) []admin.StuckPacket {
+	if minAge < d.cfg.threshold() {
+		minAge = d.cfg.threshold()
+	}
+	now := time.Now().UTC()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	res := []admin.StuckPacket{}
+	for _, r := range runners {
+		for _, end := range d.ends {
+			if end.path != r.name {
+				continue
+			}
+			for ps, since := range end.firstSeen {
+				if now.Sub(since) >= minAge {
+					res = append(res, d.stuckPacket(r, end, ps, since, now))
+				}
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if !a.FirstSeen.Equal(b.FirstSeen) {
+			return a.FirstSeen.Before(b.FirstSeen)
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		if a.ChannelID != b.ChannelID {
+			return a.ChannelID < b.ChannelID
+		}
+		if a.Sequence != b.Sequence {
+			return a.Sequence < b.Sequence
+		}
+		return !a.Ack && b.Ack
+	})
+	return res
+}
+
+// startStuckPacketDetector queries the packets and acknowledgements pending on every path at the interval of cfg,
+// reporting those pending for longer than its threshold.
+func (s *supervisor) startStuckPacketDetector(ctx context.Context, cfg *StuckPacketsConfig) *stuckPacketDetector {
+	d := newStuckPacketDetector(s.log.With(zap.String("sys", "stuckpackets")), s.metrics, cfg, s.relayAttempts)
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultStuckPacketsInterval
+	}
+	s.maintenance.schedule(ctx, TaskStuckPackets, interval, func(ctx context.Context) {
+		for _, name := range s.names {
+			d.check(ctx, s.runners[name])
+		}
+	})
+	return d
+}
+
+// registerStuckPacketHandlers exposes the stuck packets of each path through the admin API.
+//
+//	GET /stuck-packets lists the packets and acknowledgements pending for longer than the threshold, or the min_age
+//	                   query parameter if longer, on each path or on the path of the path query parameter.
+func registerStuckPacketHandlers(srv *admin.Server, s *supervisor, d *stuckPacketDetector) {
+	srv.HandleFunc("/stuck-packets", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			admin.WriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+			return
+		}
+		runners, err := s.runnersOf(req)
+		if err != nil {
+			admin.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		var minAge time.Duration
+		if v := req.URL.Query().Get("min_age"); v != "" {
+			if minAge, err = time.ParseDuration(v); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid min_age: %w", err))
+				return
+			}
+		}
+		admin.WriteJSON(w, http.StatusOK, d.snapshot(runners, minAge))
+	})
+}
@@ -0,0 +1,88 @@
+package relayer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStuckPacketsConfig(t *testing.T) {
+	var none *StuckPacketsConfig
+	require.NoError(t, none.Validate())
+	require.NoError(t, (&StuckPacketsConfig{Threshold: 10 * time.Minute}).Validate())
+	require.EqualError(t, (&StuckPacketsConfig{Interval: -time.Minute}).Validate(), "stuck-packets durations must not be negative")
+	require.EqualError(t, (&StuckPacketsConfig{CriticalAfter: 10 * time.Minute}).Validate(),
+		"stuck-packets critical-after 10m0s is shorter than the threshold 15m0s")
+
+	c := &StuckPacketsConfig{Threshold: 10 * time.Minute}
+	require.Equal(t, "", c.level(9*time.Minute))
+	require.Equal(t, StuckWarning, c.level(10*time.Minute))
+	require.Equal(t, StuckCritical, c.level(40*time.Minute))
+	c.CriticalAfter = 20 * time.Minute
+	require.Equal(t, StuckCritical, c.level(20*time.Minute))
+}
+
+func TestStuckPacketDetector(t *testing.T) {
+	chains := map[string]*Chain{
+		"hub":     {Chainid: "hub", ChainProvider: &upgradingProvider{chainID: "hub"}},
+		"rollapp": {Chainid: "rollapp", ChainProvider: &upgradingProvider{chainID: "rollapp"}},
+	}
+	paths := []NamedPath{{Name: "hub-rollapp", Path: &Path{
+		Src: &PathEnd{ChainID: "hub", ClientID: "07-tendermint-0"},
+		Dst: &PathEnd{ChainID: "rollapp", ClientID: "07-tendermint-0"},
+	}}}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+	r := s.runners["hub-rollapp"]
+
+	attempts := processor.NewRelayAttempts()
+	d := newStuckPacketDetector(zap.NewNop(), nil, &StuckPacketsConfig{Threshold: 15 * time.Minute}, attempts)
+	start := time.Now().UTC().Add(-2 * time.Hour)
+	record := func(packets, acks []uint64, at time.Duration) string {
+		return d.record(r, "hub", "channel-0", "transfer", "transfer", "channel-0", packets, acks, start.Add(at))
+	}
+
+	// Packets keep the time they were first seen pending until relayed.
+	key := record([]uint64{1, 2}, nil, 0)
+	require.Equal(t, "hub-rollapp/hub/transfer/channel-0", key)
+	require.Equal(t, "", d.ends[key].level)
+	record([]uint64{2, 3}, []uint64{7}, 10*time.Minute)
+	record([]uint64{2, 3}, []uint64{7}, 20*time.Minute)
+	require.Equal(t, StuckWarning, d.ends[key].level)
+	record([]uint64{2, 3}, []uint64{7}, time.Hour)
+	require.Equal(t, StuckCritical, d.ends[key].level)
+
+	attempts.Record(processor.RelayAttempt{
+		Path: "hub-rollapp", ChainID: "hub", ChannelID: "channel-0", PortID: "transfer", Sequence: 2, MsgType: processor.MetricRecvPacket,
+	}, errors.New("out of gas"))
+	attempts.Record(processor.RelayAttempt{
+		Path: "hub-rollapp", ChainID: "hub", ChannelID: "channel-0", PortID: "transfer", Sequence: 7, Ack: true, MsgType: processor.MetricAckPacket,
+	}, nil)
+
+	// Stuck packets are listed oldest first, with the last attempt at relaying them.
+	stuck := d.snapshot([]*pathRunner{r}, 0)
+	require.Len(t, stuck, 3)
+	require.Equal(t, uint64(2), stuck[0].Sequence)
+	require.Equal(t, StuckCritical, stuck[0].Level)
+	require.Equal(t, StuckFailing, stuck[0].Diagnosis)
+	require.Equal(t, "out of gas", stuck[0].LastError)
+	require.Equal(t, uint64(1), stuck[0].Attempts)
+	require.Equal(t, uint64(3), stuck[1].Sequence)
+	require.Equal(t, StuckUnattempted, stuck[1].Diagnosis)
+	require.Equal(t, uint64(7), stuck[2].Sequence)
+	require.True(t, stuck[2].Ack)
+	require.Equal(t, StuckInFlight, stuck[2].Diagnosis)
+	require.Equal(t, processor.MetricAckPacket, stuck[2].MsgType)
+	require.Len(t, d.snapshot([]*pathRunner{r}, 115*time.Minute), 1)
+
+	r.pauses.pause("transfer", "channel-0")
+	require.Equal(t, StuckPaused, d.snapshot([]*pathRunner{r}, 0)[0].Diagnosis)
+
+	record(nil, nil, 2*time.Hour)
+	require.Equal(t, "", d.ends[key].level)
+	require.Empty(t, d.snapshot([]*pathRunner{r}, 0))
+}
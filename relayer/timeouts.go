@@ -0,0 +1,164 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+type timeoutPacketProvider interface {
+	QuerySendPacket(ctx context.Context, srcChanId, srcPortId string, seq uint64) (provider.PacketInfo, error)
+	MsgTimeoutPacket(ctx context.Context, dst provider.ChainProvider, dsth int64, packet provider.PacketInfo, order chantypes.Order, onClose bool) (provider.RelayerMessage, error)
+}
+
+// relayTimeouts relays the timeouts of the packets sent over the channel between src and dst, in both directions,
+// back to the chain they were sent from, for packets which were not received before their timeout height or timestamp
+// elapsed on the receiving chain, or whose receiving channel end was closed.
+// With finalityGating set, packets are only proven unreceived on a rollapp at heights finalized on the settlement layer.
+func relayTimeouts(ctx context.Context, log *zap.Logger, src, dst *Chain, maxTxSize, maxMsgLength uint64, memo string, finalityGating bool, srcChannel *chantypes.IdentifiedChannel) error {
+	srch, dsth, err := QueryLatestHeights(ctx, src, dst)
+	if err != nil {
+		return err
+	}
+	sp := relayedPackets(ctx, src, dst, UnrelayedSequences(ctx, src, dst, srch-1, dsth-1, srcChannel))
+	if sp.Empty() {
+		return nil
+	}
+
+	msgs := &RelayMsgs{
+		MaxTxSize:    maxTxSize,
+		MaxMsgLength: maxMsgLength,
+	}
+	srcProofHeight, dstProofHeight := srch, dsth
+
+	var eg errgroup.Group
+	eg.Go(func() (err error) {
+		msgs.Src, dstProofHeight, err = timeoutMsgs(ctx, src, dst, dsth, sp.Src,
+			srcChannel.ChannelId, srcChannel.PortId, srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId,
+			srcChannel.Ordering, finalityGating)
+		return err
+	})
+	eg.Go(func() (err error) {
+		msgs.Dst, srcProofHeight, err = timeoutMsgs(ctx, dst, src, srch, sp.Dst,
+			srcChannel.Counterparty.ChannelId, srcChannel.Counterparty.PortId, srcChannel.ChannelId, srcChannel.PortId,
+			srcChannel.Ordering, finalityGating)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	if !msgs.Ready() {
+		return nil
+	}
+
+	// The client of each chain must be updated to the height the non-receipt was proven at on the other.
+	if err := PrependUpdateClientMsg(ctx, &msgs.Src, dst, src, dstProofHeight); err != nil {
+		return err
+	}
+	if err := PrependUpdateClientMsg(ctx, &msgs.Dst, src, dst, srcProofHeight); err != nil {
+		return err
+	}
+
+	result := msgs.Send(ctx, log, AsRelayMsgSender(src), AsRelayMsgSender(dst), memo)
+	if result.SuccessfulSrcBatches > 0 || result.SuccessfulDstBatches > 0 {
+		log.Info(
+			"Relayed packet timeouts",
+			zap.String("src_chain_id", src.ChainID()),
+			zap.String("src_channel_id", srcChannel.ChannelId),
+			zap.String("src_port_id", srcChannel.PortId),
+			zap.String("dst_chain_id", dst.ChainID()),
+			zap.String("dst_channel_id", srcChannel.Counterparty.ChannelId),
+			zap.String("dst_port_id", srcChannel.Counterparty.PortId),
+			zap.Object("send_result", result),
+		)
+	}
+	return result.Error()
+}
+
+// timeoutMsgs returns the MsgTimeout, or MsgTimeoutOnClose if the channel end on dst is closed, to send to src
+// for the packets of seqs sent on src which timed out on dst, and the height of dst non-receipt was proven at.
+// Packets which could not be queried are left for the next scan.
+func timeoutMsgs(
+	ctx context.Context,
+	src, dst *Chain,
+	dsth int64,
+	seqs []uint64,
+	srcChanID, srcPortID, dstChanID, dstPortID string,
+	order chantypes.Order,
+	finalityGating bool,
+) ([]provider.RelayerMessage, int64, error) {
+	tp, ok := src.ChainProvider.(timeoutPacketProvider)
+	if !ok || len(seqs) == 0 {
+		return nil, dsth, nil
+	}
+	if finalityGating {
+		h, finalized, err := finalizedQueryHeight(ctx, dst, dsth)
+		if err != nil || !finalized {
+			return nil, dsth, err
+		}
+		dsth = h
+	}
+
+	chanRes, err := dst.ChainProvider.QueryChannel(ctx, dsth, dstChanID, dstPortID)
+	if err != nil {
+		return nil, dsth, err
+	}
+	closed := chanRes.Channel.State == chantypes.CLOSED
+
+	var latest provider.LatestBlock
+	if !closed {
+		blockTime, err := dst.ChainProvider.BlockTime(ctx, dsth)
+		if err != nil {
+			return nil, dsth, err
+		}
+		latest = provider.LatestBlock{Height: uint64(dsth), Time: time.Unix(0, blockTime)}
+	}
+
+	var msgs []provider.RelayerMessage
+	for _, seq := range seqs {
+		packet, err := tp.QuerySendPacket(ctx, srcChanID, srcPortID, seq)
+		if err != nil {
+			src.log.Info(
+				"Failed to query packet for timeout",
+				zap.String("chain_id", src.ChainID()),
+				zap.String("channel_id", srcChanID),
+				zap.String("port_id", srcPortID),
+				zap.Uint64("sequence", seq),
+				zap.Error(err),
+			)
+			continue
+		}
+		if !closed && !packetTimedOut(dst.ChainProvider, packet, latest) {
+			continue
+		}
+		msg, err := tp.MsgTimeoutPacket(ctx, dst.ChainProvider, dsth, packet, order, closed)
+		if err != nil {
+			src.log.Info(
+				"Failed to build packet timeout",
+				zap.String("chain_id", src.ChainID()),
+				zap.String("channel_id", srcChanID),
+				zap.String("port_id", srcPortID),
+				zap.Uint64("sequence", seq),
+				zap.Bool("on_close", closed),
+				zap.Error(err),
+			)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, dsth, nil
+}
+
+// packetTimedOut reports whether the timeout height or timestamp of the packet has elapsed at the latest block of dst,
+// the chain it was sent to.
+func packetTimedOut(dst provider.ChainProvider, packet provider.PacketInfo, latest provider.LatestBlock) bool {
+	err := dst.ValidatePacket(packet, latest)
+	var heightErr *provider.TimeoutHeightError
+	var timestampErr *provider.TimeoutTimestampError
+	return errors.As(err, &heightErr) || errors.As(err, &timestampErr)
+}
@@ -0,0 +1,37 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	cosmosprovider "github.com/cosmos/relayer/v2/relayer/provider/cosmos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketTimedOut(t *testing.T) {
+	dst := &cosmosprovider.CosmosProvider{PCfg: cosmosprovider.CosmosProviderConfig{ChainID: "chain-b-1"}}
+	now := time.Unix(1_700_000_000, 0)
+	latest := provider.LatestBlock{Height: 100, Time: now}
+	packet := func(timeoutHeight uint64, timeoutTimestamp time.Time) provider.PacketInfo {
+		p := provider.PacketInfo{Sequence: 1, Data: []byte("data")}
+		if timeoutHeight > 0 {
+			p.TimeoutHeight = clienttypes.NewHeight(1, timeoutHeight)
+		}
+		if !timeoutTimestamp.IsZero() {
+			p.TimeoutTimestamp = uint64(timeoutTimestamp.UnixNano())
+		}
+		return p
+	}
+
+	require.False(t, packetTimedOut(dst, packet(101, time.Time{}), latest))
+	require.True(t, packetTimedOut(dst, packet(100, time.Time{}), latest), "the timeout height was reached")
+	require.True(t, packetTimedOut(dst, packet(0, now.Add(-time.Second)), latest), "the timeout timestamp elapsed")
+	require.False(t, packetTimedOut(dst, packet(0, now.Add(time.Second)), latest))
+
+	// Packets which can't be relayed for other reasons are not timed out.
+	invalid := packet(100, time.Time{})
+	invalid.Data = nil
+	require.False(t, packetTimedOut(dst, invalid, latest))
+}
@@ -0,0 +1,118 @@
+// Package tracing traces the relayer with OpenTelemetry, exporting its spans to a collector over OTLP,
+// so that the latency of relaying packets can be followed from their detection to the inclusion of their transactions.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// tracerName is the name of the instrumentation of the relayer.
+const tracerName = "github.com/cosmos/relayer/v2"
+
+// defaultServiceName is the service the spans of the relayer are exported for, unless configured otherwise.
+const defaultServiceName = "rly"
+
+// Config configures the export of the spans of the relayer to an OpenTelemetry collector.
+type Config struct {
+	// Endpoint is the host and port of the OTLP gRPC receiver of the collector, e.g. localhost:4317.
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+
+	// Insecure connects to the collector without TLS.
+	Insecure bool `yaml:"insecure,omitempty" json:"insecure,omitempty"`
+
+	// Headers are sent with every export, e.g. to authenticate with the collector.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// ServiceName is the service the spans are exported for, rly by default.
+	ServiceName string `yaml:"service-name,omitempty" json:"service-name,omitempty"`
+
+	// SampleRatio is the fraction of the traces exported, all of them if unset.
+	SampleRatio float64 `yaml:"sample-ratio,omitempty" json:"sample-ratio,omitempty"`
+}
+
+// Validate returns an error if c is set but invalid.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Endpoint == "" {
+		return fmt.Errorf("tracing endpoint is not set")
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample ratio must be between 0 and 1, got %v", c.SampleRatio)
+	}
+	return nil
+}
+
+// Export exports the spans of the relayer, running at the given version, as configured by c,
+// until the returned shutdown is called, which exports the spans left. Export errors are logged to log.
+// Without a config, spans are not recorded and shutdown does nothing.
+func (c *Config) Export(ctx context.Context, log *zap.Logger, version string) (shutdown func(context.Context) error, err error) {
+	if c == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(c.Endpoint)}
+	if c.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(c.Headers))
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter for %s: %w", c.Endpoint, err)
+	}
+
+	serviceName := c.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	ratio := c.SampleRatio
+	if ratio == 0 {
+		ratio = 1
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(version),
+		)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Warn("Failed to export spans", zap.String("endpoint", c.Endpoint), zap.Error(err))
+	}))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Start starts a span named name with the given attributes, as a child of the span of ctx if any.
+// Spans are only recorded once exported, see Config.Export.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span, recording err as its error if non-nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+// recordSpans records the spans started until the end of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return rec
+}
+
+func TestConfigValidate(t *testing.T) {
+	var c *Config
+	require.NoError(t, c.Validate())
+	require.NoError(t, (&Config{Endpoint: "localhost:4317", SampleRatio: 0.1}).Validate())
+	require.Error(t, (&Config{}).Validate())
+	require.Error(t, (&Config{Endpoint: "localhost:4317", SampleRatio: 1.5}).Validate())
+	require.Error(t, (&Config{Endpoint: "localhost:4317", SampleRatio: -1}).Validate())
+}
+
+func TestExportWithoutConfig(t *testing.T) {
+	var c *Config
+	shutdown, err := c.Export(context.Background(), zap.NewNop(), "v2.0.0")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
+
+func TestSpans(t *testing.T) {
+	rec := recordSpans(t)
+
+	ctx, parent := Start(context.Background(), "RelayPackets", attribute.String("src_chain_id", "chain-a"))
+	_, child := Start(ctx, "SendMessages")
+	End(child, errors.New("out of gas"))
+	End(parent, nil)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	sent, relayed := spans[0], spans[1]
+
+	require.Equal(t, "SendMessages", sent.Name())
+	require.Equal(t, relayed.SpanContext().SpanID(), sent.Parent().SpanID())
+	require.Equal(t, codes.Error, sent.Status().Code)
+	require.Equal(t, "out of gas", sent.Status().Description)
+	require.Len(t, sent.Events(), 1, "the error is recorded")
+
+	require.Equal(t, "RelayPackets", relayed.Name())
+	require.Equal(t, codes.Unset, relayed.Status().Code)
+	require.Contains(t, relayed.Attributes(), attribute.String("src_chain_id", "chain-a"))
+}
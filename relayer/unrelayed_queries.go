@@ -0,0 +1,158 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+)
+
+// UnrelayedQueryConcurrency bounds the chunks of sequences checked at once on the counterparty
+// while listing the packets or acknowledgements of a channel waiting to be relayed.
+const UnrelayedQueryConcurrency = 8
+
+// packetCommitmentPager is a chain provider passing the packet commitments of a channel a page at a time,
+// so that the sequences of a page are checked on the counterparty while the next page is queried.
+type packetCommitmentPager interface {
+	QueryPacketCommitmentPages(ctx context.Context, height uint64, channelID, portID string, fn func(page []*chantypes.PacketState) error) error
+}
+
+// listCommitments passes the sequences of the packet commitments of the channel of c at height h to emit,
+// a page at a time if the provider of c streams them.
+func listCommitments(ctx context.Context, c *Chain, channelID, portID string, h int64, emit func(seqs []uint64) error) error {
+	emitPage := func(page []*chantypes.PacketState) error {
+		seqs := make([]uint64, 0, len(page))
+		for _, pc := range page {
+			seqs = append(seqs, pc.Sequence)
+		}
+		return emit(seqs)
+	}
+	if pager, ok := c.ChainProvider.(packetCommitmentPager); ok {
+		return pager.QueryPacketCommitmentPages(ctx, uint64(h), channelID, portID, emitPage)
+	}
+	commitments, err := c.ChainProvider.QueryPacketCommitments(ctx, uint64(h), channelID, portID)
+	if err != nil {
+		return err
+	}
+	return emitPage(commitments)
+}
+
+// sequencePipeline checks the sequences listed by a query on a bounded pool of workers, a chunk at a time,
+// while the query goes on.
+type sequencePipeline struct {
+	chunkSize, concurrency int
+
+	// list passes the sequences to check to emit as they are queried.
+	list func(ctx context.Context, emit func(seqs []uint64) error) error
+
+	// check returns the sequences of a chunk to relay.
+	check func(ctx context.Context, seqs []uint64) ([]uint64, error)
+}
+
+// run returns the sorted sequences to relay. Sequences listed more than once, e.g. by a query retried
+// after some of its pages were checked, are only checked once.
+func (p sequencePipeline) run(ctx context.Context) ([]uint64, error) {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(p.concurrency)
+
+	var (
+		resc   = make(chan []uint64, p.concurrency)
+		seen   = make(map[uint64]bool)
+		chunk  = make([]uint64, 0, p.chunkSize)
+		merged = make(chan []uint64, 1)
+	)
+	go func() {
+		res := []uint64{}
+		for seqs := range resc {
+			res = append(res, seqs...)
+		}
+		merged <- res
+	}()
+
+	dispatch := func() {
+		if len(chunk) == 0 {
+			return
+		}
+		seqs := chunk
+		chunk = make([]uint64, 0, p.chunkSize)
+		g.Go(func() error {
+			res, err := p.check(gctx, seqs)
+			if err != nil {
+				return err
+			}
+			resc <- res
+			return nil
+		})
+	}
+	listErr := p.list(gctx, func(seqs []uint64) error {
+		for _, seq := range seqs {
+			if seen[seq] {
+				continue
+			}
+			seen[seq] = true
+			chunk = append(chunk, seq)
+			if len(chunk) == p.chunkSize {
+				dispatch()
+			}
+		}
+		return gctx.Err()
+	})
+	if listErr == nil {
+		dispatch()
+	}
+	err := g.Wait()
+	close(resc)
+	res := dedupSequences(<-merged)
+	if err == nil {
+		err = listErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// dedupSequences sorts seqs in place and drops the duplicates.
+func dedupSequences(seqs []uint64) []uint64 {
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	res := seqs[:0]
+	for i, seq := range seqs {
+		if i == 0 || seq != seqs[i-1] {
+			res = append(res, seq)
+		}
+	}
+	return res
+}
+
+// unrelayedQueries deduplicates the queries of the sequences waiting to be relayed on a channel,
+// so that concurrent callers, e.g. the flush of a path and the admin API, share the queries in flight.
+var unrelayedQueries singleflight.Group
+
+// sharedUnrelayedQuery runs query once for all the concurrent callers with the same key,
+// each getting its own copy of the sequences. A caller whose shared query was canceled by the context of
+// another caller runs its own query.
+func sharedUnrelayedQuery(ctx context.Context, key []string, query func() ([]uint64, error)) ([]uint64, error) {
+	v, err, shared := unrelayedQueries.Do(strings.Join(key, "/"), func() (any, error) {
+		return query()
+	})
+	if shared && ctx.Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return query()
+	}
+	seqs, _ := v.([]uint64)
+	return append([]uint64{}, seqs...), err
+}
+
+// unrelayedQueryKey identifies the query of the sequences of kind on the channel of src at srch, checked on
+// the channel of dst.
+func unrelayedQueryKey(kind string, src *Chain, srcChannelID, srcPortID string, srch int64, dst *Chain, dstChannelID, dstPortID string, dsth int64) []string {
+	return []string{
+		kind,
+		src.ChainID(), srcPortID, srcChannelID, fmt.Sprint(srch),
+		dst.ChainID(), dstPortID, dstChannelID, fmt.Sprint(dsth),
+	}
+}
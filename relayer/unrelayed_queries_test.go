@@ -0,0 +1,142 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v3/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSequencePipeline(t *testing.T) {
+	ctx := context.Background()
+	var inFlight, maxInFlight, checks int64
+	p := sequencePipeline{
+		chunkSize:   3,
+		concurrency: 2,
+		list: func(_ context.Context, emit func([]uint64) error) error {
+			for _, page := range [][]uint64{{10, 1, 2, 3}, {4, 5, 2}, {6, 7, 8, 9}} {
+				if err := emit(page); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		check: func(_ context.Context, seqs []uint64) ([]uint64, error) {
+			n := atomic.AddInt64(&inFlight, 1)
+			defer atomic.AddInt64(&inFlight, -1)
+			for {
+				max := atomic.LoadInt64(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt64(&maxInFlight, max, n) {
+					break
+				}
+			}
+			atomic.AddInt64(&checks, 1)
+			time.Sleep(5 * time.Millisecond)
+			var odd []uint64
+			for _, seq := range seqs {
+				if seq%2 == 1 {
+					odd = append(odd, seq)
+				}
+			}
+			return odd, nil
+		},
+	}
+
+	// Sequences listed twice are checked once, in chunks of up to chunkSize in the order listed, on up to concurrency workers.
+	seqs, err := p.run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 3, 5, 7, 9}, seqs)
+	require.Equal(t, int64(4), atomic.LoadInt64(&checks))
+	require.LessOrEqual(t, atomic.LoadInt64(&maxInFlight), int64(2))
+
+	// The first failure of a check is returned, rather than the cancellation of the listing it causes.
+	check := p.check
+	p.check = func(ctx context.Context, seqs []uint64) ([]uint64, error) {
+		if seqs[0] == 3 {
+			return nil, errors.New("unreceived packets unavailable")
+		}
+		return check(ctx, seqs)
+	}
+	_, err = p.run(ctx)
+	require.EqualError(t, err, "unreceived packets unavailable")
+
+	p.list = func(context.Context, func([]uint64) error) error { return errors.New("commitments unavailable") }
+	_, err = p.run(ctx)
+	require.EqualError(t, err, "commitments unavailable")
+}
+
+// pagedCommitmentsProvider streams its commitments a page at a time and counts the unreceived packets queried.
+type pagedCommitmentsProvider struct {
+	provider.ChainProvider
+	chainID string
+	pages   [][]uint64
+
+	mu         sync.Mutex
+	pageQuery  chan struct{}
+	unreceived [][]uint64
+}
+
+func (p *pagedCommitmentsProvider) ChainId() string { return p.chainID }
+
+func (p *pagedCommitmentsProvider) QueryPacketCommitmentPages(ctx context.Context, _ uint64, channelID, portID string, fn func([]*chantypes.PacketState) error) error {
+	for _, page := range p.pages {
+		if p.pageQuery != nil {
+			<-p.pageQuery
+		}
+		states := make([]*chantypes.PacketState, 0, len(page))
+		for _, seq := range page {
+			states = append(states, &chantypes.PacketState{PortId: portID, ChannelId: channelID, Sequence: seq})
+		}
+		if err := fn(states); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *pagedCommitmentsProvider) QueryUnreceivedPackets(_ context.Context, _ uint64, _, _ string, seqs []uint64) ([]uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.unreceived = append(p.unreceived, seqs)
+	return seqs, nil
+}
+
+func TestUnrelayedSequencesShared(t *testing.T) {
+	ctx := context.Background()
+	srcp := &pagedCommitmentsProvider{chainID: "chain-a", pages: [][]uint64{{1, 2}, {3}}, pageQuery: make(chan struct{})}
+	dstp := &pagedCommitmentsProvider{chainID: "chain-b"}
+	src := &Chain{log: zap.NewNop(), ChainProvider: srcp}
+	dst := &Chain{log: zap.NewNop(), ChainProvider: dstp}
+
+	// Concurrent callers share the queries in flight.
+	var wg sync.WaitGroup
+	results := make([][]uint64, 2)
+	for i := range results {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = unrelayedSequences(ctx,
+				src, "channel-0", "transfer", 10,
+				dst, "channel-1", "transfer", chantypes.UNORDERED,
+			)
+		}()
+	}
+	// Let both callers join the query before its pages are served.
+	time.Sleep(20 * time.Millisecond)
+	for range srcp.pages {
+		srcp.pageQuery <- struct{}{}
+	}
+	wg.Wait()
+
+	require.Equal(t, []uint64{1, 2, 3}, results[0])
+	require.Equal(t, []uint64{1, 2, 3}, results[1])
+	require.Len(t, dstp.unreceived, 1, "one chunk of sequences checked for both callers")
+}
@@ -0,0 +1,226 @@
+package relayer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	"github.com/cosmos/relayer/v2/relayer/feed"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// upgradePlanQueryInterval is how often the software upgrade scheduled on each chain is queried.
+// The latest height of the chains with an upgrade scheduled is checked at every run of the task.
+const upgradePlanQueryInterval = time.Minute
+
+// upgradePlanQuerier is implemented by the providers of chains scheduling their software upgrades in the upgrade module.
+type upgradePlanQuerier interface {
+	QueryUpgradePlan(ctx context.Context) (*upgradetypes.Plan, error)
+}
+
+// eventSubscriptionRenewer is implemented by the providers subscribing to events over the websocket of their chain.
+type eventSubscriptionRenewer interface {
+	RenewEventSubscriptions()
+}
+
+// ChainUpgrade is the software upgrade scheduled on a chain, as last checked.
+type ChainUpgrade struct {
+	ChainID string `json:"chain_id"`
+	Name    string `json:"name"`
+	Height  int64  `json:"height"`
+
+	// Halted is whether the paths of the chain are paused for the upgrade, from the halt margin before its height
+	// until the chain commits blocks at its height again.
+	Halted       bool       `json:"halted"`
+	HaltedAt     *time.Time `json:"halted_at,omitempty"`
+	LatestHeight int64      `json:"latest_height,omitempty"`
+
+	// Paths are the names of the paths relaying the chain.
+	Paths []string `json:"paths"`
+}
+
+// upgradeWatcher pauses the paths of the chains about to halt for a scheduled software upgrade,
+// and resumes them once the chains are upgraded.
+type upgradeWatcher struct {
+	log *zap.Logger
+
+	// margin is how many blocks before the height of an upgrade the paths of its chain are paused.
+	margin int64
+
+	// runners are the runners of the relayed paths, by path name.
+	runners map[string]*pathRunner
+
+	// chains are the chains of the relayed paths, by chain ID.
+	chains map[string]*Chain
+
+	// changes publishes the paths held and released, if non-nil.
+	changes *changefeed
+
+	mu       sync.Mutex
+	upgrades map[string]*ChainUpgrade
+	// queriedAt is when the upgrade plan of each chain was last queried, only accessed by the task.
+	queriedAt map[string]time.Time
+}
+
+func newUpgradeWatcher(log *zap.Logger, margin int64, runners map[string]*pathRunner, names []string) *upgradeWatcher {
+	w := &upgradeWatcher{
+		log:       log,
+		margin:    margin,
+		runners:   runners,
+		chains:    make(map[string]*Chain),
+		upgrades:  make(map[string]*ChainUpgrade),
+		queriedAt: make(map[string]time.Time),
+	}
+	for _, name := range names {
+		r := runners[name]
+		for _, c := range []*Chain{r.src, r.dst} {
+			if _, ok := c.ChainProvider.(upgradePlanQuerier); !ok {
+				continue
+			}
+			u, ok := w.upgrades[c.ChainID()]
+			if !ok {
+				w.chains[c.ChainID()] = c
+				u = &ChainUpgrade{ChainID: c.ChainID()}
+				w.upgrades[c.ChainID()] = u
+			}
+			u.Paths = append(u.Paths, name)
+		}
+	}
+	return w
+}
+
+// check pauses the paths of the chain with chainID once it is within the halt margin of its scheduled upgrade,
+// and resumes them once it commits blocks at the height of the upgrade, which it does running the upgraded software.
+func (w *upgradeWatcher) check(ctx context.Context, chainID string) {
+	c := w.chains[chainID]
+	w.mu.Lock()
+	u := *w.upgrades[chainID]
+	w.mu.Unlock()
+
+	if !u.Halted && time.Since(w.queriedAt[chainID]) >= upgradePlanQueryInterval {
+		plan, err := c.ChainProvider.(upgradePlanQuerier).QueryUpgradePlan(ctx)
+		if err != nil {
+			w.log.Debug("Failed to query upgrade plan", zap.String("chain_id", chainID), zap.Error(err))
+			return
+		}
+		w.queriedAt[chainID] = time.Now()
+		u.Name, u.Height = "", 0
+		if plan != nil {
+			u.Name, u.Height = plan.Name, plan.Height
+		}
+	}
+	if u.Height == 0 {
+		u.LatestHeight = 0
+		w.set(u)
+		return
+	}
+
+	h, err := c.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		// The endpoints of a halted chain are often unreachable until it restarts with the upgraded software.
+		w.log.Debug("Failed to query latest height of chain scheduling an upgrade", zap.String("chain_id", chainID), zap.Error(err))
+		return
+	}
+	u.LatestHeight = h
+	fields := []zap.Field{
+		zap.String("chain_id", chainID),
+		zap.String("upgrade", u.Name),
+		zap.Int64("upgrade_height", u.Height),
+		zap.Int64("latest_height", h),
+		zap.Strings("paths", u.Paths),
+	}
+	switch {
+	case !u.Halted && h >= u.Height-w.margin:
+		now := time.Now().UTC()
+		u.Halted, u.HaltedAt = true, &now
+		w.log.Warn("Pausing paths ahead of chain upgrade", fields...)
+		w.setPaused(u, true)
+	case u.Halted && h >= u.Height:
+		w.log.Info("Chain upgraded, resuming paths", fields...)
+		w.resume(ctx, c, u)
+		u = ChainUpgrade{ChainID: chainID, Paths: u.Paths}
+		// The next upgrade of the chain is queried at the next run.
+		delete(w.queriedAt, chainID)
+	}
+	w.set(u)
+}
+
+// set records u as the last state of the upgrade of its chain.
+func (w *upgradeWatcher) set(u ChainUpgrade) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	*w.upgrades[u.ChainID] = u
+}
+
+// resume establishes the event subscriptions over the websocket of the upgraded chain c again, updates the clients
+// of its paths on both ends, whose consensus states were not updated while the chain was halted, and resumes the paths.
+func (w *upgradeWatcher) resume(ctx context.Context, c *Chain, u ChainUpgrade) {
+	if es, ok := c.ChainProvider.(eventSubscriptionRenewer); ok {
+		es.RenewEventSubscriptions()
+	}
+	// The finalized states of rollapps settling on the upgraded chain are subscribed to by their own chain.
+	for _, rollapp := range w.chains {
+		if sp, ok := settlementProvider(rollapp.ChainProvider).(interface {
+			ChainId() string
+			eventSubscriptionRenewer
+		}); ok && sp.ChainId() == c.ChainID() {
+			sp.RenewEventSubscriptions()
+		}
+	}
+	for _, name := range u.Paths {
+		r := w.runners[name]
+		pathCtx, log := provider.WithPathName(ctx, r.name), w.log.With(zap.String("path", r.name))
+		// A threshold of 1 updates the clients whatever the time left until they expire.
+		refreshClient(pathCtx, log, r.src, r.dst, 1)
+		refreshClient(pathCtx, log, r.dst, r.src, 1)
+	}
+	w.setPaused(u, false)
+}
+
+// setPaused holds or releases every channel of the paths of the chain upgraded with u.
+func (w *upgradeWatcher) setPaused(u ChainUpgrade, paused bool) {
+	reason := "upgrade " + u.Name + " of " + u.ChainID
+	for _, name := range u.Paths {
+		r := w.runners[name]
+		if paused {
+			if r.pauses.hold(reason) {
+				w.changes.record(feed.Change{Kind: feed.ChangePathHeld, Cause: feed.CauseUpgrade, Path: name, Reason: reason}, name)
+			}
+		} else if r.pauses.release(reason) {
+			w.changes.record(feed.Change{Kind: feed.ChangePathReleased, Cause: feed.CauseUpgrade, Path: name, Reason: reason}, name)
+		}
+	}
+}
+
+// snapshot returns the last state of the upgrades of every chain, by chain ID.
+func (w *upgradeWatcher) snapshot() []ChainUpgrade {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	res := make([]ChainUpgrade, 0, len(w.upgrades))
+	for _, u := range w.upgrades {
+		res = append(res, *u)
+	}
+	sort.Slice(res, func(i, k int) bool { return res[i].ChainID < res[k].ChainID })
+	return res
+}
+
+// startUpgradeWatch checks at the given interval the software upgrades scheduled on the chains of every path,
+// pausing the paths of a chain from margin blocks before the height of its upgrade until it is upgraded.
+func (s *supervisor) startUpgradeWatch(ctx context.Context, interval time.Duration, margin int64) *upgradeWatcher {
+	w := newUpgradeWatcher(s.log.With(zap.String("sys", "upgradewatch")), margin, s.runners, s.names)
+	w.changes = s.changes
+	chainIDs := make([]string, 0, len(w.chains))
+	for chainID := range w.chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+	s.maintenance.schedule(ctx, TaskUpgradeWatch, interval, func(ctx context.Context) {
+		for _, chainID := range chainIDs {
+			w.check(ctx, chainID)
+		}
+	})
+	return w
+}
@@ -0,0 +1,97 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	ibcexported "github.com/cosmos/ibc-go/v3/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// upgradingProvider serves the upgrade scheduled on a chain and its latest height.
+type upgradingProvider struct {
+	provider.ChainProvider
+	chainID string
+	plan    *upgradetypes.Plan
+	height  int64
+	halted  bool
+	renewed int
+}
+
+func (p *upgradingProvider) ChainId() string { return p.chainID }
+
+func (p *upgradingProvider) QueryUpgradePlan(context.Context) (*upgradetypes.Plan, error) {
+	return p.plan, nil
+}
+
+func (p *upgradingProvider) QueryLatestHeight(context.Context) (int64, error) {
+	if p.halted {
+		return 0, errors.New("connection refused")
+	}
+	return p.height, nil
+}
+
+func (p *upgradingProvider) QueryClientState(context.Context, int64, string) (ibcexported.ClientState, error) {
+	return nil, errors.New("client not found")
+}
+
+func (p *upgradingProvider) RenewEventSubscriptions() { p.renewed++ }
+
+func TestUpgradeWatcher(t *testing.T) {
+	hub := &upgradingProvider{chainID: "hub", height: 90}
+	rollapp := &upgradingProvider{chainID: "rollapp", height: 10}
+	chains := map[string]*Chain{"hub": {Chainid: "hub", ChainProvider: hub}, "rollapp": {Chainid: "rollapp", ChainProvider: rollapp}}
+	paths := []NamedPath{{Name: "hub-rollapp", Path: &Path{
+		Src: &PathEnd{ChainID: "hub", ClientID: "07-tendermint-0"},
+		Dst: &PathEnd{ChainID: "rollapp", ClientID: "07-tendermint-0"},
+	}}}
+	s, err := newSupervisor(zap.NewNop(), chains, paths, 0, 0, "", ProcessorEvents, 0)
+	require.NoError(t, err)
+	r := s.runners["hub-rollapp"]
+	ctx := context.Background()
+
+	w := newUpgradeWatcher(zap.NewNop(), 5, s.runners, s.names)
+	require.Equal(t, []ChainUpgrade{
+		{ChainID: "hub", Paths: []string{"hub-rollapp"}},
+		{ChainID: "rollapp", Paths: []string{"hub-rollapp"}},
+	}, w.snapshot())
+
+	// The path is relayed until the hub is within the halt margin of its upgrade.
+	hub.plan = &upgradetypes.Plan{Name: "v2", Height: 100}
+	w.check(ctx, "hub")
+	require.False(t, r.pauses.isPaused("transfer", "channel-0"), "plan not queried again yet")
+	delete(w.queriedAt, "hub")
+	w.check(ctx, "hub")
+	require.False(t, r.pauses.isPaused("transfer", "channel-0"))
+	require.Equal(t, int64(100), w.snapshot()[0].Height)
+
+	hub.height = 95
+	w.check(ctx, "hub")
+	require.True(t, r.pauses.isPaused("transfer", "channel-0"))
+	require.True(t, w.snapshot()[0].Halted)
+
+	// The path stays paused while the hub is halted, and until it commits the block at the height of the upgrade.
+	hub.height, hub.halted = 99, true
+	w.check(ctx, "hub")
+	hub.halted = false
+	w.check(ctx, "hub")
+	require.True(t, r.pauses.isPaused("transfer", "channel-0"))
+	require.Zero(t, hub.renewed)
+
+	hub.plan, hub.height = nil, 100
+	w.check(ctx, "hub")
+	require.False(t, r.pauses.isPaused("transfer", "channel-0"))
+	require.Equal(t, 1, hub.renewed)
+	require.Equal(t, ChainUpgrade{ChainID: "hub", Paths: []string{"hub-rollapp"}}, w.snapshot()[0])
+
+	// The next upgrade is queried at the next run.
+	hub.plan = &upgradetypes.Plan{Name: "v3", Height: 102}
+	w.check(ctx, "hub")
+	require.True(t, r.pauses.isPaused("transfer", "channel-0"))
+	_, holds := r.pauses.snapshot()
+	require.Equal(t, []string{"upgrade v3 of hub"}, holds)
+}
@@ -0,0 +1,374 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// Defaults of the wallet monitor.
+const (
+	defaultWalletMonitorInterval = time.Minute
+	defaultWalletTopUpCooldown   = time.Hour
+
+	// walletCheckTimeout bounds the balance query, and the top-up, of each wallet.
+	walletCheckTimeout = 30 * time.Second
+)
+
+// Statuses of the top-ups of the wallet monitor.
+const (
+	TopUpSent   = "sent"
+	TopUpFailed = "failed"
+)
+
+// WalletMonitorConfig configures the monitor of the balances of the signing keys of the chains relayed by `rly start`,
+// alerting when a wallet runs low and topping it up from a funding account if configured.
+type WalletMonitorConfig struct {
+	// Interval is how often the balances are checked, every minute if unset.
+	Interval time.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+
+	Wallets []WalletThreshold `yaml:"wallets" json:"wallets"`
+
+	// Webhooks are posted a WalletAlert every time a wallet falls below its threshold, is topped up, or recovers.
+	Webhooks []string `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+}
+
+// WalletThreshold is the balance of a denom the wallet of the signing key of a chain should keep.
+type WalletThreshold struct {
+	// ChainID is the chain of the wallet, whose configured key is monitored.
+	ChainID string `yaml:"chain-id" json:"chain-id"`
+
+	Denom string `yaml:"denom" json:"denom"`
+
+	// MinBalance is the amount of Denom below which the wallet is low.
+	MinBalance string `yaml:"min-balance" json:"min-balance"`
+
+	// TopUp, if set, refills the wallet from a funding account while it is low.
+	TopUp *WalletTopUp `yaml:"top-up,omitempty" json:"top-up,omitempty"`
+}
+
+// WalletTopUp is a bank send from a funding account whose key is in the keyring of the chain to the relayer wallet.
+type WalletTopUp struct {
+	// FromKey is the name of the key of the funding account in the keyring of the chain.
+	FromKey string `yaml:"from-key" json:"from-key"`
+
+	// Amount is sent on every top-up, e.g. 5000000urax.
+	Amount string `yaml:"amount" json:"amount"`
+
+	// Cooldown is the minimum time between two top-ups of the wallet, one hour if unset, so that a wallet drained
+	// faster than it is refilled does not drain the funding account as well.
+	Cooldown time.Duration `yaml:"cooldown,omitempty" json:"cooldown,omitempty"`
+}
+
+// Validate checks the wallet monitor config, chains being the chains of the config the wallets are on.
+func (c *WalletMonitorConfig) Validate(chains Chains) error {
+	if c == nil {
+		return nil
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("wallet monitor interval must not be negative")
+	}
+	for _, w := range c.Wallets {
+		if err := w.validate(chains); err != nil {
+			return fmt.Errorf("wallet %s of %s: %w", w.Denom, w.ChainID, err)
+		}
+	}
+	return nil
+}
+
+func (w WalletThreshold) validate(chains Chains) error {
+	if _, err := chains.Get(w.ChainID); err != nil {
+		return err
+	}
+	if err := sdk.ValidateDenom(w.Denom); err != nil {
+		return err
+	}
+	if _, err := w.minBalance(); err != nil {
+		return err
+	}
+	if w.TopUp == nil {
+		return nil
+	}
+	if w.TopUp.FromKey == "" {
+		return errors.New("top-up without from-key")
+	}
+	if _, err := w.TopUp.amount(); err != nil {
+		return err
+	}
+	if w.TopUp.Cooldown < 0 {
+		return errors.New("top-up cooldown must not be negative")
+	}
+	return nil
+}
+
+func (w WalletThreshold) minBalance() (sdk.Int, error) {
+	min, ok := sdk.NewIntFromString(w.MinBalance)
+	if !ok || min.IsNegative() {
+		return sdk.Int{}, fmt.Errorf("invalid min-balance %q", w.MinBalance)
+	}
+	return min, nil
+}
+
+func (t WalletTopUp) amount() (sdk.Coins, error) {
+	amount, err := sdk.ParseCoinsNormalized(t.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid top-up amount %q: %w", t.Amount, err)
+	}
+	if amount.IsZero() {
+		return nil, fmt.Errorf("invalid top-up amount %q: must be positive", t.Amount)
+	}
+	return amount, nil
+}
+
+// walletFunder is implemented by the providers of chains sending funds from another key of their keyring.
+type walletFunder interface {
+	SendFromKey(ctx context.Context, fromKey, toAddress string, amount sdk.Coins, memo string) (*provider.RelayerTxResponse, error)
+}
+
+// WalletStatus is the state of a monitored wallet as last checked.
+type WalletStatus struct {
+	ChainID    string `json:"chain_id"`
+	Address    string `json:"address"`
+	Denom      string `json:"denom"`
+	Balance    string `json:"balance"`
+	MinBalance string `json:"min_balance"`
+
+	Low      bool       `json:"low"`
+	LowSince *time.Time `json:"low_since,omitempty"`
+
+	LastTopUp *WalletTopUpResult `json:"last_top_up,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	CheckedAt time.Time          `json:"checked_at"`
+}
+
+// WalletTopUpResult is a top-up of a wallet from its funding account.
+type WalletTopUpResult struct {
+	FromKey string    `json:"from_key"`
+	Amount  string    `json:"amount"`
+	Status  string    `json:"status"`
+	TxHash  string    `json:"tx_hash,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// WalletAlert is posted to the webhooks of the wallet monitor when a wallet falls below its threshold,
+// is topped up, or recovers.
+type WalletAlert struct {
+	Resolved bool               `json:"resolved"`
+	Wallet   WalletStatus       `json:"wallet"`
+	TopUp    *WalletTopUpResult `json:"top_up,omitempty"`
+}
+
+// walletMonitor checks the balances of the wallets, alerting and topping them up while they are low.
+type walletMonitor struct {
+	log      *zap.Logger
+	metrics  *processor.PrometheusMetrics
+	webhooks []string
+
+	// readOnly disables the top-ups, the relayer sending no transaction.
+	readOnly bool
+
+	mu       sync.Mutex
+	statuses map[walletKey]WalletStatus
+}
+
+type walletKey struct {
+	chainID, denom string
+}
+
+func newWalletMonitor(log *zap.Logger, metrics *processor.PrometheusMetrics, webhooks []string, readOnly bool) *walletMonitor {
+	return &walletMonitor{
+		log:      log,
+		metrics:  metrics,
+		webhooks: webhooks,
+		readOnly: readOnly,
+		statuses: make(map[walletKey]WalletStatus),
+	}
+}
+
+// check checks the balance of the wallet of c against w, topping it up if it is low and the cooldown has passed.
+func (wm *walletMonitor) check(ctx context.Context, c *Chain, w WalletThreshold) {
+	min, err := w.minBalance()
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC()
+	k := walletKey{chainID: w.ChainID, denom: w.Denom}
+
+	wm.mu.Lock()
+	prev, ok := wm.statuses[k]
+	wm.mu.Unlock()
+	if !ok {
+		prev = WalletStatus{ChainID: w.ChainID, Denom: w.Denom, MinBalance: min.String()}
+	}
+	st := prev
+	st.CheckedAt = now
+
+	balance, err := wm.queryBalance(ctx, c, &st)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		st.Error = err.Error()
+		wm.store(k, st)
+		wm.log.Warn("Failed to check wallet balance", zap.String("chain_id", w.ChainID), zap.String("denom", w.Denom), zap.Error(err))
+		return
+	}
+	st.Balance, st.Error = balance.String(), ""
+	st.Low = balance.LT(min)
+	if !st.Low {
+		st.LowSince = nil
+	} else if !prev.Low {
+		st.LowSince = &now
+	}
+	if amount, err := balance.ToDec().Float64(); err == nil {
+		wm.metrics.SetWalletBalance(w.ChainID, st.Address, w.Denom, amount)
+	}
+	wm.metrics.SetWalletLowBalance(w.ChainID, st.Address, w.Denom, st.Low)
+
+	var topUp *WalletTopUpResult
+	if st.Low && w.TopUp != nil && wm.topUpDue(prev, *w.TopUp, now) {
+		topUp = wm.topUp(ctx, c, st, *w.TopUp, now)
+		st.LastTopUp = topUp
+	}
+	wm.store(k, st)
+
+	fields := []zap.Field{
+		zap.String("chain_id", w.ChainID),
+		zap.String("address", st.Address),
+		zap.String("denom", w.Denom),
+		zap.String("balance", st.Balance),
+		zap.String("min_balance", st.MinBalance),
+	}
+	switch {
+	case st.Low && !prev.Low:
+		wm.log.Warn("Wallet balance below threshold", fields...)
+	case !st.Low && prev.Low:
+		wm.log.Info("Wallet balance recovered", fields...)
+	case topUp == nil:
+		return
+	}
+	if err := postWebhooks(ctx, wm.webhooks, WalletAlert{Resolved: !st.Low, Wallet: st, TopUp: topUp}); err != nil {
+		wm.log.Warn("Failed to post wallet alert", zap.String("chain_id", w.ChainID), zap.String("denom", w.Denom), zap.Error(err))
+	}
+}
+
+// queryBalance returns the balance of the denom of st in the wallet of the key of c, setting the address of st.
+func (wm *walletMonitor) queryBalance(ctx context.Context, c *Chain, st *WalletStatus) (sdk.Int, error) {
+	address, err := c.ChainProvider.Address()
+	if err != nil {
+		return sdk.Int{}, err
+	}
+	st.Address = address
+	coins, err := c.ChainProvider.QueryBalance(ctx, c.ChainProvider.Key())
+	if err != nil {
+		return sdk.Int{}, err
+	}
+	return coins.AmountOf(st.Denom), nil
+}
+
+// topUpDue returns whether the wallet, last checked as prev, can be topped up at now.
+func (wm *walletMonitor) topUpDue(prev WalletStatus, t WalletTopUp, now time.Time) bool {
+	if wm.readOnly {
+		return false
+	}
+	cooldown := t.Cooldown
+	if cooldown == 0 {
+		cooldown = defaultWalletTopUpCooldown
+	}
+	return prev.LastTopUp == nil || now.Sub(prev.LastTopUp.At) >= cooldown
+}
+
+// topUp sends the amount of t from the funding account of t to the wallet st of c.
+func (wm *walletMonitor) topUp(ctx context.Context, c *Chain, st WalletStatus, t WalletTopUp, now time.Time) *WalletTopUpResult {
+	res := &WalletTopUpResult{FromKey: t.FromKey, Amount: t.Amount, Status: TopUpFailed, At: now}
+	fields := []zap.Field{
+		zap.String("chain_id", st.ChainID),
+		zap.String("address", st.Address),
+		zap.String("from_key", t.FromKey),
+		zap.String("amount", t.Amount),
+	}
+
+	err := func() error {
+		funder, ok := c.ChainProvider.(walletFunder)
+		if !ok {
+			return fmt.Errorf("chain %s does not support topping up wallets", st.ChainID)
+		}
+		amount, err := t.amount()
+		if err != nil {
+			return err
+		}
+		tx, err := funder.SendFromKey(ctx, t.FromKey, st.Address, amount, "")
+		if err != nil {
+			return err
+		}
+		res.TxHash = tx.TxHash
+		return nil
+	}()
+	if err != nil {
+		res.Error = err.Error()
+		wm.metrics.IncWalletTopUps(st.ChainID, st.Address, st.Denom, TopUpFailed)
+		wm.log.Error("Failed to top up wallet", append(fields, zap.Error(err))...)
+		return res
+	}
+	res.Status = TopUpSent
+	wm.metrics.IncWalletTopUps(st.ChainID, st.Address, st.Denom, TopUpSent)
+	wm.log.Warn("Topped up wallet from funding account", append(fields, zap.String("tx_hash", res.TxHash))...)
+	return res
+}
+
+func (wm *walletMonitor) store(k walletKey, st WalletStatus) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.statuses[k] = st
+}
+
+// snapshot returns the last state of every monitored wallet, sorted by chain ID and denom.
+func (wm *walletMonitor) snapshot() []WalletStatus {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	res := make([]WalletStatus, 0, len(wm.statuses))
+	for _, st := range wm.statuses {
+		res = append(res, st)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].ChainID != res[j].ChainID {
+			return res[i].ChainID < res[j].ChainID
+		}
+		return res[i].Denom < res[j].Denom
+	})
+	return res
+}
+
+// startWalletMonitor starts checking the wallets of cfg on the relayed chains, the wallets of other chains being skipped.
+func (s *supervisor) startWalletMonitor(ctx context.Context, cfg *WalletMonitorConfig) *walletMonitor {
+	wm := newWalletMonitor(s.log.With(zap.String("sys", "walletmonitor")), s.metrics, cfg.Webhooks, s.readOnly)
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultWalletMonitorInterval
+	}
+	chains := make(map[string]*Chain)
+	for _, c := range s.chains() {
+		chains[c.ChainID()] = c
+	}
+	s.maintenance.schedule(ctx, TaskWalletMonitor, interval, func(ctx context.Context) {
+		for _, w := range cfg.Wallets {
+			c, ok := chains[w.ChainID]
+			if !ok {
+				continue
+			}
+			checkCtx, cancel := context.WithTimeout(ctx, walletCheckTimeout)
+			wm.check(checkCtx, c, w)
+			cancel()
+		}
+	})
+	return wm
+}
@@ -0,0 +1,124 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// walletProvider serves the balance of the relayer wallet, topped up by the sends from a funding key.
+type walletProvider struct {
+	provider.ChainProvider
+	balance sdk.Coins
+	sends   []string
+}
+
+func (p *walletProvider) ChainId() string { return "hub" }
+
+func (p *walletProvider) Key() string { return "relayer" }
+
+func (p *walletProvider) Address() (string, error) { return "dym1relayer", nil }
+
+func (p *walletProvider) QueryBalance(context.Context, string) (sdk.Coins, error) {
+	return p.balance, nil
+}
+
+func (p *walletProvider) SendFromKey(_ context.Context, fromKey, to string, amount sdk.Coins, _ string) (*provider.RelayerTxResponse, error) {
+	p.sends = append(p.sends, fromKey+">"+to+":"+amount.String())
+	return &provider.RelayerTxResponse{TxHash: "A1B2"}, nil
+}
+
+func TestWalletMonitorConfigValidate(t *testing.T) {
+	chains := Chains{"hub": NewChain(zap.NewNop(), &walletProvider{}, false)}
+	valid := WalletThreshold{ChainID: "hub", Denom: "urax", MinBalance: "1000", TopUp: &WalletTopUp{FromKey: "funding", Amount: "5000urax"}}
+	require.NoError(t, (&WalletMonitorConfig{Wallets: []WalletThreshold{valid}}).Validate(chains))
+	var none *WalletMonitorConfig
+	require.NoError(t, none.Validate(chains))
+
+	w := valid
+	w.ChainID = "osmosis-1"
+	require.EqualError(t, (&WalletMonitorConfig{Wallets: []WalletThreshold{w}}).Validate(chains),
+		"wallet urax of osmosis-1: chain with ID osmosis-1 is not configured")
+	w = valid
+	w.MinBalance = "-1"
+	require.EqualError(t, (&WalletMonitorConfig{Wallets: []WalletThreshold{w}}).Validate(chains),
+		`wallet urax of hub: invalid min-balance "-1"`)
+	w = valid
+	w.TopUp = &WalletTopUp{Amount: "5000urax"}
+	require.EqualError(t, (&WalletMonitorConfig{Wallets: []WalletThreshold{w}}).Validate(chains),
+		"wallet urax of hub: top-up without from-key")
+}
+
+func TestWalletMonitorTopsUpLowWallets(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		alerts []WalletAlert
+	)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var a WalletAlert
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&a))
+		mu.Lock()
+		alerts = append(alerts, a)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	p := &walletProvider{balance: sdk.NewCoins(sdk.NewInt64Coin("urax", 2000))}
+	c := NewChain(zap.NewNop(), p, false)
+	w := WalletThreshold{ChainID: "hub", Denom: "urax", MinBalance: "1000", TopUp: &WalletTopUp{FromKey: "funding", Amount: "5000urax"}}
+	wm := newWalletMonitor(zap.NewNop(), nil, []string{webhook.URL}, false)
+	ctx := context.Background()
+
+	wm.check(ctx, c, w)
+	require.Equal(t, []WalletStatus{{
+		ChainID: "hub", Address: "dym1relayer", Denom: "urax", Balance: "2000", MinBalance: "1000",
+		CheckedAt: wm.snapshot()[0].CheckedAt,
+	}}, wm.snapshot())
+
+	// A low wallet is topped up once per cooldown.
+	p.balance = sdk.NewCoins(sdk.NewInt64Coin("urax", 999))
+	wm.check(ctx, c, w)
+	wm.check(ctx, c, w)
+	require.Equal(t, []string{"funding>dym1relayer:5000urax"}, p.sends)
+	st := wm.snapshot()[0]
+	require.True(t, st.Low)
+	require.NotNil(t, st.LowSince)
+	require.Equal(t, TopUpSent, st.LastTopUp.Status)
+	require.Equal(t, "A1B2", st.LastTopUp.TxHash)
+
+	// The cooldown elapsed, the wallet is topped up again.
+	wm.statuses[walletKey{chainID: "hub", denom: "urax"}].LastTopUp.At = time.Now().Add(-2 * time.Hour)
+	wm.check(ctx, c, w)
+	require.Len(t, p.sends, 2)
+
+	p.balance = sdk.NewCoins(sdk.NewInt64Coin("urax", 10000))
+	wm.check(ctx, c, w)
+	require.False(t, wm.snapshot()[0].Low)
+	require.Nil(t, wm.snapshot()[0].LowSince)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, alerts, 3)
+	require.False(t, alerts[0].Resolved)
+	require.Equal(t, "999", alerts[0].Wallet.Balance)
+	require.Equal(t, TopUpSent, alerts[0].TopUp.Status)
+	require.NotNil(t, alerts[1].TopUp)
+	require.True(t, alerts[2].Resolved)
+	require.Nil(t, alerts[2].TopUp)
+
+	// Read-only relayers only alert.
+	ro := newWalletMonitor(zap.NewNop(), nil, nil, true)
+	p.balance, p.sends = nil, nil
+	ro.check(ctx, c, w)
+	require.True(t, ro.snapshot()[0].Low)
+	require.Empty(t, p.sends)
+}
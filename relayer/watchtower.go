@@ -0,0 +1,292 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+// Defaults of the watchtower.
+const (
+	defaultWatchtowerInterval        = time.Minute
+	defaultWatchtowerExpiryThreshold = 1.0 / 3
+)
+
+// Kinds of the alerts raised by the watchtower on a client.
+const (
+	WatchtowerAlertFrozen   = "frozen"
+	WatchtowerAlertExpired  = "expired"
+	WatchtowerAlertExpiring = "expiring"
+	WatchtowerAlertStalled  = "stalled"
+	WatchtowerAlertMismatch = "chain_id_mismatch"
+)
+
+// WatchtowerConfig configures the watchtower, which monitors the clients of rollapps hosted on a hub without relaying,
+// so without keys, and alerts when they are frozen, about to expire or no longer updated.
+type WatchtowerConfig struct {
+	// Hub is the name of the chain hosting the clients in the config.
+	Hub string `yaml:"hub" json:"hub"`
+
+	Clients []WatchedClient `yaml:"clients" json:"clients"`
+
+	// CheckInterval is how often the clients are checked, every minute if unset.
+	CheckInterval time.Duration `yaml:"check-interval,omitempty" json:"check-interval,omitempty"`
+
+	// ExpiryThreshold is the fraction of the trusting period of a client left before it expires below which to alert,
+	// a third if unset.
+	ExpiryThreshold float64 `yaml:"expiry-threshold,omitempty" json:"expiry-threshold,omitempty"`
+
+	// StallTimeout is how long the latest height of a client may stay the same before alerting, 0 to never alert on it.
+	StallTimeout time.Duration `yaml:"stall-timeout,omitempty" json:"stall-timeout,omitempty"`
+
+	// Webhooks are posted every alert raised and resolved, as a WatchtowerAlert.
+	Webhooks []string `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+}
+
+// WatchedClient is a client of a rollapp hosted on the hub of the watchtower.
+type WatchedClient struct {
+	ClientID string `yaml:"client-id" json:"client-id"`
+
+	// ChainID is the chain ID of the rollapp the client is expected to track, verified if set.
+	ChainID string `yaml:"chain-id,omitempty" json:"chain-id,omitempty"`
+}
+
+// Validate checks the watchtower config.
+func (c *WatchtowerConfig) Validate() error {
+	if c.Hub == "" {
+		return fmt.Errorf("watchtower hub is not set")
+	}
+	if len(c.Clients) == 0 {
+		return fmt.Errorf("watchtower has no clients to watch")
+	}
+	seen := make(map[string]bool, len(c.Clients))
+	for _, wc := range c.Clients {
+		if wc.ClientID == "" {
+			return fmt.Errorf("watched client without client-id")
+		}
+		if seen[wc.ClientID] {
+			return fmt.Errorf("client %s is watched more than once", wc.ClientID)
+		}
+		seen[wc.ClientID] = true
+	}
+	if c.CheckInterval < 0 || c.StallTimeout < 0 {
+		return fmt.Errorf("watchtower check-interval and stall-timeout must not be negative")
+	}
+	if c.ExpiryThreshold < 0 || c.ExpiryThreshold >= 1 {
+		return fmt.Errorf("watchtower expiry-threshold must be between 0 and 1, got %v", c.ExpiryThreshold)
+	}
+	return nil
+}
+
+// WatchedClientStatus is the state of a watched client as last checked by the watchtower.
+type WatchedClientStatus struct {
+	ChainID       string     `json:"chain_id"`
+	ClientID      string     `json:"client_id"`
+	ClientChainID string     `json:"client_chain_id,omitempty"`
+	LatestHeight  string     `json:"latest_height,omitempty"`
+	FrozenHeight  string     `json:"frozen_height,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+
+	// UpdatedAt is when the latest height of the client was first observed.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Alerts are the alerts raised on the client, by kind.
+	Alerts    map[string]string `json:"alerts,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// WatchtowerAlert is posted to the webhooks of the watchtower when an alert is raised on a client, or resolved.
+type WatchtowerAlert struct {
+	Kind     string              `json:"kind"`
+	Message  string              `json:"message"`
+	Resolved bool                `json:"resolved"`
+	Client   WatchedClientStatus `json:"client"`
+}
+
+// Watchtower monitors the clients of rollapps hosted on a hub, see WatchtowerConfig.
+type Watchtower struct {
+	log     *zap.Logger
+	hub     *Chain
+	cfg     WatchtowerConfig
+	metrics *processor.PrometheusMetrics
+
+	// statuses are the last checks of the clients, by client ID.
+	statuses map[string]WatchedClientStatus
+}
+
+// NewWatchtower returns a watchtower of the clients configured by cfg on hub, which only needs querying,
+// recording its checks to metrics if non-nil.
+func NewWatchtower(log *zap.Logger, hub *Chain, cfg WatchtowerConfig, metrics *processor.PrometheusMetrics) (*Watchtower, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.CheckInterval == 0 {
+		cfg.CheckInterval = defaultWatchtowerInterval
+	}
+	if cfg.ExpiryThreshold == 0 {
+		cfg.ExpiryThreshold = defaultWatchtowerExpiryThreshold
+	}
+	return &Watchtower{
+		log:      log,
+		hub:      hub,
+		cfg:      cfg,
+		metrics:  metrics,
+		statuses: make(map[string]WatchedClientStatus),
+	}, nil
+}
+
+// RunWatchtower runs a watchtower of the clients configured by cfg on hub until ctx is done,
+// serving its metrics on metricsListener if non-nil.
+func RunWatchtower(ctx context.Context, log *zap.Logger, hub *Chain, cfg WatchtowerConfig, metricsListener net.Listener) error {
+	var metrics *processor.PrometheusMetrics
+	if metricsListener != nil {
+		metrics = processor.NewPrometheusMetrics()
+		serveMetrics(ctx, log.With(zap.String("sys", "metricshttp")), metrics, metricsListener)
+	}
+	w, err := NewWatchtower(log.With(zap.String("sys", "watchtower")), hub, cfg, metrics)
+	if err != nil {
+		return err
+	}
+	w.Run(ctx)
+	return nil
+}
+
+// Run checks the watched clients at the check interval, until ctx is done.
+func (w *Watchtower) Run(ctx context.Context) {
+	w.log.Info(
+		"Watching clients",
+		zap.String("chain_id", w.hub.ChainID()),
+		zap.Int("clients", len(w.cfg.Clients)),
+		zap.Duration("check_interval", w.cfg.CheckInterval),
+	)
+	ticker := time.NewTicker(w.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		for _, wc := range w.cfg.Clients {
+			w.check(ctx, wc)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check queries the state of a watched client, raising and resolving its alerts.
+func (w *Watchtower) check(ctx context.Context, wc WatchedClient) {
+	now := time.Now().UTC()
+	tmcs, latestTimestamp, err := queryTendermintClient(ctx, w.hub, wc.ClientID)
+	if err != nil {
+		w.log.Warn(
+			"Failed to query watched client",
+			zap.String("chain_id", w.hub.ChainID()),
+			zap.String("client_id", wc.ClientID),
+			zap.Error(err),
+		)
+		// the alerts of the client are kept until it can be checked again
+		st := w.statuses[wc.ClientID]
+		st.ChainID, st.ClientID, st.Error, st.CheckedAt = w.hub.ChainID(), wc.ClientID, err.Error(), now
+		w.statuses[wc.ClientID] = st
+		return
+	}
+
+	prev, ok := w.statuses[wc.ClientID]
+
+	st := watchedClientStatus(w.hub.ChainID(), wc, tmcs, latestTimestamp, now)
+	st.UpdatedAt = now
+	if ok && prev.LatestHeight == st.LatestHeight && !prev.UpdatedAt.IsZero() {
+		st.UpdatedAt = prev.UpdatedAt
+	}
+	st.Alerts = watchedClientAlerts(st, wc, tmcs, w.cfg, now)
+
+	w.notify(ctx, prev, st)
+	w.metrics.SetWatchedClient(st.ChainID, st.ClientID, len(st.Alerts) > 0, st.ExpiresAt.Sub(now))
+
+	w.statuses[wc.ClientID] = st
+}
+
+// watchedClientStatus returns the status of the watched tendermint client tmcs, whose latest consensus state
+// is at latestTimestamp, without its alerts.
+func watchedClientStatus(chainID string, wc WatchedClient, tmcs *tmclient.ClientState, latestTimestamp, now time.Time) WatchedClientStatus {
+	expiresAt := latestTimestamp.Add(tmcs.TrustingPeriod).UTC()
+	st := WatchedClientStatus{
+		ChainID:       chainID,
+		ClientID:      wc.ClientID,
+		ClientChainID: tmcs.ChainId,
+		LatestHeight:  tmcs.LatestHeight.String(),
+		ExpiresAt:     &expiresAt,
+		CheckedAt:     now,
+	}
+	if !tmcs.FrozenHeight.IsZero() {
+		st.FrozenHeight = tmcs.FrozenHeight.String()
+	}
+	return st
+}
+
+// watchedClientAlerts returns the alerts to raise on a watched client with status st, by kind.
+func watchedClientAlerts(st WatchedClientStatus, wc WatchedClient, tmcs *tmclient.ClientState, cfg WatchtowerConfig, now time.Time) map[string]string {
+	alerts := make(map[string]string)
+	if st.FrozenHeight != "" {
+		alerts[WatchtowerAlertFrozen] = fmt.Sprintf("client is frozen at height %s", st.FrozenHeight)
+	}
+	left := st.ExpiresAt.Sub(now)
+	switch {
+	case left <= 0:
+		alerts[WatchtowerAlertExpired] = fmt.Sprintf("client expired at %s", st.ExpiresAt.Format(time.RFC3339))
+	case float64(left) < cfg.ExpiryThreshold*float64(tmcs.TrustingPeriod):
+		alerts[WatchtowerAlertExpiring] = fmt.Sprintf("client expires at %s, in %s", st.ExpiresAt.Format(time.RFC3339), left.Round(time.Second))
+	}
+	if cfg.StallTimeout > 0 && now.Sub(st.UpdatedAt) > cfg.StallTimeout {
+		alerts[WatchtowerAlertStalled] = fmt.Sprintf("client has been at height %s since %s", st.LatestHeight, st.UpdatedAt.Format(time.RFC3339))
+	}
+	if wc.ChainID != "" && st.ClientChainID != wc.ChainID {
+		alerts[WatchtowerAlertMismatch] = fmt.Sprintf("client tracks chain %s instead of %s", st.ClientChainID, wc.ChainID)
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+	return alerts
+}
+
+// notify logs the alerts of st raised or resolved since prev, posting them to the webhooks.
+func (w *Watchtower) notify(ctx context.Context, prev, st WatchedClientStatus) {
+	var alerts []WatchtowerAlert
+	for kind, msg := range st.Alerts {
+		if _, ok := prev.Alerts[kind]; !ok {
+			alerts = append(alerts, WatchtowerAlert{Kind: kind, Message: msg, Client: st})
+		}
+	}
+	for kind, msg := range prev.Alerts {
+		if _, ok := st.Alerts[kind]; !ok {
+			alerts = append(alerts, WatchtowerAlert{Kind: kind, Message: msg, Resolved: true, Client: st})
+		}
+	}
+	sort.Slice(alerts, func(i, k int) bool { return alerts[i].Kind < alerts[k].Kind })
+
+	for _, a := range alerts {
+		fields := []zap.Field{
+			zap.String("chain_id", st.ChainID),
+			zap.String("client_id", st.ClientID),
+			zap.String("client_chain_id", st.ClientChainID),
+			zap.String("latest_height", st.LatestHeight),
+			zap.String("alert", a.Kind),
+			zap.String("message", a.Message),
+		}
+		if a.Resolved {
+			w.log.Info("Watched client alert resolved", fields...)
+		} else {
+			w.log.Error("Watched client alert", fields...)
+		}
+		if err := postWebhooks(ctx, w.cfg.Webhooks, a); err != nil {
+			w.log.Warn("Failed to post watched client alert", zap.String("client_id", st.ClientID), zap.Error(err))
+		}
+	}
+}
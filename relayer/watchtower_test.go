@@ -0,0 +1,91 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	tmclient "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWatchtowerConfigValidate(t *testing.T) {
+	cfg := WatchtowerConfig{Hub: "hub", Clients: []WatchedClient{{ClientID: "07-tendermint-0"}}}
+	require.NoError(t, cfg.Validate())
+
+	for _, invalid := range []WatchtowerConfig{
+		{Clients: cfg.Clients},
+		{Hub: "hub"},
+		{Hub: "hub", Clients: []WatchedClient{{ClientID: "07-tendermint-0"}, {ClientID: "07-tendermint-0"}}},
+		{Hub: "hub", Clients: cfg.Clients, ExpiryThreshold: 1},
+		{Hub: "hub", Clients: cfg.Clients, StallTimeout: -time.Second},
+	} {
+		require.Error(t, invalid.Validate())
+	}
+}
+
+func TestWatchedClientAlerts(t *testing.T) {
+	now := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	cfg := WatchtowerConfig{ExpiryThreshold: defaultWatchtowerExpiryThreshold, StallTimeout: time.Hour}
+	wc := WatchedClient{ClientID: "07-tendermint-0", ChainID: "rollapp_1-1"}
+	cs := &tmclient.ClientState{
+		ChainId:        "rollapp_1-1",
+		TrustingPeriod: 24 * time.Hour,
+		LatestHeight:   clienttypes.NewHeight(1, 100),
+		FrozenHeight:   clienttypes.ZeroHeight(),
+	}
+
+	st := watchedClientStatus("hub", wc, cs, now.Add(-time.Hour), now)
+	st.UpdatedAt = now.Add(-time.Minute)
+	require.Nil(t, watchedClientAlerts(st, wc, cs, cfg, now))
+
+	// 23h of the trusting period left, then 2h, then none
+	st = watchedClientStatus("hub", wc, cs, now.Add(-22*time.Hour), now)
+	st.UpdatedAt = now
+	require.Contains(t, watchedClientAlerts(st, wc, cs, cfg, now), WatchtowerAlertExpiring)
+	st = watchedClientStatus("hub", wc, cs, now.Add(-25*time.Hour), now)
+	st.UpdatedAt = now
+	alerts := watchedClientAlerts(st, wc, cs, cfg, now)
+	require.Contains(t, alerts, WatchtowerAlertExpired)
+	require.NotContains(t, alerts, WatchtowerAlertExpiring)
+
+	frozen := *cs
+	frozen.FrozenHeight = clienttypes.NewHeight(1, 90)
+	frozen.ChainId = "rollapp_2-1"
+	st = watchedClientStatus("hub", wc, &frozen, now.Add(-time.Hour), now)
+	st.UpdatedAt = now.Add(-2 * time.Hour)
+	alerts = watchedClientAlerts(st, wc, &frozen, cfg, now)
+	require.Equal(t, "client is frozen at height 1-90", alerts[WatchtowerAlertFrozen])
+	require.Equal(t, "client tracks chain rollapp_2-1 instead of rollapp_1-1", alerts[WatchtowerAlertMismatch])
+	require.Contains(t, alerts, WatchtowerAlertStalled)
+}
+
+func TestWatchtowerNotify(t *testing.T) {
+	var posted []WatchtowerAlert
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var a WatchtowerAlert
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&a))
+		posted = append(posted, a)
+	}))
+	defer srv.Close()
+
+	w := &Watchtower{log: zap.NewNop(), cfg: WatchtowerConfig{Webhooks: []string{srv.URL}}}
+	ctx := context.Background()
+
+	frozen := WatchedClientStatus{ClientID: "07-tendermint-0", Alerts: map[string]string{WatchtowerAlertFrozen: "client is frozen"}}
+	w.notify(ctx, WatchedClientStatus{}, frozen)
+	// alerts still raised are not posted again
+	w.notify(ctx, frozen, frozen)
+	w.notify(ctx, frozen, WatchedClientStatus{ClientID: "07-tendermint-0"})
+
+	require.Len(t, posted, 2)
+	require.Equal(t, WatchtowerAlertFrozen, posted[0].Kind)
+	require.False(t, posted[0].Resolved)
+	require.Equal(t, WatchtowerAlertFrozen, posted[1].Kind)
+	require.True(t, posted[1].Resolved)
+}
@@ -0,0 +1,408 @@
+// Package relayerclient is a Go client for the admin API of a running relayer, see docs/admin_api.md,
+// so that orchestration tools can manage fleets of relayers programmatically.
+//
+// State-changing requests carry the operator of the client, the current time and a fresh nonce,
+// signed with the secret of the client if any, as the admin API requires.
+package relayerclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+)
+
+// Client calls the admin API of a single relayer. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	operator   string
+	secret     []byte
+	httpClient *http.Client
+}
+
+// Option configures optional behavior of a Client.
+type Option func(*Client)
+
+// WithOperator identifies the operator of the state-changing requests sent by the client,
+// as recorded in the audit log of the relayer.
+func WithOperator(operator string) Option {
+	return func(c *Client) {
+		c.operator = operator
+	}
+}
+
+// WithSecret signs the state-changing requests sent by the client with secret,
+// the secret the relayer was started with in --admin-secret-file.
+func WithSecret(secret []byte) Option {
+	return func(c *Client) {
+		c.secret = secret
+	}
+}
+
+// WithHTTPClient sends the requests with hc instead of http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// New returns a client for the admin API served at addr, the --admin-addr of the relayer such as "localhost:7598",
+// or a base URL such as "https://relayer-1.internal:7598".
+func New(addr string, opts ...Option) (*Client, error) {
+	if addr == "" {
+		return nil, errors.New("admin API address cannot be empty")
+	}
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	c := &Client{
+		baseURL:    strings.TrimSuffix(addr, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// APIError is returned for requests the admin API answered with an error status code.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("admin API returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Audit returns the most recent state-changing requests made to the relayer, oldest first.
+func (c *Client) Audit(ctx context.Context) ([]admin.AuditEntry, error) {
+	var entries []admin.AuditEntry
+	if err := c.do(ctx, http.MethodGet, "/audit", nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Processors returns the processor currently relaying each path.
+func (c *Client) Processors(ctx context.Context) ([]admin.PathProcessor, error) {
+	var processors []admin.PathProcessor
+	if err := c.do(ctx, http.MethodGet, "/processor", nil, &processors); err != nil {
+		return nil, err
+	}
+	return processors, nil
+}
+
+// SetProcessor hands path off to the given processor, "events" or "legacy", returning once the handoff is complete.
+func (c *Client) SetProcessor(ctx context.Context, path, processor string) (admin.PathProcessor, error) {
+	var res admin.PathProcessor
+	err := c.do(ctx, http.MethodPost, "/processor", admin.SetProcessorRequest{Path: path, Processor: processor}, &res)
+	return res, err
+}
+
+// Flush relays all pending packets and acknowledgements of path once.
+// Retrying with the same non-empty idempotencyKey performs the flush only once.
+// When the flush fails part way, the transactions that were broadcast are returned along with the error.
+func (c *Client) Flush(ctx context.Context, path, idempotencyKey string) (admin.FlushResponse, error) {
+	var res admin.FlushResponse
+	err := c.do(ctx, http.MethodPost, "/flush", admin.FlushRequest{Path: path, IdempotencyKey: idempotencyKey}, &res)
+	return res, err
+}
+
+// Channels returns the open channels relayed on each path, or only on path if it is non-empty.
+func (c *Client) Channels(ctx context.Context, path string) ([]admin.Channel, error) {
+	var channels []admin.Channel
+	if err := c.do(ctx, http.MethodGet, "/channels"+pathQuery(path), nil, &channels); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// PauseChannel stops relaying the channel of path, identified by its port and channel IDs on the src chain of the path,
+// until it is resumed.
+func (c *Client) PauseChannel(ctx context.Context, path, portID, channelID string) (admin.Channel, error) {
+	var res admin.Channel
+	err := c.do(ctx, http.MethodPost, "/channels/pause", admin.ChannelRequest{Path: path, PortID: portID, ChannelID: channelID}, &res)
+	return res, err
+}
+
+// ResumeChannel resumes relaying a channel paused with PauseChannel.
+func (c *Client) ResumeChannel(ctx context.Context, path, portID, channelID string) (admin.Channel, error) {
+	var res admin.Channel
+	err := c.do(ctx, http.MethodPost, "/channels/resume", admin.ChannelRequest{Path: path, PortID: portID, ChannelID: channelID}, &res)
+	return res, err
+}
+
+// PathPauses returns whether each path is paused.
+func (c *Client) PathPauses(ctx context.Context) ([]admin.PathPause, error) {
+	var pauses []admin.PathPause
+	if err := c.do(ctx, http.MethodGet, "/paused", nil, &pauses); err != nil {
+		return nil, err
+	}
+	return pauses, nil
+}
+
+// SetPathPaused pauses or resumes relaying the packets and acknowledgements of path, its clients being kept updated.
+func (c *Client) SetPathPaused(ctx context.Context, path string, paused bool) (admin.PathPause, error) {
+	var res admin.PathPause
+	err := c.do(ctx, http.MethodPost, "/paused", admin.PathPause{Path: path, Paused: paused}, &res)
+	return res, err
+}
+
+// Pending returns the packets and acknowledgements waiting to be relayed on each open channel,
+// of all paths or only of path if it is non-empty.
+func (c *Client) Pending(ctx context.Context, path string) ([]admin.PendingSequences, error) {
+	var pending []admin.PendingSequences
+	if err := c.do(ctx, http.MethodGet, "/pending"+pathQuery(path), nil, &pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// StuckPackets returns the packets and acknowledgements pending for longer than the threshold of the stuck packet
+// detector, or minAge if longer, on all paths, or only on path if it is non-empty.
+func (c *Client) StuckPackets(ctx context.Context, path string, minAge time.Duration) ([]admin.StuckPacket, error) {
+	q := url.Values{}
+	if path != "" {
+		q.Set("path", path)
+	}
+	if minAge > 0 {
+		q.Set("min_age", minAge.String())
+	}
+	endpoint := "/stuck-packets"
+	if len(q) > 0 {
+		endpoint += "?" + q.Encode()
+	}
+	var stuck []admin.StuckPacket
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &stuck); err != nil {
+		return nil, err
+	}
+	return stuck, nil
+}
+
+// Identity returns the account of the relayer on both chains of all paths, or only of path if it is non-empty.
+func (c *Client) Identity(ctx context.Context, path string) ([]admin.PathIdentity, error) {
+	var identities []admin.PathIdentity
+	if err := c.do(ctx, http.MethodGet, "/identity"+pathQuery(path), nil, &identities); err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// Balances returns the balance of the relayer wallet on every chain.
+func (c *Client) Balances(ctx context.Context) ([]admin.WalletBalance, error) {
+	var balances []admin.WalletBalance
+	if err := c.do(ctx, http.MethodGet, "/balances", nil, &balances); err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// Clients returns when each client of all paths, or only of path if it is non-empty, expires unless updated.
+func (c *Client) Clients(ctx context.Context, path string) ([]admin.ClientExpiry, error) {
+	var clients []admin.ClientExpiry
+	if err := c.do(ctx, http.MethodGet, "/clients"+pathQuery(path), nil, &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// Denoms returns the IBC denoms minted on the chains of all paths, or only of path if it is non-empty,
+// by the transfers received over the channels relayed.
+func (c *Client) Denoms(ctx context.Context, path string) ([]admin.IBCDenom, error) {
+	var denoms []admin.IBCDenom
+	if err := c.do(ctx, http.MethodGet, "/denoms"+pathQuery(path), nil, &denoms); err != nil {
+		return nil, err
+	}
+	return denoms, nil
+}
+
+// Keys returns the key signing the transactions sent to each chain.
+func (c *Client) Keys(ctx context.Context) ([]admin.ChainKey, error) {
+	var keys []admin.ChainKey
+	if err := c.do(ctx, http.MethodGet, "/key", nil, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// UseKey signs the transactions sent to the chain with the given key of its keyring from now on.
+func (c *Client) UseKey(ctx context.Context, chainID, key string) (admin.ChainKey, error) {
+	var res admin.ChainKey
+	err := c.do(ctx, http.MethodPost, "/key", admin.UseKeyRequest{ChainID: chainID, Key: key}, &res)
+	return res, err
+}
+
+// PathLogs returns the logging configured for each path.
+func (c *Client) PathLogs(ctx context.Context) ([]admin.PathLog, error) {
+	var logs []admin.PathLog
+	if err := c.do(ctx, http.MethodGet, "/log", nil, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// SetPathLog reconfigures the logging of a path, empty fields falling back to the logging of the relayer.
+func (c *Client) SetPathLog(ctx context.Context, log admin.PathLog) (admin.PathLog, error) {
+	var res admin.PathLog
+	err := c.do(ctx, http.MethodPost, "/log", log, &res)
+	return res, err
+}
+
+// Priorities returns whether the fees of the transactions of each path are bumped.
+func (c *Client) Priorities(ctx context.Context) ([]admin.PathPriority, error) {
+	var priorities []admin.PathPriority
+	if err := c.do(ctx, http.MethodGet, "/priority", nil, &priorities); err != nil {
+		return nil, err
+	}
+	return priorities, nil
+}
+
+// SetPriority enables or disables bumping the fees of the transactions of the path.
+func (c *Client) SetPriority(ctx context.Context, path string, enabled bool) (admin.PathPriority, error) {
+	var res admin.PathPriority
+	err := c.do(ctx, http.MethodPost, "/priority", admin.PathPriority{Path: path, Enabled: enabled}, &res)
+	return res, err
+}
+
+// FeatureFlags returns whether each experimental feature is enabled by default and for each path.
+func (c *Client) FeatureFlags(ctx context.Context) ([]admin.FeatureFlag, error) {
+	var flags []admin.FeatureFlag
+	if err := c.do(ctx, http.MethodGet, "/features", nil, &flags); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// SetFeatureFlag enables or disables the experimental feature for the path, or by default if path is empty.
+func (c *Client) SetFeatureFlag(ctx context.Context, feature, path string, enabled bool) (admin.FeatureFlag, error) {
+	var res admin.FeatureFlag
+	err := c.do(ctx, http.MethodPost, "/features", admin.FeatureFlag{Name: feature, Path: path, Enabled: enabled}, &res)
+	return res, err
+}
+
+// MaintenanceTasks returns the state of the periodic maintenance tasks of the relayer.
+func (c *Client) MaintenanceTasks(ctx context.Context) ([]admin.MaintenanceTask, error) {
+	var tasks []admin.MaintenanceTask
+	if err := c.do(ctx, http.MethodGet, "/maintenance", nil, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// SetMaintenanceTask enables or disables the maintenance task named name, or all tasks of that kind,
+// returning the tasks changed.
+func (c *Client) SetMaintenanceTask(ctx context.Context, name string, enabled bool) ([]admin.MaintenanceTask, error) {
+	var tasks []admin.MaintenanceTask
+	err := c.do(ctx, http.MethodPost, "/maintenance", admin.MaintenanceTask{Name: name, Enabled: enabled}, &tasks)
+	return tasks, err
+}
+
+// Faults returns the faults injected into the RPC requests of each chain, served by builds with the faultinjection build tag only.
+func (c *Client) Faults(ctx context.Context) ([]admin.ChainFaults, error) {
+	var faults []admin.ChainFaults
+	if err := c.do(ctx, http.MethodGet, "/faults", nil, &faults); err != nil {
+		return nil, err
+	}
+	return faults, nil
+}
+
+// SetFaults sets the faults injected into the RPC requests of a chain, zero values clearing them.
+func (c *Client) SetFaults(ctx context.Context, faults admin.ChainFaults) (*admin.ChainFaults, error) {
+	var res admin.ChainFaults
+	if err := c.do(ctx, http.MethodPost, "/faults", faults, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func pathQuery(path string) string {
+	if path == "" {
+		return ""
+	}
+	return "?" + url.Values{"path": {path}}.Encode()
+}
+
+// do sends a request with body encoded as JSON, and decodes the JSON response into res.
+// For error status codes, res is still decoded if the body has its shape, and an *APIError is returned.
+func (c *Client) do(ctx context.Context, method, path string, body, res any) error {
+	var reqBody io.Reader
+	var bodyBz []byte
+	if body != nil {
+		bz, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(bz)
+		bodyBz = bz
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions {
+		nonce, err := newNonce()
+		if err != nil {
+			return err
+		}
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(admin.OperatorHeader, c.operator)
+		req.Header.Set(admin.NonceHeader, nonce)
+		req.Header.Set(admin.TimestampHeader, timestamp)
+		if len(c.secret) > 0 {
+			req.Header.Set(admin.SignatureHeader, admin.Sign(c.secret, method, req.URL.RequestURI(), c.operator, nonce, timestamp, bodyBz))
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bz, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var errRes admin.ErrorResponse
+		_ = json.Unmarshal(bz, &errRes)
+		if res != nil {
+			_ = json.Unmarshal(bz, res)
+		}
+		msg := errRes.Error
+		if msg == "" {
+			msg = strings.TrimSpace(string(bz))
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: msg}
+	}
+	if res == nil {
+		return nil
+	}
+	if err := json.Unmarshal(bz, res); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,203 @@
+package relayerclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/admin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// startAdminServer serves an admin API with a fake processor endpoint, returning its address.
+func startAdminServer(t *testing.T, opts ...admin.ServerOption) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	srv := admin.NewServer(zaptest.NewLogger(t), opts...)
+	srv.RegisterStatus("block_times", func() any { return map[string]int{"chain-a": 6} })
+
+	processor := "legacy"
+	srv.HandleFunc("/processor", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			admin.WriteJSON(w, http.StatusOK, []admin.PathProcessor{{Path: "demo-path", Processor: processor}})
+		case http.MethodPost:
+			var body admin.SetProcessorRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				admin.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			if body.Path != "demo-path" {
+				admin.WriteError(w, http.StatusNotFound, errors.New("path not found"))
+				return
+			}
+			processor = body.Processor
+			admin.WriteJSON(w, http.StatusOK, admin.PathProcessor{Path: body.Path, Processor: processor})
+		}
+	})
+	srv.HandleFunc("/flush", func(w http.ResponseWriter, req *http.Request) {
+		admin.WriteJSON(w, http.StatusInternalServerError, admin.FlushResponse{
+			Path:  "demo-path",
+			Txs:   []admin.Tx{{ChainID: "chain-a", TxHash: "ABC"}},
+			Error: "out of gas",
+		})
+	})
+
+	paused := false
+	srv.HandleFunc("/channels", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("path") == "unknown" {
+			admin.WriteError(w, http.StatusNotFound, errors.New("path not found"))
+			return
+		}
+		admin.WriteJSON(w, http.StatusOK, []admin.Channel{{Path: "demo-path", ChainID: "chain-a", PortID: "transfer", ChannelID: "channel-0", Paused: paused}})
+	})
+	srv.HandleFunc("/channels/pause", func(w http.ResponseWriter, req *http.Request) {
+		var body admin.ChannelRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		paused = true
+		admin.WriteJSON(w, http.StatusOK, admin.Channel{Path: body.Path, PortID: body.PortID, ChannelID: body.ChannelID, Paused: true})
+	})
+	srv.HandleFunc("/key", func(w http.ResponseWriter, req *http.Request) {
+		var body admin.UseKeyRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		admin.WriteJSON(w, http.StatusOK, admin.ChainKey{ChainID: body.ChainID, Key: body.Key, Address: "cosmos1..."})
+	})
+
+	srv.HandleFunc("/clients", func(w http.ResponseWriter, req *http.Request) {
+		admin.WriteJSON(w, http.StatusOK, []admin.ClientExpiry{{Path: req.URL.Query().Get("path"), ChainID: "chain-a", ClientID: "07-tendermint-0", Reason: "frozen at height 1-10"}})
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv.Start(ctx, ln)
+	return ln.Addr().String()
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c, err := New(startAdminServer(t), WithOperator("alice"))
+	require.NoError(t, err)
+
+	status, err := c.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"block_times"}, status.Sections())
+	var blockTimes map[string]int
+	require.NoError(t, status.Decode("block_times", &blockTimes))
+	require.Equal(t, 6, blockTimes["chain-a"])
+	require.Error(t, status.Decode("signing_queues", &blockTimes))
+
+	processors, err := c.Processors(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []admin.PathProcessor{{Path: "demo-path", Processor: "legacy"}}, processors)
+
+	// State-changing requests carry the operator and a fresh nonce each.
+	for i := 0; i < 2; i++ {
+		res, err := c.SetProcessor(ctx, "demo-path", "events")
+		require.NoError(t, err)
+		require.Equal(t, "events", res.Processor)
+	}
+
+	_, err = c.SetProcessor(ctx, "unknown", "events")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	require.Equal(t, "path not found", apiErr.Message)
+
+	flush, err := c.Flush(ctx, "demo-path", "key-1")
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "out of gas", apiErr.Message)
+	require.Equal(t, []admin.Tx{{ChainID: "chain-a", TxHash: "ABC"}}, flush.Txs)
+
+	pause, err := c.PauseChannel(ctx, "demo-path", "transfer", "channel-0")
+	require.NoError(t, err)
+	require.True(t, pause.Paused)
+	channels, err := c.Channels(ctx, "demo-path")
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	require.True(t, channels[0].Paused)
+	_, err = c.Channels(ctx, "unknown")
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	clients, err := c.Clients(ctx, "demo-path")
+	require.NoError(t, err)
+	require.Equal(t, []admin.ClientExpiry{{Path: "demo-path", ChainID: "chain-a", ClientID: "07-tendermint-0", Reason: "frozen at height 1-10"}}, clients)
+
+	key, err := c.UseKey(ctx, "chain-a", "relayer-2")
+	require.NoError(t, err)
+	require.Equal(t, admin.ChainKey{ChainID: "chain-a", Key: "relayer-2", Address: "cosmos1..."}, key)
+
+	entries, err := c.Audit(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 6)
+	for _, e := range entries {
+		require.Equal(t, "alice", e.Operator)
+		require.NotEmpty(t, e.Nonce)
+	}
+	require.NotEqual(t, entries[0].Nonce, entries[1].Nonce)
+}
+
+func TestClientRequiresOperator(t *testing.T) {
+	c, err := New(startAdminServer(t))
+	require.NoError(t, err)
+
+	_, err = c.SetProcessor(context.Background(), "demo-path", "events")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
+
+func TestClientSignsRequests(t *testing.T) {
+	ctx := context.Background()
+	addr := startAdminServer(t, admin.WithSecret([]byte("s3cret")))
+
+	c, err := New(addr, WithOperator("alice"), WithSecret([]byte("s3cret")))
+	require.NoError(t, err)
+	res, err := c.SetProcessor(ctx, "demo-path", "events")
+	require.NoError(t, err)
+	require.Equal(t, "events", res.Processor)
+
+	c, err = New(addr, WithOperator("alice"))
+	require.NoError(t, err)
+	_, err = c.SetProcessor(ctx, "demo-path", "events")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestWatchStatus(t *testing.T) {
+	c, err := New(startAdminServer(t))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := c.WatchStatus(ctx, 10*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		u := <-updates
+		require.NoError(t, u.Err)
+		require.Contains(t, u.Status, "block_times")
+	}
+	cancel()
+	for range updates {
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("")
+	require.Error(t, err)
+
+	c, err := New("https://relayer-1.internal:7598/")
+	require.NoError(t, err)
+	require.Equal(t, "https://relayer-1.internal:7598", c.baseURL)
+}
@@ -0,0 +1,81 @@
+package relayerclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Status is a snapshot of the state of the relayer, by section, as returned by GET /status.
+// Sections depend on the features the relayer was started with, see docs/admin_api.md.
+type Status map[string]json.RawMessage
+
+// Sections returns the names of the sections of the status, sorted.
+func (s Status) Sections() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Decode decodes the section of the status with the given name into v.
+func (s Status) Decode(section string, v any) error {
+	raw, ok := s[section]
+	if !ok {
+		return fmt.Errorf("status has no %s section", section)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to decode %s status: %w", section, err)
+	}
+	return nil
+}
+
+// Status returns a snapshot of the state of the relayer.
+func (c *Client) Status(ctx context.Context) (Status, error) {
+	var status Status
+	if err := c.do(ctx, http.MethodGet, "/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// StatusUpdate is a status snapshot streamed by WatchStatus, or the error of the request that failed to fetch it.
+type StatusUpdate struct {
+	Time   time.Time
+	Status Status
+	Err    error
+}
+
+// WatchStatus streams a status snapshot at the given interval, starting right away, until ctx is done,
+// at which point the returned channel is closed. Failed requests are streamed as updates carrying the error,
+// so a relayer that is restarting shows as a gap rather than ending the stream.
+// Updates are dropped rather than queued while the receiver is not keeping up.
+func (c *Client) WatchStatus(ctx context.Context, interval time.Duration) <-chan StatusUpdate {
+	updates := make(chan StatusUpdate, 1)
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			status, err := c.Status(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case updates <- StatusUpdate{Time: time.Now().UTC(), Status: status, Err: err}:
+			default:
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates
+}